@@ -1,6 +1,6 @@
 #!/usr/bin/env bash
 echo "****************************************"
-echo "Creating zip file for Windows amd64"
+echo "Creating zip file for Windows ${ARCH}"
 echo "****************************************"
 set -e
 
@@ -25,6 +25,7 @@ cp ${PREPKGPATH}/install.ps1 ${BUILD_ROOT}/amazon-cloudwatch-agent/
 cp ${PREPKGPATH}/uninstall.ps1 ${BUILD_ROOT}/amazon-cloudwatch-agent/
 cp ${PREPKGPATH}/config-translator.exe ${BUILD_ROOT}/amazon-cloudwatch-agent/
 cp ${PREPKGPATH}/config-downloader.exe ${BUILD_ROOT}/amazon-cloudwatch-agent/
+cp ${PREPKGPATH}/cloudwatch-agent-supervisor.exe ${BUILD_ROOT}/amazon-cloudwatch-agent/
 cp ${PREPKGPATH}/amazon-cloudwatch-agent-config-wizard.exe ${BUILD_ROOT}/amazon-cloudwatch-agent/
 cp ${PREPKGPATH}/start-amazon-cloudwatch-agent.exe ${BUILD_ROOT}/amazon-cloudwatch-agent/
 cp ${PREPKGPATH}/common-config.toml ${BUILD_ROOT}/amazon-cloudwatch-agent/
@@ -5,7 +5,6 @@ package aws
 
 import (
 	"log"
-	"net/http"
 	"os"
 	"time"
 
@@ -42,6 +41,9 @@ type CredentialConfig struct {
 	Profile   string
 	Filename  string
 	Token     string
+	// ProxyService is the service of the clients created with the sessions,
+	// whose proxy can be overridden in agent.proxy.
+	ProxyService string
 }
 
 type stsCredentialProvider struct {
@@ -99,6 +101,7 @@ func getSession(config *aws.Config) *session.Session {
 		}
 	}
 	log.Printf("D! Successfully created credential sessions\n")
+	trackCredentials(ses.Config.Credentials)
 	cred, err := ses.Config.Credentials.Get()
 	if err != nil {
 		log.Printf("E! Failed to get credential from session: %v", err)
@@ -126,9 +129,10 @@ func (c *CredentialConfig) rootCredentials() client.ConfigProvider {
 	config := &aws.Config{
 		Region:                        aws.String(c.Region),
 		CredentialsChainVerboseErrors: aws.Bool(true),
-		HTTPClient:                    &http.Client{Timeout: 1 * time.Minute},
+		HTTPClient:                    httpClient(c.ProxyService),
 		LogLevel:                      SDKLogLevel(),
 		Logger:                        SDKLogger{},
+		EndpointResolver:              endpointResolver(),
 	}
 	config.Credentials = getRootCredentialsFromChain(c)
 	return getSession(config)
@@ -137,12 +141,13 @@ func (c *CredentialConfig) rootCredentials() client.ConfigProvider {
 func (c *CredentialConfig) assumeCredentials() client.ConfigProvider {
 	rootCredentials := c.rootCredentials()
 	config := &aws.Config{
-		Region:     aws.String(c.Region),
-		HTTPClient: &http.Client{Timeout: 1 * time.Minute},
-		LogLevel:   SDKLogLevel(),
-		Logger:     SDKLogger{},
+		Region:           aws.String(c.Region),
+		HTTPClient:       httpClient(c.ProxyService),
+		LogLevel:         SDKLogLevel(),
+		Logger:           SDKLogger{},
+		EndpointResolver: endpointResolver(),
 	}
-	config.Credentials = newStsCredentials(rootCredentials, c.RoleARN, c.Region)
+	config.Credentials = newStsCredentials(rootCredentials, c.RoleARN, c.Region, c.ProxyService)
 	return getSession(config)
 }
 
@@ -172,12 +177,12 @@ func (s *stsCredentialProvider) Retrieve() (credentials.Value, error) {
 	return v, err
 }
 
-func newStsCredentials(c client.ConfigProvider, roleARN string, region string) *credentials.Credentials {
+func newStsCredentials(c client.ConfigProvider, roleARN string, region string, proxyService string) *credentials.Credentials {
 	regional := &stscreds.AssumeRoleProvider{
 		Client: sts.New(c, &aws.Config{
 			Region:              aws.String(region),
 			STSRegionalEndpoint: endpoints.RegionalSTSEndpoint,
-			HTTPClient:          &http.Client{Timeout: 1 * time.Minute},
+			HTTPClient:          httpClient(proxyService),
 			LogLevel:            SDKLogLevel(),
 			Logger:              SDKLogger{},
 		}),
@@ -192,7 +197,7 @@ func newStsCredentials(c client.ConfigProvider, roleARN string, region string) *
 			Region:              aws.String(fallbackRegion),
 			Endpoint:            aws.String(getFallbackEndpoint(fallbackRegion)),
 			STSRegionalEndpoint: endpoints.RegionalSTSEndpoint,
-			HTTPClient:          &http.Client{Timeout: 1 * time.Minute},
+			HTTPClient:          httpClient(proxyService),
 			LogLevel:            SDKLogLevel(),
 			Logger:              SDKLogger{},
 		}),
@@ -206,7 +211,7 @@ func newStsCredentials(c client.ConfigProvider, roleARN string, region string) *
 // The partitional STS endpoint used to fallback when regional STS endpoint is not activated.
 func getFallbackEndpoint(region string) string {
 	partition := getPartition(region)
-	endpoint, _ := partition.EndpointFor("sts", region)
+	endpoint, _ := partition.EndpointFor("sts", region, endpointOptions()...)
 	log.Printf("D! STS partitional endpoint retrieved: %s", endpoint.URL)
 	return endpoint.URL
 }
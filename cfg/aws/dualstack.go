@@ -0,0 +1,173 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"bufio"
+	"bytes"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+)
+
+const (
+	// IMDSIPv6Endpoint is the IPv6 endpoint of the instance metadata service.
+	IMDSIPv6Endpoint = "http://[fd00:ec2::254]"
+
+	imdsEndpointModeIPv6 = "IPv6"
+	// rtfReject is the flag of the unreachable routes in /proc/net/ipv6_route.
+	rtfReject = 0x0200
+)
+
+var (
+	// ipv4RoutePath and ipv6RoutePath are the routing tables of the kernel.
+	ipv4RoutePath = "/proc/net/route"
+	ipv6RoutePath = "/proc/net/ipv6_route"
+)
+
+// DualStackEndpointEnabled returns whether the AWS clients resolve the
+// dual-stack endpoints of the services, which are reachable over IPv6. It is
+// set with agent.dualstack_endpoints, which is written to the environment
+// variable read by the SDK.
+func DualStackEndpointEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(envconfig.AWS_USE_DUALSTACK_ENDPOINT))
+	return enabled
+}
+
+// IMDSIPv6Enabled returns whether the IMDS clients of the SDK use the IPv6
+// endpoint of the instance metadata service.
+func IMDSIPv6Enabled() bool {
+	return strings.EqualFold(os.Getenv(envconfig.AWS_EC2_METADATA_SERVICE_ENDPOINT_MODE), imdsEndpointModeIPv6)
+}
+
+// DetectIPv6Only enables the dual-stack endpoints and the IPv6 endpoint of
+// IMDS if the host only has an IPv6 default route, unless they are set. It
+// must be called before the AWS clients are created and returns whether the
+// host is IPv6-only.
+func DetectIPv6Only() bool {
+	if !isIPv6OnlyHost() {
+		return false
+	}
+	if _, ok := os.LookupEnv(envconfig.AWS_USE_DUALSTACK_ENDPOINT); !ok {
+		_ = os.Setenv(envconfig.AWS_USE_DUALSTACK_ENDPOINT, "true")
+	}
+	if _, ok := os.LookupEnv(envconfig.AWS_EC2_METADATA_SERVICE_ENDPOINT_MODE); !ok {
+		_ = os.Setenv(envconfig.AWS_EC2_METADATA_SERVICE_ENDPOINT_MODE, imdsEndpointModeIPv6)
+	}
+	return true
+}
+
+// isIPv6OnlyHost returns whether the routing tables have an IPv6 default route
+// but no IPv4 one. It is false on the hosts without the Linux routing tables.
+func isIPv6OnlyHost() bool {
+	ipv4Routes, err := os.ReadFile(ipv4RoutePath)
+	if err != nil {
+		return false
+	}
+	ipv6Routes, err := os.ReadFile(ipv6RoutePath)
+	if err != nil {
+		return false
+	}
+	return !hasIPv4DefaultRoute(ipv4Routes) && hasIPv6DefaultRoute(ipv6Routes)
+}
+
+// hasIPv4DefaultRoute parses /proc/net/route, where the destination of the
+// default route is 00000000.
+func hasIPv4DefaultRoute(routes []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(routes))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 1 && fields[1] == "00000000" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasIPv6DefaultRoute parses /proc/net/ipv6_route, where the default route
+// has a ::/0 destination. The unreachable default route of the loopback
+// interface is ignored.
+func hasIPv6DefaultRoute(routes []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(routes))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || strings.Trim(fields[0], "0") != "" || fields[1] != "00" || fields[9] == "lo" {
+			continue
+		}
+		if flags, err := strconv.ParseUint(fields[8], 16, 32); err == nil && flags&rtfReject == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// dualStackResolver resolves the dual-stack endpoints with the default
+// resolver and falls back to the standard endpoint of the services whose
+// dual-stack endpoint does not resolve, since not all the services have one.
+type dualStackResolver struct {
+	resolver endpoints.Resolver
+
+	mu       sync.Mutex
+	resolved map[string]endpoints.ResolvedEndpoint
+}
+
+func newDualStackResolver(resolver endpoints.Resolver) *dualStackResolver {
+	return &dualStackResolver{
+		resolver: resolver,
+		resolved: make(map[string]endpoints.ResolvedEndpoint),
+	}
+}
+
+func (r *dualStackResolver) EndpointFor(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+	endpoint, err := r.resolver.EndpointFor(service, region, opts...)
+	if err != nil || service == ec2MetadataServiceID || !usesDualStack(opts) {
+		return endpoint, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if resolved, ok := r.resolved[endpoint.URL]; ok {
+		return resolved, nil
+	}
+	resolved := endpoint
+	if !resolves(endpoint.URL) {
+		if standard, err := r.resolver.EndpointFor(service, region, append(opts, standardEndpointOption)...); err == nil {
+			log.Printf("I! The dual-stack endpoint %s of %s does not resolve, using %s", endpoint.URL, service, standard.URL)
+			resolved = standard
+		}
+	}
+	r.resolved[endpoint.URL] = resolved
+	return resolved, nil
+}
+
+// usesDualStack returns whether the options resolve the dual-stack endpoints.
+func usesDualStack(opts []func(*endpoints.Options)) bool {
+	var o endpoints.Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o.UseDualStack || o.UseDualStackEndpoint == endpoints.DualStackEndpointStateEnabled
+}
+
+// resolves returns whether the host of the endpoint has any address.
+func resolves(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	addrs, err := lookupHost(u.Hostname())
+	return err == nil && len(addrs) > 0
+}
+
+// standardEndpointOption disables the dual-stack endpoints.
+func standardEndpointOption(o *endpoints.Options) {
+	o.UseDualStack = false
+	o.UseDualStackEndpoint = endpoints.DualStackEndpointStateDisabled
+}
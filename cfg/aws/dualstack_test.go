@@ -0,0 +1,134 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+)
+
+const (
+	testIPv4Routes = `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	00000000	0100000A	0003	0	0	100	00000000	0	0	0
+eth0	0000000A	00000000	0001	0	0	100	0000FFFF	0	0	0
+`
+	testIPv4LocalRoutes = `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+docker0	000011AC	00000000	0001	0	0	0	0000FFFF	0	0	0
+`
+	testIPv6Routes = `fe800000000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     eth0
+00000000000000000000000000000000 00 00000000000000000000000000000000 00 fe80000000000000045c8dfffe0e5b1f 00000400 00000002 00000000 00000003     eth0
+00000000000000000000000000000000 00 00000000000000000000000000000000 00 00000000000000000000000000000000 ffffffff 00000001 00000000 00200200       lo
+`
+	testIPv6LoopbackRoutes = `00000000000000000000000000000001 80 00000000000000000000000000000000 00 00000000000000000000000000000000 00000000 00000002 00000000 80200001       lo
+00000000000000000000000000000000 00 00000000000000000000000000000000 00 00000000000000000000000000000000 ffffffff 00000001 00000000 00200200       lo
+`
+)
+
+func TestDetectIPv6Only(t *testing.T) {
+	originalIPv4RoutePath, originalIPv6RoutePath := ipv4RoutePath, ipv6RoutePath
+	defer func() {
+		ipv4RoutePath, ipv6RoutePath = originalIPv4RoutePath, originalIPv6RoutePath
+	}()
+	testCases := map[string]struct {
+		env           *string
+		ipv4Routes    string
+		ipv6Routes    string
+		want          bool
+		wantDualStack bool
+	}{
+		"WithIPv6Only":              {ipv4Routes: testIPv4LocalRoutes, ipv6Routes: testIPv6Routes, want: true, wantDualStack: true},
+		"WithDualStackHost":         {ipv4Routes: testIPv4Routes, ipv6Routes: testIPv6Routes},
+		"WithIPv4Only":              {ipv4Routes: testIPv4Routes, ipv6Routes: testIPv6LoopbackRoutes},
+		"WithoutRoutingTables":      {},
+		"WithDisabledInAgentConfig": {env: ptr("false"), ipv4Routes: testIPv4LocalRoutes, ipv6Routes: testIPv6Routes, want: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			ipv4RoutePath = writeTestFile(t, dir, "route", testCase.ipv4Routes)
+			ipv6RoutePath = writeTestFile(t, dir, "ipv6_route", testCase.ipv6Routes)
+			// restored after the test
+			t.Setenv(envconfig.AWS_USE_DUALSTACK_ENDPOINT, "")
+			t.Setenv(envconfig.AWS_EC2_METADATA_SERVICE_ENDPOINT_MODE, "")
+			require.NoError(t, os.Unsetenv(envconfig.AWS_EC2_METADATA_SERVICE_ENDPOINT_MODE))
+			if testCase.env != nil {
+				t.Setenv(envconfig.AWS_USE_DUALSTACK_ENDPOINT, *testCase.env)
+			} else {
+				require.NoError(t, os.Unsetenv(envconfig.AWS_USE_DUALSTACK_ENDPOINT))
+			}
+
+			assert.Equal(t, testCase.want, DetectIPv6Only())
+			assert.Equal(t, testCase.wantDualStack, DualStackEndpointEnabled())
+			assert.Equal(t, testCase.want, IMDSIPv6Enabled())
+		})
+	}
+}
+
+func TestDualStackResolver(t *testing.T) {
+	defer func(original func(string) ([]string, error)) {
+		lookupHost = original
+	}(lookupHost)
+	dualStack := func(o *endpoints.Options) { o.UseDualStackEndpoint = endpoints.DualStackEndpointStateEnabled }
+	testCases := map[string]struct {
+		service string
+		opts    []func(*endpoints.Options)
+		addrs   map[string][]string
+		want    string
+	}{
+		"WithDualStackEndpoint": {
+			service: "logs",
+			opts:    []func(*endpoints.Options){dualStack},
+			addrs:   map[string][]string{"logs.us-east-1.api.aws": {"2600:1f18::1"}},
+			want:    "https://logs.us-east-1.api.aws",
+		},
+		"WithoutDualStackEndpoint": {
+			service: "logs",
+			opts:    []func(*endpoints.Options){dualStack},
+			want:    "https://logs.us-east-1.amazonaws.com",
+		},
+		"WithDualStackDisabled": {
+			service: "logs",
+			want:    "https://logs.us-east-1.amazonaws.com",
+		},
+		"WithIMDS": {
+			service: ec2MetadataServiceID,
+			opts:    []func(*endpoints.Options){dualStack},
+			want:    "http://169.254.169.254/latest",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lookupHost = func(host string) ([]string, error) {
+				if addrs, ok := testCase.addrs[host]; ok {
+					return addrs, nil
+				}
+				return nil, errors.New("no such host")
+			}
+			r := newDualStackResolver(endpoints.DefaultResolver())
+			got, err := r.EndpointFor(testCase.service, "us-east-1", testCase.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, got.URL)
+		})
+	}
+}
+
+func TestEndpointResolverWithDualStack(t *testing.T) {
+	t.Setenv(envconfig.CWAGENT_VPC_ENDPOINT_DISCOVERY, "")
+	t.Setenv(envconfig.AWS_USE_DUALSTACK_ENDPOINT, "true")
+	assert.True(t, DualStackEndpointEnabled())
+	assert.IsType(t, &dualStackResolver{}, endpointResolver())
+}
+
+func TestFallbackEndpointWithDualStack(t *testing.T) {
+	t.Setenv(envconfig.AWS_USE_FIPS_ENDPOINT, "")
+	t.Setenv(envconfig.AWS_USE_DUALSTACK_ENDPOINT, "true")
+	assert.Equal(t, "https://sts.us-east-1.api.aws", getFallbackEndpoint("us-east-1"))
+}
@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+)
+
+var (
+	// fipsEnabledPath is set to 1 by the kernel booted in FIPS mode.
+	fipsEnabledPath = "/proc/sys/crypto/fips_enabled"
+	// cryptoPolicyPath is the system wide crypto policy of RHEL and Amazon
+	// Linux 2023, e.g. FIPS or FIPS:OSPP.
+	cryptoPolicyPath = "/etc/crypto-policies/state/current"
+)
+
+// FIPSEndpointEnabled returns whether the AWS clients resolve the FIPS
+// endpoints of the services. It is set with agent.fips, which is written to
+// the environment variable read by the SDK.
+func FIPSEndpointEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(envconfig.AWS_USE_FIPS_ENDPOINT))
+	return enabled
+}
+
+// DetectFIPSEndpoint enables the FIPS endpoints if the host runs in FIPS mode,
+// unless agent.fips is set. It must be called before the AWS clients are
+// created and returns whether the FIPS endpoints are enabled.
+func DetectFIPSEndpoint() bool {
+	if _, ok := os.LookupEnv(envconfig.AWS_USE_FIPS_ENDPOINT); ok {
+		return FIPSEndpointEnabled()
+	}
+	if !isFIPSHost() {
+		return false
+	}
+	_ = os.Setenv(envconfig.AWS_USE_FIPS_ENDPOINT, "true")
+	return true
+}
+
+func isFIPSHost() bool {
+	if content, err := os.ReadFile(fipsEnabledPath); err == nil && strings.TrimSpace(string(content)) == "1" {
+		return true
+	}
+	if content, err := os.ReadFile(cryptoPolicyPath); err == nil {
+		policy, _, _ := strings.Cut(strings.TrimSpace(string(content)), ":")
+		return policy == "FIPS"
+	}
+	return false
+}
+
+// endpointOptions are the options used to resolve the endpoints explicitly,
+// which the SDK does not apply from the environment.
+func endpointOptions() []func(*endpoints.Options) {
+	var opts []func(*endpoints.Options)
+	if FIPSEndpointEnabled() {
+		opts = append(opts, endpoints.UseFIPSEndpointOption)
+	}
+	if DualStackEndpointEnabled() {
+		opts = append(opts, endpoints.UseDualStackEndpointOption)
+	}
+	return opts
+}
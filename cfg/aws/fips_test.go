@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+)
+
+func TestDetectFIPSEndpoint(t *testing.T) {
+	originalFIPSEnabledPath, originalCryptoPolicyPath := fipsEnabledPath, cryptoPolicyPath
+	defer func() {
+		fipsEnabledPath, cryptoPolicyPath = originalFIPSEnabledPath, originalCryptoPolicyPath
+	}()
+	testCases := map[string]struct {
+		env          *string
+		fipsEnabled  string
+		cryptoPolicy string
+		want         bool
+	}{
+		"WithKernelFIPSMode":        {fipsEnabled: "1\n", want: true},
+		"WithFIPSCryptoPolicy":      {fipsEnabled: "0\n", cryptoPolicy: "FIPS\n", want: true},
+		"WithFIPSSubpolicy":         {cryptoPolicy: "FIPS:OSPP\n", want: true},
+		"WithDefaultCryptoPolicy":   {fipsEnabled: "0\n", cryptoPolicy: "DEFAULT\n"},
+		"WithoutFIPSMode":           {},
+		"WithDisabledInAgentConfig": {env: ptr("false"), fipsEnabled: "1\n"},
+		"WithEnabledInAgentConfig":  {env: ptr("true"), want: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			fipsEnabledPath = writeTestFile(t, dir, "fips_enabled", testCase.fipsEnabled)
+			cryptoPolicyPath = writeTestFile(t, dir, "current", testCase.cryptoPolicy)
+			// restored after the test
+			t.Setenv(envconfig.AWS_USE_FIPS_ENDPOINT, "")
+			if testCase.env != nil {
+				t.Setenv(envconfig.AWS_USE_FIPS_ENDPOINT, *testCase.env)
+			} else {
+				require.NoError(t, os.Unsetenv(envconfig.AWS_USE_FIPS_ENDPOINT))
+			}
+
+			assert.Equal(t, testCase.want, DetectFIPSEndpoint())
+			assert.Equal(t, testCase.want, FIPSEndpointEnabled())
+		})
+	}
+}
+
+func TestFallbackEndpointWithFIPS(t *testing.T) {
+	t.Setenv(envconfig.AWS_USE_FIPS_ENDPOINT, "true")
+	assert.Equal(t, "https://sts-fips.us-east-1.amazonaws.com", getFallbackEndpoint("us-east-1"))
+	t.Setenv(envconfig.AWS_USE_FIPS_ENDPOINT, "false")
+	assert.Equal(t, "https://sts.amazonaws.com", getFallbackEndpoint("us-east-1"))
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+// writeTestFile returns the path of a missing file if the content is empty.
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if content != "" {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	return path
+}
@@ -0,0 +1,155 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+)
+
+// The services with their own proxy configuration in agent.proxy.
+const (
+	ProxyServiceLogs    = "logs"
+	ProxyServiceMetrics = "metrics"
+	ProxyServiceXray    = "xray"
+	ProxyServiceSSM     = "ssm"
+)
+
+// DefaultNoProxy are always reached directly: the link-local addresses of
+// IMDS and of the ECS and EKS credential endpoints, and the DNS names of the
+// interface VPC endpoints.
+var DefaultNoProxy = []string{"169.254.0.0/16", "fd00:ec2::/32", ".vpce.amazonaws.com"}
+
+var proxyFuncs sync.Map
+
+// ProxyFunc returns the proxy of the requests of the clients of the service,
+// or of the other clients if the service is empty.
+func ProxyFunc(service string) func(*http.Request) (*url.URL, error) {
+	if r, ok := proxyFuncs.Load(service); ok {
+		return r.(*proxyResolver).proxy
+	}
+	r, _ := proxyFuncs.LoadOrStore(service, newProxyResolver(proxyConfig(service)))
+	return r.(*proxyResolver).proxy
+}
+
+// httpClient returns the client of the sessions of the service, which uses
+// the proxy of the service.
+func httpClient(service string) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = ProxyFunc(service)
+	return &http.Client{Timeout: 1 * time.Minute, Transport: transport}
+}
+
+// proxyConfig returns the proxy configuration of the service. The environment
+// variables of the service override HTTP_PROXY, HTTPS_PROXY and NO_PROXY,
+// even if they are empty to reach the service directly.
+func proxyConfig(service string) *httpproxy.Config {
+	cfg := httpproxy.FromEnvironment()
+	if service == "" {
+		return cfg
+	}
+	if val, ok := os.LookupEnv(envconfig.ServiceProxy(service, envconfig.HTTP_PROXY)); ok {
+		cfg.HTTPProxy = val
+	}
+	if val, ok := os.LookupEnv(envconfig.ServiceProxy(service, envconfig.HTTPS_PROXY)); ok {
+		cfg.HTTPSProxy = val
+	}
+	if val, ok := os.LookupEnv(envconfig.ServiceProxy(service, envconfig.NO_PROXY)); ok {
+		cfg.NoProxy = val
+	}
+	return cfg
+}
+
+// proxyResolver extends the NO_PROXY matching of the host names with the
+// CIDR entries, so the host names resolving to the addresses of the entries,
+// e.g. the interface VPC endpoints with private DNS, are reached directly.
+type proxyResolver struct {
+	proxyFunc   func(*url.URL) (*url.URL, error)
+	noProxyNets []*net.IPNet
+
+	mu     sync.Mutex
+	direct map[string]bool
+}
+
+func newProxyResolver(cfg *httpproxy.Config) *proxyResolver {
+	entries := DefaultNoProxy
+	if cfg.NoProxy != "" {
+		entries = append(strings.Split(cfg.NoProxy, ","), DefaultNoProxy...)
+	}
+	r := &proxyResolver{direct: make(map[string]bool)}
+	for _, entry := range entries {
+		if _, ipNet, err := net.ParseCIDR(strings.TrimSpace(entry)); err == nil {
+			r.noProxyNets = append(r.noProxyNets, ipNet)
+		}
+	}
+	cfg.NoProxy = strings.Join(entries, ",")
+	r.proxyFunc = cfg.ProxyFunc()
+	return r
+}
+
+func (r *proxyResolver) proxy(req *http.Request) (*url.URL, error) {
+	proxy, err := r.proxyFunc(req.URL)
+	if err != nil || proxy == nil {
+		return proxy, err
+	}
+	if r.isDirect(req.URL.Hostname()) {
+		return nil, nil
+	}
+	return proxy, nil
+}
+
+// isDirect returns whether all the addresses of the host name are in the
+// CIDR entries of NO_PROXY. The addresses are already matched by the proxy
+// function.
+func (r *proxyResolver) isDirect(host string) bool {
+	if len(r.noProxyNets) == 0 || host == "" || net.ParseIP(host) != nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if direct, ok := r.direct[host]; ok {
+		return direct
+	}
+	addrs, err := lookupHost(host)
+	if err != nil {
+		// not cached so the host is resolved again with the next request
+		log.Printf("D! Unable to resolve %s to match the NO_PROXY addresses: %v", host, err)
+		return false
+	}
+	direct := len(addrs) > 0
+	for _, addr := range addrs {
+		if !r.containsAddr(addr) {
+			direct = false
+			break
+		}
+	}
+	if direct {
+		log.Printf("D! Reaching %s without the proxy since it resolves to the NO_PROXY addresses", host)
+	}
+	r.direct[host] = direct
+	return direct
+}
+
+func (r *proxyResolver) containsAddr(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range r.noProxyNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
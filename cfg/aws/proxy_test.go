@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+)
+
+func TestProxyResolver(t *testing.T) {
+	defer func(original func(string) ([]string, error)) {
+		lookupHost = original
+	}(lookupHost)
+	addrs := map[string][]string{
+		"logs.us-east-1.amazonaws.com":       {"10.0.1.12", "10.0.2.12"},
+		"monitoring.us-east-1.amazonaws.com": {"3.236.94.131"},
+		"xray.us-east-1.amazonaws.com":       {"10.0.1.13", "3.236.94.132"},
+	}
+	lookupHost = func(host string) ([]string, error) {
+		if addrs, ok := addrs[host]; ok {
+			return addrs, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	for _, name := range []string{"HTTP_PROXY", "http_proxy", "https_proxy", "no_proxy"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv(envconfig.HTTPS_PROXY, "http://proxy:3128")
+	t.Setenv(envconfig.NO_PROXY, "10.0.0.0/8")
+	t.Setenv(envconfig.ServiceProxy(ProxyServiceLogs, envconfig.HTTPS_PROXY), "http://logs-proxy:3128")
+	t.Setenv(envconfig.ServiceProxy(ProxyServiceLogs, envconfig.NO_PROXY), "")
+	t.Setenv(envconfig.ServiceProxy(ProxyServiceSSM, envconfig.HTTPS_PROXY), "")
+
+	testCases := map[string]struct {
+		service string
+		url     string
+		want    string
+	}{
+		"WithInterfaceEndpoint":    {url: "https://logs.us-east-1.amazonaws.com", want: ""},
+		"WithPublicEndpoint":       {url: "https://monitoring.us-east-1.amazonaws.com", want: "http://proxy:3128"},
+		"WithMixedAddresses":       {url: "https://xray.us-east-1.amazonaws.com", want: "http://proxy:3128"},
+		"WithUnresolvedHost":       {url: "https://unknown.amazonaws.com", want: "http://proxy:3128"},
+		"WithIMDS":                 {url: "http://169.254.169.254/latest/api/token", want: ""},
+		"WithIMDSv6":               {url: "http://[fd00:ec2::254]/latest/api/token", want: ""},
+		"WithVPCEndpointName":      {url: "https://vpce-0123-abcd.logs.us-east-1.vpce.amazonaws.com", want: ""},
+		"WithHTTP":                 {url: "http://monitoring.us-east-1.amazonaws.com", want: ""},
+		"WithServiceProxy":         {service: ProxyServiceLogs, url: "https://monitoring.us-east-1.amazonaws.com", want: "http://logs-proxy:3128"},
+		"WithServiceNoProxy":       {service: ProxyServiceLogs, url: "https://logs.us-east-1.amazonaws.com", want: "http://logs-proxy:3128"},
+		"WithServiceIMDS":          {service: ProxyServiceLogs, url: "http://169.254.169.254/latest/api/token", want: ""},
+		"WithServiceWithoutProxy":  {service: ProxyServiceSSM, url: "https://ssm.us-east-1.amazonaws.com", want: ""},
+		"WithServiceDefaultConfig": {service: ProxyServiceMetrics, url: "https://monitoring.us-east-1.amazonaws.com", want: "http://proxy:3128"},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := newProxyResolver(proxyConfig(testCase.service))
+			req, err := http.NewRequest(http.MethodGet, testCase.url, nil)
+			require.NoError(t, err)
+			got, err := r.proxy(req)
+			require.NoError(t, err)
+			if testCase.want == "" {
+				assert.Nil(t, got)
+			} else {
+				require.NotNil(t, got)
+				assert.Equal(t, testCase.want, got.String())
+			}
+		})
+	}
+}
+
+func TestHTTPClient(t *testing.T) {
+	client := httpClient(ProxyServiceMetrics)
+	transport, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.NotNil(t, transport.Proxy)
+	assert.NotZero(t, client.Timeout)
+}
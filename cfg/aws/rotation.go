@@ -0,0 +1,38 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// sessionCredentials are the credentials of the sessions created by the agent.
+var sessionCredentials = struct {
+	sync.Mutex
+	credentials map[*credentials.Credentials]struct{}
+}{credentials: map[*credentials.Credentials]struct{}{}}
+
+func trackCredentials(creds *credentials.Credentials) {
+	if creds == nil {
+		return
+	}
+	sessionCredentials.Lock()
+	defer sessionCredentials.Unlock()
+	sessionCredentials.credentials[creds] = struct{}{}
+}
+
+// ExpireCredentials expires the cached credentials of the sessions created by
+// the agent, so they are retrieved again on the next request, e.g. after the
+// keys of the shared credentials file are rotated. Returns the number of
+// credentials expired.
+func ExpireCredentials() int {
+	sessionCredentials.Lock()
+	defer sessionCredentials.Unlock()
+	for creds := range sessionCredentials.credentials {
+		creds.Expire()
+	}
+	return len(sessionCredentials.credentials)
+}
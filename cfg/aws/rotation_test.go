@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpireCredentials(t *testing.T) {
+	creds := credentials.NewCredentials(&credentials.StaticProvider{Value: credentials.Value{AccessKeyID: "key", SecretAccessKey: "secret"}})
+	_, err := creds.Get()
+	assert.NoError(t, err)
+	assert.False(t, creds.IsExpired())
+
+	trackCredentials(creds)
+	trackCredentials(creds)
+	trackCredentials(nil)
+	assert.GreaterOrEqual(t, ExpireCredentials(), 1)
+	assert.True(t, creds.IsExpired())
+}
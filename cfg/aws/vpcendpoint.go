@@ -0,0 +1,135 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/agent"
+)
+
+// ec2MetadataServiceID is the service of the IMDS client, which is not
+// reached through the VPC endpoints.
+const ec2MetadataServiceID = "ec2metadata"
+
+// lookupHost resolves the endpoint host names. Overridden in tests.
+var lookupHost = net.LookupHost
+
+// VPCEndpointDiscoveryEnabled returns whether the AWS clients probe the
+// endpoints of the services for interface VPC endpoints. It is set with
+// agent.vpc_endpoint_discovery.
+func VPCEndpointDiscoveryEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(envconfig.CWAGENT_VPC_ENDPOINT_DISCOVERY))
+	return enabled
+}
+
+// vpcEndpointResolver resolves the endpoints with the default resolver and
+// prefers the standard regional endpoint if only its name resolves to an
+// interface VPC endpoint, e.g. instead of the global STS or the dual-stack
+// endpoints which the private DNS of the VPC endpoints does not cover.
+type vpcEndpointResolver struct {
+	resolver endpoints.Resolver
+
+	mu       sync.Mutex
+	resolved map[string]endpoints.ResolvedEndpoint
+}
+
+var (
+	vpcEndpointResolverSingleton *vpcEndpointResolver
+	vpcEndpointResolverOnce      sync.Once
+	dualStackResolverSingleton   *dualStackResolver
+	dualStackResolverOnce        sync.Once
+)
+
+// endpointResolver returns the resolver of the sessions, which is nil to use
+// the default resolver unless the VPC endpoint discovery or the dual-stack
+// endpoints are enabled. The VPC endpoints are probed after the dual-stack
+// endpoints are resolved.
+func endpointResolver() endpoints.Resolver {
+	if VPCEndpointDiscoveryEnabled() {
+		vpcEndpointResolverOnce.Do(func() {
+			vpcEndpointResolverSingleton = newVPCEndpointResolver(newDualStackResolver(endpoints.DefaultResolver()))
+		})
+		return vpcEndpointResolverSingleton
+	}
+	if DualStackEndpointEnabled() {
+		dualStackResolverOnce.Do(func() {
+			dualStackResolverSingleton = newDualStackResolver(endpoints.DefaultResolver())
+		})
+		return dualStackResolverSingleton
+	}
+	return nil
+}
+
+func newVPCEndpointResolver(resolver endpoints.Resolver) *vpcEndpointResolver {
+	return &vpcEndpointResolver{
+		resolver: resolver,
+		resolved: make(map[string]endpoints.ResolvedEndpoint),
+	}
+}
+
+func (r *vpcEndpointResolver) EndpointFor(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+	endpoint, err := r.resolver.EndpointFor(service, region, opts...)
+	if err != nil || service == ec2MetadataServiceID {
+		return endpoint, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if resolved, ok := r.resolved[endpoint.URL]; ok {
+		return resolved, nil
+	}
+	resolved, ok := endpoint, r.isInterfaceEndpoint(endpoint.URL)
+	if !ok {
+		regional, err := r.resolver.EndpointFor(service, region, append(opts, regionalEndpointOption)...)
+		if err == nil && regional.URL != endpoint.URL && r.isInterfaceEndpoint(regional.URL) {
+			resolved, ok = regional, true
+		}
+	}
+	if ok {
+		log.Printf("I! Using the interface VPC endpoint %s for %s in %s", resolved.URL, service, region)
+		agent.UsageFlags().Set(agent.FlagVPCEndpoint)
+	} else {
+		log.Printf("D! Using the public endpoint %s for %s in %s", resolved.URL, service, region)
+	}
+	r.resolved[endpoint.URL] = resolved
+	return resolved, nil
+}
+
+// isInterfaceEndpoint returns whether the host of the endpoint only resolves
+// to private addresses, which are the addresses of the network interfaces of
+// an interface VPC endpoint with private DNS enabled.
+func (r *vpcEndpointResolver) isInterfaceEndpoint(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	addrs, err := lookupHost(u.Hostname())
+	if err != nil {
+		log.Printf("D! Unable to resolve the endpoint %s: %v", u.Hostname(), err)
+		return false
+	}
+	for _, addr := range addrs {
+		if ip := net.ParseIP(addr); ip == nil || !ip.IsPrivate() {
+			return false
+		}
+	}
+	return len(addrs) > 0
+}
+
+// regionalEndpointOption resolves the standard regional endpoint, which is
+// the name covered by the private DNS of the interface VPC endpoints. The
+// FIPS option is kept.
+func regionalEndpointOption(o *endpoints.Options) {
+	standardEndpointOption(o)
+	o.STSRegionalEndpoint = endpoints.RegionalSTSEndpoint
+	o.S3UsEast1RegionalEndpoint = endpoints.RegionalS3UsEast1Endpoint
+}
@@ -0,0 +1,109 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+)
+
+func TestVPCEndpointResolver(t *testing.T) {
+	defer func(original func(string) ([]string, error)) {
+		lookupHost = original
+	}(lookupHost)
+	testCases := map[string]struct {
+		service string
+		opts    []func(*endpoints.Options)
+		addrs   map[string][]string
+		want    string
+	}{
+		"WithInterfaceEndpoint": {
+			service: "logs",
+			addrs:   map[string][]string{"logs.us-east-1.amazonaws.com": {"10.0.1.12", "10.0.2.12"}},
+			want:    "https://logs.us-east-1.amazonaws.com",
+		},
+		"WithPublicEndpoint": {
+			service: "logs",
+			addrs:   map[string][]string{"logs.us-east-1.amazonaws.com": {"3.236.94.131"}},
+			want:    "https://logs.us-east-1.amazonaws.com",
+		},
+		"WithMixedAddresses": {
+			service: "logs",
+			addrs:   map[string][]string{"logs.us-east-1.amazonaws.com": {"10.0.1.12", "3.236.94.131"}},
+			want:    "https://logs.us-east-1.amazonaws.com",
+		},
+		"WithRegionalInterfaceEndpoint": {
+			service: "sts",
+			opts:    []func(*endpoints.Options){func(o *endpoints.Options) { o.STSRegionalEndpoint = endpoints.LegacySTSEndpoint }},
+			addrs: map[string][]string{
+				"sts.amazonaws.com":           {"209.54.177.164"},
+				"sts.us-east-1.amazonaws.com": {"10.0.1.40"},
+			},
+			want: "https://sts.us-east-1.amazonaws.com",
+		},
+		"WithDualStackInterfaceEndpoint": {
+			service: "monitoring",
+			opts:    []func(*endpoints.Options){func(o *endpoints.Options) { o.UseDualStackEndpoint = endpoints.DualStackEndpointStateEnabled }},
+			addrs: map[string][]string{
+				"monitoring.us-east-1.amazonaws.com": {"10.0.1.41"},
+			},
+			want: "https://monitoring.us-east-1.amazonaws.com",
+		},
+		"WithUnresolvedEndpoint": {
+			service: "monitoring",
+			want:    "https://monitoring.us-east-1.amazonaws.com",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var lookups int
+			lookupHost = func(host string) ([]string, error) {
+				lookups++
+				if addrs, ok := testCase.addrs[host]; ok {
+					return addrs, nil
+				}
+				return nil, errors.New("no such host")
+			}
+			r := newVPCEndpointResolver(endpoints.DefaultResolver())
+			got, err := r.EndpointFor(testCase.service, "us-east-1", testCase.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, got.URL)
+			// the endpoints are only probed once
+			lookupCount := lookups
+			got, err = r.EndpointFor(testCase.service, "us-east-1", testCase.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, got.URL)
+			assert.Equal(t, lookupCount, lookups)
+		})
+	}
+}
+
+func TestVPCEndpointResolverIMDS(t *testing.T) {
+	defer func(original func(string) ([]string, error)) {
+		lookupHost = original
+	}(lookupHost)
+	lookupHost = func(string) ([]string, error) {
+		t.Fatal("the IMDS endpoint is probed")
+		return nil, nil
+	}
+	r := newVPCEndpointResolver(endpoints.DefaultResolver())
+	got, err := r.EndpointFor(ec2MetadataServiceID, "us-east-1")
+	require.NoError(t, err)
+	assert.Equal(t, "http://169.254.169.254/latest", got.URL)
+}
+
+func TestEndpointResolver(t *testing.T) {
+	t.Setenv(envconfig.CWAGENT_VPC_ENDPOINT_DISCOVERY, "")
+	assert.False(t, VPCEndpointDiscoveryEnabled())
+	assert.Nil(t, endpointResolver())
+	t.Setenv(envconfig.CWAGENT_VPC_ENDPOINT_DISCOVERY, "true")
+	assert.True(t, VPCEndpointDiscoveryEnabled())
+	assert.NotNil(t, endpointResolver())
+}
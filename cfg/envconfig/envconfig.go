@@ -7,31 +7,50 @@ import (
 	"os"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 )
 
 const (
 	//the following are the names of environment variables
-	HTTP_PROXY                = "HTTP_PROXY"
-	HTTPS_PROXY               = "HTTPS_PROXY"
-	NO_PROXY                  = "NO_PROXY"
-	AWS_CA_BUNDLE             = "AWS_CA_BUNDLE"
-	AWS_SDK_LOG_LEVEL         = "AWS_SDK_LOG_LEVEL"
-	CWAGENT_USER_AGENT        = "CWAGENT_USER_AGENT"
-	CWAGENT_LOG_LEVEL         = "CWAGENT_LOG_LEVEL"
-	CWAGENT_USAGE_DATA        = "CWAGENT_USAGE_DATA"
-	IMDS_NUMBER_RETRY         = "IMDS_NUMBER_RETRY"
-	RunInContainer            = "RUN_IN_CONTAINER"
-	RunAsHostProcessContainer = "RUN_AS_HOST_PROCESS_CONTAINER"
-	RunInAWS                  = "RUN_IN_AWS"
-	RunWithIRSA               = "RUN_WITH_IRSA"
-	UseDefaultConfig          = "USE_DEFAULT_CONFIG"
-	HostName                  = "HOST_NAME"
-	PodName                   = "POD_NAME"
-	HostIP                    = "HOST_IP"
-	CWConfigContent           = "CW_CONFIG_CONTENT"
-	CWOtelConfigContent       = "CW_OTEL_CONFIG_CONTENT"
-	CWAgentMergedOtelConfig   = "CWAGENT_MERGED_OTEL_CONFIG"
+	HTTP_PROXY                     = "HTTP_PROXY"
+	HTTPS_PROXY                    = "HTTPS_PROXY"
+	NO_PROXY                       = "NO_PROXY"
+	AWS_CA_BUNDLE                  = "AWS_CA_BUNDLE"
+	AWS_SDK_LOG_LEVEL              = "AWS_SDK_LOG_LEVEL"
+	AWS_USE_FIPS_ENDPOINT          = "AWS_USE_FIPS_ENDPOINT"
+	AWS_EC2_METADATA_V1_DISABLED   = "AWS_EC2_METADATA_V1_DISABLED"
+	CWAGENT_USER_AGENT             = "CWAGENT_USER_AGENT"
+	CWAGENT_LOG_LEVEL              = "CWAGENT_LOG_LEVEL"
+	CWAGENT_USAGE_DATA             = "CWAGENT_USAGE_DATA"
+	CWAGENT_ADMIN_API              = "CWAGENT_ADMIN_API"
+	CWAGENT_PPROF_PORT             = "CWAGENT_PPROF_PORT"
+	CWAGENT_PROFILE_THRESHOLD_MB   = "CWAGENT_PROFILE_THRESHOLD_MB"
+	CWAGENT_PROFILE_INTERVAL       = "CWAGENT_PROFILE_INTERVAL"
+	CWAGENT_VPC_ENDPOINT_DISCOVERY = "CWAGENT_VPC_ENDPOINT_DISCOVERY"
+	CWAGENT_WATCHDOG_TIMEOUT       = "CWAGENT_WATCHDOG_TIMEOUT"
+	CWAGENT_FEATURE_GATES          = "CWAGENT_FEATURE_GATES"
+	CWAGENT_ROLE_ARN               = "CWAGENT_ROLE_ARN"
+	CWAGENT_PROFILE                = "CWAGENT_PROFILE"
+	CWAGENT_SHARED_CREDENTIAL_FILE = "CWAGENT_SHARED_CREDENTIAL_FILE"
+	IMDS_NUMBER_RETRY              = "IMDS_NUMBER_RETRY"
+	RunInContainer                 = "RUN_IN_CONTAINER"
+	RunAsHostProcessContainer      = "RUN_AS_HOST_PROCESS_CONTAINER"
+	RunInAWS                       = "RUN_IN_AWS"
+	RunWithIRSA                    = "RUN_WITH_IRSA"
+	UseDefaultConfig               = "USE_DEFAULT_CONFIG"
+	HostName                       = "HOST_NAME"
+	PodName                        = "POD_NAME"
+	HostIP                         = "HOST_IP"
+	CWConfigContent                = "CW_CONFIG_CONTENT"
+	CWOtelConfigContent            = "CW_OTEL_CONFIG_CONTENT"
+	CWAgentMergedOtelConfig        = "CWAGENT_MERGED_OTEL_CONFIG"
+)
+
+const (
+	// the environment variables read by the AWS SDK to reach the IPv6 endpoints
+	AWS_USE_DUALSTACK_ENDPOINT             = "AWS_USE_DUALSTACK_ENDPOINT"
+	AWS_EC2_METADATA_SERVICE_ENDPOINT_MODE = "AWS_EC2_METADATA_SERVICE_ENDPOINT_MODE"
 )
 
 const (
@@ -58,6 +77,12 @@ func IsUsageDataEnabled() bool {
 	return usageDataEnabled
 }
 
+// ServiceProxy returns the name of the environment variable overriding the
+// proxy variable for the clients of the service, e.g. CWAGENT_LOGS_HTTPS_PROXY.
+func ServiceProxy(service, name string) string {
+	return "CWAGENT_" + strings.ToUpper(service) + "_" + name
+}
+
 func IsRunningInContainer() bool {
 	return os.Getenv(RunInContainer) == TrueValue
 }
@@ -8,12 +8,14 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/aws/amazon-cloudwatch-agent/tool/data"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors/basicInfo"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors/migration/linux"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors/migration/windows"
+	"github.com/aws/amazon-cloudwatch-agent/tool/processors/profile"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors/serialization"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors/tracesconfig"
 	"github.com/aws/amazon-cloudwatch-agent/tool/runtime"
@@ -54,6 +56,19 @@ func main() {
 	parameterStoreName := flag.String("parameterStoreName", "", "The parameter store name. Default is AmazonCloudWatch-windows")
 	parameterStoreRegion := flag.String("parameterStoreRegion", "", "The parameter store region. Default is us-east-1")
 
+	// Parse command line args for the non-interactive config generation, which override the profile
+	nonInteractive := flag.Bool("nonInteractive", false,
+		"If true, the config is generated from the profile and the command line args without asking any question, and printed to stdout.")
+	profilePath := flag.String("profile", "", "The path of the JSON profile of the non-interactive config generation")
+	flag.String("os", "", "The OS of the agent: linux, windows or darwin. Default is the current OS")
+	flag.String("environment", "", "Where the agent runs: ec2, onPrem, ecs or eks. Default is ec2")
+	flag.String("region", "", "The region of the agent")
+	flag.String("runAsUser", "", "The user running the agent")
+	flag.String("metricsPlan", "", "The host metrics: basic, standard, advanced or none. Default is basic on hosts and none in containers")
+	flag.String("presets", "", "Comma separated presets: container_insights_enhanced and application_signals")
+	flag.String("clusterName", "", "The EKS cluster name. Default is detected by the agent")
+	validate := flag.Bool("validate", false, "If true, the generated config is translated to validate it before it is written")
+
 	flag.Parse()
 
 	if *isNonInteractiveWindowsMigration {
@@ -78,6 +93,18 @@ func main() {
 		}
 		process(ctx, config, tracesconfig.Processor, serialization.Processor)
 		return
+	} else if *nonInteractive {
+		p, err := loadProfile(*profilePath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		ctx := new(runtime.Context)
+		config := new(data.Config)
+		ctx.ConfigOutputPath = *configOutputPath
+		ctx.ValidateConfig = *validate
+		process(ctx, config, profile.NewProcessor(p), serialization.Processor)
+		return
 	}
 
 	startProcessing()
@@ -106,6 +133,46 @@ func addWindowsMigrationInputs(configFilePath string, parameterStoreName string,
 	}
 }
 
+// loadProfile reads the profile, if any, and overrides it with the command
+// line args that are set.
+func loadProfile(profilePath string) (*profile.Profile, error) {
+	p := new(profile.Profile)
+	if profilePath != "" {
+		var err error
+		if p, err = profile.Load(profilePath); err != nil {
+			return nil, err
+		}
+	}
+	flag.Visit(func(f *flag.Flag) {
+		value := f.Value.String()
+		switch f.Name {
+		case "os":
+			p.OS = value
+		case "environment":
+			p.Environment = value
+		case "region":
+			p.Region = value
+		case "runAsUser":
+			p.RunAsUser = value
+		case "metricsPlan":
+			p.MetricsPlan = value
+		case "presets":
+			p.Presets = nil
+			for _, preset := range strings.Split(value, ",") {
+				if preset = strings.TrimSpace(preset); preset != "" {
+					p.Presets = append(p.Presets, preset)
+				}
+			}
+		case "clusterName":
+			p.ClusterName = value
+		}
+	})
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
 func process(ctx *runtime.Context, config *data.Config, processors ...processors.Processor) {
 	for _, processor := range processors {
 		processor.Process(ctx, config)
@@ -0,0 +1,114 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/influxdata/wlog"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/otelcol"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/internal/admin"
+	"github.com/aws/amazon-cloudwatch-agent/internal/mapstructure"
+	cwaLogger "github.com/aws/amazon-cloudwatch-agent/logger"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/translator/tocwconfig/toyamlconfig"
+)
+
+// adminHandler performs the operations of the admin API on the running agent.
+type adminHandler struct {
+	mu        sync.RWMutex
+	config    string
+	pipelines []admin.Pipeline
+}
+
+var _ admin.Handler = (*adminHandler)(nil)
+
+// setConfig stores the effective OTel configuration of the running agent.
+func (h *adminHandler) setConfig(cfg *otelcol.Config) error {
+	result, err := mapstructure.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	var pipelines []admin.Pipeline
+	for id, pipeline := range cfg.Service.Pipelines {
+		pipelines = append(pipelines, admin.Pipeline{
+			Name:       id.String(),
+			Receivers:  toStrings(pipeline.Receivers),
+			Processors: toStrings(pipeline.Processors),
+			Exporters:  toStrings(pipeline.Exporters),
+		})
+	}
+	sort.Slice(pipelines, func(i, j int) bool {
+		return pipelines[i].Name < pipelines[j].Name
+	})
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.config = toyamlconfig.ToYamlConfig(result)
+	h.pipelines = pipelines
+	return nil
+}
+
+func (h *adminHandler) Config() string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.config
+}
+
+func (h *adminHandler) Pipelines() []admin.Pipeline {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.pipelines
+}
+
+func (h *adminHandler) RescanLogFiles() {
+	log.Println("I! [admin] Rescanning the log files")
+	logs.RequestRescan()
+}
+
+func (h *adminHandler) RotateCredentials() int {
+	expired := configaws.ExpireCredentials()
+	log.Printf("I! [admin] Expired %d cached credentials", expired)
+	return expired
+}
+
+func (h *adminHandler) SetLogLevel(level string) error {
+	if err := wlog.SetLevelFromName(level); err != nil {
+		return err
+	}
+	cwaLogger.SetLevel(cwaLogger.ConvertToAtomicLevel(wlog.LogLevel()))
+	log.Printf("I! [admin] Set the log level to %s", level)
+	return nil
+}
+
+// startAdminServer serves the admin API on the socket at path if it is enabled
+// in the env config. Returns the function stopping the server, or nil.
+func startAdminServer(path string, handler admin.Handler) func() {
+	if enabled, _ := strconv.ParseBool(os.Getenv(envconfig.CWAGENT_ADMIN_API)); !enabled {
+		return nil
+	}
+	server, err := admin.NewServer(path, handler)
+	if err != nil {
+		log.Printf("E! Unable to start the admin API: %v", err)
+		return nil
+	}
+	log.Printf("I! Serving the admin API on %s", path)
+	server.Start()
+	return server.Stop
+}
+
+func toStrings(ids []component.ID) []string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+	return values
+}
@@ -0,0 +1,51 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+
+	"github.com/influxdata/wlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/otelcol"
+	"go.opentelemetry.io/collector/service"
+	"go.opentelemetry.io/collector/service/pipelines"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/admin"
+)
+
+func TestAdminHandler(t *testing.T) {
+	handler := &adminHandler{}
+	assert.Empty(t, handler.Config())
+	assert.Empty(t, handler.Pipelines())
+
+	cfg := &otelcol.Config{
+		Service: service.Config{
+			Pipelines: map[component.ID]*pipelines.PipelineConfig{
+				component.MustNewIDWithName("metrics", "host"): {
+					Receivers:  []component.ID{component.MustNewID("telegraf_cpu")},
+					Processors: []component.ID{component.MustNewID("batch")},
+					Exporters:  []component.ID{component.MustNewID("awscloudwatch")},
+				},
+				component.MustNewIDWithName("logs", "emf"): {
+					Receivers: []component.ID{component.MustNewID("awsemf")},
+					Exporters: []component.ID{component.MustNewID("awscloudwatchlogs")},
+				},
+			},
+		},
+	}
+	require.NoError(t, handler.setConfig(cfg))
+	assert.Contains(t, handler.Config(), "metrics/host:")
+	assert.Equal(t, []admin.Pipeline{
+		{Name: "logs/emf", Receivers: []string{"awsemf"}, Processors: []string{}, Exporters: []string{"awscloudwatchlogs"}},
+		{Name: "metrics/host", Receivers: []string{"telegraf_cpu"}, Processors: []string{"batch"}, Exporters: []string{"awscloudwatch"}},
+	}, handler.Pipelines())
+
+	defer wlog.SetLevel(wlog.LogLevel())
+	require.NoError(t, handler.SetLogLevel("DEBUG"))
+	assert.Equal(t, wlog.DEBUG, wlog.LogLevel())
+	assert.Error(t, handler.SetLogLevel("VERBOSE"))
+}
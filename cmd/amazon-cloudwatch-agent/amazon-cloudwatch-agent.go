@@ -16,6 +16,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strings"
 	"syscall"
@@ -29,6 +30,7 @@ import (
 	"github.com/influxdata/wlog"
 	"github.com/kardianos/service"
 	"go.opentelemetry.io/collector/component"
+	otelfeaturegate "go.opentelemetry.io/collector/featuregate"
 	"go.opentelemetry.io/collector/otelcol"
 	"go.uber.org/zap"
 
@@ -36,9 +38,13 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
 	"github.com/aws/amazon-cloudwatch-agent/cmd/amazon-cloudwatch-agent/internal"
 	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/useragent"
+	"github.com/aws/amazon-cloudwatch-agent/internal/admin"
+	"github.com/aws/amazon-cloudwatch-agent/internal/constants"
+	"github.com/aws/amazon-cloudwatch-agent/internal/enrollment"
 	"github.com/aws/amazon-cloudwatch-agent/internal/mapstructure"
 	"github.com/aws/amazon-cloudwatch-agent/internal/merge/confmap"
 	"github.com/aws/amazon-cloudwatch-agent/internal/version"
+	"github.com/aws/amazon-cloudwatch-agent/internal/winservice"
 	cwaLogger "github.com/aws/amazon-cloudwatch-agent/logger"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins"
@@ -53,6 +59,9 @@ import (
 
 const (
 	defaultEnvCfgFileName = "env-config.json"
+	// logAgentShutdownTimeout bounds the wait for the log sources to save
+	// their state once the pipelines have stopped.
+	logAgentShutdownTimeout = 5 * time.Second
 )
 
 var fDebug = flag.Bool("debug", false,
@@ -94,9 +103,21 @@ var fServiceDisplayName = flag.String("service-display-name", "Telegraf Data Col
 var fRunAsConsole = flag.Bool("console", false, "run as console application (windows only)")
 var fSetEnv = flag.String("setenv", "", "set an env in the configuration file in the format of KEY=VALUE")
 var fStartUpErrorFile = flag.String("startup-error-file", "", "file to touch if agent can't start")
+var fEnrollEndpoint = flag.String("enroll-endpoint", "", "enroll the host with the management endpoint, store the signed identity in the trust store, and exit")
+var fEnrollTokenFile = flag.String("enroll-token-file", "", "file containing the one-time enrollment token, removed once enrolled")
+var fEnrollCA = flag.String("enroll-ca", "", "PEM file of the CAs trusted for the enrollment endpoint, uses the system roots if empty")
+var fAdmin = flag.String("admin", "", "call the admin API of the running agent with the command (dump-config, list-pipelines, rescan-logs, rotate-credentials, set-log-level <level>) and exit")
+var fAdminSocket = flag.String("admin-socket", paths.AdminSocketPath, "unix socket of the admin API")
+var fDryRun = flag.Bool("dry-run", false, "replay the fixtures of --replay through the translated configuration without sending anything, print what would be sent, and exit")
+var fReplay = flag.String("replay", "", "directory of the recorded OTLP JSON (.json), StatsD (.statsd) and log (.log) fixtures replayed with --dry-run")
+var fReplayOutput = flag.String("replay-output", "", "directory the stubbed exporters write to with --dry-run, the output directory of --replay if empty")
 
 var stop chan struct{}
 
+// collectorCtx is only cancelled to shut down the OTEL collector when handing
+// over to a new agent process. The collector handles the other signals itself.
+var collectorCtx, stopCollector = context.WithCancel(context.Background())
+
 func reloadLoop(
 	stop chan struct{},
 	inputFilters []string,
@@ -112,19 +133,28 @@ func reloadLoop(
 		ctx, cancel := context.WithCancel(context.Background())
 
 		signals := make(chan os.Signal)
-		signal.Notify(signals, os.Interrupt, syscall.SIGHUP,
-			syscall.SIGTERM, syscall.SIGINT)
+		signal.Notify(signals, append([]os.Signal{os.Interrupt, syscall.SIGHUP,
+			syscall.SIGTERM, syscall.SIGINT}, handoverSignals...)...)
 		go func() {
-			select {
-			case sig := <-signals:
-				if sig == syscall.SIGHUP {
-					log.Println("I! Reloading Telegraf config")
-					<-reload
-					reload <- true
+			for {
+				select {
+				case sig := <-signals:
+					if sig == syscall.SIGHUP {
+						log.Println("I! Reloading Telegraf config")
+						<-reload
+						reload <- true
+					} else if slices.Contains(handoverSignals, sig) {
+						// keep running if the new agent process could not take over
+						if !startHandover() {
+							continue
+						}
+						stopCollector()
+					}
+					cancel()
+				case <-stop:
+					cancel()
 				}
-				cancel()
-			case <-stop:
-				cancel()
+				return
 			}
 		}()
 
@@ -178,6 +208,10 @@ func reloadLoop(
 		}
 
 		err := runAgent(ctx, inputFilters, outputFilters)
+		if reason := getWedgedReason(); reason != "" {
+			log.Printf("E! Exiting for the service manager to restart the agent, %s", reason)
+			os.Exit(constants.ExitCodeWatchdog)
+		}
 		if err != nil && err != context.Canceled {
 			if *fStartUpErrorFile != "" {
 				f, err := os.OpenFile(*fStartUpErrorFile, os.O_CREATE|os.O_WRONLY, 0644)
@@ -287,6 +321,21 @@ func runAgent(ctx context.Context,
 	} else {
 		log.Printf("I! AWS SDK log level, %s\n", sdkLogLevel)
 	}
+	// The FIPS endpoints must also be enabled before the AWS clients are created.
+	if configaws.DetectFIPSEndpoint() {
+		log.Println("I! Using the FIPS endpoints of the AWS services")
+	}
+	if configaws.DetectIPv6Only() {
+		log.Println("I! The host is IPv6-only")
+	}
+	if configaws.DualStackEndpointEnabled() {
+		log.Println("I! Using the dual-stack endpoints of the AWS services")
+	}
+	if configaws.VPCEndpointDiscoveryEnabled() {
+		log.Println("I! Discovering the interface VPC endpoints of the AWS services")
+	}
+	// The components check their feature gates when they are created.
+	loadFeatureGates(otelfeaturegate.GlobalRegistry(), paths.FeatureGatesPath)
 
 	if *fTest || *fTestWait != 0 {
 		testWaitDuration := time.Duration(*fTestWait) * time.Second
@@ -310,11 +359,33 @@ func runAgent(ctx context.Context,
 		}
 	}
 
+	adminHandler := &adminHandler{}
+	if stopAdminServer := startAdminServer(paths.AdminSocketPath, adminHandler); stopAdminServer != nil {
+		defer stopAdminServer()
+	}
+	if stopProfiler := startProfiler(ag.Config.Agent.Logfile); stopProfiler != nil {
+		defer stopProfiler()
+	}
+	ctx, stopAgent := context.WithCancel(ctx)
+	defer stopAgent()
+	if stopWatchdog := startWatchdog(func() {
+		stopAgent()
+		stopCollector()
+	}); stopWatchdog != nil {
+		defer stopWatchdog()
+	}
+
 	if len(c.Inputs) != 0 && len(c.Outputs) != 0 {
 		log.Println("creating new logs agent")
 		logAgent := logs.NewLogAgent(c)
 		// Always run logAgent as goroutine regardless of whether starting OTEL or Telegraf.
 		go logAgent.Run(ctx)
+		defer func() {
+			stopAgent()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), logAgentShutdownTimeout)
+			defer cancel()
+			_ = logAgent.Shutdown(shutdownCtx)
+		}()
 
 		// If only a single YAML is provided and does not exist, then ASSUME the agent is
 		// just monitoring logs since this is the default when no OTEL config flag is provided.
@@ -370,6 +441,9 @@ func runAgent(ctx context.Context,
 	}
 
 	useragent.Get().SetComponents(cfg, c)
+	if err = adminHandler.setConfig(cfg); err != nil {
+		log.Printf("W! Unable to store the OTEL configuration for the admin API: %v", err)
+	}
 
 	params := getCollectorParams(factories, providerSettings, loggerOptions)
 	cmd := otelcol.NewCommand(params)
@@ -385,7 +459,7 @@ func runAgent(ctx context.Context,
 		e = append(e, "--config="+uri)
 	}
 	cmd.SetArgs(e)
-	return cmd.Execute()
+	return cmd.ExecuteContext(collectorCtx)
 }
 
 func getCollectorParams(factories otelcol.Factories, providerSettings otelcol.ConfigProviderSettings, loggingOptions []zap.Option) otelcol.CollectorSettings {
@@ -607,6 +681,34 @@ func main() {
 			}
 		}
 		return
+	case *fEnrollEndpoint != "":
+		hostname, err := os.Hostname()
+		if err != nil {
+			log.Fatalf("E! Failed to get hostname for enrollment: %v", err)
+		}
+		cfg := enrollment.Config{
+			Endpoint:  *fEnrollEndpoint,
+			TokenFile: *fEnrollTokenFile,
+			CAFile:    *fEnrollCA,
+			Name:      hostname,
+		}
+		if err = enrollment.Run(context.Background(), cfg, enrollment.NewTrustStore(paths.TrustStoreDirPath)); err != nil {
+			log.Fatalf("E! Failed to enroll: %v", err)
+		}
+		return
+	case *fDryRun:
+		if err := runReplay(context.Background(), *fReplay, *fReplayOutput, os.Stdout); err != nil {
+			log.Fatalf("E! Failed to replay the fixtures: %v", err)
+		}
+		return
+	case *fAdmin != "":
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err := admin.Run(ctx, *fAdminSocket, *fAdmin, args, os.Stdout)
+		cancel()
+		if err != nil {
+			log.Fatalf("E! Failed to call the admin API: %v", err)
+		}
+		return
 	}
 
 	if runtime.GOOS == "windows" && windowsRunAsService() {
@@ -648,6 +750,11 @@ func main() {
 			if err != nil {
 				log.Fatal("E! " + err.Error())
 			}
+			if *fService == "install" {
+				if err = winservice.ConfigureRecovery(*fServiceName); err != nil {
+					log.Println("W! " + err.Error())
+				}
+			}
 			os.Exit(0)
 		} else {
 			// When in service mode, register eventlog target and setup default logging to eventlog
@@ -662,6 +769,8 @@ func main() {
 			}
 		}
 	} else {
+		takeOverFromPreviousAgent()
+		serveHandover()
 		stop = make(chan struct{})
 		reloadLoop(
 			stop,
@@ -670,6 +779,7 @@ func main() {
 			aggregatorFilters,
 			processorFilters,
 		)
+		completeHandover()
 	}
 }
 
@@ -0,0 +1,28 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"log"
+
+	otelfeaturegate "go.opentelemetry.io/collector/featuregate"
+
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/agent"
+	"github.com/aws/amazon-cloudwatch-agent/internal/featuregate"
+)
+
+// loadFeatureGates sets the feature gates from the file at path and the env
+// config. The gates that are not in their default state are logged and
+// reported in the agent health. Returns them in the format of the env config.
+func loadFeatureGates(registry *otelfeaturegate.Registry, path string) string {
+	if err := featuregate.Load(registry, path); err != nil {
+		log.Printf("W! Unable to load the feature gates: %v", err)
+	}
+	active := featuregate.Format(featuregate.Active(registry))
+	if active != "" {
+		log.Printf("I! Feature gates: %s", active)
+		agent.UsageFlags().SetValue(agent.FlagFeatureGates, active)
+	}
+	return active
+}
@@ -0,0 +1,31 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otelfeaturegate "go.opentelemetry.io/collector/featuregate"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/agent"
+)
+
+func TestLoadFeatureGates(t *testing.T) {
+	registry := otelfeaturegate.NewRegistry()
+	registry.MustRegister("cwagent.alpha", otelfeaturegate.StageAlpha)
+	registry.MustRegister("cwagent.beta", otelfeaturegate.StageBeta)
+	path := filepath.Join(t.TempDir(), "feature-gates.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"cwagent.alpha": true}`), 0600))
+	t.Setenv(envconfig.CWAGENT_FEATURE_GATES, "-cwagent.beta")
+
+	assert.Equal(t, "+cwagent.alpha,-cwagent.beta", loadFeatureGates(registry, path))
+	got := agent.UsageFlags().GetString(agent.FlagFeatureGates)
+	require.NotNil(t, got)
+	assert.Equal(t, "+cwagent.alpha,-cwagent.beta", *got)
+}
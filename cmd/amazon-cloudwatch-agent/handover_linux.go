@@ -0,0 +1,95 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/internal/handover"
+	"github.com/aws/amazon-cloudwatch-agent/tool/paths"
+)
+
+const handoverSocketName = "handover.sock"
+
+// handoverSignals trigger an upgrade to the agent binary on disk which keeps the
+// statsd listener open.
+var handoverSignals = []os.Signal{syscall.SIGUSR2}
+
+var (
+	handoverServer  *handover.Server
+	handoverSession *handover.Session
+)
+
+// takeOverFromPreviousAgent receives the listeners from the agent process being
+// upgraded and waits for it to flush its state before returning.
+func takeOverFromPreviousAgent() {
+	path, ok := os.LookupEnv(handover.EnvSocket)
+	if !ok {
+		return
+	}
+	_ = os.Unsetenv(handover.EnvSocket)
+	log.Printf("I! Taking over from the previous agent process using %s", path)
+	if err := handover.Receive(path, handover.DefaultTimeout); err != nil {
+		log.Printf("W! Unable to take over from the previous agent process, starting with new listeners: %v", err)
+	}
+}
+
+// serveHandover allows the next agent process to take over on upgrade. The next
+// agent process is started through the start wrapper so that the config is
+// translated by the upgraded translator, which needs the privileges the wrapper
+// runs with.
+func serveHandover() {
+	if envconfig.IsRunningInContainer() {
+		return
+	}
+	path := filepath.Join(paths.AgentDir, "var", handoverSocketName)
+	if os.Geteuid() != 0 {
+		// the agent is restarted on upgrade instead, remove the socket of a previous run
+		_ = os.Remove(path)
+		log.Println("I! Upgrade handover is unavailable since the agent is not running as root")
+		return
+	}
+	server, err := handover.NewServer(path, paths.StartAgentBinaryPath)
+	if err != nil {
+		log.Printf("W! Upgrade handover is unavailable: %v", err)
+		return
+	}
+	handoverServer = server
+}
+
+// startHandover starts the next agent process and passes the listeners to it.
+// Returns true if the current agent process should shut down.
+func startHandover() bool {
+	if handoverServer == nil {
+		log.Println("W! Ignoring upgrade signal since upgrade handover is unavailable")
+		return false
+	}
+	log.Println("I! Handing over to a new agent process")
+	session, err := handoverServer.Handover()
+	if err != nil {
+		log.Printf("E! Unable to hand over to the new agent process: %v", err)
+		return false
+	}
+	handoverSession = session
+	return true
+}
+
+// completeHandover lets the next agent process start once the current one has
+// shut down.
+func completeHandover() {
+	if handoverSession == nil {
+		return
+	}
+	if err := handoverSession.Complete(); err != nil {
+		log.Printf("E! Unable to complete handover to the new agent process: %v", err)
+		return
+	}
+	log.Println("I! Handover to the new agent process complete")
+}
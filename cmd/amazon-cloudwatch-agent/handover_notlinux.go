@@ -0,0 +1,21 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+
+package main
+
+import "os"
+
+// handoverSignals is empty since upgrade handover is only supported on Linux.
+var handoverSignals []os.Signal
+
+func takeOverFromPreviousAgent() {}
+
+func serveHandover() {}
+
+func startHandover() bool {
+	return false
+}
+
+func completeHandover() {}
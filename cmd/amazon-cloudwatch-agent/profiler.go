@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/internal/profiler"
+	"github.com/aws/amazon-cloudwatch-agent/tool/paths"
+)
+
+// profilerConfig returns the profiler configuration from the env config. The
+// profiles are written next to the agent log file.
+func profilerConfig(logfile string) profiler.Config {
+	var cfg profiler.Config
+	if value := os.Getenv(envconfig.CWAGENT_PPROF_PORT); value != "" {
+		port, err := strconv.Atoi(value)
+		if err != nil || port <= 0 || port > 65535 {
+			log.Printf("W! Ignoring the invalid pprof port %q", value)
+		} else {
+			cfg.PprofPort = port
+		}
+	}
+	if value := os.Getenv(envconfig.CWAGENT_PROFILE_THRESHOLD_MB); value != "" {
+		threshold, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			log.Printf("W! Ignoring the invalid profile memory threshold %q", value)
+		} else {
+			cfg.MemoryThreshold = threshold << 20
+		}
+	}
+	if value := os.Getenv(envconfig.CWAGENT_PROFILE_INTERVAL); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			log.Printf("W! Ignoring the invalid profile capture interval %q", value)
+		} else {
+			cfg.CaptureInterval = interval
+		}
+	}
+	if logfile == "" {
+		logfile = paths.AgentLogFilePath
+	}
+	cfg.Dir = filepath.Dir(logfile)
+	return cfg
+}
+
+// startProfiler starts the profiler if it is enabled in the env config.
+// Returns the function stopping it, or nil.
+func startProfiler(logfile string) func() {
+	cfg := profilerConfig(logfile)
+	if !cfg.Enabled() {
+		return nil
+	}
+	p := profiler.New(cfg)
+	if err := p.Start(); err != nil {
+		log.Printf("E! Unable to start the profiler: %v", err)
+		return nil
+	}
+	return p.Stop
+}
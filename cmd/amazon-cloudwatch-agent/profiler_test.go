@@ -0,0 +1,58 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/internal/profiler"
+	"github.com/aws/amazon-cloudwatch-agent/tool/paths"
+)
+
+func TestProfilerConfig(t *testing.T) {
+	testCases := map[string]struct {
+		env     map[string]string
+		logfile string
+		want    profiler.Config
+	}{
+		"WithDefault": {
+			want: profiler.Config{Dir: filepath.Dir(paths.AgentLogFilePath)},
+		},
+		"WithAll": {
+			env: map[string]string{
+				envconfig.CWAGENT_PPROF_PORT:           "6060",
+				envconfig.CWAGENT_PROFILE_THRESHOLD_MB: "512",
+				envconfig.CWAGENT_PROFILE_INTERVAL:     "1800s",
+			},
+			logfile: filepath.Join("var", "log", "agent.log"),
+			want: profiler.Config{
+				PprofPort:       6060,
+				MemoryThreshold: 512 << 20,
+				CaptureInterval: 30 * time.Minute,
+				Dir:             filepath.Join("var", "log"),
+			},
+		},
+		"WithInvalid": {
+			env: map[string]string{
+				envconfig.CWAGENT_PPROF_PORT:           "70000",
+				envconfig.CWAGENT_PROFILE_THRESHOLD_MB: "-1",
+				envconfig.CWAGENT_PROFILE_INTERVAL:     "1h",
+			},
+			want: profiler.Config{CaptureInterval: time.Hour, Dir: filepath.Dir(paths.AgentLogFilePath)},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for _, key := range []string{envconfig.CWAGENT_PPROF_PORT, envconfig.CWAGENT_PROFILE_THRESHOLD_MB, envconfig.CWAGENT_PROFILE_INTERVAL} {
+				t.Setenv(key, testCase.env[key])
+			}
+			assert.Equal(t, testCase.want, profilerConfig(testCase.logfile))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/replay"
+	"github.com/aws/amazon-cloudwatch-agent/service/defaultcomponents"
+	"github.com/aws/amazon-cloudwatch-agent/translator/tocwconfig/toyamlconfig"
+)
+
+// replayOutputDirName is the output directory in the fixture directory when
+// --replay-output is not set.
+const replayOutputDirName = "output"
+
+// runReplay replays the fixtures of the directory through the translated
+// configuration of --config and --otelconfig and writes the report to w.
+func runReplay(ctx context.Context, dir, outputDir string, w io.Writer) error {
+	if dir == "" {
+		return errors.New("--dry-run requires the fixture directory of --replay")
+	}
+	if outputDir == "" {
+		outputDir = filepath.Join(dir, replayOutputDirName)
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("unable to create the output directory: %w", err)
+	}
+	otelConfig := fOtelConfigs[0]
+	merged, err := mergeConfigs(fOtelConfigs)
+	if err != nil {
+		return err
+	}
+	if merged != nil {
+		otelConfig = filepath.Join(outputDir, "merged.yaml")
+		if err = os.WriteFile(otelConfig, []byte(toyamlconfig.ToYamlConfig(merged.ToStringMap())), 0644); err != nil {
+			return fmt.Errorf("unable to write the merged OTEL configuration: %w", err)
+		}
+	}
+	factories, err := defaultcomponents.Factories()
+	if err != nil {
+		return err
+	}
+	report, err := replay.Run(ctx, replay.Config{
+		OtelConfig: otelConfig,
+		TomlConfig: *fTomlConfig,
+		Dir:        dir,
+		OutputDir:  outputDir,
+		Processors: factories.Processors,
+	})
+	if err != nil {
+		return err
+	}
+	return report.Write(w)
+}
@@ -0,0 +1,67 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+	"github.com/aws/amazon-cloudwatch-agent/internal/watchdog"
+	"github.com/aws/amazon-cloudwatch-agent/internal/winservice"
+	"github.com/aws/amazon-cloudwatch-agent/tool/paths"
+)
+
+var (
+	wedgedMu     sync.Mutex
+	wedgedReason string
+)
+
+// watchdogConfig returns the watchdog configuration from the env config.
+func watchdogConfig() watchdog.Config {
+	var cfg watchdog.Config
+	if value := os.Getenv(envconfig.CWAGENT_WATCHDOG_TIMEOUT); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			log.Printf("W! Ignoring the invalid watchdog timeout %q", value)
+		} else {
+			cfg.Timeout = timeout
+		}
+	}
+	return cfg
+}
+
+// startWatchdog starts the watchdog if it is enabled in the env config. When
+// an output is wedged, the reason is logged, written to the Event Log on
+// Windows, and stopAgent is called so that the agent exits for a restart.
+// Returns the function stopping it, or nil.
+func startWatchdog(stopAgent func()) func() {
+	cfg := watchdogConfig()
+	if !cfg.Enabled() {
+		return nil
+	}
+	w := watchdog.New(cfg, selftelemetry.Default, func(reason string) {
+		log.Printf("E! Restarting the agent, %s", reason)
+		if err := winservice.ReportWarning(paths.AgentServiceName, winservice.EventIDWatchdog, "Restarting the agent, "+reason); err != nil {
+			log.Printf("W! Unable to write the restart to the event log: %v", err)
+		}
+		wedgedMu.Lock()
+		wedgedReason = reason
+		wedgedMu.Unlock()
+		stopAgent()
+	})
+	w.Start()
+	return w.Stop
+}
+
+// getWedgedReason returns why the watchdog stopped the agent, or an empty
+// string if it did not.
+func getWedgedReason() string {
+	wedgedMu.Lock()
+	defer wedgedMu.Unlock()
+	return wedgedReason
+}
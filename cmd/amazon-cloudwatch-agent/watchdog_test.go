@@ -0,0 +1,41 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/internal/watchdog"
+)
+
+func TestWatchdogConfig(t *testing.T) {
+	testCases := map[string]struct {
+		value string
+		want  watchdog.Config
+	}{
+		"WithDefault": {},
+		"WithTimeout": {
+			value: "900s",
+			want:  watchdog.Config{Timeout: 15 * time.Minute},
+		},
+		"WithInvalid": {
+			value: "15",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(envconfig.CWAGENT_WATCHDOG_TIMEOUT, testCase.value)
+			assert.Equal(t, testCase.want, watchdogConfig())
+			stop := startWatchdog(func() {})
+			assert.Equal(t, testCase.want.Enabled(), stop != nil)
+			if stop != nil {
+				stop()
+			}
+		})
+	}
+}
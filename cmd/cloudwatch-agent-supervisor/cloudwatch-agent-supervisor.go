@@ -0,0 +1,47 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/supervisor"
+	"github.com/aws/amazon-cloudwatch-agent/internal/version"
+	"github.com/aws/amazon-cloudwatch-agent/tool/paths"
+)
+
+const supervisorConfigName = "supervisor.yaml"
+
+func main() {
+	configPath := flag.String("config", filepath.Join(filepath.Dir(paths.JsonConfigPath), supervisorConfigName), "path to the supervisor YAML configuration")
+	showVersion := flag.Bool("version", false, "print the version and exit")
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Println(version.Full())
+		return
+	}
+
+	cfg, err := supervisor.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("E! [supervisor] Invalid configuration %s: %v", *configPath, err)
+	}
+	s, err := supervisor.New(cfg)
+	if err != nil {
+		log.Fatalf("E! [supervisor] %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err = s.Run(ctx); err != nil {
+		log.Fatalf("E! [supervisor] %v", err)
+	}
+}
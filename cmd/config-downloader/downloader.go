@@ -130,6 +130,12 @@ func main() {
 	}
 	util.SetProxyEnv(cc.ProxyMap())
 	util.SetSSLEnv(cc.SSLMap())
+	if configaws.DetectFIPSEndpoint() {
+		fmt.Println("I! Using the FIPS endpoints of the AWS services")
+	}
+	if configaws.DetectIPv6Only() {
+		fmt.Println("I! Using the IPv6 endpoints of IMDS and the AWS services")
+	}
 	var errorMessage string
 	if downloadLocation == "" || outputDir == "" {
 		executable, err := os.Executable()
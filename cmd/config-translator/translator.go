@@ -6,16 +6,19 @@ package main
 import (
 	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/user"
 	"path/filepath"
 
 	"github.com/aws/amazon-cloudwatch-agent/cfg/commonconfig"
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
 	userutil "github.com/aws/amazon-cloudwatch-agent/internal/util/user"
 	"github.com/aws/amazon-cloudwatch-agent/translator"
 	"github.com/aws/amazon-cloudwatch-agent/translator/cmdutil"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+	"github.com/aws/amazon-cloudwatch-agent/translator/policy"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline"
 	translatorUtil "github.com/aws/amazon-cloudwatch-agent/translator/util"
 )
@@ -28,22 +31,45 @@ const (
 	yamlConfigFileName = "amazon-cloudwatch-agent.yaml"
 )
 
+var (
+	// dryRun validates the configuration without writing the output files.
+	dryRun bool
+	// dryRunMerge prints the json config merged from the input files instead of
+	// translating it.
+	dryRunMerge bool
+	// migrateConfig rewrites the deprecated keys of the input JSON config files
+	// instead of translating them.
+	migrateConfig bool
+	// agentPolicy restricts the translated config if the policy file of the
+	// host exists.
+	agentPolicy *policy.Policy
+)
+
 func initFlags() {
 	var inputOs = flag.String("os", "", "Please provide the os preference, valid value: windows/linux.")
+	var inputArch = flag.String("arch", "", "Please provide the arch preference, valid value: amd64/arm64. The default is the arch of the host.")
 	var inputJsonFile = flag.String("input", "", "Please provide the path of input agent json config file")
 	var inputJsonDir = flag.String("input-dir", "", "Please provide the path of input agent json config directory.")
 	var inputTomlFile = flag.String("output", "", "Please provide the path of the output CWAgent config file")
 	var inputMode = flag.String("mode", "ec2", "Please provide the mode, i.e. ec2, onPremise, onPrem, auto")
 	var inputConfig = flag.String("config", "", "Please provide the common-config file")
 	var multiConfig = flag.String("multi-config", "remove", "valid values: default, append, remove")
+	var inputPolicy = flag.String("policy", "", "Please provide the path of the policy file restricting the configuration. Skipped if the file does not exist")
+	flag.BoolVar(&dryRun, "dry-run", false, "Validate the configuration without writing the output files, and preview the log parsers on the first lines of the files they are configured for")
+	flag.BoolVar(&dryRunMerge, "dry-run-merge", false, "Print the effective json config merged from the input json config files, e.g. with --multi-config append, without translating it or writing the output files")
+	flag.BoolVar(&migrateConfig, "migrate", false, "Rewrite the deprecated keys of the input json config files to the current schema version and report the unknown keys, without writing the files with --dry-run")
 	flag.Parse()
 
 	ctx := context.CurrentContext()
 	ctx.SetOs(*inputOs)
+	ctx.SetArch(*inputArch)
 	ctx.SetInputJsonFilePath(*inputJsonFile)
 	ctx.SetInputJsonDirPath(*inputJsonDir)
 	ctx.SetMultiConfig(*multiConfig)
-	ctx.SetOutputTomlFilePath(*inputTomlFile)
+	// the log config is written next to the output file during the translation
+	if !dryRun && !dryRunMerge {
+		ctx.SetOutputTomlFilePath(*inputTomlFile)
+	}
 
 	if *inputConfig != "" {
 		f, err := os.Open(*inputConfig)
@@ -60,6 +86,22 @@ func initFlags() {
 		ctx.SetSSL(conf.SSLMap())
 		translatorUtil.LoadImdsRetries(conf.IMDS)
 	}
+	if *inputPolicy != "" {
+		if _, err := os.Stat(*inputPolicy); err == nil {
+			p, err := policy.Load(*inputPolicy)
+			if err != nil {
+				log.Fatalf("E! Failed to load policy file %s with error: %v", *inputPolicy, err)
+			}
+			agentPolicy = p
+			if p.RequireIMDSv2 {
+				// the translator looks up the instance metadata before the env config is written
+				ctx.SetIMDSv1Disabled(true)
+				os.Setenv(envconfig.AWS_EC2_METADATA_V1_DISABLED, "true")
+			}
+		} else {
+			log.Printf("I! Policy file %s does not exist. Skipping it.", *inputPolicy)
+		}
+	}
 	translatorUtil.SetProxyEnv(ctx.Proxy())
 	translatorUtil.SetSSLEnv(ctx.SSL())
 
@@ -70,12 +112,20 @@ func initFlags() {
 
 /**
  *	config-translator --input ${JSON} --input-dir ${JSON_DIR} --output ${TOML} --mode ${param_mode} --config ${COMMON_CONFIG}
- *  --multi-config [default|append|remove]
+ *  --multi-config [default|append|remove] --policy ${POLICY} --dry-run --dry-run-merge --migrate
  *
  *		multi-config:
  *			default:	only process .tmp files
  *			append:		process both existing files and .tmp files
  *			remove:		only process existing files
+ *
+ *		policy:	the restrictions of the administrators the translated config is validated against
+ *
+ *		dry-run:	validate without writing the output files
+ *
+ *		dry-run-merge:	print the merged json config without translating it
+ *
+ *		migrate:	rewrite the deprecated keys of the input json config files instead of translating them
  */
 func main() {
 	initFlags()
@@ -96,10 +146,25 @@ func main() {
 	}()
 	ctx := context.CurrentContext()
 
+	if migrateConfig {
+		if err := cmdutil.MigrateJsonConfigFiles(ctx, dryRun, os.Stdout); err != nil {
+			log.Panicf("E! Failed to migrate the json config files: %v", err)
+		}
+		log.Println("Configuration migration succeeded")
+		return
+	}
+
 	mergedJsonConfigMap, err := cmdutil.GenerateMergedJsonConfigMap(ctx)
 	if err != nil {
 		log.Panicf("E! Failed to generate merged json config: %v", err)
 	}
+	if dryRunMerge {
+		if err = cmdutil.WriteMergedJsonConfig(mergedJsonConfigMap, os.Stdout); err != nil {
+			log.Panicf("E! Failed to print the merged json config: %v", err)
+		}
+		log.Println(exitSuccessMessage)
+		return
+	}
 
 	if !ctx.RunInContainer() {
 		// run as user only applies to non container situation.
@@ -124,6 +189,16 @@ func main() {
 	if err != nil && !errors.Is(err, pipeline.ErrNoPipelines) {
 		log.Panicf("E! Failed to generate YAML configuration validation content: %v", err)
 	}
+	if err = checkPolicy(mergedJsonConfigMap, yamlConfig); err != nil {
+		log.Panicf("E! %v", err)
+	}
+	if dryRun {
+		if err = cmdutil.PreviewLogParsers(tomlConfig, os.Stdout); err != nil {
+			log.Panicf("E! Failed to preview the log parsers: %v", err)
+		}
+		log.Println(exitSuccessMessage)
+		return
+	}
 	if err = cmdutil.ConfigToTomlFile(tomlConfig, tomlConfigPath); err != nil {
 		log.Panicf("E! Failed to create the configuration TOML validation file: %v", err)
 	}
@@ -135,3 +210,19 @@ func main() {
 	envConfigPath := filepath.Join(tomlConfigDir, envConfigFileName)
 	cmdutil.TranslateJsonMapToEnvConfigFile(mergedJsonConfigMap, envConfigPath)
 }
+
+// checkPolicy logs the violations of the policy. Returns an error if there is
+// a violation of an enforced policy.
+func checkPolicy(jsonConfig map[string]interface{}, yamlConfig interface{}) error {
+	if agentPolicy == nil {
+		return nil
+	}
+	violations := agentPolicy.Check(jsonConfig, yamlConfig)
+	for _, violation := range violations {
+		log.Printf("W! Policy violation: %v", violation)
+	}
+	if len(violations) > 0 && agentPolicy.Enforced() {
+		return fmt.Errorf("the configuration violates %d rule(s) of the policy", len(violations))
+	}
+	return nil
+}
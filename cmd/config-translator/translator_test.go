@@ -58,6 +58,78 @@ func TestAgentConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgent.json", false, expectedErrorMap)
 }
 
+func TestAgentProxyConfig(t *testing.T) {
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgentProxy.json", false, expectedErrorMap)
+}
+
+func TestAgentInternalMetricsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validAgentInternalMetrics.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["string_gte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgentInternalMetrics.json", false, expectedErrorMap)
+}
+
+func TestAgentMemoryLimiterConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validAgentMemoryLimiter.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["number_lte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgentMemoryLimiter.json", false, expectedErrorMap)
+}
+
+func TestAgentRetryConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validAgentRetry.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	expectedErrorMap["number_gte"] = 2
+	expectedErrorMap["number_lte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgentRetry.json", false, expectedErrorMap)
+}
+
+func TestAgentWatchdogConfig(t *testing.T) {
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	expectedErrorMap["number_gte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgentWatchdog.json", false, expectedErrorMap)
+}
+
+func TestAgentInventoryConfig(t *testing.T) {
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["pattern"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgentInventory.json", false, expectedErrorMap)
+}
+
+func TestAgentFeatureGatesConfig(t *testing.T) {
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["invalid_type"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgentFeatureGates.json", false, expectedErrorMap)
+}
+
+func TestAgentEntityAttributesConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validAgentEntityAttributes.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["string_gte"] = 1
+	expectedErrorMap["invalid_type"] = 1
+	expectedErrorMap["unique"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgentEntityAttributes.json", false, expectedErrorMap)
+}
+
+func TestAgentHybridEntityConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validAgentHybridEntity.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	expectedErrorMap["string_gte"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgentHybridEntity.json", false, expectedErrorMap)
+}
+
 func TestTracesConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validTrace.json", true, map[string]int{})
 	expectedErrorMap := map[string]int{}
@@ -65,6 +137,43 @@ func TestTracesConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidTrace.json", false, expectedErrorMap)
 }
 
+func TestTracesTailSamplingConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validTraceTailSampling.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	expectedErrorMap["number_gte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidTraceTailSampling.json", false, expectedErrorMap)
+}
+
+func TestTracesScrubbingConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validTraceScrubbing.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	expectedErrorMap["invalid_type"] = 1
+	expectedErrorMap["string_gte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidTraceScrubbing.json", false, expectedErrorMap)
+}
+
+func TestTracesSpanMetricsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validTraceSpanMetrics.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	expectedErrorMap["number_not"] = 1
+	expectedErrorMap["number_gt"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidTraceSpanMetrics.json", false, expectedErrorMap)
+}
+
+func TestTracesDestinationsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validTraceDestinations.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_gt"] = 1
+	expectedErrorMap["number_lte"] = 1
+	expectedErrorMap["required"] = 1
+	expectedErrorMap["array_max_properties"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidTraceDestinations.json", false, expectedErrorMap)
+}
+
 func TestJMXConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validJMX.json", true, map[string]int{})
 	expectedErrorMap := map[string]int{}
@@ -83,6 +192,40 @@ func TestOTLPMetricsConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidOTLPMetrics.json", false, expectedErrorMap)
 }
 
+func TestOTLPReceiversConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validOTLPReceivers.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	expectedErrorMap["number_one_of"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidOTLPReceivers.json", false, expectedErrorMap)
+}
+
+func TestLogGroupSettingsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogGroupSettings.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["invalid_type"] = 2
+	expectedErrorMap["string_gte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogGroupSettings.json", false, expectedErrorMap)
+}
+
+func TestAlertsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validAlerts.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 2
+	expectedErrorMap["required"] = 1
+	expectedErrorMap["pattern"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAlerts.json", false, expectedErrorMap)
+}
+
+func TestPipelineSettingsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validPipelineSettings.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	expectedErrorMap["invalid_type"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidPipelineSettings.json", false, expectedErrorMap)
+}
+
 func TestLogFilesConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFiles.json", true, map[string]int{})
 	expectedErrorMap := map[string]int{}
@@ -112,6 +255,84 @@ func TestLogWindowsEventConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogWindowsEventsWithInvalidEventFormatType.json", false, expectedErrorMap3)
 }
 
+func TestLogKafkaConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogKafka.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["required"] = 2
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogKafkaWithMissingBrokersAndTopics.json", false, expectedErrorMap)
+	expectedErrorMap1 := map[string]int{}
+	expectedErrorMap1["enum"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogKafkaWithInvalidSASLMechanism.json", false, expectedErrorMap1)
+}
+
+func TestLogKinesisConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogKinesis.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["required"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogKinesisWithMissingStreams.json", false, expectedErrorMap)
+	expectedErrorMap1 := map[string]int{}
+	expectedErrorMap1["enum"] = 2
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogKinesisWithInvalidCheckpointStore.json", false, expectedErrorMap1)
+}
+
+func TestLogOtlpConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogOtlp.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	expectedErrorMap["invalid_type"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogOtlpWithUnknownField.json", false, expectedErrorMap)
+}
+
+func TestLogFluentForwardConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFluentForward.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	expectedErrorMap["string_gte"] = 1
+	expectedErrorMap["invalid_type"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFluentForward.json", false, expectedErrorMap)
+}
+
+func TestSchemaVersionConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validSchemaVersion.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_lte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidSchemaVersion.json", false, expectedErrorMap)
+}
+
+func TestWindowsCountersRefreshIntervalConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validWindowsCountersRefreshInterval.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_gte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidWindowsCountersRefreshInterval.json", false, expectedErrorMap)
+}
+
+func TestWindowsMetricBundlesConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/tocwconfig/sampleConfig/windows_metric_bundles_config.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidWindowsMetricBundleVersion.json", false, expectedErrorMap)
+}
+
+func TestLogJournaldConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogJournald.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogJournaldWithInvalidPriority.json", false, expectedErrorMap)
+	expectedErrorMap1 := map[string]int{}
+	expectedErrorMap1["required"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogJournaldWithMissingLogGroupName.json", false, expectedErrorMap1)
+}
+
+func TestLogDockerConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogDocker.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogDockerWithInvalidStream.json", false, expectedErrorMap)
+	expectedErrorMap1 := map[string]int{}
+	expectedErrorMap1["required"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogDockerWithMissingLogGroupName.json", false, expectedErrorMap1)
+}
+
 func TestMetricsConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLinuxMetrics.json", true, map[string]int{})
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validWindowsMetrics.json", true, map[string]int{})
@@ -165,10 +386,59 @@ func TestEthtoolConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validEthtoolConfig.json", true, map[string]int{})
 }
 
+func TestEfaConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validEfaConfig.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["required"] = 1
+	expectedErrorMap["string_gte"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidEfaConfig.json", false, expectedErrorMap)
+}
+
+func TestDiskStatsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validDiskStatsConfig.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["required"] = 1
+	expectedErrorMap["string_gte"] = 1
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidDiskStatsConfig.json", false, expectedErrorMap)
+}
+
+func TestNfsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validNfsConfig.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["required"] = 1
+	expectedErrorMap["string_gte"] = 1
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidNfsConfig.json", false, expectedErrorMap)
+}
+
 func TestNvidiaGpuConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validNvidiaGpuConfig.json", true, map[string]int{})
 }
 
+func TestNetworkConnectionsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validNetworkConnectionsConfig.json", true, map[string]int{})
+}
+
+func TestNetworkProbeConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validNetworkProbeConfig.json", true, map[string]int{})
+}
+
+func TestSNMPConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validSNMPConfig.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	expectedErrorMap["pattern"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidSNMPConfig.json", false, expectedErrorMap)
+}
+
+func TestValidLogFilesCrossAccountConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesCrossAccount.json", true, map[string]int{})
+}
+
 func TestValidLogFilterConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithFilters.json", true, map[string]int{})
 }
@@ -181,16 +451,246 @@ func TestInvalidLogFilterConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithFilters.json", false, expectedErrorMap)
 }
 
+func TestLogTransformTemplatesConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithTransformTemplates.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"additional_property_not_allowed": 1,
+		"enum":                            1,
+		"string_gte":                      1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithTransformTemplates.json", false, expectedErrorMap)
+}
+
+func TestLogParsersConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithParsers.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"enum":          1,
+		"number_one_of": 1,
+		"required":      1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithParsers.json", false, expectedErrorMap)
+}
+
+func TestLogEMFValidationConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithEMFValidation.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"invalid_type":  1,
+		"number_one_of": 1,
+		"string_gte":    1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithEMFValidation.json", false, expectedErrorMap)
+}
+
+func TestLogStructuredJSONConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithStructuredJSON.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"number_not":   1,
+		"invalid_type": 1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithStructuredJSON.json", false, expectedErrorMap)
+}
+
+func TestLogClockSkewConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogClockSkew.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["invalid_type"] = 1
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogClockSkew.json", false, expectedErrorMap)
+}
+
+func TestLogRateLimitConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogRateLimit.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"additional_property_not_allowed": 1,
+		"invalid_type":                    1,
+		"number_gt":                       1,
+		"required":                        1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogRateLimit.json", false, expectedErrorMap)
+}
+
+func TestLogS3DestinationConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogS3Destination.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"enum":       2,
+		"number_gte": 1,
+		"required":   1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogS3Destination.json", false, expectedErrorMap)
+}
+
+func TestLogMultilineConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithMultiline.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"enum":     1,
+		"required": 1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithMultiline.json", false, expectedErrorMap)
+}
+
+func TestLogSamplingConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithSampling.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"number_gt":                       1,
+		"number_gte":                      1,
+		"number_lte":                      1,
+		"additional_property_not_allowed": 1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithSampling.json", false, expectedErrorMap)
+}
+
+func TestLogOversizeEventsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithOversizeEvents.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"number_lte":                      1,
+		"enum":                            1,
+		"string_gte":                      1,
+		"additional_property_not_allowed": 1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithOversizeEvents.json", false, expectedErrorMap)
+}
+
+func TestFailoverEndpointsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validFailoverEndpoints.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_one_of"] = 1
+	expectedErrorMap["array_min_items"] = 1
+	expectedErrorMap["invalid_type"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidFailoverEndpoints.json", false, expectedErrorMap)
+}
+
 func TestMetricsDestinationsConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validMetricsDestinations.json", true, map[string]int{})
 	expectedErrorMap := map[string]int{}
 	expectedErrorMap["required"] = 1
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsDestinations.json", false, expectedErrorMap)
 }
+
+func TestMetricsAggregationTemporalityConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validMetricsAggregationTemporality.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 2
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsAggregationTemporality.json", false, expectedErrorMap)
+}
+
+func TestMetricsTextfileConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validMetricsTextfile.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["pattern"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsTextfile.json", false, expectedErrorMap)
+}
+
+func TestMetricsBurstCaptureConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validMetricsBurstCapture.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["required"] = 1
+	expectedErrorMap["number_gte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsBurstCapture.json", false, expectedErrorMap)
+}
+
+func TestMetricsDerivedConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validMetricsDerived.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["required"] = 1
+	expectedErrorMap["string_gte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsDerived.json", false, expectedErrorMap)
+}
+
+func TestMetricsCardinalityLimitConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validMetricsCardinalityLimit.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["invalid_type"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsCardinalityLimit.json", false, expectedErrorMap)
+}
+
+func TestMetricsNamespaceRoutesConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validMetricsNamespaceRoutes.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["required"] = 2
+	expectedErrorMap["number_any_of"] = 1
+	expectedErrorMap["array_min_items"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsNamespaceRoutes.json", false, expectedErrorMap)
+}
+
+func TestTransformsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validTransforms.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	expectedErrorMap["number_all_of"] = 3
+	expectedErrorMap["required"] = 1
+	expectedErrorMap["array_min_items"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidTransforms.json", false, expectedErrorMap)
+}
+
+func TestMetricsDimensionFiltersConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validMetricsDimensionFilters.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	expectedErrorMap["number_all_of"] = 1
+	expectedErrorMap["required"] = 1
+	expectedErrorMap["array_min_items"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsDimensionFilters.json", false, expectedErrorMap)
+}
+
+func TestMetricsDimensionNormalizationConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validMetricsDimensionNormalization.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["invalid_type"] = 1
+	expectedErrorMap["string_gte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsDimensionNormalization.json", false, expectedErrorMap)
+}
+
+func TestMetricsOTLPConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validMetricsOTLP.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	expectedErrorMap["number_gte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsOTLP.json", false, expectedErrorMap)
+}
+
+func TestMetricsSanitizationConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validMetricsSanitization.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsSanitization.json", false, expectedErrorMap)
+}
+
+func TestMetricsBatchingConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validMetricsBatching.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsBatching.json", false, expectedErrorMap)
+}
+
+func TestPrometheusEC2SDConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validPrometheusEC2SD.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["required"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidPrometheusEC2SD.json", false, expectedErrorMap)
+}
+
 func TestContainerInsightsJmxConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validContainerInsightsJmx.json", true, map[string]int{})
 }
 
+func TestKubernetesLeaderElectionConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validKubernetesLeaderElection.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"enum":       1,
+		"string_gte": 1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidKubernetesLeaderElection.json", false, expectedErrorMap)
+}
+
 // Validate all sampleConfig files schema
 func TestSampleConfigSchema(t *testing.T) {
 	if files, err := os.ReadDir("../../translator/tocwconfig/sampleConfig/"); err == nil {
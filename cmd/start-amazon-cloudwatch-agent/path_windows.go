@@ -15,11 +15,19 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
 	"github.com/aws/amazon-cloudwatch-agent/internal/util/config"
+	"github.com/aws/amazon-cloudwatch-agent/internal/winservice"
 	"github.com/aws/amazon-cloudwatch-agent/tool/paths"
 )
 
 func startAgent(writer io.WriteCloser) error {
 	if !envconfig.IsRunningInContainer() {
+		// the service may have been created without the recovery actions,
+		// e.g. by an older amazon-cloudwatch-agent-ctl.ps1
+		if winservice.IsService() {
+			if err := winservice.ConfigureRecovery(paths.AgentServiceName); err != nil {
+				log.Printf("W! Cannot configure the recovery of the service, ERROR is %v \n", err)
+			}
+		}
 		if err := writer.Close(); err != nil {
 			log.Printf("E! Cannot close the log file, ERROR is %v \n", err)
 			return err
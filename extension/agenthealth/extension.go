@@ -6,11 +6,13 @@ package agenthealth
 import (
 	"github.com/amazon-contributing/opentelemetry-collector-contrib/extension/awsmiddleware"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
 	"go.uber.org/zap"
 
 	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats"
 	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/agent"
 	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/useragent"
+	"github.com/aws/amazon-cloudwatch-agent/internal/admin"
 )
 
 type agentHealth struct {
@@ -21,6 +23,12 @@ type agentHealth struct {
 }
 
 var _ awsmiddleware.Extension = (*agentHealth)(nil)
+var _ extension.StatusWatcher = (*agentHealth)(nil)
+
+// ComponentStatusChanged records the status of the components for the admin API.
+func (ah *agentHealth) ComponentStatusChanged(source *component.InstanceID, event *component.StatusEvent) {
+	admin.RecordStatus(source, event)
+}
 
 func (ah *agentHealth) Handlers() ([]awsmiddleware.RequestHandler, []awsmiddleware.ResponseHandler) {
 	var responseHandlers []awsmiddleware.ResponseHandler
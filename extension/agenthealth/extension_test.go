@@ -8,7 +8,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension"
 	"go.uber.org/zap"
 
 	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/agent"
@@ -51,3 +53,10 @@ func TestExtensionStatusCodeOnly(t *testing.T) {
 	assert.Len(t, responseHandlers, 0)
 	assert.NoError(t, extension.Shutdown(ctx))
 }
+
+func TestExtensionStatusWatcher(t *testing.T) {
+	ah := NewAgentHealth(zap.NewNop(), &Config{})
+	watcher, ok := ah.(extension.StatusWatcher)
+	assert.True(t, ok)
+	watcher.ComponentStatusChanged(&component.InstanceID{ID: component.MustNewID("otlp"), Kind: component.KindReceiver}, component.NewStatusEvent(component.StatusOK))
+}
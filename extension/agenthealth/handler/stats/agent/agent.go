@@ -30,6 +30,8 @@ type Stats struct {
 	RegionType                *string           `json:"rt,omitempty"`
 	Mode                      *string           `json:"m,omitempty"`
 	EntityRejected            *int              `json:"ent,omitempty"`
+	VPCEndpoint               *int              `json:"vpce,omitempty"`
+	FeatureGates              *string           `json:"fg,omitempty"`
 	StatusCodes               map[string][5]int `json:"codes,omitempty"` //represents status codes 200,400,408,413,429,
 }
 
@@ -81,6 +83,12 @@ func (s *Stats) Merge(other Stats) {
 	if other.EntityRejected != nil {
 		s.EntityRejected = other.EntityRejected
 	}
+	if other.VPCEndpoint != nil {
+		s.VPCEndpoint = other.VPCEndpoint
+	}
+	if other.FeatureGates != nil {
+		s.FeatureGates = other.FeatureGates
+	}
 	if other.StatusCodes != nil {
 		if s.StatusCodes == nil {
 			s.StatusCodes = make(map[string][5]int)
@@ -35,6 +35,8 @@ func TestMerge(t *testing.T) {
 		RunningInContainer:        aws.Int(0),
 		RegionType:                aws.String("RegionType"),
 		Mode:                      aws.String("Mode"),
+		VPCEndpoint:               aws.Int(1),
+		FeatureGates:              aws.String("+cwagent.cloudwatch.adaptiveBatching"),
 	})
 	assert.EqualValues(t, 1.5, *stats.CPUPercent)
 	assert.EqualValues(t, 133, *stats.MemoryBytes)
@@ -50,6 +52,8 @@ func TestMerge(t *testing.T) {
 	assert.EqualValues(t, 0, *stats.RunningInContainer)
 	assert.EqualValues(t, "RegionType", *stats.RegionType)
 	assert.EqualValues(t, "Mode", *stats.Mode)
+	assert.EqualValues(t, 1, *stats.VPCEndpoint)
+	assert.EqualValues(t, "+cwagent.cloudwatch.adaptiveBatching", *stats.FeatureGates)
 }
 
 func TestMergeWithStatusCodes(t *testing.T) {
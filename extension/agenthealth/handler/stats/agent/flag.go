@@ -24,6 +24,8 @@ const (
 	FlagRunningInContainer
 	FlagMode
 	FlagRegionType
+	FlagVPCEndpoint
+	FlagFeatureGates
 
 	flagIMDSFallbackSuccessStr       = "imds_fallback_success"
 	flagSharedConfigFallbackStr      = "shared_config_fallback"
@@ -32,6 +34,8 @@ const (
 	flagRunningInContainerStr        = "running_in_container"
 	flagModeStr                      = "mode"
 	flagRegionTypeStr                = "region_type"
+	flagVPCEndpointStr               = "vpc_endpoint"
+	flagFeatureGatesStr              = "feature_gates"
 )
 
 type Flag int
@@ -55,6 +59,10 @@ func (f Flag) String() string {
 		return flagRunningInContainerStr
 	case FlagSharedConfigFallback:
 		return flagSharedConfigFallbackStr
+	case FlagVPCEndpoint:
+		return flagVPCEndpointStr
+	case FlagFeatureGates:
+		return flagFeatureGatesStr
 	}
 	return ""
 }
@@ -83,6 +91,10 @@ func (f *Flag) UnmarshalText(text []byte) error {
 		*f = FlagRunningInContainer
 	case flagSharedConfigFallbackStr:
 		*f = FlagSharedConfigFallback
+	case flagVPCEndpointStr:
+		*f = FlagVPCEndpoint
+	case flagFeatureGatesStr:
+		*f = FlagFeatureGates
 	default:
 		return fmt.Errorf("%w: %s", errUnsupportedFlag, s)
 	}
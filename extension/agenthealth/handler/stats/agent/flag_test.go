@@ -67,6 +67,8 @@ func TestFlag(t *testing.T) {
 		{flag: FlagRegionType, str: flagRegionTypeStr},
 		{flag: FlagRunningInContainer, str: flagRunningInContainerStr},
 		{flag: FlagSharedConfigFallback, str: flagSharedConfigFallbackStr},
+		{flag: FlagVPCEndpoint, str: flagVPCEndpointStr},
+		{flag: FlagFeatureGates, str: flagFeatureGatesStr},
 	}
 	for _, testCase := range testCases {
 		flag := testCase.flag
@@ -37,6 +37,8 @@ func (p *flagStats) update() {
 		RunningInContainer:        boolToInt(p.flagSet.IsSet(agent.FlagRunningInContainer)),
 		Mode:                      p.flagSet.GetString(agent.FlagMode),
 		RegionType:                p.flagSet.GetString(agent.FlagRegionType),
+		VPCEndpoint:               boolToSparseInt(p.flagSet.IsSet(agent.FlagVPCEndpoint)),
+		FeatureGates:              p.flagSet.GetString(agent.FlagFeatureGates),
 	})
 }
 
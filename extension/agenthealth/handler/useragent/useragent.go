@@ -42,11 +42,14 @@ const (
 var (
 	singleton UserAgent
 	once      sync.Once
+
+	flags = collections.NewSet[string](flagRunAsUser, flagContainerInsights, flagAppSignals, flagEnhancedContainerInsights)
 )
 
 type UserAgent interface {
 	SetComponents(otelCfg *otelcol.Config, telegrafCfg *telegraf.Config)
 	SetContainerInsightsFlag()
+	Components() (inputs, processors, outputs []string)
 	Header(isUsageDataEnabled bool) string
 	Listen(listener func())
 }
@@ -135,6 +138,14 @@ func (ua *userAgent) SetContainerInsightsFlag() {
 	}
 }
 
+// Components returns the sorted names of the inputs, processors and outputs
+// without the flags added to them.
+func (ua *userAgent) Components() (inputs, processors, outputs []string) {
+	ua.dataLock.Lock()
+	defer ua.dataLock.Unlock()
+	return componentNames(ua.inputs), componentNames(ua.processors), componentNames(ua.outputs)
+}
+
 func (ua *userAgent) Listen(listener func()) {
 	ua.listenerLock.Lock()
 	defer ua.listenerLock.Unlock()
@@ -183,6 +194,17 @@ func componentsStr(componentType string, componentSet collections.Set[string]) s
 	return fmt.Sprintf("%s:(%s)", componentType, strings.Join(components, separator))
 }
 
+func componentNames(componentSet collections.Set[string]) []string {
+	names := make([]string, 0, len(componentSet))
+	for name := range componentSet {
+		if !flags.Contains(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 func isRunningAsRoot() bool {
 	return os.Getuid() == 0
 }
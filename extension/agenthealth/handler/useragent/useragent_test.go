@@ -74,6 +74,12 @@ func TestSetComponents(t *testing.T) {
 	ua.SetComponents(otelCfg, telegrafCfg)
 	assert.Len(t, ua.inputs, 4)
 	assert.Equal(t, "inputs:(cpu logs prometheus run_as_user)", ua.inputsStr.Load())
+
+	ua.SetContainerInsightsFlag()
+	inputs, processors, outputs := ua.Components()
+	assert.Equal(t, []string{"cpu", "logs", "prometheus"}, inputs)
+	assert.Equal(t, []string{"batch", "filter"}, processors)
+	assert.Equal(t, []string{"cloudwatch", "cloudwatchlogs"}, outputs)
 }
 
 func TestSetComponentsEmpty(t *testing.T) {
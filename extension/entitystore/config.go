@@ -14,6 +14,23 @@ type Config struct {
 	Profile        string `mapstructure:"profile,omitempty"`
 	RoleARN        string `mapstructure:"role_arn,omitempty"`
 	Filename       string `mapstructure:"shared_credential_file,omitempty"`
+	// EntityAttributes are added to the attributes of all the entities.
+	EntityAttributes map[string]string `mapstructure:"entity_attributes,omitempty"`
+	// EC2TagKeys are the EC2 tags of the instance added to the attributes of
+	// all the entities.
+	EC2TagKeys []string `mapstructure:"ec2_tag_keys,omitempty"`
+	// Hybrid enables the entities of the hosts outside of AWS in the on-premise
+	// mode.
+	Hybrid *HybridConfig `mapstructure:"hybrid,omitempty"`
+}
+
+// HybridConfig describes the hybrid host the agent runs on.
+type HybridConfig struct {
+	// CloudProvider is auto, azure or on_premises. The Azure metadata of the
+	// host is looked up in auto mode.
+	CloudProvider string `mapstructure:"cloud_provider,omitempty"`
+	// Datacenter groups the on-premises hosts.
+	Datacenter string `mapstructure:"datacenter,omitempty"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -0,0 +1,174 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package entitystore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	ecsMetadataEndpointV4Env = "ECS_CONTAINER_METADATA_URI_V4"
+
+	ecsMetadataRequestTimeout = 5 * time.Second
+	ecsMetadataRetryInterval  = 1 * time.Minute
+)
+
+// ecsTaskMetadata is the subset of the task metadata V4 response used for the entity.
+// See https://docs.aws.amazon.com/AmazonECS/latest/developerguide/task-metadata-endpoint-v4-response.html
+type ecsTaskMetadata struct {
+	Cluster     string `json:"Cluster"`
+	TaskARN     string `json:"TaskARN"`
+	ServiceName string `json:"ServiceName"`
+	LaunchType  string `json:"LaunchType"`
+}
+
+type ECSInfo struct {
+	TaskARN     string
+	Cluster     string
+	ServiceName string
+	LaunchType  string
+	AccountID   string
+
+	endpoint   string
+	httpClient *http.Client
+	logger     *zap.Logger
+	done       chan struct{}
+	mutex      sync.RWMutex
+}
+
+func (ei *ECSInfo) initECSInfo() {
+	if ei.endpoint == "" {
+		ei.logger.Warn("ECS task metadata endpoint is not set, ECS task entity will not be available", zap.String("env", ecsMetadataEndpointV4Env))
+		return
+	}
+	ei.logger.Debug("Initializing ECSInfo")
+	if err := ei.setTaskMetadata(); err != nil {
+		return
+	}
+	ei.logger.Debug("Finished initializing ECSInfo")
+}
+
+func (ei *ECSInfo) GetTaskARN() string {
+	ei.mutex.RLock()
+	defer ei.mutex.RUnlock()
+	return ei.TaskARN
+}
+
+func (ei *ECSInfo) GetCluster() string {
+	ei.mutex.RLock()
+	defer ei.mutex.RUnlock()
+	return ei.Cluster
+}
+
+func (ei *ECSInfo) GetServiceName() string {
+	ei.mutex.RLock()
+	defer ei.mutex.RUnlock()
+	return ei.ServiceName
+}
+
+func (ei *ECSInfo) GetLaunchType() string {
+	ei.mutex.RLock()
+	defer ei.mutex.RUnlock()
+	return ei.LaunchType
+}
+
+func (ei *ECSInfo) GetAccountID() string {
+	ei.mutex.RLock()
+	defer ei.mutex.RUnlock()
+	return ei.AccountID
+}
+
+// setTaskMetadata caches the task metadata, retrying until it is available since the
+// metadata does not change for the lifetime of the task.
+func (ei *ECSInfo) setTaskMetadata() error {
+	for {
+		metadata, err := ei.getTaskMetadata()
+		if err != nil {
+			ei.logger.Debug("Failed to get ECS task metadata", zap.Error(err))
+			wait := time.NewTimer(ecsMetadataRetryInterval)
+			select {
+			case <-ei.done:
+				wait.Stop()
+				return errors.New("shutdown signal received")
+			case <-wait.C:
+				continue
+			}
+		}
+		ei.logger.Debug("Successfully retrieved ECS task metadata")
+		ei.mutex.Lock()
+		ei.TaskARN = metadata.TaskARN
+		ei.Cluster = clusterNameFromARN(metadata.Cluster)
+		ei.ServiceName = metadata.ServiceName
+		ei.LaunchType = metadata.LaunchType
+		ei.AccountID = accountIDFromARN(metadata.TaskARN)
+		ei.mutex.Unlock()
+		return nil
+	}
+}
+
+func (ei *ECSInfo) getTaskMetadata() (*ecsTaskMetadata, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ecsMetadataRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ei.endpoint+"/task", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ei.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from task metadata endpoint", resp.StatusCode)
+	}
+	var metadata ecsTaskMetadata
+	if err = json.Unmarshal(body, &metadata); err != nil {
+		return nil, err
+	}
+	if metadata.TaskARN == "" {
+		return nil, errors.New("task ARN missing from task metadata")
+	}
+	return &metadata, nil
+}
+
+// accountIDFromARN extracts the account ID from a task ARN in either of the formats
+// arn:aws:ecs:region:aws_account_id:task/task-id
+// arn:aws:ecs:region:aws_account_id:task/cluster-name/task-id
+func accountIDFromARN(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 6 {
+		return ""
+	}
+	return parts[4]
+}
+
+// clusterNameFromARN returns the cluster name if given a cluster ARN. The task metadata
+// endpoint returns either the name or the ARN depending on the launch type.
+func clusterNameFromARN(cluster string) string {
+	return cluster[strings.LastIndex(cluster, "/")+1:]
+}
+
+func newECSInfo(done chan struct{}, logger *zap.Logger) *ECSInfo {
+	return &ECSInfo{
+		endpoint:   os.Getenv(ecsMetadataEndpointV4Env),
+		httpClient: &http.Client{Timeout: ecsMetadataRequestTimeout},
+		done:       done,
+		logger:     logger,
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package entitystore
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSetTaskMetadata(t *testing.T) {
+	type want struct {
+		TaskARN     string
+		Cluster     string
+		ServiceName string
+		LaunchType  string
+		AccountID   string
+	}
+	tests := []struct {
+		name     string
+		response string
+		want     want
+	}{
+		{
+			name: "ClusterARN",
+			response: `{"Cluster":"arn:aws:ecs:us-west-2:111122223333:cluster/default","TaskARN":"arn:aws:ecs:us-west-2:111122223333:task/default/158d1c8083dd49d6b527399fd6414f5c",
+"ServiceName":"my-service","LaunchType":"FARGATE","Family":"curltest"}`,
+			want: want{
+				TaskARN:     "arn:aws:ecs:us-west-2:111122223333:task/default/158d1c8083dd49d6b527399fd6414f5c",
+				Cluster:     "default",
+				ServiceName: "my-service",
+				LaunchType:  "FARGATE",
+				AccountID:   "111122223333",
+			},
+		},
+		{
+			name:     "ClusterNameWithoutService",
+			response: `{"Cluster":"default","TaskARN":"arn:aws:ecs:us-west-2:111122223333:task/158d1c8083dd49d6b527399fd6414f5c","LaunchType":"EC2"}`,
+			want: want{
+				TaskARN:    "arn:aws:ecs:us-west-2:111122223333:task/158d1c8083dd49d6b527399fd6414f5c",
+				Cluster:    "default",
+				LaunchType: "EC2",
+				AccountID:  "111122223333",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/task", r.URL.Path)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+			t.Setenv(ecsMetadataEndpointV4Env, server.URL)
+			ei := newECSInfo(make(chan struct{}), zap.NewNop())
+			require.NoError(t, ei.setTaskMetadata())
+			assert.Equal(t, tt.want.TaskARN, ei.GetTaskARN())
+			assert.Equal(t, tt.want.Cluster, ei.GetCluster())
+			assert.Equal(t, tt.want.ServiceName, ei.GetServiceName())
+			assert.Equal(t, tt.want.LaunchType, ei.GetLaunchType())
+			assert.Equal(t, tt.want.AccountID, ei.GetAccountID())
+		})
+	}
+}
+
+func TestSetTaskMetadataStopsOnShutdown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	t.Setenv(ecsMetadataEndpointV4Env, server.URL)
+	done := make(chan struct{})
+	ei := newECSInfo(done, zap.NewNop())
+	close(done)
+	assert.Error(t, ei.setTaskMetadata())
+	assert.Empty(t, ei.GetTaskARN())
+}
+
+func TestAccountIDFromARN(t *testing.T) {
+	assert.Equal(t, "111122223333", accountIDFromARN("arn:aws:ecs:us-west-2:111122223333:task/default/abc"))
+	assert.Equal(t, "", accountIDFromARN("invalid"))
+}
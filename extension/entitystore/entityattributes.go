@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package entitystore
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"go.uber.org/zap"
+)
+
+var errNoInstanceID = errors.New("instance ID not available yet")
+
+// entityAttributes are the user defined attributes added to all the entities,
+// from the agent.entity_attributes and the values of the EC2 tags listed in
+// agent.ec2_tag_keys. The tags are read with DescribeTags and cached, since
+// they rarely change.
+type entityAttributes struct {
+	static  map[string]string
+	tagKeys []string
+	ec2Info *EC2Info
+	ec2API  func() ec2iface.EC2API
+	logger  *zap.Logger
+
+	mutex sync.RWMutex
+	tags  map[string]string
+}
+
+func newEntityAttributes(static map[string]string, tagKeys []string, ec2Info *EC2Info, ec2API func() ec2iface.EC2API, logger *zap.Logger) *entityAttributes {
+	return &entityAttributes{
+		static:  static,
+		tagKeys: tagKeys,
+		ec2Info: ec2Info,
+		ec2API:  ec2API,
+		logger:  logger,
+	}
+}
+
+// get returns the attributes, the static attributes take precedence over the
+// tags with the same key.
+func (a *entityAttributes) get() map[string]string {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	attributes := make(map[string]string, len(a.static)+len(a.tags))
+	for k, v := range a.tags {
+		attributes[k] = v
+	}
+	for k, v := range a.static {
+		attributes[k] = v
+	}
+	return attributes
+}
+
+// refreshTags reads the values of the allowed tags of the instance.
+func (a *entityAttributes) refreshTags() error {
+	instanceID := a.ec2Info.GetInstanceID()
+	if instanceID == "" {
+		return errNoInstanceID
+	}
+	input := &ec2.DescribeTagsInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("resource-type"), Values: aws.StringSlice([]string{"instance"})},
+			{Name: aws.String("resource-id"), Values: aws.StringSlice([]string{instanceID})},
+			{Name: aws.String("key"), Values: aws.StringSlice(a.tagKeys)},
+		},
+	}
+	tags := map[string]string{}
+	client := a.ec2API()
+	for {
+		result, err := client.DescribeTags(input)
+		if err != nil {
+			return err
+		}
+		for _, tag := range result.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		if result.NextToken == nil {
+			break
+		}
+		input.SetNextToken(*result.NextToken)
+	}
+	a.mutex.Lock()
+	a.tags = tags
+	a.mutex.Unlock()
+	a.logger.Debug("Refreshed the EC2 tags of the entity attributes", zap.Int("tags", len(tags)))
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package entitystore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/awsentity/entityattributes"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+)
+
+type mockTagsClient struct {
+	ec2iface.EC2API
+	inputs []*ec2.DescribeTagsInput
+	pages  []*ec2.DescribeTagsOutput
+	err    error
+}
+
+func (m *mockTagsClient) DescribeTags(input *ec2.DescribeTagsInput) (*ec2.DescribeTagsOutput, error) {
+	copied := *input
+	m.inputs = append(m.inputs, &copied)
+	if m.err != nil {
+		return nil, m.err
+	}
+	page := m.pages[0]
+	m.pages = m.pages[1:]
+	return page, nil
+}
+
+func TestEntityAttributes_refreshTags(t *testing.T) {
+	client := &mockTagsClient{
+		pages: []*ec2.DescribeTagsOutput{
+			{
+				Tags:      []*ec2.TagDescription{{Key: aws.String("team"), Value: aws.String("payments")}},
+				NextToken: aws.String("token"),
+			},
+			{
+				Tags: []*ec2.TagDescription{{Key: aws.String("owner"), Value: aws.String("alice")}},
+			},
+		},
+	}
+	ec2Info := &EC2Info{}
+	a := newEntityAttributes(map[string]string{"owner": "bob", "tier": "1"}, []string{"team", "owner"}, ec2Info, func() ec2iface.EC2API { return client }, zap.NewNop())
+
+	assert.ErrorIs(t, a.refreshTags(), errNoInstanceID)
+	assert.Equal(t, map[string]string{"owner": "bob", "tier": "1"}, a.get())
+
+	ec2Info.InstanceID = "i-123456789"
+	assert.NoError(t, a.refreshTags())
+	assert.Len(t, client.inputs, 2)
+	assert.Equal(t, []*ec2.Filter{
+		{Name: aws.String("resource-type"), Values: aws.StringSlice([]string{"instance"})},
+		{Name: aws.String("resource-id"), Values: aws.StringSlice([]string{"i-123456789"})},
+		{Name: aws.String("key"), Values: aws.StringSlice([]string{"team", "owner"})},
+	}, client.inputs[0].Filters)
+	assert.Nil(t, client.inputs[0].NextToken)
+	assert.Equal(t, "token", aws.StringValue(client.inputs[1].NextToken))
+	// the static attributes take precedence over the tags
+	assert.Equal(t, map[string]string{"owner": "bob", "team": "payments", "tier": "1"}, a.get())
+
+	// the cached tags are kept when the refresh fails
+	client.err = errors.New("UnauthorizedOperation")
+	assert.Error(t, a.refreshTags())
+	assert.Equal(t, map[string]string{"owner": "bob", "team": "payments", "tier": "1"}, a.get())
+}
+
+func TestEntityStore_EntityAttributes(t *testing.T) {
+	e := EntityStore{}
+	assert.Nil(t, e.EntityAttributes())
+
+	e.entityAttributes = newEntityAttributes(map[string]string{"team": "payments"}, nil, &EC2Info{}, nil, zap.NewNop())
+	assert.Equal(t, map[string]string{"team": "payments"}, e.EntityAttributes())
+}
+
+func TestEntityStore_CreateLogFileEntityWithEntityAttributes(t *testing.T) {
+	e := EntityStore{
+		mode: config.ModeECS,
+		ecsInfo: &ECSInfo{
+			TaskARN:   "arn:aws:ecs:us-west-2:111122223333:task/default/abc",
+			Cluster:   "default",
+			AccountID: "111122223333",
+		},
+		entityAttributes: newEntityAttributes(map[string]string{
+			"team": "payments",
+			// the attributes set by the agent are not replaced
+			entityattributes.ECSCluster: "other",
+		}, nil, &EC2Info{}, nil, zap.NewNop()),
+	}
+
+	entity := e.CreateLogFileEntity("glob", "group")
+
+	expectedAttributes := map[string]*string{
+		PlatformType:                aws.String(entityattributes.AttributeEntityECSPlatform),
+		entityattributes.ECSCluster: aws.String("default"),
+		"team":                      aws.String("payments"),
+	}
+	assert.Equal(t, dereferenceMap(expectedAttributes), dereferenceMap(entity.Attributes))
+}
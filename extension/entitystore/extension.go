@@ -11,6 +11,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/jellydator/ttlcache/v3"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/extension"
@@ -21,6 +23,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/internal/ec2metadataprovider"
 	"github.com/aws/amazon-cloudwatch-agent/internal/retryer"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/awsentity/entityattributes"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/ec2tagger"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
 	"github.com/aws/amazon-cloudwatch-agent/translator/config"
 )
@@ -65,6 +68,14 @@ type EntityStore struct {
 	// eksInfo stores information about EKS such as pod to service Env map
 	eksInfo *eksInfo
 
+	// ecsInfo stores information about the ECS task such as the task ARN,
+	// cluster, service name, and launch type
+	ecsInfo *ECSInfo
+
+	// hostInfo stores information about the hybrid host such as the cloud
+	// provider, the Azure resource group or the datacenter
+	hostInfo *HostInfo
+
 	// serviceprovider stores information about possible service names
 	// that we can attach to the entity
 	serviceprovider serviceProviderInterface
@@ -75,6 +86,10 @@ type EntityStore struct {
 
 	metadataprovider ec2metadataprovider.MetadataProvider
 
+	// entityAttributes stores the user defined attributes added to all the
+	// entities
+	entityAttributes *entityAttributes
+
 	podTerminationCheckInterval time.Duration
 }
 
@@ -94,15 +109,32 @@ func (e *EntityStore) Start(ctx context.Context, host component.Host) error {
 		Filename: e.config.Filename,
 	}
 	e.serviceprovider = newServiceProvider(e.mode, e.config.Region, &e.ec2Info, e.metadataprovider, getEC2Provider, ec2CredentialConfig, e.done, e.logger)
+	e.entityAttributes = newEntityAttributes(e.config.EntityAttributes, e.config.EC2TagKeys, &e.ec2Info, func() ec2iface.EC2API {
+		return getEC2Provider(e.config.Region, ec2CredentialConfig)
+	}, e.logger)
 	switch e.mode {
 	case config.ModeEC2:
 		e.ec2Info = *newEC2Info(e.metadataprovider, e.done, e.config.Region, e.logger)
 		go e.ec2Info.initEc2Info()
+		if len(e.config.EC2TagKeys) > 0 {
+			tagRetryer := NewRetryer(false, true, describeTagsJitterMin, describeTagsJitterMax, ec2tagger.BackoffSleepArray, infRetry, e.done, e.logger)
+			go tagRetryer.refreshLoop(e.entityAttributes.refreshTags)
+		}
 		// Instance metadata tags is not usable for EKS nodes
 		// https://github.com/kubernetes/cloud-provider-aws/issues/762
 		if e.kubernetesMode == "" {
 			go e.serviceprovider.startServiceProvider()
 		}
+	case config.ModeECS:
+		e.ecsInfo = newECSInfo(e.done, e.logger)
+		go e.ecsInfo.initECSInfo()
+	case config.ModeOnPrem, config.ModeOnPremise:
+		if e.config.Hybrid != nil && e.kubernetesMode == "" {
+			e.hostInfo = newHostInfo(e.config.Hybrid, func() stsiface.STSAPI {
+				return getSTSProvider(e.config.Region, ec2CredentialConfig)
+			}, e.done, e.logger)
+			go e.hostInfo.initHostInfo()
+		}
 	}
 	if e.kubernetesMode != "" {
 		e.eksInfo = newEKSInfo(e.logger)
@@ -138,6 +170,26 @@ func (e *EntityStore) EC2Info() EC2Info {
 	return e.ec2Info
 }
 
+// ECSInfo returns the ECS task information, which is nil when not running in ECS mode.
+func (e *EntityStore) ECSInfo() *ECSInfo {
+	return e.ecsInfo
+}
+
+// HostInfo returns the hybrid host information, which is nil unless the
+// hybrid entities are enabled in the on-premise mode.
+func (e *EntityStore) HostInfo() *HostInfo {
+	return e.hostInfo
+}
+
+// EntityAttributes returns the user defined attributes added to all the
+// entities.
+func (e *EntityStore) EntityAttributes() map[string]string {
+	if e.entityAttributes == nil {
+		return nil
+	}
+	return e.entityAttributes.get()
+}
+
 func (e *EntityStore) SetNativeCredential(client client.ConfigProvider) {
 	e.nativeCredential = client
 }
@@ -148,6 +200,9 @@ func (e *EntityStore) NativeCredentialExists() bool {
 
 // CreateLogFileEntity creates the entity for log events that are being uploaded from a log file in the environment.
 func (e *EntityStore) CreateLogFileEntity(logFileGlob LogFileGlob, logGroupName LogGroupName) *cloudwatchlogs.Entity {
+	if e.mode == config.ModeECS {
+		return e.createECSTaskEntity()
+	}
 	if e.serviceprovider == nil {
 		return nil
 	}
@@ -159,6 +214,7 @@ func (e *EntityStore) CreateLogFileEntity(logFileGlob LogFileGlob, logGroupName
 	if _, ok := keyAttributes[entityattributes.AwsAccountId]; !ok {
 		return nil
 	}
+	entityattributes.AddCustomAttributes(attributeMap, e.EntityAttributes())
 	return &cloudwatchlogs.Entity{
 		KeyAttributes: keyAttributes,
 		Attributes:    attributeMap,
@@ -245,16 +301,61 @@ func (e *EntityStore) createAttributeMap() map[string]*string {
 	switch e.mode {
 	case config.ModeEC2:
 		attributeMap[PlatformType] = aws.String(EC2PlatForm)
+	case config.ModeECS:
+		attributeMap[PlatformType] = aws.String(entityattributes.AttributeEntityECSPlatform)
+		if e.ecsInfo != nil {
+			addNonEmptyToMap(attributeMap, entityattributes.ECSCluster, e.ecsInfo.GetCluster())
+			addNonEmptyToMap(attributeMap, entityattributes.ECSService, e.ecsInfo.GetServiceName())
+			addNonEmptyToMap(attributeMap, entityattributes.ECSLaunchType, e.ecsInfo.GetLaunchType())
+		}
+	case config.ModeOnPrem, config.ModeOnPremise:
+		if e.hostInfo != nil {
+			attributeMap[PlatformType] = aws.String(entityattributes.AttributeEntityGenericPlatform)
+			addNonEmptyToMap(attributeMap, entityattributes.CloudProvider, e.hostInfo.GetCloudProvider())
+			addNonEmptyToMap(attributeMap, entityattributes.AzureSubscriptionID, e.hostInfo.GetSubscriptionID())
+			addNonEmptyToMap(attributeMap, entityattributes.AzureResourceGroup, e.hostInfo.GetResourceGroup())
+			addNonEmptyToMap(attributeMap, entityattributes.AzureVMID, e.hostInfo.GetVMID())
+			addNonEmptyToMap(attributeMap, entityattributes.AzureLocation, e.hostInfo.GetLocation())
+			addNonEmptyToMap(attributeMap, entityattributes.Datacenter, e.hostInfo.GetDatacenter())
+		}
 	}
 	return attributeMap
 }
 
+// createECSTaskEntity creates the AWS::ECS::Task resource entity for the task the agent is running in.
+func (e *EntityStore) createECSTaskEntity() *cloudwatchlogs.Entity {
+	if e.ecsInfo == nil || e.ecsInfo.GetTaskARN() == "" || e.ecsInfo.GetAccountID() == "" {
+		return nil
+	}
+	attributeMap := e.createAttributeMap()
+	entityattributes.AddCustomAttributes(attributeMap, e.EntityAttributes())
+	return &cloudwatchlogs.Entity{
+		KeyAttributes: map[string]*string{
+			entityattributes.EntityType:   aws.String(entityattributes.AttributeEntityAWSResource),
+			entityattributes.ResourceType: aws.String(entityattributes.AttributeEntityECSTaskResource),
+			entityattributes.Identifier:   aws.String(e.ecsInfo.GetTaskARN()),
+			entityattributes.AwsAccountId: aws.String(e.ecsInfo.GetAccountID()),
+		},
+		Attributes: attributeMap,
+	}
+}
+
 // createServiceKeyAttribute creates KeyAttributes for Service entities
 func (e *EntityStore) createServiceKeyAttributes(serviceAttr ServiceAttribute) map[string]*string {
 	serviceKeyAttr := map[string]*string{
 		entityattributes.EntityType: aws.String(Service),
 	}
 	addNonEmptyToMap(serviceKeyAttr, entityattributes.ServiceName, serviceAttr.ServiceName)
+	if e.hostInfo != nil {
+		// the hybrid hosts have no auto scaling group to fall back to
+		environment := serviceAttr.Environment
+		if environment == "" {
+			environment = e.hostInfo.GetEnvironment()
+		}
+		addNonEmptyToMap(serviceKeyAttr, entityattributes.DeploymentEnvironment, environment)
+		addNonEmptyToMap(serviceKeyAttr, entityattributes.AwsAccountId, e.hostInfo.GetAccountID())
+		return serviceKeyAttr
+	}
 	addNonEmptyToMap(serviceKeyAttr, entityattributes.DeploymentEnvironment, serviceAttr.Environment)
 	addNonEmptyToMap(serviceKeyAttr, entityattributes.AwsAccountId, e.ec2Info.GetAccountID())
 	return serviceKeyAttr
@@ -275,6 +376,16 @@ var getEC2Provider = func(region string, ec2CredentialConfig *configaws.Credenti
 		})
 }
 
+var getSTSProvider = func(region string, credentialConfig *configaws.CredentialConfig) stsiface.STSAPI {
+	credentialConfig.Region = region
+	return sts.New(
+		credentialConfig.Credentials(),
+		&aws.Config{
+			LogLevel: configaws.SDKLogLevel(),
+			Logger:   configaws.SDKLogger{},
+		})
+}
+
 func addNonEmptyToMap(m map[string]*string, key, value string) {
 	if value != "" {
 		m[key] = aws.String(value)
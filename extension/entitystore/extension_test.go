@@ -708,3 +708,88 @@ func assertIfNonEmpty(t *testing.T, message string, pattern string) {
 		assert.NotContains(t, message, pattern)
 	}
 }
+
+func TestEntityStore_createECSTaskEntity(t *testing.T) {
+	taskARN := "arn:aws:ecs:us-west-2:111122223333:task/default/abc"
+	e := EntityStore{
+		mode: config.ModeECS,
+		ecsInfo: &ECSInfo{
+			TaskARN:     taskARN,
+			Cluster:     "default",
+			ServiceName: "my-service",
+			LaunchType:  "FARGATE",
+			AccountID:   "111122223333",
+		},
+	}
+
+	entity := e.CreateLogFileEntity("glob", "group")
+
+	expectedEntity := cloudwatchlogs.Entity{
+		KeyAttributes: map[string]*string{
+			entityattributes.EntityType:   aws.String(entityattributes.AttributeEntityAWSResource),
+			entityattributes.ResourceType: aws.String(entityattributes.AttributeEntityECSTaskResource),
+			entityattributes.Identifier:   aws.String(taskARN),
+			entityattributes.AwsAccountId: aws.String("111122223333"),
+		},
+		Attributes: map[string]*string{
+			PlatformType:                   aws.String(entityattributes.AttributeEntityECSPlatform),
+			entityattributes.ECSCluster:    aws.String("default"),
+			entityattributes.ECSService:    aws.String("my-service"),
+			entityattributes.ECSLaunchType: aws.String("FARGATE"),
+		},
+	}
+	assert.Equal(t, dereferenceMap(expectedEntity.KeyAttributes), dereferenceMap(entity.KeyAttributes))
+	assert.Equal(t, dereferenceMap(expectedEntity.Attributes), dereferenceMap(entity.Attributes))
+
+	// the task metadata is not available yet
+	e.ecsInfo = &ECSInfo{}
+	assert.Nil(t, e.CreateLogFileEntity("glob", "group"))
+}
+
+func TestEntityStore_createHybridLogFileEntity(t *testing.T) {
+	glob := LogFileGlob("glob")
+	group := LogGroupName("group")
+	sp := new(mockServiceProvider)
+	sp.On("logFileServiceAttribute", glob, group).Return(ServiceAttribute{
+		ServiceName:       "test-service",
+		ServiceNameSource: ServiceNameSourceUserConfiguration,
+	})
+	e := EntityStore{
+		mode: config.ModeOnPremise,
+		hostInfo: &HostInfo{
+			CloudProvider:  CloudProviderAzure,
+			SubscriptionID: "8d10da13-8125-4ba9-a717-bf7490507b3d",
+			ResourceGroup:  "payments-rg",
+			VMID:           "02aab8a4-74ef-476e-8182-f6d2ba4166a6",
+			Location:       "westeurope",
+			AccountID:      "111122223333",
+		},
+		serviceprovider: sp,
+	}
+
+	entity := e.CreateLogFileEntity(glob, group)
+
+	expectedEntity := cloudwatchlogs.Entity{
+		KeyAttributes: map[string]*string{
+			entityattributes.DeploymentEnvironment: aws.String("azure:payments-rg"),
+			entityattributes.ServiceName:           aws.String("test-service"),
+			entityattributes.EntityType:            aws.String(Service),
+			entityattributes.AwsAccountId:          aws.String("111122223333"),
+		},
+		Attributes: map[string]*string{
+			ServiceNameSourceKey:                 aws.String(ServiceNameSourceUserConfiguration),
+			PlatformType:                         aws.String(entityattributes.AttributeEntityGenericPlatform),
+			entityattributes.CloudProvider:       aws.String(CloudProviderAzure),
+			entityattributes.AzureSubscriptionID: aws.String("8d10da13-8125-4ba9-a717-bf7490507b3d"),
+			entityattributes.AzureResourceGroup:  aws.String("payments-rg"),
+			entityattributes.AzureVMID:           aws.String("02aab8a4-74ef-476e-8182-f6d2ba4166a6"),
+			entityattributes.AzureLocation:       aws.String("westeurope"),
+		},
+	}
+	assert.Equal(t, dereferenceMap(expectedEntity.KeyAttributes), dereferenceMap(entity.KeyAttributes))
+	assert.Equal(t, dereferenceMap(expectedEntity.Attributes), dereferenceMap(entity.Attributes))
+
+	// the account ID is not available yet
+	e.hostInfo = &HostInfo{CloudProvider: CloudProviderOnPremises, Datacenter: "dc-east-1"}
+	assert.Nil(t, e.CreateLogFileEntity(glob, group))
+}
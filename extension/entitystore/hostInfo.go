@@ -0,0 +1,251 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package entitystore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"go.uber.org/zap"
+)
+
+const (
+	CloudProviderAuto        = "auto"
+	CloudProviderAzure       = "azure"
+	CloudProviderOnPremises  = "on_premises"
+	azureEnvironmentPrefix   = "azure:"
+	onPremEnvironmentPrefix  = "onprem:"
+	genericEnvironmentPrefix = "generic:"
+
+	azureMetadataEndpoint = "http://169.254.169.254/metadata/instance/compute?api-version=2021-02-01&format=json"
+
+	hostMetadataRequestTimeout = 2 * time.Second
+	hostInfoRetryInterval      = 1 * time.Minute
+)
+
+// azureComputeMetadata is the subset of the compute metadata of the Azure
+// Instance Metadata Service used for the entity.
+// See https://learn.microsoft.com/en-us/azure/virtual-machines/instance-metadata-service
+type azureComputeMetadata struct {
+	SubscriptionID    string `json:"subscriptionId"`
+	ResourceGroupName string `json:"resourceGroupName"`
+	VMID              string `json:"vmId"`
+	Location          string `json:"location"`
+}
+
+// HostInfo stores information about the hybrid host the agent runs on outside
+// of AWS, an Azure virtual machine or an on-premises server, and the account
+// its telemetry is sent to.
+type HostInfo struct {
+	CloudProvider  string
+	SubscriptionID string
+	ResourceGroup  string
+	VMID           string
+	Location       string
+	Datacenter     string
+	AccountID      string
+
+	// cloudProvider is the configured provider, the Azure metadata is only
+	// looked up when it is auto or azure.
+	cloudProvider    string
+	metadataEndpoint string
+	httpClient       *http.Client
+	stsAPI           func() stsiface.STSAPI
+	logger           *zap.Logger
+	done             chan struct{}
+	mutex            sync.RWMutex
+}
+
+func (hi *HostInfo) initHostInfo() {
+	hi.logger.Debug("Initializing HostInfo")
+	if err := hi.setHostMetadata(); err != nil {
+		return
+	}
+	hi.logger.Debug("Finished initializing HostInfo", zap.String("cloudProvider", hi.GetCloudProvider()))
+}
+
+func (hi *HostInfo) GetCloudProvider() string {
+	hi.mutex.RLock()
+	defer hi.mutex.RUnlock()
+	return hi.CloudProvider
+}
+
+func (hi *HostInfo) GetSubscriptionID() string {
+	hi.mutex.RLock()
+	defer hi.mutex.RUnlock()
+	return hi.SubscriptionID
+}
+
+func (hi *HostInfo) GetResourceGroup() string {
+	hi.mutex.RLock()
+	defer hi.mutex.RUnlock()
+	return hi.ResourceGroup
+}
+
+func (hi *HostInfo) GetVMID() string {
+	hi.mutex.RLock()
+	defer hi.mutex.RUnlock()
+	return hi.VMID
+}
+
+func (hi *HostInfo) GetLocation() string {
+	hi.mutex.RLock()
+	defer hi.mutex.RUnlock()
+	return hi.Location
+}
+
+func (hi *HostInfo) GetDatacenter() string {
+	hi.mutex.RLock()
+	defer hi.mutex.RUnlock()
+	return hi.Datacenter
+}
+
+func (hi *HostInfo) GetAccountID() string {
+	hi.mutex.RLock()
+	defer hi.mutex.RUnlock()
+	return hi.AccountID
+}
+
+// GetEnvironment returns the deployment environment of the services of the
+// host when none is configured, which groups the hosts by resource group on
+// Azure and by datacenter on premises.
+func (hi *HostInfo) GetEnvironment() string {
+	hi.mutex.RLock()
+	defer hi.mutex.RUnlock()
+	switch {
+	case hi.CloudProvider == CloudProviderAzure && hi.ResourceGroup != "":
+		return azureEnvironmentPrefix + hi.ResourceGroup
+	case hi.Datacenter != "":
+		return onPremEnvironmentPrefix + hi.Datacenter
+	default:
+		return genericEnvironmentPrefix + "default"
+	}
+}
+
+// setHostMetadata detects the cloud provider and looks up the account ID,
+// retrying until both are available since they do not change for the
+// lifetime of the agent.
+func (hi *HostInfo) setHostMetadata() error {
+	providerSet := false
+	for {
+		var err error
+		if !providerSet {
+			if err = hi.setCloudProvider(); err == nil {
+				providerSet = true
+			}
+		}
+		if err == nil {
+			if err = hi.setAccountID(); err == nil {
+				return nil
+			}
+		}
+		hi.logger.Debug("Failed to get the host metadata", zap.Error(err))
+		wait := time.NewTimer(hostInfoRetryInterval)
+		select {
+		case <-hi.done:
+			wait.Stop()
+			return errors.New("shutdown signal received")
+		case <-wait.C:
+		}
+	}
+}
+
+// setCloudProvider looks up the Azure metadata of the host. In auto mode,
+// the host is on premises if there is no Azure metadata service.
+func (hi *HostInfo) setCloudProvider() error {
+	if hi.cloudProvider == CloudProviderOnPremises {
+		hi.mutex.Lock()
+		hi.CloudProvider = CloudProviderOnPremises
+		hi.mutex.Unlock()
+		return nil
+	}
+	metadata, err := hi.getAzureMetadata()
+	if err != nil {
+		if hi.cloudProvider == CloudProviderAzure {
+			return err
+		}
+		hi.logger.Debug("Azure metadata not available, the host is on premises", zap.Error(err))
+		hi.mutex.Lock()
+		hi.CloudProvider = CloudProviderOnPremises
+		hi.mutex.Unlock()
+		return nil
+	}
+	hi.mutex.Lock()
+	hi.CloudProvider = CloudProviderAzure
+	hi.SubscriptionID = metadata.SubscriptionID
+	hi.ResourceGroup = metadata.ResourceGroupName
+	hi.VMID = metadata.VMID
+	hi.Location = metadata.Location
+	hi.mutex.Unlock()
+	return nil
+}
+
+func (hi *HostInfo) getAzureMetadata() (*azureComputeMetadata, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), hostMetadataRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, hi.metadataEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata", "true")
+	resp, err := hi.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from Azure metadata endpoint", resp.StatusCode)
+	}
+	var metadata azureComputeMetadata
+	if err = json.Unmarshal(body, &metadata); err != nil {
+		return nil, err
+	}
+	if metadata.SubscriptionID == "" {
+		return nil, errors.New("subscription ID missing from Azure metadata")
+	}
+	return &metadata, nil
+}
+
+// setAccountID looks up the account of the credentials of the agent, since
+// there is no instance metadata to read it from.
+func (hi *HostInfo) setAccountID() error {
+	output, err := hi.stsAPI().GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return err
+	}
+	hi.mutex.Lock()
+	hi.AccountID = aws.StringValue(output.Account)
+	hi.mutex.Unlock()
+	return nil
+}
+
+func newHostInfo(cfg *HybridConfig, stsAPI func() stsiface.STSAPI, done chan struct{}, logger *zap.Logger) *HostInfo {
+	cloudProvider := cfg.CloudProvider
+	if cloudProvider == "" {
+		cloudProvider = CloudProviderAuto
+	}
+	return &HostInfo{
+		Datacenter:       cfg.Datacenter,
+		cloudProvider:    cloudProvider,
+		metadataEndpoint: azureMetadataEndpoint,
+		// the metadata service must not be reached through a proxy
+		httpClient: &http.Client{Timeout: hostMetadataRequestTimeout, Transport: &http.Transport{Proxy: nil}},
+		stsAPI:     stsAPI,
+		done:       done,
+		logger:     logger,
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package entitystore
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type mockSTSClient struct {
+	stsiface.STSAPI
+	account string
+	err     error
+}
+
+func (m *mockSTSClient) GetCallerIdentity(*sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &sts.GetCallerIdentityOutput{Account: aws.String(m.account)}, nil
+}
+
+func TestSetHostMetadata(t *testing.T) {
+	type want struct {
+		CloudProvider  string
+		SubscriptionID string
+		ResourceGroup  string
+		VMID           string
+		Location       string
+		Environment    string
+	}
+	tests := []struct {
+		name          string
+		cloudProvider string
+		datacenter    string
+		status        int
+		response      string
+		want          want
+	}{
+		{
+			name:     "Azure",
+			status:   http.StatusOK,
+			response: `{"subscriptionId":"8d10da13-8125-4ba9-a717-bf7490507b3d","resourceGroupName":"payments-rg","vmId":"02aab8a4-74ef-476e-8182-f6d2ba4166a6","location":"westeurope","name":"vm-1"}`,
+			want: want{
+				CloudProvider:  CloudProviderAzure,
+				SubscriptionID: "8d10da13-8125-4ba9-a717-bf7490507b3d",
+				ResourceGroup:  "payments-rg",
+				VMID:           "02aab8a4-74ef-476e-8182-f6d2ba4166a6",
+				Location:       "westeurope",
+				Environment:    "azure:payments-rg",
+			},
+		},
+		{
+			name:       "OnPremisesWithoutAzureMetadata",
+			datacenter: "dc-east-1",
+			status:     http.StatusNotFound,
+			want: want{
+				CloudProvider: CloudProviderOnPremises,
+				Environment:   "onprem:dc-east-1",
+			},
+		},
+		{
+			name:          "OnPremisesConfigured",
+			cloudProvider: CloudProviderOnPremises,
+			status:        http.StatusOK,
+			response:      `{"subscriptionId":"8d10da13-8125-4ba9-a717-bf7490507b3d","resourceGroupName":"payments-rg"}`,
+			want: want{
+				CloudProvider: CloudProviderOnPremises,
+				Environment:   "generic:default",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "true", r.Header.Get("Metadata"))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+			sts := &mockSTSClient{account: "111122223333"}
+			hi := newHostInfo(&HybridConfig{CloudProvider: tt.cloudProvider, Datacenter: tt.datacenter}, func() stsiface.STSAPI {
+				return sts
+			}, make(chan struct{}), zap.NewNop())
+			hi.metadataEndpoint = server.URL
+			require.NoError(t, hi.setHostMetadata())
+			assert.Equal(t, tt.want.CloudProvider, hi.GetCloudProvider())
+			assert.Equal(t, tt.want.SubscriptionID, hi.GetSubscriptionID())
+			assert.Equal(t, tt.want.ResourceGroup, hi.GetResourceGroup())
+			assert.Equal(t, tt.want.VMID, hi.GetVMID())
+			assert.Equal(t, tt.want.Location, hi.GetLocation())
+			assert.Equal(t, tt.want.Environment, hi.GetEnvironment())
+			assert.Equal(t, tt.datacenter, hi.GetDatacenter())
+			assert.Equal(t, "111122223333", hi.GetAccountID())
+		})
+	}
+}
+
+func TestSetHostMetadata_Shutdown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	done := make(chan struct{})
+	close(done)
+
+	// the Azure metadata is required when azure is configured
+	hi := newHostInfo(&HybridConfig{CloudProvider: CloudProviderAzure}, func() stsiface.STSAPI {
+		return &mockSTSClient{account: "111122223333"}
+	}, done, zap.NewNop())
+	hi.metadataEndpoint = server.URL
+	assert.Error(t, hi.setHostMetadata())
+	assert.Empty(t, hi.GetCloudProvider())
+	assert.Empty(t, hi.GetAccountID())
+
+	// the entities need the account ID
+	hi = newHostInfo(&HybridConfig{CloudProvider: CloudProviderOnPremises}, func() stsiface.STSAPI {
+		return &mockSTSClient{err: errors.New("no credentials")}
+	}, done, zap.NewNop())
+	assert.Error(t, hi.setHostMetadata())
+	assert.Equal(t, CloudProviderOnPremises, hi.GetCloudProvider())
+	assert.Empty(t, hi.GetAccountID())
+}
@@ -31,6 +31,7 @@ const (
 	ServiceNameSourceUnknown           = "Unknown"
 	ServiceNameSourceUserConfiguration = "UserConfiguration"
 	ServiceNameSourceK8sWorkload       = "K8sWorkload"
+	ServiceNameSourceEnvironment       = "EnvironmentVariable"
 
 	describeTagsJitterMax = 3600
 	describeTagsJitterMin = 3000
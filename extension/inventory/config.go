@@ -0,0 +1,46 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+const (
+	customTypePrefix = "Custom:"
+	minInterval      = 5 * time.Minute
+)
+
+type Config struct {
+	// Interval is how often the inventory is reported.
+	Interval time.Duration `mapstructure:"interval"`
+	// TypeName is the custom inventory type, which must start with Custom:.
+	TypeName string `mapstructure:"type_name"`
+	// ConfigHash identifies the agent configuration the agent was started
+	// with. It is computed by the translator.
+	ConfigHash string `mapstructure:"config_hash,omitempty"`
+	// Pipelines are the names of the pipelines of the agent configuration.
+	Pipelines []string `mapstructure:"pipelines,omitempty"`
+	Region    string   `mapstructure:"region"`
+	Profile   string   `mapstructure:"profile,omitempty"`
+	RoleARN   string   `mapstructure:"role_arn,omitempty"`
+	Filename  string   `mapstructure:"shared_credential_file,omitempty"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+func (c *Config) Validate() error {
+	if c.Interval < minInterval {
+		return fmt.Errorf("interval must be at least %v", minInterval)
+	}
+	if !strings.HasPrefix(c.TypeName, customTypePrefix) || len(c.TypeName) == len(customTypePrefix) {
+		return errors.New("type_name must start with " + customTypePrefix)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func TestUnmarshalDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NoError(t, confmap.New().Unmarshal(cfg))
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]struct {
+		cfg     *Config
+		wantErr bool
+	}{
+		"WithDefault": {
+			cfg: &Config{Interval: defaultInterval, TypeName: defaultTypeName},
+		},
+		"WithShortInterval": {
+			cfg:     &Config{Interval: time.Minute, TypeName: defaultTypeName},
+			wantErr: true,
+		},
+		"WithoutCustomPrefix": {
+			cfg:     &Config{Interval: defaultInterval, TypeName: "AWS:Application"},
+			wantErr: true,
+		},
+		"WithOnlyCustomPrefix": {
+			cfg:     &Config{Interval: defaultInterval, TypeName: "Custom:"},
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := testCase.cfg.Validate()
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
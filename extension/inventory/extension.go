@@ -0,0 +1,179 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
+	"go.uber.org/zap"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/useragent"
+	"github.com/aws/amazon-cloudwatch-agent/extension/entitystore"
+	"github.com/aws/amazon-cloudwatch-agent/internal/version"
+)
+
+const (
+	schemaVersion     = "1.0"
+	captureTimeLayout = "2006-01-02T15:04:05Z"
+	separator         = ","
+)
+
+// initialDelay leaves time for the entity store to get the instance ID
+// before the first report. Overridden in tests.
+var initialDelay = time.Minute
+
+var errNoInstanceID = errors.New("no EC2 or managed instance ID")
+
+// inventoryPutter is the part of the SSM API used by the reporter.
+type inventoryPutter interface {
+	PutInventoryWithContext(ctx aws.Context, input *ssm.PutInventoryInput, opts ...request.Option) (*ssm.PutInventoryOutput, error)
+}
+
+// reporter puts the agent version, configuration and components as a custom
+// inventory type of the instance in SSM Inventory, so that the fleet owners
+// can query which agents run which configuration.
+type reporter struct {
+	logger *zap.Logger
+	config *Config
+	client inventoryPutter
+
+	// instanceID and components are overridden in tests.
+	instanceID func() string
+	components func() (inputs, processors, outputs []string)
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ extension.Extension = (*reporter)(nil)
+
+func newReporter(logger *zap.Logger, config *Config) *reporter {
+	return &reporter{
+		logger:     logger,
+		config:     config,
+		instanceID: instanceID,
+		components: useragent.Get().Components,
+	}
+}
+
+func (r *reporter) Start(context.Context, component.Host) error {
+	if r.client == nil {
+		credentialConfig := &configaws.CredentialConfig{
+			Region:       r.config.Region,
+			RoleARN:      r.config.RoleARN,
+			Profile:      r.config.Profile,
+			Filename:     r.config.Filename,
+			ProxyService: configaws.ProxyServiceSSM,
+		}
+		r.client = ssm.New(credentialConfig.Credentials(), &aws.Config{
+			LogLevel: configaws.SDKLogLevel(),
+			Logger:   configaws.SDKLogger{},
+		})
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.run(ctx)
+	}()
+	return nil
+}
+
+func (r *reporter) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+	return nil
+}
+
+// run reports the inventory after the initial delay and then every interval
+// until the context is done.
+func (r *reporter) run(ctx context.Context) {
+	timer := time.NewTimer(initialDelay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			if err := r.report(ctx); err != nil && ctx.Err() == nil {
+				r.logger.Warn("Unable to report the agent inventory", zap.String("type", r.config.TypeName), zap.Error(err))
+			}
+			timer.Reset(r.config.Interval)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *reporter) report(ctx context.Context) error {
+	id := r.instanceID()
+	if id == "" {
+		return errNoInstanceID
+	}
+	content, err := r.content()
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(b)
+	_, err = r.client.PutInventoryWithContext(ctx, &ssm.PutInventoryInput{
+		InstanceId: aws.String(id),
+		Items: []*ssm.InventoryItem{
+			{
+				TypeName:      aws.String(r.config.TypeName),
+				SchemaVersion: aws.String(schemaVersion),
+				CaptureTime:   aws.String(time.Now().UTC().Format(captureTimeLayout)),
+				// SSM Inventory does not update the item if the hash did not change
+				ContentHash: aws.String(hex.EncodeToString(hash[:])),
+				Content:     []map[string]*string{content},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	r.logger.Debug("Reported the agent inventory", zap.String("type", r.config.TypeName), zap.String("instance", id))
+	return nil
+}
+
+// content returns the attributes of the inventory item.
+func (r *reporter) content() (map[string]*string, error) {
+	inputs, processors, outputs := r.components()
+	content := map[string]*string{
+		"AgentVersion": aws.String(version.Number()),
+		"ConfigHash":   aws.String(r.config.ConfigHash),
+		"Pipelines":    aws.String(strings.Join(r.config.Pipelines, separator)),
+		"Inputs":       aws.String(strings.Join(inputs, separator)),
+		"Processors":   aws.String(strings.Join(processors, separator)),
+		"Outputs":      aws.String(strings.Join(outputs, separator)),
+	}
+	if es := entitystore.GetEntityStore(); es != nil {
+		content["Mode"] = aws.String(es.Mode())
+		if attributes := es.EntityAttributes(); len(attributes) > 0 {
+			b, err := json.Marshal(attributes)
+			if err != nil {
+				return nil, err
+			}
+			content["EntityAttributes"] = aws.String(string(b))
+		}
+	}
+	return content, nil
+}
@@ -0,0 +1,123 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/zap"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/version"
+)
+
+type mockPutter struct {
+	mu     sync.Mutex
+	inputs []*ssm.PutInventoryInput
+	err    error
+}
+
+func (m *mockPutter) PutInventoryWithContext(_ aws.Context, input *ssm.PutInventoryInput, _ ...request.Option) (*ssm.PutInventoryOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inputs = append(m.inputs, input)
+	return &ssm.PutInventoryOutput{}, m.err
+}
+
+func (m *mockPutter) calls() []*ssm.PutInventoryInput {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.inputs
+}
+
+func newTestReporter(client inventoryPutter, id string) *reporter {
+	r := newReporter(zap.NewNop(), &Config{
+		Interval:   time.Hour,
+		TypeName:   defaultTypeName,
+		ConfigHash: "abc123",
+		Pipelines:  []string{"logs/emf_logs", "metrics/host"},
+	})
+	r.client = client
+	r.instanceID = func() string { return id }
+	r.components = func() ([]string, []string, []string) {
+		return []string{"cpu", "logs"}, []string{"batch"}, []string{"cloudwatch", "cloudwatchlogs"}
+	}
+	return r
+}
+
+func TestReport(t *testing.T) {
+	client := &mockPutter{}
+	r := newTestReporter(client, "i-0123456789abcdef0")
+	require.NoError(t, r.report(context.Background()))
+	require.NoError(t, r.report(context.Background()))
+
+	calls := client.calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "i-0123456789abcdef0", aws.StringValue(calls[0].InstanceId))
+	require.Len(t, calls[0].Items, 1)
+	item := calls[0].Items[0]
+	assert.Equal(t, defaultTypeName, aws.StringValue(item.TypeName))
+	assert.Equal(t, schemaVersion, aws.StringValue(item.SchemaVersion))
+	_, err := time.Parse(captureTimeLayout, aws.StringValue(item.CaptureTime))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*string{
+		"AgentVersion": aws.String(version.Number()),
+		"ConfigHash":   aws.String("abc123"),
+		"Pipelines":    aws.String("logs/emf_logs,metrics/host"),
+		"Inputs":       aws.String("cpu,logs"),
+		"Processors":   aws.String("batch"),
+		"Outputs":      aws.String("cloudwatch,cloudwatchlogs"),
+	}, item.Content[0])
+	// the hash does not depend on the capture time
+	assert.NotEmpty(t, aws.StringValue(item.ContentHash))
+	assert.Equal(t, aws.StringValue(item.ContentHash), aws.StringValue(calls[1].Items[0].ContentHash))
+}
+
+func TestReportErrors(t *testing.T) {
+	client := &mockPutter{}
+	assert.ErrorIs(t, newTestReporter(client, "").report(context.Background()), errNoInstanceID)
+	assert.Empty(t, client.calls())
+
+	client.err = errors.New("AccessDeniedException")
+	assert.ErrorContains(t, newTestReporter(client, "mi-0123456789abcdef0").report(context.Background()), "AccessDeniedException")
+}
+
+func TestStartShutdown(t *testing.T) {
+	defer func(original time.Duration) {
+		initialDelay = original
+	}(initialDelay)
+	initialDelay = time.Millisecond
+
+	client := &mockPutter{}
+	r := newTestReporter(client, "i-0123456789abcdef0")
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Eventually(t, func() bool {
+		return len(client.calls()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Len(t, client.calls(), 1)
+}
+
+func TestManagedInstanceID(t *testing.T) {
+	defer func(original func() string) {
+		registrationPath = original
+	}(registrationPath)
+	path := filepath.Join(t.TempDir(), "registration")
+	registrationPath = func() string { return path }
+
+	assert.Equal(t, "", instanceID())
+	require.NoError(t, os.WriteFile(path, []byte(`{"ManagedInstanceID":"mi-0123456789abcdef0","Region":"us-west-2"}`), 0600))
+	assert.Equal(t, "mi-0123456789abcdef0", instanceID())
+}
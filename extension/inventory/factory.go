@@ -0,0 +1,41 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
+)
+
+const (
+	defaultInterval = time.Hour
+	defaultTypeName = "Custom:AmazonCloudWatchAgent"
+)
+
+var (
+	TypeStr, _ = component.NewType("inventory")
+)
+
+func NewFactory() extension.Factory {
+	return extension.NewFactory(
+		TypeStr,
+		createDefaultConfig,
+		createExtension,
+		component.StabilityLevelAlpha,
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		Interval: defaultInterval,
+		TypeName: defaultTypeName,
+	}
+}
+
+func createExtension(_ context.Context, settings extension.CreateSettings, cfg component.Config) (extension.Extension, error) {
+	return newReporter(settings.Logger, cfg.(*Config)), nil
+}
@@ -0,0 +1,46 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/aws/amazon-cloudwatch-agent/extension/entitystore"
+)
+
+// registrationPath returns the file where the SSM agent saves the ID of the
+// managed instances registered with a hybrid activation. Overridden in tests.
+var registrationPath = func() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("ProgramData"), "Amazon", "SSM", "InstanceData", "registration")
+	}
+	return "/var/lib/amazon/ssm/registration"
+}
+
+type registration struct {
+	ManagedInstanceID string `json:"ManagedInstanceID"`
+}
+
+// instanceID returns the ID of the EC2 instance from the entity store, or
+// the ID of the on-premises managed instance.
+func instanceID() string {
+	if es := entitystore.GetEntityStore(); es != nil {
+		ec2Info := es.EC2Info()
+		if id := ec2Info.GetInstanceID(); id != "" {
+			return id
+		}
+	}
+	content, err := os.ReadFile(registrationPath())
+	if err != nil {
+		return ""
+	}
+	var r registration
+	if err = json.Unmarshal(content, &r); err != nil {
+		return ""
+	}
+	return r.ManagedInstanceID
+}
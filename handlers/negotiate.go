@@ -0,0 +1,112 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/compression"
+)
+
+const headerContentEncoding = "Content-Encoding"
+
+type uncompressedBodyKey struct{}
+
+// RequestCompression compresses the request bodies of the operations with the
+// codec negotiated with the endpoint. If the endpoint rejects the codec with
+// 415 Unsupported Media Type, the request is retried with the fallback codec.
+type RequestCompression struct {
+	opNames    []string
+	negotiator *compression.Negotiator
+}
+
+// NewRequestCompression creates a RequestCompression for the operations.
+func NewRequestCompression(opNames []string, negotiator *compression.Negotiator) *RequestCompression {
+	return &RequestCompression{opNames: opNames, negotiator: negotiator}
+}
+
+// Configure adds the handlers to the client handlers. The bodies are
+// compressed once they are built and again if the retry falls back.
+func (c *RequestCompression) Configure(handlers *request.Handlers) {
+	handlers.Build.PushBackNamed(request.NamedHandler{Name: "RequestCompressionHandler", Fn: c.compress})
+	handlers.Retry.PushFrontNamed(request.NamedHandler{Name: "RequestCompressionFallbackHandler", Fn: c.fallBack})
+}
+
+// Stats returns the stats of the compressed requests.
+func (c *RequestCompression) Stats() compression.Stats {
+	return c.negotiator.Stats()
+}
+
+func (c *RequestCompression) compress(req *request.Request) {
+	if !c.matches(req.Operation.Name) {
+		return
+	}
+	body, err := io.ReadAll(req.GetBody())
+	if err != nil {
+		log.Printf("I! Error occurred when trying to compress payload for operation %v, uncompressed request is sent, error: %v", req.Operation.Name, err)
+		req.ResetBody()
+		return
+	}
+	// the uncompressed body is kept to compress it again with the fallback codec
+	req.SetContext(context.WithValue(req.Context(), uncompressedBodyKey{}, body))
+	c.setBody(req, body, c.negotiator.Codec())
+}
+
+func (c *RequestCompression) fallBack(req *request.Request) {
+	if req.HTTPResponse == nil || req.HTTPResponse.StatusCode != http.StatusUnsupportedMediaType {
+		return
+	}
+	body, ok := req.Context().Value(uncompressedBodyKey{}).([]byte)
+	if !ok {
+		return
+	}
+	rejected, err := compression.Get(req.HTTPRequest.Header.Get(headerContentEncoding))
+	if err != nil || !c.negotiator.Reject(rejected) {
+		return
+	}
+	c.setBody(req, body, c.negotiator.Codec())
+	req.Retryable = aws.Bool(true)
+}
+
+func (c *RequestCompression) setBody(req *request.Request, body []byte, codec compression.Codec) {
+	compressed, err := c.negotiator.Compress(codec, body)
+	if err != nil {
+		log.Printf("I! Error occurred when trying to compress payload for operation %v, uncompressed request is sent, error: %v", req.Operation.Name, err)
+		setBufferBody(req, body, "")
+		return
+	}
+	if len(body) <= len(compressed) {
+		log.Printf("D! The payload is not compressed. original payload size: %v, compressed payload size: %v.", len(body), len(compressed))
+		setBufferBody(req, body, "")
+		return
+	}
+	setBufferBody(req, compressed, codec.Name())
+}
+
+func setBufferBody(req *request.Request, body []byte, encoding string) {
+	req.SetBufferBody(body)
+	req.HTTPRequest.ContentLength = int64(len(body))
+	req.HTTPRequest.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	if encoding == "" {
+		req.HTTPRequest.Header.Del(headerContentEncoding)
+	} else {
+		req.HTTPRequest.Header.Set(headerContentEncoding, encoding)
+	}
+}
+
+func (c *RequestCompression) matches(opName string) bool {
+	for _, name := range c.opNames {
+		if name == opName {
+			return true
+		}
+	}
+	return false
+}
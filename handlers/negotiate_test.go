@@ -0,0 +1,74 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/compression"
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
+)
+
+func TestRequestCompressionFallback(t *testing.T) {
+	var mutex sync.Mutex
+	var encodings []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := r.Header.Get(headerContentEncoding)
+		mutex.Lock()
+		encodings = append(encodings, encoding)
+		mutex.Unlock()
+		if encoding == compression.Zstd {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			_, _ = w.Write([]byte(`{"__type":"UnsupportedMediaType","message":"unsupported content encoding"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	negotiator, err := compression.NewNegotiator("test", compression.Zstd)
+	require.NoError(t, err)
+	rc := NewRequestCompression([]string{"PutLogEvents"}, negotiator)
+	client := cloudwatchlogs.New(session.Must(session.NewSession(&aws.Config{
+		Endpoint:    aws.String(server.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(1),
+	})))
+	rc.Configure(&client.Handlers)
+
+	input := &cloudwatchlogs.PutLogEventsInput{
+		LogGroupName:  aws.String("group"),
+		LogStreamName: aws.String("stream"),
+		LogEvents: []*cloudwatchlogs.InputLogEvent{
+			{Message: aws.String(strings.Repeat("the same message compresses well ", 10)), Timestamp: aws.Int64(1700000000000)},
+		},
+	}
+	// the zstd request is rejected and sent again with gzip
+	_, err = client.PutLogEvents(input)
+	require.NoError(t, err)
+	// gzip is used until the retry interval elapses
+	_, err = client.PutLogEvents(input)
+	require.NoError(t, err)
+	// operations that are not listed are not compressed
+	_, err = client.CreateLogGroup(&cloudwatchlogs.CreateLogGroupInput{LogGroupName: aws.String("group")})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{compression.Zstd, compression.Gzip, compression.Gzip, ""}, encodings)
+	stats := rc.Stats()
+	assert.Equal(t, compression.Gzip, stats.Codec)
+	assert.EqualValues(t, 1, stats.Fallbacks)
+	assert.EqualValues(t, 3, stats.Requests)
+	assert.Less(t, stats.Ratio(), 1.0)
+}
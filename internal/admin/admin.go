@@ -0,0 +1,174 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package admin provides the local gRPC admin API of the agent, to introspect
+// and control the running agent without restarting it. The messages are the
+// protobuf well-known types, so the service does not need generated code.
+package admin
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/featuregate"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	ServiceName = "amazon.cloudwatch.agent.admin.v1.Admin"
+
+	methodGetConfig         = "GetConfig"
+	methodListFeatureGates  = "ListFeatureGates"
+	methodListPipelines     = "ListPipelines"
+	methodRescanLogFiles    = "RescanLogFiles"
+	methodRotateCredentials = "RotateCredentials"
+	methodSetLogLevel       = "SetLogLevel"
+)
+
+// Pipeline is an OTel pipeline of the running agent.
+type Pipeline struct {
+	Name       string
+	Receivers  []string
+	Processors []string
+	Exporters  []string
+}
+
+// Handler performs the operations of the admin API on the running agent.
+type Handler interface {
+	// Config returns the effective OTel configuration as YAML.
+	Config() string
+	// Pipelines returns the OTel pipelines of the running agent.
+	Pipelines() []Pipeline
+	// RescanLogFiles looks for new log files to collect without waiting for
+	// the next scan.
+	RescanLogFiles()
+	// RotateCredentials expires the cached AWS credentials, so they are
+	// retrieved again on the next request. Returns the number of credentials
+	// expired.
+	RotateCredentials() int
+	// SetLogLevel sets the level of the agent logs until the agent restarts.
+	SetLogLevel(level string) error
+}
+
+// adminServer is the server API of the admin service.
+type adminServer interface {
+	getConfig(context.Context, *emptypb.Empty) (*wrapperspb.StringValue, error)
+	listFeatureGates(context.Context, *emptypb.Empty) (*structpb.Struct, error)
+	listPipelines(context.Context, *emptypb.Empty) (*structpb.Struct, error)
+	rescanLogFiles(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	rotateCredentials(context.Context, *emptypb.Empty) (*wrapperspb.Int64Value, error)
+	setLogLevel(context.Context, *wrapperspb.StringValue) (*emptypb.Empty, error)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*adminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: methodGetConfig, Handler: unaryHandler((*service).getConfig)},
+		{MethodName: methodListFeatureGates, Handler: unaryHandler((*service).listFeatureGates)},
+		{MethodName: methodListPipelines, Handler: unaryHandler((*service).listPipelines)},
+		{MethodName: methodRescanLogFiles, Handler: unaryHandler((*service).rescanLogFiles)},
+		{MethodName: methodRotateCredentials, Handler: unaryHandler((*service).rotateCredentials)},
+		{MethodName: methodSetLogLevel, Handler: unaryHandler((*service).setLogLevel)},
+	},
+}
+
+// unaryHandler decodes the request of the method and calls it through the
+// interceptor of the server, like the generated gRPC code.
+func unaryHandler[Req any, Resp any](method func(*service, context.Context, *Req) (Resp, error)) func(any, context.Context, func(any) error, grpc.UnaryServerInterceptor) (any, error) {
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		in := new(Req)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		if interceptor == nil {
+			return method(srv.(*service), ctx, in)
+		}
+		info := &grpc.UnaryServerInfo{Server: srv}
+		return interceptor(ctx, in, info, func(ctx context.Context, req any) (any, error) {
+			return method(srv.(*service), ctx, req.(*Req))
+		})
+	}
+}
+
+// service implements the methods of the admin API with the Handler.
+type service struct {
+	handler Handler
+	health  *healthRegistry
+	gates   *featuregate.Registry
+}
+
+var _ adminServer = (*service)(nil)
+
+func (s *service) getConfig(context.Context, *emptypb.Empty) (*wrapperspb.StringValue, error) {
+	return wrapperspb.String(s.handler.Config()), nil
+}
+
+func (s *service) listFeatureGates(context.Context, *emptypb.Empty) (*structpb.Struct, error) {
+	var gates []any
+	s.gates.VisitAll(func(gate *featuregate.Gate) {
+		gates = append(gates, map[string]any{
+			"id":          gate.ID(),
+			"stage":       gate.Stage().String(),
+			"enabled":     gate.IsEnabled(),
+			"description": gate.Description(),
+		})
+	})
+	return structpb.NewStruct(map[string]any{"gates": gates})
+}
+
+func (s *service) listPipelines(context.Context, *emptypb.Empty) (*structpb.Struct, error) {
+	var pipelines []any
+	for _, pipeline := range s.handler.Pipelines() {
+		pipelines = append(pipelines, map[string]any{
+			"name":       pipeline.Name,
+			"receivers":  toList(pipeline.Receivers),
+			"processors": toList(pipeline.Processors),
+			"exporters":  toList(pipeline.Exporters),
+		})
+	}
+	var components []any
+	for _, c := range s.health.components() {
+		component := map[string]any{
+			"id":        c.ID,
+			"kind":      c.Kind,
+			"pipelines": toList(c.Pipelines),
+			"status":    c.Status,
+			"timestamp": c.Timestamp.UTC().Format("2006-01-02T15:04:05Z"),
+		}
+		if c.Error != "" {
+			component["error"] = c.Error
+		}
+		components = append(components, component)
+	}
+	return structpb.NewStruct(map[string]any{
+		"pipelines":  pipelines,
+		"components": components,
+	})
+}
+
+func (s *service) rescanLogFiles(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	s.handler.RescanLogFiles()
+	return &emptypb.Empty{}, nil
+}
+
+func (s *service) rotateCredentials(context.Context, *emptypb.Empty) (*wrapperspb.Int64Value, error) {
+	return wrapperspb.Int64(int64(s.handler.RotateCredentials())), nil
+}
+
+func (s *service) setLogLevel(_ context.Context, level *wrapperspb.StringValue) (*emptypb.Empty, error) {
+	if err := s.handler.SetLogLevel(level.GetValue()); err != nil {
+		return nil, invalidArgument(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func toList(values []string) []any {
+	list := make([]any, len(values))
+	for i, value := range values {
+		list[i] = value
+	}
+	return list
+}
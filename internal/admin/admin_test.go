@@ -0,0 +1,157 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/featuregate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockHandler struct {
+	rescans  int
+	logLevel string
+}
+
+func (m *mockHandler) Config() string {
+	return "receivers:\n  otlp: {}\n"
+}
+
+func (m *mockHandler) Pipelines() []Pipeline {
+	return []Pipeline{{Name: "metrics/host", Receivers: []string{"telegraf_cpu"}, Exporters: []string{"awscloudwatch"}}}
+}
+
+func (m *mockHandler) RescanLogFiles() {
+	m.rescans++
+}
+
+func (m *mockHandler) RotateCredentials() int {
+	return 2
+}
+
+func (m *mockHandler) SetLogLevel(level string) error {
+	if level != "DEBUG" {
+		return errors.New("invalid log level")
+	}
+	m.logLevel = level
+	return nil
+}
+
+func TestAdminAPI(t *testing.T) {
+	original, originalGates := health, gates
+	defer func() { health, gates = original, originalGates }()
+	health = &healthRegistry{health: map[string]ComponentHealth{}}
+	gates = featuregate.NewRegistry()
+	gates.MustRegister("cwagent.test", featuregate.StageAlpha, featuregate.WithRegisterDescription("Test gate"))
+	require.NoError(t, gates.Set("cwagent.test", true))
+	RecordStatus(&component.InstanceID{
+		ID:          component.MustNewID("awscloudwatch"),
+		Kind:        component.KindExporter,
+		PipelineIDs: map[component.ID]struct{}{component.MustNewIDWithName("metrics", "host"): {}},
+	}, component.NewStatusEvent(component.StatusOK))
+
+	path := filepath.Join(t.TempDir(), "admin.sock")
+	handler := &mockHandler{}
+	server, err := NewServer(path, handler)
+	require.NoError(t, err)
+	server.Start()
+	defer server.Stop()
+
+	ctx := context.Background()
+	client, err := NewClient(path)
+	require.NoError(t, err)
+	defer client.Close()
+
+	config, err := client.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "receivers:\n  otlp: {}\n", config)
+
+	pipelines, err := client.ListPipelines(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{
+		"name":       "metrics/host",
+		"receivers":  []any{"telegraf_cpu"},
+		"processors": []any{},
+		"exporters":  []any{"awscloudwatch"},
+	}}, pipelines["pipelines"])
+	components := pipelines["components"].([]any)
+	require.Len(t, components, 1)
+	c := components[0].(map[string]any)
+	assert.Equal(t, "awscloudwatch", c["id"])
+	assert.Equal(t, "Exporter", c["kind"])
+	assert.Equal(t, "StatusOK", c["status"])
+	assert.Equal(t, []any{"metrics/host"}, c["pipelines"])
+	assert.NotContains(t, c, "error")
+
+	featureGates, err := client.ListFeatureGates(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{
+		"id":          "cwagent.test",
+		"stage":       "Alpha",
+		"enabled":     true,
+		"description": "Test gate",
+	}}, featureGates["gates"])
+
+	require.NoError(t, client.RescanLogFiles(ctx))
+	assert.Equal(t, 1, handler.rescans)
+
+	expired, err := client.RotateCredentials(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, expired)
+
+	require.NoError(t, client.SetLogLevel(ctx, "DEBUG"))
+	assert.Equal(t, "DEBUG", handler.logLevel)
+	err = client.SetLogLevel(ctx, "VERBOSE")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "admin.sock")
+	server, err := NewServer(path, &mockHandler{})
+	require.NoError(t, err)
+	server.Start()
+	defer server.Stop()
+
+	ctx := context.Background()
+	var out bytes.Buffer
+	require.NoError(t, Run(ctx, path, CommandRotateCredentials, nil, &out))
+	assert.Equal(t, "Expired 2 cached credentials\n", out.String())
+
+	out.Reset()
+	require.NoError(t, Run(ctx, path, CommandSetLogLevel, []string{"DEBUG"}, &out))
+	assert.Equal(t, "Set the log level to DEBUG\n", out.String())
+
+	assert.EqualError(t, Run(ctx, path, CommandSetLogLevel, nil, &out), "set-log-level requires the log level")
+	assert.EqualError(t, Run(ctx, path, "restart", nil, &out), `unknown admin command "restart"`)
+	// the agent is not running
+	assert.Error(t, Run(ctx, filepath.Join(t.TempDir(), "missing.sock"), CommandRescanLogs, nil, &out))
+}
+
+func TestHealthRegistry(t *testing.T) {
+	registry := &healthRegistry{health: map[string]ComponentHealth{}}
+	registry.record(nil, component.NewStatusEvent(component.StatusOK))
+	assert.Empty(t, registry.components())
+
+	otlp := component.MustNewID("otlp")
+	registry.record(&component.InstanceID{ID: otlp, Kind: component.KindReceiver}, component.NewStatusEvent(component.StatusStarting))
+	registry.record(&component.InstanceID{ID: otlp, Kind: component.KindExporter}, component.NewRecoverableErrorEvent(errors.New("connection refused")))
+	registry.record(&component.InstanceID{ID: otlp, Kind: component.KindReceiver}, component.NewStatusEvent(component.StatusOK))
+
+	components := registry.components()
+	require.Len(t, components, 2)
+	assert.Equal(t, "Exporter", components[0].Kind)
+	assert.Equal(t, "StatusRecoverableError", components[0].Status)
+	assert.Equal(t, "connection refused", components[0].Error)
+	assert.Equal(t, "Receiver", components[1].Kind)
+	assert.Equal(t, "StatusOK", components[1].Status)
+}
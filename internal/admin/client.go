@@ -0,0 +1,154 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// The commands of the admin API client.
+const (
+	CommandDumpConfig        = "dump-config"
+	CommandListFeatureGates  = "list-feature-gates"
+	CommandListPipelines     = "list-pipelines"
+	CommandRescanLogs        = "rescan-logs"
+	CommandRotateCredentials = "rotate-credentials"
+	CommandSetLogLevel       = "set-log-level"
+)
+
+// Client calls the admin API of the running agent.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient connects to the admin API on the unix socket at path.
+func NewClient(path string) (*Client, error) {
+	conn, err := grpc.NewClient("unix:"+path, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) invoke(ctx context.Context, method string, in, out any) error {
+	return c.conn.Invoke(ctx, "/"+ServiceName+"/"+method, in, out)
+}
+
+// GetConfig returns the effective OTel configuration as YAML.
+func (c *Client) GetConfig(ctx context.Context) (string, error) {
+	out := &wrapperspb.StringValue{}
+	if err := c.invoke(ctx, methodGetConfig, &emptypb.Empty{}, out); err != nil {
+		return "", err
+	}
+	return out.GetValue(), nil
+}
+
+// ListFeatureGates returns the feature gates and whether they are enabled.
+func (c *Client) ListFeatureGates(ctx context.Context) (map[string]any, error) {
+	out := &structpb.Struct{}
+	if err := c.invoke(ctx, methodListFeatureGates, &emptypb.Empty{}, out); err != nil {
+		return nil, err
+	}
+	return out.AsMap(), nil
+}
+
+// ListPipelines returns the pipelines and the health of the components.
+func (c *Client) ListPipelines(ctx context.Context) (map[string]any, error) {
+	out := &structpb.Struct{}
+	if err := c.invoke(ctx, methodListPipelines, &emptypb.Empty{}, out); err != nil {
+		return nil, err
+	}
+	return out.AsMap(), nil
+}
+
+// RescanLogFiles looks for new log files to collect.
+func (c *Client) RescanLogFiles(ctx context.Context) error {
+	return c.invoke(ctx, methodRescanLogFiles, &emptypb.Empty{}, &emptypb.Empty{})
+}
+
+// RotateCredentials expires the cached AWS credentials and returns the number
+// of credentials expired.
+func (c *Client) RotateCredentials(ctx context.Context) (int64, error) {
+	out := &wrapperspb.Int64Value{}
+	if err := c.invoke(ctx, methodRotateCredentials, &emptypb.Empty{}, out); err != nil {
+		return 0, err
+	}
+	return out.GetValue(), nil
+}
+
+// SetLogLevel sets the level of the agent logs.
+func (c *Client) SetLogLevel(ctx context.Context, level string) error {
+	return c.invoke(ctx, methodSetLogLevel, wrapperspb.String(level), &emptypb.Empty{})
+}
+
+// Run calls the admin API for the command and writes the result to out.
+func Run(ctx context.Context, path string, command string, args []string, out io.Writer) error {
+	client, err := NewClient(path)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	switch command {
+	case CommandDumpConfig:
+		config, err := client.GetConfig(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(out, config)
+		return err
+	case CommandListFeatureGates:
+		gates, err := client.ListFeatureGates(ctx)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(gates)
+	case CommandListPipelines:
+		pipelines, err := client.ListPipelines(ctx)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(pipelines)
+	case CommandRescanLogs:
+		if err = client.RescanLogFiles(ctx); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, "Requested a scan of the log files")
+		return err
+	case CommandRotateCredentials:
+		expired, err := client.RotateCredentials(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "Expired %d cached credentials\n", expired)
+		return err
+	case CommandSetLogLevel:
+		if len(args) != 1 {
+			return fmt.Errorf("%s requires the log level", command)
+		}
+		if err = client.SetLogLevel(ctx, args[0]); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "Set the log level to %s\n", args[0])
+		return err
+	default:
+		return fmt.Errorf("unknown admin command %q", command)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// ComponentHealth is the last status reported by an OTel component.
+type ComponentHealth struct {
+	ID        string
+	Kind      string
+	Pipelines []string
+	Status    string
+	Error     string
+	Timestamp time.Time
+}
+
+type healthRegistry struct {
+	mu     sync.RWMutex
+	health map[string]ComponentHealth
+}
+
+// health stores the status of the components of the running OTel collector.
+var health = &healthRegistry{health: map[string]ComponentHealth{}}
+
+// RecordStatus stores the status of the component. It is called by the
+// extensions watching the status of the components.
+func RecordStatus(source *component.InstanceID, event *component.StatusEvent) {
+	health.record(source, event)
+}
+
+func (r *healthRegistry) record(source *component.InstanceID, event *component.StatusEvent) {
+	if source == nil || event == nil {
+		return
+	}
+	pipelines := make([]string, 0, len(source.PipelineIDs))
+	for id := range source.PipelineIDs {
+		pipelines = append(pipelines, id.String())
+	}
+	sort.Strings(pipelines)
+	h := ComponentHealth{
+		ID:        source.ID.String(),
+		Kind:      source.Kind.String(),
+		Pipelines: pipelines,
+		Status:    event.Status().String(),
+		Timestamp: event.Timestamp(),
+	}
+	if err := event.Err(); err != nil {
+		h.Error = err.Error()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// the same name can be used by components of different kinds
+	r.health[h.Kind+"/"+h.ID] = h
+}
+
+// components returns the health of the components ordered by kind and ID.
+func (r *healthRegistry) components() []ComponentHealth {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	components := make([]ComponentHealth, 0, len(r.health))
+	for _, h := range r.health {
+		components = append(components, h)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].Kind != components[j].Kind {
+			return components[i].Kind < components[j].Kind
+		}
+		return components[i].ID < components[j].ID
+	})
+	return components
+}
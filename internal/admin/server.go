@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+
+	"go.opentelemetry.io/collector/featuregate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// gates are the feature gates listed by the admin API.
+var gates = featuregate.GlobalRegistry()
+
+// Server serves the admin API on a unix socket.
+type Server struct {
+	path       string
+	listener   net.Listener
+	grpcServer *grpc.Server
+}
+
+// NewServer listens on the unix socket at path. A stale socket file left
+// behind by a previous process is replaced.
+func NewServer(path string, handler Handler) (*Server, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	// the admin API controls the agent, so restrict it to the agent's user
+	if err = os.Chmod(path, 0600); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	grpcServer := grpc.NewServer()
+	grpcServer.RegisterService(&serviceDesc, &service{handler: handler, health: health, gates: gates})
+	return &Server{path: path, listener: listener, grpcServer: grpcServer}, nil
+}
+
+// Start serves the admin API until the server is stopped.
+func (s *Server) Start() {
+	go func() {
+		if err := s.grpcServer.Serve(s.listener); err != nil {
+			log.Printf("E! [admin] Stopped serving the admin API on %s: %v", s.path, err)
+		}
+	}()
+}
+
+// Stop closes the socket, waiting for the pending requests.
+func (s *Server) Stop() {
+	s.grpcServer.GracefulStop()
+}
+
+func invalidArgument(err error) error {
+	return status.Error(codes.InvalidArgument, err.Error())
+}
@@ -0,0 +1,70 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package atomicfile replaces files so that a crash never leaves them
+// partially written, e.g. the state files or the configuration files written
+// by the agent.
+package atomicfile
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteFile replaces the file with the data, with the permissions of perm.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	return Write(path, bytes.NewReader(data), perm)
+}
+
+// Write replaces the file with the content read from r. The content is synced
+// to a temporary file in the same directory before it is renamed over the
+// path, so the file has either the previous or the new content after a crash.
+// The temporary file is removed if the content cannot be written.
+func Write(path string, r io.Reader, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), tempPrefix(path)+"*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err = tmp.Chmod(perm); err == nil {
+		if _, err = io.Copy(tmp, r); err == nil {
+			err = tmp.Sync()
+		}
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	syncDir(filepath.Dir(path))
+	return nil
+}
+
+// IsTempFile returns true if the file is a temporary file of the path, e.g.
+// left behind by a crash.
+func IsTempFile(file, path string) bool {
+	return filepath.Dir(file) == filepath.Dir(path) &&
+		strings.HasPrefix(filepath.Base(file), tempPrefix(path)) && strings.HasSuffix(file, ".tmp")
+}
+
+func tempPrefix(path string) string {
+	return "." + filepath.Base(path) + "."
+}
+
+// syncDir persists the rename. It is best effort since the directories cannot
+// be synced on every platform.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
@@ -0,0 +1,54 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package atomicfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+
+	require.NoError(t, WriteFile(path, []byte("first"), 0600))
+	require.NoError(t, WriteFile(path, []byte("second"), 0600))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	// the previous content is kept and the temporary file removed on failure
+	assert.Error(t, Write(path, failingReader{}, 0600))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestIsTempFile(t *testing.T) {
+	path := filepath.Join("state", "file_state")
+	assert.True(t, IsTempFile(filepath.Join("state", ".file_state.12345.tmp"), path))
+	assert.False(t, IsTempFile(path, path))
+	assert.False(t, IsTempFile(filepath.Join("state", ".file_state.12345"), path))
+	assert.False(t, IsTempFile(filepath.Join("other", ".file_state.12345.tmp"), path))
+}
@@ -0,0 +1,140 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package backpressure
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+const mib = 1024 * 1024
+
+// unlimitedCgroupMemory is the lowest value reported as the memory limit of a
+// cgroup v1 without a limit, the largest multiple of the page size.
+const unlimitedCgroupMemory = 1 << 62
+
+// cgroupMemoryLimitFiles are the memory limits of the cgroup of the agent, e.g.
+// when running in a container, with cgroup v2 then v1.
+var cgroupMemoryLimitFiles = []string{
+	"/sys/fs/cgroup/memory.max",
+	"/sys/fs/cgroup/memory/memory.limit_in_bytes",
+}
+
+// MemoryGate pauses the readers while the memory used by the agent is above a
+// limit, so that the data is left at the source instead of being buffered or
+// dropped. The gate is shared by the readers, and the memory is checked at most
+// once per interval.
+type MemoryGate struct {
+	limit        uint64
+	interval     time.Duration
+	readMemStats func(*runtime.MemStats)
+	gc           func()
+
+	mu      sync.Mutex
+	checked time.Time
+	above   bool
+}
+
+// NewMemoryGate creates a gate closing while the memory used by the agent is
+// above the percentage of the memory limit of its cgroup, or of the total
+// memory of the host if the cgroup has no limit.
+func NewMemoryGate(percentage int, interval time.Duration) (*MemoryGate, error) {
+	total, err := totalMemory(cgroupMemoryLimitFiles)
+	if err != nil {
+		return nil, err
+	}
+	return newMemoryGate(total*uint64(percentage)/100, interval), nil
+}
+
+func totalMemory(limitFiles []string) (uint64, error) {
+	if limit, ok := cgroupMemoryLimit(limitFiles); ok {
+		return limit, nil
+	}
+	vm, err := mem.VirtualMemory()
+	if err != nil {
+		return 0, fmt.Errorf("unable to get the total memory: %w", err)
+	}
+	return vm.Total, nil
+}
+
+// cgroupMemoryLimit returns the first limit set in the files. Returns false if
+// none of the files exists or sets a limit, e.g. "max" with cgroup v2.
+func cgroupMemoryLimit(limitFiles []string) (uint64, bool) {
+	for _, file := range limitFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		limit, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+		if err != nil || limit == 0 || limit >= unlimitedCgroupMemory {
+			continue
+		}
+		return limit, true
+	}
+	return 0, false
+}
+
+func newMemoryGate(limit uint64, interval time.Duration) *MemoryGate {
+	return &MemoryGate{
+		limit:        limit,
+		interval:     interval,
+		readMemStats: runtime.ReadMemStats,
+		gc:           runtime.GC,
+	}
+}
+
+// Wait blocks while the memory used is above the limit. Returns false if done
+// is closed while waiting.
+func (g *MemoryGate) Wait(done <-chan struct{}) bool {
+	if !g.check() {
+		return true
+	}
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !g.check() {
+				return true
+			}
+		case <-done:
+			return false
+		}
+	}
+}
+
+// check returns whether the memory used is above the limit. The garbage is
+// collected before resuming, the same as the memory limiter of the pipelines,
+// since the readers barely allocate while they are paused.
+func (g *MemoryGate) check() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := time.Now()
+	if now.Sub(g.checked) < g.interval {
+		return g.above
+	}
+	g.checked = now
+	var ms runtime.MemStats
+	g.readMemStats(&ms)
+	if ms.Alloc >= g.limit {
+		g.gc()
+		g.readMemStats(&ms)
+	}
+	above := ms.Alloc >= g.limit
+	if above && !g.above {
+		log.Printf("W! [backpressure] Memory usage of %d MiB is above the limit of %d MiB, pausing the reads", ms.Alloc/mib, g.limit/mib)
+	} else if !above && g.above {
+		log.Printf("I! [backpressure] Memory usage of %d MiB is below the limit of %d MiB, resuming the reads", ms.Alloc/mib, g.limit/mib)
+	}
+	g.above = above
+	return above
+}
@@ -0,0 +1,119 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package backpressure
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMemory struct {
+	alloc atomic.Uint64
+	gcs   atomic.Int32
+}
+
+func (f *fakeMemory) gate(limit uint64, interval time.Duration) *MemoryGate {
+	g := newMemoryGate(limit, interval)
+	g.readMemStats = func(ms *runtime.MemStats) {
+		ms.Alloc = f.alloc.Load()
+	}
+	g.gc = func() {
+		f.gcs.Add(1)
+	}
+	return g
+}
+
+func TestMemoryGate(t *testing.T) {
+	var memory fakeMemory
+	g := memory.gate(100*mib, time.Millisecond)
+
+	memory.alloc.Store(50 * mib)
+	assert.True(t, g.Wait(nil))
+	assert.EqualValues(t, 0, memory.gcs.Load())
+
+	memory.alloc.Store(150 * mib)
+	// check the memory on the next wait
+	g.checked = time.Time{}
+	resumed := make(chan bool)
+	go func() {
+		resumed <- g.Wait(nil)
+	}()
+	select {
+	case <-resumed:
+		assert.Fail(t, "resumed above the limit")
+	case <-time.After(20 * time.Millisecond):
+	}
+	assert.Greater(t, memory.gcs.Load(), int32(0))
+
+	memory.alloc.Store(80 * mib)
+	select {
+	case ok := <-resumed:
+		assert.True(t, ok)
+	case <-time.After(time.Second):
+		assert.Fail(t, "not resumed below the limit")
+	}
+}
+
+func TestMemoryGateDone(t *testing.T) {
+	var memory fakeMemory
+	memory.alloc.Store(150 * mib)
+	g := memory.gate(100*mib, time.Millisecond)
+	done := make(chan struct{})
+	close(done)
+	assert.False(t, g.Wait(done))
+}
+
+func TestMemoryGateInterval(t *testing.T) {
+	var memory fakeMemory
+	g := memory.gate(100*mib, time.Hour)
+	memory.alloc.Store(50 * mib)
+	assert.False(t, g.check())
+	// the memory is not checked again until the interval has passed
+	memory.alloc.Store(150 * mib)
+	assert.False(t, g.check())
+	assert.EqualValues(t, 0, memory.gcs.Load())
+}
+
+func TestNewMemoryGate(t *testing.T) {
+	g, err := NewMemoryGate(50, time.Second)
+	require.NoError(t, err)
+	assert.Greater(t, g.limit, uint64(0))
+	assert.Equal(t, time.Second, g.interval)
+}
+
+func TestTotalMemory(t *testing.T) {
+	dir := t.TempDir()
+	v2 := filepath.Join(dir, "memory.max")
+	v1 := filepath.Join(dir, "memory.limit_in_bytes")
+	files := []string{v2, v1}
+
+	require.NoError(t, os.WriteFile(v2, []byte("536870912\n"), 0644))
+	total, err := totalMemory(files)
+	require.NoError(t, err)
+	assert.EqualValues(t, 512*mib, total)
+
+	// without a limit, cgroup v2 reports max and cgroup v1 the largest page aligned value
+	require.NoError(t, os.WriteFile(v2, []byte("max\n"), 0644))
+	require.NoError(t, os.WriteFile(v1, []byte("9223372036854771712\n"), 0644))
+	_, ok := cgroupMemoryLimit(files)
+	assert.False(t, ok)
+	vm, err := mem.VirtualMemory()
+	require.NoError(t, err)
+	total, err = totalMemory(files)
+	require.NoError(t, err)
+	assert.Equal(t, vm.Total, total)
+
+	require.NoError(t, os.WriteFile(v1, []byte("1073741824\n"), 0644))
+	total, err = totalMemory(files)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1024*mib, total)
+}
@@ -0,0 +1,148 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package clockskew measures how far the clock of the host is from the clock
+// of the AWS endpoints, using the Date header of their responses. CloudWatch
+// Logs rejects the events more than 2 hours in the future, so a host with a
+// skewed clock loses its logs unless their timestamps are corrected.
+package clockskew
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+const (
+	// DefaultThreshold is the skew above which a warning is logged and, if
+	// enabled, the timestamps are corrected.
+	DefaultThreshold = time.Minute
+
+	// dateResolution is the precision of the Date header, which is truncated
+	// to the second.
+	dateResolution = time.Second
+)
+
+// Detector keeps the last skew measured on the responses of the clients it
+// is configured on.
+type Detector struct {
+	// name prefixes the logs and is the component of the metric, e.g.
+	// cloudwatchlogs.
+	name      string
+	threshold time.Duration
+	correct   bool
+
+	mu   sync.Mutex
+	skew time.Duration
+	// exceeded is set while the skew is above the threshold, so that the
+	// warning is only logged when it is crossed.
+	exceeded bool
+
+	unregister func()
+	now        func() time.Time
+}
+
+// New creates a detector. The timestamps are only adjusted if correct is set.
+// The absolute skew is reported by the clock_skew_seconds metric of the
+// component until Stop is called.
+func New(name string, threshold time.Duration, correct bool) *Detector {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	d := &Detector{
+		name:      name,
+		threshold: threshold,
+		correct:   correct,
+		now:       time.Now,
+	}
+	d.unregister = selftelemetry.RegisterGauge(selftelemetry.MetricClockSkew, name, func() float64 {
+		return math.Abs(d.Skew().Seconds())
+	})
+	return d
+}
+
+// Configure measures the skew on the responses of the client.
+func (d *Detector) Configure(handlers *request.Handlers) {
+	handlers.CompleteAttempt.PushBackNamed(request.NamedHandler{Name: "ClockSkewHandler", Fn: d.observe})
+}
+
+// Stop unregisters the metric.
+func (d *Detector) Stop() {
+	if d != nil && d.unregister != nil {
+		d.unregister()
+	}
+}
+
+// Skew is the offset to add to the local time to get the time of the
+// endpoints. It is positive when the local clock is behind.
+func (d *Detector) Skew() time.Duration {
+	if d == nil {
+		return 0
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.skew
+}
+
+// Adjust corrects the timestamp by the skew if the correction is enabled and
+// the skew is above the threshold. Smaller skews are left alone, since the
+// measurement is only precise to the second.
+func (d *Detector) Adjust(t time.Time) time.Time {
+	if d == nil || !d.correct || t.IsZero() {
+		return t
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.exceeded {
+		return t
+	}
+	return t.Add(d.skew)
+}
+
+func (d *Detector) observe(r *request.Request) {
+	if r.HTTPResponse == nil {
+		return
+	}
+	date, err := http.ParseTime(r.HTTPResponse.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+	received := d.now()
+	// the endpoint stamped the response somewhere between the start of the
+	// attempt and now
+	local := received
+	if !r.AttemptTime.IsZero() && r.AttemptTime.Before(received) {
+		local = r.AttemptTime.Add(received.Sub(r.AttemptTime) / 2)
+	}
+	d.update(date.Add(dateResolution / 2).Sub(local))
+}
+
+func (d *Detector) update(skew time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.skew = skew
+	exceeded := skew.Abs() >= d.threshold
+	if exceeded == d.exceeded {
+		return
+	}
+	d.exceeded = exceeded
+	if !exceeded {
+		log.Printf("I! %s: the clock of the host is back within %v of the clock of the endpoint", d.name, d.threshold)
+		return
+	}
+	direction := "behind"
+	if skew < 0 {
+		direction = "ahead of"
+	}
+	if d.correct {
+		log.Printf("W! %s: the clock of the host is %v %s the clock of the endpoint, the timestamps of the log events are corrected", d.name, skew.Abs().Round(time.Second), direction)
+	} else {
+		log.Printf("W! %s: the clock of the host is %v %s the clock of the endpoint, the log events may be rejected. Synchronize the clock with NTP or enable correct_timestamps", d.name, skew.Abs().Round(time.Second), direction)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package clockskew
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+func newResponse(date string, attemptTime time.Time) *request.Request {
+	return &request.Request{
+		AttemptTime:  attemptTime,
+		HTTPResponse: &http.Response{Header: http.Header{"Date": []string{date}}},
+	}
+}
+
+func TestDetector(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	d := New("test", 0, true)
+	t.Cleanup(d.Stop)
+	d.now = func() time.Time { return now }
+	assert.Equal(t, DefaultThreshold, d.threshold)
+
+	// the endpoint is 10 seconds ahead, below the threshold
+	d.observe(newResponse(now.Add(10*time.Second).Format(http.TimeFormat), now.Add(-200*time.Millisecond)))
+	assert.Equal(t, 10*time.Second+600*time.Millisecond, d.Skew())
+	assert.Equal(t, now, d.Adjust(now))
+	assert.Empty(t, buf.String())
+
+	// the local clock is 3 hours ahead
+	d.observe(newResponse(now.Add(-3*time.Hour).Format(http.TimeFormat), now))
+	assert.Equal(t, -3*time.Hour+500*time.Millisecond, d.Skew())
+	assert.Equal(t, now.Add(-3*time.Hour+500*time.Millisecond), d.Adjust(now))
+	assert.Equal(t, time.Time{}, d.Adjust(time.Time{}))
+	assert.Contains(t, buf.String(), "W! test: the clock of the host is 3h0m0s ahead of the clock of the endpoint, the timestamps of the log events are corrected")
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("W!")))
+
+	// still skewed, not logged again
+	d.observe(newResponse(now.Add(-3*time.Hour).Format(http.TimeFormat), now))
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("W!")))
+
+	var skew float64
+	for _, sample := range selftelemetry.Default.Collect() {
+		if sample.Name == selftelemetry.MetricClockSkew && sample.Component == "test" {
+			skew = sample.Value
+		}
+	}
+	assert.InDelta(t, 3*time.Hour.Seconds(), skew, 1)
+
+	// synchronized again
+	d.observe(newResponse(now.Format(http.TimeFormat), now))
+	assert.Contains(t, buf.String(), "I! test: the clock of the host is back within 1m0s of the clock of the endpoint")
+	assert.Equal(t, now, d.Adjust(now))
+}
+
+func TestDetectorWithoutCorrection(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	now := time.Now()
+	d := New("test", 5*time.Minute, false)
+	t.Cleanup(d.Stop)
+	d.now = func() time.Time { return now }
+
+	d.observe(newResponse(now.Add(10*time.Minute).Format(http.TimeFormat), time.Time{}))
+	assert.Greater(t, d.Skew(), 9*time.Minute)
+	assert.Equal(t, now, d.Adjust(now))
+	assert.Contains(t, buf.String(), "behind the clock of the endpoint, the log events may be rejected")
+}
+
+func TestDetectorIgnoresResponsesWithoutDate(t *testing.T) {
+	d := New("test", 0, true)
+	t.Cleanup(d.Stop)
+	d.observe(&request.Request{})
+	d.observe(newResponse("", time.Time{}))
+	d.observe(newResponse("yesterday", time.Time{}))
+	assert.Zero(t, d.Skew())
+}
+
+func TestDetectorConfigure(t *testing.T) {
+	d := New("test", 0, true)
+	t.Cleanup(d.Stop)
+	var handlers request.Handlers
+	d.Configure(&handlers)
+	require.Equal(t, 1, handlers.CompleteAttempt.Len())
+}
+
+func TestNilDetector(t *testing.T) {
+	var d *Detector
+	now := time.Now()
+	assert.Equal(t, now, d.Adjust(now))
+	assert.Zero(t, d.Skew())
+	d.Stop()
+}
@@ -0,0 +1,117 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	Gzip = "gzip"
+	Zstd = "zstd"
+)
+
+// Codec compresses request bodies for a Content-Encoding.
+type Codec interface {
+	// Name is the Content-Encoding of the compressed body.
+	Name() string
+	Compress(src []byte) ([]byte, error)
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{}
+)
+
+// Register makes a codec available by its name. Registering a codec with the
+// same name replaces it.
+func Register(c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[c.Name()] = c
+}
+
+// Get returns the registered codec with the name.
+func Get(name string) (Codec, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	c, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compression codec %q, supported codecs are %v", name, supported())
+	}
+	return c, nil
+}
+
+func supported() []string {
+	names := make([]string, 0, len(codecs))
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type gzipCodec struct {
+	pool sync.Pool
+}
+
+func newGzipCodec() *gzipCodec {
+	return &gzipCodec{pool: sync.Pool{
+		New: func() interface{} {
+			return gzip.NewWriter(io.Discard)
+		},
+	}}
+}
+
+func (*gzipCodec) Name() string {
+	return Gzip
+}
+
+func (c *gzipCodec) Compress(src []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := c.pool.Get().(*gzip.Writer)
+	defer c.pool.Put(w)
+	w.Reset(buf)
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type zstdCodec struct {
+	// the encoder is safe for concurrent use with EncodeAll
+	encoder *zstd.Encoder
+}
+
+func newZstdCodec() *zstdCodec {
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		// only returned for invalid options
+		panic(err)
+	}
+	return &zstdCodec{encoder: encoder}
+}
+
+func (*zstdCodec) Name() string {
+	return Zstd
+}
+
+func (c *zstdCodec) Compress(src []byte) ([]byte, error) {
+	return c.encoder.EncodeAll(src, make([]byte, 0, len(src)/2)), nil
+}
+
+func init() {
+	Register(newGzipCodec())
+	Register(newZstdCodec())
+}
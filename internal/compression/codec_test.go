@@ -0,0 +1,79 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decompress(t *testing.T, encoding string, data []byte) []byte {
+	t.Helper()
+	var r io.Reader
+	switch encoding {
+	case Gzip:
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		r = gr
+	case Zstd:
+		zr, err := zstd.NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		defer zr.Close()
+		r = zr
+	default:
+		return data
+	}
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return out
+}
+
+func TestCodecs(t *testing.T) {
+	payload := bytes.Repeat([]byte(`{"resourceMetrics":[{"scopeMetrics":[]}]}`), 100)
+	for _, name := range []string{Gzip, Zstd} {
+		t.Run(name, func(t *testing.T) {
+			c, err := Get(name)
+			require.NoError(t, err)
+			assert.Equal(t, name, c.Name())
+			compressed, err := c.Compress(payload)
+			require.NoError(t, err)
+			assert.Less(t, len(compressed), len(payload))
+			assert.Equal(t, payload, decompress(t, name, compressed))
+			// the writers are reused
+			compressed, err = c.Compress(payload[:10])
+			require.NoError(t, err)
+			assert.Equal(t, payload[:10], decompress(t, name, compressed))
+		})
+	}
+}
+
+type identityCodec struct{}
+
+func (identityCodec) Name() string {
+	return "identity"
+}
+
+func (identityCodec) Compress(src []byte) ([]byte, error) {
+	return src, nil
+}
+
+func TestRegister(t *testing.T) {
+	_, err := Get("identity")
+	assert.ErrorContains(t, err, "supported codecs are [gzip zstd]")
+	Register(identityCodec{})
+	t.Cleanup(func() {
+		codecsMu.Lock()
+		delete(codecs, "identity")
+		codecsMu.Unlock()
+	})
+	c, err := Get("identity")
+	require.NoError(t, err)
+	assert.Equal(t, identityCodec{}, c)
+}
@@ -0,0 +1,126 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package compression
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/profiler"
+)
+
+// DefaultRetryInterval is how long the fallback codec is used before the
+// preferred codec is tried again, e.g. after the server is upgraded.
+const DefaultRetryInterval = time.Hour
+
+// Stats of the requests compressed for a destination.
+type Stats struct {
+	// Codec is the codec currently negotiated with the server.
+	Codec             string
+	Requests          int64
+	Fallbacks         int64
+	UncompressedBytes int64
+	CompressedBytes   int64
+}
+
+// Ratio returns the compressed size relative to the uncompressed size.
+func (s Stats) Ratio() float64 {
+	if s.UncompressedBytes == 0 {
+		return 0
+	}
+	return float64(s.CompressedBytes) / float64(s.UncompressedBytes)
+}
+
+// Negotiator picks the codec of the requests to a destination. Once the
+// server rejects the preferred codec, the fallback codec is used until the
+// preferred codec is retried after the retry interval.
+type Negotiator struct {
+	name          string
+	preferred     Codec
+	fallback      Codec
+	retryInterval time.Duration
+
+	mutex         sync.Mutex
+	fallbackUntil time.Time
+	stats         Stats
+}
+
+// NewNegotiator creates a Negotiator for the destination with the name, which
+// is used for the stats. Gzip is used as the fallback codec.
+func NewNegotiator(name, codec string) (*Negotiator, error) {
+	preferred, err := Get(codec)
+	if err != nil {
+		return nil, err
+	}
+	fallback, err := Get(Gzip)
+	if err != nil {
+		return nil, err
+	}
+	return &Negotiator{
+		name:          name,
+		preferred:     preferred,
+		fallback:      fallback,
+		retryInterval: DefaultRetryInterval,
+		stats:         Stats{Codec: preferred.Name()},
+	}, nil
+}
+
+// Codec returns the codec to use for the next request.
+func (n *Negotiator) Codec() Codec {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.fallbackUntil.IsZero() {
+		return n.preferred
+	}
+	if time.Now().Before(n.fallbackUntil) {
+		return n.fallback
+	}
+	n.fallbackUntil = time.Time{}
+	n.stats.Codec = n.preferred.Name()
+	log.Printf("D! [compression] Retrying %s compression for %s", n.preferred.Name(), n.name)
+	return n.preferred
+}
+
+// Reject switches to the fallback codec after the server rejected the codec.
+// Returns false if the codec is the fallback codec, which there is nothing to
+// fall back from.
+func (n *Negotiator) Reject(codec Codec) bool {
+	if codec == n.fallback {
+		return false
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.fallbackUntil.IsZero() {
+		log.Printf("W! [compression] %s rejected %s compression, falling back to %s for %v", n.name, codec.Name(), n.fallback.Name(), n.retryInterval)
+	}
+	n.fallbackUntil = time.Now().Add(n.retryInterval)
+	n.stats.Codec = n.fallback.Name()
+	n.stats.Fallbacks++
+	profiler.Profiler.AddStats([]string{"compression", n.name, codec.Name(), "rejected"}, 1)
+	return true
+}
+
+// Compress compresses the body with the codec and records the sizes.
+func (n *Negotiator) Compress(codec Codec, body []byte) ([]byte, error) {
+	compressed, err := codec.Compress(body)
+	if err != nil {
+		return nil, err
+	}
+	n.mutex.Lock()
+	n.stats.Requests++
+	n.stats.UncompressedBytes += int64(len(body))
+	n.stats.CompressedBytes += int64(len(compressed))
+	n.mutex.Unlock()
+	profiler.Profiler.AddStats([]string{"compression", n.name, codec.Name(), "uncompressed_bytes"}, float64(len(body)))
+	profiler.Profiler.AddStats([]string{"compression", n.name, codec.Name(), "compressed_bytes"}, float64(len(compressed)))
+	return compressed, nil
+}
+
+// Stats returns the stats since the Negotiator was created.
+func (n *Negotiator) Stats() Stats {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.stats
+}
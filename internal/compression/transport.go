@@ -0,0 +1,80 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package compression
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+const (
+	headerContentEncoding = "Content-Encoding"
+)
+
+// Transport compresses the request bodies with the preferred codec. If the
+// server rejects the encoding with 415 Unsupported Media Type, the request is
+// sent again with the fallback codec, which is then used for the destination
+// until the preferred codec is retried after the retry interval.
+type Transport struct {
+	base       http.RoundTripper
+	negotiator *Negotiator
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// NewTransport creates a Transport for the destination with the name, which is
+// used for the stats. Gzip is used as the fallback codec.
+func NewTransport(base http.RoundTripper, name, codec string) (*Transport, error) {
+	negotiator, err := NewNegotiator(name, codec)
+	if err != nil {
+		return nil, err
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base, negotiator: negotiator}, nil
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// bodies that are already encoded are sent as is
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get(headerContentEncoding) != "" {
+		return t.base.RoundTrip(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	codec := t.negotiator.Codec()
+	resp, err := t.send(req, body, codec)
+	if err != nil || resp.StatusCode != http.StatusUnsupportedMediaType || !t.negotiator.Reject(codec) {
+		return resp, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return t.send(req, body, t.negotiator.Codec())
+}
+
+func (t *Transport) send(req *http.Request, body []byte, codec Codec) (*http.Response, error) {
+	compressed, err := t.negotiator.Compress(codec, body)
+	if err != nil {
+		return nil, err
+	}
+	r := req.Clone(req.Context())
+	r.Body = io.NopCloser(bytes.NewReader(compressed))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	r.ContentLength = int64(len(compressed))
+	r.Header.Set(headerContentEncoding, codec.Name())
+	r.Header.Set("Content-Length", strconv.Itoa(len(compressed)))
+	return t.base.RoundTrip(r)
+}
+
+// Stats returns the stats since the Transport was created.
+func (t *Transport) Stats() Stats {
+	return t.negotiator.Stats()
+}
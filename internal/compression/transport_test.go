@@ -0,0 +1,145 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package compression
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// server records the received encodings and rejects the ones not accepted.
+type server struct {
+	mutex     sync.Mutex
+	accepted  map[string]bool
+	encodings []string
+	bodies    []string
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	encoding := r.Header.Get("Content-Encoding")
+	s.encodings = append(s.encodings, encoding)
+	if !s.accepted[encoding] {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+	data, _ := io.ReadAll(r.Body)
+	s.bodies = append(s.bodies, string(data))
+	w.WriteHeader(http.StatusOK)
+}
+
+func post(t *testing.T, client *http.Client, url, body string) int {
+	t.Helper()
+	resp, err := client.Post(url, "application/x-protobuf", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestTransport(t *testing.T) {
+	s := &server{accepted: map[string]bool{Gzip: true, Zstd: true}}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	transport, err := NewTransport(nil, "otlp/test", Zstd)
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+	body := strings.Repeat("metric", 100)
+	assert.Equal(t, http.StatusOK, post(t, client, ts.URL, body))
+	assert.Equal(t, []string{Zstd}, s.encodings)
+	assert.Equal(t, body, string(decompress(t, Zstd, []byte(s.bodies[0]))))
+
+	stats := transport.Stats()
+	assert.Equal(t, Zstd, stats.Codec)
+	assert.EqualValues(t, 1, stats.Requests)
+	assert.EqualValues(t, len(body), stats.UncompressedBytes)
+	assert.EqualValues(t, len(s.bodies[0]), stats.CompressedBytes)
+	assert.Less(t, stats.Ratio(), 1.0)
+}
+
+func TestTransportFallback(t *testing.T) {
+	s := &server{accepted: map[string]bool{Gzip: true}}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	transport, err := NewTransport(nil, "otlp/test", Zstd)
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+	body := strings.Repeat("metric", 100)
+
+	// Given a server rejecting zstd
+	// Then the request is sent again with gzip
+	assert.Equal(t, http.StatusOK, post(t, client, ts.URL, body))
+	assert.Equal(t, []string{Zstd, Gzip}, s.encodings)
+	assert.Equal(t, body, string(decompress(t, Gzip, []byte(s.bodies[0]))))
+	// And gzip is used for the next requests
+	assert.Equal(t, http.StatusOK, post(t, client, ts.URL, body))
+	assert.Equal(t, []string{Zstd, Gzip, Gzip}, s.encodings)
+	stats := transport.Stats()
+	assert.Equal(t, Gzip, stats.Codec)
+	assert.EqualValues(t, 1, stats.Fallbacks)
+	assert.EqualValues(t, 3, stats.Requests)
+
+	// Given the retry interval has elapsed and the server accepts zstd
+	transport.negotiator.mutex.Lock()
+	transport.negotiator.fallbackUntil = time.Now().Add(-time.Second)
+	transport.negotiator.mutex.Unlock()
+	s.mutex.Lock()
+	s.accepted[Zstd] = true
+	s.mutex.Unlock()
+	// Then zstd is negotiated again
+	assert.Equal(t, http.StatusOK, post(t, client, ts.URL, body))
+	assert.Equal(t, []string{Zstd, Gzip, Gzip, Zstd}, s.encodings)
+	assert.Equal(t, Zstd, transport.Stats().Codec)
+}
+
+func TestTransportRejectsFallback(t *testing.T) {
+	s := &server{accepted: map[string]bool{}}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	transport, err := NewTransport(nil, "otlp/test", Gzip)
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+	// the response is returned if the fallback codec is rejected too
+	assert.Equal(t, http.StatusUnsupportedMediaType, post(t, client, ts.URL, "metric"))
+	assert.Equal(t, []string{Gzip}, s.encodings)
+}
+
+func TestTransportPassThrough(t *testing.T) {
+	s := &server{accepted: map[string]bool{"": true, "deflate": true}}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	transport, err := NewTransport(nil, "otlp/test", Zstd)
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+	resp, err := client.Get(ts.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	req, err := http.NewRequest(http.MethodPost, ts.URL, bytes.NewReader([]byte("encoded")))
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "deflate")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, []string{"", "deflate"}, s.encodings)
+	assert.Equal(t, []string{"", "encoded"}, s.bodies)
+	assert.EqualValues(t, 0, transport.Stats().Requests)
+}
+
+func TestNewTransportUnsupported(t *testing.T) {
+	_, err := NewTransport(nil, "otlp/test", "brotli")
+	assert.Error(t, err)
+}
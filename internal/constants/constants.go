@@ -8,4 +8,7 @@ const (
 	FileSuffixYAML = ".yaml"
 
 	ExitCodeNoJSONFile = 99
+	// ExitCodeWatchdog is the exit code of the agent when the watchdog
+	// restarts it, so that the service manager starts it again.
+	ExitCodeWatchdog = 98
 )
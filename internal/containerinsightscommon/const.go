@@ -17,6 +17,9 @@ const (
 	MetricType              = "Type"
 	SourcesKey              = "Sources"
 	GpuDeviceKey            = "GpuDevice"
+	// GPU and compute instances of the MIG (Multi-Instance GPU) partitions
+	GpuInstanceIdKey        = "GpuInstanceId"
+	GpuComputeInstanceIdKey = "ComputeInstanceId"
 
 	ClusterQueueNameKey     = "ClusterQueue"
 	ClusterQueueStatusKey   = "Status"
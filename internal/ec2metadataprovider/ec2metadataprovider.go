@@ -5,10 +5,13 @@ package ec2metadataprovider
 
 import (
 	"context"
+	"errors"
 	"log"
 	"strings"
+	"sync/atomic"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 
@@ -29,6 +32,11 @@ type MetadataProvider interface {
 type metadataClient struct {
 	metadataFallbackDisabled *ec2metadata.EC2Metadata
 	metadataFallbackEnabled  *ec2metadata.EC2Metadata
+	// metadataIPv6 uses the IPv6 endpoint, which is tried when the IPv4 one
+	// cannot be reached, e.g. on an IPv6-only subnet. Nil if the SDK already
+	// uses the IPv6 endpoint.
+	metadataIPv6 *ec2metadata.EC2Metadata
+	useIPv6      atomic.Bool
 }
 
 var _ MetadataProvider = (*metadataClient)(nil)
@@ -44,10 +52,16 @@ func NewMetadataProvider(p client.ConfigProvider, retries int) MetadataProvider
 		LogLevel: configaws.SDKLogLevel(),
 		Logger:   configaws.SDKLogger{},
 	}
-	return &metadataClient{
+	c := &metadataClient{
 		metadataFallbackDisabled: ec2metadata.New(p, disableFallbackConfig),
 		metadataFallbackEnabled:  ec2metadata.New(p, enableFallbackConfig),
 	}
+	if !configaws.IMDSIPv6Enabled() {
+		ipv6Config := disableFallbackConfig.Copy()
+		ipv6Config.Endpoint = aws.String(configaws.IMDSIPv6Endpoint)
+		c.metadataIPv6 = ec2metadata.New(p, ipv6Config)
+	}
+	return c
 }
 
 func (c *metadataClient) InstanceID(ctx context.Context) (string, error) {
@@ -92,6 +106,9 @@ func (c *metadataClient) Get(ctx context.Context) (ec2metadata.EC2InstanceIdenti
 }
 
 func withMetadataFallbackRetry[T any](ctx context.Context, c *metadataClient, operation func(*ec2metadata.EC2Metadata) (T, error)) (T, error) {
+	if c.useIPv6.Load() {
+		return operation(c.metadataIPv6)
+	}
 	result, err := operation(c.metadataFallbackDisabled)
 	if err != nil {
 		log.Printf("D! could not perform operation without imds v1 fallback enable thus enable fallback")
@@ -100,5 +117,15 @@ func withMetadataFallbackRetry[T any](ctx context.Context, c *metadataClient, op
 			agent.UsageFlags().Set(agent.FlagIMDSFallbackSuccess)
 		}
 	}
+	// the IPv4 endpoint answered if the request failed with a status code
+	var requestFailure awserr.RequestFailure
+	if err != nil && c.metadataIPv6 != nil && ctx.Err() == nil && !errors.As(err, &requestFailure) {
+		log.Printf("D! could not perform operation on the imds IPv4 endpoint thus try the IPv6 endpoint")
+		if ipv6Result, ipv6Err := operation(c.metadataIPv6); ipv6Err == nil {
+			log.Printf("I! Using the IPv6 endpoint %s of imds", configaws.IMDSIPv6Endpoint)
+			c.useIPv6.Store(true)
+			return ipv6Result, nil
+		}
+	}
 	return result, err
 }
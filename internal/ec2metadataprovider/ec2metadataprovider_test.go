@@ -5,14 +5,18 @@ package ec2metadataprovider
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/awstesting/mock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMetadataProvider_Get(t *testing.T) {
@@ -70,3 +74,47 @@ func TestMetadataProvider_available(t *testing.T) {
 		})
 	}
 }
+
+func TestMetadataProvider_IPv6Fallback(t *testing.T) {
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "false")
+	// unreachable
+	ipv4 := httptest.NewServer(http.NotFoundHandler())
+	ipv4.Close()
+	ipv6 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			_, _ = w.Write([]byte("token"))
+			return
+		}
+		_, _ = w.Write([]byte("i-1234567890abcdef0"))
+	}))
+	defer ipv6.Close()
+
+	newClient := func(endpoint string) *ec2metadata.EC2Metadata {
+		return ec2metadata.New(mock.Session, &aws.Config{Endpoint: aws.String(endpoint), MaxRetries: aws.Int(0)})
+	}
+	c := &metadataClient{
+		metadataFallbackDisabled: newClient(ipv4.URL),
+		metadataFallbackEnabled:  newClient(ipv4.URL),
+		metadataIPv6:             newClient(ipv6.URL),
+	}
+	got, err := c.InstanceID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "i-1234567890abcdef0", got)
+	assert.True(t, c.useIPv6.Load())
+
+	got, err = c.InstanceID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "i-1234567890abcdef0", got)
+
+	// the IPv4 endpoint is reachable
+	ipv4 = httptest.NewServer(http.NotFoundHandler())
+	defer ipv4.Close()
+	c = &metadataClient{
+		metadataFallbackDisabled: newClient(ipv4.URL),
+		metadataFallbackEnabled:  newClient(ipv4.URL),
+		metadataIPv6:             newClient(ipv6.URL),
+	}
+	_, err = c.InstanceID(context.Background())
+	assert.Error(t, err)
+	assert.False(t, c.useIPv6.Load())
+}
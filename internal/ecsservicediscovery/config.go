@@ -4,16 +4,23 @@
 package ecsservicediscovery
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
 )
 
 const (
 	AwsSdkLevelRetryCount = 3
 
 	portSeparator = ";"
+
+	tagExpressionPrefix    = "tag:"
+	tagExpressionSeparator = ","
 )
 
 type ServiceNameForTasksConfig struct {
@@ -64,14 +71,7 @@ func (t *TaskDefinitionConfig) init() {
 		t.containerNameRegex = regexp.MustCompile(t.ContainerNamePattern)
 	}
 
-	ports := strings.Split(t.MetricsPorts, portSeparator)
-	for _, v := range ports {
-		if port, err := strconv.Atoi(strings.TrimSpace(v)); err != nil || port < 0 {
-			continue
-		} else {
-			t.metricsPortList = append(t.metricsPortList, port)
-		}
-	}
+	t.metricsPortList = parseMetricsPorts(t.MetricsPorts)
 }
 
 func (s *ServiceNameForTasksConfig) String() string {
@@ -91,22 +91,145 @@ func (s *ServiceNameForTasksConfig) init() {
 		s.containerNameRegex = regexp.MustCompile(s.ContainerNamePattern)
 	}
 
-	ports := strings.Split(s.MetricsPorts, portSeparator)
-	for _, v := range ports {
+	s.metricsPortList = parseMetricsPorts(s.MetricsPorts)
+}
+
+// ServiceConnectNamespaceConfig discovers the tasks of the services in the
+// matching Service Connect namespaces.
+type ServiceConnectNamespaceConfig struct {
+	ContainerNamePattern string `toml:"sd_container_name_pattern"`
+	JobName              string `toml:"sd_job_name"`
+	MetricsPath          string `toml:"sd_metrics_path"`
+	MetricsPorts         string `toml:"sd_metrics_ports"`
+	NamespacePattern     string `toml:"sd_namespace_pattern"`
+
+	containerNameRegex *regexp.Regexp
+	namespaceRegex     *regexp.Regexp
+	metricsPortList    []int
+}
+
+func (s *ServiceConnectNamespaceConfig) String() string {
+	return fmt.Sprintf("ContainerNamePattern: %v\nJobName: %v\nMetricsPath: %v\nMetricsPorts: %v\nNamespacePattern: %v\n",
+		s.ContainerNamePattern,
+		s.JobName,
+		s.MetricsPath,
+		s.MetricsPorts,
+		s.NamespacePattern,
+	)
+}
+
+func (s *ServiceConnectNamespaceConfig) init() {
+	s.namespaceRegex = regexp.MustCompile(s.NamespacePattern)
+
+	if s.ContainerNamePattern != "" {
+		s.containerNameRegex = regexp.MustCompile(s.ContainerNamePattern)
+	}
+
+	s.metricsPortList = parseMetricsPorts(s.MetricsPorts)
+}
+
+// TaskTagConfig discovers the tasks with the tags matching the tag expression,
+// e.g. "tag:prometheus=true". The expression is a comma separated list of
+// tag:key=value terms, or tag:key for any value, which must all match.
+type TaskTagConfig struct {
+	ContainerNamePattern string `toml:"sd_container_name_pattern"`
+	JobName              string `toml:"sd_job_name"`
+	MetricsPath          string `toml:"sd_metrics_path"`
+	MetricsPorts         string `toml:"sd_metrics_ports"`
+	TagExpression        string `toml:"sd_tag_expression"`
+
+	containerNameRegex *regexp.Regexp
+	tagMatchers        []tagMatcher
+	metricsPortList    []int
+}
+
+func (t *TaskTagConfig) String() string {
+	return fmt.Sprintf("ContainerNamePattern: %v\nJobName: %v\nMetricsPath: %v\nMetricsPorts: %v\nTagExpression: %v\n",
+		t.ContainerNamePattern,
+		t.JobName,
+		t.MetricsPath,
+		t.MetricsPorts,
+		t.TagExpression,
+	)
+}
+
+// init expects the tag expression to be valid, which is checked when the
+// config is validated.
+func (t *TaskTagConfig) init() {
+	t.tagMatchers, _ = parseTagExpression(t.TagExpression)
+
+	if t.ContainerNamePattern != "" {
+		t.containerNameRegex = regexp.MustCompile(t.ContainerNamePattern)
+	}
+
+	t.metricsPortList = parseMetricsPorts(t.MetricsPorts)
+}
+
+// matchTags returns true if the tags match all the terms of the expression.
+func (t *TaskTagConfig) matchTags(tags []*ecs.Tag) bool {
+	for _, m := range t.tagMatchers {
+		if !m.match(tags) {
+			return false
+		}
+	}
+	return len(t.tagMatchers) > 0
+}
+
+type tagMatcher struct {
+	key      string
+	value    string
+	anyValue bool
+}
+
+func (m tagMatcher) match(tags []*ecs.Tag) bool {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == m.key && (m.anyValue || aws.StringValue(tag.Value) == m.value) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseTagExpression(expression string) ([]tagMatcher, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, errors.New("empty tag expression")
+	}
+	var matchers []tagMatcher
+	for _, term := range strings.Split(expression, tagExpressionSeparator) {
+		term = strings.TrimSpace(term)
+		if !strings.HasPrefix(term, tagExpressionPrefix) {
+			return nil, fmt.Errorf("tag expression term %q does not start with %s", term, tagExpressionPrefix)
+		}
+		key, value, hasValue := strings.Cut(strings.TrimPrefix(term, tagExpressionPrefix), "=")
+		if key == "" {
+			return nil, fmt.Errorf("tag expression term %q has no tag key", term)
+		}
+		matchers = append(matchers, tagMatcher{key: key, value: value, anyValue: !hasValue})
+	}
+	return matchers, nil
+}
+
+// parseMetricsPorts returns the valid ports of the semicolon separated list.
+func parseMetricsPorts(metricsPorts string) []int {
+	var result []int
+	for _, v := range strings.Split(metricsPorts, portSeparator) {
 		if port, err := strconv.Atoi(strings.TrimSpace(v)); err != nil || port < 0 {
 			continue
 		} else {
-			s.metricsPortList = append(s.metricsPortList, port)
+			result = append(result, port)
 		}
 	}
+	return result
 }
 
 type ServiceDiscoveryConfig struct {
-	Frequency            string                       `toml:"sd_frequency"`
-	ResultFile           string                       `toml:"sd_result_file"`
-	TargetCluster        string                       `toml:"sd_target_cluster"`
-	TargetClusterRegion  string                       `toml:"sd_cluster_region"`
-	ServiceNamesForTasks []*ServiceNameForTasksConfig `toml:"service_name_list_for_tasks"`
-	DockerLabel          *DockerLabelConfig           `toml:"docker_label"`
-	TaskDefinitions      []*TaskDefinitionConfig      `toml:"task_definition_list"`
+	Frequency            string                           `toml:"sd_frequency"`
+	ResultFile           string                           `toml:"sd_result_file"`
+	TargetCluster        string                           `toml:"sd_target_cluster"`
+	TargetClusterRegion  string                           `toml:"sd_cluster_region"`
+	ServiceNamesForTasks []*ServiceNameForTasksConfig     `toml:"service_name_list_for_tasks"`
+	DockerLabel          *DockerLabelConfig               `toml:"docker_label"`
+	TaskDefinitions      []*TaskDefinitionConfig          `toml:"task_definition_list"`
+	ServiceConnect       []*ServiceConnectNamespaceConfig `toml:"service_connect_namespace_list"`
+	TaskTags             []*TaskTagConfig                 `toml:"task_tag_list"`
 }
@@ -7,6 +7,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,3 +33,73 @@ func Test_ServiceNameForTasksConfig_init(t *testing.T) {
 	config.init()
 	assert.True(t, reflect.DeepEqual(config.metricsPortList, []int{11, 12, 13, 14}))
 }
+
+func Test_ServiceConnectNamespaceConfig_init(t *testing.T) {
+	config := ServiceConnectNamespaceConfig{
+		JobName:          "test_job_1",
+		MetricsPorts:     "11;12;	 13 ;a;14  ",
+		NamespacePattern: "^prod.*$",
+	}
+
+	config.init()
+	assert.True(t, reflect.DeepEqual(config.metricsPortList, []int{11, 12, 13, 14}))
+	assert.True(t, config.namespaceRegex.MatchString("prod.local"))
+	assert.Nil(t, config.containerNameRegex)
+}
+
+func Test_parseTagExpression(t *testing.T) {
+	testCases := map[string]struct {
+		expression string
+		want       []tagMatcher
+		wantErr    bool
+	}{
+		"WithValue":    {expression: "tag:prometheus=true", want: []tagMatcher{{key: "prometheus", value: "true"}}},
+		"WithAnyValue": {expression: "tag:prometheus", want: []tagMatcher{{key: "prometheus", anyValue: true}}},
+		"WithEmptyValue": {
+			expression: "tag:prometheus=",
+			want:       []tagMatcher{{key: "prometheus"}},
+		},
+		"WithMultiple": {
+			expression: "tag:prometheus=true, tag:team",
+			want:       []tagMatcher{{key: "prometheus", value: "true"}, {key: "team", anyValue: true}},
+		},
+		"WithEmpty":     {expression: " ", wantErr: true},
+		"WithNoPrefix":  {expression: "prometheus=true", wantErr: true},
+		"WithNoKey":     {expression: "tag:=true", wantErr: true},
+		"WithEmptyTerm": {expression: "tag:prometheus,", wantErr: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseTagExpression(testCase.expression)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func Test_TaskTagConfig_matchTags(t *testing.T) {
+	config := TaskTagConfig{
+		MetricsPorts:  "9404",
+		TagExpression: "tag:prometheus=true,tag:team",
+	}
+	config.init()
+	assert.Equal(t, []int{9404}, config.metricsPortList)
+
+	assert.True(t, config.matchTags([]*ecs.Tag{
+		{Key: aws.String("prometheus"), Value: aws.String("true")},
+		{Key: aws.String("team"), Value: aws.String("payments")},
+	}))
+	assert.False(t, config.matchTags([]*ecs.Tag{
+		{Key: aws.String("prometheus"), Value: aws.String("false")},
+		{Key: aws.String("team"), Value: aws.String("payments")},
+	}))
+	assert.False(t, config.matchTags([]*ecs.Tag{
+		{Key: aws.String("prometheus"), Value: aws.String("true")},
+	}))
+	assert.False(t, config.matchTags(nil))
+	assert.False(t, (&TaskTagConfig{}).matchTags([]*ecs.Tag{{Key: aws.String("prometheus")}}))
+}
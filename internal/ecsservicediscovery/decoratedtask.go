@@ -17,6 +17,7 @@ import (
 const (
 	containerNameLabel   = "container_name"
 	serviceNameLabel     = "ServiceName"
+	namespaceLabel       = "ServiceConnectNamespace"
 	taskFamilyLabel      = "TaskDefinitionFamily"
 	taskRevisionLabel    = "TaskRevision"
 	taskGroupLabel       = "TaskGroup"
@@ -49,17 +50,25 @@ type DecoratedTask struct {
 	EC2Info        *EC2MetaData
 	ServiceName    string
 
+	// ServiceConnectNamespace and ServiceConnectService are set if the task
+	// belongs to a service in a matching Service Connect namespace.
+	ServiceConnectNamespace string
+	ServiceConnectService   string
+
 	DockerLabelBased    bool
 	TaskDefinitionBased bool
+	TagBased            bool
 }
 
 func (t *DecoratedTask) String() string {
-	return fmt.Sprintf("Task:\n\t\tTaskArn: %v\n\t\tTaskDefinitionArn: %v\n\t\tEC2Info: %v\n\t\tDockerLabelBased: %v\n\t\tTaskDefinitionBased: %v\n",
+	return fmt.Sprintf("Task:\n\t\tTaskArn: %v\n\t\tTaskDefinitionArn: %v\n\t\tEC2Info: %v\n\t\tDockerLabelBased: %v\n\t\tTaskDefinitionBased: %v\n\t\tTagBased: %v\n\t\tServiceConnectNamespace: %v\n",
 		aws.StringValue(t.Task.TaskArn),
 		aws.StringValue(t.Task.TaskDefinitionArn),
 		t.EC2Info,
 		t.DockerLabelBased,
 		t.TaskDefinitionBased,
+		t.TagBased,
+		t.ServiceConnectNamespace,
 	)
 }
 
@@ -315,6 +324,90 @@ func (t *DecoratedTask) exportServiceEndpointBasedTarget(config *ServiceDiscover
 
 }
 
+// exportPortBasedTargets adds a target for each of the metrics ports mapped by
+// the container, with the extra labels.
+func (t *DecoratedTask) exportPortBasedTargets(dockerLabelReg *regexp.Regexp,
+	ip string,
+	c *ecs.ContainerDefinition,
+	metricsPorts []int,
+	configuredMetricsPath string,
+	jobName string,
+	extraLabels map[string]string,
+	targets map[string]*PrometheusTarget) {
+
+	metricsPath := defaultPrometheusMetricsPath
+	if configuredMetricsPath != "" {
+		metricsPath = configuredMetricsPath
+	}
+	for _, port := range metricsPorts {
+		mappedPort := t.getPrometheusExporterPort(int64(port), c)
+		if mappedPort == 0 {
+			continue
+		}
+
+		targetKey := fmt.Sprintf("%s:%d%s", ip, mappedPort, metricsPath)
+		if _, ok := targets[targetKey]; ok {
+			continue
+		}
+
+		prometheusTarget := t.generatePrometheusTarget(dockerLabelReg, c, ip, mappedPort, configuredMetricsPath, jobName)
+		for k, v := range extraLabels {
+			addExporterLabels(prometheusTarget.Labels, k, &v)
+		}
+		targets[targetKey] = prometheusTarget
+	}
+}
+
+func (t *DecoratedTask) exportServiceConnectBasedTarget(config *ServiceDiscoveryConfig,
+	dockerLabelReg *regexp.Regexp,
+	ip string,
+	c *ecs.ContainerDefinition,
+	targets map[string]*PrometheusTarget) {
+
+	if t.ServiceConnectNamespace == "" {
+		return
+	}
+
+	for _, v := range config.ServiceConnect {
+		if !v.namespaceRegex.MatchString(t.ServiceConnectNamespace) {
+			continue
+		}
+
+		if v.ContainerNamePattern != "" && !v.containerNameRegex.MatchString(aws.StringValue(c.Name)) {
+			continue
+		}
+
+		extraLabels := map[string]string{
+			namespaceLabel:   t.ServiceConnectNamespace,
+			serviceNameLabel: t.ServiceConnectService,
+		}
+		t.exportPortBasedTargets(dockerLabelReg, ip, c, v.metricsPortList, v.MetricsPath, v.JobName, extraLabels, targets)
+	}
+}
+
+func (t *DecoratedTask) exportTagBasedTarget(config *ServiceDiscoveryConfig,
+	dockerLabelReg *regexp.Regexp,
+	ip string,
+	c *ecs.ContainerDefinition,
+	targets map[string]*PrometheusTarget) {
+
+	if !t.TagBased {
+		return
+	}
+
+	for _, v := range config.TaskTags {
+		if !v.matchTags(t.Task.Tags) {
+			continue
+		}
+
+		if v.ContainerNamePattern != "" && !v.containerNameRegex.MatchString(aws.StringValue(c.Name)) {
+			continue
+		}
+
+		t.exportPortBasedTargets(dockerLabelReg, ip, c, v.metricsPortList, v.MetricsPath, v.JobName, nil, targets)
+	}
+}
+
 func (t *DecoratedTask) ExporterInformation(config *ServiceDiscoveryConfig, dockerLabelRegex *regexp.Regexp, targets map[string]*PrometheusTarget) {
 	ip := t.getPrivateIp()
 	if ip == "" {
@@ -324,5 +417,7 @@ func (t *DecoratedTask) ExporterInformation(config *ServiceDiscoveryConfig, dock
 		t.exportServiceEndpointBasedTarget(config, dockerLabelRegex, ip, c, targets)
 		t.exportDockerLabelBasedTarget(config, dockerLabelRegex, ip, c, targets)
 		t.exportTaskDefinitionBasedTarget(config, dockerLabelRegex, ip, c, targets)
+		t.exportServiceConnectBasedTarget(config, dockerLabelRegex, ip, c, targets)
+		t.exportTagBasedTarget(config, dockerLabelRegex, ip, c, targets)
 	}
 }
@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/stretchr/testify/assert"
 )
@@ -270,6 +271,75 @@ func Test_exportServiceEndpointBasedTarget_Fargate_AWSVPC(t *testing.T) {
 	assert.Equal(t, "/metrics", target.Labels["ECS_PROMETHEUS_METRICS_PATH"])
 }
 
+func Test_exportServiceConnectBasedTarget_Fargate_AWSVPC(t *testing.T) {
+	fullTask := buildWorkloadFargateAwsvpc(true, false, false, "")
+	fullTask.ServiceConnectNamespace = "prod.local"
+	fullTask.ServiceConnectService = "payments"
+	config := &ServiceDiscoveryConfig{
+		ServiceConnect: []*ServiceConnectNamespaceConfig{
+			{
+				NamespacePattern:     "^prod\\..*$",
+				ContainerNamePattern: "^bugbash-jar.*$",
+				MetricsPorts:         "9404;9406",
+			},
+		},
+	}
+	config.ServiceConnect[0].init()
+
+	targets := make(map[string]*PrometheusTarget)
+	dockerLabelRegex := regexp.MustCompile(prometheusLabelNamePattern)
+	fullTask.ExporterInformation(config, dockerLabelRegex, targets)
+
+	assert.Equal(t, 1, len(targets))
+	target, ok := targets["10.0.0.129:9406/metrics"]
+	assert.True(t, ok, "Missing target: 10.0.0.129:9406/metrics")
+	assert.Equal(t, "bugbash-jar-fargate-awsvpc-with-dockerlabel", target.Labels["container_name"])
+	assert.Equal(t, "prod.local", target.Labels["ServiceConnectNamespace"])
+	assert.Equal(t, "payments", target.Labels["ServiceName"])
+	assert.Equal(t, "ExampleCluster", target.Labels["TaskClusterName"])
+	_, ok = target.Labels["__metrics_path__"]
+	assert.False(t, ok)
+}
+
+func Test_exportTagBasedTarget_Fargate_AWSVPC(t *testing.T) {
+	fullTask := buildWorkloadFargateAwsvpc(true, false, false, "")
+	fullTask.TagBased = true
+	fullTask.Task.Tags = []*ecs.Tag{{Key: aws.String("prometheus"), Value: aws.String("true")}}
+	config := &ServiceDiscoveryConfig{
+		TaskTags: []*TaskTagConfig{
+			{
+				TagExpression: "tag:prometheus=true",
+				JobName:       "tagged",
+				MetricsPorts:  "9404;9406",
+				MetricsPath:   "/stats/metrics",
+			},
+			{
+				TagExpression: "tag:prometheus=false",
+				MetricsPorts:  "9404",
+			},
+		},
+	}
+	config.TaskTags[0].init()
+	config.TaskTags[1].init()
+
+	targets := make(map[string]*PrometheusTarget)
+	dockerLabelRegex := regexp.MustCompile(prometheusLabelNamePattern)
+	fullTask.ExporterInformation(config, dockerLabelRegex, targets)
+
+	assert.Equal(t, 2, len(targets))
+	target, ok := targets["10.0.0.129:9404/stats/metrics"]
+	assert.True(t, ok, "Missing target: 10.0.0.129:9404/stats/metrics")
+	assert.Equal(t, "tagged", target.Labels["job"])
+	assert.Equal(t, "/stats/metrics", target.Labels["__metrics_path__"])
+	_, ok = targets["10.0.0.129:9406/stats/metrics"]
+	assert.True(t, ok, "Missing target: 10.0.0.129:9406/stats/metrics")
+
+	fullTask.TagBased = false
+	targets = make(map[string]*PrometheusTarget)
+	fullTask.ExporterInformation(config, dockerLabelRegex, targets)
+	assert.Equal(t, 0, len(targets))
+}
+
 func Test_ExportMixedSDTarget_Fargate_AWSVPC(t *testing.T) {
 	fullTask := buildWorkloadFargateAwsvpc(true, true, true, "")
 	log.Print(fullTask)
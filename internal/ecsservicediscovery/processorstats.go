@@ -14,6 +14,8 @@ const (
 	AWSCLIDescribeInstancesRequest   = "AWSCLI_DescribeInstancesRequest"
 	AWSCLIDescribeTaskDefinition     = "AWSCLI_DescribeTaskDefinition"
 	AWSCLIListServices               = "AWSCLI_ListServices"
+	AWSCLIDescribeServices           = "AWSCLI_DescribeServices"
+	AWSCLIGetNamespace               = "AWSCLI_GetNamespace"
 	AWSCLIListTasks                  = "AWSCLI_ListTasks"
 	AWSCLIDescribeTasks              = "AWSCLI_DescribeTasks"
 	LRUCacheGetEC2MetaData           = "LRUCache_Get_EC2MetaData"
@@ -0,0 +1,146 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecsservicediscovery
+
+import (
+	"log"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+)
+
+// serviceConnectDeployment is the service and the Service Connect namespace
+// of a deployment.
+type serviceConnectDeployment struct {
+	serviceName string
+	namespace   string
+}
+
+// Tag the Tasks of the services in the Service Connect namespaces that match
+// the Service Connect Namespace based Service Discovery
+type ServiceConnectDiscoveryProcessor struct {
+	serviceConnectConfig []*ServiceConnectNamespaceConfig
+	svcEcs               *ecs.ECS
+	svcCloudMap          *servicediscovery.ServiceDiscovery
+	stats                *ProcessorStats
+
+	// namespaceNames caches the names of the Cloud Map namespaces by ARN,
+	// since the namespaces cannot be renamed.
+	namespaceNames map[string]string
+}
+
+func NewServiceConnectDiscoveryProcessor(ecs *ecs.ECS, cloudMap *servicediscovery.ServiceDiscovery, serviceConnect []*ServiceConnectNamespaceConfig, s *ProcessorStats) *ServiceConnectDiscoveryProcessor {
+	for _, v := range serviceConnect {
+		v.init()
+	}
+
+	return &ServiceConnectDiscoveryProcessor{
+		serviceConnectConfig: serviceConnect,
+		svcEcs:               ecs,
+		svcCloudMap:          cloudMap,
+		stats:                s,
+		namespaceNames:       make(map[string]string),
+	}
+}
+
+func (p *ServiceConnectDiscoveryProcessor) Process(cluster string, taskList []*DecoratedTask) ([]*DecoratedTask, error) {
+	if len(p.serviceConnectConfig) == 0 {
+		return taskList, nil
+	}
+	var services []*string
+	req := &ecs.ListServicesInput{Cluster: &cluster}
+	for {
+		listServiceResp, listServiceErr := p.svcEcs.ListServices(req)
+		p.stats.AddStats(AWSCLIListServices)
+		if listServiceErr != nil {
+			return taskList, newServiceDiscoveryError("Failed to list service ARNs for "+cluster, &listServiceErr)
+		}
+		services = append(services, listServiceResp.ServiceArns...)
+		if listServiceResp.NextToken == nil {
+			break
+		}
+		req.NextToken = listServiceResp.NextToken
+	}
+	deployments := make(map[string]serviceConnectDeployment)
+	for startIndex := 0; startIndex < len(services); startIndex += 10 {
+		endIndex := minInt(startIndex+10, len(services))
+		describeServiceResp, describeServiceErr := p.svcEcs.DescribeServices(&ecs.DescribeServicesInput{Cluster: &cluster, Services: services[startIndex:endIndex]})
+		p.stats.AddStats(AWSCLIDescribeServices)
+		if describeServiceErr != nil {
+			return taskList, newServiceDiscoveryError("Failed to describe service ARNs for "+cluster, &describeServiceErr)
+		}
+		p.mapDeploymentNamespaces(describeServiceResp.Services, deployments)
+	}
+	p.processDecoratedTasks(taskList, deployments)
+	return taskList, nil
+}
+
+// mapDeploymentNamespaces maps the active deployments with Service Connect to
+// their service and namespace name.
+func (p *ServiceConnectDiscoveryProcessor) mapDeploymentNamespaces(services []*ecs.Service, deployments map[string]serviceConnectDeployment) {
+	for _, service := range services {
+		for _, deployment := range service.Deployments {
+			status := aws.StringValue(deployment.Status)
+			if status != "ACTIVE" && status != "PRIMARY" {
+				continue
+			}
+			serviceConnect := deployment.ServiceConnectConfiguration
+			if serviceConnect == nil || !aws.BoolValue(serviceConnect.Enabled) || aws.StringValue(serviceConnect.Namespace) == "" {
+				continue
+			}
+			deployments[aws.StringValue(deployment.Id)] = serviceConnectDeployment{
+				serviceName: aws.StringValue(service.ServiceName),
+				namespace:   p.namespaceName(aws.StringValue(serviceConnect.Namespace)),
+			}
+		}
+	}
+}
+
+// namespaceName returns the name of the namespace, which is either the name or
+// the ARN of the Cloud Map namespace. The ARN is returned if the name cannot
+// be looked up.
+func (p *ServiceConnectDiscoveryProcessor) namespaceName(namespace string) string {
+	if !arn.IsARN(namespace) {
+		return namespace
+	}
+	if name, ok := p.namespaceNames[namespace]; ok {
+		return name
+	}
+	parsed, err := arn.Parse(namespace)
+	if err != nil || p.svcCloudMap == nil {
+		return namespace
+	}
+	id := strings.TrimPrefix(parsed.Resource, "namespace/")
+	resp, err := p.svcCloudMap.GetNamespace(&servicediscovery.GetNamespaceInput{Id: &id})
+	p.stats.AddStats(AWSCLIGetNamespace)
+	if err != nil || resp.Namespace == nil || aws.StringValue(resp.Namespace.Name) == "" {
+		log.Printf("W! Unable to get the name of the Cloud Map namespace %v, matching its ARN instead: %v \n", namespace, err)
+		return namespace
+	}
+	p.namespaceNames[namespace] = aws.StringValue(resp.Namespace.Name)
+	return p.namespaceNames[namespace]
+}
+
+func (p *ServiceConnectDiscoveryProcessor) processDecoratedTasks(taskList []*DecoratedTask, deployments map[string]serviceConnectDeployment) {
+	for _, v := range taskList {
+		deployment, ok := deployments[aws.StringValue(v.Task.StartedBy)]
+		if !ok {
+			continue
+		}
+		for _, config := range p.serviceConnectConfig {
+			if config.namespaceRegex.MatchString(deployment.namespace) {
+				v.ServiceConnectNamespace = deployment.namespace
+				v.ServiceConnectService = deployment.serviceName
+				break
+			}
+		}
+	}
+}
+
+func (p *ServiceConnectDiscoveryProcessor) ProcessorName() string {
+	return "ServiceConnectDiscoveryProcessor"
+}
@@ -0,0 +1,117 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecsservicediscovery
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestingServicesforServiceConnect() []*ecs.Service {
+	return []*ecs.Service{
+		{
+			ServiceName: aws.String("payments"),
+			Deployments: []*ecs.Deployment{
+				{
+					Id:     aws.String("ecs-svc/1"),
+					Status: aws.String("PRIMARY"),
+					ServiceConnectConfiguration: &ecs.ServiceConnectConfiguration{
+						Enabled:   aws.Bool(true),
+						Namespace: aws.String("prod.local"),
+					},
+				},
+				{
+					Id:     aws.String("ecs-svc/2"),
+					Status: aws.String("INACTIVE"),
+					ServiceConnectConfiguration: &ecs.ServiceConnectConfiguration{
+						Enabled:   aws.Bool(true),
+						Namespace: aws.String("prod.local"),
+					},
+				},
+			},
+		},
+		{
+			ServiceName: aws.String("orders"),
+			Deployments: []*ecs.Deployment{
+				{
+					Id:     aws.String("ecs-svc/3"),
+					Status: aws.String("ACTIVE"),
+					ServiceConnectConfiguration: &ecs.ServiceConnectConfiguration{
+						Enabled:   aws.Bool(true),
+						Namespace: aws.String("staging.local"),
+					},
+				},
+				{
+					Id:     aws.String("ecs-svc/4"),
+					Status: aws.String("PRIMARY"),
+					ServiceConnectConfiguration: &ecs.ServiceConnectConfiguration{
+						Enabled: aws.Bool(false),
+					},
+				},
+			},
+		},
+		{
+			ServiceName: aws.String("legacy"),
+			Deployments: []*ecs.Deployment{
+				{
+					Id:     aws.String("ecs-svc/5"),
+					Status: aws.String("PRIMARY"),
+				},
+			},
+		},
+	}
+}
+
+func Test_ServiceConnectDiscoveryProcessor_EmptyConfig(t *testing.T) {
+	p := NewServiceConnectDiscoveryProcessor(nil, nil, nil, &ProcessorStats{})
+	assert.Equal(t, "ServiceConnectDiscoveryProcessor", p.ProcessorName())
+	taskList := []*DecoratedTask{{Task: &ecs.Task{StartedBy: aws.String("ecs-svc/1")}}}
+	taskList, err := p.Process("test_ecs_cluster_name", taskList)
+	assert.NoError(t, err)
+	assert.Equal(t, "", taskList[0].ServiceConnectNamespace)
+}
+
+func Test_ServiceConnectDiscoveryProcessor_Normal(t *testing.T) {
+	config := []*ServiceConnectNamespaceConfig{
+		{NamespacePattern: "^prod\\..*$"},
+		{NamespacePattern: "^staging\\..*$"},
+	}
+	p := NewServiceConnectDiscoveryProcessor(nil, nil, config, &ProcessorStats{})
+
+	deployments := make(map[string]serviceConnectDeployment)
+	p.mapDeploymentNamespaces(buildTestingServicesforServiceConnect(), deployments)
+	assert.Equal(t, map[string]serviceConnectDeployment{
+		"ecs-svc/1": {serviceName: "payments", namespace: "prod.local"},
+		"ecs-svc/3": {serviceName: "orders", namespace: "staging.local"},
+	}, deployments)
+
+	taskList := []*DecoratedTask{
+		{Task: &ecs.Task{StartedBy: aws.String("ecs-svc/1")}},
+		{Task: &ecs.Task{StartedBy: aws.String("ecs-svc/2")}},
+		{Task: &ecs.Task{StartedBy: aws.String("ecs-svc/3")}},
+		{Task: &ecs.Task{}},
+	}
+	p.processDecoratedTasks(taskList, deployments)
+
+	assert.Equal(t, "prod.local", taskList[0].ServiceConnectNamespace)
+	assert.Equal(t, "payments", taskList[0].ServiceConnectService)
+	assert.Equal(t, "", taskList[1].ServiceConnectNamespace)
+	assert.Equal(t, "staging.local", taskList[2].ServiceConnectNamespace)
+	assert.Equal(t, "orders", taskList[2].ServiceConnectService)
+	assert.Equal(t, "", taskList[3].ServiceConnectNamespace)
+}
+
+func Test_ServiceConnectDiscoveryProcessor_NamespaceName(t *testing.T) {
+	p := NewServiceConnectDiscoveryProcessor(nil, nil, nil, &ProcessorStats{})
+	assert.Equal(t, "prod.local", p.namespaceName("prod.local"))
+
+	namespaceArn := "arn:aws:servicediscovery:us-east-2:211220956907:namespace/ns-abcdefghijklmnop"
+	// the ARN is matched if the name cannot be looked up
+	assert.Equal(t, namespaceArn, p.namespaceName(namespaceArn))
+	p.namespaceNames[namespaceArn] = "prod.local"
+	assert.Equal(t, "prod.local", p.namespaceName(namespaceArn))
+}
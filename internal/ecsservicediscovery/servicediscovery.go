@@ -12,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
 
 	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
 )
@@ -19,8 +20,9 @@ import (
 type ServiceDiscovery struct {
 	Config *ServiceDiscoveryConfig
 
-	svcEcs *ecs.ECS
-	svcEc2 *ec2.EC2
+	svcEcs      *ecs.ECS
+	svcEc2      *ec2.EC2
+	svcCloudMap *servicediscovery.ServiceDiscovery
 
 	stats             ProcessorStats
 	clusterProcessors []Processor
@@ -48,15 +50,27 @@ func (sd *ServiceDiscovery) init() {
 		}
 	}
 
+	sd.svcCloudMap = servicediscovery.New(configProvider, aws.NewConfig().WithRegion(sd.Config.TargetClusterRegion).WithMaxRetries(AwsSdkLevelRetryCount))
+
+	if sd.Configurer != nil {
+		if err := sd.Configurer.Configure(awsmiddleware.SDKv1(&sd.svcCloudMap.Handlers)); err != nil {
+			log.Printf("ERROR: Failed to configure Cloud Map client: %v", err)
+		}
+	}
+
 	sd.initClusterProcessorPipeline()
 }
 
 func (sd *ServiceDiscovery) initClusterProcessorPipeline() {
-	sd.clusterProcessors = append(sd.clusterProcessors, NewTaskProcessor(sd.svcEcs, &sd.stats))
+	taskProcessor := NewTaskProcessor(sd.svcEcs, &sd.stats)
+	taskProcessor.includeTags = len(sd.Config.TaskTags) > 0
+	sd.clusterProcessors = append(sd.clusterProcessors, taskProcessor)
 	sd.clusterProcessors = append(sd.clusterProcessors, NewTaskDefinitionProcessor(sd.svcEcs, &sd.stats))
 	sd.clusterProcessors = append(sd.clusterProcessors, NewServiceEndpointDiscoveryProcessor(sd.svcEcs, sd.Config.ServiceNamesForTasks, &sd.stats))
 	sd.clusterProcessors = append(sd.clusterProcessors, NewDockerLabelDiscoveryProcessor(sd.Config.DockerLabel))
 	sd.clusterProcessors = append(sd.clusterProcessors, NewTaskDefinitionDiscoveryProcessor(sd.Config.TaskDefinitions))
+	sd.clusterProcessors = append(sd.clusterProcessors, NewServiceConnectDiscoveryProcessor(sd.svcEcs, sd.svcCloudMap, sd.Config.ServiceConnect, &sd.stats))
+	sd.clusterProcessors = append(sd.clusterProcessors, NewTaskTagDiscoveryProcessor(sd.Config.TaskTags))
 	sd.clusterProcessors = append(sd.clusterProcessors, NewTaskFilterProcessor())
 	sd.clusterProcessors = append(sd.clusterProcessors, NewContainerInstanceProcessor(sd.svcEcs, sd.svcEc2, &sd.stats))
 	sd.clusterProcessors = append(sd.clusterProcessors, NewTargetsExportProcessor(sd.Config, &sd.stats))
@@ -103,11 +117,19 @@ func (sd *ServiceDiscovery) validateConfig() bool {
 		return false
 	}
 
-	if sd.Config.DockerLabel == nil && len(sd.Config.TaskDefinitions) == 0 && len(sd.Config.ServiceNamesForTasks) == 0 {
-		log.Printf("E! Neither docker label based discovery, nor task definition based discovery, nor service name based discovery is enabled.\n")
+	if sd.Config.DockerLabel == nil && len(sd.Config.TaskDefinitions) == 0 && len(sd.Config.ServiceNamesForTasks) == 0 &&
+		len(sd.Config.ServiceConnect) == 0 && len(sd.Config.TaskTags) == 0 {
+		log.Printf("E! Neither docker label based discovery, nor task definition based discovery, nor service name based discovery, nor Service Connect namespace based discovery, nor task tag based discovery is enabled.\n")
 		return false
 	}
 
+	for _, v := range sd.Config.TaskTags {
+		if _, err := parseTagExpression(v.TagExpression); err != nil {
+			log.Printf("E! Invalid ECS service discovery tag expression: %v.\n", err)
+			return false
+		}
+	}
+
 	if sd.Config.TargetCluster == "" || sd.Config.TargetClusterRegion == "" {
 		log.Printf("E! Target ECS cluster info is not correct.\n")
 		return false
@@ -20,7 +20,7 @@ func Test_ServiceDiscovery_InitPipelines(t *testing.T) {
 	p := &ServiceDiscovery{Config: &config}
 	p.initClusterProcessorPipeline()
 
-	assert.Equal(t, 8, len(p.clusterProcessors))
+	assert.Equal(t, 10, len(p.clusterProcessors))
 }
 
 func Test_StartECSServiceDiscovery_NilConfig(t *testing.T) {
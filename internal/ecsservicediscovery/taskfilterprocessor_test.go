@@ -57,6 +57,14 @@ func buildTestingTasksforTaskFilter() []*DecoratedTask {
 			TaskDefinitionBased: false,
 			TaskDefinition:      &ecs.TaskDefinition{},
 		},
+		&DecoratedTask{
+			ServiceConnectNamespace: "prod.local",
+			TaskDefinition:          &ecs.TaskDefinition{},
+		},
+		&DecoratedTask{
+			TagBased:       true,
+			TaskDefinition: &ecs.TaskDefinition{},
+		},
 	}
 }
 
@@ -65,7 +73,7 @@ func Test_NewTaskFilterProcessor_Normal(t *testing.T) {
 	assert.Equal(t, "TaskFilterProcessor", p.ProcessorName())
 	taskList := buildTestingTasksforTaskFilter()
 	taskList, _ = p.Process("test_ecs_cluster_name", taskList)
-	assert.Equal(t, 7, len(taskList))
+	assert.Equal(t, 9, len(taskList))
 }
 
 func Test_NewTaskFilterProcessor_Empty(t *testing.T) {
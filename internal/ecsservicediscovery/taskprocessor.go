@@ -6,6 +6,7 @@ package ecsservicediscovery
 import (
 	"log"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecs"
 )
 
@@ -13,6 +14,10 @@ import (
 type TaskProcessor struct {
 	svcEcs *ecs.ECS
 	stats  *ProcessorStats
+
+	// includeTags describes the tasks with their tags, for the tag based
+	// discovery.
+	includeTags bool
 }
 
 func NewTaskProcessor(ecs *ecs.ECS, s *ProcessorStats) *TaskProcessor {
@@ -31,7 +36,11 @@ func (p *TaskProcessor) Process(cluster string, taskList []*DecoratedTask) ([]*D
 			return taskList, newServiceDiscoveryError("Failed to list task ARNs for "+cluster, &listTaskErr)
 		}
 
-		descTaskResp, descTaskErr := p.svcEcs.DescribeTasks(&ecs.DescribeTasksInput{Cluster: &cluster, Tasks: listTaskResp.TaskArns})
+		descTaskReq := &ecs.DescribeTasksInput{Cluster: &cluster, Tasks: listTaskResp.TaskArns}
+		if p.includeTags {
+			descTaskReq.Include = aws.StringSlice([]string{ecs.TaskFieldTags})
+		}
+		descTaskResp, descTaskErr := p.svcEcs.DescribeTasks(descTaskReq)
 		p.stats.AddStats(AWSCLIDescribeTasks)
 		if descTaskErr != nil {
 			return taskList, newServiceDiscoveryError("Failed to describe ECS Tasks for "+cluster, &descTaskErr)
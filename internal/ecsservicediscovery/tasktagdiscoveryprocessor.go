@@ -0,0 +1,54 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecsservicediscovery
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// Tag the Tasks that match the Task Tag based Service Discovery
+type TaskTagDiscoveryProcessor struct {
+	taskTagsConfig []*TaskTagConfig
+}
+
+func NewTaskTagDiscoveryProcessor(taskTags []*TaskTagConfig) *TaskTagDiscoveryProcessor {
+	for _, v := range taskTags {
+		v.init()
+	}
+
+	return &TaskTagDiscoveryProcessor{taskTagsConfig: taskTags}
+}
+
+func checkContainerNamePatternTag(containers []*ecs.ContainerDefinition, config *TaskTagConfig) bool {
+	for _, c := range containers {
+		if config.containerNameRegex.MatchString(aws.StringValue(c.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *TaskTagDiscoveryProcessor) Process(cluster string, taskList []*DecoratedTask) ([]*DecoratedTask, error) {
+	if len(p.taskTagsConfig) == 0 {
+		return taskList, nil
+	}
+
+	for _, v := range taskList {
+		for _, t := range p.taskTagsConfig {
+			if t.matchTags(v.Task.Tags) {
+				if t.ContainerNamePattern == "" || checkContainerNamePatternTag(v.TaskDefinition.ContainerDefinitions, t) {
+					v.TagBased = true
+					break
+				}
+			}
+		}
+	}
+
+	return taskList, nil
+}
+
+func (p *TaskTagDiscoveryProcessor) ProcessorName() string {
+	return "TaskTagDiscoveryProcessor"
+}
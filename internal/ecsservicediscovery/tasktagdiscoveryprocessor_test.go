@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecsservicediscovery
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestingTasksforTaskTag() []*DecoratedTask {
+	taskDefinition := &ecs.TaskDefinition{
+		ContainerDefinitions: []*ecs.ContainerDefinition{
+			{Name: aws.String("envoy")},
+			{Name: aws.String("java-app")},
+		},
+	}
+	return []*DecoratedTask{
+		{
+			Task: &ecs.Task{Tags: []*ecs.Tag{
+				{Key: aws.String("prometheus"), Value: aws.String("true")},
+			}},
+			TaskDefinition: taskDefinition,
+		},
+		{
+			Task: &ecs.Task{Tags: []*ecs.Tag{
+				{Key: aws.String("prometheus"), Value: aws.String("false")},
+			}},
+			TaskDefinition: taskDefinition,
+		},
+		{
+			Task:           &ecs.Task{},
+			TaskDefinition: taskDefinition,
+		},
+		{
+			Task: &ecs.Task{Tags: []*ecs.Tag{
+				{Key: aws.String("scrape"), Value: aws.String("")},
+			}},
+			TaskDefinition: &ecs.TaskDefinition{},
+		},
+	}
+}
+
+func Test_TaskTagDiscoveryProcessor_EmptyConfig(t *testing.T) {
+	p := NewTaskTagDiscoveryProcessor(nil)
+	assert.Equal(t, "TaskTagDiscoveryProcessor", p.ProcessorName())
+	taskList := buildTestingTasksforTaskTag()
+	p.Process("test_ecs_cluster_name", taskList)
+
+	for _, v := range taskList {
+		assert.False(t, v.TagBased)
+	}
+}
+
+func Test_TaskTagDiscoveryProcessor_Normal(t *testing.T) {
+	config := []*TaskTagConfig{
+		{TagExpression: "tag:prometheus=true"},
+		{TagExpression: "tag:scrape", ContainerNamePattern: "^java-.*$"},
+	}
+
+	taskList := buildTestingTasksforTaskTag()
+	p := NewTaskTagDiscoveryProcessor(config)
+	p.Process("test_ecs_cluster_name", taskList)
+
+	assert.True(t, taskList[0].TagBased)
+	assert.False(t, taskList[1].TagBased)
+	assert.False(t, taskList[2].TagBased)
+	// the task has no container matching the container name pattern
+	assert.False(t, taskList[3].TagBased)
+}
@@ -0,0 +1,189 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package enrollment
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	pemTypeCertificateRequest = "CERTIFICATE REQUEST"
+
+	defaultTimeout = 30 * time.Second
+	// maxResponseSize bounds the response read from the enrollment endpoint.
+	maxResponseSize = 1 << 20
+)
+
+var (
+	errEmptyToken = errors.New("enrollment token is empty")
+)
+
+// Request is sent to the enrollment endpoint. The token can only be used once.
+type Request struct {
+	Token string `json:"token"`
+	// CSR is the PEM encoded certificate signing request for the key
+	// generated by the agent. The private key never leaves the host.
+	CSR string `json:"csr"`
+}
+
+// Response is returned by the enrollment endpoint.
+type Response struct {
+	// Certificate is the PEM encoded certificate signed for the CSR.
+	Certificate string `json:"certificate"`
+	// CABundle is the PEM encoded CA certificates used to authenticate both
+	// sides of the management channel.
+	CABundle string `json:"ca_bundle"`
+}
+
+// Client exchanges an enrollment token for a signed identity.
+type Client struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+func NewClient(endpoint string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Client{endpoint: endpoint, httpClient: httpClient}
+}
+
+// Enroll generates a new key pair and requests a certificate for it using the
+// token. The name is the common name requested for the host.
+func (c *Client) Enroll(ctx context.Context, token, name string) (*Identity, error) {
+	if token == "" {
+		return nil, errEmptyToken
+	}
+	u, err := url.Parse(c.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enrollment endpoint: %w", err)
+	}
+	if u.Scheme != "https" {
+		return nil, fmt.Errorf("enrollment endpoint %s must use https", c.endpoint)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate key: %w", err)
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: name},
+	}, key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create certificate request: %w", err)
+	}
+	body, err := json.Marshal(Request{
+		Token: token,
+		CSR:   string(pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificateRequest, Bytes: csr})),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("enrollment request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read enrollment response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("enrollment rejected with status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	var enrollResp Response
+	if err = json.Unmarshal(respBody, &enrollResp); err != nil {
+		return nil, fmt.Errorf("invalid enrollment response: %w", err)
+	}
+
+	certs, err := decodeCertificates([]byte(enrollResp.Certificate))
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate in enrollment response: %w", err)
+	}
+	cas, err := decodeCertificates([]byte(enrollResp.CABundle))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA bundle in enrollment response: %w", err)
+	}
+	id := &Identity{Key: key, Certificate: certs[0], CACertificates: cas}
+	if err = id.Verify(); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// Config of a one-time enrollment.
+type Config struct {
+	Endpoint string
+	// TokenFile contains the one-time enrollment token. It is removed once
+	// the identity is stored.
+	TokenFile string
+	// CAFile optionally restricts the CAs trusted for the enrollment
+	// endpoint. The system roots are used if empty.
+	CAFile string
+	// Name is the common name requested for the host.
+	Name string
+}
+
+// Run enrolls the host and stores the identity in the trust store.
+func Run(ctx context.Context, cfg Config, store *TrustStore) error {
+	token, err := os.ReadFile(cfg.TokenFile)
+	if err != nil {
+		return fmt.Errorf("unable to read enrollment token: %w", err)
+	}
+	httpClient := &http.Client{Timeout: defaultTimeout}
+	if cfg.CAFile != "" {
+		caData, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return fmt.Errorf("unable to read enrollment CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return fmt.Errorf("no certificate found in enrollment CA file %s", cfg.CAFile)
+		}
+		httpClient.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		}
+	}
+	if store.Enrolled() {
+		log.Printf("W! Replacing the identity enrolled in %s", store.dir)
+	}
+	id, err := NewClient(cfg.Endpoint, httpClient).Enroll(ctx, strings.TrimSpace(string(token)), cfg.Name)
+	if err != nil {
+		return err
+	}
+	if err = store.Save(id); err != nil {
+		return fmt.Errorf("unable to store identity: %w", err)
+	}
+	// the token has been used and cannot be presented again
+	if err = os.Remove(cfg.TokenFile); err != nil {
+		log.Printf("W! Unable to remove used enrollment token %s: %v", cfg.TokenFile, err)
+	}
+	log.Printf("I! Enrolled as %s, identity stored in %s", id.Certificate.Subject.CommonName, store.dir)
+	return nil
+}
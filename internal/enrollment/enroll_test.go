@@ -0,0 +1,139 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package enrollment
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "one-time-token"
+
+// newTestServer returns an enrollment endpoint signing the CSR if presented
+// with the test token. The token can only be used once.
+func newTestServer(t *testing.T, ca *testCA) *httptest.Server {
+	t.Helper()
+	used := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Token != testToken || used {
+			http.Error(w, "invalid token", http.StatusForbidden)
+			return
+		}
+		used = true
+		block, _ := pem.Decode([]byte(req.CSR))
+		require.NotNil(t, block)
+		csr, err := x509.ParseCertificateRequest(block.Bytes)
+		require.NoError(t, err)
+		require.NoError(t, csr.CheckSignature())
+		cert := ca.sign(t, csr.Subject.CommonName, csr.PublicKey)
+		_ = json.NewEncoder(w).Encode(Response{
+			Certificate: string(encodeCertificates(cert)),
+			CABundle:    string(encodeCertificates(ca.cert)),
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEnroll(t *testing.T) {
+	ca := newTestCA(t)
+	server := newTestServer(t, ca)
+	client := NewClient(server.URL, server.Client())
+
+	id, err := client.Enroll(context.Background(), testToken, "host")
+	require.NoError(t, err)
+	assert.Equal(t, "host", id.Certificate.Subject.CommonName)
+	assert.NoError(t, id.Verify())
+
+	// the token cannot be presented again
+	_, err = client.Enroll(context.Background(), testToken, "host")
+	assert.ErrorContains(t, err, "status 403")
+}
+
+func TestEnrollWithInvalidRequest(t *testing.T) {
+	ca := newTestCA(t)
+	server := newTestServer(t, ca)
+	testCases := map[string]struct {
+		endpoint string
+		token    string
+		wantErr  string
+	}{
+		"WithEmptyToken": {
+			endpoint: server.URL,
+			wantErr:  errEmptyToken.Error(),
+		},
+		"WithInvalidToken": {
+			endpoint: server.URL,
+			token:    "invalid",
+			wantErr:  "status 403",
+		},
+		"WithHTTP": {
+			endpoint: "http://localhost",
+			token:    testToken,
+			wantErr:  "must use https",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewClient(testCase.endpoint, server.Client()).Enroll(context.Background(), testCase.token, "host")
+			assert.ErrorContains(t, err, testCase.wantErr)
+		})
+	}
+}
+
+func TestEnrollWithMismatchedCertificate(t *testing.T) {
+	ca := newTestCA(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(Response{
+			Certificate: string(encodeCertificates(ca.sign(t, "host", &key.PublicKey))),
+			CABundle:    string(encodeCertificates(ca.cert)),
+		})
+	}))
+	defer server.Close()
+	_, err = NewClient(server.URL, server.Client()).Enroll(context.Background(), testToken, "host")
+	assert.ErrorIs(t, err, errKeyMismatch)
+}
+
+func TestRun(t *testing.T) {
+	ca := newTestCA(t)
+	server := newTestServer(t, ca)
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte(testToken+"\n"), 0600))
+	caFile := filepath.Join(dir, "server-ca.pem")
+	require.NoError(t, os.WriteFile(caFile, encodeCertificates(server.Certificate()), 0600))
+	store := NewTrustStore(filepath.Join(dir, "trust-store"))
+
+	cfg := Config{Endpoint: server.URL, TokenFile: tokenFile, CAFile: caFile, Name: "host"}
+	require.NoError(t, Run(context.Background(), cfg, store))
+	assert.True(t, store.Enrolled())
+	_, err := os.Stat(tokenFile)
+	assert.True(t, os.IsNotExist(err))
+	id, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "host", id.Certificate.Subject.CommonName)
+
+	// fails without the token
+	assert.Error(t, Run(context.Background(), cfg, store))
+}
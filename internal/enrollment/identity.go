@@ -0,0 +1,122 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package enrollment
+
+import (
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+const (
+	pemTypeCertificate = "CERTIFICATE"
+	pemTypePrivateKey  = "EC PRIVATE KEY"
+)
+
+var (
+	errNoCertificate = errors.New("no certificate found")
+	errKeyMismatch   = errors.New("certificate does not match the private key")
+)
+
+// Identity is the signed identity of the host. The certificate is presented
+// when opening a management channel session and the CA certificates are the
+// only ones trusted to authenticate the other side.
+type Identity struct {
+	Key            *ecdsa.PrivateKey
+	Certificate    *x509.Certificate
+	CACertificates []*x509.Certificate
+}
+
+// Verify checks that the certificate belongs to the key and is issued for
+// client authentication by the CA.
+func (id *Identity) Verify() error {
+	if id.Key == nil || id.Certificate == nil || len(id.CACertificates) == 0 {
+		return errors.New("incomplete identity")
+	}
+	pub, ok := id.Certificate.PublicKey.(*ecdsa.PublicKey)
+	if !ok || !pub.Equal(&id.Key.PublicKey) {
+		return errKeyMismatch
+	}
+	_, err := id.Certificate.Verify(x509.VerifyOptions{
+		Roots:     id.CAPool(),
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("certificate is not trusted by the CA: %w", err)
+	}
+	return nil
+}
+
+// CAPool returns the CA certificates as a pool.
+func (id *Identity) CAPool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	for _, ca := range id.CACertificates {
+		pool.AddCert(ca)
+	}
+	return pool
+}
+
+// TLSConfig returns a mutual TLS config presenting the host certificate and
+// only trusting servers signed by the CA.
+func (id *Identity) TLSConfig() *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{id.Certificate.Raw},
+			PrivateKey:  id.Key,
+			Leaf:        id.Certificate,
+		}},
+		RootCAs:    id.CAPool(),
+		MinVersion: tls.VersionTLS12,
+	}
+}
+
+func encodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: der}), nil
+}
+
+func decodeKey(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemTypePrivateKey {
+		return nil, errors.New("no private key found")
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+func encodeCertificates(certs ...*x509.Certificate) []byte {
+	var data []byte
+	for _, cert := range certs {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: cert.Raw})...)
+	}
+	return data
+}
+
+func decodeCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != pemTypeCertificate {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errNoCertificate
+	}
+	return certs, nil
+}
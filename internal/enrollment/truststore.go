@@ -0,0 +1,104 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package enrollment
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/atomicfile"
+)
+
+const (
+	keyFileName         = "identity.key"
+	certificateFileName = "identity.crt"
+	caFileName          = "ca.crt"
+)
+
+// TrustStore persists the host identity in a directory only readable by the
+// agent user.
+type TrustStore struct {
+	dir string
+}
+
+func NewTrustStore(dir string) *TrustStore {
+	return &TrustStore{dir: dir}
+}
+
+// Enrolled returns true if an identity has been stored.
+func (s *TrustStore) Enrolled() bool {
+	for _, name := range []string{keyFileName, certificateFileName, caFileName} {
+		if _, err := os.Stat(filepath.Join(s.dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Save verifies and stores the identity, replacing any previous one.
+func (s *TrustStore) Save(id *Identity) error {
+	if err := id.Verify(); err != nil {
+		return err
+	}
+	key, err := encodeKey(id.Key)
+	if err != nil {
+		return fmt.Errorf("unable to encode private key: %w", err)
+	}
+	if err = os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{keyFileName, key},
+		{certificateFileName, encodeCertificates(id.Certificate)},
+		{caFileName, encodeCertificates(id.CACertificates...)},
+	}
+	for _, file := range files {
+		if err = atomicfile.WriteFile(filepath.Join(s.dir, file.name), file.data, 0600); err != nil {
+			return fmt.Errorf("unable to write %s to trust store: %w", file.name, err)
+		}
+	}
+	return nil
+}
+
+// Load reads and verifies the stored identity.
+func (s *TrustStore) Load() (*Identity, error) {
+	keyData, err := os.ReadFile(filepath.Join(s.dir, keyFileName))
+	if err != nil {
+		return nil, err
+	}
+	key, err := decodeKey(keyData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", keyFileName, err)
+	}
+	certs, err := readCertificates(filepath.Join(s.dir, certificateFileName))
+	if err != nil {
+		return nil, err
+	}
+	cas, err := readCertificates(filepath.Join(s.dir, caFileName))
+	if err != nil {
+		return nil, err
+	}
+	id := &Identity{Key: key, Certificate: certs[0], CACertificates: cas}
+	if err = id.Verify(); err != nil {
+		return nil, fmt.Errorf("invalid identity in trust store %s: %w", s.dir, err)
+	}
+	return id, nil
+}
+
+func readCertificates(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	certs, err := decodeCertificates(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", filepath.Base(path), err)
+	}
+	return certs, nil
+}
@@ -0,0 +1,92 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package enrollment
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustStore(t *testing.T) {
+	ca := newTestCA(t)
+	id := newTestIdentity(t, ca)
+	dir := filepath.Join(t.TempDir(), "trust-store")
+	store := NewTrustStore(dir)
+	assert.False(t, store.Enrolled())
+	_, err := store.Load()
+	assert.Error(t, err)
+
+	require.NoError(t, store.Save(id))
+	assert.True(t, store.Enrolled())
+	if runtime.GOOS != "windows" {
+		for _, name := range []string{keyFileName, certificateFileName, caFileName} {
+			info, err := os.Stat(filepath.Join(dir, name))
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), name)
+		}
+		info, err := os.Stat(dir)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+	}
+
+	got, err := store.Load()
+	require.NoError(t, err)
+	assert.True(t, got.Key.Equal(id.Key))
+	assert.True(t, got.Certificate.Equal(id.Certificate))
+	require.Len(t, got.CACertificates, 1)
+	assert.True(t, got.CACertificates[0].Equal(ca.cert))
+	tlsConfig := got.TLSConfig()
+	assert.Len(t, tlsConfig.Certificates, 1)
+	assert.NotNil(t, tlsConfig.RootCAs)
+
+	// replaces the previous identity
+	other := newTestIdentity(t, ca)
+	require.NoError(t, store.Save(other))
+	got, err = store.Load()
+	require.NoError(t, err)
+	assert.True(t, got.Key.Equal(other.Key))
+}
+
+func TestTrustStoreRejectsInvalidIdentity(t *testing.T) {
+	ca := newTestCA(t)
+	otherCA := newTestCA(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	testCases := map[string]struct {
+		id      *Identity
+		wantErr error
+	}{
+		"WithMismatchedKey": {
+			id:      &Identity{Key: key, Certificate: newTestIdentity(t, ca).Certificate, CACertificates: []*x509.Certificate{ca.cert}},
+			wantErr: errKeyMismatch,
+		},
+		"WithUntrustedCertificate": {
+			id: &Identity{Key: key, Certificate: otherCA.sign(t, "host", &key.PublicKey), CACertificates: []*x509.Certificate{ca.cert}},
+		},
+		"WithoutCA": {
+			id: &Identity{Key: key, Certificate: ca.sign(t, "host", &key.PublicKey)},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			store := NewTrustStore(dir)
+			err := store.Save(testCase.id)
+			require.Error(t, err)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			}
+			assert.False(t, store.Enrolled())
+		})
+	}
+}
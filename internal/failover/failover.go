@@ -0,0 +1,194 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package failover routes the requests of an AWS SDK client to an ordered list
+// of endpoints. The requests go to the first endpoint until it fails several
+// times in a row, then to the next one. While a secondary endpoint is active,
+// the primary is probed periodically and the client fails back to it once a
+// probe succeeds.
+package failover
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+
+	"github.com/aws/amazon-cloudwatch-agent/profiler"
+)
+
+const (
+	// DefaultFailureThreshold is the number of consecutive failed attempts
+	// after which the next endpoint is used.
+	DefaultFailureThreshold = 3
+	// DefaultFailbackInterval is how long a secondary endpoint is used before
+	// the primary is probed.
+	DefaultFailbackInterval = 5 * time.Minute
+)
+
+// Endpoints is the ordered list of endpoints of a client. The first one is
+// the primary.
+type Endpoints struct {
+	// name prefixes the profiler stats, e.g. cloudwatchlogs.
+	name             string
+	endpoints        []*url.URL
+	failureThreshold int
+	failbackInterval time.Duration
+
+	mu       sync.Mutex
+	active   int
+	failures int
+	// activeSince is when the active endpoint was last confirmed to be the
+	// best one, i.e. when it became active or the last probe of the primary
+	// failed.
+	activeSince time.Time
+	// probing is set while an attempt is sent to the primary.
+	probing bool
+
+	now func() time.Time
+}
+
+// New creates the endpoints. The URLs must have a scheme and a host.
+func New(name string, endpoints []string) (*Endpoints, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("at least one endpoint must be set")
+	}
+	e := &Endpoints{
+		name:             name,
+		failureThreshold: DefaultFailureThreshold,
+		failbackInterval: DefaultFailbackInterval,
+		now:              time.Now,
+	}
+	for _, endpoint := range endpoints {
+		u, err := Parse(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		e.endpoints = append(e.endpoints, u)
+	}
+	return e, nil
+}
+
+// Parse parses the endpoint URL.
+func Parse(endpoint string) (*url.URL, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q: must be an http or https URL with a host", endpoint)
+	}
+	return u, nil
+}
+
+// Active returns the endpoint the requests are sent to.
+func (e *Endpoints) Active() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.endpoints[e.active].String()
+}
+
+// Configure routes the requests of the client. Each attempt is sent to the
+// endpoint selected when it is signed, so the retries of a request can fail
+// over too.
+func (e *Endpoints) Configure(handlers *request.Handlers) {
+	handlers.Sign.PushFrontNamed(request.NamedHandler{Name: "FailoverRouteHandler", Fn: e.route})
+	handlers.CompleteAttempt.PushBackNamed(request.NamedHandler{Name: "FailoverHealthHandler", Fn: e.observe})
+}
+
+func (e *Endpoints) route(r *request.Request) {
+	u := e.endpoints[e.next()]
+	r.HTTPRequest.URL.Scheme = u.Scheme
+	r.HTTPRequest.URL.Host = u.Host
+	r.HTTPRequest.Host = ""
+}
+
+// next returns the index of the endpoint for the next attempt.
+func (e *Endpoints) next() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.active != 0 && !e.probing && e.now().Sub(e.activeSince) >= e.failbackInterval {
+		e.probing = true
+		return 0
+	}
+	return e.active
+}
+
+func (e *Endpoints) observe(r *request.Request) {
+	index := e.index(r.HTTPRequest.URL)
+	if index < 0 {
+		return
+	}
+	var aerr awserr.Error
+	if errors.As(r.Error, &aerr) && aerr.Code() == request.CanceledErrorCode {
+		// the agent canceled the request, it says nothing about the endpoint
+		return
+	}
+	profiler.Profiler.AddStats([]string{e.name, "endpoint", strconv.Itoa(index), "attempts"}, 1)
+	e.update(index, healthy(r))
+}
+
+// healthy returns false if the endpoint could not be reached or had a server
+// error. Client errors and throttling mean the endpoint is up.
+func healthy(r *request.Request) bool {
+	if r.Error == nil {
+		return true
+	}
+	return r.HTTPResponse != nil && r.HTTPResponse.StatusCode != 0 && r.HTTPResponse.StatusCode < http.StatusInternalServerError
+}
+
+func (e *Endpoints) index(u *url.URL) int {
+	for i, endpoint := range e.endpoints {
+		if endpoint.Scheme == u.Scheme && endpoint.Host == u.Host {
+			return i
+		}
+	}
+	return -1
+}
+
+func (e *Endpoints) update(index int, healthy bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if index == 0 && e.probing {
+		e.probing = false
+		if healthy {
+			log.Printf("I! %s: primary endpoint %s is healthy again, failing back from %s", e.name, e.endpoints[0], e.endpoints[e.active])
+			profiler.Profiler.AddStats([]string{e.name, "endpoint", "failbacks"}, 1)
+			e.activate(0)
+		} else {
+			e.activeSince = e.now()
+		}
+		return
+	}
+	// attempts sent before the last switch are ignored
+	if index != e.active {
+		return
+	}
+	if healthy {
+		e.failures = 0
+		return
+	}
+	e.failures++
+	if e.failures < e.failureThreshold || len(e.endpoints) == 1 {
+		return
+	}
+	next := (e.active + 1) % len(e.endpoints)
+	log.Printf("W! %s: endpoint %s failed %d times in a row, failing over to %s", e.name, e.endpoints[e.active], e.failures, e.endpoints[next])
+	profiler.Profiler.AddStats([]string{e.name, "endpoint", "failovers"}, 1)
+	e.activate(next)
+}
+
+// activate makes the endpoint active. Must be called with mu held.
+func (e *Endpoints) activate(index int) {
+	e.active = index
+	e.failures = 0
+	e.probing = false
+	e.activeSince = e.now()
+}
@@ -0,0 +1,130 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package failover
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New("test", nil)
+	assert.Error(t, err)
+	_, err = New("test", []string{"https://logs.us-east-1.amazonaws.com", "logs.us-west-2.amazonaws.com"})
+	assert.Error(t, err)
+	_, err = New("test", []string{"ftp://logs.us-west-2.amazonaws.com"})
+	assert.Error(t, err)
+	e, err := New("test", []string{"https://logs.us-east-1.amazonaws.com", "https://logs.us-west-2.amazonaws.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://logs.us-east-1.amazonaws.com", e.Active())
+}
+
+func TestUpdate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	e, err := New("test", []string{"https://primary", "https://secondary"})
+	require.NoError(t, err)
+	e.now = func() time.Time { return now }
+
+	// a success resets the failures
+	e.update(e.next(), false)
+	e.update(e.next(), false)
+	e.update(e.next(), true)
+	e.update(e.next(), false)
+	e.update(e.next(), false)
+	assert.Equal(t, 0, e.active)
+	e.update(e.next(), false)
+	assert.Equal(t, 1, e.active)
+
+	// attempts sent to the previous endpoint are ignored
+	e.update(0, false)
+	assert.Equal(t, 0, e.failures)
+
+	// the primary is probed once the failback interval elapsed
+	now = now.Add(DefaultFailbackInterval)
+	assert.Equal(t, 0, e.next())
+	assert.Equal(t, 1, e.next())
+	e.update(0, false)
+	assert.Equal(t, 1, e.active)
+	assert.Equal(t, 1, e.next())
+
+	now = now.Add(DefaultFailbackInterval)
+	assert.Equal(t, 0, e.next())
+	e.update(0, true)
+	assert.Equal(t, 0, e.active)
+	assert.Equal(t, "https://primary", e.Active())
+}
+
+func TestUpdateWraps(t *testing.T) {
+	e, err := New("test", []string{"https://primary", "https://secondary"})
+	require.NoError(t, err)
+	for i := 0; i < 2*DefaultFailureThreshold; i++ {
+		e.update(e.next(), false)
+	}
+	assert.Equal(t, 0, e.active)
+}
+
+func TestConfigure(t *testing.T) {
+	var primaryStatus atomic.Int32
+	primaryStatus.Store(http.StatusServiceUnavailable)
+	var primaryCount, secondaryCount atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		primaryCount.Add(1)
+		w.WriteHeader(int(primaryStatus.Load()))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		secondaryCount.Add(1)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer secondary.Close()
+
+	now := time.Unix(1700000000, 0)
+	e, err := New("test", []string{primary.URL, secondary.URL})
+	require.NoError(t, err)
+	e.now = func() time.Time { return now }
+
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(primary.URL),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		Retryer: client.DefaultRetryer{
+			NumMaxRetries: DefaultFailureThreshold,
+			MinRetryDelay: time.Millisecond,
+			MaxRetryDelay: time.Millisecond,
+			// 503 is a throttling error for the SDK
+			MinThrottleDelay: time.Millisecond,
+			MaxThrottleDelay: time.Millisecond,
+		},
+	}))
+	svc := cloudwatchlogs.New(sess)
+	e.Configure(&svc.Handlers)
+	input := &cloudwatchlogs.CreateLogGroupInput{LogGroupName: aws.String("group")}
+
+	// the last retry goes to the secondary
+	_, err = svc.CreateLogGroup(input)
+	require.NoError(t, err)
+	assert.EqualValues(t, DefaultFailureThreshold, primaryCount.Load())
+	assert.EqualValues(t, 1, secondaryCount.Load())
+	assert.Equal(t, secondary.URL, e.Active())
+
+	// client errors do not fail over
+	primaryStatus.Store(http.StatusBadRequest)
+	now = now.Add(DefaultFailbackInterval)
+	_, err = svc.CreateLogGroup(input)
+	require.Error(t, err)
+	assert.Equal(t, primary.URL, e.Active())
+}
@@ -0,0 +1,132 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package featuregate sets the feature gates of the agent, which turn the
+// features still in development on or off without a new release. The gates
+// are registered in the registry of the collector, so the gates of the
+// upstream components can be set the same way.
+package featuregate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/featuregate"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+)
+
+// Load sets the gates of the registry from the file at path, e.g. written by
+// a remote configuration to roll out a feature, then from the
+// CWAGENT_FEATURE_GATES environment variable set with agent.feature_gates,
+// so that the local configuration takes precedence. A missing file is
+// ignored, and the gates that are unknown or cannot be set are logged and
+// skipped. It must be called before the components are created.
+func Load(registry *featuregate.Registry, path string) error {
+	gates, err := readFile(path)
+	if err != nil {
+		return err
+	}
+	for _, id := range sortedKeys(gates) {
+		set(registry, id, gates[id])
+	}
+	gates, err = Parse(os.Getenv(envconfig.CWAGENT_FEATURE_GATES))
+	if err != nil {
+		return err
+	}
+	for _, id := range sortedKeys(gates) {
+		set(registry, id, gates[id])
+	}
+	return nil
+}
+
+// Parse parses a comma-separated list of gates in the format of the
+// --feature-gates flag of the collector, where "+id" or "id" enables the gate
+// and "-id" disables it.
+func Parse(value string) (map[string]bool, error) {
+	gates := make(map[string]bool)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		enabled := true
+		switch {
+		case item == "":
+			continue
+		case item[0] == '-':
+			enabled = false
+			item = item[1:]
+		case item[0] == '+':
+			item = item[1:]
+		}
+		if item == "" {
+			return nil, fmt.Errorf("invalid feature gate in %q", value)
+		}
+		gates[item] = enabled
+	}
+	return gates, nil
+}
+
+// Format formats the gates in the format read by Parse, sorted by ID.
+func Format(gates map[string]bool) string {
+	items := make([]string, 0, len(gates))
+	for _, id := range sortedKeys(gates) {
+		if gates[id] {
+			items = append(items, "+"+id)
+		} else {
+			items = append(items, "-"+id)
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// Active returns the gates of the registry that are not in their default
+// state, i.e. the enabled alpha gates and the disabled beta gates.
+func Active(registry *featuregate.Registry) map[string]bool {
+	active := make(map[string]bool)
+	registry.VisitAll(func(gate *featuregate.Gate) {
+		switch {
+		case gate.Stage() == featuregate.StageAlpha && gate.IsEnabled():
+			active[gate.ID()] = true
+		case gate.Stage() == featuregate.StageBeta && !gate.IsEnabled():
+			active[gate.ID()] = false
+		}
+	})
+	return active
+}
+
+func readFile(path string) (map[string]bool, error) {
+	if path == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var gates map[string]bool
+	if err = json.Unmarshal(content, &gates); err != nil {
+		return nil, fmt.Errorf("invalid feature gates file %s: %w", path, err)
+	}
+	return gates, nil
+}
+
+func set(registry *featuregate.Registry, id string, enabled bool) {
+	if err := registry.Set(id, enabled); err != nil {
+		log.Printf("W! Ignoring the feature gate %s: %v", id, err)
+	}
+}
+
+func sortedKeys(gates map[string]bool) []string {
+	ids := make([]string, 0, len(gates))
+	for id := range gates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
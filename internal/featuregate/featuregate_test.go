@@ -0,0 +1,109 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package featuregate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/featuregate"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+)
+
+func newTestRegistry() *featuregate.Registry {
+	registry := featuregate.NewRegistry()
+	registry.MustRegister("cwagent.alpha", featuregate.StageAlpha)
+	registry.MustRegister("cwagent.beta", featuregate.StageBeta)
+	registry.MustRegister("cwagent.other", featuregate.StageAlpha)
+	registry.MustRegister("cwagent.stable", featuregate.StageStable, featuregate.WithRegisterToVersion("v2.0.0"))
+	return registry
+}
+
+func isEnabled(registry *featuregate.Registry) map[string]bool {
+	enabled := make(map[string]bool)
+	registry.VisitAll(func(gate *featuregate.Gate) {
+		enabled[gate.ID()] = gate.IsEnabled()
+	})
+	return enabled
+}
+
+func TestLoad(t *testing.T) {
+	testCases := map[string]struct {
+		file    string
+		env     string
+		want    map[string]bool
+		wantErr bool
+	}{
+		"WithDefaults": {
+			want: map[string]bool{"cwagent.alpha": false, "cwagent.beta": true, "cwagent.other": false, "cwagent.stable": true},
+		},
+		"WithFile": {
+			file: `{"cwagent.alpha": true, "cwagent.beta": false}`,
+			want: map[string]bool{"cwagent.alpha": true, "cwagent.beta": false, "cwagent.other": false, "cwagent.stable": true},
+		},
+		"WithEnv": {
+			env:  "+cwagent.alpha,cwagent.other",
+			want: map[string]bool{"cwagent.alpha": true, "cwagent.beta": true, "cwagent.other": true, "cwagent.stable": true},
+		},
+		"WithEnvOverridingFile": {
+			file: `{"cwagent.alpha": true, "cwagent.beta": false}`,
+			env:  "-cwagent.alpha",
+			want: map[string]bool{"cwagent.alpha": false, "cwagent.beta": false, "cwagent.other": false, "cwagent.stable": true},
+		},
+		"WithUnknownAndStableGates": {
+			file: `{"cwagent.unknown": true, "cwagent.stable": false}`,
+			env:  "cwagent.alpha",
+			want: map[string]bool{"cwagent.alpha": true, "cwagent.beta": true, "cwagent.other": false, "cwagent.stable": true},
+		},
+		"WithInvalidFile": {
+			file:    `["cwagent.alpha"]`,
+			wantErr: true,
+		},
+		"WithInvalidEnv": {
+			env:     "+",
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "feature-gates.json")
+			if testCase.file != "" {
+				require.NoError(t, os.WriteFile(path, []byte(testCase.file), 0600))
+			}
+			t.Setenv(envconfig.CWAGENT_FEATURE_GATES, testCase.env)
+			registry := newTestRegistry()
+			err := Load(registry, path)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, isEnabled(registry))
+		})
+	}
+}
+
+func TestParseAndFormat(t *testing.T) {
+	gates, err := Parse(" +cwagent.alpha, -cwagent.beta ,cwagent.other,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"cwagent.alpha": true, "cwagent.beta": false, "cwagent.other": true}, gates)
+	assert.Equal(t, "+cwagent.alpha,-cwagent.beta,+cwagent.other", Format(gates))
+
+	gates, err = Parse("")
+	require.NoError(t, err)
+	assert.Empty(t, gates)
+	assert.Equal(t, "", Format(gates))
+}
+
+func TestActive(t *testing.T) {
+	registry := newTestRegistry()
+	assert.Empty(t, Active(registry))
+	require.NoError(t, registry.Set("cwagent.alpha", true))
+	require.NoError(t, registry.Set("cwagent.beta", false))
+	assert.Equal(t, map[string]bool{"cwagent.alpha": true, "cwagent.beta": false}, Active(registry))
+}
@@ -0,0 +1,261 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package filestate persists the offsets of the tailed files in a single
+// state file.
+//
+// Each line of the state file is an entry prefixed by the CRC-32 of the entry.
+// The file is rewritten from the entries in memory on every flush, so it only
+// holds the live entries, and it is replaced atomically by renaming a
+// temporary file. A corrupted line only loses the offset of its file.
+package filestate
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/atomicfile"
+)
+
+const (
+	fileMode = 0644
+	// maxLineSize is the longest line read from the state file.
+	maxLineSize = 64 * 1024
+)
+
+// entry is the state of a file. Missing is when the file was first found
+// missing by a compaction.
+type entry struct {
+	File    string    `json:"file"`
+	Offset  int64     `json:"offset"`
+	Missing time.Time `json:"missing,omitempty"`
+}
+
+// Store is the state of the tailed files, keyed by the file name.
+type Store struct {
+	path string
+	// ttl is how long the entry of a missing file is kept.
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	dirty   bool
+	// writeMu serializes the flushes.
+	writeMu sync.Mutex
+}
+
+// New creates an empty store saved to the path. Load reads the entries
+// previously saved.
+func New(path string, ttl time.Duration) *Store {
+	return &Store{path: path, ttl: ttl, entries: make(map[string]*entry)}
+}
+
+// Path is the state file of the store.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// IsStoreFile returns true if the path is the state file or its temporary
+// file.
+func (s *Store) IsStoreFile(path string) bool {
+	return path == s.path || atomicfile.IsTempFile(path, s.path)
+}
+
+// Load reads the entries of the state file. The corrupted lines are skipped
+// and the file is rewritten without them on the next flush. A missing state
+// file is not an error.
+func (s *Store) Load() error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	entries := make(map[string]*entry)
+	var corrupted int
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		e, err := decode(line)
+		if err != nil {
+			corrupted++
+			continue
+		}
+		entries[e.File] = e
+	}
+	if err = scanner.Err(); err != nil {
+		// the rest of the file cannot be split into lines
+		corrupted++
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = entries
+	if corrupted > 0 {
+		log.Printf("W! [filestate] Skipped %d corrupted entries of the state file %s, the files of these entries are read from the start or the end", corrupted, s.path)
+		s.dirty = true
+	}
+	return nil
+}
+
+// Get returns the saved offset of the file.
+func (s *Store) Get(file string) (int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[file]
+	if !ok {
+		return 0, false
+	}
+	return e.Offset, true
+}
+
+// Set updates the offset of the file. It is saved by the next flush.
+func (s *Store) Set(file string, offset int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[file]; ok && e.Offset == offset && e.Missing.IsZero() {
+		return
+	}
+	s.entries[file] = &entry{File: file, Offset: offset}
+	s.dirty = true
+}
+
+// Delete removes the entry of the file.
+func (s *Store) Delete(file string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[file]; ok {
+		delete(s.entries, file)
+		s.dirty = true
+	}
+}
+
+// Compact removes the entries of the files missing for longer than the TTL.
+func (s *Store) Compact(now time.Time) {
+	s.mu.Lock()
+	files := make([]string, 0, len(s.entries))
+	for file := range s.entries {
+		files = append(files, file)
+	}
+	s.mu.Unlock()
+
+	// the files are checked without the lock since the Stat can be slow on
+	// network file systems
+	exists := make(map[string]bool, len(files))
+	for _, file := range files {
+		_, err := os.Stat(file)
+		exists[file] = err == nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for file, found := range exists {
+		e, ok := s.entries[file]
+		switch {
+		case !ok:
+		case found:
+			if !e.Missing.IsZero() {
+				e.Missing = time.Time{}
+				s.dirty = true
+			}
+		case e.Missing.IsZero():
+			e.Missing = now
+			s.dirty = true
+		case now.Sub(e.Missing) >= s.ttl:
+			delete(s.entries, file)
+			s.dirty = true
+		}
+	}
+}
+
+// Flush writes the entries to the state file if they changed since the last
+// flush.
+func (s *Store) Flush() error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.mu.Lock()
+	if !s.dirty {
+		s.mu.Unlock()
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, e := range s.sorted() {
+		if err := encode(&buf, e); err != nil {
+			s.mu.Unlock()
+			return err
+		}
+	}
+	s.dirty = false
+	s.mu.Unlock()
+
+	if err := atomicfile.WriteFile(s.path, buf.Bytes(), fileMode); err != nil {
+		s.mu.Lock()
+		s.dirty = true
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// sorted returns the entries by file name so that the state file is stable.
+func (s *Store) sorted() []*entry {
+	entries := make([]*entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].File < entries[j].File
+	})
+	return entries
+}
+
+func encode(buf *bytes.Buffer, e *entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(buf, "%08x ", crc32.ChecksumIEEE(b))
+	buf.Write(b)
+	buf.WriteByte('\n')
+	return nil
+}
+
+func decode(line []byte) (*entry, error) {
+	checksum, b, ok := bytes.Cut(line, []byte(" "))
+	if !ok {
+		return nil, errors.New("missing checksum")
+	}
+	expected, err := strconv.ParseUint(string(checksum), 16, 32)
+	if err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(b) != uint32(expected) {
+		return nil, errors.New("checksum mismatch")
+	}
+	var e entry
+	if err = json.Unmarshal(b, &e); err != nil {
+		return nil, err
+	}
+	if e.File == "" || e.Offset < 0 {
+		return nil, fmt.Errorf("invalid entry %s", b)
+	}
+	return &e, nil
+}
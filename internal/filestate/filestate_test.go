@@ -0,0 +1,156 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package filestate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state")
+	s := New(path, time.Hour)
+	require.NoError(t, s.Load())
+	_, ok := s.Get("/var/log/a.log")
+	assert.False(t, ok)
+
+	s.Set("/var/log/a.log", 10)
+	s.Set("/var/log/b.log", 20)
+	s.Set("/var/log/a.log", 30)
+	s.Delete("/var/log/b.log")
+	require.NoError(t, s.Flush())
+
+	loaded := New(path, time.Hour)
+	require.NoError(t, loaded.Load())
+	offset, ok := loaded.Get("/var/log/a.log")
+	assert.True(t, ok)
+	assert.EqualValues(t, 30, offset)
+	_, ok = loaded.Get("/var/log/b.log")
+	assert.False(t, ok)
+
+	// the file is not rewritten without changes
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, s.Flush())
+	assert.NoFileExists(t, path)
+	s.Set("/var/log/a.log", 30)
+	require.NoError(t, s.Flush())
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	assert.True(t, s.IsStoreFile(path))
+	assert.True(t, s.IsStoreFile(filepath.Join(dir, ".state.12345.tmp")))
+	assert.False(t, s.IsStoreFile(filepath.Join(dir, "other")))
+}
+
+func TestStoreLoadCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	s := New(path, time.Hour)
+	s.Set("/var/log/a.log", 10)
+	s.Set("/var/log/b.log", 20)
+	s.Set("/var/log/c.log", 30)
+	require.NoError(t, s.Flush())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(string(content), "\n")
+	require.Len(t, lines, 4)
+	// flip the offset of b without updating the checksum, and add a line
+	// without a checksum and a partially written line
+	lines[1] = strings.Replace(lines[1], "20", "21", 1)
+	corrupted := strings.Join([]string{lines[0], lines[1], `{"file":"/var/log/d.log","offset":1}`, lines[2], lines[2][:20]}, "\n")
+	require.NoError(t, os.WriteFile(path, []byte(corrupted), 0644))
+
+	loaded := New(path, time.Hour)
+	require.NoError(t, loaded.Load())
+	offset, ok := loaded.Get("/var/log/a.log")
+	assert.True(t, ok)
+	assert.EqualValues(t, 10, offset)
+	_, ok = loaded.Get("/var/log/b.log")
+	assert.False(t, ok)
+	_, ok = loaded.Get("/var/log/d.log")
+	assert.False(t, ok)
+	offset, ok = loaded.Get("/var/log/c.log")
+	assert.True(t, ok)
+	assert.EqualValues(t, 30, offset)
+
+	// the corrupted lines are dropped by the next flush
+	require.NoError(t, loaded.Flush())
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, lines[0]+"\n"+lines[2]+"\n", string(content))
+}
+
+func TestStoreLoadMissing(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "state"), time.Hour)
+	assert.NoError(t, s.Load())
+	assert.NoError(t, s.Flush())
+}
+
+func TestStoreCompact(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.log")
+	require.NoError(t, os.WriteFile(existing, nil, 0644))
+	missing := filepath.Join(dir, "missing.log")
+	path := filepath.Join(dir, "state")
+
+	s := New(path, time.Hour)
+	s.Set(existing, 1)
+	s.Set(missing, 2)
+	now := time.Now()
+	s.Compact(now)
+	s.Compact(now.Add(30 * time.Minute))
+	_, ok := s.Get(missing)
+	assert.True(t, ok, "the entry of a missing file is kept for the TTL")
+
+	// the time the file went missing is saved
+	require.NoError(t, s.Flush())
+	loaded := New(path, time.Hour)
+	require.NoError(t, loaded.Load())
+	loaded.Compact(now.Add(time.Hour))
+	_, ok = loaded.Get(missing)
+	assert.False(t, ok)
+	_, ok = loaded.Get(existing)
+	assert.True(t, ok)
+
+	// a file found again restarts the TTL
+	s.Compact(now.Add(45 * time.Minute))
+	require.NoError(t, os.WriteFile(missing, nil, 0644))
+	s.Compact(now.Add(50 * time.Minute))
+	require.NoError(t, os.Remove(missing))
+	s.Compact(now.Add(2 * time.Hour))
+	_, ok = s.Get(missing)
+	assert.True(t, ok)
+}
+
+func TestStoreFlushError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state")
+	s := New(path, time.Hour)
+	s.Set("/var/log/a.log", 1)
+	require.NoError(t, s.Flush())
+
+	// the temporary file cannot be created while the folder is missing
+	require.NoError(t, os.Rename(dir, dir+".moved"))
+	s.Set("/var/log/a.log", 2)
+	assert.Error(t, s.Flush())
+	require.NoError(t, os.Rename(dir+".moved", dir))
+
+	// the previous content is intact and the entries are written again
+	loaded := New(path, time.Hour)
+	require.NoError(t, loaded.Load())
+	offset, _ := loaded.Get("/var/log/a.log")
+	assert.EqualValues(t, 1, offset)
+	require.NoError(t, s.Flush())
+	require.NoError(t, loaded.Load())
+	offset, _ = loaded.Get("/var/log/a.log")
+	assert.EqualValues(t, 2, offset)
+}
@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package handover hands the statsd listener over to the upgraded agent process.
+//
+// The running agent serves a unix socket. When asked to upgrade, it starts a
+// new agent process through the start wrapper, so that the config is translated
+// again by the upgraded translator, with EnvSocket set to the path of that
+// socket. The environment is passed on when the wrapper execs the agent. The new
+// process connects, receives the file descriptors of the registered listeners
+// (SCM_RIGHTS), and waits for the old process to shut down. The old process
+// flushes its state (e.g. the log tailer offsets in the file state folder)
+// during shutdown and signals when it is done. Since the listening sockets are
+// never closed, packets are queued by the kernel instead of being dropped while
+// the processes switch over.
+//
+// Only the UDP listener of the statsd input is handed over, so this is not a
+// zero-downtime upgrade. The OTLP receivers open their own sockets inside the
+// OTEL collector, which cannot be passed a listener, so connections to them are
+// still refused until the new agent process has started its pipelines.
+package handover
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// EnvSocket is set on the new agent process to the path of the socket
+	// served by the process being replaced.
+	EnvSocket = "CWAGENT_HANDOVER_SOCKET"
+
+	// DefaultTimeout is the maximum time to wait for each step of the handover.
+	DefaultTimeout = 2 * time.Minute
+
+	protocolVersion = 1
+	// maxFiles limits the number of file descriptors passed in a single handover.
+	maxFiles = 64
+
+	requestMessage = "HANDOVER"
+	readyMessage   = "READY"
+	doneMessage    = "DONE"
+)
+
+var (
+	ErrUnsupported = errors.New("handover is not supported on this platform")
+	errTooManyFile = errors.New("too many files to hand over")
+)
+
+// header is sent along with the file descriptors. Names[i] identifies the
+// i-th file descriptor.
+type header struct {
+	Version int      `json:"version"`
+	Names   []string `json:"names"`
+}
+
+// listenerName is used to match a listener between processes.
+func listenerName(network, address string) string {
+	return network + "://" + address
+}
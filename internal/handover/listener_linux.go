@@ -0,0 +1,117 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package handover
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"sync"
+)
+
+// registry keeps a duplicate of each listening socket for the lifetime of the
+// process so that it survives plugin restarts (e.g. config reloads) and can be
+// passed to the next agent process.
+type registry struct {
+	mu sync.Mutex
+	// inherited are the files received from the previous agent process that
+	// have not been claimed yet.
+	inherited map[string]*os.File
+	// active are the files for the listeners opened by this process.
+	active map[string]*os.File
+}
+
+var listeners = newRegistry()
+
+func newRegistry() *registry {
+	return &registry{
+		inherited: make(map[string]*os.File),
+		active:    make(map[string]*os.File),
+	}
+}
+
+// ListenUDP is a replacement for net.ListenUDP that reuses the socket from a
+// previous agent process or config reload if one exists for the address.
+func ListenUDP(network, address string) (*net.UDPConn, error) {
+	return listeners.listenUDP(network, address)
+}
+
+func (r *registry) listenUDP(network, address string) (*net.UDPConn, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	name := listenerName(network, address)
+	file, ok := r.active[name]
+	if !ok {
+		if file, ok = r.inherited[name]; ok {
+			delete(r.inherited, name)
+			r.active[name] = file
+		}
+	}
+	if ok {
+		pc, err := net.FilePacketConn(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to reuse socket for %s: %w", name, err)
+		}
+		conn, isUDP := pc.(*net.UDPConn)
+		if !isUDP {
+			_ = pc.Close()
+			return nil, fmt.Errorf("socket for %s is not a UDP socket", name)
+		}
+		return conn, nil
+	}
+	addr, err := net.ResolveUDPAddr(network, address)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	if file, err = conn.File(); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	r.active[name] = file
+	return conn, nil
+}
+
+// inherit adds the files received from the previous agent process.
+func (r *registry) inherit(names []string, files []*os.File) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, name := range names {
+		if existing, ok := r.inherited[name]; ok {
+			_ = existing.Close()
+		}
+		r.inherited[name] = files[i]
+	}
+}
+
+// files returns the names and files of all sockets that should be handed over.
+// Inherited sockets that were never claimed are passed along as well in case
+// the next agent process still needs them.
+func (r *registry) files() ([]string, []*os.File) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := make(map[string]*os.File, len(r.active)+len(r.inherited))
+	for name, file := range r.inherited {
+		all[name] = file
+	}
+	for name, file := range r.active {
+		all[name] = file
+	}
+	names := make([]string, 0, len(all))
+	for name := range all {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	files := make([]*os.File, len(names))
+	for i, name := range names {
+		files[i] = all[name]
+	}
+	return names, files
+}
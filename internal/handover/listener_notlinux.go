@@ -0,0 +1,18 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+
+package handover
+
+import "net"
+
+// ListenUDP is a passthrough to net.ListenUDP since sockets cannot be handed
+// over on this platform.
+func ListenUDP(network, address string) (*net.UDPConn, error) {
+	addr, err := net.ResolveUDPAddr(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return net.ListenUDP(network, addr)
+}
@@ -0,0 +1,269 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package handover
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Server accepts the handover request from the next agent process.
+type Server struct {
+	path     string
+	listener *net.UnixListener
+	timeout  time.Duration
+	registry *registry
+	// startSuccessor starts the next agent process with the given environment
+	// variable and returns a channel that receives when the process exits.
+	startSuccessor func(env string) (<-chan error, error)
+}
+
+// Session is an in-progress handover. The files have been passed to the next
+// agent process, which waits for Complete before starting.
+type Session struct {
+	conn *net.UnixConn
+}
+
+// NewServer listens on the unix socket at path. A stale socket file left
+// behind by a previous process is replaced. The successor is the binary started
+// as the next agent process.
+func NewServer(path, successor string) (*Server, error) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	// the next agent process replaces the socket file, so it must not be removed on close
+	listener.SetUnlinkOnClose(false)
+	// the socket hands out the agent's listeners, so restrict it to the agent's user
+	if err = os.Chmod(path, 0600); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	return &Server{
+		path:     path,
+		listener: listener,
+		timeout:  DefaultTimeout,
+		registry: listeners,
+		startSuccessor: func(env string) (<-chan error, error) {
+			return start(successor, env)
+		},
+	}, nil
+}
+
+// Handover starts the next agent process and passes the listeners to it. On
+// success, the caller must shut down and call Session.Complete once the state
+// has been flushed. On failure, the caller should keep running.
+func (s *Server) Handover() (*Session, error) {
+	exited, err := s.startSuccessor(EnvSocket + "=" + s.path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start new agent process: %w", err)
+	}
+	accepted := make(chan *net.UnixConn, 1)
+	acceptErr := make(chan error, 1)
+	go func() {
+		_ = s.listener.SetDeadline(time.Now().Add(s.timeout))
+		conn, err := s.listener.AcceptUnix()
+		if err != nil {
+			acceptErr <- err
+			return
+		}
+		accepted <- conn
+	}()
+	var conn *net.UnixConn
+	select {
+	case conn = <-accepted:
+	case err = <-acceptErr:
+		return nil, fmt.Errorf("new agent process did not connect: %w", err)
+	case err = <-exited:
+		// unblock the pending accept
+		_ = s.listener.SetDeadline(time.Now())
+		return nil, fmt.Errorf("new agent process exited before handover: %v", err)
+	}
+	if err = s.send(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return &Session{conn: conn}, nil
+}
+
+func (s *Server) send(conn *net.UnixConn) error {
+	_ = conn.SetDeadline(time.Now().Add(s.timeout))
+	reader := bufio.NewReader(conn)
+	if err := expect(reader, requestMessage); err != nil {
+		return err
+	}
+	names, files := s.registry.files()
+	if len(files) > maxFiles {
+		return errTooManyFile
+	}
+	content, err := json.Marshal(header{Version: protocolVersion, Names: names})
+	if err != nil {
+		return err
+	}
+	fds := make([]int, len(files))
+	for i, file := range files {
+		fds[i] = int(file.Fd())
+	}
+	var oob []byte
+	if len(fds) > 0 {
+		oob = syscall.UnixRights(fds...)
+	}
+	if _, _, err = conn.WriteMsgUnix(content, oob, nil); err != nil {
+		return err
+	}
+	if err = expect(reader, readyMessage); err != nil {
+		return err
+	}
+	// the next agent process is now responsible for the listeners
+	_ = conn.SetDeadline(time.Time{})
+	return nil
+}
+
+// Close stops accepting handover requests.
+func (s *Server) Close() error {
+	return s.listener.Close()
+}
+
+// Complete notifies the next agent process that the state has been flushed
+// and it can start.
+func (s *Session) Complete() error {
+	defer s.conn.Close()
+	_, err := s.conn.Write([]byte(doneMessage + "\n"))
+	return err
+}
+
+// Receive takes over the listeners from the agent process serving the socket
+// at path. It blocks until the previous process has completed its shutdown.
+func Receive(path string, timeout time.Duration) error {
+	return receive(path, timeout, listeners)
+}
+
+func receive(path string, timeout time.Duration, r *registry) error {
+	conn, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	if _, err = conn.Write([]byte(requestMessage + "\n")); err != nil {
+		return err
+	}
+	buf := make([]byte, 64*1024)
+	oob := make([]byte, syscall.CmsgSpace(4*maxFiles))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		return err
+	}
+	files, err := parseRights(oob[:oobn])
+	if err != nil {
+		return err
+	}
+	var h header
+	if err = json.Unmarshal(buf[:n], &h); err != nil {
+		closeAll(files)
+		return fmt.Errorf("invalid handover header: %w", err)
+	}
+	if h.Version != protocolVersion || len(h.Names) != len(files) {
+		closeAll(files)
+		return fmt.Errorf("unsupported handover (version: %d, names: %d, files: %d)", h.Version, len(h.Names), len(files))
+	}
+	r.inherit(h.Names, files)
+	if _, err = conn.Write([]byte(readyMessage + "\n")); err != nil {
+		return err
+	}
+	// take over as the main process of the service before the previous one exits
+	if err = notify(fmt.Sprintf("MAINPID=%d", os.Getpid())); err != nil {
+		return fmt.Errorf("unable to notify service manager: %w", err)
+	}
+	return expect(bufio.NewReader(conn), doneMessage)
+}
+
+func parseRights(oob []byte) ([]*os.File, error) {
+	if len(oob) == 0 {
+		return nil, nil
+	}
+	messages, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return nil, err
+	}
+	var files []*os.File
+	for i := range messages {
+		fds, err := syscall.ParseUnixRights(&messages[i])
+		if err != nil {
+			closeAll(files)
+			return nil, err
+		}
+		for _, fd := range fds {
+			files = append(files, os.NewFile(uintptr(fd), "handover"))
+		}
+	}
+	return files, nil
+}
+
+func closeAll(files []*os.File) {
+	for _, file := range files {
+		_ = file.Close()
+	}
+}
+
+func expect(reader *bufio.Reader, message string) error {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if got := strings.TrimSpace(line); got != message {
+		return fmt.Errorf("unexpected handover message: %q", got)
+	}
+	return nil
+}
+
+// start runs the binary with the environment of the current process and the
+// additional environment variable.
+func start(name string, env string) (<-chan error, error) {
+	cmd := exec.Command(name)
+	cmd.Env = append(os.Environ(), env)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	return exited, nil
+}
+
+// notify sends the state to the service manager if the process is managed by
+// systemd. See sd_notify(3).
+func notify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+	if strings.HasPrefix(socket, "@") {
+		// abstract namespace socket
+		socket = "\x00" + socket[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
@@ -0,0 +1,148 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package handover
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, r *registry) *Server {
+	t.Helper()
+	s, err := NewServer(filepath.Join(t.TempDir(), "handover.sock"), "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	s.registry = r
+	s.timeout = 5 * time.Second
+	return s
+}
+
+func TestHandover(t *testing.T) {
+	oldRegistry := newRegistry()
+	oldConn, err := oldRegistry.listenUDP("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	// the plugin closing its connection does not close the registered socket
+	require.NoError(t, oldConn.Close())
+
+	newRegistry := newRegistry()
+	received := make(chan error, 1)
+	s := newTestServer(t, oldRegistry)
+	s.startSuccessor = func(env string) (<-chan error, error) {
+		path, ok := strings.CutPrefix(env, EnvSocket+"=")
+		require.True(t, ok)
+		go func() {
+			received <- receive(path, 5*time.Second, newRegistry)
+		}()
+		return make(chan error), nil
+	}
+
+	session, err := s.Handover()
+	require.NoError(t, err)
+	select {
+	case err = <-received:
+		t.Fatalf("receive returned before the handover was completed: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	require.NoError(t, session.Complete())
+	require.NoError(t, <-received)
+
+	names, _ := newRegistry.files()
+	assert.Equal(t, []string{"udp://127.0.0.1:0"}, names)
+	newConn, err := newRegistry.listenUDP("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer newConn.Close()
+
+	client, err := net.DialUDP("udp", nil, newConn.LocalAddr().(*net.UDPAddr))
+	require.NoError(t, err)
+	defer client.Close()
+	_, err = client.Write([]byte("metric:1|c"))
+	require.NoError(t, err)
+	require.NoError(t, newConn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, 64)
+	n, err := newConn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "metric:1|c", string(buf[:n]))
+}
+
+func TestHandoverSuccessorExited(t *testing.T) {
+	s := newTestServer(t, newRegistry())
+	s.startSuccessor = func(string) (<-chan error, error) {
+		exited := make(chan error, 1)
+		exited <- errors.New("exit status 1")
+		return exited, nil
+	}
+	_, err := s.Handover()
+	assert.ErrorContains(t, err, "exited before handover")
+
+	s.startSuccessor = func(string) (<-chan error, error) {
+		return nil, errors.New("not found")
+	}
+	_, err = s.Handover()
+	assert.ErrorContains(t, err, "unable to start")
+}
+
+func TestStartSuccessor(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "env")
+	wrapper := filepath.Join(dir, "start-amazon-cloudwatch-agent")
+	script := fmt.Sprintf("#!/bin/sh\necho \"$%s\" > %s\n", EnvSocket, out)
+	require.NoError(t, os.WriteFile(wrapper, []byte(script), 0700))
+
+	s, err := NewServer(filepath.Join(dir, "handover.sock"), wrapper)
+	require.NoError(t, err)
+	defer s.Close()
+	// the start wrapper is run with the socket path in its environment
+	exited, err := s.startSuccessor(EnvSocket + "=" + s.path)
+	require.NoError(t, err)
+	require.NoError(t, <-exited)
+	content, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, s.path, strings.TrimSpace(string(content)))
+}
+
+func TestListenUDPReuse(t *testing.T) {
+	r := newRegistry()
+	first, err := r.listenUDP("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := first.LocalAddr().(*net.UDPAddr).Port
+	require.NoError(t, first.Close())
+
+	// a config reload gets the same socket back
+	second, err := r.listenUDP("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer second.Close()
+	assert.Equal(t, port, second.LocalAddr().(*net.UDPAddr).Port)
+
+	_, err = r.listenUDP("udp", "invalid")
+	assert.Error(t, err)
+}
+
+func TestNotify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	t.Setenv("NOTIFY_SOCKET", "")
+	assert.NoError(t, notify("READY=1"))
+
+	t.Setenv("NOTIFY_SOCKET", path)
+	require.NoError(t, notify(fmt.Sprintf("MAINPID=%d", os.Getpid())))
+	buf := make([]byte, 64)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("MAINPID=%d", os.Getpid()), string(buf[:n]))
+}
@@ -0,0 +1,34 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package harness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	c := NewClock(start)
+	assert.Equal(t, start, c.Now())
+
+	short := c.After(time.Second)
+	long := c.After(time.Minute)
+	c.Advance(500 * time.Millisecond)
+	assert.Len(t, short, 0)
+	c.Advance(500 * time.Millisecond)
+	assert.Equal(t, start.Add(time.Second), <-short)
+	assert.Len(t, long, 0)
+	assert.Equal(t, time.Second, c.Since(start))
+
+	// the clock does not go backward
+	c.Set(start)
+	assert.Equal(t, start.Add(time.Second), c.Now())
+	c.Set(start.Add(time.Hour))
+	assert.Equal(t, start.Add(time.Hour), <-long)
+
+	assert.Len(t, c.After(0), 1)
+}
@@ -0,0 +1,49 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package harness runs the agent pipelines in-process for regression tests
+// that must not reach AWS. It provides fake CloudWatch and CloudWatch Logs
+// backends recording the requests they receive, a clock the test controls and
+// golden file assertions.
+//
+// The golden files are rewritten instead of compared when the UPDATE_GOLDEN
+// environment variable is set, e.g.
+//
+//	UPDATE_GOLDEN=1 go test ./plugins/outputs/cloudwatchlogs/...
+package harness
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// UpdateGoldenEnv is the environment variable that rewrites the golden files.
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// Offline makes the SDK credential chain and the region lookup resolve
+// without any network access for the duration of the test.
+func Offline(t *testing.T) {
+	t.Helper()
+	t.Setenv("AWS_ACCESS_KEY_ID", AccessKey)
+	t.Setenv("AWS_SECRET_ACCESS_KEY", SecretKey)
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	t.Setenv("AWS_REGION", Region)
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+}
+
+// AssertGolden compares got with the content of the golden file.
+func AssertGolden(t *testing.T, path string, got []byte) {
+	t.Helper()
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, got, 0644))
+		return
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "missing golden file, run the test with %s=1 to create it", UpdateGoldenEnv)
+	assert.Equal(t, string(want), string(got), "%s differs, run the test with %s=1 to update it", path, UpdateGoldenEnv)
+}
@@ -0,0 +1,326 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package harness
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+const (
+	// AccessKey and SecretKey are the static credentials of Config.
+	AccessKey = "AKIDHARNESS"
+	SecretKey = "harness-secret-key"
+	// Region is the region of Config.
+	Region = "us-east-1"
+	// RequestID is returned in every response.
+	RequestID = "00000000-0000-0000-0000-000000000000"
+
+	scrubbed = "<scrubbed>"
+
+	headerTarget          = "X-Amz-Target"
+	headerContentEncoding = "Content-Encoding"
+	cloudWatchXMLNS       = "http://monitoring.amazonaws.com/doc/2010-08-01/"
+)
+
+type protocol int
+
+const (
+	// protocolQuery is the form encoded protocol of CloudWatch.
+	protocolQuery protocol = iota
+	// protocolJSON is the JSON RPC protocol of CloudWatch Logs.
+	protocolJSON
+)
+
+// Request is a request received by a fake backend.
+type Request struct {
+	// Operation is the API name, e.g. PutLogEvents.
+	Operation string
+	Header    http.Header
+	// Body is the payload, decompressed if the client compressed it.
+	Body []byte
+}
+
+// Failure is an error response returned by a fake backend.
+type Failure struct {
+	StatusCode int
+	// Code is the AWS error code, e.g. ThrottlingException.
+	Code    string
+	Message string
+}
+
+// Server is a fake AWS backend. It records the requests it receives and
+// returns an empty successful response unless a failure or a response was set
+// for the operation.
+type Server struct {
+	// URL is the endpoint to set on the clients.
+	URL string
+
+	t        testing.TB
+	server   *httptest.Server
+	protocol protocol
+
+	mu        sync.Mutex
+	requests  []Request
+	failures  map[string][]Failure
+	responses map[string]string
+	scrub     map[string]struct{}
+}
+
+// NewCloudWatch starts a fake CloudWatch backend that is closed with the test.
+func NewCloudWatch(t testing.TB) *Server {
+	return newServer(t, protocolQuery)
+}
+
+// NewLogs starts a fake CloudWatch Logs backend that is closed with the test.
+func NewLogs(t testing.TB) *Server {
+	return newServer(t, protocolJSON)
+}
+
+func newServer(t testing.TB, p protocol) *Server {
+	s := &Server{
+		t:         t,
+		protocol:  p,
+		failures:  make(map[string][]Failure),
+		responses: make(map[string]string),
+		scrub:     make(map[string]struct{}),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = s.server.URL
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// Config returns the SDK config of a client sending its requests to the
+// server with static credentials.
+func (s *Server) Config() *aws.Config {
+	return &aws.Config{
+		Region:      aws.String(Region),
+		Endpoint:    aws.String(s.URL),
+		Credentials: credentials.NewStaticCredentials(AccessKey, SecretKey, ""),
+	}
+}
+
+// Fail queues a failure for the next request of the operation. Several
+// failures are returned in the order they were queued.
+func (s *Server) Fail(operation string, f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[operation] = append(s.failures[operation], f)
+}
+
+// Respond sets the body of the successful responses to the operation.
+func (s *Server) Respond(operation, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[operation] = body
+}
+
+// Scrub replaces the values of the keys in the dumped requests, e.g. the
+// timestamp of the log events. For CloudWatch, the key is the last part of
+// the parameter name, e.g. Timestamp for MetricData.member.1.Timestamp.
+func (s *Server) Scrub(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		s.scrub[key] = struct{}{}
+	}
+}
+
+// Requests returns the requests received for the operations, or all of them
+// if none is given.
+func (s *Server) Requests(operations ...string) []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var requests []Request
+	for _, r := range s.requests {
+		if len(operations) == 0 || contains(operations, r.Operation) {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+// Reset forgets the received requests and the queued failures.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.failures = make(map[string][]Failure)
+}
+
+// Dump returns the requests received for the operations in a stable text
+// format for golden files. The payloads are normalized and the volatile
+// headers, e.g. the signature, are left out.
+func (s *Server) Dump(operations ...string) []byte {
+	var buf bytes.Buffer
+	for _, r := range s.Requests(operations...) {
+		fmt.Fprintf(&buf, "### %s\n", r.Operation)
+		body, err := s.normalize(r.Body)
+		if err != nil {
+			s.t.Errorf("unable to normalize %s request: %v", r.Operation, err)
+			body = r.Body
+		}
+		buf.Write(body)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(r)
+	if err != nil {
+		s.t.Errorf("unable to read request: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	operation := s.operation(r, body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Operation: operation, Header: r.Header.Clone(), Body: body})
+	var failure *Failure
+	if queued := s.failures[operation]; len(queued) > 0 {
+		failure = &queued[0]
+		s.failures[operation] = queued[1:]
+	}
+	response, ok := s.responses[operation]
+	s.mu.Unlock()
+
+	w.Header().Set("X-Amzn-Requestid", RequestID)
+	if failure != nil {
+		s.writeFailure(w, operation, *failure)
+		return
+	}
+	switch s.protocol {
+	case protocolJSON:
+		if !ok {
+			response = "{}"
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	default:
+		if !ok {
+			response = fmt.Sprintf(`<%[1]sResponse xmlns=%[2]q><ResponseMetadata><RequestId>%[3]s</RequestId></ResponseMetadata></%[1]sResponse>`,
+				operation, cloudWatchXMLNS, RequestID)
+		}
+		w.Header().Set("Content-Type", "text/xml")
+	}
+	_, _ = io.WriteString(w, response)
+}
+
+func (s *Server) writeFailure(w http.ResponseWriter, operation string, f Failure) {
+	switch s.protocol {
+	case protocolJSON:
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		w.WriteHeader(f.StatusCode)
+		_ = json.NewEncoder(w).Encode(map[string]string{"__type": f.Code, "message": f.Message})
+	default:
+		w.Header().Set("Content-Type", "text/xml")
+		w.WriteHeader(f.StatusCode)
+		_, _ = fmt.Fprintf(w, `<ErrorResponse xmlns=%q><Error><Type>Sender</Type><Code>%s</Code><Message>%s</Message></Error><RequestId>%s</RequestId></ErrorResponse>`,
+			cloudWatchXMLNS, f.Code, f.Message, RequestID)
+	}
+}
+
+// operation returns the API name from the target header of the JSON protocol
+// or the action parameter of the query protocol.
+func (s *Server) operation(r *http.Request, body []byte) string {
+	if s.protocol == protocolJSON {
+		target := r.Header.Get(headerTarget)
+		return target[strings.LastIndex(target, ".")+1:]
+	}
+	values, _ := url.ParseQuery(string(body))
+	return values.Get("Action")
+}
+
+func readBody(r *http.Request) ([]byte, error) {
+	var reader io.Reader = r.Body
+	if r.Header.Get(headerContentEncoding) == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+	return io.ReadAll(reader)
+}
+
+func (s *Server) normalize(body []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.protocol == protocolJSON {
+		var v any
+		if err := json.Unmarshal(body, &v); err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		// the keys of the maps are sorted when encoded
+		if err := encoder.Encode(s.scrubJSON(v)); err != nil {
+			return nil, err
+		}
+		return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	for _, key := range keys {
+		value := strings.Join(values[key], ",")
+		if _, ok := s.scrub[key[strings.LastIndex(key, ".")+1:]]; ok {
+			value = scrubbed
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", key, value)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// scrubJSON must be called with mu held.
+func (s *Server) scrubJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if _, ok := s.scrub[key]; ok {
+				v[key] = scrubbed
+				continue
+			}
+			v[key] = s.scrubJSON(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = s.scrubJSON(value)
+		}
+	}
+	return v
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package harness
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/handlers"
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatch"
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
+)
+
+func TestLogs(t *testing.T) {
+	s := NewLogs(t)
+	client := cloudwatchlogs.New(session.Must(session.NewSession(s.Config().WithMaxRetries(0))))
+	client.Handlers.Build.PushBackNamed(handlers.NewRequestCompressionHandler([]string{"PutLogEvents"}))
+
+	s.Fail("CreateLogStream", Failure{StatusCode: http.StatusBadRequest, Code: cloudwatchlogs.ErrCodeResourceNotFoundException, Message: "missing group"})
+	_, err := client.CreateLogStream(&cloudwatchlogs.CreateLogStreamInput{LogGroupName: aws.String("group"), LogStreamName: aws.String("stream")})
+	var aerr awserr.Error
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, cloudwatchlogs.ErrCodeResourceNotFoundException, aerr.Code())
+	// the failure is only returned once
+	_, err = client.CreateLogStream(&cloudwatchlogs.CreateLogStreamInput{LogGroupName: aws.String("group"), LogStreamName: aws.String("stream")})
+	require.NoError(t, err)
+
+	s.Respond("DescribeLogGroups", `{"logGroups":[{"logGroupName":"group","retentionInDays":7}]}`)
+	output, err := client.DescribeLogGroups(&cloudwatchlogs.DescribeLogGroupsInput{})
+	require.NoError(t, err)
+	require.Len(t, output.LogGroups, 1)
+	assert.EqualValues(t, 7, *output.LogGroups[0].RetentionInDays)
+
+	clock := NewClock(time.Unix(1700000000, 0))
+	var events []*cloudwatchlogs.InputLogEvent
+	for i := 0; i < 5; i++ {
+		clock.Advance(time.Second)
+		events = append(events, &cloudwatchlogs.InputLogEvent{
+			Message:   aws.String("the same message compresses well"),
+			Timestamp: aws.Int64(clock.Now().UnixMilli()),
+		})
+	}
+	_, err = client.PutLogEvents(&cloudwatchlogs.PutLogEventsInput{LogGroupName: aws.String("group"), LogStreamName: aws.String("stream"), LogEvents: events[:2]})
+	require.NoError(t, err)
+	_, err = client.PutLogEvents(&cloudwatchlogs.PutLogEventsInput{LogGroupName: aws.String("group"), LogStreamName: aws.String("stream"), LogEvents: events})
+	require.NoError(t, err)
+
+	requests := s.Requests("PutLogEvents")
+	require.Len(t, requests, 2)
+	assert.Equal(t, "gzip", requests[1].Header.Get(headerContentEncoding))
+	assert.Len(t, s.Requests(), 5)
+
+	// the timestamps come from the clock, so the requests are stable
+	AssertGolden(t, filepath.Join("testdata", "logs.golden"), s.Dump("CreateLogStream", "PutLogEvents"))
+
+	s.Reset()
+	assert.Empty(t, s.Requests())
+}
+
+func TestCloudWatch(t *testing.T) {
+	s := NewCloudWatch(t)
+	client := cloudwatch.New(session.Must(session.NewSession(s.Config().WithMaxRetries(0))))
+	input := &cloudwatch.PutMetricDataInput{
+		Namespace: aws.String("CWAgent"),
+		MetricData: []*cloudwatch.MetricDatum{
+			{
+				MetricName: aws.String("mem_used_percent"),
+				Dimensions: []*cloudwatch.Dimension{{Name: aws.String("host"), Value: aws.String("localhost")}},
+				Timestamp:  aws.Time(time.Now()),
+				Value:      aws.Float64(42),
+				Unit:       aws.String(cloudwatch.StandardUnitPercent),
+			},
+		},
+	}
+
+	s.Fail("PutMetricData", Failure{StatusCode: http.StatusBadRequest, Code: "Throttling", Message: "Rate exceeded"})
+	_, err := client.PutMetricData(input)
+	var aerr awserr.Error
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, "Throttling", aerr.Code())
+	assert.Equal(t, "Rate exceeded", aerr.Message())
+
+	_, err = client.PutMetricData(input)
+	require.NoError(t, err)
+	require.Len(t, s.Requests("PutMetricData"), 2)
+
+	s.Scrub("Timestamp")
+	AssertGolden(t, filepath.Join("testdata", "cloudwatch.golden"), s.Dump("PutMetricData"))
+}
@@ -25,5 +25,8 @@ const (
 	LogEntryField = "value"
 
 	WindowsEventLogPrefix = "Amazon_CloudWatch_WindowsEventLog_"
+	JournaldPrefix        = "Amazon_CloudWatch_Journald_"
+	DockerPrefix          = "Amazon_CloudWatch_Docker_"
+	KinesisPrefix         = "Amazon_CloudWatch_Kinesis_"
 	LogType               = "log_type"
 )
@@ -0,0 +1,164 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package logsource has the log sources shared by the log inputs whose events
+// are read or received by routines of their own, e.g. the Kafka consumers or
+// the OTLP handlers, and handed over to the log agent, along with the
+// templates of the log group and stream names referencing the events.
+package logsource
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
+)
+
+// Event is a log event read by an input.
+type Event struct {
+	message   string
+	timestamp time.Time
+	done      func()
+}
+
+var _ logs.LogEvent = (*Event)(nil)
+
+// NewEvent creates an event. The done func, if any, is called once the event
+// has been published, which allows the input to save its position, e.g. the
+// offset of a Kafka partition or the journald cursor.
+func NewEvent(message string, timestamp time.Time, done func()) *Event {
+	return &Event{message: message, timestamp: timestamp, done: done}
+}
+
+func (e *Event) Message() string {
+	return e.message
+}
+
+func (e *Event) Time() time.Time {
+	return e.timestamp
+}
+
+func (e *Event) Done() {
+	if e.done != nil {
+		e.done()
+	}
+}
+
+// Src is a log source publishing the events handed to it by the routines of
+// an input to a single log group and stream.
+type Src struct {
+	group         string
+	stream        string
+	description   string
+	destination   string
+	logGroupClass string
+	retention     int
+
+	events    chan logs.LogEvent
+	outputFn  func(logs.LogEvent)
+	startOnce sync.Once
+	stopOnce  sync.Once
+	done      chan struct{}
+}
+
+var _ logs.LogSrc = (*Src)(nil)
+
+func New(group, stream, description, destination, logGroupClass string, retention int) *Src {
+	return &Src{
+		group:         group,
+		stream:        stream,
+		description:   description,
+		destination:   destination,
+		logGroupClass: logGroupClass,
+		retention:     retention,
+		events:        make(chan logs.LogEvent),
+		done:          make(chan struct{}),
+	}
+}
+
+func (s *Src) SetOutput(fn func(logs.LogEvent)) {
+	if fn == nil {
+		return
+	}
+	s.outputFn = fn
+	s.startOnce.Do(func() { go s.run() })
+}
+
+func (s *Src) Group() string {
+	return s.group
+}
+
+func (s *Src) Stream() string {
+	return s.stream
+}
+
+func (s *Src) Destination() string {
+	return s.destination
+}
+
+func (s *Src) Description() string {
+	return s.description
+}
+
+func (s *Src) Retention() int {
+	return s.retention
+}
+
+func (s *Src) Class() string {
+	return s.logGroupClass
+}
+
+func (s *Src) Stop() {
+	s.stopOnce.Do(func() { close(s.done) })
+}
+
+func (s *Src) Entity() *cloudwatchlogs.Entity {
+	return nil
+}
+
+// Publish blocks until the event has been handed to the output or either the
+// source or the given stop channel is closed. Returns false if the event was
+// not handed over.
+func (s *Src) Publish(e logs.LogEvent, stop <-chan struct{}) bool {
+	select {
+	case s.events <- e:
+		return true
+	case <-s.done:
+		return false
+	case <-stop:
+		return false
+	}
+}
+
+func (s *Src) run() {
+	for {
+		select {
+		case e := <-s.events:
+			s.outputFn(e)
+		case <-s.done:
+			s.outputFn(nil)
+			return
+		}
+	}
+}
+
+var fileNameReplacer = strings.NewReplacer("/", "_", "\\", "_", " ", "_", ":", "_", "*", "_", "{", "_", "}", "_")
+
+// EscapeFileName returns a valid filename string, e.g. for the state file of a
+// log input named after its config.
+func EscapeFileName(name string) string {
+	return fileNameReplacer.Replace(name)
+}
+
+// Sleep returns false if the context is done before the duration elapses.
+func Sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logsource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+func TestSrc(t *testing.T) {
+	src := New("group", "stream", "kafka:topic/0", "cloudwatchlogs", "STANDARD", 7)
+	assert.Equal(t, "group", src.Group())
+	assert.Equal(t, "stream", src.Stream())
+	assert.Equal(t, "kafka:topic/0", src.Description())
+	assert.Equal(t, "cloudwatchlogs", src.Destination())
+	assert.Equal(t, "STANDARD", src.Class())
+	assert.Equal(t, 7, src.Retention())
+	assert.Nil(t, src.Entity())
+
+	events := make(chan logs.LogEvent, 2)
+	src.SetOutput(func(e logs.LogEvent) {
+		events <- e
+	})
+	var done int
+	now := time.Now()
+	require.True(t, src.Publish(NewEvent("message", now, func() { done++ }), nil))
+	e := <-events
+	assert.Equal(t, "message", e.Message())
+	assert.Equal(t, now, e.Time())
+	e.Done()
+	assert.Equal(t, 1, done)
+
+	// the output is told that the source stopped, and nothing is published after
+	src.Stop()
+	src.Stop()
+	assert.Nil(t, <-events)
+	assert.False(t, src.Publish(NewEvent("message", now, nil), nil))
+}
+
+func TestSrcPublishStopped(t *testing.T) {
+	// without an output, the event is not handed over until the caller gives up
+	src := New("group", "stream", "", "", "", -1)
+	stop := make(chan struct{})
+	close(stop)
+	assert.False(t, src.Publish(NewEvent("message", time.Now(), nil), stop))
+}
+
+func TestEscapeFileName(t *testing.T) {
+	assert.Equal(t, "_docker__container_id__a_b", EscapeFileName("/docker/{container_id}/a:b"))
+	assert.Equal(t, "C__logs_app_1.log", EscapeFileName("C:\\logs\\app 1.log"))
+}
+
+func TestSleep(t *testing.T) {
+	assert.True(t, Sleep(context.Background(), time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, Sleep(ctx, time.Minute))
+}
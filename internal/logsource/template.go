@@ -0,0 +1,88 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logsource
+
+import "regexp"
+
+const maxLogNameLength = 512
+
+var (
+	// invalidLogGroupChars are the characters not allowed in a log group name.
+	invalidLogGroupChars = regexp.MustCompile(`[^\w./#-]`)
+	// invalidLogStreamChars are the characters not allowed in a log stream name.
+	invalidLogStreamChars = regexp.MustCompile(`[:*]`)
+)
+
+// Template is a log group or stream name referencing the events, e.g. the
+// fields of JSON log lines or the tag of fluent forward messages. The
+// references are matched by a regexp whose first group identifies what is
+// referenced.
+type Template struct {
+	name      string
+	reference *regexp.Regexp
+	invalid   *regexp.Regexp
+	// references are the first groups of the references in order of appearance in the name.
+	references []string
+}
+
+// NewGroupTemplate returns the template of the log group name, nil if the name
+// has no reference, along with the name without the references. The latter is
+// used by the events that cannot be resolved.
+func NewGroupTemplate(name string, reference *regexp.Regexp) (*Template, string) {
+	return newTemplate(name, reference, invalidLogGroupChars)
+}
+
+// NewStreamTemplate returns the template of the log stream name, nil if the
+// name has no reference, along with the name without the references.
+func NewStreamTemplate(name string, reference *regexp.Regexp) (*Template, string) {
+	return newTemplate(name, reference, invalidLogStreamChars)
+}
+
+func newTemplate(name string, reference, invalid *regexp.Regexp) (*Template, string) {
+	matches := reference.FindAllStringSubmatch(name, -1)
+	if len(matches) == 0 {
+		return nil, name
+	}
+	t := &Template{name: name, reference: reference, invalid: invalid}
+	for _, match := range matches {
+		var ref string
+		if len(match) > 1 {
+			ref = match[1]
+		}
+		t.references = append(t.references, ref)
+	}
+	return t, reference.ReplaceAllString(name, "")
+}
+
+// References returns the first group of each reference in order of
+// appearance in the name.
+func (t *Template) References() []string {
+	return t.references
+}
+
+// Resolve returns the name with the references replaced by the values, in the
+// same order, with the characters not allowed in the name replaced and
+// truncated to the maximum length. Returns an empty string if any value is
+// empty.
+func (t *Template) Resolve(values []string) string {
+	if len(values) != len(t.references) {
+		return ""
+	}
+	for _, value := range values {
+		if value == "" {
+			return ""
+		}
+	}
+	i := 0
+	resolved := t.reference.ReplaceAllStringFunc(t.name, func(string) string {
+		value := values[i]
+		i++
+		return value
+	})
+	resolved = t.invalid.ReplaceAllString(resolved, "_")
+	if len(resolved) > maxLogNameLength {
+		resolved = resolved[:maxLogNameLength]
+	}
+	return resolved
+}
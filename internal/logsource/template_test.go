@@ -0,0 +1,36 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logsource
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testReferenceRegexp = regexp.MustCompile(`\{ref(\d*)\}`)
+
+func TestTemplate(t *testing.T) {
+	template, static := NewGroupTemplate("/app/logs", testReferenceRegexp)
+	assert.Nil(t, template)
+	assert.Equal(t, "/app/logs", static)
+
+	template, static = NewGroupTemplate("/app/{ref1}/{ref2}", testReferenceRegexp)
+	assert.NotNil(t, template)
+	assert.Equal(t, "/app//", static)
+	assert.Equal(t, []string{"1", "2"}, template.References())
+	assert.Equal(t, "/app/web/v2", template.Resolve([]string{"web", "v2"}))
+	assert.Equal(t, "/app/my_app/v_2", template.Resolve([]string{"my app", "v:2"}))
+	assert.Equal(t, "", template.Resolve([]string{"web", ""}))
+	assert.Equal(t, "", template.Resolve([]string{"web"}))
+
+	template, static = NewStreamTemplate("{ref}:*", testReferenceRegexp)
+	assert.Equal(t, ":*", static)
+	assert.Equal(t, "my app__", template.Resolve([]string{"my app"}))
+
+	template, _ = NewStreamTemplate("{ref}", testReferenceRegexp)
+	assert.Len(t, template.Resolve([]string{strings.Repeat("a", 2*maxLogNameLength)}), maxLogNameLength)
+}
@@ -114,35 +114,52 @@ func (e *Encoder) encodeStruct(value reflect.Value) (any, error) {
 		return e.encode(value)
 	}
 	result := make(map[string]any)
+	if err = e.encodeFields(value, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// encodeFields encodes the exported fields of the struct into the result.
+func (e *Encoder) encodeFields(value reflect.Value, result map[string]any) error {
 	for i := 0; i < value.NumField(); i++ {
 		field := value.Field(i)
-		if field.CanInterface() {
-			info := getTagInfo(value.Type().Field(i))
-			if (info.omitEmpty && field.IsZero()) || info.name == optionSkip {
-				continue
-			}
-			encoded, err := e.encode(field)
-			if err != nil {
-				if errors.Is(err, errUnsupportedKind) {
-					continue
+		structField := value.Type().Field(i)
+		if !field.CanInterface() {
+			// the exported fields of a squashed embedded struct are promoted even
+			// if the struct type is unexported
+			if structField.Anonymous && field.Kind() == reflect.Struct && getTagInfo(structField).squash {
+				if err := e.encodeFields(field, result); err != nil {
+					return err
 				}
-				return nil, fmt.Errorf("error encoding field %q: %w", info.name, err)
 			}
-			if e.config.OmitNilFields && encoded == nil {
+			continue
+		}
+		info := getTagInfo(structField)
+		if (info.omitEmpty && field.IsZero()) || info.name == optionSkip {
+			continue
+		}
+		encoded, err := e.encode(field)
+		if err != nil {
+			if errors.Is(err, errUnsupportedKind) {
 				continue
 			}
-			if info.squash {
-				if m, ok := encoded.(map[string]any); ok {
-					for k, v := range m {
-						result[k] = v
-					}
+			return fmt.Errorf("error encoding field %q: %w", info.name, err)
+		}
+		if e.config.OmitNilFields && encoded == nil {
+			continue
+		}
+		if info.squash {
+			if m, ok := encoded.(map[string]any); ok {
+				for k, v := range m {
+					result[k] = v
 				}
-			} else {
-				result[info.name] = encoded
 			}
+		} else {
+			result[info.name] = encoded
 		}
 	}
-	return result, nil
+	return nil
 }
 
 // encodeSlice iterates over the slice and encodes each of the elements.
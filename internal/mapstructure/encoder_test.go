@@ -296,6 +296,28 @@ func TestEncodeStruct(t *testing.T) {
 	assert.Equal(t, "final", got)
 }
 
+type testUnexportedEmbedded struct {
+	Name string `mapstructure:"name"`
+	// not promoted
+	hidden string
+}
+
+type TestUnexportedEmbeddedStruct struct {
+	testUnexportedEmbedded `mapstructure:",squash"`
+	Value                  string `mapstructure:"value"`
+}
+
+func TestEncodeUnexportedEmbeddedStruct(t *testing.T) {
+	enc := New(&EncoderConfig{})
+	testCase := TestUnexportedEmbeddedStruct{
+		testUnexportedEmbedded: testUnexportedEmbedded{Name: "name", hidden: "hidden"},
+		Value:                  "value",
+	}
+	got, err := enc.Encode(testCase)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "name", "value": "value"}, got)
+}
+
 func TestEncodeStructError(t *testing.T) {
 	enc := New(&EncoderConfig{
 		EncodeHook: testHookFunc(),
@@ -8,6 +8,7 @@ import (
 	"reflect"
 
 	"github.com/mitchellh/mapstructure"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/confmap"
@@ -33,6 +34,8 @@ func encoderConfig(rawVal any) *EncoderConfig {
 		EncodeHook: mapstructure.ComposeDecodeHookFunc(
 			NilHookFunc[configopaque.String](),
 			NilZeroValueHookFunc[configtls.ServerConfig](),
+			// the empty error mode cannot be unmarshaled, e.g. in the unused OTTL policy of the tail sampling processor
+			NilZeroValueHookFunc[ottl.ErrorMode](),
 			TextMarshalerHookFunc(),
 			MarshalerHookFunc(rawVal),
 			UnsupportedKindHookFunc(),
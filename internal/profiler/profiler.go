@@ -0,0 +1,236 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package profiler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	rpprof "runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+const (
+	DefaultCaptureInterval = time.Hour
+	DefaultCheckInterval   = 10 * time.Second
+	// MaxCaptures is the number of captures kept in the directory. The
+	// oldest are removed so that the captures cannot fill the disk.
+	MaxCaptures = 5
+
+	heapPrefix      = "heap-"
+	goroutinePrefix = "goroutines-"
+	timestampFormat = "20060102T150405Z"
+)
+
+// Config enables the pprof endpoint, the capture of the profiles when the
+// memory of the agent crosses a threshold, or both.
+type Config struct {
+	// PprofPort is the port of the pprof endpoint on localhost. Disabled if 0.
+	PprofPort int
+	// MemoryThreshold is the RSS in bytes above which the profiles are
+	// captured. Disabled if 0.
+	MemoryThreshold uint64
+	// CaptureInterval is the minimum time between two captures.
+	CaptureInterval time.Duration
+	// CheckInterval is how often the RSS is checked.
+	CheckInterval time.Duration
+	// Dir is the directory the profiles are written to.
+	Dir string
+}
+
+// Enabled returns true if either the endpoint or the capture is configured.
+func (c Config) Enabled() bool {
+	return c.PprofPort != 0 || c.MemoryThreshold != 0
+}
+
+// rss returns the resident memory of the agent. Overridden in tests.
+var rss = func() (uint64, error) {
+	proc, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		return 0, err
+	}
+	info, err := proc.MemoryInfo()
+	if err != nil {
+		return 0, err
+	}
+	return info.RSS, nil
+}
+
+// Profiler serves pprof and captures the profiles of the agent.
+type Profiler struct {
+	cfg    Config
+	server *http.Server
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	lastCapture time.Time
+}
+
+// New returns a profiler with the defaults set for the intervals that are not
+// configured.
+func New(cfg Config) *Profiler {
+	if cfg.CaptureInterval <= 0 {
+		cfg.CaptureInterval = DefaultCaptureInterval
+	}
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = DefaultCheckInterval
+	}
+	return &Profiler{cfg: cfg}
+}
+
+// Start serves pprof on localhost and monitors the memory in the background.
+// The endpoint is only reachable from the host since the profiles can
+// contain sensitive data.
+func (p *Profiler) Start() error {
+	if p.cfg.PprofPort != 0 {
+		listener, err := net.Listen("tcp", net.JoinHostPort("localhost", strconv.Itoa(p.cfg.PprofPort)))
+		if err != nil {
+			return fmt.Errorf("unable to serve pprof: %w", err)
+		}
+		p.server = &http.Server{Handler: newPprofMux(), ReadHeaderTimeout: 10 * time.Second}
+		log.Printf("I! Serving pprof at http://%s/debug/pprof/", listener.Addr())
+		go func() {
+			if err := p.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("E! Stopped serving pprof: %v", err)
+			}
+		}()
+	}
+	if p.cfg.MemoryThreshold != 0 {
+		if err := os.MkdirAll(p.cfg.Dir, 0755); err != nil {
+			p.Stop()
+			return fmt.Errorf("unable to create the profile directory: %w", err)
+		}
+		log.Printf("I! Capturing the profiles to %s when the memory exceeds %d MiB", p.cfg.Dir, p.cfg.MemoryThreshold>>20)
+		ctx, cancel := context.WithCancel(context.Background())
+		p.cancel = cancel
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.monitor(ctx)
+		}()
+	}
+	return nil
+}
+
+// Stop stops the endpoint and the monitoring.
+func (p *Profiler) Stop() {
+	if p.server != nil {
+		_ = p.server.Close()
+	}
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+}
+
+func (p *Profiler) monitor(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			p.check(now)
+		}
+	}
+}
+
+// check captures the profiles if the memory is above the threshold and no
+// capture was done in the capture interval.
+func (p *Profiler) check(now time.Time) {
+	used, err := rss()
+	if err != nil {
+		log.Printf("D! Unable to read the memory of the agent: %v", err)
+		return
+	}
+	if used < p.cfg.MemoryThreshold {
+		return
+	}
+	if !p.lastCapture.IsZero() && now.Sub(p.lastCapture) < p.cfg.CaptureInterval {
+		return
+	}
+	p.lastCapture = now
+	heapPath, goroutinePath, err := capture(p.cfg.Dir, now)
+	if err != nil {
+		log.Printf("E! The memory of the agent (%d MiB) exceeds %d MiB but the profiles could not be captured: %v", used>>20, p.cfg.MemoryThreshold>>20, err)
+		return
+	}
+	log.Printf("W! The memory of the agent (%d MiB) exceeds %d MiB, captured the heap profile to %s and the goroutine dump to %s", used>>20, p.cfg.MemoryThreshold>>20, heapPath, goroutinePath)
+	prune(p.cfg.Dir, MaxCaptures)
+}
+
+// capture writes the heap profile and the goroutine dump to the directory.
+func capture(dir string, now time.Time) (heapPath string, goroutinePath string, err error) {
+	timestamp := now.UTC().Format(timestampFormat)
+	heapPath = filepath.Join(dir, heapPrefix+timestamp+".pprof")
+	goroutinePath = filepath.Join(dir, goroutinePrefix+timestamp+".txt")
+	// collect the garbage so that the profile shows the live objects
+	runtime.GC()
+	if err = writeProfile(heapPath, "heap", 0); err != nil {
+		return "", "", err
+	}
+	if err = writeProfile(goroutinePath, "goroutine", 2); err != nil {
+		return "", "", err
+	}
+	return heapPath, goroutinePath, nil
+}
+
+func writeProfile(path, name string, debug int) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err = rpprof.Lookup(name).WriteTo(f, debug); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// prune removes the oldest captures so that at most max of each are kept.
+func prune(dir string, max int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, prefix := range []string{heapPrefix, goroutinePrefix} {
+		var names []string
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasPrefix(entry.Name(), prefix) {
+				names = append(names, entry.Name())
+			}
+		}
+		// the timestamps sort the names from the oldest
+		sort.Strings(names)
+		for i := 0; i < len(names)-max; i++ {
+			if err = os.Remove(filepath.Join(dir, names[i])); err != nil {
+				log.Printf("W! Unable to remove the profile %s: %v", names[i], err)
+			}
+		}
+	}
+}
+
+func newPprofMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
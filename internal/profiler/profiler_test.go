@@ -0,0 +1,102 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	var used atomic.Uint64
+	defer func(original func() (uint64, error)) { rss = original }(rss)
+	rss = func() (uint64, error) { return used.Load(), nil }
+
+	dir := t.TempDir()
+	p := New(Config{MemoryThreshold: 100 << 20, CaptureInterval: time.Minute, Dir: dir})
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	used.Store(50 << 20)
+	p.check(now)
+	assert.Empty(t, listDir(t, dir))
+
+	used.Store(150 << 20)
+	p.check(now)
+	assert.Equal(t, []string{"goroutines-20240102T030405Z.txt", "heap-20240102T030405Z.pprof"}, listDir(t, dir))
+	goroutines, err := os.ReadFile(filepath.Join(dir, "goroutines-20240102T030405Z.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(goroutines), "goroutine")
+
+	// rate limited
+	p.check(now.Add(30 * time.Second))
+	assert.Len(t, listDir(t, dir), 2)
+	p.check(now.Add(time.Minute))
+	assert.Len(t, listDir(t, dir), 4)
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 4; i++ {
+		for _, name := range []string{fmt.Sprintf("heap-2024010%dT000000Z.pprof", i), fmt.Sprintf("goroutines-2024010%dT000000Z.txt", i)} {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0600))
+		}
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "amazon-cloudwatch-agent.log"), nil, 0600))
+	prune(dir, 2)
+	assert.Equal(t, []string{
+		"amazon-cloudwatch-agent.log",
+		"goroutines-20240102T000000Z.txt",
+		"goroutines-20240103T000000Z.txt",
+		"heap-20240102T000000Z.pprof",
+		"heap-20240103T000000Z.pprof",
+	}, listDir(t, dir))
+}
+
+func TestPprofEndpoint(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	p := New(Config{PprofPort: port})
+	require.NoError(t, p.Start())
+	defer p.Stop()
+
+	resp, err := http.Get(fmt.Sprintf("http://localhost:%d/debug/pprof/goroutine?debug=1", port))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(body), "goroutine profile:"))
+}
+
+func TestConfigEnabled(t *testing.T) {
+	assert.False(t, Config{}.Enabled())
+	assert.True(t, Config{PprofPort: 6060}.Enabled())
+	assert.True(t, Config{MemoryThreshold: 1 << 30}.Enabled())
+}
+
+func listDir(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
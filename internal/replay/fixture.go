@@ -0,0 +1,190 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/aws/amazon-cloudwatch-agent/receiver/adapter/accumulator"
+)
+
+const (
+	otlpExt   = ".json"
+	statsdExt = ".statsd"
+	logExt    = ".log"
+
+	// statsdDefaultSeparator replaces the dots of the StatsD names, as the
+	// statsd input does without templates.
+	statsdDefaultSeparator = "_"
+)
+
+// statsdMetricTypes are the metric_type tags of the replayed StatsD types.
+var statsdMetricTypes = map[string]string{
+	"c":  "counter",
+	"g":  "gauge",
+	"ms": "timing",
+	"h":  "histogram",
+	"d":  "distribution",
+}
+
+// fixtures are the recorded telemetry of a directory.
+type fixtures struct {
+	otlpMetrics   pmetric.Metrics
+	otlpLogs      plog.Logs
+	statsdMetrics pmetric.Metrics
+	// logFiles are the paths of the log fixtures.
+	logFiles []string
+	skipped  []string
+}
+
+func loadFixtures(dir string) (*fixtures, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the fixtures: %w", err)
+	}
+	f := &fixtures{
+		otlpMetrics:   pmetric.NewMetrics(),
+		otlpLogs:      plog.NewLogs(),
+		statsdMetrics: pmetric.NewMetrics(),
+	}
+	// ReadDir sorts the entries so the fixtures are replayed in a stable order
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		switch filepath.Ext(entry.Name()) {
+		case otlpExt:
+			err = f.loadOTLP(path)
+		case statsdExt:
+			err = f.loadStatsD(path)
+		case logExt:
+			f.logFiles = append(f.logFiles, path)
+		default:
+			f.skipped = append(f.skipped, fmt.Sprintf("fixture %s: unknown extension", entry.Name()))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// loadOTLP loads the metrics or the logs of the OTLP JSON file.
+func (f *fixtures) loadOTLP(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	var probe struct {
+		ResourceMetrics json.RawMessage `json:"resourceMetrics"`
+		ResourceLogs    json.RawMessage `json:"resourceLogs"`
+	}
+	if err = json.Unmarshal(content, &probe); err != nil {
+		return fmt.Errorf("invalid OTLP JSON in %s: %w", path, err)
+	}
+	switch {
+	case probe.ResourceMetrics != nil:
+		var md pmetric.Metrics
+		if md, err = (&pmetric.JSONUnmarshaler{}).UnmarshalMetrics(content); err != nil {
+			return fmt.Errorf("invalid OTLP metrics in %s: %w", path, err)
+		}
+		md.ResourceMetrics().MoveAndAppendTo(f.otlpMetrics.ResourceMetrics())
+	case probe.ResourceLogs != nil:
+		var ld plog.Logs
+		if ld, err = (&plog.JSONUnmarshaler{}).UnmarshalLogs(content); err != nil {
+			return fmt.Errorf("invalid OTLP logs in %s: %w", path, err)
+		}
+		ld.ResourceLogs().MoveAndAppendTo(f.otlpLogs.ResourceLogs())
+	default:
+		f.skipped = append(f.skipped, fmt.Sprintf("fixture %s: neither OTLP metrics nor logs", filepath.Base(path)))
+	}
+	return nil
+}
+
+// loadStatsD converts the StatsD lines to the metrics of the statsd input.
+// Each line is a single sample, the samples are not aggregated.
+func (f *fixtures) loadStatsD(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	now := time.Now()
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		md, err := parseStatsD(line, now)
+		if err != nil {
+			f.skipped = append(f.skipped, fmt.Sprintf("fixture %s:%d: %v", filepath.Base(path), n, err))
+			continue
+		}
+		md.ResourceMetrics().MoveAndAppendTo(f.statsdMetrics.ResourceMetrics())
+	}
+	return scanner.Err()
+}
+
+// parseStatsD parses a <bucket>:<value>|<type>[|@<rate>][|#<tags>] line, with
+// the Influx style tags of the bucket and the DogStatsD tags.
+func parseStatsD(line string, now time.Time) (pmetric.Metrics, error) {
+	bucket, rest, ok := strings.Cut(line, ":")
+	if !ok {
+		return pmetric.Metrics{}, fmt.Errorf("missing value")
+	}
+	parts := strings.Split(rest, "|")
+	if len(parts) < 2 {
+		return pmetric.Metrics{}, fmt.Errorf("missing type")
+	}
+	tags := map[string]string{}
+	for _, part := range parts[2:] {
+		if strings.HasPrefix(part, "#") {
+			for _, tag := range strings.Split(part[1:], ",") {
+				k, v, ok := strings.Cut(tag, ":")
+				if !ok {
+					v = "<empty>"
+				}
+				if k != "" {
+					tags[k] = v
+				}
+			}
+		}
+	}
+	bucketParts := strings.Split(bucket, ",")
+	for _, tag := range bucketParts[1:] {
+		if k, v, ok := strings.Cut(tag, "="); ok && k != "" {
+			tags[k] = v
+		}
+	}
+	name := strings.ReplaceAll(bucketParts[0], ".", statsdDefaultSeparator)
+	value, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return pmetric.Metrics{}, fmt.Errorf("invalid value %q", parts[0])
+	}
+	metricType, ok := statsdMetricTypes[parts[1]]
+	if !ok {
+		return pmetric.Metrics{}, fmt.Errorf("type %q is not replayed", parts[1])
+	}
+	tags["metric_type"] = metricType
+	fields := map[string]interface{}{"value": value}
+	valueType := telegraf.Gauge
+	if parts[1] == "c" {
+		fields["value"] = int64(value)
+		valueType = telegraf.Counter
+	}
+	return accumulator.ConvertTelegrafToOtelMetrics(name, fields, tags, valueType, now)
+}
@@ -0,0 +1,150 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/parser"
+)
+
+type logFilter struct {
+	Type       string `toml:"type"`
+	Expression string `toml:"expression"`
+}
+
+type logFileConfig struct {
+	FilePath      string      `toml:"file_path"`
+	LogGroupName  string      `toml:"log_group_name"`
+	LogStreamName string      `toml:"log_stream_name"`
+	Filters       []logFilter `toml:"filters"`
+	parser.Config
+}
+
+type logsConfig struct {
+	Inputs struct {
+		Logfile []struct {
+			FileConfig []logFileConfig `toml:"file_config"`
+		} `toml:"logfile"`
+	} `toml:"inputs"`
+	Outputs struct {
+		CloudWatchLogs []struct {
+			LogStreamName string `toml:"log_stream_name"`
+		} `toml:"cloudwatchlogs"`
+	} `toml:"outputs"`
+}
+
+// replayLogFiles collects each log fixture with the first file_config whose
+// file_path has a base name matching the name of the fixture. Each line is an
+// event, the multiline starters are not applied.
+func replayLogFiles(cfg Config, f *fixtures, report *Report) error {
+	if len(f.logFiles) == 0 {
+		return nil
+	}
+	var conf logsConfig
+	if _, err := toml.DecodeFile(cfg.TomlConfig, &conf); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to decode the log file configs: %w", err)
+	}
+	var defaultStream string
+	if len(conf.Outputs.CloudWatchLogs) > 0 {
+		defaultStream = conf.Outputs.CloudWatchLogs[0].LogStreamName
+	}
+	for _, fixture := range f.logFiles {
+		name := filepath.Base(fixture)
+		fileConfig := matchFileConfig(conf, name)
+		if fileConfig == nil {
+			report.Skipped = append(report.Skipped, fmt.Sprintf("fixture %s: no file_config matches", name))
+			continue
+		}
+		events, err := collectEvents(fixture, fileConfig)
+		if err != nil {
+			return err
+		}
+		file := outputFile("", "logfile", name)
+		if err = writeOutput(cfg.OutputDir, file, []byte(strings.Join(events, ""))); err != nil {
+			return err
+		}
+		stream := fileConfig.LogStreamName
+		if stream == "" {
+			stream = defaultStream
+		}
+		report.Logs = append(report.Logs, LogDestination{
+			Source:    fileConfig.FilePath,
+			LogGroup:  fileConfig.LogGroupName,
+			LogStream: stream,
+			Events:    len(events),
+			File:      file,
+		})
+	}
+	return nil
+}
+
+func matchFileConfig(conf logsConfig, name string) *logFileConfig {
+	for _, logfile := range conf.Inputs.Logfile {
+		for i, fileConfig := range logfile.FileConfig {
+			// the file paths of the Windows configurations use backslashes
+			pattern := path.Base(strings.ReplaceAll(fileConfig.FilePath, `\`, "/"))
+			if ok, _ := path.Match(pattern, name); ok {
+				return &logfile.FileConfig[i]
+			}
+		}
+	}
+	return nil
+}
+
+// collectEvents returns the lines of the fixture the filters publish, run
+// through the parsers of the file_config, each terminated by a new line.
+func collectEvents(fixture string, fileConfig *logFileConfig) ([]string, error) {
+	filters := make([]*regexp.Regexp, len(fileConfig.Filters))
+	for i, filter := range fileConfig.Filters {
+		var err error
+		if filters[i], err = regexp.Compile(filter.Expression); err != nil {
+			return nil, fmt.Errorf("invalid filter of %s: %w", fileConfig.FilePath, err)
+		}
+	}
+	pipeline, err := parser.New(fileConfig.Config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parsers of %s: %w", fileConfig.FilePath, err)
+	}
+	content, err := os.ReadFile(fixture)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", fixture, err)
+	}
+	var events []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || !shouldPublish(fileConfig.Filters, filters, line) {
+			continue
+		}
+		if pipeline != nil {
+			if parsed, _, ok := pipeline.Parse(line); ok {
+				line = parsed
+			}
+		}
+		events = append(events, line+"\n")
+	}
+	return events, scanner.Err()
+}
+
+// shouldPublish applies the include and exclude filters as the logfile input
+// does: the event is dropped by the first filter rejecting it.
+func shouldPublish(filters []logFilter, expressions []*regexp.Regexp, line string) bool {
+	for i, filter := range filters {
+		if (filter.Type == "include") != expressions[i].MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
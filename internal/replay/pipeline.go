@@ -0,0 +1,395 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package replay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	otelconfmap "go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/merge/confmap"
+)
+
+const (
+	metricsPipeline = "metrics"
+	logsPipeline    = "logs"
+
+	otlpReceiver           = "otlp"
+	statsdReceiver         = "statsd"
+	telegrafStatsdReceiver = "telegraf_statsd"
+)
+
+// onlineProcessors reach AWS or the Kubernetes API, so they are not run and
+// the data goes through them unchanged.
+var onlineProcessors = map[string]bool{
+	"awsapplicationsignals": true,
+	"awsentity":             true,
+	"ec2tagger":             true,
+	"k8sattributes":         true,
+	"resourcedetection":     true,
+}
+
+type pipelineConfig struct {
+	Receivers  []string `mapstructure:"receivers"`
+	Processors []string `mapstructure:"processors"`
+	Exporters  []string `mapstructure:"exporters"`
+}
+
+// exporterConfig is the destination of the AWS exporters.
+type exporterConfig struct {
+	Namespace     string `mapstructure:"namespace"`
+	LogGroupName  string `mapstructure:"log_group_name"`
+	LogStreamName string `mapstructure:"log_stream_name"`
+}
+
+type replayer struct {
+	cfg    Config
+	conf   *otelconfmap.Conf
+	report *Report
+}
+
+func replayPipelines(ctx context.Context, cfg Config, f *fixtures, report *Report) error {
+	loaded, err := confmap.NewFileLoader(cfg.OtelConfig).Load()
+	if err != nil {
+		return err
+	}
+	r := &replayer{cfg: cfg, conf: otelconfmap.NewFromStringMap(loaded.ToStringMap()), report: report}
+	var service struct {
+		Service struct {
+			Pipelines map[string]pipelineConfig `mapstructure:"pipelines"`
+		} `mapstructure:"service"`
+	}
+	if err = r.conf.Unmarshal(&service, otelconfmap.WithIgnoreUnused()); err != nil {
+		return fmt.Errorf("invalid pipelines in %s: %w", cfg.OtelConfig, err)
+	}
+	names := make([]string, 0, len(service.Service.Pipelines))
+	for name := range service.Service.Pipelines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pipeline := service.Service.Pipelines[name]
+		switch componentType(name) {
+		case metricsPipeline:
+			md := pmetric.NewMetrics()
+			if hasReceiver(pipeline, otlpReceiver) {
+				f.otlpMetrics.ResourceMetrics().CopyTo(md.ResourceMetrics())
+			}
+			if hasReceiver(pipeline, statsdReceiver, telegrafStatsdReceiver) {
+				appendMetrics(md, f.statsdMetrics)
+			}
+			if md.ResourceMetrics().Len() > 0 {
+				err = r.replayMetrics(ctx, name, pipeline, md)
+			}
+		case logsPipeline:
+			if hasReceiver(pipeline, otlpReceiver) && f.otlpLogs.ResourceLogs().Len() > 0 {
+				ld := plog.NewLogs()
+				f.otlpLogs.CopyTo(ld)
+				err = r.replayLogs(ctx, name, pipeline, ld)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *replayer) replayMetrics(ctx context.Context, name string, pipeline pipelineConfig, md pmetric.Metrics) error {
+	sinks := make([]pmetric.Metrics, len(pipeline.Exporters))
+	for i := range sinks {
+		sinks[i] = pmetric.NewMetrics()
+	}
+	next, _ := consumer.NewMetrics(func(_ context.Context, md pmetric.Metrics) error {
+		for _, sink := range sinks {
+			appendMetrics(sink, md)
+		}
+		return nil
+	})
+	var processors []component.Component
+	for i := len(pipeline.Processors) - 1; i >= 0; i-- {
+		factory, id, cfg, ok := r.processorConfig(name, pipeline.Processors[i])
+		if !ok {
+			continue
+		}
+		p, err := factory.CreateMetricsProcessor(ctx, processorSettings(id), cfg, next)
+		if err != nil {
+			r.skip(name, pipeline.Processors[i], err.Error())
+			continue
+		}
+		next = p
+		processors = append([]component.Component{p}, processors...)
+	}
+	if err := run(ctx, name, processors, func() error { return next.ConsumeMetrics(ctx, md) }); err != nil {
+		return err
+	}
+	for i, exporter := range pipeline.Exporters {
+		dest := r.exporterConfig(exporter)
+		file := outputFile(otlpExt, name, exporter)
+		content, err := (&pmetric.JSONMarshaler{}).MarshalMetrics(sinks[i])
+		if err != nil {
+			return err
+		}
+		if err = writeOutput(r.cfg.OutputDir, file, content); err != nil {
+			return err
+		}
+		r.report.Metrics = append(r.report.Metrics, MetricDestination{
+			Pipeline:  name,
+			Exporter:  exporter,
+			Namespace: dest.Namespace,
+			LogGroup:  dest.LogGroupName,
+			LogStream: dest.LogStreamName,
+			Metrics:   summarize(sinks[i]),
+			File:      file,
+		})
+	}
+	return nil
+}
+
+func (r *replayer) replayLogs(ctx context.Context, name string, pipeline pipelineConfig, ld plog.Logs) error {
+	sinks := make([]plog.Logs, len(pipeline.Exporters))
+	for i := range sinks {
+		sinks[i] = plog.NewLogs()
+	}
+	next, _ := consumer.NewLogs(func(_ context.Context, ld plog.Logs) error {
+		for _, sink := range sinks {
+			for i := 0; i < ld.ResourceLogs().Len(); i++ {
+				ld.ResourceLogs().At(i).CopyTo(sink.ResourceLogs().AppendEmpty())
+			}
+		}
+		return nil
+	})
+	var processors []component.Component
+	for i := len(pipeline.Processors) - 1; i >= 0; i-- {
+		factory, id, cfg, ok := r.processorConfig(name, pipeline.Processors[i])
+		if !ok {
+			continue
+		}
+		p, err := factory.CreateLogsProcessor(ctx, processorSettings(id), cfg, next)
+		if err != nil {
+			r.skip(name, pipeline.Processors[i], err.Error())
+			continue
+		}
+		next = p
+		processors = append([]component.Component{p}, processors...)
+	}
+	if err := run(ctx, name, processors, func() error { return next.ConsumeLogs(ctx, ld) }); err != nil {
+		return err
+	}
+	for i, exporter := range pipeline.Exporters {
+		dest := r.exporterConfig(exporter)
+		file := outputFile(otlpExt, name, exporter)
+		content, err := (&plog.JSONMarshaler{}).MarshalLogs(sinks[i])
+		if err != nil {
+			return err
+		}
+		if err = writeOutput(r.cfg.OutputDir, file, content); err != nil {
+			return err
+		}
+		r.report.Logs = append(r.report.Logs, LogDestination{
+			Pipeline:  name,
+			Exporter:  exporter,
+			LogGroup:  dest.LogGroupName,
+			LogStream: dest.LogStreamName,
+			Events:    sinks[i].LogRecordCount(),
+			File:      file,
+		})
+	}
+	return nil
+}
+
+// processorConfig returns the factory and the configuration of the processor,
+// or false if the processor is not run.
+func (r *replayer) processorConfig(pipeline, name string) (processor.Factory, component.ID, component.Config, bool) {
+	var id component.ID
+	if err := id.UnmarshalText([]byte(name)); err != nil {
+		r.skip(pipeline, name, err.Error())
+		return nil, id, nil, false
+	}
+	if onlineProcessors[id.Type().String()] {
+		r.skip(pipeline, name, "requires access to AWS or Kubernetes")
+		return nil, id, nil, false
+	}
+	factory, ok := r.cfg.Processors[id.Type()]
+	if !ok {
+		r.skip(pipeline, name, "unknown processor")
+		return nil, id, nil, false
+	}
+	cfg := factory.CreateDefaultConfig()
+	if err := r.sub("processors", name).Unmarshal(cfg); err != nil {
+		r.skip(pipeline, name, err.Error())
+		return nil, id, nil, false
+	}
+	return factory, id, cfg, true
+}
+
+func (r *replayer) exporterConfig(name string) exporterConfig {
+	var cfg exporterConfig
+	_ = r.sub("exporters", name).Unmarshal(&cfg, otelconfmap.WithIgnoreUnused())
+	return cfg
+}
+
+// sub returns the configuration of the component, empty if it has none.
+func (r *replayer) sub(kind, name string) *otelconfmap.Conf {
+	components, err := r.conf.Sub(kind)
+	if err != nil {
+		return otelconfmap.New()
+	}
+	conf, err := components.Sub(name)
+	if err != nil {
+		return otelconfmap.New()
+	}
+	return conf
+}
+
+func (r *replayer) skip(pipeline, processor, reason string) {
+	r.report.Skipped = append(r.report.Skipped, fmt.Sprintf("processor %s of %s: %s", processor, pipeline, reason))
+}
+
+// run starts the processors, consumes the fixtures and shuts the processors
+// down from the first one so that the data they buffer is flushed to the next.
+func run(ctx context.Context, pipeline string, processors []component.Component, consume func() error) error {
+	host := componenttest.NewNopHost()
+	for i := len(processors) - 1; i >= 0; i-- {
+		if err := processors[i].Start(ctx, host); err != nil {
+			return fmt.Errorf("unable to start the processors of %s: %w", pipeline, err)
+		}
+	}
+	err := consume()
+	for _, p := range processors {
+		if shutdownErr := p.Shutdown(ctx); shutdownErr != nil && err == nil {
+			err = shutdownErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("unable to replay %s: %w", pipeline, err)
+	}
+	return nil
+}
+
+func processorSettings(id component.ID) processor.Settings {
+	return processor.Settings{
+		ID:                id,
+		TelemetrySettings: componenttest.NewNopTelemetrySettings(),
+		BuildInfo:         component.NewDefaultBuildInfo(),
+	}
+}
+
+// componentType returns the type of the component or pipeline name.
+func componentType(name string) string {
+	t, _, _ := strings.Cut(name, "/")
+	return t
+}
+
+func hasReceiver(pipeline pipelineConfig, receiverTypes ...string) bool {
+	for _, receiver := range pipeline.Receivers {
+		for _, receiverType := range receiverTypes {
+			if componentType(receiver) == receiverType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func appendMetrics(dst, src pmetric.Metrics) {
+	for i := 0; i < src.ResourceMetrics().Len(); i++ {
+		src.ResourceMetrics().At(i).CopyTo(dst.ResourceMetrics().AppendEmpty())
+	}
+}
+
+// summarize returns the metrics by name with the distinct attribute keys of
+// their data points.
+func summarize(md pmetric.Metrics) []Metric {
+	type summary struct {
+		keySets    map[string][]string
+		dataPoints int
+	}
+	summaries := map[string]*summary{}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			ms := sms.At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				m := ms.At(k)
+				s, ok := summaries[m.Name()]
+				if !ok {
+					s = &summary{keySets: map[string][]string{}}
+					summaries[m.Name()] = s
+				}
+				s.dataPoints += forEachDataPoint(m, func(attrs pcommon.Map) {
+					keys := make([]string, 0, attrs.Len())
+					attrs.Range(func(k string, _ pcommon.Value) bool {
+						keys = append(keys, k)
+						return true
+					})
+					sort.Strings(keys)
+					if len(keys) > 0 {
+						s.keySets[strings.Join(keys, ",")] = keys
+					}
+				})
+			}
+		}
+	}
+	metrics := make([]Metric, 0, len(summaries))
+	for name, s := range summaries {
+		m := Metric{Name: name, DataPoints: s.dataPoints}
+		for _, keys := range s.keySets {
+			m.Dimensions = append(m.Dimensions, keys)
+		}
+		sort.Slice(m.Dimensions, func(i, j int) bool {
+			return strings.Join(m.Dimensions[i], ",") < strings.Join(m.Dimensions[j], ",")
+		})
+		metrics = append(metrics, m)
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Name < metrics[j].Name
+	})
+	return metrics
+}
+
+// forEachDataPoint calls fn with the attributes of each data point of the
+// metric and returns the number of data points.
+func forEachDataPoint(m pmetric.Metric, fn func(pcommon.Map)) int {
+	var count int
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := m.Gauge().DataPoints()
+		for count = 0; count < dps.Len(); count++ {
+			fn(dps.At(count).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		dps := m.Sum().DataPoints()
+		for count = 0; count < dps.Len(); count++ {
+			fn(dps.At(count).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		for count = 0; count < dps.Len(); count++ {
+			fn(dps.At(count).Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := m.ExponentialHistogram().DataPoints()
+		for count = 0; count < dps.Len(); count++ {
+			fn(dps.At(count).Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		dps := m.Summary().DataPoints()
+		for count = 0; count < dps.Len(); count++ {
+			fn(dps.At(count).Attributes())
+		}
+	}
+	return count
+}
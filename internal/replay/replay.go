@@ -0,0 +1,142 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package replay feeds recorded telemetry through the pipelines of a
+// translated configuration without reaching AWS, to validate configuration
+// changes offline. The exporters are replaced by stubs writing what they would
+// send to local files, and the report lists the namespaces, dimensions and log
+// groups of the data so that it can be diffed between two configurations.
+//
+// The fixtures are read from a directory by extension:
+//
+//	*.json    OTLP JSON metrics or logs, received by the otlp receivers
+//	*.statsd  StatsD lines, received by the statsd receivers
+//	*.log     log lines, collected by the file_config whose file_path matches the name
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/processor"
+)
+
+// Config is the configuration of a replay.
+type Config struct {
+	// OtelConfig is the path of the translated YAML configuration. The OTel
+	// pipelines are not replayed if the file does not exist, as the agent
+	// then only runs the log files.
+	OtelConfig string
+	// TomlConfig is the path of the translated TOML configuration, which has
+	// the log files.
+	TomlConfig string
+	// Dir is the directory of the fixtures.
+	Dir string
+	// OutputDir is the directory the stubbed exporters write to.
+	OutputDir string
+	// Processors are the factories of the processors of the pipelines.
+	Processors map[component.Type]processor.Factory
+}
+
+// Report is what the agent would send for the fixtures.
+type Report struct {
+	Metrics []MetricDestination `json:"metrics,omitempty"`
+	Logs    []LogDestination    `json:"logs,omitempty"`
+	// Skipped are the fixtures and the processors that were not replayed,
+	// with the reason.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// MetricDestination are the metrics an exporter of a pipeline would send.
+type MetricDestination struct {
+	Pipeline  string   `json:"pipeline"`
+	Exporter  string   `json:"exporter"`
+	Namespace string   `json:"namespace,omitempty"`
+	LogGroup  string   `json:"log_group,omitempty"`
+	LogStream string   `json:"log_stream,omitempty"`
+	Metrics   []Metric `json:"metrics"`
+	// File is the file of the output directory with the metrics as OTLP JSON.
+	File string `json:"file"`
+}
+
+// Metric is a metric of a destination.
+type Metric struct {
+	Name string `json:"name"`
+	// Dimensions are the distinct sorted attribute keys of the data points.
+	Dimensions [][]string `json:"dimensions,omitempty"`
+	DataPoints int        `json:"datapoints"`
+}
+
+// LogDestination are the log events that would be sent to a log stream, either
+// by the exporter of an OTel pipeline or from a log file.
+type LogDestination struct {
+	Pipeline  string `json:"pipeline,omitempty"`
+	Exporter  string `json:"exporter,omitempty"`
+	Source    string `json:"source,omitempty"`
+	LogGroup  string `json:"log_group,omitempty"`
+	LogStream string `json:"log_stream,omitempty"`
+	Events    int    `json:"events"`
+	// File is the file of the output directory with the events.
+	File string `json:"file"`
+}
+
+// Run replays the fixtures and writes what would be sent to the output
+// directory.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	f, err := loadFixtures(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(cfg.OutputDir, 0755); err != nil {
+		return nil, fmt.Errorf("unable to create the output directory: %w", err)
+	}
+	report := &Report{Skipped: f.skipped}
+	if _, err = os.Stat(cfg.OtelConfig); err == nil {
+		if err = replayPipelines(ctx, cfg, f, report); err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("unable to read %s: %w", cfg.OtelConfig, err)
+	}
+	if cfg.TomlConfig != "" {
+		if err = replayLogFiles(cfg, f, report); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// Write writes the report as indented JSON.
+func (r *Report) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// outputFile returns a file name of the output directory made of the parts.
+func outputFile(ext string, parts ...string) string {
+	var name string
+	for i, part := range parts {
+		if i > 0 {
+			name += "."
+		}
+		name += unsafeFileChars.ReplaceAllString(part, "_")
+	}
+	return name + ext
+}
+
+func writeOutput(dir, name string, content []byte) error {
+	if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+		return fmt.Errorf("unable to write %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package replay
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/harness"
+	"github.com/aws/amazon-cloudwatch-agent/service/defaultcomponents"
+)
+
+func TestRun(t *testing.T) {
+	factories, err := defaultcomponents.Factories()
+	require.NoError(t, err)
+	outputDir := t.TempDir()
+	report, err := Run(context.Background(), Config{
+		OtelConfig: filepath.Join("testdata", "config.yaml"),
+		TomlConfig: filepath.Join("testdata", "config.toml"),
+		Dir:        filepath.Join("testdata", "fixtures"),
+		OutputDir:  outputDir,
+		Processors: factories.Processors,
+	})
+	require.NoError(t, err)
+	var got bytes.Buffer
+	require.NoError(t, report.Write(&got))
+	harness.AssertGolden(t, filepath.Join("testdata", "report.golden"), got.Bytes())
+
+	for _, dest := range report.Metrics {
+		assert.FileExists(t, filepath.Join(outputDir, dest.File))
+	}
+	events, err := os.ReadFile(filepath.Join(outputDir, "logfile.app.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-01T00:00:00Z INFO started\n2024-01-01T00:00:02Z ERROR unable to connect\n", string(events))
+}
+
+func TestRunWithoutOtelConfig(t *testing.T) {
+	report, err := Run(context.Background(), Config{
+		OtelConfig: filepath.Join(t.TempDir(), "missing.yaml"),
+		TomlConfig: filepath.Join("testdata", "config.toml"),
+		Dir:        filepath.Join("testdata", "fixtures"),
+		OutputDir:  t.TempDir(),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, report.Metrics)
+	require.Len(t, report.Logs, 1)
+	assert.Equal(t, "app", report.Logs[0].LogGroup)
+	assert.Equal(t, "i-0123456789abcdef0", report.Logs[0].LogStream)
+	assert.Equal(t, 2, report.Logs[0].Events)
+}
+
+func TestParseStatsD(t *testing.T) {
+	testCases := map[string]struct {
+		line      string
+		wantName  string
+		wantAttrs map[string]any
+		wantErr   bool
+	}{
+		"Counter": {
+			line:      "requests.count:2|c|@0.5|#route:/home,canary",
+			wantName:  "requests_count",
+			wantAttrs: map[string]any{"route": "/home", "canary": "<empty>", "metric_type": "counter"},
+		},
+		"BucketTags": {
+			line:      "queue.depth,queue=orders:3|g",
+			wantName:  "queue_depth",
+			wantAttrs: map[string]any{"queue": "orders", "metric_type": "gauge"},
+		},
+		"Set": {
+			line:    "unique.users:42|s",
+			wantErr: true,
+		},
+		"MissingType": {
+			line:    "requests.count:2",
+			wantErr: true,
+		},
+		"InvalidValue": {
+			line:    "requests.count:two|c",
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			md, err := parseStatsD(testCase.line, time.Now())
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 1, md.MetricCount())
+			m := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+			assert.Equal(t, testCase.wantName, m.Name())
+			var attrs map[string]any
+			forEachDataPoint(m, func(a pcommon.Map) {
+				attrs = a.AsRaw()
+			})
+			assert.Equal(t, testCase.wantAttrs, attrs)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package selftelemetry keeps the metrics the agent reports about itself, e.g.
+// the events dropped by the outputs. The components add to the counters or
+// register gauges, and the selftelemetry receiver publishes them when the
+// agent.internal_metrics pipeline is enabled.
+package selftelemetry
+
+import (
+	"sort"
+	"sync"
+)
+
+const (
+	// MetricDroppedEvents is the number of events dropped by a component.
+	MetricDroppedEvents = "dropped_events"
+	// MetricBufferUtilization is the percentage of the buffer of a component
+	// in use.
+	MetricBufferUtilization = "buffer_utilization_percent"
+	// MetricThrottledBatches is the number of requests of a component
+	// throttled by the service.
+	MetricThrottledBatches = "throttled_batches"
+	// MetricDeferredBatches is the number of requests of a component delayed
+	// by its rate limits.
+	MetricDeferredBatches = "deferred_batches"
+	// MetricClampedDatapoints is the number of data points a component
+	// published with the OTHER dimension values because their metric had too
+	// many dimension sets.
+	MetricClampedDatapoints = "clamped_datapoints"
+	// MetricExportedBatches is the number of requests of a component
+	// accepted by the service.
+	MetricExportedBatches = "exported_batches"
+	// MetricInvalidEMFRecords is the number of log lines of a component
+	// expected in the embedded metric format that are not valid.
+	MetricInvalidEMFRecords = "invalid_emf_records"
+	// MetricSampledLogEvents is the number of log lines of a component
+	// dropped by the sampling or the rate limit of their collect_list entry.
+	MetricSampledLogEvents = "sampled_log_events"
+	// MetricTruncatedLogEvents is the number of log events of a component
+	// truncated to their size limit.
+	MetricTruncatedLogEvents = "truncated_log_events"
+	// MetricSplitLogEvents is the number of log events of a component split
+	// into several log events to remain within their size limit.
+	MetricSplitLogEvents = "split_log_events"
+	// MetricSanitizationFixes is the number of metric names and dimensions of
+	// a component fixed by its sanitization policies.
+	MetricSanitizationFixes = "sanitization_fixes"
+	// MetricSanitizationRejections is the number of violations of the
+	// sanitization policies of a component for which the metric or the data
+	// point was dropped.
+	MetricSanitizationRejections = "sanitization_rejections"
+	// MetricClockSkew is the absolute difference in seconds between the clock
+	// of the host and the clock of the endpoints of a component.
+	MetricClockSkew = "clock_skew_seconds"
+)
+
+// Default is the registry of the agent.
+var Default = NewRegistry()
+
+// Sample is a value of a metric of a component.
+type Sample struct {
+	Name      string
+	Component string
+	Value     float64
+	// Cumulative is set for the counters.
+	Cumulative bool
+}
+
+type key struct {
+	name      string
+	component string
+}
+
+type gauge struct {
+	fn func() float64
+}
+
+// Registry is a set of counters and gauges.
+type Registry struct {
+	mu       sync.Mutex
+	counters map[key]float64
+	gauges   map[key][]*gauge
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		counters: make(map[key]float64),
+		gauges:   make(map[key][]*gauge),
+	}
+}
+
+// Add adds the value to the counter of the component.
+func (r *Registry) Add(name, component string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counters[key{name: name, component: component}] += value
+}
+
+// RegisterGauge registers the function returning the current value of the
+// gauge of the component. When several functions are registered for the same
+// gauge, e.g. by two outputs of the same type, the highest value is reported.
+// The returned function unregisters the gauge.
+func (r *Registry) RegisterGauge(name, component string, fn func() float64) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k := key{name: name, component: component}
+	g := &gauge{fn: fn}
+	r.gauges[k] = append(r.gauges[k], g)
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for i, registered := range r.gauges[k] {
+			if registered == g {
+				r.gauges[k] = append(r.gauges[k][:i], r.gauges[k][i+1:]...)
+				break
+			}
+		}
+		if len(r.gauges[k]) == 0 {
+			delete(r.gauges, k)
+		}
+	}
+}
+
+// Collect returns the current values of the counters and gauges, sorted by
+// name and component.
+func (r *Registry) Collect() []Sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	samples := make([]Sample, 0, len(r.counters)+len(r.gauges))
+	for k, value := range r.counters {
+		samples = append(samples, Sample{Name: k.name, Component: k.component, Value: value, Cumulative: true})
+	}
+	for k, gauges := range r.gauges {
+		value := gauges[0].fn()
+		for _, g := range gauges[1:] {
+			value = max(value, g.fn())
+		}
+		samples = append(samples, Sample{Name: k.name, Component: k.component, Value: value})
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		if samples[i].Name != samples[j].Name {
+			return samples[i].Name < samples[j].Name
+		}
+		return samples[i].Component < samples[j].Component
+	})
+	return samples
+}
+
+// Add adds the value to the counter of the component in the default registry.
+func Add(name, component string, value float64) {
+	Default.Add(name, component, value)
+}
+
+// RegisterGauge registers the gauge of the component in the default registry.
+func RegisterGauge(name, component string, fn func() float64) func() {
+	return Default.RegisterGauge(name, component, fn)
+}
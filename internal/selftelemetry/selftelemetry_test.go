@@ -0,0 +1,33 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selftelemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	assert.Empty(t, r.Collect())
+
+	r.Add(MetricDroppedEvents, "logfile", 2)
+	r.Add(MetricDroppedEvents, "logfile", 3)
+	r.Add(MetricDroppedEvents, "cloudwatchlogs", 1)
+	unregisterFirst := r.RegisterGauge(MetricBufferUtilization, "cloudwatch", func() float64 { return 10 })
+	unregisterSecond := r.RegisterGauge(MetricBufferUtilization, "cloudwatch", func() float64 { return 25 })
+	assert.Equal(t, []Sample{
+		{Name: MetricBufferUtilization, Component: "cloudwatch", Value: 25},
+		{Name: MetricDroppedEvents, Component: "cloudwatchlogs", Value: 1, Cumulative: true},
+		{Name: MetricDroppedEvents, Component: "logfile", Value: 5, Cumulative: true},
+	}, r.Collect())
+
+	unregisterSecond()
+	assert.Equal(t, Sample{Name: MetricBufferUtilization, Component: "cloudwatch", Value: 10}, r.Collect()[0])
+	unregisterFirst()
+	// unregistering twice is a no-op
+	unregisterFirst()
+	assert.Len(t, r.Collect(), 2)
+}
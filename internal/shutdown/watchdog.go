@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package shutdown
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+const defaultReportInterval = 5 * time.Second
+
+// Watchdog bounds the time spent stopping the parts of a component and
+// reports the parts that are holding up the shutdown.
+type Watchdog struct {
+	owner          string
+	reportInterval time.Duration
+}
+
+func NewWatchdog(owner string) *Watchdog {
+	return &Watchdog{owner: owner, reportInterval: defaultReportInterval}
+}
+
+// Stop calls stopFn and waits for it to return or for the context to be
+// done. The part is reported every report interval while stopFn is running.
+// If the context is done first, stopFn is left running in the background and
+// the context error is returned.
+func (w *Watchdog) Stop(ctx context.Context, part string, stopFn func()) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		stopFn()
+	}()
+
+	start := time.Now()
+	ticker := time.NewTicker(w.reportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return nil
+		case <-ticker.C:
+			log.Printf("W! [%s] shutdown is blocked on %s for %v", w.owner, part, time.Since(start).Truncate(time.Millisecond))
+		case <-ctx.Done():
+			log.Printf("E! [%s] %s did not stop within %v, abandoning it: %v", w.owner, part, time.Since(start).Truncate(time.Millisecond), ctx.Err())
+			return ctx.Err()
+		}
+	}
+}
+
+// Wait waits for the wait group with the same bounds as Stop.
+func (w *Watchdog) Wait(ctx context.Context, part string, wg *sync.WaitGroup) error {
+	return w.Stop(ctx, part, wg.Wait)
+}
@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package shutdown
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchdog(t *testing.T) {
+	var buf bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+
+	w := NewWatchdog("test")
+	w.reportInterval = 10 * time.Millisecond
+
+	t.Run("Stopped", func(t *testing.T) {
+		buf.Reset()
+		assert.NoError(t, w.Stop(context.Background(), "fast", func() {}))
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("SlowButInTime", func(t *testing.T) {
+		buf.Reset()
+		assert.NoError(t, w.Stop(context.Background(), "slow", func() {
+			time.Sleep(50 * time.Millisecond)
+		}))
+		assert.Contains(t, buf.String(), "W! [test] shutdown is blocked on slow")
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		buf.Reset()
+		release := make(chan struct{})
+		defer close(release)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		assert.ErrorIs(t, w.Stop(ctx, "blocked", func() { <-release }), context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Contains(t, buf.String(), "W! [test] shutdown is blocked on blocked")
+		assert.Contains(t, buf.String(), "E! [test] blocked did not stop within")
+	})
+
+	t.Run("WaitGroup", func(t *testing.T) {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, w.Wait(ctx, "workers", &wg), context.DeadlineExceeded)
+		wg.Done()
+		assert.NoError(t, w.Wait(context.Background(), "workers", &wg))
+	})
+}
@@ -0,0 +1,348 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package sigv4a signs the AWS SDK requests with Signature Version 4a. Unlike
+// SigV4, the signature is not bound to a single region but to a region set, so
+// the same signed request is accepted by the endpoints of every region in the
+// set. The signing key is an ECDSA P-256 key derived from the credentials.
+package sigv4a
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+const (
+	// Algorithm is the signing algorithm in the Authorization header.
+	Algorithm = "AWS4-ECDSA-P256-SHA256"
+	// HeaderRegionSet is the header with the regions the signature is valid in.
+	HeaderRegionSet = "X-Amz-Region-Set"
+	// RegionWildcard makes the signature valid in every region.
+	RegionWildcard = "*"
+
+	headerAuthorization = "Authorization"
+	headerDate          = "X-Amz-Date"
+	headerSecurityToken = "X-Amz-Security-Token"
+
+	timeFormat      = "20060102T150405Z"
+	shortTimeFormat = "20060102"
+
+	// keyDerivationPrefix is prepended to the secret key for the KDF input key.
+	keyDerivationPrefix = "AWS4A"
+	// maxKeyDerivationCounter bounds the candidates tried for the private key.
+	maxKeyDerivationCounter = 0xFF
+)
+
+var (
+	// ignoredHeaders are not signed since they can be changed by proxies or the
+	// HTTP client.
+	ignoredHeaders = map[string]struct{}{
+		"authorization":   {},
+		"user-agent":      {},
+		"x-amzn-trace-id": {},
+	}
+
+	one       = big.NewInt(1)
+	nMinusTwo = new(big.Int).Sub(elliptic.P256().Params().N, big.NewInt(2))
+)
+
+// Signer signs the requests for a region set.
+type Signer struct {
+	regionSet string
+
+	mu sync.Mutex
+	// accessKey, secretKey and key are the last derived signing key. The
+	// credentials only change when they are refreshed.
+	accessKey string
+	secretKey string
+	key       *ecdsa.PrivateKey
+
+	now func() time.Time
+}
+
+// NewSigner creates a signer for the regions, e.g. us-east-1 and us-west-2 or
+// the RegionWildcard.
+func NewSigner(regionSet []string) *Signer {
+	return &Signer{regionSet: strings.Join(regionSet, ","), now: time.Now}
+}
+
+// Configure replaces the SigV4 signer of the client handlers.
+func (s *Signer) Configure(handlers *request.Handlers) {
+	handler := request.NamedHandler{Name: "SigV4aSignHandler", Fn: s.signSDKRequest}
+	if !handlers.Sign.Swap(v4.SignRequestHandler.Name, handler) {
+		handlers.Sign.PushBackNamed(handler)
+	}
+}
+
+func (s *Signer) signSDKRequest(r *request.Request) {
+	if r.Config.Credentials == credentials.AnonymousCredentials {
+		return
+	}
+	creds, err := r.Config.Credentials.GetWithContext(r.Context())
+	if err != nil {
+		r.Error = err
+		return
+	}
+	service := r.ClientInfo.SigningName
+	if service == "" {
+		service = r.ClientInfo.ServiceName
+	}
+	signingTime := s.now()
+	if err = s.Sign(r.HTTPRequest, r.GetBody(), creds, service, signingTime); err != nil {
+		r.Error = err
+		r.SignedHeaderVals = nil
+		return
+	}
+	r.LastSignedAt = signingTime
+}
+
+// Sign adds the SigV4a headers to the HTTP request. The body is read to hash
+// the payload and seeked back to its current position.
+func (s *Signer) Sign(req *http.Request, body io.ReadSeeker, creds credentials.Value, service string, signingTime time.Time) error {
+	key, err := s.signingKey(creds.AccessKeyID, creds.SecretAccessKey)
+	if err != nil {
+		return err
+	}
+	payloadHash, err := hashPayload(body)
+	if err != nil {
+		return err
+	}
+	signingTime = signingTime.UTC()
+	req.Header.Set(HeaderRegionSet, s.regionSet)
+	req.Header.Set(headerDate, signingTime.Format(timeFormat))
+	if creds.SessionToken != "" {
+		req.Header.Set(headerSecurityToken, creds.SessionToken)
+	} else {
+		req.Header.Del(headerSecurityToken)
+	}
+
+	signedHeaders, scope, stringToSign := buildStringToSign(req, payloadHash, service, signingTime)
+	digest := sha256.Sum256([]byte(stringToSign))
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		return fmt.Errorf("unable to sign request: %w", err)
+	}
+	req.Header.Set(headerAuthorization, fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		Algorithm, creds.AccessKeyID, scope, signedHeaders, hex.EncodeToString(signature)))
+	return nil
+}
+
+// buildStringToSign returns the signed header names, the credential scope
+// and the string to sign of the request.
+func buildStringToSign(req *http.Request, payloadHash, service string, signingTime time.Time) (signedHeaders, scope, stringToSign string) {
+	signedHeaders, canonicalHeaders := buildCanonicalHeaders(req)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope = strings.Join([]string{signingTime.Format(shortTimeFormat), service, "aws4_request"}, "/")
+	stringToSign = strings.Join([]string{
+		Algorithm,
+		signingTime.Format(timeFormat),
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+	return signedHeaders, scope, stringToSign
+}
+
+// signingKey returns the key derived from the credentials, reusing the last
+// one if they did not change.
+func (s *Signer) signingKey(accessKey, secretKey string) (*ecdsa.PrivateKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.key != nil && s.accessKey == accessKey && s.secretKey == secretKey {
+		return s.key, nil
+	}
+	key, err := deriveKey(accessKey, secretKey)
+	if err != nil {
+		return nil, err
+	}
+	s.accessKey, s.secretKey, s.key = accessKey, secretKey, key
+	return key, nil
+}
+
+// deriveKey derives the P-256 private key from the access key pair, following
+// FIPS 186-4 B.4.2 with the NIST SP 800-108 counter mode KDF. Candidates are
+// tried with an increasing counter until one is lower than n-2.
+func deriveKey(accessKey, secretKey string) (*ecdsa.PrivateKey, error) {
+	if accessKey == "" || secretKey == "" {
+		return nil, errors.New("SigV4a requires an access key and a secret key")
+	}
+	inputKey := []byte(keyDerivationPrefix + secretKey)
+	var candidate []byte
+	for counter := 1; ; counter++ {
+		if counter > maxKeyDerivationCounter {
+			return nil, errors.New("unable to derive SigV4a signing key")
+		}
+		kdfContext := append([]byte(accessKey), byte(counter))
+		candidate = hmacKeyDerivation(inputKey, []byte(Algorithm), kdfContext, 256)
+		if new(big.Int).SetBytes(candidate).Cmp(nMinusTwo) < 0 {
+			break
+		}
+	}
+	d := new(big.Int).SetBytes(candidate)
+	d.Add(d, one)
+
+	ecdhKey, err := ecdh.P256().NewPrivateKey(d.FillBytes(make([]byte, 32)))
+	if err != nil {
+		return nil, err
+	}
+	// the uncompressed point is 0x04 || X || Y
+	point := ecdhKey.PublicKey().Bytes()
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(point[1:33]),
+			Y:     new(big.Int).SetBytes(point[33:]),
+		},
+		D: d,
+	}, nil
+}
+
+// hmacKeyDerivation is the NIST SP 800-108 KDF in counter mode with
+// HMAC-SHA256 as the PRF and a 32-bit counter.
+func hmacKeyDerivation(key, label, context []byte, bitLen int) []byte {
+	var fixedInput bytes.Buffer
+	fixedInput.Write(label)
+	fixedInput.WriteByte(0x00)
+	fixedInput.Write(context)
+	_ = binary.Write(&fixedInput, binary.BigEndian, uint32(bitLen))
+
+	h := hmac.New(sha256.New, key)
+	var output []byte
+	for i := uint32(1); len(output) < bitLen/8; i++ {
+		h.Reset()
+		_ = binary.Write(h, binary.BigEndian, i)
+		h.Write(fixedInput.Bytes())
+		output = h.Sum(output)
+	}
+	return output[:bitLen/8]
+}
+
+// buildCanonicalHeaders returns the signed header names and the canonical
+// headers. All the headers but the ignored ones are signed.
+func buildCanonicalHeaders(req *http.Request) (string, string) {
+	values := map[string][]string{"host": {host(req)}}
+	for k, v := range req.Header {
+		name := strings.ToLower(k)
+		if _, ok := ignoredHeaders[name]; ok {
+			continue
+		}
+		values[name] = append(values[name], v...)
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		trimmed := make([]string, len(values[name]))
+		for i, v := range values[name] {
+			trimmed[i] = strings.Join(strings.Fields(v), " ")
+		}
+		canonical.WriteString(name)
+		canonical.WriteByte(':')
+		canonical.WriteString(strings.Join(trimmed, ","))
+		canonical.WriteByte('\n')
+	}
+	return strings.Join(names, ";"), canonical.String()
+}
+
+// host returns the request host without the default port of the scheme.
+func host(req *http.Request) string {
+	h := req.Host
+	if h == "" {
+		h = req.URL.Host
+	}
+	if (req.URL.Scheme == "https" && strings.HasSuffix(h, ":443")) ||
+		(req.URL.Scheme == "http" && strings.HasSuffix(h, ":80")) {
+		h = h[:strings.LastIndex(h, ":")]
+	}
+	return h
+}
+
+// canonicalURI escapes the already escaped path a second time, like SigV4 does
+// for every service but S3.
+func canonicalURI(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	return escapePath(path)
+}
+
+func escapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if isUnreserved(c) || c == '/' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '_' || c == '.' || c == '~'
+}
+
+func canonicalQuery(u *url.URL) string {
+	query := u.Query()
+	for k := range query {
+		sort.Strings(query[k])
+	}
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+func hashPayload(body io.ReadSeeker) (string, error) {
+	if body == nil {
+		return hashHex(nil), nil
+	}
+	start, err := body.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	if _, err = io.Copy(h, body); err != nil {
+		return "", err
+	}
+	if _, err = body.Seek(start, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashHex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
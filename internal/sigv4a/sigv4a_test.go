@@ -0,0 +1,159 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package sigv4a
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
+)
+
+const (
+	testAccessKey = "AKISORANDOMAASORANDOM"
+	testSecretKey = "q+jcrXGc+0zWN6uzclKVhvMmUsIfRPa4rlRandom"
+)
+
+var authorizationPattern = regexp.MustCompile(`^AWS4-ECDSA-P256-SHA256 Credential=([^,]+), SignedHeaders=([^,]+), Signature=([0-9a-f]+)$`)
+
+func TestDeriveKey(t *testing.T) {
+	key, err := deriveKey(testAccessKey, testSecretKey)
+	require.NoError(t, err)
+	wantX, _ := new(big.Int).SetString("15D242CEEBF8D8169FD6A8B5A746C41140414C3B07579038DA06AF89190FFFCB", 16)
+	wantY, _ := new(big.Int).SetString("515242CEDD82E94799482E4C0514B505AFCCF2C0C98D6A553BF539F424C5EC0", 16)
+	assert.Equal(t, 0, wantX.Cmp(key.X))
+	assert.Equal(t, 0, wantY.Cmp(key.Y))
+
+	_, err = deriveKey(testAccessKey, "")
+	assert.Error(t, err)
+}
+
+func TestBuildStringToSign(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "https://logs.us-east-1.amazonaws.com:443/a b/c", nil)
+	require.NoError(t, err)
+	req.URL.RawQuery = "b=2&a=x y&a=1"
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "Logs_20140328.PutLogEvents")
+	req.Header.Set("X-Custom", "  spaced   value ")
+	req.Header.Set("User-Agent", "agent")
+	req.Header.Set(HeaderRegionSet, "us-east-1,us-west-2")
+	signingTime := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	req.Header.Set(headerDate, signingTime.Format(timeFormat))
+
+	signedHeaders, scope, stringToSign := buildStringToSign(req, hashHex(nil), "logs", signingTime)
+	assert.Equal(t, "content-type;host;x-amz-date;x-amz-region-set;x-amz-target;x-custom", signedHeaders)
+	assert.Equal(t, "20240506/logs/aws4_request", scope)
+	canonicalRequest := strings.Join([]string{
+		"POST",
+		"/a%2520b/c",
+		"a=1&a=x%20y&b=2",
+		"content-type:application/x-amz-json-1.1",
+		"host:logs.us-east-1.amazonaws.com",
+		"x-amz-date:20240506T070809Z",
+		"x-amz-region-set:us-east-1,us-west-2",
+		"x-amz-target:Logs_20140328.PutLogEvents",
+		"x-custom:spaced value",
+		"",
+		signedHeaders,
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	}, "\n")
+	want := strings.Join([]string{
+		Algorithm,
+		"20240506T070809Z",
+		"20240506/logs/aws4_request",
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+	assert.Equal(t, want, stringToSign)
+}
+
+// verify checks the signature in the Authorization header with the public key
+// derived from the test credentials.
+func verify(t *testing.T, req *http.Request, body []byte, service string) {
+	t.Helper()
+	match := authorizationPattern.FindStringSubmatch(req.Header.Get(headerAuthorization))
+	require.Len(t, match, 4, req.Header.Get(headerAuthorization))
+	signingTime, err := time.Parse(timeFormat, req.Header.Get(headerDate))
+	require.NoError(t, err)
+	signedHeaders, scope, stringToSign := buildStringToSign(req, hashHex(body), service, signingTime)
+	assert.Equal(t, testAccessKey+"/"+scope, match[1])
+	assert.Equal(t, signedHeaders, match[2])
+	signature, err := hex.DecodeString(match[3])
+	require.NoError(t, err)
+	key, err := deriveKey(testAccessKey, testSecretKey)
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(stringToSign))
+	assert.True(t, ecdsa.VerifyASN1(&key.PublicKey, digest[:], signature))
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"logGroupName":"group"}`)
+	reader := bytes.NewReader(body)
+	req, err := http.NewRequest(http.MethodPost, "https://logs.us-east-1.amazonaws.com/", nil)
+	require.NoError(t, err)
+	req.Header.Set(headerSecurityToken, "stale")
+
+	s := NewSigner([]string{RegionWildcard})
+	creds := credentials.Value{AccessKeyID: testAccessKey, SecretAccessKey: testSecretKey}
+	require.NoError(t, s.Sign(req, reader, creds, "logs", time.Now()))
+	assert.Equal(t, "*", req.Header.Get(HeaderRegionSet))
+	assert.Empty(t, req.Header.Get(headerSecurityToken))
+	verify(t, req, body, "logs")
+	// the body can be read again
+	remaining, _ := io.ReadAll(reader)
+	assert.Equal(t, body, remaining)
+	_, _ = reader.Seek(0, io.SeekStart)
+
+	key := s.key
+	creds.SessionToken = "token"
+	require.NoError(t, s.Sign(req, reader, creds, "logs", time.Now()))
+	assert.Equal(t, "token", req.Header.Get(headerSecurityToken))
+	assert.Same(t, key, s.key)
+	verify(t, req, body, "logs")
+}
+
+func TestConfigure(t *testing.T) {
+	var got *http.Request
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(server.URL),
+		Credentials: credentials.NewStaticCredentials(testAccessKey, testSecretKey, ""),
+	}))
+	client := cloudwatchlogs.New(sess)
+	NewSigner([]string{"us-east-1", "us-west-2"}).Configure(&client.Handlers)
+
+	_, err := client.CreateLogGroup(&cloudwatchlogs.CreateLogGroupInput{LogGroupName: aws.String("group")})
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "us-east-1,us-west-2", got.Header.Get(HeaderRegionSet))
+	// the server sees the request the client signed, plus the header added by
+	// the transport
+	got.Header.Del("Accept-Encoding")
+	got.URL.Scheme = "http"
+	got.URL.Host = got.Host
+	verify(t, got, gotBody, "logs")
+}
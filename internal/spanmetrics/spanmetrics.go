@@ -0,0 +1,172 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package spanmetrics aggregates the request rate, error and duration (RED)
+// metrics of the spans of the traces pipelines. The spanmetrics processor
+// records the spans in an aggregator and the spanmetrics receiver publishes
+// the aggregates of the interval in a metrics pipeline.
+package spanmetrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+const (
+	DimensionServiceName = "service.name"
+	DimensionSpanName    = "span.name"
+	DimensionSpanKind    = "span.kind"
+
+	// MaxSeries is the number of dimension sets an aggregator keeps between
+	// two collections. The spans of the other dimension sets are dropped.
+	MaxSeries = 1000
+
+	// component is the component of the dropped spans in the self telemetry.
+	component = "spanmetrics"
+)
+
+// DefaultBuckets are the upper bounds in milliseconds of the duration
+// histogram buckets.
+var DefaultBuckets = []float64{2, 4, 6, 8, 10, 50, 100, 200, 400, 800, 1000, 1400, 2000, 5000, 10000, 15000}
+
+// Default is the registry of the agent.
+var Default = NewRegistry()
+
+// Dimension is a dimension of a series.
+type Dimension struct {
+	Name  string
+	Value string
+}
+
+// Series are the aggregates of the spans with the same dimensions.
+type Series struct {
+	Dimensions []Dimension
+	Calls      uint64
+	Errors     uint64
+	// Sum, Min and Max are the durations in milliseconds.
+	Sum float64
+	Min float64
+	Max float64
+	// BucketCounts has a count per bound and one for the durations above the
+	// last bound.
+	BucketCounts []uint64
+	Bounds       []float64
+}
+
+// Aggregator aggregates the spans of a processor.
+type Aggregator struct {
+	bounds []float64
+	mu     sync.Mutex
+	series map[string]*Series
+}
+
+// NewAggregator creates an aggregator with the sorted histogram bounds.
+func NewAggregator(bounds []float64) *Aggregator {
+	return &Aggregator{bounds: bounds, series: make(map[string]*Series)}
+}
+
+// Record adds a span to the series of the dimensions. It returns false if the
+// span was dropped because the aggregator has too many series.
+func (a *Aggregator) Record(dimensions []Dimension, durationMs float64, isError bool) bool {
+	k := seriesKey(dimensions)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s, ok := a.series[k]
+	if !ok {
+		if len(a.series) >= MaxSeries {
+			selftelemetry.Add(selftelemetry.MetricDroppedEvents, component, 1)
+			return false
+		}
+		s = &Series{
+			Dimensions:   dimensions,
+			Min:          durationMs,
+			Max:          durationMs,
+			BucketCounts: make([]uint64, len(a.bounds)+1),
+			Bounds:       a.bounds,
+		}
+		a.series[k] = s
+	}
+	s.Calls++
+	if isError {
+		s.Errors++
+	}
+	s.Sum += durationMs
+	s.Min = min(s.Min, durationMs)
+	s.Max = max(s.Max, durationMs)
+	s.BucketCounts[sort.SearchFloat64s(a.bounds, durationMs)]++
+	return true
+}
+
+// collect returns the series of the interval and starts a new one.
+func (a *Aggregator) collect() []*Series {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	series := make([]*Series, 0, len(a.series))
+	for _, s := range a.series {
+		series = append(series, s)
+	}
+	a.series = make(map[string]*Series)
+	return series
+}
+
+// seriesKey joins the dimensions with separators that are unlikely in the
+// attribute values.
+func seriesKey(dimensions []Dimension) string {
+	var sb strings.Builder
+	for _, d := range dimensions {
+		sb.WriteString(d.Name)
+		sb.WriteByte(0)
+		sb.WriteString(d.Value)
+		sb.WriteByte(1)
+	}
+	return sb.String()
+}
+
+// Registry is the set of aggregators of the processors.
+type Registry struct {
+	mu          sync.Mutex
+	aggregators []*Aggregator
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds the aggregator to the registry. The returned function removes
+// it.
+func (r *Registry) Register(a *Aggregator) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.aggregators = append(r.aggregators, a)
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for i, registered := range r.aggregators {
+			if registered == a {
+				r.aggregators = append(r.aggregators[:i], r.aggregators[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Collect returns the series of the aggregators since the previous collection,
+// sorted by dimensions. The series of the same dimensions in several
+// aggregators are returned separately.
+func (r *Registry) Collect() []*Series {
+	r.mu.Lock()
+	aggregators := append([]*Aggregator(nil), r.aggregators...)
+	r.mu.Unlock()
+	var series []*Series
+	for _, a := range aggregators {
+		series = append(series, a.collect()...)
+	}
+	sort.SliceStable(series, func(i, j int) bool {
+		return seriesKey(series[i].Dimensions) < seriesKey(series[j].Dimensions)
+	})
+	return series
+}
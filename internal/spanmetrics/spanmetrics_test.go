@@ -0,0 +1,67 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package spanmetrics
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	assert.Empty(t, r.Collect())
+
+	checkout := []Dimension{{DimensionServiceName, "shop"}, {DimensionSpanName, "GET /checkout"}}
+	cart := []Dimension{{DimensionServiceName, "shop"}, {DimensionSpanName, "GET /cart"}}
+	a := NewAggregator([]float64{10, 100})
+	unregister := r.Register(a)
+	assert.True(t, a.Record(checkout, 5, false))
+	assert.True(t, a.Record(checkout, 150, true))
+	assert.True(t, a.Record(checkout, 10, false))
+	assert.True(t, a.Record(cart, 50, false))
+
+	series := r.Collect()
+	require.Len(t, series, 2)
+	assert.Equal(t, &Series{
+		Dimensions:   cart,
+		Calls:        1,
+		Sum:          50,
+		Min:          50,
+		Max:          50,
+		BucketCounts: []uint64{0, 1, 0},
+		Bounds:       []float64{10, 100},
+	}, series[0])
+	assert.Equal(t, &Series{
+		Dimensions:   checkout,
+		Calls:        3,
+		Errors:       1,
+		Sum:          165,
+		Min:          5,
+		Max:          150,
+		BucketCounts: []uint64{2, 0, 1},
+		Bounds:       []float64{10, 100},
+	}, series[1])
+	// the series are reset by the collection
+	assert.Empty(t, r.Collect())
+
+	a.Record(cart, 1, false)
+	unregister()
+	assert.Empty(t, r.Collect())
+	// unregistering twice is a no-op
+	unregister()
+}
+
+func TestAggregatorMaxSeries(t *testing.T) {
+	a := NewAggregator(DefaultBuckets)
+	for i := 0; i < MaxSeries; i++ {
+		require.True(t, a.Record([]Dimension{{DimensionSpanName, fmt.Sprint(i)}}, 1, false))
+	}
+	assert.False(t, a.Record([]Dimension{{DimensionSpanName, "overflow"}}, 1, false))
+	// the existing series are still recorded
+	assert.True(t, a.Record([]Dimension{{DimensionSpanName, "0"}}, 1, false))
+	assert.Len(t, a.collect(), MaxSeries)
+}
@@ -0,0 +1,141 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package supervisor
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/paths"
+)
+
+const (
+	DefaultHealthyAfter      = 30 * time.Second
+	DefaultCrashLoopRestarts = 3
+	DefaultRestartDelay      = 5 * time.Second
+	DefaultStopTimeout       = 30 * time.Second
+
+	startAgentName = "start-amazon-cloudwatch-agent"
+)
+
+// Config is the supervisor configuration.
+type Config struct {
+	Server  Server  `yaml:"server"`
+	Agent   Agent   `yaml:"agent"`
+	Storage Storage `yaml:"storage"`
+}
+
+// Server is the OpAMP server the supervisor connects to.
+type Server struct {
+	// Endpoint is the ws(s):// or http(s):// URL of the server.
+	Endpoint string            `yaml:"endpoint"`
+	Headers  map[string]string `yaml:"headers"`
+	TLS      TLS               `yaml:"tls"`
+}
+
+// TLS is the TLS configuration of the server connection.
+type TLS struct {
+	CAFile             string `yaml:"ca_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// Agent is the agent process managed by the supervisor.
+type Agent struct {
+	// Executable is started by the supervisor. It defaults to the start
+	// wrapper, which translates ConfigFile before running the agent.
+	Executable string   `yaml:"executable"`
+	Args       []string `yaml:"args"`
+	// Binary is the file replaced by the upgrades of the agent package.
+	Binary string `yaml:"binary"`
+	// ConfigFile is where the remote configs are written to.
+	ConfigFile string `yaml:"config_file"`
+	// HealthyAfter is how long the agent must stay up after a start before
+	// its config is kept as the last known good one.
+	HealthyAfter time.Duration `yaml:"healthy_after"`
+	// CrashLoopRestarts is how many exits before HealthyAfter make a crash
+	// loop, which rolls back the config or the upgrade that caused it.
+	CrashLoopRestarts int           `yaml:"crash_loop_restarts"`
+	RestartDelay      time.Duration `yaml:"restart_delay"`
+	StopTimeout       time.Duration `yaml:"stop_timeout"`
+}
+
+// Storage is where the supervisor persists its state.
+type Storage struct {
+	Directory string `yaml:"directory"`
+}
+
+// DefaultConfig returns the configuration managing the installed agent.
+func DefaultConfig() Config {
+	return Config{
+		Agent: Agent{
+			Executable:        filepath.Join(filepath.Dir(paths.AgentBinaryPath), startAgentName+filepath.Ext(paths.AgentBinaryPath)),
+			Binary:            paths.AgentBinaryPath,
+			ConfigFile:        paths.JsonConfigPath,
+			HealthyAfter:      DefaultHealthyAfter,
+			CrashLoopRestarts: DefaultCrashLoopRestarts,
+			RestartDelay:      DefaultRestartDelay,
+			StopTimeout:       DefaultStopTimeout,
+		},
+		Storage: Storage{
+			Directory: filepath.Join(filepath.Dir(paths.AdminSocketPath), "supervisor"),
+		},
+	}
+}
+
+// LoadConfig reads the YAML configuration file on top of the defaults.
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err = yaml.Unmarshal(content, &cfg); err != nil {
+		return cfg, fmt.Errorf("unable to parse %s: %w", path, err)
+	}
+	return cfg, cfg.Validate()
+}
+
+// Validate checks the configuration is usable.
+func (c Config) Validate() error {
+	if c.Server.Endpoint == "" {
+		return errors.New("server endpoint is required")
+	}
+	u, err := url.Parse(c.Server.Endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid server endpoint: %w", err)
+	}
+	switch u.Scheme {
+	case "ws", "wss", "http", "https":
+	default:
+		return fmt.Errorf("unsupported server endpoint scheme %q", u.Scheme)
+	}
+	if c.Agent.Executable == "" {
+		return errors.New("agent executable is required")
+	}
+	if c.Agent.Binary == "" {
+		return errors.New("agent binary is required")
+	}
+	if c.Agent.ConfigFile == "" {
+		return errors.New("agent config_file is required")
+	}
+	if c.Agent.HealthyAfter <= 0 {
+		return errors.New("agent healthy_after must be positive")
+	}
+	if c.Agent.CrashLoopRestarts < 1 {
+		return errors.New("agent crash_loop_restarts must be at least 1")
+	}
+	if c.Agent.RestartDelay < 0 || c.Agent.StopTimeout < 0 {
+		return errors.New("agent restart_delay and stop_timeout cannot be negative")
+	}
+	if c.Storage.Directory == "" {
+		return errors.New("storage directory is required")
+	}
+	return nil
+}
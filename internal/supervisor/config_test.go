@@ -0,0 +1,69 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "supervisor.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+server:
+  endpoint: wss://opamp.example.com/v1/opamp
+  headers:
+    Authorization: Bearer token
+agent:
+  healthy_after: 1m
+  crash_loop_restarts: 5
+`), 0600))
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "wss://opamp.example.com/v1/opamp", cfg.Server.Endpoint)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer token"}, cfg.Server.Headers)
+	assert.Equal(t, time.Minute, cfg.Agent.HealthyAfter)
+	assert.Equal(t, 5, cfg.Agent.CrashLoopRestarts)
+	// defaults
+	defaults := DefaultConfig()
+	assert.Equal(t, defaults.Agent.Executable, cfg.Agent.Executable)
+	assert.Equal(t, defaults.Agent.ConfigFile, cfg.Agent.ConfigFile)
+	assert.Equal(t, DefaultRestartDelay, cfg.Agent.RestartDelay)
+	assert.Equal(t, defaults.Storage.Directory, cfg.Storage.Directory)
+
+	_, err = LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := DefaultConfig()
+	valid.Server.Endpoint = "https://opamp.example.com/v1/opamp"
+	require.NoError(t, valid.Validate())
+
+	testCases := map[string]func(cfg *Config){
+		"MissingEndpoint": func(cfg *Config) { cfg.Server.Endpoint = "" },
+		"InvalidScheme":   func(cfg *Config) { cfg.Server.Endpoint = "tcp://opamp.example.com" },
+		"MissingExecutable": func(cfg *Config) {
+			cfg.Agent.Executable = ""
+		},
+		"MissingConfigFile":  func(cfg *Config) { cfg.Agent.ConfigFile = "" },
+		"ZeroHealthyAfter":   func(cfg *Config) { cfg.Agent.HealthyAfter = 0 },
+		"ZeroRestarts":       func(cfg *Config) { cfg.Agent.CrashLoopRestarts = 0 },
+		"NegativeDelay":      func(cfg *Config) { cfg.Agent.RestartDelay = -time.Second },
+		"MissingStorageDir":  func(cfg *Config) { cfg.Storage.Directory = "" },
+		"MissingAgentBinary": func(cfg *Config) { cfg.Agent.Binary = "" },
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg := valid
+			testCase(&cfg)
+			assert.Error(t, cfg.Validate())
+		})
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/atomicfile"
+)
+
+const (
+	// agentPackage is the name of the top-level package, which is the agent
+	// binary itself.
+	agentPackage = ""
+
+	packagesStateFile    = "state.json"
+	packagesStatusesFile = "statuses.pb"
+	stagedSuffix         = ".new"
+	previousSuffix       = ".previous"
+)
+
+// packages is the local state of the packages offered by the server. The
+// content of the agent package is staged next to the agent binary until the
+// supervisor installs it, the other packages are only stored.
+type packages struct {
+	mu     sync.Mutex
+	dir    string
+	binary string
+}
+
+var _ types.PackagesStateProvider = (*packages)(nil)
+
+type packagesState struct {
+	AllPackagesHash []byte                  `json:"all_packages_hash,omitempty"`
+	Packages        map[string]packageState `json:"packages,omitempty"`
+}
+
+type packageState struct {
+	Type        protobufs.PackageType `json:"type"`
+	Hash        []byte                `json:"hash,omitempty"`
+	Version     string                `json:"version,omitempty"`
+	ContentHash []byte                `json:"content_hash,omitempty"`
+}
+
+func newPackages(dir, binary string) *packages {
+	return &packages{dir: dir, binary: binary}
+}
+
+// stagedBinary is where the content of the agent package is downloaded to.
+func (p *packages) stagedBinary() string {
+	return p.binary + stagedSuffix
+}
+
+// previousBinary is where the replaced agent binary is kept for rollbacks.
+func (p *packages) previousBinary() string {
+	return p.binary + previousSuffix
+}
+
+func (p *packages) load() (packagesState, error) {
+	var state packagesState
+	content, err := os.ReadFile(filepath.Join(p.dir, packagesStateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	} else if err == nil {
+		err = json.Unmarshal(content, &state)
+	}
+	if state.Packages == nil {
+		state.Packages = map[string]packageState{}
+	}
+	return state, err
+}
+
+func (p *packages) save(state packagesState) error {
+	content, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(filepath.Join(p.dir, packagesStateFile), content, 0600)
+}
+
+// update loads the state, applies the change and saves it back.
+func (p *packages) update(fn func(state *packagesState) error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	state, err := p.load()
+	if err != nil {
+		return err
+	}
+	if err = fn(&state); err != nil {
+		return err
+	}
+	return p.save(state)
+}
+
+func (p *packages) contentPath(name string) string {
+	if name == agentPackage {
+		return p.stagedBinary()
+	}
+	return filepath.Join(p.dir, url.PathEscape(name))
+}
+
+func (p *packages) AllPackagesHash() ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	state, err := p.load()
+	return state.AllPackagesHash, err
+}
+
+func (p *packages) SetAllPackagesHash(hash []byte) error {
+	return p.update(func(state *packagesState) error {
+		state.AllPackagesHash = hash
+		return nil
+	})
+}
+
+func (p *packages) Packages() ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	state, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(state.Packages))
+	for name := range state.Packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (p *packages) PackageState(name string) (types.PackageState, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	state, err := p.load()
+	if err != nil {
+		return types.PackageState{}, err
+	}
+	pkg, ok := state.Packages[name]
+	if !ok {
+		return types.PackageState{}, nil
+	}
+	return types.PackageState{Exists: true, Type: pkg.Type, Hash: pkg.Hash, Version: pkg.Version}, nil
+}
+
+func (p *packages) SetPackageState(name string, s types.PackageState) error {
+	return p.update(func(state *packagesState) error {
+		pkg, ok := state.Packages[name]
+		if !ok {
+			return fmt.Errorf("package %q does not exist", name)
+		}
+		if pkg.Type != s.Type {
+			return fmt.Errorf("package %q type cannot change from %v to %v", name, pkg.Type, s.Type)
+		}
+		pkg.Hash = s.Hash
+		pkg.Version = s.Version
+		state.Packages[name] = pkg
+		return nil
+	})
+}
+
+func (p *packages) CreatePackage(name string, typ protobufs.PackageType) error {
+	return p.update(func(state *packagesState) error {
+		if _, ok := state.Packages[name]; ok {
+			return fmt.Errorf("package %q already exists", name)
+		}
+		state.Packages[name] = packageState{Type: typ}
+		return nil
+	})
+}
+
+func (p *packages) FileContentHash(name string) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	state, err := p.load()
+	return state.Packages[name].ContentHash, err
+}
+
+func (p *packages) UpdateContent(ctx context.Context, name string, data io.Reader, contentHash []byte) error {
+	if err := atomicfile.Write(p.contentPath(name), &contextReader{ctx: ctx, r: data}, 0755); err != nil {
+		return err
+	}
+	return p.update(func(state *packagesState) error {
+		pkg, ok := state.Packages[name]
+		if !ok {
+			return fmt.Errorf("package %q does not exist", name)
+		}
+		pkg.ContentHash = contentHash
+		state.Packages[name] = pkg
+		return nil
+	})
+}
+
+func (p *packages) DeletePackage(name string) error {
+	if err := os.Remove(p.contentPath(name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return p.update(func(state *packagesState) error {
+		delete(state.Packages, name)
+		return nil
+	})
+}
+
+func (p *packages) LastReportedStatuses() (*protobufs.PackageStatuses, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	content, err := os.ReadFile(filepath.Join(p.dir, packagesStatusesFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	statuses := &protobufs.PackageStatuses{}
+	return statuses, proto.Unmarshal(content, statuses)
+}
+
+func (p *packages) SetLastReportedStatuses(statuses *protobufs.PackageStatuses) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	content, err := proto.Marshal(statuses)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(filepath.Join(p.dir, packagesStatusesFile), content, 0600)
+}
+
+// contextReader stops reading once the context is cancelled.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(b []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(b)
+}
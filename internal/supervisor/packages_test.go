@@ -0,0 +1,84 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package supervisor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackages(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "amazon-cloudwatch-agent")
+	p := newPackages(filepath.Join(dir, packagesDir), binary)
+	require.NoError(t, os.MkdirAll(p.dir, 0700))
+
+	names, err := p.Packages()
+	require.NoError(t, err)
+	assert.Empty(t, names)
+	state, err := p.PackageState(agentPackage)
+	require.NoError(t, err)
+	assert.False(t, state.Exists)
+
+	require.NoError(t, p.CreatePackage(agentPackage, protobufs.PackageType_PackageType_TopLevel))
+	require.NoError(t, p.CreatePackage("plugins/jmx", protobufs.PackageType_PackageType_Addon))
+	assert.Error(t, p.CreatePackage(agentPackage, protobufs.PackageType_PackageType_TopLevel))
+	names, err = p.Packages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{agentPackage, "plugins/jmx"}, names)
+
+	// the agent package is staged next to the binary
+	require.NoError(t, p.UpdateContent(context.Background(), agentPackage, strings.NewReader("new agent"), []byte("content")))
+	content, err := os.ReadFile(binary + stagedSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, "new agent", string(content))
+	require.NoError(t, p.UpdateContent(context.Background(), "plugins/jmx", strings.NewReader("jar"), []byte("jar")))
+	content, err = os.ReadFile(filepath.Join(p.dir, "plugins%2Fjmx"))
+	require.NoError(t, err)
+	assert.Equal(t, "jar", string(content))
+	hash, err := p.FileContentHash(agentPackage)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("content"), hash)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, p.UpdateContent(ctx, "plugins/jmx", strings.NewReader("other"), []byte("other")))
+
+	require.NoError(t, p.SetPackageState(agentPackage, types.PackageState{Exists: true, Type: protobufs.PackageType_PackageType_TopLevel, Hash: []byte("hash"), Version: "2.0.0"}))
+	assert.Error(t, p.SetPackageState(agentPackage, types.PackageState{Exists: true, Type: protobufs.PackageType_PackageType_Addon, Hash: []byte("hash"), Version: "2.0.0"}))
+	assert.Error(t, p.SetPackageState("missing", types.PackageState{Exists: true, Type: protobufs.PackageType_PackageType_Addon, Hash: []byte("hash"), Version: "2.0.0"}))
+	state, err = p.PackageState(agentPackage)
+	require.NoError(t, err)
+	assert.True(t, state.Exists)
+	assert.Equal(t, "2.0.0", state.Version)
+	assert.Equal(t, []byte("hash"), state.Hash)
+
+	require.NoError(t, p.SetAllPackagesHash([]byte("all")))
+	allHash, err := p.AllPackagesHash()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("all"), allHash)
+
+	require.NoError(t, p.DeletePackage("plugins/jmx"))
+	_, err = os.Stat(filepath.Join(p.dir, "plugins%2Fjmx"))
+	assert.True(t, os.IsNotExist(err))
+	names, err = p.Packages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{agentPackage}, names)
+
+	statuses, err := p.LastReportedStatuses()
+	require.NoError(t, err)
+	assert.Nil(t, statuses)
+	require.NoError(t, p.SetLastReportedStatuses(&protobufs.PackageStatuses{ServerProvidedAllPackagesHash: []byte("all")}))
+	statuses, err = p.LastReportedStatuses()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("all"), statuses.GetServerProvidedAllPackagesHash())
+}
@@ -0,0 +1,70 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package supervisor
+
+import (
+	"os"
+	"os/exec"
+	"time"
+)
+
+// process is a started agent.
+type process interface {
+	// Exited is closed once the process exited. Err is only set after.
+	Exited() <-chan struct{}
+	Err() error
+	// Stop asks the process to exit and kills it after the timeout.
+	Stop(timeout time.Duration)
+}
+
+// startFunc starts the agent.
+type startFunc func(executable string, args []string) (process, error)
+
+// command is a process started from the agent executable.
+type command struct {
+	cmd    *exec.Cmd
+	exited chan struct{}
+	err    error
+}
+
+var _ process = (*command)(nil)
+
+func startCommand(executable string, args []string) (process, error) {
+	cmd := exec.Command(executable, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	c := &command{cmd: cmd, exited: make(chan struct{})}
+	go func() {
+		c.err = cmd.Wait()
+		close(c.exited)
+	}()
+	return c, nil
+}
+
+func (c *command) Exited() <-chan struct{} {
+	return c.exited
+}
+
+func (c *command) Err() error {
+	return c.err
+}
+
+func (c *command) Stop(timeout time.Duration) {
+	select {
+	case <-c.exited:
+		return
+	default:
+	}
+	terminate(c.cmd)
+	select {
+	case <-c.exited:
+	case <-time.After(timeout):
+		kill(c.cmd)
+		<-c.exited
+	}
+}
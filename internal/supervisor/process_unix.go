@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package supervisor
+
+import (
+	"log"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the agent in its own process group so that the
+// processes it starts are stopped with it.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func terminate(cmd *exec.Cmd) {
+	if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM); err != nil {
+		log.Printf("E! [supervisor] Error terminating the agent: %v", err)
+	}
+}
+
+func kill(cmd *exec.Cmd) {
+	if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); err != nil {
+		log.Printf("E! [supervisor] Error killing the agent: %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build windows
+
+package supervisor
+
+import (
+	"log"
+	"os/exec"
+	"strconv"
+)
+
+func setProcessGroup(*exec.Cmd) {
+}
+
+// terminate kills the process tree since the start wrapper runs the agent
+// as a child process and Windows has no graceful signal to forward.
+func terminate(cmd *exec.Cmd) {
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run(); err != nil {
+		log.Printf("E! [supervisor] Error terminating the agent: %v", err)
+	}
+}
+
+func kill(cmd *exec.Cmd) {
+	if err := cmd.Process.Kill(); err != nil {
+		log.Printf("E! [supervisor] Error killing the agent: %v", err)
+	}
+}
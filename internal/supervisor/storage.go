@@ -0,0 +1,88 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package supervisor
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/atomicfile"
+)
+
+const (
+	instanceUIDFile        = "instance_uid"
+	lastKnownGoodFile      = "last_known_good.json"
+	remoteConfigStatusFile = "remote_config_status.pb"
+	packagesDir            = "packages"
+)
+
+// storage persists the supervisor state across restarts.
+type storage struct {
+	dir string
+}
+
+func newStorage(dir string) (storage, error) {
+	return storage{dir: dir}, os.MkdirAll(filepath.Join(dir, packagesDir), 0700)
+}
+
+// instanceUID returns the identity of the agent on the server, which is
+// generated on the first start.
+func (s storage) instanceUID() ([16]byte, error) {
+	path := filepath.Join(s.dir, instanceUIDFile)
+	content, err := os.ReadFile(path)
+	if err == nil {
+		var id uuid.UUID
+		if id, err = uuid.ParseBytes(content); err == nil {
+			return id, nil
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return uuid.Nil, err
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, atomicfile.WriteFile(path, []byte(id.String()), 0600)
+}
+
+// lastKnownGood returns the last config the agent stayed healthy with, nil
+// if there is none yet.
+func (s storage) lastKnownGood() ([]byte, error) {
+	content, err := os.ReadFile(filepath.Join(s.dir, lastKnownGoodFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return content, err
+}
+
+func (s storage) saveLastKnownGood(config []byte) error {
+	return atomicfile.WriteFile(filepath.Join(s.dir, lastKnownGoodFile), config, 0600)
+}
+
+// remoteConfigStatus returns the status last reported to the server, nil if
+// no remote config was received yet.
+func (s storage) remoteConfigStatus() (*protobufs.RemoteConfigStatus, error) {
+	content, err := os.ReadFile(filepath.Join(s.dir, remoteConfigStatusFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	status := &protobufs.RemoteConfigStatus{}
+	return status, proto.Unmarshal(content, status)
+}
+
+func (s storage) saveRemoteConfigStatus(status *protobufs.RemoteConfigStatus) error {
+	content, err := proto.Marshal(status)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(filepath.Join(s.dir, remoteConfigStatusFile), content, 0600)
+}
@@ -0,0 +1,587 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package supervisor manages the agent process on behalf of an OpAMP server.
+// It writes the configs pushed by the server, installs the agent package
+// upgrades and rolls either back when the agent crash loops after them.
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/client"
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/atomicfile"
+	"github.com/aws/amazon-cloudwatch-agent/internal/version"
+)
+
+const (
+	serviceName     = "amazon-cloudwatch-agent"
+	jsonContentType = "application/json"
+)
+
+// opampClient is the part of the OpAMP client the supervisor reports to.
+type opampClient interface {
+	SetHealth(health *protobufs.ComponentHealth) error
+	SetRemoteConfigStatus(status *protobufs.RemoteConfigStatus) error
+	SetPackageStatuses(statuses *protobufs.PackageStatuses) error
+	UpdateEffectiveConfig(ctx context.Context) error
+}
+
+// Supervisor runs the agent and applies what the OpAMP server pushes to it.
+type Supervisor struct {
+	cfg      Config
+	storage  storage
+	packages *packages
+	start    startFunc
+	client   opampClient
+
+	remoteConfigs chan *protobufs.AgentRemoteConfig
+	synced        chan struct{}
+	restarts      chan struct{}
+
+	// the state below is only accessed from the run loop
+	agent   process
+	started time.Time
+	healthy <-chan time.Time
+	restart <-chan time.Time
+	// exits counts the exits since the agent was last healthy
+	exits int
+	// configPending is set while the agent did not stay healthy with the
+	// last remote config yet
+	configPending bool
+	// upgradePending is set while the agent did not stay healthy since the
+	// last upgrade yet
+	upgradePending bool
+}
+
+// New creates a supervisor from the configuration.
+func New(cfg Config) (*Supervisor, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return newSupervisor(cfg, startCommand)
+}
+
+func newSupervisor(cfg Config, start startFunc) (*Supervisor, error) {
+	s, err := newStorage(cfg.Storage.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the storage directory: %w", err)
+	}
+	return &Supervisor{
+		cfg:           cfg,
+		storage:       s,
+		packages:      newPackages(filepath.Join(cfg.Storage.Directory, packagesDir), cfg.Agent.Binary),
+		start:         start,
+		remoteConfigs: make(chan *protobufs.AgentRemoteConfig, 1),
+		synced:        make(chan struct{}, 1),
+		restarts:      make(chan struct{}, 1),
+	}, nil
+}
+
+// Run connects to the server and supervises the agent until the context is
+// cancelled, which stops the agent.
+func (s *Supervisor) Run(ctx context.Context) error {
+	settings, err := s.startSettings()
+	if err != nil {
+		return err
+	}
+	var c client.OpAMPClient
+	if strings.HasPrefix(s.cfg.Server.Endpoint, "ws") {
+		c = client.NewWebSocket(logger{})
+	} else {
+		c = client.NewHTTP(logger{})
+	}
+	if err = c.SetAgentDescription(agentDescription()); err != nil {
+		return err
+	}
+	if err = c.Start(ctx, settings); err != nil {
+		return fmt.Errorf("unable to start the OpAMP client: %w", err)
+	}
+	defer func() {
+		if err := c.Stop(context.Background()); err != nil {
+			log.Printf("W! [supervisor] Error stopping the OpAMP client: %v", err)
+		}
+	}()
+	s.client = c
+	s.run(ctx)
+	return nil
+}
+
+func (s *Supervisor) startSettings() (types.StartSettings, error) {
+	uid, err := s.storage.instanceUID()
+	if err != nil {
+		return types.StartSettings{}, fmt.Errorf("unable to load the instance UID: %w", err)
+	}
+	status, err := s.storage.remoteConfigStatus()
+	if err != nil {
+		return types.StartSettings{}, fmt.Errorf("unable to load the remote config status: %w", err)
+	}
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return types.StartSettings{}, err
+	}
+	header := http.Header{}
+	for k, v := range s.cfg.Server.Headers {
+		header.Set(k, v)
+	}
+	return types.StartSettings{
+		OpAMPServerURL:        s.cfg.Server.Endpoint,
+		Header:                header,
+		TLSConfig:             tlsConfig,
+		InstanceUid:           uid,
+		RemoteConfigStatus:    status,
+		PackagesStateProvider: s.packages,
+		Capabilities: protobufs.AgentCapabilities_AgentCapabilities_ReportsStatus |
+			protobufs.AgentCapabilities_AgentCapabilities_AcceptsRemoteConfig |
+			protobufs.AgentCapabilities_AgentCapabilities_ReportsRemoteConfig |
+			protobufs.AgentCapabilities_AgentCapabilities_ReportsEffectiveConfig |
+			protobufs.AgentCapabilities_AgentCapabilities_AcceptsPackages |
+			protobufs.AgentCapabilities_AgentCapabilities_ReportsPackageStatuses |
+			protobufs.AgentCapabilities_AgentCapabilities_AcceptsRestartCommand |
+			protobufs.AgentCapabilities_AgentCapabilities_ReportsHealth,
+		Callbacks: types.CallbacksStruct{
+			OnConnectFunc: func(context.Context) {
+				log.Printf("I! [supervisor] Connected to %s", s.cfg.Server.Endpoint)
+			},
+			OnConnectFailedFunc: func(_ context.Context, err error) {
+				log.Printf("W! [supervisor] Unable to connect to %s: %v", s.cfg.Server.Endpoint, err)
+			},
+			OnErrorFunc: func(_ context.Context, err *protobufs.ServerErrorResponse) {
+				log.Printf("E! [supervisor] Server error: %s", err.GetErrorMessage())
+			},
+			OnMessageFunc:          s.onMessage,
+			OnCommandFunc:          s.onCommand,
+			GetEffectiveConfigFunc: s.effectiveConfig,
+		},
+	}, nil
+}
+
+func (s *Supervisor) tlsConfig() (*tls.Config, error) {
+	if !strings.HasPrefix(s.cfg.Server.Endpoint, "wss") && !strings.HasPrefix(s.cfg.Server.Endpoint, "https") {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: s.cfg.Server.TLS.InsecureSkipVerify, // #nosec G402 -- opt-in for test servers
+	}
+	if s.cfg.Server.TLS.CAFile != "" {
+		pem, err := os.ReadFile(s.cfg.Server.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the server CA file: %w", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", s.cfg.Server.TLS.CAFile)
+		}
+	}
+	return cfg, nil
+}
+
+func agentDescription() *protobufs.AgentDescription {
+	hostname, _ := os.Hostname()
+	return &protobufs.AgentDescription{
+		IdentifyingAttributes: []*protobufs.KeyValue{
+			stringKeyValue("service.name", serviceName),
+			stringKeyValue("service.version", version.Number()),
+		},
+		NonIdentifyingAttributes: []*protobufs.KeyValue{
+			stringKeyValue("host.name", hostname),
+		},
+	}
+}
+
+func stringKeyValue(key, value string) *protobufs.KeyValue {
+	return &protobufs.KeyValue{
+		Key:   key,
+		Value: &protobufs.AnyValue{Value: &protobufs.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+// onMessage hands the message over to the run loop. The packages are synced
+// in the background and installed by the run loop once done.
+func (s *Supervisor) onMessage(ctx context.Context, msg *types.MessageData) {
+	if msg.RemoteConfig != nil {
+		// only the latest config matters
+		select {
+		case <-s.remoteConfigs:
+		default:
+		}
+		s.remoteConfigs <- msg.RemoteConfig
+	}
+	if msg.PackageSyncer != nil {
+		if err := msg.PackageSyncer.Sync(ctx); err != nil {
+			log.Printf("E! [supervisor] Unable to sync the packages: %v", err)
+			return
+		}
+		go func() {
+			<-msg.PackageSyncer.Done()
+			notify(s.synced)
+		}()
+	}
+}
+
+func (s *Supervisor) onCommand(_ context.Context, command *protobufs.ServerToAgentCommand) error {
+	if command.GetType() != protobufs.CommandType_CommandType_Restart {
+		return fmt.Errorf("unsupported command %v", command.GetType())
+	}
+	notify(s.restarts)
+	return nil
+}
+
+func (s *Supervisor) effectiveConfig(context.Context) (*protobufs.EffectiveConfig, error) {
+	content, err := os.ReadFile(s.cfg.Agent.ConfigFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return &protobufs.EffectiveConfig{
+		ConfigMap: &protobufs.AgentConfigMap{
+			ConfigMap: map[string]*protobufs.AgentConfigFile{
+				"": {Body: content, ContentType: jsonContentType},
+			},
+		},
+	}, nil
+}
+
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// run is the loop owning the agent process.
+func (s *Supervisor) run(ctx context.Context) {
+	s.startAgent()
+	for {
+		var exited <-chan struct{}
+		if s.agent != nil {
+			exited = s.agent.Exited()
+		}
+		select {
+		case <-ctx.Done():
+			s.stopAgent()
+			return
+		case rc := <-s.remoteConfigs:
+			s.applyRemoteConfig(ctx, rc)
+		case <-s.synced:
+			s.installUpgrade()
+		case <-s.restarts:
+			log.Printf("I! [supervisor] Restarting the agent on request of the server")
+			s.restartAgent()
+		case <-exited:
+			s.onExit(ctx)
+		case <-s.healthy:
+			s.onHealthy()
+		case <-s.restart:
+			s.restart = nil
+			s.startAgent()
+		}
+	}
+}
+
+func (s *Supervisor) startAgent() {
+	s.restart = nil
+	agent, err := s.start(s.cfg.Agent.Executable, s.cfg.Agent.Args)
+	if err != nil {
+		log.Printf("E! [supervisor] Unable to start the agent: %v", err)
+		s.reportHealth(false, err)
+		s.restart = time.After(s.cfg.Agent.RestartDelay)
+		return
+	}
+	log.Printf("I! [supervisor] Started the agent")
+	s.agent = agent
+	s.started = time.Now()
+	s.healthy = time.After(s.cfg.Agent.HealthyAfter)
+	s.reportHealth(true, nil)
+}
+
+func (s *Supervisor) stopAgent() {
+	s.healthy = nil
+	s.restart = nil
+	if s.agent == nil {
+		return
+	}
+	s.agent.Stop(s.cfg.Agent.StopTimeout)
+	s.agent = nil
+}
+
+func (s *Supervisor) restartAgent() {
+	s.stopAgent()
+	s.exits = 0
+	s.startAgent()
+}
+
+func (s *Supervisor) onExit(ctx context.Context) {
+	err := s.agent.Err()
+	if err == nil {
+		err = errors.New("agent exited")
+	}
+	log.Printf("E! [supervisor] Agent exited: %v", err)
+	s.agent = nil
+	s.healthy = nil
+	s.exits++
+	if s.exits >= s.cfg.Agent.CrashLoopRestarts {
+		s.rollback(ctx, err)
+		s.exits = 0
+	}
+	s.reportHealth(false, err)
+	s.restart = time.After(s.cfg.Agent.RestartDelay)
+}
+
+// onHealthy keeps what the agent stayed up with.
+func (s *Supervisor) onHealthy() {
+	s.healthy = nil
+	s.exits = 0
+	if s.upgradePending {
+		log.Printf("I! [supervisor] Agent is healthy after the upgrade")
+		s.upgradePending = false
+	}
+	if s.configPending {
+		s.configPending = false
+		content, err := os.ReadFile(s.cfg.Agent.ConfigFile)
+		if err == nil {
+			err = s.storage.saveLastKnownGood(content)
+		}
+		if err != nil {
+			log.Printf("E! [supervisor] Unable to save the last known good config: %v", err)
+		}
+		s.setRemoteConfigStatus(protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED, "")
+	}
+}
+
+// rollback undoes the upgrade or the config the agent crash loops with.
+func (s *Supervisor) rollback(ctx context.Context, cause error) {
+	switch {
+	case s.upgradePending:
+		s.upgradePending = false
+		s.rollbackUpgrade(cause)
+	case s.configPending:
+		s.configPending = false
+		s.rollbackConfig(ctx, cause)
+	default:
+		log.Printf("E! [supervisor] Agent is crash looping")
+	}
+}
+
+func (s *Supervisor) rollbackConfig(ctx context.Context, cause error) {
+	message := fmt.Sprintf("agent crash looped with the config: %v", cause)
+	lastKnownGood, err := s.storage.lastKnownGood()
+	if err == nil && lastKnownGood != nil {
+		err = atomicfile.WriteFile(s.cfg.Agent.ConfigFile, lastKnownGood, 0644)
+		if err == nil {
+			log.Printf("W! [supervisor] Rolled back to the last known good config")
+			message += ", rolled back to the last known good config"
+			s.updateEffectiveConfig(ctx)
+		}
+	}
+	if err != nil {
+		log.Printf("E! [supervisor] Unable to roll back the config: %v", err)
+	}
+	s.setRemoteConfigStatus(protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, message)
+}
+
+func (s *Supervisor) rollbackUpgrade(cause error) {
+	message := fmt.Sprintf("agent crash looped after the upgrade: %v", cause)
+	if err := os.Rename(s.packages.previousBinary(), s.cfg.Agent.Binary); err != nil {
+		log.Printf("E! [supervisor] Unable to roll back the upgrade: %v", err)
+	} else {
+		log.Printf("W! [supervisor] Rolled back to the previous agent binary")
+		message += ", rolled back to the previous version"
+	}
+	statuses, err := s.packages.LastReportedStatuses()
+	if err != nil || statuses == nil {
+		statuses = &protobufs.PackageStatuses{}
+	}
+	if statuses.Packages == nil {
+		statuses.Packages = map[string]*protobufs.PackageStatus{}
+	}
+	status, ok := statuses.Packages[agentPackage]
+	if !ok {
+		status = &protobufs.PackageStatus{Name: agentPackage}
+		statuses.Packages[agentPackage] = status
+	}
+	status.Status = protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed
+	status.ErrorMessage = message
+	if err = s.packages.SetLastReportedStatuses(statuses); err != nil {
+		log.Printf("E! [supervisor] Unable to save the package statuses: %v", err)
+	}
+	if s.client != nil {
+		if err = s.client.SetPackageStatuses(statuses); err != nil {
+			log.Printf("E! [supervisor] Unable to report the package statuses: %v", err)
+		}
+	}
+}
+
+// installUpgrade replaces the agent binary with the synced agent package, if
+// any, keeping the replaced binary for rollbacks.
+func (s *Supervisor) installUpgrade() {
+	staged := s.packages.stagedBinary()
+	if _, err := os.Stat(staged); err != nil {
+		return
+	}
+	s.stopAgent()
+	binary := s.cfg.Agent.Binary
+	previous := s.packages.previousBinary()
+	if err := os.Rename(binary, previous); err != nil {
+		log.Printf("E! [supervisor] Unable to back up the agent binary: %v", err)
+	} else if err = os.Rename(staged, binary); err != nil {
+		log.Printf("E! [supervisor] Unable to install the agent package: %v", err)
+		if err = os.Rename(previous, binary); err != nil {
+			log.Printf("E! [supervisor] Unable to restore the agent binary: %v", err)
+		}
+	} else {
+		log.Printf("I! [supervisor] Installed the agent package")
+		s.upgradePending = true
+	}
+	s.exits = 0
+	s.startAgent()
+}
+
+// applyRemoteConfig writes the remote config and restarts the agent with it.
+func (s *Supervisor) applyRemoteConfig(ctx context.Context, rc *protobufs.AgentRemoteConfig) {
+	status, err := s.storage.remoteConfigStatus()
+	if err != nil {
+		log.Printf("W! [supervisor] Unable to load the remote config status: %v", err)
+	}
+	if status != nil && bytes.Equal(status.GetLastRemoteConfigHash(), rc.GetConfigHash()) {
+		return
+	}
+	content, err := remoteConfigContent(rc)
+	if err != nil {
+		log.Printf("E! [supervisor] Rejected the remote config: %v", err)
+		s.setRemoteConfigStatusWithHash(rc.GetConfigHash(), protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, err.Error())
+		return
+	}
+	if err = s.snapshotLastKnownGood(); err != nil {
+		log.Printf("W! [supervisor] Unable to save the current config as the last known good one: %v", err)
+	}
+	if err = atomicfile.WriteFile(s.cfg.Agent.ConfigFile, content, 0644); err != nil {
+		log.Printf("E! [supervisor] Unable to write the remote config: %v", err)
+		s.setRemoteConfigStatusWithHash(rc.GetConfigHash(), protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, err.Error())
+		return
+	}
+	log.Printf("I! [supervisor] Applying the remote config")
+	s.configPending = true
+	s.setRemoteConfigStatusWithHash(rc.GetConfigHash(), protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLYING, "")
+	s.updateEffectiveConfig(ctx)
+	s.restartAgent()
+}
+
+// snapshotLastKnownGood keeps the config the agent runs with before the
+// first remote config, so that there is always one to roll back to.
+func (s *Supervisor) snapshotLastKnownGood() error {
+	lastKnownGood, err := s.storage.lastKnownGood()
+	if err != nil || lastKnownGood != nil {
+		return err
+	}
+	content, err := os.ReadFile(s.cfg.Agent.ConfigFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return s.storage.saveLastKnownGood(content)
+}
+
+// remoteConfigContent returns the agent JSON config of the remote config,
+// which must have a single file.
+func remoteConfigContent(rc *protobufs.AgentRemoteConfig) ([]byte, error) {
+	files := rc.GetConfig().GetConfigMap()
+	file, ok := files[""]
+	if !ok {
+		if len(files) != 1 {
+			return nil, fmt.Errorf("expected a single config file, got %d", len(files))
+		}
+		for _, f := range files {
+			file = f
+		}
+	}
+	if file.GetContentType() != "" && file.GetContentType() != jsonContentType {
+		return nil, fmt.Errorf("unsupported content type %q", file.GetContentType())
+	}
+	if !json.Valid(file.GetBody()) {
+		return nil, errors.New("config is not valid JSON")
+	}
+	return file.GetBody(), nil
+}
+
+func (s *Supervisor) setRemoteConfigStatus(status protobufs.RemoteConfigStatuses, message string) {
+	var hash []byte
+	if last, err := s.storage.remoteConfigStatus(); err == nil && last != nil {
+		hash = last.GetLastRemoteConfigHash()
+	}
+	s.setRemoteConfigStatusWithHash(hash, status, message)
+}
+
+func (s *Supervisor) setRemoteConfigStatusWithHash(hash []byte, status protobufs.RemoteConfigStatuses, message string) {
+	rcs := &protobufs.RemoteConfigStatus{
+		LastRemoteConfigHash: hash,
+		Status:               status,
+		ErrorMessage:         message,
+	}
+	if err := s.storage.saveRemoteConfigStatus(rcs); err != nil {
+		log.Printf("E! [supervisor] Unable to save the remote config status: %v", err)
+	}
+	if s.client != nil {
+		if err := s.client.SetRemoteConfigStatus(rcs); err != nil {
+			log.Printf("E! [supervisor] Unable to report the remote config status: %v", err)
+		}
+	}
+}
+
+func (s *Supervisor) updateEffectiveConfig(ctx context.Context) {
+	if s.client == nil {
+		return
+	}
+	if err := s.client.UpdateEffectiveConfig(ctx); err != nil {
+		log.Printf("E! [supervisor] Unable to report the effective config: %v", err)
+	}
+}
+
+func (s *Supervisor) reportHealth(healthy bool, err error) {
+	if s.client == nil {
+		return
+	}
+	health := &protobufs.ComponentHealth{Healthy: healthy}
+	if healthy {
+		health.StartTimeUnixNano = uint64(s.started.UnixNano())
+		health.Status = "running"
+	} else {
+		health.Status = "stopped"
+		if err != nil {
+			health.LastError = err.Error()
+		}
+	}
+	if err := s.client.SetHealth(health); err != nil {
+		log.Printf("E! [supervisor] Unable to report the health: %v", err)
+	}
+}
+
+// logger writes the OpAMP client logs to the supervisor log.
+type logger struct{}
+
+var _ types.Logger = logger{}
+
+func (logger) Debugf(_ context.Context, format string, v ...any) {
+	log.Printf("D! [supervisor] "+format, v...)
+}
+
+func (logger) Errorf(_ context.Context, format string, v ...any) {
+	log.Printf("E! [supervisor] "+format, v...)
+}
@@ -0,0 +1,274 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	goodConfig = `{"agent":{"metrics_collection_interval":60}}`
+	badConfig  = `{"agent":{"metrics_collection_interval":"crash"}}`
+)
+
+// fakeProcess exits on its own if it crashes.
+type fakeProcess struct {
+	once   sync.Once
+	exited chan struct{}
+	err    error
+}
+
+func newFakeProcess(crash bool) *fakeProcess {
+	p := &fakeProcess{exited: make(chan struct{})}
+	if crash {
+		p.exit(errors.New("exit status 1"))
+	}
+	return p
+}
+
+func (p *fakeProcess) exit(err error) {
+	p.once.Do(func() {
+		p.err = err
+		close(p.exited)
+	})
+}
+
+func (p *fakeProcess) Exited() <-chan struct{} {
+	return p.exited
+}
+
+func (p *fakeProcess) Err() error {
+	return p.err
+}
+
+func (p *fakeProcess) Stop(time.Duration) {
+	p.exit(nil)
+}
+
+type fakeClient struct {
+	mu                 sync.Mutex
+	remoteConfigStatus *protobufs.RemoteConfigStatus
+	packageStatuses    *protobufs.PackageStatuses
+	health             *protobufs.ComponentHealth
+}
+
+var _ opampClient = (*fakeClient)(nil)
+
+func (c *fakeClient) SetHealth(health *protobufs.ComponentHealth) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.health = health
+	return nil
+}
+
+func (c *fakeClient) SetRemoteConfigStatus(status *protobufs.RemoteConfigStatus) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remoteConfigStatus = status
+	return nil
+}
+
+func (c *fakeClient) SetPackageStatuses(statuses *protobufs.PackageStatuses) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.packageStatuses = statuses
+	return nil
+}
+
+func (c *fakeClient) UpdateEffectiveConfig(context.Context) error {
+	return nil
+}
+
+func (c *fakeClient) configStatus() protobufs.RemoteConfigStatuses {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.remoteConfigStatus.GetStatus()
+}
+
+func (c *fakeClient) agentPackageStatus() protobufs.PackageStatusEnum {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.packageStatuses.GetPackages()[agentPackage].GetStatus()
+}
+
+// newTestSupervisor returns a running supervisor whose agent crashes when
+// started with the bad config or with a binary containing "crash".
+func newTestSupervisor(t *testing.T) (*Supervisor, *fakeClient) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.Server.Endpoint = "ws://localhost:4320/v1/opamp"
+	cfg.Agent.Executable = filepath.Join(dir, "start-amazon-cloudwatch-agent")
+	cfg.Agent.Binary = filepath.Join(dir, "amazon-cloudwatch-agent")
+	cfg.Agent.ConfigFile = filepath.Join(dir, "amazon-cloudwatch-agent.json")
+	cfg.Agent.HealthyAfter = 100 * time.Millisecond
+	cfg.Agent.CrashLoopRestarts = 2
+	cfg.Agent.RestartDelay = time.Millisecond
+	cfg.Storage.Directory = filepath.Join(dir, "supervisor")
+	require.NoError(t, cfg.Validate())
+	require.NoError(t, os.WriteFile(cfg.Agent.ConfigFile, []byte(goodConfig), 0600))
+	require.NoError(t, os.WriteFile(cfg.Agent.Binary, []byte("agent v1"), 0600))
+
+	start := func(string, []string) (process, error) {
+		config, err := os.ReadFile(cfg.Agent.ConfigFile)
+		if err != nil {
+			return nil, err
+		}
+		binary, err := os.ReadFile(cfg.Agent.Binary)
+		if err != nil {
+			return nil, err
+		}
+		return newFakeProcess(string(config) == badConfig || string(binary) == "crash"), nil
+	}
+	s, err := newSupervisor(cfg, start)
+	require.NoError(t, err)
+	client := &fakeClient{}
+	s.client = client
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return s, client
+}
+
+func remoteConfig(hash, body string) *protobufs.AgentRemoteConfig {
+	return &protobufs.AgentRemoteConfig{
+		ConfigHash: []byte(hash),
+		Config: &protobufs.AgentConfigMap{
+			ConfigMap: map[string]*protobufs.AgentConfigFile{
+				"": {Body: []byte(body), ContentType: jsonContentType},
+			},
+		},
+	}
+}
+
+func TestRemoteConfigApplied(t *testing.T) {
+	s, client := newTestSupervisor(t)
+	newConfig := `{"agent":{"metrics_collection_interval":10}}`
+	s.remoteConfigs <- remoteConfig("v2", newConfig)
+
+	assert.Eventually(t, func() bool {
+		return client.configStatus() == protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED
+	}, 5*time.Second, 10*time.Millisecond)
+	content, err := os.ReadFile(s.cfg.Agent.ConfigFile)
+	require.NoError(t, err)
+	assert.Equal(t, newConfig, string(content))
+	lastKnownGood, err := s.storage.lastKnownGood()
+	require.NoError(t, err)
+	assert.Equal(t, newConfig, string(lastKnownGood))
+	status, err := s.storage.remoteConfigStatus()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v2"), status.GetLastRemoteConfigHash())
+}
+
+func TestRemoteConfigRollback(t *testing.T) {
+	s, client := newTestSupervisor(t)
+	s.remoteConfigs <- remoteConfig("bad", badConfig)
+
+	assert.Eventually(t, func() bool {
+		return client.configStatus() == protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED
+	}, 5*time.Second, 10*time.Millisecond)
+	content, err := os.ReadFile(s.cfg.Agent.ConfigFile)
+	require.NoError(t, err)
+	assert.Equal(t, goodConfig, string(content))
+	// the agent recovers with the last known good config
+	assert.Eventually(t, func() bool {
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		return client.health.GetHealthy()
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, client.configStatus())
+	status, err := s.storage.remoteConfigStatus()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("bad"), status.GetLastRemoteConfigHash())
+	assert.Contains(t, status.GetErrorMessage(), "rolled back")
+}
+
+func TestRemoteConfigInvalid(t *testing.T) {
+	s, client := newTestSupervisor(t)
+	s.remoteConfigs <- remoteConfig("invalid", "{not json")
+
+	assert.Eventually(t, func() bool {
+		return client.configStatus() == protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED
+	}, 5*time.Second, 10*time.Millisecond)
+	content, err := os.ReadFile(s.cfg.Agent.ConfigFile)
+	require.NoError(t, err)
+	assert.Equal(t, goodConfig, string(content))
+}
+
+func TestUpgradeRollback(t *testing.T) {
+	s, client := newTestSupervisor(t)
+	require.NoError(t, os.WriteFile(s.packages.stagedBinary(), []byte("crash"), 0600))
+	notify(s.synced)
+
+	assert.Eventually(t, func() bool {
+		return client.agentPackageStatus() == protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed
+	}, 5*time.Second, 10*time.Millisecond)
+	content, err := os.ReadFile(s.cfg.Agent.Binary)
+	require.NoError(t, err)
+	assert.Equal(t, "agent v1", string(content))
+}
+
+func TestUpgrade(t *testing.T) {
+	s, client := newTestSupervisor(t)
+	require.NoError(t, os.WriteFile(s.packages.stagedBinary(), []byte("agent v2"), 0600))
+	notify(s.synced)
+
+	assert.Eventually(t, func() bool {
+		content, err := os.ReadFile(s.cfg.Agent.Binary)
+		return err == nil && string(content) == "agent v2"
+	}, 5*time.Second, 10*time.Millisecond)
+	content, err := os.ReadFile(s.packages.previousBinary())
+	require.NoError(t, err)
+	assert.Equal(t, "agent v1", string(content))
+	assert.NotEqual(t, protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed, client.agentPackageStatus())
+}
+
+func TestRemoteConfigContent(t *testing.T) {
+	content, err := remoteConfigContent(&protobufs.AgentRemoteConfig{
+		Config: &protobufs.AgentConfigMap{
+			ConfigMap: map[string]*protobufs.AgentConfigFile{
+				"agent.json": {Body: []byte(goodConfig)},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, goodConfig, string(content))
+
+	_, err = remoteConfigContent(&protobufs.AgentRemoteConfig{
+		Config: &protobufs.AgentConfigMap{
+			ConfigMap: map[string]*protobufs.AgentConfigFile{
+				"a.json": {Body: []byte(goodConfig)},
+				"b.json": {Body: []byte(goodConfig)},
+			},
+		},
+	})
+	assert.Error(t, err)
+
+	_, err = remoteConfigContent(&protobufs.AgentRemoteConfig{
+		Config: &protobufs.AgentConfigMap{
+			ConfigMap: map[string]*protobufs.AgentConfigFile{
+				"": {Body: []byte("receivers: {}"), ContentType: "text/yaml"},
+			},
+		},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,252 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package tls
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay groups the events of a rotation, e.g. the certificate and the
+// key written one after the other, into a single reload.
+var reloadDelay = time.Second
+
+// Provider serves the certificate and the CA certificates of a TLS config
+// from their files, and reloads them when the files change. The listeners and
+// the clients using the config pick up a rotated certificate on their next
+// handshake, so they do not need to be restarted.
+type Provider struct {
+	certFile string
+	keyFile  string
+	caFiles  []string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	pool *x509.CertPool
+
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewProvider loads the certificate and key pair and the CA certificates.
+// Either may be empty.
+func NewProvider(certFile, keyFile string, caFiles []string) (*Provider, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("both the certificate and the key files are required")
+	}
+	p := &Provider{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFiles:  caFiles,
+		done:     make(chan struct{}),
+	}
+	if _, err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Start watches the directories of the files, which also catches the files
+// replaced by a rename or the symlinks swapped by Kubernetes for the mounted
+// secrets.
+func (p *Provider) Start() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	dirs := map[string]struct{}{}
+	for _, file := range p.files() {
+		dir := filepath.Dir(file)
+		if _, ok := dirs[dir]; ok {
+			continue
+		}
+		dirs[dir] = struct{}{}
+		if err = watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return fmt.Errorf("unable to watch %s: %w", dir, err)
+		}
+	}
+	p.watcher = watcher
+	p.wg.Add(1)
+	go p.watch()
+	return nil
+}
+
+// Close stops watching the files. The last loaded certificates are still
+// served.
+func (p *Provider) Close() error {
+	if p.watcher == nil {
+		return nil
+	}
+	close(p.done)
+	err := p.watcher.Close()
+	p.wg.Wait()
+	p.watcher = nil
+	return err
+}
+
+func (p *Provider) watch() {
+	defer p.wg.Done()
+	timer := time.NewTimer(reloadDelay)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case event, ok := <-p.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op.Has(fsnotify.Chmod) && !event.Op.Has(fsnotify.Write) {
+				continue
+			}
+			timer.Reset(reloadDelay)
+		case err, ok := <-p.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("E! TLS certificate watch error: %v", err)
+		case <-timer.C:
+			changed, err := p.reload()
+			if err != nil {
+				// the rotation may not be complete yet, and the previous
+				// certificates are served until the files are valid again
+				log.Printf("E! Unable to reload the TLS certificates, keeping the previous ones: %v", err)
+			} else if changed {
+				log.Printf("I! Reloaded the TLS certificates of %v", p.files())
+			}
+		}
+	}
+}
+
+// reload reads the files, and returns whether their certificates changed.
+func (p *Provider) reload() (bool, error) {
+	var cert *tls.Certificate
+	if p.certFile != "" {
+		c, err := tls.LoadX509KeyPair(p.certFile, p.keyFile)
+		if err != nil {
+			return false, fmt.Errorf("could not load keypair %s:%s: %w", p.certFile, p.keyFile, err)
+		}
+		cert = &c
+	}
+	var pool *x509.CertPool
+	if len(p.caFiles) > 0 {
+		var err error
+		if pool, err = makeCertPool(p.caFiles); err != nil {
+			return false, err
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	changed := !sameCertificate(p.cert, cert) || (pool != nil && !pool.Equal(p.pool))
+	p.cert = cert
+	p.pool = pool
+	return changed, nil
+}
+
+func (p *Provider) files() []string {
+	var files []string
+	if p.certFile != "" {
+		files = append(files, p.certFile, p.keyFile)
+	}
+	return append(files, p.caFiles...)
+}
+
+func (p *Provider) certificate() *tls.Certificate {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.cert
+}
+
+func (p *Provider) certPool() *x509.CertPool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.pool
+}
+
+// ApplyServer returns a copy of the server config presenting the current
+// certificate and verifying the client certificates with the current CAs.
+func (p *Provider) ApplyServer(config *tls.Config) *tls.Config {
+	config = config.Clone()
+	if p.certFile != "" {
+		config.Certificates = nil
+		config.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return p.certificate(), nil
+		}
+	}
+	if len(p.caFiles) > 0 {
+		base := config.Clone()
+		config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			c := base.Clone()
+			c.ClientCAs = p.certPool()
+			return c, nil
+		}
+	}
+	return config
+}
+
+// ApplyClient returns a copy of the client config presenting the current
+// certificate and verifying the server certificate with the current CAs. The
+// ServerName of the config must be set to connect to an IP address.
+func (p *Provider) ApplyClient(config *tls.Config) *tls.Config {
+	config = config.Clone()
+	if p.certFile != "" {
+		config.Certificates = nil
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return p.certificate(), nil
+		}
+	}
+	if len(p.caFiles) > 0 && !config.InsecureSkipVerify {
+		// the RootCAs cannot change once the handshake started, so the
+		// verification is done here with the current CAs instead
+		config.InsecureSkipVerify = true
+		config.RootCAs = nil
+		serverName := config.ServerName
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			return p.verifyServer(state, serverName)
+		}
+	}
+	return config
+}
+
+func (p *Provider) verifyServer(state tls.ConnectionState, serverName string) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("tls: the server did not present a certificate")
+	}
+	if serverName == "" {
+		serverName = state.ServerName
+	}
+	if serverName == "" {
+		// no SNI is sent for an IP address, so it must be set in the config
+		return errors.New("tls: the server name to verify is not set")
+	}
+	opts := x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         p.certPool(),
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range state.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := state.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+func sameCertificate(a, b *tls.Certificate) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return len(a.Certificate) > 0 && len(b.Certificate) > 0 && bytes.Equal(a.Certificate[0], b.Certificate[0])
+}
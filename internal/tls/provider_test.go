@@ -0,0 +1,139 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package tls
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testFiles struct {
+	cert, key, ca string
+}
+
+func newTestFiles(t *testing.T) testFiles {
+	t.Helper()
+	dir := t.TempDir()
+	files := testFiles{
+		cert: filepath.Join(dir, "tls.crt"),
+		key:  filepath.Join(dir, "tls.key"),
+		ca:   filepath.Join(dir, "ca.crt"),
+	}
+	require.NoError(t, writeCerts(files.cert, files.key, files.ca, "127.0.0.1"))
+	return files
+}
+
+// leaf returns the DER of the certificate in the file.
+func leaf(t *testing.T, certFile string) []byte {
+	t.Helper()
+	content, err := os.ReadFile(certFile)
+	require.NoError(t, err)
+	block, _ := pem.Decode(content)
+	require.NotNil(t, block)
+	return block.Bytes
+}
+
+// handshake connects to a listener serving the server config and returns the
+// certificate presented by the server.
+func handshake(t *testing.T, server, client *tls.Config) (*x509.Certificate, error) {
+	t.Helper()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", server)
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		_ = conn.(*tls.Conn).Handshake()
+		_ = conn.Close()
+	}()
+	conn, err := tls.Dial("tcp", listener.Addr().String(), client)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestProviderReload(t *testing.T) {
+	defer func(original time.Duration) {
+		reloadDelay = original
+	}(reloadDelay)
+	reloadDelay = 10 * time.Millisecond
+
+	files := newTestFiles(t)
+	p, err := NewProvider(files.cert, files.key, []string{files.ca})
+	require.NoError(t, err)
+	require.NoError(t, p.Start())
+	defer p.Close()
+	server := p.ApplyServer(&tls.Config{MinVersion: tls.VersionTLS12})
+	client := p.ApplyClient(&tls.Config{ServerName: "127.0.0.1"})
+	assert.Nil(t, server.Certificates)
+	assert.True(t, client.InsecureSkipVerify)
+
+	got, err := handshake(t, server, client)
+	require.NoError(t, err)
+	assert.Equal(t, leaf(t, files.cert), got.Raw)
+
+	// the certificate is rotated with a new CA
+	require.NoError(t, writeCerts(files.cert, files.key, files.ca, "127.0.0.1"))
+	rotated := leaf(t, files.cert)
+	assert.Eventually(t, func() bool {
+		got, err = handshake(t, server, client)
+		return err == nil && bytes.Equal(rotated, got.Raw)
+	}, 5*time.Second, 20*time.Millisecond)
+
+	// an invalid key is not loaded, and the previous certificate is served
+	require.NoError(t, os.WriteFile(files.key, []byte("invalid"), 0600))
+	time.Sleep(10 * reloadDelay)
+	got, err = handshake(t, server, client)
+	require.NoError(t, err)
+	assert.Equal(t, rotated, got.Raw)
+
+	require.NoError(t, p.Close())
+	assert.NoError(t, p.Close())
+}
+
+func TestProviderVerifyServer(t *testing.T) {
+	files := newTestFiles(t)
+	serverProvider, err := NewProvider(files.cert, files.key, nil)
+	require.NoError(t, err)
+	server := serverProvider.ApplyServer(&tls.Config{MinVersion: tls.VersionTLS12})
+
+	untrusted, err := NewProvider("", "", []string{newTestFiles(t).ca})
+	require.NoError(t, err)
+	_, err = handshake(t, server, untrusted.ApplyClient(&tls.Config{ServerName: "127.0.0.1"}))
+	assert.Error(t, err)
+
+	trusted, err := NewProvider("", "", []string{files.ca})
+	require.NoError(t, err)
+	_, err = handshake(t, server, trusted.ApplyClient(&tls.Config{ServerName: "localhost"}))
+	assert.ErrorContains(t, err, "localhost")
+	_, err = handshake(t, server, trusted.ApplyClient(&tls.Config{}))
+	assert.ErrorContains(t, err, "server name")
+
+	// the verification is left to the config if it is skipped
+	insecure := trusted.ApplyClient(&tls.Config{InsecureSkipVerify: true})
+	assert.Nil(t, insecure.VerifyConnection)
+}
+
+func TestNewProviderInvalid(t *testing.T) {
+	files := newTestFiles(t)
+	_, err := NewProvider(files.cert, "", nil)
+	assert.Error(t, err)
+	_, err = NewProvider(files.cert, files.ca, nil)
+	assert.Error(t, err)
+	_, err = NewProvider("", "", []string{filepath.Join(t.TempDir(), "missing.crt")})
+	assert.Error(t, err)
+}
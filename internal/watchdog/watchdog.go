@@ -0,0 +1,137 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package watchdog detects the outputs of the agent that stopped exporting
+// while they still have data to send, e.g. a request hanging on a broken
+// connection, so that the agent can restart instead of staying wedged.
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+const (
+	DefaultCheckInterval = time.Minute
+)
+
+// Config is the watchdog configuration.
+type Config struct {
+	// Timeout is how long an output can go without exporting while it has
+	// data to send before it is considered wedged. Disabled if 0.
+	Timeout time.Duration
+	// CheckInterval is how often the outputs are checked.
+	CheckInterval time.Duration
+}
+
+// Enabled returns true if the timeout is set.
+func (c Config) Enabled() bool {
+	return c.Timeout > 0
+}
+
+// state is what the watchdog last saw of an output.
+type state struct {
+	exported   float64
+	dropped    float64
+	lastExport time.Time
+}
+
+// Watchdog monitors the exported_batches counters of the self telemetry
+// registry. An output is wedged when it exported at least once, has not
+// exported for the timeout, and either its buffer is in use or it dropped
+// events since its last export. The outputs that never exported are not
+// monitored, since they may be misconfigured rather than wedged, and neither
+// are the idle ones.
+type Watchdog struct {
+	cfg      Config
+	registry *selftelemetry.Registry
+	onWedged func(reason string)
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	states map[string]*state
+}
+
+// New returns a watchdog calling onWedged once with the reason when an output
+// is wedged.
+func New(cfg Config, registry *selftelemetry.Registry, onWedged func(reason string)) *Watchdog {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = DefaultCheckInterval
+	}
+	return &Watchdog{
+		cfg:      cfg,
+		registry: registry,
+		onWedged: onWedged,
+		states:   make(map[string]*state),
+	}
+}
+
+// Start monitors the outputs in the background.
+func (w *Watchdog) Start() {
+	log.Printf("I! Restarting the agent if an output does not export for %v while it has data to send", w.cfg.Timeout)
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.cfg.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if reason, wedged := w.check(now); wedged {
+					w.onWedged(reason)
+					return
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops monitoring the outputs.
+func (w *Watchdog) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+}
+
+// check updates the state of the outputs from the registry. Returns the
+// reason and true if an output is wedged.
+func (w *Watchdog) check(now time.Time) (string, bool) {
+	exported := make(map[string]float64)
+	dropped := make(map[string]float64)
+	buffered := make(map[string]bool)
+	for _, sample := range w.registry.Collect() {
+		switch sample.Name {
+		case selftelemetry.MetricExportedBatches:
+			exported[sample.Component] = sample.Value
+		case selftelemetry.MetricDroppedEvents:
+			dropped[sample.Component] = sample.Value
+		case selftelemetry.MetricBufferUtilization:
+			buffered[sample.Component] = sample.Value > 0
+		}
+	}
+	for component, count := range exported {
+		s, ok := w.states[component]
+		if !ok || count != s.exported {
+			w.states[component] = &state{exported: count, dropped: dropped[component], lastExport: now}
+			continue
+		}
+		idle := now.Sub(s.lastExport)
+		if idle < w.cfg.Timeout {
+			continue
+		}
+		if buffered[component] || dropped[component] > s.dropped {
+			return fmt.Sprintf("the %s output has not exported for %v while it has data to send", component, idle.Truncate(time.Second)), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,85 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package watchdog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+func TestCheck(t *testing.T) {
+	registry := selftelemetry.NewRegistry()
+	w := New(Config{Timeout: 10 * time.Minute}, registry, nil)
+	start := time.Now()
+
+	// never exported
+	registry.Add(selftelemetry.MetricDroppedEvents, "cloudwatchlogs", 5)
+	_, wedged := w.check(start.Add(time.Hour))
+	assert.False(t, wedged)
+
+	registry.Add(selftelemetry.MetricExportedBatches, "cloudwatchlogs", 1)
+	registry.Add(selftelemetry.MetricExportedBatches, "cloudwatch", 1)
+	_, wedged = w.check(start)
+	assert.False(t, wedged)
+
+	// idle
+	_, wedged = w.check(start.Add(20 * time.Minute))
+	assert.False(t, wedged)
+
+	// exporting again
+	registry.Add(selftelemetry.MetricExportedBatches, "cloudwatchlogs", 1)
+	_, wedged = w.check(start.Add(25 * time.Minute))
+	assert.False(t, wedged)
+
+	// dropping without exporting, but within the timeout
+	registry.Add(selftelemetry.MetricDroppedEvents, "cloudwatchlogs", 1)
+	_, wedged = w.check(start.Add(30 * time.Minute))
+	assert.False(t, wedged)
+
+	reason, wedged := w.check(start.Add(35 * time.Minute))
+	assert.True(t, wedged)
+	assert.Equal(t, "the cloudwatchlogs output has not exported for 10m0s while it has data to send", reason)
+}
+
+func TestCheckBuffer(t *testing.T) {
+	registry := selftelemetry.NewRegistry()
+	w := New(Config{Timeout: time.Minute}, registry, nil)
+	start := time.Now()
+
+	utilization := 0.0
+	registry.RegisterGauge(selftelemetry.MetricBufferUtilization, "cloudwatch", func() float64 { return utilization })
+	registry.Add(selftelemetry.MetricExportedBatches, "cloudwatch", 1)
+	_, wedged := w.check(start)
+	assert.False(t, wedged)
+	_, wedged = w.check(start.Add(2 * time.Minute))
+	assert.False(t, wedged)
+
+	utilization = 5
+	reason, wedged := w.check(start.Add(3 * time.Minute))
+	assert.True(t, wedged)
+	assert.Contains(t, reason, "cloudwatch output")
+}
+
+func TestStartStop(t *testing.T) {
+	registry := selftelemetry.NewRegistry()
+	registry.Add(selftelemetry.MetricExportedBatches, "cloudwatch", 1)
+	registry.RegisterGauge(selftelemetry.MetricBufferUtilization, "cloudwatch", func() float64 { return 100 })
+	reasons := make(chan string, 1)
+	w := New(Config{Timeout: time.Nanosecond, CheckInterval: 10 * time.Millisecond}, registry, func(reason string) {
+		reasons <- reason
+	})
+	w.Start()
+	defer w.Stop()
+	select {
+	case reason := <-reasons:
+		assert.Contains(t, reason, "cloudwatch output")
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the watchdog did not detect the wedged output")
+	}
+}
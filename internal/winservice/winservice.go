@@ -0,0 +1,25 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package winservice configures the Windows service of the agent. The
+// functions do nothing on the other platforms.
+package winservice
+
+import (
+	"time"
+)
+
+const (
+	// RecoveryResetPeriod is the time without failures after which the
+	// failure count of the service is reset.
+	RecoveryResetPeriod = 24 * time.Hour
+
+	// EventIDWatchdog is the ID of the events logged when the watchdog
+	// restarts the agent.
+	EventIDWatchdog = 1001
+)
+
+// RecoveryDelays are the delays before the service is restarted after its
+// first, second and subsequent failures. The same as the ones set by
+// amazon-cloudwatch-agent-ctl.ps1.
+var RecoveryDelays = []time.Duration{0, 0, 2 * time.Second}
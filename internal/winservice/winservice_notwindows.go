@@ -0,0 +1,22 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+// +build !windows
+
+package winservice
+
+// IsService always returns false outside of Windows.
+func IsService() bool {
+	return false
+}
+
+// ConfigureRecovery does nothing outside of Windows.
+func ConfigureRecovery(string) error {
+	return nil
+}
+
+// ReportWarning does nothing outside of Windows.
+func ReportWarning(string, uint32, string) error {
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build windows
+// +build windows
+
+package winservice
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// IsService returns true if the process runs as a Windows service.
+func IsService() bool {
+	isService, err := svc.IsWindowsService()
+	return err == nil && isService
+}
+
+// ConfigureRecovery makes the service control manager restart the service
+// when it crashes or exits with a non-zero code, e.g. when the watchdog
+// restarts the agent.
+func ConfigureRecovery(name string) error {
+	manager, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("unable to connect to the service control manager: %w", err)
+	}
+	defer manager.Disconnect()
+	service, err := manager.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("unable to open the service %s: %w", name, err)
+	}
+	defer service.Close()
+	actions := make([]mgr.RecoveryAction, 0, len(RecoveryDelays))
+	for _, delay := range RecoveryDelays {
+		actions = append(actions, mgr.RecoveryAction{Type: mgr.ServiceRestart, Delay: delay})
+	}
+	if err = service.SetRecoveryActions(actions, uint32(RecoveryResetPeriod.Seconds())); err != nil {
+		return fmt.Errorf("unable to set the recovery actions of the service %s: %w", name, err)
+	}
+	if err = service.SetRecoveryActionsOnNonCrashFailures(true); err != nil {
+		return fmt.Errorf("unable to set the recovery on non-crash failures of the service %s: %w", name, err)
+	}
+	return nil
+}
+
+// ReportWarning writes the message as a warning to the Application Event Log
+// with the source.
+func ReportWarning(source string, eventID uint32, message string) error {
+	l, err := eventlog.Open(source)
+	if err != nil {
+		return fmt.Errorf("unable to open the event log: %w", err)
+	}
+	defer l.Close()
+	return l.Warning(eventID, message)
+}
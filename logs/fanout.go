@@ -0,0 +1,82 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// DestinationSeparator separates the names of the destinations of a LogSrc publishing to
+// several of them, e.g. "cloudwatchlogs,s3logs".
+const DestinationSeparator = ","
+
+// destinationNames returns the names of the destinations of a LogSrc.
+func destinationNames(destination string) []string {
+	var names []string
+	for _, name := range strings.Split(destination, DestinationSeparator) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// fanoutDest publishes the events to all the destinations of a LogSrc. An event is only done,
+// e.g. its file offset is saved, once all the destinations are done with it.
+type fanoutDest struct {
+	dests []LogDest
+}
+
+var _ LogDest = (*fanoutDest)(nil)
+
+func (f *fanoutDest) Publish(events []LogEvent) error {
+	shared := make([]LogEvent, len(events))
+	for i, e := range events {
+		shared[i] = newFanoutEvent(e, len(f.dests))
+	}
+	var firstErr error
+	for _, dest := range f.dests {
+		if err := dest.Publish(shared); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// fanoutEvent calls Done on the event once it is called by each of the destinations.
+type fanoutEvent struct {
+	LogEvent
+	pending *atomic.Int32
+}
+
+func (e fanoutEvent) Done() {
+	if e.pending.Add(-1) == 0 {
+		e.LogEvent.Done()
+	}
+}
+
+// routedFanoutEvent keeps the log group and stream of a RoutedLogEvent.
+type routedFanoutEvent struct {
+	fanoutEvent
+	routed RoutedLogEvent
+}
+
+func (e routedFanoutEvent) Group() string {
+	return e.routed.Group()
+}
+
+func (e routedFanoutEvent) Stream() string {
+	return e.routed.Stream()
+}
+
+func newFanoutEvent(e LogEvent, count int) LogEvent {
+	pending := new(atomic.Int32)
+	pending.Store(int32(count))
+	fe := fanoutEvent{LogEvent: e, pending: pending}
+	if re, ok := e.(RoutedLogEvent); ok {
+		return routedFanoutEvent{fanoutEvent: fe, routed: re}
+	}
+	return fe
+}
@@ -6,12 +6,15 @@ package logs
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/shutdown"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/tail"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
 )
@@ -30,10 +33,67 @@ type LogEvent interface {
 	Done()
 }
 
+// A RoutedLogEvent is a LogEvent that resolves its own log group and stream, e.g. from fields
+// in the log line. An empty name falls back to the one of the LogSrc.
+type RoutedLogEvent interface {
+	LogEvent
+	Group() string
+	Stream() string
+}
+
 type LogEntityProvider interface {
 	Entity() *cloudwatchlogs.Entity
 }
 
+// A LogCredentialProvider is a LogSrc publishing to a log group in another account or region,
+// e.g. a central security account. Empty values fall back to the ones of the LogBackend.
+type LogCredentialProvider interface {
+	RoleARN() string
+	Region() string
+}
+
+// A LogFieldIndexProvider is a LogSrc whose parsed fields are indexed on its log groups.
+type LogFieldIndexProvider interface {
+	FieldIndexes() []string
+}
+
+// A LogGroupSettingsProvider is a LogSrc with the tags and the KMS key of its log groups.
+type LogGroupSettingsProvider interface {
+	LogGroupSettings() LogGroupSettings
+}
+
+// A LogEventSizeProvider is a LogSrc with its own handling of the log events above the size limit.
+type LogEventSizeProvider interface {
+	EventSizeLimit() EventSizeLimit
+}
+
+const (
+	// OversizeTruncateTail keeps the start of the oversize log events.
+	OversizeTruncateTail = "truncate_tail"
+	// OversizeTruncateHead keeps the end of the oversize log events.
+	OversizeTruncateHead = "truncate_head"
+	// OversizeTruncateMiddle keeps the start and the end of the oversize log events.
+	OversizeTruncateMiddle = "truncate_middle"
+	// OversizeSplit publishes the oversize log events as several log events.
+	OversizeSplit = "split"
+)
+
+// EventSizeLimit is how the log events above MaxSize bytes are published. MaxSize is capped by the size limit
+// of PutLogEvents, which is also the default. The marker replaces the truncated part of the message.
+type EventSizeLimit struct {
+	MaxSize  int
+	Strategy string
+	Marker   string
+}
+
+// LogGroupSettings are applied when the log group is created, and re-applied if they drift on an existing log
+// group. The log groups not managed by the agent, e.g. pre-created ones, are neither created nor updated.
+type LogGroupSettings struct {
+	Tags      map[string]string
+	KMSKeyID  string
+	Unmanaged bool
+}
+
 // A LogSrc is a single source where log events are generated
 // e.g. a single log file
 type LogSrc interface {
@@ -60,6 +120,9 @@ type LogDest interface {
 	Publish(events []LogEvent) error
 }
 
+// rescan requests an immediate scan of the log sources.
+var rescan = make(chan struct{}, 1)
+
 // LogAgent is the agent handles pure log pipelines
 type LogAgent struct {
 	Config                    *config.Config
@@ -67,6 +130,21 @@ type LogAgent struct {
 	destNames                 map[LogDest]string
 	collections               []LogCollection
 	retentionAlreadyAttempted map[string]bool
+
+	mu       sync.Mutex
+	running  map[*runningSrc]struct{}
+	stopping bool
+}
+
+// runningSrc is a LogSrc piped to its LogDest. It is stopped once, either when
+// the src or the dest stops or when the agent shuts down.
+type runningSrc struct {
+	src  LogSrc
+	once sync.Once
+}
+
+func (r *runningSrc) stop() {
+	r.once.Do(r.src.Stop)
 }
 
 func NewLogAgent(c *config.Config) *LogAgent {
@@ -75,12 +153,14 @@ func NewLogAgent(c *config.Config) *LogAgent {
 		backends:                  make(map[string]LogBackend),
 		destNames:                 make(map[LogDest]string),
 		retentionAlreadyAttempted: make(map[string]bool),
+		running:                   make(map[*runningSrc]struct{}),
 	}
 }
 
 // Run LogAgent will scan all input and output plugins for LogCollection and LogBackend.
 // And connect all the LogSrc from the LogCollection found to the respective LogDest
-// based on the configured "destination", and "name"
+// based on the configured "destination", and "name". A LogSrc with several destinations
+// separated by DestinationSeparator publishes to all of them.
 func (l *LogAgent) Run(ctx context.Context) {
 	log.Printf("I! [logagent] starting")
 	for _, output := range l.Config.Outputs {
@@ -112,37 +192,103 @@ func (l *LogAgent) Run(ctx context.Context) {
 	for {
 		select {
 		case <-t.C:
-			log.Printf("D! [logagent] open file count, %v", tail.OpenFileCount.Load())
-			for _, c := range l.collections {
-				srcs := c.FindLogSrc()
-				for _, src := range srcs {
-					dname := src.Destination()
-					logGroup := src.Group()
-					logStream := src.Stream()
-					description := src.Description()
-					retention := src.Retention()
-					logGroupClass := src.Class()
-					backend, ok := l.backends[dname]
-					if !ok {
-						log.Printf("E! [logagent] Failed to find destination %s for log source %s/%s(%s) ", dname, logGroup, logStream, description)
-						continue
-					}
-					retention = l.checkRetentionAlreadyAttempted(retention, logGroup)
-					dest := backend.CreateDest(logGroup, logStream, retention, logGroupClass, src)
-					l.destNames[dest] = dname
-					log.Printf("I! [logagent] piping log from %s/%s(%s) to %s with retention %d", logGroup, logStream, description, dname, retention)
-					go l.runSrcToDest(src, dest)
-				}
-			}
+			l.findLogSrcs()
+		case <-rescan:
+			log.Printf("I! [logagent] scanning the log sources on request")
+			l.findLogSrcs()
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// findLogSrcs connects the new LogSrc of the collections to their LogDest.
+func (l *LogAgent) findLogSrcs() {
+	log.Printf("D! [logagent] open file count, %v", tail.OpenFileCount.Load())
+	for _, c := range l.collections {
+		srcs := c.FindLogSrc()
+		for _, src := range srcs {
+			dname := src.Destination()
+			logGroup := src.Group()
+			logStream := src.Stream()
+			description := src.Description()
+			retention := src.Retention()
+			logGroupClass := src.Class()
+			retention = l.checkRetentionAlreadyAttempted(retention, logGroup)
+			var dests []LogDest
+			for _, name := range destinationNames(dname) {
+				backend, ok := l.backends[name]
+				if !ok {
+					log.Printf("E! [logagent] Failed to find destination %s for log source %s/%s(%s) ", name, logGroup, logStream, description)
+					continue
+				}
+				dests = append(dests, backend.CreateDest(logGroup, logStream, retention, logGroupClass, src))
+			}
+			if len(dests) == 0 {
+				continue
+			}
+			dest := dests[0]
+			if len(dests) > 1 {
+				dest = &fanoutDest{dests: dests}
+			}
+			l.destNames[dest] = dname
+			log.Printf("I! [logagent] piping log from %s/%s(%s) to %s with retention %d", logGroup, logStream, description, dname, retention)
+			go l.runSrcToDest(src, dest)
+		}
+	}
+}
+
+// RequestRescan makes the running LogAgent look for new log sources without
+// waiting for the next scan.
+func RequestRescan() {
+	select {
+	case rescan <- struct{}{}:
+	default:
+		// a scan is already pending
+	}
+}
+
+// Shutdown stops the log sources which are still running, so that the tailers
+// save their last offsets. The sources blocking the shutdown are reported until
+// the context is done.
+func (l *LogAgent) Shutdown(ctx context.Context) error {
+	l.mu.Lock()
+	l.stopping = true
+	running := make([]*runningSrc, 0, len(l.running))
+	for r := range l.running {
+		running = append(running, r)
+	}
+	l.mu.Unlock()
+
+	watchdog := shutdown.NewWatchdog("logagent")
+	var err error
+	for _, r := range running {
+		part := fmt.Sprintf("%v/%v(%v)", r.src.Group(), r.src.Stream(), r.src.Description())
+		// the remaining sources are still stopped after a blocked one
+		if stopErr := watchdog.Stop(ctx, part, r.stop); stopErr != nil && err == nil {
+			err = stopErr
+		}
+	}
+	return err
+}
+
 func (l *LogAgent) runSrcToDest(src LogSrc, dest LogDest) {
 	eventsCh := make(chan LogEvent)
-	defer src.Stop()
+	r := &runningSrc{src: src}
+	l.mu.Lock()
+	if l.stopping {
+		l.mu.Unlock()
+		src.Stop()
+		return
+	}
+	l.running[r] = struct{}{}
+	l.mu.Unlock()
+	defer func() {
+		r.stop()
+		l.mu.Lock()
+		delete(l.running, r)
+		l.mu.Unlock()
+	}()
 
 	src.SetOutput(func(e LogEvent) {
 		if e == nil {
@@ -4,10 +4,14 @@
 package logs
 
 import (
+	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/influxdata/telegraf/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRetentionAlreadySet(t *testing.T) {
@@ -20,3 +24,135 @@ func TestRetentionAlreadySet(t *testing.T) {
 	assert.Equal(t, -1, secondAttempt)
 	assert.True(t, l.retentionAlreadyAttempted["logGroup1"])
 }
+
+func TestRequestRescan(t *testing.T) {
+	RequestRescan()
+	// the pending scan is not requested twice
+	RequestRescan()
+	assert.Len(t, rescan, 1)
+	<-rescan
+}
+
+type testEvent struct {
+	done *int
+}
+
+func (e testEvent) Message() string { return "message" }
+func (e testEvent) Time() time.Time { return time.Time{} }
+func (e testEvent) Done()           { *e.done++ }
+
+type testRoutedEvent struct {
+	testEvent
+}
+
+func (e testRoutedEvent) Group() string  { return "group" }
+func (e testRoutedEvent) Stream() string { return "stream" }
+
+type testDest struct {
+	events []LogEvent
+	err    error
+}
+
+func (d *testDest) Publish(events []LogEvent) error {
+	d.events = append(d.events, events...)
+	return d.err
+}
+
+func TestDestinationNames(t *testing.T) {
+	assert.Equal(t, []string{"cloudwatchlogs"}, destinationNames("cloudwatchlogs"))
+	assert.Equal(t, []string{"cloudwatchlogs", "s3logs"}, destinationNames("cloudwatchlogs, s3logs,"))
+	assert.Empty(t, destinationNames(""))
+}
+
+func TestFanoutDest(t *testing.T) {
+	var done int
+	cwl, s3 := &testDest{}, &testDest{err: ErrOutputStopped}
+	f := &fanoutDest{dests: []LogDest{cwl, s3}}
+	assert.ErrorIs(t, f.Publish([]LogEvent{testEvent{done: &done}, testRoutedEvent{testEvent{done: &done}}}), ErrOutputStopped)
+	require.Len(t, cwl.events, 2)
+	require.Len(t, s3.events, 2)
+
+	// the routed events keep their log group and stream
+	re, ok := s3.events[1].(RoutedLogEvent)
+	require.True(t, ok)
+	assert.Equal(t, "group", re.Group())
+
+	// the events are only done once both destinations are done with them
+	cwl.events[0].Done()
+	cwl.events[1].Done()
+	assert.Equal(t, 0, done)
+	s3.events[1].Done()
+	assert.Equal(t, 1, done)
+	s3.events[0].Done()
+	assert.Equal(t, 2, done)
+}
+
+type testSrc struct {
+	LogSrc
+	name    string
+	release chan struct{}
+
+	mu      sync.Mutex
+	output  func(LogEvent)
+	stopped int
+}
+
+func (s *testSrc) SetOutput(fn func(LogEvent)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.output = fn
+}
+
+func (s *testSrc) Group() string       { return "group" }
+func (s *testSrc) Stream() string      { return s.name }
+func (s *testSrc) Description() string { return s.name }
+
+func (s *testSrc) Stop() {
+	if s.release != nil {
+		<-s.release
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped++
+	if s.output != nil {
+		s.output(nil)
+	}
+}
+
+func (s *testSrc) stopCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stopped
+}
+
+func (l *LogAgent) runningCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.running)
+}
+
+func TestShutdown(t *testing.T) {
+	l := NewLogAgent(config.NewConfig())
+	idle := &testSrc{name: "idle"}
+	blocked := &testSrc{name: "blocked", release: make(chan struct{})}
+	go l.runSrcToDest(blocked, &testDest{})
+	go l.runSrcToDest(idle, &testDest{})
+	require.Eventually(t, func() bool { return l.runningCount() == 2 }, time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.Shutdown(ctx), context.DeadlineExceeded)
+	// the source after the blocked one is still stopped
+	assert.Eventually(t, func() bool { return idle.stopCount() == 1 }, time.Second, 10*time.Millisecond)
+
+	close(blocked.release)
+	assert.Eventually(t, func() bool { return l.runningCount() == 0 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, blocked.stopCount())
+	assert.Equal(t, 1, idle.stopCount())
+
+	// the sources found while shutting down are stopped right away
+	late := &testSrc{name: "late"}
+	l.runSrcToDest(late, &testDest{})
+	assert.Equal(t, 1, late.stopCount())
+	assert.Equal(t, 0, l.runningCount())
+}
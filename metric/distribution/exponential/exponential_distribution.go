@@ -0,0 +1,314 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package exponential
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+)
+
+const (
+	// MaxScale and MinScale are the range of the scales of the OTel exponential
+	// histograms. The base of the buckets is 2^(2^-scale).
+	MaxScale int32 = 20
+	MinScale int32 = -10
+)
+
+// ExponentialDistribution keeps the buckets of the OTel exponential histograms,
+// so the values sent to CloudWatch are as precise as the histograms. When there
+// are more buckets than the limit, the buckets are merged by reducing the scale
+// like the OTel SDKs do.
+type ExponentialDistribution struct {
+	maximum     float64
+	minimum     float64
+	sampleCount float64
+	sum         float64
+	unit        string
+	// multiplier converts the bucket boundaries to the unit of the distribution.
+	multiplier float64
+	maxBuckets int
+	scale      int32
+	zeroCount  float64
+	positive   map[int32]float64 // from bucket index to the counter (i.e. weight)
+	negative   map[int32]float64 // from bucket index of the absolute value to the counter
+}
+
+var _ distribution.Distribution = (*ExponentialDistribution)(nil)
+
+// NewExponentialDistribution creates a distribution with at most maxBuckets
+// values, or without limit if maxBuckets is 0.
+func NewExponentialDistribution(maxBuckets int) *ExponentialDistribution {
+	return &ExponentialDistribution{
+		maximum:    -math.MaxFloat64,
+		minimum:    math.MaxFloat64,
+		multiplier: 1,
+		maxBuckets: maxBuckets,
+		scale:      MaxScale,
+		positive:   map[int32]float64{},
+		negative:   map[int32]float64{},
+	}
+}
+
+func (ed *ExponentialDistribution) Maximum() float64 {
+	return ed.maximum
+}
+
+func (ed *ExponentialDistribution) Minimum() float64 {
+	return ed.minimum
+}
+
+func (ed *ExponentialDistribution) SampleCount() float64 {
+	return ed.sampleCount
+}
+
+func (ed *ExponentialDistribution) Sum() float64 {
+	return ed.sum
+}
+
+func (ed *ExponentialDistribution) Scale() int32 {
+	return ed.scale
+}
+
+// ValuesAndCounts returns the middle of each bucket, in log scale like SEH1,
+// bounded by the minimum and the maximum, in ascending order.
+func (ed *ExponentialDistribution) ValuesAndCounts() (values []float64, counts []float64) {
+	values = make([]float64, 0, ed.Size())
+	counts = make([]float64, 0, ed.Size())
+	for _, index := range sortedIndexes(ed.negative, true) {
+		values = append(values, ed.clamp(-ed.bucketValue(index)))
+		counts = append(counts, ed.negative[index])
+	}
+	if ed.zeroCount > 0 {
+		values = append(values, ed.clamp(0))
+		counts = append(counts, ed.zeroCount)
+	}
+	for _, index := range sortedIndexes(ed.positive, false) {
+		values = append(values, ed.clamp(ed.bucketValue(index)))
+		counts = append(counts, ed.positive[index])
+	}
+	return
+}
+
+func (ed *ExponentialDistribution) Unit() string {
+	return ed.unit
+}
+
+func (ed *ExponentialDistribution) Size() int {
+	size := len(ed.positive) + len(ed.negative)
+	if ed.zeroCount > 0 {
+		size++
+	}
+	return size
+}
+
+// weight is 1/samplingRate
+func (ed *ExponentialDistribution) AddEntryWithUnit(value float64, weight float64, unit string) error {
+	if weight <= 0 {
+		return fmt.Errorf("unsupported weight %v: %w", weight, distribution.ErrUnsupportedWeight)
+	}
+	if !distribution.IsSupportedValue(value, distribution.MinValue, distribution.MaxValue) {
+		return fmt.Errorf("unsupported value %v: %w", value, distribution.ErrUnsupportedValue)
+	}
+	ed.sampleCount += weight
+	ed.sum += value * weight
+	ed.minimum = math.Min(ed.minimum, value)
+	ed.maximum = math.Max(ed.maximum, value)
+	ed.addValue(value, weight)
+	ed.fit()
+	ed.setUnit(unit)
+	return nil
+}
+
+// weight is 1/samplingRate
+func (ed *ExponentialDistribution) AddEntry(value float64, weight float64) error {
+	return ed.AddEntryWithUnit(value, weight, "")
+}
+
+func (ed *ExponentialDistribution) AddDistribution(distribution distribution.Distribution) {
+	ed.AddDistributionWithWeight(distribution, 1)
+}
+
+func (ed *ExponentialDistribution) AddDistributionWithWeight(distribution distribution.Distribution, weight float64) {
+	if distribution.SampleCount()*weight <= 0 {
+		log.Printf("D! SampleCount * Weight should be larger than 0: %v, %v", distribution.SampleCount(), weight)
+		return
+	}
+	from, ok := distribution.(*ExponentialDistribution)
+	if !ok {
+		log.Printf("E! The from distribution type is not compatible with the to distribution type: from distribution type %T, to distribution type %T", distribution, ed)
+		return
+	}
+	// the buckets are merged at the lowest scale of both distributions
+	ed.downscale(ed.scale - from.scale)
+	for index, count := range from.positive {
+		ed.positive[index>>(from.scale-ed.scale)] += count * weight
+	}
+	for index, count := range from.negative {
+		ed.negative[index>>(from.scale-ed.scale)] += count * weight
+	}
+	ed.zeroCount += from.zeroCount * weight
+	ed.sampleCount += from.sampleCount * weight
+	ed.sum += from.sum * weight
+	ed.minimum = math.Min(ed.minimum, from.minimum)
+	ed.maximum = math.Max(ed.maximum, from.maximum)
+	ed.fit()
+	ed.setUnit(from.unit)
+}
+
+// ConvertToOtel sets the values of the distribution as the explicit bounds of
+// the histogram, like the regular distribution.
+func (ed *ExponentialDistribution) ConvertToOtel(dp pmetric.HistogramDataPoint) {
+	dp.SetMax(ed.maximum)
+	dp.SetMin(ed.minimum)
+	dp.SetCount(uint64(ed.sampleCount))
+	dp.SetSum(ed.sum)
+	values, counts := ed.ValuesAndCounts()
+	dp.ExplicitBounds().FromRaw(values)
+	dp.BucketCounts().EnsureCapacity(len(counts))
+	for _, count := range counts {
+		// Beware of potential loss of precision due to type conversion.
+		dp.BucketCounts().Append(uint64(count))
+	}
+}
+
+func (ed *ExponentialDistribution) ConvertFromOtel(dp pmetric.HistogramDataPoint, unit string) {
+	for i := 0; i < dp.ExplicitBounds().Len() && i < dp.BucketCounts().Len(); i++ {
+		ed.addValue(dp.ExplicitBounds().At(i), float64(dp.BucketCounts().At(i)))
+	}
+	ed.maximum = dp.Max()
+	ed.minimum = dp.Min()
+	ed.sampleCount = float64(dp.Count())
+	ed.sum = dp.Sum()
+	ed.unit = unit
+	ed.fit()
+}
+
+// ConvertFromOtelExponential copies the buckets of the exponential histogram.
+// The multiplier converts the values to the unit.
+func (ed *ExponentialDistribution) ConvertFromOtelExponential(dp pmetric.ExponentialHistogramDataPoint, unit string, multiplier float64) {
+	ed.unit = unit
+	ed.multiplier = multiplier
+	ed.scale = max(min(dp.Scale(), MaxScale), MinScale)
+	// histograms with a higher resolution than supported are downscaled
+	shift := max(dp.Scale()-MaxScale, 0)
+	copyBuckets(ed.positive, dp.Positive(), shift)
+	copyBuckets(ed.negative, dp.Negative(), shift)
+	ed.zeroCount = float64(dp.ZeroCount())
+	ed.sampleCount = float64(dp.Count())
+	ed.fit()
+
+	values, _ := ed.ValuesAndCounts()
+	if dp.HasMin() {
+		ed.minimum = dp.Min() * multiplier
+	} else if len(values) > 0 {
+		ed.minimum = values[0]
+	}
+	if dp.HasMax() {
+		ed.maximum = dp.Max() * multiplier
+	} else if len(values) > 0 {
+		ed.maximum = values[len(values)-1]
+	}
+	if dp.HasSum() {
+		ed.sum = dp.Sum() * multiplier
+	} else {
+		// estimate the sum from the buckets
+		values, counts := ed.ValuesAndCounts()
+		for i, value := range values {
+			ed.sum += value * counts[i]
+		}
+	}
+}
+
+func (ed *ExponentialDistribution) setUnit(unit string) {
+	if ed.unit == "" {
+		ed.unit = unit
+	} else if ed.unit != unit && unit != "" {
+		log.Printf("D! Multiple units are detected: %s, %s", ed.unit, unit)
+	}
+}
+
+// addValue adds the value to its bucket without updating the statistics.
+func (ed *ExponentialDistribution) addValue(value float64, weight float64) {
+	switch {
+	case value > 0:
+		ed.positive[ed.bucketIndex(value/ed.multiplier)] += weight
+	case value < 0:
+		ed.negative[ed.bucketIndex(-value/ed.multiplier)] += weight
+	default:
+		ed.zeroCount += weight
+	}
+}
+
+// bucketIndex returns the index of the bucket (base^index, base^(index+1)]
+// of the positive value.
+func (ed *ExponentialDistribution) bucketIndex(value float64) int32 {
+	return int32(math.Ceil(math.Log2(value)*math.Ldexp(1, int(ed.scale)))) - 1
+}
+
+// bucketValue returns the middle of the bucket in log scale.
+func (ed *ExponentialDistribution) bucketValue(index int32) float64 {
+	return math.Exp2((float64(index)+0.5)*math.Ldexp(1, -int(ed.scale))) * ed.multiplier
+}
+
+func (ed *ExponentialDistribution) clamp(value float64) float64 {
+	// the minimum and the maximum are not known yet
+	if ed.minimum > ed.maximum {
+		return value
+	}
+	return math.Max(ed.minimum, math.Min(ed.maximum, value))
+}
+
+// fit merges the buckets until they are within the limit.
+func (ed *ExponentialDistribution) fit() {
+	for ed.maxBuckets > 0 && ed.Size() > ed.maxBuckets && ed.scale > MinScale {
+		ed.downscale(1)
+	}
+}
+
+// downscale reduces the scale, each bucket is merged with its 2^by - 1
+// neighbours.
+func (ed *ExponentialDistribution) downscale(by int32) {
+	if by <= 0 {
+		return
+	}
+	ed.positive = downscaleBuckets(ed.positive, by)
+	ed.negative = downscaleBuckets(ed.negative, by)
+	ed.scale -= by
+}
+
+func downscaleBuckets(buckets map[int32]float64, by int32) map[int32]float64 {
+	merged := make(map[int32]float64, len(buckets))
+	for index, count := range buckets {
+		merged[index>>by] += count
+	}
+	return merged
+}
+
+func copyBuckets(to map[int32]float64, from pmetric.ExponentialHistogramDataPointBuckets, shift int32) {
+	for i := 0; i < from.BucketCounts().Len(); i++ {
+		if count := from.BucketCounts().At(i); count > 0 {
+			to[(from.Offset()+int32(i))>>shift] += float64(count)
+		}
+	}
+}
+
+func sortedIndexes(buckets map[int32]float64, descending bool) []int32 {
+	indexes := make([]int32, 0, len(buckets))
+	for index := range buckets {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		if descending {
+			return indexes[i] > indexes[j]
+		}
+		return indexes[i] < indexes[j]
+	})
+	return indexes
+}
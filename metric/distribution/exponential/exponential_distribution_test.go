@@ -0,0 +1,148 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package exponential
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+)
+
+func newDataPoint(scale int32, offset int32, counts []uint64) pmetric.ExponentialHistogramDataPoint {
+	dp := pmetric.NewExponentialHistogramDataPoint()
+	dp.SetScale(scale)
+	dp.Positive().SetOffset(offset)
+	dp.Positive().BucketCounts().FromRaw(counts)
+	var count uint64
+	for _, c := range counts {
+		count += c
+	}
+	dp.SetCount(count)
+	return dp
+}
+
+func TestConvertFromOtelExponential(t *testing.T) {
+	// buckets (1, 2], (2, 4] and (4, 8]
+	dp := newDataPoint(0, 0, []uint64{1, 2, 3})
+	dp.SetZeroCount(1)
+	dp.SetCount(7)
+	dp.SetSum(30)
+	dp.SetMin(0)
+	dp.SetMax(7)
+
+	dist := NewExponentialDistribution(150)
+	dist.ConvertFromOtelExponential(dp, "Milliseconds", 1)
+	assert.Equal(t, 4, dist.Size())
+	assert.Equal(t, 7.0, dist.SampleCount())
+	assert.Equal(t, 30.0, dist.Sum())
+	assert.Equal(t, 0.0, dist.Minimum())
+	assert.Equal(t, 7.0, dist.Maximum())
+	assert.Equal(t, "Milliseconds", dist.Unit())
+	values, counts := dist.ValuesAndCounts()
+	assert.InDeltaSlice(t, []float64{0, math.Sqrt2, 2 * math.Sqrt2, 4 * math.Sqrt2}, values, 1e-9)
+	assert.Equal(t, []float64{1, 1, 2, 3}, counts)
+}
+
+func TestConvertFromOtelExponentialWithoutStatistics(t *testing.T) {
+	dp := newDataPoint(0, 0, []uint64{1, 1})
+	dp.Negative().SetOffset(1)
+	dp.Negative().BucketCounts().FromRaw([]uint64{2})
+	dp.SetCount(4)
+
+	// the values are converted from nanoseconds to microseconds
+	dist := NewExponentialDistribution(0)
+	dist.ConvertFromOtelExponential(dp, "Microseconds", 0.001)
+	values, counts := dist.ValuesAndCounts()
+	assert.InDeltaSlice(t, []float64{-0.001 * 2 * math.Sqrt2, 0.001 * math.Sqrt2, 0.001 * 2 * math.Sqrt2}, values, 1e-12)
+	assert.Equal(t, []float64{2, 1, 1}, counts)
+	assert.InDelta(t, values[0], dist.Minimum(), 1e-12)
+	assert.InDelta(t, values[2], dist.Maximum(), 1e-12)
+	assert.InDelta(t, 2*values[0]+values[1]+values[2], dist.Sum(), 1e-12)
+}
+
+func TestConvertFromOtelExponentialDownscale(t *testing.T) {
+	testCases := map[string]struct {
+		dp         pmetric.ExponentialHistogramDataPoint
+		maxBuckets int
+		wantScale  int32
+		wantCounts []float64
+	}{
+		"WithinLimit": {
+			dp:         newDataPoint(3, -2, []uint64{1, 0, 2, 3}),
+			maxBuckets: 3,
+			wantScale:  3,
+			wantCounts: []float64{1, 2, 3},
+		},
+		// indexes -2, 0 and 1 are merged into -1 and 0
+		"OverLimit": {
+			dp:         newDataPoint(3, -2, []uint64{1, 0, 2, 3}),
+			maxBuckets: 2,
+			wantScale:  2,
+			wantCounts: []float64{1, 5},
+		},
+		"OverMaxScale": {
+			dp:         newDataPoint(22, 4, []uint64{1, 1, 1, 1, 1}),
+			maxBuckets: 150,
+			wantScale:  MaxScale,
+			wantCounts: []float64{4, 1},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dist := NewExponentialDistribution(testCase.maxBuckets)
+			dist.ConvertFromOtelExponential(testCase.dp, "", 1)
+			assert.Equal(t, testCase.wantScale, dist.Scale())
+			_, counts := dist.ValuesAndCounts()
+			assert.Equal(t, testCase.wantCounts, counts)
+		})
+	}
+}
+
+func TestAddDistribution(t *testing.T) {
+	dist := NewExponentialDistribution(150)
+	dist.ConvertFromOtelExponential(newDataPoint(1, 0, []uint64{1, 1}), "Seconds", 1)
+	another := NewExponentialDistribution(150)
+	another.ConvertFromOtelExponential(newDataPoint(0, 0, []uint64{2}), "Seconds", 1)
+
+	// merged at the lowest scale
+	dist.AddDistribution(another)
+	assert.Equal(t, int32(0), dist.Scale())
+	assert.Equal(t, 4.0, dist.SampleCount())
+	_, counts := dist.ValuesAndCounts()
+	assert.Equal(t, []float64{4}, counts)
+
+	assert.NoError(t, dist.AddEntry(1.5, 2))
+	assert.Equal(t, 6.0, dist.SampleCount())
+	_, counts = dist.ValuesAndCounts()
+	assert.Equal(t, []float64{6}, counts)
+	assert.NoError(t, dist.AddEntry(3, 1))
+	assert.Equal(t, 2, dist.Size())
+	assert.ErrorIs(t, dist.AddEntry(math.NaN(), 1), distribution.ErrUnsupportedValue)
+	assert.ErrorIs(t, dist.AddEntry(1, 0), distribution.ErrUnsupportedWeight)
+}
+
+// The relative error of the percentiles is bounded by the width of the buckets.
+func TestPercentileFidelity(t *testing.T) {
+	dist := NewExponentialDistribution(150)
+	for i := 1; i <= 100000; i++ {
+		assert.NoError(t, dist.AddEntry(float64(i), 1))
+	}
+	assert.LessOrEqual(t, dist.Size(), 150)
+	values, counts := dist.ValuesAndCounts()
+	var seen float64
+	var p99 float64
+	for i, count := range counts {
+		seen += count
+		if seen >= 0.99*dist.SampleCount() {
+			p99 = values[i]
+			break
+		}
+	}
+	base := math.Exp2(math.Ldexp(1, -int(dist.Scale())))
+	assert.InEpsilon(t, 99000, p99, base-1)
+}
@@ -53,9 +53,11 @@ ln -f -s /opt/aws/amazon-cloudwatch-agent/var ${RPM_BUILD_ROOT}/var/run/amazon/a
 /opt/aws/amazon-cloudwatch-agent/bin/CWAGENT_VERSION
 /opt/aws/amazon-cloudwatch-agent/bin/config-translator
 /opt/aws/amazon-cloudwatch-agent/bin/config-downloader
+/opt/aws/amazon-cloudwatch-agent/bin/cloudwatch-agent-supervisor
 /opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent-config-wizard
 /opt/aws/amazon-cloudwatch-agent/bin/start-amazon-cloudwatch-agent
 /opt/aws/amazon-cloudwatch-agent/bin/opentelemetry-jmx-metrics.jar
+/opt/aws/amazon-cloudwatch-agent/bin/network_connections.bpf.o
 /opt/aws/amazon-cloudwatch-agent/doc/amazon-cloudwatch-agent-schema.json
 %config(noreplace) /opt/aws/amazon-cloudwatch-agent/etc/common-config.toml
 /opt/aws/amazon-cloudwatch-agent/LICENSE
@@ -76,7 +78,10 @@ ln -f -s /opt/aws/amazon-cloudwatch-agent/var ${RPM_BUILD_ROOT}/var/run/amazon/a
 if [ $1 -ge 2 ]; then
     if [ -x /opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent-ctl ]; then
         /opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent-ctl -a prep-restart
-        /opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent-ctl -a stop
+        # a running agent that supports handover is upgraded in place after the install
+        if [ ! -S /opt/aws/amazon-cloudwatch-agent/var/handover.sock ]; then
+            /opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent-ctl -a stop
+        fi
     fi
 fi
 
@@ -14,6 +14,8 @@ Param (
     [string]$Mode = 'ec2',
     [Parameter(Mandatory = $false)]
     [string]$LogLevel = '',
+    [Parameter(Mandatory = $false)]
+    [switch]$Upgrade = $false,
     [parameter(ValueFromRemainingArguments=$true)]
     $unsupportedVars
 )
@@ -25,10 +27,12 @@ $UsageString = @"
 
 
         usage:  amazon-cloudwatch-agent-ctl.ps1 -a
-                stop|start|status|fetch-config|append-config|remove-config|set-log-level
+                stop|start|status|fetch-config|append-config|remove-config|set-log-level|
+                dump-config|list-pipelines|list-feature-gates|rescan-logs|rotate-credentials
                 [-m ec2|onPremise|onPrem|auto]
                 [-c default|all|ssm:<parameter-store-name>|file:<file-path>]
                 [-s]
+                [-u]
                 [-l INFO|DEBUG|WARN|ERROR|OFF]
 
         e.g.
@@ -47,6 +51,11 @@ $UsageString = @"
             append-config:                          append json config with the existing json configs if any, followed by -c. Target config can be based on the location (ssm parameter store name, file name), or 'default'.
             remove-config:                          remove config for agent, followed by -c. Target config can be based on the location (ssm parameter store name, file name), or 'all'.
             set-log-level:                          sets the log level, followed by -l to provide the level in all caps.
+            dump-config:                            print the effective OTel configuration of the running agent. Requires the admin API.
+            list-pipelines:                         list the pipelines of the running agent and the health of their components. Requires the admin API.
+            list-feature-gates:                     list the feature gates of the running agent and whether they are enabled. Requires the admin API.
+            rescan-logs:                            look for new log files to collect without waiting for the next scan. Requires the admin API.
+            rotate-credentials:                     expire the cached AWS credentials of the running agent. Requires the admin API.
 
         -m: mode
             ec2:                                    indicate this is on ec2 host.
@@ -62,6 +71,9 @@ $UsageString = @"
         -s: optionally restart after configuring the agent configuration
             this parameter is used for 'fetch-config', 'append-config', 'remove-config' action only.
 
+        -u: optionally rewrite the deprecated keys of the json configs to the current schema version before applying them
+            this parameter is used for 'fetch-config', 'append-config' action only.
+
         -l: log level to set the agent to INFO, DEBUG, WARN, ERROR, or OFF
             this parameter is used for 'set-log-level' only.
 
@@ -93,6 +105,7 @@ $JSON="${CWAProgramData}\amazon-cloudwatch-agent.json"
 $JSON_DIR = "${CWAProgramData}\Configs"
 $COMMON_CONIG="${CWAProgramData}\common-config.toml"
 $ENV_CONFIG="${CWAProgramData}\env-config.json"
+$POLICY="${CWAProgramData}\policy.json"
 
 $EC2 = $false
 # WMI is unavailable on Nano, CIM is unavailable on 2003
@@ -312,8 +325,13 @@ Function CWAConfig() {
         Remove-Item "${TOML}" -Force -ErrorAction SilentlyContinue
         Remove-Item "${OTEL_YAML}" -Force -ErrorAction SilentlyContinue
     } else {
+        if ($Upgrade -And $multi_config -ne 'remove') {
+            Write-Output "Start configuration migration..."
+            & cmd /c "`"$CWAProgramFiles\config-translator.exe`" --input ${JSON} --input-dir ${JSON_DIR} --migrate 2>&1"
+            CheckCMDResult
+        }
         Write-Output "Start configuration validation..."
-        & cmd /c "`"$CWAProgramFiles\config-translator.exe`" --input ${JSON} --input-dir ${JSON_DIR} --output ${TOML} --mode ${param_mode} --config ${COMMON_CONIG} --multi-config ${multi_config} 2>&1"
+        & cmd /c "`"$CWAProgramFiles\config-translator.exe`" --input ${JSON} --input-dir ${JSON_DIR} --output ${TOML} --mode ${param_mode} --config ${COMMON_CONIG} --multi-config ${multi_config} --policy ${POLICY} 2>&1"
         CheckCMDResult
         # Let command pass so we can check return code and give user-friendly error-message
         $ErrorActionPreference = "Continue"
@@ -404,6 +422,22 @@ Function SetLogLevelAll() {
 
     & cmd /c "`"${CWAProgramFiles}\amazon-cloudwatch-agent.exe`" --setenv CWAGENT_LOG_LEVEL=${LogLevel} --envconfig ${ENV_CONFIG} 2>&1"
     CheckCMDResult "" "Set CWAGENT_LOG_LEVEL to ${LogLevel}"
+
+    # apply the log level right away if the admin API of the running agent is enabled
+    $svc = Get-Service -Name "${CWAServiceName}" -ErrorAction SilentlyContinue
+    if ($svc -and $svc.Status -eq 'Running') {
+        & cmd /c "`"${CWAProgramFiles}\amazon-cloudwatch-agent.exe`" --admin set-log-level ${LogLevel} 2>nul"
+    }
+}
+
+Function AdminAll() {
+    $svc = Get-Service -Name "${CWAServiceName}" -ErrorAction SilentlyContinue
+    if (!$svc -or $svc.Status -ne 'Running') {
+        Write-Output "${CWAServiceName} is not running"
+        Exit 1
+    }
+    & cmd /c "`"${CWAProgramFiles}\amazon-cloudwatch-agent.exe`" --admin ${Action} 2>&1"
+    CheckCMDResult "" ""
 }
 
 Function main() {
@@ -442,6 +476,11 @@ Function main() {
         cond-restart { CondRestartAll }
         preun { PreunAll }
         set-log-level { SetLogLevelAll }
+        dump-config { AdminAll }
+        list-pipelines { AdminAll }
+        list-feature-gates { AdminAll }
+        rescan-logs { AdminAll }
+        rotate-credentials { AdminAll }
         default {
            Write-Output "Invalid action: ${Action}`n${UsageString}"
            Exit 1
@@ -30,6 +30,7 @@ New-Item -ItemType Directory -Force -Path "${CWAProgramData}\Configs" | Out-Null
 "start-amazon-cloudwatch-agent.exe",
 "amazon-cloudwatch-agent-ctl.ps1",
 "config-downloader.exe",
+"cloudwatch-agent-supervisor.exe",
 "config-translator.exe",
 "amazon-cloudwatch-agent-config-wizard.exe",
 "amazon-cloudwatch-agent-schema.json"
@@ -0,0 +1,234 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectd
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"collectd.org/api"
+	"collectd.org/network"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/influxdata/telegraf/plugins/inputs/socket_listener"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+const (
+	defaultServiceAddress = "udp://127.0.0.1:25826"
+	defaultAuthFile       = "/etc/collectd/auth_file"
+	defaultReloadInterval = time.Minute
+
+	// measurementUnknownTypes counts the value lists dropped because their
+	// type is missing from the types.db files.
+	measurementUnknownTypes = "unknown_types"
+	fieldDropped            = "dropped"
+	tagType                 = "type"
+)
+
+// CollectD receives the metrics of the collectd network plugin. The
+// types.db files are reloaded periodically, so that the types of new custom
+// plugins are picked up without restarting the agent.
+type CollectD struct {
+	ServiceAddress        string          `toml:"service_address"`
+	ReadBufferSize        config.Size     `toml:"read_buffer_size"`
+	AuthFile              string          `toml:"collectd_auth_file"`
+	SecurityLevel         string          `toml:"collectd_security_level"`
+	TypesDB               []string        `toml:"collectd_typesdb"`
+	TypesDBReloadInterval config.Duration `toml:"collectd_typesdb_reload_interval"`
+	ParseMultiValue       string          `toml:"collectd_parse_multivalue"`
+	Log                   telegraf.Logger `toml:"-"`
+
+	parser   *parser
+	listener *socket_listener.SocketListener
+	// typesDBFiles are the types.db files last loaded.
+	typesDBFiles []typesDBFile
+	done         chan struct{}
+	wg           sync.WaitGroup
+}
+
+var _ telegraf.ServiceInput = (*CollectD)(nil)
+
+// typesDBFile identifies a version of a types.db file.
+type typesDBFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func (*CollectD) SampleConfig() string {
+	return sampleConfig
+}
+
+func (*CollectD) Description() string {
+	return "Listens for the metrics of collectd sent by its network plugin"
+}
+
+func (c *CollectD) Init() error {
+	popts := network.ParseOpts{}
+	switch c.SecurityLevel {
+	case "", "none":
+		popts.SecurityLevel = network.None
+	case "sign":
+		popts.SecurityLevel = network.Sign
+	case "encrypt":
+		popts.SecurityLevel = network.Encrypt
+	default:
+		return fmt.Errorf("invalid collectd_security_level %q", c.SecurityLevel)
+	}
+	if c.AuthFile == "" {
+		c.AuthFile = defaultAuthFile
+	}
+	popts.PasswordLookup = network.NewAuthFile(c.AuthFile)
+
+	switch c.ParseMultiValue {
+	case "":
+		c.ParseMultiValue = multiValueSplit
+	case multiValueSplit, multiValueJoin:
+	default:
+		return fmt.Errorf("invalid collectd_parse_multivalue %q, must be split or join", c.ParseMultiValue)
+	}
+	c.parser = newParser(popts, c.ParseMultiValue, c.Log)
+	return c.reloadTypesDB()
+}
+
+func (c *CollectD) Start(acc telegraf.Accumulator) error {
+	c.listener = &socket_listener.SocketListener{
+		ServiceAddress: c.ServiceAddress,
+		ReadBufferSize: c.ReadBufferSize,
+		Log:            c.Log,
+		Parser:         c.parser,
+	}
+	if err := c.listener.Start(acc); err != nil {
+		return err
+	}
+	if c.TypesDBReloadInterval > 0 {
+		c.done = make(chan struct{})
+		c.wg.Add(1)
+		go c.runReload(time.Duration(c.TypesDBReloadInterval))
+	}
+	return nil
+}
+
+func (c *CollectD) Stop() {
+	if c.done != nil {
+		close(c.done)
+		c.wg.Wait()
+		c.done = nil
+	}
+	if c.listener != nil {
+		c.listener.Stop()
+	}
+}
+
+// Gather reports the value lists of unknown types dropped since the last
+// collection, so that the missing types.db entries can be found.
+func (c *CollectD) Gather(acc telegraf.Accumulator) error {
+	for typ, count := range c.parser.dropped() {
+		acc.AddFields(measurementUnknownTypes, map[string]interface{}{fieldDropped: count}, map[string]string{tagType: typ})
+	}
+	return nil
+}
+
+func (c *CollectD) runReload(interval time.Duration) {
+	defer c.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.reloadTypesDB(); err != nil {
+				c.Log.Warnf("Unable to reload the types.db files, keeping the previous types: %v", err)
+			}
+		}
+	}
+}
+
+// reloadTypesDB loads the types.db files if any of them was added, removed or
+// modified since they were last loaded.
+func (c *CollectD) reloadTypesDB() error {
+	files, err := findTypesDBFiles(c.TypesDB)
+	if err != nil {
+		return err
+	}
+	if c.typesDBFiles != nil && slices.Equal(files, c.typesDBFiles) {
+		return nil
+	}
+	var db *api.TypesDB
+	for _, file := range files {
+		fileDB, err := loadTypesDB(file.path)
+		if err != nil {
+			return fmt.Errorf("unable to load %s: %w", file.path, err)
+		}
+		if db == nil {
+			db = fileDB
+		} else {
+			db.Merge(fileDB)
+		}
+	}
+	if len(files) == 0 && len(c.TypesDB) > 0 {
+		c.Log.Warnf("No types.db file matches %v, the values are named by their index", c.TypesDB)
+	}
+	c.parser.typesDB.Store(db)
+	if c.typesDBFiles != nil {
+		c.Log.Infof("Reloaded %d types.db files", len(files))
+	}
+	c.typesDBFiles = files
+	return nil
+}
+
+// findTypesDBFiles expands the glob patterns of the paths. The paths without a
+// pattern must exist.
+func findTypesDBFiles(paths []string) ([]typesDBFile, error) {
+	files := []typesDBFile{}
+	for _, path := range paths {
+		matches := []string{path}
+		if strings.ContainsAny(path, "*?[") {
+			var err error
+			if matches, err = filepath.Glob(path); err != nil {
+				return nil, fmt.Errorf("invalid types.db pattern %q: %w", path, err)
+			}
+		}
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, err
+			}
+			if info.IsDir() {
+				return nil, fmt.Errorf("types.db %s is a directory", match)
+			}
+			files = append(files, typesDBFile{path: match, size: info.Size(), modTime: info.ModTime()})
+		}
+	}
+	return files, nil
+}
+
+func loadTypesDB(path string) (*api.TypesDB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return api.NewTypesDB(f)
+}
+
+func init() {
+	inputs.Add("collectd", func() telegraf.Input {
+		return &CollectD{
+			ServiceAddress:        defaultServiceAddress,
+			TypesDBReloadInterval: config.Duration(defaultReloadInterval),
+		}
+	})
+}
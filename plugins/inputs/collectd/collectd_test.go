@@ -0,0 +1,139 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"collectd.org/api"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "types.db"), []byte(testTypesDB), 0600))
+
+	testCases := map[string]struct {
+		collectd *CollectD
+		wantErr  bool
+	}{
+		"Default": {
+			collectd: &CollectD{TypesDB: []string{filepath.Join(dir, "types.db")}},
+		},
+		"WithUnmatchedGlob": {
+			collectd: &CollectD{TypesDB: []string{filepath.Join(dir, "types.d", "*.db")}},
+		},
+		"WithMissingFile": {
+			collectd: &CollectD{TypesDB: []string{filepath.Join(dir, "missing.db")}},
+			wantErr:  true,
+		},
+		"WithInvalidGlob": {
+			collectd: &CollectD{TypesDB: []string{filepath.Join(dir, "[.db")}},
+			wantErr:  true,
+		},
+		"WithInvalidSecurityLevel": {
+			collectd: &CollectD{SecurityLevel: "secret"},
+			wantErr:  true,
+		},
+		"WithInvalidMultiValue": {
+			collectd: &CollectD{ParseMultiValue: "merge"},
+			wantErr:  true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			testCase.collectd.Log = testutil.Logger{}
+			err := testCase.collectd.Init()
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReloadTypesDB(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "types.db"), []byte(testTypesDB), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "types.d"), 0700))
+
+	c := &CollectD{
+		TypesDB: []string{filepath.Join(dir, "types.db"), filepath.Join(dir, "types.d", "*.db")},
+		Log:     testutil.Logger{},
+	}
+	require.NoError(t, c.Init())
+	db := c.parser.typesDB.Load()
+	_, ok := db.DataSet("custom_type")
+	assert.False(t, ok)
+
+	// unchanged files are not loaded again
+	require.NoError(t, c.reloadTypesDB())
+	assert.Same(t, db, c.parser.typesDB.Load())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "types.d", "custom.db"), []byte("custom_type  value:GAUGE:U:U\n"), 0600))
+	require.NoError(t, c.reloadTypesDB())
+	_, ok = c.parser.typesDB.Load().DataSet("custom_type")
+	assert.True(t, ok)
+	_, ok = c.parser.typesDB.Load().DataSet("load")
+	assert.True(t, ok)
+
+	// the previous types are kept if the files cannot be loaded
+	require.NoError(t, os.Remove(filepath.Join(dir, "types.db")))
+	assert.Error(t, c.reloadTypesDB())
+	_, ok = c.parser.typesDB.Load().DataSet("custom_type")
+	assert.True(t, ok)
+}
+
+func TestCollectD(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "types.db"), []byte(testTypesDB), 0600))
+
+	c := &CollectD{
+		ServiceAddress:        "udp://127.0.0.1:0",
+		TypesDB:               []string{filepath.Join(dir, "*.db")},
+		TypesDBReloadInterval: config.Duration(10 * time.Millisecond),
+		Log:                   testutil.Logger{},
+	}
+	require.NoError(t, c.Init())
+	acc := &testutil.Accumulator{}
+	require.NoError(t, c.Start(acc))
+	defer c.Stop()
+
+	conn, err := net.Dial("udp", c.listener.Closer.(net.PacketConn).LocalAddr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	send := func(vls ...*api.ValueList) {
+		_, err := conn.Write(encode(t, vls...))
+		require.NoError(t, err)
+	}
+	send(valueList("queue", "queue_length", api.Gauge(5)), valueList("custom", "custom_type", api.Gauge(1)))
+	acc.Wait(1)
+	assert.Eventually(t, func() bool {
+		c.parser.mutex.Lock()
+		defer c.parser.mutex.Unlock()
+		return len(c.parser.unknownTypes) > 0
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, c.Gather(acc))
+	acc.AssertContainsTaggedFields(t, measurementUnknownTypes, map[string]interface{}{fieldDropped: 1}, map[string]string{tagType: "custom_type"})
+
+	// the types added to the types.db files are picked up without a restart
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "custom.db"), []byte("custom_type  value:GAUGE:U:U\n"), 0600))
+	assert.Eventually(t, func() bool {
+		_, ok := c.parser.typesDB.Load().DataSet("custom_type")
+		return ok
+	}, time.Second, 10*time.Millisecond)
+	send(valueList("custom", "custom_type", api.Gauge(2)))
+	assert.Eventually(t, func() bool {
+		return acc.HasMeasurement("custom_value")
+	}, time.Second, 10*time.Millisecond)
+}
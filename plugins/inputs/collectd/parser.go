@@ -0,0 +1,194 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectd
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"collectd.org/api"
+	"collectd.org/network"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/parsers"
+)
+
+const (
+	multiValueSplit = "split"
+	multiValueJoin  = "join"
+)
+
+// parser converts the collectd network packets into metrics. Unlike the
+// telegraf parser, the types.db can be replaced while the packets are parsed,
+// and the values of unknown types are counted instead of only being logged.
+type parser struct {
+	popts       network.ParseOpts
+	multiValue  string
+	defaultTags map[string]string
+	log         telegraf.Logger
+
+	// typesDB is nil if no types.db file is loaded. The values are then
+	// named by their index.
+	typesDB atomic.Pointer[api.TypesDB]
+
+	mutex        sync.Mutex
+	unknownTypes map[string]int
+	warned       map[string]bool
+}
+
+var _ parsers.Parser = (*parser)(nil)
+
+func newParser(popts network.ParseOpts, multiValue string, log telegraf.Logger) *parser {
+	return &parser{
+		popts:        popts,
+		multiValue:   multiValue,
+		log:          log,
+		unknownTypes: map[string]int{},
+		warned:       map[string]bool{},
+	}
+}
+
+func (p *parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	// the types are looked up here instead of by the network parser, which
+	// only logs the values it drops
+	valueLists, err := network.Parse(buf, p.popts)
+	if err != nil {
+		return nil, fmt.Errorf("collectd parser error: %w", err)
+	}
+	db := p.typesDB.Load()
+	var metrics []telegraf.Metric
+	for _, vl := range valueLists {
+		if db != nil && !p.applyTypesDB(db, vl) {
+			continue
+		}
+		metrics = append(metrics, p.unmarshalValueList(vl)...)
+	}
+	for _, m := range metrics {
+		for k, v := range p.defaultTags {
+			if !m.HasTag(k) {
+				m.AddTag(k, v)
+			}
+		}
+	}
+	return metrics, nil
+}
+
+func (p *parser) ParseLine(line string) (telegraf.Metric, error) {
+	metrics, err := p.Parse([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+	if len(metrics) != 1 {
+		return nil, errors.New("line contains multiple metrics")
+	}
+	return metrics[0], nil
+}
+
+func (p *parser) SetDefaultTags(tags map[string]string) {
+	p.defaultTags = tags
+}
+
+// applyTypesDB converts the values to the data source types of the type and
+// names them. Returns false if the value list is dropped.
+func (p *parser) applyTypesDB(db *api.TypesDB, vl *api.ValueList) bool {
+	ds, ok := db.DataSet(vl.Type)
+	if !ok {
+		p.dropUnknownType(vl.Type)
+		return false
+	}
+	values := make([]interface{}, len(vl.Values))
+	for i, v := range vl.Values {
+		values[i] = v
+	}
+	typed, err := ds.Values(values...)
+	if err != nil {
+		p.log.Debugf("Dropping the values of %q not matching the type %q: %v", vl.Identifier, vl.Type, err)
+		return false
+	}
+	vl.Values = typed
+	vl.DSNames = ds.Names()
+	return true
+}
+
+func (p *parser) dropUnknownType(typ string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.unknownTypes[typ]++
+	if !p.warned[typ] {
+		p.warned[typ] = true
+		p.log.Warnf("Dropping the values of the type %q missing from the types.db files", typ)
+	}
+}
+
+// dropped returns the number of value lists of each unknown type dropped
+// since it was last called.
+func (p *parser) dropped() map[string]int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	dropped := p.unknownTypes
+	p.unknownTypes = map[string]int{}
+	return dropped
+}
+
+// unmarshalValueList converts the value list into metrics the same way as the
+// telegraf collectd parser.
+func (p *parser) unmarshalValueList(vl *api.ValueList) []telegraf.Metric {
+	timestamp := vl.Time.UTC()
+	tags := map[string]string{}
+	if vl.Identifier.Host != "" {
+		tags["host"] = vl.Identifier.Host
+	}
+	if vl.Identifier.PluginInstance != "" {
+		tags["instance"] = vl.Identifier.PluginInstance
+	}
+	if vl.Identifier.Type != "" {
+		tags["type"] = vl.Identifier.Type
+	}
+	if vl.Identifier.TypeInstance != "" {
+		tags["type_instance"] = vl.Identifier.TypeInstance
+	}
+
+	if p.multiValue == multiValueJoin {
+		fields := map[string]interface{}{}
+		for i, value := range vl.Values {
+			if v, ok := toFloat(value); ok {
+				fields[vl.DSName(i)] = v
+			}
+		}
+		return []telegraf.Metric{metric.New(vl.Identifier.Plugin, tags, fields, timestamp)}
+	}
+
+	metrics := make([]telegraf.Metric, 0, len(vl.Values))
+	for i, value := range vl.Values {
+		fields := map[string]interface{}{}
+		if v, ok := toFloat(value); ok {
+			fields["value"] = v
+		}
+		name := fmt.Sprintf("%s_%s", vl.Identifier.Plugin, vl.DSName(i))
+		metrics = append(metrics, metric.New(name, copyTags(tags), fields, timestamp))
+	}
+	return metrics
+}
+
+func toFloat(value api.Value) (float64, bool) {
+	switch v := value.(type) {
+	case api.Gauge:
+		return float64(v), true
+	case api.Derive:
+		return float64(v), true
+	case api.Counter:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func copyTags(tags map[string]string) map[string]string {
+	c := make(map[string]string, len(tags))
+	for k, v := range tags {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,122 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"collectd.org/api"
+	"collectd.org/network"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTypesDB = `
+load  shortterm:GAUGE:0:5000, midterm:GAUGE:0:5000, longterm:GAUGE:0:5000
+queue_length  value:GAUGE:0:U
+`
+
+func encode(t *testing.T, vls ...*api.ValueList) []byte {
+	t.Helper()
+	buf := network.NewBuffer(0)
+	for _, vl := range vls {
+		require.NoError(t, buf.Write(context.Background(), vl))
+	}
+	b, err := buf.Bytes()
+	require.NoError(t, err)
+	return b
+}
+
+func valueList(plugin, typ string, values ...api.Value) *api.ValueList {
+	return &api.ValueList{
+		Identifier: api.Identifier{Host: "host1", Plugin: plugin, Type: typ},
+		Time:       time.Unix(1700000000, 0),
+		Interval:   10 * time.Second,
+		Values:     values,
+	}
+}
+
+func newTestParser(t *testing.T, multiValue string) *parser {
+	t.Helper()
+	p := newParser(network.ParseOpts{}, multiValue, testutil.Logger{})
+	db, err := api.NewTypesDB(strings.NewReader(testTypesDB))
+	require.NoError(t, err)
+	p.typesDB.Store(db)
+	return p
+}
+
+func TestParserSplit(t *testing.T) {
+	p := newTestParser(t, multiValueSplit)
+	p.SetDefaultTags(map[string]string{"env": "prod"})
+	metrics, err := p.Parse(encode(t, valueList("load", "load", api.Gauge(1), api.Gauge(2), api.Gauge(3))))
+	require.NoError(t, err)
+	require.Len(t, metrics, 3)
+	names := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		names = append(names, m.Name())
+		assert.Equal(t, map[string]string{"host": "host1", "type": "load", "env": "prod"}, m.Tags())
+	}
+	assert.Equal(t, []string{"load_shortterm", "load_midterm", "load_longterm"}, names)
+	assert.Equal(t, map[string]interface{}{"value": float64(2)}, metrics[1].Fields())
+}
+
+func TestParserJoin(t *testing.T) {
+	p := newTestParser(t, multiValueJoin)
+	metrics, err := p.Parse(encode(t, valueList("load", "load", api.Gauge(1), api.Gauge(2), api.Gauge(3))))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "load", metrics[0].Name())
+	assert.Equal(t, map[string]interface{}{"shortterm": float64(1), "midterm": float64(2), "longterm": float64(3)}, metrics[0].Fields())
+}
+
+func TestParserUnknownTypes(t *testing.T) {
+	p := newTestParser(t, multiValueSplit)
+	buf := encode(t,
+		valueList("custom", "custom_type", api.Gauge(1)),
+		valueList("queue", "queue_length", api.Gauge(5)),
+		valueList("custom", "custom_type", api.Gauge(2)),
+		// the number of values does not match the type
+		valueList("load", "load", api.Gauge(1)),
+	)
+	metrics, err := p.Parse(buf)
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "queue_value", metrics[0].Name())
+	assert.Equal(t, map[string]int{"custom_type": 2}, p.dropped())
+	assert.Empty(t, p.dropped())
+
+	// the types added to the types.db are no longer dropped
+	db, err := api.NewTypesDB(strings.NewReader(testTypesDB + "custom_type  value:GAUGE:U:U\n"))
+	require.NoError(t, err)
+	p.typesDB.Store(db)
+	metrics, err = p.Parse(buf)
+	require.NoError(t, err)
+	assert.Len(t, metrics, 3)
+	assert.Empty(t, p.dropped())
+}
+
+func TestParserWithoutTypesDB(t *testing.T) {
+	p := newParser(network.ParseOpts{}, multiValueSplit, testutil.Logger{})
+	metrics, err := p.Parse(encode(t, valueList("custom", "custom_type", api.Gauge(1), api.Derive(2))))
+	require.NoError(t, err)
+	got := map[string]telegraf.Metric{}
+	for _, m := range metrics {
+		got[m.Name()] = m
+	}
+	assert.Len(t, got, 2)
+	assert.Contains(t, got, "custom_0")
+	assert.Contains(t, got, "custom_1")
+	assert.Empty(t, p.dropped())
+}
+
+func TestParserInvalidPacket(t *testing.T) {
+	p := newTestParser(t, multiValueSplit)
+	_, err := p.Parse([]byte{0x00, 0x01})
+	assert.Error(t, err)
+}
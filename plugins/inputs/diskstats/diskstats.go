@@ -0,0 +1,345 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package diskstats
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/inputs"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+const (
+	measurement = "diskstats"
+
+	defaultProcPath   = "/proc"
+	defaultCgroupPath = "/sys/fs/cgroup"
+	// procPathEnv and sysPathEnv are the procfs and sysfs mounts of the host
+	// when the agent runs in a container, as for gopsutil.
+	procPathEnv = "HOST_PROC"
+	sysPathEnv  = "HOST_SYS"
+
+	defaultSampleInterval = time.Second
+
+	tagDevice = "name"
+	tagCgroup = "cgroup"
+
+	fieldReadLatency    = "read_latency"
+	fieldWriteLatency   = "write_latency"
+	fieldDiscardLatency = "discard_latency"
+	fieldFlushLatency   = "flush_latency"
+	fieldQueueDepth     = "queue_depth"
+	fieldInFlight       = "in_flight"
+	fieldIOLatency      = "io_latency"
+	fieldReadOps        = "read_ops"
+	fieldWriteOps       = "write_ops"
+	fieldReadBytes      = "read_bytes"
+	fieldWriteBytes     = "write_bytes"
+)
+
+// DiskStats samples the block devices more often than it is collected, so
+// that the latency and the queue depth of each device are reported as
+// distributions of the samples of the interval instead of an average.
+type DiskStats struct {
+	Devices        []string        `toml:"devices"`
+	ExcludeDevices []string        `toml:"exclude_devices"`
+	Cgroups        []string        `toml:"cgroups"`
+	SampleInterval config.Duration `toml:"sample_interval"`
+	ProcPath       string          `toml:"proc_path"`
+	CgroupPath     string          `toml:"cgroup_path"`
+	Log            telegraf.Logger `toml:"-"`
+
+	devices filter.Filter
+
+	mu sync.Mutex
+	// previous are the counters of the last sample, to compute the changes
+	// of the next one.
+	previous        map[string]*deviceStats
+	previousCgroups map[string]map[string]*cgroupStats
+	previousTime    time.Time
+	// distributions and counters are the samples since the last collection.
+	distributions map[series]map[string]distribution.Distribution
+	counters      map[series]map[string]uint64
+	// warned is set once a failed sample was logged.
+	warned bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// series are the dimensions of the metrics, the cgroup is only set for the
+// cgroup stats.
+type series struct {
+	device string
+	cgroup string
+}
+
+var _ telegraf.ServiceInput = (*DiskStats)(nil)
+
+func (*DiskStats) SampleConfig() string {
+	return sampleConfig
+}
+
+func (*DiskStats) Description() string {
+	return "Collects the latency and the queue depth distributions of the block devices"
+}
+
+func (d *DiskStats) Init() error {
+	if d.ProcPath == "" {
+		d.ProcPath = defaultProcPath
+		if hostProc := os.Getenv(procPathEnv); hostProc != "" {
+			d.ProcPath = hostProc
+		}
+	}
+	if d.CgroupPath == "" {
+		d.CgroupPath = defaultCgroupPath
+		if hostSys := os.Getenv(sysPathEnv); hostSys != "" {
+			d.CgroupPath = filepath.Join(hostSys, "fs", "cgroup")
+		}
+	}
+	if d.SampleInterval <= 0 {
+		d.SampleInterval = config.Duration(defaultSampleInterval)
+	}
+	devices, err := filter.NewIncludeExcludeFilter(d.Devices, d.ExcludeDevices)
+	if err != nil {
+		return fmt.Errorf("invalid device filters: %w", err)
+	}
+	for _, pattern := range d.Cgroups {
+		if _, err = filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid cgroup %q: %w", pattern, err)
+		}
+	}
+	d.devices = devices
+	d.reset()
+	return nil
+}
+
+func (d *DiskStats) reset() {
+	d.distributions = map[series]map[string]distribution.Distribution{}
+	d.counters = map[series]map[string]uint64{}
+}
+
+func (d *DiskStats) Start(telegraf.Accumulator) error {
+	d.done = make(chan struct{})
+	d.sampleAndLog(time.Now())
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(time.Duration(d.SampleInterval))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.done:
+				return
+			case now := <-ticker.C:
+				d.sampleAndLog(now)
+			}
+		}
+	}()
+	return nil
+}
+
+func (d *DiskStats) Stop() {
+	close(d.done)
+	d.wg.Wait()
+}
+
+// Gather reports the distributions of the samples since the previous
+// collection.
+func (d *DiskStats) Gather(acc telegraf.Accumulator) error {
+	d.mu.Lock()
+	distributions, counters := d.distributions, d.counters
+	d.reset()
+	d.mu.Unlock()
+
+	for s, fields := range distributions {
+		histogram := make(map[string]interface{}, len(fields))
+		for field, dist := range fields {
+			histogram[field] = dist
+		}
+		acc.AddHistogram(measurement, histogram, s.tags())
+	}
+	for s, fields := range counters {
+		values := make(map[string]interface{}, len(fields))
+		for field, v := range fields {
+			values[field] = v
+		}
+		acc.AddFields(measurement, values, s.tags())
+	}
+	return nil
+}
+
+func (s series) tags() map[string]string {
+	tags := map[string]string{tagDevice: s.device}
+	if s.cgroup != "" {
+		tags[tagCgroup] = s.cgroup
+	}
+	return tags
+}
+
+func (d *DiskStats) sampleAndLog(now time.Time) {
+	if err := d.sample(now); err != nil && !d.warned {
+		d.warned = true
+		d.Log.Warnf("Unable to sample the disk stats: %v", err)
+	}
+}
+
+// sample adds the changes since the previous sample to the distributions.
+func (d *DiskStats) sample(now time.Time) error {
+	f, err := os.Open(filepath.Join(d.ProcPath, "diskstats"))
+	if err != nil {
+		return err
+	}
+	current, err := parseDiskStats(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	cgroups := d.readCgroups()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	elapsedMs := float64(now.Sub(d.previousTime)) / float64(time.Millisecond)
+	for devnum, stats := range current {
+		if !d.devices.Match(stats.name) {
+			continue
+		}
+		previous, ok := d.previous[devnum]
+		if !ok || previous.name != stats.name || !countersIncreased(previous, stats) {
+			continue
+		}
+		s := series{device: stats.name}
+		d.addLatency(s, fieldReadLatency, stats.reads-previous.reads, stats.readMs-previous.readMs)
+		d.addLatency(s, fieldWriteLatency, stats.writes-previous.writes, stats.writeMs-previous.writeMs)
+		d.addLatency(s, fieldDiscardLatency, stats.discards-previous.discards, stats.discardMs-previous.discardMs)
+		d.addLatency(s, fieldFlushLatency, stats.flushes-previous.flushes, stats.flushMs-previous.flushMs)
+		if elapsedMs > 0 {
+			// the time spent by the requests in the queue over the elapsed
+			// time is the average number of requests queued
+			d.addEntry(s, fieldQueueDepth, float64(stats.queueMs-previous.queueMs)/elapsedMs, 1)
+		}
+		d.addEntry(s, fieldInFlight, float64(stats.inFlight), 1)
+	}
+	for cgroup, devices := range cgroups {
+		for devnum, stats := range devices {
+			device, ok := current[devnum]
+			if !ok || !d.devices.Match(device.name) {
+				continue
+			}
+			previous, ok := d.previousCgroups[cgroup][devnum]
+			if !ok || stats.reads < previous.reads || stats.writes < previous.writes || stats.discards < previous.discards ||
+				stats.readBytes < previous.readBytes || stats.writeBytes < previous.writeBytes {
+				continue
+			}
+			s := series{device: device.name, cgroup: cgroup}
+			ios := stats.reads - previous.reads + stats.writes - previous.writes + stats.discards - previous.discards
+			d.addCounter(s, fieldReadOps, stats.reads-previous.reads)
+			d.addCounter(s, fieldWriteOps, stats.writes-previous.writes)
+			d.addCounter(s, fieldReadBytes, stats.readBytes-previous.readBytes)
+			d.addCounter(s, fieldWriteBytes, stats.writeBytes-previous.writeBytes)
+			if stats.hasAvgLat && ios > 0 {
+				d.addEntry(s, fieldIOLatency, float64(stats.avgLatUs)/1000, float64(ios))
+			}
+		}
+	}
+	d.previous = current
+	d.previousCgroups = cgroups
+	d.previousTime = now
+	return nil
+}
+
+// readCgroups reads the io.stat of the cgroups matching the patterns, keyed
+// by their path from the root of the hierarchy.
+func (d *DiskStats) readCgroups() map[string]map[string]*cgroupStats {
+	cgroups := map[string]map[string]*cgroupStats{}
+	for _, pattern := range d.Cgroups {
+		dirs, _ := filepath.Glob(filepath.Join(d.CgroupPath, pattern))
+		for _, dir := range dirs {
+			f, err := os.Open(filepath.Join(dir, "io.stat"))
+			if err != nil {
+				if !errors.Is(err, fs.ErrNotExist) && !d.warned {
+					d.warned = true
+					d.Log.Warnf("Unable to read the io.stat of %s: %v", dir, err)
+				}
+				continue
+			}
+			stats, err := parseIOStat(f)
+			f.Close()
+			if err != nil {
+				continue
+			}
+			rel, err := filepath.Rel(d.CgroupPath, dir)
+			if err != nil {
+				continue
+			}
+			cgroups["/"+filepath.ToSlash(rel)] = stats
+		}
+	}
+	return cgroups
+}
+
+// countersIncreased is false if the counters of the device were reset.
+func countersIncreased(previous, current *deviceStats) bool {
+	return current.reads >= previous.reads && current.readMs >= previous.readMs &&
+		current.writes >= previous.writes && current.writeMs >= previous.writeMs &&
+		current.discards >= previous.discards && current.discardMs >= previous.discardMs &&
+		current.flushes >= previous.flushes && current.flushMs >= previous.flushMs &&
+		current.queueMs >= previous.queueMs
+}
+
+// addLatency adds the average latency of the operations of the sample,
+// weighted by their count.
+func (d *DiskStats) addLatency(s series, field string, ops, ms uint64) {
+	if ops == 0 {
+		return
+	}
+	d.addEntry(s, field, float64(ms)/float64(ops), float64(ops))
+}
+
+func (d *DiskStats) addEntry(s series, field string, value, weight float64) {
+	fields, ok := d.distributions[s]
+	if !ok {
+		fields = map[string]distribution.Distribution{}
+		d.distributions[s] = fields
+	}
+	dist, ok := fields[field]
+	if !ok {
+		dist = distribution.NewDistribution()
+		fields[field] = dist
+	}
+	if err := dist.AddEntry(value, weight); err != nil {
+		d.Log.Debugf("Unable to add %v to %s of %s: %v", value, field, s.device, err)
+	}
+}
+
+func (d *DiskStats) addCounter(s series, field string, value uint64) {
+	fields, ok := d.counters[s]
+	if !ok {
+		fields = map[string]uint64{}
+		d.counters[s] = fields
+	}
+	fields[field] += value
+}
+
+func init() {
+	inputs.Add(measurement, func() telegraf.Input {
+		return &DiskStats{
+			ExcludeDevices: []string{"loop*", "ram*"},
+		}
+	})
+}
@@ -0,0 +1,199 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package diskstats
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/regular"
+)
+
+// diskStatsFormat has a device with the flush counters, one without the
+// discard counters and a loop device.
+const diskStatsFormat = `   7       0 loop0 10 0 80 %d 0 0 0 0 0 10 10 0 0 0 0
+ 259       0 nvme0n1 %d 0 1000 %d %d 0 2000 %d %d 500 %d 0 0 0 0 %d %d
+ 259       1 nvme0n1p1 5 0 40 5 0 0 0 0 0 5 5 0 0 0 0 0 0
+ 202       0 xvda 100 0 800 200 50 0 400 100 0 300 300
+`
+
+type sample struct {
+	reads, readMs, writes, writeMs, inFlight, queueMs, flushes, flushMs int
+}
+
+func writeDiskStats(t *testing.T, proc string, s sample) {
+	t.Helper()
+	content := fmt.Sprintf(diskStatsFormat, s.readMs, s.reads, s.readMs, s.writes, s.writeMs, s.inFlight, s.queueMs, s.flushes, s.flushMs)
+	require.NoError(t, os.WriteFile(filepath.Join(proc, "diskstats"), []byte(content), 0644))
+}
+
+func newTestDiskStats(t *testing.T) *DiskStats {
+	t.Helper()
+	distribution.NewDistribution = regular.NewRegularDistribution
+	d := &DiskStats{
+		ExcludeDevices: []string{"loop*"},
+		ProcPath:       t.TempDir(),
+		CgroupPath:     t.TempDir(),
+		Log:            testutil.Logger{},
+	}
+	require.NoError(t, d.Init())
+	return d
+}
+
+func TestGather(t *testing.T) {
+	d := newTestDiskStats(t)
+	d.Devices = []string{"nvme?n?"}
+	require.NoError(t, d.Init())
+	start := time.Now()
+
+	writeDiskStats(t, d.ProcPath, sample{reads: 100, readMs: 100, writes: 10, writeMs: 10})
+	require.NoError(t, d.sample(start))
+	// 10 reads of 2ms, 2 writes of 5ms, 1 flush of 1ms and 2 requests queued
+	writeDiskStats(t, d.ProcPath, sample{reads: 110, readMs: 120, writes: 12, writeMs: 20, inFlight: 3, queueMs: 2000, flushes: 1, flushMs: 1})
+	require.NoError(t, d.sample(start.Add(time.Second)))
+	// 30 reads of 1ms and nothing queued
+	writeDiskStats(t, d.ProcPath, sample{reads: 140, readMs: 150, writes: 12, writeMs: 20, inFlight: 1, queueMs: 2000, flushes: 1, flushMs: 1})
+	require.NoError(t, d.sample(start.Add(2*time.Second)))
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, d.Gather(acc))
+	require.Len(t, acc.Metrics, 1)
+	m := acc.Metrics[0]
+	assert.Equal(t, measurement, m.Measurement)
+	assert.Equal(t, map[string]string{tagDevice: "nvme0n1"}, m.Tags)
+	assert.NotContains(t, m.Fields, fieldDiscardLatency)
+
+	reads := m.Fields[fieldReadLatency].(distribution.Distribution)
+	assert.Equal(t, 40.0, reads.SampleCount())
+	assert.Equal(t, 2.0, reads.Maximum())
+	assert.Equal(t, 1.0, reads.Minimum())
+	assert.Equal(t, 50.0, reads.Sum())
+	writes := m.Fields[fieldWriteLatency].(distribution.Distribution)
+	assert.Equal(t, 2.0, writes.SampleCount())
+	assert.Equal(t, 5.0, writes.Maximum())
+	flushes := m.Fields[fieldFlushLatency].(distribution.Distribution)
+	assert.Equal(t, 1.0, flushes.SampleCount())
+	queueDepth := m.Fields[fieldQueueDepth].(distribution.Distribution)
+	assert.Equal(t, 2.0, queueDepth.SampleCount())
+	assert.Equal(t, 2.0, queueDepth.Maximum())
+	assert.Equal(t, 0.0, queueDepth.Minimum())
+	inFlight := m.Fields[fieldInFlight].(distribution.Distribution)
+	assert.Equal(t, 3.0, inFlight.Maximum())
+	assert.Equal(t, 1.0, inFlight.Minimum())
+
+	// the samples are reported once
+	acc = &testutil.Accumulator{}
+	require.NoError(t, d.Gather(acc))
+	assert.Empty(t, acc.Metrics)
+}
+
+func TestGatherReset(t *testing.T) {
+	d := newTestDiskStats(t)
+	start := time.Now()
+	writeDiskStats(t, d.ProcPath, sample{reads: 100, readMs: 100})
+	require.NoError(t, d.sample(start))
+	// the counters were reset
+	writeDiskStats(t, d.ProcPath, sample{reads: 10, readMs: 10})
+	require.NoError(t, d.sample(start.Add(time.Second)))
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, d.Gather(acc))
+	// the unchanged devices, without the reset and the excluded ones
+	require.Len(t, acc.Metrics, 2)
+	for _, m := range acc.Metrics {
+		assert.Contains(t, []string{"nvme0n1p1", "xvda"}, m.Tags[tagDevice])
+		assert.NotContains(t, m.Fields, fieldReadLatency)
+		assert.Contains(t, m.Fields, fieldQueueDepth)
+	}
+}
+
+func TestGatherCgroups(t *testing.T) {
+	d := newTestDiskStats(t)
+	d.Cgroups = []string{"system.slice/docker-*.scope"}
+	require.NoError(t, d.Init())
+	cgroup := filepath.Join(d.CgroupPath, "system.slice", "docker-abc.scope")
+	require.NoError(t, os.MkdirAll(cgroup, 0755))
+	writeIOStat := func(content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(cgroup, "io.stat"), []byte(content), 0644))
+	}
+	start := time.Now()
+	writeDiskStats(t, d.ProcPath, sample{reads: 100, readMs: 100})
+	writeIOStat("259:0 rbytes=4096 wbytes=0 rios=1 wios=0 dbytes=0 dios=0 depth=max avg_lat=100 win=100\n")
+	require.NoError(t, d.sample(start))
+	writeIOStat("259:0 rbytes=12288 wbytes=4096 rios=3 wios=1 dbytes=0 dios=0 depth=max avg_lat=2500 win=100\n" +
+		"8:0 rbytes=4096 wbytes=0 rios=1 wios=0 dbytes=0 dios=0\n")
+	require.NoError(t, d.sample(start.Add(time.Second)))
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, d.Gather(acc))
+	var found bool
+	for _, m := range acc.Metrics {
+		if m.Tags[tagCgroup] == "" {
+			continue
+		}
+		assert.Equal(t, "/system.slice/docker-abc.scope", m.Tags[tagCgroup])
+		assert.Equal(t, "nvme0n1", m.Tags[tagDevice])
+		if latency, ok := m.Fields[fieldIOLatency]; ok {
+			dist := latency.(distribution.Distribution)
+			assert.Equal(t, 3.0, dist.SampleCount())
+			assert.Equal(t, 2.5, dist.Maximum())
+			found = true
+		} else {
+			assert.Equal(t, uint64(2), m.Fields[fieldReadOps])
+			assert.Equal(t, uint64(1), m.Fields[fieldWriteOps])
+			assert.Equal(t, uint64(8192), m.Fields[fieldReadBytes])
+			assert.Equal(t, uint64(4096), m.Fields[fieldWriteBytes])
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestStartStop(t *testing.T) {
+	d := newTestDiskStats(t)
+	d.SampleInterval = config.Duration(10 * time.Millisecond)
+	writeDiskStats(t, d.ProcPath, sample{reads: 100, readMs: 100})
+	acc := &testutil.Accumulator{}
+	require.NoError(t, d.Start(acc))
+	defer d.Stop()
+	assert.Eventually(t, func() bool {
+		acc := &testutil.Accumulator{}
+		return d.Gather(acc) == nil && len(acc.Metrics) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestParseDiskStats(t *testing.T) {
+	devices, err := parseDiskStats(strings.NewReader(fmt.Sprintf(diskStatsFormat, 1, 2, 3, 4, 5, 6, 7, 8, 9)))
+	require.NoError(t, err)
+	assert.Len(t, devices, 4)
+	nvme := devices["259:0"]
+	assert.Equal(t, &deviceStats{name: "nvme0n1", reads: 2, readMs: 3, writes: 4, writeMs: 5, inFlight: 6, ioMs: 500, queueMs: 7, flushes: 8, flushMs: 9}, nvme)
+	xvda := devices["202:0"]
+	assert.Equal(t, uint64(0), xvda.discards)
+
+	_, err = parseDiskStats(strings.NewReader("259 0 nvme0n1 x 0 0 0 0 0 0 0 0 0 0\n"))
+	assert.Error(t, err)
+}
+
+func TestInit(t *testing.T) {
+	t.Setenv(procPathEnv, "/rootfs/proc")
+	t.Setenv(sysPathEnv, "/rootfs/sys")
+	d := &DiskStats{}
+	require.NoError(t, d.Init())
+	assert.Equal(t, "/rootfs/proc", d.ProcPath)
+	assert.Equal(t, "/rootfs/sys/fs/cgroup", d.CgroupPath)
+	assert.Equal(t, config.Duration(defaultSampleInterval), d.SampleInterval)
+
+	d = &DiskStats{Cgroups: []string{"[invalid"}}
+	assert.Error(t, d.Init())
+}
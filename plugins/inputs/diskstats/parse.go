@@ -0,0 +1,129 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package diskstats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// deviceStats are the counters of a device in /proc/diskstats. The discard
+// and flush counters are only present since Linux 4.18 and 5.5.
+type deviceStats struct {
+	name      string
+	reads     uint64
+	readMs    uint64
+	writes    uint64
+	writeMs   uint64
+	inFlight  uint64
+	ioMs      uint64
+	queueMs   uint64
+	discards  uint64
+	discardMs uint64
+	flushes   uint64
+	flushMs   uint64
+}
+
+// parseDiskStats reads /proc/diskstats, keyed by the major:minor of the
+// devices.
+func parseDiskStats(r io.Reader) (map[string]*deviceStats, error) {
+	devices := map[string]*deviceStats{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// major minor name and at least the 11 original counters
+		if len(fields) < 14 {
+			continue
+		}
+		counters := make([]uint64, len(fields)-3)
+		for i, field := range fields[3:] {
+			v, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid counter of %s: %w", fields[2], err)
+			}
+			counters[i] = v
+		}
+		stats := &deviceStats{
+			name:     fields[2],
+			reads:    counters[0],
+			readMs:   counters[3],
+			writes:   counters[4],
+			writeMs:  counters[7],
+			inFlight: counters[8],
+			ioMs:     counters[9],
+			queueMs:  counters[10],
+		}
+		if len(counters) >= 15 {
+			stats.discards = counters[11]
+			stats.discardMs = counters[14]
+		}
+		if len(counters) >= 17 {
+			stats.flushes = counters[15]
+			stats.flushMs = counters[16]
+		}
+		devices[fields[0]+":"+fields[1]] = stats
+	}
+	return devices, scanner.Err()
+}
+
+// cgroupStats are the counters of a device in the io.stat of a cgroup. The
+// average latency is only reported by the kernel when the io.latency
+// controller is enabled for the cgroup.
+type cgroupStats struct {
+	readBytes  uint64
+	writeBytes uint64
+	reads      uint64
+	writes     uint64
+	discards   uint64
+	// avgLatUs is the moving average of the latency in microseconds.
+	avgLatUs  uint64
+	hasAvgLat bool
+}
+
+// parseIOStat reads the io.stat of a cgroup v2, keyed by the major:minor of
+// the devices, e.g.
+//
+//	259:0 rbytes=1024 wbytes=2048 rios=1 wios=2 dbytes=0 dios=0 depth=max avg_lat=250 win=100
+func parseIOStat(r io.Reader) (map[string]*cgroupStats, error) {
+	devices := map[string]*cgroupStats{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		stats := &cgroupStats{}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			v, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				// e.g. depth=max
+				continue
+			}
+			switch key {
+			case "rbytes":
+				stats.readBytes = v
+			case "wbytes":
+				stats.writeBytes = v
+			case "rios":
+				stats.reads = v
+			case "wios":
+				stats.writes = v
+			case "dios":
+				stats.discards = v
+			case "avg_lat":
+				stats.avgLatUs = v
+				stats.hasAvgLat = true
+			}
+		}
+		devices[fields[0]] = stats
+	}
+	return devices, scanner.Err()
+}
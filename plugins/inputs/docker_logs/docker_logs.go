@@ -0,0 +1,195 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker_logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/tool/paths"
+)
+
+const (
+	defaultEndpoint      = "unix:///var/run/docker.sock"
+	defaultLogStreamName = containerNamePlaceholder
+)
+
+// dockerClient is the part of the Docker Engine API used by the plugin.
+type dockerClient interface {
+	ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error)
+	ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error)
+	ContainerLogs(ctx context.Context, containerID string, options container.LogsOptions) (io.ReadCloser, error)
+	Close() error
+}
+
+// newClient connects to the Docker Engine API at the endpoint. Overridden in
+// tests.
+var newClient = func(endpoint string) (dockerClient, error) {
+	return client.NewClientWithOpts(client.WithHost(endpoint), client.WithAPIVersionNegotiation())
+}
+
+type ContainerConfig struct {
+	// LabelFilters select the containers by their labels, with either "key"
+	// or "key=value". All the containers are collected if it is empty.
+	LabelFilters []string `toml:"label_filters"`
+	// Streams are the output streams collected, stdout and stderr if empty.
+	Streams       []string `toml:"streams"`
+	LogGroupName  string   `toml:"log_group_name"`
+	LogStreamName string   `toml:"log_stream_name"`
+	LogGroupClass string   `toml:"log_group_class"`
+	Destination   string   `toml:"destination"`
+	Retention     int      `toml:"retention_in_days"`
+}
+
+// collects returns whether the output stream of the containers is collected.
+func (c ContainerConfig) collects(stream string) bool {
+	return len(c.Streams) == 0 || slices.Contains(c.Streams, stream)
+}
+
+type Plugin struct {
+	Endpoint        string            `toml:"endpoint"`
+	FileStateFolder string            `toml:"file_state_folder"`
+	Containers      []ContainerConfig `toml:"container_config"`
+	Destination     string            `toml:"destination"`
+	Log             telegraf.Logger   `toml:"-"`
+
+	mu         sync.Mutex
+	client     dockerClient
+	sources    []*logsource.Src
+	newSources []logs.LogSrc
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+var _ logs.LogCollection = (*Plugin)(nil)
+
+func (p *Plugin) Description() string {
+	return "A plugin to collect the stdout and stderr of the Docker containers"
+}
+
+func (p *Plugin) SampleConfig() string {
+	return `
+	## The Docker Engine API endpoint.
+	endpoint = "unix:///var/run/docker.sock"
+	file_state_folder = ` + strconv.Quote(paths.FileStateFolderPath) + `
+	destination = "cloudwatchlogs"
+
+	[[inputs.docker_logs.container_config]]
+	## The containers of all the labels are collected if empty.
+	label_filters = ["com.example.team=payments", "com.example.service"]
+	## Either or both of stdout and stderr.
+	streams = ["stdout", "stderr"]
+	## The {container_name}, {container_id}, {image} and {label:<key>}
+	## placeholders are replaced by the metadata of the container.
+	log_group_name = "/docker/{label:com.example.service}"
+	log_stream_name = "{container_name}"
+	`
+}
+
+func (p *Plugin) Gather(acc telegraf.Accumulator) error {
+	return nil
+}
+
+func (p *Plugin) FindLogSrc() []logs.LogSrc {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	srcs := p.newSources
+	p.newSources = nil
+	return srcs
+}
+
+// Start follows the containers of each container config in the background.
+// The sources are created as the lines of their containers are read.
+func (p *Plugin) Start(acc telegraf.Accumulator) error {
+	if len(p.Containers) == 0 {
+		return errors.New("no container_config configured")
+	}
+	if p.FileStateFolder == "" {
+		return errors.New("empty file_state_folder")
+	}
+	if p.Endpoint == "" {
+		p.Endpoint = defaultEndpoint
+	}
+	configs := make([]ContainerConfig, 0, len(p.Containers))
+	for _, config := range p.Containers {
+		if config.LogGroupName == "" {
+			return errors.New("log_group_name is required")
+		}
+		for _, stream := range config.Streams {
+			if stream != streamStdout && stream != streamStderr {
+				return fmt.Errorf("invalid stream: %s", stream)
+			}
+		}
+		if config.LogStreamName == "" {
+			config.LogStreamName = defaultLogStreamName
+		}
+		if config.Destination == "" {
+			config.Destination = p.Destination
+		}
+		configs = append(configs, config)
+	}
+	if err := os.MkdirAll(p.FileStateFolder, 0755); err != nil {
+		return err
+	}
+	c, err := newClient(p.Endpoint)
+	if err != nil {
+		return fmt.Errorf("unable to connect to %s: %w", p.Endpoint, err)
+	}
+	p.client = c
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	for _, config := range configs {
+		w := newContainerWatcher(config, c, p.FileStateFolder, p.Log, p.addSource)
+		p.wg.Add(2)
+		go func() {
+			defer p.wg.Done()
+			w.run(ctx)
+		}()
+		go func() {
+			defer p.wg.Done()
+			w.runSaveState(ctx)
+		}()
+	}
+	return nil
+}
+
+func (p *Plugin) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+	if p.client != nil {
+		_ = p.client.Close()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, src := range p.sources {
+		src.Stop()
+	}
+}
+
+func (p *Plugin) addSource(src *logsource.Src) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sources = append(p.sources, src)
+	p.newSources = append(p.newSources, src)
+}
+
+func init() {
+	inputs.Add("docker_logs", func() telegraf.Input { return &Plugin{} })
+}
@@ -0,0 +1,213 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker_logs
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+const testContainerID = "4f66ad9a0b2e8c3f1e7d6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d"
+
+// fakeClient lists a running and a stopped container, and returns the
+// output of the running one until the context is done.
+type fakeClient struct {
+	output []byte
+
+	mu          sync.Mutex
+	listOptions []container.ListOptions
+	logsOptions []container.LogsOptions
+}
+
+var _ dockerClient = (*fakeClient)(nil)
+
+func (f *fakeClient) ContainerList(_ context.Context, options container.ListOptions) ([]types.Container, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.listOptions = append(f.listOptions, options)
+	return []types.Container{
+		{ID: testContainerID, State: "running"},
+		{ID: "stopped", State: "exited"},
+	}, nil
+}
+
+func (f *fakeClient) ContainerInspect(_ context.Context, id string) (types.ContainerJSON, error) {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{ID: id, Name: "/payments-api"},
+		Config: &container.Config{
+			Image:  "payments:1.2",
+			Labels: map[string]string{"com.example.team": "payments"},
+		},
+	}, nil
+}
+
+func (f *fakeClient) ContainerLogs(ctx context.Context, _ string, options container.LogsOptions) (io.ReadCloser, error) {
+	f.mu.Lock()
+	f.logsOptions = append(f.logsOptions, options)
+	f.mu.Unlock()
+	r, w := io.Pipe()
+	go func() {
+		_, _ = w.Write(f.output)
+		// the output is followed until the container stops
+		<-ctx.Done()
+		_ = w.CloseWithError(ctx.Err())
+	}()
+	return r, nil
+}
+
+func (f *fakeClient) Close() error {
+	return nil
+}
+
+func (f *fakeClient) calls() ([]container.ListOptions, []container.LogsOptions) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.listOptions, f.logsOptions
+}
+
+func TestPlugin(t *testing.T) {
+	var output []byte
+	output = append(output, frame(frameStdout, "2024-01-02T03:04:05Z listening on :8080\n")...)
+	output = append(output, frame(frameStderr, "2024-01-02T03:04:06Z connection refused\n")...)
+	fake := &fakeClient{output: output}
+	defer func(original func(string) (dockerClient, error)) {
+		newClient = original
+	}(newClient)
+	newClient = func(endpoint string) (dockerClient, error) {
+		assert.Equal(t, defaultEndpoint, endpoint)
+		return fake, nil
+	}
+
+	config := ContainerConfig{LabelFilters: []string{"com.example.team"}, LogGroupName: "/docker/{label:com.example.team}", Retention: 7}
+	p := &Plugin{
+		FileStateFolder: t.TempDir(),
+		Destination:     "cloudwatchlogs",
+		Containers:      []ContainerConfig{config},
+		Log:             testutil.Logger{},
+	}
+	config.LogStreamName = defaultLogStreamName
+	w := newContainerWatcher(config, nil, p.FileStateFolder, nil, nil)
+	staleState := w.stateFilePath("removed")
+	require.NoError(t, os.WriteFile(staleState, []byte("2024-01-01T00:00:00Z"), 0644))
+	require.NoError(t, p.Start(nil))
+
+	var src logs.LogSrc
+	events := make(chan logs.LogEvent, 2)
+	assert.Eventually(t, func() bool {
+		for _, s := range p.FindLogSrc() {
+			src = s
+			s.SetOutput(func(e logs.LogEvent) {
+				if e != nil {
+					events <- e
+				}
+			})
+		}
+		return src != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "/docker/payments", src.Group())
+	assert.Equal(t, "payments-api", src.Stream())
+	assert.Equal(t, "docker:payments-api", src.Description())
+	assert.Equal(t, "cloudwatchlogs", src.Destination())
+	assert.Equal(t, 7, src.Retention())
+
+	var published []logs.LogEvent
+	for len(published) < 2 {
+		select {
+		case e := <-events:
+			published = append(published, e)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event published")
+		}
+	}
+	assert.Equal(t, "listening on :8080", published[0].Message())
+	assert.Equal(t, "connection refused", published[1].Message())
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 6, 0, time.UTC), published[1].Time())
+	published[0].Done()
+	published[1].Done()
+
+	// the timestamp of the last published line is saved, and the state of
+	// the removed container is deleted
+	stateFile := w.stateFilePath(testContainerID)
+	assert.Eventually(t, func() bool {
+		content, err := os.ReadFile(stateFile)
+		return err == nil && string(content) == "2024-01-02T03:04:06Z"
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoFileExists(t, staleState)
+	p.Stop()
+
+	listOptions, logsOptions := fake.calls()
+	require.NotEmpty(t, listOptions)
+	assert.True(t, listOptions[0].All)
+	assert.Equal(t, []string{"com.example.team"}, listOptions[0].Filters.Get("label"))
+	require.Len(t, logsOptions, 1)
+	assert.Equal(t, container.LogsOptions{ShowStdout: true, ShowStderr: true, Follow: true, Timestamps: true, Tail: "0"}, logsOptions[0])
+}
+
+func TestWatcherResume(t *testing.T) {
+	var output []byte
+	output = append(output, frame(frameStderr, "2024-01-02T03:04:05Z published before\n")...)
+	output = append(output, frame(frameStderr, "2024-01-02T03:04:06Z not published yet\n")...)
+	fake := &fakeClient{output: output}
+	config := ContainerConfig{Streams: []string{streamStderr}, LogGroupName: "docker", LogStreamName: "{container_id}"}
+	var src *logsource.Src
+	w := newContainerWatcher(config, fake, t.TempDir(), testutil.Logger{}, func(s *logsource.Src) { src = s })
+	require.NoError(t, os.WriteFile(w.stateFilePath(testContainerID), []byte("2024-01-02T03:04:05Z"), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- w.tail(ctx, testContainerID, true)
+	}()
+	var e logs.LogEvent
+	assert.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return src != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "4f66ad9a0b2e", src.Stream())
+	events := make(chan logs.LogEvent, 1)
+	src.SetOutput(func(e logs.LogEvent) {
+		if e != nil {
+			events <- e
+		}
+	})
+	select {
+	case e = <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event published")
+	}
+	assert.Equal(t, "not published yet", e.Message())
+	cancel()
+	<-done
+
+	_, logsOptions := fake.calls()
+	require.Len(t, logsOptions, 1)
+	assert.Equal(t, container.LogsOptions{ShowStderr: true, Follow: true, Timestamps: true, Since: "2024-01-02T03:04:05Z"}, logsOptions[0])
+}
+
+func TestStartInvalidConfig(t *testing.T) {
+	p := &Plugin{Log: testutil.Logger{}}
+	assert.Error(t, p.Start(nil))
+	p.Containers = []ContainerConfig{{LogGroupName: "docker"}}
+	assert.Error(t, p.Start(nil))
+	p.FileStateFolder = t.TempDir()
+	p.Containers = []ContainerConfig{{}}
+	assert.Error(t, p.Start(nil))
+	p.Containers = []ContainerConfig{{LogGroupName: "docker", Streams: []string{"stdin"}}}
+	assert.Error(t, p.Start(nil))
+}
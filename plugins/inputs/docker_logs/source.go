@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker_logs
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	containerNamePlaceholder = "{container_name}"
+	containerIDPlaceholder   = "{container_id}"
+	imagePlaceholder         = "{image}"
+
+	// shortIDLength is the length of the container IDs shown by docker ps.
+	shortIDLength = 12
+)
+
+// labelPlaceholder matches the {label:<key>} placeholders.
+var labelPlaceholder = regexp.MustCompile(`\{label:([^}]+)\}`)
+
+// containerInfo is the container metadata available to the log group and
+// stream name templates.
+type containerInfo struct {
+	id     string
+	name   string
+	image  string
+	labels map[string]string
+	tty    bool
+}
+
+func (c containerInfo) shortID() string {
+	if len(c.id) > shortIDLength {
+		return c.id[:shortIDLength]
+	}
+	return c.id
+}
+
+// resolveTemplate replaces the container placeholders in the log group or
+// stream name. A missing label is replaced by an empty string, and the
+// characters that are not allowed in the names, e.g. the colon of the image
+// tags, are replaced.
+func resolveTemplate(template string, c containerInfo) string {
+	escape := strings.NewReplacer(":", "_", "*", "_").Replace
+	resolved := strings.NewReplacer(
+		containerNamePlaceholder, escape(c.name),
+		containerIDPlaceholder, c.shortID(),
+		imagePlaceholder, escape(c.image),
+	).Replace(template)
+	return labelPlaceholder.ReplaceAllStringFunc(resolved, func(match string) string {
+		key := labelPlaceholder.FindStringSubmatch(match)[1]
+		return escape(c.labels[key])
+	})
+}
+
+type sourceKey struct {
+	group  string
+	stream string
+}
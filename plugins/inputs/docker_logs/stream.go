@@ -0,0 +1,125 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker_logs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	streamStdout = "stdout"
+	streamStderr = "stderr"
+
+	// The stream types in the headers of the multiplexed frames.
+	frameStdout    = 1
+	frameStderr    = 2
+	frameSystemErr = 3
+
+	frameHeaderSize = 8
+	// maxLineSize caps the lines joined from partial frames to the maximum
+	// size of a CloudWatch Logs event.
+	maxLineSize = 256 * 1024
+)
+
+// logLine is a line of the output of a container.
+type logLine struct {
+	stream    string
+	timestamp time.Time
+	message   string
+}
+
+// readLines reads the lines of the log stream of a container, requested with
+// timestamps, until the stream ends or fn returns false. The output of the
+// containers without a TTY is multiplexed in frames, each holding a line or
+// the part of a line that the logging driver split.
+func readLines(r io.Reader, tty bool, fn func(logLine) bool) error {
+	if tty {
+		return readRawLines(r, fn)
+	}
+	return readFrames(r, fn)
+}
+
+func readRawLines(r io.Reader, fn func(logLine) bool) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 && !fn(parseLine(streamStdout, line)) {
+			return nil
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func readFrames(r io.Reader, fn func(logLine) bool) error {
+	header := make([]byte, frameHeaderSize)
+	// partial holds the lines of each stream that are not complete yet.
+	partial := map[string]*logLine{}
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				// the stream ends when the container stops
+				for _, stream := range []string{streamStdout, streamStderr} {
+					if p, ok := partial[stream]; ok && !fn(*p) {
+						return nil
+					}
+				}
+				return nil
+			}
+			return err
+		}
+		payload := make([]byte, binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return err
+		}
+		var stream string
+		switch header[0] {
+		case frameStdout:
+			stream = streamStdout
+		case frameStderr:
+			stream = streamStderr
+		case frameSystemErr:
+			return fmt.Errorf("log stream error: %s", bytes.TrimSpace(payload))
+		default:
+			continue
+		}
+		line := parseLine(stream, payload)
+		complete := bytes.HasSuffix(payload, []byte{'\n'})
+		if p, ok := partial[stream]; ok {
+			// the first part of the line has its timestamp
+			p.message += line.message
+			line = *p
+			delete(partial, stream)
+		}
+		if !complete && len(line.message) < maxLineSize {
+			partial[stream] = &line
+			continue
+		}
+		if !fn(line) {
+			return nil
+		}
+	}
+}
+
+// parseLine splits the timestamp added to the line by the daemon from the
+// message. The current time is used if the line has no timestamp.
+func parseLine(stream string, b []byte) logLine {
+	b = bytes.TrimRight(b, "\r\n")
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		if timestamp, err := time.Parse(time.RFC3339Nano, string(b[:i])); err == nil {
+			return logLine{stream: stream, timestamp: timestamp, message: string(b[i+1:])}
+		}
+	}
+	return logLine{stream: stream, timestamp: time.Now(), message: string(b)}
+}
@@ -0,0 +1,90 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker_logs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// frame returns a multiplexed frame of the stream type with the payload.
+func frame(streamType byte, payload string) []byte {
+	header := make([]byte, frameHeaderSize)
+	header[0] = streamType
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, payload...)
+}
+
+func collectLines(t *testing.T, input []byte, tty bool) ([]logLine, error) {
+	t.Helper()
+	var lines []logLine
+	err := readLines(bytes.NewReader(input), tty, func(line logLine) bool {
+		lines = append(lines, line)
+		return true
+	})
+	return lines, err
+}
+
+func TestReadFrames(t *testing.T) {
+	var input []byte
+	input = append(input, frame(frameStdout, "2024-01-02T03:04:05.000000001Z started\n")...)
+	input = append(input, frame(frameStderr, "2024-01-02T03:04:06Z first part ")...)
+	input = append(input, frame(frameStdout, "2024-01-02T03:04:07Z listening\n")...)
+	input = append(input, frame(frameStderr, "2024-01-02T03:04:08Z of the error\n")...)
+	input = append(input, frame(0, "stdin is skipped\n")...)
+	lines, err := collectLines(t, input, false)
+	require.NoError(t, err)
+	assert.Equal(t, []logLine{
+		{stream: streamStdout, timestamp: time.Date(2024, 1, 2, 3, 4, 5, 1, time.UTC), message: "started"},
+		{stream: streamStdout, timestamp: time.Date(2024, 1, 2, 3, 4, 7, 0, time.UTC), message: "listening"},
+		{stream: streamStderr, timestamp: time.Date(2024, 1, 2, 3, 4, 6, 0, time.UTC), message: "first part of the error"},
+	}, lines)
+
+	_, err = collectLines(t, frame(frameSystemErr, "container not found\n"), false)
+	assert.ErrorContains(t, err, "container not found")
+
+	_, err = collectLines(t, frame(frameStdout, "truncated")[:frameHeaderSize+3], false)
+	assert.Error(t, err)
+}
+
+func TestReadFramesMaxLineSize(t *testing.T) {
+	part := strings.Repeat("a", maxLineSize/2)
+	var input []byte
+	for i := 0; i < 3; i++ {
+		input = append(input, frame(frameStdout, "2024-01-02T03:04:05Z "+part)...)
+	}
+	lines, err := collectLines(t, input, false)
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	assert.Len(t, lines[0].message, maxLineSize)
+	assert.Len(t, lines[1].message, maxLineSize/2)
+}
+
+func TestReadRawLines(t *testing.T) {
+	lines, err := collectLines(t, []byte("2024-01-02T03:04:05Z $ ls\r\nno timestamp\n2024-01-02T03:04:06Z last"), true)
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	assert.Equal(t, logLine{stream: streamStdout, timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), message: "$ ls"}, lines[0])
+	assert.Equal(t, "no timestamp", lines[1].message)
+	assert.WithinDuration(t, time.Now(), lines[1].timestamp, time.Minute)
+	assert.Equal(t, "last", lines[2].message)
+}
+
+func TestResolveTemplate(t *testing.T) {
+	c := containerInfo{
+		id:     "4f66ad9a0b2e8c3f1e7d6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d",
+		name:   "payments-api",
+		image:  "public.ecr.aws/nginx/nginx:1.25",
+		labels: map[string]string{"com.example.service": "payments"},
+	}
+	assert.Equal(t, "/docker/payments/payments-api", resolveTemplate("/docker/{label:com.example.service}/{container_name}", c))
+	assert.Equal(t, "public.ecr.aws/nginx/nginx_1.25/4f66ad9a0b2e", resolveTemplate("{image}/{container_id}", c))
+	assert.Equal(t, "team-", resolveTemplate("team-{label:com.example.team}", c))
+}
@@ -0,0 +1,289 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker_logs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/influxdata/telegraf"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logscommon"
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+)
+
+const (
+	stateRunning      = "running"
+	saveStateInterval = 100 * time.Millisecond
+)
+
+// discoveryInterval is how often the containers are listed. Overridden in
+// tests.
+var discoveryInterval = 10 * time.Second
+
+// tailState is the timestamp of the last published line of a container.
+type tailState struct {
+	timestamp time.Time
+	changed   bool
+}
+
+// containerWatcher discovers the running containers matching the label
+// filters of a container config, follows their output and routes the lines
+// to the sources of their log group and stream.
+type containerWatcher struct {
+	config      ContainerConfig
+	client      dockerClient
+	stateFolder string
+	log         telegraf.Logger
+	addSource   func(*logsource.Src)
+
+	// tailers and started are only accessed by the watcher goroutine.
+	tailers map[string]chan struct{}
+	started bool
+
+	mu      sync.Mutex
+	sources map[sourceKey]*logsource.Src
+	states  map[string]*tailState
+	wg      sync.WaitGroup
+}
+
+func newContainerWatcher(config ContainerConfig, client dockerClient, stateFolder string, log telegraf.Logger, addSource func(*logsource.Src)) *containerWatcher {
+	return &containerWatcher{
+		config:      config,
+		client:      client,
+		stateFolder: stateFolder,
+		log:         log,
+		addSource:   addSource,
+		tailers:     make(map[string]chan struct{}),
+		sources:     make(map[sourceKey]*logsource.Src),
+		states:      make(map[string]*tailState),
+	}
+}
+
+// run lists the containers until the context is done and waits for their
+// tailers to exit.
+func (w *containerWatcher) run(ctx context.Context) {
+	defer w.wg.Wait()
+	for {
+		if err := w.discover(ctx); err != nil && ctx.Err() == nil {
+			w.log.Errorf("Unable to list the containers with labels %v: %v", w.config.LabelFilters, err)
+		}
+		if !logsource.Sleep(ctx, discoveryInterval) {
+			return
+		}
+	}
+}
+
+// discover starts following the running containers that are not followed
+// yet. The state files of the containers that were removed are deleted.
+func (w *containerWatcher) discover(ctx context.Context) error {
+	args := filters.NewArgs()
+	for _, label := range w.config.LabelFilters {
+		args.Add("label", label)
+	}
+	containers, err := w.client.ContainerList(ctx, container.ListOptions{All: true, Filters: args})
+	if err != nil {
+		return err
+	}
+	// the output of the containers running when the agent starts is only
+	// collected from then on, unless it was collected before.
+	fromStart := w.started
+	w.started = true
+	existing := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		existing[c.ID] = true
+		if done, ok := w.tailers[c.ID]; ok {
+			select {
+			case <-done:
+				delete(w.tailers, c.ID)
+			default:
+				continue
+			}
+		}
+		if c.State != stateRunning {
+			continue
+		}
+		done := make(chan struct{})
+		w.tailers[c.ID] = done
+		w.wg.Add(1)
+		go func(id string) {
+			defer w.wg.Done()
+			defer close(done)
+			if err := w.tail(ctx, id, fromStart); err != nil && ctx.Err() == nil {
+				w.log.Errorf("Unable to read the logs of container %s: %v", id, err)
+			}
+		}(c.ID)
+	}
+	w.removeStates(existing)
+	return nil
+}
+
+// tail follows the output of the container from the timestamp of the last
+// published line until the container stops or the context is done.
+func (w *containerWatcher) tail(ctx context.Context, id string, fromStart bool) error {
+	inspect, err := w.client.ContainerInspect(ctx, id)
+	if err != nil {
+		return err
+	}
+	info := containerInfo{
+		id:   id,
+		name: strings.TrimPrefix(inspect.Name, "/"),
+	}
+	if inspect.Config != nil {
+		info.image = inspect.Config.Image
+		info.labels = inspect.Config.Labels
+		info.tty = inspect.Config.Tty
+	}
+	options := container.LogsOptions{
+		ShowStdout: w.config.collects(streamStdout),
+		ShowStderr: w.config.collects(streamStderr),
+		Follow:     true,
+		Timestamps: true,
+	}
+	since := w.loadState(id)
+	switch {
+	case !since.IsZero():
+		options.Since = since.Format(time.RFC3339Nano)
+	case !fromStart:
+		options.Tail = "0"
+	}
+	stream, err := w.client.ContainerLogs(ctx, id, options)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	w.log.Debugf("Reading the logs of container %s since %v", info.name, since)
+	return readLines(stream, info.tty, func(line logLine) bool {
+		// the lines at the saved timestamp were published already
+		if line.message == "" || !line.timestamp.After(since) {
+			return true
+		}
+		evt := logsource.NewEvent(line.message, line.timestamp, w.publishedFn(id, line.timestamp))
+		return w.source(info).Publish(evt, ctx.Done())
+	})
+}
+
+// source returns the source for the log group and stream of the container.
+func (w *containerWatcher) source(info containerInfo) *logsource.Src {
+	key := sourceKey{
+		group:  resolveTemplate(w.config.LogGroupName, info),
+		stream: resolveTemplate(w.config.LogStreamName, info),
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if src, ok := w.sources[key]; ok {
+		return src
+	}
+	src := logsource.New(key.group, key.stream, "docker:"+info.name, w.config.Destination, w.config.LogGroupClass, w.config.Retention)
+	w.sources[key] = src
+	w.addSource(src)
+	return src
+}
+
+// publishedFn returns the function recording the timestamp of a published
+// line of the container.
+func (w *containerWatcher) publishedFn(id string, timestamp time.Time) func() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	state, ok := w.states[id]
+	if !ok {
+		state = &tailState{}
+		w.states[id] = state
+	}
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if timestamp.After(state.timestamp) {
+			state.timestamp = timestamp
+			state.changed = true
+		}
+	}
+}
+
+// runSaveState saves the timestamps of the last published lines until the
+// context is done.
+func (w *containerWatcher) runSaveState(ctx context.Context) {
+	t := time.NewTicker(saveStateInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			w.saveStates()
+		case <-ctx.Done():
+			w.saveStates()
+			return
+		}
+	}
+}
+
+func (w *containerWatcher) saveStates() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for id, state := range w.states {
+		if !state.changed {
+			continue
+		}
+		path := w.stateFilePath(id)
+		if err := os.WriteFile(path, []byte(state.timestamp.Format(time.RFC3339Nano)), 0644); err != nil {
+			w.log.Errorf("Unable to save the log position of container %s to %s, duplicate logs may be sent: %v", id, path, err)
+			continue
+		}
+		state.changed = false
+	}
+}
+
+func (w *containerWatcher) loadState(id string) time.Time {
+	path := w.stateFilePath(id)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			w.log.Warnf("Unable to read the log position of container %s from %s: %v", id, path, err)
+		}
+		return time.Time{}
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(content)))
+	if err != nil {
+		w.log.Warnf("Invalid log position of container %s in %s: %v", id, path, err)
+		return time.Time{}
+	}
+	return timestamp
+}
+
+// removeStates deletes the state files of the containers of the config that
+// no longer exist.
+func (w *containerWatcher) removeStates(existing map[string]bool) {
+	prefix := w.stateFilePrefix()
+	entries, err := os.ReadDir(w.stateFolder)
+	if err != nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, entry := range entries {
+		id, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok || existing[id] {
+			continue
+		}
+		delete(w.states, id)
+		if err = os.Remove(filepath.Join(w.stateFolder, entry.Name())); err != nil {
+			w.log.Warnf("Unable to remove the log position of container %s: %v", id, err)
+		}
+	}
+}
+
+// stateFilePrefix returns a unique file name prefix for the container config.
+func (w *containerWatcher) stateFilePrefix() string {
+	return logscommon.DockerPrefix + logsource.EscapeFileName(w.config.LogGroupName+"_"+w.config.LogStreamName+"_"+strings.Join(w.config.LabelFilters, ",")) + "_"
+}
+
+func (w *containerWatcher) stateFilePath(id string) string {
+	return filepath.Join(w.stateFolder, w.stateFilePrefix()+id)
+}
@@ -0,0 +1,171 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package efa
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+const (
+	measurement = "efa"
+
+	defaultSysPath = "/sys"
+	// sysPathEnv is the sysfs mount of the host when the agent runs in a
+	// container, as for gopsutil.
+	sysPathEnv = "HOST_SYS"
+
+	efaDriver = "efa"
+
+	tagDevice = "device"
+	tagPort   = "port"
+)
+
+// Counters are the hardware counters collected, out of the ones of the EFA
+// driver. They are cumulative since the device was attached.
+var Counters = []string{
+	"rx_bytes",
+	"rx_pkts",
+	"rx_drops",
+	"tx_bytes",
+	"tx_pkts",
+	"rdma_read_bytes",
+	"rdma_write_bytes",
+	"rdma_write_recv_bytes",
+	"retrans_bytes",
+	"retrans_pkts",
+	"retrans_timeout_events",
+	"impaired_remote_conn_events",
+	"unresponsive_remote_events",
+}
+
+// EFA reports the hardware counters of the ports of the Elastic Fabric
+// Adapter devices, read from sysfs.
+type EFA struct {
+	SysPath       string          `toml:"sys_path"`
+	DeviceInclude []string        `toml:"device_include"`
+	Log           telegraf.Logger `toml:"-"`
+
+	devices filter.Filter
+	// warned is set once the missing devices were logged.
+	warned bool
+}
+
+var _ telegraf.Input = (*EFA)(nil)
+
+func (*EFA) SampleConfig() string {
+	return sampleConfig
+}
+
+func (*EFA) Description() string {
+	return "Collects the hardware counters of the Elastic Fabric Adapter devices"
+}
+
+func (e *EFA) Init() error {
+	if e.SysPath == "" {
+		e.SysPath = defaultSysPath
+		if hostSys := os.Getenv(sysPathEnv); hostSys != "" {
+			e.SysPath = hostSys
+		}
+	}
+	devices, err := filter.Compile(e.DeviceInclude)
+	if err != nil {
+		return fmt.Errorf("invalid device_include: %w", err)
+	}
+	e.devices = devices
+	return nil
+}
+
+func (e *EFA) Gather(acc telegraf.Accumulator) error {
+	classPath := filepath.Join(e.SysPath, "class", "infiniband")
+	entries, err := os.ReadDir(classPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		e.warnNoDevices()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, entry := range entries {
+		device := entry.Name()
+		if e.devices != nil && !e.devices.Match(device) {
+			continue
+		}
+		devicePath := filepath.Join(classPath, device)
+		if !isEFA(devicePath) {
+			continue
+		}
+		found = true
+		ports, err := os.ReadDir(filepath.Join(devicePath, "ports"))
+		if err != nil {
+			acc.AddError(fmt.Errorf("unable to list the ports of %s: %w", device, err))
+			continue
+		}
+		for _, port := range ports {
+			fields := readCounters(filepath.Join(devicePath, "ports", port.Name(), "hw_counters"))
+			if len(fields) == 0 {
+				continue
+			}
+			acc.AddCounter(measurement, fields, map[string]string{tagDevice: device, tagPort: port.Name()})
+		}
+	}
+	if !found {
+		e.warnNoDevices()
+	}
+	return nil
+}
+
+func (e *EFA) warnNoDevices() {
+	if !e.warned {
+		e.warned = true
+		e.Log.Warnf("No EFA device found in %s, the instance type may not support EFA or the driver is not loaded", filepath.Join(e.SysPath, "class", "infiniband"))
+	}
+}
+
+// isEFA returns true if the device is bound to the efa driver. The devices
+// without a driver link are assumed to be EFA.
+func isEFA(devicePath string) bool {
+	driver, err := filepath.EvalSymlinks(filepath.Join(devicePath, "device", "driver"))
+	if err != nil {
+		return true
+	}
+	return filepath.Base(driver) == efaDriver
+}
+
+func readCounters(path string) map[string]interface{} {
+	fields := make(map[string]interface{}, len(Counters))
+	for _, counter := range Counters {
+		content, err := os.ReadFile(filepath.Join(path, counter))
+		if err != nil {
+			// older drivers do not have all the counters
+			continue
+		}
+		value, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+		if err != nil {
+			continue
+		}
+		fields[counter] = value
+	}
+	return fields
+}
+
+func init() {
+	inputs.Add(measurement, func() telegraf.Input {
+		return &EFA{}
+	})
+}
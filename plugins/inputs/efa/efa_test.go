@@ -0,0 +1,78 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package efa
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSysfs creates the sysfs tree of an EFA device with the counters on port
+// 1, and of a Mellanox device.
+func newSysfs(t *testing.T) string {
+	t.Helper()
+	sys := t.TempDir()
+	for driver, device := range map[string]string{"efa": "rdmap0s6", "mlx5_core": "mlx5_0"} {
+		driverPath := filepath.Join(sys, "bus", "pci", "drivers", driver)
+		devicePath := filepath.Join(sys, "class", "infiniband", device)
+		countersPath := filepath.Join(devicePath, "ports", "1", "hw_counters")
+		require.NoError(t, os.MkdirAll(driverPath, 0755))
+		require.NoError(t, os.MkdirAll(filepath.Join(devicePath, "device"), 0755))
+		require.NoError(t, os.MkdirAll(countersPath, 0755))
+		require.NoError(t, os.Symlink(driverPath, filepath.Join(devicePath, "device", "driver")))
+		for name, value := range map[string]string{"rx_bytes": "1024\n", "tx_bytes": "2048\n", "rdma_read_bytes": "4096\n", "lifespan": "12\n", "rx_drops": "n/a\n"} {
+			require.NoError(t, os.WriteFile(filepath.Join(countersPath, name), []byte(value), 0644))
+		}
+	}
+	return sys
+}
+
+func TestGather(t *testing.T) {
+	e := &EFA{SysPath: newSysfs(t), Log: testutil.Logger{}}
+	require.NoError(t, e.Init())
+	var acc testutil.Accumulator
+	require.NoError(t, e.Gather(&acc))
+
+	require.Len(t, acc.Metrics, 1)
+	acc.AssertContainsTaggedFields(t, measurement, map[string]interface{}{
+		"rx_bytes":        uint64(1024),
+		"tx_bytes":        uint64(2048),
+		"rdma_read_bytes": uint64(4096),
+	}, map[string]string{tagDevice: "rdmap0s6", tagPort: "1"})
+	assert.False(t, e.warned)
+}
+
+func TestGatherWithDeviceInclude(t *testing.T) {
+	e := &EFA{SysPath: newSysfs(t), DeviceInclude: []string{"rdmap1*"}, Log: testutil.Logger{}}
+	require.NoError(t, e.Init())
+	var acc testutil.Accumulator
+	require.NoError(t, e.Gather(&acc))
+	assert.Empty(t, acc.Metrics)
+	assert.True(t, e.warned)
+}
+
+func TestGatherWithoutDevices(t *testing.T) {
+	e := &EFA{SysPath: t.TempDir(), Log: testutil.Logger{}}
+	require.NoError(t, e.Init())
+	var acc testutil.Accumulator
+	require.NoError(t, e.Gather(&acc))
+	assert.Empty(t, acc.Metrics)
+	assert.Empty(t, acc.Errors)
+	assert.True(t, e.warned)
+}
+
+func TestInit(t *testing.T) {
+	t.Setenv(sysPathEnv, "/rootfs/sys")
+	e := &EFA{}
+	require.NoError(t, e.Init())
+	assert.Equal(t, "/rootfs/sys", e.SysPath)
+
+	e = &EFA{DeviceInclude: []string{"["}}
+	assert.Error(t, e.Init())
+}
@@ -0,0 +1,318 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent_forward
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/tinylib/msgp/msgp"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+const (
+	defaultServiceAddress = "tcp://127.0.0.1:24224"
+	defaultLogGroupName   = "fluent_forward/default"
+
+	networkTCP  = "tcp"
+	networkUnix = "unix"
+
+	handshakeTimeout = 10 * time.Second
+)
+
+type Plugin struct {
+	ServiceAddress string `toml:"service_address"`
+	SharedKey      string `toml:"shared_key"`
+	LogGroupName   string `toml:"log_group_name"`
+	LogStreamName  string `toml:"log_stream_name"`
+	LogGroupClass  string `toml:"log_group_class"`
+	Retention      int    `toml:"retention_in_days"`
+	Destination    string `toml:"destination"`
+	MessageKey     string `toml:"message_key"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	groupTemplate  *logsource.Template
+	streamTemplate *logsource.Template
+	hostname       string
+
+	mu         sync.Mutex
+	src        *logsource.Src
+	newSources []logs.LogSrc
+	listener   net.Listener
+	conns      map[net.Conn]struct{}
+	closed     bool
+	wg         sync.WaitGroup
+}
+
+var _ logs.LogCollection = (*Plugin)(nil)
+
+func (p *Plugin) Description() string {
+	return "A plugin to receive log events over the Fluent Forward protocol"
+}
+
+func (p *Plugin) SampleConfig() string {
+	return `
+	service_address = "tcp://127.0.0.1:24224"
+	log_group_name = "/fluent/{tag[0]}"
+	log_stream_name = "{tag}"
+	destination = "cloudwatchlogs"
+	`
+}
+
+func (p *Plugin) Gather(acc telegraf.Accumulator) error {
+	return nil
+}
+
+func (p *Plugin) FindLogSrc() []logs.LogSrc {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	srcs := p.newSources
+	p.newSources = nil
+	return srcs
+}
+
+// Start listens for the forward connections. The listener is created before
+// returning so that an address already in use is reported right away.
+func (p *Plugin) Start(acc telegraf.Accumulator) error {
+	if p.ServiceAddress == "" {
+		p.ServiceAddress = defaultServiceAddress
+	}
+	network, address, err := parseServiceAddress(p.ServiceAddress)
+	if err != nil {
+		return err
+	}
+	if p.hostname, err = os.Hostname(); err != nil {
+		return fmt.Errorf("unable to get the hostname: %w", err)
+	}
+	p.initSource()
+
+	if network == networkUnix {
+		// remove the socket left behind by a previous run
+		if info, err := os.Stat(address); err == nil && info.Mode()&os.ModeSocket != 0 {
+			_ = os.Remove(address)
+		}
+	}
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return fmt.Errorf("unable to listen on %s: %w", p.ServiceAddress, err)
+	}
+	p.mu.Lock()
+	p.listener = listener
+	p.conns = map[net.Conn]struct{}{}
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.accept(listener)
+	}()
+	return nil
+}
+
+func (p *Plugin) Stop() {
+	p.mu.Lock()
+	p.closed = true
+	if p.listener != nil {
+		_ = p.listener.Close()
+	}
+	for conn := range p.conns {
+		_ = conn.Close()
+	}
+	p.mu.Unlock()
+	// unblock the connections waiting on the output before waiting for them
+	if p.src != nil {
+		p.src.Stop()
+	}
+	p.wg.Wait()
+}
+
+// parseServiceAddress splits the address into the network and the address on
+// that network, e.g. tcp://127.0.0.1:24224 or unix:///var/run/fluent.sock. The
+// addresses without a scheme are TCP addresses.
+func parseServiceAddress(serviceAddress string) (string, string, error) {
+	scheme, address, found := strings.Cut(serviceAddress, "://")
+	if !found {
+		return networkTCP, serviceAddress, nil
+	}
+	switch scheme {
+	case networkTCP, networkUnix:
+		return scheme, address, nil
+	default:
+		return "", "", fmt.Errorf("unsupported service address %q, the scheme must be tcp or unix", serviceAddress)
+	}
+}
+
+// initSource creates the single log source for the plugin. The log group and
+// stream of the source are the names without the tag references.
+func (p *Plugin) initSource() {
+	var group, stream string
+	p.groupTemplate, group = logsource.NewGroupTemplate(p.LogGroupName, tagReferenceRegexp)
+	p.streamTemplate, stream = logsource.NewStreamTemplate(p.LogStreamName, tagReferenceRegexp)
+	if group == "" {
+		group = defaultLogGroupName
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// the log group and stream of the source are used by the events whose tag cannot be resolved
+	p.src = logsource.New(group, stream, "fluent_forward:"+p.ServiceAddress, p.Destination, p.LogGroupClass, p.Retention)
+	p.newSources = append(p.newSources, p.src)
+}
+
+func (p *Plugin) accept(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				p.Log.Errorf("Stopped receiving forward connections on %s: %v", p.ServiceAddress, err)
+			}
+			return
+		}
+		if !p.track(conn) {
+			_ = conn.Close()
+			return
+		}
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			defer p.untrack(conn)
+			p.handle(conn)
+		}()
+	}
+}
+
+func (p *Plugin) track(conn net.Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return false
+	}
+	p.conns[conn] = struct{}{}
+	return true
+}
+
+func (p *Plugin) untrack(conn net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_ = conn.Close()
+	delete(p.conns, conn)
+}
+
+// handle reads the messages of the connection until it is closed. The chunks
+// are only acknowledged once all their entries have been handed to the
+// output, so that the clients retry rather than the entries being lost.
+func (p *Plugin) handle(conn net.Conn) {
+	r := msgp.NewReader(conn)
+	w := msgp.NewWriter(conn)
+	if p.SharedKey != "" {
+		if err := p.authenticate(conn, r, w); err != nil {
+			p.Log.Warnf("Unable to authenticate the forward connection from %s: %v", conn.RemoteAddr(), err)
+			return
+		}
+	}
+	for {
+		m, err := readMessage(r)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				p.Log.Warnf("Unable to read the forward message from %s: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		if !p.publish(m) {
+			return
+		}
+		if m.chunk != "" {
+			if err = writeAck(w, m.chunk); err != nil {
+				p.Log.Debugf("Unable to acknowledge the chunk %s: %v", m.chunk, err)
+				return
+			}
+		}
+	}
+}
+
+// authenticate runs the handshake with the shared key. The client must
+// answer the HELO with its PING before the timeout.
+func (p *Plugin) authenticate(conn net.Conn, r *msgp.Reader, w *msgp.Writer) error {
+	h, err := newHandshake(p.hostname, p.SharedKey)
+	if err != nil {
+		return err
+	}
+	if err = conn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return err
+	}
+	if err = h.writeHelo(w); err != nil {
+		return err
+	}
+	pi, err := readPing(r)
+	if err != nil {
+		return err
+	}
+	authErr := h.verify(pi)
+	if err = h.writePong(w, pi.salt, authErr); err != nil {
+		return err
+	}
+	if authErr != nil {
+		return authErr
+	}
+	return conn.SetDeadline(time.Time{})
+}
+
+// publish routes each entry to the log group and stream resolved from the tag.
+// Returns false if any entry was not handed over.
+func (p *Plugin) publish(m *message) bool {
+	var group, stream string
+	if p.groupTemplate != nil {
+		group = resolveTag(p.groupTemplate, m.tag)
+	}
+	if p.streamTemplate != nil {
+		stream = resolveTag(p.streamTemplate, m.tag)
+	}
+	for _, e := range m.entries {
+		msg, err := p.recordMessage(e.record)
+		if err != nil {
+			p.Log.Debugf("Unable to convert the record: %v", err)
+			continue
+		}
+		// blocks until the event is handed over or the source is stopped
+		if !p.src.Publish(&logEvent{
+			message:   msg,
+			timestamp: e.timestamp,
+			group:     group,
+			stream:    stream,
+		}, nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// recordMessage is the value of the message key if it is a string, otherwise
+// the whole record encoded as JSON.
+func (p *Plugin) recordMessage(record map[string]any) (string, error) {
+	if p.MessageKey != "" {
+		if msg, ok := record[p.MessageKey].(string); ok {
+			return msg, nil
+		}
+	}
+	content, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+func init() {
+	inputs.Add("fluent_forward", func() telegraf.Input { return &Plugin{} })
+}
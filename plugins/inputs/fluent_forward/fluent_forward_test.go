@@ -0,0 +1,206 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent_forward
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tinylib/msgp/msgp"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+func freeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func startPlugin(t *testing.T, p *Plugin) chan logs.LogEvent {
+	p.Log = testutil.Logger{}
+	require.NoError(t, p.Start(nil))
+	t.Cleanup(p.Stop)
+	srcs := p.FindLogSrc()
+	require.Len(t, srcs, 1)
+	events := make(chan logs.LogEvent, 10)
+	srcs[0].SetOutput(func(e logs.LogEvent) {
+		if e != nil {
+			events <- e
+		}
+	})
+	return events
+}
+
+// writeMessage writes a Message mode message with the chunk option.
+func writeMessage(t *testing.T, w *msgp.Writer, tag string, record map[string]any, chunk string) {
+	require.NoError(t, w.WriteArrayHeader(4))
+	require.NoError(t, w.WriteString(tag))
+	require.NoError(t, w.WriteExtension(&eventTime{testTime}))
+	require.NoError(t, w.WriteMapStrIntf(record))
+	require.NoError(t, w.WriteMapStrIntf(map[string]any{"chunk": chunk}))
+	require.NoError(t, w.Flush())
+}
+
+func readAck(t *testing.T, r *msgp.Reader) string {
+	ack := map[string]any{}
+	require.NoError(t, r.ReadMapStrIntf(ack))
+	return ack["ack"].(string)
+}
+
+func TestPlugin(t *testing.T) {
+	p := &Plugin{
+		ServiceAddress: "tcp://" + freeAddress(t),
+		LogGroupName:   "/fluent/{tag[0]}",
+		LogStreamName:  "{tag}",
+		Destination:    "cloudwatchlogs",
+		MessageKey:     "log",
+		Retention:      7,
+	}
+	events := startPlugin(t, p)
+	src := p.src
+	assert.Equal(t, "/fluent/", src.Group())
+	assert.Equal(t, "", src.Stream())
+	assert.Equal(t, "cloudwatchlogs", src.Destination())
+	assert.Equal(t, 7, src.Retention())
+	assert.Empty(t, p.FindLogSrc())
+
+	conn, err := net.Dial("tcp", p.ServiceAddress[len("tcp://"):])
+	require.NoError(t, err)
+	defer conn.Close()
+	r, w := msgp.NewReader(conn), msgp.NewWriter(conn)
+
+	writeMessage(t, w, "app.web", map[string]any{"log": "GET /", "stream": "stdout"}, "chunk-1")
+	assert.Equal(t, "chunk-1", readAck(t, r))
+	e := (<-events).(logs.RoutedLogEvent)
+	assert.Equal(t, "GET /", e.Message())
+	assert.Equal(t, "/fluent/app", e.Group())
+	assert.Equal(t, "app.web", e.Stream())
+	assert.True(t, testTime.Equal(e.Time()))
+
+	// the records without the message key are encoded as JSON
+	writeMessage(t, w, "", map[string]any{"level": "info", "count": 2}, "chunk-2")
+	assert.Equal(t, "chunk-2", readAck(t, r))
+	e = (<-events).(logs.RoutedLogEvent)
+	assert.JSONEq(t, `{"level":"info","count":2}`, e.Message())
+	assert.Equal(t, "", e.Group())
+	assert.Equal(t, "", e.Stream())
+}
+
+func TestPluginUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fluent.sock")
+	events := startPlugin(t, &Plugin{ServiceAddress: "unix://" + path})
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	defer conn.Close()
+	w := msgp.NewWriter(conn)
+	require.NoError(t, w.WriteArrayHeader(2))
+	require.NoError(t, w.WriteString("app"))
+	require.NoError(t, w.WriteArrayHeader(1))
+	require.NoError(t, writeEntry(w, int64(1700000000), map[string]any{"log": "hello"}))
+	require.NoError(t, w.Flush())
+
+	e := <-events
+	assert.Equal(t, `{"log":"hello"}`, e.Message())
+	assert.Equal(t, time.Unix(1700000000, 0), e.Time())
+}
+
+func TestPluginSharedKey(t *testing.T) {
+	testCases := map[string]struct {
+		sharedKey string
+		wantAuth  bool
+	}{
+		"WithSharedKey":      {sharedKey: "secret", wantAuth: true},
+		"WithWrongSharedKey": {sharedKey: "wrong"},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			p := &Plugin{ServiceAddress: freeAddress(t), SharedKey: "secret"}
+			events := startPlugin(t, p)
+
+			conn, err := net.Dial("tcp", p.ServiceAddress)
+			require.NoError(t, err)
+			defer conn.Close()
+			r, w := msgp.NewReader(conn), msgp.NewWriter(conn)
+
+			// HELO
+			n, err := r.ReadArrayHeader()
+			require.NoError(t, err)
+			require.EqualValues(t, 2, n)
+			kind, err := r.ReadString()
+			require.NoError(t, err)
+			assert.Equal(t, messageHelo, kind)
+			options := map[string]any{}
+			require.NoError(t, r.ReadMapStrIntf(options))
+			client := &handshake{hostname: "client", sharedKey: testCase.sharedKey, nonce: options["nonce"].(string)}
+
+			// PING
+			require.NoError(t, w.WriteArrayHeader(6))
+			for _, field := range []string{messagePing, "client", "salt", client.digest("salt", "client"), "", ""} {
+				require.NoError(t, w.WriteString(field))
+			}
+			require.NoError(t, w.Flush())
+
+			// PONG
+			n, err = r.ReadArrayHeader()
+			require.NoError(t, err)
+			require.EqualValues(t, 5, n)
+			kind, err = r.ReadString()
+			require.NoError(t, err)
+			assert.Equal(t, messagePong, kind)
+			authenticated, err := r.ReadBool()
+			require.NoError(t, err)
+			assert.Equal(t, testCase.wantAuth, authenticated)
+			reason, err := r.ReadString()
+			require.NoError(t, err)
+			hostname, err := r.ReadString()
+			require.NoError(t, err)
+			digest, err := r.ReadString()
+			require.NoError(t, err)
+			if !testCase.wantAuth {
+				assert.Equal(t, errInvalidSharedKey.Error(), reason)
+				_, err = r.ReadArrayHeader()
+				assert.Error(t, err)
+				return
+			}
+			assert.Equal(t, "", reason)
+			// the client verifies the server with its own shared key
+			assert.Equal(t, client.digest("salt", hostname), digest)
+
+			writeMessage(t, w, "app", map[string]any{"log": "hello"}, "chunk")
+			assert.Equal(t, "chunk", readAck(t, r))
+			assert.Equal(t, `{"log":"hello"}`, (<-events).Message())
+		})
+	}
+}
+
+func TestParseServiceAddress(t *testing.T) {
+	network, address, err := parseServiceAddress("127.0.0.1:24224")
+	assert.NoError(t, err)
+	assert.Equal(t, networkTCP, network)
+	assert.Equal(t, "127.0.0.1:24224", address)
+
+	network, address, err = parseServiceAddress("unix:///var/run/fluent.sock")
+	assert.NoError(t, err)
+	assert.Equal(t, networkUnix, network)
+	assert.Equal(t, "/var/run/fluent.sock", address)
+
+	_, _, err = parseServiceAddress("udp://127.0.0.1:24224")
+	assert.Error(t, err)
+}
+
+func TestPublishStoppedSource(t *testing.T) {
+	p := &Plugin{LogGroupName: "{tag}", Log: testutil.Logger{}}
+	p.initSource()
+	p.src.Stop()
+	assert.False(t, p.publish(&message{tag: "app", entries: []entry{{timestamp: testTime, record: map[string]any{}}}}))
+	assert.Equal(t, defaultLogGroupName, p.src.Group())
+}
@@ -0,0 +1,465 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent_forward
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+// The Fluent Forward protocol v1, see https://github.com/fluent/fluentd/wiki/Forward-Protocol-Specification-v1
+const (
+	// eventTimeExtension is the msgpack extension type of the EventTime, the seconds and
+	// nanoseconds since the epoch as two big endian uint32.
+	eventTimeExtension = 0
+	eventTimeLength    = 8
+
+	optionChunk      = "chunk"
+	optionCompressed = "compressed"
+	optionAck        = "ack"
+	compressedGzip   = "gzip"
+
+	messageHelo = "HELO"
+	messagePing = "PING"
+	messagePong = "PONG"
+
+	// maxChunkSize is the maximum size of the entries of a PackedForward
+	// message, once uncompressed.
+	maxChunkSize = 32 * 1024 * 1024
+)
+
+var (
+	errInvalidMessage   = errors.New("invalid forward message")
+	errChunkTooLarge    = fmt.Errorf("chunk larger than %d bytes", maxChunkSize)
+	errInvalidPing      = errors.New("invalid PING message")
+	errSameHostname     = errors.New("the client has the same hostname as the server")
+	errInvalidSharedKey = errors.New("shared key mismatch")
+)
+
+type eventTime struct {
+	time.Time
+}
+
+var _ msgp.Extension = (*eventTime)(nil)
+
+func (t *eventTime) ExtensionType() int8 {
+	return eventTimeExtension
+}
+
+func (t *eventTime) Len() int {
+	return eventTimeLength
+}
+
+func (t *eventTime) MarshalBinaryTo(b []byte) error {
+	binary.BigEndian.PutUint32(b, uint32(t.Unix()))
+	binary.BigEndian.PutUint32(b[4:], uint32(t.Nanosecond()))
+	return nil
+}
+
+func (t *eventTime) UnmarshalBinary(b []byte) error {
+	if len(b) != eventTimeLength {
+		return fmt.Errorf("invalid EventTime length %d", len(b))
+	}
+	t.Time = time.Unix(int64(binary.BigEndian.Uint32(b)), int64(binary.BigEndian.Uint32(b[4:])))
+	return nil
+}
+
+type entry struct {
+	timestamp time.Time
+	record    map[string]any
+}
+
+// message is a Message, Forward, PackedForward or CompressedPackedForward
+// message, i.e. the entries of a tag.
+type message struct {
+	tag     string
+	entries []entry
+	// chunk is the ID to acknowledge once the entries have been handled.
+	chunk string
+}
+
+// readMessage reads the next message of the connection.
+//
+//	Message:         [tag, time, record, option?]
+//	Forward:         [tag, [[time, record], ...], option?]
+//	PackedForward:   [tag, packed entries, option?]
+func readMessage(r *msgp.Reader) (*message, error) {
+	n, err := r.ReadArrayHeader()
+	if err != nil {
+		return nil, err
+	}
+	if n < 2 || n > 4 {
+		return nil, fmt.Errorf("%w: %d elements", errInvalidMessage, n)
+	}
+	tag, err := readString(r)
+	if err != nil {
+		return nil, err
+	}
+	m := &message{tag: tag}
+	t, err := r.NextType()
+	if err != nil {
+		return nil, err
+	}
+	var packed []byte
+	remaining := n - 2
+	switch t {
+	case msgp.ArrayType:
+		count, err := r.ReadArrayHeader()
+		if err != nil {
+			return nil, err
+		}
+		for i := uint32(0); i < count; i++ {
+			e, err := readEntry(r)
+			if err != nil {
+				return nil, err
+			}
+			m.entries = append(m.entries, e)
+		}
+	case msgp.StrType, msgp.BinType:
+		if packed, err = readBlob(r); err != nil {
+			return nil, err
+		}
+	default:
+		if remaining == 0 {
+			return nil, fmt.Errorf("%w: missing record", errInvalidMessage)
+		}
+		e := entry{}
+		if e.timestamp, err = readTime(r); err != nil {
+			return nil, err
+		}
+		if e.record, err = readRecord(r); err != nil {
+			return nil, err
+		}
+		m.entries = append(m.entries, e)
+		remaining--
+	}
+
+	option := map[string]any{}
+	if remaining > 0 {
+		if t, err := r.NextType(); err != nil {
+			return nil, err
+		} else if t == msgp.NilType {
+			err = r.ReadNil()
+		} else {
+			err = r.ReadMapStrIntf(option)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	m.chunk = optionString(option, optionChunk)
+
+	if packed != nil {
+		if optionString(option, optionCompressed) == compressedGzip {
+			if packed, err = gunzip(packed); err != nil {
+				return nil, err
+			}
+		}
+		if m.entries, err = readPackedEntries(packed); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func readPackedEntries(packed []byte) ([]entry, error) {
+	r := msgp.NewReader(bytes.NewReader(packed))
+	var entries []entry
+	for {
+		if _, err := r.NextType(); errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		e, err := readEntry(r)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+}
+
+// gunzip decompresses the concatenated gzip members.
+func gunzip(compressed []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	uncompressed, err := io.ReadAll(io.LimitReader(zr, maxChunkSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(uncompressed) > maxChunkSize {
+		return nil, errChunkTooLarge
+	}
+	return uncompressed, nil
+}
+
+func readEntry(r *msgp.Reader) (entry, error) {
+	var e entry
+	n, err := r.ReadArrayHeader()
+	if err != nil {
+		return e, err
+	}
+	if n != 2 {
+		return e, fmt.Errorf("%w: entry with %d elements", errInvalidMessage, n)
+	}
+	if e.timestamp, err = readTime(r); err != nil {
+		return e, err
+	}
+	e.record, err = readRecord(r)
+	return e, err
+}
+
+// readTime reads an EventTime or the seconds since the epoch. The current time
+// is used if the time is missing.
+func readTime(r *msgp.Reader) (time.Time, error) {
+	t, err := r.NextType()
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch t {
+	case msgp.ExtensionType:
+		var et eventTime
+		if err = r.ReadExtension(&et); err != nil {
+			return time.Time{}, err
+		}
+		return et.Time, nil
+	case msgp.IntType:
+		seconds, err := r.ReadInt64()
+		if err != nil || seconds <= 0 {
+			return time.Now(), err
+		}
+		return time.Unix(seconds, 0), nil
+	case msgp.UintType:
+		seconds, err := r.ReadUint64()
+		if err != nil || seconds == 0 {
+			return time.Now(), err
+		}
+		return time.Unix(int64(seconds), 0), nil
+	case msgp.Float64Type, msgp.Float32Type:
+		seconds, err := r.ReadFloat64()
+		if err != nil || seconds <= 0 {
+			return time.Now(), err
+		}
+		whole, fraction := math.Modf(seconds)
+		return time.Unix(int64(whole), int64(fraction*float64(time.Second))), nil
+	case msgp.NilType:
+		return time.Now(), r.ReadNil()
+	default:
+		return time.Time{}, fmt.Errorf("%w: unsupported time type %s", errInvalidMessage, t)
+	}
+}
+
+func readRecord(r *msgp.Reader) (map[string]any, error) {
+	record := map[string]any{}
+	if err := r.ReadMapStrIntf(record); err != nil {
+		return nil, err
+	}
+	return normalizeMap(record), nil
+}
+
+// normalizeMap converts the binary values, which fluent-bit uses for some
+// strings, to strings so that they are not base64 encoded in the JSON message.
+func normalizeMap(m map[string]any) map[string]any {
+	for k, v := range m {
+		m[k] = normalize(v)
+	}
+	return m
+}
+
+func normalize(v any) any {
+	switch value := v.(type) {
+	case []byte:
+		return string(value)
+	case map[string]any:
+		return normalizeMap(value)
+	case []any:
+		for i := range value {
+			value[i] = normalize(value[i])
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+// readString reads a string, which may be encoded as binary.
+func readString(r *msgp.Reader) (string, error) {
+	t, err := r.NextType()
+	if err != nil {
+		return "", err
+	}
+	if t == msgp.BinType {
+		b, err := r.ReadBytes(nil)
+		return string(b), err
+	}
+	return r.ReadString()
+}
+
+// readBlob reads the binary or string value with a size limit.
+func readBlob(r *msgp.Reader) ([]byte, error) {
+	t, err := r.NextType()
+	if err != nil {
+		return nil, err
+	}
+	var size uint32
+	if t == msgp.BinType {
+		size, err = r.ReadBytesHeader()
+	} else {
+		size, err = r.ReadStringHeader()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if size > maxChunkSize {
+		return nil, errChunkTooLarge
+	}
+	blob := make([]byte, size)
+	if _, err = r.ReadFull(blob); err != nil {
+		return nil, err
+	}
+	return blob, nil
+}
+
+func optionString(option map[string]any, key string) string {
+	switch value := option[key].(type) {
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	default:
+		return ""
+	}
+}
+
+func writeAck(w *msgp.Writer, chunk string) error {
+	if err := w.WriteMapHeader(1); err != nil {
+		return err
+	}
+	if err := w.WriteString(optionAck); err != nil {
+		return err
+	}
+	if err := w.WriteString(chunk); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// handshake authenticates the clients with the shared key. The user
+// authentication is not supported.
+type handshake struct {
+	hostname  string
+	sharedKey string
+	nonce     string
+}
+
+func newHandshake(hostname, sharedKey string) (*handshake, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return &handshake{hostname: hostname, sharedKey: sharedKey, nonce: hex.EncodeToString(nonce)}, nil
+}
+
+// digest is the hex encoded SHA-512 of the salt, hostname, nonce and shared key.
+func (h *handshake) digest(salt, hostname string) string {
+	sum := sha512.Sum512([]byte(salt + hostname + h.nonce + h.sharedKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeHelo writes ["HELO", {"nonce": nonce, "auth": "", "keepalive": true}].
+func (h *handshake) writeHelo(w *msgp.Writer) error {
+	if err := w.WriteArrayHeader(2); err != nil {
+		return err
+	}
+	if err := w.WriteString(messageHelo); err != nil {
+		return err
+	}
+	if err := w.WriteMapHeader(3); err != nil {
+		return err
+	}
+	for _, err := range []error{
+		w.WriteString("nonce"), w.WriteString(h.nonce),
+		w.WriteString("auth"), w.WriteString(""),
+		w.WriteString("keepalive"), w.WriteBool(true),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// ping is the ["PING", hostname, salt, digest, username, password] message of the client.
+type ping struct {
+	hostname string
+	salt     string
+	digest   string
+}
+
+func readPing(r *msgp.Reader) (*ping, error) {
+	n, err := r.ReadArrayHeader()
+	if err != nil {
+		return nil, err
+	}
+	if n != 6 {
+		return nil, fmt.Errorf("%w: %d elements", errInvalidPing, n)
+	}
+	fields := make([]string, n)
+	for i := range fields {
+		if fields[i], err = readString(r); err != nil {
+			return nil, err
+		}
+	}
+	if fields[0] != messagePing {
+		return nil, fmt.Errorf("%w: %q", errInvalidPing, fields[0])
+	}
+	return &ping{hostname: fields[1], salt: fields[2], digest: fields[3]}, nil
+}
+
+// verify returns an error if the client is not authenticated by the PING.
+func (h *handshake) verify(p *ping) error {
+	if p.hostname == h.hostname {
+		return errSameHostname
+	}
+	if subtle.ConstantTimeCompare([]byte(p.digest), []byte(h.digest(p.salt, p.hostname))) != 1 {
+		return errInvalidSharedKey
+	}
+	return nil
+}
+
+// writePong writes ["PONG", authenticated, reason, hostname, digest].
+func (h *handshake) writePong(w *msgp.Writer, salt string, authErr error) error {
+	reason := ""
+	if authErr != nil {
+		reason = authErr.Error()
+	}
+	if err := w.WriteArrayHeader(5); err != nil {
+		return err
+	}
+	for _, err := range []error{
+		w.WriteString(messagePong),
+		w.WriteBool(authErr == nil),
+		w.WriteString(reason),
+		w.WriteString(h.hostname),
+		w.WriteString(h.digest(salt, h.hostname)),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,181 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent_forward
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tinylib/msgp/msgp"
+)
+
+var testTime = time.Unix(1700000000, 123456789)
+
+// encode writes the values with the writer functions and returns the encoded bytes.
+func encode(t *testing.T, fn func(w *msgp.Writer) error) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := msgp.NewWriter(&buf)
+	require.NoError(t, fn(w))
+	require.NoError(t, w.Flush())
+	return buf.Bytes()
+}
+
+func writeEntry(w *msgp.Writer, timestamp any, record map[string]any) error {
+	if err := w.WriteArrayHeader(2); err != nil {
+		return err
+	}
+	if err := w.WriteIntf(timestamp); err != nil {
+		return err
+	}
+	return w.WriteMapStrIntf(record)
+}
+
+func gzipped(t *testing.T, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestReadMessage(t *testing.T) {
+	record := map[string]any{"log": "hello"}
+	packed := encode(t, func(w *msgp.Writer) error {
+		if err := writeEntry(w, &eventTime{testTime}, record); err != nil {
+			return err
+		}
+		return writeEntry(w, int64(1700000001), map[string]any{"log": []byte("world")})
+	})
+
+	testCases := map[string]struct {
+		content   []byte
+		wantChunk string
+		wantErr   bool
+	}{
+		"Message": {
+			content: encode(t, func(w *msgp.Writer) error {
+				_ = w.WriteArrayHeader(3)
+				_ = w.WriteString("app.web")
+				_ = w.WriteExtension(&eventTime{testTime})
+				return w.WriteMapStrIntf(record)
+			}),
+		},
+		"MessageWithOption": {
+			content: encode(t, func(w *msgp.Writer) error {
+				_ = w.WriteArrayHeader(4)
+				_ = w.WriteString("app.web")
+				_ = w.WriteExtension(&eventTime{testTime})
+				_ = w.WriteMapStrIntf(record)
+				return w.WriteMapStrIntf(map[string]any{"chunk": "abc"})
+			}),
+			wantChunk: "abc",
+		},
+		"Forward": {
+			content: encode(t, func(w *msgp.Writer) error {
+				_ = w.WriteArrayHeader(2)
+				_ = w.WriteString("app.web")
+				_ = w.WriteArrayHeader(1)
+				return writeEntry(w, &eventTime{testTime}, record)
+			}),
+		},
+		"PackedForward": {
+			content: encode(t, func(w *msgp.Writer) error {
+				_ = w.WriteArrayHeader(3)
+				_ = w.WriteString("app.web")
+				_ = w.WriteBytes(packed)
+				return w.WriteMapStrIntf(map[string]any{"size": 2, "chunk": "def"})
+			}),
+			wantChunk: "def",
+		},
+		"CompressedPackedForward": {
+			content: encode(t, func(w *msgp.Writer) error {
+				_ = w.WriteArrayHeader(3)
+				_ = w.WriteString("app.web")
+				_ = w.WriteBytes(gzipped(t, packed))
+				return w.WriteMapStrIntf(map[string]any{"compressed": "gzip"})
+			}),
+		},
+		"WithInvalidLength": {
+			content: encode(t, func(w *msgp.Writer) error {
+				_ = w.WriteArrayHeader(1)
+				return w.WriteString("app.web")
+			}),
+			wantErr: true,
+		},
+		"WithInvalidRecord": {
+			content: encode(t, func(w *msgp.Writer) error {
+				_ = w.WriteArrayHeader(3)
+				_ = w.WriteString("app.web")
+				_ = w.WriteInt64(1700000000)
+				return w.WriteString("hello")
+			}),
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			m, err := readMessage(msgp.NewReader(bytes.NewReader(testCase.content)))
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "app.web", m.tag)
+			assert.Equal(t, testCase.wantChunk, m.chunk)
+			require.NotEmpty(t, m.entries)
+			assert.True(t, testTime.Equal(m.entries[0].timestamp))
+			assert.Equal(t, record, m.entries[0].record)
+			if len(m.entries) > 1 {
+				assert.Equal(t, time.Unix(1700000001, 0), m.entries[1].timestamp)
+				assert.Equal(t, map[string]any{"log": "world"}, m.entries[1].record)
+			}
+		})
+	}
+}
+
+func TestReadTime(t *testing.T) {
+	testCases := map[string]struct {
+		value any
+		want  time.Time
+	}{
+		"EventTime": {value: &eventTime{testTime}, want: testTime},
+		"Int":       {value: int64(1700000000), want: time.Unix(1700000000, 0)},
+		"Uint":      {value: uint64(1700000000), want: time.Unix(1700000000, 0)},
+		"Float":     {value: 1700000000.5, want: time.Unix(1700000000, 500000000)},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			content := encode(t, func(w *msgp.Writer) error { return w.WriteIntf(testCase.value) })
+			got, err := readTime(msgp.NewReader(bytes.NewReader(content)))
+			require.NoError(t, err)
+			assert.True(t, testCase.want.Equal(got), got)
+		})
+	}
+
+	// the missing time is the current time
+	before := time.Now()
+	content := encode(t, func(w *msgp.Writer) error { return w.WriteNil() })
+	got, err := readTime(msgp.NewReader(bytes.NewReader(content)))
+	require.NoError(t, err)
+	assert.False(t, got.Before(before))
+}
+
+func TestHandshake(t *testing.T) {
+	h, err := newHandshake("server", "secret")
+	require.NoError(t, err)
+
+	client := &handshake{hostname: "client", sharedKey: "secret", nonce: h.nonce}
+	assert.NoError(t, h.verify(&ping{hostname: "client", salt: "salt", digest: client.digest("salt", "client")}))
+
+	client.sharedKey = "wrong"
+	assert.ErrorIs(t, h.verify(&ping{hostname: "client", salt: "salt", digest: client.digest("salt", "client")}), errInvalidSharedKey)
+	assert.ErrorIs(t, h.verify(&ping{hostname: "server", salt: "salt", digest: h.digest("salt", "server")}), errSameHostname)
+}
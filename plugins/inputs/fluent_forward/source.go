@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent_forward
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+// tagReferenceRegexp matches references to the tag or one of its dot separated parts, e.g. {tag} or {tag[1]}
+var tagReferenceRegexp = regexp.MustCompile(`\{tag(?:\[(\d+)\])?\}`)
+
+// resolveTag returns the name of the template with the tag references replaced. Returns an empty
+// string if the tag is empty or does not have one of the referenced parts.
+func resolveTag(t *logsource.Template, tag string) string {
+	if tag == "" {
+		return ""
+	}
+	parts := strings.Split(tag, ".")
+	values := make([]string, len(t.References()))
+	for i, index := range t.References() {
+		if index == "" {
+			values[i] = tag
+			continue
+		}
+		part, err := strconv.Atoi(index)
+		if err != nil || part >= len(parts) {
+			return ""
+		}
+		values[i] = parts[part]
+	}
+	return t.Resolve(values)
+}
+
+type logEvent struct {
+	message   string
+	timestamp time.Time
+	group     string
+	stream    string
+}
+
+var _ logs.RoutedLogEvent = (*logEvent)(nil)
+
+func (e *logEvent) Message() string {
+	return e.message
+}
+
+func (e *logEvent) Time() time.Time {
+	return e.timestamp
+}
+
+func (e *logEvent) Done() {}
+
+// Group is the log group resolved from the tag.
+func (e *logEvent) Group() string {
+	return e.group
+}
+
+// Stream is the log stream resolved from the tag.
+func (e *logEvent) Stream() string {
+	return e.stream
+}
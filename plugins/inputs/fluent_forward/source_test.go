@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent_forward
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+)
+
+func TestResolveTag(t *testing.T) {
+	template, _ := logsource.NewGroupTemplate("/fluent/logs", tagReferenceRegexp)
+	assert.Nil(t, template)
+
+	testCases := map[string]struct {
+		name string
+		tag  string
+		want string
+	}{
+		"WithTag": {
+			name: "/fluent/{tag}",
+			tag:  "app.web",
+			want: "/fluent/app.web",
+		},
+		"WithParts": {
+			name: "/fluent/{tag[0]}/{tag[1]}",
+			tag:  "app.web",
+			want: "/fluent/app/web",
+		},
+		"WithInvalidChars": {
+			name: "/fluent/{tag}",
+			tag:  "docker.my app",
+			want: "/fluent/docker.my_app",
+		},
+		"WithMissingPart": {
+			name: "/fluent/{tag[2]}",
+			tag:  "app.web",
+			want: "",
+		},
+		"WithEmptyPart": {
+			name: "/fluent/{tag[1]}",
+			tag:  "app..web",
+			want: "",
+		},
+		"WithEmptyTag": {
+			name: "/fluent/{tag}",
+			tag:  "",
+			want: "",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			template, _ := logsource.NewGroupTemplate(testCase.name, tagReferenceRegexp)
+			assert.Equal(t, testCase.want, resolveTag(template, testCase.tag))
+		})
+	}
+
+	template, static := logsource.NewStreamTemplate("{tag}:*", tagReferenceRegexp)
+	assert.Equal(t, "app.web__", resolveTag(template, "app.web"))
+	assert.Equal(t, ":*", static)
+}
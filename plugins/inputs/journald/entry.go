@@ -0,0 +1,116 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package journald
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	fieldCursor            = "__CURSOR"
+	fieldRealtimeTimestamp = "__REALTIME_TIMESTAMP"
+	fieldMessage           = "MESSAGE"
+	fieldSystemdUnit       = "_SYSTEMD_UNIT"
+	fieldSyslogIdentifier  = "SYSLOG_IDENTIFIER"
+	fieldTransport         = "_TRANSPORT"
+
+	// defaultUnit is used for the entries that were not logged by a unit or
+	// with an identifier.
+	defaultUnit = "journal"
+)
+
+// entry is a journal entry exported by journalctl with --output=json.
+type entry map[string]interface{}
+
+func parseEntry(line []byte) (entry, error) {
+	var e entry
+	if err := json.Unmarshal(line, &e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// field returns the value of the field as a string. Fields that are not valid
+// UTF-8 are exported as arrays of bytes, and fields that are set more than
+// once as arrays of their values, which are joined by new lines.
+func (e entry) field(name string) string {
+	switch v := e[name].(type) {
+	case string:
+		return v
+	case []interface{}:
+		if isByteArray(v) {
+			b := make([]byte, len(v))
+			for i, n := range v {
+				b[i] = byte(n.(float64))
+			}
+			return string(b)
+		}
+		values := make([]string, 0, len(v))
+		for _, value := range v {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return strings.Join(values, "\n")
+	default:
+		return ""
+	}
+}
+
+func isByteArray(v []interface{}) bool {
+	for _, n := range v {
+		if _, ok := n.(float64); !ok {
+			return false
+		}
+	}
+	return len(v) > 0
+}
+
+func (e entry) cursor() string {
+	return e.field(fieldCursor)
+}
+
+// time returns the wallclock time of the entry, or the current time if it is
+// missing.
+func (e entry) time() time.Time {
+	usec, err := strconv.ParseInt(e.field(fieldRealtimeTimestamp), 10, 64)
+	if err != nil {
+		return time.Now()
+	}
+	return time.UnixMicro(usec)
+}
+
+// unit returns the systemd unit that logged the entry, falling back to the
+// syslog identifier and the transport, e.g. kernel.
+func (e entry) unit() string {
+	for _, name := range []string{fieldSystemdUnit, fieldSyslogIdentifier, fieldTransport} {
+		if unit := e.field(name); unit != "" {
+			return unit
+		}
+	}
+	return defaultUnit
+}
+
+// message returns the log event message in the format. The json format has
+// all the fields of the entry except for the address fields, which start
+// with a double underscore.
+func (e entry) message(format string) (string, error) {
+	if format != eventFormatJSON {
+		return e.field(fieldMessage), nil
+	}
+	fields := make(map[string]string, len(e))
+	for name := range e {
+		if !strings.HasPrefix(name, "__") {
+			fields[name] = e.field(name)
+		}
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
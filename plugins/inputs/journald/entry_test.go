@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package journald
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntry(t *testing.T) {
+	e, err := parseEntry([]byte(`{"__CURSOR":"s=1;i=2","__REALTIME_TIMESTAMP":"1700000000123456","__MONOTONIC_TIMESTAMP":"42","MESSAGE":"started","_SYSTEMD_UNIT":"nginx.service","PRIORITY":"6"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "s=1;i=2", e.cursor())
+	assert.Equal(t, time.UnixMicro(1700000000123456), e.time())
+	assert.Equal(t, "nginx.service", e.unit())
+
+	message, err := e.message(eventFormatText)
+	require.NoError(t, err)
+	assert.Equal(t, "started", message)
+	message, err = e.message(eventFormatJSON)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"MESSAGE":"started","_SYSTEMD_UNIT":"nginx.service","PRIORITY":"6"}`, message)
+}
+
+func TestEntryField(t *testing.T) {
+	e, err := parseEntry([]byte(`{"MESSAGE":[104,105],"TAG":["a","b"],"SYSLOG_IDENTIFIER":"sudo"}`))
+	require.NoError(t, err)
+	// the values that are not valid UTF-8 are arrays of bytes
+	assert.Equal(t, "hi", e.field(fieldMessage))
+	// the fields set more than once are arrays of strings
+	assert.Equal(t, "a\nb", e.field("TAG"))
+	assert.Equal(t, "", e.field("MISSING"))
+	assert.Equal(t, "sudo", e.unit())
+	assert.WithinDuration(t, time.Now(), e.time(), time.Minute)
+}
+
+func TestEntryUnit(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+		want  string
+	}{
+		"WithUnit":       {input: `{"_SYSTEMD_UNIT":"sshd.service","SYSLOG_IDENTIFIER":"sshd"}`, want: "sshd.service"},
+		"WithIdentifier": {input: `{"SYSLOG_IDENTIFIER":"sshd"}`, want: "sshd"},
+		"WithTransport":  {input: `{"_TRANSPORT":"kernel"}`, want: "kernel"},
+		"WithNone":       {input: `{}`, want: defaultUnit},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			e, err := parseEntry([]byte(testCase.input))
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, e.unit())
+		})
+	}
+}
+
+func TestResolveTemplate(t *testing.T) {
+	assert.Equal(t, "host/nginx.service", resolveTemplate("host/{unit}", "nginx.service"))
+	assert.Equal(t, "systemd-coredump@1_2.service", resolveTemplate("{unit}", "systemd-coredump@1:2.service"))
+	assert.Equal(t, "journald", resolveTemplate("journald", "nginx.service"))
+}
@@ -0,0 +1,170 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package journald
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logscommon"
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/tool/paths"
+)
+
+const (
+	eventFormatText = "text"
+	eventFormatJSON = "json"
+
+	defaultLogStreamName = unitPlaceholder
+)
+
+type JournalConfig struct {
+	// Units are the systemd units the entries are collected for. The entries
+	// of all the units are collected if it is empty.
+	Units []string `toml:"units"`
+	// Priority is the lowest priority of the entries collected, e.g. warning.
+	Priority      string `toml:"priority"`
+	EventFormat   string `toml:"event_format"`
+	LogGroupName  string `toml:"log_group_name"`
+	LogStreamName string `toml:"log_stream_name"`
+	LogGroupClass string `toml:"log_group_class"`
+	Destination   string `toml:"destination"`
+	Retention     int    `toml:"retention_in_days"`
+}
+
+type Plugin struct {
+	FileStateFolder string          `toml:"file_state_folder"`
+	Journals        []JournalConfig `toml:"journal_config"`
+	Destination     string          `toml:"destination"`
+	Log             telegraf.Logger `toml:"-"`
+
+	mu         sync.Mutex
+	sources    []*logsource.Src
+	newSources []logs.LogSrc
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+var _ logs.LogCollection = (*Plugin)(nil)
+
+func (p *Plugin) Description() string {
+	return "A plugin to collect the systemd journal entries"
+}
+
+func (p *Plugin) SampleConfig() string {
+	return `
+	file_state_folder = ` + strconv.Quote(paths.FileStateFolderPath) + `
+	destination = "cloudwatchlogs"
+
+	[[inputs.journald.journal_config]]
+	## The entries of all the units are collected if empty.
+	units = ["nginx.service", "sshd.service"]
+	## The lowest priority collected, from emerg to debug.
+	priority = "info"
+	## Either "text" for the message or "json" for all the fields.
+	event_format = "text"
+	log_group_name = "journald"
+	## The {unit} placeholder is replaced by the unit of the entry.
+	log_stream_name = "{unit}"
+	`
+}
+
+func (p *Plugin) Gather(acc telegraf.Accumulator) error {
+	return nil
+}
+
+func (p *Plugin) FindLogSrc() []logs.LogSrc {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	srcs := p.newSources
+	p.newSources = nil
+	return srcs
+}
+
+// Start follows the journal of each journal config in the background. The
+// sources are created as the entries of their units are read.
+func (p *Plugin) Start(acc telegraf.Accumulator) error {
+	if len(p.Journals) == 0 {
+		return errors.New("no journal_config configured")
+	}
+	if p.FileStateFolder == "" {
+		return errors.New("empty file_state_folder")
+	}
+	if err := os.MkdirAll(p.FileStateFolder, 0755); err != nil {
+		return err
+	}
+	readers := make([]*journalReader, 0, len(p.Journals))
+	for _, config := range p.Journals {
+		if config.LogGroupName == "" {
+			return errors.New("log_group_name is required")
+		}
+		switch config.EventFormat {
+		case "":
+			config.EventFormat = eventFormatText
+		case eventFormatText, eventFormatJSON:
+		default:
+			return fmt.Errorf("invalid event_format: %s", config.EventFormat)
+		}
+		if config.LogStreamName == "" {
+			config.LogStreamName = defaultLogStreamName
+		}
+		if config.Destination == "" {
+			config.Destination = p.Destination
+		}
+		readers = append(readers, newJournalReader(config, p.stateFilePath(config), p.Log, p.addSource))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	for _, r := range readers {
+		p.wg.Add(2)
+		go func(r *journalReader) {
+			defer p.wg.Done()
+			r.run(ctx)
+		}(r)
+		go func(r *journalReader) {
+			defer p.wg.Done()
+			r.runSaveState(ctx)
+		}(r)
+	}
+	return nil
+}
+
+func (p *Plugin) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, src := range p.sources {
+		src.Stop()
+	}
+}
+
+func (p *Plugin) addSource(src *logsource.Src) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sources = append(p.sources, src)
+	p.newSources = append(p.newSources, src)
+}
+
+// stateFilePath returns a unique file pathname for the journal config.
+func (p *Plugin) stateFilePath(config JournalConfig) string {
+	name := logscommon.JournaldPrefix + logsource.EscapeFileName(config.LogGroupName+"_"+config.LogStreamName+"_"+strings.Join(config.Units, ","))
+	return filepath.Join(p.FileStateFolder, name)
+}
+
+func init() {
+	inputs.Add("journald", func() telegraf.Input { return &Plugin{} })
+}
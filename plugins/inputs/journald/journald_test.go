@@ -0,0 +1,142 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package journald
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+const testEntries = `{"__CURSOR":"c1","__REALTIME_TIMESTAMP":"1700000000000000","MESSAGE":"nginx started","_SYSTEMD_UNIT":"nginx.service"}
+{"__CURSOR":"c2","__REALTIME_TIMESTAMP":"1700000001000000","MESSAGE":"accepted key","_SYSTEMD_UNIT":"sshd.service"}
+{"__CURSOR":"c3","__REALTIME_TIMESTAMP":"1700000002000000","MESSAGE":"","_SYSTEMD_UNIT":"sshd.service"}
+`
+
+// fakeJournal replaces journalctl with the entries and records the arguments
+// it was started with.
+type fakeJournal struct {
+	mu   sync.Mutex
+	args [][]string
+}
+
+func (f *fakeJournal) start(ctx context.Context, args []string) (io.ReadCloser, func() error, error) {
+	f.mu.Lock()
+	f.args = append(f.args, args)
+	f.mu.Unlock()
+	r, w := io.Pipe()
+	go func() {
+		_, _ = io.Copy(w, strings.NewReader(testEntries))
+		// journalctl follows the journal until it is killed
+		<-ctx.Done()
+		_ = w.CloseWithError(ctx.Err())
+	}()
+	return r, func() error { return nil }, nil
+}
+
+func (f *fakeJournal) calls() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.args
+}
+
+func TestPlugin(t *testing.T) {
+	journal := &fakeJournal{}
+	defer func(original func(context.Context, []string) (io.ReadCloser, func() error, error)) {
+		journalctl = original
+	}(journalctl)
+	journalctl = journal.start
+
+	p := &Plugin{
+		FileStateFolder: t.TempDir(),
+		Destination:     "cloudwatchlogs",
+		Journals: []JournalConfig{
+			{Units: []string{"nginx.service", "sshd.service"}, Priority: "info", LogGroupName: "journald", Retention: 7},
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, p.Start(nil))
+
+	srcs := map[string]logs.LogSrc{}
+	events := make(chan logs.LogEvent, 2)
+	assert.Eventually(t, func() bool {
+		for _, src := range p.FindLogSrc() {
+			srcs[src.Stream()] = src
+			src.SetOutput(func(e logs.LogEvent) {
+				if e != nil {
+					events <- e
+				}
+			})
+		}
+		return len(srcs) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	nginx := srcs["nginx.service"]
+	require.NotNil(t, nginx)
+	assert.Equal(t, "journald", nginx.Group())
+	assert.Equal(t, "journald:nginx.service", nginx.Description())
+	assert.Equal(t, "cloudwatchlogs", nginx.Destination())
+	assert.Equal(t, 7, nginx.Retention())
+	require.NotNil(t, srcs["sshd.service"])
+
+	var published []logs.LogEvent
+	for len(published) < 2 {
+		select {
+		case e := <-events:
+			published = append(published, e)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event published")
+		}
+	}
+	messages := []string{published[0].Message(), published[1].Message()}
+	assert.ElementsMatch(t, []string{"nginx started", "accepted key"}, messages)
+	for _, e := range published {
+		if e.Message() == "accepted key" {
+			assert.Equal(t, time.Unix(1700000001, 0), e.Time())
+			e.Done()
+		}
+	}
+
+	// the cursor of the last published entry is saved
+	stateFile := p.stateFilePath(JournalConfig{Units: []string{"nginx.service", "sshd.service"}, LogGroupName: "journald", LogStreamName: defaultLogStreamName})
+	assert.Eventually(t, func() bool {
+		content, err := os.ReadFile(stateFile)
+		return err == nil && string(content) == "c2"
+	}, 5*time.Second, 10*time.Millisecond)
+	p.Stop()
+
+	calls := journal.calls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, []string{"--follow", "--output=json", "--no-pager", "--lines=0", "--unit=nginx.service", "--unit=sshd.service", "--priority=info"}, calls[0])
+}
+
+func TestReaderArgs(t *testing.T) {
+	stateFile := t.TempDir() + "/state"
+	require.NoError(t, os.WriteFile(stateFile, []byte("s=1;i=2\n"), 0644))
+	r := newJournalReader(JournalConfig{}, stateFile, testutil.Logger{}, nil)
+	r.cursor = r.loadState()
+	assert.Equal(t, []string{"--follow", "--output=json", "--no-pager", "--after-cursor=s=1;i=2", "--no-tail"}, r.args())
+}
+
+func TestStartInvalidConfig(t *testing.T) {
+	p := &Plugin{Log: testutil.Logger{}}
+	assert.Error(t, p.Start(nil))
+	p.Journals = []JournalConfig{{LogGroupName: "journald"}}
+	assert.Error(t, p.Start(nil))
+	p.FileStateFolder = t.TempDir()
+	p.Journals = []JournalConfig{{}}
+	assert.Error(t, p.Start(nil))
+	p.Journals = []JournalConfig{{LogGroupName: "journald", EventFormat: "xml"}}
+	assert.Error(t, p.Start(nil))
+}
@@ -0,0 +1,237 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package journald
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+)
+
+const (
+	retryInterval     = 10 * time.Second
+	saveStateInterval = 100 * time.Millisecond
+)
+
+// journalctl starts journalctl with the arguments and returns its output and
+// a function waiting for it to exit. Overridden in tests.
+var journalctl = func(ctx context.Context, args []string) (io.ReadCloser, func() error, error) {
+	cmd := exec.CommandContext(ctx, "journalctl", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err = cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+	wait := func() error {
+		if err := cmd.Wait(); err != nil {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return nil
+	}
+	return stdout, wait, nil
+}
+
+// cursorState is the position of a published entry. The entries are ordered
+// by the sequence since they can be published by several sources.
+type cursorState struct {
+	seq    uint64
+	cursor string
+}
+
+// journalReader follows the journal entries of a journal config and routes
+// them to the sources of their units.
+type journalReader struct {
+	config    JournalConfig
+	stateFile string
+	log       telegraf.Logger
+	addSource func(*logsource.Src)
+
+	// cursor, seq and sources are only accessed by the reader goroutine.
+	cursor  string
+	seq     uint64
+	sources map[sourceKey]*logsource.Src
+
+	mu    sync.Mutex
+	state cursorState
+}
+
+func newJournalReader(config JournalConfig, stateFile string, log telegraf.Logger, addSource func(*logsource.Src)) *journalReader {
+	return &journalReader{
+		config:    config,
+		stateFile: stateFile,
+		log:       log,
+		addSource: addSource,
+		sources:   make(map[sourceKey]*logsource.Src),
+	}
+}
+
+// args returns the journalctl arguments to follow the entries after the
+// cursor, or the new entries if there is no cursor.
+func (r *journalReader) args() []string {
+	args := []string{"--follow", "--output=json", "--no-pager"}
+	if r.cursor != "" {
+		args = append(args, "--after-cursor="+r.cursor, "--no-tail")
+	} else {
+		args = append(args, "--lines=0")
+	}
+	for _, unit := range r.config.Units {
+		args = append(args, "--unit="+unit)
+	}
+	if r.config.Priority != "" {
+		args = append(args, "--priority="+r.config.Priority)
+	}
+	return args
+}
+
+// run restarts journalctl from the last cursor read whenever it exits until
+// the context is done.
+func (r *journalReader) run(ctx context.Context) {
+	r.cursor = r.loadState()
+	for {
+		if err := r.read(ctx); err != nil && ctx.Err() == nil {
+			r.log.Errorf("Unable to read the journal for units %v: %v", r.config.Units, err)
+		}
+		if !logsource.Sleep(ctx, retryInterval) {
+			return
+		}
+	}
+}
+
+func (r *journalReader) read(ctx context.Context) error {
+	stdout, wait, err := journalctl(ctx, r.args())
+	if err != nil {
+		return err
+	}
+	defer stdout.Close()
+	reader := bufio.NewReader(stdout)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 && !r.process(ctx, line) {
+			// journalctl is killed since the context is done
+			_ = wait()
+			return nil
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return wait()
+			}
+			_ = wait()
+			return err
+		}
+	}
+}
+
+// process publishes the entry to the source of its unit. Returns false if the
+// context is done.
+func (r *journalReader) process(ctx context.Context, line []byte) bool {
+	e, err := parseEntry(line)
+	if err != nil {
+		r.log.Warnf("Unable to parse the journal entry: %v", err)
+		return true
+	}
+	r.cursor = e.cursor()
+	message, err := e.message(r.config.EventFormat)
+	if err != nil {
+		r.log.Warnf("Unable to format the journal entry %s: %v", r.cursor, err)
+		return true
+	}
+	if message == "" {
+		return true
+	}
+	r.seq++
+	state := cursorState{seq: r.seq, cursor: r.cursor}
+	evt := logsource.NewEvent(message, e.time(), func() { r.done(state) })
+	return r.source(e.unit()).Publish(evt, ctx.Done())
+}
+
+// source returns the source for the log group and stream of the unit.
+func (r *journalReader) source(unit string) *logsource.Src {
+	key := sourceKey{
+		group:  resolveTemplate(r.config.LogGroupName, unit),
+		stream: resolveTemplate(r.config.LogStreamName, unit),
+	}
+	if src, ok := r.sources[key]; ok {
+		return src
+	}
+	src := logsource.New(key.group, key.stream, "journald:"+unit, r.config.Destination, r.config.LogGroupClass, r.config.Retention)
+	r.sources[key] = src
+	r.addSource(src)
+	return src
+}
+
+func (r *journalReader) done(state cursorState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if state.seq > r.state.seq {
+		r.state = state
+	}
+}
+
+// runSaveState saves the cursor of the last published entry until the context
+// is done.
+func (r *journalReader) runSaveState(ctx context.Context) {
+	t := time.NewTicker(saveStateInterval)
+	defer t.Stop()
+	var lastSaved uint64
+	for {
+		select {
+		case <-t.C:
+			saved, err := r.saveState(lastSaved)
+			if err != nil {
+				r.log.Errorf("Unable to save the journal cursor to %s: %v", r.stateFile, err)
+				continue
+			}
+			lastSaved = saved
+		case <-ctx.Done():
+			if _, err := r.saveState(lastSaved); err != nil {
+				r.log.Errorf("Unable to save the journal cursor to %s, duplicate logs may be sent at the next start: %v", r.stateFile, err)
+			}
+			return
+		}
+	}
+}
+
+// saveState writes the cursor if an entry was published since the last save
+// and returns the sequence of the saved entry.
+func (r *journalReader) saveState(lastSaved uint64) (uint64, error) {
+	r.mu.Lock()
+	state := r.state
+	r.mu.Unlock()
+	if state.seq == lastSaved || state.cursor == "" {
+		return lastSaved, nil
+	}
+	if err := os.WriteFile(r.stateFile, []byte(state.cursor), 0644); err != nil {
+		return lastSaved, err
+	}
+	return state.seq, nil
+}
+
+func (r *journalReader) loadState() string {
+	content, err := os.ReadFile(r.stateFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			r.log.Warnf("Unable to read the journal cursor from %s: %v", r.stateFile, err)
+		}
+		return ""
+	}
+	cursor := strings.TrimSpace(string(content))
+	r.log.Debugf("Reading the journal after cursor %s from %s", cursor, r.stateFile)
+	return cursor
+}
@@ -0,0 +1,20 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package journald
+
+import "strings"
+
+const unitPlaceholder = "{unit}"
+
+// resolveTemplate replaces the unit placeholder in the log group or stream
+// name. The characters that are not allowed in the names are replaced.
+func resolveTemplate(template, unit string) string {
+	unit = strings.NewReplacer(":", "_", "*", "_").Replace(unit)
+	return strings.ReplaceAll(template, unitPlaceholder, unit)
+}
+
+type sourceKey struct {
+	group  string
+	stream string
+}
@@ -0,0 +1,111 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka_logs
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/IBM/sarama"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+)
+
+// consumerHandler pipes the messages of each claimed partition to its log
+// source. Offsets are only marked once the events have been published, so
+// anything that was not published is consumed again after a restart or
+// rebalance.
+type consumerHandler struct {
+	source func(topic string, partition int32) *logsource.Src
+}
+
+var _ sarama.ConsumerGroupHandler = (*consumerHandler)(nil)
+
+func (h *consumerHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *consumerHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *consumerHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	topic, partition := claim.Topic(), claim.Partition()
+	src := h.source(topic, partition)
+	tracker := newOffsetTracker(func(offset int64) {
+		// the committed offset is the next message to consume
+		session.MarkOffset(topic, partition, offset+1, "")
+	})
+	stop := session.Context().Done()
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			offset := msg.Offset
+			tracker.add(offset)
+			if len(msg.Value) == 0 {
+				// nothing to publish for tombstones
+				tracker.done(offset)
+				continue
+			}
+			// the offset is committed once the event has been published
+			e := logsource.NewEvent(string(msg.Value), msg.Timestamp, func() { tracker.done(offset) })
+			if !src.Publish(e, stop) {
+				return nil
+			}
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+type pendingOffset struct {
+	offset int64
+	done   bool
+}
+
+// offsetTracker commits the highest offset for which it and all of the
+// offsets before it are done. The offsets in a batch are not necessarily done
+// in order and a batch that failed to publish is never done, so committing
+// the latest done offset could skip over events that were never published.
+type offsetTracker struct {
+	mu      sync.Mutex
+	pending []pendingOffset
+	commit  func(offset int64)
+}
+
+func newOffsetTracker(commit func(offset int64)) *offsetTracker {
+	return &offsetTracker{commit: commit}
+}
+
+// add must be called in the order that the offsets are consumed.
+func (t *offsetTracker) add(offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = append(t.pending, pendingOffset{offset: offset})
+}
+
+func (t *offsetTracker) done(offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	i := sort.Search(len(t.pending), func(i int) bool {
+		return t.pending[i].offset >= offset
+	})
+	if i == len(t.pending) || t.pending[i].offset != offset {
+		return
+	}
+	t.pending[i].done = true
+	n := 0
+	for n < len(t.pending) && t.pending[n].done {
+		n++
+	}
+	if n == 0 {
+		return
+	}
+	last := t.pending[n-1].offset
+	t.pending = t.pending[n:]
+	t.commit(last)
+}
@@ -0,0 +1,155 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka_logs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+type mockSession struct {
+	sarama.ConsumerGroupSession
+	ctx context.Context
+
+	mu      sync.Mutex
+	offsets map[partitionKey]int64
+}
+
+func newMockSession(ctx context.Context) *mockSession {
+	return &mockSession{ctx: ctx, offsets: make(map[partitionKey]int64)}
+}
+
+func (s *mockSession) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockSession) MarkOffset(topic string, partition int32, offset int64, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offsets[partitionKey{topic: topic, partition: partition}] = offset
+}
+
+func (s *mockSession) offset(topic string, partition int32) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offsets[partitionKey{topic: topic, partition: partition}]
+}
+
+type mockClaim struct {
+	sarama.ConsumerGroupClaim
+	topic     string
+	partition int32
+	messages  chan *sarama.ConsumerMessage
+}
+
+func (c *mockClaim) Topic() string {
+	return c.topic
+}
+
+func (c *mockClaim) Partition() int32 {
+	return c.partition
+}
+
+func (c *mockClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
+
+func TestOffsetTracker(t *testing.T) {
+	var committed []int64
+	tracker := newOffsetTracker(func(offset int64) {
+		committed = append(committed, offset)
+	})
+	for offset := int64(10); offset < 15; offset++ {
+		tracker.add(offset)
+	}
+	// done in reverse order within a batch
+	tracker.done(12)
+	tracker.done(11)
+	assert.Empty(t, committed)
+	tracker.done(10)
+	assert.Equal(t, []int64{12}, committed)
+	// unknown offsets are ignored
+	tracker.done(3)
+	tracker.done(12)
+	assert.Equal(t, []int64{12}, committed)
+	// a gap holds back the commit
+	tracker.done(14)
+	assert.Equal(t, []int64{12}, committed)
+	tracker.done(13)
+	assert.Equal(t, []int64{12, 14}, committed)
+	assert.Empty(t, tracker.pending)
+}
+
+func TestConsumeClaim(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	src := logsource.New("group", "stream", "kafka:topic/1", "cloudwatchlogs", "", -1)
+	handler := &consumerHandler{source: func(string, int32) *logsource.Src { return src }}
+	session := newMockSession(ctx)
+	claim := &mockClaim{topic: "topic", partition: 1, messages: make(chan *sarama.ConsumerMessage, 3)}
+	now := time.Now()
+	claim.messages <- &sarama.ConsumerMessage{Offset: 5, Value: []byte("first"), Timestamp: now}
+	claim.messages <- &sarama.ConsumerMessage{Offset: 6}
+	claim.messages <- &sarama.ConsumerMessage{Offset: 7, Value: []byte("second"), Timestamp: now}
+	close(claim.messages)
+
+	events := make(chan logs.LogEvent, 2)
+	src.SetOutput(func(e logs.LogEvent) {
+		if e != nil {
+			events <- e
+		}
+	})
+	require.NoError(t, handler.ConsumeClaim(session, claim))
+
+	var published []logs.LogEvent
+	for len(published) < 2 {
+		select {
+		case e := <-events:
+			published = append(published, e)
+		case <-time.After(5 * time.Second):
+			t.Fatal("events were not published")
+		}
+	}
+	assert.Equal(t, "first", published[0].Message())
+	assert.Equal(t, now, published[0].Time())
+	assert.Equal(t, "second", published[1].Message())
+	assert.EqualValues(t, 0, session.offset("topic", 1))
+
+	published[1].Done()
+	assert.EqualValues(t, 0, session.offset("topic", 1))
+	// the tombstone is committed along with the first event
+	published[0].Done()
+	assert.EqualValues(t, 8, session.offset("topic", 1))
+	src.Stop()
+}
+
+func TestConsumeClaimSessionDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	src := logsource.New("group", "stream", "kafka:topic/0", "cloudwatchlogs", "", -1)
+	handler := &consumerHandler{source: func(string, int32) *logsource.Src { return src }}
+	claim := &mockClaim{topic: "topic", partition: 0, messages: make(chan *sarama.ConsumerMessage, 1)}
+	// the output is never set, so the event cannot be handed over
+	claim.messages <- &sarama.ConsumerMessage{Offset: 0, Value: []byte("message")}
+
+	errs := make(chan error)
+	go func() {
+		errs <- handler.ConsumeClaim(newMockSession(ctx), claim)
+	}()
+	cancel()
+	select {
+	case err := <-errs:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("consume claim did not return after the session ended")
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka_logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+const (
+	clientID             = "amazon-cloudwatch-agent"
+	defaultConsumerGroup = "amazon-cloudwatch-agent"
+
+	initialOffsetLatest   = "latest"
+	initialOffsetEarliest = "earliest"
+
+	saslMechanismMSKIAM = "AWS_MSK_IAM"
+
+	defaultLogGroupName  = topicPlaceholder
+	defaultLogStreamName = topicPlaceholder + "_" + partitionPlaceholder
+
+	retryInterval = 10 * time.Second
+)
+
+// newConsumerGroup is overridden in tests.
+var newConsumerGroup = sarama.NewConsumerGroup
+
+type TopicConfig struct {
+	Topics        []string `toml:"topics"`
+	LogGroupName  string   `toml:"log_group_name"`
+	LogStreamName string   `toml:"log_stream_name"`
+	LogGroupClass string   `toml:"log_group_class"`
+	Destination   string   `toml:"destination"`
+	Retention     int      `toml:"retention_in_days"`
+}
+
+type Plugin struct {
+	Brokers       []string      `toml:"brokers"`
+	ConsumerGroup string        `toml:"consumer_group"`
+	InitialOffset string        `toml:"initial_offset"`
+	SASLMechanism string        `toml:"sasl_mechanism"`
+	TLS           bool          `toml:"tls"`
+	Topics        []TopicConfig `toml:"topic_config"`
+	Destination   string        `toml:"destination"`
+
+	Region    string `toml:"region"`
+	AccessKey string `toml:"access_key"`
+	SecretKey string `toml:"secret_key"`
+	RoleARN   string `toml:"role_arn"`
+	Profile   string `toml:"profile"`
+	Filename  string `toml:"shared_credential_file"`
+	Token     string `toml:"token"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	mu           sync.Mutex
+	topicConfigs map[string]*TopicConfig
+	sources      map[partitionKey]*logsource.Src
+	newSources   []logs.LogSrc
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+var _ logs.LogCollection = (*Plugin)(nil)
+
+func (p *Plugin) Description() string {
+	return "A plugin to collect log events from Kafka topics"
+}
+
+func (p *Plugin) SampleConfig() string {
+	return `
+	brokers = ["b-1.cluster.kafka.us-east-1.amazonaws.com:9098"]
+	consumer_group = "amazon-cloudwatch-agent"
+	initial_offset = "latest"
+	sasl_mechanism = "AWS_MSK_IAM"
+	destination = "cloudwatchlogs"
+
+	[[inputs.kafka_logs.topic_config]]
+	topics = ["application-logs"]
+	log_group_name = "{topic}"
+	log_stream_name = "{topic}_{partition}"
+	`
+}
+
+func (p *Plugin) Gather(acc telegraf.Accumulator) error {
+	return nil
+}
+
+func (p *Plugin) FindLogSrc() []logs.LogSrc {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	srcs := p.newSources
+	p.newSources = nil
+	return srcs
+}
+
+// Start consumes from the configured topics in the background since
+// connecting to the brokers can take a while and should not hold up the
+// other log collections.
+func (p *Plugin) Start(acc telegraf.Accumulator) error {
+	if len(p.Brokers) == 0 {
+		return errors.New("no brokers configured")
+	}
+	topics := p.initTopics()
+	if len(topics) == 0 {
+		return errors.New("no topics configured")
+	}
+	cfg, err := p.saramaConfig()
+	if err != nil {
+		return err
+	}
+	if p.ConsumerGroup == "" {
+		p.ConsumerGroup = defaultConsumerGroup
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.wg.Add(1)
+	go p.run(ctx, cfg, topics)
+	return nil
+}
+
+func (p *Plugin) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, src := range p.sources {
+		src.Stop()
+	}
+}
+
+// initTopics maps each topic to its config. If the same topic is configured
+// more than once, the first config is used.
+func (p *Plugin) initTopics() []string {
+	p.topicConfigs = make(map[string]*TopicConfig)
+	p.sources = make(map[partitionKey]*logsource.Src)
+	var topics []string
+	for i := range p.Topics {
+		tc := &p.Topics[i]
+		for _, topic := range tc.Topics {
+			if _, ok := p.topicConfigs[topic]; ok {
+				p.Log.Warnf("Topic %s is configured more than once, only the first config is used", topic)
+				continue
+			}
+			p.topicConfigs[topic] = tc
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+func (p *Plugin) saramaConfig() (*sarama.Config, error) {
+	cfg := sarama.NewConfig()
+	cfg.ClientID = clientID
+	switch p.InitialOffset {
+	case "", initialOffsetLatest:
+		cfg.Consumer.Offsets.Initial = sarama.OffsetNewest
+	case initialOffsetEarliest:
+		cfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+	default:
+		return nil, fmt.Errorf("invalid initial_offset: %s", p.InitialOffset)
+	}
+	cfg.Net.TLS.Enable = p.TLS
+	switch p.SASLMechanism {
+	case "":
+	case saslMechanismMSKIAM:
+		// IAM access control is only available on the TLS listener
+		cfg.Net.TLS.Enable = true
+		cfg.Net.SASL.Enable = true
+		cfg.Net.SASL.Mechanism = sarama.SASLTypeOAuth
+		cfg.Net.SASL.TokenProvider = newMSKIAMTokenProvider(p.Region, p.credentialConfig().Credentials().ClientConfig(mskSigningName).Config.Credentials)
+	default:
+		return nil, fmt.Errorf("unsupported sasl_mechanism: %s", p.SASLMechanism)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (p *Plugin) credentialConfig() *configaws.CredentialConfig {
+	return &configaws.CredentialConfig{
+		Region:    p.Region,
+		AccessKey: p.AccessKey,
+		SecretKey: p.SecretKey,
+		RoleARN:   p.RoleARN,
+		Profile:   p.Profile,
+		Filename:  p.Filename,
+		Token:     p.Token,
+	}
+}
+
+func (p *Plugin) run(ctx context.Context, cfg *sarama.Config, topics []string) {
+	defer p.wg.Done()
+	var group sarama.ConsumerGroup
+	for group == nil {
+		var err error
+		if group, err = newConsumerGroup(p.Brokers, p.ConsumerGroup, cfg); err != nil {
+			p.Log.Errorf("Unable to create consumer group %s for brokers %v: %v", p.ConsumerGroup, p.Brokers, err)
+			if !logsource.Sleep(ctx, retryInterval) {
+				return
+			}
+		}
+	}
+	defer group.Close()
+	handler := &consumerHandler{source: p.source}
+	for ctx.Err() == nil {
+		// Consume returns whenever the group rebalances
+		if err := group.Consume(ctx, topics, handler); err != nil {
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return
+			}
+			p.Log.Errorf("Unable to consume from topics %v: %v", topics, err)
+			if !logsource.Sleep(ctx, retryInterval) {
+				return
+			}
+		}
+	}
+}
+
+// source returns the log source for the topic partition. The sources outlive
+// the consumer group sessions so that each partition is only piped to its
+// destination once.
+func (p *Plugin) source(topic string, partition int32) *logsource.Src {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := partitionKey{topic: topic, partition: partition}
+	if src, ok := p.sources[key]; ok {
+		return src
+	}
+	tc := p.topicConfigs[topic]
+	destination := tc.Destination
+	if destination == "" {
+		destination = p.Destination
+	}
+	src := logsource.New(
+		resolveTemplate(tc.LogGroupName, defaultLogGroupName, key),
+		resolveTemplate(tc.LogStreamName, defaultLogStreamName, key),
+		"kafka:"+key.String(),
+		destination,
+		tc.LogGroupClass,
+		tc.Retention,
+	)
+	p.sources[key] = src
+	p.newSources = append(p.newSources, src)
+	return src
+}
+
+func init() {
+	inputs.Add("kafka_logs", func() telegraf.Input { return &Plugin{} })
+}
@@ -0,0 +1,147 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka_logs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+type mockConsumerGroup struct {
+	sarama.ConsumerGroup
+	messages map[partitionKey][]*sarama.ConsumerMessage
+	session  *mockSession
+	closed   chan struct{}
+}
+
+// Consume claims every partition with messages and blocks until the context
+// is done.
+func (g *mockConsumerGroup) Consume(ctx context.Context, _ []string, handler sarama.ConsumerGroupHandler) error {
+	g.session = newMockSession(ctx)
+	for key, messages := range g.messages {
+		claim := &mockClaim{topic: key.topic, partition: key.partition, messages: make(chan *sarama.ConsumerMessage, len(messages))}
+		for _, msg := range messages {
+			claim.messages <- msg
+		}
+		go handler.ConsumeClaim(g.session, claim)
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (g *mockConsumerGroup) Close() error {
+	close(g.closed)
+	return nil
+}
+
+func TestPlugin(t *testing.T) {
+	group := &mockConsumerGroup{
+		messages: map[partitionKey][]*sarama.ConsumerMessage{
+			{topic: "app", partition: 0}:   {{Offset: 0, Value: []byte("app 0")}},
+			{topic: "app", partition: 1}:   {{Offset: 3, Value: []byte("app 1")}},
+			{topic: "audit", partition: 0}: {{Offset: 9, Value: []byte("audit 0")}},
+		},
+		closed: make(chan struct{}),
+	}
+	defer func(original func([]string, string, *sarama.Config) (sarama.ConsumerGroup, error)) {
+		newConsumerGroup = original
+	}(newConsumerGroup)
+	newConsumerGroup = func(brokers []string, groupID string, _ *sarama.Config) (sarama.ConsumerGroup, error) {
+		assert.Equal(t, []string{"localhost:9092"}, brokers)
+		assert.Equal(t, defaultConsumerGroup, groupID)
+		return group, nil
+	}
+
+	p := &Plugin{
+		Brokers:     []string{"localhost:9092"},
+		Destination: "cloudwatchlogs",
+		Topics: []TopicConfig{
+			{Topics: []string{"app"}, LogGroupName: "/kafka/{topic}", Retention: 7},
+			{Topics: []string{"audit", "app"}, LogGroupName: "audit", LogStreamName: "partition-{partition}", LogGroupClass: "INFREQUENT_ACCESS"},
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, p.Start(nil))
+
+	srcs := map[string]logs.LogSrc{}
+	assert.Eventually(t, func() bool {
+		for _, src := range p.FindLogSrc() {
+			srcs[src.Description()] = src
+		}
+		return len(srcs) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	app0 := srcs["kafka:app/0"]
+	require.NotNil(t, app0)
+	assert.Equal(t, "/kafka/app", app0.Group())
+	assert.Equal(t, "app_0", app0.Stream())
+	assert.Equal(t, 7, app0.Retention())
+	assert.Equal(t, "cloudwatchlogs", app0.Destination())
+	audit0 := srcs["kafka:audit/0"]
+	require.NotNil(t, audit0)
+	assert.Equal(t, "audit", audit0.Group())
+	assert.Equal(t, "partition-0", audit0.Stream())
+	assert.Equal(t, "INFREQUENT_ACCESS", audit0.Class())
+
+	events := make(chan logs.LogEvent, 1)
+	audit0.SetOutput(func(e logs.LogEvent) {
+		if e != nil {
+			events <- e
+		}
+	})
+	select {
+	case e := <-events:
+		assert.Equal(t, "audit 0", e.Message())
+		e.Done()
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event published")
+	}
+	assert.EqualValues(t, 10, group.session.offset("audit", 0))
+	assert.EqualValues(t, 0, group.session.offset("app", 0))
+
+	p.Stop()
+	select {
+	case <-group.closed:
+	default:
+		t.Fatal("consumer group was not closed")
+	}
+}
+
+func TestSaramaConfig(t *testing.T) {
+	p := &Plugin{InitialOffset: initialOffsetEarliest, SASLMechanism: saslMechanismMSKIAM, Region: "us-east-1"}
+	cfg, err := p.saramaConfig()
+	require.NoError(t, err)
+	assert.Equal(t, sarama.OffsetOldest, cfg.Consumer.Offsets.Initial)
+	assert.True(t, cfg.Net.TLS.Enable)
+	assert.True(t, cfg.Net.SASL.Enable)
+	assert.Equal(t, sarama.SASLMechanism(sarama.SASLTypeOAuth), cfg.Net.SASL.Mechanism)
+	assert.IsType(t, &mskIAMTokenProvider{}, cfg.Net.SASL.TokenProvider)
+
+	p = &Plugin{}
+	cfg, err = p.saramaConfig()
+	require.NoError(t, err)
+	assert.Equal(t, sarama.OffsetNewest, cfg.Consumer.Offsets.Initial)
+	assert.False(t, cfg.Net.TLS.Enable)
+	assert.False(t, cfg.Net.SASL.Enable)
+
+	_, err = (&Plugin{InitialOffset: "middle"}).saramaConfig()
+	assert.Error(t, err)
+	_, err = (&Plugin{SASLMechanism: "PLAIN"}).saramaConfig()
+	assert.Error(t, err)
+}
+
+func TestStartInvalidConfig(t *testing.T) {
+	p := &Plugin{Log: testutil.Logger{}}
+	assert.Error(t, p.Start(nil))
+	p.Brokers = []string{"localhost:9092"}
+	assert.Error(t, p.Start(nil))
+}
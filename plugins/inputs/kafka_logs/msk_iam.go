@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka_logs
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+const (
+	mskSigningName  = "kafka-cluster"
+	mskAction       = "kafka-cluster:Connect"
+	mskTokenExpiry  = 15 * time.Minute
+	mskUserAgentKey = "User-Agent"
+)
+
+// mskIAMTokenProvider provides the SASL/OAUTHBEARER token for Amazon MSK IAM
+// access control. The token is a presigned kafka-cluster:Connect request.
+// See https://github.com/aws/aws-msk-iam-sasl-signer-go
+type mskIAMTokenProvider struct {
+	region string
+	signer *v4.Signer
+	now    func() time.Time
+}
+
+var _ sarama.AccessTokenProvider = (*mskIAMTokenProvider)(nil)
+
+func newMSKIAMTokenProvider(region string, creds *credentials.Credentials) *mskIAMTokenProvider {
+	return &mskIAMTokenProvider{
+		region: region,
+		signer: v4.NewSigner(creds),
+		now:    time.Now,
+	}
+}
+
+func (p *mskIAMTokenProvider) Token() (*sarama.AccessToken, error) {
+	if p.region == "" {
+		return nil, fmt.Errorf("region is required for %s", saslMechanismMSKIAM)
+	}
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://kafka.%s.amazonaws.com/", p.region), nil)
+	if err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	query.Set("Action", mskAction)
+	req.URL.RawQuery = query.Encode()
+	if _, err = p.signer.Presign(req, nil, mskSigningName, p.region, mskTokenExpiry, p.now()); err != nil {
+		return nil, fmt.Errorf("unable to sign %s token: %w", saslMechanismMSKIAM, err)
+	}
+	// the user agent is not part of the signature
+	query = req.URL.Query()
+	query.Set(mskUserAgentKey, clientID)
+	req.URL.RawQuery = query.Encode()
+	return &sarama.AccessToken{Token: base64.RawURLEncoding.EncodeToString([]byte(req.URL.String()))}, nil
+}
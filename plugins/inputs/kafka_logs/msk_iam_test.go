@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka_logs
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMSKIAMTokenProvider(t *testing.T) {
+	provider := newMSKIAMTokenProvider("us-west-2", credentials.NewStaticCredentials("AKID", "SECRET", "SESSION"))
+	provider.now = func() time.Time {
+		return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+	token, err := provider.Token()
+	require.NoError(t, err)
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token.Token)
+	require.NoError(t, err)
+	u, err := url.Parse(string(decoded))
+	require.NoError(t, err)
+	assert.Equal(t, "kafka.us-west-2.amazonaws.com", u.Host)
+	query := u.Query()
+	assert.Equal(t, mskAction, query.Get("Action"))
+	assert.Equal(t, "AWS4-HMAC-SHA256", query.Get("X-Amz-Algorithm"))
+	assert.Equal(t, "AKID/20240102/us-west-2/kafka-cluster/aws4_request", query.Get("X-Amz-Credential"))
+	assert.Equal(t, "20240102T030405Z", query.Get("X-Amz-Date"))
+	assert.Equal(t, "900", query.Get("X-Amz-Expires"))
+	assert.Equal(t, "SESSION", query.Get("X-Amz-Security-Token"))
+	assert.NotEmpty(t, query.Get("X-Amz-Signature"))
+	assert.Equal(t, clientID, query.Get(mskUserAgentKey))
+
+	provider.region = ""
+	_, err = provider.Token()
+	assert.Error(t, err)
+}
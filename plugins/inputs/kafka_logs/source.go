@@ -0,0 +1,36 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka_logs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	topicPlaceholder     = "{topic}"
+	partitionPlaceholder = "{partition}"
+)
+
+type partitionKey struct {
+	topic     string
+	partition int32
+}
+
+func (k partitionKey) String() string {
+	return fmt.Sprintf("%s/%d", k.topic, k.partition)
+}
+
+// resolveTemplate replaces the topic and partition placeholders in the log
+// group or stream name.
+func resolveTemplate(template, defaultTemplate string, key partitionKey) string {
+	if template == "" {
+		template = defaultTemplate
+	}
+	return strings.NewReplacer(
+		topicPlaceholder, key.topic,
+		partitionPlaceholder, strconv.FormatInt(int64(key.partition), 10),
+	).Replace(template)
+}
@@ -0,0 +1,128 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesis_logs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/atomicfile"
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+)
+
+// shardEnd is the checkpoint of a closed shard that was consumed to its end,
+// after which its child shards are consumed.
+const shardEnd = "SHARD_END"
+
+// shard is an open or closed shard of a stream. Closed shards are listed
+// until they expire from the retention period of the stream.
+type shard struct {
+	key     shardKey
+	parents []string
+}
+
+// checkpointStore saves the last published sequence number of the shards and
+// decides which shards the agent consumes.
+type checkpointStore interface {
+	// balance returns the listed shards the agent consumes.
+	balance(ctx context.Context, shards []shard) ([]shardKey, error)
+	// checkpoint returns the last published sequence number of the shard, or
+	// an empty string if the shard was never checkpointed.
+	checkpoint(ctx context.Context, key shardKey) (string, error)
+	// save checkpoints a shard consumed by the agent.
+	save(ctx context.Context, key shardKey, sequenceNumber string) error
+	// release gives up the shards so that other agents can take them over.
+	release(ctx context.Context, keys []shardKey)
+}
+
+// eligible returns the shards that still have to be consumed. A child shard
+// is only consumed once its parents are consumed to their end, so that the
+// records of a partition key are published in order.
+func eligible(shards []shard, finished func(shardKey) bool) []shardKey {
+	listed := make(map[shardKey]bool, len(shards))
+	for _, s := range shards {
+		listed[s.key] = true
+	}
+	var keys []shardKey
+	for _, s := range shards {
+		if finished(s.key) {
+			continue
+		}
+		ready := true
+		for _, parent := range s.parents {
+			key := shardKey{stream: s.key.stream, shard: parent}
+			// the parents that expired from the stream are not listed
+			if listed[key] && !finished(key) {
+				ready = false
+				break
+			}
+		}
+		if ready {
+			keys = append(keys, s.key)
+		}
+	}
+	return keys
+}
+
+// fileStore saves the checkpoints in a local file. The agent consumes all
+// the shards, so the streams cannot be shared with other agents.
+type fileStore struct {
+	mu          sync.Mutex
+	path        string
+	checkpoints map[string]string
+}
+
+var _ checkpointStore = (*fileStore)(nil)
+
+func newFileStore(path string) (*fileStore, error) {
+	s := &fileStore{path: path, checkpoints: map[string]string{}}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(content, &s.checkpoints); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileStore) balance(_ context.Context, shards []shard) ([]shardKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return eligible(shards, func(key shardKey) bool {
+		return s.checkpoints[key.String()] == shardEnd
+	}), nil
+}
+
+func (s *fileStore) checkpoint(_ context.Context, key shardKey) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkpoints[key.String()], nil
+}
+
+func (s *fileStore) save(_ context.Context, key shardKey, sequenceNumber string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[key.String()] = sequenceNumber
+	content, err := json.Marshal(s.checkpoints)
+	if err != nil {
+		return err
+	}
+	// replace the file so that a crash does not leave it truncated
+	return atomicfile.WriteFile(s.path, content, 0644)
+}
+
+func (s *fileStore) release(context.Context, []shardKey) {
+}
+
+func fileStorePath(folder, applicationName string) string {
+	return filepath.Join(folder, logsource.EscapeFileName(applicationName))
+}
@@ -0,0 +1,97 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesis_logs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEligible(t *testing.T) {
+	parent := shardKey{stream: "app", shard: "shardId-000000000000"}
+	left := shardKey{stream: "app", shard: "shardId-000000000001"}
+	right := shardKey{stream: "app", shard: "shardId-000000000002"}
+	merged := shardKey{stream: "app", shard: "shardId-000000000003"}
+	orphan := shardKey{stream: "app", shard: "shardId-000000000004"}
+	shards := []shard{
+		{key: parent},
+		{key: left, parents: []string{parent.shard}},
+		{key: right, parents: []string{parent.shard}},
+		{key: merged, parents: []string{left.shard, right.shard}},
+		// the parent expired from the stream
+		{key: orphan, parents: []string{"shardId-000000000099"}},
+	}
+
+	testCases := map[string]struct {
+		finished map[shardKey]bool
+		want     []shardKey
+	}{
+		"WithOpenParent": {
+			want: []shardKey{parent, orphan},
+		},
+		"WithFinishedParent": {
+			finished: map[shardKey]bool{parent: true},
+			want:     []shardKey{left, right, orphan},
+		},
+		"WithOneFinishedParentOfMerge": {
+			finished: map[shardKey]bool{parent: true, left: true},
+			want:     []shardKey{right, orphan},
+		},
+		"WithFinishedParentsOfMerge": {
+			finished: map[shardKey]bool{parent: true, left: true, right: true},
+			want:     []shardKey{merged, orphan},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := eligible(shards, func(key shardKey) bool {
+				return testCase.finished[key]
+			})
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "Amazon_CloudWatch_Kinesis_app")
+	parent := shardKey{stream: "app", shard: "shardId-000000000000"}
+	child := shardKey{stream: "app", shard: "shardId-000000000001"}
+	shards := []shard{{key: parent}, {key: child, parents: []string{parent.shard}}}
+
+	s, err := newFileStore(path)
+	require.NoError(t, err)
+	keys, err := s.balance(ctx, shards)
+	require.NoError(t, err)
+	assert.Equal(t, []shardKey{parent}, keys)
+	checkpoint, err := s.checkpoint(ctx, parent)
+	require.NoError(t, err)
+	assert.Empty(t, checkpoint)
+
+	require.NoError(t, s.save(ctx, parent, shardEnd))
+	require.NoError(t, s.save(ctx, child, "49590338271490256608559692538361571095921575989136588898"))
+
+	// the checkpoints are read back by the next agent run
+	s, err = newFileStore(path)
+	require.NoError(t, err)
+	keys, err = s.balance(ctx, shards)
+	require.NoError(t, err)
+	assert.Equal(t, []shardKey{child}, keys)
+	checkpoint, err = s.checkpoint(ctx, child)
+	require.NoError(t, err)
+	assert.Equal(t, "49590338271490256608559692538361571095921575989136588898", checkpoint)
+	assert.NoFileExists(t, path+".tmp")
+}
+
+func TestFileStoreWithCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Amazon_CloudWatch_Kinesis_app")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0644))
+	_, err := newFileStore(path)
+	assert.Error(t, err)
+}
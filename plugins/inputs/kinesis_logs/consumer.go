@@ -0,0 +1,301 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesis_logs
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+	"github.com/influxdata/telegraf"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+)
+
+// shardConsumer reads the records of a shard, from the checkpoint if there is
+// one, and pipes them to the log source of the shard. The records are read
+// with GetRecords, or pushed to the consumer of the stream with enhanced
+// fan-out if the consumer ARN is set.
+type shardConsumer struct {
+	key             shardKey
+	client          kinesisiface.KinesisAPI
+	consumerARN     string
+	initialPosition string
+	pollInterval    time.Duration
+	src             *logsource.Src
+	tracker         *sequenceTracker
+	log             telegraf.Logger
+
+	// position is the sequence number the shard is read after, empty until
+	// a record is read if the shard was not checkpointed.
+	position string
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func newShardConsumer(key shardKey, client kinesisiface.KinesisAPI, consumerARN, initialPosition string, pollInterval time.Duration, src *logsource.Src, checkpoint string, log telegraf.Logger) *shardConsumer {
+	return &shardConsumer{
+		key:             key,
+		client:          client,
+		consumerARN:     consumerARN,
+		initialPosition: initialPosition,
+		pollInterval:    pollInterval,
+		src:             src,
+		tracker:         newSequenceTracker(checkpoint),
+		log:             log,
+		position:        checkpoint,
+		done:            make(chan struct{}),
+	}
+}
+
+func (c *shardConsumer) start(ctx context.Context) {
+	ctx, c.cancel = context.WithCancel(ctx)
+	go func() {
+		defer close(c.done)
+		c.run(ctx)
+	}()
+}
+
+func (c *shardConsumer) stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	<-c.done
+}
+
+// running is false once the shard was read to its end or the consumer was
+// stopped.
+func (c *shardConsumer) running() bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+		return true
+	}
+}
+
+func (c *shardConsumer) run(ctx context.Context) {
+	for ctx.Err() == nil {
+		var ended bool
+		var err error
+		if c.consumerARN != "" {
+			ended, err = c.subscribe(ctx)
+		} else {
+			ended, err = c.poll(ctx)
+		}
+		if ended {
+			c.log.Debugf("Shard %s was read to its end", c.key)
+			c.tracker.end()
+			return
+		}
+		if err != nil && ctx.Err() == nil {
+			c.log.Errorf("Unable to read shard %s: %v", c.key, err)
+			if !logsource.Sleep(ctx, retryInterval) {
+				return
+			}
+		}
+	}
+}
+
+// poll reads the shard with GetRecords until the shard ends or an error that
+// requires a new shard iterator.
+func (c *shardConsumer) poll(ctx context.Context) (bool, error) {
+	iterator, err := c.shardIterator(ctx)
+	if err != nil {
+		return false, err
+	}
+	for iterator != nil {
+		out, err := c.client.GetRecordsWithContext(ctx, &kinesis.GetRecordsInput{ShardIterator: iterator})
+		if isErrorCode(err, kinesis.ErrCodeProvisionedThroughputExceededException) {
+			// the read throughput of the shard is shared with the other consumers
+			if !logsource.Sleep(ctx, c.pollInterval) {
+				return false, nil
+			}
+			continue
+		}
+		if isErrorCode(err, kinesis.ErrCodeExpiredIteratorException) {
+			// the iterators expire after 5 minutes, and are fetched again from the position
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if !c.publish(ctx, out.Records) {
+			return false, nil
+		}
+		iterator = out.NextShardIterator
+		if iterator != nil && !logsource.Sleep(ctx, c.pollInterval) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (c *shardConsumer) shardIterator(ctx context.Context) (*string, error) {
+	input := &kinesis.GetShardIteratorInput{
+		StreamName: aws.String(c.key.stream),
+		ShardId:    aws.String(c.key.shard),
+	}
+	if c.position != "" {
+		input.ShardIteratorType = aws.String(kinesis.ShardIteratorTypeAfterSequenceNumber)
+		input.StartingSequenceNumber = aws.String(c.position)
+	} else {
+		input.ShardIteratorType = aws.String(c.iteratorType())
+	}
+	out, err := c.client.GetShardIteratorWithContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return out.ShardIterator, nil
+}
+
+func (c *shardConsumer) iteratorType() string {
+	if c.initialPosition == initialPositionTrimHorizon {
+		return kinesis.ShardIteratorTypeTrimHorizon
+	}
+	return kinesis.ShardIteratorTypeLatest
+}
+
+// subscribe reads the shard with SubscribeToShard until the subscription
+// expires, which happens every 5 minutes, the shard ends or an error.
+func (c *shardConsumer) subscribe(ctx context.Context) (bool, error) {
+	position := &kinesis.StartingPosition{Type: aws.String(c.iteratorType())}
+	if c.position != "" {
+		position = &kinesis.StartingPosition{
+			Type:           aws.String(kinesis.ShardIteratorTypeAfterSequenceNumber),
+			SequenceNumber: aws.String(c.position),
+		}
+	}
+	out, err := c.client.SubscribeToShardWithContext(ctx, &kinesis.SubscribeToShardInput{
+		ConsumerARN:      aws.String(c.consumerARN),
+		ShardId:          aws.String(c.key.shard),
+		StartingPosition: position,
+	})
+	if err != nil {
+		return false, err
+	}
+	stream := out.GetStream()
+	defer stream.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case event, ok := <-stream.Events():
+			if !ok {
+				return false, stream.Err()
+			}
+			e, ok := event.(*kinesis.SubscribeToShardEvent)
+			if !ok {
+				continue
+			}
+			if !c.publish(ctx, e.Records) {
+				return false, nil
+			}
+			if e.ContinuationSequenceNumber == nil {
+				return true, nil
+			}
+			c.position = *e.ContinuationSequenceNumber
+		}
+	}
+}
+
+// publish hands the records to the log source. Returns false if the consumer
+// or the source was stopped.
+func (c *shardConsumer) publish(ctx context.Context, records []*kinesis.Record) bool {
+	for _, record := range records {
+		sequenceNumber := aws.StringValue(record.SequenceNumber)
+		done := c.tracker.add(sequenceNumber)
+		c.position = sequenceNumber
+		if len(record.Data) == 0 {
+			done()
+			continue
+		}
+		e := logsource.NewEvent(string(record.Data), aws.TimeValue(record.ApproximateArrivalTimestamp), done)
+		if !c.src.Publish(e, ctx.Done()) {
+			return false
+		}
+	}
+	return true
+}
+
+type pendingSequence struct {
+	id             uint64
+	sequenceNumber string
+	done           bool
+}
+
+// sequenceTracker returns the highest sequence number for which it and all
+// of the records before it are published. The records in a batch are not
+// necessarily published in order and a batch that failed to publish is never
+// done, so checkpointing the latest published record could skip over records
+// that were never published.
+type sequenceTracker struct {
+	mu         sync.Mutex
+	pending    []pendingSequence
+	next       uint64
+	checkpoint string
+	ended      bool
+}
+
+func newSequenceTracker(checkpoint string) *sequenceTracker {
+	return &sequenceTracker{checkpoint: checkpoint}
+}
+
+// add must be called in the order that the records are read. Returns the
+// function to call once the record is published.
+func (t *sequenceTracker) add(sequenceNumber string) func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	id := t.next
+	t.next++
+	t.pending = append(t.pending, pendingSequence{id: id, sequenceNumber: sequenceNumber})
+	return func() { t.done(id) }
+}
+
+func (t *sequenceTracker) done(id uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	i := sort.Search(len(t.pending), func(i int) bool {
+		return t.pending[i].id >= id
+	})
+	if i == len(t.pending) || t.pending[i].id != id {
+		return
+	}
+	t.pending[i].done = true
+	n := 0
+	for n < len(t.pending) && t.pending[n].done {
+		n++
+	}
+	if n == 0 {
+		return
+	}
+	t.checkpoint = t.pending[n-1].sequenceNumber
+	t.pending = t.pending[n:]
+	t.update()
+}
+
+// end is called once the shard was read to its end, after which the shard
+// is checkpointed as ended once all of its records are published.
+func (t *sequenceTracker) end() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ended = true
+	t.update()
+}
+
+func (t *sequenceTracker) update() {
+	if t.ended && len(t.pending) == 0 {
+		t.checkpoint = shardEnd
+	}
+}
+
+func (t *sequenceTracker) current() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.checkpoint
+}
@@ -0,0 +1,238 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesis_logs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+type mockShard struct {
+	parent  string
+	records []*kinesis.Record
+	// closed shards have no next iterator once their records are read
+	closed bool
+}
+
+// mockKinesis serves the records of the shards. The iterators are the shard
+// and the index of the next record.
+type mockKinesis struct {
+	kinesisiface.KinesisAPI
+
+	mu         sync.Mutex
+	shards     map[shardKey]*mockShard
+	iterators  []string
+	consumers  map[string]string
+	subscribed []string
+}
+
+func newRecord(sequenceNumber int, data string) *kinesis.Record {
+	return &kinesis.Record{
+		SequenceNumber:              aws.String(strconv.Itoa(sequenceNumber)),
+		Data:                        []byte(data),
+		ApproximateArrivalTimestamp: aws.Time(time.UnixMilli(int64(sequenceNumber))),
+	}
+}
+
+func (m *mockKinesis) ListShardsWithContext(_ aws.Context, input *kinesis.ListShardsInput, _ ...request.Option) (*kinesis.ListShardsOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := &kinesis.ListShardsOutput{}
+	for key, s := range m.shards {
+		if key.stream != aws.StringValue(input.StreamName) {
+			continue
+		}
+		shard := &kinesis.Shard{ShardId: aws.String(key.shard)}
+		if s.parent != "" {
+			shard.ParentShardId = aws.String(s.parent)
+		}
+		out.Shards = append(out.Shards, shard)
+	}
+	return out, nil
+}
+
+func (m *mockKinesis) GetShardIteratorWithContext(_ aws.Context, input *kinesis.GetShardIteratorInput, _ ...request.Option) (*kinesis.GetShardIteratorOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := shardKey{stream: aws.StringValue(input.StreamName), shard: aws.StringValue(input.ShardId)}
+	s, ok := m.shards[key]
+	if !ok {
+		return nil, fmt.Errorf("shard %s not found", key)
+	}
+	iteratorType := aws.StringValue(input.ShardIteratorType)
+	m.iterators = append(m.iterators, iteratorType+" "+aws.StringValue(input.StartingSequenceNumber))
+	index := 0
+	switch iteratorType {
+	case kinesis.ShardIteratorTypeLatest:
+		index = len(s.records)
+	case kinesis.ShardIteratorTypeAfterSequenceNumber:
+		for i, r := range s.records {
+			if aws.StringValue(r.SequenceNumber) == aws.StringValue(input.StartingSequenceNumber) {
+				index = i + 1
+			}
+		}
+	}
+	return &kinesis.GetShardIteratorOutput{ShardIterator: aws.String(fmt.Sprintf("%s|%d", key, index))}, nil
+}
+
+func (m *mockKinesis) GetRecordsWithContext(_ aws.Context, input *kinesis.GetRecordsInput, _ ...request.Option) (*kinesis.GetRecordsOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name, index, _ := strings.Cut(aws.StringValue(input.ShardIterator), "|")
+	key, _ := parseShardKey(name)
+	i, _ := strconv.Atoi(index)
+	s := m.shards[key]
+	out := &kinesis.GetRecordsOutput{Records: s.records[i:]}
+	if !s.closed {
+		out.NextShardIterator = aws.String(fmt.Sprintf("%s|%d", key, len(s.records)))
+	}
+	return out, nil
+}
+
+func (m *mockKinesis) SubscribeToShardWithContext(_ aws.Context, input *kinesis.SubscribeToShardInput, _ ...request.Option) (*kinesis.SubscribeToShardOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscribed = append(m.subscribed, aws.StringValue(input.StartingPosition.Type)+" "+aws.StringValue(input.StartingPosition.SequenceNumber))
+	var s *mockShard
+	for key, shard := range m.shards {
+		if key.shard == aws.StringValue(input.ShardId) {
+			s = shard
+		}
+	}
+	records := s.records
+	if aws.StringValue(input.StartingPosition.Type) == kinesis.ShardIteratorTypeAfterSequenceNumber {
+		for i, r := range s.records {
+			if aws.StringValue(r.SequenceNumber) == aws.StringValue(input.StartingPosition.SequenceNumber) {
+				records = s.records[i+1:]
+			}
+		}
+	}
+	// the subscription stays open while there are no new records
+	events := make(chan kinesis.SubscribeToShardEventStreamEvent, 1)
+	if len(records) > 0 || s.closed {
+		event := &kinesis.SubscribeToShardEvent{Records: records}
+		if !s.closed {
+			event.ContinuationSequenceNumber = s.records[len(s.records)-1].SequenceNumber
+		}
+		events <- event
+		close(events)
+	}
+	stream := kinesis.NewSubscribeToShardEventStream(func(es *kinesis.SubscribeToShardEventStream) {
+		es.Reader = &mockEventStreamReader{events: events}
+		es.StreamCloser = io.NopCloser(nil)
+	})
+	out := &kinesis.SubscribeToShardOutput{}
+	out.EventStream = stream
+	return out, nil
+}
+
+type mockEventStreamReader struct {
+	events chan kinesis.SubscribeToShardEventStreamEvent
+}
+
+func (r *mockEventStreamReader) Events() <-chan kinesis.SubscribeToShardEventStreamEvent {
+	return r.events
+}
+
+func (r *mockEventStreamReader) Close() error {
+	return nil
+}
+
+func (r *mockEventStreamReader) Err() error {
+	return nil
+}
+
+// publishAll marks the events as published as they are read.
+func publishAll(src *logsource.Src) chan logs.LogEvent {
+	events := make(chan logs.LogEvent, 10)
+	src.SetOutput(func(e logs.LogEvent) {
+		if e != nil {
+			e.Done()
+			events <- e
+		}
+	})
+	return events
+}
+
+func TestShardConsumerPoll(t *testing.T) {
+	key := shardKey{stream: "app", shard: "shardId-000000000000"}
+	client := &mockKinesis{shards: map[shardKey]*mockShard{
+		key: {records: []*kinesis.Record{newRecord(1, "a"), newRecord(2, ""), newRecord(3, "c")}, closed: true},
+	}}
+	src := logsource.New("app", "app_shard", "kinesis:"+key.String(), "cloudwatchlogs", "", -1)
+	defer src.Stop()
+	events := publishAll(src)
+
+	c := newShardConsumer(key, client, "", initialPositionTrimHorizon, time.Millisecond, src, "1", testutil.Logger{})
+	c.start(context.Background())
+	defer c.stop()
+
+	e := <-events
+	assert.Equal(t, "c", e.Message())
+	assert.Equal(t, time.UnixMilli(3), e.Time())
+	assert.Eventually(t, func() bool { return !c.running() }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, shardEnd, c.tracker.current())
+	assert.Equal(t, []string{"AFTER_SEQUENCE_NUMBER 1"}, client.iterators)
+}
+
+func TestShardConsumerSubscribe(t *testing.T) {
+	key := shardKey{stream: "app", shard: "shardId-000000000000"}
+	client := &mockKinesis{shards: map[shardKey]*mockShard{
+		key: {records: []*kinesis.Record{newRecord(1, "a"), newRecord(2, "b")}},
+	}}
+	src := logsource.New("app", "app_shard", "kinesis:"+key.String(), "cloudwatchlogs", "", -1)
+	defer src.Stop()
+	events := publishAll(src)
+
+	c := newShardConsumer(key, client, "arn:aws:kinesis:us-east-1:123456789012:stream/app/consumer/agent:1", initialPositionLatest, time.Millisecond, src, "", testutil.Logger{})
+	c.start(context.Background())
+
+	assert.Equal(t, "a", (<-events).Message())
+	assert.Equal(t, "b", (<-events).Message())
+	assert.Eventually(t, func() bool { return c.tracker.current() == "2" }, time.Second, 10*time.Millisecond)
+	// resubscribes after the continuation sequence number once the subscription expires
+	assert.Eventually(t, func() bool {
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		return len(client.subscribed) == 2
+	}, time.Second, 10*time.Millisecond)
+	c.stop()
+	assert.Equal(t, []string{"LATEST ", "AFTER_SEQUENCE_NUMBER 2"}, client.subscribed)
+}
+
+func TestSequenceTracker(t *testing.T) {
+	tracker := newSequenceTracker("10")
+	done11 := tracker.add("11")
+	done12 := tracker.add("12")
+	done13 := tracker.add("13")
+
+	done12()
+	assert.Equal(t, "10", tracker.current())
+	done11()
+	assert.Equal(t, "12", tracker.current())
+
+	tracker.end()
+	assert.Equal(t, "12", tracker.current())
+	done13()
+	assert.Equal(t, shardEnd, tracker.current())
+	// done is idempotent
+	done13()
+	assert.Equal(t, shardEnd, tracker.current())
+}
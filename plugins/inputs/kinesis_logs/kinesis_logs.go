@@ -0,0 +1,472 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesis_logs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/internal/logscommon"
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+const (
+	defaultApplicationName = "amazon-cloudwatch-agent"
+
+	initialPositionLatest      = "latest"
+	initialPositionTrimHorizon = "trim_horizon"
+
+	checkpointStoreDynamoDB = "dynamodb"
+	checkpointStoreFile     = "file"
+
+	defaultLogGroupName  = streamPlaceholder
+	defaultLogStreamName = streamPlaceholder + "_" + shardPlaceholder
+
+	// syncInterval is how often the shards are listed, the leases renewed
+	// and the shards checkpointed.
+	syncInterval  = 10 * time.Second
+	leaseDuration = 3 * syncInterval
+	pollInterval  = time.Second
+	retryInterval = 10 * time.Second
+	// releaseTimeout bounds the last checkpoints and the release of the
+	// leases when the agent stops.
+	releaseTimeout = 5 * time.Second
+)
+
+// newKinesisClient and newDynamoDBClient are overridden in tests.
+var (
+	newKinesisClient = func(p client.ConfigProvider, cfg *aws.Config) kinesisiface.KinesisAPI {
+		return kinesis.New(p, cfg)
+	}
+	newDynamoDBClient = func(p client.ConfigProvider, cfg *aws.Config) dynamodbiface.DynamoDBAPI {
+		return dynamodb.New(p, cfg)
+	}
+)
+
+type StreamConfig struct {
+	StreamNames   []string `toml:"stream_names"`
+	LogGroupName  string   `toml:"log_group_name"`
+	LogStreamName string   `toml:"log_stream_name"`
+	LogGroupClass string   `toml:"log_group_class"`
+	Destination   string   `toml:"destination"`
+	Retention     int      `toml:"retention_in_days"`
+}
+
+type Plugin struct {
+	// ApplicationName identifies the agents sharing the streams. It names the
+	// lease table and the enhanced fan-out consumers of the streams.
+	ApplicationName string         `toml:"application_name"`
+	InitialPosition string         `toml:"initial_position"`
+	EnhancedFanOut  bool           `toml:"enhanced_fan_out"`
+	CheckpointStore string         `toml:"checkpoint_store"`
+	LeaseTableName  string         `toml:"lease_table_name"`
+	FileStateFolder string         `toml:"file_state_folder"`
+	Streams         []StreamConfig `toml:"stream_config"`
+	Destination     string         `toml:"destination"`
+
+	Region    string `toml:"region"`
+	AccessKey string `toml:"access_key"`
+	SecretKey string `toml:"secret_key"`
+	RoleARN   string `toml:"role_arn"`
+	Profile   string `toml:"profile"`
+	Filename  string `toml:"shared_credential_file"`
+	Token     string `toml:"token"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	client        kinesisiface.KinesisAPI
+	store         checkpointStore
+	streamConfigs map[string]*StreamConfig
+	// consumerARNs are the enhanced fan-out consumers of the streams.
+	consumerARNs map[string]string
+
+	mu         sync.Mutex
+	sources    map[shardKey]*logsource.Src
+	newSources []logs.LogSrc
+	consumers  map[shardKey]*shardConsumer
+	// saved are the last checkpoints saved for the consumed shards.
+	saved  map[shardKey]string
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ logs.LogCollection = (*Plugin)(nil)
+
+func (p *Plugin) Description() string {
+	return "A plugin to collect log events from Kinesis data streams"
+}
+
+func (p *Plugin) SampleConfig() string {
+	return `
+	application_name = "amazon-cloudwatch-agent"
+	initial_position = "latest"
+	enhanced_fan_out = false
+	## Either "dynamodb" to share the streams between agents, or "file".
+	checkpoint_store = "dynamodb"
+	destination = "cloudwatchlogs"
+
+	[[inputs.kinesis_logs.stream_config]]
+	stream_names = ["appliance-logs"]
+	log_group_name = "{stream}"
+	log_stream_name = "{stream}_{shard}"
+	`
+}
+
+func (p *Plugin) Gather(acc telegraf.Accumulator) error {
+	return nil
+}
+
+func (p *Plugin) FindLogSrc() []logs.LogSrc {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	srcs := p.newSources
+	p.newSources = nil
+	return srcs
+}
+
+// Start consumes from the configured streams in the background since the
+// lease table and the stream consumers can take a while to be created and
+// should not hold up the other log collections.
+func (p *Plugin) Start(acc telegraf.Accumulator) error {
+	streams := p.initStreams()
+	if len(streams) == 0 {
+		return errors.New("no streams configured")
+	}
+	if p.ApplicationName == "" {
+		p.ApplicationName = defaultApplicationName
+	}
+	switch p.InitialPosition {
+	case "":
+		p.InitialPosition = initialPositionLatest
+	case initialPositionLatest, initialPositionTrimHorizon:
+	default:
+		return fmt.Errorf("invalid initial_position: %s", p.InitialPosition)
+	}
+	sess := p.credentialConfig().Credentials()
+	cfg := &aws.Config{
+		LogLevel: configaws.SDKLogLevel(),
+		Logger:   configaws.SDKLogger{},
+	}
+	p.client = newKinesisClient(sess, cfg)
+	switch p.CheckpointStore {
+	case "", checkpointStoreDynamoDB:
+		table := p.LeaseTableName
+		if table == "" {
+			table = p.ApplicationName
+		}
+		owner, err := workerID()
+		if err != nil {
+			return err
+		}
+		p.store = newDynamoDBStore(newDynamoDBClient(sess, cfg), table, owner, leaseDuration, p.Log)
+	case checkpointStoreFile:
+		if p.FileStateFolder == "" {
+			return errors.New("empty file_state_folder")
+		}
+		if err := os.MkdirAll(p.FileStateFolder, 0755); err != nil {
+			return err
+		}
+		store, err := newFileStore(fileStorePath(p.FileStateFolder, logscommon.KinesisPrefix+p.ApplicationName))
+		if err != nil {
+			return err
+		}
+		p.store = store
+	default:
+		return fmt.Errorf("invalid checkpoint_store: %s", p.CheckpointStore)
+	}
+	p.consumerARNs = make(map[string]string)
+	p.consumers = make(map[shardKey]*shardConsumer)
+	p.saved = make(map[shardKey]string)
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.wg.Add(1)
+	go p.run(ctx, streams)
+	return nil
+}
+
+func (p *Plugin) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, src := range p.sources {
+		src.Stop()
+	}
+}
+
+// initStreams maps each stream to its config. If the same stream is
+// configured more than once, the first config is used.
+func (p *Plugin) initStreams() []string {
+	p.streamConfigs = make(map[string]*StreamConfig)
+	p.sources = make(map[shardKey]*logsource.Src)
+	var streams []string
+	for i := range p.Streams {
+		sc := &p.Streams[i]
+		for _, stream := range sc.StreamNames {
+			if _, ok := p.streamConfigs[stream]; ok {
+				p.Log.Warnf("Stream %s is configured more than once, only the first config is used", stream)
+				continue
+			}
+			p.streamConfigs[stream] = sc
+			streams = append(streams, stream)
+		}
+	}
+	return streams
+}
+
+func (p *Plugin) credentialConfig() *configaws.CredentialConfig {
+	return &configaws.CredentialConfig{
+		Region:    p.Region,
+		AccessKey: p.AccessKey,
+		SecretKey: p.SecretKey,
+		RoleARN:   p.RoleARN,
+		Profile:   p.Profile,
+		Filename:  p.Filename,
+		Token:     p.Token,
+	}
+}
+
+func (p *Plugin) run(ctx context.Context, streams []string) {
+	defer p.wg.Done()
+	ticker := time.NewTicker(syncInterval)
+	defer ticker.Stop()
+	for {
+		p.sync(ctx, streams)
+		select {
+		case <-ctx.Done():
+			p.shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync checkpoints the consumed shards, then balances the shards with the
+// other agents and starts or stops the consumers of the shards accordingly.
+func (p *Plugin) sync(ctx context.Context, streams []string) {
+	p.checkpointAll(ctx)
+	var shards []shard
+	for _, stream := range streams {
+		if p.EnhancedFanOut {
+			if _, ok := p.consumerARNs[stream]; !ok {
+				arn, err := p.registerConsumer(ctx, stream)
+				if err != nil {
+					if ctx.Err() == nil {
+						p.Log.Errorf("Unable to register the enhanced fan-out consumer of stream %s: %v", stream, err)
+					}
+					continue
+				}
+				if arn == "" {
+					continue
+				}
+				p.consumerARNs[stream] = arn
+			}
+		}
+		streamShards, err := p.listShards(ctx, stream)
+		if err != nil {
+			if ctx.Err() == nil {
+				p.Log.Errorf("Unable to list the shards of stream %s: %v", stream, err)
+			}
+			continue
+		}
+		shards = append(shards, streamShards...)
+	}
+	owned, err := p.store.balance(ctx, shards)
+	if err != nil {
+		if ctx.Err() == nil {
+			p.Log.Errorf("Unable to balance the shards: %v", err)
+		}
+		return
+	}
+	ownedSet := make(map[shardKey]bool, len(owned))
+	for _, key := range owned {
+		ownedSet[key] = true
+	}
+	for key, c := range p.consumers {
+		if !ownedSet[key] || (!c.running() && p.saved[key] == shardEnd) {
+			// the consumers that read their shard to its end are done once
+			// the end is checkpointed
+			c.stop()
+			delete(p.consumers, key)
+			delete(p.saved, key)
+		}
+	}
+	for _, key := range owned {
+		if _, ok := p.consumers[key]; ok {
+			continue
+		}
+		checkpoint, err := p.store.checkpoint(ctx, key)
+		if err != nil {
+			if ctx.Err() == nil {
+				p.Log.Errorf("Unable to get the checkpoint of shard %s: %v", key, err)
+			}
+			continue
+		}
+		if checkpoint == shardEnd {
+			continue
+		}
+		c := newShardConsumer(key, p.client, p.consumerARNs[key.stream], p.InitialPosition, pollInterval, p.source(key), checkpoint, p.Log)
+		p.consumers[key] = c
+		p.saved[key] = checkpoint
+		c.start(ctx)
+	}
+}
+
+// checkpointAll saves the checkpoints that changed since the last sync.
+func (p *Plugin) checkpointAll(ctx context.Context) {
+	for key, c := range p.consumers {
+		checkpoint := c.tracker.current()
+		if checkpoint == "" || checkpoint == p.saved[key] {
+			continue
+		}
+		if err := p.store.save(ctx, key, checkpoint); err != nil {
+			if errors.Is(err, errLeaseLost) {
+				p.Log.Infof("Lease of shard %s was taken over by another agent", key)
+			} else if ctx.Err() == nil {
+				p.Log.Errorf("Unable to checkpoint shard %s: %v", key, err)
+			}
+			continue
+		}
+		p.saved[key] = checkpoint
+	}
+}
+
+// shutdown stops the consumers, then saves their last checkpoints and
+// releases their leases so that the other agents take over their shards
+// right away. The records that are published afterward are consumed again.
+func (p *Plugin) shutdown() {
+	keys := make([]shardKey, 0, len(p.consumers))
+	for key, c := range p.consumers {
+		c.stop()
+		keys = append(keys, key)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+	p.checkpointAll(ctx)
+	p.store.release(ctx, keys)
+}
+
+func (p *Plugin) listShards(ctx context.Context, stream string) ([]shard, error) {
+	var shards []shard
+	input := &kinesis.ListShardsInput{StreamName: aws.String(stream)}
+	for {
+		out, err := p.client.ListShardsWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range out.Shards {
+			sh := shard{key: shardKey{stream: stream, shard: aws.StringValue(s.ShardId)}}
+			if s.ParentShardId != nil {
+				sh.parents = append(sh.parents, *s.ParentShardId)
+			}
+			if s.AdjacentParentShardId != nil {
+				sh.parents = append(sh.parents, *s.AdjacentParentShardId)
+			}
+			shards = append(shards, sh)
+		}
+		if out.NextToken == nil {
+			return shards, nil
+		}
+		// the stream name cannot be set with the next token
+		input = &kinesis.ListShardsInput{NextToken: out.NextToken}
+	}
+}
+
+// registerConsumer returns the ARN of the enhanced fan-out consumer of the
+// stream named after the application, registering it if needed. Returns an
+// empty ARN until the consumer is active.
+func (p *Plugin) registerConsumer(ctx context.Context, stream string) (string, error) {
+	summary, err := p.client.DescribeStreamSummaryWithContext(ctx, &kinesis.DescribeStreamSummaryInput{StreamName: aws.String(stream)})
+	if err != nil {
+		return "", err
+	}
+	streamARN := summary.StreamDescriptionSummary.StreamARN
+	out, err := p.client.DescribeStreamConsumerWithContext(ctx, &kinesis.DescribeStreamConsumerInput{
+		StreamARN:    streamARN,
+		ConsumerName: aws.String(p.ApplicationName),
+	})
+	if isErrorCode(err, kinesis.ErrCodeResourceNotFoundException) {
+		p.Log.Infof("Registering the enhanced fan-out consumer %s of stream %s", p.ApplicationName, stream)
+		_, err = p.client.RegisterStreamConsumerWithContext(ctx, &kinesis.RegisterStreamConsumerInput{
+			StreamARN:    streamARN,
+			ConsumerName: aws.String(p.ApplicationName),
+		})
+		// another agent registered it at the same time
+		if err == nil || isErrorCode(err, kinesis.ErrCodeResourceInUseException) {
+			return "", nil
+		}
+		return "", err
+	}
+	if err != nil {
+		return "", err
+	}
+	if aws.StringValue(out.ConsumerDescription.ConsumerStatus) != kinesis.ConsumerStatusActive {
+		return "", nil
+	}
+	return aws.StringValue(out.ConsumerDescription.ConsumerARN), nil
+}
+
+// source returns the log source for the shard. The sources outlive the
+// consumers so that each shard is only piped to its destination once.
+func (p *Plugin) source(key shardKey) *logsource.Src {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if src, ok := p.sources[key]; ok {
+		return src
+	}
+	sc := p.streamConfigs[key.stream]
+	destination := sc.Destination
+	if destination == "" {
+		destination = p.Destination
+	}
+	src := logsource.New(
+		resolveTemplate(sc.LogGroupName, defaultLogGroupName, key),
+		resolveTemplate(sc.LogStreamName, defaultLogStreamName, key),
+		"kinesis:"+key.String(),
+		destination,
+		sc.LogGroupClass,
+		sc.Retention,
+	)
+	p.sources[key] = src
+	p.newSources = append(p.newSources, src)
+	return src
+}
+
+// workerID identifies the agent in the lease table. It changes on every
+// start, so the leases of a previous run are only taken back once released
+// or expired.
+func workerID() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+	suffix := make([]byte, 4)
+	if _, err = rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return hostname + "-" + hex.EncodeToString(suffix), nil
+}
+
+func init() {
+	inputs.Add("kinesis_logs", func() telegraf.Input { return &Plugin{} })
+}
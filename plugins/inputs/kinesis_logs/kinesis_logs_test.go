@@ -0,0 +1,245 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesis_logs
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+func (m *mockKinesis) DescribeStreamSummaryWithContext(_ aws.Context, input *kinesis.DescribeStreamSummaryInput, _ ...request.Option) (*kinesis.DescribeStreamSummaryOutput, error) {
+	return &kinesis.DescribeStreamSummaryOutput{StreamDescriptionSummary: &kinesis.StreamDescriptionSummary{
+		StreamARN: aws.String("arn:aws:kinesis:us-east-1:123456789012:stream/" + aws.StringValue(input.StreamName)),
+	}}, nil
+}
+
+func (m *mockKinesis) DescribeStreamConsumerWithContext(_ aws.Context, input *kinesis.DescribeStreamConsumerInput, _ ...request.Option) (*kinesis.DescribeStreamConsumerOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	arn := aws.StringValue(input.StreamARN) + "/consumer/" + aws.StringValue(input.ConsumerName)
+	status, ok := m.consumers[arn]
+	if !ok {
+		return nil, awserr.New(kinesis.ErrCodeResourceNotFoundException, "consumer not found", nil)
+	}
+	// the consumer is active once described after its registration
+	m.consumers[arn] = kinesis.ConsumerStatusActive
+	return &kinesis.DescribeStreamConsumerOutput{ConsumerDescription: &kinesis.ConsumerDescription{
+		ConsumerARN:    aws.String(arn),
+		ConsumerStatus: aws.String(status),
+	}}, nil
+}
+
+func (m *mockKinesis) RegisterStreamConsumerWithContext(_ aws.Context, input *kinesis.RegisterStreamConsumerInput, _ ...request.Option) (*kinesis.RegisterStreamConsumerOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.consumers[aws.StringValue(input.StreamARN)+"/consumer/"+aws.StringValue(input.ConsumerName)] = kinesis.ConsumerStatusCreating
+	return &kinesis.RegisterStreamConsumerOutput{}, nil
+}
+
+func collectEvents(t *testing.T, p *Plugin, want int) (map[string]logs.LogSrc, map[string][]string) {
+	t.Helper()
+	srcs := map[string]logs.LogSrc{}
+	events := make(chan [2]string, 10)
+	messages := map[string][]string{}
+	require.Eventually(t, func() bool {
+		for _, src := range p.FindLogSrc() {
+			description := src.Description()
+			srcs[description] = src
+			src.SetOutput(func(e logs.LogEvent) {
+				if e != nil {
+					e.Done()
+					events <- [2]string{description, e.Message()}
+				}
+			})
+		}
+		for {
+			select {
+			case e := <-events:
+				messages[e[0]] = append(messages[e[0]], e[1])
+			default:
+				n := 0
+				for _, m := range messages {
+					n += len(m)
+				}
+				return n == want
+			}
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+	return srcs, messages
+}
+
+func TestPlugin(t *testing.T) {
+	app0 := shardKey{stream: "app", shard: "shardId-000000000000"}
+	app1 := shardKey{stream: "app", shard: "shardId-000000000001"}
+	audit0 := shardKey{stream: "audit", shard: "shardId-000000000000"}
+	kinesisClient := &mockKinesis{shards: map[shardKey]*mockShard{
+		app0:   {records: []*kinesis.Record{newRecord(1, "app 0")}},
+		app1:   {records: []*kinesis.Record{newRecord(2, "app 1"), newRecord(3, "app 1 again")}},
+		audit0: {records: []*kinesis.Record{newRecord(4, "audit 0")}},
+	}}
+	defer func(original func(client.ConfigProvider, *aws.Config) kinesisiface.KinesisAPI) {
+		newKinesisClient = original
+	}(newKinesisClient)
+	newKinesisClient = func(client.ConfigProvider, *aws.Config) kinesisiface.KinesisAPI {
+		return kinesisClient
+	}
+
+	folder := t.TempDir()
+	p := &Plugin{
+		InitialPosition: initialPositionTrimHorizon,
+		CheckpointStore: checkpointStoreFile,
+		FileStateFolder: folder,
+		Destination:     "cloudwatchlogs",
+		Streams: []StreamConfig{
+			{StreamNames: []string{"app"}, LogGroupName: "/kinesis/{stream}", Retention: 7},
+			{StreamNames: []string{"audit", "app"}, LogGroupName: "audit", LogStreamName: "shard-{shard}", LogGroupClass: "INFREQUENT_ACCESS"},
+		},
+		Region:    "us-east-1",
+		AccessKey: "access_key",
+		SecretKey: "secret_key",
+		Log:       testutil.Logger{},
+	}
+	require.NoError(t, p.Start(nil))
+
+	srcs, messages := collectEvents(t, p, 4)
+	require.Len(t, srcs, 3)
+	src := srcs["kinesis:app/shardId-000000000001"]
+	require.NotNil(t, src)
+	assert.Equal(t, "/kinesis/app", src.Group())
+	assert.Equal(t, "app_shardId-000000000001", src.Stream())
+	assert.Equal(t, "cloudwatchlogs", src.Destination())
+	assert.Equal(t, 7, src.Retention())
+	assert.Equal(t, []string{"app 1", "app 1 again"}, messages["kinesis:app/shardId-000000000001"])
+	src = srcs["kinesis:audit/shardId-000000000000"]
+	require.NotNil(t, src)
+	assert.Equal(t, "audit", src.Group())
+	assert.Equal(t, "shard-shardId-000000000000", src.Stream())
+	assert.Equal(t, "INFREQUENT_ACCESS", src.Class())
+
+	p.Stop()
+
+	// the last checkpoints are saved on stop
+	content, err := os.ReadFile(filepath.Join(folder, "Amazon_CloudWatch_Kinesis_amazon-cloudwatch-agent"))
+	require.NoError(t, err)
+	var checkpoints map[string]string
+	require.NoError(t, json.Unmarshal(content, &checkpoints))
+	assert.Equal(t, map[string]string{
+		"app/shardId-000000000000":   "1",
+		"app/shardId-000000000001":   "3",
+		"audit/shardId-000000000000": "4",
+	}, checkpoints)
+}
+
+func TestPluginSync(t *testing.T) {
+	parent := shardKey{stream: "app", shard: "shardId-000000000000"}
+	child := shardKey{stream: "app", shard: "shardId-000000000001"}
+	client := &mockKinesis{
+		shards: map[shardKey]*mockShard{
+			parent: {records: []*kinesis.Record{newRecord(1, "parent")}, closed: true},
+			child:  {parent: parent.shard, records: []*kinesis.Record{newRecord(2, "child")}},
+		},
+		consumers: map[string]string{},
+	}
+	store, err := newFileStore(filepath.Join(t.TempDir(), "state"))
+	require.NoError(t, err)
+	p := &Plugin{
+		ApplicationName: defaultApplicationName,
+		InitialPosition: initialPositionTrimHorizon,
+		EnhancedFanOut:  true,
+		Streams:         []StreamConfig{{StreamNames: []string{"app"}}},
+		Log:             testutil.Logger{},
+		client:          client,
+		store:           store,
+		consumerARNs:    map[string]string{},
+		consumers:       map[shardKey]*shardConsumer{},
+		saved:           map[shardKey]string{},
+	}
+	streams := p.initStreams()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the shards are not consumed until the consumer is registered and active
+	p.sync(ctx, streams)
+	assert.Empty(t, p.consumers)
+	assert.Equal(t, map[string]string{
+		"arn:aws:kinesis:us-east-1:123456789012:stream/app/consumer/amazon-cloudwatch-agent": kinesis.ConsumerStatusCreating,
+	}, client.consumers)
+	p.sync(ctx, streams)
+	assert.Empty(t, p.consumerARNs)
+	p.sync(ctx, streams)
+	assert.Equal(t, map[string]string{
+		"app": "arn:aws:kinesis:us-east-1:123456789012:stream/app/consumer/amazon-cloudwatch-agent",
+	}, p.consumerARNs)
+	require.Contains(t, p.consumers, parent)
+	assert.NotContains(t, p.consumers, child)
+
+	// the child shard is consumed once the parent is checkpointed to its end
+	_, messages := collectEvents(t, p, 1)
+	assert.Equal(t, []string{"parent"}, messages["kinesis:app/shardId-000000000000"])
+	require.Eventually(t, func() bool { return !p.consumers[parent].running() }, time.Second, 10*time.Millisecond)
+	p.sync(ctx, streams)
+	checkpoint, err := store.checkpoint(ctx, parent)
+	require.NoError(t, err)
+	assert.Equal(t, shardEnd, checkpoint)
+	assert.NotContains(t, p.consumers, parent)
+	require.Contains(t, p.consumers, child)
+	_, messages = collectEvents(t, p, 1)
+	assert.Equal(t, []string{"child"}, messages["kinesis:app/shardId-000000000001"])
+
+	cancel()
+	p.shutdown()
+	checkpoint, err = store.checkpoint(context.Background(), child)
+	require.NoError(t, err)
+	assert.Equal(t, "2", checkpoint)
+}
+
+func TestPluginStart(t *testing.T) {
+	testCases := map[string]struct {
+		plugin  *Plugin
+		wantErr string
+	}{
+		"WithoutStreams": {
+			plugin:  &Plugin{},
+			wantErr: "no streams configured",
+		},
+		"WithInvalidInitialPosition": {
+			plugin:  &Plugin{InitialPosition: "earliest", Streams: []StreamConfig{{StreamNames: []string{"app"}}}},
+			wantErr: "invalid initial_position: earliest",
+		},
+		"WithInvalidCheckpointStore": {
+			plugin:  &Plugin{CheckpointStore: "redis", Streams: []StreamConfig{{StreamNames: []string{"app"}}}},
+			wantErr: "invalid checkpoint_store: redis",
+		},
+		"WithoutFileStateFolder": {
+			plugin:  &Plugin{CheckpointStore: checkpointStoreFile, Streams: []StreamConfig{{StreamNames: []string{"app"}}}},
+			wantErr: "empty file_state_folder",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			p := testCase.plugin
+			p.Region = "us-east-1"
+			p.AccessKey = "access_key"
+			p.SecretKey = "secret_key"
+			p.Log = testutil.Logger{}
+			assert.EqualError(t, p.Start(nil), testCase.wantErr)
+		})
+	}
+}
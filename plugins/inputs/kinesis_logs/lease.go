@@ -0,0 +1,322 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesis_logs
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	attrShardKey   = "shard_key"
+	attrOwner      = "lease_owner"
+	attrExpiry     = "lease_expiry"
+	attrCheckpoint = "checkpoint"
+
+	updateLease      = "SET " + attrOwner + " = :owner, " + attrExpiry + " = :expiry"
+	updateCheckpoint = "SET " + attrCheckpoint + " = :checkpoint"
+	removeLease      = "REMOVE " + attrOwner + ", " + attrExpiry
+	// conditionOwned is true for the leases held by the agent.
+	conditionOwned = attrOwner + " = :owner"
+	// conditionAvailable is true for the leases that are not held or expired.
+	conditionAvailable = "attribute_not_exists(" + attrOwner + ") OR " + attrExpiry + " < :now"
+	// conditionHeldBy is true for the leases still held by the other agent.
+	conditionHeldBy = attrOwner + " = :previous"
+)
+
+// lease is the item of a shard in the lease table.
+type lease struct {
+	key        shardKey
+	owner      string
+	expiry     time.Time
+	checkpoint string
+}
+
+// dynamoDBStore saves the checkpoints in a DynamoDB table, which also holds
+// the leases of the shards so that the agents sharing the streams each
+// consume a share of the shards. The leases are renewed on every balance and
+// expire if the agent stops renewing them.
+type dynamoDBStore struct {
+	client        dynamodbiface.DynamoDBAPI
+	table         string
+	owner         string
+	leaseDuration time.Duration
+	log           telegraf.Logger
+	now           func() time.Time
+
+	tableReady bool
+}
+
+var _ checkpointStore = (*dynamoDBStore)(nil)
+
+func newDynamoDBStore(client dynamodbiface.DynamoDBAPI, table, owner string, leaseDuration time.Duration, log telegraf.Logger) *dynamoDBStore {
+	return &dynamoDBStore{
+		client:        client,
+		table:         table,
+		owner:         owner,
+		leaseDuration: leaseDuration,
+		log:           log,
+		now:           time.Now,
+	}
+}
+
+// ensureTable creates the lease table if it does not exist. The agents
+// sharing the table may create it at the same time.
+func (s *dynamoDBStore) ensureTable(ctx context.Context) error {
+	if s.tableReady {
+		return nil
+	}
+	_, err := s.client.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(s.table)})
+	if isErrorCode(err, dynamodb.ErrCodeResourceNotFoundException) {
+		s.log.Infof("Creating the lease table %s", s.table)
+		_, err = s.client.CreateTableWithContext(ctx, &dynamodb.CreateTableInput{
+			TableName:   aws.String(s.table),
+			BillingMode: aws.String(dynamodb.BillingModePayPerRequest),
+			AttributeDefinitions: []*dynamodb.AttributeDefinition{
+				{AttributeName: aws.String(attrShardKey), AttributeType: aws.String(dynamodb.ScalarAttributeTypeS)},
+			},
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String(attrShardKey), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			},
+		})
+		if err != nil && !isErrorCode(err, dynamodb.ErrCodeResourceInUseException) {
+			return err
+		}
+		err = s.client.WaitUntilTableExistsWithContext(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(s.table)})
+	}
+	if err != nil {
+		return err
+	}
+	s.tableReady = true
+	return nil
+}
+
+func (s *dynamoDBStore) leases(ctx context.Context) (map[shardKey]lease, error) {
+	leases := map[shardKey]lease{}
+	err := s.client.ScanPagesWithContext(ctx, &dynamodb.ScanInput{
+		TableName:      aws.String(s.table),
+		ConsistentRead: aws.Bool(true),
+	}, func(out *dynamodb.ScanOutput, _ bool) bool {
+		for _, item := range out.Items {
+			if l, ok := parseLease(item); ok {
+				leases[l.key] = l
+			}
+		}
+		return true
+	})
+	return leases, err
+}
+
+func parseLease(item map[string]*dynamodb.AttributeValue) (lease, bool) {
+	attr, ok := item[attrShardKey]
+	if !ok || attr.S == nil {
+		return lease{}, false
+	}
+	key, ok := parseShardKey(*attr.S)
+	if !ok {
+		return lease{}, false
+	}
+	l := lease{key: key}
+	if attr, ok = item[attrOwner]; ok {
+		l.owner = aws.StringValue(attr.S)
+	}
+	if attr, ok = item[attrExpiry]; ok {
+		if ms, err := strconv.ParseInt(aws.StringValue(attr.N), 10, 64); err == nil {
+			l.expiry = time.UnixMilli(ms)
+		}
+	}
+	if attr, ok = item[attrCheckpoint]; ok {
+		l.checkpoint = aws.StringValue(attr.S)
+	}
+	return l, true
+}
+
+func (s *dynamoDBStore) balance(ctx context.Context, shards []shard) ([]shardKey, error) {
+	if err := s.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	leases, err := s.leases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys := eligible(shards, func(key shardKey) bool {
+		return leases[key].checkpoint == shardEnd
+	})
+	now := s.now()
+	owned, take, steal := planLeases(keys, leases, s.owner, now)
+	expiry := now.Add(s.leaseDuration)
+
+	var result []shardKey
+	for _, key := range owned {
+		ok, err := s.updateLease(ctx, key, expiry, conditionOwned, nil)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, key)
+		} else {
+			s.log.Infof("Lease of shard %s was taken over by another agent", key)
+		}
+	}
+	for _, key := range take {
+		ok, err := s.updateLease(ctx, key, expiry, conditionAvailable, map[string]*dynamodb.AttributeValue{
+			":now": {N: aws.String(strconv.FormatInt(now.UnixMilli(), 10))},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			s.log.Debugf("Took the lease of shard %s", key)
+			result = append(result, key)
+		}
+	}
+	if steal != nil {
+		ok, err := s.updateLease(ctx, steal.key, expiry, conditionHeldBy, map[string]*dynamodb.AttributeValue{
+			":previous": {S: aws.String(steal.owner)},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			s.log.Infof("Took over the lease of shard %s from %s to balance the shards", steal.key, steal.owner)
+			result = append(result, steal.key)
+		}
+	}
+	return result, nil
+}
+
+// updateLease sets the agent as the owner of the lease until the expiry if
+// the condition holds. Returns false if the condition does not hold.
+func (s *dynamoDBStore) updateLease(ctx context.Context, key shardKey, expiry time.Time, condition string, values map[string]*dynamodb.AttributeValue) (bool, error) {
+	if values == nil {
+		values = map[string]*dynamodb.AttributeValue{}
+	}
+	values[":owner"] = &dynamodb.AttributeValue{S: aws.String(s.owner)}
+	values[":expiry"] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(expiry.UnixMilli(), 10))}
+	_, err := s.client.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(s.table),
+		Key:                       s.itemKey(key),
+		UpdateExpression:          aws.String(updateLease),
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeValues: values,
+	})
+	if isErrorCode(err, dynamodb.ErrCodeConditionalCheckFailedException) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *dynamoDBStore) checkpoint(ctx context.Context, key shardKey) (string, error) {
+	out, err := s.client.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.table),
+		Key:            s.itemKey(key),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return "", err
+	}
+	if attr, ok := out.Item[attrCheckpoint]; ok {
+		return aws.StringValue(attr.S), nil
+	}
+	return "", nil
+}
+
+func (s *dynamoDBStore) save(ctx context.Context, key shardKey, sequenceNumber string) error {
+	_, err := s.client.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(s.table),
+		Key:                 s.itemKey(key),
+		UpdateExpression:    aws.String(updateCheckpoint),
+		ConditionExpression: aws.String(conditionOwned),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":owner":      {S: aws.String(s.owner)},
+			":checkpoint": {S: aws.String(sequenceNumber)},
+		},
+	})
+	if isErrorCode(err, dynamodb.ErrCodeConditionalCheckFailedException) {
+		return errLeaseLost
+	}
+	return err
+}
+
+func (s *dynamoDBStore) release(ctx context.Context, keys []shardKey) {
+	for _, key := range keys {
+		_, err := s.client.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+			TableName:           aws.String(s.table),
+			Key:                 s.itemKey(key),
+			UpdateExpression:    aws.String(removeLease),
+			ConditionExpression: aws.String(conditionOwned),
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":owner": {S: aws.String(s.owner)},
+			},
+		})
+		if err != nil && !isErrorCode(err, dynamodb.ErrCodeConditionalCheckFailedException) {
+			s.log.Warnf("Unable to release the lease of shard %s: %v", key, err)
+		}
+	}
+}
+
+func (s *dynamoDBStore) itemKey(key shardKey) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{attrShardKey: {S: aws.String(key.String())}}
+}
+
+// planLeases splits the shards evenly between the agents holding live
+// leases. It returns the leases the agent holds, the ones it takes because
+// they are not held by anyone, and if there are not enough of them, one lease
+// of the agent holding the most shards above the share of each agent.
+func planLeases(keys []shardKey, leases map[shardKey]lease, owner string, now time.Time) (owned, take []shardKey, steal *lease) {
+	counts := map[string]int{owner: 0}
+	var available []shardKey
+	for _, key := range keys {
+		l, ok := leases[key]
+		switch {
+		case ok && l.owner == owner:
+			owned = append(owned, key)
+			counts[owner]++
+		case !ok || l.owner == "" || l.expiry.Before(now):
+			available = append(available, key)
+		default:
+			counts[l.owner]++
+		}
+	}
+	target := int(math.Ceil(float64(len(keys)) / float64(len(counts))))
+	needed := target - len(owned)
+	if needed <= 0 {
+		return owned, nil, nil
+	}
+	if len(available) >= needed {
+		return owned, available[:needed], nil
+	}
+	take = available
+	var busiest string
+	for o, count := range counts {
+		if o != owner && count > target && (busiest == "" || count > counts[busiest] || (count == counts[busiest] && o < busiest)) {
+			busiest = o
+		}
+	}
+	if busiest == "" {
+		return owned, take, nil
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		if l, ok := leases[keys[i]]; ok && l.owner == busiest && !l.expiry.Before(now) {
+			return owned, take, &l
+		}
+	}
+	return owned, take, nil
+}
+
+var errLeaseLost = errors.New("lease was taken over by another agent")
+
+func isErrorCode(err error, code string) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == code
+}
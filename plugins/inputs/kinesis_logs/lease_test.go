@@ -0,0 +1,289 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesis_logs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDynamoDB is an in-memory lease table that evaluates the conditions
+// and updates used by dynamoDBStore.
+type mockDynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+
+	mu      sync.Mutex
+	created bool
+	items   map[string]map[string]*dynamodb.AttributeValue
+}
+
+func newMockDynamoDB() *mockDynamoDB {
+	return &mockDynamoDB{items: map[string]map[string]*dynamodb.AttributeValue{}}
+}
+
+func (m *mockDynamoDB) DescribeTableWithContext(aws.Context, *dynamodb.DescribeTableInput, ...request.Option) (*dynamodb.DescribeTableOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.created {
+		return nil, awserr.New(dynamodb.ErrCodeResourceNotFoundException, "table not found", nil)
+	}
+	return &dynamodb.DescribeTableOutput{}, nil
+}
+
+func (m *mockDynamoDB) CreateTableWithContext(aws.Context, *dynamodb.CreateTableInput, ...request.Option) (*dynamodb.CreateTableOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.created = true
+	return &dynamodb.CreateTableOutput{}, nil
+}
+
+func (m *mockDynamoDB) WaitUntilTableExistsWithContext(aws.Context, *dynamodb.DescribeTableInput, ...request.WaiterOption) error {
+	return nil
+}
+
+func (m *mockDynamoDB) ScanPagesWithContext(_ aws.Context, _ *dynamodb.ScanInput, fn func(*dynamodb.ScanOutput, bool) bool, _ ...request.Option) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := &dynamodb.ScanOutput{}
+	for _, item := range m.items {
+		out.Items = append(out.Items, item)
+	}
+	fn(out, true)
+	return nil
+}
+
+func (m *mockDynamoDB) GetItemWithContext(_ aws.Context, input *dynamodb.GetItemInput, _ ...request.Option) (*dynamodb.GetItemOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return &dynamodb.GetItemOutput{Item: m.items[aws.StringValue(input.Key[attrShardKey].S)]}, nil
+}
+
+func (m *mockDynamoDB) UpdateItemWithContext(_ aws.Context, input *dynamodb.UpdateItemInput, _ ...request.Option) (*dynamodb.UpdateItemOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := aws.StringValue(input.Key[attrShardKey].S)
+	item, ok := m.items[key]
+	if !ok {
+		item = map[string]*dynamodb.AttributeValue{attrShardKey: {S: aws.String(key)}}
+	}
+	values := input.ExpressionAttributeValues
+	owner := attributeString(item, attrOwner)
+	var holds bool
+	switch aws.StringValue(input.ConditionExpression) {
+	case conditionOwned:
+		holds = owner == aws.StringValue(values[":owner"].S)
+	case conditionHeldBy:
+		holds = owner == aws.StringValue(values[":previous"].S)
+	case conditionAvailable:
+		expiry, _ := strconv.ParseInt(attributeNumber(item, attrExpiry), 10, 64)
+		now, _ := strconv.ParseInt(aws.StringValue(values[":now"].N), 10, 64)
+		holds = owner == "" || expiry < now
+	default:
+		return nil, fmt.Errorf("unexpected condition %q", aws.StringValue(input.ConditionExpression))
+	}
+	if !holds {
+		return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "condition failed", nil)
+	}
+	switch aws.StringValue(input.UpdateExpression) {
+	case updateLease:
+		item[attrOwner] = values[":owner"]
+		item[attrExpiry] = values[":expiry"]
+	case updateCheckpoint:
+		item[attrCheckpoint] = values[":checkpoint"]
+	case removeLease:
+		delete(item, attrOwner)
+		delete(item, attrExpiry)
+	default:
+		return nil, fmt.Errorf("unexpected update %q", aws.StringValue(input.UpdateExpression))
+	}
+	m.items[key] = item
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (m *mockDynamoDB) owner(key shardKey) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return attributeString(m.items[key.String()], attrOwner)
+}
+
+func attributeString(item map[string]*dynamodb.AttributeValue, name string) string {
+	if attr, ok := item[name]; ok {
+		return aws.StringValue(attr.S)
+	}
+	return ""
+}
+
+func attributeNumber(item map[string]*dynamodb.AttributeValue, name string) string {
+	if attr, ok := item[name]; ok {
+		return aws.StringValue(attr.N)
+	}
+	return ""
+}
+
+func testShards(n int) ([]shard, []shardKey) {
+	var shards []shard
+	var keys []shardKey
+	for i := 0; i < n; i++ {
+		key := shardKey{stream: "app", shard: fmt.Sprintf("shardId-%012d", i)}
+		shards = append(shards, shard{key: key})
+		keys = append(keys, key)
+	}
+	return shards, keys
+}
+
+func TestPlanLeases(t *testing.T) {
+	now := time.Now()
+	live := now.Add(time.Minute)
+	_, keys := testShards(4)
+
+	testCases := map[string]struct {
+		leases    map[shardKey]lease
+		wantOwned []shardKey
+		wantTake  []shardKey
+		wantSteal *shardKey
+	}{
+		"WithNoLeases": {
+			wantTake: keys,
+		},
+		"WithOwnedLeases": {
+			leases: map[shardKey]lease{
+				keys[0]: {key: keys[0], owner: "a", expiry: live},
+				keys[1]: {key: keys[1], owner: "b", expiry: live},
+			},
+			wantOwned: []shardKey{keys[0]},
+			wantTake:  []shardKey{keys[2]},
+		},
+		"WithExpiredLeases": {
+			leases: map[shardKey]lease{
+				keys[0]: {key: keys[0], owner: "b", expiry: now.Add(-time.Second)},
+				keys[1]: {key: keys[1], owner: "b", expiry: live},
+				keys[2]: {key: keys[2], owner: "b", expiry: live},
+				keys[3]: {key: keys[3], owner: "b", expiry: live},
+			},
+			wantTake: []shardKey{keys[0]},
+			// the agent takes the expired lease and steals one of the 3 others
+			wantSteal: &keys[3],
+		},
+		"WithBalancedLeases": {
+			leases: map[shardKey]lease{
+				keys[0]: {key: keys[0], owner: "a", expiry: live},
+				keys[1]: {key: keys[1], owner: "a", expiry: live},
+				keys[2]: {key: keys[2], owner: "b", expiry: live},
+				keys[3]: {key: keys[3], owner: "b", expiry: live},
+			},
+			wantOwned: []shardKey{keys[0], keys[1]},
+		},
+		"WithNewAgent": {
+			leases: map[shardKey]lease{
+				keys[0]: {key: keys[0], owner: "b", expiry: live},
+				keys[1]: {key: keys[1], owner: "b", expiry: live},
+				keys[2]: {key: keys[2], owner: "c", expiry: live},
+				keys[3]: {key: keys[3], owner: "c", expiry: live},
+			},
+			// each agent has a share of 2, so there is nothing to steal
+		},
+		"WithBusiestAgent": {
+			leases: map[shardKey]lease{
+				keys[0]: {key: keys[0], owner: "c", expiry: live},
+				keys[1]: {key: keys[1], owner: "c", expiry: live},
+				keys[2]: {key: keys[2], owner: "c", expiry: live},
+				keys[3]: {key: keys[3], owner: "b", expiry: live},
+			},
+			wantSteal: &keys[2],
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			owned, take, steal := planLeases(keys, testCase.leases, "a", now)
+			assert.Equal(t, testCase.wantOwned, owned)
+			assert.Equal(t, testCase.wantTake, take)
+			if testCase.wantSteal == nil {
+				assert.Nil(t, steal)
+			} else {
+				require.NotNil(t, steal)
+				assert.Equal(t, *testCase.wantSteal, steal.key)
+			}
+		})
+	}
+}
+
+func TestDynamoDBStoreBalance(t *testing.T) {
+	ctx := context.Background()
+	client := newMockDynamoDB()
+	now := time.Now()
+	shards, keys := testShards(4)
+	a := newDynamoDBStore(client, "app", "a", leaseDuration, testutil.Logger{})
+	a.now = func() time.Time { return now }
+	b := newDynamoDBStore(client, "app", "b", leaseDuration, testutil.Logger{})
+	b.now = func() time.Time { return now }
+
+	// the first agent creates the table and takes all of the shards
+	owned, err := a.balance(ctx, shards)
+	require.NoError(t, err)
+	assert.True(t, client.created)
+	assert.ElementsMatch(t, keys, owned)
+
+	// the second agent steals a shard on every balance until it has its share
+	owned, err = b.balance(ctx, shards)
+	require.NoError(t, err)
+	assert.Len(t, owned, 1)
+	owned, err = b.balance(ctx, shards)
+	require.NoError(t, err)
+	assert.Len(t, owned, 2)
+	owned, err = b.balance(ctx, shards)
+	require.NoError(t, err)
+	assert.Len(t, owned, 2)
+
+	// the first agent no longer renews the stolen leases
+	owned, err = a.balance(ctx, shards)
+	require.NoError(t, err)
+	assert.Len(t, owned, 2)
+	for _, key := range owned {
+		assert.Equal(t, "a", client.owner(key))
+	}
+
+	// the second agent cannot checkpoint the shards of the first
+	require.NoError(t, a.save(ctx, owned[0], "1"))
+	assert.ErrorIs(t, b.save(ctx, owned[0], "2"), errLeaseLost)
+	checkpoint, err := b.checkpoint(ctx, owned[0])
+	require.NoError(t, err)
+	assert.Equal(t, "1", checkpoint)
+
+	// the leases of the first agent are taken over once released
+	a.release(ctx, owned)
+	owned, err = b.balance(ctx, shards)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, keys, owned)
+}
+
+func TestDynamoDBStoreBalanceWithFinishedParent(t *testing.T) {
+	ctx := context.Background()
+	client := newMockDynamoDB()
+	parent := shardKey{stream: "app", shard: "shardId-000000000000"}
+	child := shardKey{stream: "app", shard: "shardId-000000000001"}
+	shards := []shard{{key: parent}, {key: child, parents: []string{parent.shard}}}
+	s := newDynamoDBStore(client, "app", "a", leaseDuration, testutil.Logger{})
+
+	owned, err := s.balance(ctx, shards)
+	require.NoError(t, err)
+	assert.Equal(t, []shardKey{parent}, owned)
+
+	require.NoError(t, s.save(ctx, parent, shardEnd))
+	owned, err = s.balance(ctx, shards)
+	require.NoError(t, err)
+	assert.Equal(t, []shardKey{child}, owned)
+}
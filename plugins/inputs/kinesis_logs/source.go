@@ -0,0 +1,39 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesis_logs
+
+import "strings"
+
+const (
+	streamPlaceholder = "{stream}"
+	shardPlaceholder  = "{shard}"
+)
+
+type shardKey struct {
+	stream string
+	shard  string
+}
+
+func (k shardKey) String() string {
+	return k.stream + "/" + k.shard
+}
+
+// parseShardKey is the inverse of shardKey.String. The stream names cannot
+// contain a slash.
+func parseShardKey(s string) (shardKey, bool) {
+	stream, shard, ok := strings.Cut(s, "/")
+	return shardKey{stream: stream, shard: shard}, ok
+}
+
+// resolveTemplate replaces the stream and shard placeholders in the log group
+// or stream name.
+func resolveTemplate(template, defaultTemplate string, key shardKey) string {
+	if template == "" {
+		template = defaultTemplate
+	}
+	return strings.NewReplacer(
+		streamPlaceholder, key.stream,
+		shardPlaceholder, key.shard,
+	).Replace(template)
+}
@@ -0,0 +1,231 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+const (
+	// defaultEMFDeadLetterSuffix is appended to the log stream of the invalid
+	// records if no dead-letter stream is configured.
+	defaultEMFDeadLetterSuffix = "-invalid-emf"
+	// emfMaxDimensionSets is the number of distinct sets of dimension values
+	// of a metric above which a warning is logged.
+	emfMaxDimensionSets = 1000
+
+	emfMaxDimensions = 30
+	emfMaxMetrics    = 100
+	emfMaxValues     = 100
+)
+
+var emfUnits = map[string]bool{
+	"Seconds": true, "Microseconds": true, "Milliseconds": true,
+	"Bytes": true, "Kilobytes": true, "Megabytes": true, "Gigabytes": true, "Terabytes": true,
+	"Bits": true, "Kilobits": true, "Megabits": true, "Gigabits": true, "Terabits": true,
+	"Percent": true, "Count": true,
+	"Bytes/Second": true, "Kilobytes/Second": true, "Megabytes/Second": true, "Gigabytes/Second": true, "Terabytes/Second": true,
+	"Bits/Second": true, "Kilobits/Second": true, "Megabits/Second": true, "Gigabits/Second": true, "Terabits/Second": true,
+	"Count/Second": true, "None": true,
+}
+
+// emfMetricKey identifies a metric for the cardinality checks.
+type emfMetricKey struct {
+	namespace string
+	name      string
+}
+
+// emfValidator validates the log lines written in the embedded metric format.
+// The invalid records are routed to a dead-letter log stream and counted by
+// the invalid_emf_records metric of the logfile component, and a warning is
+// logged once for the metrics with too many distinct sets of dimension values.
+type emfValidator struct {
+	deadLetterStream string
+	description      string
+
+	mu         sync.Mutex
+	warned     bool
+	dimensions map[emfMetricKey]map[string]struct{}
+	exceeded   map[emfMetricKey]bool
+}
+
+func newEMFValidator(deadLetterStream, description string) *emfValidator {
+	return &emfValidator{
+		deadLetterStream: deadLetterStream,
+		description:      description,
+		dimensions:       make(map[emfMetricKey]map[string]struct{}),
+		exceeded:         make(map[emfMetricKey]bool),
+	}
+}
+
+// validate routes the event to the dead-letter stream if it is not valid EMF.
+func (v *emfValidator) validate(e *LogEvent, stream string) {
+	directives, root, err := parseEMF(e.msg)
+	if err != nil {
+		selftelemetry.Add(selftelemetry.MetricInvalidEMFRecords, "logfile", 1)
+		if e.stream != "" {
+			stream = e.stream
+		}
+		e.stream = v.deadLetter(stream)
+		v.mu.Lock()
+		warned := v.warned
+		v.warned = true
+		v.mu.Unlock()
+		if !warned {
+			log.Printf("W! [logfile] Invalid EMF record in %s routed to the log stream %s: %v", v.description, e.stream, err)
+		} else {
+			log.Printf("D! [logfile] Invalid EMF record in %s: %v", v.description, err)
+		}
+		return
+	}
+	v.track(directives, root)
+}
+
+func (v *emfValidator) deadLetter(stream string) string {
+	if v.deadLetterStream != "" {
+		return v.deadLetterStream
+	}
+	return stream + defaultEMFDeadLetterSuffix
+}
+
+// track counts the distinct sets of dimension values of the metrics.
+func (v *emfValidator) track(directives []emfDirective, root map[string]interface{}) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, directive := range directives {
+		for _, set := range directive.Dimensions {
+			values := make([]string, 0, len(set))
+			for _, dimension := range set {
+				values = append(values, dimension+"="+root[dimension].(string))
+			}
+			id := strings.Join(values, ",")
+			for _, metric := range directive.Metrics {
+				key := emfMetricKey{namespace: directive.Namespace, name: metric.Name}
+				if v.exceeded[key] {
+					continue
+				}
+				sets, ok := v.dimensions[key]
+				if !ok {
+					sets = make(map[string]struct{})
+					v.dimensions[key] = sets
+				}
+				sets[id] = struct{}{}
+				if len(sets) > emfMaxDimensionSets {
+					log.Printf("W! [logfile] The EMF metric %s/%s of %s has more than %d sets of dimension values, check that the dimensions do not contain unbounded values", key.namespace, key.name, v.description, emfMaxDimensionSets)
+					v.exceeded[key] = true
+					delete(v.dimensions, key)
+				}
+			}
+		}
+	}
+}
+
+type emfMetric struct {
+	Name              string   `json:"Name"`
+	Unit              *string  `json:"Unit"`
+	StorageResolution *float64 `json:"StorageResolution"`
+}
+
+type emfDirective struct {
+	Namespace  string      `json:"Namespace"`
+	Dimensions [][]string  `json:"Dimensions"`
+	Metrics    []emfMetric `json:"Metrics"`
+}
+
+type emfMetadata struct {
+	Timestamp         *float64       `json:"Timestamp"`
+	CloudWatchMetrics []emfDirective `json:"CloudWatchMetrics"`
+}
+
+// parseEMF checks the record against the specification of the embedded metric
+// format. Returns its metric directives and its root members.
+func parseEMF(msg string) ([]emfDirective, map[string]interface{}, error) {
+	var root map[string]interface{}
+	if err := json.Unmarshal([]byte(msg), &root); err != nil {
+		return nil, nil, fmt.Errorf("not a JSON object: %w", err)
+	}
+	raw, ok := root["_aws"]
+	if !ok {
+		return nil, nil, errors.New("missing _aws metadata")
+	}
+	b, _ := json.Marshal(raw)
+	var metadata emfMetadata
+	if err := json.Unmarshal(b, &metadata); err != nil {
+		return nil, nil, fmt.Errorf("invalid _aws metadata: %w", err)
+	}
+	if metadata.Timestamp == nil {
+		return nil, nil, errors.New("missing _aws.Timestamp")
+	}
+	if len(metadata.CloudWatchMetrics) == 0 {
+		return nil, nil, errors.New("missing _aws.CloudWatchMetrics")
+	}
+	for _, directive := range metadata.CloudWatchMetrics {
+		if err := validateEMFDirective(directive, root); err != nil {
+			return nil, nil, err
+		}
+	}
+	return metadata.CloudWatchMetrics, root, nil
+}
+
+func validateEMFDirective(directive emfDirective, root map[string]interface{}) error {
+	if directive.Namespace == "" || len(directive.Namespace) > 255 {
+		return errors.New("the namespace must have between 1 and 255 characters")
+	}
+	for _, set := range directive.Dimensions {
+		if len(set) > emfMaxDimensions {
+			return fmt.Errorf("a dimension set of %s has more than %d dimensions", directive.Namespace, emfMaxDimensions)
+		}
+		for _, dimension := range set {
+			if _, ok := root[dimension].(string); !ok {
+				return fmt.Errorf("the dimension %s of %s is not a string member", dimension, directive.Namespace)
+			}
+		}
+	}
+	if len(directive.Metrics) == 0 || len(directive.Metrics) > emfMaxMetrics {
+		return fmt.Errorf("%s must have between 1 and %d metrics", directive.Namespace, emfMaxMetrics)
+	}
+	for _, metric := range directive.Metrics {
+		if metric.Name == "" {
+			return fmt.Errorf("a metric of %s has no name", directive.Namespace)
+		}
+		if metric.Unit != nil && !emfUnits[*metric.Unit] {
+			return fmt.Errorf("the unit %s of the metric %s is not valid", *metric.Unit, metric.Name)
+		}
+		if metric.StorageResolution != nil && *metric.StorageResolution != 1 && *metric.StorageResolution != 60 {
+			return fmt.Errorf("the storage resolution of the metric %s must be 1 or 60", metric.Name)
+		}
+		if err := validateEMFValue(metric.Name, root[metric.Name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateEMFValue(name string, value interface{}) error {
+	switch v := value.(type) {
+	case float64:
+		return nil
+	case []interface{}:
+		if len(v) == 0 || len(v) > emfMaxValues {
+			return fmt.Errorf("the metric %s must have between 1 and %d values", name, emfMaxValues)
+		}
+		for _, item := range v {
+			if _, ok := item.(float64); !ok {
+				return fmt.Errorf("the values of the metric %s must be numbers", name)
+			}
+		}
+		return nil
+	case nil:
+		return fmt.Errorf("the metric %s has no value", name)
+	default:
+		return fmt.Errorf("the value of the metric %s must be a number or an array of numbers", name)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+func invalidEMFRecords() float64 {
+	for _, sample := range selftelemetry.Default.Collect() {
+		if sample.Name == selftelemetry.MetricInvalidEMFRecords && sample.Component == "logfile" {
+			return sample.Value
+		}
+	}
+	return 0
+}
+
+func TestParseEMF(t *testing.T) {
+	testCases := map[string]struct {
+		msg     string
+		wantErr string
+	}{
+		"Valid": {
+			msg: `{"_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Namespace":"App","Dimensions":[["method"]],"Metrics":[{"Name":"latency","Unit":"Milliseconds","StorageResolution":1}]}]},"method":"GET","latency":[12,15]}`,
+		},
+		"NoDimensions": {
+			msg: `{"_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Namespace":"App","Metrics":[{"Name":"count"}]}]},"count":1}`,
+		},
+		"NotJSON": {
+			msg:     `GET /index.html 200`,
+			wantErr: "not a JSON object",
+		},
+		"NoMetadata": {
+			msg:     `{"count":1}`,
+			wantErr: "missing _aws metadata",
+		},
+		"NoTimestamp": {
+			msg:     `{"_aws":{"CloudWatchMetrics":[{"Namespace":"App","Metrics":[{"Name":"count"}]}]},"count":1}`,
+			wantErr: "missing _aws.Timestamp",
+		},
+		"NoDirectives": {
+			msg:     `{"_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[]},"count":1}`,
+			wantErr: "missing _aws.CloudWatchMetrics",
+		},
+		"NoNamespace": {
+			msg:     `{"_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Metrics":[{"Name":"count"}]}]},"count":1}`,
+			wantErr: "the namespace must have between 1 and 255 characters",
+		},
+		"MissingDimension": {
+			msg:     `{"_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Namespace":"App","Dimensions":[["method"]],"Metrics":[{"Name":"count"}]}]},"count":1}`,
+			wantErr: "the dimension method of App is not a string member",
+		},
+		"NoMetrics": {
+			msg:     `{"_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Namespace":"App","Metrics":[]}]}}`,
+			wantErr: "App must have between 1 and 100 metrics",
+		},
+		"InvalidUnit": {
+			msg:     `{"_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Namespace":"App","Metrics":[{"Name":"count","Unit":"Things"}]}]},"count":1}`,
+			wantErr: "the unit Things of the metric count is not valid",
+		},
+		"InvalidStorageResolution": {
+			msg:     `{"_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Namespace":"App","Metrics":[{"Name":"count","StorageResolution":10}]}]},"count":1}`,
+			wantErr: "the storage resolution of the metric count must be 1 or 60",
+		},
+		"MissingValue": {
+			msg:     `{"_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Namespace":"App","Metrics":[{"Name":"count"}]}]}}`,
+			wantErr: "the metric count has no value",
+		},
+		"StringValue": {
+			msg:     `{"_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Namespace":"App","Metrics":[{"Name":"count"}]}]},"count":"1"}`,
+			wantErr: "the value of the metric count must be a number or an array of numbers",
+		},
+		"EmptyValues": {
+			msg:     `{"_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Namespace":"App","Metrics":[{"Name":"count"}]}]},"count":[]}`,
+			wantErr: "the metric count must have between 1 and 100 values",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := parseEMF(testCase.msg)
+			if testCase.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, testCase.wantErr)
+			}
+		})
+	}
+}
+
+func TestEMFValidatorDeadLetter(t *testing.T) {
+	valid := `{"_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Namespace":"App","Metrics":[{"Name":"count"}]}]},"count":1}`
+	before := invalidEMFRecords()
+
+	v := newEMFValidator("", "/var/log/app.log")
+	e := &LogEvent{msg: valid}
+	v.validate(e, "stream")
+	assert.Equal(t, "", e.stream)
+
+	e = &LogEvent{msg: "not emf"}
+	v.validate(e, "stream")
+	assert.Equal(t, "stream-invalid-emf", e.stream)
+
+	e = &LogEvent{msg: "not emf", stream: "resolved"}
+	v.validate(e, "stream")
+	assert.Equal(t, "resolved-invalid-emf", e.stream)
+
+	v = newEMFValidator("dead-letter", "/var/log/app.log")
+	e = &LogEvent{msg: "not emf"}
+	v.validate(e, "stream")
+	assert.Equal(t, "dead-letter", e.stream)
+
+	assert.Equal(t, before+3, invalidEMFRecords())
+}
+
+func TestEMFValidatorCardinality(t *testing.T) {
+	v := newEMFValidator("", "/var/log/app.log")
+	key := emfMetricKey{namespace: "App", name: "count"}
+	for i := 0; i < emfMaxDimensionSets; i++ {
+		v.validate(&LogEvent{msg: fmt.Sprintf(`{"_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Namespace":"App","Dimensions":[["id"]],"Metrics":[{"Name":"count"}]}]},"id":"%d","count":1}`, i%(emfMaxDimensionSets/2))}, "stream")
+	}
+	assert.Len(t, v.dimensions[key], emfMaxDimensionSets/2)
+	assert.False(t, v.exceeded[key])
+
+	for i := 0; i <= emfMaxDimensionSets; i++ {
+		v.validate(&LogEvent{msg: fmt.Sprintf(`{"_aws":{"Timestamp":1700000000000,"CloudWatchMetrics":[{"Namespace":"App","Dimensions":[["id"]],"Metrics":[{"Name":"count"}]}]},"id":"%d","count":1}`, i)}, "stream")
+	}
+	assert.True(t, v.exceeded[key])
+	assert.NotContains(t, v.dimensions, key)
+}
@@ -17,6 +17,7 @@ import (
 	"golang.org/x/text/encoding/ianaindex"
 
 	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/parser"
 	"github.com/aws/amazon-cloudwatch-agent/profiler"
 )
 
@@ -41,6 +42,16 @@ type FileConfig struct {
 	LogStreamName string `toml:"log_stream_name"`
 	//log group class
 	LogGroupClass string `toml:"log_group_class"`
+	//The role assumed and the region used to publish to the log group.
+	//The ones of the output are used if empty.
+	RoleARN string `toml:"role_arn"`
+	Region  string `toml:"region"`
+	//The tags and the KMS key applied to the log group.
+	Tags     map[string]string `toml:"tags"`
+	KMSKeyID string            `toml:"kms_key_id"`
+	//Whether the agent creates the log group and applies its retention, tags and KMS key.
+	//Set to false for the log groups created outside the agent. Managed if not present.
+	ManageLogGroup *bool `toml:"manage_log_group"`
 
 	//The regex of the timestampFromLogLine presents in the log entry
 	TimestampRegex string `toml:"timestamp_regex"`
@@ -54,6 +65,9 @@ type FileConfig struct {
 	//If this config is specified as "{timestamp_regex}", it means to use the same regex as timestampFromLogLine.
 	//If this config is specified as some regex, it will use the regex to determine if this line is a start line of multiline entry.
 	MultiLineStartPattern string `toml:"multi_line_start_pattern"`
+	//Joins the lines of the events with a pattern, negate and match, like filebeat.
+	//It cannot be used together with multi_line_start_pattern.
+	Multiline *Multiline `toml:"multiline"`
 
 	// automatically remove the file / symlink after uploading.
 	// This auto removal does not support the case where other log rotation mechanism is already in place.
@@ -78,10 +92,28 @@ type FileConfig struct {
 	//Suffix to be added to truncated logline to indicate its truncation
 	TruncateSuffix string `toml:"truncate_suffix"`
 
+	//Truncates or splits the log events above the size limit when they are published
+	OversizeEvents *OversizeEvents `toml:"oversize_events"`
+
 	//Indicate retention in days for log group
 	RetentionInDays int `toml:"retention_in_days"`
 
 	Filters []*LogFilter `toml:"filters"`
+	//Samples and rate limits the log events of all the files of the entry
+	Sampling *Sampling `toml:"sampling"`
+
+	//Parsers extract fields from the log events, which are then published as JSON objects
+	Parsers []*parser.Parser `toml:"parsers"`
+	//The parsed field overriding the timestamp of the log event
+	TimestampField string `toml:"timestamp_field"`
+	//Converts the parsed log events to the embedded metric format
+	EMF *parser.EMF `toml:"emf"`
+	//Validates that the log events are in the embedded metric format. The invalid ones are
+	//published to the dead-letter log stream, which is the log stream suffixed with -invalid-emf if empty.
+	EMFValidation       bool   `toml:"emf_validation"`
+	EMFDeadLetterStream string `toml:"emf_dead_letter_stream"`
+	//Publishes the log events as JSON objects with the selected parsed fields
+	StructuredJSON *parser.Structured `toml:"structured_json"`
 
 	//Customer specified service.name
 	ServiceName string `toml:"service_name"`
@@ -99,6 +131,8 @@ type FileConfig struct {
 	//Decoder object
 	Enc         encoding.Encoding
 	sampleCount int
+	//Parsing pipeline of the log events
+	parsePipeline *parser.Pipeline
 }
 
 // Initialize some variables in the FileConfig object based on the rest info fetched from the configuration file.
@@ -130,14 +164,23 @@ func (config *FileConfig) init() error {
 		}
 	}
 
-	if config.MultiLineStartPattern == "" {
-		config.MultiLineStartPattern = "^[\\S]"
-	}
-	if config.MultiLineStartPattern == "{timestamp_regex}" {
-		config.MultiLineStartPatternP = config.TimestampRegexP
+	if config.Multiline != nil {
+		if config.MultiLineStartPattern != "" {
+			return errors.New("multi_line_start_pattern and multiline cannot be set together")
+		}
+		if err = config.Multiline.init(config.TimestampRegexP); err != nil {
+			return err
+		}
 	} else {
-		if config.MultiLineStartPatternP, err = regexp.Compile(config.MultiLineStartPattern); err != nil {
-			return fmt.Errorf("multi_line_start_pattern has issue, regexp: Compile( %v ): %v", config.MultiLineStartPattern, err.Error())
+		if config.MultiLineStartPattern == "" {
+			config.MultiLineStartPattern = "^[\\S]"
+		}
+		if config.MultiLineStartPattern == "{timestamp_regex}" {
+			config.MultiLineStartPatternP = config.TimestampRegexP
+		} else {
+			if config.MultiLineStartPatternP, err = regexp.Compile(config.MultiLineStartPattern); err != nil {
+				return fmt.Errorf("multi_line_start_pattern has issue, regexp: Compile( %v ): %v", config.MultiLineStartPattern, err.Error())
+			}
 		}
 	}
 
@@ -165,6 +208,30 @@ func (config *FileConfig) init() error {
 		}
 	}
 
+	if config.Sampling != nil {
+		if err = config.Sampling.init(); err != nil {
+			return err
+		}
+	}
+
+	if config.OversizeEvents != nil {
+		if err = config.OversizeEvents.init(config.TruncateSuffix); err != nil {
+			return err
+		}
+	}
+
+	config.parsePipeline, err = parser.New(parser.Config{
+		Parsers:         config.Parsers,
+		TimestampField:  config.TimestampField,
+		TimestampLayout: config.TimestampLayout,
+		Timezone:        config.Timezone,
+		EMF:             config.EMF,
+		Structured:      config.StructuredJSON,
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
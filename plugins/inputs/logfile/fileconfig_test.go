@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/parser"
 	"github.com/aws/amazon-cloudwatch-agent/tool/util"
 )
 
@@ -308,6 +309,26 @@ func TestFileConfigInitWithFiltersFails(t *testing.T) {
 	assert.Equal(t, "filter regex has issue, regexp: Compile( StatusCode: ([4-5]\\d\\d ): error parsing regexp: missing closing ): `StatusCode: ([4-5]\\d\\d`", err.Error())
 }
 
+func TestFileConfigInitWithParsers(t *testing.T) {
+	fileConfig := &FileConfig{
+		FilePath:       "/tmp/logfile.log",
+		Parsers:        []*parser.Parser{{Type: parser.TypeJSON}},
+		TimestampField: "time",
+	}
+	assert.NoError(t, fileConfig.init())
+	assert.NotNil(t, fileConfig.parsePipeline)
+
+	fileConfig = &FileConfig{
+		FilePath: "/tmp/logfile.log",
+		Parsers:  []*parser.Parser{{Type: parser.TypeRegex, Expression: "(?P<level"}},
+	}
+	assert.Error(t, fileConfig.init())
+
+	fileConfig = &FileConfig{FilePath: "/tmp/logfile.log"}
+	assert.NoError(t, fileConfig.init())
+	assert.Nil(t, fileConfig.parsePipeline)
+}
+
 func TestLogEmptyFilters(t *testing.T) {
 	assertPublishedForFilters(t, []*LogFilter{}, "foo")
 	assertPublishedForFilters(t, []*LogFilter{}, "Some other log message")
@@ -605,3 +626,60 @@ func initializeLogFiltersForBenchmarks(b *testing.B, filters []*LogFilter) []*Lo
 	}
 	return filters
 }
+
+func TestFileConfigInitWithMultiline(t *testing.T) {
+	fileConfig := &FileConfig{
+		FilePath:       "/tmp/logfile.log",
+		TimestampRegex: "(\\d{2} \\w{3} \\d{4} \\d{2}:\\d{2}:\\d{2})",
+		Multiline:      &Multiline{Pattern: "{timestamp_regex}", Negate: true},
+	}
+	require.NoError(t, fileConfig.init())
+	assert.Nil(t, fileConfig.MultiLineStartPatternP)
+	assert.Equal(t, multilineMatchAfter, fileConfig.Multiline.Match)
+	assert.Equal(t, defaultMultilineMaxLines, fileConfig.Multiline.MaxLines)
+	assert.EqualValues(t, defaultMultilineTimeout, fileConfig.Multiline.Timeout)
+
+	// the lines without a timestamp continue the event of the line before them
+	assert.True(t, fileConfig.Multiline.isStart("02 Jan 2006 15:04:05 started"))
+	assert.False(t, fileConfig.Multiline.isStart("    at com.example.App.run(App.java:12)"))
+	assert.False(t, fileConfig.Multiline.isEnd("02 Jan 2006 15:04:05 started"))
+
+	fileConfig.Multiline = &Multiline{Pattern: `\\$`, Match: multilineMatchBefore}
+	require.NoError(t, fileConfig.init())
+	assert.False(t, fileConfig.Multiline.isStart("last"))
+	assert.True(t, fileConfig.Multiline.isEnd("last"))
+	assert.False(t, fileConfig.Multiline.isEnd("continued \\"))
+}
+
+func TestFileConfigInitWithMultilineFails(t *testing.T) {
+	testCases := map[string]struct {
+		fileConfig *FileConfig
+		wantErr    string
+	}{
+		"WithMultiLineStartPattern": {
+			fileConfig: &FileConfig{MultiLineStartPattern: "^\\S", Multiline: &Multiline{Pattern: "^\\s"}},
+			wantErr:    "multi_line_start_pattern and multiline cannot be set together",
+		},
+		"WithoutPattern": {
+			fileConfig: &FileConfig{Multiline: &Multiline{}},
+			wantErr:    "multiline pattern is required",
+		},
+		"WithoutTimestampRegex": {
+			fileConfig: &FileConfig{Multiline: &Multiline{Pattern: "{timestamp_regex}"}},
+			wantErr:    "multiline pattern {timestamp_regex} requires timestamp_regex",
+		},
+		"WithInvalidPattern": {
+			fileConfig: &FileConfig{Multiline: &Multiline{Pattern: "(\\d{2}+)"}},
+			wantErr:    "multiline pattern has issue, regexp: Compile( (\\d{2}+) ): error parsing regexp: invalid nested repetition operator: `{2}+`",
+		},
+		"WithInvalidMatch": {
+			fileConfig: &FileConfig{Multiline: &Multiline{Pattern: "^\\s", Match: "next"}},
+			wantErr:    `multiline match "next" is invalid, it must be "after" or "before"`,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.EqualError(t, testCase.fileConfig.init(), testCase.wantErr)
+		})
+	}
+}
@@ -14,10 +14,12 @@ import (
 	"time"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/plugins/inputs"
 
 	"github.com/aws/amazon-cloudwatch-agent/extension/entitystore"
-	"github.com/aws/amazon-cloudwatch-agent/internal/logscommon"
+	"github.com/aws/amazon-cloudwatch-agent/internal/backpressure"
+	"github.com/aws/amazon-cloudwatch-agent/internal/filestate"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/globpath"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/tail"
@@ -30,10 +32,16 @@ type LogFile struct {
 	FileStateFolder string `toml:"file_state_folder"`
 	//destination
 	Destination string `toml:"destination"`
+	//pause the reads while the memory used by the agent is above the percentage of the cgroup memory limit or the total memory
+	MemoryLimitPercentage int             `toml:"memory_limit_percentage"`
+	MemoryCheckInterval   config.Duration `toml:"memory_check_interval"`
 
 	Log telegraf.Logger `toml:"-"`
 
 	configs           map[*FileConfig]map[string]*tailerSrc
+	lookups           map[*FileConfig]*lookup
+	memoryGate        *backpressure.MemoryGate
+	stateStore        *filestate.Store
 	done              chan struct{}
 	removeTailerSrcCh chan *tailerSrc
 	started           bool
@@ -42,11 +50,21 @@ type LogFile struct {
 func NewLogFile() *LogFile {
 	return &LogFile{
 		configs:           make(map[*FileConfig]map[string]*tailerSrc),
+		lookups:           make(map[*FileConfig]*lookup),
 		done:              make(chan struct{}),
 		removeTailerSrcCh: make(chan *tailerSrc, 100),
 	}
 }
 
+const (
+	// stateStoreFileName is the file in the file state folder with the offsets of all the log files.
+	stateStoreFileName = "logfile_state"
+	// fileStateTTL is how long the offset of a log file is kept after the file is found missing,
+	// e.g. on a file system that is not mounted yet.
+	fileStateTTL       = 24 * time.Hour
+	stateFlushInterval = time.Second
+)
+
 const sampleConfig = `
   ## log files to tail.
   ## These accept standard unix glob matching rules, but with the addition of
@@ -64,6 +82,12 @@ const sampleConfig = `
   ## folder path where state of how much of a file has been transferred is stored
   file_state_folder = "/tmp/logfile/state"
 
+  ## Pause the reads while the memory used by the agent is above the percentage
+  ## of the memory limit of its cgroup, or else of the total memory, checked at
+  ## most once per interval. Disabled by default.
+  # memory_limit_percentage = 55
+  # memory_check_interval = "1s"
+
   [[inputs.logs.file_config]]
       file_path = "/tmp/logfile.log*"
       ## Regular expression for log files to ignore
@@ -77,6 +101,14 @@ const sampleConfig = `
       timestamp_layout = ["_2 Jan 2006 15:04:05"]
       timezone = "UTC"
       multi_line_start_pattern = "{timestamp_regex}"
+      ## Join the lines of the events like the multiline settings of filebeat,
+      ## instead of multi_line_start_pattern.
+      # [inputs.logs.file_config.multiline]
+      #   pattern = "^\\s"
+      #   negate = false
+      #   match = "after"
+      #   max_lines = 500
+      #   timeout = "5s"
       ## Read file from beginning.
       from_beginning = false
       ## Whether file is a named pipe
@@ -108,22 +140,45 @@ func (t *LogFile) Start(acc telegraf.Accumulator) error {
 		return fmt.Errorf("failed to create state file directory %s: %v", t.FileStateFolder, err)
 	}
 
+	t.stateStore = filestate.New(filepath.Join(t.FileStateFolder, stateStoreFileName), fileStateTTL)
+	if err = t.stateStore.Load(); err != nil {
+		t.Log.Warnf("Unable to read the file state %s, the files are read from the start or the end: %v", t.stateStore.Path(), err)
+	}
+	t.stateStore.Compact(time.Now())
+
 	// Clean state file on init and regularly
 	go func() {
 		t.cleanupStateFolder()
-		ticker := time.NewTicker(1 * time.Hour)
-		defer ticker.Stop()
+		cleanupTicker := time.NewTicker(1 * time.Hour)
+		defer cleanupTicker.Stop()
+		flushTicker := time.NewTicker(stateFlushInterval)
+		defer flushTicker.Stop()
 		for {
 			select {
-			case <-ticker.C:
+			case <-cleanupTicker.C:
 				t.cleanupStateFolder()
+				t.stateStore.Compact(time.Now())
+			case <-flushTicker.C:
+				t.flushState()
 			case <-t.done:
+				// the tailer srcs flush their final offsets when they are stopped
+				t.flushState()
 				t.Log.Debugf("Cleanup state folder routine received shutdown signal, stopping.")
 				return
 			}
 		}
 	}()
 
+	if t.MemoryLimitPercentage > 0 {
+		interval := time.Duration(t.MemoryCheckInterval)
+		if interval <= 0 {
+			interval = time.Second
+		}
+		if t.memoryGate, err = backpressure.NewMemoryGate(t.MemoryLimitPercentage, interval); err != nil {
+			t.Log.Warnf("Unable to pause the reads above the memory limit: %v", err)
+		}
+	}
+
 	// Initialize all the file configs
 	for i := range t.FileConfig {
 		if err := t.FileConfig[i].init(); err != nil {
@@ -164,7 +219,7 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 			es.AddServiceAttrEntryForLogFile(entitystore.LogFileGlob(fileconfig.FilePath), fileconfig.ServiceName, fileconfig.Environment)
 		}
 
-		targetFiles, err := t.getTargetFiles(fileconfig)
+		targetFiles, err := t.findTargetFiles(fileconfig)
 		if err != nil {
 			t.Log.Errorf("Failed to find target files for file config %v, with error: %v", fileconfig.FilePath, err)
 		}
@@ -216,7 +271,9 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 			}
 
 			var mlCheck func(string) bool
-			if fileconfig.MultiLineStartPattern != "" {
+			if fileconfig.Multiline != nil {
+				mlCheck = fileconfig.Multiline.isStart
+			} else if fileconfig.MultiLineStartPattern != "" {
 				mlCheck = fileconfig.isMultilineStart
 			}
 
@@ -241,7 +298,7 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 			src := NewTailerSrc(
 				groupName, streamName,
 				t.Destination,
-				t.getStateFilePath(filename),
+				t.stateStore,
 				fileconfig.LogGroupClass,
 				fileconfig.FilePath,
 				tailer,
@@ -254,6 +311,27 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 				fileconfig.TruncateSuffix,
 				fileconfig.RetentionInDays,
 			)
+			src.roleARN = fileconfig.RoleARN
+			src.region = fileconfig.Region
+			src.logGroupSettings = logs.LogGroupSettings{
+				Tags:      fileconfig.Tags,
+				KMSKeyID:  fileconfig.KMSKeyID,
+				Unmanaged: fileconfig.ManageLogGroup != nil && !*fileconfig.ManageLogGroup,
+			}
+			src.parser = fileconfig.parsePipeline
+			src.sampling = fileconfig.Sampling
+			if fileconfig.OversizeEvents != nil {
+				src.eventSizeLimit = fileconfig.OversizeEvents.sizeLimit()
+			}
+			if fileconfig.EMFValidation {
+				src.emfValidator = newEMFValidator(fileconfig.EMFDeadLetterStream, filename)
+			}
+			src.memoryGate = t.memoryGate
+			if ml := fileconfig.Multiline; ml != nil {
+				src.isMLEnd = ml.isEnd
+				src.maxLines = ml.MaxLines
+				src.multilineTimeout = time.Duration(ml.Timeout)
+			}
 
 			src.AddCleanUpFn(func(ts *tailerSrc) func() {
 				return func() {
@@ -325,8 +403,16 @@ func (t *LogFile) getTargetFiles(fileconfig *FileConfig) ([]string, error) {
 	return targetFileList, nil
 }
 
-// The plugin will look at the state folder, and restore the offset of the file seeked if such state exists.
+// The plugin will look at the file state, and restore the offset of the file seeked if such state exists.
+// The offsets saved by older versions in a state file per log file are moved to the file state.
 func (t *LogFile) restoreState(filename string) (int64, error) {
+	if t.stateStore != nil {
+		if offset, ok := t.stateStore.Get(filename); ok {
+			t.Log.Infof("Reading from offset %v in %s", offset, filename)
+			return offset, nil
+		}
+	}
+
 	filePath := t.getStateFilePath(filename)
 
 	if _, err := os.Stat(filePath); err != nil {
@@ -349,10 +435,33 @@ func (t *LogFile) restoreState(filename string) (int64, error) {
 	if offset < 0 {
 		return 0, fmt.Errorf("negative state file offset, %v, %v", filePath, offset)
 	}
+	t.migrateState(filename, filePath, offset)
 	t.Log.Infof("Reading from offset %v in %s", offset, filename)
 	return offset, nil
 }
 
+// migrateState moves the offset of the state file written by an older version to the file state. The state
+// file is removed once the offset is saved, so that it is not restored again after the log file is deleted.
+func (t *LogFile) migrateState(filename, filePath string, offset int64) {
+	if t.stateStore == nil {
+		return
+	}
+	t.stateStore.Set(filename, offset)
+	if err := t.stateStore.Flush(); err != nil {
+		t.Log.Warnf("Unable to move the state file %s to the file state %s: %v", filePath, t.stateStore.Path(), err)
+		return
+	}
+	if err := os.Remove(filePath); err != nil {
+		t.Log.Warnf("Unable to remove the state file %s moved to the file state: %v", filePath, err)
+	}
+}
+
+func (t *LogFile) flushState() {
+	if err := t.stateStore.Flush(); err != nil {
+		t.Log.Errorf("Error happens when saving the file state %s: %v", t.stateStore.Path(), err)
+	}
+}
+
 func (t *LogFile) getStateFilePath(filename string) string {
 	if t.FileStateFolder == "" {
 		return ""
@@ -372,7 +481,7 @@ func (t *LogFile) cleanupStateFolder() {
 			continue
 		}
 
-		if strings.Contains(file, logscommon.WindowsEventLogPrefix) {
+		if t.stateStore != nil && t.stateStore.IsStoreFile(file) {
 			continue
 		}
 
@@ -381,12 +490,15 @@ func (t *LogFile) cleanupStateFolder() {
 			t.Log.Errorf("Error happens when reading the content from file %s in clean up state fodler step: %v", file, err)
 			continue
 		}
+		// the state files of the log files are named after the file whose offset they record,
+		// the other files in the folder belong to other inputs, e.g. the windows event log.
 		contentArray := strings.Split(string(byteArray), "\n")
-		if len(contentArray) >= 2 {
-			if _, err = os.Stat(contentArray[1]); err == nil {
-				// the original source file still exists
-				continue
-			}
+		if len(contentArray) < 2 || filepath.Base(file) != escapeFilePath(contentArray[1]) {
+			continue
+		}
+		if _, err = os.Stat(contentArray[1]); err == nil {
+			// the original source file still exists
+			continue
 		}
 		if err = os.Remove(file); err != nil {
 			t.Log.Errorf("Error happens when deleting old state file %s: %v", file, err)
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -18,6 +19,8 @@ import (
 	"golang.org/x/text/encoding/simplifiedchinese"
 	"golang.org/x/text/transform"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/filestate"
+	"github.com/aws/amazon-cloudwatch-agent/internal/logscommon"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 )
 
@@ -90,6 +93,33 @@ func TestLogs(t *testing.T) {
 	tt.Stop()
 }
 
+func TestLogsCrossAccountDestination(t *testing.T) {
+	tmpfile, err := createTempFile("", "")
+	require.NoError(t, err)
+	defer os.Remove(tmpfile.Name())
+
+	tt := NewLogFile()
+	tt.Log = TestLogger{t}
+	tt.FileConfig = []FileConfig{{
+		FilePath:      tmpfile.Name(),
+		FromBeginning: true,
+		RoleARN:       "arn:aws:iam::222222222222:role/central-logs",
+		Region:        "eu-west-1",
+	}}
+	tt.FileConfig[0].init()
+	tt.started = true
+
+	lsrcs := tt.FindLogSrc()
+	require.Len(t, lsrcs, 1)
+	cp, ok := lsrcs[0].(logs.LogCredentialProvider)
+	require.True(t, ok)
+	assert.Equal(t, "arn:aws:iam::222222222222:role/central-logs", cp.RoleARN())
+	assert.Equal(t, "eu-west-1", cp.Region())
+
+	lsrcs[0].Stop()
+	tt.Stop()
+}
+
 func TestLogsEncoding(t *testing.T) {
 	multilineWaitPeriod = 10 * time.Millisecond
 	//2 * rune_len when it is coded in gbk encoding.
@@ -674,7 +704,7 @@ func TestLogsFileWithOffset(t *testing.T) {
 	stateFileName := filepath.Join(stateDir, escapeFilePath(tmpfile.Name()))
 	stateFile, err := os.OpenFile(stateFileName, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
 	require.NoError(t, err)
-	_, err = stateFile.WriteString("10")
+	_, err = stateFile.WriteString("10\n" + tmpfile.Name())
 	defer os.Remove(stateFileName)
 
 	_, err = tmpfile.WriteString(logEntryString + "\n")
@@ -780,7 +810,7 @@ func TestLogsFileRecreate(t *testing.T) {
 	stateFileName := filepath.Join(stateDir, escapeFilePath(tmpfile.Name()))
 	stateFile, err := os.OpenFile(stateFileName, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
 	require.NoError(t, err)
-	_, err = stateFile.WriteString("10")
+	_, err = stateFile.WriteString("10\n" + tmpfile.Name())
 	require.NoError(t, err)
 	err = stateFile.Close()
 	require.NoError(t, err)
@@ -790,8 +820,7 @@ func TestLogsFileRecreate(t *testing.T) {
 	tt.FileStateFolder = stateDir
 	tt.Log = TestLogger{t}
 	tt.FileConfig = []FileConfig{{FilePath: tmpfile.Name(), FromBeginning: true}}
-	tt.FileConfig[0].init()
-	tt.started = true
+	require.NoError(t, tt.Start(nil))
 
 	lsrcs := tt.FindLogSrc()
 	if len(lsrcs) != 1 {
@@ -806,6 +835,9 @@ func TestLogsFileRecreate(t *testing.T) {
 		}
 	})
 
+	// the offset is moved from the state file to the file state
+	assert.NoFileExists(t, stateFileName)
+
 	go func() {
 		time.Sleep(1 * time.Second)
 
@@ -1129,3 +1161,119 @@ func TestGenerateLogGroupName(t *testing.T) {
 		logGroupName,
 		expectLogGroup))
 }
+
+func TestLogFileStateStore(t *testing.T) {
+	multilineWaitPeriod = 10 * time.Millisecond
+	logEntryString := "xxxxxxxxxxContentAfterOffset\n"
+
+	tmpfile, err := createTempFile("", "")
+	require.NoError(t, err)
+	defer os.Remove(tmpfile.Name())
+	_, err = tmpfile.WriteString(logEntryString)
+	require.NoError(t, err)
+
+	// the offset saved by an older version is moved to the file state
+	stateDir := t.TempDir()
+	legacyStateFile := filepath.Join(stateDir, escapeFilePath(tmpfile.Name()))
+	require.NoError(t, os.WriteFile(legacyStateFile, []byte("10\n"+tmpfile.Name()), 0644))
+
+	tt := NewLogFile()
+	tt.FileStateFolder = stateDir
+	tt.Log = TestLogger{t}
+	tt.FileConfig = []FileConfig{{FilePath: tmpfile.Name(), FromBeginning: true}}
+	require.NoError(t, tt.Start(nil))
+
+	lsrcs := tt.FindLogSrc()
+	require.Len(t, lsrcs, 1)
+	lsrc := lsrcs[0]
+	evts := make(chan logs.LogEvent)
+	lsrc.SetOutput(func(e logs.LogEvent) {
+		if e != nil {
+			evts <- e
+		}
+	})
+	e := <-evts
+	assert.Equal(t, "ContentAfterOffset", e.Message())
+	e.Done()
+	assert.NoFileExists(t, legacyStateFile)
+
+	// the final offset is flushed when the tailer src is stopped
+	lsrc.Stop()
+	tt.Stop()
+	stateStore := filestate.New(filepath.Join(stateDir, stateStoreFileName), fileStateTTL)
+	assert.Eventually(t, func() bool {
+		require.NoError(t, stateStore.Load())
+		offset, _ := stateStore.Get(tmpfile.Name())
+		return offset == int64(len(logEntryString))
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestCleanupStateFolder(t *testing.T) {
+	stateDir := t.TempDir()
+	// the offset of a log file that no longer exists
+	orphanStateFile := filepath.Join(stateDir, escapeFilePath("/var/log/removed.log"))
+	require.NoError(t, os.WriteFile(orphanStateFile, []byte("10\n/var/log/removed.log"), 0644))
+	// the windows event log state records the log group rather than a file
+	eventLogStateFile := filepath.Join(stateDir, logscommon.WindowsEventLogPrefix+"System")
+	require.NoError(t, os.WriteFile(eventLogStateFile, []byte("10\n/windows/system"), 0644))
+	// the checkpoints of the kinesis input are a single line of JSON
+	kinesisStateFile := filepath.Join(stateDir, logscommon.KinesisPrefix+"app")
+	require.NoError(t, os.WriteFile(kinesisStateFile, []byte(`{"stream/shardId-000000000000":"49590338271490256608559692538361571095921575989136588898"}`), 0644))
+	// the processors keep their state in their own folder
+	heartbeatStateFile := filepath.Join(stateDir, "procstat_heartbeat", "host.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(heartbeatStateFile), 0755))
+	require.NoError(t, os.WriteFile(heartbeatStateFile, []byte(`{"sshd":{"running":true,"observed":true,"started":true,"restart_count":2}}`), 0644))
+
+	tt := NewLogFile()
+	tt.FileStateFolder = stateDir
+	tt.Log = TestLogger{t}
+	tt.cleanupStateFolder()
+
+	assert.NoFileExists(t, orphanStateFile)
+	assert.FileExists(t, eventLogStateFile)
+	assert.FileExists(t, kinesisStateFile)
+	assert.FileExists(t, heartbeatStateFile)
+}
+
+func TestFindTargetFilesUnresponsive(t *testing.T) {
+	defer func(original time.Duration) {
+		lookupTimeout = original
+	}(lookupTimeout)
+	lookupTimeout = 10 * time.Millisecond
+	defer func(original func(*LogFile, *FileConfig) ([]string, error)) {
+		getTargetFiles = original
+	}(getTargetFiles)
+	hung := make(chan struct{})
+	var calls atomic.Int32
+	getTargetFiles = func(_ *LogFile, fileconfig *FileConfig) ([]string, error) {
+		calls.Add(1)
+		if fileconfig.FilePath == "/mnt/hung/*.log" {
+			<-hung
+		}
+		return []string{fileconfig.FilePath}, nil
+	}
+
+	tt := NewLogFile()
+	tt.Log = TestLogger{t}
+	responsive := &FileConfig{FilePath: "/var/log/app.log"}
+	unresponsive := &FileConfig{FilePath: "/mnt/hung/*.log"}
+	files, err := tt.findTargetFiles(unresponsive)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+	files, err = tt.findTargetFiles(responsive)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/var/log/app.log"}, files)
+
+	// the lookup is not started again while it is hung, and its result is
+	// used once it returns
+	files, err = tt.findTargetFiles(unresponsive)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+	assert.EqualValues(t, 2, calls.Load())
+	close(hung)
+	assert.Eventually(t, func() bool {
+		files, _ = tt.findTargetFiles(unresponsive)
+		return len(files) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Empty(t, tt.lookups)
+}
@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"time"
+)
+
+// lookupTimeout is how long the lookup of the files of a file config can
+// take before its file system is considered unresponsive, e.g. a hung NFS
+// mount.
+var lookupTimeout = 5 * time.Second
+
+// getTargetFiles lists the files of a file config. It blocks while its file
+// system does not respond.
+var getTargetFiles = (*LogFile).getTargetFiles
+
+// lookup is the search of the files of a file config running in the
+// background.
+type lookup struct {
+	done  chan lookupResult
+	start time.Time
+}
+
+type lookupResult struct {
+	files []string
+	err   error
+}
+
+// findTargetFiles returns the files of the file config, without blocking
+// the other file configs when its file system does not respond. A lookup
+// that timed out is not started again until it returns, and its result is
+// used by the next call after that.
+func (t *LogFile) findTargetFiles(fileconfig *FileConfig) ([]string, error) {
+	l, ok := t.lookups[fileconfig]
+	if !ok {
+		l = &lookup{done: make(chan lookupResult, 1), start: time.Now()}
+		t.lookups[fileconfig] = l
+		go func() {
+			files, err := getTargetFiles(t, fileconfig)
+			l.done <- lookupResult{files: files, err: err}
+		}()
+		timer := time.NewTimer(lookupTimeout)
+		defer timer.Stop()
+		select {
+		case result := <-l.done:
+			delete(t.lookups, fileconfig)
+			return result.files, result.err
+		case <-timer.C:
+			t.Log.Warnf("The files of %s were not found within %s, the file system may be unresponsive, e.g. a hung NFS mount. The file config is skipped until it responds.", fileconfig.FilePath, lookupTimeout)
+			return nil, nil
+		}
+	}
+	select {
+	case result := <-l.done:
+		delete(t.lookups, fileconfig)
+		t.Log.Infof("The files of %s were found after %s", fileconfig.FilePath, time.Since(l.start).Truncate(time.Second))
+		return result.files, result.err
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/influxdata/telegraf/config"
+)
+
+const (
+	multilineMatchAfter  = "after"
+	multilineMatchBefore = "before"
+
+	defaultMultilineMaxLines = 500
+	defaultMultilineTimeout  = 5 * time.Second
+)
+
+// Multiline joins the lines of a log event, e.g. a Java stack trace or a
+// Python traceback, with the same semantics as the multiline settings of
+// filebeat.
+type Multiline struct {
+	//The lines matching the pattern, or not matching it when negate is set, continue an event.
+	//If it is specified as "{timestamp_regex}", it means to use the same regex as the timestamp.
+	Pattern string `toml:"pattern"`
+	Negate  bool   `toml:"negate"`
+	//"after" appends the continuing lines to the line before them, "before" prepends them
+	//to the line after them. Defaults to "after".
+	Match string `toml:"match"`
+	//The lines of an event beyond max_lines are dropped. Defaults to 500.
+	MaxLines int `toml:"max_lines"`
+	//The event is published if it is not complete after the timeout. Defaults to 5s.
+	Timeout config.Duration `toml:"timeout"`
+
+	patternP *regexp.Regexp
+}
+
+func (m *Multiline) init(timestampRegexP *regexp.Regexp) error {
+	var err error
+	switch m.Pattern {
+	case "":
+		return errors.New("multiline pattern is required")
+	case "{timestamp_regex}":
+		if timestampRegexP == nil {
+			return errors.New("multiline pattern {timestamp_regex} requires timestamp_regex")
+		}
+		m.patternP = timestampRegexP
+	default:
+		if m.patternP, err = regexp.Compile(m.Pattern); err != nil {
+			return fmt.Errorf("multiline pattern has issue, regexp: Compile( %v ): %v", m.Pattern, err.Error())
+		}
+	}
+
+	if m.Match == "" {
+		m.Match = multilineMatchAfter
+	}
+	if m.Match != multilineMatchAfter && m.Match != multilineMatchBefore {
+		return fmt.Errorf("multiline match %q is invalid, it must be %q or %q", m.Match, multilineMatchAfter, multilineMatchBefore)
+	}
+	if m.MaxLines < 0 {
+		return fmt.Errorf("multiline max_lines %d is invalid", m.MaxLines)
+	}
+	if m.MaxLines == 0 {
+		m.MaxLines = defaultMultilineMaxLines
+	}
+	if m.Timeout <= 0 {
+		m.Timeout = config.Duration(defaultMultilineTimeout)
+	}
+	return nil
+}
+
+// continues determines whether the line continues an event instead of being
+// a line of its own.
+func (m *Multiline) continues(logValue string) bool {
+	return m.patternP.MatchString(logValue) != m.Negate
+}
+
+// isStart determines whether the line starts a new event when the continuing
+// lines are appended to the line before them.
+func (m *Multiline) isStart(logValue string) bool {
+	return m.Match == multilineMatchAfter && !m.continues(logValue)
+}
+
+// isEnd determines whether the line ends the event when the continuing lines
+// are prepended to the line after them.
+func (m *Multiline) isEnd(logValue string) bool {
+	return m.Match == multilineMatchBefore && !m.continues(logValue)
+}
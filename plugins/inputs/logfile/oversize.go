@@ -0,0 +1,46 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+// OversizeEvents is how the log events of a collect_list entry above the size
+// limit are published, instead of only keeping their start.
+type OversizeEvents struct {
+	//The size in bytes above which the log events are truncated or split. Defaults to the
+	//size limit of PutLogEvents, which also caps it.
+	MaxSize int `toml:"max_size"`
+	//One of "truncate_tail", "truncate_head", "truncate_middle" or "split". Defaults to "truncate_tail".
+	Strategy string `toml:"strategy"`
+	//Replaces the truncated part of the log events. Defaults to the truncate_suffix.
+	Marker string `toml:"marker"`
+}
+
+func (o *OversizeEvents) init(truncateSuffix string) error {
+	switch o.Strategy {
+	case "":
+		o.Strategy = logs.OversizeTruncateTail
+	case logs.OversizeTruncateTail, logs.OversizeTruncateHead, logs.OversizeTruncateMiddle, logs.OversizeSplit:
+	default:
+		return fmt.Errorf("oversize_events strategy %q is invalid", o.Strategy)
+	}
+	if o.MaxSize < 0 {
+		return fmt.Errorf("oversize_events max_size %d must not be negative", o.MaxSize)
+	}
+	if o.Marker == "" {
+		o.Marker = truncateSuffix
+	}
+	if o.MaxSize > 0 && len(o.Marker) >= o.MaxSize {
+		return fmt.Errorf("oversize_events marker must be shorter than max_size %d", o.MaxSize)
+	}
+	return nil
+}
+
+func (o *OversizeEvents) sizeLimit() logs.EventSizeLimit {
+	return logs.EventSizeLimit{MaxSize: o.MaxSize, Strategy: o.Strategy, Marker: o.Marker}
+}
@@ -0,0 +1,34 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+func TestOversizeEventsInit(t *testing.T) {
+	o := &OversizeEvents{MaxSize: 65536}
+	require.NoError(t, o.init(defaultTruncateSuffix))
+	assert.Equal(t, logs.EventSizeLimit{MaxSize: 65536, Strategy: logs.OversizeTruncateTail, Marker: defaultTruncateSuffix}, o.sizeLimit())
+
+	o = &OversizeEvents{Strategy: logs.OversizeTruncateMiddle, Marker: "<snip>"}
+	require.NoError(t, o.init(defaultTruncateSuffix))
+	assert.Equal(t, logs.EventSizeLimit{Strategy: logs.OversizeTruncateMiddle, Marker: "<snip>"}, o.sizeLimit())
+
+	testCases := map[string]*OversizeEvents{
+		"WithInvalidStrategy":    {Strategy: "drop"},
+		"WithNegativeMaxSize":    {MaxSize: -1},
+		"WithMarkerAboveMaxSize": {MaxSize: 4, Marker: "[...]"},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, testCase.init(defaultTruncateSuffix))
+		})
+	}
+}
@@ -0,0 +1,410 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package parser extracts fields from the log events of a file. The parsers
+// are applied in order, each adding the fields it extracted, and the event is
+// replaced by the fields as a JSON object, optionally with the EMF metadata
+// turning some of the fields into metrics.
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	TypeRegex = "regex"
+	TypeJSON  = "json"
+
+	// FieldSeparator joins the keys of the nested JSON objects when they are
+	// flattened, e.g. {"http": {"status": 200}} becomes http.status.
+	FieldSeparator = "."
+
+	// StructuredMessageKey is the field of the structured events with the
+	// original event.
+	StructuredMessageKey = "message"
+	// MaxIndexedFields is the number of fields a field index policy can have.
+	MaxIndexedFields = 20
+
+	emfMetadataKey = "_aws"
+	// epochMillisThreshold separates the epoch timestamps in seconds from
+	// the ones in milliseconds. It is in 1973 as milliseconds and in 5138 as
+	// seconds.
+	epochMillisThreshold = 1e11
+)
+
+// Parser is a stage of the parsing pipeline.
+type Parser struct {
+	// Type is either regex or json.
+	Type string `toml:"type"`
+	// Expression is the regular expression with the named captures
+	// extracted as fields. Only used by the regex parsers.
+	Expression string `toml:"expression"`
+	// Source is the field parsed instead of the log event, e.g. the JSON
+	// payload captured by a previous regex parser. A JSON source field is
+	// replaced by its flattened fields.
+	Source string `toml:"source"`
+
+	expressionP *regexp.Regexp
+}
+
+// EMFMetric is a field published as a metric.
+type EMFMetric struct {
+	Name string `toml:"name"`
+	Unit string `toml:"unit"`
+}
+
+// EMF converts the parsed events to the embedded metric format.
+type EMF struct {
+	Namespace string `toml:"namespace"`
+	// Dimensions are the dimension sets. A set is only used by the events
+	// with all of its fields.
+	Dimensions [][]string   `toml:"dimensions"`
+	Metrics    []*EMFMetric `toml:"metrics"`
+}
+
+// Structured publishes the events as JSON objects with the original event in
+// the message field and only the selected parsed fields next to it, so that
+// the events of a file have the same shape whether the parsers matched or not.
+type Structured struct {
+	// Fields are the parsed fields promoted to the top level of the events.
+	Fields []string `toml:"fields"`
+	// FieldIndex adds the fields to the field index policy of the log group.
+	FieldIndex bool `toml:"field_index"`
+}
+
+// Config is the parsing configuration of a file.
+type Config struct {
+	Parsers []*Parser `toml:"parsers"`
+	// TimestampField overrides the timestamp of the event with the value of
+	// the field.
+	TimestampField string `toml:"timestamp_field"`
+	// TimestampLayout are the Go layouts tried to parse the timestamp field.
+	// RFC 3339 is used if empty. Numbers are parsed as epoch seconds or
+	// milliseconds.
+	TimestampLayout []string `toml:"timestamp_layout"`
+	// Timezone is either UTC or Local, used by the layouts without zone.
+	Timezone string `toml:"timezone"`
+	EMF      *EMF   `toml:"emf"`
+	// Structured parses the events as JSON objects if there is no parser.
+	Structured *Structured `toml:"structured_json"`
+}
+
+// Pipeline parses the log events of a file.
+type Pipeline struct {
+	parsers        []*Parser
+	timestampField string
+	layouts        []string
+	location       *time.Location
+	emf            *EMF
+	structured     *Structured
+}
+
+// New returns nil if the config does not have any parser.
+func New(cfg Config) (*Pipeline, error) {
+	if err := cfg.Structured.validate(cfg.EMF); err != nil {
+		return nil, err
+	}
+	if len(cfg.Parsers) == 0 && cfg.Structured != nil {
+		cfg.Parsers = []*Parser{{Type: TypeJSON}}
+	}
+	if len(cfg.Parsers) == 0 {
+		if cfg.TimestampField != "" || cfg.EMF != nil {
+			return nil, errors.New("timestamp_field and emf require parsers")
+		}
+		return nil, nil
+	}
+	for _, p := range cfg.Parsers {
+		if err := p.init(); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.EMF.validate(); err != nil {
+		return nil, err
+	}
+	pipeline := &Pipeline{
+		parsers:        cfg.Parsers,
+		timestampField: cfg.TimestampField,
+		layouts:        cfg.TimestampLayout,
+		location:       time.Local,
+		emf:            cfg.EMF,
+		structured:     cfg.Structured,
+	}
+	if cfg.Timezone == time.UTC.String() {
+		pipeline.location = time.UTC
+	}
+	if len(pipeline.layouts) == 0 {
+		pipeline.layouts = []string{time.RFC3339Nano}
+	}
+	return pipeline, nil
+}
+
+func (p *Parser) init() error {
+	switch p.Type {
+	case TypeRegex:
+		var err error
+		if p.expressionP, err = regexp.Compile(p.Expression); err != nil {
+			return fmt.Errorf("parser regex has issue, regexp: Compile( %v ): %v", p.Expression, err.Error())
+		}
+		for _, name := range p.expressionP.SubexpNames() {
+			if name != "" {
+				return nil
+			}
+		}
+		return fmt.Errorf("parser regex %v does not have any named capture, e.g. (?P<level>\\w+)", p.Expression)
+	case TypeJSON:
+		if p.Expression != "" {
+			return errors.New("expression is only supported by the regex parsers")
+		}
+		return nil
+	default:
+		return fmt.Errorf("parser type %s is incorrect, valid types are: %v", p.Type, []string{TypeRegex, TypeJSON})
+	}
+}
+
+func (e *EMF) validate() error {
+	if e == nil {
+		return nil
+	}
+	if e.Namespace == "" {
+		return errors.New("emf namespace must be set")
+	}
+	if len(e.Metrics) == 0 {
+		return errors.New("emf metrics must not be empty")
+	}
+	metrics := make(map[string]bool, len(e.Metrics))
+	for _, metric := range e.Metrics {
+		if metric.Name == "" {
+			return errors.New("emf metric name must be set")
+		}
+		metrics[metric.Name] = true
+	}
+	for _, set := range e.Dimensions {
+		if len(set) == 0 || len(set) > 30 {
+			return errors.New("emf dimension sets must have between 1 and 30 dimensions")
+		}
+		for _, dimension := range set {
+			if metrics[dimension] {
+				return fmt.Errorf("emf field %s cannot be both a metric and a dimension", dimension)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Structured) validate(emf *EMF) error {
+	if s == nil {
+		return nil
+	}
+	if emf != nil {
+		return errors.New("structured_json and emf cannot be used together")
+	}
+	if len(s.Fields) == 0 {
+		return errors.New("structured_json fields must not be empty")
+	}
+	if s.FieldIndex && len(s.Fields) > MaxIndexedFields {
+		return fmt.Errorf("structured_json can index up to %d fields", MaxIndexedFields)
+	}
+	for _, field := range s.Fields {
+		if field == "" || field == StructuredMessageKey {
+			return fmt.Errorf("structured_json field %q is reserved", field)
+		}
+	}
+	return nil
+}
+
+// IndexedFields returns the fields to add to the field index policy of the
+// log group, if any.
+func (p *Pipeline) IndexedFields() []string {
+	if p == nil || p.structured == nil || !p.structured.FieldIndex {
+		return nil
+	}
+	return p.structured.Fields
+}
+
+// Parse returns the parsed event and the timestamp of the timestamp field.
+// It returns false if none of the parsers extracted any field, in which case
+// the event is published unchanged, unless it is structured. The timestamp is
+// zero if the field is missing or could not be parsed.
+func (p *Pipeline) Parse(msg string) (string, time.Time, bool) {
+	fields := map[string]interface{}{}
+	for _, parser := range p.parsers {
+		input := msg
+		if parser.Source != "" {
+			value, ok := fields[parser.Source].(string)
+			if !ok {
+				continue
+			}
+			input = value
+		}
+		parser.parse(input, fields)
+	}
+	if len(fields) == 0 && p.structured == nil {
+		return msg, time.Time{}, false
+	}
+	var timestamp time.Time
+	if p.timestampField != "" {
+		timestamp = p.timestamp(fields[p.timestampField])
+	}
+	if p.emf != nil {
+		p.emf.apply(fields, timestamp)
+	}
+	if p.structured != nil {
+		fields = p.structured.promote(msg, fields)
+	}
+	content, err := json.Marshal(fields)
+	if err != nil {
+		return msg, time.Time{}, false
+	}
+	return string(content), timestamp, true
+}
+
+// promote returns the structured event with the selected fields.
+func (s *Structured) promote(msg string, fields map[string]interface{}) map[string]interface{} {
+	event := map[string]interface{}{StructuredMessageKey: msg}
+	for _, field := range s.Fields {
+		if value, ok := fields[field]; ok {
+			event[field] = value
+		}
+	}
+	return event
+}
+
+func (p *Parser) parse(input string, fields map[string]interface{}) {
+	switch p.Type {
+	case TypeRegex:
+		match := p.expressionP.FindStringSubmatch(input)
+		if match == nil {
+			return
+		}
+		for i, name := range p.expressionP.SubexpNames() {
+			if name != "" && match[i] != "" {
+				fields[name] = match[i]
+			}
+		}
+	case TypeJSON:
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(input), &object); err != nil {
+			return
+		}
+		if p.Source != "" {
+			delete(fields, p.Source)
+		}
+		flatten("", object, fields)
+	}
+}
+
+// flatten adds the fields of the nested objects with their keys joined by
+// the separator. The arrays are kept as is.
+func flatten(prefix string, object map[string]interface{}, fields map[string]interface{}) {
+	for key, value := range object {
+		if prefix != "" {
+			key = prefix + FieldSeparator + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flatten(key, nested, fields)
+			continue
+		}
+		fields[key] = value
+	}
+}
+
+func (p *Pipeline) timestamp(value interface{}) time.Time {
+	switch v := value.(type) {
+	case float64:
+		return epoch(v)
+	case string:
+		for _, layout := range p.layouts {
+			if t, err := time.ParseInLocation(layout, v, p.location); err == nil {
+				return t
+			}
+		}
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return epoch(f)
+		}
+	}
+	return time.Time{}
+}
+
+func epoch(value float64) time.Time {
+	if value >= epochMillisThreshold {
+		return time.UnixMilli(int64(value))
+	}
+	return time.UnixMilli(int64(value * 1000))
+}
+
+// apply converts the metric fields to numbers and adds the EMF metadata for
+// the metrics and dimension sets found in the fields. The metadata is not
+// added if none of the metrics are.
+func (e *EMF) apply(fields map[string]interface{}, timestamp time.Time) {
+	var metrics []map[string]string
+	for _, metric := range e.Metrics {
+		value, ok := number(fields[metric.Name])
+		if !ok {
+			continue
+		}
+		fields[metric.Name] = value
+		definition := map[string]string{"Name": metric.Name}
+		if metric.Unit != "" {
+			definition["Unit"] = metric.Unit
+		}
+		metrics = append(metrics, definition)
+	}
+	if len(metrics) == 0 {
+		return
+	}
+	dimensions := [][]string{}
+	for _, set := range e.Dimensions {
+		if hasDimensions(fields, set) {
+			dimensions = append(dimensions, set)
+		}
+	}
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	fields[emfMetadataKey] = map[string]interface{}{
+		"Timestamp": timestamp.UnixMilli(),
+		"CloudWatchMetrics": []map[string]interface{}{{
+			"Namespace":  e.Namespace,
+			"Dimensions": dimensions,
+			"Metrics":    metrics,
+		}},
+	}
+}
+
+// hasDimensions returns true if all the dimensions of the set are scalar
+// fields. The values are converted to strings as required by EMF.
+func hasDimensions(fields map[string]interface{}, set []string) bool {
+	values := make([]string, len(set))
+	for i, dimension := range set {
+		switch v := fields[dimension].(type) {
+		case string:
+			values[i] = v
+		case float64:
+			values[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			values[i] = strconv.FormatBool(v)
+		default:
+			return false
+		}
+	}
+	for i, dimension := range set {
+		fields[dimension] = values[i]
+	}
+	return true
+}
+
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
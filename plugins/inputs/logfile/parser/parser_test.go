@@ -0,0 +1,274 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	testCases := map[string]struct {
+		cfg     Config
+		wantNil bool
+		wantErr bool
+	}{
+		"WithoutParsers": {
+			wantNil: true,
+		},
+		"WithTimestampFieldWithoutParsers": {
+			cfg:     Config{TimestampField: "time"},
+			wantErr: true,
+		},
+		"WithValid": {
+			cfg: Config{
+				Parsers: []*Parser{{Type: TypeRegex, Expression: `^(?P<level>\w+)`}, {Type: TypeJSON}},
+				EMF:     &EMF{Namespace: "App", Metrics: []*EMFMetric{{Name: "latency"}}, Dimensions: [][]string{{"level"}}},
+			},
+		},
+		"WithInvalidType": {
+			cfg:     Config{Parsers: []*Parser{{Type: "grok"}}},
+			wantErr: true,
+		},
+		"WithInvalidRegex": {
+			cfg:     Config{Parsers: []*Parser{{Type: TypeRegex, Expression: `(?P<level>\w+`}}},
+			wantErr: true,
+		},
+		"WithoutNamedCapture": {
+			cfg:     Config{Parsers: []*Parser{{Type: TypeRegex, Expression: `^(\w+)`}}},
+			wantErr: true,
+		},
+		"WithJSONExpression": {
+			cfg:     Config{Parsers: []*Parser{{Type: TypeJSON, Expression: `.*`}}},
+			wantErr: true,
+		},
+		"WithoutEMFNamespace": {
+			cfg:     Config{Parsers: []*Parser{{Type: TypeJSON}}, EMF: &EMF{Metrics: []*EMFMetric{{Name: "latency"}}}},
+			wantErr: true,
+		},
+		"WithoutEMFMetrics": {
+			cfg:     Config{Parsers: []*Parser{{Type: TypeJSON}}, EMF: &EMF{Namespace: "App"}},
+			wantErr: true,
+		},
+		"WithStructuredWithoutParsers": {
+			cfg: Config{Structured: &Structured{Fields: []string{"level"}}},
+		},
+		"WithStructuredAndEMF": {
+			cfg: Config{
+				Parsers:    []*Parser{{Type: TypeJSON}},
+				EMF:        &EMF{Namespace: "App", Metrics: []*EMFMetric{{Name: "latency"}}},
+				Structured: &Structured{Fields: []string{"level"}},
+			},
+			wantErr: true,
+		},
+		"WithoutStructuredFields": {
+			cfg:     Config{Structured: &Structured{}},
+			wantErr: true,
+		},
+		"WithStructuredMessageField": {
+			cfg:     Config{Structured: &Structured{Fields: []string{"level", StructuredMessageKey}}},
+			wantErr: true,
+		},
+		"WithTooManyIndexedFields": {
+			cfg: Config{Structured: &Structured{
+				Fields:     []string{"f1", "f2", "f3", "f4", "f5", "f6", "f7", "f8", "f9", "f10", "f11", "f12", "f13", "f14", "f15", "f16", "f17", "f18", "f19", "f20", "f21"},
+				FieldIndex: true,
+			}},
+			wantErr: true,
+		},
+		"WithEMFMetricDimension": {
+			cfg: Config{
+				Parsers: []*Parser{{Type: TypeJSON}},
+				EMF:     &EMF{Namespace: "App", Metrics: []*EMFMetric{{Name: "latency"}}, Dimensions: [][]string{{"latency"}}},
+			},
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := New(testCase.cfg)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.wantNil, got == nil)
+		})
+	}
+}
+
+func parse(t *testing.T, cfg Config, msg string) (map[string]interface{}, time.Time, bool) {
+	t.Helper()
+	p, err := New(cfg)
+	require.NoError(t, err)
+	out, timestamp, ok := p.Parse(msg)
+	if !ok {
+		assert.Equal(t, msg, out)
+		return nil, timestamp, false
+	}
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &fields))
+	return fields, timestamp, true
+}
+
+func TestParseRegex(t *testing.T) {
+	cfg := Config{Parsers: []*Parser{{Type: TypeRegex, Expression: `^(?P<time>\S+) (?P<level>\w+) (?:user=(?P<user>\w+) )?(?P<message>.*)$`}}}
+	fields, _, ok := parse(t, cfg, "2024-01-01T00:00:00Z ERROR disk full")
+	require.True(t, ok)
+	// empty captures are not extracted
+	assert.Equal(t, map[string]interface{}{
+		"time":    "2024-01-01T00:00:00Z",
+		"level":   "ERROR",
+		"message": "disk full",
+	}, fields)
+
+	_, _, ok = parse(t, cfg, "not matching")
+	assert.False(t, ok)
+}
+
+func TestParseJSON(t *testing.T) {
+	cfg := Config{Parsers: []*Parser{
+		{Type: TypeRegex, Expression: `^(?P<level>\w+) (?P<payload>\{.*\})$`},
+		{Type: TypeJSON, Source: "payload"},
+	}}
+	fields, _, ok := parse(t, cfg, `INFO {"http": {"method": "GET", "status": 200}, "tags": ["a"]}`)
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{
+		"level":       "INFO",
+		"http.method": "GET",
+		"http.status": float64(200),
+		"tags":        []interface{}{"a"},
+	}, fields)
+
+	// the source is kept if it is not JSON
+	fields, _, ok = parse(t, cfg, `INFO {not json}`)
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"level": "INFO", "payload": "{not json}"}, fields)
+
+	fields, _, ok = parse(t, Config{Parsers: []*Parser{{Type: TypeJSON}}}, `{"a": {"b": {"c": true}}}`)
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"a.b.c": true}, fields)
+}
+
+func TestParseTimestamp(t *testing.T) {
+	testCases := map[string]struct {
+		cfg  Config
+		msg  string
+		want time.Time
+	}{
+		"WithRFC3339": {
+			msg:  `{"time": "2024-01-02T03:04:05.678Z"}`,
+			want: time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.UTC),
+		},
+		"WithLayout": {
+			cfg:  Config{TimestampLayout: []string{"02/01/2006 15:04:05"}, Timezone: "UTC"},
+			msg:  `{"time": "02/01/2024 03:04:05"}`,
+			want: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		"WithEpochSeconds": {
+			msg:  `{"time": 1704164645.5}`,
+			want: time.UnixMilli(1704164645500),
+		},
+		"WithEpochMillis": {
+			msg:  `{"time": "1704164645678"}`,
+			want: time.UnixMilli(1704164645678),
+		},
+		"WithInvalid": {
+			msg: `{"time": "yesterday"}`,
+		},
+		"WithMissing": {
+			msg: `{"level": "INFO"}`,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg := testCase.cfg
+			cfg.Parsers = []*Parser{{Type: TypeJSON}}
+			cfg.TimestampField = "time"
+			_, got, ok := parse(t, cfg, testCase.msg)
+			require.True(t, ok)
+			assert.True(t, testCase.want.Equal(got), "want %v, got %v", testCase.want, got)
+		})
+	}
+}
+
+func TestParseEMF(t *testing.T) {
+	cfg := Config{
+		Parsers:        []*Parser{{Type: TypeRegex, Expression: `^(?P<time>\d+) (?P<method>\w+) (?P<status>\d+) (?P<latency>\S+)$`}},
+		TimestampField: "time",
+		EMF: &EMF{
+			Namespace:  "App",
+			Dimensions: [][]string{{"method"}, {"method", "region"}},
+			Metrics:    []*EMFMetric{{Name: "latency", Unit: "Milliseconds"}, {Name: "bytes"}},
+		},
+	}
+	fields, timestamp, ok := parse(t, cfg, "1704164645678 GET 200 12.5")
+	require.True(t, ok)
+	assert.Equal(t, time.UnixMilli(1704164645678), timestamp)
+	assert.Equal(t, map[string]interface{}{
+		"time":    "1704164645678",
+		"method":  "GET",
+		"status":  "200",
+		"latency": 12.5,
+		"_aws": map[string]interface{}{
+			"Timestamp": float64(1704164645678),
+			"CloudWatchMetrics": []interface{}{
+				map[string]interface{}{
+					"Namespace":  "App",
+					"Dimensions": []interface{}{[]interface{}{"method"}},
+					"Metrics": []interface{}{
+						map[string]interface{}{"Name": "latency", "Unit": "Milliseconds"},
+					},
+				},
+			},
+		},
+	}, fields)
+
+	// the metadata is not added without any metric
+	fields, _, ok = parse(t, cfg, "1704164645678 GET 200 -")
+	require.True(t, ok)
+	assert.NotContains(t, fields, emfMetadataKey)
+}
+
+func TestParseStructured(t *testing.T) {
+	cfg := Config{
+		Parsers:    []*Parser{{Type: TypeRegex, Expression: `^(?P<level>\w+) (?P<requestId>\S+) (?P<detail>.*)$`}},
+		Structured: &Structured{Fields: []string{"level", "requestId"}, FieldIndex: true},
+	}
+	fields, _, ok := parse(t, cfg, "ERROR req-1 disk full")
+	require.True(t, ok)
+	// the fields that are not selected are dropped
+	assert.Equal(t, map[string]interface{}{
+		"message":   "ERROR req-1 disk full",
+		"level":     "ERROR",
+		"requestId": "req-1",
+	}, fields)
+
+	// the events the parsers do not match are structured as well
+	fields, _, ok = parse(t, cfg, "not matching")
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"message": "not matching"}, fields)
+
+	p, err := New(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"level", "requestId"}, p.IndexedFields())
+
+	// the events are parsed as JSON without parsers
+	cfg = Config{Structured: &Structured{Fields: []string{"http.status"}}}
+	fields, _, ok = parse(t, cfg, `{"http": {"status": 500}, "user": "a"}`)
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{
+		"message":     `{"http": {"status": 500}, "user": "a"}`,
+		"http.status": float64(500),
+	}, fields)
+
+	p, err = New(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, p.IndexedFields())
+	assert.Nil(t, (*Pipeline)(nil).IndexedFields())
+}
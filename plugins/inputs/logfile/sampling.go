@@ -0,0 +1,113 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+	"github.com/aws/amazon-cloudwatch-agent/profiler"
+)
+
+const (
+	defaultSamplingLevelField = "level"
+)
+
+// Sampling reduces the rate of the log events of a collect_list entry, e.g. a
+// flood of debug logs. It is shared by all the files of the entry, so the
+// rate limit applies to the events of all the files.
+type Sampling struct {
+	//The fraction of the events published, between 0 and 1. Defaults to 1.
+	SampleRate float64 `toml:"sample_rate"`
+	//The events published per second once sampled. 0 does not limit the rate.
+	RateLimitPerSecond float64 `toml:"rate_limit_per_second"`
+	//The events published at once above the rate limit. Defaults to a second of events.
+	Burst int `toml:"burst"`
+	//The parsed field with the severity of the events. Defaults to "level".
+	LevelField string `toml:"level_field"`
+	//The sample rates of the severities, replacing the sample rate for the events with
+	//the level field. The severities are not case-sensitive.
+	LevelSampleRates map[string]float64 `toml:"level_sample_rates"`
+
+	limiter *rate.Limiter
+	random  func() float64
+	now     func() time.Time
+	// limited is set once the rate limit was logged.
+	limited atomic.Bool
+}
+
+func (s *Sampling) init() error {
+	if s.SampleRate == 0 {
+		s.SampleRate = 1
+	}
+	if s.SampleRate < 0 || s.SampleRate > 1 {
+		return fmt.Errorf("sampling sample_rate %v is invalid, it must be between 0 and 1", s.SampleRate)
+	}
+	levelSampleRates := make(map[string]float64, len(s.LevelSampleRates))
+	for level, sampleRate := range s.LevelSampleRates {
+		if sampleRate < 0 || sampleRate > 1 {
+			return fmt.Errorf("sampling level_sample_rates %s %v is invalid, it must be between 0 and 1", level, sampleRate)
+		}
+		levelSampleRates[strings.ToLower(level)] = sampleRate
+	}
+	s.LevelSampleRates = levelSampleRates
+	if len(s.LevelSampleRates) > 0 && s.LevelField == "" {
+		s.LevelField = defaultSamplingLevelField
+	}
+	if s.RateLimitPerSecond < 0 || s.Burst < 0 {
+		return fmt.Errorf("sampling rate_limit_per_second %v and burst %d must not be negative", s.RateLimitPerSecond, s.Burst)
+	}
+	if s.RateLimitPerSecond > 0 {
+		if s.Burst == 0 {
+			s.Burst = int(math.Ceil(s.RateLimitPerSecond))
+		}
+		s.limiter = rate.NewLimiter(rate.Limit(s.RateLimitPerSecond), s.Burst)
+	}
+	s.random = rand.Float64
+	s.now = time.Now
+	return nil
+}
+
+// usesLevel returns true if the sample rate depends on the parsed severity
+// of the events.
+func (s *Sampling) usesLevel() bool {
+	return len(s.LevelSampleRates) > 0
+}
+
+// sample returns true if the event is published. The fields are the parsed
+// fields of the event, with its severity. The dropped events are counted by
+// the sampled_log_events metric of the logfile component.
+func (s *Sampling) sample(logGroupName, logStreamName string, fields map[string]interface{}) bool {
+	sampleRate := s.SampleRate
+	if level, ok := fields[s.LevelField].(string); ok {
+		if levelSampleRate, ok := s.LevelSampleRates[strings.ToLower(level)]; ok {
+			sampleRate = levelSampleRate
+		}
+	}
+	if sampleRate < 1 && s.random() >= sampleRate {
+		s.drop(logGroupName, logStreamName, "sampled")
+		return false
+	}
+	if s.limiter != nil && !s.limiter.AllowN(s.now(), 1) {
+		if !s.limited.Swap(true) {
+			log.Printf("W! The log events of %s/%s exceed the rate limit of %v events per second, the events above it are dropped", logGroupName, logStreamName, s.RateLimitPerSecond)
+		}
+		s.drop(logGroupName, logStreamName, "rate_limited")
+		return false
+	}
+	return true
+}
+
+func (s *Sampling) drop(logGroupName, logStreamName, reason string) {
+	selftelemetry.Add(selftelemetry.MetricSampledLogEvents, "logfile", 1)
+	profiler.Profiler.AddStats([]string{"logfile", logGroupName, logStreamName, "messages", reason}, 1)
+}
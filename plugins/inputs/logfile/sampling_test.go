@@ -0,0 +1,94 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+func sampledLogEvents() float64 {
+	for _, sample := range selftelemetry.Default.Collect() {
+		if sample.Name == selftelemetry.MetricSampledLogEvents && sample.Component == "logfile" {
+			return sample.Value
+		}
+	}
+	return 0
+}
+
+func TestSamplingInit(t *testing.T) {
+	s := &Sampling{RateLimitPerSecond: 2.5, LevelSampleRates: map[string]float64{"DEBUG": 0}}
+	require.NoError(t, s.init())
+	assert.Equal(t, 1.0, s.SampleRate)
+	assert.Equal(t, 3, s.Burst)
+	assert.Equal(t, defaultSamplingLevelField, s.LevelField)
+	assert.Equal(t, map[string]float64{"debug": 0}, s.LevelSampleRates)
+
+	testCases := map[string]*Sampling{
+		"WithInvalidSampleRate":      {SampleRate: 1.5},
+		"WithInvalidLevelSampleRate": {LevelSampleRates: map[string]float64{"info": -0.1}},
+		"WithNegativeRateLimit":      {RateLimitPerSecond: -1},
+		"WithNegativeBurst":          {RateLimitPerSecond: 1, Burst: -1},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, testCase.init())
+		})
+	}
+}
+
+func TestSamplingSampleRate(t *testing.T) {
+	s := &Sampling{SampleRate: 0.25, LevelSampleRates: map[string]float64{"debug": 0, "error": 1}}
+	require.NoError(t, s.init())
+	randoms := []float64{0.1, 0.3, 0.5, 0.2}
+	s.random = func() float64 {
+		r := randoms[0]
+		randoms = append(randoms[1:], r)
+		return r
+	}
+	before := sampledLogEvents()
+	var published int
+	for i := 0; i < 4; i++ {
+		if s.sample("group", "stream", nil) {
+			published++
+		}
+	}
+	assert.Equal(t, 2, published)
+	assert.Equal(t, before+2, sampledLogEvents())
+
+	// the severities are not case-sensitive, and the others use the sample rate
+	randoms = []float64{0.1, 0.1, 0.9}
+	assert.False(t, s.sample("group", "stream", map[string]interface{}{"level": "DEBUG"}))
+	assert.True(t, s.sample("group", "stream", map[string]interface{}{"level": "Error"}))
+	assert.True(t, s.sample("group", "stream", map[string]interface{}{"level": "warn"}))
+	assert.False(t, s.sample("group", "stream", map[string]interface{}{"level": "warn"}))
+}
+
+func TestSamplingRateLimit(t *testing.T) {
+	s := &Sampling{RateLimitPerSecond: 2, Burst: 3}
+	require.NoError(t, s.init())
+	now := time.Unix(1704164645, 0)
+	s.now = func() time.Time { return now }
+	before := sampledLogEvents()
+	var published int
+	for i := 0; i < 5; i++ {
+		if s.sample("group", "stream", nil) {
+			published++
+		}
+	}
+	// the burst is published at once, then the events above the rate are dropped
+	assert.Equal(t, 3, published)
+	assert.Equal(t, before+2, sampledLogEvents())
+	assert.True(t, s.limited.Load())
+
+	now = now.Add(time.Second)
+	assert.True(t, s.sample("group", "stream", nil))
+	assert.True(t, s.sample("group", "stream", nil))
+	assert.False(t, s.sample("group", "stream", nil))
+}
@@ -6,22 +6,28 @@ package logfile
 import (
 	"bytes"
 	"log"
+	"math"
 	"os"
-	"strconv"
 	"sync"
 	"time"
 
 	"golang.org/x/text/encoding"
 
 	"github.com/aws/amazon-cloudwatch-agent/extension/entitystore"
+	"github.com/aws/amazon-cloudwatch-agent/internal/backpressure"
+	"github.com/aws/amazon-cloudwatch-agent/internal/filestate"
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/parser"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/tail"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
 )
 
 const (
-	stateFileMode = 0644
-	bufferLimit   = 50
+	bufferLimit = 50
+	// multilineWaitTicks is the number of wait periods after which a multiline
+	// event is published when it has no timeout.
+	multilineWaitTicks = 5
 )
 
 var (
@@ -44,8 +50,14 @@ type LogEvent struct {
 	t      time.Time
 	offset fileOffset
 	src    *tailerSrc
+	// group and stream are resolved from the fields of the log line.
+	group  string
+	stream string
 }
 
+// Verify LogEvent implements RoutedLogEvent
+var _ logs.RoutedLogEvent = (*LogEvent)(nil)
+
 func (le LogEvent) Message() string {
 	return le.msg
 }
@@ -58,35 +70,67 @@ func (le LogEvent) Done() {
 	le.src.Done(le.offset)
 }
 
+func (le LogEvent) Group() string {
+	return le.group
+}
+
+func (le LogEvent) Stream() string {
+	return le.stream
+}
+
 type tailerSrc struct {
-	group           string
-	stream          string
-	class           string
-	fileGlobPath    string
-	destination     string
-	stateFilePath   string
-	tailer          *tail.Tail
-	autoRemoval     bool
-	timestampFn     func(string) time.Time
-	enc             encoding.Encoding
-	maxEventSize    int
-	truncateSuffix  string
-	retentionInDays int
+	group            string
+	stream           string
+	class            string
+	roleARN          string
+	region           string
+	logGroupSettings logs.LogGroupSettings
+	eventSizeLimit   logs.EventSizeLimit
+	fileGlobPath     string
+	destination      string
+	stateStore       *filestate.Store
+	tailer           *tail.Tail
+	autoRemoval      bool
+	timestampFn      func(string) time.Time
+	enc              encoding.Encoding
+	maxEventSize     int
+	truncateSuffix   string
+	retentionInDays  int
+	groupTemplate    *logsource.Template
+	streamTemplate   *logsource.Template
+	parser           *parser.Pipeline
+	emfValidator     *emfValidator
+	sampling         *Sampling
+	memoryGate       *backpressure.MemoryGate
 
 	outputFn        func(logs.LogEvent)
 	isMLStart       func(string) bool
 	filters         []*LogFilter
 	offsetCh        chan fileOffset
 	done            chan struct{}
+	stateSaved      chan struct{}
+	stopOnce        sync.Once
 	startTailerOnce sync.Once
 	cleanUpFns      []func()
+
+	// isMLEnd, maxLines and multilineTimeout are set for the multiline events
+	// joined like filebeat.
+	isMLEnd          func(string) bool
+	maxLines         int
+	multilineTimeout time.Duration
 }
 
 // Verify tailerSrc implements LogSrc
 var _ logs.LogSrc = (*tailerSrc)(nil)
+var _ logs.LogCredentialProvider = (*tailerSrc)(nil)
+var _ logs.LogFieldIndexProvider = (*tailerSrc)(nil)
+var _ logs.LogGroupSettingsProvider = (*tailerSrc)(nil)
+var _ logs.LogEventSizeProvider = (*tailerSrc)(nil)
 
 func NewTailerSrc(
-	group, stream, destination, stateFilePath, logClass, fileGlobPath string,
+	group, stream, destination string,
+	stateStore *filestate.Store,
+	logClass, fileGlobPath string,
 	tailer *tail.Tail,
 	autoRemoval bool,
 	isMultilineStartFn func(string) bool,
@@ -98,10 +142,8 @@ func NewTailerSrc(
 	retentionInDays int,
 ) *tailerSrc {
 	ts := &tailerSrc{
-		group:           group,
-		stream:          stream,
 		destination:     destination,
-		stateFilePath:   stateFilePath,
+		stateStore:      stateStore,
 		class:           logClass,
 		fileGlobPath:    fileGlobPath,
 		tailer:          tailer,
@@ -114,9 +156,17 @@ func NewTailerSrc(
 		truncateSuffix:  truncateSuffix,
 		retentionInDays: retentionInDays,
 
-		offsetCh: make(chan fileOffset, 2000),
-		done:     make(chan struct{}),
+		offsetCh:   make(chan fileOffset, 2000),
+		done:       make(chan struct{}),
+		stateSaved: make(chan struct{}),
 	}
+	// The sources with names referencing fields use the rest of the name for the events
+	// without the fields.
+	ts.groupTemplate, ts.group = logsource.NewGroupTemplate(group, fieldReferenceRegexp)
+	if ts.groupTemplate != nil && ts.group == "" {
+		ts.group = logGroupName(fileGlobPath)
+	}
+	ts.streamTemplate, ts.stream = logsource.NewStreamTemplate(stream, fieldReferenceRegexp)
 	go ts.runSaveState()
 	return ts
 }
@@ -152,6 +202,27 @@ func (ts *tailerSrc) Retention() int {
 func (ts *tailerSrc) Class() string {
 	return ts.class
 }
+
+func (ts *tailerSrc) RoleARN() string {
+	return ts.roleARN
+}
+
+func (ts *tailerSrc) Region() string {
+	return ts.region
+}
+
+func (ts *tailerSrc) LogGroupSettings() logs.LogGroupSettings {
+	return ts.logGroupSettings
+}
+
+func (ts *tailerSrc) EventSizeLimit() logs.EventSizeLimit {
+	return ts.eventSizeLimit
+}
+
+func (ts *tailerSrc) FieldIndexes() []string {
+	return ts.parser.IndexedFields()
+}
+
 func (ts *tailerSrc) Done(offset fileOffset) {
 	// ts.offsetCh will only be blocked when the runSaveState func has exited,
 	// which only happens when the original file has been removed, thus making
@@ -162,8 +233,13 @@ func (ts *tailerSrc) Done(offset fileOffset) {
 	}
 }
 
+// Stop stops the tailer src and returns once the last offset is saved in the
+// file state.
 func (ts *tailerSrc) Stop() {
-	close(ts.done)
+	ts.stopOnce.Do(func() { close(ts.done) })
+	if ts.stateSaved != nil {
+		<-ts.stateSaved
+	}
 }
 
 func (ts *tailerSrc) AddCleanUpFn(f func()) {
@@ -178,33 +254,75 @@ func (ts *tailerSrc) Entity() *cloudwatchlogs.Entity {
 	return nil
 }
 
+func (ts *tailerSrc) newEvent(msg string, offset fileOffset) *LogEvent {
+	return &LogEvent{
+		msg:    msg,
+		t:      ts.timestampFn(msg),
+		offset: offset,
+		src:    ts,
+	}
+}
+
+// publish sends the event to the output unless it is filtered out. The
+// filters match the log line as read from the file, so the event is only
+// parsed afterward, then sampled, and the group and stream are resolved from
+// the parsed fields.
+func (ts *tailerSrc) publish(msg string, offset fileOffset) {
+	e := ts.newEvent(msg, offset)
+	// Note: This only checks against the truncated log message, so it is not necessary to load
+	//       the entire log message for filtering.
+	if !ShouldPublish(ts.group, ts.stream, ts.filters, e) {
+		return
+	}
+	if ts.parser != nil {
+		if parsed, timestamp, ok := ts.parser.Parse(e.msg); ok {
+			e.msg = parsed
+			if !timestamp.IsZero() {
+				e.t = timestamp
+			}
+		}
+	}
+	var fields map[string]interface{}
+	if (ts.sampling != nil && ts.sampling.usesLevel()) || ts.groupTemplate != nil || ts.streamTemplate != nil {
+		fields = parseJSONFields(e.msg)
+	}
+	// the events are sampled once parsed, so the sample rate can depend on their severity
+	if ts.sampling != nil && !ts.sampling.sample(ts.group, ts.stream, fields) {
+		return
+	}
+	if ts.groupTemplate != nil {
+		e.group = resolveFields(ts.groupTemplate, fields)
+	}
+	if ts.streamTemplate != nil {
+		e.stream = resolveFields(ts.streamTemplate, fields)
+	}
+	if ts.emfValidator != nil {
+		ts.emfValidator.validate(e, ts.stream)
+	}
+	ts.outputFn(e)
+}
+
 func (ts *tailerSrc) runTail() {
 	defer ts.cleanUp()
 	t := time.NewTicker(multilineWaitPeriod)
 	defer t.Stop()
 	var init string
 	var msgBuf bytes.Buffer
-	var cnt int
+	var cnt, lines int
 	fo := &fileOffset{}
 
 	ignoreUntilNextEvent := false
 	for {
+		// Leave the lines in the file while the agent is using too much memory.
+		if ts.memoryGate != nil && !ts.memoryGate.Wait(ts.done) {
+			return
+		}
 
 		select {
 		case line, ok := <-ts.tailer.Lines:
 			if !ok {
 				if msgBuf.Len() > 0 {
-					msg := msgBuf.String()
-					e := &LogEvent{
-						msg:    msg,
-						t:      ts.timestampFn(msg),
-						offset: *fo,
-						src:    ts,
-					}
-
-					if ShouldPublish(ts.group, ts.stream, ts.filters, e) {
-						ts.outputFn(e)
-					}
+					ts.publish(msgBuf.String(), *fo)
 				}
 				return
 			}
@@ -232,9 +350,10 @@ func (ts *tailerSrc) runTail() {
 			} else if ts.isMLStart(text) || (!ignoreUntilNextEvent && msgBuf.Len() == 0) {
 				init = text
 				ignoreUntilNextEvent = false
-			} else if ignoreUntilNextEvent || msgBuf.Len() >= ts.maxEventSize {
+			} else if ignoreUntilNextEvent || msgBuf.Len() >= ts.maxEventSize || (ts.maxLines > 0 && lines >= ts.maxLines) {
 				ignoreUntilNextEvent = true
 				fo.SetOffset(line.Offset)
+				ts.publishIfMLEnd(text, &msgBuf, *fo, &ignoreUntilNextEvent)
 				continue
 			} else {
 				msgBuf.WriteString("\n")
@@ -243,48 +362,32 @@ func (ts *tailerSrc) runTail() {
 					msgBuf.Truncate(ts.maxEventSize - len(ts.truncateSuffix))
 					msgBuf.WriteString(ts.truncateSuffix)
 				}
+				lines++
 				fo.SetOffset(line.Offset)
+				ts.publishIfMLEnd(text, &msgBuf, *fo, &ignoreUntilNextEvent)
 				continue
 			}
 
 			if msgBuf.Len() > 0 {
-				msg := msgBuf.String()
-				e := &LogEvent{
-					msg:    msg,
-					t:      ts.timestampFn(msg),
-					offset: *fo,
-					src:    ts,
-				}
-				// Note: This only checks against the truncated log message, so it is not necessary to load
-				//       the entire log message for filtering.
-				if ShouldPublish(ts.group, ts.stream, ts.filters, e) {
-					ts.outputFn(e)
-				}
+				ts.publish(msgBuf.String(), *fo)
 			}
 
 			msgBuf.Reset()
 			msgBuf.WriteString(init)
 			fo.SetOffset(line.Offset)
 			cnt = 0
+			lines = 1
+			ts.publishIfMLEnd(text, &msgBuf, *fo, &ignoreUntilNextEvent)
 		case <-t.C:
 			if msgBuf.Len() > 0 {
 				cnt++
 			}
 
-			if cnt < 5 {
+			if cnt < ts.multilineTimeoutTicks() {
 				continue
 			}
 
-			msg := msgBuf.String()
-			e := &LogEvent{
-				msg:    msg,
-				t:      ts.timestampFn(msg),
-				offset: *fo,
-				src:    ts,
-			}
-			if ShouldPublish(ts.group, ts.stream, ts.filters, e) {
-				ts.outputFn(e)
-			}
+			ts.publish(msgBuf.String(), *fo)
 			msgBuf.Reset()
 			cnt = 0
 		case <-ts.done:
@@ -293,6 +396,28 @@ func (ts *tailerSrc) runTail() {
 	}
 }
 
+// publishIfMLEnd publishes the event in the buffer if the line ends it, for
+// the events whose continuing lines are prepended to the line after them.
+func (ts *tailerSrc) publishIfMLEnd(text string, msgBuf *bytes.Buffer, fo fileOffset, ignoreUntilNextEvent *bool) {
+	if ts.isMLEnd == nil || !ts.isMLEnd(text) {
+		return
+	}
+	if msgBuf.Len() > 0 {
+		ts.publish(msgBuf.String(), fo)
+	}
+	msgBuf.Reset()
+	*ignoreUntilNextEvent = false
+}
+
+// multilineTimeoutTicks returns the number of wait periods after which an
+// incomplete multiline event is published.
+func (ts *tailerSrc) multilineTimeoutTicks() int {
+	if ts.multilineTimeout <= 0 {
+		return multilineWaitTicks
+	}
+	return int(math.Ceil(float64(ts.multilineTimeout) / float64(multilineWaitPeriod)))
+}
+
 func (ts *tailerSrc) cleanUp() {
 	if ts.autoRemoval {
 		if err := os.Remove(ts.tailer.Filename); err != nil {
@@ -311,48 +436,53 @@ func (ts *tailerSrc) cleanUp() {
 }
 
 func (ts *tailerSrc) runSaveState() {
+	defer close(ts.stateSaved)
 	t := time.NewTicker(100 * time.Millisecond)
 	defer t.Stop()
 
 	var offset, lastSavedOffset fileOffset
+	update := func(o fileOffset) {
+		if o.seq > offset.seq || (o.seq == offset.seq && o.offset > offset.offset) {
+			offset = o
+		}
+	}
 	for {
 		select {
 		case o := <-ts.offsetCh:
-			if o.seq > offset.seq || (o.seq == offset.seq && o.offset > offset.offset) {
-				offset = o
-			}
+			update(o)
 		case <-t.C:
 			if offset == lastSavedOffset {
 				continue
 			}
-			err := ts.saveState(offset.offset)
-			if err != nil {
-				log.Printf("E! [logfile] Error happened when saving file state %s to file state folder %s: %v", ts.tailer.Filename, ts.stateFilePath, err)
-				continue
-			}
+			ts.saveState(offset.offset)
 			lastSavedOffset = offset
 		case <-ts.tailer.FileDeletedCh:
-			log.Printf("W! [logfile] deleting state file %s", ts.stateFilePath)
-			err := os.Remove(ts.stateFilePath)
-			if err != nil {
-				log.Printf("W! [logfile] Error happened while deleting state file %s on cleanup: %v", ts.stateFilePath, err)
+			if ts.stateStore != nil {
+				log.Printf("W! [logfile] deleting file state of %s", ts.tailer.Filename)
+				ts.stateStore.Delete(ts.tailer.Filename)
 			}
 			return
 		case <-ts.done:
-			err := ts.saveState(offset.offset)
-			if err != nil {
-				log.Printf("E! [logfile] Error happened during final file state saving of logfile %s to file state folder %s, duplicate log maybe sent at next start: %v", ts.tailer.Filename, ts.stateFilePath, err)
+			// the offsets already done are saved as well
+			for len(ts.offsetCh) > 0 {
+				update(<-ts.offsetCh)
+			}
+			ts.saveState(offset.offset)
+			if ts.stateStore == nil {
+				return
+			}
+			if err := ts.stateStore.Flush(); err != nil {
+				log.Printf("E! [logfile] Error happened during final file state saving of logfile %s to file state %s, duplicate log maybe sent at next start: %v", ts.tailer.Filename, ts.stateStore.Path(), err)
 			}
 			return
 		}
 	}
 }
 
-func (ts *tailerSrc) saveState(offset int64) error {
-	if ts.stateFilePath == "" || offset == 0 {
-		return nil
+// saveState updates the offset in the file state, which is flushed by the logfile plugin.
+func (ts *tailerSrc) saveState(offset int64) {
+	if ts.stateStore == nil || offset == 0 {
+		return
 	}
-
-	content := []byte(strconv.FormatInt(offset, 10) + "\n" + ts.tailer.Filename)
-	return os.WriteFile(ts.stateFilePath, content, stateFileMode)
+	ts.stateStore.Set(ts.tailer.Filename, offset)
 }
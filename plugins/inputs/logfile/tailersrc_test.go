@@ -9,8 +9,8 @@ import (
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -19,17 +19,23 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/backpressure"
+	"github.com/aws/amazon-cloudwatch-agent/internal/filestate"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/parser"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/tail"
 	"github.com/aws/amazon-cloudwatch-agent/profiler"
 	"github.com/aws/amazon-cloudwatch-agent/tool/util"
 )
 
 type tailerTestResources struct {
-	done      *chan struct{}
-	consumed  *int32
-	file      *os.File
-	statefile *os.File
+	done     *chan struct{}
+	consumed *int32
+	file     *os.File
+}
+
+func newTestStateStore(t *testing.T) *filestate.Store {
+	return filestate.New(filepath.Join(t.TempDir(), stateStoreFileName), fileStateTTL)
 }
 
 func TestTailerSrc(t *testing.T) {
@@ -40,9 +46,7 @@ func TestTailerSrc(t *testing.T) {
 	defer os.Remove(file.Name())
 	require.NoError(t, err, fmt.Sprintf("Failed to create temp file: %v", err))
 
-	statefile, err := os.CreateTemp("", "tailsrctest-state-*.log")
-	defer os.Remove(statefile.Name())
-	require.NoError(t, err, fmt.Sprintf("Failed to create temp file: %v", err))
+	stateStore := newTestStateStore(t)
 	beforeCount := tail.OpenFileCount.Load()
 	tailer, err := tail.TailFile(file.Name(),
 		tail.Config{
@@ -60,7 +64,7 @@ func TestTailerSrc(t *testing.T) {
 	require.Equal(t, beforeCount+1, tail.OpenFileCount.Load())
 	ts := NewTailerSrc(
 		"groupName", "streamName",
-		"destination", statefile.Name(),
+		"destination", stateStore,
 		util.InfrequentAccessLogGroupClass,
 		"tailsrctest-*.log",
 		tailer,
@@ -151,9 +155,7 @@ func TestOffsetDoneCallBack(t *testing.T) {
 	defer os.Remove(file.Name())
 	require.NoError(t, err, fmt.Sprintf("Failed to create temp file: %v", err))
 
-	statefile, err := os.CreateTemp("", "tailsrctest-state-*.log")
-	defer os.Remove(statefile.Name())
-	require.NoError(t, err, fmt.Sprintf("Failed to create temp file: %v", err))
+	stateStore := newTestStateStore(t)
 
 	tailer, err := tail.TailFile(file.Name(),
 		tail.Config{
@@ -172,7 +174,7 @@ func TestOffsetDoneCallBack(t *testing.T) {
 	ts := NewTailerSrc(
 		"groupName", "streamName",
 		"destination",
-		statefile.Name(),
+		stateStore,
 		util.InfrequentAccessLogGroupClass,
 		"tailsrctest-*.log",
 		tailer,
@@ -201,31 +203,20 @@ func TestOffsetDoneCallBack(t *testing.T) {
 		case 10:
 			// Test before first truncate
 			time.Sleep(1 * time.Second)
-			b, err := os.ReadFile(statefile.Name())
-			require.NoError(t, err, fmt.Sprintf("Failed to read state file: %v", err))
-			offset, err := strconv.Atoi(string(bytes.Split(b, []byte("\n"))[0]))
-			require.NoError(t, err, fmt.Sprintf("Failed to parse offset: %v, from '%s'", err, b))
-			require.Equal(t, offset, 1010, fmt.Sprintf("Wrong offset %v is written to state file, expecting 1010", offset))
+			offset, ok := stateStore.Get(file.Name())
+			require.True(t, ok, "Missing offset in file state")
+			require.EqualValues(t, 1010, offset, fmt.Sprintf("Wrong offset %v is written to file state, expecting 1010", offset))
 		case 15:
 			// Test after first truncate, saved offset should decrease
 			time.Sleep(1 * time.Second)
-			log.Println(statefile.Name())
-			b, err := os.ReadFile(statefile.Name())
-			require.NoError(t, err, fmt.Sprintf("Failed to read state file: %v", err))
-			file_parts := bytes.Split(b, []byte("\n"))
-			log.Println("file_parts: ", file_parts)
-			file_string := string(file_parts[0])
-			log.Println("file_string: ", file_string)
-			offset, err := strconv.Atoi(file_string)
-			require.NoError(t, err, fmt.Sprintf("Failed to parse offset: %v, from '%s'", err, b))
-			require.Equal(t, offset, 505, fmt.Sprintf("Wrong offset %v is written to state file, after truncate and write shorter logs expecting 505", offset))
+			offset, ok := stateStore.Get(file.Name())
+			require.True(t, ok, "Missing offset in file state")
+			require.EqualValues(t, 505, offset, fmt.Sprintf("Wrong offset %v is written to file state, after truncate and write shorter logs expecting 505", offset))
 		case 35:
 			time.Sleep(1 * time.Second)
-			b, err := os.ReadFile(statefile.Name())
-			require.NoError(t, err, fmt.Sprintf("Failed to read state file: %v", err))
-			offset, err := strconv.Atoi(string(bytes.Split(b, []byte("\n"))[0]))
-			require.NoError(t, err, fmt.Sprintf("Failed to parse offset: %v, from '%s'", err, b))
-			require.Equal(t, offset, 2020, fmt.Sprintf("Wrong offset %v is written to state file, after truncate and write shorter logs expecting 2022", offset))
+			offset, ok := stateStore.Get(file.Name())
+			require.True(t, ok, "Missing offset in file state")
+			require.EqualValues(t, 2020, offset, fmt.Sprintf("Wrong offset %v is written to file state, after truncate and write shorter logs expecting 2022", offset))
 		}
 	})
 
@@ -271,6 +262,55 @@ func TestOffsetDoneCallBack(t *testing.T) {
 
 	<-done
 	require.GreaterOrEqual(t, i, 35, fmt.Sprintf("Not enough logs have been processed, only %v are processed", i))
+	// the offset of the removed file is deleted from the file state
+	assert.Eventually(t, func() bool {
+		_, ok := stateStore.Get(file.Name())
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestTailerSrcStopSavesState(t *testing.T) {
+	file, err := createTempFile("", "tailsrctest-*.log")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	stateStore := newTestStateStore(t)
+	tailer, err := tail.TailFile(file.Name(), tail.Config{
+		Follow:      true,
+		Location:    &tail.SeekInfo{Whence: io.SeekStart, Offset: 0},
+		MustExist:   true,
+		Poll:        true,
+		MaxLineSize: defaultMaxEventSize,
+	})
+	require.NoError(t, err)
+	defer tailer.Stop()
+
+	ts := NewTailerSrc(
+		"groupName", "streamName",
+		"destination",
+		stateStore,
+		util.StandardLogGroupClass,
+		"tailsrctest-*.log",
+		tailer,
+		false, // AutoRemoval
+		nil,
+		nil,
+		parseRFC3339Timestamp,
+		nil, // encoding
+		defaultMaxEventSize,
+		defaultTruncateSuffix,
+		1,
+	)
+	ts.Done(fileOffset{offset: 42})
+	ts.Stop()
+	// the offset is flushed by the time Stop returns, and stopping again is a no-op
+	ts.Stop()
+
+	loaded := filestate.New(stateStore.Path(), fileStateTTL)
+	require.NoError(t, loaded.Load())
+	offset, ok := loaded.Get(file.Name())
+	assert.True(t, ok)
+	assert.EqualValues(t, 42, offset)
 }
 
 func TestTailerSrcFiltersSingleLineLogs(t *testing.T) {
@@ -359,8 +399,7 @@ func setupTailer(t *testing.T, multiLineFn func(string) bool, maxEventSize int)
 	var consumed int32
 	file, err := createTempFile("", "tailsrctest-*.log")
 	require.NoError(t, err, fmt.Sprintf("Failed to create temp file: %v", err))
-	statefile, err := createTempFile("", "tailsrctest-state-*.log")
-	require.NoError(t, err, fmt.Sprintf("Failed to create temp file: %v", err))
+	stateStore := newTestStateStore(t)
 
 	tailer, err := tail.TailFile(file.Name(),
 		tail.Config{
@@ -392,9 +431,9 @@ func setupTailer(t *testing.T, multiLineFn func(string) bool, maxEventSize int)
 		t.Name(),
 		t.Name(),
 		"destination",
+		stateStore,
 		util.InfrequentAccessLogGroupClass,
 		"tailsrctest-*.log",
-		statefile.Name(),
 		tailer,
 		false, // AutoRemoval
 		multiLineFn,
@@ -416,10 +455,9 @@ func setupTailer(t *testing.T, multiLineFn func(string) bool, maxEventSize int)
 	})
 
 	return tailerTestResources{
-		done:      &done,
-		consumed:  &consumed,
-		file:      file,
-		statefile: statefile,
+		done:     &done,
+		consumed: &consumed,
+		file:     file,
 	}
 }
 
@@ -465,5 +503,253 @@ func resetState(originWaitDuration time.Duration) {
 
 func teardown(resources tailerTestResources) {
 	os.Remove(resources.file.Name())
-	os.Remove(resources.statefile.Name())
+}
+
+func TestTailerSrcPublishParsed(t *testing.T) {
+	pipeline, err := parser.New(parser.Config{
+		Parsers:        []*parser.Parser{{Type: parser.TypeRegex, Expression: `^(?P<time>\d+) (?P<level>\w+) (?P<message>.*)$`}},
+		TimestampField: "time",
+	})
+	require.NoError(t, err)
+	filter := &LogFilter{Type: includeFilterType, Expression: `^\d+ ERROR`}
+	require.NoError(t, filter.init())
+	groupTemplate, _ := newTestTemplate("/app/{$.level}", true)
+	ts := &tailerSrc{
+		timestampFn:   func(string) time.Time { return time.Time{} },
+		filters:       []*LogFilter{filter},
+		parser:        pipeline,
+		groupTemplate: groupTemplate,
+	}
+
+	// the filters match the line before it is parsed
+	assert.Nil(t, publishedEvent(ts, "1704164645678 INFO started"))
+	e := publishedEvent(ts, "1704164645678 ERROR disk full")
+	require.NotNil(t, e)
+	assert.JSONEq(t, `{"time": "1704164645678", "level": "ERROR", "message": "disk full"}`, e.Message())
+	assert.Equal(t, time.UnixMilli(1704164645678), e.Time())
+	assert.Equal(t, "/app/ERROR", e.Group())
+
+	// the lines the parsers do not match are published unchanged
+	ts.filters = nil
+	e = publishedEvent(ts, "unstructured")
+	require.NotNil(t, e)
+	assert.Equal(t, "unstructured", e.Message())
+	assert.True(t, e.Time().IsZero())
+}
+
+func TestTailerSrcPublishSampled(t *testing.T) {
+	pipeline, err := parser.New(parser.Config{
+		Parsers: []*parser.Parser{{Type: parser.TypeRegex, Expression: `^(?P<level>\w+) (?P<message>.*)$`}},
+	})
+	require.NoError(t, err)
+	sampling := &Sampling{LevelSampleRates: map[string]float64{"debug": 0}}
+	require.NoError(t, sampling.init())
+	ts := &tailerSrc{
+		timestampFn: func(string) time.Time { return time.Time{} },
+		parser:      pipeline,
+		sampling:    sampling,
+	}
+
+	// the severity is read from the parsed fields
+	assert.Nil(t, publishedEvent(ts, "DEBUG cache miss"))
+	e := publishedEvent(ts, "INFO started")
+	require.NotNil(t, e)
+	assert.JSONEq(t, `{"level": "INFO", "message": "started"}`, e.Message())
+}
+
+func TestTailerSrcMemoryGate(t *testing.T) {
+	file, err := createTempFile("", "tailsrctest-*.log")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+	stateStore := newTestStateStore(t)
+
+	tailer, err := tail.TailFile(file.Name(),
+		tail.Config{
+			Follow:      true,
+			Location:    &tail.SeekInfo{Whence: io.SeekStart, Offset: 0},
+			MustExist:   true,
+			Poll:        true,
+			MaxLineSize: defaultMaxEventSize,
+		})
+	require.NoError(t, err)
+	ts := NewTailerSrc(
+		"groupName", "streamName",
+		"destination", stateStore,
+		util.InfrequentAccessLogGroupClass,
+		"tailsrctest-*.log",
+		tailer,
+		false, // AutoRemoval
+		nil,
+		nil,
+		parseRFC3339Timestamp,
+		nil, // encoding
+		defaultMaxEventSize,
+		defaultTruncateSuffix,
+		1,
+	)
+	// the memory used by the agent is always above a limit of 0
+	ts.memoryGate, err = backpressure.NewMemoryGate(0, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	var consumed int32
+	ts.SetOutput(func(evt logs.LogEvent) {
+		if evt == nil {
+			close(done)
+			return
+		}
+		atomic.AddInt32(&consumed, 1)
+		evt.Done()
+	})
+	fmt.Fprintln(file, logWithTimestampPrefix("paused"))
+
+	time.Sleep(200 * time.Millisecond)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&consumed))
+
+	// the paused tailer still stops
+	ts.Stop()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "tailer not stopped")
+	}
+}
+
+func TestTailerSrcMultiline(t *testing.T) {
+	testCases := map[string]struct {
+		multiline *Multiline
+		lines     []string
+		want      []string
+	}{
+		"JavaStackTrace": {
+			multiline: &Multiline{Pattern: `^\s+at |^Caused by:`},
+			lines: []string{
+				"Exception in thread \"main\" java.lang.IllegalStateException: failed",
+				"    at com.example.App.run(App.java:12)",
+				"Caused by: java.io.IOException: closed",
+				"    at com.example.App.read(App.java:20)",
+				"started",
+			},
+			want: []string{
+				"Exception in thread \"main\" java.lang.IllegalStateException: failed\n    at com.example.App.run(App.java:12)\nCaused by: java.io.IOException: closed\n    at com.example.App.read(App.java:20)",
+				"started",
+			},
+		},
+		"PythonTraceback": {
+			multiline: &Multiline{Pattern: `^\d{4}-\d{2}-\d{2} `, Negate: true},
+			lines: []string{
+				"2024-01-02 03:04:05 ERROR request failed",
+				"Traceback (most recent call last):",
+				"  File \"app.py\", line 3, in <module>",
+				"ZeroDivisionError: division by zero",
+				"2024-01-02 03:04:06 INFO done",
+			},
+			want: []string{
+				"2024-01-02 03:04:05 ERROR request failed\nTraceback (most recent call last):\n  File \"app.py\", line 3, in <module>\nZeroDivisionError: division by zero",
+				"2024-01-02 03:04:06 INFO done",
+			},
+		},
+		"MatchBefore": {
+			multiline: &Multiline{Pattern: `\\$`, Match: multilineMatchBefore},
+			lines: []string{
+				"first \\",
+				"second \\",
+				"third",
+				"single",
+			},
+			want: []string{
+				"first \\\nsecond \\\nthird",
+				"single",
+			},
+		},
+		"MaxLines": {
+			multiline: &Multiline{Pattern: `^\s`, MaxLines: 2},
+			lines: []string{
+				"start",
+				" one",
+				" two",
+				" three",
+				"next",
+			},
+			want: []string{
+				"start\n one",
+				"next",
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, testCase.multiline.init(nil))
+			assert.Equal(t, testCase.want, tailMultiline(t, testCase.multiline, testCase.lines))
+		})
+	}
+}
+
+func TestTailerSrcMultilineTimeout(t *testing.T) {
+	original := multilineWaitPeriod
+	defer resetState(original)
+	multilineWaitPeriod = 10 * time.Millisecond
+
+	ts := &tailerSrc{multilineTimeout: 25 * time.Millisecond}
+	assert.Equal(t, 3, ts.multilineTimeoutTicks())
+	ts.multilineTimeout = 0
+	assert.Equal(t, multilineWaitTicks, ts.multilineTimeoutTicks())
+}
+
+// tailMultiline returns the events published for the lines of a file.
+func tailMultiline(t *testing.T, ml *Multiline, lines []string) []string {
+	file, err := createTempFile("", "tailsrctest-*.log")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+	stateStore := newTestStateStore(t)
+	for _, l := range lines {
+		fmt.Fprintln(file, l)
+	}
+
+	tailer, err := tail.TailFile(file.Name(),
+		tail.Config{
+			Follow:      true,
+			Location:    &tail.SeekInfo{Whence: io.SeekStart, Offset: 0},
+			MustExist:   true,
+			Poll:        true,
+			MaxLineSize: defaultMaxEventSize,
+		})
+	require.NoError(t, err)
+	ts := NewTailerSrc(
+		"groupName", "streamName",
+		"destination", stateStore,
+		util.InfrequentAccessLogGroupClass,
+		"tailsrctest-*.log",
+		tailer,
+		false, // AutoRemoval
+		ml.isStart,
+		nil,
+		parseRFC3339Timestamp,
+		nil, // encoding
+		defaultMaxEventSize,
+		defaultTruncateSuffix,
+		1,
+	)
+	ts.isMLEnd = ml.isEnd
+	ts.maxLines = ml.MaxLines
+	ts.multilineTimeout = time.Duration(ml.Timeout)
+
+	done := make(chan struct{})
+	var published []string
+	ts.SetOutput(func(evt logs.LogEvent) {
+		if evt == nil {
+			close(done)
+			return
+		}
+		published = append(published, evt.Message())
+		evt.Done()
+	})
+	time.Sleep(500 * time.Millisecond)
+	require.NoError(t, os.Remove(file.Name()))
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "tailer not stopped")
+	}
+	return published
 }
@@ -0,0 +1,72 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+)
+
+// fieldReferenceRegexp matches JSONPath-style references to fields in the log line,
+// e.g. {$.kubernetes.pod_name}
+var fieldReferenceRegexp = regexp.MustCompile(`\{\$((?:\.[^.{}]+)+)\}`)
+
+// resolveFields returns the name of the template with the field references replaced by the
+// values in the fields. Returns an empty string if any of the fields is missing or is not a scalar.
+func resolveFields(t *logsource.Template, fields map[string]interface{}) string {
+	if fields == nil {
+		return ""
+	}
+	values := make([]string, len(t.References()))
+	for i, path := range t.References() {
+		value, ok := lookupField(fields, path)
+		if !ok {
+			return ""
+		}
+		values[i] = value
+	}
+	return t.Resolve(values)
+}
+
+// lookupField returns the scalar value of the field at the path, e.g. .kubernetes.pod_name
+func lookupField(fields map[string]interface{}, path string) (string, bool) {
+	var value interface{} = fields
+	for path != "" {
+		var key string
+		key, path, _ = strings.Cut(strings.TrimPrefix(path, "."), ".")
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if value, ok = m[key]; !ok {
+			return "", false
+		}
+	}
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+// parseJSONFields returns nil if the message is not a JSON object.
+func parseJSONFields(msg string) map[string]interface{} {
+	trimmed := strings.TrimSpace(msg)
+	if !strings.HasPrefix(trimmed, "{") {
+		return nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(trimmed), &fields); err != nil {
+		return nil
+	}
+	return fields
+}
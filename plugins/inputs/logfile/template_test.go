@@ -0,0 +1,116 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+// newTestTemplate returns the template of the log group name if group is true, else of the
+// log stream name.
+func newTestTemplate(name string, group bool) (*logsource.Template, string) {
+	if group {
+		return logsource.NewGroupTemplate(name, fieldReferenceRegexp)
+	}
+	return logsource.NewStreamTemplate(name, fieldReferenceRegexp)
+}
+
+func TestResolveFields(t *testing.T) {
+	for _, name := range []string{"stream", "{instance_id}", "{$.}"} {
+		template, _ := newTestTemplate(name, false)
+		assert.Nil(t, template)
+	}
+
+	fields := parseJSONFields(`{"kubernetes": {"namespace_name": "kube-system", "pod_name": "coredns:1*", "restarts": 2, "labels": {}}, "ready": true}`)
+	testCases := map[string]struct {
+		name           string
+		group          bool
+		fields         map[string]interface{}
+		expectedStatic string
+		expected       string
+	}{
+		"WithNestedField": {
+			name:           "{$.kubernetes.pod_name}",
+			fields:         fields,
+			expectedStatic: "",
+			expected:       "coredns_1_",
+		},
+		"WithMultipleFields": {
+			name:           "pod-{$.kubernetes.namespace_name}_{$.kubernetes.restarts}_{$.ready}",
+			fields:         fields,
+			expectedStatic: "pod-__",
+			expected:       "pod-kube-system_2_true",
+		},
+		"WithMissingField": {
+			name:           "pod-{$.kubernetes.container_name}",
+			fields:         fields,
+			expectedStatic: "pod-",
+			expected:       "",
+		},
+		"WithObjectField": {
+			name:           "{$.kubernetes.labels}",
+			fields:         fields,
+			expectedStatic: "",
+			expected:       "",
+		},
+		"WithoutJSON": {
+			name:           "{$.kubernetes.pod_name}",
+			fields:         parseJSONFields("plain text"),
+			expectedStatic: "",
+			expected:       "",
+		},
+		"WithInvalidGroupChars": {
+			name:           "/eks/{$.kubernetes.pod_name}",
+			group:          true,
+			fields:         fields,
+			expectedStatic: "/eks/",
+			expected:       "/eks/coredns_1_",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			template, static := newTestTemplate(testCase.name, testCase.group)
+			assert.NotNil(t, template)
+			assert.Equal(t, testCase.expectedStatic, static)
+			assert.Equal(t, testCase.expected, resolveFields(template, testCase.fields))
+		})
+	}
+}
+
+// publishedEvent returns the event published by the source, nil if it was filtered out.
+func publishedEvent(ts *tailerSrc, msg string) *LogEvent {
+	var published *LogEvent
+	ts.outputFn = func(e logs.LogEvent) {
+		published = e.(*LogEvent)
+	}
+	ts.publish(msg, fileOffset{})
+	return published
+}
+
+func TestTailerSrcPublishTemplates(t *testing.T) {
+	groupTemplate, _ := newTestTemplate("/eks/{$.namespace}", true)
+	streamTemplate, _ := newTestTemplate("{$.pod}", false)
+	ts := &tailerSrc{
+		timestampFn:    func(string) time.Time { return time.Time{} },
+		groupTemplate:  groupTemplate,
+		streamTemplate: streamTemplate,
+	}
+	e := publishedEvent(ts, `{"namespace": "default", "pod": "web-1"}`)
+	assert.Equal(t, "/eks/default", e.Group())
+	assert.Equal(t, "web-1", e.Stream())
+	e = publishedEvent(ts, `{"pod": "web-1"}`)
+	assert.Equal(t, "", e.Group())
+	assert.Equal(t, "web-1", e.Stream())
+
+	ts = &tailerSrc{timestampFn: ts.timestampFn}
+	e = publishedEvent(ts, `{"namespace": "default", "pod": "web-1"}`)
+	assert.Equal(t, "", e.Group())
+	assert.Equal(t, "", e.Stream())
+}
@@ -0,0 +1,146 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// CO-RE eBPF programs used by the network_connections input plugin to track
+// per-process TCP retransmits and smoothed RTT. The objects are built for
+// amd64 and arm64 by "make network-connections-bpf" with clang and the libbpf
+// headers, and installed next to the agent binary:
+//
+//	clang -O2 -g -Wall -target bpf -D__TARGET_ARCH_x86 -c network_connections.bpf.c -o network_connections.bpf.o
+//	clang -O2 -g -Wall -target bpf -D__TARGET_ARCH_arm64 -c network_connections.bpf.c -o network_connections.bpf.o
+//
+// vmlinux.h only defines the kernel types the programs read, so no kernel
+// headers or BTF of the build host are needed.
+//
+// The layout of conn_key and conn_val must match connStatsKey and
+// connStatsValue in ebpf_linux.go.
+
+#include "vmlinux.h"
+#include <bpf/bpf_core_read.h>
+#include <bpf/bpf_endian.h>
+#include <bpf/bpf_helpers.h>
+#include <bpf/bpf_tracing.h>
+
+#define AF_INET 2
+#define AF_INET6 10
+#define IPPROTO_TCP 6
+
+char LICENSE[] SEC("license") = "Dual MIT/GPL";
+
+struct conn_key {
+	__u32 pid;
+	__u8 proto;
+	__u8 family;
+	__u16 dport;
+	__u8 daddr[16];
+};
+
+struct conn_val {
+	__u64 retransmits;
+	__u64 srtt_us_sum;
+	__u64 rtt_samples;
+};
+
+struct {
+	__uint(type, BPF_MAP_TYPE_LRU_HASH);
+	__uint(max_entries, 16384);
+	__type(key, struct conn_key);
+	__type(value, struct conn_val);
+} conn_stats SEC(".maps");
+
+// sock_owner maps the socket to the process that created it since the
+// retransmit and RTT hooks run in softirq context.
+struct {
+	__uint(type, BPF_MAP_TYPE_LRU_HASH);
+	__uint(max_entries, 65536);
+	__type(key, __u64);
+	__type(value, __u32);
+} sock_owner SEC(".maps");
+
+static __always_inline int fill_key(struct sock *sk, struct conn_key *key)
+{
+	__u32 *pid = bpf_map_lookup_elem(&sock_owner, &sk);
+	if (!pid)
+		return -1;
+	key->pid = *pid;
+	key->proto = IPPROTO_TCP;
+	key->dport = bpf_ntohs(BPF_CORE_READ(sk, __sk_common.skc_dport));
+	__u16 family = BPF_CORE_READ(sk, __sk_common.skc_family);
+	if (family == AF_INET) {
+		key->family = 4;
+		BPF_CORE_READ_INTO(key->daddr, sk, __sk_common.skc_daddr);
+	} else if (family == AF_INET6) {
+		key->family = 6;
+		BPF_CORE_READ_INTO(key->daddr, sk, __sk_common.skc_v6_daddr.in6_u.u6_addr8);
+	} else {
+		return -1;
+	}
+	return 0;
+}
+
+static __always_inline struct conn_val *lookup_or_init(struct conn_key *key)
+{
+	struct conn_val zero = {};
+	struct conn_val *val = bpf_map_lookup_elem(&conn_stats, key);
+	if (val)
+		return val;
+	bpf_map_update_elem(&conn_stats, key, &zero, BPF_NOEXIST);
+	return bpf_map_lookup_elem(&conn_stats, key);
+}
+
+SEC("kprobe/tcp_connect")
+int BPF_KPROBE(trace_connect, struct sock *sk)
+{
+	__u64 skp = (__u64)sk;
+	__u32 pid = bpf_get_current_pid_tgid() >> 32;
+	bpf_map_update_elem(&sock_owner, &skp, &pid, BPF_ANY);
+	return 0;
+}
+
+SEC("kretprobe/inet_csk_accept")
+int BPF_KRETPROBE(trace_accept, struct sock *sk)
+{
+	if (!sk)
+		return 0;
+	__u64 skp = (__u64)sk;
+	__u32 pid = bpf_get_current_pid_tgid() >> 32;
+	bpf_map_update_elem(&sock_owner, &skp, &pid, BPF_ANY);
+	return 0;
+}
+
+SEC("kprobe/tcp_retransmit_skb")
+int BPF_KPROBE(trace_retransmit, struct sock *sk)
+{
+	struct conn_key key = {};
+	if (fill_key(sk, &key))
+		return 0;
+	struct conn_val *val = lookup_or_init(&key);
+	if (val)
+		__sync_fetch_and_add(&val->retransmits, 1);
+	return 0;
+}
+
+SEC("kprobe/tcp_rcv_established")
+int BPF_KPROBE(trace_rcv_established, struct sock *sk)
+{
+	struct conn_key key = {};
+	if (fill_key(sk, &key))
+		return 0;
+	struct tcp_sock *tp = (struct tcp_sock *)sk;
+	// srtt_us is stored left shifted by 3
+	__u32 srtt = BPF_CORE_READ(tp, srtt_us) >> 3;
+	struct conn_val *val = lookup_or_init(&key);
+	if (val) {
+		__sync_fetch_and_add(&val->srtt_us_sum, srtt);
+		__sync_fetch_and_add(&val->rtt_samples, 1);
+	}
+	return 0;
+}
+
+SEC("kprobe/tcp_close")
+int BPF_KPROBE(trace_close, struct sock *sk)
+{
+	__u64 skp = (__u64)sk;
+	bpf_map_delete_elem(&sock_owner, &skp);
+	return 0;
+}
@@ -0,0 +1,187 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package network_connections
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+	"github.com/cilium/ebpf/rlimit"
+	"go.uber.org/multierr"
+)
+
+const (
+	// kernelBTFPath is required for CO-RE relocations.
+	kernelBTFPath = "/sys/kernel/btf/vmlinux"
+	// connStatsMapName is the name of the map defined in bpf/network_connections.bpf.c
+	connStatsMapName = "conn_stats"
+
+	protoTCP = 6
+	protoUDP = 17
+)
+
+// connStatsKey must match struct conn_key in bpf/network_connections.bpf.c
+type connStatsKey struct {
+	PID    uint32
+	Proto  uint8
+	Family uint8
+	DPort  uint16
+	DAddr  [16]byte
+}
+
+// connStatsValue must match struct conn_val in bpf/network_connections.bpf.c
+type connStatsValue struct {
+	Retransmits uint64
+	SRTTSum     uint64
+	RTTSamples  uint64
+}
+
+// ebpfCollector uses procfs for the connection counts and enriches them with
+// the retransmits and RTT samples tracked by the eBPF programs.
+type ebpfCollector struct {
+	procfs     *procfsCollector
+	collection *ebpf.Collection
+	links      []link.Link
+	stats      *ebpf.Map
+}
+
+var _ collector = (*ebpfCollector)(nil)
+
+func newEBPFCollector(objectPath string, procfs *procfsCollector) (*ebpfCollector, error) {
+	if _, err := os.Stat(kernelBTFPath); err != nil {
+		return nil, fmt.Errorf("%w: kernel BTF is unavailable: %v", errEBPFNotSupported, err)
+	}
+	if _, err := os.Stat(objectPath); err != nil {
+		return nil, fmt.Errorf("%w: %v", errEBPFNotSupported, err)
+	}
+	if err := rlimit.RemoveMemlock(); err != nil {
+		return nil, fmt.Errorf("unable to remove memlock limit: %w", err)
+	}
+	spec, err := ebpf.LoadCollectionSpec(objectPath)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := ebpf.NewCollection(spec)
+	if err != nil {
+		return nil, err
+	}
+	c := &ebpfCollector{procfs: procfs, collection: collection}
+	var ok bool
+	if c.stats, ok = collection.Maps[connStatsMapName]; !ok {
+		_ = c.close()
+		return nil, fmt.Errorf("map %q not found in %s", connStatsMapName, objectPath)
+	}
+	for name, progSpec := range spec.Programs {
+		l, err := attach(progSpec, collection.Programs[name])
+		if err != nil {
+			_ = c.close()
+			return nil, fmt.Errorf("unable to attach program %q: %w", name, err)
+		}
+		c.links = append(c.links, l)
+	}
+	return c, nil
+}
+
+// attach uses the ELF section name to determine how to attach the program.
+func attach(spec *ebpf.ProgramSpec, prog *ebpf.Program) (link.Link, error) {
+	section := spec.SectionName
+	switch {
+	case strings.HasPrefix(section, "kprobe/"):
+		return link.Kprobe(strings.TrimPrefix(section, "kprobe/"), prog, nil)
+	case strings.HasPrefix(section, "kretprobe/"):
+		return link.Kretprobe(strings.TrimPrefix(section, "kretprobe/"), prog, nil)
+	case strings.HasPrefix(section, "tracepoint/"):
+		group, name, ok := strings.Cut(strings.TrimPrefix(section, "tracepoint/"), "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid tracepoint section: %q", section)
+		}
+		return link.Tracepoint(group, name, prog, nil)
+	case strings.HasPrefix(section, "fentry/"), strings.HasPrefix(section, "fexit/"):
+		return link.AttachTracing(link.TracingOptions{Program: prog})
+	}
+	return nil, fmt.Errorf("unsupported section: %q", section)
+}
+
+func (c *ebpfCollector) collect() (map[connectionKey]*connectionStats, error) {
+	stats, err := c.procfs.collect()
+	if err != nil {
+		return nil, err
+	}
+	// the eBPF retransmits replace the procfs ones, which only represent the
+	// unrecovered timeouts at the time of collection
+	for _, stat := range stats {
+		stat.retransmits = 0
+	}
+	var (
+		key   connStatsKey
+		value connStatsValue
+		keys  []connStatsKey
+	)
+	iter := c.stats.Iterate()
+	for iter.Next(&key, &value) {
+		keys = append(keys, key)
+		ck := connectionKey{
+			pid:         int32(key.PID),
+			protocol:    protocolName(key.Proto),
+			destination: destination(key),
+		}
+		stat, ok := stats[ck]
+		if !ok {
+			// the connection was closed since the last collection
+			stat = &connectionStats{processName: processName(filepath.Join(c.procfs.procPath, strconv.Itoa(int(key.PID))))}
+			stats[ck] = stat
+		}
+		stat.retransmits += int64(value.Retransmits)
+		stat.rttSum += value.SRTTSum
+		stat.rttSamples += value.RTTSamples
+	}
+	if err = iter.Err(); err != nil {
+		return nil, err
+	}
+	// reset the values so that each collection reports the delta
+	for i := range keys {
+		if err = c.stats.Delete(&keys[i]); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+func (c *ebpfCollector) close() error {
+	var errs error
+	for _, l := range c.links {
+		errs = multierr.Append(errs, l.Close())
+	}
+	c.links = nil
+	if c.collection != nil {
+		c.collection.Close()
+	}
+	return errs
+}
+
+func protocolName(proto uint8) string {
+	if proto == protoUDP {
+		return protocolUDP
+	}
+	return protocolTCP
+}
+
+func destination(key connStatsKey) string {
+	var ip net.IP
+	if key.Family == 4 {
+		ip = net.IP(key.DAddr[:net.IPv4len])
+	} else {
+		ip = net.IP(key.DAddr[:])
+	}
+	return net.JoinHostPort(ip.String(), strconv.Itoa(int(key.DPort)))
+}
@@ -0,0 +1,10 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+
+package network_connections
+
+func newEBPFCollector(string, *procfsCollector) (collector, error) {
+	return nil, errEBPFNotSupported
+}
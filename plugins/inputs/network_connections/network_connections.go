@@ -0,0 +1,193 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package network_connections
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/containerinsightscommon"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+const (
+	measurement = "network_connections"
+
+	CollectionMethodAuto   = "auto"
+	CollectionMethodEBPF   = "ebpf"
+	CollectionMethodProcfs = "procfs"
+
+	defaultProcPath       = "/proc"
+	defaultEBPFObjectPath = "/opt/aws/amazon-cloudwatch-agent/bin/network_connections.bpf.o"
+
+	tagProtocol    = "protocol"
+	tagProcessName = "process_name"
+	tagPID         = "pid"
+	tagDestination = "destination"
+
+	fieldConnections = "connections"
+	fieldRetransmits = "retransmits"
+	fieldRTTAverage  = "rtt_avg"
+)
+
+var errEBPFNotSupported = errors.New("eBPF connection tracking is not supported on this host")
+
+// connectionKey identifies the aggregation group for a set of connections.
+type connectionKey struct {
+	pid         int32
+	protocol    string
+	destination string
+}
+
+// connectionStats are the aggregated values for a connectionKey.
+type connectionStats struct {
+	processName string
+	connections int64
+	retransmits int64
+	// rttSum is in microseconds. Only populated by the eBPF collector.
+	rttSum     uint64
+	rttSamples uint64
+}
+
+// collector gathers the per-process connection statistics.
+type collector interface {
+	collect() (map[connectionKey]*connectionStats, error)
+	close() error
+}
+
+// NetworkConnections reports per-process TCP/UDP connection metrics. When the
+// kernel supports it, CO-RE eBPF programs are used to track retransmits and
+// RTT. Otherwise, the connection tables in procfs are parsed.
+type NetworkConnections struct {
+	CollectionMethod string          `toml:"collection_method"`
+	PerDestination   bool            `toml:"per_destination"`
+	IncludeUDP       bool            `toml:"include_udp"`
+	ProcPath         string          `toml:"proc_path"`
+	EBPFObjectPath   string          `toml:"ebpf_object_path"`
+	Log              telegraf.Logger `toml:"-"`
+
+	collector collector
+}
+
+var _ telegraf.ServiceInput = (*NetworkConnections)(nil)
+
+func (*NetworkConnections) SampleConfig() string {
+	return sampleConfig
+}
+
+func (*NetworkConnections) Description() string {
+	return "Collects per-process TCP/UDP connection metrics using eBPF or procfs"
+}
+
+func (n *NetworkConnections) Init() error {
+	if n.ProcPath == "" {
+		n.ProcPath = defaultProcPath
+		if hostProc := os.Getenv(containerinsightscommon.GoPSUtilProcDirEnv); hostProc != "" {
+			n.ProcPath = hostProc
+		}
+	}
+	procfs := newProcfsCollector(n.ProcPath, n.IncludeUDP)
+	switch n.CollectionMethod {
+	case CollectionMethodProcfs:
+		n.collector = procfs
+	case CollectionMethodEBPF:
+		c, err := newEBPFCollector(n.EBPFObjectPath, procfs)
+		if err != nil {
+			return fmt.Errorf("unable to load eBPF programs: %w", err)
+		}
+		n.collector = c
+	case "", CollectionMethodAuto:
+		c, err := newEBPFCollector(n.EBPFObjectPath, procfs)
+		if err != nil {
+			n.Log.Infof("Falling back to procfs for network connection metrics: %v", err)
+			n.collector = procfs
+		} else {
+			n.collector = c
+		}
+	default:
+		return fmt.Errorf("unsupported collection_method: %q", n.CollectionMethod)
+	}
+	return nil
+}
+
+func (n *NetworkConnections) Gather(acc telegraf.Accumulator) error {
+	stats, err := n.collector.collect()
+	if err != nil {
+		return err
+	}
+	for key, stat := range n.aggregate(stats) {
+		tags := map[string]string{
+			tagProtocol:    key.protocol,
+			tagProcessName: stat.processName,
+			tagPID:         strconv.Itoa(int(key.pid)),
+		}
+		if key.destination != "" {
+			tags[tagDestination] = key.destination
+		}
+		fields := map[string]interface{}{
+			fieldConnections: stat.connections,
+			fieldRetransmits: stat.retransmits,
+		}
+		if stat.rttSamples > 0 {
+			fields[fieldRTTAverage] = float64(stat.rttSum) / float64(stat.rttSamples)
+		}
+		acc.AddFields(measurement, fields, tags)
+	}
+	return nil
+}
+
+// aggregate merges the destinations together if per destination metrics are
+// disabled.
+func (n *NetworkConnections) aggregate(stats map[connectionKey]*connectionStats) map[connectionKey]*connectionStats {
+	if n.PerDestination {
+		return stats
+	}
+	result := make(map[connectionKey]*connectionStats, len(stats))
+	for key, stat := range stats {
+		key.destination = ""
+		existing, ok := result[key]
+		if !ok {
+			existing = &connectionStats{processName: stat.processName}
+			result[key] = existing
+		}
+		existing.connections += stat.connections
+		existing.retransmits += stat.retransmits
+		existing.rttSum += stat.rttSum
+		existing.rttSamples += stat.rttSamples
+	}
+	return result
+}
+
+// Start is a no-op. The plugin is a service input so that Stop is called on shutdown.
+func (n *NetworkConnections) Start(telegraf.Accumulator) error {
+	return nil
+}
+
+// Stop releases the eBPF programs if they were loaded.
+func (n *NetworkConnections) Stop() {
+	if n.collector != nil {
+		if err := n.collector.close(); err != nil {
+			n.Log.Errorf("Unable to close network connections collector: %v", err)
+		}
+	}
+}
+
+func init() {
+	inputs.Add(measurement, func() telegraf.Input {
+		return &NetworkConnections{
+			CollectionMethod: CollectionMethodAuto,
+			PerDestination:   true,
+			IncludeUDP:       true,
+			EBPFObjectPath:   defaultEBPFObjectPath,
+		}
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package network_connections
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	tcpTable = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 100 1 0000000000000000 100 0 0 10 0
+   1: 0F02000A:D2F0 0101A8C0:01BB 01 00000000:00000000 00:00000000 00000002     0        0 101 1 0000000000000000 20 4 30 10 -1
+   2: 0F02000A:D2F2 0101A8C0:01BB 01 00000000:00000000 00:00000000 00000000     0        0 102 1 0000000000000000 20 4 30 10 -1
+   3: 0F02000A:D2F4 0201A8C0:0050 06 00000000:00000000 00:00000000 00000000     0        0 103 1 0000000000000000 20 4 30 10 -1
+`
+	tcp6Table = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000001000000:D2F6 00000000000000000000000001000000:1F90 01 00000000:00000000 00:00000000 00000000     0        0 104 1 0000000000000000 20 4 30 10 -1
+`
+	udpTable = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+   0: 00000000:14E9 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 105 2 0000000000000000 0
+   1: 0F02000A:A1B2 0202000A:0035 01 00000000:00000000 00:00000000 00000000     0        0 106 2 0000000000000000 0
+`
+)
+
+// createProc creates a fake procfs with two processes.
+func createProc(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "net"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "net", "tcp"), []byte(tcpTable), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "net", "tcp6"), []byte(tcp6Table), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "net", "udp"), []byte(udpTable), 0600))
+	processes := map[string]struct {
+		comm   string
+		inodes []string
+	}{
+		"10": {comm: "curl", inodes: []string{"101", "102", "103"}},
+		"20": {comm: "app", inodes: []string{"100", "104", "105", "106"}},
+	}
+	for pid, process := range processes {
+		fdDir := filepath.Join(dir, pid, "fd")
+		require.NoError(t, os.MkdirAll(fdDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, pid, "comm"), []byte(process.comm+"\n"), 0600))
+		for i, inode := range process.inodes {
+			require.NoError(t, os.Symlink("socket:["+inode+"]", filepath.Join(fdDir, string(rune('3'+i)))))
+		}
+		require.NoError(t, os.Symlink("/dev/null", filepath.Join(fdDir, "0")))
+	}
+	return dir
+}
+
+func TestParseAddress(t *testing.T) {
+	testCases := map[string]string{
+		"0101A8C0:01BB":                         "192.168.1.1:443",
+		"00000000:0000":                         "",
+		"00000000000000000000000001000000:1F90": "[::1]:8080",
+		"0000000000000000FFFF00000100007F:0050": "127.0.0.1:80",
+	}
+	for input, want := range testCases {
+		got, err := parseAddress(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+	_, err := parseAddress("invalid")
+	assert.Error(t, err)
+}
+
+func TestGatherProcfs(t *testing.T) {
+	plugin := &NetworkConnections{
+		CollectionMethod: CollectionMethodProcfs,
+		PerDestination:   true,
+		IncludeUDP:       true,
+		ProcPath:         createProc(t),
+		Log:              testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	acc := testutil.Accumulator{}
+	require.NoError(t, plugin.Gather(&acc))
+
+	acc.AssertContainsTaggedFields(t, measurement,
+		map[string]interface{}{fieldConnections: int64(2), fieldRetransmits: int64(2)},
+		map[string]string{tagProtocol: protocolTCP, tagProcessName: "curl", tagPID: "10", tagDestination: "192.168.1.1:443"})
+	acc.AssertContainsTaggedFields(t, measurement,
+		map[string]interface{}{fieldConnections: int64(1), fieldRetransmits: int64(0)},
+		map[string]string{tagProtocol: protocolTCP, tagProcessName: "app", tagPID: "20", tagDestination: "[::1]:8080"})
+	acc.AssertContainsTaggedFields(t, measurement,
+		map[string]interface{}{fieldConnections: int64(1), fieldRetransmits: int64(0)},
+		map[string]string{tagProtocol: protocolUDP, tagProcessName: "app", tagPID: "20", tagDestination: "10.0.2.2:53"})
+	// listening, unconnected, and closing sockets are not counted
+	assert.Len(t, acc.Metrics, 3)
+}
+
+func TestGatherProcfsWithoutDestination(t *testing.T) {
+	plugin := &NetworkConnections{
+		CollectionMethod: CollectionMethodProcfs,
+		ProcPath:         createProc(t),
+		Log:              testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	acc := testutil.Accumulator{}
+	require.NoError(t, plugin.Gather(&acc))
+
+	acc.AssertContainsTaggedFields(t, measurement,
+		map[string]interface{}{fieldConnections: int64(2), fieldRetransmits: int64(2)},
+		map[string]string{tagProtocol: protocolTCP, tagProcessName: "curl", tagPID: "10"})
+	acc.AssertContainsTaggedFields(t, measurement,
+		map[string]interface{}{fieldConnections: int64(1), fieldRetransmits: int64(0)},
+		map[string]string{tagProtocol: protocolTCP, tagProcessName: "app", tagPID: "20"})
+	assert.Len(t, acc.Metrics, 2)
+}
+
+func TestInitFallback(t *testing.T) {
+	plugin := &NetworkConnections{
+		CollectionMethod: CollectionMethodAuto,
+		ProcPath:         createProc(t),
+		EBPFObjectPath:   filepath.Join(t.TempDir(), "missing.bpf.o"),
+		Log:              testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	assert.IsType(t, &procfsCollector{}, plugin.collector)
+
+	plugin.CollectionMethod = CollectionMethodEBPF
+	assert.Error(t, plugin.Init())
+
+	plugin.CollectionMethod = "invalid"
+	assert.Error(t, plugin.Init())
+}
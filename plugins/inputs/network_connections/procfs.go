@@ -0,0 +1,209 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package network_connections
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	protocolTCP = "tcp"
+	protocolUDP = "udp"
+
+	// tcpStateEstablished is the TCP_ESTABLISHED state in include/net/tcp_states.h
+	tcpStateEstablished = "01"
+	// socketLinkPrefix is the prefix of the file descriptor links that point to sockets.
+	socketLinkPrefix = "socket:["
+)
+
+// socketTable is a file in procfs listing the sockets for a protocol.
+type socketTable struct {
+	protocol string
+	file     string
+}
+
+// procfsCollector parses the socket tables in procfs and maps the sockets to
+// the owning processes using the file descriptors in /proc/<pid>/fd.
+type procfsCollector struct {
+	procPath string
+	tables   []socketTable
+}
+
+var _ collector = (*procfsCollector)(nil)
+
+func newProcfsCollector(procPath string, includeUDP bool) *procfsCollector {
+	tables := []socketTable{
+		{protocol: protocolTCP, file: "tcp"},
+		{protocol: protocolTCP, file: "tcp6"},
+	}
+	if includeUDP {
+		tables = append(tables,
+			socketTable{protocol: protocolUDP, file: "udp"},
+			socketTable{protocol: protocolUDP, file: "udp6"},
+		)
+	}
+	return &procfsCollector{procPath: procPath, tables: tables}
+}
+
+func (c *procfsCollector) collect() (map[connectionKey]*connectionStats, error) {
+	owners, err := c.socketOwners()
+	if err != nil {
+		return nil, err
+	}
+	stats := map[connectionKey]*connectionStats{}
+	for _, table := range c.tables {
+		entries, err := parseSocketTable(filepath.Join(c.procPath, "net", table.file), table.protocol)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// IPv6 may be disabled
+				continue
+			}
+			return nil, err
+		}
+		for _, entry := range entries {
+			owner, ok := owners[entry.inode]
+			if !ok {
+				continue
+			}
+			key := connectionKey{pid: owner.pid, protocol: table.protocol, destination: entry.destination}
+			stat, ok := stats[key]
+			if !ok {
+				stat = &connectionStats{processName: owner.name}
+				stats[key] = stat
+			}
+			stat.connections++
+			stat.retransmits += entry.retransmits
+		}
+	}
+	return stats, nil
+}
+
+func (c *procfsCollector) close() error {
+	return nil
+}
+
+type socketOwner struct {
+	pid  int32
+	name string
+}
+
+// socketOwners maps the socket inodes to the processes that have them open.
+func (c *procfsCollector) socketOwners() (map[string]socketOwner, error) {
+	dirs, err := os.ReadDir(c.procPath)
+	if err != nil {
+		return nil, err
+	}
+	owners := map[string]socketOwner{}
+	for _, dir := range dirs {
+		pid, err := strconv.ParseInt(dir.Name(), 10, 32)
+		if err != nil || !dir.IsDir() {
+			continue
+		}
+		pidPath := filepath.Join(c.procPath, dir.Name())
+		fds, err := os.ReadDir(filepath.Join(pidPath, "fd"))
+		if err != nil {
+			// the process exited or the agent does not have permission
+			continue
+		}
+		var owner *socketOwner
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(pidPath, "fd", fd.Name()))
+			if err != nil || !strings.HasPrefix(link, socketLinkPrefix) {
+				continue
+			}
+			if owner == nil {
+				owner = &socketOwner{pid: int32(pid), name: processName(pidPath)}
+			}
+			owners[strings.TrimSuffix(strings.TrimPrefix(link, socketLinkPrefix), "]")] = *owner
+		}
+	}
+	return owners, nil
+}
+
+func processName(pidPath string) string {
+	comm, err := os.ReadFile(filepath.Join(pidPath, "comm"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(comm))
+}
+
+type socketEntry struct {
+	inode       string
+	destination string
+	retransmits int64
+}
+
+// parseSocketTable reads the connected sockets from a /proc/net/{tcp,udp}[6]
+// file. Listening and unconnected sockets are skipped. The format is
+//
+//	sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+func parseSocketTable(path string, protocol string) ([]socketEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []socketEntry
+	scanner := bufio.NewScanner(f)
+	// skip the header
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		if protocol == protocolTCP && fields[3] != tcpStateEstablished {
+			continue
+		}
+		destination, err := parseAddress(fields[2])
+		if err != nil || destination == "" {
+			continue
+		}
+		retransmits, _ := strconv.ParseInt(fields[6], 16, 64)
+		entries = append(entries, socketEntry{
+			inode:       fields[9],
+			destination: destination,
+			retransmits: retransmits,
+		})
+	}
+	return entries, scanner.Err()
+}
+
+// parseAddress converts the hex encoded address and port into "ip:port". The
+// address is stored as 32-bit words in host byte order. Returns an empty string
+// for unconnected sockets.
+func parseAddress(s string) (string, error) {
+	addr, portHex, ok := strings.Cut(s, ":")
+	if !ok {
+		return "", fmt.Errorf("invalid address: %q", s)
+	}
+	raw, err := hex.DecodeString(addr)
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return "", fmt.Errorf("invalid address: %q", s)
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return "", fmt.Errorf("invalid port: %q", s)
+	}
+	ip := make(net.IP, len(raw))
+	for i := 0; i < len(raw); i += 4 {
+		ip[i], ip[i+1], ip[i+2], ip[i+3] = raw[i+3], raw[i+2], raw[i+1], raw[i]
+	}
+	if ip.IsUnspecified() && port == 0 {
+		return "", nil
+	}
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	return net.JoinHostPort(ip.String(), strconv.FormatUint(port, 10)), nil
+}
@@ -0,0 +1,304 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package network_probe
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"golang.org/x/time/rate"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+const (
+	measurement = "network_probe"
+
+	ProtocolICMP = "icmp"
+	ProtocolTCP  = "tcp"
+	ProtocolUDP  = "udp"
+
+	defaultInterval           = time.Minute
+	defaultCount              = 3
+	defaultTimeout            = 3 * time.Second
+	defaultMaxProbesPerSecond = 10
+
+	tagSource      = "src"
+	tagDestination = "dst"
+	tagProtocol    = "protocol"
+	tagPort        = "port"
+
+	fieldRTTMin          = "rtt_min"
+	fieldRTTAvg          = "rtt_avg"
+	fieldRTTMax          = "rtt_max"
+	fieldPacketsSent     = "packets_sent"
+	fieldPacketsReceived = "packets_received"
+	fieldPacketLoss      = "packet_loss"
+)
+
+// Target is probed on its own schedule.
+type Target struct {
+	Name     string          `toml:"name"`
+	Address  string          `toml:"address"`
+	Protocol string          `toml:"protocol"`
+	Port     int             `toml:"port"`
+	Interval config.Duration `toml:"interval"`
+	Count    int             `toml:"count"`
+	Timeout  config.Duration `toml:"timeout"`
+}
+
+func (t *Target) destination() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.Address
+}
+
+func (t *Target) validate() error {
+	if t.Address == "" {
+		return errors.New("target address is required")
+	}
+	if t.Protocol == "" {
+		t.Protocol = ProtocolICMP
+	}
+	switch t.Protocol {
+	case ProtocolICMP:
+	case ProtocolTCP, ProtocolUDP:
+		if t.Port <= 0 || t.Port > math.MaxUint16 {
+			return fmt.Errorf("target %s: a valid port is required for %s", t.Address, t.Protocol)
+		}
+	default:
+		return fmt.Errorf("target %s: unsupported protocol %q", t.Address, t.Protocol)
+	}
+	if t.Interval <= 0 {
+		t.Interval = config.Duration(defaultInterval)
+	}
+	if t.Count <= 0 {
+		t.Count = defaultCount
+	}
+	if t.Timeout <= 0 {
+		t.Timeout = config.Duration(defaultTimeout)
+	}
+	if t.Timeout > t.Interval {
+		return fmt.Errorf("target %s: timeout %s exceeds interval %s", t.Address, time.Duration(t.Timeout), time.Duration(t.Interval))
+	}
+	return nil
+}
+
+// prober sends a single probe to the target and returns the round trip time.
+// An error means the probe is counted as lost.
+type prober interface {
+	probe(ctx context.Context, t *Target, seq int) (time.Duration, error)
+}
+
+// result of one probing round of a target.
+type result struct {
+	target   *Target
+	time     time.Time
+	sent     int
+	received []time.Duration
+}
+
+// NetworkProbe measures the RTT and loss between the host and a set of
+// targets. Each target is probed on its own interval and the completed rounds
+// are reported on the next collection.
+type NetworkProbe struct {
+	Source             string          `toml:"source"`
+	MaxProbesPerSecond float64         `toml:"max_probes_per_second"`
+	Targets            []*Target       `toml:"target"`
+	Log                telegraf.Logger `toml:"-"`
+
+	limiter *rate.Limiter
+	probers map[string]prober
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	mutex   sync.Mutex
+	results []result
+}
+
+var _ telegraf.ServiceInput = (*NetworkProbe)(nil)
+
+func (*NetworkProbe) SampleConfig() string {
+	return sampleConfig
+}
+
+func (*NetworkProbe) Description() string {
+	return "Probes the RTT and loss to a set of targets using ICMP, TCP or UDP"
+}
+
+func (n *NetworkProbe) Init() error {
+	if len(n.Targets) == 0 {
+		return errors.New("no targets configured")
+	}
+	if n.Source == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("unable to determine source name: %w", err)
+		}
+		n.Source = hostname
+	}
+	limit := rate.Inf
+	if n.MaxProbesPerSecond > 0 {
+		limit = rate.Limit(n.MaxProbesPerSecond)
+	}
+	n.limiter = rate.NewLimiter(limit, 1)
+
+	hasICMP := false
+	for _, t := range n.Targets {
+		if err := t.validate(); err != nil {
+			return err
+		}
+		hasICMP = hasICMP || t.Protocol == ProtocolICMP
+	}
+	if n.probers == nil {
+		n.probers = map[string]prober{
+			ProtocolTCP: tcpProber{},
+			ProtocolUDP: udpProber{},
+		}
+		if hasICMP {
+			p, err := newICMPProber()
+			if err != nil {
+				n.Log.Warnf("ICMP probes require unprivileged ICMP sockets (net.ipv4.ping_group_range) or CAP_NET_RAW, skipping ICMP targets: %v", err)
+			} else {
+				n.probers[ProtocolICMP] = p
+			}
+		}
+	}
+	return nil
+}
+
+// Start probes each target on its interval until Stop is called.
+func (n *NetworkProbe) Start(telegraf.Accumulator) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	n.cancel = cancel
+	for _, t := range n.Targets {
+		p, ok := n.probers[t.Protocol]
+		if !ok {
+			continue
+		}
+		n.wg.Add(1)
+		go n.run(ctx, t, p)
+	}
+	return nil
+}
+
+func (n *NetworkProbe) Stop() {
+	if n.cancel != nil {
+		n.cancel()
+	}
+	n.wg.Wait()
+}
+
+func (n *NetworkProbe) run(ctx context.Context, t *Target, p prober) {
+	defer n.wg.Done()
+	ticker := time.NewTicker(time.Duration(t.Interval))
+	defer ticker.Stop()
+	seq := 0
+	for {
+		r, ok := n.probeTarget(ctx, t, p, seq)
+		if !ok {
+			return
+		}
+		seq = (seq + t.Count) & math.MaxUint16
+		n.mutex.Lock()
+		n.results = append(n.results, r)
+		n.mutex.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probeTarget runs one round of probes. Returns false if the round was
+// interrupted by Stop.
+func (n *NetworkProbe) probeTarget(ctx context.Context, t *Target, p prober, seq int) (result, bool) {
+	r := result{target: t}
+	for i := 0; i < t.Count; i++ {
+		if err := n.limiter.Wait(ctx); err != nil {
+			return r, false
+		}
+		r.sent++
+		rtt, err := p.probe(ctx, t, (seq+i)&math.MaxUint16)
+		if err != nil {
+			n.Log.Debugf("Probe to %s over %s failed: %v", t.Address, t.Protocol, err)
+			continue
+		}
+		r.received = append(r.received, rtt)
+	}
+	r.time = time.Now()
+	return r, true
+}
+
+// Gather reports the rounds completed since the last collection.
+func (n *NetworkProbe) Gather(acc telegraf.Accumulator) error {
+	n.mutex.Lock()
+	results := n.results
+	n.results = nil
+	n.mutex.Unlock()
+	for _, r := range results {
+		acc.AddFields(measurement, r.fields(), n.tags(r.target), r.time)
+	}
+	return nil
+}
+
+func (n *NetworkProbe) tags(t *Target) map[string]string {
+	tags := map[string]string{
+		tagSource:      n.Source,
+		tagDestination: t.destination(),
+		tagProtocol:    t.Protocol,
+	}
+	if t.Protocol != ProtocolICMP {
+		tags[tagPort] = strconv.Itoa(t.Port)
+	}
+	return tags
+}
+
+// fields returns the loss in percent and, if any probe was answered, the RTT
+// in milliseconds.
+func (r result) fields() map[string]interface{} {
+	fields := map[string]interface{}{
+		fieldPacketsSent:     r.sent,
+		fieldPacketsReceived: len(r.received),
+		fieldPacketLoss:      100 * float64(r.sent-len(r.received)) / float64(r.sent),
+	}
+	if len(r.received) == 0 {
+		return fields
+	}
+	minRTT, maxRTT, sum := r.received[0], r.received[0], time.Duration(0)
+	for _, rtt := range r.received {
+		minRTT = min(minRTT, rtt)
+		maxRTT = max(maxRTT, rtt)
+		sum += rtt
+	}
+	fields[fieldRTTMin] = milliseconds(minRTT)
+	fields[fieldRTTMax] = milliseconds(maxRTT)
+	fields[fieldRTTAvg] = milliseconds(sum) / float64(len(r.received))
+	return fields
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func init() {
+	inputs.Add(measurement, func() telegraf.Input {
+		return &NetworkProbe{
+			MaxProbesPerSecond: defaultMaxProbesPerSecond,
+		}
+	})
+}
@@ -0,0 +1,206 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package network_probe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/icmp"
+)
+
+// fakeProber answers the probes with the configured round trip times, or
+// loses them if nil.
+type fakeProber struct {
+	rtts []time.Duration
+	seqs []int
+}
+
+func (f *fakeProber) probe(_ context.Context, _ *Target, seq int) (time.Duration, error) {
+	f.seqs = append(f.seqs, seq)
+	rtt := f.rtts[(len(f.seqs)-1)%len(f.rtts)]
+	if rtt == 0 {
+		return 0, errors.New("timeout")
+	}
+	return rtt, nil
+}
+
+func TestInit(t *testing.T) {
+	n := &NetworkProbe{
+		Source: "dc1",
+		Log:    testutil.Logger{},
+		Targets: []*Target{
+			{Address: "10.0.0.1"},
+			{Address: "example.com", Protocol: ProtocolTCP, Port: 443, Interval: config.Duration(30 * time.Second), Count: 5, Timeout: config.Duration(time.Second)},
+		},
+		probers: map[string]prober{},
+	}
+	require.NoError(t, n.Init())
+	assert.Equal(t, &Target{Address: "10.0.0.1", Protocol: ProtocolICMP, Interval: config.Duration(defaultInterval), Count: defaultCount, Timeout: config.Duration(defaultTimeout)}, n.Targets[0])
+	assert.Equal(t, 5, n.Targets[1].Count)
+	assert.Equal(t, config.Duration(30*time.Second), n.Targets[1].Interval)
+}
+
+func TestInitInvalid(t *testing.T) {
+	testCases := map[string]*Target{
+		"MissingAddress": {Protocol: ProtocolTCP, Port: 443},
+		"MissingPort":    {Address: "10.0.0.1", Protocol: ProtocolUDP},
+		"InvalidPort":    {Address: "10.0.0.1", Protocol: ProtocolTCP, Port: 70000},
+		"Protocol":       {Address: "10.0.0.1", Protocol: "sctp"},
+		"Timeout":        {Address: "10.0.0.1", Interval: config.Duration(time.Second), Timeout: config.Duration(2 * time.Second)},
+	}
+	for name, target := range testCases {
+		t.Run(name, func(t *testing.T) {
+			n := &NetworkProbe{Source: "dc1", Log: testutil.Logger{}, Targets: []*Target{target}, probers: map[string]prober{}}
+			assert.Error(t, n.Init())
+		})
+	}
+	assert.Error(t, (&NetworkProbe{Log: testutil.Logger{}}).Init())
+}
+
+func TestICMPWithoutPrivileges(t *testing.T) {
+	orig := listenICMP
+	t.Cleanup(func() { listenICMP = orig })
+	listenICMP = func(string, string) (*icmp.PacketConn, error) {
+		return nil, errors.New("operation not permitted")
+	}
+	n := &NetworkProbe{
+		Source: "dc1",
+		Log:    testutil.Logger{},
+		Targets: []*Target{
+			{Address: "10.0.0.1"},
+			{Address: "127.0.0.1", Protocol: ProtocolTCP, Port: 443},
+		},
+	}
+	require.NoError(t, n.Init())
+	assert.NotContains(t, n.probers, ProtocolICMP)
+	assert.Contains(t, n.probers, ProtocolTCP)
+}
+
+func TestProbeTarget(t *testing.T) {
+	n := &NetworkProbe{Source: "dc1", Log: testutil.Logger{}, Targets: []*Target{{Address: "10.0.0.1", Count: 4}}, probers: map[string]prober{}}
+	require.NoError(t, n.Init())
+	p := &fakeProber{rtts: []time.Duration{2 * time.Millisecond, 0, 4 * time.Millisecond, 6 * time.Millisecond}}
+	r, ok := n.probeTarget(context.Background(), n.Targets[0], p, 65534)
+	require.True(t, ok)
+	assert.Equal(t, []int{65534, 65535, 0, 1}, p.seqs)
+	assert.Equal(t, map[string]interface{}{
+		fieldPacketsSent:     4,
+		fieldPacketsReceived: 3,
+		fieldPacketLoss:      25.0,
+		fieldRTTMin:          2.0,
+		fieldRTTAvg:          4.0,
+		fieldRTTMax:          6.0,
+	}, r.fields())
+
+	p = &fakeProber{rtts: []time.Duration{0}}
+	r, ok = n.probeTarget(context.Background(), n.Targets[0], p, 0)
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{
+		fieldPacketsSent:     4,
+		fieldPacketsReceived: 0,
+		fieldPacketLoss:      100.0,
+	}, r.fields())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, ok = n.probeTarget(ctx, n.Targets[0], p, 0)
+	assert.False(t, ok)
+}
+
+func TestRateLimit(t *testing.T) {
+	n := &NetworkProbe{
+		Source:             "dc1",
+		MaxProbesPerSecond: 20,
+		Log:                testutil.Logger{},
+		Targets:            []*Target{{Address: "10.0.0.1", Count: 5}},
+		probers:            map[string]prober{},
+	}
+	require.NoError(t, n.Init())
+	start := time.Now()
+	_, ok := n.probeTarget(context.Background(), n.Targets[0], &fakeProber{rtts: []time.Duration{time.Millisecond}}, 0)
+	require.True(t, ok)
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestTCPAndUDP(t *testing.T) {
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer tcpListener.Close()
+	go func() {
+		for {
+			conn, err := tcpListener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	udpConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer udpConn.Close()
+	go func() {
+		buf := make([]byte, maxReplySize)
+		for {
+			n, addr, err := udpConn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			_, _ = udpConn.WriteTo(buf[:n], addr)
+		}
+	}()
+	// nothing is listening on the port of a closed socket
+	closed, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedPort := closed.LocalAddr().(*net.UDPAddr).Port
+	require.NoError(t, closed.Close())
+
+	n := &NetworkProbe{
+		Source: "dc1",
+		Log:    testutil.Logger{},
+		Targets: []*Target{
+			{Name: "web", Address: "127.0.0.1", Protocol: ProtocolTCP, Port: tcpListener.Addr().(*net.TCPAddr).Port, Timeout: config.Duration(time.Second)},
+			{Address: "127.0.0.1", Protocol: ProtocolUDP, Port: udpConn.LocalAddr().(*net.UDPAddr).Port, Timeout: config.Duration(time.Second)},
+			{Address: "127.0.0.1", Protocol: ProtocolUDP, Port: closedPort, Timeout: config.Duration(time.Second)},
+		},
+	}
+	require.NoError(t, n.Init())
+	var acc testutil.Accumulator
+	require.NoError(t, n.Start(&acc))
+	assert.Eventually(t, func() bool {
+		require.NoError(t, n.Gather(&acc))
+		return acc.NMetrics() == 3
+	}, 5*time.Second, 50*time.Millisecond)
+	n.Stop()
+
+	for _, m := range acc.GetTelegrafMetrics() {
+		assert.Equal(t, measurement, m.Name())
+		tags := m.Tags()
+		assert.Equal(t, "dc1", tags[tagSource])
+		assert.Contains(t, []string{"web", "127.0.0.1"}, tags[tagDestination])
+		assert.Contains(t, tags, tagPort)
+		fields := m.Fields()
+		assert.EqualValues(t, defaultCount, fields[fieldPacketsReceived], tags)
+		assert.EqualValues(t, 0, fields[fieldPacketLoss], tags)
+		assert.Contains(t, fields, fieldRTTAvg)
+	}
+	assert.True(t, acc.HasTag(measurement, tagProtocol))
+}
+
+func TestICMPLoopback(t *testing.T) {
+	p, err := newICMPProber()
+	if err != nil {
+		t.Skipf("ICMP sockets are not permitted: %v", err)
+	}
+	rtt, err := p.probe(context.Background(), &Target{Address: "127.0.0.1", Timeout: config.Duration(time.Second)}, 1)
+	require.NoError(t, err)
+	assert.Greater(t, rtt, time.Duration(0))
+}
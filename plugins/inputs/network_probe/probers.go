@@ -0,0 +1,192 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package network_probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+const (
+	protocolNumberICMP   = 1
+	protocolNumberICMPv6 = 58
+
+	maxReplySize = 1500
+)
+
+var (
+	probePayload = []byte("amazon-cloudwatch-agent-probe")
+
+	// listenICMP is overridden in tests.
+	listenICMP = icmp.ListenPacket
+)
+
+// tcpProber measures the time to complete the TCP handshake. A refused
+// connection still counts as a reply since the target answered.
+type tcpProber struct{}
+
+func (tcpProber) probe(ctx context.Context, t *Target, _ int) (time.Duration, error) {
+	dialer := net.Dialer{Timeout: time.Duration(t.Timeout)}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(t.Address, strconv.Itoa(t.Port)))
+	rtt := time.Since(start)
+	if err != nil {
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return rtt, nil
+		}
+		return 0, err
+	}
+	_ = conn.Close()
+	return rtt, nil
+}
+
+// udpProber measures the time for the target to reply to a datagram. An ICMP
+// port unreachable counts as a reply since the target answered.
+type udpProber struct{}
+
+func (udpProber) probe(ctx context.Context, t *Target, _ int) (time.Duration, error) {
+	dialer := net.Dialer{Timeout: time.Duration(t.Timeout)}
+	conn, err := dialer.DialContext(ctx, "udp", net.JoinHostPort(t.Address, strconv.Itoa(t.Port)))
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	if err = conn.SetDeadline(time.Now().Add(time.Duration(t.Timeout))); err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	if _, err = conn.Write(probePayload); err != nil {
+		return 0, err
+	}
+	buf := make([]byte, maxReplySize)
+	_, err = conn.Read(buf)
+	rtt := time.Since(start)
+	if err != nil && !errors.Is(err, syscall.ECONNREFUSED) {
+		return 0, err
+	}
+	return rtt, nil
+}
+
+// icmpProber sends echo requests. Unprivileged ICMP sockets are used when the
+// host allows them, otherwise raw sockets are used which require CAP_NET_RAW.
+type icmpProber struct {
+	privileged bool
+	id         atomic.Uint32
+}
+
+// newICMPProber checks which kind of ICMP socket the agent is allowed to open.
+func newICMPProber() (*icmpProber, error) {
+	conn, err := listenICMP("udp4", "0.0.0.0")
+	if err == nil {
+		_ = conn.Close()
+		return &icmpProber{}, nil
+	}
+	conn, rawErr := listenICMP("ip4:icmp", "0.0.0.0")
+	if rawErr != nil {
+		return nil, errors.Join(err, rawErr)
+	}
+	_ = conn.Close()
+	return &icmpProber{privileged: true}, nil
+}
+
+func (p *icmpProber) probe(ctx context.Context, t *Target, seq int) (time.Duration, error) {
+	ip, err := resolve(ctx, t.Address)
+	if err != nil {
+		return 0, err
+	}
+	network, address, protocol := "udp4", "0.0.0.0", protocolNumberICMP
+	var requestType, replyType icmp.Type = ipv4.ICMPTypeEcho, ipv4.ICMPTypeEchoReply
+	if ip.To4() == nil {
+		network, address, protocol = "udp6", "::", protocolNumberICMPv6
+		requestType, replyType = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply
+	}
+	var dst net.Addr = &net.UDPAddr{IP: ip}
+	if p.privileged {
+		network = "ip4:icmp"
+		if ip.To4() == nil {
+			network = "ip6:ipv6-icmp"
+		}
+		dst = &net.IPAddr{IP: ip}
+	}
+
+	conn, err := listenICMP(network, address)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	if err = conn.SetDeadline(time.Now().Add(time.Duration(t.Timeout))); err != nil {
+		return 0, err
+	}
+	id := int(p.id.Add(1) & 0xffff)
+	request, err := (&icmp.Message{
+		Type: requestType,
+		Body: &icmp.Echo{ID: id, Seq: seq, Data: probePayload},
+	}).Marshal(nil)
+	if err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	if _, err = conn.WriteTo(request, dst); err != nil {
+		return 0, err
+	}
+	buf := make([]byte, maxReplySize)
+	for {
+		n, peer, err := conn.ReadFrom(buf)
+		if err != nil {
+			return 0, err
+		}
+		rtt := time.Since(start)
+		reply, err := icmp.ParseMessage(protocol, buf[:n])
+		if err != nil || reply.Type != replyType {
+			continue
+		}
+		echo, ok := reply.Body.(*icmp.Echo)
+		if !ok || echo.Seq != seq || !peerIP(peer).Equal(ip) {
+			continue
+		}
+		// the kernel rewrites the ID of unprivileged sockets and only delivers
+		// the replies for the socket, but raw sockets receive every reply
+		if p.privileged && echo.ID != id {
+			continue
+		}
+		return rtt, nil
+	}
+}
+
+func peerIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case *net.UDPAddr:
+		return a.IP
+	case *net.IPAddr:
+		return a.IP
+	}
+	return nil
+}
+
+// resolve returns the IPv4 address of the host if it has one.
+func resolve(ctx context.Context, host string) (net.IP, error) {
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no address found for %s", host)
+	}
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip, nil
+		}
+	}
+	return ips[0], nil
+}
@@ -0,0 +1,99 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package nfs
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// opStats are the cumulative statistics of an NFS operation of a mount.
+type opStats struct {
+	ops      uint64
+	trans    uint64
+	timeouts uint64
+	// executeMs is the time from the queuing of the requests to the
+	// completion of their replies.
+	executeMs uint64
+}
+
+// mountStats are the cumulative statistics of an NFS mount.
+type mountStats struct {
+	device     string
+	mountPoint string
+	fstype     string
+	readBytes  uint64
+	writeBytes uint64
+	ops        map[string]opStats
+}
+
+// parseMountStats reads the NFS mounts of /proc/<pid>/mountstats. The other
+// mounts are skipped.
+//
+//	device 10.0.0.1:/ mounted on /mnt/efs with fstype nfs4 statvers=1.1
+//		bytes:	0 0 0 0 4096 8192 1 2
+//		per-op statistics
+//		        READ: 10 11 0 1600 4720 3 25 30 0
+func parseMountStats(r io.Reader) ([]*mountStats, error) {
+	var mounts []*mountStats
+	var current *mountStats
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "device" {
+			current = nil
+			// device <device> mounted on <mount point> with fstype <fstype> [statvers=<version>]
+			if len(fields) >= 8 && fields[2] == "mounted" && fields[3] == "on" && fields[5] == "with" && fields[6] == "fstype" && isNFS(fields[7]) {
+				current = &mountStats{
+					device:     fields[1],
+					mountPoint: fields[4],
+					fstype:     fields[7],
+					ops:        map[string]opStats{},
+				}
+				mounts = append(mounts, current)
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		switch {
+		case fields[0] == "bytes:":
+			// normalread normalwrite directread directwrite serverread serverwrite readpages writepages
+			if len(fields) >= 7 {
+				current.readBytes = parseUint(fields[5])
+				current.writeBytes = parseUint(fields[6])
+			}
+		case strings.HasSuffix(fields[0], ":") && len(fields) >= 9 && isUpper(fields[0]):
+			// ops transmissions major_timeouts bytes_sent bytes_recv queue_ms rtt_ms execute_ms [errors]
+			current.ops[strings.TrimSuffix(fields[0], ":")] = opStats{
+				ops:       parseUint(fields[1]),
+				trans:     parseUint(fields[2]),
+				timeouts:  parseUint(fields[3]),
+				executeMs: parseUint(fields[8]),
+			}
+		}
+	}
+	return mounts, scanner.Err()
+}
+
+func isNFS(fstype string) bool {
+	return fstype == "nfs" || fstype == "nfs4"
+}
+
+// isUpper returns true for the names of the operations, e.g. READ: or
+// GETATTR:, to tell them from the other lines ending with a colon.
+func isUpper(s string) bool {
+	return strings.ToUpper(s) == s
+}
+
+func parseUint(s string) uint64 {
+	v, _ := strconv.ParseUint(s, 10, 64)
+	return v
+}
@@ -0,0 +1,289 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package nfs
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+const (
+	measurement = "nfs"
+
+	defaultProcPath = "/proc"
+	// procPathEnv is the procfs mount of the host when the agent runs in a
+	// container, as for gopsutil.
+	procPathEnv = "HOST_PROC"
+
+	defaultProbeTimeout = 5 * time.Second
+
+	tagMountPoint = "mount_point"
+	tagExport     = "export"
+	tagFstype     = "fstype"
+
+	fieldOps          = "ops"
+	fieldRetrans      = "retrans"
+	fieldTimeouts     = "timeouts"
+	fieldOpLatency    = "op_latency"
+	fieldReadOps      = "read_ops"
+	fieldReadLatency  = "read_latency"
+	fieldReadBytes    = "read_bytes"
+	fieldWriteOps     = "write_ops"
+	fieldWriteLatency = "write_latency"
+	fieldWriteBytes   = "write_bytes"
+	fieldResponsive   = "responsive"
+	fieldStale        = "stale"
+	fieldProbeLatency = "probe_latency"
+)
+
+// probe stats the mount point. It blocks while the server of a hard mount
+// does not respond.
+var probe = func(path string) error {
+	_, err := os.Stat(path)
+	return err
+}
+
+// NFS reports the operations of the NFS mounts, e.g. EFS, from their
+// mountstats, and probes each mount point so the hung and stale mounts are
+// reported instead of silently blocking the processes reading them.
+type NFS struct {
+	ProcPath     string          `toml:"proc_path"`
+	MountPoints  []string        `toml:"mount_points"`
+	ProbeTimeout config.Duration `toml:"probe_timeout"`
+	Log          telegraf.Logger `toml:"-"`
+
+	mountPoints filter.Filter
+	// previous are the statistics of the last collection, to report the
+	// operations of each interval.
+	previous map[string]*mountStats
+	// probes are the probes not completed yet. A mount is not probed again until its last probe returns, so the hung
+	// mounts do not pile up goroutines.
+	probes map[string]*pendingProbe
+	// warned is set once the missing mountstats were logged.
+	warned bool
+}
+
+type pendingProbe struct {
+	start time.Time
+	done  chan error
+	// warned is set once the probe timed out.
+	warned bool
+}
+
+var _ telegraf.Input = (*NFS)(nil)
+
+func (*NFS) SampleConfig() string {
+	return sampleConfig
+}
+
+func (*NFS) Description() string {
+	return "Collects the operations and the responsiveness of the NFS mounts"
+}
+
+func (n *NFS) Init() error {
+	if n.ProcPath == "" {
+		n.ProcPath = defaultProcPath
+		if hostProc := os.Getenv(procPathEnv); hostProc != "" {
+			n.ProcPath = hostProc
+		}
+	}
+	if n.ProbeTimeout <= 0 {
+		n.ProbeTimeout = config.Duration(defaultProbeTimeout)
+	}
+	mountPoints, err := filter.Compile(n.MountPoints)
+	if err != nil {
+		return fmt.Errorf("invalid mount_points: %w", err)
+	}
+	n.mountPoints = mountPoints
+	n.previous = map[string]*mountStats{}
+	n.probes = map[string]*pendingProbe{}
+	return nil
+}
+
+func (n *NFS) Gather(acc telegraf.Accumulator) error {
+	path := filepath.Join(n.ProcPath, "self", "mountstats")
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if !n.warned {
+			n.warned = true
+			n.Log.Warnf("%s not found, the NFS mounts are not collected", path)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	mounts, err := parseMountStats(f)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", path, err)
+	}
+
+	var collected []*mountStats
+	for _, mount := range mounts {
+		if n.mountPoints != nil && !n.mountPoints.Match(mount.mountPoint) {
+			continue
+		}
+		collected = append(collected, mount)
+		n.startProbe(mount.mountPoint)
+	}
+	// the mounts are probed in parallel, so the collection takes at most
+	// the probe timeout
+	deadline := time.Now().Add(time.Duration(n.ProbeTimeout))
+	current := make(map[string]*mountStats, len(collected))
+	for _, mount := range collected {
+		key := mount.device + " " + mount.mountPoint
+		current[key] = mount
+		fields := n.probeFields(mount.mountPoint, deadline)
+		if previous, ok := n.previous[key]; ok {
+			addDeltaFields(fields, previous, mount)
+		}
+		acc.AddFields(measurement, fields, map[string]string{
+			tagMountPoint: mount.mountPoint,
+			tagExport:     mount.device,
+			tagFstype:     mount.fstype,
+		})
+	}
+	n.previous = current
+	for mountPoint := range n.probes {
+		if !n.isMounted(mountPoint) {
+			delete(n.probes, mountPoint)
+		}
+	}
+	return nil
+}
+
+func (n *NFS) isMounted(mountPoint string) bool {
+	for _, mount := range n.previous {
+		if mount.mountPoint == mountPoint {
+			return true
+		}
+	}
+	return false
+}
+
+// startProbe probes the mount point, unless its previous probe did not
+// return yet.
+func (n *NFS) startProbe(mountPoint string) {
+	if _, ok := n.probes[mountPoint]; ok {
+		return
+	}
+	p := &pendingProbe{start: time.Now(), done: make(chan error, 1)}
+	n.probes[mountPoint] = p
+	go func() {
+		p.done <- probe(mountPoint)
+	}()
+}
+
+// probeFields waits for the probe of the mount point until the deadline.
+func (n *NFS) probeFields(mountPoint string, deadline time.Time) map[string]interface{} {
+	p := n.probes[mountPoint]
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	select {
+	case err := <-p.done:
+		delete(n.probes, mountPoint)
+		stale := errors.Is(err, syscall.ESTALE)
+		if stale {
+			n.Log.Warnf("The NFS mount %s has a stale file handle", mountPoint)
+		}
+		return map[string]interface{}{
+			fieldResponsive:   boolToInt(!stale),
+			fieldStale:        boolToInt(stale),
+			fieldProbeLatency: float64(time.Since(p.start)) / float64(time.Millisecond),
+		}
+	case <-timer.C:
+		if !p.warned {
+			p.warned = true
+			n.Log.Warnf("The NFS mount %s did not respond within %s", mountPoint, time.Duration(n.ProbeTimeout))
+		}
+		return map[string]interface{}{
+			fieldResponsive: 0,
+			fieldStale:      0,
+		}
+	}
+}
+
+// addDeltaFields adds the operations since the previous collection. Nothing
+// is added if the counters were reset, e.g. by a remount.
+func addDeltaFields(fields map[string]interface{}, previous, current *mountStats) {
+	if current.readBytes < previous.readBytes || current.writeBytes < previous.writeBytes {
+		return
+	}
+	deltas := make(map[string]interface{})
+	var total opStats
+	for name, op := range current.ops {
+		prev := previous.ops[name]
+		if op.ops < prev.ops || op.trans < prev.trans || op.timeouts < prev.timeouts || op.executeMs < prev.executeMs {
+			return
+		}
+		delta := opStats{
+			ops:       op.ops - prev.ops,
+			trans:     op.trans - prev.trans,
+			timeouts:  op.timeouts - prev.timeouts,
+			executeMs: op.executeMs - prev.executeMs,
+		}
+		total.ops += delta.ops
+		total.trans += delta.trans
+		total.timeouts += delta.timeouts
+		total.executeMs += delta.executeMs
+		switch name {
+		case "READ":
+			deltas[fieldReadOps] = delta.ops
+			deltas[fieldReadLatency] = latency(delta)
+		case "WRITE":
+			deltas[fieldWriteOps] = delta.ops
+			deltas[fieldWriteLatency] = latency(delta)
+		}
+	}
+	deltas[fieldOps] = total.ops
+	// the requests sent but not replied yet are counted as retransmissions
+	// until they complete
+	deltas[fieldRetrans] = uint64(0)
+	if total.trans > total.ops {
+		deltas[fieldRetrans] = total.trans - total.ops
+	}
+	deltas[fieldTimeouts] = total.timeouts
+	deltas[fieldOpLatency] = latency(total)
+	deltas[fieldReadBytes] = current.readBytes - previous.readBytes
+	deltas[fieldWriteBytes] = current.writeBytes - previous.writeBytes
+	for k, v := range deltas {
+		fields[k] = v
+	}
+}
+
+// latency returns the average time of the operations in milliseconds.
+func latency(op opStats) float64 {
+	if op.ops == 0 {
+		return 0
+	}
+	return float64(op.executeMs) / float64(op.ops)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func init() {
+	inputs.Add(measurement, func() telegraf.Input {
+		return &NFS{}
+	})
+}
@@ -0,0 +1,141 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package nfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mountStatsFormat = `device proc mounted on /proc with fstype proc
+device 10.0.0.1:/ mounted on /mnt/efs with fstype nfs4 statvers=1.1
+	opts:	rw,vers=4.1,rsize=1048576,wsize=1048576,hard,proto=tcp,timeo=600,retrans=2
+	age:	3600
+	bytes:	0 0 0 0 %d %d 1 2
+	RPC iostats version: 1.1  p/v: 100003/4 (nfs)
+	xprt:	tcp 0 1 2 0 11 6442 6442 0 6442 0 2 0 0
+	per-op statistics
+	        NULL: 1 1 0 44 24 0 0 0 0
+	        READ: %d %d 0 1600 4720 3 25 %d 0
+	       WRITE: 4 4 1 8192 480 1 20 40 0
+	     GETATTR: 10 10 0 1600 2400 0 5 10 0
+device nfs.example.com:/export mounted on /mnt/stale with fstype nfs statvers=1.1
+	bytes:	0 0 0 0 0 0 0 0
+	per-op statistics
+	        READ: 0 0 0 0 0 0 0 0 0
+device nfs.example.com:/hung mounted on /mnt/hung with fstype nfs statvers=1.1
+	bytes:	0 0 0 0 0 0 0 0
+`
+
+func writeMountStats(t *testing.T, proc string, readBytes, writeBytes, readOps, readTrans, readExecuteMs int) {
+	t.Helper()
+	content := fmt.Sprintf(mountStatsFormat, readBytes, writeBytes, readOps, readTrans, readExecuteMs)
+	require.NoError(t, os.WriteFile(filepath.Join(proc, "self", "mountstats"), []byte(content), 0644))
+}
+
+func TestGather(t *testing.T) {
+	proc := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(proc, "self"), 0755))
+	writeMountStats(t, proc, 4096, 8192, 10, 10, 30)
+
+	hung := make(chan struct{})
+	defer close(hung)
+	defer func(original func(string) error) {
+		probe = original
+	}(probe)
+	var mu sync.Mutex
+	probes := map[string]int{}
+	probe = func(path string) error {
+		mu.Lock()
+		probes[path]++
+		mu.Unlock()
+		switch path {
+		case "/mnt/stale":
+			return &os.PathError{Op: "stat", Path: path, Err: syscall.ESTALE}
+		case "/mnt/hung":
+			<-hung
+		}
+		return nil
+	}
+
+	n := &NFS{ProcPath: proc, ProbeTimeout: config.Duration(50 * time.Millisecond), Log: testutil.Logger{}}
+	require.NoError(t, n.Init())
+	var acc testutil.Accumulator
+	require.NoError(t, n.Gather(&acc))
+	require.Len(t, acc.Metrics, 3)
+	efs := acc.Metrics[0]
+	assert.Equal(t, map[string]string{tagMountPoint: "/mnt/efs", tagExport: "10.0.0.1:/", tagFstype: "nfs4"}, efs.Tags)
+	assert.Equal(t, 1, efs.Fields[fieldResponsive])
+	assert.Equal(t, 0, efs.Fields[fieldStale])
+	assert.Contains(t, efs.Fields, fieldProbeLatency)
+	// the operations are reported from the second collection
+	assert.NotContains(t, efs.Fields, fieldOps)
+	assert.Equal(t, map[string]interface{}{fieldResponsive: 0, fieldStale: 1, fieldProbeLatency: acc.Metrics[1].Fields[fieldProbeLatency]}, acc.Metrics[1].Fields)
+	assert.Equal(t, map[string]interface{}{fieldResponsive: 0, fieldStale: 0}, acc.Metrics[2].Fields)
+
+	writeMountStats(t, proc, 5120, 8192, 14, 17, 50)
+	acc.ClearMetrics()
+	require.NoError(t, n.Gather(&acc))
+	require.Len(t, acc.Metrics, 3)
+	efs = acc.Metrics[0]
+	assert.Equal(t, uint64(4), efs.Fields[fieldOps])
+	assert.Equal(t, uint64(3), efs.Fields[fieldRetrans])
+	assert.Equal(t, uint64(0), efs.Fields[fieldTimeouts])
+	assert.Equal(t, 5.0, efs.Fields[fieldOpLatency])
+	assert.Equal(t, uint64(4), efs.Fields[fieldReadOps])
+	assert.Equal(t, 5.0, efs.Fields[fieldReadLatency])
+	assert.Equal(t, uint64(1024), efs.Fields[fieldReadBytes])
+	assert.Equal(t, uint64(0), efs.Fields[fieldWriteOps])
+	assert.Equal(t, uint64(0), efs.Fields[fieldWriteBytes])
+	assert.Equal(t, 0, acc.Metrics[2].Fields[fieldResponsive])
+	// the hung mount is not probed again until its probe returns
+	mu.Lock()
+	assert.Equal(t, map[string]int{"/mnt/efs": 2, "/mnt/stale": 2, "/mnt/hung": 1}, probes)
+	mu.Unlock()
+
+	// the counters are reset by a remount
+	writeMountStats(t, proc, 0, 0, 1, 1, 2)
+	acc.ClearMetrics()
+	require.NoError(t, n.Gather(&acc))
+	assert.NotContains(t, acc.Metrics[0].Fields, fieldOps)
+}
+
+func TestGatherWithMountPoints(t *testing.T) {
+	proc := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(proc, "self"), 0755))
+	writeMountStats(t, proc, 0, 0, 0, 0, 0)
+	defer func(original func(string) error) {
+		probe = original
+	}(probe)
+	probe = func(string) error {
+		return nil
+	}
+
+	n := &NFS{ProcPath: proc, MountPoints: []string{"/mnt/e*"}, Log: testutil.Logger{}}
+	require.NoError(t, n.Init())
+	assert.Equal(t, config.Duration(defaultProbeTimeout), n.ProbeTimeout)
+	var acc testutil.Accumulator
+	require.NoError(t, n.Gather(&acc))
+	require.Len(t, acc.Metrics, 1)
+	assert.Equal(t, "/mnt/efs", acc.Metrics[0].Tags[tagMountPoint])
+}
+
+func TestGatherWithoutMountStats(t *testing.T) {
+	n := &NFS{ProcPath: t.TempDir(), Log: testutil.Logger{}}
+	require.NoError(t, n.Init())
+	var acc testutil.Accumulator
+	require.NoError(t, n.Gather(&acc))
+	assert.Empty(t, acc.Metrics)
+	assert.True(t, n.warned)
+}
@@ -0,0 +1,87 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlp_logs
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+type scopeBody struct {
+	Name       string         `json:"name,omitempty"`
+	Version    string         `json:"version,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// recordBody is the same JSON structure as the one used by the awscloudwatchlogs exporter, so
+// the log records look the same regardless of the pipeline they were sent through.
+type recordBody struct {
+	Body                   any            `json:"body,omitempty"`
+	SeverityNumber         int32          `json:"severity_number,omitempty"`
+	SeverityText           string         `json:"severity_text,omitempty"`
+	DroppedAttributesCount uint32         `json:"dropped_attributes_count,omitempty"`
+	Flags                  uint32         `json:"flags,omitempty"`
+	TraceID                string         `json:"trace_id,omitempty"`
+	SpanID                 string         `json:"span_id,omitempty"`
+	Attributes             map[string]any `json:"attributes,omitempty"`
+	Scope                  *scopeBody     `json:"scope,omitempty"`
+	Resource               map[string]any `json:"resource,omitempty"`
+}
+
+// recordMessage returns the body of the log record as is if rawLog is set, otherwise the log
+// record with its scope and resource encoded as JSON.
+func recordMessage(resource pcommon.Resource, scope pcommon.InstrumentationScope, record plog.LogRecord, rawLog bool) (string, error) {
+	if rawLog {
+		return record.Body().AsString(), nil
+	}
+	body := recordBody{
+		Body:                   record.Body().AsRaw(),
+		SeverityNumber:         int32(record.SeverityNumber()),
+		SeverityText:           record.SeverityText(),
+		DroppedAttributesCount: record.DroppedAttributesCount(),
+		Flags:                  uint32(record.Flags()),
+		Attributes:             attributesValue(record.Attributes()),
+		Resource:               attributesValue(resource.Attributes()),
+	}
+	if traceID := record.TraceID(); !traceID.IsEmpty() {
+		body.TraceID = hex.EncodeToString(traceID[:])
+	}
+	if spanID := record.SpanID(); !spanID.IsEmpty() {
+		body.SpanID = hex.EncodeToString(spanID[:])
+	}
+	if scope.Name() != "" {
+		body.Scope = &scopeBody{
+			Name:       scope.Name(),
+			Version:    scope.Version(),
+			Attributes: attributesValue(scope.Attributes()),
+		}
+	}
+	content, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// recordTime falls back to the observed time if the log record has no time.
+func recordTime(record plog.LogRecord) time.Time {
+	if record.Timestamp() != 0 {
+		return record.Timestamp().AsTime()
+	}
+	if record.ObservedTimestamp() != 0 {
+		return record.ObservedTimestamp().AsTime()
+	}
+	return time.Now()
+}
+
+func attributesValue(attrs pcommon.Map) map[string]any {
+	if attrs.Len() == 0 {
+		return nil
+	}
+	return attrs.AsRaw()
+}
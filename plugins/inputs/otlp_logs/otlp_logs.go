@@ -0,0 +1,286 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlp_logs
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/common/tls"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+	tlsInternal "github.com/aws/amazon-cloudwatch-agent/internal/tls"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+const (
+	defaultHTTPEndpoint = "127.0.0.1:4318"
+	defaultLogGroupName = "otlp/logs/default"
+
+	logsPath = "/v1/logs"
+
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeJSON     = "application/json"
+
+	maxRequestBodySize = 20 * 1024 * 1024
+	shutdownTimeout    = 5 * time.Second
+)
+
+type Plugin struct {
+	HTTPEndpoint  string `toml:"http_endpoint"`
+	LogGroupName  string `toml:"log_group_name"`
+	LogStreamName string `toml:"log_stream_name"`
+	LogGroupClass string `toml:"log_group_class"`
+	Retention     int    `toml:"retention_in_days"`
+	Destination   string `toml:"destination"`
+	RawLog        bool   `toml:"raw_log"`
+	tls.ServerConfig
+
+	Log telegraf.Logger `toml:"-"`
+
+	groupTemplate  *logsource.Template
+	streamTemplate *logsource.Template
+
+	mu         sync.Mutex
+	src        *logsource.Src
+	newSources []logs.LogSrc
+	server     *http.Server
+	tlsReload  *tlsInternal.Provider
+	wg         sync.WaitGroup
+}
+
+var _ logs.LogCollection = (*Plugin)(nil)
+
+func (p *Plugin) Description() string {
+	return "A plugin to receive log records over OTLP/HTTP"
+}
+
+func (p *Plugin) SampleConfig() string {
+	return `
+	http_endpoint = "127.0.0.1:4318"
+	log_group_name = "/otlp/{resource.service.name}"
+	log_stream_name = "{resource.host.name}"
+	destination = "cloudwatchlogs"
+	`
+}
+
+func (p *Plugin) Gather(acc telegraf.Accumulator) error {
+	return nil
+}
+
+func (p *Plugin) FindLogSrc() []logs.LogSrc {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	srcs := p.newSources
+	p.newSources = nil
+	return srcs
+}
+
+// Start listens for the OTLP/HTTP requests. The listener is created before
+// returning so that an endpoint already in use is reported right away.
+func (p *Plugin) Start(acc telegraf.Accumulator) error {
+	if p.HTTPEndpoint == "" {
+		p.HTTPEndpoint = defaultHTTPEndpoint
+	}
+	tlsConfig, err := p.ServerConfig.TLSConfig()
+	if err != nil {
+		return fmt.Errorf("invalid tls config: %w", err)
+	}
+	if tlsConfig != nil {
+		// the rotated certificates are used without restarting the listener
+		if p.tlsReload, err = tlsInternal.NewProvider(p.TLSCert, p.TLSKey, p.TLSAllowedCACerts); err != nil {
+			return fmt.Errorf("invalid tls config: %w", err)
+		}
+		if err = p.tlsReload.Start(); err != nil {
+			return err
+		}
+		tlsConfig = p.tlsReload.ApplyServer(tlsConfig)
+	}
+	p.initSource()
+
+	listener, err := net.Listen("tcp", p.HTTPEndpoint)
+	if err != nil {
+		p.closeTLSReload()
+		return fmt.Errorf("unable to listen on %s: %w", p.HTTPEndpoint, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(logsPath, p.handleLogs)
+	p.server = &http.Server{
+		Handler:           mux,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		var serveErr error
+		if tlsConfig != nil {
+			serveErr = p.server.ServeTLS(listener, "", "")
+		} else {
+			serveErr = p.server.Serve(listener)
+		}
+		if serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+			p.Log.Errorf("Stopped receiving OTLP logs on %s: %v", p.HTTPEndpoint, serveErr)
+		}
+	}()
+	return nil
+}
+
+func (p *Plugin) Stop() {
+	if p.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := p.server.Shutdown(ctx); err != nil {
+			p.Log.Warnf("Unable to shut down the OTLP logs endpoint %s: %v", p.HTTPEndpoint, err)
+		}
+	}
+	p.wg.Wait()
+	p.closeTLSReload()
+	if p.src != nil {
+		p.src.Stop()
+	}
+}
+
+func (p *Plugin) closeTLSReload() {
+	if p.tlsReload == nil {
+		return
+	}
+	if err := p.tlsReload.Close(); err != nil {
+		p.Log.Warnf("Unable to stop watching the TLS certificates: %v", err)
+	}
+	p.tlsReload = nil
+}
+
+// initSource creates the single log source for the plugin. The log group and
+// stream of the source are the names without the attribute references.
+func (p *Plugin) initSource() {
+	var group, stream string
+	p.groupTemplate, group = logsource.NewGroupTemplate(p.LogGroupName, attributeReferenceRegexp)
+	p.streamTemplate, stream = logsource.NewStreamTemplate(p.LogStreamName, attributeReferenceRegexp)
+	if group == "" {
+		group = defaultLogGroupName
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// the log group and stream of the source are used by the records that cannot be routed from their resource
+	p.src = logsource.New(group, stream, "otlp:"+p.HTTPEndpoint, p.Destination, p.LogGroupClass, p.Retention)
+	p.newSources = append(p.newSources, p.src)
+}
+
+// handleLogs responds once all the log records in the request have been
+// handed to the output, so that the clients are slowed down rather than the
+// records being dropped when the output falls behind.
+func (p *Plugin) handleLogs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// the content type may have parameters, e.g. application/json; charset=utf-8
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType != contentTypeProtobuf && contentType != contentTypeJSON {
+		http.Error(w, fmt.Sprintf("unsupported content type %q", contentType), http.StatusUnsupportedMediaType)
+		return
+	}
+	req, err := readRequest(r, contentType)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !p.publish(req, r.Context().Done()) {
+		http.Error(w, "unable to publish the log records", http.StatusServiceUnavailable)
+		return
+	}
+	resp := plogotlp.NewExportResponse()
+	var content []byte
+	if contentType == contentTypeJSON {
+		content, err = resp.MarshalJSON()
+	} else {
+		content, err = resp.MarshalProto()
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(content)
+}
+
+func readRequest(r *http.Request, contentType string) (plogotlp.ExportRequest, error) {
+	req := plogotlp.NewExportRequest()
+	body := io.LimitReader(r.Body, maxRequestBodySize)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(body)
+		if err != nil {
+			return req, err
+		}
+		defer zr.Close()
+		body = io.LimitReader(zr, maxRequestBodySize)
+	}
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return req, err
+	}
+	if contentType == contentTypeJSON {
+		err = req.UnmarshalJSON(content)
+	} else {
+		err = req.UnmarshalProto(content)
+	}
+	return req, err
+}
+
+// publish routes each log record to the log group and stream resolved from
+// its resource attributes. Returns false if any record was not handed over.
+func (p *Plugin) publish(req plogotlp.ExportRequest, stop <-chan struct{}) bool {
+	rls := req.Logs().ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		var group, stream string
+		if p.groupTemplate != nil {
+			group = resolveAttributes(p.groupTemplate, rl.Resource().Attributes())
+		}
+		if p.streamTemplate != nil {
+			stream = resolveAttributes(p.streamTemplate, rl.Resource().Attributes())
+		}
+		sls := rl.ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			sl := sls.At(j)
+			records := sl.LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				record := records.At(k)
+				msg, err := recordMessage(rl.Resource(), sl.Scope(), record, p.RawLog)
+				if err != nil {
+					p.Log.Debugf("Unable to convert the log record: %v", err)
+					continue
+				}
+				e := &logEvent{
+					message:   msg,
+					timestamp: recordTime(record),
+					group:     group,
+					stream:    stream,
+				}
+				if !p.src.Publish(e, stop) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func init() {
+	inputs.Add("otlp_logs", func() telegraf.Input { return &Plugin{} })
+}
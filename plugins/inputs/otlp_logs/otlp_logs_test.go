@@ -0,0 +1,190 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlp_logs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+func newTestRequest() plogotlp.ExportRequest {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	rl.Resource().Attributes().PutStr("host.name", "host-1")
+	record := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.Body().SetStr("order placed")
+	record.SetTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(1700000000000)))
+
+	// the resource without the service name uses the log group of the source
+	rl = ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("host.name", "host-2")
+	record = rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.Body().SetStr("no service")
+	return plogotlp.NewExportRequestFromLogs(ld)
+}
+
+func freeEndpoint(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func TestPlugin(t *testing.T) {
+	p := &Plugin{
+		HTTPEndpoint:  freeEndpoint(t),
+		LogGroupName:  "/otlp/{resource.service.name}",
+		LogStreamName: "{resource.host.name}",
+		Destination:   "cloudwatchlogs",
+		RawLog:        true,
+		Retention:     7,
+		Log:           testutil.Logger{},
+	}
+	require.NoError(t, p.Start(nil))
+	defer p.Stop()
+
+	srcs := p.FindLogSrc()
+	require.Len(t, srcs, 1)
+	src := srcs[0]
+	assert.Equal(t, "/otlp/", src.Group())
+	assert.Equal(t, "", src.Stream())
+	assert.Equal(t, "cloudwatchlogs", src.Destination())
+	assert.Equal(t, 7, src.Retention())
+	assert.Empty(t, p.FindLogSrc())
+
+	events := make(chan logs.LogEvent, 10)
+	src.SetOutput(func(e logs.LogEvent) {
+		if e != nil {
+			events <- e
+		}
+	})
+
+	content, err := newTestRequest().MarshalProto()
+	require.NoError(t, err)
+	resp, err := http.Post(fmt.Sprintf("http://%s/v1/logs", p.HTTPEndpoint), contentTypeProtobuf, bytes.NewReader(content))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, contentTypeProtobuf, resp.Header.Get("Content-Type"))
+
+	e := (<-events).(logs.RoutedLogEvent)
+	assert.Equal(t, "order placed", e.Message())
+	assert.Equal(t, "/otlp/checkout", e.Group())
+	assert.Equal(t, "host-1", e.Stream())
+	assert.True(t, time.UnixMilli(1700000000000).Equal(e.Time()))
+	e = (<-events).(logs.RoutedLogEvent)
+	assert.Equal(t, "no service", e.Message())
+	assert.Equal(t, "", e.Group())
+	assert.Equal(t, "host-2", e.Stream())
+}
+
+func TestHandleLogs(t *testing.T) {
+	jsonContent, err := newTestRequest().MarshalJSON()
+	require.NoError(t, err)
+	var gzipped bytes.Buffer
+	zw := gzip.NewWriter(&gzipped)
+	_, err = zw.Write(jsonContent)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	testCases := map[string]struct {
+		method          string
+		contentType     string
+		contentEncoding string
+		body            []byte
+		wantStatus      int
+		wantEvents      int
+	}{
+		"WithJSON": {
+			method:      http.MethodPost,
+			contentType: "application/json; charset=utf-8",
+			body:        jsonContent,
+			wantStatus:  http.StatusOK,
+			wantEvents:  2,
+		},
+		"WithGzip": {
+			method:          http.MethodPost,
+			contentType:     contentTypeJSON,
+			contentEncoding: "gzip",
+			body:            gzipped.Bytes(),
+			wantStatus:      http.StatusOK,
+			wantEvents:      2,
+		},
+		"WithGet": {
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		"WithUnsupportedContentType": {
+			method:      http.MethodPost,
+			contentType: "text/plain",
+			body:        []byte("log"),
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+		"WithInvalidBody": {
+			method:      http.MethodPost,
+			contentType: contentTypeJSON,
+			body:        []byte("{"),
+			wantStatus:  http.StatusBadRequest,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			p := &Plugin{LogGroupName: "{resource.service.name}", Log: testutil.Logger{}}
+			p.initSource()
+			events := make(chan logs.LogEvent, 10)
+			p.src.SetOutput(func(e logs.LogEvent) {
+				if e != nil {
+					events <- e
+				}
+			})
+			defer p.src.Stop()
+
+			req, err := http.NewRequest(testCase.method, logsPath, bytes.NewReader(testCase.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", testCase.contentType)
+			req.Header.Set("Content-Encoding", testCase.contentEncoding)
+			w := httptest.NewRecorder()
+			p.handleLogs(w, req)
+			assert.Equal(t, testCase.wantStatus, w.Code)
+			for i := 0; i < testCase.wantEvents; i++ {
+				e := <-events
+				if i == 0 {
+					assert.True(t, strings.HasPrefix(e.Message(), `{"body":"order placed"`))
+				}
+			}
+			assert.Equal(t, "otlp/logs/default", p.src.Group())
+		})
+	}
+}
+
+func TestHandleLogsStoppedSource(t *testing.T) {
+	p := &Plugin{Log: testutil.Logger{}}
+	p.initSource()
+	p.src.Stop()
+	content, err := newTestRequest().MarshalProto()
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, logsPath, bytes.NewReader(content))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentTypeProtobuf)
+	w := httptest.NewRecorder()
+	p.handleLogs(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
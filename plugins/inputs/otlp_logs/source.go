@@ -0,0 +1,67 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlp_logs
+
+import (
+	"regexp"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+// attributeReferenceRegexp matches references to resource attributes, e.g. {resource.service.name}
+var attributeReferenceRegexp = regexp.MustCompile(`\{resource\.([^{}]+)\}`)
+
+// resolveAttributes returns the name of the template with the attribute references replaced by
+// the values of the resource attributes. Returns an empty string if any of the attributes is
+// missing or empty. Only the first element of slice attributes, e.g. aws.log.group.names, is used.
+func resolveAttributes(t *logsource.Template, attrs pcommon.Map) string {
+	values := make([]string, len(t.References()))
+	for i, key := range t.References() {
+		value, ok := attrs.Get(key)
+		if !ok {
+			return ""
+		}
+		if value.Type() == pcommon.ValueTypeSlice {
+			if value.Slice().Len() == 0 {
+				return ""
+			}
+			value = value.Slice().At(0)
+		}
+		values[i] = value.AsString()
+	}
+	return t.Resolve(values)
+}
+
+type logEvent struct {
+	message   string
+	timestamp time.Time
+	group     string
+	stream    string
+}
+
+var _ logs.RoutedLogEvent = (*logEvent)(nil)
+
+func (e *logEvent) Message() string {
+	return e.message
+}
+
+func (e *logEvent) Time() time.Time {
+	return e.timestamp
+}
+
+func (e *logEvent) Done() {}
+
+// Group is the log group resolved from the resource attributes.
+func (e *logEvent) Group() string {
+	return e.group
+}
+
+// Stream is the log stream resolved from the resource attributes.
+func (e *logEvent) Stream() string {
+	return e.stream
+}
@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlp_logs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logsource"
+)
+
+func TestResolveAttributes(t *testing.T) {
+	template, _ := logsource.NewGroupTemplate("/otlp/logs", attributeReferenceRegexp)
+	assert.Nil(t, template)
+
+	attrs := pcommon.NewMap()
+	attrs.PutStr("service.name", "check out")
+	attrs.PutInt("service.version", 2)
+	attrs.PutEmptySlice("aws.log.group.names").AppendEmpty().SetStr("/app/logs")
+	attrs.PutEmptySlice("aws.log.stream.names")
+
+	testCases := map[string]struct {
+		name string
+		want string
+	}{
+		"WithAttributes": {
+			name: "/otlp/{resource.service.name}/v{resource.service.version}",
+			want: "/otlp/check_out/v2",
+		},
+		"WithSlice": {
+			name: "{resource.aws.log.group.names}",
+			want: "/app/logs",
+		},
+		"WithEmptySlice": {
+			name: "{resource.aws.log.stream.names}",
+			want: "",
+		},
+		"WithMissingAttribute": {
+			name: "/otlp/{resource.service.name}/{resource.deployment.environment}",
+			want: "",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			template, _ := logsource.NewGroupTemplate(testCase.name, attributeReferenceRegexp)
+			assert.Equal(t, testCase.want, resolveAttributes(template, attrs))
+		})
+	}
+
+	template, static := logsource.NewStreamTemplate("{resource.service.name}:*", attributeReferenceRegexp)
+	assert.Equal(t, "check out__", resolveAttributes(template, attrs))
+	assert.Equal(t, ":*", static)
+}
@@ -0,0 +1,94 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstat
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const cgroupProcsFile = "cgroup.procs"
+
+// cgroupMounts are the mounts of the cgroup hierarchy that systemd manages,
+// relative to the cgroup root. The unified hierarchy is at the root with
+// cgroup v2 and in unified/ with the hybrid layout. With cgroup v1, systemd
+// uses its own named hierarchy.
+var cgroupMounts = []string{"", "unified", "systemd"}
+
+// cgroupPIDs returns the PIDs of the processes in the control group and its
+// children. The child control groups contain the processes that were moved by
+// the unit, so they are still members of the unit.
+func cgroupPIDs(root string, controlGroup string) ([]int32, error) {
+	for _, mount := range cgroupMounts {
+		dir := filepath.Join(root, mount, controlGroup)
+		if _, err := os.Stat(filepath.Join(dir, cgroupProcsFile)); err != nil {
+			continue
+		}
+		var pids []int32
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// the child control group was removed during the walk
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() || d.Name() != cgroupProcsFile {
+				return nil
+			}
+			procs, err := readCgroupProcs(path)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			pids = append(pids, procs...)
+			return nil
+		})
+		return pids, err
+	}
+	return nil, errors.New("control group not found: " + controlGroup)
+}
+
+func readCgroupProcs(path string) ([]int32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var pids []int32
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		pid, err := strconv.ParseInt(line, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		pids = append(pids, int32(pid))
+	}
+	return pids, scanner.Err()
+}
+
+// descendants returns the root and all the processes started by it, directly
+// or not, from the parent PID of each process.
+func descendants(root int32, parents map[int32]int32) []int32 {
+	children := make(map[int32][]int32, len(parents))
+	for pid, parent := range parents {
+		// the idle process is its own parent on Windows
+		if pid != parent {
+			children[parent] = append(children[parent], pid)
+		}
+	}
+	pids := []int32{root}
+	for i := 0; i < len(pids); i++ {
+		pids = append(pids, children[pids[i]]...)
+	}
+	return pids
+}
@@ -0,0 +1,235 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package procstat extends the telegraf procstat input to monitor all the
+// processes of a systemd unit or a Windows service as a single unit. The
+// other selectors are handled by the telegraf input.
+package procstat
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	telegrafprocstat "github.com/influxdata/telegraf/plugins/inputs/procstat"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+const (
+	measurement       = "procstat"
+	lookupMeasurement = "procstat_lookup"
+
+	tagUnit = "unit"
+
+	modeSolaris = "solaris"
+)
+
+var errMultipleUnits = errors.New("only one of systemd_unit and win_service can be set")
+
+// unitResolver returns the PIDs of all the processes of the unit, including
+// the children, and the PID of its main process. The main PID is 0 if the
+// unit does not have one.
+type unitResolver func(unit string) (mainPID int32, pids []int32, err error)
+
+// unitProcess is the subset of the process stats aggregated for a unit.
+type unitProcess interface {
+	Times() (*cpu.TimesStat, error)
+	Percent(interval time.Duration) (float64, error)
+	MemoryInfo() (*process.MemoryInfoStat, error)
+	NumThreads() (int32, error)
+	NumFDs() (int32, error)
+	IOCounters() (*process.IOCountersStat, error)
+}
+
+// Procstat monitors processes like the telegraf procstat input. When
+// systemd_unit or win_service is set, the processes are resolved through
+// D-Bus or the service control manager and their metrics are summed into a
+// single metric with a unit tag.
+type Procstat struct {
+	telegrafprocstat.Procstat
+
+	unit          string
+	resolve       unitResolver
+	createProcess func(pid int32) (unitProcess, error)
+	procs         map[int32]unitProcess
+}
+
+var _ telegraf.Initializer = (*Procstat)(nil)
+
+func (p *Procstat) Init() error {
+	switch {
+	case p.SystemdUnit != "" && p.WinService != "":
+		return errMultipleUnits
+	case p.SystemdUnit != "":
+		p.unit = p.SystemdUnit
+		p.resolve = systemdUnitPIDs
+	case p.WinService != "":
+		p.unit = p.WinService
+		p.resolve = winServicePIDs
+	}
+	if p.createProcess == nil {
+		p.createProcess = newProcess
+	}
+	return p.Procstat.Init()
+}
+
+func (p *Procstat) Gather(acc telegraf.Accumulator) error {
+	if p.unit == "" {
+		return p.Procstat.Gather(acc)
+	}
+	return p.gatherUnit(acc)
+}
+
+// gatherUnit adds one procstat metric with the sum of the stats of the
+// processes of the unit and the procstat_lookup metric.
+func (p *Procstat) gatherUnit(acc telegraf.Accumulator) error {
+	now := time.Now()
+	mainPID, pids, err := p.resolve(p.unit)
+	if err != nil {
+		p.procs = nil
+		acc.AddFields(lookupMeasurement, map[string]interface{}{
+			"pid_count":   0,
+			"running":     0,
+			"result_code": 1,
+		}, map[string]string{tagUnit: p.unit, "result": "lookup_error"}, now)
+		return fmt.Errorf("unable to find the processes of %s: %w", p.unit, err)
+	}
+
+	// keep the processes between gathers, since the cpu usage is computed
+	// from the cpu times of the previous gather
+	procs := make(map[int32]unitProcess, len(pids))
+	for _, pid := range pids {
+		proc, ok := p.procs[pid]
+		if !ok {
+			if proc, err = p.createProcess(pid); err != nil {
+				// the process exited after the lookup
+				continue
+			}
+		}
+		procs[pid] = proc
+	}
+	p.procs = procs
+
+	tags := map[string]string{tagUnit: p.unit}
+	if len(procs) > 0 {
+		acc.AddFields(measurement, p.unitFields(mainPID, pids), tags, now)
+	}
+	acc.AddFields(lookupMeasurement, map[string]interface{}{
+		"pid_count":   len(pids),
+		"running":     len(procs),
+		"result_code": 0,
+	}, map[string]string{tagUnit: p.unit, "result": "success"}, now)
+	return nil
+}
+
+// unitFields sums the stats of the processes. The pid field is the main PID
+// of the unit, or the lowest PID if the unit does not have a main process, so
+// that restarts of the unit can be detected.
+func (p *Procstat) unitFields(mainPID int32, pids []int32) map[string]interface{} {
+	var prefix string
+	if p.Prefix != "" {
+		prefix = p.Prefix + "_"
+	}
+	var (
+		numThreads, numFDs                           int32
+		cpuUser, cpuSystem, cpuUsage                 float64
+		rss, vms, swap, data, stack, locked          uint64
+		readCount, writeCount, readBytes, writeBytes uint64
+		hasThreads, hasFDs, hasTimes, hasUsage       bool
+		hasMemory, hasIO                             bool
+	)
+	for _, proc := range p.procs {
+		if threads, err := proc.NumThreads(); err == nil {
+			numThreads += threads
+			hasThreads = true
+		}
+		if fds, err := proc.NumFDs(); err == nil {
+			numFDs += fds
+			hasFDs = true
+		}
+		if times, err := proc.Times(); err == nil {
+			cpuUser += times.User
+			cpuSystem += times.System
+			hasTimes = true
+		}
+		if usage, err := proc.Percent(0); err == nil {
+			cpuUsage += usage
+			hasUsage = true
+		}
+		if mem, err := proc.MemoryInfo(); err == nil {
+			rss += mem.RSS
+			vms += mem.VMS
+			swap += mem.Swap
+			data += mem.Data
+			stack += mem.Stack
+			locked += mem.Locked
+			hasMemory = true
+		}
+		if io, err := proc.IOCounters(); err == nil {
+			readCount += io.ReadCount
+			writeCount += io.WriteCount
+			readBytes += io.ReadBytes
+			writeBytes += io.WriteBytes
+			hasIO = true
+		}
+	}
+
+	pid := mainPID
+	if _, ok := p.procs[pid]; !ok {
+		pid = 0
+		for _, candidate := range pids {
+			if _, ok = p.procs[candidate]; ok && (pid == 0 || candidate < pid) {
+				pid = candidate
+			}
+		}
+	}
+	fields := map[string]interface{}{"pid": pid}
+	if hasThreads {
+		fields[prefix+"num_threads"] = numThreads
+	}
+	if hasFDs {
+		fields[prefix+"num_fds"] = numFDs
+	}
+	if hasTimes {
+		fields[prefix+"cpu_time_user"] = cpuUser
+		fields[prefix+"cpu_time_system"] = cpuSystem
+	}
+	if hasUsage {
+		if strings.EqualFold(p.Mode, modeSolaris) {
+			cpuUsage /= float64(runtime.NumCPU())
+		}
+		fields[prefix+"cpu_usage"] = cpuUsage
+	}
+	if hasMemory {
+		fields[prefix+"memory_rss"] = rss
+		fields[prefix+"memory_vms"] = vms
+		fields[prefix+"memory_swap"] = swap
+		fields[prefix+"memory_data"] = data
+		fields[prefix+"memory_stack"] = stack
+		fields[prefix+"memory_locked"] = locked
+	}
+	if hasIO {
+		fields[prefix+"read_count"] = readCount
+		fields[prefix+"write_count"] = writeCount
+		fields[prefix+"read_bytes"] = readBytes
+		fields[prefix+"write_bytes"] = writeBytes
+	}
+	return fields
+}
+
+func newProcess(pid int32) (unitProcess, error) {
+	return process.NewProcess(pid)
+}
+
+func init() {
+	// replaces the telegraf procstat input, which is registered first since
+	// this package imports it
+	inputs.Add(measurement, func() telegraf.Input {
+		return &Procstat{}
+	})
+}
@@ -0,0 +1,194 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstat
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	telegrafprocstat "github.com/influxdata/telegraf/plugins/inputs/procstat"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockProcess struct {
+	pid     int32
+	percent float64
+}
+
+func (m *mockProcess) Times() (*cpu.TimesStat, error) {
+	return &cpu.TimesStat{User: float64(m.pid), System: 1}, nil
+}
+
+func (m *mockProcess) Percent(time.Duration) (float64, error) {
+	return m.percent, nil
+}
+
+func (m *mockProcess) MemoryInfo() (*process.MemoryInfoStat, error) {
+	return &process.MemoryInfoStat{RSS: 100, VMS: 1000, Swap: 10}, nil
+}
+
+func (m *mockProcess) NumThreads() (int32, error) {
+	return 2, nil
+}
+
+func (m *mockProcess) NumFDs() (int32, error) {
+	return 0, errors.New("permission denied")
+}
+
+func (m *mockProcess) IOCounters() (*process.IOCountersStat, error) {
+	return &process.IOCountersStat{ReadBytes: 5, WriteBytes: 7}, nil
+}
+
+func TestInit(t *testing.T) {
+	p := &Procstat{Procstat: telegrafprocstat.Procstat{SystemdUnit: "nginx", WinService: "nginx"}}
+	assert.ErrorIs(t, p.Init(), errMultipleUnits)
+
+	p = &Procstat{Procstat: telegrafprocstat.Procstat{SystemdUnit: "nginx"}}
+	require.NoError(t, p.Init())
+	assert.Equal(t, "nginx", p.unit)
+	assert.NotNil(t, p.resolve)
+
+	p = &Procstat{Procstat: telegrafprocstat.Procstat{Exe: "nginx"}}
+	require.NoError(t, p.Init())
+	assert.Empty(t, p.unit)
+}
+
+func TestGatherUnit(t *testing.T) {
+	var (
+		mainPID int32 = 10
+		pids          = []int32{10, 11, 12}
+		created []int32
+	)
+	p := &Procstat{
+		unit: "nginx.service",
+		resolve: func(unit string) (int32, []int32, error) {
+			assert.Equal(t, "nginx.service", unit)
+			return mainPID, pids, nil
+		},
+		createProcess: func(pid int32) (unitProcess, error) {
+			created = append(created, pid)
+			if pid == 12 {
+				return nil, errors.New("process not found")
+			}
+			return &mockProcess{pid: pid, percent: 1.5}, nil
+		},
+	}
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, p.Gather(acc))
+	tags := map[string]string{tagUnit: "nginx.service"}
+	acc.AssertContainsTaggedFields(t, measurement, map[string]interface{}{
+		"pid":             int32(10),
+		"num_threads":     int32(4),
+		"cpu_time_user":   float64(21),
+		"cpu_time_system": float64(2),
+		"cpu_usage":       float64(3),
+		"memory_rss":      uint64(200),
+		"memory_vms":      uint64(2000),
+		"memory_swap":     uint64(20),
+		"memory_data":     uint64(0),
+		"memory_stack":    uint64(0),
+		"memory_locked":   uint64(0),
+		"read_count":      uint64(0),
+		"write_count":     uint64(0),
+		"read_bytes":      uint64(10),
+		"write_bytes":     uint64(14),
+	}, tags)
+	acc.AssertContainsTaggedFields(t, lookupMeasurement, map[string]interface{}{
+		"pid_count":   3,
+		"running":     2,
+		"result_code": 0,
+	}, map[string]string{tagUnit: "nginx.service", "result": "success"})
+
+	// the main process exited and a child was started, the lowest PID is used
+	mainPID = 0
+	pids = []int32{13, 11}
+	created = nil
+	acc.ClearMetrics()
+	require.NoError(t, p.Gather(acc))
+	assert.Equal(t, []int32{13}, created)
+	pid, ok := acc.Get(measurement)
+	require.True(t, ok)
+	assert.Equal(t, int32(11), pid.Fields["pid"])
+	assert.Len(t, p.procs, 2)
+}
+
+func TestGatherUnitLookupError(t *testing.T) {
+	p := &Procstat{
+		unit: "nginx.service",
+		resolve: func(string) (int32, []int32, error) {
+			return 0, nil, errors.New("unit not loaded")
+		},
+		procs: map[int32]unitProcess{10: &mockProcess{pid: 10}},
+	}
+	acc := &testutil.Accumulator{}
+	assert.EqualError(t, p.Gather(acc), "unable to find the processes of nginx.service: unit not loaded")
+	assert.False(t, acc.HasMeasurement(measurement))
+	acc.AssertContainsTaggedFields(t, lookupMeasurement, map[string]interface{}{
+		"pid_count":   0,
+		"running":     0,
+		"result_code": 1,
+	}, map[string]string{tagUnit: "nginx.service", "result": "lookup_error"})
+	assert.Empty(t, p.procs)
+}
+
+func writeCgroupProcs(t *testing.T, dir string, procs string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, cgroupProcsFile), []byte(procs), 0600))
+}
+
+func TestCgroupPIDs(t *testing.T) {
+	controlGroup := "/system.slice/nginx.service"
+	testCases := map[string]string{
+		"v2":     "",
+		"hybrid": "unified",
+		"v1":     "systemd",
+	}
+	for name, mount := range testCases {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			dir := filepath.Join(root, mount, controlGroup)
+			writeCgroupProcs(t, dir, "10\n11\n")
+			writeCgroupProcs(t, filepath.Join(dir, "worker"), "12\n\n")
+
+			pids, err := cgroupPIDs(root, controlGroup)
+			require.NoError(t, err)
+			sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+			assert.Equal(t, []int32{10, 11, 12}, pids)
+		})
+	}
+
+	_, err := cgroupPIDs(t.TempDir(), controlGroup)
+	assert.EqualError(t, err, "control group not found: /system.slice/nginx.service")
+
+	root := t.TempDir()
+	writeCgroupProcs(t, filepath.Join(root, controlGroup), "nginx\n")
+	_, err = cgroupPIDs(root, controlGroup)
+	assert.Error(t, err)
+}
+
+func TestDescendants(t *testing.T) {
+	parents := map[int32]int32{
+		0:  0,
+		4:  0,
+		10: 4,
+		11: 10,
+		12: 11,
+		13: 10,
+		20: 4,
+	}
+	pids := descendants(10, parents)
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+	assert.Equal(t, []int32{10, 11, 12, 13}, pids)
+	assert.Equal(t, []int32{30}, descendants(30, parents))
+}
@@ -0,0 +1,88 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package procstat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+)
+
+const (
+	defaultCgroupRoot = "/sys/fs/cgroup"
+	dbusTimeout       = 5 * time.Second
+
+	propertyControlGroup = "ControlGroup"
+	propertyMainPID      = "MainPID"
+)
+
+var errWinServiceNotSupported = errors.New("win_service is only supported on Windows")
+
+// systemdUnitPIDs asks systemd over D-Bus for the control group and the main
+// PID of the unit and returns the processes in the control group.
+func systemdUnitPIDs(unit string) (int32, []int32, error) {
+	unit = unitName(unit)
+	ctx, cancel := context.WithTimeout(context.Background(), dbusTimeout)
+	defer cancel()
+	conn, err := dbus.NewSystemConnectionContext(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer conn.Close()
+	properties, err := conn.GetUnitTypePropertiesContext(ctx, unit, unitType(unit))
+	if err != nil {
+		return 0, nil, err
+	}
+	controlGroup, _ := properties[propertyControlGroup].(string)
+	if controlGroup == "" {
+		return 0, nil, fmt.Errorf("%s is not active", unit)
+	}
+	mainPID, _ := properties[propertyMainPID].(uint32)
+	pids, err := cgroupPIDs(cgroupRoot(), controlGroup)
+	return int32(mainPID), pids, err
+}
+
+func winServicePIDs(string) (int32, []int32, error) {
+	return 0, nil, errWinServiceNotSupported
+}
+
+// unitTypes are the D-Bus interfaces of the unit types that have processes,
+// by the suffix of the unit.
+var unitTypes = map[string]string{
+	".mount":   "Mount",
+	".scope":   "Scope",
+	".service": "Service",
+	".slice":   "Slice",
+	".socket":  "Socket",
+	".swap":    "Swap",
+}
+
+// unitName adds the .service suffix if the unit does not have the suffix of
+// a unit type, like systemctl.
+func unitName(unit string) string {
+	if _, ok := unitTypes[filepath.Ext(unit)]; !ok {
+		return unit + ".service"
+	}
+	return unit
+}
+
+func unitType(unit string) string {
+	return unitTypes[filepath.Ext(unit)]
+}
+
+// cgroupRoot is the mount point of the cgroup file system, which is under
+// HOST_SYS when the agent runs in a container.
+func cgroupRoot() string {
+	if hostSys := os.Getenv("HOST_SYS"); hostSys != "" {
+		return filepath.Join(hostSys, "fs", "cgroup")
+	}
+	return defaultCgroupRoot
+}
@@ -0,0 +1,35 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package procstat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitName(t *testing.T) {
+	assert.Equal(t, "nginx.service", unitName("nginx"))
+	assert.Equal(t, "nginx.service", unitName("nginx.service"))
+	assert.Equal(t, "session-1.scope", unitName("session-1.scope"))
+	assert.Equal(t, "my.app.service", unitName("my.app"))
+
+	assert.Equal(t, "Service", unitType("nginx.service"))
+	assert.Equal(t, "Scope", unitType("session-1.scope"))
+	assert.Equal(t, "Slice", unitType("user.slice"))
+}
+
+func TestCgroupRoot(t *testing.T) {
+	t.Setenv("HOST_SYS", "")
+	assert.Equal(t, defaultCgroupRoot, cgroupRoot())
+	t.Setenv("HOST_SYS", "/rootfs/sys")
+	assert.Equal(t, "/rootfs/sys/fs/cgroup", cgroupRoot())
+}
+
+func TestWinServiceNotSupported(t *testing.T) {
+	_, _, err := winServicePIDs("nginx")
+	assert.ErrorIs(t, err, errWinServiceNotSupported)
+}
@@ -0,0 +1,21 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !linux && !windows
+
+package procstat
+
+import "errors"
+
+var (
+	errSystemdUnitNotSupported = errors.New("systemd_unit is only supported on Linux")
+	errWinServiceNotSupported  = errors.New("win_service is only supported on Windows")
+)
+
+func systemdUnitPIDs(string) (int32, []int32, error) {
+	return 0, nil, errSystemdUnitNotSupported
+}
+
+func winServicePIDs(string) (int32, []int32, error) {
+	return 0, nil, errWinServiceNotSupported
+}
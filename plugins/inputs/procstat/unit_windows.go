@@ -0,0 +1,70 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build windows
+
+package procstat
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+var errSystemdUnitNotSupported = errors.New("systemd_unit is only supported on Linux")
+
+func systemdUnitPIDs(string) (int32, []int32, error) {
+	return 0, nil, errSystemdUnitNotSupported
+}
+
+// winServicePIDs asks the service control manager for the process of the
+// service and returns it with the processes it started.
+func winServicePIDs(name string) (int32, []int32, error) {
+	manager, err := mgr.Connect()
+	if err != nil {
+		return 0, nil, err
+	}
+	defer manager.Disconnect()
+	service, err := manager.OpenService(name)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer service.Close()
+	status, err := service.Query()
+	if err != nil {
+		return 0, nil, err
+	}
+	if status.State != svc.Running || status.ProcessId == 0 {
+		return 0, nil, fmt.Errorf("%s is not running", name)
+	}
+	parents, err := parentPIDs()
+	if err != nil {
+		return 0, nil, err
+	}
+	mainPID := int32(status.ProcessId)
+	return mainPID, descendants(mainPID, parents), nil
+}
+
+// parentPIDs returns the parent PID of every process from a snapshot of the
+// processes.
+func parentPIDs() (map[int32]int32, error) {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer windows.CloseHandle(snapshot)
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	parents := map[int32]int32{}
+	for err = windows.Process32First(snapshot, &entry); err == nil; err = windows.Process32Next(snapshot, &entry) {
+		parents[int32(entry.ProcessID)] = int32(entry.ParentProcessID)
+	}
+	if !errors.Is(err, windows.ERROR_NO_MORE_FILES) {
+		return nil, err
+	}
+	return parents, nil
+}
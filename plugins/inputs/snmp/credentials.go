@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package snmp
+
+import (
+	"fmt"
+
+	"gopkg.in/ini.v1"
+)
+
+// defaultSection holds the credentials of the targets without a section of
+// their own.
+const defaultSection = "default"
+
+// credentials are the SNMP credentials of a target. The community is used by
+// v2c and the rest by v3.
+type credentials struct {
+	Community    string `ini:"community"`
+	ContextName  string `ini:"context_name"`
+	SecLevel     string `ini:"sec_level"`
+	SecName      string `ini:"sec_name"`
+	AuthProtocol string `ini:"auth_protocol"`
+	AuthPassword string `ini:"auth_password"`
+	PrivProtocol string `ini:"priv_protocol"`
+	PrivPassword string `ini:"priv_password"`
+}
+
+// credentialsFile maps the section names, which are the target addresses, to
+// their credentials, e.g.
+//
+//	[default]
+//	community = public
+//
+//	[udp://10.0.0.1:161]
+//	sec_name = monitor
+//	sec_level = authPriv
+//	auth_protocol = SHA
+//	auth_password = ...
+//	priv_protocol = AES
+//	priv_password = ...
+type credentialsFile map[string]credentials
+
+func loadCredentials(path string) (credentialsFile, error) {
+	if path == "" {
+		return credentialsFile{}, nil
+	}
+	file, err := ini.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the credentials file %s: %w", path, err)
+	}
+	result := credentialsFile{}
+	for _, section := range file.Sections() {
+		if section.Name() == ini.DefaultSection {
+			continue
+		}
+		var c credentials
+		if err = section.MapTo(&c); err != nil {
+			return nil, fmt.Errorf("unable to read the credentials of %s: %w", section.Name(), err)
+		}
+		result[section.Name()] = c
+	}
+	return result, nil
+}
+
+// lookup returns the credentials of the target address, or the default ones.
+func (f credentialsFile) lookup(address string) (credentials, bool) {
+	if c, ok := f[address]; ok {
+		return c, true
+	}
+	c, ok := f[defaultSection]
+	return c, ok
+}
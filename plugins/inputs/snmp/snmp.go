@@ -0,0 +1,178 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package snmp
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/influxdata/telegraf/plugins/inputs/snmp"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+const (
+	measurement = "snmp"
+
+	// AgentHostTag is the tag with the host of the polled target.
+	AgentHostTag = "agent_host"
+
+	// translatorNetsnmp looks up the MIB names with the net-snmp tools. The
+	// tools are not needed if the OIDs are numeric and the fields are named.
+	translatorNetsnmp = "netsnmp"
+
+	defaultVersion        = 2
+	defaultTimeout        = 5 * time.Second
+	defaultRetries        = 3
+	defaultMaxRepetitions = 10
+)
+
+// Target is a network device polled with its own credentials.
+type Target struct {
+	// Address is the [scheme://]host[:port] of the SNMP agent, e.g.
+	// udp://10.0.0.1:161. It is also the section of the credentials file.
+	Address string `toml:"address"`
+	// Version is either 2 for v2c or 3.
+	Version uint8 `toml:"version"`
+}
+
+// SNMP polls the fields and walks the tables of each target. The polling is
+// delegated to the telegraf SNMP input, with one instance per target so that
+// each has its own credentials.
+type SNMP struct {
+	Targets         []Target        `toml:"target"`
+	CredentialsFile string          `toml:"credentials_file"`
+	Timeout         config.Duration `toml:"timeout"`
+	Retries         int             `toml:"retries"`
+	MaxRepetitions  uint32          `toml:"max_repetitions"`
+	Fields          []snmp.Field    `toml:"field"`
+	Tables          []snmp.Table    `toml:"table"`
+	Log             telegraf.Logger `toml:"-"`
+
+	pollers []*snmp.Snmp
+}
+
+var _ telegraf.Initializer = (*SNMP)(nil)
+
+func (*SNMP) SampleConfig() string {
+	return sampleConfig
+}
+
+func (*SNMP) Description() string {
+	return "Polls the SNMP v2c and v3 agents of network devices"
+}
+
+func (s *SNMP) Init() error {
+	if len(s.Targets) == 0 {
+		return errors.New("no targets configured")
+	}
+	if len(s.Fields) == 0 && len(s.Tables) == 0 {
+		return errors.New("no fields or tables configured")
+	}
+	creds, err := loadCredentials(s.CredentialsFile)
+	if err != nil {
+		return err
+	}
+	s.pollers = make([]*snmp.Snmp, 0, len(s.Targets))
+	for _, target := range s.Targets {
+		poller, err := s.newPoller(target, creds)
+		if err != nil {
+			return fmt.Errorf("target %s: %w", target.Address, err)
+		}
+		s.pollers = append(s.pollers, poller)
+	}
+	return nil
+}
+
+func (s *SNMP) newPoller(target Target, creds credentialsFile) (*snmp.Snmp, error) {
+	if target.Address == "" {
+		return nil, errors.New("target address is required")
+	}
+	if target.Version == 0 {
+		target.Version = defaultVersion
+	}
+	c, ok := creds.lookup(target.Address)
+	switch target.Version {
+	case 2:
+		if !ok || c.Community == "" {
+			s.Log.Debugf("No community for target %s in the credentials file, using the default community", target.Address)
+		}
+	case 3:
+		if c.SecName == "" {
+			return nil, errors.New("no sec_name in the credentials file")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported version %d", target.Version)
+	}
+
+	poller := &snmp.Snmp{
+		Agents:       []string{target.Address},
+		AgentHostTag: AgentHostTag,
+		Name:         measurement,
+		Fields:       slices.Clone(s.Fields),
+		Tables:       cloneTables(s.Tables),
+		Log:          s.Log,
+	}
+	poller.Version = target.Version
+	poller.Timeout = s.Timeout
+	poller.Retries = s.Retries
+	poller.MaxRepetitions = s.MaxRepetitions
+	poller.Community = c.Community
+	poller.ContextName = c.ContextName
+	poller.SecLevel = c.SecLevel
+	poller.SecName = c.SecName
+	poller.AuthProtocol = c.AuthProtocol
+	poller.AuthPassword = c.AuthPassword
+	poller.PrivProtocol = c.PrivProtocol
+	poller.PrivPassword = c.PrivPassword
+	poller.SetTranslator(translatorNetsnmp)
+	if err := poller.Init(); err != nil {
+		return nil, err
+	}
+	return poller, nil
+}
+
+// Gather polls the targets concurrently. The errors of a target are added to
+// the accumulator without affecting the others.
+func (s *SNMP) Gather(acc telegraf.Accumulator) error {
+	var wg sync.WaitGroup
+	for _, poller := range s.pollers {
+		wg.Add(1)
+		go func(poller *snmp.Snmp) {
+			defer wg.Done()
+			_ = poller.Gather(acc)
+		}(poller)
+	}
+	wg.Wait()
+	return nil
+}
+
+// cloneTables copies the tables, since their fields are resolved in place
+// when each poller is initialized.
+func cloneTables(tables []snmp.Table) []snmp.Table {
+	result := make([]snmp.Table, len(tables))
+	for i, table := range tables {
+		table.Fields = slices.Clone(table.Fields)
+		result[i] = table
+	}
+	return result
+}
+
+func init() {
+	inputs.Add(measurement, func() telegraf.Input {
+		return &SNMP{
+			Timeout:        config.Duration(defaultTimeout),
+			Retries:        defaultRetries,
+			MaxRepetitions: defaultMaxRepetitions,
+		}
+	})
+}
@@ -0,0 +1,290 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package snmp
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAgent is a SNMP v2c agent answering the get and bulk requests with the
+// community it was created with.
+type fakeAgent struct {
+	conn      *net.UDPConn
+	community string
+	oids      []string
+	values    map[string]gosnmp.SnmpPDU
+}
+
+func newFakeAgent(t *testing.T, community string, pdus ...gosnmp.SnmpPDU) *fakeAgent {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	a := &fakeAgent{conn: conn, community: community, values: map[string]gosnmp.SnmpPDU{}}
+	for _, pdu := range pdus {
+		a.oids = append(a.oids, pdu.Name)
+		a.values[pdu.Name] = pdu
+	}
+	sort.Slice(a.oids, func(i, j int) bool { return compareOIDs(a.oids[i], a.oids[j]) < 0 })
+	t.Cleanup(func() { _ = conn.Close() })
+	go a.serve()
+	return a
+}
+
+func (a *fakeAgent) address() string {
+	return "udp://" + a.conn.LocalAddr().String()
+}
+
+func (a *fakeAgent) serve() {
+	buf := make([]byte, 65536)
+	decoder := &gosnmp.GoSNMP{Version: gosnmp.Version2c}
+	for {
+		n, addr, err := a.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		request, err := decoder.SnmpDecodePacket(buf[:n])
+		if err != nil || request.Community != a.community {
+			continue
+		}
+		response := &gosnmp.SnmpPacket{
+			Version:   gosnmp.Version2c,
+			Community: request.Community,
+			PDUType:   gosnmp.GetResponse,
+			RequestID: request.RequestID,
+		}
+		for _, variable := range request.Variables {
+			switch request.PDUType {
+			case gosnmp.GetRequest:
+				pdu, ok := a.values[strings.TrimPrefix(variable.Name, ".")]
+				if !ok {
+					pdu = gosnmp.SnmpPDU{Name: variable.Name, Type: gosnmp.NoSuchObject}
+				}
+				response.Variables = append(response.Variables, pdu)
+			case gosnmp.GetBulkRequest:
+				response.Variables = append(response.Variables, a.next(variable.Name, int(request.MaxRepetitions))...)
+			}
+		}
+		out, err := response.MarshalMsg()
+		if err != nil {
+			continue
+		}
+		_, _ = a.conn.WriteToUDP(out, addr)
+	}
+}
+
+// next returns the values after the OID.
+func (a *fakeAgent) next(oid string, count int) []gosnmp.SnmpPDU {
+	oid = strings.TrimPrefix(oid, ".")
+	// the decoder does not set the max repetitions of the request
+	if count == 0 {
+		count = len(a.oids)
+	}
+	var result []gosnmp.SnmpPDU
+	for _, name := range a.oids {
+		if compareOIDs(name, oid) > 0 && len(result) < count {
+			result = append(result, a.values[name])
+		}
+	}
+	if len(result) == 0 {
+		result = append(result, gosnmp.SnmpPDU{Name: oid, Type: gosnmp.EndOfMibView})
+	}
+	return result
+}
+
+func compareOIDs(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, _ := strconv.Atoi(as[i])
+		y, _ := strconv.Atoi(bs[i])
+		if x != y {
+			return x - y
+		}
+	}
+	return len(as) - len(bs)
+}
+
+func loadPlugin(t *testing.T, toml string) *SNMP {
+	c := config.NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(toml)))
+	require.Len(t, c.Inputs, 1)
+	s, ok := c.Inputs[0].Input.(*SNMP)
+	require.True(t, ok)
+	s.Log = testutil.Logger{}
+	return s
+}
+
+func writeCredentials(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "snmp_credentials")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestGather(t *testing.T) {
+	agent := newFakeAgent(t, "s3cret",
+		gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(12345)},
+		gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.2.2.1.2.1", Type: gosnmp.OctetString, Value: []byte("eth0")},
+		gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.2.2.1.2.2", Type: gosnmp.OctetString, Value: []byte("eth1")},
+		gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.2.2.1.10.1", Type: gosnmp.Counter32, Value: uint(100)},
+		gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.2.2.1.10.2", Type: gosnmp.Counter32, Value: uint(200)},
+		gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.4.1.0", Type: gosnmp.Integer, Value: 1},
+	)
+	// the other target has no community and is not answered
+	other := newFakeAgent(t, "other")
+	credentialsFile := writeCredentials(t, "[default]\ncommunity = public\n\n["+agent.address()+"]\ncommunity = s3cret\n")
+
+	s := loadPlugin(t, `
+[[inputs.snmp]]
+  credentials_file = "`+credentialsFile+`"
+  timeout = "500ms"
+  retries = 0
+
+  [[inputs.snmp.target]]
+    address = "`+agent.address()+`"
+
+  [[inputs.snmp.target]]
+    address = "`+other.address()+`"
+    version = 2
+
+  [[inputs.snmp.field]]
+    name = "uptime"
+    oid = "1.3.6.1.2.1.1.3.0"
+
+  [[inputs.snmp.table]]
+    name = "interface"
+    index_as_tag = true
+
+    [[inputs.snmp.table.field]]
+      name = "ifDescr"
+      oid = "1.3.6.1.2.1.2.2.1.2"
+      is_tag = true
+
+    [[inputs.snmp.table.field]]
+      name = "ifInOctets"
+      oid = ".1.3.6.1.2.1.2.2.1.10"
+`)
+	require.NoError(t, s.Init())
+	require.Len(t, s.pollers, 2)
+
+	var acc testutil.Accumulator
+	require.NoError(t, s.Gather(&acc))
+
+	host, _, err := net.SplitHostPort(agent.conn.LocalAddr().String())
+	require.NoError(t, err)
+	uptime, ok := acc.Get(measurement)
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"uptime": uint32(12345)}, uptime.Fields)
+	assert.Equal(t, host, uptime.Tags[AgentHostTag])
+
+	var interfaces []map[string]string
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() != "interface" {
+			continue
+		}
+		interfaces = append(interfaces, m.Tags())
+		assert.Contains(t, m.Fields(), "ifInOctets")
+	}
+	assert.ElementsMatch(t, []map[string]string{
+		{AgentHostTag: host, "index": "1", "ifDescr": "eth0"},
+		{AgentHostTag: host, "index": "2", "ifDescr": "eth1"},
+	}, interfaces)
+	// the target that does not answer is reported without affecting the other
+	assert.NotEmpty(t, acc.Errors)
+}
+
+func TestInit(t *testing.T) {
+	credentialsFile := writeCredentials(t, "[udp://10.0.0.1:161]\nsec_name = monitor\nsec_level = authPriv\nauth_protocol = SHA\nauth_password = authpass\npriv_protocol = AES\npriv_password = privpass\n")
+	field := `
+  [[inputs.snmp.field]]
+    name = "uptime"
+    oid = "1.3.6.1.2.1.1.3.0"
+`
+	testCases := map[string]struct {
+		toml    string
+		wantErr string
+	}{
+		"WithV3": {
+			toml: `
+[[inputs.snmp]]
+  credentials_file = "` + credentialsFile + `"
+  [[inputs.snmp.target]]
+    address = "udp://10.0.0.1:161"
+    version = 3
+` + field,
+		},
+		"WithV3WithoutCredentials": {
+			toml: `
+[[inputs.snmp]]
+  credentials_file = "` + credentialsFile + `"
+  [[inputs.snmp.target]]
+    address = "udp://10.0.0.2:161"
+    version = 3
+` + field,
+			wantErr: "target udp://10.0.0.2:161: no sec_name in the credentials file",
+		},
+		"WithUnsupportedVersion": {
+			toml: `
+[[inputs.snmp]]
+  [[inputs.snmp.target]]
+    address = "udp://10.0.0.1:161"
+    version = 1
+` + field,
+			wantErr: "target udp://10.0.0.1:161: unsupported version 1",
+		},
+		"WithMissingCredentialsFile": {
+			toml: `
+[[inputs.snmp]]
+  credentials_file = "/does/not/exist"
+  [[inputs.snmp.target]]
+    address = "udp://10.0.0.1:161"
+` + field,
+			wantErr: "unable to load the credentials file",
+		},
+		"WithoutTargets": {
+			toml:    "[[inputs.snmp]]\n" + field,
+			wantErr: "no targets configured",
+		},
+		"WithoutFields": {
+			toml: `
+[[inputs.snmp]]
+  [[inputs.snmp.target]]
+    address = "udp://10.0.0.1:161"
+`,
+			wantErr: "no fields or tables configured",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s := loadPlugin(t, testCase.toml)
+			err := s.Init()
+			if testCase.wantErr != "" {
+				assert.ErrorContains(t, err, testCase.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, s.pollers, 1)
+			assert.Equal(t, "monitor", s.pollers[0].SecName)
+			assert.Equal(t, "privpass", s.pollers[0].PrivPassword)
+			assert.EqualValues(t, 3, s.pollers[0].Version)
+		})
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	s := loadPlugin(t, "[[inputs.snmp]]\n")
+	assert.Equal(t, config.Duration(5*time.Second), s.Timeout)
+	assert.Equal(t, 3, s.Retries)
+	assert.EqualValues(t, 10, s.MaxRepetitions)
+}
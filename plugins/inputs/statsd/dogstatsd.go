@@ -0,0 +1,182 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// dogStatsDEventPrefix starts the DogStatsD events, e.g.
+	// _e{5,4}:title|text|d:1700000000|h:host|p:low|t:warning|#env:prod
+	dogStatsDEventPrefix = "_e{"
+	// dogStatsDServiceCheckPrefix starts the DogStatsD service checks, e.g.
+	// _sc|name|status|d:1700000000|h:host|#env:prod|m:message
+	dogStatsDServiceCheckPrefix = "_sc|"
+
+	// eventQueueSize is the number of events waiting for the statsd_events
+	// log collection, once filled the events are dropped.
+	eventQueueSize = 1000
+)
+
+var eventDropWarn = "E! Error: statsd event queue full. " +
+	"We have dropped %d events so far. " +
+	"The events are only published if the logs section is configured\n"
+
+// eventQueue passes the DogStatsD events from the statsd listener to the
+// statsd_events log collection. They are separate plugin instances, since
+// the metrics and the logs are collected by different agents.
+var (
+	eventQueue = make(chan *dogStatsDEvent, eventQueueSize)
+	eventDrops atomic.Int64
+)
+
+// dogStatsDEvent is published to CloudWatch Logs as a JSON object.
+type dogStatsDEvent struct {
+	Title          string    `json:"title"`
+	Text           string    `json:"text"`
+	Timestamp      time.Time `json:"-"`
+	Hostname       string    `json:"hostname,omitempty"`
+	AggregationKey string    `json:"aggregation_key,omitempty"`
+	Priority       string    `json:"priority,omitempty"`
+	SourceTypeName string    `json:"source_type_name,omitempty"`
+	AlertType      string    `json:"alert_type,omitempty"`
+	Tags           []string  `json:"tags,omitempty"`
+}
+
+func (e *dogStatsDEvent) message() string {
+	message, err := json.Marshal(e)
+	if err != nil {
+		return e.Title
+	}
+	return string(message)
+}
+
+// parseDogStatsDEvent parses the event, the lengths of the title and the text
+// are in bytes.
+func parseDogStatsDEvent(line string) (*dogStatsDEvent, error) {
+	header, rest, ok := strings.Cut(strings.TrimPrefix(line, dogStatsDEventPrefix), "}:")
+	if !ok {
+		return nil, errors.New("missing the lengths of the title and text")
+	}
+	titleLength, textLength, ok := strings.Cut(header, ",")
+	if !ok {
+		return nil, errors.New("missing the length of the text")
+	}
+	titleLen, err := strconv.Atoi(titleLength)
+	if err != nil || titleLen <= 0 {
+		return nil, fmt.Errorf("invalid title length %q", titleLength)
+	}
+	textLen, err := strconv.Atoi(textLength)
+	if err != nil || textLen < 0 {
+		return nil, fmt.Errorf("invalid text length %q", textLength)
+	}
+	if len(rest) < titleLen+1+textLen || rest[titleLen] != '|' {
+		return nil, errors.New("the title and text do not match their lengths")
+	}
+	e := &dogStatsDEvent{
+		Title:     rest[:titleLen],
+		Text:      strings.ReplaceAll(rest[titleLen+1:titleLen+1+textLen], `\n`, "\n"),
+		Timestamp: time.Now(),
+	}
+	metadata := rest[titleLen+1+textLen:]
+	if metadata == "" {
+		return e, nil
+	}
+	if metadata[0] != '|' {
+		return nil, errors.New("the title and text do not match their lengths")
+	}
+	for _, field := range strings.Split(metadata[1:], "|") {
+		switch {
+		case strings.HasPrefix(field, "d:"):
+			timestamp, err := strconv.ParseInt(field[2:], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timestamp %q", field[2:])
+			}
+			e.Timestamp = time.Unix(timestamp, 0)
+		case strings.HasPrefix(field, "h:"):
+			e.Hostname = field[2:]
+		case strings.HasPrefix(field, "k:"):
+			e.AggregationKey = field[2:]
+		case strings.HasPrefix(field, "p:"):
+			e.Priority = field[2:]
+		case strings.HasPrefix(field, "s:"):
+			e.SourceTypeName = field[2:]
+		case strings.HasPrefix(field, "t:"):
+			e.AlertType = field[2:]
+		case strings.HasPrefix(field, "#"):
+			e.Tags = strings.Split(field[1:], ",")
+		}
+	}
+	return e, nil
+}
+
+// dogStatsDServiceCheck is reported as a gauge of the status, 0 for OK, 1 for
+// WARNING, 2 for CRITICAL and 3 for UNKNOWN.
+type dogStatsDServiceCheck struct {
+	name   string
+	status int
+	tags   map[string]string
+}
+
+func parseDogStatsDServiceCheck(line string) (*dogStatsDServiceCheck, error) {
+	// the message is the last field and can contain pipes
+	line, _, _ = strings.Cut(line, "|m:")
+	fields := strings.Split(strings.TrimPrefix(line, dogStatsDServiceCheckPrefix), "|")
+	if len(fields) < 2 || fields[0] == "" {
+		return nil, errors.New("missing the name or status")
+	}
+	status, err := strconv.Atoi(fields[1])
+	if err != nil || status < 0 || status > 3 {
+		return nil, fmt.Errorf("invalid status %q", fields[1])
+	}
+	check := &dogStatsDServiceCheck{name: fields[0], status: status, tags: map[string]string{}}
+	for _, field := range fields[2:] {
+		if strings.HasPrefix(field, "#") {
+			parseDataDogTags(field[1:], check.tags)
+		}
+	}
+	return check, nil
+}
+
+// parseDataDogTags adds the comma separated tags to the map. The tags without
+// a value are added with a placeholder value.
+func parseDataDogTags(tagstr string, tags map[string]string) {
+	for _, tag := range strings.Split(tagstr, ",") {
+		ts := strings.SplitN(tag, ":", 2)
+		var k, v string
+		switch len(ts) {
+		case 1:
+			// just a tag
+			k = ts[0]
+			v = "<empty>" //cloudwatch does not allow empty string
+		case 2:
+			k = ts[0]
+			v = ts[1]
+		}
+		if k != "" {
+			tags[k] = v
+		}
+	}
+}
+
+// publishEvent queues the event for the statsd_events log collection without
+// blocking the listener.
+func publishEvent(e *dogStatsDEvent) {
+	select {
+	case eventQueue <- e:
+	default:
+		drops := eventDrops.Add(1)
+		if drops == 1 || drops%eventQueueSize == 0 {
+			log.Printf(eventDropWarn, drops)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+)
+
+// drainEvents empties the queue shared by the tests.
+func drainEvents() {
+	for {
+		select {
+		case <-eventQueue:
+		default:
+			return
+		}
+	}
+}
+
+func TestParse_Distributions(t *testing.T) {
+	s := NewTestStatsd()
+	s.ParseDataDogTags = true
+	acc := &testutil.Accumulator{}
+
+	for _, line := range []string{
+		"request.latency:1|d|#env:prod",
+		"request.latency:11|d|@0.5|#env:prod",
+	} {
+		assert.NoError(t, s.parseStatsdLine(line))
+	}
+	assert.Error(t, s.parseStatsdLine("request.latency:+1|d"))
+	s.Gather(acc)
+
+	dist := distribution.NewDistribution()
+	assert.NoError(t, dist.AddEntry(1, 1))
+	assert.NoError(t, dist.AddEntry(11, 2))
+	require.Len(t, acc.Metrics, 1)
+	metric := acc.Metrics[0]
+	assert.Equal(t, "request_latency", metric.Measurement)
+	assert.Equal(t, map[string]string{"metric_type": "distribution", "env": "prod"}, metric.Tags)
+	assert.Equal(t, dist, metric.Fields[defaultFieldName])
+}
+
+func TestParse_ServiceChecks(t *testing.T) {
+	s := NewTestStatsd()
+	s.DataDogServiceChecks = true
+	acc := &testutil.Accumulator{}
+
+	assert.NoError(t, s.parseStatsdLine("_sc|app.can_connect|0|d:1700000000|h:web-1|#env:prod,primary|m:connected"))
+	assert.NoError(t, s.parseStatsdLine("_sc|app.can_connect|2|#env:prod,primary|m:timeout | retrying"))
+	assert.Error(t, s.parseStatsdLine("_sc|app.can_connect|5"))
+	assert.Error(t, s.parseStatsdLine("_sc|app.can_connect"))
+	s.Gather(acc)
+
+	require.Len(t, acc.Metrics, 1)
+	metric := acc.Metrics[0]
+	assert.Equal(t, "app_can_connect", metric.Measurement)
+	assert.Equal(t, map[string]string{"metric_type": "service_check", "env": "prod", "primary": "<empty>"}, metric.Tags)
+	assert.Equal(t, map[string]interface{}{defaultFieldName: float64(2)}, metric.Fields)
+}
+
+func TestParse_DataDogExtensionsDisabled(t *testing.T) {
+	drainEvents()
+	s := NewTestStatsd()
+	acc := &testutil.Accumulator{}
+
+	assert.NoError(t, s.parseStatsdLine("_sc|app.can_connect|0"))
+	assert.NoError(t, s.parseStatsdLine("_e{5,4}:title|text"))
+	s.Gather(acc)
+	assert.Empty(t, acc.Metrics)
+	assert.Empty(t, eventQueue)
+}
+
+func TestParse_Events(t *testing.T) {
+	drainEvents()
+	defer drainEvents()
+	s := NewTestStatsd()
+	s.DataDogEvents = true
+
+	assert.NoError(t, s.parseStatsdLine(`_e{10,21}:Deployment|version 2\nrolled out|d:1700000000|h:web-1|p:low|t:success|#env:prod,canary`))
+	assert.Error(t, s.parseStatsdLine("_e{10,4}:title|text"))
+	require.Len(t, eventQueue, 1)
+	e := <-eventQueue
+	assert.Equal(t, time.Unix(1700000000, 0), e.Timestamp)
+	assert.JSONEq(t, `{
+		"title": "Deployment",
+		"text": "version 2\nrolled out",
+		"hostname": "web-1",
+		"priority": "low",
+		"alert_type": "success",
+		"tags": ["env:prod", "canary"]
+	}`, e.message())
+}
+
+func TestParseDogStatsDEvent(t *testing.T) {
+	testCases := map[string]struct {
+		line    string
+		want    *dogStatsDEvent
+		wantErr string
+	}{
+		"WithoutMetadata": {
+			line: "_e{5,0}:title|",
+			want: &dogStatsDEvent{Title: "title"},
+		},
+		"WithPipeInText": {
+			line: "_e{5,5}:title|a|b|c|k:deploy|s:jenkins",
+			want: &dogStatsDEvent{Title: "title", Text: "a|b|c", AggregationKey: "deploy", SourceTypeName: "jenkins"},
+		},
+		"WithMultibyteTitle": {
+			line: "_e{6,4}:héllo|text",
+			want: &dogStatsDEvent{Title: "héllo", Text: "text"},
+		},
+		"WithoutLengths": {
+			line:    "_e{5}:title|text",
+			wantErr: "missing the length of the text",
+		},
+		"WithInvalidLength": {
+			line:    "_e{x,4}:title|text",
+			wantErr: `invalid title length "x"`,
+		},
+		"WithShortText": {
+			line:    "_e{5,2}:title|text",
+			wantErr: "the title and text do not match their lengths",
+		},
+		"WithInvalidTimestamp": {
+			line:    "_e{5,4}:title|text|d:yesterday",
+			wantErr: `invalid timestamp "yesterday"`,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseDogStatsDEvent(testCase.line)
+			if testCase.wantErr != "" {
+				assert.EqualError(t, err, testCase.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			got.Timestamp = time.Time{}
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
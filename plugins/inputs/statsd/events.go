@@ -0,0 +1,163 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
+)
+
+// Events is the log collection of the dogstatsd events received by the statsd
+// plugin with dogstatsd_events enabled.
+type Events struct {
+	LogGroupName    string `toml:"log_group_name"`
+	LogStreamName   string `toml:"log_stream_name"`
+	LogGroupClass   string `toml:"log_group_class"`
+	RetentionInDays int    `toml:"retention_in_days"`
+	Destination     string `toml:"destination"`
+
+	src     *eventSrc
+	srcOnce sync.Once
+}
+
+var _ logs.LogCollection = (*Events)(nil)
+
+const eventsSampleConfig = `
+  ## The log group and stream the dogstatsd events are published to
+  log_group_name = "statsd-events"
+  log_stream_name = "{instance_id}"
+  destination = "cloudwatchlogs"
+`
+
+func (*Events) Description() string {
+	return "Publishes the dogstatsd events received by the statsd plugin"
+}
+
+func (*Events) SampleConfig() string {
+	return eventsSampleConfig
+}
+
+func (*Events) Gather(telegraf.Accumulator) error {
+	return nil
+}
+
+func (*Events) Start(telegraf.Accumulator) error {
+	return nil
+}
+
+func (*Events) Stop() {}
+
+// FindLogSrc returns the source of the events the first time it is called.
+func (e *Events) FindLogSrc() []logs.LogSrc {
+	var srcs []logs.LogSrc
+	e.srcOnce.Do(func() {
+		e.src = newEventSrc(e.LogGroupName, e.LogStreamName, e.Destination, e.LogGroupClass, e.RetentionInDays)
+		srcs = append(srcs, e.src)
+	})
+	return srcs
+}
+
+type eventLog struct {
+	message   string
+	timestamp time.Time
+}
+
+func (e *eventLog) Message() string {
+	return e.message
+}
+
+func (e *eventLog) Time() time.Time {
+	return e.timestamp
+}
+
+func (e *eventLog) Done() {}
+
+// eventSrc publishes the events of the queue shared with the statsd plugin.
+type eventSrc struct {
+	group         string
+	stream        string
+	destination   string
+	logGroupClass string
+	retention     int
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	done      chan struct{}
+}
+
+var _ logs.LogSrc = (*eventSrc)(nil)
+
+func newEventSrc(group, stream, destination, logGroupClass string, retention int) *eventSrc {
+	return &eventSrc{
+		group:         group,
+		stream:        stream,
+		destination:   destination,
+		logGroupClass: logGroupClass,
+		retention:     retention,
+		done:          make(chan struct{}),
+	}
+}
+
+func (s *eventSrc) SetOutput(fn func(logs.LogEvent)) {
+	if fn == nil {
+		return
+	}
+	s.startOnce.Do(func() { go s.run(fn) })
+}
+
+func (s *eventSrc) Group() string {
+	return s.group
+}
+
+func (s *eventSrc) Stream() string {
+	return s.stream
+}
+
+func (s *eventSrc) Destination() string {
+	return s.destination
+}
+
+func (s *eventSrc) Description() string {
+	return "statsd_events"
+}
+
+func (s *eventSrc) Retention() int {
+	return s.retention
+}
+
+func (s *eventSrc) Class() string {
+	return s.logGroupClass
+}
+
+func (s *eventSrc) Stop() {
+	s.stopOnce.Do(func() { close(s.done) })
+}
+
+func (s *eventSrc) Entity() *cloudwatchlogs.Entity {
+	return nil
+}
+
+func (s *eventSrc) run(output func(logs.LogEvent)) {
+	for {
+		select {
+		case e := <-eventQueue:
+			output(&eventLog{message: e.message(), timestamp: e.Timestamp})
+		case <-s.done:
+			output(nil)
+			return
+		}
+	}
+}
+
+func init() {
+	inputs.Add("statsd_events", func() telegraf.Input {
+		return &Events{Destination: "cloudwatchlogs"}
+	})
+}
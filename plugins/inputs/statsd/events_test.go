@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+func TestEventsFindLogSrc(t *testing.T) {
+	drainEvents()
+	defer drainEvents()
+	e := &Events{
+		LogGroupName:    "statsd-events",
+		LogStreamName:   "i-1234567890",
+		RetentionInDays: 7,
+		Destination:     "cloudwatchlogs",
+	}
+	srcs := e.FindLogSrc()
+	require.Len(t, srcs, 1)
+	assert.Empty(t, e.FindLogSrc())
+	src := srcs[0]
+	assert.Equal(t, "statsd-events", src.Group())
+	assert.Equal(t, "i-1234567890", src.Stream())
+	assert.Equal(t, "cloudwatchlogs", src.Destination())
+	assert.Equal(t, 7, src.Retention())
+
+	published := make(chan logs.LogEvent, 2)
+	src.SetOutput(func(e logs.LogEvent) { published <- e })
+	publishEvent(&dogStatsDEvent{Title: "Deployment", Timestamp: time.Unix(1700000000, 0)})
+
+	select {
+	case got := <-published:
+		assert.Equal(t, `{"title":"Deployment","text":""}`, got.Message())
+		assert.Equal(t, time.Unix(1700000000, 0), got.Time())
+	case <-time.After(time.Second):
+		assert.Fail(t, "event not published")
+	}
+	src.Stop()
+	select {
+	case got := <-published:
+		assert.Nil(t, got)
+	case <-time.After(time.Second):
+		assert.Fail(t, "source not stopped")
+	}
+}
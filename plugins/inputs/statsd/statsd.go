@@ -16,8 +16,10 @@ import (
 
 	//"github.com/influxdata/telegraf/plugins/parsers/graphite"
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/plugins/inputs"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/handover"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/statsd/graphite"
 )
@@ -55,6 +57,12 @@ type Statsd struct {
 	// This flag enables parsing of tags in the dogstatsd extension to the
 	// statsd protocol (http://docs.datadoghq.com/guides/dogstatsd/)
 	ParseDataDogTags bool
+	// DataDogEvents publishes the dogstatsd events to CloudWatch Logs through
+	// the statsd_events plugin. They are dropped if not set.
+	DataDogEvents bool `toml:"dogstatsd_events"`
+	// DataDogServiceChecks reports the dogstatsd service checks as a gauge of
+	// their status. They are dropped if not set.
+	DataDogServiceChecks bool `toml:"dogstatsd_service_checks"`
 
 	// UDPPacketSize is deprecated, it's only here for legacy support
 	// we now always create 1 max size buffer and then copy only what we need
@@ -62,15 +70,43 @@ type Statsd struct {
 	// see https://github.com/influxdata/telegraf/pull/992
 	int `toml:"udp_packet_size"`
 
+	// EphemeralTag is the tag marking the counters sent by short-lived sources,
+	// such as cron jobs, that can exit before the next Gather. The tag is
+	// removed from the metric.
+	EphemeralTag string `toml:"ephemeral_tag"`
+	// EphemeralSourcePorts treats the packets sent from a source port that was
+	// not seen during the previous interval as coming from a short-lived source.
+	// Long-lived clients reuse their socket, so their source port stays the same.
+	EphemeralSourcePorts bool `toml:"ephemeral_source_ports"`
+	// EphemeralJobIDTag is the tag identifying the job of a short-lived source.
+	// The counters of all the processes of a job are aggregated together. The
+	// source address is used if the tag is not set.
+	EphemeralJobIDTag string `toml:"ephemeral_job_id_tag"`
+	// EphemeralGraceWindow is how long the counters of a short-lived source are
+	// aggregated after its last packet before being flushed. They are flushed
+	// immediately if zero.
+	EphemeralGraceWindow config.Duration `toml:"ephemeral_grace_window"`
+
 	sync.Mutex
 	wg sync.WaitGroup
 	// drops tracks the number of dropped metrics.
 	drops int
 
 	// Channel for all incoming statsd packets
-	in   chan []byte
+	in   chan packet
 	done chan struct{}
 
+	// acc is used to flush the counters of short-lived sources without waiting
+	// for the next Gather.
+	acc telegraf.Accumulator
+	// ephemeralJobs maps the job ID or source address to the counters of the
+	// short-lived sources waiting for the grace window to elapse.
+	ephemeralJobs map[string]*ephemeralJob
+	// previousSources and currentSources track the source addresses seen during
+	// the previous and current intervals for the source port heuristic.
+	previousSources map[string]struct{}
+	currentSources  map[string]struct{}
+
 	// Cache gauges, counters & sets so they can be aggregated as they arrive
 	// gauges and counters map measurement/tags hash -> field name -> metrics
 	// sets and timings map measurement/tags hash -> metrics
@@ -87,6 +123,19 @@ type Statsd struct {
 	graphiteParser *graphite.GraphiteParser
 }
 
+// packet is a UDP packet and the address it was sent from.
+type packet struct {
+	data   []byte
+	source string
+}
+
+// ephemeralJob aggregates the counters of short-lived sources until no
+// packets have been received for the grace window.
+type ephemeralJob struct {
+	counters map[string]cachedcounter
+	lastSeen time.Time
+}
+
 // One statsd metric, form is <bucket>:<value>|<mtype>|@<samplerate>
 type metric struct {
 	name       string
@@ -152,6 +201,11 @@ const sampleConfig = `
   ## Parses tags in the datadog statsd format
   ## http://docs.datadoghq.com/guides/dogstatsd/
   parse_data_dog_tags = false
+  ## Publishes the dogstatsd events to CloudWatch Logs through the
+  ## statsd_events plugin
+  # dogstatsd_events = false
+  ## Reports the dogstatsd service checks as a gauge of their status
+  # dogstatsd_service_checks = false
 
   ## Statsd data translation templates, more info can be read here:
   ## https://github.com/influxdata/telegraf/blob/master/docs/DATA_FORMATS_INPUT.md#graphite
@@ -166,6 +220,18 @@ const sampleConfig = `
   ## The aggregation interval for the metrics
   metric_aggregation_interval = "60s"
 
+  ## Counters sent by short-lived sources, such as cron jobs that exit before
+  ## the next interval, are flushed without waiting for the next interval.
+  ## Tag marking the counters of short-lived sources, removed from the metric
+  # ephemeral_tag = "ephemeral"
+  ## Treat source ports not seen during the previous interval as short-lived
+  # ephemeral_source_ports = false
+  ## Tag identifying the job of a short-lived source, the counters of all the
+  ## processes of a job are aggregated together
+  # ephemeral_job_id_tag = "job_id"
+  ## How long to aggregate the counters of a job after its last packet
+  # ephemeral_grace_window = "0s"
+
 `
 
 func (_ *Statsd) SampleConfig() string {
@@ -209,18 +275,25 @@ func (s *Statsd) Gather(acc telegraf.Accumulator) error {
 		s.sets = make(map[string]cachedset)
 	}
 
+	s.previousSources = s.currentSources
+	s.currentSources = make(map[string]struct{})
+
 	return nil
 }
 
-func (s *Statsd) Start(_ telegraf.Accumulator) error {
+func (s *Statsd) Start(acc telegraf.Accumulator) error {
 	// Make data structures
 	s.done = make(chan struct{})
-	s.in = make(chan []byte, s.AllowedPendingMessages)
+	s.in = make(chan packet, s.AllowedPendingMessages)
+	s.acc = acc
 
 	s.gauges = make(map[string]cachedgauge)
 	s.counters = make(map[string]cachedcounter)
 	s.sets = make(map[string]cachedset)
 	s.timings = make(map[string]cachedtimings)
+	s.ephemeralJobs = make(map[string]*ephemeralJob)
+	s.previousSources = make(map[string]struct{})
+	s.currentSources = make(map[string]struct{})
 
 	if s.MetricSeparator == "" {
 		s.MetricSeparator = defaultSeparator
@@ -231,6 +304,10 @@ func (s *Statsd) Start(_ telegraf.Accumulator) error {
 	go s.udpListen()
 	// Start the line parser
 	go s.parser()
+	if s.EphemeralGraceWindow > 0 {
+		s.wg.Add(1)
+		go s.ephemeralFlusher()
+	}
 	log.Printf("I! Started the statsd service on %s\n", s.ServiceAddress)
 	return nil
 }
@@ -238,13 +315,15 @@ func (s *Statsd) Start(_ telegraf.Accumulator) error {
 // udpListen starts listening for udp packets on the configured port.
 func (s *Statsd) udpListen() error {
 	defer s.wg.Done()
-	var err error
-	address, _ := net.ResolveUDPAddr("udp", s.ServiceAddress)
-	s.listener, err = net.ListenUDP("udp", address)
+	// the socket is kept open across reloads and handed over to the next agent process on upgrade
+	listener, err := handover.ListenUDP("udp", s.ServiceAddress)
 	if err != nil {
 		log.Fatalf("ERROR: ListenUDP - %s", err)
 	}
-	log.Println("I! Statsd listener listening on: ", s.listener.LocalAddr().String())
+	s.Lock()
+	s.listener = listener
+	s.Unlock()
+	log.Println("I! Statsd listener listening on: ", listener.LocalAddr().String())
 
 	buf := make([]byte, UDP_MAX_PACKET_SIZE)
 	for {
@@ -252,16 +331,20 @@ func (s *Statsd) udpListen() error {
 		case <-s.done:
 			return nil
 		default:
-			n, _, err := s.listener.ReadFromUDP(buf)
+			n, addr, err := listener.ReadFromUDP(buf)
 			if err != nil && !strings.Contains(err.Error(), "closed network") {
 				log.Printf("E! Error READ: %s\n", err.Error())
 				continue
 			}
 			bufCopy := make([]byte, n)
 			copy(bufCopy, buf[:n])
+			var source string
+			if addr != nil {
+				source = addr.String()
+			}
 
 			select {
-			case s.in <- bufCopy:
+			case s.in <- packet{data: bufCopy, source: source}:
 			default:
 				s.drops++
 				if s.drops == 1 || s.AllowedPendingMessages == 0 || s.drops%s.AllowedPendingMessages == 0 {
@@ -277,26 +360,51 @@ func (s *Statsd) udpListen() error {
 // single statsd metric into a struct.
 func (s *Statsd) parser() error {
 	defer s.wg.Done()
-	var packet []byte
 	for {
 		select {
 		case <-s.done:
 			return nil
-		case packet = <-s.in:
-			lines := strings.Split(string(packet), "\n")
+		case p := <-s.in:
+			ephemeral := s.isEphemeralSource(p.source)
+			lines := strings.Split(string(p.data), "\n")
 			for _, line := range lines {
 				line = strings.TrimSpace(line)
 				if line != "" {
-					s.parseStatsdLine(line)
+					s.parseLine(line, p.source, ephemeral)
 				}
 			}
 		}
 	}
 }
 
+// isEphemeralSource records the source address and returns true if the source
+// port heuristic considers it short-lived.
+func (s *Statsd) isEphemeralSource(source string) bool {
+	if !s.EphemeralSourcePorts || source == "" {
+		return false
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.currentSources[source] = struct{}{}
+	_, ok := s.previousSources[source]
+	return !ok
+}
+
 // parseStatsdLine will parse the given statsd line, validating it as it goes.
 // If the line is valid, it will be cached for the next call to Gather()
 func (s *Statsd) parseStatsdLine(line string) error {
+	return s.parseLine(line, "", false)
+}
+
+// parseLine parses the statsd line sent from the source. The counters of
+// short-lived sources are flushed separately, see aggregateEphemeral.
+func (s *Statsd) parseLine(line string, source string, ephemeralSource bool) error {
+	if strings.HasPrefix(line, dogStatsDEventPrefix) {
+		return s.parseEvent(line)
+	}
+	if strings.HasPrefix(line, dogStatsDServiceCheckPrefix) {
+		return s.parseServiceCheck(line)
+	}
 
 	lineTags := make(map[string]string)
 	if s.ParseDataDogTags {
@@ -310,24 +418,7 @@ func (s *Statsd) parseStatsdLine(line string) error {
 		for _, segment := range pipesplit {
 			if len(segment) > 0 && segment[0] == '#' {
 				// we have ourselves a tag; they are comma separated
-				tagstr := segment[1:]
-				tags := strings.Split(tagstr, ",")
-				for _, tag := range tags {
-					ts := strings.SplitN(tag, ":", 2)
-					var k, v string
-					switch len(ts) {
-					case 1:
-						// just a tag
-						k = ts[0]
-						v = "<empty>" //cloudwatch does not allow empty string
-					case 2:
-						k = ts[0]
-						v = ts[1]
-					}
-					if k != "" {
-						lineTags[k] = v
-					}
-				}
+				parseDataDogTags(segment[1:], lineTags)
 			} else {
 				recombinedSegments = append(recombinedSegments, segment)
 			}
@@ -375,7 +466,7 @@ func (s *Statsd) parseStatsdLine(line string) error {
 
 		// Validate metric type
 		switch pipesplit[1] {
-		case "g", "c", "s", "ms", "h":
+		case "g", "c", "s", "ms", "h", "d":
 			m.mtype = pipesplit[1]
 		default:
 			log.Printf("E! Error: Statsd Metric type %s unsupported", pipesplit[1])
@@ -392,7 +483,7 @@ func (s *Statsd) parseStatsdLine(line string) error {
 		}
 
 		switch m.mtype {
-		case "g", "ms", "h":
+		case "g", "ms", "h", "d":
 			v, err := strconv.ParseFloat(pipesplit[0], 64)
 			if err != nil {
 				log.Printf("E! Error: parsing value to float64: %s\n", line)
@@ -432,6 +523,8 @@ func (s *Statsd) parseStatsdLine(line string) error {
 			m.tags["metric_type"] = "timing"
 		case "h":
 			m.tags["metric_type"] = "histogram"
+		case "d":
+			m.tags["metric_type"] = "distribution"
 		}
 
 		if len(lineTags) > 0 {
@@ -440,6 +533,14 @@ func (s *Statsd) parseStatsdLine(line string) error {
 			}
 		}
 
+		ephemeral := ephemeralSource
+		if s.EphemeralTag != "" {
+			if _, ok := m.tags[s.EphemeralTag]; ok {
+				ephemeral = true
+				delete(m.tags, s.EphemeralTag)
+			}
+		}
+
 		// Make a unique key for the measurement name/tags
 		var tg []string
 		for k, v := range m.tags {
@@ -448,12 +549,62 @@ func (s *Statsd) parseStatsdLine(line string) error {
 		sort.Strings(tg)
 		m.hash = fmt.Sprintf("%s%s", strings.Join(tg, ""), m.name)
 
-		s.aggregate(m)
+		if ephemeral && m.mtype == "c" {
+			s.aggregateEphemeral(m, s.ephemeralJobKey(m, source))
+		} else {
+			s.aggregate(m)
+		}
 	}
 
 	return nil
 }
 
+// parseEvent publishes the dogstatsd event if the events are enabled.
+func (s *Statsd) parseEvent(line string) error {
+	if !s.DataDogEvents {
+		return nil
+	}
+	e, err := parseDogStatsDEvent(line)
+	if err != nil {
+		log.Printf("E! Error: %s, unable to parse event: %s\n", err, line)
+		return errors.New("Error Parsing statsd line")
+	}
+	publishEvent(e)
+	return nil
+}
+
+// parseServiceCheck caches the status of the dogstatsd service check as a
+// gauge if the service checks are enabled. The tags of the service check are
+// always parsed.
+func (s *Statsd) parseServiceCheck(line string) error {
+	if !s.DataDogServiceChecks {
+		return nil
+	}
+	check, err := parseDogStatsDServiceCheck(line)
+	if err != nil {
+		log.Printf("E! Error: %s, unable to parse service check: %s\n", err, line)
+		return errors.New("Error Parsing statsd line")
+	}
+	m := metric{
+		bucket:     check.name,
+		floatvalue: float64(check.status),
+		mtype:      "g",
+	}
+	m.name, m.field, m.tags = s.parseName(m.bucket)
+	m.tags["metric_type"] = "service_check"
+	for k, v := range check.tags {
+		m.tags[k] = v
+	}
+	var tg []string
+	for k, v := range m.tags {
+		tg = append(tg, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(tg)
+	m.hash = fmt.Sprintf("%s%s", strings.Join(tg, ""), m.name)
+	s.aggregate(m)
+	return nil
+}
+
 // parseName parses the given bucket name with the list of bucket maps in the
 // config file. If there is a match, it will parse the name of the metric and
 // map of tags.
@@ -519,7 +670,7 @@ func (s *Statsd) aggregate(m metric) {
 	defer s.Unlock()
 
 	switch m.mtype {
-	case "ms", "h":
+	case "ms", "h", "d":
 		// Check if the measurement exists
 		cached, ok := s.timings[m.hash]
 		if !ok {
@@ -603,12 +754,92 @@ func (s *Statsd) aggregate(m metric) {
 	}
 }
 
+// ephemeralJobKey returns the key the counter of a short-lived source is
+// aggregated under.
+func (s *Statsd) ephemeralJobKey(m metric, source string) string {
+	if s.EphemeralJobIDTag != "" {
+		if jobID, ok := m.tags[s.EphemeralJobIDTag]; ok {
+			return "job:" + jobID
+		}
+	}
+	return "source:" + source
+}
+
+// aggregateEphemeral aggregates the counter of a short-lived source with the
+// other counters of its job. Without a grace window, the counter is flushed
+// immediately.
+func (s *Statsd) aggregateEphemeral(m metric, key string) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.EphemeralGraceWindow <= 0 {
+		if s.acc != nil {
+			s.acc.AddFields(m.name, map[string]interface{}{m.field: m.intvalue}, m.tags, time.Now())
+		}
+		return
+	}
+	job, ok := s.ephemeralJobs[key]
+	if !ok {
+		job = &ephemeralJob{counters: make(map[string]cachedcounter)}
+		s.ephemeralJobs[key] = job
+	}
+	job.lastSeen = time.Now()
+	cached, ok := job.counters[m.hash]
+	if !ok {
+		cached = cachedcounter{
+			name:   m.name,
+			fields: make(map[string]interface{}),
+			tags:   m.tags,
+		}
+		job.counters[m.hash] = cached
+	}
+	value, _ := cached.fields[m.field].(int64)
+	cached.fields[m.field] = value + m.intvalue
+}
+
+// ephemeralFlusher flushes the jobs that have not sent any packets for the
+// grace window.
+func (s *Statsd) ephemeralFlusher() {
+	defer s.wg.Done()
+	window := time.Duration(s.EphemeralGraceWindow)
+	ticker := time.NewTicker(min(max(window/2, 100*time.Millisecond), time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.flushEphemeralJobs(now.Add(-window))
+		}
+	}
+}
+
+// flushEphemeralJobs flushes the jobs last seen before the cutoff.
+func (s *Statsd) flushEphemeralJobs(cutoff time.Time) {
+	s.Lock()
+	defer s.Unlock()
+	now := time.Now()
+	for key, job := range s.ephemeralJobs {
+		if job.lastSeen.After(cutoff) {
+			continue
+		}
+		if s.acc != nil {
+			for _, counter := range job.counters {
+				s.acc.AddFields(counter.name, counter.fields, counter.tags, now)
+			}
+		}
+		delete(s.ephemeralJobs, key)
+	}
+}
+
 func (s *Statsd) Stop() {
 	log.Println("D! Stopping the statsd service")
 	close(s.done)
 	s.listener.Close()
 	s.wg.Wait()
 	close(s.in)
+	// flush the short-lived sources still in their grace window
+	s.flushEphemeralJobs(time.Now())
 	log.Println("D! Stopped the statsd service")
 }
 
@@ -7,8 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net"
 	"testing"
+	"time"
 
+	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/assert"
 
@@ -21,11 +24,14 @@ func NewTestStatsd() *Statsd {
 
 	// Make data structures
 	s.done = make(chan struct{})
-	s.in = make(chan []byte, s.AllowedPendingMessages)
+	s.in = make(chan packet, s.AllowedPendingMessages)
 	s.gauges = make(map[string]cachedgauge)
 	s.counters = make(map[string]cachedcounter)
 	s.sets = make(map[string]cachedset)
 	s.timings = make(map[string]cachedtimings)
+	s.ephemeralJobs = make(map[string]*ephemeralJob)
+	s.previousSources = make(map[string]struct{})
+	s.currentSources = make(map[string]struct{})
 
 	s.MetricSeparator = "_"
 
@@ -1363,6 +1369,115 @@ func TestParse_Counters_Delete(t *testing.T) {
 	}
 }
 
+func TestParse_EphemeralTagFlushesImmediately(t *testing.T) {
+	s := NewTestStatsd()
+	s.ParseDataDogTags = true
+	s.EphemeralTag = "ephemeral"
+	acc := &testutil.Accumulator{}
+	s.acc = acc
+
+	assert.NoError(t, s.parseStatsdLine("batch.processed:5|c|#ephemeral,env:prod"))
+	assert.NoError(t, s.parseStatsdLine("batch.processed:3|c|#env:prod"))
+	// only counters are flushed immediately
+	assert.NoError(t, s.parseStatsdLine("batch.duration:20|ms|#ephemeral"))
+
+	acc.AssertContainsTaggedFields(t, "batch_processed",
+		map[string]interface{}{"value": int64(5)},
+		map[string]string{"metric_type": "counter", "env": "prod"})
+	assert.Len(t, acc.Metrics, 1)
+	assert.NoError(t, test_validate_counter("batch_processed", 3, s.counters))
+	assert.Len(t, s.timings, 1)
+	for _, timing := range s.timings {
+		assert.NotContains(t, timing.tags, "ephemeral")
+	}
+}
+
+func TestParse_EphemeralGraceWindowByJobID(t *testing.T) {
+	s := NewTestStatsd()
+	s.ParseDataDogTags = true
+	s.EphemeralTag = "ephemeral"
+	s.EphemeralJobIDTag = "job_id"
+	s.EphemeralGraceWindow = config.Duration(time.Minute)
+	acc := &testutil.Accumulator{}
+	s.acc = acc
+
+	// two processes of the same job and one of another job
+	assert.NoError(t, s.parseLine("batch.processed:5|c|#ephemeral,job_id:a", "127.0.0.1:40001", false))
+	assert.NoError(t, s.parseLine("batch.processed:7|c|#ephemeral,job_id:a", "127.0.0.1:40002", false))
+	assert.NoError(t, s.parseLine("batch.processed:1|c|#ephemeral,job_id:b", "127.0.0.1:40003", false))
+	assert.Len(t, s.ephemeralJobs, 2)
+	assert.Empty(t, s.counters)
+
+	// still within the grace window
+	s.flushEphemeralJobs(time.Now().Add(-time.Minute))
+	assert.Empty(t, acc.Metrics)
+
+	s.flushEphemeralJobs(time.Now())
+	acc.AssertContainsTaggedFields(t, "batch_processed",
+		map[string]interface{}{"value": int64(12)},
+		map[string]string{"metric_type": "counter", "job_id": "a"})
+	acc.AssertContainsTaggedFields(t, "batch_processed",
+		map[string]interface{}{"value": int64(1)},
+		map[string]string{"metric_type": "counter", "job_id": "b"})
+	assert.Empty(t, s.ephemeralJobs)
+}
+
+func TestEphemeralSourcePorts(t *testing.T) {
+	s := NewTestStatsd()
+	s.EphemeralSourcePorts = true
+	acc := &testutil.Accumulator{}
+
+	assert.True(t, s.isEphemeralSource("127.0.0.1:40001"))
+	assert.True(t, s.isEphemeralSource("127.0.0.1:40001"))
+	assert.NoError(t, s.Gather(acc))
+	// seen during the previous interval
+	assert.False(t, s.isEphemeralSource("127.0.0.1:40001"))
+	assert.True(t, s.isEphemeralSource("127.0.0.1:40002"))
+	assert.NoError(t, s.Gather(acc))
+	assert.NoError(t, s.Gather(acc))
+	assert.True(t, s.isEphemeralSource("127.0.0.1:40001"))
+
+	s.EphemeralSourcePorts = false
+	assert.False(t, s.isEphemeralSource("127.0.0.1:40003"))
+}
+
+func TestEphemeralFlushOnStop(t *testing.T) {
+	s := &Statsd{
+		ServiceAddress:         "127.0.0.1:0",
+		MetricSeparator:        "_",
+		AllowedPendingMessages: defaultAllowPendingMessage,
+		ParseDataDogTags:       true,
+		EphemeralTag:           "ephemeral",
+		EphemeralGraceWindow:   config.Duration(time.Hour),
+	}
+	acc := &testutil.Accumulator{}
+	assert.NoError(t, s.Start(acc))
+	assert.Eventually(t, func() bool {
+		s.Lock()
+		defer s.Unlock()
+		return s.listener != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	s.Lock()
+	addr := s.listener.LocalAddr().String()
+	s.Unlock()
+	conn, err := net.Dial("udp", addr)
+	assert.NoError(t, err)
+	_, err = conn.Write([]byte("batch.processed:5|c|#ephemeral"))
+	assert.NoError(t, err)
+	assert.NoError(t, conn.Close())
+	assert.Eventually(t, func() bool {
+		s.Lock()
+		defer s.Unlock()
+		return len(s.ephemeralJobs) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	s.Stop()
+	acc.AssertContainsTaggedFields(t, "batch_processed",
+		map[string]interface{}{"value": int64(5)},
+		map[string]string{"metric_type": "counter"})
+}
+
 func TestParseKeyValue(t *testing.T) {
 	k, v := parseKeyValue("foo=bar")
 	if k != "foo" {
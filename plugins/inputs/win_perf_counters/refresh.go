@@ -0,0 +1,98 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package win_perf_counters
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	noInstance    = "------"
+	totalInstance = "_Total"
+)
+
+// counterPath builds the path of the counter, without the instance for the
+// objects that do not have any.
+func counterPath(objectName, instance, counter string) string {
+	if instance == noInstance {
+		return "\\" + objectName + "\\" + counter
+	}
+	return "\\" + objectName + "(" + instance + ")\\" + counter
+}
+
+func isWildcard(instance string) bool {
+	return instance != noInstance && strings.Contains(instance, "*")
+}
+
+// instanceFromPath returns the instance of an expanded counter path, e.g. C:
+// for \LogicalDisk(C:)\% Free Space. The instances may contain parentheses,
+// but the counter names do not contain backslashes.
+func instanceFromPath(path string) (string, bool) {
+	start := strings.Index(path, "(")
+	end := strings.LastIndex(path, ")\\")
+	if start < 0 || end < start {
+		return "", false
+	}
+	return path[start+1 : end], true
+}
+
+// expandedInstances returns the sorted instances of the expanded counter paths.
+// The _Total instances are left out unless they are included, the same as when
+// the wildcard instances are collected without being expanded.
+func expandedInstances(paths []string, includeTotal bool) []string {
+	set := map[string]struct{}{}
+	for _, path := range paths {
+		instance, ok := instanceFromPath(path)
+		if !ok || (!includeTotal && strings.Contains(instance, totalInstance)) {
+			continue
+		}
+		set[instance] = struct{}{}
+	}
+	instances := make([]string, 0, len(set))
+	for instance := range set {
+		instances = append(instances, instance)
+	}
+	sort.Strings(instances)
+	return instances
+}
+
+// diffInstances compares the instances currently collected with the expanded
+// instances, and returns the sorted instances to add and to remove.
+func diffInstances(current map[string]int, instances []string) (added, removed []string) {
+	expanded := make(map[string]struct{}, len(instances))
+	for _, instance := range instances {
+		expanded[instance] = struct{}{}
+		if _, ok := current[instance]; !ok {
+			added = append(added, instance)
+		}
+	}
+	for instance := range current {
+		if _, ok := expanded[instance]; !ok {
+			removed = append(removed, instance)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// splitMultiString splits a list of null terminated UTF-16 strings, which ends
+// with an empty string.
+func splitMultiString(buf []uint16) []string {
+	var result []string
+	start := 0
+	for i, c := range buf {
+		if c != 0 {
+			continue
+		}
+		if i == start {
+			break
+		}
+		result = append(result, string(utf16.Decode(buf[start:i])))
+		start = i + 1
+	}
+	return result
+}
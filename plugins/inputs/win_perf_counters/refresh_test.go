@@ -0,0 +1,59 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package win_perf_counters
+
+import (
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounterPath(t *testing.T) {
+	assert.Equal(t, `\Memory\Available Bytes`, counterPath("Memory", "------", "Available Bytes"))
+	assert.Equal(t, `\LogicalDisk(C:)\% Free Space`, counterPath("LogicalDisk", "C:", "% Free Space"))
+}
+
+func TestIsWildcard(t *testing.T) {
+	assert.True(t, isWildcard("*"))
+	assert.True(t, isWildcard("w3wp*"))
+	assert.False(t, isWildcard("_Total"))
+	assert.False(t, isWildcard("------"))
+}
+
+func TestExpandedInstances(t *testing.T) {
+	paths := []string{
+		`\\HOST\Process(w3wp#1)\% Processor Time`,
+		`\\HOST\Process(app (x86))\% Processor Time`,
+		`\\HOST\Process(_Total)\% Processor Time`,
+		`\\HOST\Process(w3wp#1)\% Processor Time`,
+		`\\HOST\Memory\Available Bytes`,
+	}
+	assert.Equal(t, []string{"app (x86)", "w3wp#1"}, expandedInstances(paths, false))
+	assert.Equal(t, []string{"_Total", "app (x86)", "w3wp#1"}, expandedInstances(paths, true))
+	assert.Empty(t, expandedInstances(nil, false))
+}
+
+func TestDiffInstances(t *testing.T) {
+	current := map[string]int{"C:": 0, "D:": 1, "E:": 2}
+	added, removed := diffInstances(current, []string{"C:", "F:", "G:"})
+	assert.Equal(t, []string{"F:", "G:"}, added)
+	assert.Equal(t, []string{"D:", "E:"}, removed)
+
+	added, removed = diffInstances(current, []string{"C:", "D:", "E:"})
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+}
+
+func TestSplitMultiString(t *testing.T) {
+	var buf []uint16
+	for _, s := range []string{`\LogicalDisk(C:)\% Free Space`, `\LogicalDisk(D:)\% Free Space`} {
+		buf = append(buf, utf16.Encode([]rune(s))...)
+		buf = append(buf, 0)
+	}
+	// the list ends with an empty string, and the rest of the buffer is ignored
+	buf = append(buf, 0, 'x', 0)
+	assert.Equal(t, []string{`\LogicalDisk(C:)\% Free Space`, `\LogicalDisk(D:)\% Free Space`}, splitMultiString(buf))
+	assert.Empty(t, splitMultiString([]uint16{0, 0}))
+}
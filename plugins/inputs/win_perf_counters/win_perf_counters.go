@@ -11,9 +11,11 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 	"unsafe"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
 
@@ -26,6 +28,9 @@ var sampleConfig = `
   ## Settings:
   # PrintValid = false # Print All matching performance counters
   # DisableReplacer = false # Disable the name replacer
+  ## Period after which the wildcard instances are expanded again, so that the
+  ## instances added after the startup are collected. Disabled by default.
+  # CountersRefreshInterval = "1m"
 
   [[inputs.win_perf_counters.object]]
     # Processor usage, alternative to native, reports on a per core.
@@ -72,16 +77,21 @@ var sampleConfig = `
 `
 
 type Win_PerfCounters struct {
-	configParsed    bool
-	PrintValid      bool
-	DisableReplacer bool
-	TestName        string
-	PreVistaSupport bool
-	Object          []perfobject
+	configParsed            bool
+	PrintValid              bool
+	DisableReplacer         bool
+	TestName                string
+	PreVistaSupport         bool
+	CountersRefreshInterval config.Duration
+	Object                  []perfobject
 	// Valid queries end up in this map.
 	gItemList        map[int]*item
+	nextItem         int
 	testConfigParsed bool
 	testObject       string
+	// The wildcard queries expanded to an item per instance.
+	wildcards   []*wildcardQuery
+	lastRefresh time.Time
 }
 
 type perfobject struct {
@@ -112,6 +122,19 @@ type item struct {
 	counterHandle PDH_HCOUNTER
 }
 
+// wildcardQuery is a query with a wildcard instance. When the counters are
+// refreshed, it is expanded to an item per instance, and the items of the
+// instances added or removed since the last refresh are added or removed.
+type wildcardQuery struct {
+	objectName    string
+	counter       string
+	instance      string
+	measurement   string
+	include_total bool
+	// items maps the expanded instances to their index in gItemList.
+	items map[string]int
+}
+
 func (item *item) init() error {
 	if item.initialized {
 		return nil
@@ -154,7 +177,9 @@ func (m *Win_PerfCounters) AddItem(metrics *itemList, query string, objectName s
 
 	temp := &item{query, objectName, counter, instance, measurement,
 		include_total, false, handle, counterHandle}
-	index := len(m.gItemList)
+	// The items of the removed instances leave gaps in the indexes.
+	index := m.nextItem
+	m.nextItem++
 	m.gItemList[index] = temp
 
 	if metrics.items == nil {
@@ -177,17 +202,27 @@ func (m *Win_PerfCounters) ParseConfig(metrics *itemList) error {
 
 	m.configParsed = true
 	m.gItemList = make(map[int]*item)
+	m.nextItem = 0
+	m.wildcards = nil
+	m.lastRefresh = time.Now()
 
 	if len(m.Object) > 0 {
 		for _, PerfObject := range m.Object {
 			for _, counter := range PerfObject.Counters {
 				for _, instance := range PerfObject.Instances {
 					objectname := PerfObject.ObjectName
-
-					if instance == "------" {
-						query = "\\" + objectname + "\\" + counter
-					} else {
-						query = "\\" + objectname + "(" + instance + ")\\" + counter
+					query = counterPath(objectname, instance, counter)
+
+					if m.CountersRefreshInterval > 0 && isWildcard(instance) {
+						w := &wildcardQuery{objectname, counter, instance,
+							PerfObject.Measurement, PerfObject.IncludeTotal, map[string]int{}}
+						if err := m.refreshWildcard(metrics, w); err == nil {
+							m.wildcards = append(m.wildcards, w)
+							continue
+						} else if PerfObject.FailOnMissing || PerfObject.WarnOnMissing {
+							// Collect the instances found at the startup, as if the counters are not refreshed.
+							fmt.Printf("Unable to expand query: '%s'. Error: %s", query, err.Error())
+						}
 					}
 
 					err := m.AddItem(metrics, query, objectname, counter, instance,
@@ -216,6 +251,75 @@ func (m *Win_PerfCounters) ParseConfig(metrics *itemList) error {
 	}
 }
 
+// refreshWildcard expands the wildcard query, and adds the items of the new
+// instances and removes the items of the instances which are gone.
+func (m *Win_PerfCounters) refreshWildcard(metrics *itemList, w *wildcardQuery) error {
+	paths, err := expandWildCardPath(counterPath(w.objectName, w.instance, w.counter))
+	if err != nil {
+		return err
+	}
+	added, removed := diffInstances(w.items, expandedInstances(paths, w.include_total))
+	for _, instance := range removed {
+		index := w.items[instance]
+		if metric := m.gItemList[index]; metric.initialized {
+			PdhCloseQuery(metric.handle)
+		}
+		delete(m.gItemList, index)
+		delete(metrics.items, index)
+		delete(w.items, instance)
+	}
+	for _, instance := range added {
+		query := counterPath(w.objectName, instance, w.counter)
+		// The item is collected once it can be initialized, the same as the items of the config.
+		if err = m.AddItem(metrics, query, w.objectName, w.counter, instance, w.measurement, w.include_total); err != nil {
+			log.Printf("D! unable to add query %s: %v", query, err)
+		} else if m.PrintValid {
+			fmt.Printf("Valid: %s\n", query)
+		}
+		w.items[instance] = m.nextItem - 1
+	}
+	if len(added) > 0 || len(removed) > 0 {
+		log.Printf("D! refreshed instances of %s: added %v, removed %v", counterPath(w.objectName, w.instance, w.counter), added, removed)
+	}
+	return nil
+}
+
+// refreshCounters expands the wildcard queries again once the refresh interval
+// has passed.
+func (m *Win_PerfCounters) refreshCounters(metrics *itemList) {
+	if m.CountersRefreshInterval <= 0 || time.Since(m.lastRefresh) < time.Duration(m.CountersRefreshInterval) {
+		return
+	}
+	m.lastRefresh = time.Now()
+	for _, w := range m.wildcards {
+		if err := m.refreshWildcard(metrics, w); err != nil {
+			log.Printf("W! unable to refresh instances of %s: %v", counterPath(w.objectName, w.instance, w.counter), err)
+		}
+	}
+}
+
+// expandWildCardPath returns the paths of the counters currently matching the
+// wildcard path.
+func expandWildCardPath(path string) ([]string, error) {
+	var size uint32
+	ret := PdhExpandWildCardPath(path, nil, &size)
+	// Retry if instances are added between the calls.
+	for attempt := 0; ret == PDH_MORE_DATA && attempt < 3; attempt++ {
+		buf := make([]uint16, size)
+		ret = PdhExpandWildCardPath(path, &buf[0], &size)
+		if ret == ERROR_SUCCESS {
+			return splitMultiString(buf), nil
+		}
+	}
+	switch ret {
+	case ERROR_SUCCESS, PDH_CSTATUS_NO_INSTANCE:
+		// There are no instances yet.
+		return nil, nil
+	default:
+		return nil, errors.New(PdhFormatError(ret))
+	}
+}
+
 func (m *Win_PerfCounters) Cleanup(metrics *itemList) {
 	// Cleanup
 
@@ -254,6 +358,8 @@ func (m *Win_PerfCounters) Gather(acc telegraf.Accumulator) error {
 		if err != nil {
 			return err
 		}
+	} else {
+		m.refreshCounters(&metrics)
 	}
 
 	var bufSize uint32
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/require"
 )
@@ -521,3 +522,41 @@ func TestWinPerfcountersCollect2(t *testing.T) {
 	acc.AssertContainsTaggedFields(t, measurement, fields, tags)
 
 }
+
+func TestWinPerfcountersCollectRefresh(t *testing.T) {
+	PerfObject := perfobject{
+		ObjectName:    "Processor Information",
+		Instances:     []string{"*"},
+		Counters:      []string{"Parking Status"},
+		Measurement:   "test",
+		FailOnMissing: true,
+	}
+
+	m := Win_PerfCounters{TestName: "CollectRefresh", Object: []perfobject{PerfObject}, DisableReplacer: true,
+		CountersRefreshInterval: config.Duration(time.Millisecond)}
+	var acc testutil.Accumulator
+	require.NoError(t, m.Gather(&acc))
+	require.Len(t, m.wildcards, 1)
+	w := m.wildcards[0]
+	require.Contains(t, w.items, "0,0")
+	require.NotContains(t, w.items, "_Total")
+
+	// An instance which is gone is removed on the next refresh.
+	m.gItemList[m.nextItem] = &item{query: `\Processor Information(gone)\Parking Status`, instance: "gone"}
+	w.items["gone"] = m.nextItem
+	m.nextItem++
+
+	time.Sleep(2000 * time.Millisecond)
+	require.NoError(t, m.Gather(&acc))
+	require.NotContains(t, w.items, "gone")
+	require.Len(t, m.gItemList, len(w.items))
+
+	tags := map[string]string{
+		"instance":   "0,0",
+		"objectname": PerfObject.ObjectName,
+	}
+	fields := map[string]interface{}{
+		PerfObject.Counters[0]: float32(0),
+	}
+	acc.AssertContainsTaggedFields(t, PerfObject.Measurement, fields, tags)
+}
@@ -9,6 +9,7 @@ package windows_event_log
 import (
 	"errors"
 	"log"
+	"sync"
 	"time"
 
 	"golang.org/x/sys/windows/svc"
@@ -30,6 +31,7 @@ type statusChecker interface {
 }
 
 type serviceMonitor struct {
+	mu        sync.Mutex
 	listeners []chan struct{}
 	done      chan struct{}
 }
@@ -77,11 +79,16 @@ func (m *serviceMonitor) stop() {
 	close(m.done)
 }
 
+// addListener can be called after the monitor has started for discovered channels.
 func (m *serviceMonitor) addListener(listener chan struct{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.listeners = append(m.listeners, listener)
 }
 
 func (m *serviceMonitor) notify() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	for _, l := range m.listeners {
 		select {
 		case l <- struct{}{}:
@@ -24,12 +24,15 @@ import (
 
 const (
 	forcePullInterval = 250 * time.Millisecond
+	// discoveryInterval is how often the channels are listed for the event names with wildcards.
+	discoveryInterval = time.Minute
 )
 
 var startOnlyOnce sync.Once
 
 type EventConfig struct {
 	Name          string   `toml:"event_name"`
+	RemoteHost    string   `toml:"remote_host"`
 	Levels        []string `toml:"event_levels"`
 	RenderFormat  string   `toml:"event_format"`
 	BatchReadSize int      `toml:"batch_read_size"`
@@ -47,6 +50,11 @@ type Plugin struct {
 	Log             telegraf.Logger `toml:"-"`
 
 	newEvents []logs.LogSrc
+	monitor   *serviceMonitor
+	// patterns are the event configs with wildcards in the event name.
+	patterns      []EventConfig
+	subscribed    map[string]bool
+	lastDiscovery time.Time
 }
 
 func (s *Plugin) Description() string {
@@ -64,6 +72,15 @@ func (s *Plugin) SampleConfig() string {
 	log_group_name = "System"
 	log_stream_name = "STREAM_NAME"
 	destination = "cloudwatchlogs"
+
+	[[inputs.windows_event_log.event_config]]
+	## Wildcards subscribe to all the matching channels.
+	event_name = "Microsoft-Windows-Sysmon/*"
+	## Optional remote computer to read the events from with the agent's credentials.
+	remote_host = "collector.example.com"
+	batch_read_size = 1
+	log_group_name = "Sysmon"
+	log_stream_name = "STREAM_NAME"
 	`
 }
 
@@ -72,6 +89,9 @@ func (s *Plugin) Gather(acc telegraf.Accumulator) (err error) {
 }
 
 func (s *Plugin) FindLogSrc() []logs.LogSrc {
+	if len(s.patterns) > 0 && time.Since(s.lastDiscovery) >= discoveryInterval {
+		s.discoverChannels()
+	}
 	events := s.newEvents
 	s.newEvents = nil
 	return events
@@ -89,41 +109,106 @@ func (s *Plugin) Start(acc telegraf.Accumulator) error {
 		return nil
 	}
 
-	monitor := newServiceMonitor()
+	s.monitor = newServiceMonitor()
+	s.subscribed = make(map[string]bool)
 	for _, eventConfig := range s.Events {
-		// Assume no 2 EventConfigs have the same combination of:
-		// LogGroupName, LogStreamName, Name.
-		stateFilePath, err := getStateFilePath(s, &eventConfig)
-		if err != nil {
-			return err
-		}
-		destination := eventConfig.Destination
-		if destination == "" {
-			destination = s.Destination
+		if wineventlog.IsChannelPattern(eventConfig.Name) {
+			s.patterns = append(s.patterns, eventConfig)
+			continue
 		}
-		eventLog := wineventlog.NewEventLog(
-			eventConfig.Name,
-			eventConfig.Levels,
-			eventConfig.LogGroupName,
-			eventConfig.LogStreamName,
-			eventConfig.RenderFormat,
-			destination,
-			stateFilePath,
-			eventConfig.BatchReadSize,
-			eventConfig.Retention,
-			eventConfig.LogGroupClass,
-		)
-		err = eventLog.Init()
-		if err != nil {
+		if err := s.subscribe(eventConfig); err != nil {
 			return err
 		}
-		monitor.addListener(eventLog.ResubscribeCh())
-		s.newEvents = append(s.newEvents, eventLog)
 	}
-	go monitor.start()
+	s.discoverChannels()
+	go s.monitor.start()
+	return nil
+}
+
+// subscribe creates the event log source for a single channel.
+func (s *Plugin) subscribe(eventConfig EventConfig) error {
+	// Assume no 2 EventConfigs have the same combination of:
+	// LogGroupName, LogStreamName, Name, RemoteHost.
+	stateFilePath, err := getStateFilePath(s, &eventConfig)
+	if err != nil {
+		return err
+	}
+	destination := eventConfig.Destination
+	if destination == "" {
+		destination = s.Destination
+	}
+	eventLog := wineventlog.NewEventLog(
+		eventConfig.Name,
+		eventConfig.Levels,
+		eventConfig.LogGroupName,
+		eventConfig.LogStreamName,
+		eventConfig.RenderFormat,
+		destination,
+		stateFilePath,
+		eventConfig.BatchReadSize,
+		eventConfig.Retention,
+		eventConfig.LogGroupClass,
+		eventConfig.RemoteHost,
+	)
+	err = eventLog.Init()
+	if err != nil {
+		return err
+	}
+	s.monitor.addListener(eventLog.ResubscribeCh())
+	s.subscribed[subscriptionKey(eventConfig)] = true
+	s.newEvents = append(s.newEvents, eventLog)
 	return nil
 }
 
+// discoverChannels subscribes to the channels matching the event names with wildcards that
+// have not been subscribed to yet. Channels can be registered at any time, e.g. when Sysmon is
+// installed, so this runs periodically.
+func (s *Plugin) discoverChannels() {
+	s.lastDiscovery = time.Now()
+	channelsByHost := make(map[string][]string)
+	for _, pattern := range s.patterns {
+		channels, ok := channelsByHost[pattern.RemoteHost]
+		if !ok {
+			var err error
+			channels, err = listChannels(pattern.RemoteHost)
+			if err != nil {
+				s.Log.Warnf("Unable to list the channels on %q: %v", pattern.RemoteHost, err)
+				continue
+			}
+			channelsByHost[pattern.RemoteHost] = channels
+		}
+		for _, channel := range channels {
+			if !wineventlog.MatchChannel(pattern.Name, channel) {
+				continue
+			}
+			eventConfig := pattern
+			eventConfig.Name = channel
+			if s.subscribed[subscriptionKey(eventConfig)] {
+				continue
+			}
+			s.Log.Debugf("Discovered channel %s matching %s", channel, pattern.Name)
+			if err := s.subscribe(eventConfig); err != nil {
+				s.Log.Errorf("Unable to subscribe to channel %s: %v", channel, err)
+			}
+		}
+	}
+}
+
+func listChannels(remoteHost string) ([]string, error) {
+	session, err := wineventlog.OpenSession(remoteHost)
+	if err != nil {
+		return nil, err
+	}
+	if session != 0 {
+		defer wineventlog.EvtClose(session)
+	}
+	return wineventlog.ListChannels(session)
+}
+
+func subscriptionKey(ec EventConfig) string {
+	return strings.ToLower(ec.RemoteHost + "\\" + ec.Name + "\\" + ec.LogGroupName + "\\" + ec.LogStreamName)
+}
+
 // getStateFilePath returns a unique file pathname for a given EventConfig.
 func getStateFilePath(plugin *Plugin, ec *EventConfig) (string, error) {
 	if plugin.FileStateFolder == "" {
@@ -135,6 +220,10 @@ func getStateFilePath(plugin *Plugin, ec *EventConfig) (string, error) {
 	}
 	stateFileName := logscommon.WindowsEventLogPrefix +
 		escapeFileName(ec.LogGroupName+"_"+ec.LogStreamName+"_"+ec.Name)
+	// Each remote host keeps its own bookmark for the channel.
+	if ec.RemoteHost != "" {
+		stateFileName += "_" + escapeFileName(ec.RemoteHost)
+	}
 	return filepath.Join(plugin.FileStateFolder, stateFileName), nil
 }
 
@@ -67,6 +67,31 @@ func TestGetStateFilePathEscape(t *testing.T) {
 	}
 }
 
+// TestGetStateFilePathRemoteHost tests getStateFilePath() with a remote host.
+func TestGetStateFilePathRemoteHost(t *testing.T) {
+	fileStateFolder := filepath.Join(os.TempDir(), "CloudWatchAgentTest")
+	// cleanup
+	defer os.RemoveAll(fileStateFolder)
+	plugin := Plugin{
+		FileStateFolder: fileStateFolder,
+	}
+	ec := EventConfig{
+		LogGroupName:  "MyGroup",
+		LogStreamName: "MyStream",
+		Name:          "ForwardedEvents",
+		RemoteHost:    "collector.example.com",
+	}
+	pathname, err := getStateFilePath(&plugin, &ec)
+	if err != nil {
+		t.Errorf("expected nil, actual %v", err)
+	}
+	expected := filepath.Join(fileStateFolder,
+		"Amazon_CloudWatch_WindowsEventLog_MyGroup_MyStream_ForwardedEvents_collector.example.com")
+	if pathname != expected {
+		t.Errorf("expected %s, actual %s", expected, pathname)
+	}
+}
+
 // TestGetStateFilePathEmpty tests getStateFilePath() with empty folder.
 func TestGetStateFilePathEmpty(t *testing.T) {
 	fileStateFolder := ""
@@ -20,6 +20,30 @@ const (
 	EvtSubscribeStartAfterBookmark EvtSubscribeFlag = 3
 )
 
+// EvtLoginClass defines the values that specify the type of login for a remote session.
+type EvtLoginClass uint32
+
+const (
+	EvtRpcLoginClass EvtLoginClass = 1
+)
+
+// EvtRpcLoginFlags defines the values that specify the authentication method for a remote session.
+type EvtRpcLoginFlags uint32
+
+const (
+	EvtRpcLoginAuthNegotiate EvtRpcLoginFlags = 1
+)
+
+// EvtRpcLogin contains the information used to connect to a remote computer.
+// https://learn.microsoft.com/en-us/windows/win32/api/winevt/ns-winevt-evt_rpc_login
+type EvtRpcLogin struct {
+	Server   *uint16
+	User     *uint16
+	Domain   *uint16
+	Password *uint16
+	Flags    EvtRpcLoginFlags
+}
+
 // EvtRenderFlag defines the values that specify what to render.
 type EvtRenderFlag uint32
 
@@ -27,6 +51,7 @@ type EvtRenderFlag uint32
 // https://msdn.microsoft.com/en-us/library/windows/desktop/aa385563(v=vs.85).aspx
 const (
 	EvtRenderEventXml EvtRenderFlag = 1
+	EvtRenderBookmark EvtRenderFlag = 2
 )
 
 // EvtRenderContextFlag defines the values that specify the type of information
@@ -52,6 +77,10 @@ var (
 	procEvtNext                  = modwevtapi.NewProc("EvtNext")
 	procEvtFormatMessage         = modwevtapi.NewProc("EvtFormatMessage")
 	procEvtOpenPublisherMetadata = modwevtapi.NewProc("EvtOpenPublisherMetadata")
+	procEvtOpenSession           = modwevtapi.NewProc("EvtOpenSession")
+	procEvtUpdateBookmark        = modwevtapi.NewProc("EvtUpdateBookmark")
+	procEvtOpenChannelEnum       = modwevtapi.NewProc("EvtOpenChannelEnum")
+	procEvtNextChannelPath       = modwevtapi.NewProc("EvtNextChannelPath")
 )
 
 func EvtSubscribe(session EvtHandle, signalEvent uintptr, channelPath *uint16, query *uint16, bookmark EvtHandle, context uintptr, callback syscall.Handle, flags EvtSubscribeFlag) (handle EvtHandle, err error) {
@@ -153,3 +182,53 @@ func EvtOpenPublisherMetadata(session EvtHandle, publisherIdentity *uint16, logF
 	}
 	return
 }
+
+func EvtOpenSession(loginClass EvtLoginClass, login *EvtRpcLogin, timeout uint32, flags uint32) (handle EvtHandle, err error) {
+	r0, _, e1 := syscall.Syscall6(procEvtOpenSession.Addr(), 4, uintptr(loginClass), uintptr(unsafe.Pointer(login)), uintptr(timeout), uintptr(flags), 0, 0)
+	handle = EvtHandle(r0)
+	if handle == 0 {
+		if e1 != 0 {
+			err = error(e1)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
+func EvtUpdateBookmark(bookmark EvtHandle, event EvtHandle) (err error) {
+	r1, _, e1 := syscall.Syscall(procEvtUpdateBookmark.Addr(), 2, uintptr(bookmark), uintptr(event), 0)
+	if r1 == 0 {
+		if e1 != 0 {
+			err = error(e1)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
+func EvtOpenChannelEnum(session EvtHandle, flags uint32) (handle EvtHandle, err error) {
+	r0, _, e1 := syscall.Syscall(procEvtOpenChannelEnum.Addr(), 2, uintptr(session), uintptr(flags), 0)
+	handle = EvtHandle(r0)
+	if handle == 0 {
+		if e1 != 0 {
+			err = error(e1)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
+func EvtNextChannelPath(channelEnum EvtHandle, channelPathBufferSize uint32, channelPathBuffer *uint16, channelPathBufferUsed *uint32) (err error) {
+	r1, _, e1 := syscall.Syscall6(procEvtNextChannelPath.Addr(), 4, uintptr(channelEnum), uintptr(channelPathBufferSize), uintptr(unsafe.Pointer(channelPathBuffer)), uintptr(unsafe.Pointer(channelPathBufferUsed)), 0, 0)
+	if r1 == 0 {
+		if e1 != 0 {
+			err = error(e1)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"path"
 	"strconv"
 	"strings"
 	"syscall"
@@ -51,7 +52,11 @@ func RenderEventXML(eventHandle EvtHandle, renderBuf []byte) ([]byte, error) {
 }
 
 func CreateBookmark(channel string, recordID uint64) (h EvtHandle, err error) {
-	xml := fmt.Sprintf(bookmarkTemplate, channel, recordID)
+	return CreateBookmarkFromXML(fmt.Sprintf(bookmarkTemplate, channel, recordID))
+}
+
+// CreateBookmarkFromXML creates a bookmark from the XML rendered by RenderBookmark.
+func CreateBookmarkFromXML(xml string) (h EvtHandle, err error) {
 	p, err := syscall.UTF16PtrFromString(xml)
 	if err != nil {
 		return 0, err
@@ -63,6 +68,70 @@ func CreateBookmark(channel string, recordID uint64) (h EvtHandle, err error) {
 	return h, nil
 }
 
+// RenderBookmark renders the current position of the bookmark as XML.
+func RenderBookmark(bookmark EvtHandle, renderBuf []byte) (string, error) {
+	var bufferUsed, propertyCount uint32
+
+	if err := EvtRender(0, bookmark, EvtRenderBookmark, uint32(len(renderBuf)), &renderBuf[0], &bufferUsed, &propertyCount); err != nil {
+		return "", fmt.Errorf("error when rendering the bookmark. Details: %v", err)
+	}
+	xml, err := utf16ToUTF8Bytes(renderBuf, bufferUsed)
+	return string(xml), err
+}
+
+// OpenSession opens a session to the remote host with the credentials the agent runs as.
+// The local session (0) is returned for an empty host.
+func OpenSession(host string) (EvtHandle, error) {
+	if host == "" {
+		return 0, nil
+	}
+	server, err := syscall.UTF16PtrFromString(host)
+	if err != nil {
+		return 0, err
+	}
+	login := &EvtRpcLogin{Server: server, Flags: EvtRpcLoginAuthNegotiate}
+	return EvtOpenSession(EvtRpcLoginClass, login, 0, 0)
+}
+
+// ListChannels returns the paths of all the channels registered in the session.
+func ListChannels(session EvtHandle) ([]string, error) {
+	channelEnum, err := EvtOpenChannelEnum(session, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error when enumerating the channels. Details: %v", err)
+	}
+	defer EvtClose(channelEnum)
+
+	var channels []string
+	buf := make([]uint16, 256)
+	for {
+		var bufferUsed uint32
+		err = EvtNextChannelPath(channelEnum, uint32(len(buf)), &buf[0], &bufferUsed)
+		switch err {
+		case nil:
+			channels = append(channels, syscall.UTF16ToString(buf[:bufferUsed]))
+		case ERROR_INSUFFICIENT_BUFFER:
+			buf = make([]uint16, bufferUsed)
+		case ERROR_NO_MORE_ITEMS:
+			return channels, nil
+		default:
+			return nil, fmt.Errorf("error when enumerating the channels. Details: %v", err)
+		}
+	}
+}
+
+// IsChannelPattern returns true if the event name is a wildcard pattern of channels.
+func IsChannelPattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// MatchChannel reports whether the channel matches the wildcard pattern. Channel
+// names are case-insensitive and "*" does not match the "/" separator, so
+// Microsoft-Windows-Sysmon/* matches the channels of the Sysmon provider.
+func MatchChannel(pattern, channel string) bool {
+	matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(channel))
+	return matched
+}
+
 func CreateQuery(path string, levels []string) (*uint16, error) {
 	var filterLevels string
 	for _, level := range levels {
@@ -132,3 +132,21 @@ func TestInsertPlaceholderValues(t *testing.T) {
 func resetState() {
 	NumberOfBytesPerCharacter = 0
 }
+
+func TestMatchChannel(t *testing.T) {
+	assert.True(t, IsChannelPattern("Microsoft-Windows-Sysmon/*"))
+	assert.False(t, IsChannelPattern("ForwardedEvents"))
+
+	assert.True(t, MatchChannel("Microsoft-Windows-Sysmon/*", "Microsoft-Windows-Sysmon/Operational"))
+	assert.True(t, MatchChannel("microsoft-windows-sysmon/*", "Microsoft-Windows-Sysmon/Operational"))
+	assert.True(t, MatchChannel("Microsoft-Windows-*/Operational", "Microsoft-Windows-Sysmon/Operational"))
+	assert.False(t, MatchChannel("Microsoft-Windows-*", "Microsoft-Windows-Sysmon/Operational"))
+	assert.False(t, MatchChannel("Microsoft-Windows-Sysmon/*", "Application"))
+}
+
+func TestListChannels(t *testing.T) {
+	channels, err := ListChannels(0)
+	assert.NoError(t, err)
+	assert.Contains(t, channels, "Application")
+	assert.Contains(t, channels, "ForwardedEvents")
+}
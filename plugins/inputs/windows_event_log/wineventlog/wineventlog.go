@@ -27,14 +27,21 @@ import (
 // https://msdn.microsoft.com/en-us/library/windows/desktop/ms681382(v=vs.85).aspx
 // https://msdn.microsoft.com/en-us/library/windows/desktop/aa385525(v=vs.85).aspx
 const (
-	RPC_S_INVALID_BOUND syscall.Errno = 1734
+	ERROR_INSUFFICIENT_BUFFER syscall.Errno = 122
+	ERROR_NO_MORE_ITEMS       syscall.Errno = 259
+	RPC_S_SERVER_UNAVAILABLE  syscall.Errno = 1722
+	RPC_S_CALL_FAILED         syscall.Errno = 1726
+	RPC_S_INVALID_BOUND       syscall.Errno = 1734
 
 	collectionInterval  = time.Second
 	saveStateInterval   = 100 * time.Millisecond
+	reconnectInterval   = 30 * time.Second
 	subscribeMaxRetries = 3
+	bookmarkBufferSize  = 1 << 12
 
-	apiEvtSubscribe = "EvtSubscribe"
-	apiEvtClose     = "EvtClose"
+	apiEvtSubscribe   = "EvtSubscribe"
+	apiEvtClose       = "EvtClose"
+	apiEvtOpenSession = "EvtOpenSession"
 )
 
 type wevtAPIError struct {
@@ -47,8 +54,17 @@ func (e *wevtAPIError) Error() string {
 	return fmt.Sprintf("%s(), name %s, err %v", e.api, e.name, e.err)
 }
 
+// eventState is the position of a published event. The events are ordered by the sequence
+// because the record IDs of forwarded events belong to the source computers.
+type eventState struct {
+	seq      uint64
+	offset   uint64
+	bookmark string
+}
+
 type windowsEventLog struct {
 	name          string
+	remoteHost    string
 	levels        []string
 	logGroupName  string
 	logStreamName string
@@ -58,19 +74,28 @@ type windowsEventLog struct {
 	destination   string
 	stateFilePath string
 
-	eventHandle   EvtHandle
-	eventOffset   uint64
-	retention     int
-	outputFn      func(logs.LogEvent)
-	offsetCh      chan uint64
-	done          chan struct{}
-	startOnce     sync.Once
-	resubscribeCh chan struct{}
-}
-
-func NewEventLog(name string, levels []string, logGroupName, logStreamName, renderFormat, destination, stateFilePath string, maximumToRead int, retention int, logGroupClass string) *windowsEventLog {
+	session        EvtHandle
+	eventHandle    EvtHandle
+	eventOffset    uint64
+	bookmark       EvtHandle
+	bookmarkXML    string
+	bookmarkBuf    []byte
+	seq            uint64
+	lostConnection bool
+	retention      int
+	outputFn       func(logs.LogEvent)
+	stateCh        chan eventState
+	done           chan struct{}
+	startOnce      sync.Once
+	resubscribeCh  chan struct{}
+}
+
+// NewEventLog creates a subscription to the channel. The remote host is optional and the local
+// channel is subscribed to if it is empty.
+func NewEventLog(name string, levels []string, logGroupName, logStreamName, renderFormat, destination, stateFilePath string, maximumToRead int, retention int, logGroupClass string, remoteHost string) *windowsEventLog {
 	eventLog := &windowsEventLog{
 		name:          name,
+		remoteHost:    remoteHost,
 		levels:        levels,
 		logGroupName:  logGroupName,
 		logStreamName: logStreamName,
@@ -81,7 +106,8 @@ func NewEventLog(name string, levels []string, logGroupName, logStreamName, rend
 		stateFilePath: stateFilePath,
 		retention:     retention,
 
-		offsetCh:      make(chan uint64, 100),
+		bookmarkBuf:   make([]byte, bookmarkBufferSize),
+		stateCh:       make(chan eventState, 100),
 		done:          make(chan struct{}),
 		resubscribeCh: make(chan struct{}),
 	}
@@ -90,7 +116,7 @@ func NewEventLog(name string, levels []string, logGroupName, logStreamName, rend
 
 func (w *windowsEventLog) Init() error {
 	go w.runSaveState()
-	w.eventOffset = w.loadState()
+	w.eventOffset, w.bookmarkXML = w.loadState()
 	return w.Open()
 }
 
@@ -111,6 +137,9 @@ func (w *windowsEventLog) Stream() string {
 }
 
 func (w *windowsEventLog) Description() string {
+	if w.remoteHost != "" {
+		return fmt.Sprintf("%v:%v%v", w.remoteHost, w.name, w.levels)
+	}
 	return fmt.Sprintf("%v%v", w.name, w.levels)
 }
 
@@ -140,13 +169,24 @@ func (w *windowsEventLog) run() {
 
 	retryCount := 0
 	var shouldResubscribe bool
+	var lastReconnect time.Time
 	for {
 		select {
 		case <-w.resubscribeCh:
 			shouldResubscribe = true
 		case <-ticker.C:
+			if w.lostConnection && time.Since(lastReconnect) >= reconnectInterval {
+				lastReconnect = time.Now()
+				w.eventOffset, w.bookmarkXML = w.loadState()
+				if err := w.resubscribe(); err != nil {
+					log.Printf("W! [wineventlog] Unable to reconnect to %s: %v", w.remoteHost, err)
+				} else {
+					log.Printf("I! [wineventlog] Reconnected to %s on %s", w.name, w.remoteHost)
+					w.lostConnection = false
+				}
+			}
 			if shouldResubscribe {
-				w.eventOffset = w.loadState()
+				w.eventOffset, w.bookmarkXML = w.loadState()
 				if err := w.resubscribe(); err != nil {
 					log.Printf("E! [wineventlog] Unable to re-subscribe: %v", err)
 					retryCount++
@@ -168,11 +208,12 @@ func (w *windowsEventLog) run() {
 					continue
 				}
 				recordNumber, _ := strconv.ParseUint(record.System.EventRecordID, 10, 64)
+				w.seq++
 				evt := &LogEvent{
-					msg:    value,
-					t:      record.System.TimeCreated.SystemTime,
-					offset: recordNumber,
-					src:    w,
+					msg:   value,
+					t:     record.System.TimeCreated.SystemTime,
+					state: eventState{seq: w.seq, offset: recordNumber, bookmark: record.bookmark},
+					src:   w,
 				}
 				w.outputFn(evt)
 			}
@@ -183,46 +224,88 @@ func (w *windowsEventLog) run() {
 }
 
 // Open subscription for events. Instead of failing the subscription if the eventlog name has not been registered,
-// log the error.
+// log the error. A remote host that cannot be reached is retried in the background.
 func (w *windowsEventLog) Open() error {
 	err := w.open()
-	if werr, ok := err.(*wevtAPIError); ok && werr.api == apiEvtSubscribe {
+	if werr, ok := err.(*wevtAPIError); ok && (werr.api == apiEvtSubscribe || werr.api == apiEvtOpenSession) {
 		log.Printf("W! [wineventlog] %v", err)
+		w.lostConnection = w.remoteHost != ""
 		return nil
 	}
 	return err
 }
 
 func (w *windowsEventLog) open() error {
-	bookmark, err := CreateBookmark(w.name, w.eventOffset)
+	session, err := OpenSession(w.remoteHost)
+	if err != nil {
+		return &wevtAPIError{api: apiEvtOpenSession, name: w.remoteHost, err: err}
+	}
+	bookmark, err := w.createBookmark()
 	if err != nil {
+		closeSession(session)
 		return err
 	}
-	defer EvtClose(bookmark)
 	// Using a pull subscription to receive events. See:
 	// https://msdn.microsoft.com/en-us/library/windows/desktop/aa385771(v=vs.85).aspx#pull
 	signalEvent, err := windows.CreateEvent(nil, 0, 0, nil)
 	if err != nil {
+		EvtClose(bookmark)
+		closeSession(session)
 		return nil
 	}
 	channelPath, err := syscall.UTF16PtrFromString(w.name)
 	if err != nil {
+		EvtClose(bookmark)
+		closeSession(session)
 		return err
 	}
 	query, err := CreateQuery(w.name, w.levels)
 	if err != nil {
+		EvtClose(bookmark)
+		closeSession(session)
 		return err
 	}
-	eventHandle, err := EvtSubscribe(0, uintptr(signalEvent), channelPath, query, bookmark, 0, 0, EvtSubscribeStartAfterBookmark)
+	eventHandle, err := EvtSubscribe(session, uintptr(signalEvent), channelPath, query, bookmark, 0, 0, EvtSubscribeStartAfterBookmark)
 	if err != nil {
+		EvtClose(bookmark)
+		closeSession(session)
 		return &wevtAPIError{api: apiEvtSubscribe, name: w.name, err: err}
 	}
+	w.session = session
 	w.eventHandle = eventHandle
+	// The bookmark is kept to be updated with each read event.
+	w.bookmark = bookmark
 	return nil
 }
 
+// createBookmark creates the bookmark from the saved state. The rendered bookmark is preferred over
+// the record ID since it stays valid for channels with forwarded events.
+func (w *windowsEventLog) createBookmark() (EvtHandle, error) {
+	if w.bookmarkXML != "" {
+		bookmark, err := CreateBookmarkFromXML(w.bookmarkXML)
+		if err == nil {
+			return bookmark, nil
+		}
+		log.Printf("W! [wineventlog] Unable to restore the bookmark of %s, falling back to offset %d: %v", w.name, w.eventOffset, err)
+	}
+	return CreateBookmark(w.name, w.eventOffset)
+}
+
+func closeSession(session EvtHandle) {
+	if session != 0 {
+		EvtClose(session)
+	}
+}
+
 func (w *windowsEventLog) Close() error {
-	return EvtClose(w.eventHandle)
+	if w.bookmark != 0 {
+		EvtClose(w.bookmark)
+		w.bookmark = 0
+	}
+	err := EvtClose(w.eventHandle)
+	closeSession(w.session)
+	w.session = 0
+	return err
 }
 
 // resubscribe closes the event subscription based on the event handle and resets the handle to the
@@ -253,8 +336,8 @@ func (w *windowsEventLog) SetEventOffset(eventOffset uint64) {
 	w.eventOffset = eventOffset
 }
 
-func (w *windowsEventLog) Done(offset uint64) {
-	w.offsetCh <- offset
+func (w *windowsEventLog) Done(state eventState) {
+	w.stateCh <- state
 }
 
 func (w *windowsEventLog) ResubscribeCh() chan struct{} {
@@ -265,25 +348,25 @@ func (w *windowsEventLog) runSaveState() {
 	t := time.NewTicker(saveStateInterval)
 	defer w.Stop()
 
-	var offset, lastSavedOffset uint64
+	var state, lastSavedState eventState
 	for {
 		select {
-		case o := <-w.offsetCh:
-			if o > offset {
-				offset = o
+		case s := <-w.stateCh:
+			if s.seq > state.seq {
+				state = s
 			}
 		case <-t.C:
-			if offset == lastSavedOffset {
+			if state.seq == lastSavedState.seq {
 				continue
 			}
-			err := w.saveState(offset)
+			err := w.saveState(state)
 			if err != nil {
 				log.Printf("E! [wineventlog] Error happened when saving file state %s to file state folder %s: %v", w.logGroupName, w.stateFilePath, err)
 				continue
 			}
-			lastSavedOffset = offset
+			lastSavedState = state
 		case <-w.done:
-			err := w.saveState(offset)
+			err := w.saveState(state)
 			if err != nil {
 				log.Printf("E! [wineventlog] Error happened during final file state saving of logfile %s to file state folder %s, duplicate log maybe sent at next start: %v", w.logGroupName, w.stateFilePath, err)
 			}
@@ -292,12 +375,17 @@ func (w *windowsEventLog) runSaveState() {
 	}
 }
 
-func (w *windowsEventLog) saveState(offset uint64) error {
-	if w.stateFilePath == "" || offset == 0 {
+// saveState writes the record ID, the log group name and the rendered bookmark on separate lines.
+// The bookmark is last since it can span multiple lines.
+func (w *windowsEventLog) saveState(state eventState) error {
+	if w.stateFilePath == "" || state.offset == 0 {
 		return nil
 	}
-	content := []byte(strconv.FormatUint(offset, 10) + "\n" + w.logGroupName)
-	return os.WriteFile(w.stateFilePath, content, 0644)
+	content := strconv.FormatUint(state.offset, 10) + "\n" + w.logGroupName
+	if state.bookmark != "" {
+		content += "\n" + state.bookmark
+	}
+	return os.WriteFile(w.stateFilePath, []byte(content), 0644)
 }
 
 func (w *windowsEventLog) read() []*windowsEventLogRecord {
@@ -314,6 +402,13 @@ func (w *windowsEventLog) read() []*windowsEventLogRecord {
 		eventHandles = make([]EvtHandle, maxToRead)
 		err := EvtNext(w.eventHandle, uint32(len(eventHandles)),
 			&eventHandles[0], 0, 0, &numRead)
+		if w.remoteHost != "" && (err == RPC_S_SERVER_UNAVAILABLE || err == RPC_S_CALL_FAILED) {
+			if !w.lostConnection {
+				log.Printf("W! [wineventlog] Lost the connection to %s: %v", w.remoteHost, err)
+			}
+			w.lostConnection = true
+			return nil
+		}
 		// Handle special case when events size is too large - retry with smaller size
 		if err == RPC_S_INVALID_BOUND {
 			if maxToRead == 1 {
@@ -334,10 +429,10 @@ func (w *windowsEventLog) read() []*windowsEventLogRecord {
 }
 
 type LogEvent struct {
-	msg    string
-	t      time.Time
-	offset uint64
-	src    *windowsEventLog
+	msg   string
+	t     time.Time
+	state eventState
+	src   *windowsEventLog
 }
 
 func (le LogEvent) Message() string {
@@ -349,15 +444,22 @@ func (le LogEvent) Time() time.Time {
 }
 
 func (le LogEvent) Done() {
-	le.src.Done(le.offset)
+	le.src.Done(le.state)
 }
 
 // getRecords attempts to render and format each of the given EvtHandles.
 // If one handle has an error, continue on because something is better than nothing.
 func (w *windowsEventLog) getRecords(handles []EvtHandle) (records []*windowsEventLogRecord) {
 	for _, evtHandle := range handles {
+		// The bookmark moves past the events that cannot be rendered as well.
+		if w.bookmark != 0 {
+			if err := EvtUpdateBookmark(w.bookmark, evtHandle); err != nil {
+				log.Printf("D! [wineventlog] Unable to update the bookmark of %s: %v", w.name, err)
+			}
+		}
 		r, err := w.getRecord(evtHandle)
 		if err == nil {
+			r.bookmark = w.renderBookmark()
 			records = append(records, r)
 		} else {
 			log.Printf("I! [wineventlog] %v", err)
@@ -366,6 +468,20 @@ func (w *windowsEventLog) getRecords(handles []EvtHandle) (records []*windowsEve
 	return records
 }
 
+// renderBookmark returns the current position of the subscription as XML. The offset is used
+// to resume the subscription if the bookmark cannot be rendered.
+func (w *windowsEventLog) renderBookmark() string {
+	if w.bookmark == 0 {
+		return ""
+	}
+	bookmark, err := RenderBookmark(w.bookmark, w.bookmarkBuf)
+	if err != nil {
+		log.Printf("D! [wineventlog] Unable to render the bookmark of %s: %v", w.name, err)
+		return ""
+	}
+	return bookmark
+}
+
 // getRecord attemps to render and format the message for the given EvtHandle.
 func (w *windowsEventLog) getRecord(evtHandle EvtHandle) (*windowsEventLogRecord, error) {
 	// Notes on the process:
@@ -384,19 +500,26 @@ func (w *windowsEventLog) getRecord(evtHandle EvtHandle) (*windowsEventLogRecord
 	//we need the "System.TimeCreated.SystemTime"
 	xml.Unmarshal(outputBuf, newRecord)
 	publisher, _ := syscall.UTF16PtrFromString(newRecord.System.Provider.Name)
-	publisherMetadataEvtHandle, err := EvtOpenPublisherMetadata(0, publisher, nil, 0, 0)
+	var descriptionBytes []byte
+	publisherMetadataEvtHandle, err := EvtOpenPublisherMetadata(w.session, publisher, nil, 0, 0)
 	if err != nil {
-		return nil, fmt.Errorf("EvtOpenPublisherMetadata() publisher %v, err %v", newRecord.System.Provider.Name, err)
-	}
-	var bufferUsed uint32
-	err = EvtFormatMessage(publisherMetadataEvtHandle, evtHandle, 0, 0, 0, EvtFormatMessageXml, uint32(bufferSize), &renderBuf[0], &bufferUsed)
-	EvtClose(publisherMetadataEvtHandle)
-	if err != nil && bufferUsed == 0 {
-		return nil, fmt.Errorf("EvtFormatMessage() publisher %v, err %v", newRecord.System.Provider.Name, err)
-	}
-	descriptionBytes, err := UTF16ToUTF8BytesForWindowsEventBuffer(renderBuf, bufferUsed)
-	if err != nil {
-		return nil, fmt.Errorf("utf16ToUTF8Bytes() err %v", err)
+		// Events forwarded in the RenderedText format already contain the message rendered on the
+		// source computer, so the publisher does not need to be installed on this one.
+		if newRecord.RenderingInfo.Message == "" {
+			return nil, fmt.Errorf("EvtOpenPublisherMetadata() publisher %v, err %v", newRecord.System.Provider.Name, err)
+		}
+		descriptionBytes = outputBuf
+	} else {
+		var bufferUsed uint32
+		err = EvtFormatMessage(publisherMetadataEvtHandle, evtHandle, 0, 0, 0, EvtFormatMessageXml, uint32(bufferSize), &renderBuf[0], &bufferUsed)
+		EvtClose(publisherMetadataEvtHandle)
+		if err != nil && bufferUsed == 0 {
+			return nil, fmt.Errorf("EvtFormatMessage() publisher %v, err %v", newRecord.System.Provider.Name, err)
+		}
+		descriptionBytes, err = UTF16ToUTF8BytesForWindowsEventBuffer(renderBuf, bufferUsed)
+		if err != nil {
+			return nil, fmt.Errorf("utf16ToUTF8Bytes() err %v", err)
+		}
 	}
 
 	// The insertion strings could be in either EventData or UserData
@@ -427,21 +550,28 @@ func (w *windowsEventLog) getRecord(evtHandle EvtHandle) (*windowsEventLogRecord
 	return newRecord, nil
 }
 
-func (w *windowsEventLog) loadState() uint64 {
+// loadState returns the record ID and the rendered bookmark from the state file. State files
+// written by older versions do not have the bookmark.
+func (w *windowsEventLog) loadState() (uint64, string) {
 	if _, err := os.Stat(w.stateFilePath); err != nil {
 		log.Printf("I! [wineventlog] The state file for %s does not exist: %v", w.stateFilePath, err)
-		return 0
+		return 0, ""
 	}
 	byteArray, err := os.ReadFile(w.stateFilePath)
 	if err != nil {
 		log.Printf("W! [wineventlog] Issue encountered when reading offset from file %s: %v", w.stateFilePath, err)
-		return 0
+		return 0, ""
 	}
-	offset, err := strconv.ParseUint(strings.Split(string(byteArray), "\n")[0], 10, 64)
+	lines := strings.SplitN(string(byteArray), "\n", 3)
+	offset, err := strconv.ParseUint(lines[0], 10, 64)
 	if err != nil {
 		log.Printf("W! [wineventlog] Issue encountered when parsing offset value %v: %v", byteArray, err)
-		return 0
+		return 0, ""
+	}
+	var bookmark string
+	if len(lines) == 3 {
+		bookmark = lines[2]
 	}
 	log.Printf("D! [wineventlog] Reading from offset %v in %s", offset, w.stateFilePath)
-	return offset
+	return offset, bookmark
 }
@@ -8,11 +8,15 @@ package wineventlog
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/sys/windows/svc/eventlog"
 )
 
@@ -29,12 +33,13 @@ var (
 	BATCH_SIZE      = 99
 	RETENTION       = 42
 	LOG_GROUP_CLASS = "standard"
+	REMOTE_HOST     = ""
 )
 
 // TestNewEventLog verifies constructor's default values.
 func TestNewEventLog(t *testing.T) {
 	elog := NewEventLog(NAME, LEVELS, GROUP_NAME, STREAM_NAME, RENDER_FMT, DEST,
-		STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS)
+		STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS, REMOTE_HOST)
 	assert.Equal(t, NAME, elog.name)
 	assert.Equal(t, uint64(0), elog.eventOffset)
 	assert.Zero(t, elog.eventHandle)
@@ -45,26 +50,26 @@ func TestNewEventLog(t *testing.T) {
 func TestOpen(t *testing.T) {
 	// Happy path.
 	elog := NewEventLog(NAME, LEVELS, GROUP_NAME, STREAM_NAME, RENDER_FMT, DEST,
-		STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS)
+		STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS, REMOTE_HOST)
 	assert.NoError(t, elog.Open())
 	assert.NotZero(t, elog.eventHandle)
 	assert.NoError(t, elog.Close())
 	// Bad event log source name does not cause Open() to fail.
 	// But eventHandle will be 0 and Close() will fail because of it.
 	elog = NewEventLog("FakeBadElogName", LEVELS, GROUP_NAME, STREAM_NAME,
-		RENDER_FMT, DEST, STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS)
+		RENDER_FMT, DEST, STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS, REMOTE_HOST)
 	assert.NoError(t, elog.Open())
 	assert.Zero(t, elog.eventHandle)
 	assert.Error(t, elog.Close())
 	// bad LEVELS does not cause Open() to fail.
 	elog = NewEventLog(NAME, []string{"498"}, GROUP_NAME, STREAM_NAME,
-		RENDER_FMT, DEST, STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS)
+		RENDER_FMT, DEST, STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS, REMOTE_HOST)
 	assert.NoError(t, elog.Open())
 	assert.NotZero(t, elog.eventHandle)
 	assert.NoError(t, elog.Close())
 	// bad wlog.eventOffset does not cause Open() to fail.
 	elog = NewEventLog(NAME, []string{"498"}, GROUP_NAME, STREAM_NAME,
-		RENDER_FMT, DEST, STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS)
+		RENDER_FMT, DEST, STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS, REMOTE_HOST)
 	elog.eventOffset = 9987
 	assert.NoError(t, elog.Open())
 	assert.NotZero(t, elog.eventHandle)
@@ -75,7 +80,7 @@ func TestOpen(t *testing.T) {
 // event log source.
 func TestReadGoodSource(t *testing.T) {
 	elog := NewEventLog(NAME, LEVELS, GROUP_NAME, STREAM_NAME, RENDER_FMT, DEST,
-		STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS)
+		STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS, REMOTE_HOST)
 	assert.NoError(t, elog.Open())
 	seekToEnd(t, elog)
 	writeEvents(t, 10, true, "CWA_UnitTest111", 777)
@@ -88,7 +93,7 @@ func TestReadGoodSource(t *testing.T) {
 // unregistered event log source.
 func TestReadBadSource(t *testing.T) {
 	elog := NewEventLog(NAME, LEVELS, GROUP_NAME, STREAM_NAME, RENDER_FMT, DEST,
-		STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS)
+		STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS, REMOTE_HOST)
 	assert.NoError(t, elog.Open())
 	seekToEnd(t, elog)
 	writeEvents(t, 10, false, "CWA_UnitTest222", 888)
@@ -102,7 +107,7 @@ func TestReadBadSource(t *testing.T) {
 // unregistered source too.
 func TestReadWithBothSources(t *testing.T) {
 	elog := NewEventLog(NAME, LEVELS, GROUP_NAME, STREAM_NAME, RENDER_FMT, DEST,
-		STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS)
+		STATE_FILE_PATH, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS, REMOTE_HOST)
 	assert.NoError(t, elog.Open())
 	seekToEnd(t, elog)
 	writeEvents(t, 10, true, "CWA_UnitTest111", 777)
@@ -113,6 +118,49 @@ func TestReadWithBothSources(t *testing.T) {
 	assert.NoError(t, elog.Close())
 }
 
+// TestReadBookmark verifies the records have the rendered bookmark and the subscription resumes
+// from the saved bookmark.
+func TestReadBookmark(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "state")
+	elog := NewEventLog(NAME, LEVELS, GROUP_NAME, STREAM_NAME, RENDER_FMT, DEST,
+		stateFilePath, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS, REMOTE_HOST)
+	assert.NoError(t, elog.Open())
+	seekToEnd(t, elog)
+	writeEvents(t, 2, true, "CWA_UnitTest333", 999)
+	records := readHelper(elog)
+	assert.NoError(t, elog.Close())
+	checkEvents(t, records, "[Application] [ERROR] [999] [CWA_UnitTest333] ", 2)
+	require.NotEmpty(t, records)
+	last := records[len(records)-1]
+	assert.Contains(t, last.bookmark, "RecordId=")
+
+	// resume after the first of the two events
+	first := records[len(records)-2]
+	offset, _ := strconv.ParseUint(first.RecordId(), 10, 64)
+	require.NoError(t, elog.saveState(eventState{seq: 1, offset: offset, bookmark: first.bookmark}))
+	gotOffset, gotBookmark := elog.loadState()
+	assert.Equal(t, offset, gotOffset)
+	assert.Equal(t, first.bookmark, gotBookmark)
+
+	elog.eventOffset, elog.bookmarkXML = gotOffset, gotBookmark
+	assert.NoError(t, elog.Open())
+	records = readHelper(elog)
+	assert.NoError(t, elog.Close())
+	require.Len(t, records, 1)
+	assert.Equal(t, last.RecordId(), records[0].RecordId())
+}
+
+// TestLoadStateWithoutBookmark verifies the state files of older versions can be loaded.
+func TestLoadStateWithoutBookmark(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "state")
+	require.NoError(t, os.WriteFile(stateFilePath, []byte("123\n"+GROUP_NAME), 0644))
+	elog := NewEventLog(NAME, LEVELS, GROUP_NAME, STREAM_NAME, RENDER_FMT, DEST,
+		stateFilePath, BATCH_SIZE, RETENTION, LOG_GROUP_CLASS, REMOTE_HOST)
+	offset, bookmark := elog.loadState()
+	assert.EqualValues(t, 123, offset)
+	assert.Empty(t, bookmark)
+}
+
 // seekToEnd skips past all current events in the event log.
 func seekToEnd(t *testing.T, elog *windowsEventLog) {
 	// loop until we stop getting records.
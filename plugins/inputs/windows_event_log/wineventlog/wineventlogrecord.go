@@ -26,6 +26,8 @@ type eventMessage struct {
 // For Windows versions later than 2003
 type windowsEventLogRecord struct {
 	windowsEventLog *windowsEventLog
+	// bookmark is the rendered position of the subscription after the record.
+	bookmark string
 
 	XmlFormatContent string
 
@@ -49,6 +51,11 @@ type windowsEventLogRecord struct {
 
 	EventData EventData `xml:"EventData"`
 	UserData  UserData  `xml:"UserData"`
+
+	// RenderingInfo is only set for events forwarded in the RenderedText format.
+	RenderingInfo struct {
+		Message string `xml:"Message"`
+	} `xml:"RenderingInfo"`
 }
 
 func newEventLogRecord(l *windowsEventLog) *windowsEventLogRecord {
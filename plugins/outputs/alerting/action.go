@@ -0,0 +1,200 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+const (
+	filePerm = 0644
+
+	defaultSNMPPort = 162
+	// sysUpTimeOID and snmpTrapOID are the first two variables of a v2c trap.
+	sysUpTimeOID = ".1.3.6.1.2.1.1.3.0"
+	snmpTrapOID  = ".1.3.6.1.6.3.1.1.4.1.0"
+)
+
+// fileMu serializes the writes of the rules sharing a file, so the lines
+// are not interleaved.
+var fileMu sync.Mutex
+
+var processStart = time.Now()
+
+func runAction(action Action, n Notification) error {
+	switch action.Type {
+	case ActionFile:
+		return appendToFile(action, n)
+	case ActionCommand:
+		return runCommand(action, n)
+	case ActionWebhook:
+		return postWebhook(action, n)
+	case ActionSNMPTrap:
+		return sendTrap(action, n)
+	}
+	return fmt.Errorf("unsupported action type %q", action.Type)
+}
+
+func appendToFile(action Action, n Notification) error {
+	line, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	f, err := os.OpenFile(action.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, filePerm)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// runCommand passes the notification as JSON on the standard input and in
+// the ALERT_ environment variables, so simple scripts do not have to parse it.
+func runCommand(action Action, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), action.Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, action.Command[0], action.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(),
+		"ALERT_RULE="+n.Rule,
+		"ALERT_STATE="+n.State,
+		"ALERT_METRIC="+n.Metric,
+		"ALERT_VALUE="+strconv.FormatFloat(n.Value, 'f', -1, 64),
+		"ALERT_THRESHOLD="+strconv.FormatFloat(n.Threshold, 'f', -1, 64),
+		"ALERT_TIMESTAMP="+n.Timestamp.UTC().Format(time.RFC3339),
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("command %s failed: %w: %s", action.Command[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func postWebhook(action Action, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), action.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, action.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s responded with status %d", action.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// sendTrap sends a v2c trap with the fields of the notification as string
+// variables under the trap OID, in the order rule, state, metric, value,
+// threshold and dimensions.
+func sendTrap(action Action, n Notification) error {
+	host, port, err := splitAddress(action.Address)
+	if err != nil {
+		return err
+	}
+	community := action.Community
+	if community == "" {
+		community = "public"
+	}
+	g := &gosnmp.GoSNMP{
+		Target:    host,
+		Port:      port,
+		Community: community,
+		Version:   gosnmp.Version2c,
+		Timeout:   action.Timeout,
+	}
+	if err = g.Connect(); err != nil {
+		return err
+	}
+	defer g.Conn.Close()
+	trapOID := "." + strings.TrimPrefix(action.TrapOID, ".")
+	variables := []gosnmp.SnmpPDU{
+		{Name: sysUpTimeOID, Type: gosnmp.TimeTicks, Value: uint32(time.Since(processStart) / (10 * time.Millisecond))},
+		{Name: snmpTrapOID, Type: gosnmp.ObjectIdentifier, Value: trapOID},
+	}
+	for i, value := range []string{
+		n.Rule,
+		n.State,
+		n.Metric,
+		strconv.FormatFloat(n.Value, 'f', -1, 64),
+		strconv.FormatFloat(n.Threshold, 'f', -1, 64),
+		formatDimensions(n.Dimensions),
+	} {
+		variables = append(variables, gosnmp.SnmpPDU{
+			Name:  fmt.Sprintf("%s.%d", trapOID, i+1),
+			Type:  gosnmp.OctetString,
+			Value: value,
+		})
+	}
+	_, err = g.SendTrap(gosnmp.SnmpTrap{Variables: variables})
+	return err
+}
+
+func splitAddress(address string) (string, uint16, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		// the port is optional
+		return address, defaultSNMPPort, nil
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port in address %q", address)
+	}
+	return host, uint16(port), nil
+}
+
+// formatDimensions returns the dimensions as sorted key=value pairs.
+func formatDimensions(dimensions map[string]string) string {
+	pairs := make([]string, 0, len(dimensions))
+	for key, value := range dimensions {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// validateOID checks that the OID is made of numbers separated by dots.
+func validateOID(oid string) error {
+	parts := strings.Split(strings.TrimPrefix(oid, "."), ".")
+	if len(parts) < 2 {
+		return fmt.Errorf("invalid 'trap_oid' %q", oid)
+	}
+	for _, part := range parts {
+		if _, err := strconv.ParseUint(part, 10, 32); err != nil {
+			return fmt.Errorf("invalid 'trap_oid' %q", oid)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package alerting
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNotification = Notification{
+	Rule:       "high_cpu",
+	State:      StateAlarm,
+	Metric:     "cpu_usage_active",
+	Dimensions: map[string]string{"cpu": "cpu0", "host": "edge-1"},
+	Value:      95.5,
+	Comparison: ComparisonGreaterThan,
+	Threshold:  90,
+	Timestamp:  time.Unix(1700000000, 0).UTC(),
+}
+
+func TestAppendToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.log")
+	action := Action{Type: ActionFile, Path: path, Timeout: time.Second}
+	require.NoError(t, runAction(action, testNotification))
+	require.NoError(t, runAction(action, testNotification))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+	var got Notification
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+	assert.Equal(t, testNotification, got)
+}
+
+func TestRunCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	path := filepath.Join(t.TempDir(), "out")
+	action := Action{
+		Type:    ActionCommand,
+		Command: []string{"sh", "-c", `echo "$ALERT_RULE $ALERT_STATE $ALERT_VALUE" > ` + path + `; cat >> ` + path},
+		Timeout: 5 * time.Second,
+	}
+	require.NoError(t, runAction(action, testNotification))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.SplitN(string(content), "\n", 2)
+	assert.Equal(t, "high_cpu ALARM 95.5", lines[0])
+	assert.Contains(t, lines[1], `"rule":"high_cpu"`)
+
+	action.Command = []string{"sh", "-c", "echo broken >&2; exit 3"}
+	assert.ErrorContains(t, runAction(action, testNotification), "broken")
+}
+
+func TestPostWebhook(t *testing.T) {
+	var got Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &got))
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	action := Action{Type: ActionWebhook, URL: server.URL + "/alerts", Timeout: time.Second}
+	require.NoError(t, runAction(action, testNotification))
+	assert.Equal(t, testNotification, got)
+
+	action.URL = server.URL + "/fail"
+	assert.ErrorContains(t, runAction(action, testNotification), "status 500")
+}
+
+func TestSendTrap(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	action := Action{
+		Type:    ActionSNMPTrap,
+		Address: conn.LocalAddr().String(),
+		TrapOID: "1.3.6.1.4.1.8072.9999",
+		Timeout: time.Second,
+	}
+	require.NoError(t, runAction(action, testNotification))
+
+	buf := make([]byte, 4096)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	decoder := &gosnmp.GoSNMP{Version: gosnmp.Version2c}
+	packet, err := decoder.SnmpDecodePacket(buf[:n])
+	require.NoError(t, err)
+	assert.Equal(t, "public", packet.Community)
+	require.Len(t, packet.Variables, 8)
+	assert.Equal(t, ".1.3.6.1.4.1.8072.9999", packet.Variables[1].Value)
+	var values []string
+	for _, variable := range packet.Variables[2:] {
+		values = append(values, string(variable.Value.([]byte)))
+	}
+	assert.Equal(t, []string{"high_cpu", "ALARM", "cpu_usage_active", "95.5", "90", "cpu=cpu0,host=edge-1"}, values)
+}
@@ -0,0 +1,250 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package alerting
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+const (
+	StateAlarm = "ALARM"
+	StateOK    = "OK"
+)
+
+// Notification is passed to the actions when a series of a rule changes state.
+type Notification struct {
+	Rule       string            `json:"rule"`
+	State      string            `json:"state"`
+	Metric     string            `json:"metric"`
+	Dimensions map[string]string `json:"dimensions,omitempty"`
+	Value      float64           `json:"value"`
+	Comparison string            `json:"comparison"`
+	Threshold  float64           `json:"threshold"`
+	Timestamp  time.Time         `json:"timestamp"`
+}
+
+// seriesState is the state of a rule for a series of its metric.
+type seriesState struct {
+	// breachingSince is the timestamp of the first data point of the
+	// current breach, zero if the series is not breaching.
+	breachingSince time.Time
+	alarm          bool
+}
+
+type alerting struct {
+	config *Config
+	logger *zap.Logger
+	run    func(Action, Notification) error
+
+	mu sync.Mutex
+	// states are keyed by the rule, then by the dimensions of the series.
+	states map[string]map[string]*seriesState
+
+	// wg tracks the actions in flight, so they are not cut off by the shutdown.
+	wg sync.WaitGroup
+}
+
+func newAlerting(config *Config, logger *zap.Logger) *alerting {
+	a := &alerting{
+		config: config,
+		logger: logger,
+		run:    runAction,
+		states: make(map[string]map[string]*seriesState, len(config.Rules)),
+	}
+	for _, rule := range config.Rules {
+		a.states[rule.Name] = make(map[string]*seriesState)
+	}
+	return a
+}
+
+// Shutdown waits for the actions in flight.
+func (a *alerting) Shutdown(context.Context) error {
+	a.wg.Wait()
+	return nil
+}
+
+// ConsumeMetrics evaluates the rules on the gauge and sum data points.
+func (a *alerting) ConsumeMetrics(_ context.Context, md pmetric.Metrics) error {
+	var notifications []notification
+	a.mu.Lock()
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				notifications = append(notifications, a.evaluate(metrics.At(k))...)
+			}
+		}
+	}
+	a.mu.Unlock()
+	for _, n := range notifications {
+		a.notify(n.rule, n.Notification)
+	}
+	return nil
+}
+
+type notification struct {
+	Notification
+	rule *Rule
+}
+
+func (a *alerting) evaluate(metric pmetric.Metric) []notification {
+	var dps pmetric.NumberDataPointSlice
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps = metric.Gauge().DataPoints()
+	case pmetric.MetricTypeSum:
+		dps = metric.Sum().DataPoints()
+	default:
+		return nil
+	}
+	var notifications []notification
+	for i := range a.config.Rules {
+		rule := &a.config.Rules[i]
+		if rule.Metric != metric.Name() {
+			continue
+		}
+		for j := 0; j < dps.Len(); j++ {
+			dp := dps.At(j)
+			if !matches(dp.Attributes(), rule.Dimensions) {
+				continue
+			}
+			var value float64
+			switch dp.ValueType() {
+			case pmetric.NumberDataPointValueTypeInt:
+				value = float64(dp.IntValue())
+			case pmetric.NumberDataPointValueTypeDouble:
+				value = dp.DoubleValue()
+			default:
+				continue
+			}
+			timestamp := dp.Timestamp().AsTime()
+			if dp.Timestamp() == 0 {
+				timestamp = time.Now()
+			}
+			if state, ok := a.transition(rule, dp.Attributes(), value, timestamp); ok {
+				notifications = append(notifications, notification{
+					rule: rule,
+					Notification: Notification{
+						Rule:       rule.Name,
+						State:      state,
+						Metric:     rule.Metric,
+						Dimensions: dimensions(dp.Attributes()),
+						Value:      value,
+						Comparison: rule.Comparison,
+						Threshold:  rule.Threshold,
+						Timestamp:  timestamp,
+					},
+				})
+			}
+		}
+	}
+	return notifications
+}
+
+// transition updates the state of the series with the value and returns the
+// new state if it changed.
+func (a *alerting) transition(rule *Rule, attrs pcommon.Map, value float64, timestamp time.Time) (string, bool) {
+	states := a.states[rule.Name]
+	key := attributesKey(attrs)
+	state, ok := states[key]
+	if !ok {
+		state = &seriesState{}
+		states[key] = state
+	}
+	if !rule.breaches(value) {
+		state.breachingSince = time.Time{}
+		if state.alarm {
+			state.alarm = false
+			return StateOK, true
+		}
+		return "", false
+	}
+	if state.breachingSince.IsZero() {
+		state.breachingSince = timestamp
+	}
+	if !state.alarm && timestamp.Sub(state.breachingSince) >= rule.Duration {
+		state.alarm = true
+		return StateAlarm, true
+	}
+	return "", false
+}
+
+// notify runs the actions of the rule in the background, so the slow ones do
+// not hold up the pipeline.
+func (a *alerting) notify(rule *Rule, n Notification) {
+	a.logger.Info("Alert state changed", zap.String("rule", n.Rule), zap.String("state", n.State), zap.Float64("value", n.Value), zap.Any("dimensions", n.Dimensions))
+	for _, action := range rule.Actions {
+		a.wg.Add(1)
+		go func(action Action) {
+			defer a.wg.Done()
+			if err := a.run(action, n); err != nil {
+				a.logger.Error("Failed to run the alert action", zap.String("rule", n.Rule), zap.String("type", action.Type), zap.Error(err))
+			}
+		}(action)
+	}
+}
+
+func (r *Rule) breaches(value float64) bool {
+	switch r.Comparison {
+	case ComparisonGreaterThan:
+		return value > r.Threshold
+	case ComparisonGreaterThanOrEqualTo:
+		return value >= r.Threshold
+	case ComparisonLessThan:
+		return value < r.Threshold
+	case ComparisonLessThanOrEqualTo:
+		return value <= r.Threshold
+	}
+	return false
+}
+
+func matches(attrs pcommon.Map, dimensions map[string]string) bool {
+	for key, want := range dimensions {
+		value, ok := attrs.Get(key)
+		if !ok || value.AsString() != want {
+			return false
+		}
+	}
+	return true
+}
+
+func dimensions(attrs pcommon.Map) map[string]string {
+	if attrs.Len() == 0 {
+		return nil
+	}
+	result := make(map[string]string, attrs.Len())
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		result[k] = v.AsString()
+		return true
+	})
+	return result
+}
+
+func attributesKey(attrs pcommon.Map) string {
+	keys := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, _ pcommon.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		v, _ := attrs.Get(k)
+		sb.WriteString(k)
+		sb.WriteByte(0)
+		sb.WriteString(v.AsString())
+		sb.WriteByte(0)
+	}
+	return sb.String()
+}
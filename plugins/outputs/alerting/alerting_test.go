@@ -0,0 +1,126 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package alerting
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+func newTestMetrics(timestamp time.Time, values map[string]float64) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	m := metrics.AppendEmpty()
+	m.SetName("cpu_usage_active")
+	dps := m.SetEmptyGauge().DataPoints()
+	for cpu, value := range values {
+		dp := dps.AppendEmpty()
+		dp.SetDoubleValue(value)
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+		dp.Attributes().PutStr("cpu", cpu)
+	}
+	m = metrics.AppendEmpty()
+	m.SetName("mem_used_percent")
+	m.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(99)
+	return md
+}
+
+type recorder struct {
+	mu            sync.Mutex
+	notifications []Notification
+}
+
+func (r *recorder) run(_ Action, n Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifications = append(r.notifications, n)
+	return nil
+}
+
+func (r *recorder) states() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var states []string
+	for _, n := range r.notifications {
+		states = append(states, n.Rule+"/"+n.Dimensions["cpu"]+"/"+n.State)
+	}
+	return states
+}
+
+func TestConsumeMetrics(t *testing.T) {
+	cfg := &Config{
+		Rules: []Rule{
+			{
+				Name:       "high_cpu",
+				Metric:     "cpu_usage_active",
+				Comparison: ComparisonGreaterThan,
+				Threshold:  90,
+				Duration:   time.Minute,
+				Actions:    []Action{{Type: ActionFile, Path: "alerts.log", Timeout: time.Second}},
+			},
+			{
+				Name:       "idle_cpu0",
+				Metric:     "cpu_usage_active",
+				Dimensions: map[string]string{"cpu": "cpu0"},
+				Comparison: ComparisonLessThanOrEqualTo,
+				Threshold:  1,
+				Actions:    []Action{{Type: ActionFile, Path: "alerts.log", Timeout: time.Second}},
+			},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+	r := &recorder{}
+	a := newAlerting(cfg, zap.NewNop())
+	a.run = r.run
+	start := time.Unix(1700000000, 0)
+
+	steps := []struct {
+		offset time.Duration
+		values map[string]float64
+		want   []string
+	}{
+		{0, map[string]float64{"cpu0": 95, "cpu1": 50}, nil},
+		// breaching for less than the duration
+		{30 * time.Second, map[string]float64{"cpu0": 95, "cpu1": 95}, nil},
+		{time.Minute, map[string]float64{"cpu0": 96, "cpu1": 95}, []string{"high_cpu/cpu0/ALARM"}},
+		// still in alarm
+		{90 * time.Second, map[string]float64{"cpu0": 97, "cpu1": 95}, []string{"high_cpu/cpu1/ALARM"}},
+		{2 * time.Minute, map[string]float64{"cpu0": 0.5, "cpu1": 95}, []string{"high_cpu/cpu0/OK", "idle_cpu0/cpu0/ALARM"}},
+		{3 * time.Minute, map[string]float64{"cpu0": 50, "cpu1": 50}, []string{"high_cpu/cpu1/OK", "idle_cpu0/cpu0/OK"}},
+	}
+	for _, step := range steps {
+		r.notifications = nil
+		require.NoError(t, a.ConsumeMetrics(context.Background(), newTestMetrics(start.Add(step.offset), step.values)))
+		require.NoError(t, a.Shutdown(context.Background()))
+		assert.ElementsMatch(t, step.want, r.states(), "at %v", step.offset)
+	}
+}
+
+func TestBreaches(t *testing.T) {
+	testCases := []struct {
+		comparison string
+		value      float64
+		want       bool
+	}{
+		{ComparisonGreaterThan, 10, false},
+		{ComparisonGreaterThan, 11, true},
+		{ComparisonGreaterThanOrEqualTo, 10, true},
+		{ComparisonLessThan, 10, false},
+		{ComparisonLessThan, 9, true},
+		{ComparisonLessThanOrEqualTo, 10, true},
+		{ComparisonLessThanOrEqualTo, 11, false},
+	}
+	for _, testCase := range testCases {
+		rule := Rule{Comparison: testCase.comparison, Threshold: 10}
+		assert.Equal(t, testCase.want, rule.breaches(testCase.value), "%v %s 10", testCase.value, testCase.comparison)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+const (
+	ComparisonGreaterThan          = ">"
+	ComparisonGreaterThanOrEqualTo = ">="
+	ComparisonLessThan             = "<"
+	ComparisonLessThanOrEqualTo    = "<="
+
+	ActionFile     = "file"
+	ActionCommand  = "command"
+	ActionWebhook  = "webhook"
+	ActionSNMPTrap = "snmp_trap"
+)
+
+// Config represent a configuration for the alerting exporter.
+type Config struct {
+	Rules []Rule `mapstructure:"rules"`
+}
+
+// Rule fires its actions once the data points of a series of the metric
+// have breached the threshold for the duration, and again once they stop
+// breaching it.
+type Rule struct {
+	// Name of the rule, e.g. high_cpu.
+	Name string `mapstructure:"name"`
+	// Metric is the name of the metric the rule is evaluated on.
+	Metric string `mapstructure:"metric"`
+	// Dimensions the series must have. All the series of the metric are
+	// evaluated separately if empty.
+	Dimensions map[string]string `mapstructure:"dimensions,omitempty"`
+	// Comparison of the value with the threshold, i.e. >, >=, < or <=.
+	Comparison string  `mapstructure:"comparison"`
+	Threshold  float64 `mapstructure:"threshold"`
+	// Duration the threshold must be breached for. The rule fires on the
+	// first breaching data point if zero.
+	Duration time.Duration `mapstructure:"duration"`
+	Actions  []Action      `mapstructure:"actions"`
+}
+
+// Action is run with the notification of the rule.
+type Action struct {
+	// Type is file, command, webhook or snmp_trap.
+	Type string `mapstructure:"type"`
+	// Path of the file the notifications are appended to as JSON lines.
+	Path string `mapstructure:"path,omitempty"`
+	// Command and its arguments. The notification is passed in the
+	// environment variables and as JSON on the standard input.
+	Command []string `mapstructure:"command,omitempty"`
+	// URL the notification is posted to as JSON.
+	URL string `mapstructure:"url,omitempty"`
+	// Address of the SNMP manager receiving the v2c traps, e.g. 10.0.0.5:162.
+	Address   string `mapstructure:"address,omitempty"`
+	Community string `mapstructure:"community,omitempty"`
+	// TrapOID identifies the trap. The fields of the notification are sent
+	// as variables under it.
+	TrapOID string `mapstructure:"trap_oid,omitempty"`
+	// Timeout of the command, the request or the trap.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the exporter configuration is valid.
+func (c *Config) Validate() error {
+	if len(c.Rules) == 0 {
+		return errors.New("'rules' must not be empty")
+	}
+	names := make(map[string]struct{}, len(c.Rules))
+	for _, rule := range c.Rules {
+		if rule.Name == "" {
+			return errors.New("rule 'name' must not be empty")
+		}
+		if _, ok := names[rule.Name]; ok {
+			return fmt.Errorf("rule %q is defined more than once", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *Rule) validate() error {
+	if r.Metric == "" {
+		return errors.New("'metric' must be set")
+	}
+	switch r.Comparison {
+	case ComparisonGreaterThan, ComparisonGreaterThanOrEqualTo, ComparisonLessThan, ComparisonLessThanOrEqualTo:
+	default:
+		return fmt.Errorf("invalid 'comparison' %q", r.Comparison)
+	}
+	if r.Duration < 0 {
+		return errors.New("'duration' must not be negative")
+	}
+	if len(r.Actions) == 0 {
+		return errors.New("'actions' must not be empty")
+	}
+	for i, action := range r.Actions {
+		if err := action.validate(); err != nil {
+			return fmt.Errorf("action %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (a *Action) validate() error {
+	if a.Timeout <= 0 {
+		return errors.New("'timeout' must be positive")
+	}
+	switch a.Type {
+	case ActionFile:
+		if a.Path == "" {
+			return errors.New("'path' must be set")
+		}
+	case ActionCommand:
+		if len(a.Command) == 0 || a.Command[0] == "" {
+			return errors.New("'command' must be set")
+		}
+	case ActionWebhook:
+		u, err := url.Parse(a.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid 'url' %q", a.URL)
+		}
+	case ActionSNMPTrap:
+		if a.Address == "" {
+			return errors.New("'address' must be set")
+		}
+		if a.TrapOID == "" {
+			return errors.New("'trap_oid' must be set")
+		}
+		if err := validateOID(a.TrapOID); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid 'type' %q", a.Type)
+	}
+	return nil
+}
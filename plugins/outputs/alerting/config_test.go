@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidate(t *testing.T) {
+	testCases := map[string]struct {
+		modify  func(cfg *Config)
+		wantErr string
+	}{
+		"WithRule": {
+			modify: func(*Config) {},
+		},
+		"WithoutRules": {
+			modify:  func(cfg *Config) { cfg.Rules = nil },
+			wantErr: "'rules' must not be empty",
+		},
+		"WithDuplicateRule": {
+			modify:  func(cfg *Config) { cfg.Rules = append(cfg.Rules, cfg.Rules[0]) },
+			wantErr: `rule "high_cpu" is defined more than once`,
+		},
+		"WithoutMetric": {
+			modify:  func(cfg *Config) { cfg.Rules[0].Metric = "" },
+			wantErr: "'metric' must be set",
+		},
+		"WithInvalidComparison": {
+			modify:  func(cfg *Config) { cfg.Rules[0].Comparison = "==" },
+			wantErr: `invalid 'comparison' "=="`,
+		},
+		"WithNegativeDuration": {
+			modify:  func(cfg *Config) { cfg.Rules[0].Duration = -time.Second },
+			wantErr: "'duration' must not be negative",
+		},
+		"WithoutActions": {
+			modify:  func(cfg *Config) { cfg.Rules[0].Actions = nil },
+			wantErr: "'actions' must not be empty",
+		},
+		"WithInvalidActionType": {
+			modify:  func(cfg *Config) { cfg.Rules[0].Actions[0].Type = "email" },
+			wantErr: `invalid 'type' "email"`,
+		},
+		"WithoutTimeout": {
+			modify:  func(cfg *Config) { cfg.Rules[0].Actions[0].Timeout = 0 },
+			wantErr: "'timeout' must be positive",
+		},
+		"WithoutCommand": {
+			modify: func(cfg *Config) {
+				cfg.Rules[0].Actions = []Action{{Type: ActionCommand, Timeout: time.Second}}
+			},
+			wantErr: "'command' must be set",
+		},
+		"WithInvalidURL": {
+			modify: func(cfg *Config) {
+				cfg.Rules[0].Actions = []Action{{Type: ActionWebhook, URL: "localhost:9000", Timeout: time.Second}}
+			},
+			wantErr: `invalid 'url' "localhost:9000"`,
+		},
+		"WithInvalidTrapOID": {
+			modify: func(cfg *Config) {
+				cfg.Rules[0].Actions = []Action{{Type: ActionSNMPTrap, Address: "10.0.0.5", TrapOID: "1.3.a", Timeout: time.Second}}
+			},
+			wantErr: `invalid 'trap_oid' "1.3.a"`,
+		},
+		"WithAllActions": {
+			modify: func(cfg *Config) {
+				cfg.Rules[0].Actions = append(cfg.Rules[0].Actions,
+					Action{Type: ActionCommand, Command: []string{"/usr/local/bin/notify"}, Timeout: time.Second},
+					Action{Type: ActionWebhook, URL: "http://127.0.0.1:9000/alerts", Timeout: time.Second},
+					Action{Type: ActionSNMPTrap, Address: "10.0.0.5:162", TrapOID: ".1.3.6.1.4.1.8072.9999", Timeout: time.Second},
+				)
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Config{
+				Rules: []Rule{
+					{
+						Name:       "high_cpu",
+						Metric:     "cpu_usage_active",
+						Comparison: ComparisonGreaterThan,
+						Threshold:  90,
+						Duration:   5 * time.Minute,
+						Actions:    []Action{{Type: ActionFile, Path: "/var/log/alerts.log", Timeout: time.Second}},
+					},
+				},
+			}
+			testCase.modify(cfg)
+			if testCase.wantErr != "" {
+				assert.ErrorContains(t, cfg.Validate(), testCase.wantErr)
+			} else {
+				assert.NoError(t, cfg.Validate())
+			}
+		})
+	}
+}
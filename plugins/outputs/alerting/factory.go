@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package alerting provides a metric exporter for the OpenTelemetry collector
+// that evaluates threshold rules on the metrics and runs local actions when
+// they breach, for the hosts that cannot rely on CloudWatch alarms.
+package alerting
+
+import (
+	"context"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+const (
+	stability = component.StabilityLevelAlpha
+)
+
+var (
+	TypeStr, _ = component.NewType("alerting")
+)
+
+func NewFactory() exporter.Factory {
+	return exporter.NewFactory(
+		TypeStr,
+		createDefaultConfig,
+		exporter.WithMetrics(createMetricsExporter, stability),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{}
+}
+
+func createMetricsExporter(
+	ctx context.Context,
+	settings exporter.CreateSettings,
+	config component.Config,
+) (exporter.Metrics, error) {
+	cfg := config.(*Config)
+	a := newAlerting(cfg, settings.Logger)
+	exp, err := exporterhelper.NewMetricsExporter(
+		ctx,
+		settings,
+		config,
+		a.ConsumeMetrics,
+		exporterhelper.WithShutdown(a.Shutdown),
+	)
+	if err != nil {
+		return nil, err
+	}
+	// the dimensions of the rules can match the resource attributes
+	return resourcetotelemetry.WrapMetricsExporter(resourcetotelemetry.Settings{Enabled: true}, exp), nil
+}
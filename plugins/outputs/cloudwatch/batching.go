@@ -0,0 +1,164 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/featuregate"
+)
+
+const (
+	defaultTargetLatency     = 2 * time.Second
+	minAdaptiveDatumsPerCall = 20
+	// adaptiveFlushJitterRatio is the fraction of the flush interval used as
+	// the maximum jitter, so that the agents started at the same time do not
+	// keep flushing at the same time.
+	adaptiveFlushJitterRatio = 10
+)
+
+// adaptiveBatchingGate enables the adaptive batching of all the CloudWatch
+// outputs, to roll it out before it becomes the default.
+var adaptiveBatchingGate = featuregate.GlobalRegistry().MustRegister(
+	"cwagent.cloudwatch.adaptiveBatching",
+	featuregate.StageAlpha,
+	featuregate.WithRegisterDescription("Sizes the PutMetricData batches of the CloudWatch output to the observed latency and throttling, as with batching.adaptive"),
+)
+
+// BatchingConfig configures how the datums are batched into PutMetricData
+// requests.
+type BatchingConfig struct {
+	// Adaptive sizes the batches and limits the in-flight requests based on
+	// the latency and throttling of the PutMetricData requests. The flushes
+	// are also jittered.
+	Adaptive bool `mapstructure:"adaptive,omitempty"`
+	// MaxInFlightRequests is the maximum number of PutMetricData requests
+	// sent concurrently. Defaults to 10.
+	MaxInFlightRequests int `mapstructure:"max_in_flight_requests,omitempty"`
+	// TargetLatency is the PutMetricData latency above which the adaptive
+	// batches are made smaller. Defaults to 2 seconds.
+	TargetLatency time.Duration `mapstructure:"target_latency,omitempty"`
+}
+
+func (c *BatchingConfig) Validate() error {
+	if c.MaxInFlightRequests < 0 {
+		return errors.New("'max_in_flight_requests' must not be negative")
+	}
+	if c.TargetLatency < 0 {
+		return errors.New("'target_latency' must not be negative")
+	}
+	return nil
+}
+
+func (c *BatchingConfig) maxInFlightRequests() int {
+	if c == nil || c.MaxInFlightRequests == 0 {
+		return maxConcurrentPublisher
+	}
+	return c.MaxInFlightRequests
+}
+
+// adaptive is checked when the output starts, so the gate applies from the
+// next start of the agent.
+func (c *BatchingConfig) adaptive() bool {
+	return (c != nil && c.Adaptive) || adaptiveBatchingGate.IsEnabled()
+}
+
+// adaptiveBatcher adjusts the batch size and the number of in-flight requests
+// based on the outcome of each request:
+//   - A throttled request halves the in-flight requests and restores the
+//     batch size to the maximum, so that the same datums take fewer requests.
+//   - A request slower than the target latency shrinks the batch size by a quarter.
+//   - Any other request allows one more in-flight request and, if well under
+//     the target latency, grows the batch size by a tenth of the maximum.
+type adaptiveBatcher struct {
+	maxDatums     int
+	minDatums     int
+	maxInFlight   int
+	targetLatency time.Duration
+
+	mu            sync.Mutex
+	cond          *sync.Cond
+	datums        int
+	inFlightLimit int
+	inFlight      int
+	closed        bool
+}
+
+func newAdaptiveBatcher(maxDatums, maxInFlight int, targetLatency time.Duration) *adaptiveBatcher {
+	if targetLatency == 0 {
+		targetLatency = defaultTargetLatency
+	}
+	b := &adaptiveBatcher{
+		maxDatums:     maxDatums,
+		minDatums:     min(minAdaptiveDatumsPerCall, maxDatums),
+		maxInFlight:   maxInFlight,
+		targetLatency: targetLatency,
+		datums:        maxDatums,
+		inFlightLimit: maxInFlight,
+	}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// batchSize is the number of datums the next batch is limited to.
+func (b *adaptiveBatcher) batchSize() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.datums
+}
+
+// acquire blocks until another request is allowed to be in flight. Returns
+// false if the batcher was closed while waiting.
+func (b *adaptiveBatcher) acquire() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for !b.closed && b.inFlight >= b.inFlightLimit {
+		b.cond.Wait()
+	}
+	if b.closed {
+		return false
+	}
+	b.inFlight++
+	return true
+}
+
+// close releases the requests waiting in acquire.
+func (b *adaptiveBatcher) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.cond.Broadcast()
+}
+
+func (b *adaptiveBatcher) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.inFlight--
+	b.cond.Broadcast()
+}
+
+// observe adjusts the limits with the latency of a request and whether it
+// was throttled.
+func (b *adaptiveBatcher) observe(latency time.Duration, throttled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case throttled:
+		b.inFlightLimit = max(1, b.inFlightLimit/2)
+		b.datums = b.maxDatums
+		log.Printf("D! cloudwatch: throttled, limiting in-flight requests to %v", b.inFlightLimit)
+	case latency > b.targetLatency:
+		b.datums = max(b.minDatums, b.datums*3/4)
+		log.Printf("D! cloudwatch: request took %v, limiting batches to %v datums", latency, b.datums)
+	default:
+		b.inFlightLimit = min(b.maxInFlight, b.inFlightLimit+1)
+		if latency < b.targetLatency/2 {
+			b.datums = min(b.maxDatums, b.datums+max(1, b.maxDatums/10))
+		}
+	}
+	b.cond.Broadcast()
+}
@@ -0,0 +1,110 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/featuregate"
+)
+
+func TestBatchingConfigDefaults(t *testing.T) {
+	var cfg *BatchingConfig
+	assert.False(t, cfg.adaptive())
+	assert.Equal(t, maxConcurrentPublisher, cfg.maxInFlightRequests())
+	cfg = &BatchingConfig{Adaptive: true, MaxInFlightRequests: 3}
+	assert.True(t, cfg.adaptive())
+	assert.Equal(t, 3, cfg.maxInFlightRequests())
+}
+
+func TestAdaptiveBatchingGate(t *testing.T) {
+	require.NoError(t, featuregate.GlobalRegistry().Set(adaptiveBatchingGate.ID(), true))
+	defer func() {
+		require.NoError(t, featuregate.GlobalRegistry().Set(adaptiveBatchingGate.ID(), false))
+	}()
+	var cfg *BatchingConfig
+	assert.True(t, cfg.adaptive())
+	assert.True(t, (&BatchingConfig{}).adaptive())
+}
+
+func TestAdaptiveBatcherObserve(t *testing.T) {
+	b := newAdaptiveBatcher(1000, 8, time.Second)
+	assert.Equal(t, 1000, b.batchSize())
+
+	// slow requests shrink the batches down to the minimum
+	b.observe(2*time.Second, false)
+	assert.Equal(t, 750, b.batchSize())
+	for i := 0; i < 20; i++ {
+		b.observe(2*time.Second, false)
+	}
+	assert.Equal(t, minAdaptiveDatumsPerCall, b.batchSize())
+
+	// fast requests grow the batches back
+	b.observe(100*time.Millisecond, false)
+	assert.Equal(t, minAdaptiveDatumsPerCall+100, b.batchSize())
+	// requests close to the target latency keep the batch size
+	b.observe(800*time.Millisecond, false)
+	assert.Equal(t, minAdaptiveDatumsPerCall+100, b.batchSize())
+
+	// throttles use the largest batches with fewer requests in flight
+	b.observe(100*time.Millisecond, true)
+	assert.Equal(t, 1000, b.batchSize())
+	assert.Equal(t, 4, b.inFlightLimit)
+	b.observe(100*time.Millisecond, true)
+	b.observe(100*time.Millisecond, true)
+	b.observe(100*time.Millisecond, true)
+	assert.Equal(t, 1, b.inFlightLimit)
+	for i := 0; i < 20; i++ {
+		b.observe(100*time.Millisecond, false)
+	}
+	assert.Equal(t, 8, b.inFlightLimit)
+	assert.Equal(t, 1000, b.batchSize())
+}
+
+func TestAdaptiveBatcherInFlight(t *testing.T) {
+	b := newAdaptiveBatcher(1000, 2, time.Second)
+	b.observe(0, true)
+	assert.Equal(t, 1, b.inFlightLimit)
+	b.acquire()
+
+	acquired := make(chan struct{})
+	go func() {
+		b.acquire()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("acquired more than the in-flight limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+	b.release()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("not acquired after release")
+	}
+	b.release()
+}
+
+func TestAdaptiveBatcherClose(t *testing.T) {
+	b := newAdaptiveBatcher(1000, 1, time.Second)
+	assert.True(t, b.acquire())
+
+	acquired := make(chan bool)
+	go func() {
+		acquired <- b.acquire()
+	}()
+	b.close()
+	select {
+	case ok := <-acquired:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("still waiting after close")
+	}
+	b.release()
+	assert.False(t, b.acquire())
+}
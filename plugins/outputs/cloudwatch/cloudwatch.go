@@ -5,6 +5,7 @@ package cloudwatch
 
 import (
 	"context"
+	"errors"
 	"log"
 	"reflect"
 	"sort"
@@ -14,11 +15,13 @@ import (
 	"github.com/amazon-contributing/opentelemetry-collector-contrib/extension/awsmiddleware"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/models"
 	"github.com/influxdata/telegraf/plugins/outputs"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
@@ -26,8 +29,12 @@ import (
 
 	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
 	"github.com/aws/amazon-cloudwatch-agent/handlers"
+	"github.com/aws/amazon-cloudwatch-agent/internal/failover"
 	"github.com/aws/amazon-cloudwatch-agent/internal/publisher"
 	"github.com/aws/amazon-cloudwatch-agent/internal/retryer"
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+	"github.com/aws/amazon-cloudwatch-agent/internal/shutdown"
+	"github.com/aws/amazon-cloudwatch-agent/internal/sigv4a"
 	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatch"
@@ -45,6 +52,7 @@ const (
 	highResolutionTagKey                  = "aws:StorageResolution"
 	defaultRetryCount                     = 5 // this is the retry count, the total attempts would be retry count + 1 at most.
 	backoffRetryBase                      = 200 * time.Millisecond
+	defaultShutdownTimeout                = 15 * time.Second // used when the collector does not set a shutdown deadline
 	MaxDimensions                         = 30
 )
 
@@ -53,6 +61,8 @@ const (
 	opPutMetricData = "PutMetricData"
 )
 
+var errShuttingDown = errors.New("cloudwatch output is shutting down")
+
 type CloudWatch struct {
 	config *Config
 	logger *zap.Logger
@@ -72,6 +82,14 @@ type CloudWatch struct {
 	aggregatorShutdownChan chan struct{}
 	aggregatorWaitGroup    sync.WaitGroup
 	lastRequestBytes       int
+	sanitizer              *sanitizer
+	batcher                *adaptiveBatcher
+	// requestCtx is canceled at the end of the shutdown to abort the
+	// requests still in flight.
+	requestCtx     context.Context
+	cancelRequests context.CancelFunc
+	// unregisterBufferGauge stops reporting the utilization of metricChan.
+	unregisterBufferGauge func()
 }
 
 // Compile time interface check.
@@ -84,17 +102,18 @@ func (c *CloudWatch) Capabilities() consumer.Capabilities {
 func (c *CloudWatch) Start(_ context.Context, host component.Host) error {
 	c.publisher, _ = publisher.NewPublisher(
 		publisher.NewNonBlockingFifoQueue(metricChanBufferSize),
-		maxConcurrentPublisher,
+		int64(c.config.Batching.maxInFlightRequests()),
 		2*time.Second,
 		c.WriteToCloudWatch)
 	credentialConfig := &configaws.CredentialConfig{
-		Region:    c.config.Region,
-		AccessKey: c.config.AccessKey,
-		SecretKey: c.config.SecretKey,
-		RoleARN:   c.config.RoleARN,
-		Profile:   c.config.Profile,
-		Filename:  c.config.SharedCredentialFilename,
-		Token:     c.config.Token,
+		Region:       c.config.Region,
+		AccessKey:    c.config.AccessKey,
+		SecretKey:    c.config.SecretKey,
+		RoleARN:      c.config.RoleARN,
+		Profile:      c.config.Profile,
+		Filename:     c.config.SharedCredentialFilename,
+		Token:        c.config.Token,
+		ProxyService: configaws.ProxyServiceMetrics,
 	}
 	configProvider := credentialConfig.Credentials()
 	logger := models.NewLogger("outputs", "cloudwatch", "")
@@ -108,9 +127,27 @@ func (c *CloudWatch) Start(_ context.Context, host component.Host) error {
 			Logger:   configaws.SDKLogger{},
 		})
 	svc.Handlers.Build.PushBackNamed(handlers.NewRequestCompressionHandler([]string{opPutLogEvents, opPutMetricData}))
+	if len(c.config.SigV4aRegionSet) > 0 {
+		sigv4a.NewSigner(c.config.SigV4aRegionSet).Configure(&svc.Handlers)
+	}
+	if len(c.config.FailoverEndpoints) > 0 {
+		endpoints, err := failover.New("cloudwatch", append([]string{svc.Endpoint}, c.config.FailoverEndpoints...))
+		if err != nil {
+			return err
+		}
+		endpoints.Configure(&svc.Handlers)
+	}
 	if c.config.MiddlewareID != nil {
 		awsmiddleware.TryConfigure(c.logger, host, *c.config.MiddlewareID, awsmiddleware.SDKv1(&svc.Handlers))
 	}
+	if c.config.Sanitization != nil {
+		namespace, err := sanitizeNamespace(c.config.Namespace, c.config.Sanitization.InvalidCharacters)
+		if err != nil {
+			return err
+		}
+		c.config.Namespace = namespace
+		c.sanitizer = newSanitizer(c.config.Sanitization)
+	}
 	//Format unique roll up list
 	c.config.RollupDimensions = GetUniqueRollupList(c.config.RollupDimensions)
 	c.svc = svc
@@ -121,33 +158,74 @@ func (c *CloudWatch) Start(_ context.Context, host component.Host) error {
 
 func (c *CloudWatch) startRoutines() {
 	setNewDistributionFunc(c.config.MaxValuesPerDatum)
-	c.metricChan = make(chan *aggregationDatum, metricChanBufferSize)
+	setExponentialHistogramMaxBuckets(c.config.ExponentialHistogramMaxBuckets, c.config.MaxValuesPerDatum)
+	c.metricChan = make(chan *aggregationDatum, c.config.queueSize())
+	c.unregisterBufferGauge = selftelemetry.RegisterGauge(selftelemetry.MetricBufferUtilization, "cloudwatch", func() float64 {
+		return float64(len(c.metricChan)) / float64(cap(c.metricChan)) * 100
+	})
 	c.datumBatchChan = make(chan map[string][]*cloudwatch.MetricDatum, datumBatchChanBufferSize)
 	c.shutdownChan = make(chan struct{})
+	c.requestCtx, c.cancelRequests = context.WithCancel(context.Background())
 	c.aggregatorShutdownChan = make(chan struct{})
 	c.aggregator = NewAggregator(c.metricChan, c.aggregatorShutdownChan, &c.aggregatorWaitGroup)
+	if c.config.Batching.adaptive() {
+		c.batcher = newAdaptiveBatcher(c.config.MaxDatumsPerCall, c.config.Batching.maxInFlightRequests(), c.config.Batching.TargetLatency)
+	}
 	perRequestConstSize := overallConstPerRequestSize + len(c.config.Namespace) + namespaceOverheads
 	c.metricDatumBatch = newMetricDatumBatch(c.config.MaxDatumsPerCall, perRequestConstSize)
 	go c.pushMetricDatum()
 	go c.publish()
 }
 
+// Shutdown waits for the queued metrics to be published until the context is
+// done. Retries stop once shutting down, and the requests still in flight when
+// the context is done are canceled.
 func (c *CloudWatch) Shutdown(ctx context.Context) error {
 	log.Println("D! Stopping the CloudWatch output plugin")
-	for i := 0; i < 5; i++ {
-		if len(c.metricChan) == 0 && len(c.datumBatchChan) == 0 {
-			break
-		} else {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultShutdownTimeout)
+		defer cancel()
+	}
+	stopCancel := context.AfterFunc(ctx, c.cancelRequests)
+	defer stopCancel()
+	watchdog := shutdown.NewWatchdog("cloudwatch")
+	_ = watchdog.Stop(ctx, "draining metrics", func() {
+		for i := 0; i < 5; i++ {
+			if len(c.metricChan) == 0 && len(c.datumBatchChan) == 0 {
+				break
+			}
 			log.Printf("D! CloudWatch Close, %vth time to sleep since there is still some metric data remaining to publish.", i)
-			time.Sleep(time.Second)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
 		}
-	}
+	})
 	if metricChanLen, datumBatchChanLen := len(c.metricChan), len(c.datumBatchChan); metricChanLen != 0 || datumBatchChanLen != 0 {
 		log.Printf("D! CloudWatch Close, metricChan length = %v, datumBatchChan length = %v.", metricChanLen, datumBatchChanLen)
 	}
 	close(c.shutdownChan)
-	c.publisher.Close()
+	if c.unregisterBufferGauge != nil {
+		c.unregisterBufferGauge()
+	}
+	if c.batcher != nil {
+		// the queued batches are still published while the publisher drains,
+		// the requests waiting for an in-flight slot are only released once
+		// the context is done
+		stopBatcher := context.AfterFunc(ctx, c.batcher.close)
+		defer stopBatcher()
+	}
+	_ = watchdog.Stop(ctx, "publisher", c.publisher.Close)
+	if c.batcher != nil {
+		c.batcher.close()
+	}
+	c.cancelRequests()
 	c.retryer.Stop()
+	if c.sanitizer != nil {
+		log.Printf("I! cloudwatch: sanitization actions taken: %v", c.sanitizer.Counts())
+	}
 	log.Println("D! Stopped the CloudWatch output plugin")
 	return nil
 }
@@ -156,11 +234,20 @@ func (c *CloudWatch) Shutdown(ctx context.Context) error {
 // The actual publishing will occur in a long running goroutine.
 // This method can block when publishing is backed up.
 func (c *CloudWatch) ConsumeMetrics(ctx context.Context, metrics pmetric.Metrics) error {
+	var err error
+	if c.sanitizer != nil {
+		// Violations of policies in the error mode have already been removed,
+		// so the rest of the metrics are still published.
+		if err = c.sanitizer.Sanitize(metrics); err != nil {
+			log.Printf("E! cloudwatch: sanitization failed: %v", err)
+			err = consumererror.NewPermanent(err)
+		}
+	}
 	datums := ConvertOtelMetrics(metrics)
 	for _, d := range datums {
 		c.aggregator.AddMetric(d)
 	}
-	return nil
+	return err
 }
 
 // pushMetricDatum groups datums into batches for efficient API calls.
@@ -192,10 +279,13 @@ func (c *CloudWatch) pushMetricDatum() {
 				c.metricDatumBatch.Partition[entityStr] = append(c.metricDatumBatch.Partition[entityStr], datums[i])
 				c.metricDatumBatch.Size += payload(datums[i])
 				c.metricDatumBatch.Count++
+				if isHighResolution(datums[i]) && c.metricDatumBatch.highResolutionSince.IsZero() {
+					c.metricDatumBatch.highResolutionSince = time.Now()
+				}
 				if c.metricDatumBatch.isFull() {
 					// if batch is full
 					c.datumBatchChan <- c.metricDatumBatch.Partition
-					c.metricDatumBatch.clear()
+					c.clearMetricDatumBatch()
 				}
 			}
 		case <-ticker.C:
@@ -203,7 +293,7 @@ func (c *CloudWatch) pushMetricDatum() {
 				// if the time to publish comes
 				c.lastRequestBytes = c.metricDatumBatch.Size
 				c.datumBatchChan <- c.metricDatumBatch.Partition
-				c.metricDatumBatch.clear()
+				c.clearMetricDatumBatch()
 			}
 		case <-c.shutdownChan:
 			return
@@ -218,6 +308,11 @@ type MetricDatumBatch struct {
 	Size                int
 	Count               int
 	perRequestConstSize int
+	// flushJitter delays the flush of the batch past the flush interval.
+	flushJitter time.Duration
+	// highResolutionSince is when the first high-resolution datum was added
+	// to the batch, zero if it has none.
+	highResolutionSince time.Time
 }
 
 func newMetricDatumBatch(maxDatumsPerCall, perRequestConstSize int) *MetricDatumBatch {
@@ -236,14 +331,48 @@ func (b *MetricDatumBatch) clear() {
 	b.BeginTime = time.Now()
 	b.Size = b.perRequestConstSize
 	b.Count = 0
+	b.highResolutionSince = time.Time{}
 }
 
 func (b *MetricDatumBatch) isFull() bool {
 	return b.Count >= b.MaxDatumsPerCall || b.Size >= bottomLinePayloadSizeInBytesToPublish
 }
 
+// clearMetricDatumBatch starts a new batch. With adaptive batching, the new
+// batch is limited to the current adaptive batch size and its flush is jittered.
+func (c *CloudWatch) clearMetricDatumBatch() {
+	c.metricDatumBatch.clear()
+	if c.batcher != nil {
+		c.metricDatumBatch.MaxDatumsPerCall = c.batcher.batchSize()
+		c.metricDatumBatch.flushJitter = c.flushJitter(c.config.ForceFlushInterval)
+	}
+}
+
+// timeToPublish returns whether the batch is due. The batches holding
+// high-resolution datums are due after the high-resolution flush interval if
+// it is set, so that they are not delayed by the force flush interval.
 func (c *CloudWatch) timeToPublish(b *MetricDatumBatch) bool {
-	return len(b.Partition) > 0 && time.Since(b.BeginTime) >= c.config.ForceFlushInterval
+	if len(b.Partition) == 0 {
+		return false
+	}
+	if c.config.HighResolutionFlushInterval > 0 && !b.highResolutionSince.IsZero() && time.Since(b.highResolutionSince) >= c.config.HighResolutionFlushInterval {
+		return true
+	}
+	return time.Since(b.BeginTime) >= c.config.ForceFlushInterval+b.flushJitter
+}
+
+func isHighResolution(datum *cloudwatch.MetricDatum) bool {
+	return datum.StorageResolution != nil && *datum.StorageResolution == 1
+}
+
+// flushJitter returns a random fraction of the interval with adaptive
+// batching, zero otherwise.
+func (c *CloudWatch) flushJitter(interval time.Duration) time.Duration {
+	maxJitter := interval / adaptiveFlushJitterRatio
+	if c.batcher == nil || maxJitter <= 0 {
+		return 0
+	}
+	return publishJitter(maxJitter)
 }
 
 // getFirstPushMs returns the time at which the first upload should occur.
@@ -304,7 +433,7 @@ func (c *CloudWatch) publish() {
 			if !bufferFullOccurred {
 				currentInterval = c.config.ForceFlushInterval
 			}
-			nextMs += currentInterval.Milliseconds()
+			nextMs += currentInterval.Milliseconds() + c.flushJitter(currentInterval).Milliseconds()
 		}
 
 		if shouldPublish {
@@ -340,16 +469,19 @@ func (c *CloudWatch) pushMetricDatumBatch() {
 }
 
 // backoffSleep sleeps some amount of time based on number of retries done.
-func (c *CloudWatch) backoffSleep() {
-	d := 1 * time.Minute
-	if c.retries <= defaultRetryCount {
-		d = backoffRetryBase * time.Duration(1<<c.retries)
-	}
+// Returns false without waiting the full time if shutting down.
+func (c *CloudWatch) backoffSleep() bool {
+	d := c.config.Retry.backoff(c.retries)
 	d = (d / 2) + publishJitter(d/2)
 	log.Printf("W! cloudwatch: %v retries, going to sleep %v ms before retrying.",
 		c.retries, d.Milliseconds())
 	c.retries++
-	time.Sleep(d)
+	select {
+	case <-c.shutdownChan:
+		return false
+	case <-time.After(d):
+		return true
+	}
 }
 
 func createEntityMetricData(entityToMetrics map[string][]*cloudwatch.MetricDatum) []*cloudwatch.EntityMetricData {
@@ -384,35 +516,81 @@ func (c *CloudWatch) WriteToCloudWatch(req interface{}) {
 	}
 
 	var err error
-	for i := 0; i < defaultRetryCount; i++ {
-		_, err = c.svc.PutMetricData(params)
-		if err != nil {
-			awsErr, ok := err.(awserr.Error)
-			if !ok {
-				log.Printf("E! cloudwatch: Cannot cast PutMetricData error %v into awserr.Error.", err)
-				c.backoffSleep()
-				continue
-			}
+	start := time.Now()
+	for i := 0; i < c.config.Retry.attempts(); i++ {
+		err = c.putMetricData(params)
+		if err == nil {
+			c.retries = 0
+			selftelemetry.Add(selftelemetry.MetricExportedBatches, "cloudwatch", 1)
+			break
+		}
+		if errors.Is(err, errShuttingDown) {
+			break
+		}
+		awsErr, ok := err.(awserr.Error)
+		if !ok {
+			log.Printf("E! cloudwatch: Cannot cast PutMetricData error %v into awserr.Error.", err)
+		} else {
 			switch awsErr.Code() {
 			case cloudwatch.ErrCodeLimitExceededFault, cloudwatch.ErrCodeInternalServiceFault:
 				log.Printf("W! cloudwatch: PutMetricData, error: %s, message: %s",
 					awsErr.Code(),
 					awsErr.Message())
-				c.backoffSleep()
-				continue
-
 			default:
 				log.Printf("E! cloudwatch: code: %s, message: %s, original error: %+v", awsErr.Code(), awsErr.Message(), awsErr.OrigErr())
-				c.backoffSleep()
 			}
-		} else {
-			c.retries = 0
 		}
-		break
+		if c.config.Retry.expired(start) {
+			log.Printf("W! cloudwatch: not retrying PutMetricData after %v, the maximum elapsed time.", time.Since(start))
+			break
+		}
+		if !c.backoffSleep() {
+			log.Printf("W! cloudwatch: shutting down, not retrying PutMetricData after %v attempts.", i+1)
+			break
+		}
+		if ok && awsErr.Code() != cloudwatch.ErrCodeLimitExceededFault && awsErr.Code() != cloudwatch.ErrCodeInternalServiceFault {
+			break
+		}
 	}
 	if err != nil {
 		log.Println("E! cloudwatch: WriteToCloudWatch failure, err: ", err)
+		selftelemetry.Add(selftelemetry.MetricDroppedEvents, "cloudwatch", float64(countMetricDatums(params)))
+	}
+}
+
+func countMetricDatums(params *cloudwatch.PutMetricDataInput) int {
+	count := len(params.MetricData)
+	for _, entityMetricData := range params.EntityMetricData {
+		count += len(entityMetricData.MetricData)
+	}
+	return count
+}
+
+// putMetricData sends the request, waiting for an in-flight slot and feeding
+// the outcome back to the adaptive batcher if batching is adaptive.
+func (c *CloudWatch) putMetricData(params *cloudwatch.PutMetricDataInput) error {
+	if c.batcher == nil {
+		_, err := c.svc.PutMetricDataWithContext(c.requestCtx, params)
+		return err
+	}
+	if !c.batcher.acquire() {
+		return errShuttingDown
+	}
+	defer c.batcher.release()
+	start := time.Now()
+	_, err := c.svc.PutMetricDataWithContext(c.requestCtx, params)
+	c.batcher.observe(time.Since(start), isThrottle(err))
+	return err
+}
+
+func isThrottle(err error) bool {
+	if err == nil {
+		return false
+	}
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == cloudwatch.ErrCodeLimitExceededFault {
+		return true
 	}
+	return request.IsErrorThrottle(err)
 }
 
 // BuildMetricDatum may just return the datum as-is.
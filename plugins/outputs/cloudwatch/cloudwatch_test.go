@@ -5,26 +5,33 @@ package cloudwatch
 
 import (
 	"context"
+	"errors"
 	"log"
 	"math"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/amazon-contributing/opentelemetry-collector-contrib/extension/awsmiddleware"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/metric"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/harness"
 	"github.com/aws/amazon-cloudwatch-agent/internal/publisher"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatch"
@@ -342,6 +349,29 @@ func TestIsFlushable(t *testing.T) {
 	cw.Shutdown(context.Background())
 }
 
+func TestHighResolutionFlush(t *testing.T) {
+	cw := &CloudWatch{config: &Config{ForceFlushInterval: time.Minute, HighResolutionFlushInterval: time.Second}}
+	batch := newMetricDatumBatch(defaultMaxDatumsPerCall, 0)
+	datum := &cloudwatch.MetricDatum{MetricName: aws.String("test_metric"), Value: aws.Float64(1)}
+	batch.Partition[""] = []*cloudwatch.MetricDatum{datum}
+	assert.False(t, isHighResolution(datum))
+	assert.False(t, cw.timeToPublish(batch))
+
+	datum = &cloudwatch.MetricDatum{MetricName: aws.String("test_metric"), Value: aws.Float64(1), StorageResolution: aws.Int64(1)}
+	assert.True(t, isHighResolution(datum))
+	batch.highResolutionSince = time.Now()
+	assert.False(t, cw.timeToPublish(batch))
+	batch.highResolutionSince = time.Now().Add(-time.Second)
+	assert.True(t, cw.timeToPublish(batch))
+
+	// disabled by default
+	cw.config.HighResolutionFlushInterval = 0
+	assert.False(t, cw.timeToPublish(batch))
+
+	batch.clear()
+	assert.True(t, batch.highResolutionSince.IsZero())
+}
+
 func TestIsFull(t *testing.T) {
 	assert := assert.New(t)
 	perRequestConstSize := overallConstPerRequestSize + len("CWAgent") + namespaceOverheads
@@ -383,9 +413,79 @@ func (svc *mockCloudWatchClient) PutMetricData(
 	return args.Get(0).(*cloudwatch.PutMetricDataOutput), args.Error(1)
 }
 
+func (svc *mockCloudWatchClient) PutMetricDataWithContext(
+	_ aws.Context,
+	input *cloudwatch.PutMetricDataInput,
+	_ ...request.Option,
+) (*cloudwatch.PutMetricDataOutput, error) {
+	return svc.PutMetricData(input)
+}
+
+// blockingCloudWatchClient holds every request until its context is canceled,
+// or fails it right away with err if set.
+type blockingCloudWatchClient struct {
+	cloudwatchiface.CloudWatchAPI
+	err    error
+	called chan struct{}
+	once   sync.Once
+}
+
+func (svc *blockingCloudWatchClient) PutMetricDataWithContext(
+	ctx aws.Context,
+	_ *cloudwatch.PutMetricDataInput,
+	_ ...request.Option,
+) (*cloudwatch.PutMetricDataOutput, error) {
+	svc.once.Do(func() { close(svc.called) })
+	if svc.err != nil {
+		return nil, svc.err
+	}
+	<-ctx.Done()
+	return nil, awserr.New(request.CanceledErrorCode, "request context canceled", ctx.Err())
+}
+
+// gatedCloudWatchClient holds every request until release is closed, and
+// counts the datums published.
+type gatedCloudWatchClient struct {
+	cloudwatchiface.CloudWatchAPI
+	release chan struct{}
+
+	mu     sync.Mutex
+	datums int
+}
+
+func (svc *gatedCloudWatchClient) PutMetricDataWithContext(
+	ctx aws.Context,
+	input *cloudwatch.PutMetricDataInput,
+	_ ...request.Option,
+) (*cloudwatch.PutMetricDataOutput, error) {
+	select {
+	case <-svc.release:
+	case <-ctx.Done():
+		return nil, awserr.New(request.CanceledErrorCode, "request context canceled", ctx.Err())
+	}
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.datums += len(input.MetricData)
+	return &cloudwatch.PutMetricDataOutput{}, nil
+}
+
+func (svc *gatedCloudWatchClient) published() int {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	return svc.datums
+}
+
 func newCloudWatchClient(
 	svc cloudwatchiface.CloudWatchAPI,
 	forceFlushInterval time.Duration,
+) *CloudWatch {
+	return newCloudWatchClientWithBatching(svc, forceFlushInterval, nil)
+}
+
+func newCloudWatchClientWithBatching(
+	svc cloudwatchiface.CloudWatchAPI,
+	forceFlushInterval time.Duration,
+	batching *BatchingConfig,
 ) *CloudWatch {
 	cloudwatch := &CloudWatch{
 		svc: svc,
@@ -393,6 +493,7 @@ func newCloudWatchClient(
 			ForceFlushInterval: forceFlushInterval,
 			MaxDatumsPerCall:   defaultMaxDatumsPerCall,
 			MaxValuesPerDatum:  defaultMaxValuesPerDatum,
+			Batching:           batching,
 		},
 	}
 	cloudwatch.startRoutines()
@@ -471,6 +572,28 @@ func TestWriteError(t *testing.T) {
 	cw.Shutdown(ctx)
 }
 
+func TestWriteErrorWithRetry(t *testing.T) {
+	svc := new(mockCloudWatchClient)
+	res := cloudwatch.PutMetricDataOutput{}
+	svc.On("PutMetricData", mock.Anything).Return(
+		&res,
+		awserr.New(cloudwatch.ErrCodeLimitExceededFault, "", nil))
+	cw := newCloudWatchClient(svc, time.Second)
+	maxRetries := 1
+	cw.config.Retry = &RetryConfig{MaxRetries: &maxRetries, InitialInterval: 10 * time.Millisecond}
+	cw.publisher, _ = publisher.NewPublisher(
+		publisher.NewNonBlockingFifoQueue(10),
+		10,
+		2*time.Second,
+		cw.WriteToCloudWatch)
+	metrics := createTestMetrics(20, 1, 10, "")
+	ctx := context.Background()
+	cw.ConsumeMetrics(ctx, metrics)
+	time.Sleep(2 * time.Second)
+	assert.True(t, svc.AssertNumberOfCalls(t, "PutMetricData", 2))
+	cw.Shutdown(ctx)
+}
+
 // TestPublish verifies metric batches do not get pushed immediately when
 // batch-buffer is full.
 func TestPublish(t *testing.T) {
@@ -548,7 +671,7 @@ func TestMiddleware(t *testing.T) {
 }
 
 func TestBackoffRetries(t *testing.T) {
-	c := &CloudWatch{}
+	c := &CloudWatch{config: &Config{}}
 	sleeps := []time.Duration{
 		time.Millisecond * 200,
 		time.Millisecond * 400,
@@ -700,3 +823,143 @@ func TestWriteToCloudWatchEntity(t *testing.T) {
 
 	assert.Equal(t, expectedPMDInput, input)
 }
+
+func TestAdaptiveBatching(t *testing.T) {
+	svc := new(mockCloudWatchClient)
+	res := cloudwatch.PutMetricDataOutput{}
+	throttleErr := awserr.New("Throttling", "Rate exceeded", nil)
+	svc.On("PutMetricData", mock.Anything).Return(&res, throttleErr).Once()
+	svc.On("PutMetricData", mock.Anything).Return(&res, nil)
+	assert.Zero(t, newCloudWatchClient(svc, time.Minute).flushJitter(time.Minute))
+	cw := newCloudWatchClientWithBatching(svc, time.Minute, &BatchingConfig{
+		Adaptive:            true,
+		MaxInFlightRequests: 4,
+		TargetLatency:       time.Second,
+	})
+	require.NotNil(t, cw.batcher)
+
+	params := &cloudwatch.PutMetricDataInput{Namespace: aws.String("CWAgent")}
+	assert.Error(t, cw.putMetricData(params))
+	assert.Equal(t, 2, cw.batcher.inFlightLimit)
+	assert.NoError(t, cw.putMetricData(params))
+	assert.Equal(t, 3, cw.batcher.inFlightLimit)
+	assert.Zero(t, cw.batcher.inFlight)
+
+	// the batch is owned by the pushMetricDatum routine, the next one is
+	// cleared with the adaptive batch size and flush jitter
+	cw.batcher.observe(2*time.Second, false)
+	assert.Equal(t, 750, cw.batcher.batchSize())
+	assert.Less(t, cw.flushJitter(time.Minute), 6*time.Second)
+}
+
+func TestShutdownWithPendingRequests(t *testing.T) {
+	testCases := map[string]struct {
+		err             error
+		shutdownTimeout time.Duration
+	}{
+		"HangingRequest": {
+			shutdownTimeout: 500 * time.Millisecond,
+		},
+		"ThrottledRequest": {
+			err:             awserr.New(cloudwatch.ErrCodeLimitExceededFault, "", nil),
+			shutdownTimeout: 10 * time.Second,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			svc := &blockingCloudWatchClient{err: testCase.err, called: make(chan struct{})}
+			cw := newCloudWatchClient(svc, 100*time.Millisecond)
+			cw.publisher, _ = publisher.NewPublisher(
+				publisher.NewNonBlockingFifoQueue(10), 10, time.Second,
+				cw.WriteToCloudWatch)
+			assert.NoError(t, cw.ConsumeMetrics(context.Background(), createTestMetrics(1, 1, 1, "Count")))
+			select {
+			case <-svc.called:
+			case <-time.After(5 * time.Second):
+				t.Fatal("PutMetricData not called")
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), testCase.shutdownTimeout)
+			defer cancel()
+			start := time.Now()
+			assert.NoError(t, cw.Shutdown(ctx))
+			assert.Less(t, time.Since(start), 3*time.Second)
+			assert.Error(t, cw.requestCtx.Err())
+		})
+	}
+}
+
+func TestShutdownPublishesQueuedBatches(t *testing.T) {
+	svc := &gatedCloudWatchClient{release: make(chan struct{})}
+	cw := newCloudWatchClientWithBatching(svc, time.Minute, &BatchingConfig{Adaptive: true})
+	require.NotNil(t, cw.batcher)
+	// a single publisher keeps the other batches queued behind the held request
+	cw.publisher, _ = publisher.NewPublisher(
+		publisher.NewNonBlockingFifoQueue(10), 1, 5*time.Second,
+		cw.WriteToCloudWatch)
+	for i := 0; i < 3; i++ {
+		cw.publisher.Publish(map[string][]*cloudwatch.MetricDatum{
+			"": {{MetricName: aws.String("metric"), Value: aws.Float64(1)}},
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, cw.Shutdown(ctx))
+	}()
+	// let the shutdown start before the requests are answered
+	time.Sleep(200 * time.Millisecond)
+	close(svc.release)
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Shutdown did not return")
+	}
+	assert.Equal(t, 3, svc.published())
+}
+
+func TestIsThrottle(t *testing.T) {
+	assert.False(t, isThrottle(nil))
+	assert.False(t, isThrottle(errors.New("other")))
+	assert.False(t, isThrottle(awserr.New(cloudwatch.ErrCodeInvalidParameterValueException, "", nil)))
+	assert.True(t, isThrottle(awserr.New(cloudwatch.ErrCodeLimitExceededFault, "", nil)))
+	assert.True(t, isThrottle(awserr.New("Throttling", "", nil)))
+}
+
+func TestPublishToBackend(t *testing.T) {
+	harness.Offline(t)
+	backend := harness.NewCloudWatch(t)
+	cw := &CloudWatch{
+		config: &Config{
+			Region:             harness.Region,
+			Namespace:          "CWAgent",
+			ForceFlushInterval: 100 * time.Millisecond,
+			MaxDatumsPerCall:   defaultMaxDatumsPerCall,
+			MaxValuesPerDatum:  defaultMaxValuesPerDatum,
+			EndpointOverride:   backend.URL,
+		},
+		logger: zap.NewNop(),
+	}
+	ctx := context.Background()
+	require.NoError(t, cw.Start(ctx, nil))
+
+	metrics := pmetric.NewMetrics()
+	m := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("mem_used_percent")
+	m.SetUnit("Percent")
+	dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	dp.SetDoubleValue(42)
+	dp.Attributes().PutStr("host", "localhost")
+	require.NoError(t, cw.ConsumeMetrics(ctx, metrics))
+	require.Eventually(t, func() bool {
+		return len(backend.Requests("PutMetricData")) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	require.NoError(t, cw.Shutdown(ctx))
+
+	backend.Scrub("Timestamp")
+	harness.AssertGolden(t, filepath.Join("testdata", "publish.golden"), backend.Dump("PutMetricData"))
+}
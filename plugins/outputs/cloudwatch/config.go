@@ -5,10 +5,13 @@ package cloudwatch
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
 	"go.opentelemetry.io/collector/component"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/failover"
 )
 
 // Config represent a configuration for the CloudWatch metrics exporter.
@@ -27,6 +30,28 @@ type Config struct {
 	RollupDimensions         [][]string      `mapstructure:"rollup_dimensions,omitempty"`
 	DropOriginalConfigs      map[string]bool `mapstructure:"drop_original_metrics,omitempty"`
 	Namespace                string          `mapstructure:"namespace"`
+	// FailoverEndpoints are used in order when the endpoint override, or the
+	// regional endpoint if it is not set, is unhealthy.
+	FailoverEndpoints []string `mapstructure:"failover_endpoints,omitempty"`
+	// SigV4aRegionSet makes the requests signed with SigV4a for the regions
+	// instead of SigV4, so the endpoints of all the regions accept them.
+	SigV4aRegionSet []string `mapstructure:"sigv4a_region_set,omitempty"`
+	// Sanitization is the optional set of policies applied to the metrics
+	// before they are converted into MetricDatums.
+	Sanitization *SanitizationConfig `mapstructure:"sanitization,omitempty"`
+	// Batching is the optional tuning of the PutMetricData batches.
+	Batching *BatchingConfig `mapstructure:"batching,omitempty"`
+	// Retry is the optional retry policy of the PutMetricData requests.
+	Retry *RetryConfig `mapstructure:"retry,omitempty"`
+	// QueueSize is the number of metrics buffered before they are aggregated
+	// and batched. Defaults to 10000.
+	QueueSize int `mapstructure:"queue_size,omitempty"`
+	// ExponentialHistogramMaxBuckets is the maximum number of values sent for
+	// an exponential histogram. Adjacent buckets are merged to stay within it.
+	ExponentialHistogramMaxBuckets int `mapstructure:"exponential_histogram_max_buckets,omitempty"`
+	// HighResolutionFlushInterval flushes the batches holding high-resolution
+	// datums sooner than the ForceFlushInterval. Disabled if zero.
+	HighResolutionFlushInterval time.Duration `mapstructure:"high_resolution_flush_interval,omitempty"`
 
 	// ResourceToTelemetrySettings is the option for converting resource
 	// attributes to telemetry attributes.
@@ -50,5 +75,50 @@ func (c *Config) Validate() error {
 	if c.ForceFlushInterval < time.Millisecond {
 		return errors.New("'force_flush_interval' must be at least 1 millisecond")
 	}
+	if c.HighResolutionFlushInterval < 0 {
+		return errors.New("'high_resolution_flush_interval' must not be negative")
+	}
+	if c.QueueSize < 0 {
+		return errors.New("'queue_size' must not be negative")
+	}
+	if c.ExponentialHistogramMaxBuckets < 0 || c.ExponentialHistogramMaxBuckets > defaultMaxValuesPerDatum {
+		return fmt.Errorf("'exponential_histogram_max_buckets' must be between 0 and %d", defaultMaxValuesPerDatum)
+	}
+	for _, endpoint := range c.FailoverEndpoints {
+		if _, err := failover.Parse(endpoint); err != nil {
+			return fmt.Errorf("'failover_endpoints': %w", err)
+		}
+	}
+	for _, region := range c.SigV4aRegionSet {
+		if region == "" {
+			return errors.New("'sigv4a_region_set' must not contain empty regions")
+		}
+	}
+	if c.Sanitization != nil {
+		if err := c.Sanitization.Validate(); err != nil {
+			return err
+		}
+		if _, err := sanitizeNamespace(c.Namespace, c.Sanitization.InvalidCharacters); err != nil {
+			return err
+		}
+	}
+	if c.Batching != nil {
+		if err := c.Batching.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Retry != nil {
+		if err := c.Retry.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// queueSize returns the size of the metric buffer, defaulting if it is not set.
+func (c *Config) queueSize() int {
+	if c.QueueSize == 0 {
+		return metricChanBufferSize
+	}
+	return c.QueueSize
+}
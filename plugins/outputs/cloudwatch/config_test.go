@@ -111,3 +111,90 @@ func TestConfigDropOriginConfigs(t *testing.T) {
 	assert.True(t, drop["cpu_usage"])
 	assert.True(t, drop["foo_bar"])
 }
+
+func TestConfigBatching(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	assert.NoError(t, err)
+	factory := NewFactory()
+	factories.Exporters[TypeStr] = factory
+
+	fp := filepath.Join("testdata", "batching.yaml")
+	c, err := otelcoltest.LoadConfigAndValidate(fp, factories)
+	assert.NoError(t, err)
+
+	assert.NotNil(t, c)
+	c2, ok := c.Exporters[component.NewID(TypeStr)].(*Config)
+	assert.True(t, ok)
+	assert.Equal(t, &BatchingConfig{Adaptive: true, MaxInFlightRequests: 4, TargetLatency: 500 * time.Millisecond}, c2.Batching)
+	assert.Equal(t, 5*time.Second, c2.HighResolutionFlushInterval)
+
+	c2.HighResolutionFlushInterval = -time.Second
+	assert.Error(t, c2.Validate())
+	c2.HighResolutionFlushInterval = 0
+	c2.Batching.MaxInFlightRequests = -1
+	assert.Error(t, c2.Validate())
+}
+
+func TestConfigRetry(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	assert.NoError(t, err)
+	factory := NewFactory()
+	factories.Exporters[TypeStr] = factory
+
+	fp := filepath.Join("testdata", "retry.yaml")
+	c, err := otelcoltest.LoadConfigAndValidate(fp, factories)
+	assert.NoError(t, err)
+
+	assert.NotNil(t, c)
+	c2, ok := c.Exporters[component.NewID(TypeStr)].(*Config)
+	assert.True(t, ok)
+	maxRetries := 2
+	assert.Equal(t, &RetryConfig{MaxRetries: &maxRetries, InitialInterval: time.Second, MaxInterval: 30 * time.Second, MaxElapsedTime: 5 * time.Minute}, c2.Retry)
+
+	c2.Retry.InitialInterval = time.Minute
+	assert.Error(t, c2.Validate())
+}
+
+func TestConfigFailover(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	assert.NoError(t, err)
+	factory := NewFactory()
+	factories.Exporters[TypeStr] = factory
+
+	fp := filepath.Join("testdata", "failover.yaml")
+	c, err := otelcoltest.LoadConfigAndValidate(fp, factories)
+	assert.NoError(t, err)
+
+	assert.NotNil(t, c)
+	c2, ok := c.Exporters[component.NewID(TypeStr)].(*Config)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"https://monitoring.us-west-2.amazonaws.com"}, c2.FailoverEndpoints)
+	assert.Equal(t, []string{"us-east-1", "us-west-2"}, c2.SigV4aRegionSet)
+
+	c2.FailoverEndpoints = []string{"monitoring.us-west-2.amazonaws.com"}
+	assert.Error(t, c2.Validate())
+	c2.FailoverEndpoints = nil
+	c2.SigV4aRegionSet = []string{""}
+	assert.Error(t, c2.Validate())
+}
+
+func TestConfigExponentialHistogram(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	assert.NoError(t, err)
+	factory := NewFactory()
+	factories.Exporters[TypeStr] = factory
+
+	fp := filepath.Join("testdata", "exponential_histogram.yaml")
+	c, err := otelcoltest.LoadConfigAndValidate(fp, factories)
+	assert.NoError(t, err)
+
+	assert.NotNil(t, c)
+	c2, ok := c.Exporters[component.NewID(TypeStr)].(*Config)
+	assert.True(t, ok)
+	assert.Equal(t, 100, c2.ExponentialHistogramMaxBuckets)
+
+	c2.ExponentialHistogramMaxBuckets = defaultMaxValuesPerDatum + 1
+	assert.Error(t, c2.Validate())
+	c2.ExponentialHistogramMaxBuckets = -1
+	assert.Error(t, c2.Validate())
+}
@@ -14,6 +14,7 @@ import (
 
 	cloudwatchutil "github.com/aws/amazon-cloudwatch-agent/internal/cloudwatch"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/exponential"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/awsentity/entityattributes"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatch"
 )
@@ -144,6 +145,45 @@ func ConvertOtelHistogramDataPoints(
 	return datums
 }
 
+// ConvertOtelExponentialHistogramDataPoints converts each datapoint in the
+// given slice to an exponential distribution, keeping the buckets of the
+// histogram up to the maximum number of values.
+func ConvertOtelExponentialHistogramDataPoints(
+	dataPoints pmetric.ExponentialHistogramDataPointSlice,
+	name string,
+	unit string,
+	scale float64,
+	entity cloudwatch.Entity,
+) []*aggregationDatum {
+	datums := make([]*aggregationDatum, 0, dataPoints.Len())
+	for i := 0; i < dataPoints.Len(); i++ {
+		dp := dataPoints.At(i)
+		if dp.Count() == 0 {
+			continue
+		}
+		attrs := dp.Attributes()
+		storageResolution := checkHighResolution(&attrs)
+		aggregationInterval := getAggregationInterval(&attrs)
+		dimensions := ConvertOtelDimensions(attrs)
+		ad := aggregationDatum{
+			MetricDatum: cloudwatch.MetricDatum{
+				Dimensions:        dimensions,
+				MetricName:        aws.String(name),
+				Unit:              aws.String(unit),
+				Timestamp:         aws.Time(dp.Timestamp().AsTime()),
+				StorageResolution: aws.Int64(storageResolution),
+			},
+			aggregationInterval: aggregationInterval,
+			entity:              entity,
+		}
+		dist := exponential.NewExponentialDistribution(exponentialHistogramMaxBuckets)
+		dist.ConvertFromOtelExponential(dp, unit, scale)
+		ad.distribution = dist
+		datums = append(datums, &ad)
+	}
+	return datums
+}
+
 // ConvertOtelMetric creates a list of datums from the datapoints in the given
 // metric and returns it. Only supports the metric DataTypes that we plan to use.
 // Intentionally not caching previous values and converting cumulative to delta.
@@ -161,6 +201,8 @@ func ConvertOtelMetric(m pmetric.Metric, entity cloudwatch.Entity) []*aggregatio
 		return ConvertOtelNumberDataPoints(m.Sum().DataPoints(), name, unit, scale, entity)
 	case pmetric.MetricTypeHistogram:
 		return ConvertOtelHistogramDataPoints(m.Histogram().DataPoints(), name, unit, scale, entity)
+	case pmetric.MetricTypeExponentialHistogram:
+		return ConvertOtelExponentialHistogramDataPoints(m.ExponentialHistogram().DataPoints(), name, unit, scale, entity)
 	default:
 		log.Printf("E! cloudwatch: Unsupported type, %s", m.Type())
 	}
@@ -201,6 +201,44 @@ func TestConvertOtelMetrics_Histogram(t *testing.T) {
 	}
 }
 
+func TestConvertOtelMetrics_ExponentialHistogram(t *testing.T) {
+	defer setExponentialHistogramMaxBuckets(0, defaultMaxValuesPerDatum)
+	setExponentialHistogramMaxBuckets(2, defaultMaxValuesPerDatum)
+
+	metrics := pmetric.NewMetrics()
+	m := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("latency")
+	m.SetUnit("min")
+	dps := m.SetEmptyExponentialHistogram().DataPoints()
+	dp := dps.AppendEmpty()
+	dp.Attributes().PutStr("service", "checkout")
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	// buckets (1, 2], (2, 4] and (4, 8] of minutes
+	dp.SetScale(0)
+	dp.Positive().BucketCounts().FromRaw([]uint64{1, 2, 3})
+	dp.SetCount(6)
+	dp.SetSum(30)
+	dp.SetMin(1.5)
+	dp.SetMax(7)
+	// empty data points are skipped
+	dps.AppendEmpty()
+
+	datums := ConvertOtelMetrics(metrics)
+	assert.Len(t, datums, 1)
+	d := datums[0]
+	assert.Equal(t, "latency", *d.MetricName)
+	assert.Equal(t, "Seconds", *d.Unit)
+	assert.Equal(t, []*cloudwatch.Dimension{{Name: aws.String("service"), Value: aws.String("checkout")}}, d.Dimensions)
+	assert.Equal(t, 6.0, d.distribution.SampleCount())
+	assert.InDelta(t, 1800.0, d.distribution.Sum(), 1e-9)
+	assert.InDelta(t, 90.0, d.distribution.Minimum(), 1e-9)
+	assert.InDelta(t, 420.0, d.distribution.Maximum(), 1e-9)
+	// merged into the buckets (1, 4] and (4, 16] to stay within the limit
+	values, counts := d.distribution.ValuesAndCounts()
+	assert.InDeltaSlice(t, []float64{120, 420}, values, 1e-9)
+	assert.Equal(t, []float64{3, 3}, counts)
+}
+
 func TestConvertOtelMetrics_Dimensions(t *testing.T) {
 	for i := 0; i < 100; i++ {
 		// 1 data point per metric, but vary the number dimensions.
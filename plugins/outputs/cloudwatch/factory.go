@@ -9,6 +9,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
@@ -46,8 +47,9 @@ func createMetricsExporter(
 	settings exporter.CreateSettings,
 	config component.Config,
 ) (exporter.Metrics, error) {
+	cfg := config.(*Config)
 	cw := &CloudWatch{
-		config: config.(*Config),
+		config: cfg,
 		logger: settings.Logger,
 	}
 	exp, err := exporterhelper.NewMetricsExporter(
@@ -57,6 +59,8 @@ func createMetricsExporter(
 		cw.ConsumeMetrics,
 		exporterhelper.WithStart(cw.Start),
 		exporterhelper.WithShutdown(cw.Shutdown),
+		// The sanitizer modifies the metrics in place.
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: cfg.Sanitization.enabled()}),
 	)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,74 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultMaxBackoffInterval = time.Minute
+)
+
+// RetryConfig configures how the throttled and failed PutMetricData requests
+// are retried.
+type RetryConfig struct {
+	// MaxRetries is the number of times a failed request is retried. Defaults
+	// to 4, for 5 attempts.
+	MaxRetries *int `mapstructure:"max_retries,omitempty"`
+	// InitialInterval is the wait before the first retry, doubled for each of
+	// the following consecutive retries. Defaults to 200 milliseconds.
+	InitialInterval time.Duration `mapstructure:"initial_interval,omitempty"`
+	// MaxInterval is the upper bound of the wait between retries, used after
+	// 5 consecutive retries. Defaults to 1 minute.
+	MaxInterval time.Duration `mapstructure:"max_interval,omitempty"`
+	// MaxElapsedTime is how long a request is retried for before its datums
+	// are dropped. Zero means no limit.
+	MaxElapsedTime time.Duration `mapstructure:"max_elapsed_time,omitempty"`
+}
+
+func (c *RetryConfig) Validate() error {
+	if c.MaxRetries != nil && *c.MaxRetries < 0 {
+		return errors.New("'max_retries' must not be negative")
+	}
+	if c.InitialInterval < 0 || c.MaxInterval < 0 || c.MaxElapsedTime < 0 {
+		return errors.New("retry intervals must not be negative")
+	}
+	if c.InitialInterval > 0 && c.MaxInterval > 0 && c.InitialInterval > c.MaxInterval {
+		return errors.New("'initial_interval' must not be greater than 'max_interval'")
+	}
+	return nil
+}
+
+// attempts is the number of times a request is sent at most.
+func (c *RetryConfig) attempts() int {
+	if c == nil || c.MaxRetries == nil {
+		return defaultRetryCount
+	}
+	return *c.MaxRetries + 1
+}
+
+// backoff is the wait before the retry following the consecutive retries.
+func (c *RetryConfig) backoff(retries int) time.Duration {
+	initialInterval, maxInterval := backoffRetryBase, defaultMaxBackoffInterval
+	if c != nil && c.InitialInterval > 0 {
+		initialInterval = c.InitialInterval
+	}
+	if c != nil && c.MaxInterval > 0 {
+		maxInterval = c.MaxInterval
+	}
+	if retries <= defaultRetryCount {
+		if d := initialInterval * time.Duration(1<<retries); d < maxInterval {
+			return d
+		}
+	}
+	return maxInterval
+}
+
+// expired returns true if a request first sent at start should not be
+// retried anymore.
+func (c *RetryConfig) expired(start time.Time) bool {
+	return c != nil && c.MaxElapsedTime > 0 && time.Since(start) >= c.MaxElapsedTime
+}
@@ -0,0 +1,44 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryConfigDefaults(t *testing.T) {
+	var c *RetryConfig
+	assert.Equal(t, defaultRetryCount, c.attempts())
+	assert.Equal(t, 200*time.Millisecond, c.backoff(0))
+	assert.Equal(t, 6400*time.Millisecond, c.backoff(5))
+	assert.Equal(t, time.Minute, c.backoff(6))
+	assert.False(t, c.expired(time.Now().Add(-time.Hour)))
+}
+
+func TestRetryConfig(t *testing.T) {
+	maxRetries := 0
+	c := &RetryConfig{
+		MaxRetries:      &maxRetries,
+		InitialInterval: time.Second,
+		MaxInterval:     5 * time.Second,
+		MaxElapsedTime:  time.Minute,
+	}
+	assert.NoError(t, c.Validate())
+	assert.Equal(t, 1, c.attempts())
+	assert.Equal(t, time.Second, c.backoff(0))
+	assert.Equal(t, 4*time.Second, c.backoff(2))
+	assert.Equal(t, 5*time.Second, c.backoff(3))
+	assert.Equal(t, 5*time.Second, c.backoff(10))
+	assert.False(t, c.expired(time.Now()))
+	assert.True(t, c.expired(time.Now().Add(-time.Minute)))
+
+	maxRetries = -1
+	assert.Error(t, c.Validate())
+	maxRetries = 3
+	c.MaxElapsedTime = -time.Second
+	assert.Error(t, c.Validate())
+}
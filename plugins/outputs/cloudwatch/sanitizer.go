@@ -0,0 +1,410 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/multierr"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/awsentity/entityattributes"
+)
+
+// SanitizationMode determines what happens to a datum that violates one of the
+// CloudWatch PutMetricData constraints.
+type SanitizationMode string
+
+const (
+	// SanitizationModeNone leaves the datum untouched. This is the default and
+	// matches the behavior of the exporter before sanitization was introduced.
+	SanitizationModeNone SanitizationMode = ""
+	// SanitizationModeError drops the datum and surfaces an error to the pipeline.
+	SanitizationModeError SanitizationMode = "error"
+	// SanitizationModeFix repairs the datum so that it is accepted by the API.
+	SanitizationModeFix SanitizationMode = "fix"
+	// SanitizationModeDrop silently drops the datum.
+	SanitizationModeDrop SanitizationMode = "drop"
+)
+
+const (
+	maxMetricNameLength     = 255
+	maxDimensionNameLength  = 255
+	maxDimensionValueLength = 1024
+	maxNamespaceLength      = 255
+	replacementCharacter    = '_'
+)
+
+// Names of the sanitization policies. Used as keys for the action counters.
+const (
+	policyInvalidCharacters = "invalid_characters"
+	policyDimensionLimit    = "dimension_limit"
+	policyNameLength        = "name_length"
+)
+
+// SanitizationConfig configures the sanitization layer that runs before the
+// metrics are converted into MetricDatums.
+type SanitizationConfig struct {
+	// InvalidCharacters applies to metric names, dimension names, and dimension
+	// values containing non-printable or non-ASCII characters.
+	InvalidCharacters SanitizationMode `mapstructure:"invalid_characters,omitempty"`
+	// DimensionLimit applies to data points with more than MaxDimensions dimensions.
+	DimensionLimit SanitizationMode `mapstructure:"dimension_limit,omitempty"`
+	// NameLength applies to metric names, dimension names, and dimension values
+	// that exceed the API length limits.
+	NameLength SanitizationMode `mapstructure:"name_length,omitempty"`
+	// DimensionPriority is the ordered list of dimension names to keep when the
+	// dimension limit is enforced with the "fix" mode. The "host" dimension is
+	// always kept first, followed by these names, followed by the remaining
+	// dimensions in alphabetical order.
+	DimensionPriority []string `mapstructure:"dimension_priority,omitempty"`
+}
+
+// Validate checks that each of the modes is supported.
+func (c *SanitizationConfig) Validate() error {
+	var errs error
+	for policy, mode := range map[string]SanitizationMode{
+		policyInvalidCharacters: c.InvalidCharacters,
+		policyDimensionLimit:    c.DimensionLimit,
+		policyNameLength:        c.NameLength,
+	} {
+		switch mode {
+		case SanitizationModeNone, SanitizationModeError, SanitizationModeFix, SanitizationModeDrop:
+		default:
+			errs = multierr.Append(errs, fmt.Errorf("invalid sanitization mode for %q: %q", policy, mode))
+		}
+	}
+	return errs
+}
+
+func (c *SanitizationConfig) enabled() bool {
+	return c != nil && (c.InvalidCharacters != SanitizationModeNone ||
+		c.DimensionLimit != SanitizationModeNone ||
+		c.NameLength != SanitizationModeNone)
+}
+
+// sanitizationCounter tracks the number of times each action was taken for a policy.
+type sanitizationCounter struct {
+	fixed   atomic.Int64
+	dropped atomic.Int64
+	errored atomic.Int64
+}
+
+// record counts the action and adds it to the sanitization metrics of the agent.
+func (c *sanitizationCounter) record(mode SanitizationMode) {
+	switch mode {
+	case SanitizationModeFix:
+		c.fixed.Add(1)
+		selftelemetry.Add(selftelemetry.MetricSanitizationFixes, "cloudwatch", 1)
+	case SanitizationModeDrop:
+		c.dropped.Add(1)
+		selftelemetry.Add(selftelemetry.MetricSanitizationRejections, "cloudwatch", 1)
+	case SanitizationModeError:
+		c.errored.Add(1)
+		selftelemetry.Add(selftelemetry.MetricSanitizationRejections, "cloudwatch", 1)
+	}
+}
+
+// sanitizer enforces the PutMetricData constraints on the data points before they
+// are converted. All the counters are safe for concurrent use.
+type sanitizer struct {
+	cfg      *SanitizationConfig
+	priority map[string]int
+	counters map[string]*sanitizationCounter
+}
+
+func newSanitizer(cfg *SanitizationConfig) *sanitizer {
+	if !cfg.enabled() {
+		return nil
+	}
+	priority := make(map[string]int, len(cfg.DimensionPriority))
+	for i, name := range cfg.DimensionPriority {
+		if _, ok := priority[name]; !ok {
+			priority[name] = i
+		}
+	}
+	return &sanitizer{
+		cfg:      cfg,
+		priority: priority,
+		counters: map[string]*sanitizationCounter{
+			policyInvalidCharacters: {},
+			policyDimensionLimit:    {},
+			policyNameLength:        {},
+		},
+	}
+}
+
+// Counts returns a snapshot of the action counters keyed by "<policy>.<action>".
+func (s *sanitizer) Counts() map[string]int64 {
+	counts := make(map[string]int64, len(s.counters)*3)
+	for policy, counter := range s.counters {
+		counts[policy+".fixed"] = counter.fixed.Load()
+		counts[policy+".dropped"] = counter.dropped.Load()
+		counts[policy+".errored"] = counter.errored.Load()
+	}
+	return counts
+}
+
+// Sanitize applies the configured policies in place. Data points (or entire
+// metrics if the name is invalid) that cannot be fixed are removed. Returns an
+// error describing every violation for policies in the "error" mode.
+func (s *sanitizer) Sanitize(md pmetric.Metrics) error {
+	var errs error
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			sms.At(j).Metrics().RemoveIf(func(m pmetric.Metric) bool {
+				keep, err := s.sanitizeMetric(m)
+				errs = multierr.Append(errs, err)
+				return !keep
+			})
+		}
+	}
+	return errs
+}
+
+// sanitizeMetric returns false if the metric should be removed.
+func (s *sanitizer) sanitizeMetric(m pmetric.Metric) (bool, error) {
+	name, keep, err := s.sanitizeString(m.Name(), maxMetricNameLength, isValidNameRune, "metric name")
+	if !keep {
+		return false, err
+	}
+	m.SetName(name)
+	dataPointFunc := func(attrs pcommon.Map) bool {
+		ok, dpErr := s.sanitizeAttributes(m.Name(), attrs)
+		err = multierr.Append(err, dpErr)
+		return !ok
+	}
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		m.Gauge().DataPoints().RemoveIf(func(dp pmetric.NumberDataPoint) bool {
+			return dataPointFunc(dp.Attributes())
+		})
+	case pmetric.MetricTypeSum:
+		m.Sum().DataPoints().RemoveIf(func(dp pmetric.NumberDataPoint) bool {
+			return dataPointFunc(dp.Attributes())
+		})
+	case pmetric.MetricTypeHistogram:
+		m.Histogram().DataPoints().RemoveIf(func(dp pmetric.HistogramDataPoint) bool {
+			return dataPointFunc(dp.Attributes())
+		})
+	case pmetric.MetricTypeExponentialHistogram:
+		m.ExponentialHistogram().DataPoints().RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool {
+			return dataPointFunc(dp.Attributes())
+		})
+	case pmetric.MetricTypeSummary:
+		m.Summary().DataPoints().RemoveIf(func(dp pmetric.SummaryDataPoint) bool {
+			return dataPointFunc(dp.Attributes())
+		})
+	}
+	return true, err
+}
+
+// sanitizeAttributes returns false if the data point should be removed.
+func (s *sanitizer) sanitizeAttributes(metricName string, attrs pcommon.Map) (bool, error) {
+	var errs error
+	replacements := map[string]string{}
+	var removals []string
+	dropped := false
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		if isSpecialAttribute(k) {
+			return true
+		}
+		key, keep, err := s.sanitizeString(k, maxDimensionNameLength, isValidNameRune, "dimension name")
+		errs = multierr.Append(errs, err)
+		if !keep {
+			dropped = true
+			return false
+		}
+		value, keep, err := s.sanitizeString(v.AsString(), maxDimensionValueLength, isValidValueRune, "dimension value")
+		errs = multierr.Append(errs, err)
+		if !keep {
+			dropped = true
+			return false
+		}
+		if key != k {
+			removals = append(removals, k)
+		}
+		if key != k || value != v.AsString() {
+			replacements[key] = value
+		}
+		return true
+	})
+	if dropped {
+		return false, wrapMetricError(metricName, errs)
+	}
+	for _, k := range removals {
+		attrs.Remove(k)
+	}
+	for k, v := range replacements {
+		attrs.PutStr(k, v)
+	}
+	if ok, err := s.enforceDimensionLimit(attrs); !ok {
+		return false, wrapMetricError(metricName, multierr.Append(errs, err))
+	}
+	return true, wrapMetricError(metricName, errs)
+}
+
+// enforceDimensionLimit keeps the highest priority dimensions if there are more
+// than MaxDimensions. The priority order is "host", then the configured dimension
+// priority, then alphabetical.
+func (s *sanitizer) enforceDimensionLimit(attrs pcommon.Map) (bool, error) {
+	var keys []string
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		if !isSpecialAttribute(k) && v.AsString() != "" {
+			keys = append(keys, k)
+		}
+		return true
+	})
+	if len(keys) <= MaxDimensions {
+		return true, nil
+	}
+	mode := s.cfg.DimensionLimit
+	s.counters[policyDimensionLimit].record(mode)
+	switch mode {
+	case SanitizationModeFix:
+		sort.Slice(keys, func(i, j int) bool {
+			return s.lessDimension(keys[i], keys[j])
+		})
+		for _, k := range keys[MaxDimensions:] {
+			attrs.Remove(k)
+		}
+		return true, nil
+	case SanitizationModeDrop:
+		return false, nil
+	case SanitizationModeError:
+		return false, fmt.Errorf("%d dimensions exceeds the limit of %d", len(keys), MaxDimensions)
+	}
+	return true, nil
+}
+
+func (s *sanitizer) lessDimension(a, b string) bool {
+	if a == "host" || b == "host" {
+		return a == "host"
+	}
+	pa, aOk := s.priority[a]
+	pb, bOk := s.priority[b]
+	switch {
+	case aOk && bOk:
+		return pa < pb
+	case aOk != bOk:
+		return aOk
+	}
+	return a < b
+}
+
+// sanitizeString applies the invalid character and length policies to the
+// string. Returns false if the owner of the string should be removed.
+func (s *sanitizer) sanitizeString(str string, maxLength int, isValid func(rune) bool, kind string) (string, bool, error) {
+	if strings.IndexFunc(str, func(r rune) bool { return !isValid(r) }) != -1 {
+		mode := s.cfg.InvalidCharacters
+		s.counters[policyInvalidCharacters].record(mode)
+		switch mode {
+		case SanitizationModeFix:
+			str = strings.Map(func(r rune) rune {
+				if isValid(r) {
+					return r
+				}
+				return replacementCharacter
+			}, str)
+		case SanitizationModeDrop:
+			return str, false, nil
+		case SanitizationModeError:
+			return str, false, fmt.Errorf("%s %q contains invalid characters", kind, str)
+		}
+	}
+	if len(str) > maxLength {
+		mode := s.cfg.NameLength
+		s.counters[policyNameLength].record(mode)
+		switch mode {
+		case SanitizationModeFix:
+			str = truncate(str, maxLength)
+		case SanitizationModeDrop:
+			return str, false, nil
+		case SanitizationModeError:
+			return str, false, fmt.Errorf("%s %q exceeds the maximum length of %d", kind, str, maxLength)
+		}
+	}
+	return str, true, nil
+}
+
+// truncate shortens the string to at most maxLength bytes without splitting a
+// multibyte character.
+func truncate(str string, maxLength int) string {
+	if len(str) <= maxLength {
+		return str
+	}
+	for maxLength > 0 && !utf8.RuneStart(str[maxLength]) {
+		maxLength--
+	}
+	return str[:maxLength]
+}
+
+func wrapMetricError(metricName string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("metric %q: %w", metricName, err)
+}
+
+// isSpecialAttribute returns true for attributes that are removed by the
+// exporter before the dimensions are built.
+func isSpecialAttribute(k string) bool {
+	return k == highResolutionTagKey || k == aggregationIntervalTagKey ||
+		strings.HasPrefix(k, entityattributes.AWSEntityPrefix)
+}
+
+// isValidNameRune is used for metric and dimension names, which must be
+// printable ASCII.
+func isValidNameRune(r rune) bool {
+	return r >= 0x20 && r < 0x7f
+}
+
+// isValidValueRune is used for dimension values, which can contain any
+// printable character.
+func isValidValueRune(r rune) bool {
+	return r >= 0x20 && r != 0x7f && r != utf8.RuneError
+}
+
+var errInvalidNamespace = errors.New("namespace must only contain alphanumeric characters, periods, hyphens, underscores, forward slashes, hash signs, colons, and spaces")
+
+// isValidNamespaceRune matches the characters allowed by the PutMetricData API.
+func isValidNamespaceRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+		strings.ContainsRune(".-_/#: ", r)
+}
+
+// sanitizeNamespace validates the namespace. The invalid characters policy
+// determines whether an invalid namespace is fixed or rejected. Namespaces
+// cannot be dropped, so the "drop" mode is treated the same as "error".
+func sanitizeNamespace(namespace string, mode SanitizationMode) (string, error) {
+	if strings.IndexFunc(namespace, func(r rune) bool { return !isValidNamespaceRune(r) }) == -1 &&
+		len(namespace) <= maxNamespaceLength {
+		return namespace, nil
+	}
+	switch mode {
+	case SanitizationModeFix:
+		fixed := strings.Map(func(r rune) rune {
+			if isValidNamespaceRune(r) {
+				return r
+			}
+			return replacementCharacter
+		}, namespace)
+		fixed = truncate(fixed, maxNamespaceLength)
+		log.Printf("W! cloudwatch: sanitized namespace %q to %q", namespace, fixed)
+		return fixed, nil
+	case SanitizationModeError, SanitizationModeDrop:
+		return namespace, fmt.Errorf("invalid namespace %q: %w", namespace, errInvalidNamespace)
+	}
+	return namespace, nil
+}
@@ -0,0 +1,199 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+func newSanitizerTestMetrics(name string, attrs map[string]string) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName(name)
+	dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetDoubleValue(1)
+	for k, v := range attrs {
+		dp.Attributes().PutStr(k, v)
+	}
+	return md
+}
+
+func firstAttributes(t *testing.T, md pmetric.Metrics) pcommon.Map {
+	t.Helper()
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	require.Equal(t, 1, metrics.At(0).Gauge().DataPoints().Len())
+	return metrics.At(0).Gauge().DataPoints().At(0).Attributes()
+}
+
+func TestSanitizerDisabled(t *testing.T) {
+	assert.Nil(t, newSanitizer(nil))
+	assert.Nil(t, newSanitizer(&SanitizationConfig{DimensionPriority: []string{"a"}}))
+}
+
+func TestSanitizerInvalidCharacters(t *testing.T) {
+	testCases := map[string]struct {
+		mode        SanitizationMode
+		wantCount   int
+		wantAttrs   map[string]any
+		wantName    string
+		wantErr     bool
+		wantCounter string
+		wantActions int64
+	}{
+		"Fix": {
+			mode:        SanitizationModeFix,
+			wantCount:   1,
+			wantName:    "bad_name",
+			wantAttrs:   map[string]any{"dim_key": "value_"},
+			wantCounter: "invalid_characters.fixed",
+			wantActions: 3,
+		},
+		"Drop": {
+			mode:        SanitizationModeDrop,
+			wantCount:   0,
+			wantCounter: "invalid_characters.dropped",
+			wantActions: 1,
+		},
+		"Error": {
+			mode:        SanitizationModeError,
+			wantCount:   0,
+			wantErr:     true,
+			wantCounter: "invalid_characters.errored",
+			wantActions: 1,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s := newSanitizer(&SanitizationConfig{InvalidCharacters: testCase.mode})
+			md := newSanitizerTestMetrics("bad\tname", map[string]string{"dim\nkey": "value\x00"})
+			err := s.Sanitize(md)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.wantCount, md.DataPointCount())
+			if testCase.wantCount > 0 {
+				assert.Equal(t, testCase.wantName, md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+				assert.Equal(t, testCase.wantAttrs, firstAttributes(t, md).AsRaw())
+			}
+			assert.Equal(t, testCase.wantActions, s.Counts()[testCase.wantCounter])
+		})
+	}
+}
+
+func TestSanitizerNameLength(t *testing.T) {
+	longName := strings.Repeat("a", maxMetricNameLength+10)
+	longValue := strings.Repeat("ü", maxDimensionValueLength)
+
+	s := newSanitizer(&SanitizationConfig{NameLength: SanitizationModeFix})
+	md := newSanitizerTestMetrics(longName, map[string]string{"key": longValue})
+	require.NoError(t, s.Sanitize(md))
+	assert.Len(t, md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name(), maxMetricNameLength)
+	got, ok := firstAttributes(t, md).Get("key")
+	require.True(t, ok)
+	assert.Len(t, got.Str(), maxDimensionValueLength)
+	assert.True(t, strings.HasPrefix(longValue, got.Str()))
+	assert.Equal(t, int64(2), s.Counts()["name_length.fixed"])
+
+	s = newSanitizer(&SanitizationConfig{NameLength: SanitizationModeDrop})
+	md = newSanitizerTestMetrics("name", map[string]string{"key": longValue})
+	require.NoError(t, s.Sanitize(md))
+	assert.Equal(t, 0, md.DataPointCount())
+	assert.Equal(t, int64(1), s.Counts()["name_length.dropped"])
+}
+
+func TestSanitizerDimensionLimit(t *testing.T) {
+	attrs := map[string]string{"host": "h", "zzz": "priority", highResolutionTagKey: "true"}
+	for i := 0; i < MaxDimensions+5; i++ {
+		attrs["key"+strconv.Itoa(i)] = "value"
+	}
+
+	s := newSanitizer(&SanitizationConfig{
+		DimensionLimit:    SanitizationModeFix,
+		DimensionPriority: []string{"zzz"},
+	})
+	md := newSanitizerTestMetrics("name", attrs)
+	require.NoError(t, s.Sanitize(md))
+	got := firstAttributes(t, md)
+	// the special attribute is not counted as a dimension
+	assert.Equal(t, MaxDimensions+1, got.Len())
+	for _, k := range []string{"host", "zzz", highResolutionTagKey, "key0"} {
+		_, ok := got.Get(k)
+		assert.True(t, ok, k)
+	}
+	assert.Equal(t, int64(1), s.Counts()["dimension_limit.fixed"])
+
+	s = newSanitizer(&SanitizationConfig{DimensionLimit: SanitizationModeError})
+	md = newSanitizerTestMetrics("name", attrs)
+	assert.Error(t, s.Sanitize(md))
+	assert.Equal(t, 0, md.DataPointCount())
+}
+
+func sanitizationSample(name string) float64 {
+	for _, sample := range selftelemetry.Default.Collect() {
+		if sample.Name == name && sample.Component == "cloudwatch" {
+			return sample.Value
+		}
+	}
+	return 0
+}
+
+func TestSanitizerDataPointTypes(t *testing.T) {
+	fixes := sanitizationSample(selftelemetry.MetricSanitizationFixes)
+	rejections := sanitizationSample(selftelemetry.MetricSanitizationRejections)
+
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("latency")
+	histogram.SetEmptyExponentialHistogram().DataPoints().AppendEmpty().Attributes().PutStr("key", "bad\x00")
+	histogram.ExponentialHistogram().DataPoints().AppendEmpty().Attributes().PutStr("key", "good")
+	summary := metrics.AppendEmpty()
+	summary.SetName("duration")
+	summary.SetEmptySummary().DataPoints().AppendEmpty().Attributes().PutStr("key", "bad\x00")
+
+	s := newSanitizer(&SanitizationConfig{InvalidCharacters: SanitizationModeDrop, NameLength: SanitizationModeFix})
+	require.NoError(t, s.Sanitize(md))
+	assert.Equal(t, 1, histogram.ExponentialHistogram().DataPoints().Len())
+	assert.Equal(t, 0, summary.Summary().DataPoints().Len())
+	assert.Equal(t, int64(2), s.Counts()["invalid_characters.dropped"])
+
+	md = newSanitizerTestMetrics(strings.Repeat("a", maxMetricNameLength+1), nil)
+	require.NoError(t, s.Sanitize(md))
+	assert.Equal(t, fixes+1, sanitizationSample(selftelemetry.MetricSanitizationFixes))
+	assert.Equal(t, rejections+2, sanitizationSample(selftelemetry.MetricSanitizationRejections))
+}
+
+func TestSanitizeNamespace(t *testing.T) {
+	got, err := sanitizeNamespace("Valid/Name_space:1", SanitizationModeError)
+	assert.NoError(t, err)
+	assert.Equal(t, "Valid/Name_space:1", got)
+
+	got, err = sanitizeNamespace("bad$namespace", SanitizationModeFix)
+	assert.NoError(t, err)
+	assert.Equal(t, "bad_namespace", got)
+
+	_, err = sanitizeNamespace("bad$namespace", SanitizationModeDrop)
+	assert.ErrorIs(t, err, errInvalidNamespace)
+
+	got, err = sanitizeNamespace("bad$namespace", SanitizationModeNone)
+	assert.NoError(t, err)
+	assert.Equal(t, "bad$namespace", got)
+}
+
+func TestSanitizationConfigValidate(t *testing.T) {
+	assert.NoError(t, (&SanitizationConfig{InvalidCharacters: SanitizationModeFix}).Validate())
+	assert.Error(t, (&SanitizationConfig{DimensionLimit: "truncate"}).Validate())
+}
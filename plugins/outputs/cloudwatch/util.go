@@ -9,9 +9,11 @@ import (
 	"math/rand"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/exponential"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/regular"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/seh1"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatch"
@@ -61,11 +63,16 @@ const (
 	*/
 )
 
-// Set seed once.
-var seededRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+// Set seed once. The source is shared by the flush and publisher routines.
+var (
+	seededRand   = rand.New(rand.NewSource(time.Now().UnixNano()))
+	seededRandMu sync.Mutex
+)
 
 // publishJitter returns a random duration between 0 and the given publishInterval.
 func publishJitter(publishInterval time.Duration) time.Duration {
+	seededRandMu.Lock()
+	defer seededRandMu.Unlock()
 	jitter := seededRand.Int63n(int64(publishInterval))
 	return time.Duration(jitter)
 }
@@ -78,6 +85,19 @@ func setNewDistributionFunc(maxValuesPerDatumLimit int) {
 	}
 }
 
+// exponentialHistogramMaxBuckets is the maximum number of values of the
+// distributions converted from the exponential histograms.
+var exponentialHistogramMaxBuckets = defaultMaxValuesPerDatum
+
+// setExponentialHistogramMaxBuckets uses the configured maximum, or the limit
+// of the values per datum, whichever is lower.
+func setExponentialHistogramMaxBuckets(maxBuckets int, maxValuesPerDatumLimit int) {
+	if maxBuckets <= 0 {
+		maxBuckets = defaultMaxValuesPerDatum
+	}
+	exponentialHistogramMaxBuckets = min(maxBuckets, maxValuesPerDatumLimit)
+}
+
 func resize(dist distribution.Distribution, listMaxSize int) (distList []distribution.Distribution) {
 	var ok bool
 	// If this is SEH1 distribution, it has already considered the list max size.
@@ -85,6 +105,11 @@ func resize(dist distribution.Distribution, listMaxSize int) (distList []distrib
 		distList = append(distList, dist)
 		return
 	}
+	// The exponential distribution merges its buckets to stay within the list max size.
+	if _, ok = dist.(*exponential.ExponentialDistribution); ok {
+		distList = append(distList, dist)
+		return
+	}
 	var regularDist *regular.RegularDistribution
 	if regularDist, ok = dist.(*regular.RegularDistribution); !ok {
 		log.Printf("E! The distribution type %T is not supported for resizing.", dist)
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/exponential"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/regular"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/seh1"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatch"
@@ -101,6 +102,29 @@ func TestResize(t *testing.T) {
 	assert.Equal(t, float64(7), sum)
 }
 
+func TestResizeExponential(t *testing.T) {
+	dist := exponential.NewExponentialDistribution(2)
+	for _, value := range []float64{1, 2, 3, 4} {
+		assert.NoError(t, dist.AddEntry(value, 1))
+	}
+	// the buckets are merged instead of split into more distributions
+	distList := resize(dist, 2)
+	assert.Equal(t, []distribution.Distribution{dist}, distList)
+	assert.Equal(t, 2, distList[0].Size())
+}
+
+func TestSetExponentialHistogramMaxBuckets(t *testing.T) {
+	defer setExponentialHistogramMaxBuckets(0, defaultMaxValuesPerDatum)
+	setExponentialHistogramMaxBuckets(0, defaultMaxValuesPerDatum)
+	assert.Equal(t, defaultMaxValuesPerDatum, exponentialHistogramMaxBuckets)
+	setExponentialHistogramMaxBuckets(0, maxValuesPerDatum)
+	assert.Equal(t, defaultMaxValuesPerDatum, exponentialHistogramMaxBuckets)
+	setExponentialHistogramMaxBuckets(50, defaultMaxValuesPerDatum)
+	assert.Equal(t, 50, exponentialHistogramMaxBuckets)
+	setExponentialHistogramMaxBuckets(100, 20)
+	assert.Equal(t, 20, exponentialHistogramMaxBuckets)
+}
+
 func TestPayload_ValuesAndCounts(t *testing.T) {
 	datum := new(cloudwatch.MetricDatum)
 	datum.SetCounts(aws.Float64Slice([]float64{1, 2, 3}))
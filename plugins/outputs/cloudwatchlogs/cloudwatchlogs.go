@@ -4,6 +4,7 @@
 package cloudwatchlogs
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"regexp"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/amazon-contributing/opentelemetry-collector-contrib/extension/awsmiddleware"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/plugins/outputs"
 	"go.uber.org/zap"
@@ -24,7 +26,12 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/useragent"
 	"github.com/aws/amazon-cloudwatch-agent/handlers"
 	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/internal/clockskew"
+	"github.com/aws/amazon-cloudwatch-agent/internal/compression"
+	"github.com/aws/amazon-cloudwatch-agent/internal/failover"
 	"github.com/aws/amazon-cloudwatch-agent/internal/retryer"
+	"github.com/aws/amazon-cloudwatch-agent/internal/shutdown"
+	"github.com/aws/amazon-cloudwatch-agent/internal/sigv4a"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/cloudwatchlogs/internal/pusher"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
@@ -44,12 +51,36 @@ const (
 	metricRetryTimeout = 2 * time.Minute
 
 	attributesInFields = "attributesInFields"
+
+	compressionNone = "none"
+
+	// shutdownGracePeriod is the time the pushers get to flush their last batch before the requests in flight are
+	// canceled. The pushers are abandoned if they have not returned shutdownAbortPeriod after that.
+	shutdownGracePeriod = 5 * time.Second
+	shutdownAbortPeriod = 5 * time.Second
+
+	// logGroupSettingsInterval is how often the tags and the KMS keys changed outside the agent are re-applied to
+	// the log groups.
+	logGroupSettingsInterval = time.Hour
 )
 
 var (
 	containerInsightsRegexp = regexp.MustCompile("^/aws/.*containerinsights/.*/(performance|prometheus)$")
 )
 
+// credentialKey is the role and region a destination publishes with. The sources can override
+// the ones of the output to publish to another account or region.
+type credentialKey struct {
+	roleARN string
+	region  string
+}
+
+// destKey identifies a destination. The same log group can exist in several accounts.
+type destKey struct {
+	target      pusher.Target
+	credentials credentialKey
+}
+
 type CloudWatchLogs struct {
 	Region           string `toml:"region"`
 	RegionType       string `toml:"region_type"`
@@ -61,6 +92,21 @@ type CloudWatchLogs struct {
 	Profile          string `toml:"profile"`
 	Filename         string `toml:"shared_credential_file"`
 	Token            string `toml:"token"`
+	// FailoverEndpoints are used in order when the endpoint override, or the
+	// regional endpoint if it is not set, is unhealthy.
+	FailoverEndpoints []string `toml:"failover_endpoints"`
+	// SigV4aRegionSet makes the requests signed with SigV4a for the regions
+	// instead of SigV4, so the endpoints of all the regions accept them.
+	SigV4aRegionSet []string `toml:"sigv4a_region_set"`
+	// Compression is the codec the PutLogEvents requests are compressed with,
+	// or none. Defaults to gzip, which zstd falls back to if it is rejected.
+	Compression string `toml:"compression"`
+	// ClockSkewCorrection adjusts the timestamps of the events by the skew of
+	// the host clock measured on the responses, once it is above the
+	// ClockSkewThreshold. A warning is logged when it is above the threshold
+	// either way.
+	ClockSkewCorrection bool              `toml:"clock_skew_correction"`
+	ClockSkewThreshold  internal.Duration `toml:"clock_skew_threshold"`
 
 	//log group and stream names
 	LogStreamName string `toml:"log_stream_name"`
@@ -70,17 +116,37 @@ type CloudWatchLogs struct {
 	RetentionInDays int `toml:"retention_in_days"`
 	Concurrency     int `toml:"concurrency"`
 
+	// RateLimitQuota is the PutLogEvents requests per second of all the log groups of an account and region,
+	// and RateLimit the requests per second of each log group without its own rate in RateLimits. Zero is
+	// unlimited.
+	RateLimitQuota float64            `toml:"rate_limit_quota"`
+	RateLimit      float64            `toml:"rate_limit"`
+	RateLimits     map[string]float64 `toml:"rate_limits"`
+
 	ForceFlushInterval internal.Duration `toml:"force_flush_interval"` // unit is second
 
 	Log telegraf.Logger `toml:"-"`
 
 	pusherStopChan  chan struct{}
 	pusherWaitGroup sync.WaitGroup
-	cwDests         map[pusher.Target]*cwDest
+	requestCtx      context.Context
+	cancelRequests  context.CancelFunc
+	cwDests         map[destKey]*cwDest
+	cwDestsMu       sync.Mutex
 	workerPool      pusher.WorkerPool
-	targetManager   pusher.TargetManager
 	once            sync.Once
-	middleware      awsmiddleware.Middleware
+	// sessions and targetManagers are shared by the destinations with the same credentials.
+	sessions       map[credentialKey]client.ConfigProvider
+	targetManagers map[credentialKey]pusher.TargetManager
+	rateLimiters   map[credentialKey]*pusher.RateLimiter
+	middleware     awsmiddleware.Middleware
+	// compressions negotiate the codec with the endpoint of each region.
+	compressions map[string]*handlers.RequestCompression
+	// signer and endpoints are shared by the clients for the region of the output.
+	signer    *sigv4a.Signer
+	endpoints *failover.Endpoints
+	// clock measures the skew of the host clock on the responses of all the clients.
+	clock *clockskew.Detector
 }
 
 func (c *CloudWatchLogs) Connect() error {
@@ -89,14 +155,25 @@ func (c *CloudWatchLogs) Connect() error {
 
 func (c *CloudWatchLogs) Close() error {
 	close(c.pusherStopChan)
-	c.pusherWaitGroup.Wait()
+	if c.cancelRequests != nil {
+		// requests still in flight after the grace period are canceled
+		time.AfterFunc(shutdownGracePeriod, c.cancelRequests)
+		defer c.cancelRequests()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownGracePeriod+shutdownAbortPeriod)
+	defer cancel()
+	watchdog := shutdown.NewWatchdog("cloudwatchlogs")
+	_ = watchdog.Wait(ctx, "pushers", &c.pusherWaitGroup)
 
+	c.cwDestsMu.Lock()
 	for _, d := range c.cwDests {
 		d.Stop()
 	}
+	c.cwDestsMu.Unlock()
 	if c.workerPool != nil {
-		c.workerPool.Stop()
+		_ = watchdog.Stop(ctx, "worker pool", c.workerPool.Stop)
 	}
+	c.clock.Stop()
 
 	return nil
 }
@@ -129,49 +206,147 @@ func (c *CloudWatchLogs) CreateDest(group, stream string, retention int, logGrou
 }
 
 func (c *CloudWatchLogs) getDest(t pusher.Target, logSrc logs.LogSrc) *cwDest {
-	if cwd, ok := c.cwDests[t]; ok {
+	// Destinations are also created while publishing events routed to their own log group or stream.
+	c.cwDestsMu.Lock()
+	defer c.cwDestsMu.Unlock()
+	key := destKey{target: t, credentials: c.credentialKey(logSrc)}
+	if cwd, ok := c.cwDests[key]; ok {
 		return cwd
 	}
 
 	logThrottleRetryer := retryer.NewLogThrottleRetryer(c.Log)
-	client := c.createClient(logThrottleRetryer)
+	client := c.createClient(logThrottleRetryer, key.credentials)
 	agent.UsageFlags().SetValue(agent.FlagRegionType, c.RegionType)
 	agent.UsageFlags().SetValue(agent.FlagMode, c.Mode)
 	if containerInsightsRegexp.MatchString(t.Group) {
 		useragent.Get().SetContainerInsightsFlag()
 	}
 	c.once.Do(func() {
+		c.requestCtx, c.cancelRequests = context.WithCancel(context.Background())
 		if c.Concurrency > 0 {
 			c.workerPool = pusher.NewWorkerPool(c.Concurrency)
 		}
-		c.targetManager = pusher.NewTargetManager(c.Log, client)
+		go c.reapplyLogGroupSettings(logGroupSettingsInterval)
 	})
-	p := pusher.NewPusher(c.Log, t, client, c.targetManager, logSrc, c.workerPool, c.ForceFlushInterval.Duration, maxRetryTimeout, c.pusherStopChan, &c.pusherWaitGroup)
-	cwd := &cwDest{pusher: p, retryer: logThrottleRetryer}
-	c.cwDests[t] = cwd
+	if c.targetManagers == nil {
+		c.targetManagers = make(map[credentialKey]pusher.TargetManager)
+	}
+	targetManager, ok := c.targetManagers[key.credentials]
+	if !ok {
+		targetManager = pusher.NewTargetManager(c.Log, client)
+		c.targetManagers[key.credentials] = targetManager
+	}
+	p := pusher.NewPusher(c.requestCtx, c.Log, t, client, targetManager, logSrc, c.workerPool, c.rateLimiter(key.credentials), c.clock, c.ForceFlushInterval.Duration, maxRetryTimeout, c.pusherStopChan, &c.pusherWaitGroup)
+	cwd := &cwDest{pusher: p, retryer: logThrottleRetryer, logSrc: logSrc, parent: c}
+	c.cwDests[key] = cwd
 	return cwd
 }
 
-func (c *CloudWatchLogs) createClient(retryer aws.RequestRetryer) *cloudwatchlogs.CloudWatchLogs {
+// reapplyLogGroupSettings periodically restores the log group settings of every target manager until the output is
+// closed.
+func (c *CloudWatchLogs) reapplyLogGroupSettings(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.pusherStopChan:
+			return
+		case <-ticker.C:
+			c.cwDestsMu.Lock()
+			targetManagers := make([]pusher.TargetManager, 0, len(c.targetManagers))
+			for _, targetManager := range c.targetManagers {
+				targetManagers = append(targetManagers, targetManager)
+			}
+			c.cwDestsMu.Unlock()
+			for _, targetManager := range targetManagers {
+				targetManager.ReapplyLogGroupSettings()
+			}
+		}
+	}
+}
+
+// rateLimiter returns the rate limiter shared by the destinations with the same credentials, since the quota is
+// for the account and region, or nil if the rates are unlimited. Must be called with cwDestsMu held.
+func (c *CloudWatchLogs) rateLimiter(key credentialKey) *pusher.RateLimiter {
+	if limiter, ok := c.rateLimiters[key]; ok {
+		return limiter
+	}
+	limiter := pusher.NewRateLimiter(pusher.RateLimits{
+		Quota:     c.RateLimitQuota,
+		Default:   c.RateLimit,
+		LogGroups: c.RateLimits,
+	})
+	if c.rateLimiters == nil {
+		c.rateLimiters = make(map[credentialKey]*pusher.RateLimiter)
+	}
+	c.rateLimiters[key] = limiter
+	return limiter
+}
+
+// credentialKey returns the role and region used for the source, falling back to the ones of
+// the output.
+func (c *CloudWatchLogs) credentialKey(logSrc logs.LogSrc) credentialKey {
+	key := credentialKey{roleARN: c.RoleARN, region: c.Region}
+	if cp, ok := logSrc.(logs.LogCredentialProvider); ok {
+		if roleARN := cp.RoleARN(); roleARN != "" {
+			key.roleARN = roleARN
+		}
+		if region := cp.Region(); region != "" {
+			key.region = region
+		}
+	}
+	return key
+}
+
+// session returns the cached credential session for the role and region. Must be called with
+// cwDestsMu held.
+func (c *CloudWatchLogs) session(key credentialKey) client.ConfigProvider {
+	if sess, ok := c.sessions[key]; ok {
+		return sess
+	}
 	credentialConfig := &configaws.CredentialConfig{
-		Region:    c.Region,
-		AccessKey: c.AccessKey,
-		SecretKey: c.SecretKey,
-		RoleARN:   c.RoleARN,
-		Profile:   c.Profile,
-		Filename:  c.Filename,
-		Token:     c.Token,
+		Region:       key.region,
+		AccessKey:    c.AccessKey,
+		SecretKey:    c.SecretKey,
+		RoleARN:      key.roleARN,
+		Profile:      c.Profile,
+		Filename:     c.Filename,
+		Token:        c.Token,
+		ProxyService: configaws.ProxyServiceLogs,
+	}
+	sess := credentialConfig.Credentials()
+	if c.sessions == nil {
+		c.sessions = make(map[credentialKey]client.ConfigProvider)
+	}
+	c.sessions[key] = sess
+	return sess
+}
+
+func (c *CloudWatchLogs) createClient(retryer aws.RequestRetryer, key credentialKey) *cloudwatchlogs.CloudWatchLogs {
+	// the endpoint override is for the region of the output
+	endpoint := c.EndpointOverride
+	if key.region != c.Region {
+		endpoint = ""
 	}
 	client := cloudwatchlogs.New(
-		credentialConfig.Credentials(),
+		c.session(key),
 		&aws.Config{
-			Endpoint: aws.String(c.EndpointOverride),
+			Endpoint: aws.String(endpoint),
 			Retryer:  retryer,
 			LogLevel: configaws.SDKLogLevel(),
 			Logger:   configaws.SDKLogger{},
 		},
 	)
-	client.Handlers.Build.PushBackNamed(handlers.NewRequestCompressionHandler([]string{"PutLogEvents"}))
+	if rc := c.compression(key.region); rc != nil {
+		rc.Configure(&client.Handlers)
+	}
+	if c.clock == nil {
+		c.clock = clockskew.New("cloudwatchlogs", c.ClockSkewThreshold.Duration, c.ClockSkewCorrection)
+	}
+	c.clock.Configure(&client.Handlers)
+	if key.region == c.Region {
+		c.configureRegionalClient(client)
+	}
 	if c.middleware != nil {
 		if err := awsmiddleware.NewConfigurer(c.middleware.Handlers()).Configure(awsmiddleware.SDKv1(&client.Handlers)); err != nil {
 			c.Log.Errorf("Unable to configure middleware on cloudwatch logs client: %v", err)
@@ -182,6 +357,55 @@ func (c *CloudWatchLogs) createClient(retryer aws.RequestRetryer) *cloudwatchlog
 	return client
 }
 
+// compression returns the request compression shared by the clients for the region, or nil if
+// the requests are not compressed. Must be called with cwDestsMu held.
+func (c *CloudWatchLogs) compression(region string) *handlers.RequestCompression {
+	if c.Compression == compressionNone {
+		return nil
+	}
+	if rc, ok := c.compressions[region]; ok {
+		return rc
+	}
+	codec := c.Compression
+	if codec == "" {
+		codec = compression.Gzip
+	}
+	negotiator, err := compression.NewNegotiator("cloudwatchlogs/"+region, codec)
+	if err != nil {
+		c.Log.Errorf("Unable to configure the request compression, using %s: %v", compression.Gzip, err)
+		negotiator, _ = compression.NewNegotiator("cloudwatchlogs/"+region, compression.Gzip)
+	}
+	rc := handlers.NewRequestCompression([]string{"PutLogEvents"}, negotiator)
+	if c.compressions == nil {
+		c.compressions = make(map[string]*handlers.RequestCompression)
+	}
+	c.compressions[region] = rc
+	return rc
+}
+
+// configureRegionalClient sets up the SigV4a signing and the failover endpoints on a client for
+// the region of the output. Must be called with cwDestsMu held.
+func (c *CloudWatchLogs) configureRegionalClient(client *cloudwatchlogs.CloudWatchLogs) {
+	if len(c.SigV4aRegionSet) > 0 {
+		if c.signer == nil {
+			c.signer = sigv4a.NewSigner(c.SigV4aRegionSet)
+		}
+		c.signer.Configure(&client.Handlers)
+	}
+	if len(c.FailoverEndpoints) == 0 {
+		return
+	}
+	if c.endpoints == nil {
+		endpoints, err := failover.New("cloudwatchlogs", append([]string{client.Endpoint}, c.FailoverEndpoints...))
+		if err != nil {
+			c.Log.Errorf("Unable to configure the failover endpoints, using %s only: %v", client.Endpoint, err)
+			return
+		}
+		c.endpoints = endpoints
+	}
+	c.endpoints.Configure(&client.Handlers)
+}
+
 func (c *CloudWatchLogs) writeMetricAsStructuredLog(m telegraf.Metric) {
 	t, err := c.getTargetFromMetric(m)
 	if err != nil {
@@ -318,17 +542,23 @@ type cwDest struct {
 	isEMF   bool
 	stopped bool
 	retryer *retryer.LogThrottleRetryer
+	logSrc  logs.LogSrc
+	parent  *CloudWatchLogs
 }
 
 func (cd *cwDest) Publish(events []logs.LogEvent) error {
 	for _, e := range events {
-		if !cd.isEMF {
+		d := cd
+		if re, ok := e.(logs.RoutedLogEvent); ok {
+			d = cd.route(re)
+		}
+		if !d.isEMF {
 			msg := e.Message()
 			if strings.HasPrefix(msg, "{") && strings.HasSuffix(msg, "}") && strings.Contains(msg, "\"CloudWatchMetrics\"") {
-				cd.switchToEMF()
+				d.switchToEMF()
 			}
 		}
-		cd.AddEvent(e)
+		d.AddEvent(e)
 	}
 	if cd.stopped {
 		return logs.ErrOutputStopped
@@ -336,6 +566,22 @@ func (cd *cwDest) Publish(events []logs.LogEvent) error {
 	return nil
 }
 
+// route returns the destination for the log group and stream resolved by the event. Each target
+// has its own pusher, so the events are batched by the resolved log group and stream.
+func (cd *cwDest) route(e logs.RoutedLogEvent) *cwDest {
+	t := cd.pusher.Target
+	if group := e.Group(); group != "" {
+		t.Group = group
+	}
+	if stream := e.Stream(); stream != "" {
+		t.Stream = stream
+	}
+	if t == cd.pusher.Target || cd.parent == nil {
+		return cd
+	}
+	return cd.parent.getDest(t, cd.logSrc)
+}
+
 func (cd *cwDest) Stop() {
 	cd.retryer.Stop()
 	cd.stopped = true
@@ -400,7 +646,7 @@ func init() {
 		return &CloudWatchLogs{
 			ForceFlushInterval: internal.Duration{Duration: defaultFlushTimeout},
 			pusherStopChan:     make(chan struct{}),
-			cwDests:            make(map[pusher.Target]*cwDest),
+			cwDests:            make(map[destKey]*cwDest),
 			middleware: agenthealth.NewAgentHealth(
 				zap.NewNop(),
 				&agenthealth.Config{
@@ -4,13 +4,19 @@
 package cloudwatchlogs
 
 import (
+	"net/http"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/require"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/internal/harness"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/cloudwatchlogs/internal/pusher"
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
 	"github.com/aws/amazon-cloudwatch-agent/tool/util"
 )
 
@@ -75,7 +81,7 @@ func TestCreateDestination(t *testing.T) {
 				AccessKey:      "access_key",
 				SecretKey:      "secret_key",
 				pusherStopChan: make(chan struct{}),
-				cwDests:        make(map[pusher.Target]*cwDest),
+				cwDests:        make(map[destKey]*cwDest),
 			}
 			dest := c.CreateDest(testCase.cfgLogGroup, testCase.cfgLogStream, testCase.cfgLogRetention, testCase.cfgLogClass, testCase.cfgTailerSrc).(*cwDest)
 			require.Equal(t, testCase.expectedLogGroup, dest.pusher.Group)
@@ -92,7 +98,7 @@ func TestDuplicateDestination(t *testing.T) {
 		Log:            testutil.Logger{Name: "test"},
 		AccessKey:      "access_key",
 		SecretKey:      "secret_key",
-		cwDests:        make(map[pusher.Target]*cwDest),
+		cwDests:        make(map[destKey]*cwDest),
 		pusherStopChan: make(chan struct{}),
 	}
 	// Given the same log group, log stream, same retention, and logClass
@@ -102,3 +108,196 @@ func TestDuplicateDestination(t *testing.T) {
 	// Then the destination for cloudwatchlogs endpoint would be the same
 	require.Equal(t, d1, d2)
 }
+
+type routedLogEvent struct {
+	logs.LogEvent
+	group, stream string
+}
+
+func (e routedLogEvent) Group() string {
+	return e.group
+}
+
+func (e routedLogEvent) Stream() string {
+	return e.stream
+}
+
+func TestRoutedDestination(t *testing.T) {
+	c := &CloudWatchLogs{
+		Log:            testutil.Logger{Name: "test"},
+		AccessKey:      "access_key",
+		SecretKey:      "secret_key",
+		cwDests:        make(map[destKey]*cwDest),
+		pusherStopChan: make(chan struct{}),
+	}
+	d := c.CreateDest("G1", "S1", 7, util.StandardLogGroupClass, nil).(*cwDest)
+
+	// Given an event without a resolved log group or stream
+	// Then the event stays in the destination of the source
+	require.Equal(t, d, d.route(routedLogEvent{}))
+
+	// Given an event with a resolved log stream
+	// Then the event is routed to the destination for the log stream in the same log group
+	routed := d.route(routedLogEvent{stream: "S2"})
+	require.NotEqual(t, d, routed)
+	require.Equal(t, pusher.Target{Group: "G1", Stream: "S2", Class: util.StandardLogGroupClass, Retention: 7}, routed.pusher.Target)
+	require.Equal(t, routed, d.route(routedLogEvent{group: "G1", stream: "S2"}))
+	require.Equal(t, routed, c.CreateDest("G1", "S2", 7, util.StandardLogGroupClass, nil))
+
+	// Given an event with a resolved log group and stream
+	routed = d.route(routedLogEvent{group: "G2", stream: "S2"})
+	require.Equal(t, pusher.Target{Group: "G2", Stream: "S2", Class: util.StandardLogGroupClass, Retention: 7}, routed.pusher.Target)
+	require.Len(t, c.cwDests, 3)
+}
+
+type crossAccountSrc struct {
+	logs.LogSrc
+	roleARN, region string
+}
+
+func (s crossAccountSrc) RoleARN() string {
+	return s.roleARN
+}
+
+func (s crossAccountSrc) Region() string {
+	return s.region
+}
+
+func TestCredentialKey(t *testing.T) {
+	c := &CloudWatchLogs{RoleARN: "arn:aws:iam::111111111111:role/local", Region: "us-east-1"}
+	require.Equal(t, credentialKey{roleARN: "arn:aws:iam::111111111111:role/local", region: "us-east-1"}, c.credentialKey(nil))
+	require.Equal(t, credentialKey{roleARN: "arn:aws:iam::111111111111:role/local", region: "us-east-1"}, c.credentialKey(crossAccountSrc{}))
+	require.Equal(t, credentialKey{roleARN: "arn:aws:iam::222222222222:role/central", region: "us-east-1"}, c.credentialKey(crossAccountSrc{roleARN: "arn:aws:iam::222222222222:role/central"}))
+	require.Equal(t, credentialKey{roleARN: "arn:aws:iam::222222222222:role/central", region: "eu-west-1"}, c.credentialKey(crossAccountSrc{roleARN: "arn:aws:iam::222222222222:role/central", region: "eu-west-1"}))
+}
+
+func TestCrossRegionDestination(t *testing.T) {
+	c := &CloudWatchLogs{
+		Log:              testutil.Logger{Name: "test"},
+		Region:           "us-east-1",
+		EndpointOverride: "https://logs.us-east-1.example.com",
+		AccessKey:        "access_key",
+		SecretKey:        "secret_key",
+		cwDests:          make(map[destKey]*cwDest),
+		pusherStopChan:   make(chan struct{}),
+	}
+	local := c.CreateDest("G1", "S1", -1, util.StandardLogGroupClass, nil).(*cwDest)
+	// Given the same log group in another region
+	remote := c.CreateDest("G1", "S1", -1, util.StandardLogGroupClass, crossAccountSrc{region: "eu-west-1"}).(*cwDest)
+	// Then the destinations are different and use their own client
+	require.NotEqual(t, local, remote)
+	require.Equal(t, "us-east-1", *local.pusher.Service.(*cloudwatchlogs.CloudWatchLogs).Client.Config.Region)
+	require.Equal(t, "https://logs.us-east-1.example.com", local.pusher.Service.(*cloudwatchlogs.CloudWatchLogs).Client.Endpoint)
+	require.Equal(t, "eu-west-1", *remote.pusher.Service.(*cloudwatchlogs.CloudWatchLogs).Client.Config.Region)
+	require.NotEqual(t, "https://logs.us-east-1.example.com", remote.pusher.Service.(*cloudwatchlogs.CloudWatchLogs).Client.Endpoint)
+
+	// Given another log group in the other region
+	c.CreateDest("G2", "S1", -1, util.StandardLogGroupClass, crossAccountSrc{region: "eu-west-1"})
+	// Then the session and target manager are shared
+	require.Len(t, c.cwDests, 3)
+	require.Len(t, c.sessions, 2)
+	require.Len(t, c.targetManagers, 2)
+}
+
+func TestRateLimitedDestination(t *testing.T) {
+	c := &CloudWatchLogs{
+		Log:            testutil.Logger{Name: "test"},
+		Region:         "us-east-1",
+		AccessKey:      "access_key",
+		SecretKey:      "secret_key",
+		RateLimit:      10,
+		RateLimits:     map[string]float64{"G2": 1},
+		cwDests:        make(map[destKey]*cwDest),
+		pusherStopChan: make(chan struct{}),
+	}
+	c.CreateDest("G1", "S1", -1, util.StandardLogGroupClass, nil)
+	c.CreateDest("G2", "S1", -1, util.StandardLogGroupClass, nil)
+	c.CreateDest("G1", "S1", -1, util.StandardLogGroupClass, crossAccountSrc{region: "eu-west-1"})
+	// Then the rates are shared by the log groups of the same account and region
+	require.Len(t, c.rateLimiters, 2)
+	local := c.rateLimiters[credentialKey{region: "us-east-1"}]
+	require.NotNil(t, local)
+	require.NotSame(t, local, c.rateLimiters[credentialKey{region: "eu-west-1"}])
+
+	// Given no rate limits
+	c = &CloudWatchLogs{
+		Log:            testutil.Logger{Name: "test"},
+		AccessKey:      "access_key",
+		SecretKey:      "secret_key",
+		cwDests:        make(map[destKey]*cwDest),
+		pusherStopChan: make(chan struct{}),
+	}
+	c.CreateDest("G1", "S1", -1, util.StandardLogGroupClass, nil)
+	// Then the batches are not rate limited
+	require.Nil(t, c.rateLimiters[credentialKey{}])
+}
+
+func TestFailoverDestination(t *testing.T) {
+	c := &CloudWatchLogs{
+		Log:               testutil.Logger{Name: "test"},
+		Region:            "us-east-1",
+		EndpointOverride:  "https://logs.us-east-1.example.com",
+		FailoverEndpoints: []string{"https://logs.us-west-2.example.com"},
+		SigV4aRegionSet:   []string{"us-east-1", "us-west-2"},
+		AccessKey:         "access_key",
+		SecretKey:         "secret_key",
+		cwDests:           make(map[destKey]*cwDest),
+		pusherStopChan:    make(chan struct{}),
+	}
+	local := c.CreateDest("G1", "S1", -1, util.StandardLogGroupClass, nil).(*cwDest)
+	c.CreateDest("G2", "S1", -1, util.StandardLogGroupClass, nil)
+	remote := c.CreateDest("G1", "S1", -1, util.StandardLogGroupClass, crossAccountSrc{region: "eu-west-1"}).(*cwDest)
+	// Then the clients for the region of the output share the failover endpoints
+	require.NotNil(t, c.signer)
+	require.NotNil(t, c.endpoints)
+	require.Equal(t, "https://logs.us-east-1.example.com", c.endpoints.Active())
+	// And the route handler is only added to them
+	localClient := local.pusher.Service.(*cloudwatchlogs.CloudWatchLogs).Client
+	remoteClient := remote.pusher.Service.(*cloudwatchlogs.CloudWatchLogs).Client
+	require.Equal(t, remoteClient.Handlers.Sign.Len()+1, localClient.Handlers.Sign.Len())
+	require.Equal(t, remoteClient.Handlers.CompleteAttempt.Len()+1, localClient.Handlers.CompleteAttempt.Len())
+}
+
+type testLogEvent struct {
+	msg string
+	t   time.Time
+}
+
+func (e testLogEvent) Message() string {
+	return e.msg
+}
+
+func (e testLogEvent) Time() time.Time {
+	return e.t
+}
+
+func (e testLogEvent) Done() {}
+
+func TestPublishToBackend(t *testing.T) {
+	backend := harness.NewLogs(t)
+	// the first attempt to publish fails and is retried
+	backend.Fail("PutLogEvents", harness.Failure{StatusCode: http.StatusInternalServerError, Code: cloudwatchlogs.ErrCodeServiceUnavailableException})
+	c := &CloudWatchLogs{
+		Log:                testutil.Logger{Name: "test"},
+		Region:             harness.Region,
+		EndpointOverride:   backend.URL,
+		AccessKey:          harness.AccessKey,
+		SecretKey:          harness.SecretKey,
+		ForceFlushInterval: internal.Duration{Duration: 100 * time.Millisecond},
+		cwDests:            make(map[destKey]*cwDest),
+		pusherStopChan:     make(chan struct{}),
+	}
+	d := c.CreateDest("G1", "S1", -1, util.StandardLogGroupClass, nil)
+	start := time.Now()
+	require.NoError(t, d.Publish([]logs.LogEvent{
+		testLogEvent{msg: "first", t: start},
+		testLogEvent{msg: "second", t: start.Add(time.Millisecond)},
+	}))
+	require.Eventually(t, func() bool {
+		return len(backend.Requests("PutLogEvents")) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, c.Close())
+
+	backend.Scrub("timestamp")
+	harness.AssertGolden(t, filepath.Join("testdata", "publish.golden"), backend.Dump("PutLogEvents"))
+}
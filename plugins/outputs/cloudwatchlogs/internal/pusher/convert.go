@@ -5,9 +5,12 @@ package pusher
 
 import (
 	"time"
+	"unicode/utf8"
 
 	"github.com/influxdata/telegraf"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/clockskew"
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 )
 
@@ -25,26 +28,117 @@ const (
 type converter struct {
 	Target
 	logger          telegraf.Logger
+	clock           *clockskew.Detector
+	sizeLimit       logs.EventSizeLimit
 	lastValidTime   time.Time
 	lastUpdateTime  time.Time
 	lastWarnMessage time.Time
+	// warnedOversize is set once an oversize log event was logged.
+	warnedOversize bool
 }
 
-func newConverter(logger telegraf.Logger, target Target) *converter {
+func newConverter(logger telegraf.Logger, target Target, clock *clockskew.Detector, sizeLimit logs.EventSizeLimit) *converter {
+	if sizeLimit.MaxSize <= 0 || sizeLimit.MaxSize > msgSizeLimit {
+		sizeLimit.MaxSize = msgSizeLimit
+	}
+	if sizeLimit.Strategy == "" {
+		sizeLimit.Strategy = logs.OversizeTruncateTail
+	}
+	if sizeLimit.Marker == "" || len(sizeLimit.Marker) >= sizeLimit.MaxSize {
+		sizeLimit.Marker = truncatedSuffix
+	}
 	return &converter{
-		logger: logger,
-		Target: target,
+		logger:    logger,
+		Target:    target,
+		clock:     clock,
+		sizeLimit: sizeLimit,
 	}
 }
 
-// convert handles message truncation to remain within PutLogEvents limits and sets a timestamp if not set in the
-// logs.LogEvent.
-func (c *converter) convert(e logs.LogEvent) *logEvent {
+// convert handles message truncation or splitting to remain within PutLogEvents limits and sets a timestamp if not
+// set in the logs.LogEvent. The timestamp is corrected by the skew of the host clock if the clock is set. Only the
+// last of the split log events has the done callback of the logs.LogEvent.
+func (c *converter) convert(e logs.LogEvent) []*logEvent {
+	t := c.timestamp(e)
 	message := e.Message()
+	if len(message) <= c.sizeLimit.MaxSize {
+		return []*logEvent{newLogEvent(t, message, e.Done)}
+	}
+	if !c.warnedOversize {
+		c.warnedOversize = true
+		c.logger.Warnf("Log event of %d bytes in %v/%v is above the size limit of %d bytes, applying %s to it and the next ones", len(message), c.Group, c.Stream, c.sizeLimit.MaxSize, c.sizeLimit.Strategy)
+	}
+	if c.sizeLimit.Strategy == logs.OversizeSplit {
+		chunks := split(message, c.sizeLimit.MaxSize)
+		events := make([]*logEvent, len(chunks))
+		for i, chunk := range chunks {
+			var done func()
+			if i == len(chunks)-1 {
+				done = e.Done
+			}
+			events[i] = newLogEvent(t, chunk, done)
+		}
+		selftelemetry.Add(selftelemetry.MetricSplitLogEvents, "cloudwatchlogs", 1)
+		return events
+	}
+	selftelemetry.Add(selftelemetry.MetricTruncatedLogEvents, "cloudwatchlogs", 1)
+	return []*logEvent{newLogEvent(t, c.truncate(message), e.Done)}
+}
 
-	if len(message) > msgSizeLimit {
-		message = message[:msgSizeLimit-len(truncatedSuffix)] + truncatedSuffix
+// truncate replaces the part of the message above the size limit with the marker.
+func (c *converter) truncate(message string) string {
+	marker := c.sizeLimit.Marker
+	keep := c.sizeLimit.MaxSize - len(marker)
+	switch c.sizeLimit.Strategy {
+	case logs.OversizeTruncateHead:
+		return marker + message[suffixStart(message, keep):]
+	case logs.OversizeTruncateMiddle:
+		head := message[:prefixEnd(message, keep/2)]
+		return head + marker + message[suffixStart(message, keep-len(head)):]
+	default:
+		return message[:prefixEnd(message, keep)] + marker
 	}
+}
+
+// split cuts the message into parts of at most size bytes.
+func split(message string, size int) []string {
+	var parts []string
+	for len(message) > size {
+		end := prefixEnd(message, size)
+		if end == 0 {
+			end = size
+		}
+		parts = append(parts, message[:end])
+		message = message[end:]
+	}
+	return append(parts, message)
+}
+
+// prefixEnd is the end of the longest prefix of at most n bytes that does not cut a UTF-8 character.
+func prefixEnd(s string, n int) int {
+	if n >= len(s) {
+		return len(s)
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return n
+}
+
+// suffixStart is the start of the longest suffix of at most n bytes that does not cut a UTF-8 character.
+func suffixStart(s string, n int) int {
+	if n >= len(s) {
+		return 0
+	}
+	i := len(s) - n
+	for i < len(s) && !utf8.RuneStart(s[i]) {
+		i++
+	}
+	return i
+}
+
+// timestamp is the time of the logs.LogEvent, or the last valid one if it does not have one.
+func (c *converter) timestamp(e logs.LogEvent) time.Time {
 	now := time.Now()
 	var t time.Time
 	if e.Time().IsZero() {
@@ -69,5 +163,5 @@ func (c *converter) convert(e logs.LogEvent) *logEvent {
 		c.lastUpdateTime = now
 		c.lastWarnMessage = time.Time{}
 	}
-	return newLogEvent(t, message, e.Done)
+	return c.clock.Adjust(t)
 }
@@ -7,13 +7,20 @@ import (
 	"bytes"
 	"io"
 	"log"
+	"net/http"
 	"os"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/clockskew"
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
 )
 
 type stubLogEvent struct {
@@ -51,8 +58,8 @@ func TestConverter(t *testing.T) {
 		t.Parallel()
 		now := time.Now()
 
-		conv := newConverter(logger, target)
-		le := conv.convert(newStubLogEvent("Test message", now))
+		conv := newConverter(logger, target, nil, logs.EventSizeLimit{})
+		le := conv.convert(newStubLogEvent("Test message", now))[0]
 
 		assert.Equal(t, now, le.timestamp)
 		assert.Equal(t, "Test message", le.message)
@@ -63,10 +70,10 @@ func TestConverter(t *testing.T) {
 		t.Parallel()
 		testTimestampMs := time.UnixMilli(12345678)
 
-		conv := newConverter(logger, target)
+		conv := newConverter(logger, target, nil, logs.EventSizeLimit{})
 		conv.lastValidTime = testTimestampMs
 
-		le := conv.convert(newStubLogEvent("Test message", time.Time{}))
+		le := conv.convert(newStubLogEvent("Test message", time.Time{}))[0]
 
 		assert.Equal(t, testTimestampMs, le.timestamp)
 		assert.Equal(t, "Test message", le.message)
@@ -77,8 +84,8 @@ func TestConverter(t *testing.T) {
 		largeMessage := string(make([]byte, msgSizeLimit+100))
 		event := newStubLogEvent(largeMessage, time.Now())
 
-		conv := newConverter(logger, target)
-		le := conv.convert(event)
+		conv := newConverter(logger, target, nil, logs.EventSizeLimit{})
+		le := conv.convert(event)[0]
 
 		assert.Equal(t, msgSizeLimit, len(le.message))
 		assert.Equal(t, truncatedSuffix, (le.message)[len(le.message)-len(truncatedSuffix):])
@@ -86,13 +93,13 @@ func TestConverter(t *testing.T) {
 
 	t.Run("WithOldTimestampWarning", func(t *testing.T) {
 		oldTime := time.Now().Add(-25 * time.Hour)
-		conv := newConverter(logger, target)
+		conv := newConverter(logger, target, nil, logs.EventSizeLimit{})
 		conv.lastValidTime = oldTime
 		conv.lastUpdateTime = oldTime
 
 		var logbuf bytes.Buffer
 		log.SetOutput(io.MultiWriter(&logbuf, os.Stdout))
-		le := conv.convert(newStubLogEvent("Test message", time.Time{}))
+		le := conv.convert(newStubLogEvent("Test message", time.Time{}))[0]
 
 		assert.Equal(t, oldTime, le.timestamp)
 		assert.Equal(t, "Test message", le.message)
@@ -102,4 +109,104 @@ func TestConverter(t *testing.T) {
 		assert.True(t, strings.Contains(logline, "W!"))
 		assert.True(t, strings.Contains(logline, "Unable to parse timestamp"))
 	})
+
+	t.Run("WithClockSkew", func(t *testing.T) {
+		clock := clockskew.New("converter", time.Minute, true)
+		defer clock.Stop()
+		var handlers request.Handlers
+		clock.Configure(&handlers)
+		// the endpoint says the local clock is 3 hours ahead
+		handlers.CompleteAttempt.Run(&request.Request{
+			HTTPResponse: &http.Response{Header: http.Header{"Date": []string{time.Now().Add(-3 * time.Hour).Format(http.TimeFormat)}}},
+		})
+
+		now := time.Now()
+		conv := newConverter(logger, target, clock, logs.EventSizeLimit{})
+		le := conv.convert(newStubLogEvent("Test message", now))[0]
+
+		assert.InDelta(t, now.Add(-3*time.Hour).UnixMilli(), le.timestamp.UnixMilli(), float64(2*time.Second/time.Millisecond))
+		assert.Equal(t, now, conv.lastValidTime)
+	})
+}
+
+func oversizeLogEvents(name string) float64 {
+	for _, sample := range selftelemetry.Default.Collect() {
+		if sample.Name == name && sample.Component == "cloudwatchlogs" {
+			return sample.Value
+		}
+	}
+	return 0
+}
+
+func TestConverterOversize(t *testing.T) {
+	logger := testutil.Logger{Name: "converter"}
+	target := Target{Group: "testGroup", Stream: "testStream"}
+	message := strings.Repeat("a", 40) + strings.Repeat("é", 20) + strings.Repeat("z", 40)
+
+	testCases := map[string]struct {
+		sizeLimit logs.EventSizeLimit
+		want      []string
+		metric    string
+	}{
+		"WithinLimit": {
+			sizeLimit: logs.EventSizeLimit{MaxSize: len(message)},
+			want:      []string{message},
+		},
+		"TruncateTail": {
+			sizeLimit: logs.EventSizeLimit{MaxSize: 50, Marker: "..."},
+			want:      []string{strings.Repeat("a", 40) + "ééé..."},
+			metric:    selftelemetry.MetricTruncatedLogEvents,
+		},
+		"TruncateHead": {
+			sizeLimit: logs.EventSizeLimit{MaxSize: 46, Strategy: logs.OversizeTruncateHead, Marker: "..."},
+			want:      []string{"..." + "é" + strings.Repeat("z", 40)},
+			metric:    selftelemetry.MetricTruncatedLogEvents,
+		},
+		"TruncateMiddle": {
+			sizeLimit: logs.EventSizeLimit{MaxSize: 83, Strategy: logs.OversizeTruncateMiddle, Marker: "[...]"},
+			want:      []string{strings.Repeat("a", 39) + "[...]" + strings.Repeat("z", 39)},
+			metric:    selftelemetry.MetricTruncatedLogEvents,
+		},
+		"DefaultMarker": {
+			sizeLimit: logs.EventSizeLimit{MaxSize: 54},
+			want:      []string{strings.Repeat("a", 40) + truncatedSuffix},
+			metric:    selftelemetry.MetricTruncatedLogEvents,
+		},
+		"Split": {
+			sizeLimit: logs.EventSizeLimit{MaxSize: 45, Strategy: logs.OversizeSplit},
+			want: []string{
+				strings.Repeat("a", 40) + "éé",
+				strings.Repeat("é", 18) + strings.Repeat("z", 9),
+				strings.Repeat("z", 31),
+			},
+			metric: selftelemetry.MetricSplitLogEvents,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var done int
+			event := newStubLogEvent(message, time.Now())
+			event.done = func() { done++ }
+
+			conv := newConverter(logger, target, nil, testCase.sizeLimit)
+			before := oversizeLogEvents(testCase.metric)
+			les := conv.convert(event)
+			if testCase.metric != "" {
+				assert.Equal(t, before+1, oversizeLogEvents(testCase.metric))
+			}
+
+			var got []string
+			for _, le := range les {
+				assert.True(t, utf8.ValidString(le.message))
+				assert.LessOrEqual(t, len(le.message), conv.sizeLimit.MaxSize)
+				got = append(got, le.message)
+			}
+			assert.Equal(t, testCase.want, got)
+			for _, le := range les[:len(les)-1] {
+				assert.Nil(t, le.doneCallback)
+			}
+			les[len(les)-1].doneCallback()
+			assert.Equal(t, 1, done)
+		})
+	}
 }
@@ -11,25 +11,46 @@ import (
 
 type WorkerPool interface {
 	Submit(task func())
+	// SubmitFor adds a task of the key. The workers take the tasks of the keys in round robin, so a key with
+	// many tasks does not delay the tasks of the other keys.
+	SubmitFor(key string, task func())
 	Stop()
 }
 
+// queuedTask is a task waiting to be handed to a worker.
+type queuedTask struct {
+	task func()
+	// handed is closed once a worker received the task.
+	handed chan struct{}
+}
+
 type workerPool struct {
 	tasks       chan func()
 	workerCount atomic.Int32
 	wg          sync.WaitGroup
 	stopCh      chan struct{}
+	dispatched  chan struct{}
+
+	mu sync.Mutex
+	// queues are the tasks waiting for a worker by key and keys the keys with tasks in round robin order.
+	queues map[string][]*queuedTask
+	keys   []string
+	wakeCh chan struct{}
 }
 
 // NewWorkerPool creates a pool of workers of the specified size.
 func NewWorkerPool(size int) WorkerPool {
 	p := &workerPool{
-		tasks:  make(chan func(), size*2),
-		stopCh: make(chan struct{}),
+		tasks:      make(chan func()),
+		stopCh:     make(chan struct{}),
+		dispatched: make(chan struct{}),
+		queues:     make(map[string][]*queuedTask),
+		wakeCh:     make(chan struct{}, 1),
 	}
 	for i := 0; i < size; i++ {
 		p.addWorker()
 	}
+	go p.dispatch()
 	return p
 }
 
@@ -51,17 +72,75 @@ func (p *workerPool) worker() {
 	}
 }
 
+// dispatch hands the queued tasks to the workers, taking a task of each key in turn.
+func (p *workerPool) dispatch() {
+	defer close(p.dispatched)
+	for {
+		t := p.next()
+		if t == nil {
+			select {
+			case <-p.wakeCh:
+				continue
+			case <-p.stopCh:
+				return
+			}
+		}
+		select {
+		case p.tasks <- t.task:
+			close(t.handed)
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+// next removes the first task of the next key, or returns nil if there are no tasks.
+func (p *workerPool) next() *queuedTask {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.keys) == 0 {
+		return nil
+	}
+	key := p.keys[0]
+	p.keys = p.keys[1:]
+	queue := p.queues[key]
+	t := queue[0]
+	if len(queue) > 1 {
+		p.queues[key] = queue[1:]
+		p.keys = append(p.keys, key)
+	} else {
+		delete(p.queues, key)
+	}
+	return t
+}
+
 // Submit adds a task to the pool. Blocks until a worker is available to receive the task or the pool is stopped.
 func (p *workerPool) Submit(task func()) {
+	p.SubmitFor("", task)
+}
+
+// SubmitFor adds a task of the key to the pool. Blocks until a worker is available to receive the task or the pool
+// is stopped.
+func (p *workerPool) SubmitFor(key string, task func()) {
 	select {
 	case <-p.stopCh:
 		return
 	default:
-		select {
-		case p.tasks <- task:
-		case <-p.stopCh:
-			return
-		}
+	}
+	t := &queuedTask{task: task, handed: make(chan struct{})}
+	p.mu.Lock()
+	if _, ok := p.queues[key]; !ok {
+		p.keys = append(p.keys, key)
+	}
+	p.queues[key] = append(p.queues[key], t)
+	p.mu.Unlock()
+	select {
+	case p.wakeCh <- struct{}{}:
+	default:
+	}
+	select {
+	case <-t.handed:
+	case <-p.stopCh:
 	}
 }
 
@@ -77,6 +156,7 @@ func (p *workerPool) Stop() {
 		return
 	default:
 		close(p.stopCh)
+		<-p.dispatched
 		close(p.tasks)
 		p.wg.Wait()
 	}
@@ -97,9 +177,9 @@ func newSenderPool(workerPool WorkerPool, sender Sender) Sender {
 	}
 }
 
-// Send submits a send task to the worker pool.
+// Send submits a send task of the log group of the batch to the worker pool.
 func (s *senderPool) Send(batch *logEventBatch) {
-	s.workerPool.Submit(func() {
+	s.workerPool.SubmitFor(batch.Group, func() {
 		s.sender.Send(batch)
 	})
 }
@@ -4,6 +4,7 @@
 package pusher
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -103,12 +104,56 @@ func TestWorkerPool(t *testing.T) {
 	})
 }
 
+func TestWorkerPoolRoundRobin(t *testing.T) {
+	pool := NewWorkerPool(1).(*workerPool)
+	defer pool.Stop()
+
+	// the worker is busy while the tasks are queued
+	gate := make(chan struct{})
+	pool.Submit(func() { <-gate })
+	queued := func() int {
+		pool.mu.Lock()
+		defer pool.mu.Unlock()
+		var n int
+		for _, queue := range pool.queues {
+			n += len(queue)
+		}
+		return n
+	}
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	submit := func(key string) {
+		wg.Add(1)
+		go pool.SubmitFor(key, func() {
+			defer wg.Done()
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, key)
+		})
+	}
+	// the first task is taken by the dispatcher waiting for the worker
+	submit("chatty")
+	assert.Eventually(t, func() bool { return queued() == 0 }, time.Second, time.Millisecond)
+	for i := 1; i < 5; i++ {
+		submit("chatty")
+		assert.Eventually(t, func() bool { return queued() == i }, time.Second, time.Millisecond)
+	}
+	submit("quiet")
+	assert.Eventually(t, func() bool { return queued() == 5 }, time.Second, time.Millisecond)
+
+	close(gate)
+	wg.Wait()
+	assert.Equal(t, []string{"chatty", "chatty", "quiet", "chatty", "chatty", "chatty"}, order)
+}
+
 func TestSenderPool(t *testing.T) {
 	logger := testutil.Logger{Name: "test"}
 	stop := make(chan struct{})
 	mockService := new(mockLogsService)
 	mockService.On("PutLogEvents", mock.Anything).Return(&cloudwatchlogs.PutLogEventsOutput{}, nil)
-	s := newSender(logger, mockService, nil, time.Second, stop)
+	s := newSender(context.Background(), logger, mockService, nil, time.Second, stop)
 	p := NewWorkerPool(12)
 	sp := newSenderPool(p, s)
 
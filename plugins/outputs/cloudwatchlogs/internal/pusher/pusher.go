@@ -4,11 +4,13 @@
 package pusher
 
 import (
+	"context"
 	"sync"
 	"time"
 
 	"github.com/influxdata/telegraf"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/clockskew"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 )
 
@@ -22,23 +24,35 @@ type Pusher struct {
 	Sender         Sender
 }
 
-// NewPusher creates a new Pusher instance with a new Queue and Sender. Calls PutRetentionPolicy using the
-// TargetManager.
+// NewPusher creates a new Pusher instance with a new Queue and Sender. Calls PutLogGroupSettings for the sources with
+// log group settings, PutRetentionPolicy and, for the sources with indexed fields, PutIndexPolicy using the
+// TargetManager. The Queue flushes its last batch when stop is closed, while canceling ctx aborts the requests in
+// flight. The batches are delayed by the rateLimiter if it is not nil, and the timestamps of the events are corrected
+// by the clock if it is not nil.
 func NewPusher(
+	ctx context.Context,
 	logger telegraf.Logger,
 	target Target,
 	service cloudWatchLogsService,
 	targetManager TargetManager,
 	entityProvider logs.LogEntityProvider,
 	workerPool WorkerPool,
+	rateLimiter *RateLimiter,
+	clock *clockskew.Detector,
 	flushTimeout time.Duration,
 	retryDuration time.Duration,
 	stop <-chan struct{},
 	wg *sync.WaitGroup,
 ) *Pusher {
-	s := createSender(logger, service, targetManager, workerPool, retryDuration, stop)
-	q := newQueue(logger, target, flushTimeout, entityProvider, s, stop, wg)
+	s := createSender(ctx, logger, service, targetManager, workerPool, rateLimiter, retryDuration, stop)
+	q := newQueue(logger, target, flushTimeout, entityProvider, s, clock, stop, wg)
+	if sp, ok := entityProvider.(logs.LogGroupSettingsProvider); ok {
+		targetManager.PutLogGroupSettings(target.Group, sp.LogGroupSettings())
+	}
 	targetManager.PutRetentionPolicy(target)
+	if ip, ok := entityProvider.(logs.LogFieldIndexProvider); ok {
+		targetManager.PutIndexPolicy(target.Group, ip.FieldIndexes())
+	}
 	return &Pusher{
 		Target:         target,
 		Queue:          q,
@@ -49,18 +63,24 @@ func NewPusher(
 	}
 }
 
-// createSender initializes a Sender. Wraps it in a senderPool if a WorkerPool is provided.
+// createSender initializes a Sender. Wraps it in a senderPool if a WorkerPool is provided, and in a
+// rateLimitedSender if a RateLimiter is provided.
 func createSender(
+	ctx context.Context,
 	logger telegraf.Logger,
 	service cloudWatchLogsService,
 	targetManager TargetManager,
 	workerPool WorkerPool,
+	rateLimiter *RateLimiter,
 	retryDuration time.Duration,
 	stop <-chan struct{},
 ) Sender {
-	s := newSender(logger, service, targetManager, retryDuration, stop)
-	if workerPool == nil {
-		return s
+	s := newSender(ctx, logger, service, targetManager, retryDuration, stop)
+	if workerPool != nil {
+		s = newSenderPool(workerPool, s)
+	}
+	if rateLimiter != nil {
+		s = newRateLimitedSender(s, rateLimiter, stop)
 	}
-	return newSenderPool(workerPool, s)
+	return s
 }
@@ -4,6 +4,7 @@
 package pusher
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -77,12 +78,15 @@ func setupPusher(t *testing.T, name string, workerPool WorkerPool, stop chan str
 	mockManager.On("PutRetentionPolicy", target).Return()
 
 	pusher := NewPusher(
+		context.Background(),
 		logger,
 		target,
 		service,
 		mockManager,
 		nil,
 		workerPool,
+		nil,
+		nil,
 		time.Second,
 		time.Minute,
 		stop,
@@ -10,6 +10,8 @@ import (
 
 	"github.com/influxdata/telegraf"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/clockskew"
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/profiler"
 )
@@ -48,13 +50,18 @@ func newQueue(
 	flushTimeout time.Duration,
 	entityProvider logs.LogEntityProvider,
 	sender Sender,
+	clock *clockskew.Detector,
 	stop <-chan struct{},
 	wg *sync.WaitGroup,
 ) Queue {
+	var sizeLimit logs.EventSizeLimit
+	if sp, ok := entityProvider.(logs.LogEventSizeProvider); ok {
+		sizeLimit = sp.EventSizeLimit()
+	}
 	q := &queue{
 		target:          target,
 		logger:          logger,
-		converter:       newConverter(logger, target),
+		converter:       newConverter(logger, target, clock, sizeLimit),
 		batch:           newLogEventBatch(target, entityProvider),
 		sender:          sender,
 		eventsCh:        make(chan logs.LogEvent, 100),
@@ -75,9 +82,14 @@ func newQueue(
 func (q *queue) AddEvent(e logs.LogEvent) {
 	if !hasValidTime(e) {
 		q.logger.Errorf("The log entry in (%v/%v) with timestamp (%v) comparing to the current time (%v) is out of accepted time range. Discard the log entry.", q.target.Group, q.target.Stream, e.Time(), time.Now())
+		selftelemetry.Add(selftelemetry.MetricDroppedEvents, "cloudwatchlogs", 1)
 		return
 	}
-	q.eventsCh <- e
+	// the queue no longer reads events once stopped
+	select {
+	case q.eventsCh <- e:
+	case <-q.stop:
+	}
 }
 
 // AddEventNonBlocking adds an event to the queue without blocking. If the queue is full, drops the oldest event in
@@ -85,6 +97,7 @@ func (q *queue) AddEvent(e logs.LogEvent) {
 func (q *queue) AddEventNonBlocking(e logs.LogEvent) {
 	if !hasValidTime(e) {
 		q.logger.Errorf("The log entry in (%v/%v) with timestamp (%v) comparing to the current time (%v) is out of accepted time range. Discard the log entry.", q.target.Group, q.target.Stream, e.Time(), time.Now())
+		selftelemetry.Add(selftelemetry.MetricDroppedEvents, "cloudwatchlogs", 1)
 		return
 	}
 
@@ -101,6 +114,7 @@ func (q *queue) AddEventNonBlocking(e logs.LogEvent) {
 		default:
 			<-q.nonBlockingEventsCh
 			q.addStats("emfMetricDrop", 1)
+			selftelemetry.Add(selftelemetry.MetricDroppedEvents, "cloudwatchlogs", 1)
 		}
 	}
 }
@@ -134,11 +148,12 @@ func (q *queue) start() {
 			if len(q.batch.events) == 0 {
 				q.resetFlushTimer()
 			}
-			event := q.converter.convert(e)
-			if !q.batch.inTimeRange(event.timestamp) || !q.batch.hasSpace(event.eventBytes) {
-				q.send()
+			for _, event := range q.converter.convert(e) {
+				if !q.batch.inTimeRange(event.timestamp) || !q.batch.hasSpace(event.eventBytes) {
+					q.send()
+				}
+				q.batch.append(event)
 			}
-			q.batch.append(event)
 		case <-q.flushCh:
 			lastSentTime, _ := q.lastSentTime.Load().(time.Time)
 			if time.Since(lastSentTime) >= q.flushTimeout && len(q.batch.events) > 0 {
@@ -5,6 +5,7 @@ package pusher
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/require"
 
@@ -30,9 +32,15 @@ type stubLogsService struct {
 	clg func(input *cloudwatchlogs.CreateLogGroupInput) (*cloudwatchlogs.CreateLogGroupOutput, error)
 	cls func(input *cloudwatchlogs.CreateLogStreamInput) (*cloudwatchlogs.CreateLogStreamOutput, error)
 	prp func(input *cloudwatchlogs.PutRetentionPolicyInput) (*cloudwatchlogs.PutRetentionPolicyOutput, error)
+	dip func(input *cloudwatchlogs.DescribeIndexPoliciesInput) (*cloudwatchlogs.DescribeIndexPoliciesOutput, error)
+	pip func(input *cloudwatchlogs.PutIndexPolicyInput) (*cloudwatchlogs.PutIndexPolicyOutput, error)
+	dlg func(input *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
+	akk func(input *cloudwatchlogs.AssociateKmsKeyInput) (*cloudwatchlogs.AssociateKmsKeyOutput, error)
+	ltl func(input *cloudwatchlogs.ListTagsLogGroupInput) (*cloudwatchlogs.ListTagsLogGroupOutput, error)
+	tlg func(input *cloudwatchlogs.TagLogGroupInput) (*cloudwatchlogs.TagLogGroupOutput, error)
 }
 
-func (s *stubLogsService) PutLogEvents(in *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+func (s *stubLogsService) PutLogEventsWithContext(_ aws.Context, in *cloudwatchlogs.PutLogEventsInput, _ ...request.Option) (*cloudwatchlogs.PutLogEventsOutput, error) {
 	if s.ple != nil {
 		return s.ple(in)
 	}
@@ -60,6 +68,48 @@ func (s *stubLogsService) PutRetentionPolicy(in *cloudwatchlogs.PutRetentionPoli
 	return nil, nil
 }
 
+func (s *stubLogsService) DescribeIndexPolicies(in *cloudwatchlogs.DescribeIndexPoliciesInput) (*cloudwatchlogs.DescribeIndexPoliciesOutput, error) {
+	if s.dip != nil {
+		return s.dip(in)
+	}
+	return &cloudwatchlogs.DescribeIndexPoliciesOutput{}, nil
+}
+
+func (s *stubLogsService) PutIndexPolicy(in *cloudwatchlogs.PutIndexPolicyInput) (*cloudwatchlogs.PutIndexPolicyOutput, error) {
+	if s.pip != nil {
+		return s.pip(in)
+	}
+	return nil, nil
+}
+
+func (s *stubLogsService) DescribeLogGroups(in *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	if s.dlg != nil {
+		return s.dlg(in)
+	}
+	return &cloudwatchlogs.DescribeLogGroupsOutput{}, nil
+}
+
+func (s *stubLogsService) AssociateKmsKey(in *cloudwatchlogs.AssociateKmsKeyInput) (*cloudwatchlogs.AssociateKmsKeyOutput, error) {
+	if s.akk != nil {
+		return s.akk(in)
+	}
+	return nil, nil
+}
+
+func (s *stubLogsService) ListTagsLogGroup(in *cloudwatchlogs.ListTagsLogGroupInput) (*cloudwatchlogs.ListTagsLogGroupOutput, error) {
+	if s.ltl != nil {
+		return s.ltl(in)
+	}
+	return &cloudwatchlogs.ListTagsLogGroupOutput{}, nil
+}
+
+func (s *stubLogsService) TagLogGroup(in *cloudwatchlogs.TagLogGroupInput) (*cloudwatchlogs.TagLogGroupOutput, error) {
+	if s.tlg != nil {
+		return s.tlg(in)
+	}
+	return nil, nil
+}
+
 func TestAddSingleEvent_WithAccountId(t *testing.T) {
 	t.Parallel()
 	var wg sync.WaitGroup
@@ -649,13 +699,14 @@ func testPreparation(
 	stop := make(chan struct{})
 	logger := testutil.Logger{Name: "test"}
 	tm := NewTargetManager(logger, service)
-	s := newSender(logger, service, tm, retryDuration, stop)
+	s := newSender(context.Background(), logger, service, tm, retryDuration, stop)
 	q := newQueue(
 		logger,
 		Target{"G", "S", util.StandardLogGroupClass, retention},
 		flushTimeout,
 		entityProvider,
 		s,
+		nil,
 		stop,
 		wg,
 	)
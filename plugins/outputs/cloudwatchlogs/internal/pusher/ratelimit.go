@@ -0,0 +1,128 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package pusher
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+// RateLimits are the PutLogEvents requests per second allowed. Zero is unlimited.
+type RateLimits struct {
+	// Quota is the rate of all the log groups, e.g. the share of the PutLogEvents quota of the account in the
+	// region for the agent.
+	Quota float64
+	// Default is the rate of each log group without its own rate in LogGroups.
+	Default float64
+	// LogGroups are the rates of the log groups.
+	LogGroups map[string]float64
+}
+
+// IsEnabled returns true if any of the rates is limited.
+func (l RateLimits) IsEnabled() bool {
+	if l.Quota > 0 || l.Default > 0 {
+		return true
+	}
+	for _, r := range l.LogGroups {
+		if r > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimiter is a token bucket per log group, and one for all the log groups, shared by the pushers of the
+// same account and region. The burst of a bucket is a second of requests.
+type RateLimiter struct {
+	limits RateLimits
+	quota  *rate.Limiter
+
+	mu     sync.Mutex
+	groups map[string]*rate.Limiter
+}
+
+// NewRateLimiter creates a RateLimiter, or returns nil if the rates are unlimited.
+func NewRateLimiter(limits RateLimits) *RateLimiter {
+	if !limits.IsEnabled() {
+		return nil
+	}
+	r := &RateLimiter{limits: limits, groups: make(map[string]*rate.Limiter)}
+	if limits.Quota > 0 {
+		r.quota = newLimiter(limits.Quota)
+	}
+	return r
+}
+
+func newLimiter(requestsPerSecond float64) *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(requestsPerSecond), int(math.Max(1, math.Ceil(requestsPerSecond))))
+}
+
+// limiter returns the token bucket of the log group, or nil if its rate is unlimited.
+func (r *RateLimiter) limiter(group string) *rate.Limiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if l, ok := r.groups[group]; ok {
+		return l
+	}
+	requestsPerSecond, ok := r.limits.LogGroups[group]
+	if !ok {
+		requestsPerSecond = r.limits.Default
+	}
+	var l *rate.Limiter
+	if requestsPerSecond > 0 {
+		l = newLimiter(requestsPerSecond)
+	}
+	r.groups[group] = l
+	return l
+}
+
+// Wait blocks until a request to the log group is allowed by the rate of the log group and the quota. It returns
+// false if the request is not delayed. The request is allowed right away once stop is closed, so the last batches
+// are not held back during shutdown.
+func (r *RateLimiter) Wait(group string, stop <-chan struct{}) bool {
+	now := time.Now()
+	var delay time.Duration
+	for _, l := range []*rate.Limiter{r.limiter(group), r.quota} {
+		if l != nil {
+			delay = max(delay, l.ReserveN(now, 1).DelayFrom(now))
+		}
+	}
+	if delay <= 0 {
+		return false
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-stop:
+	}
+	return true
+}
+
+// rateLimitedSender delays the batches of the log groups above their rate before they are sent. The wait is in the
+// goroutine of the queue, so the batches held back do not take the workers of the pool from the other log groups.
+type rateLimitedSender struct {
+	Sender
+	limiter *RateLimiter
+	stop    <-chan struct{}
+}
+
+var _ Sender = (*rateLimitedSender)(nil)
+
+func newRateLimitedSender(sender Sender, limiter *RateLimiter, stop <-chan struct{}) Sender {
+	return &rateLimitedSender{Sender: sender, limiter: limiter, stop: stop}
+}
+
+// Send waits for the rate of the log group of the batch and sends it.
+func (s *rateLimitedSender) Send(batch *logEventBatch) {
+	if len(batch.events) > 0 && s.limiter.Wait(batch.Group, s.stop) {
+		selftelemetry.Add(selftelemetry.MetricDeferredBatches, "cloudwatchlogs", 1)
+	}
+	s.Sender.Send(batch)
+}
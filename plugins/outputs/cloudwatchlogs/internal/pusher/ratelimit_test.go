@@ -0,0 +1,101 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package pusher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+type mockSender struct {
+	mock.Mock
+}
+
+func (m *mockSender) Send(batch *logEventBatch) {
+	m.Called(batch)
+}
+
+func (m *mockSender) SetRetryDuration(duration time.Duration) {
+	m.Called(duration)
+}
+
+func (m *mockSender) RetryDuration() time.Duration {
+	return m.Called().Get(0).(time.Duration)
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	assert.Nil(t, NewRateLimiter(RateLimits{}))
+	assert.Nil(t, NewRateLimiter(RateLimits{LogGroups: map[string]float64{"G": 0}}))
+	assert.NotNil(t, NewRateLimiter(RateLimits{Quota: 1}))
+	assert.NotNil(t, NewRateLimiter(RateLimits{Default: 1}))
+	assert.NotNil(t, NewRateLimiter(RateLimits{LogGroups: map[string]float64{"G": 1}}))
+}
+
+func TestRateLimiter(t *testing.T) {
+	stop := make(chan struct{})
+	r := NewRateLimiter(RateLimits{Default: 100, LogGroups: map[string]float64{"chatty": 5, "unlimited": 0}})
+
+	// the burst is a second of requests
+	for i := 0; i < 5; i++ {
+		assert.False(t, r.Wait("chatty", stop))
+	}
+	start := time.Now()
+	assert.True(t, r.Wait("chatty", stop))
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+
+	// the other log groups are not held back by the chatty one
+	for i := 0; i < 10; i++ {
+		assert.False(t, r.Wait("other", stop))
+		assert.False(t, r.Wait("unlimited", stop))
+	}
+
+	// the batches are not delayed during shutdown
+	close(stop)
+	start = time.Now()
+	assert.True(t, r.Wait("chatty", stop))
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestRateLimiterQuota(t *testing.T) {
+	stop := make(chan struct{})
+	r := NewRateLimiter(RateLimits{Quota: 5})
+	for _, group := range []string{"A", "B", "C", "D", "E"} {
+		assert.False(t, r.Wait(group, stop))
+	}
+	start := time.Now()
+	assert.True(t, r.Wait("F", stop))
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestRateLimitedSender(t *testing.T) {
+	deferred := func() float64 {
+		for _, sample := range selftelemetry.Default.Collect() {
+			if sample.Name == selftelemetry.MetricDeferredBatches && sample.Component == "cloudwatchlogs" {
+				return sample.Value
+			}
+		}
+		return 0
+	}
+	before := deferred()
+
+	stop := make(chan struct{})
+	inner := new(mockSender)
+	inner.On("Send", mock.Anything).Return()
+	s := newRateLimitedSender(inner, NewRateLimiter(RateLimits{Default: 10}), stop)
+
+	// the empty batches do not take a request
+	s.Send(newLogEventBatch(Target{Group: "G", Stream: "S"}, nil))
+	for i := 0; i < 11; i++ {
+		batch := newLogEventBatch(Target{Group: "G", Stream: "S"}, nil)
+		batch.append(newLogEvent(time.Now(), "test", nil))
+		s.Send(batch)
+	}
+	inner.AssertNumberOfCalls(t, "Send", 12)
+	assert.Equal(t, before+1, deferred())
+}
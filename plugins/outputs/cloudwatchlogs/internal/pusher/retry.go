@@ -108,6 +108,15 @@ func chooseRetryWaitStrategy(err error) retryWaitStrategy {
 	return retryShort
 }
 
+// isErrThrottle returns true if the request was throttled, e.g. above the PutLogEvents quota of the account.
+func isErrThrottle(err error) bool {
+	if request.IsErrorThrottle(err) {
+		return true
+	}
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == cloudwatchlogs.ErrCodeThrottlingException
+}
+
 func isErrConnectionTimeout(err error) bool {
 	var netErr net.Error
 	return errors.As(err, &netErr) && netErr.Timeout()
@@ -4,21 +4,31 @@
 package pusher
 
 import (
+	"context"
 	"errors"
 	"sync/atomic"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/influxdata/telegraf"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
 )
 
 type cloudWatchLogsService interface {
-	PutLogEvents(*cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error)
+	PutLogEventsWithContext(aws.Context, *cloudwatchlogs.PutLogEventsInput, ...request.Option) (*cloudwatchlogs.PutLogEventsOutput, error)
 	CreateLogStream(input *cloudwatchlogs.CreateLogStreamInput) (*cloudwatchlogs.CreateLogStreamOutput, error)
 	CreateLogGroup(input *cloudwatchlogs.CreateLogGroupInput) (*cloudwatchlogs.CreateLogGroupOutput, error)
 	PutRetentionPolicy(input *cloudwatchlogs.PutRetentionPolicyInput) (*cloudwatchlogs.PutRetentionPolicyOutput, error)
+	DescribeIndexPolicies(input *cloudwatchlogs.DescribeIndexPoliciesInput) (*cloudwatchlogs.DescribeIndexPoliciesOutput, error)
+	PutIndexPolicy(input *cloudwatchlogs.PutIndexPolicyInput) (*cloudwatchlogs.PutIndexPolicyOutput, error)
+	DescribeLogGroups(input *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
+	AssociateKmsKey(input *cloudwatchlogs.AssociateKmsKeyInput) (*cloudwatchlogs.AssociateKmsKeyOutput, error)
+	ListTagsLogGroup(input *cloudwatchlogs.ListTagsLogGroupInput) (*cloudwatchlogs.ListTagsLogGroupOutput, error)
+	TagLogGroup(input *cloudwatchlogs.TagLogGroupInput) (*cloudwatchlogs.TagLogGroupOutput, error)
 }
 
 type Sender interface {
@@ -28,6 +38,7 @@ type Sender interface {
 }
 
 type sender struct {
+	ctx           context.Context
 	service       cloudWatchLogsService
 	retryDuration atomic.Value
 	targetManager TargetManager
@@ -36,6 +47,7 @@ type sender struct {
 }
 
 func newSender(
+	ctx context.Context,
 	logger telegraf.Logger,
 	service cloudWatchLogsService,
 	targetManager TargetManager,
@@ -43,6 +55,7 @@ func newSender(
 	stop <-chan struct{},
 ) Sender {
 	s := &sender{
+		ctx:           ctx,
 		logger:        logger,
 		service:       service,
 		targetManager: targetManager,
@@ -60,11 +73,17 @@ func (s *sender) Send(batch *logEventBatch) {
 	}
 	input := batch.build()
 	startTime := time.Now()
+	published := false
+	defer func() {
+		if !published {
+			selftelemetry.Add(selftelemetry.MetricDroppedEvents, "cloudwatchlogs", float64(len(batch.events)))
+		}
+	}()
 
 	retryCountShort := 0
 	retryCountLong := 0
 	for {
-		output, err := s.service.PutLogEvents(input)
+		output, err := s.service.PutLogEventsWithContext(s.ctx, input)
 		if err == nil {
 			if output.RejectedLogEventsInfo != nil {
 				info := output.RejectedLogEventsInfo
@@ -79,10 +98,21 @@ func (s *sender) Send(batch *logEventBatch) {
 				}
 			}
 			batch.done()
+			published = true
+			selftelemetry.Add(selftelemetry.MetricExportedBatches, "cloudwatchlogs", 1)
 			s.logger.Debugf("Pusher published %v log events to group: %v stream: %v with size %v KB in %v.", len(batch.events), batch.Group, batch.Stream, batch.bufferedSize/1024, time.Since(startTime))
 			return
 		}
 
+		if s.ctx.Err() != nil {
+			s.logger.Errorf("Request to %v/%v canceled while shutting down after %v retries, %v log events dropped.", batch.Group, batch.Stream, retryCountShort+retryCountLong, len(batch.events))
+			return
+		}
+
+		if isErrThrottle(err) {
+			selftelemetry.Add(selftelemetry.MetricThrottledBatches, "cloudwatchlogs", 1)
+		}
+
 		var awsErr awserr.Error
 		if !errors.As(err, &awsErr) {
 			s.logger.Errorf("Non aws error received when sending logs to %v/%v: %v. CloudWatch agent will not retry and logs will be missing!", batch.Group, batch.Stream, err)
@@ -95,8 +125,11 @@ func (s *sender) Send(batch *logEventBatch) {
 				s.logger.Errorf("Unable to create log stream %v/%v: %v", batch.Group, batch.Stream, targetErr)
 				break
 			}
-		case *cloudwatchlogs.InvalidParameterException,
-			*cloudwatchlogs.DataAlreadyAcceptedException:
+		case *cloudwatchlogs.DataAlreadyAcceptedException:
+			published = true
+			s.logger.Errorf("%v, will not retry the request", e)
+			return
+		case *cloudwatchlogs.InvalidParameterException:
 			s.logger.Errorf("%v, will not retry the request", e)
 			return
 		default:
@@ -121,6 +154,9 @@ func (s *sender) Send(batch *logEventBatch) {
 		s.logger.Warnf("Retried %v time, going to sleep %v before retrying.", retryCountShort+retryCountLong-1, wait)
 
 		select {
+		case <-s.ctx.Done():
+			s.logger.Errorf("Request to %v/%v canceled while shutting down after %v retries, %v log events dropped.", batch.Group, batch.Stream, retryCountShort+retryCountLong-1, len(batch.events))
+			return
 		case <-s.stop:
 			s.logger.Errorf("Stop requested after %v retries to %v/%v failed for PutLogEvents, request dropped.", retryCountShort+retryCountLong-1, batch.Group, batch.Stream)
 			return
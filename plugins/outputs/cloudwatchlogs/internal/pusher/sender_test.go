@@ -4,15 +4,20 @@
 package pusher
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
 )
 
@@ -20,8 +25,8 @@ type mockLogsService struct {
 	mock.Mock
 }
 
-func (m *mockLogsService) PutLogEvents(input *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
-	args := m.Called(input)
+func (m *mockLogsService) PutLogEventsWithContext(_ aws.Context, input *cloudwatchlogs.PutLogEventsInput, _ ...request.Option) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	args := m.MethodCalled("PutLogEvents", input)
 	return args.Get(0).(*cloudwatchlogs.PutLogEventsOutput), args.Error(1)
 }
 
@@ -40,6 +45,36 @@ func (m *mockLogsService) PutRetentionPolicy(input *cloudwatchlogs.PutRetentionP
 	return args.Get(0).(*cloudwatchlogs.PutRetentionPolicyOutput), args.Error(1)
 }
 
+func (m *mockLogsService) DescribeIndexPolicies(input *cloudwatchlogs.DescribeIndexPoliciesInput) (*cloudwatchlogs.DescribeIndexPoliciesOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*cloudwatchlogs.DescribeIndexPoliciesOutput), args.Error(1)
+}
+
+func (m *mockLogsService) PutIndexPolicy(input *cloudwatchlogs.PutIndexPolicyInput) (*cloudwatchlogs.PutIndexPolicyOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*cloudwatchlogs.PutIndexPolicyOutput), args.Error(1)
+}
+
+func (m *mockLogsService) DescribeLogGroups(input *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*cloudwatchlogs.DescribeLogGroupsOutput), args.Error(1)
+}
+
+func (m *mockLogsService) AssociateKmsKey(input *cloudwatchlogs.AssociateKmsKeyInput) (*cloudwatchlogs.AssociateKmsKeyOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*cloudwatchlogs.AssociateKmsKeyOutput), args.Error(1)
+}
+
+func (m *mockLogsService) ListTagsLogGroup(input *cloudwatchlogs.ListTagsLogGroupInput) (*cloudwatchlogs.ListTagsLogGroupOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*cloudwatchlogs.ListTagsLogGroupOutput), args.Error(1)
+}
+
+func (m *mockLogsService) TagLogGroup(input *cloudwatchlogs.TagLogGroupInput) (*cloudwatchlogs.TagLogGroupOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*cloudwatchlogs.TagLogGroupOutput), args.Error(1)
+}
+
 type mockTargetManager struct {
 	mock.Mock
 }
@@ -53,6 +88,28 @@ func (m *mockTargetManager) PutRetentionPolicy(target Target) {
 	m.Called(target)
 }
 
+func (m *mockTargetManager) PutIndexPolicy(group string, fields []string) {
+	m.Called(group, fields)
+}
+
+func (m *mockTargetManager) PutLogGroupSettings(group string, settings logs.LogGroupSettings) {
+	m.Called(group, settings)
+}
+
+func (m *mockTargetManager) ReapplyLogGroupSettings() {
+	m.Called()
+}
+
+// droppedEvents returns the events dropped by the pushers reported in the self telemetry.
+func droppedEvents() float64 {
+	for _, sample := range selftelemetry.Default.Collect() {
+		if sample.Name == selftelemetry.MetricDroppedEvents && sample.Component == "cloudwatchlogs" {
+			return sample.Value
+		}
+	}
+	return 0
+}
+
 func TestSender(t *testing.T) {
 	logger := testutil.Logger{Name: "test"}
 
@@ -70,7 +127,7 @@ func TestSender(t *testing.T) {
 		mockManager := new(mockTargetManager)
 		mockService.On("PutLogEvents", mock.Anything).Return(&cloudwatchlogs.PutLogEventsOutput{RejectedLogEventsInfo: rejectedInfo}, nil).Once()
 
-		s := newSender(logger, mockService, mockManager, time.Second, make(chan struct{}))
+		s := newSender(context.Background(), logger, mockService, mockManager, time.Second, make(chan struct{}))
 		s.Send(batch)
 
 		mockService.AssertExpectations(t)
@@ -88,7 +145,7 @@ func TestSender(t *testing.T) {
 		mockManager.On("InitTarget", mock.Anything).Return(nil).Once()
 		mockService.On("PutLogEvents", mock.Anything).Return(&cloudwatchlogs.PutLogEventsOutput{}, nil).Once()
 
-		s := newSender(logger, mockService, mockManager, time.Second, make(chan struct{}))
+		s := newSender(context.Background(), logger, mockService, mockManager, time.Second, make(chan struct{}))
 		s.Send(batch)
 
 		mockService.AssertExpectations(t)
@@ -104,10 +161,12 @@ func TestSender(t *testing.T) {
 		mockService.On("PutLogEvents", mock.Anything).
 			Return(&cloudwatchlogs.PutLogEventsOutput{}, &cloudwatchlogs.InvalidParameterException{}).Once()
 
-		s := newSender(logger, mockService, mockManager, time.Second, make(chan struct{}))
+		dropped := droppedEvents()
+		s := newSender(context.Background(), logger, mockService, mockManager, time.Second, make(chan struct{}))
 		s.Send(batch)
 
 		mockService.AssertExpectations(t)
+		assert.Equal(t, float64(1), droppedEvents()-dropped)
 	})
 
 	t.Run("Error/DataAlreadyAccepted", func(t *testing.T) {
@@ -119,10 +178,12 @@ func TestSender(t *testing.T) {
 		mockService.On("PutLogEvents", mock.Anything).
 			Return(&cloudwatchlogs.PutLogEventsOutput{}, &cloudwatchlogs.DataAlreadyAcceptedException{}).Once()
 
-		s := newSender(logger, mockService, mockManager, time.Second, make(chan struct{}))
+		dropped := droppedEvents()
+		s := newSender(context.Background(), logger, mockService, mockManager, time.Second, make(chan struct{}))
 		s.Send(batch)
 
 		mockService.AssertExpectations(t)
+		assert.Equal(t, float64(0), droppedEvents()-dropped)
 	})
 
 	t.Run("Error/DropOnGeneric", func(t *testing.T) {
@@ -134,7 +195,7 @@ func TestSender(t *testing.T) {
 		mockService.On("PutLogEvents", mock.Anything).
 			Return(&cloudwatchlogs.PutLogEventsOutput{}, errors.New("test")).Once()
 
-		s := newSender(logger, mockService, mockManager, time.Second, make(chan struct{}))
+		s := newSender(context.Background(), logger, mockService, mockManager, time.Second, make(chan struct{}))
 		s.Send(batch)
 
 		mockService.AssertExpectations(t)
@@ -151,7 +212,7 @@ func TestSender(t *testing.T) {
 		mockService.On("PutLogEvents", mock.Anything).
 			Return(&cloudwatchlogs.PutLogEventsOutput{}, nil).Once()
 
-		s := newSender(logger, mockService, mockManager, time.Second, make(chan struct{}))
+		s := newSender(context.Background(), logger, mockService, mockManager, time.Second, make(chan struct{}))
 		s.Send(batch)
 
 		mockService.AssertExpectations(t)
@@ -166,7 +227,7 @@ func TestSender(t *testing.T) {
 		mockService.On("PutLogEvents", mock.Anything).
 			Return(&cloudwatchlogs.PutLogEventsOutput{}, awserr.New("SomeAWSError", "Some AWS error", nil)).Once()
 
-		s := newSender(logger, mockService, mockManager, 100*time.Millisecond, make(chan struct{}))
+		s := newSender(context.Background(), logger, mockService, mockManager, 100*time.Millisecond, make(chan struct{}))
 		s.Send(batch)
 
 		mockService.AssertExpectations(t)
@@ -182,7 +243,7 @@ func TestSender(t *testing.T) {
 			Return(&cloudwatchlogs.PutLogEventsOutput{}, awserr.New("SomeAWSError", "Some AWS error", nil)).Once()
 
 		stopCh := make(chan struct{})
-		s := newSender(logger, mockService, mockManager, time.Second, stopCh)
+		s := newSender(context.Background(), logger, mockService, mockManager, time.Second, stopCh)
 
 		go func() {
 			time.Sleep(50 * time.Millisecond)
@@ -193,4 +254,42 @@ func TestSender(t *testing.T) {
 
 		mockService.AssertExpectations(t)
 	})
+	t.Run("ContextCanceled", func(t *testing.T) {
+		batch := newLogEventBatch(Target{Group: "G", Stream: "S"}, nil)
+		batch.append(newLogEvent(time.Now(), "Test message", nil))
+
+		mockService := &blockingLogsService{called: make(chan struct{})}
+		mockManager := new(mockTargetManager)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		s := newSender(ctx, logger, mockService, mockManager, time.Hour, make(chan struct{}))
+
+		go func() {
+			<-mockService.called
+			cancel()
+		}()
+
+		done := make(chan struct{})
+		go func() {
+			s.Send(batch)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Send did not return after the context was canceled")
+		}
+	})
+}
+
+// blockingLogsService holds PutLogEvents until the request context is canceled.
+type blockingLogsService struct {
+	mockLogsService
+	called chan struct{}
+}
+
+func (m *blockingLogsService) PutLogEventsWithContext(ctx aws.Context, _ *cloudwatchlogs.PutLogEventsInput, _ ...request.Option) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	close(m.called)
+	<-ctx.Done()
+	return nil, awserr.New(request.CanceledErrorCode, "request context canceled", ctx.Err())
 }
@@ -4,12 +4,15 @@
 package pusher
 
 import (
+	"encoding/json"
+	"slices"
 	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/influxdata/telegraf"
 
+	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
 )
 
@@ -21,6 +24,14 @@ type Target struct {
 type TargetManager interface {
 	InitTarget(target Target) error
 	PutRetentionPolicy(target Target)
+	PutIndexPolicy(group string, fields []string)
+	PutLogGroupSettings(group string, settings logs.LogGroupSettings)
+	ReapplyLogGroupSettings()
+}
+
+// indexPolicyDocument is the policy document of a field index policy.
+type indexPolicyDocument struct {
+	Fields []string `json:"Fields"`
 }
 
 type targetManager struct {
@@ -28,14 +39,22 @@ type targetManager struct {
 	service cloudWatchLogsService
 	// cache of initialized targets
 	cache map[Target]struct{}
-	mu    sync.Mutex
+	// pendingIndexes are the fields to index on the log groups not created yet
+	pendingIndexes map[string][]string
+	mu             sync.Mutex
+	// settings are the tags and KMS keys of the log groups, applied when they are created. They are
+	// guarded by their own lock since the retention policy is put with and without mu held.
+	settings   map[string]logs.LogGroupSettings
+	settingsMu sync.Mutex
 }
 
 func NewTargetManager(logger telegraf.Logger, service cloudWatchLogsService) TargetManager {
 	return &targetManager{
-		logger:  logger,
-		service: service,
-		cache:   make(map[Target]struct{}),
+		logger:         logger,
+		service:        service,
+		cache:          make(map[Target]struct{}),
+		pendingIndexes: make(map[string][]string),
+		settings:       make(map[string]logs.LogGroupSettings),
 	}
 }
 
@@ -49,6 +68,9 @@ func (m *targetManager) InitTarget(target Target) error {
 			return err
 		}
 		m.PutRetentionPolicy(target)
+		if fields, ok := m.pendingIndexes[target.Group]; ok {
+			m.putIndexPolicy(target.Group, fields)
+		}
 		m.cache[target] = struct{}{}
 	}
 	return nil
@@ -62,6 +84,10 @@ func (m *targetManager) createLogGroupAndStream(t Target) error {
 
 	m.logger.Debugf("creating stream fail due to : %v", err)
 	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == cloudwatchlogs.ErrCodeResourceNotFoundException {
+		if m.logGroupSettings(t.Group).Unmanaged {
+			m.logger.Errorf("Log group %v is not managed by the agent and does not exist", t.Group)
+			return err
+		}
 		err = m.createLogGroup(t)
 
 		// attempt to create stream again if group created successfully.
@@ -82,17 +108,20 @@ func (m *targetManager) createLogGroupAndStream(t Target) error {
 }
 
 func (m *targetManager) createLogGroup(t Target) error {
-	var err error
+	input := &cloudwatchlogs.CreateLogGroupInput{
+		LogGroupName: &t.Group,
+	}
 	if t.Class != "" {
-		_, err = m.service.CreateLogGroup(&cloudwatchlogs.CreateLogGroupInput{
-			LogGroupName:  &t.Group,
-			LogGroupClass: &t.Class,
-		})
-	} else {
-		_, err = m.service.CreateLogGroup(&cloudwatchlogs.CreateLogGroupInput{
-			LogGroupName: &t.Group,
-		})
+		input.LogGroupClass = &t.Class
+	}
+	settings := m.logGroupSettings(t.Group)
+	if len(settings.Tags) > 0 {
+		input.Tags = aws.StringMap(settings.Tags)
+	}
+	if settings.KMSKeyID != "" {
+		input.KmsKeyId = aws.String(settings.KMSKeyID)
 	}
+	_, err := m.service.CreateLogGroup(input)
 	return err
 }
 
@@ -111,7 +140,7 @@ func (m *targetManager) createLogStream(t Target) error {
 
 // PutRetentionPolicy tries to set the retention policy for a log group. Does not retry on failure.
 func (m *targetManager) PutRetentionPolicy(t Target) {
-	if t.Retention > 0 {
+	if t.Retention > 0 && !m.logGroupSettings(t.Group).Unmanaged {
 		i := aws.Int64(int64(t.Retention))
 		putRetentionInput := &cloudwatchlogs.PutRetentionPolicyInput{
 			LogGroupName:    &t.Group,
@@ -132,3 +161,178 @@ func (m *targetManager) PutRetentionPolicy(t Target) {
 		}
 	}
 }
+
+// PutIndexPolicy adds the fields to the field index policy of the log group, keeping the fields it already
+// indexes. Does not retry on failure, except if the log group does not exist yet, in which case the policy is
+// put once the log group is created.
+func (m *targetManager) PutIndexPolicy(group string, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.putIndexPolicy(group, fields)
+}
+
+func (m *targetManager) putIndexPolicy(group string, fields []string) {
+	out, err := m.service.DescribeIndexPolicies(&cloudwatchlogs.DescribeIndexPoliciesInput{
+		LogGroupIdentifiers: []*string{aws.String(group)},
+	})
+	if err != nil {
+		m.handleIndexPolicyError(group, fields, err)
+		return
+	}
+	delete(m.pendingIndexes, group)
+	// the policy of the log group overrides the one of the account, so the fields of both are kept
+	var indexed []string
+	for _, policy := range out.IndexPolicies {
+		var document indexPolicyDocument
+		if err = json.Unmarshal([]byte(aws.StringValue(policy.PolicyDocument)), &document); err != nil {
+			m.logger.Warnf("Ignoring the invalid field index policy of log group %v: %v", group, err)
+			continue
+		}
+		indexed = append(indexed, document.Fields...)
+	}
+	merged := indexed
+	for _, field := range fields {
+		if !slices.Contains(merged, field) {
+			merged = append(merged, field)
+		}
+	}
+	if len(merged) == len(indexed) {
+		m.logger.Debugf("Fields %v are already indexed on log group %v", fields, group)
+		return
+	}
+	document, err := json.Marshal(indexPolicyDocument{Fields: merged})
+	if err != nil {
+		m.logger.Errorf("Unable to create the field index policy for log group %v: %v", group, err)
+		return
+	}
+	_, err = m.service.PutIndexPolicy(&cloudwatchlogs.PutIndexPolicyInput{
+		LogGroupIdentifier: aws.String(group),
+		PolicyDocument:     aws.String(string(document)),
+	})
+	if err != nil {
+		m.handleIndexPolicyError(group, fields, err)
+		return
+	}
+	m.logger.Debugf("successfully updated field index policy for log group %v with fields %v", group, merged)
+}
+
+func (m *targetManager) handleIndexPolicyError(group string, fields []string, err error) {
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == cloudwatchlogs.ErrCodeResourceNotFoundException {
+		m.logger.Debugf("Log group %v not created yet: %v", group, err)
+		m.pendingIndexes[group] = fields
+		return
+	}
+	m.logger.Errorf("Unable to put field index policy for log group %v: %v", group, err)
+}
+
+// PutLogGroupSettings sets the tags and the KMS key of the log group. They are applied when the log group is
+// created by the agent, and re-applied now and by ReapplyLogGroupSettings if the existing log group has drifted
+// from them. Does not retry on failure.
+func (m *targetManager) PutLogGroupSettings(group string, settings logs.LogGroupSettings) {
+	m.settingsMu.Lock()
+	m.settings[group] = settings
+	m.settingsMu.Unlock()
+	m.applyLogGroupSettings(group, settings)
+}
+
+// ReapplyLogGroupSettings restores the tags and the KMS keys of the managed log groups changed outside the agent.
+func (m *targetManager) ReapplyLogGroupSettings() {
+	m.settingsMu.Lock()
+	settings := make(map[string]logs.LogGroupSettings, len(m.settings))
+	for group, s := range m.settings {
+		settings[group] = s
+	}
+	m.settingsMu.Unlock()
+	for group, s := range settings {
+		m.applyLogGroupSettings(group, s)
+	}
+}
+
+func (m *targetManager) logGroupSettings(group string) logs.LogGroupSettings {
+	m.settingsMu.Lock()
+	defer m.settingsMu.Unlock()
+	return m.settings[group]
+}
+
+func (m *targetManager) applyLogGroupSettings(group string, settings logs.LogGroupSettings) {
+	if settings.Unmanaged {
+		return
+	}
+	if settings.KMSKeyID != "" {
+		m.associateKMSKey(group, settings.KMSKeyID)
+	}
+	if len(settings.Tags) > 0 {
+		m.tagLogGroup(group, settings.Tags)
+	}
+}
+
+func (m *targetManager) associateKMSKey(group, kmsKeyID string) {
+	out, err := m.service.DescribeLogGroups(&cloudwatchlogs.DescribeLogGroupsInput{
+		LogGroupNamePrefix: aws.String(group),
+	})
+	if err != nil {
+		m.logger.Errorf("Unable to describe log group %v: %v", group, err)
+		return
+	}
+	var current *cloudwatchlogs.LogGroup
+	for _, logGroup := range out.LogGroups {
+		if aws.StringValue(logGroup.LogGroupName) == group {
+			current = logGroup
+			break
+		}
+	}
+	if current == nil {
+		// the key is associated when the log group is created
+		m.logger.Debugf("Log group %v not created yet", group)
+		return
+	}
+	if aws.StringValue(current.KmsKeyId) == kmsKeyID {
+		return
+	}
+	_, err = m.service.AssociateKmsKey(&cloudwatchlogs.AssociateKmsKeyInput{
+		LogGroupName: aws.String(group),
+		KmsKeyId:     aws.String(kmsKeyID),
+	})
+	if err != nil {
+		m.logger.Errorf("Unable to associate KMS key %v with log group %v: %v", kmsKeyID, group, err)
+		return
+	}
+	m.logger.Infof("Associated KMS key %v with log group %v", kmsKeyID, group)
+}
+
+func (m *targetManager) tagLogGroup(group string, tags map[string]string) {
+	out, err := m.service.ListTagsLogGroup(&cloudwatchlogs.ListTagsLogGroupInput{
+		LogGroupName: aws.String(group),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == cloudwatchlogs.ErrCodeResourceNotFoundException {
+			// the tags are added when the log group is created
+			m.logger.Debugf("Log group %v not created yet: %v", group, err)
+		} else {
+			m.logger.Errorf("Unable to list the tags of log group %v: %v", group, err)
+		}
+		return
+	}
+	// the other tags of the log group are kept
+	drifted := make(map[string]*string)
+	for key, value := range tags {
+		if current, ok := out.Tags[key]; !ok || aws.StringValue(current) != value {
+			drifted[key] = aws.String(value)
+		}
+	}
+	if len(drifted) == 0 {
+		return
+	}
+	_, err = m.service.TagLogGroup(&cloudwatchlogs.TagLogGroupInput{
+		LogGroupName: aws.String(group),
+		Tags:         drifted,
+	})
+	if err != nil {
+		m.logger.Errorf("Unable to tag log group %v: %v", group, err)
+		return
+	}
+	m.logger.Debugf("successfully updated tags %v of log group %v", aws.StringValueMap(drifted), group)
+}
@@ -9,11 +9,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
 )
 
@@ -120,6 +122,152 @@ func TestTargetManager(t *testing.T) {
 		mockService.AssertNotCalled(t, "PutRetentionPolicy", mock.Anything)
 	})
 
+	t.Run("PutIndexPolicy", func(t *testing.T) {
+		mockService := new(mockLogsService)
+		mockService.On("DescribeIndexPolicies", mock.Anything).Return(&cloudwatchlogs.DescribeIndexPoliciesOutput{
+			IndexPolicies: []*cloudwatchlogs.IndexPolicy{
+				{PolicyDocument: aws.String(`{"Fields":["requestId","level"]}`), Source: aws.String(cloudwatchlogs.IndexSourceAccount)},
+			},
+		}, nil).Once()
+		mockService.On("PutIndexPolicy", &cloudwatchlogs.PutIndexPolicyInput{
+			LogGroupIdentifier: aws.String("G"),
+			PolicyDocument:     aws.String(`{"Fields":["requestId","level","traceId"]}`),
+		}).Return(&cloudwatchlogs.PutIndexPolicyOutput{}, nil).Once()
+
+		manager := NewTargetManager(logger, mockService)
+		manager.PutIndexPolicy("G", []string{"level", "traceId"})
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("PutIndexPolicy/AlreadyIndexed", func(t *testing.T) {
+		mockService := new(mockLogsService)
+		mockService.On("DescribeIndexPolicies", mock.Anything).Return(&cloudwatchlogs.DescribeIndexPoliciesOutput{
+			IndexPolicies: []*cloudwatchlogs.IndexPolicy{
+				{PolicyDocument: aws.String(`{"Fields":["level"]}`), Source: aws.String(cloudwatchlogs.IndexSourceLogGroup)},
+			},
+		}, nil).Once()
+
+		manager := NewTargetManager(logger, mockService)
+		manager.PutIndexPolicy("G", []string{"level"})
+
+		mockService.AssertExpectations(t)
+		mockService.AssertNotCalled(t, "PutIndexPolicy", mock.Anything)
+	})
+
+	t.Run("PutIndexPolicy/LogGroupNotFound", func(t *testing.T) {
+		target := Target{Group: "G", Stream: "S"}
+
+		mockService := new(mockLogsService)
+		mockService.On("DescribeIndexPolicies", mock.Anything).
+			Return(&cloudwatchlogs.DescribeIndexPoliciesOutput{}, awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "Log group not found", nil)).Once()
+		mockService.On("CreateLogStream", mock.Anything).
+			Return(&cloudwatchlogs.CreateLogStreamOutput{}, awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "Log group not found", nil)).Once()
+		mockService.On("CreateLogGroup", mock.Anything).Return(&cloudwatchlogs.CreateLogGroupOutput{}, nil).Once()
+		mockService.On("CreateLogStream", mock.Anything).Return(&cloudwatchlogs.CreateLogStreamOutput{}, nil).Once()
+		mockService.On("DescribeIndexPolicies", mock.Anything).Return(&cloudwatchlogs.DescribeIndexPoliciesOutput{}, nil).Once()
+		mockService.On("PutIndexPolicy", &cloudwatchlogs.PutIndexPolicyInput{
+			LogGroupIdentifier: aws.String("G"),
+			PolicyDocument:     aws.String(`{"Fields":["level"]}`),
+		}).Return(&cloudwatchlogs.PutIndexPolicyOutput{}, nil).Once()
+
+		manager := NewTargetManager(logger, mockService)
+		manager.PutIndexPolicy("G", []string{"level"})
+		mockService.AssertNotCalled(t, "PutIndexPolicy", mock.Anything)
+
+		assert.NoError(t, manager.InitTarget(target))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("PutIndexPolicy/NoFields", func(t *testing.T) {
+		mockService := new(mockLogsService)
+
+		manager := NewTargetManager(logger, mockService)
+		manager.PutIndexPolicy("G", nil)
+
+		mockService.AssertNotCalled(t, "DescribeIndexPolicies", mock.Anything)
+	})
+
+	t.Run("PutLogGroupSettings/CreateLogGroup", func(t *testing.T) {
+		target := Target{Group: "G", Stream: "S"}
+		notFound := awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "Log group not found", nil)
+
+		mockService := new(mockLogsService)
+		mockService.On("DescribeLogGroups", mock.Anything).Return(&cloudwatchlogs.DescribeLogGroupsOutput{}, nil).Once()
+		mockService.On("ListTagsLogGroup", mock.Anything).Return(&cloudwatchlogs.ListTagsLogGroupOutput{}, notFound).Once()
+		mockService.On("CreateLogStream", mock.Anything).Return(&cloudwatchlogs.CreateLogStreamOutput{}, notFound).Once()
+		mockService.On("CreateLogGroup", &cloudwatchlogs.CreateLogGroupInput{
+			LogGroupName: aws.String("G"),
+			KmsKeyId:     aws.String("key"),
+			Tags:         map[string]*string{"team": aws.String("platform")},
+		}).Return(&cloudwatchlogs.CreateLogGroupOutput{}, nil).Once()
+		mockService.On("CreateLogStream", mock.Anything).Return(&cloudwatchlogs.CreateLogStreamOutput{}, nil).Once()
+
+		manager := NewTargetManager(logger, mockService)
+		manager.PutLogGroupSettings("G", logs.LogGroupSettings{Tags: map[string]string{"team": "platform"}, KMSKeyID: "key"})
+
+		assert.NoError(t, manager.InitTarget(target))
+		mockService.AssertExpectations(t)
+		mockService.AssertNotCalled(t, "AssociateKmsKey", mock.Anything)
+		mockService.AssertNotCalled(t, "TagLogGroup", mock.Anything)
+	})
+
+	t.Run("PutLogGroupSettings/Drift", func(t *testing.T) {
+		mockService := new(mockLogsService)
+		mockService.On("DescribeLogGroups", mock.Anything).Return(&cloudwatchlogs.DescribeLogGroupsOutput{
+			LogGroups: []*cloudwatchlogs.LogGroup{
+				{LogGroupName: aws.String("G-other"), KmsKeyId: aws.String("key")},
+				{LogGroupName: aws.String("G")},
+			},
+		}, nil).Once()
+		mockService.On("AssociateKmsKey", &cloudwatchlogs.AssociateKmsKeyInput{
+			LogGroupName: aws.String("G"),
+			KmsKeyId:     aws.String("key"),
+		}).Return(&cloudwatchlogs.AssociateKmsKeyOutput{}, nil).Once()
+		mockService.On("ListTagsLogGroup", mock.Anything).Return(&cloudwatchlogs.ListTagsLogGroupOutput{
+			Tags: map[string]*string{"team": aws.String("other"), "owner": aws.String("me"), "env": aws.String("prod")},
+		}, nil).Once()
+		mockService.On("TagLogGroup", &cloudwatchlogs.TagLogGroupInput{
+			LogGroupName: aws.String("G"),
+			Tags:         map[string]*string{"team": aws.String("platform")},
+		}).Return(&cloudwatchlogs.TagLogGroupOutput{}, nil).Once()
+
+		manager := NewTargetManager(logger, mockService)
+		manager.PutLogGroupSettings("G", logs.LogGroupSettings{Tags: map[string]string{"team": "platform", "env": "prod"}, KMSKeyID: "key"})
+		mockService.AssertExpectations(t)
+
+		// nothing to re-apply once in sync
+		mockService.On("DescribeLogGroups", mock.Anything).Return(&cloudwatchlogs.DescribeLogGroupsOutput{
+			LogGroups: []*cloudwatchlogs.LogGroup{{LogGroupName: aws.String("G"), KmsKeyId: aws.String("key")}},
+		}, nil).Once()
+		mockService.On("ListTagsLogGroup", mock.Anything).Return(&cloudwatchlogs.ListTagsLogGroupOutput{
+			Tags: map[string]*string{"team": aws.String("platform"), "env": aws.String("prod")},
+		}, nil).Once()
+		manager.ReapplyLogGroupSettings()
+		mockService.AssertExpectations(t)
+		mockService.AssertNumberOfCalls(t, "AssociateKmsKey", 1)
+		mockService.AssertNumberOfCalls(t, "TagLogGroup", 1)
+	})
+
+	t.Run("PutLogGroupSettings/Unmanaged", func(t *testing.T) {
+		target := Target{Group: "G", Stream: "S", Retention: 7}
+
+		mockService := new(mockLogsService)
+		mockService.On("CreateLogStream", mock.Anything).
+			Return(&cloudwatchlogs.CreateLogStreamOutput{}, awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "Log group not found", nil)).Once()
+
+		manager := NewTargetManager(logger, mockService)
+		manager.PutLogGroupSettings("G", logs.LogGroupSettings{Tags: map[string]string{"team": "platform"}, Unmanaged: true})
+		manager.PutRetentionPolicy(target)
+		manager.ReapplyLogGroupSettings()
+
+		assert.Error(t, manager.InitTarget(target))
+		mockService.AssertExpectations(t)
+		mockService.AssertNotCalled(t, "CreateLogGroup", mock.Anything)
+		mockService.AssertNotCalled(t, "PutRetentionPolicy", mock.Anything)
+		mockService.AssertNotCalled(t, "ListTagsLogGroup", mock.Anything)
+	})
+
 	t.Run("ConcurrentInit", func(t *testing.T) {
 		targets := []Target{
 			{Group: "G1", Stream: "S1"},
@@ -0,0 +1,102 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlpmetrics
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/compression"
+)
+
+const (
+	ProtocolGRPC = "grpc"
+	ProtocolHTTP = "http"
+
+	// CompressionNone disables the request compression.
+	CompressionNone = "none"
+)
+
+// Config represent a configuration for the OTLP metrics and traces exporters.
+type Config struct {
+	exporterhelper.TimeoutSettings `mapstructure:",squash"`
+	// QueueSettings buffers the requests while the endpoint is unavailable.
+	QueueSettings exporterhelper.QueueSettings `mapstructure:"sending_queue"`
+	// BackOffConfig retries the requests that failed with a retryable error.
+	BackOffConfig configretry.BackOffConfig `mapstructure:"retry_on_failure"`
+
+	// Endpoint is the host:port of the gRPC server or the base URL of the
+	// HTTP server. The /v1/metrics or /v1/traces path is appended to the
+	// HTTP base URL.
+	Endpoint string `mapstructure:"endpoint"`
+	// Protocol is either grpc or http.
+	Protocol string `mapstructure:"protocol"`
+	// Compression is the codec used for the request bodies. The HTTP protocol
+	// supports the codecs of the compression package, gRPC only supports gzip.
+	Compression string `mapstructure:"compression"`
+	// TLSSetting configures the client TLS. The system roots are used if no
+	// CA is set.
+	TLSSetting configtls.ClientConfig `mapstructure:"tls"`
+	// Headers are added to every request. Secrets should be read from SSM
+	// instead, since the headers are written to the translated config.
+	Headers map[string]string `mapstructure:"headers,omitempty"`
+	// HeadersSSM maps the header names to the SSM parameters holding their
+	// values, e.g. SecureString API keys. The parameters are read when the
+	// exporter starts and override the static headers with the same name.
+	HeadersSSM map[string]string `mapstructure:"headers_ssm,omitempty"`
+
+	// The AWS settings are only used to read the SSM parameters.
+	Region                   string `mapstructure:"region,omitempty"`
+	AccessKey                string `mapstructure:"access_key,omitempty"`
+	SecretKey                string `mapstructure:"secret_key,omitempty"`
+	RoleARN                  string `mapstructure:"role_arn,omitempty"`
+	Profile                  string `mapstructure:"profile,omitempty"`
+	SharedCredentialFilename string `mapstructure:"shared_credential_file,omitempty"`
+	Token                    string `mapstructure:"token,omitempty"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the exporter configuration is valid.
+func (c *Config) Validate() error {
+	if c.Endpoint == "" {
+		return errors.New("'endpoint' must be set")
+	}
+	switch c.Protocol {
+	case ProtocolGRPC:
+		if c.Compression != CompressionNone && c.Compression != compression.Gzip {
+			return fmt.Errorf("'compression' %q is not supported by the grpc protocol", c.Compression)
+		}
+	case ProtocolHTTP:
+		u, err := url.Parse(c.Endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid 'endpoint': %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("'endpoint' %s must use http or https", c.Endpoint)
+		}
+		if c.Compression != CompressionNone {
+			if _, err = compression.Get(c.Compression); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("'protocol' must be %s or %s", ProtocolGRPC, ProtocolHTTP)
+	}
+	for header, parameter := range c.HeadersSSM {
+		if parameter == "" {
+			return fmt.Errorf("SSM parameter for header %q must be set", header)
+		}
+	}
+	if len(c.HeadersSSM) > 0 && c.Region == "" {
+		return errors.New("'region' must be set to read the SSM parameters")
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlpmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidate(t *testing.T) {
+	testCases := map[string]struct {
+		modify  func(cfg *Config)
+		wantErr bool
+	}{
+		"WithDefaults": {
+			modify: func(*Config) {},
+		},
+		"WithMissingEndpoint": {
+			modify:  func(cfg *Config) { cfg.Endpoint = "" },
+			wantErr: true,
+		},
+		"WithInvalidProtocol": {
+			modify:  func(cfg *Config) { cfg.Protocol = "udp" },
+			wantErr: true,
+		},
+		"WithGRPC/Zstd": {
+			modify:  func(cfg *Config) { cfg.Compression = "zstd" },
+			wantErr: true,
+		},
+		"WithGRPC/None": {
+			modify: func(cfg *Config) { cfg.Compression = CompressionNone },
+		},
+		"WithHTTP/Zstd": {
+			modify: func(cfg *Config) {
+				cfg.Protocol = ProtocolHTTP
+				cfg.Endpoint = "https://otlp.example.com/otlp"
+				cfg.Compression = "zstd"
+			},
+		},
+		"WithHTTP/UnknownCompression": {
+			modify: func(cfg *Config) {
+				cfg.Protocol = ProtocolHTTP
+				cfg.Endpoint = "https://otlp.example.com/otlp"
+				cfg.Compression = "lz4"
+			},
+			wantErr: true,
+		},
+		"WithHTTP/MissingScheme": {
+			modify: func(cfg *Config) {
+				cfg.Protocol = ProtocolHTTP
+				cfg.Endpoint = "otlp.example.com:4318"
+			},
+			wantErr: true,
+		},
+		"WithHeadersSSM": {
+			modify: func(cfg *Config) {
+				cfg.HeadersSSM = map[string]string{"Authorization": "/otlp/api-key"}
+				cfg.Region = "us-east-1"
+			},
+		},
+		"WithHeadersSSM/MissingRegion": {
+			modify: func(cfg *Config) {
+				cfg.HeadersSSM = map[string]string{"Authorization": "/otlp/api-key"}
+			},
+			wantErr: true,
+		},
+		"WithHeadersSSM/EmptyParameter": {
+			modify: func(cfg *Config) {
+				cfg.HeadersSSM = map[string]string{"Authorization": ""}
+				cfg.Region = "us-east-1"
+			},
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = "otlp.example.com:4317"
+			testCase.modify(cfg)
+			if testCase.wantErr {
+				assert.Error(t, cfg.Validate())
+			} else {
+				assert.NoError(t, cfg.Validate())
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package otlpmetrics provides a metric exporter for the OpenTelemetry
+// collector that sends the metrics to an OTLP endpoint over gRPC or HTTP.
+// It is used to deliver the metrics to third parties in parallel with
+// CloudWatch without running a second collector. The otlptraces exporter of
+// the package sends the traces the same way in parallel with X-Ray.
+package otlpmetrics
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/compression"
+)
+
+const (
+	stability = component.StabilityLevelAlpha
+)
+
+var (
+	TypeStr, _       = component.NewType("otlpmetrics")
+	TracesTypeStr, _ = component.NewType("otlptraces")
+)
+
+func NewFactory() exporter.Factory {
+	return exporter.NewFactory(
+		TypeStr,
+		createDefaultConfig,
+		exporter.WithMetrics(createMetricsExporter, stability),
+	)
+}
+
+// NewTracesFactory returns the factory of the exporter sending the traces,
+// which has the same config as the metrics one.
+func NewTracesFactory() exporter.Factory {
+	return exporter.NewFactory(
+		TracesTypeStr,
+		createDefaultConfig,
+		exporter.WithTraces(createTracesExporter, stability),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		TimeoutSettings: exporterhelper.NewDefaultTimeoutSettings(),
+		QueueSettings:   exporterhelper.NewDefaultQueueSettings(),
+		BackOffConfig:   configretry.NewDefaultBackOffConfig(),
+		Protocol:        ProtocolGRPC,
+		Compression:     compression.Gzip,
+	}
+}
+
+func createMetricsExporter(
+	ctx context.Context,
+	settings exporter.CreateSettings,
+	config component.Config,
+) (exporter.Metrics, error) {
+	cfg := config.(*Config)
+	exp := newExporter(cfg, settings.Logger)
+	return exporterhelper.NewMetricsExporter(
+		ctx,
+		settings,
+		config,
+		exp.ConsumeMetrics,
+		exporterhelper.WithStart(exp.Start),
+		exporterhelper.WithShutdown(exp.Shutdown),
+		exporterhelper.WithTimeout(cfg.TimeoutSettings),
+		exporterhelper.WithRetry(cfg.BackOffConfig),
+		exporterhelper.WithQueue(cfg.QueueSettings),
+	)
+}
+
+func createTracesExporter(
+	ctx context.Context,
+	settings exporter.CreateSettings,
+	config component.Config,
+) (exporter.Traces, error) {
+	cfg := config.(*Config)
+	exp := newTracesExporter(cfg, settings.Logger)
+	return exporterhelper.NewTracesExporter(
+		ctx,
+		settings,
+		config,
+		exp.ConsumeTraces,
+		exporterhelper.WithStart(exp.Start),
+		exporterhelper.WithShutdown(exp.Shutdown),
+		exporterhelper.WithTimeout(cfg.TimeoutSettings),
+		exporterhelper.WithRetry(cfg.BackOffConfig),
+		exporterhelper.WithQueue(cfg.QueueSettings),
+	)
+}
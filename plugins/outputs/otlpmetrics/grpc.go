@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlpmetrics
+
+import (
+	"context"
+	"crypto/tls"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/compression"
+)
+
+type grpcSender struct {
+	conn         *grpc.ClientConn
+	client       pmetricotlp.GRPCClient
+	tracesClient ptraceotlp.GRPCClient
+	metadata     metadata.MD
+	options      []grpc.CallOption
+	logger       *zap.Logger
+}
+
+func newGRPCSender(config *Config, tlsConfig *tls.Config, headers map[string]string, logger *zap.Logger) (*grpcSender, error) {
+	creds := insecure.NewCredentials()
+	if !config.TLSSetting.Insecure {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	conn, err := grpc.NewClient(config.Endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	var options []grpc.CallOption
+	if config.Compression == compression.Gzip {
+		options = append(options, grpc.UseCompressor(gzip.Name))
+	}
+	return &grpcSender{
+		conn:         conn,
+		client:       pmetricotlp.NewGRPCClient(conn),
+		tracesClient: ptraceotlp.NewGRPCClient(conn),
+		metadata:     metadata.New(headers),
+		options:      options,
+		logger:       logger,
+	}, nil
+}
+
+func (s *grpcSender) export(ctx context.Context, req pmetricotlp.ExportRequest) error {
+	resp, err := s.client.Export(s.outgoingContext(ctx), req, s.options...)
+	if err != nil {
+		return grpcError(err)
+	}
+	logPartialSuccess(s.logger, resp)
+	return nil
+}
+
+func (s *grpcSender) exportTraces(ctx context.Context, req ptraceotlp.ExportRequest) error {
+	resp, err := s.tracesClient.Export(s.outgoingContext(ctx), req, s.options...)
+	if err != nil {
+		return grpcError(err)
+	}
+	logTracesPartialSuccess(s.logger, resp)
+	return nil
+}
+
+func (s *grpcSender) outgoingContext(ctx context.Context) context.Context {
+	if s.metadata.Len() > 0 {
+		return metadata.NewOutgoingContext(ctx, s.metadata)
+	}
+	return ctx
+}
+
+// grpcError marks the errors that should not be retried as permanent.
+func grpcError(err error) error {
+	if isRetryableCode(status.Code(err)) {
+		return err
+	}
+	return consumererror.NewPermanent(err)
+}
+
+func (s *grpcSender) shutdown() error {
+	return s.conn.Close()
+}
+
+// isRetryableCode follows the OTLP/gRPC specification for the status codes
+// that can be retried.
+func isRetryableCode(code codes.Code) bool {
+	switch code {
+	case codes.Canceled, codes.DeadlineExceeded, codes.Aborted, codes.OutOfRange,
+		codes.Unavailable, codes.DataLoss, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlpmetrics
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.uber.org/zap"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/internal/compression"
+)
+
+const (
+	metricsPath = "/v1/metrics"
+	tracesPath  = "/v1/traces"
+
+	contentTypeProtobuf = "application/x-protobuf"
+	// maxResponseSize bounds the response read from the endpoint.
+	maxResponseSize = 64 * 1024
+)
+
+type httpSender struct {
+	url       string
+	tracesURL string
+	headers   map[string]string
+	client    *http.Client
+	logger    *zap.Logger
+}
+
+// newHTTPSender creates the sender of the exporter with the name, which sends
+// the requests through the proxy of the service.
+func newHTTPSender(config *Config, tlsConfig *tls.Config, headers map[string]string, name, proxyService string, logger *zap.Logger) (*httpSender, error) {
+	var transport http.RoundTripper = &http.Transport{
+		Proxy:           configaws.ProxyFunc(proxyService),
+		TLSClientConfig: tlsConfig,
+	}
+	if config.Compression != CompressionNone {
+		var err error
+		transport, err = compression.NewTransport(transport, name, config.Compression)
+		if err != nil {
+			return nil, err
+		}
+	}
+	endpoint := strings.TrimSuffix(config.Endpoint, "/")
+	return &httpSender{
+		url:       endpoint + metricsPath,
+		tracesURL: endpoint + tracesPath,
+		headers:   headers,
+		client:    &http.Client{Transport: transport},
+		logger:    logger,
+	}, nil
+}
+
+func (s *httpSender) export(ctx context.Context, req pmetricotlp.ExportRequest) error {
+	body, err := req.MarshalProto()
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	respBody, err := s.post(ctx, s.url, body)
+	if err != nil {
+		return err
+	}
+	exportResp := pmetricotlp.NewExportResponse()
+	if err = exportResp.UnmarshalProto(respBody); err == nil {
+		logPartialSuccess(s.logger, exportResp)
+	}
+	return nil
+}
+
+func (s *httpSender) exportTraces(ctx context.Context, req ptraceotlp.ExportRequest) error {
+	body, err := req.MarshalProto()
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	respBody, err := s.post(ctx, s.tracesURL, body)
+	if err != nil {
+		return err
+	}
+	exportResp := ptraceotlp.NewExportResponse()
+	if err = exportResp.UnmarshalProto(respBody); err == nil {
+		logTracesPartialSuccess(s.logger, exportResp)
+	}
+	return nil
+}
+
+// post sends the protobuf request body and returns the body of a successful
+// response.
+func (s *httpSender) post(ctx context.Context, url string, body []byte) ([]byte, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, consumererror.NewPermanent(err)
+	}
+	for name, value := range s.headers {
+		httpReq.Header.Set(name, value)
+	}
+	httpReq.Header.Set("Content-Type", contentTypeProtobuf)
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return respBody, nil
+	}
+	err = fmt.Errorf("OTLP endpoint responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	if isRetryableStatus(resp.StatusCode) {
+		return nil, err
+	}
+	return nil, consumererror.NewPermanent(err)
+}
+
+func (s *httpSender) shutdown() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// isRetryableStatus follows the OTLP/HTTP specification for the status codes
+// that can be retried.
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
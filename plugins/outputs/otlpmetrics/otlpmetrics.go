@@ -0,0 +1,198 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlpmetrics
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.uber.org/zap"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	tlsInternal "github.com/aws/amazon-cloudwatch-agent/internal/tls"
+)
+
+// sender exports a request to the endpoint. Errors that should not be
+// retried are wrapped with consumererror.NewPermanent.
+type sender interface {
+	export(ctx context.Context, req pmetricotlp.ExportRequest) error
+	exportTraces(ctx context.Context, req ptraceotlp.ExportRequest) error
+	shutdown() error
+}
+
+type otlpMetrics struct {
+	config *Config
+	logger *zap.Logger
+	// name and proxyService are those of the exported signal.
+	name         string
+	proxyService string
+	// getParameter reads a decrypted SSM parameter. It is replaced in tests.
+	getParameter parameterGetter
+
+	sender    sender
+	tlsReload *tlsInternal.Provider
+}
+
+func newExporter(config *Config, logger *zap.Logger) *otlpMetrics {
+	return &otlpMetrics{
+		config:       config,
+		logger:       logger,
+		name:         TypeStr.String(),
+		proxyService: configaws.ProxyServiceMetrics,
+		getParameter: newSSMParameterGetter(config),
+	}
+}
+
+// newTracesExporter creates the exporter of the traces, which reaches the
+// endpoint through the proxy of X-Ray.
+func newTracesExporter(config *Config, logger *zap.Logger) *otlpMetrics {
+	o := newExporter(config, logger)
+	o.name = TracesTypeStr.String()
+	o.proxyService = configaws.ProxyServiceXray
+	return o
+}
+
+// Start resolves the headers and connects to the endpoint. An SSM parameter
+// that cannot be read fails the start, since the endpoint would reject every
+// request without it.
+func (o *otlpMetrics) Start(ctx context.Context, _ component.Host) error {
+	headers, err := o.headers(ctx)
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := o.config.TLSSetting.LoadTLSConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to load the TLS config: %w", err)
+	}
+	if tlsConfig, err = o.reloadTLS(tlsConfig); err != nil {
+		return err
+	}
+	if o.config.Protocol == ProtocolHTTP {
+		s, err := newHTTPSender(o.config, tlsConfig, headers, o.name, o.proxyService, o.logger)
+		if err != nil {
+			return err
+		}
+		o.sender = s
+		return nil
+	}
+	s, err := newGRPCSender(o.config, tlsConfig, headers, o.logger)
+	if err != nil {
+		return fmt.Errorf("unable to connect to %s: %w", o.config.Endpoint, err)
+	}
+	o.sender = s
+	return nil
+}
+
+func (o *otlpMetrics) Shutdown(context.Context) error {
+	if o.tlsReload != nil {
+		if err := o.tlsReload.Close(); err != nil {
+			o.logger.Warn("Unable to stop watching the TLS certificates", zap.Error(err))
+		}
+	}
+	if o.sender == nil {
+		return nil
+	}
+	return o.sender.shutdown()
+}
+
+// reloadTLS makes the client use the rotated certificate and CA files without
+// restarting the exporter.
+func (o *otlpMetrics) reloadTLS(tlsConfig *tls.Config) (*tls.Config, error) {
+	setting := o.config.TLSSetting
+	if tlsConfig == nil || setting.Insecure || (setting.CertFile == "" && setting.CAFile == "") {
+		return tlsConfig, nil
+	}
+	var caFiles []string
+	if setting.CAFile != "" {
+		caFiles = []string{setting.CAFile}
+	}
+	provider, err := tlsInternal.NewProvider(setting.CertFile, setting.KeyFile, caFiles)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the TLS config: %w", err)
+	}
+	if err = provider.Start(); err != nil {
+		return nil, err
+	}
+	o.tlsReload = provider
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = o.serverName()
+	}
+	return provider.ApplyClient(tlsConfig), nil
+}
+
+// serverName is the host of the endpoint verified in the server certificate.
+func (o *otlpMetrics) serverName() string {
+	if o.config.Protocol == ProtocolHTTP {
+		if u, err := url.Parse(o.config.Endpoint); err == nil {
+			return u.Hostname()
+		}
+		return ""
+	}
+	// the gRPC target may have a resolver scheme, e.g. dns:///host:port
+	target := o.config.Endpoint
+	if i := strings.LastIndex(target, "/"); i >= 0 {
+		target = target[i+1:]
+	}
+	host, _, err := net.SplitHostPort(target)
+	if err != nil {
+		return target
+	}
+	return host
+}
+
+func (o *otlpMetrics) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	return o.sender.export(ctx, pmetricotlp.NewExportRequestFromMetrics(md))
+}
+
+func (o *otlpMetrics) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	return o.sender.exportTraces(ctx, ptraceotlp.NewExportRequestFromTraces(td))
+}
+
+// headers merges the static headers with the ones read from SSM.
+func (o *otlpMetrics) headers(ctx context.Context) (map[string]string, error) {
+	headers := make(map[string]string, len(o.config.Headers)+len(o.config.HeadersSSM))
+	for name, value := range o.config.Headers {
+		headers[name] = value
+	}
+	for name, parameter := range o.config.HeadersSSM {
+		value, err := o.getParameter(ctx, parameter)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read SSM parameter %s for header %s: %w", parameter, name, err)
+		}
+		headers[name] = value
+	}
+	return headers, nil
+}
+
+// logPartialSuccess logs the data points rejected by the endpoint. They are
+// not retried since the endpoint accepted the rest of the request.
+func logPartialSuccess(logger *zap.Logger, resp pmetricotlp.ExportResponse) {
+	partialSuccess := resp.PartialSuccess()
+	if partialSuccess.RejectedDataPoints() == 0 && partialSuccess.ErrorMessage() == "" {
+		return
+	}
+	logger.Warn("OTLP endpoint partially rejected the metrics",
+		zap.Int64("rejected_data_points", partialSuccess.RejectedDataPoints()),
+		zap.String("message", partialSuccess.ErrorMessage()))
+}
+
+// logTracesPartialSuccess logs the spans rejected by the endpoint.
+func logTracesPartialSuccess(logger *zap.Logger, resp ptraceotlp.ExportResponse) {
+	partialSuccess := resp.PartialSuccess()
+	if partialSuccess.RejectedSpans() == 0 && partialSuccess.ErrorMessage() == "" {
+		return
+	}
+	logger.Warn("OTLP endpoint partially rejected the traces",
+		zap.Int64("rejected_spans", partialSuccess.RejectedSpans()),
+		zap.String("message", partialSuccess.ErrorMessage()))
+}
@@ -0,0 +1,297 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlpmetrics
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func testMetrics() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("cpu_usage_idle")
+	metric.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(99)
+	return md
+}
+
+func testTraces() ptrace.Traces {
+	td := ptrace.NewTraces()
+	span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("GET /orders")
+	return td
+}
+
+func newTestExporter(t *testing.T, cfg *Config) *otlpMetrics {
+	t.Helper()
+	exp := newExporter(cfg, zap.NewNop())
+	exp.getParameter = func(_ context.Context, name string) (string, error) {
+		if name == "/otlp/api-key" {
+			return "Bearer secret", nil
+		}
+		return "", errors.New("parameter not found")
+	}
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, exp.Shutdown(context.Background())) })
+	return exp
+}
+
+func TestExporterHTTP(t *testing.T) {
+	var got pmetricotlp.ExportRequest
+	var gotHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/otlp/v1/metrics", r.URL.Path)
+		gotHeader = r.Header
+		reader, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		got = pmetricotlp.NewExportRequest()
+		require.NoError(t, got.UnmarshalProto(body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = ProtocolHTTP
+	cfg.Endpoint = server.URL + "/otlp/"
+	cfg.Headers = map[string]string{"X-Scope-OrgID": "tenant", "Authorization": "overridden"}
+	cfg.HeadersSSM = map[string]string{"Authorization": "/otlp/api-key"}
+	exp := newTestExporter(t, cfg)
+
+	require.NoError(t, exp.ConsumeMetrics(context.Background(), testMetrics()))
+	assert.Equal(t, 1, got.Metrics().MetricCount())
+	assert.Equal(t, contentTypeProtobuf, gotHeader.Get("Content-Type"))
+	assert.Equal(t, "gzip", gotHeader.Get("Content-Encoding"))
+	assert.Equal(t, "tenant", gotHeader.Get("X-Scope-OrgID"))
+	assert.Equal(t, "Bearer secret", gotHeader.Get("Authorization"))
+}
+
+func TestExporterHTTPTraces(t *testing.T) {
+	var got ptraceotlp.ExportRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/otlp/v1/traces", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		got = ptraceotlp.NewExportRequest()
+		require.NoError(t, got.UnmarshalProto(body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = ProtocolHTTP
+	cfg.Compression = CompressionNone
+	cfg.Endpoint = server.URL + "/otlp"
+	exp := newTracesExporter(cfg, zap.NewNop())
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, exp.Shutdown(context.Background())) }()
+
+	require.NoError(t, exp.ConsumeTraces(context.Background(), testTraces()))
+	assert.Equal(t, 1, got.Traces().SpanCount())
+}
+
+func TestExporterHTTPTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = ProtocolHTTP
+	cfg.Endpoint = server.URL
+	cfg.TLSSetting.CAFile = caFile
+	exp := newTestExporter(t, cfg)
+	// the CA is verified with the certificates reloaded from the file
+	require.NotNil(t, exp.tlsReload)
+	assert.NoError(t, exp.ConsumeMetrics(context.Background(), testMetrics()))
+}
+
+func TestServerName(t *testing.T) {
+	testCases := map[string]struct {
+		protocol string
+		endpoint string
+		want     string
+	}{
+		"WithHTTP":          {protocol: ProtocolHTTP, endpoint: "https://otlp.example.com:4318/otlp", want: "otlp.example.com"},
+		"WithGRPC":          {protocol: ProtocolGRPC, endpoint: "otlp.example.com:4317", want: "otlp.example.com"},
+		"WithGRPCResolver":  {protocol: ProtocolGRPC, endpoint: "dns:///otlp.example.com:4317", want: "otlp.example.com"},
+		"WithGRPCNoPort":    {protocol: ProtocolGRPC, endpoint: "otlp.example.com", want: "otlp.example.com"},
+		"WithGRPCIPAddress": {protocol: ProtocolGRPC, endpoint: "[::1]:4317", want: "::1"},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			exp := newExporter(&Config{Protocol: testCase.protocol, Endpoint: testCase.endpoint}, zap.NewNop())
+			assert.Equal(t, testCase.want, exp.serverName())
+		})
+	}
+}
+
+func TestExporterHTTPErrors(t *testing.T) {
+	testCases := map[string]struct {
+		status        int
+		wantPermanent bool
+	}{
+		"Throttled":   {status: http.StatusTooManyRequests},
+		"Unavailable": {status: http.StatusServiceUnavailable},
+		"BadRequest":  {status: http.StatusBadRequest, wantPermanent: true},
+		"Forbidden":   {status: http.StatusForbidden, wantPermanent: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(testCase.status)
+			}))
+			defer server.Close()
+
+			cfg := createDefaultConfig().(*Config)
+			cfg.Protocol = ProtocolHTTP
+			cfg.Endpoint = server.URL
+			cfg.Compression = CompressionNone
+			exp := newTestExporter(t, cfg)
+
+			err := exp.ConsumeMetrics(context.Background(), testMetrics())
+			require.Error(t, err)
+			assert.Equal(t, testCase.wantPermanent, consumererror.IsPermanent(err))
+		})
+	}
+}
+
+type grpcServer struct {
+	pmetricotlp.UnimplementedGRPCServer
+	err      error
+	requests chan pmetricotlp.ExportRequest
+	metadata chan metadata.MD
+}
+
+func (s *grpcServer) Export(ctx context.Context, req pmetricotlp.ExportRequest) (pmetricotlp.ExportResponse, error) {
+	if s.err != nil {
+		return pmetricotlp.NewExportResponse(), s.err
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.metadata <- md
+	s.requests <- req
+	return pmetricotlp.NewExportResponse(), nil
+}
+
+type grpcTracesServer struct {
+	ptraceotlp.UnimplementedGRPCServer
+	requests chan ptraceotlp.ExportRequest
+}
+
+func (s *grpcTracesServer) Export(_ context.Context, req ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	s.requests <- req
+	return ptraceotlp.NewExportResponse(), nil
+}
+
+func startGRPCServer(t *testing.T, srv *grpcServer) string {
+	t.Helper()
+	return startGRPCServerWith(t, func(server *grpc.Server) {
+		pmetricotlp.RegisterGRPCServer(server, srv)
+	})
+}
+
+func startGRPCServerWith(t *testing.T, register func(*grpc.Server)) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	register(server)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func TestExporterGRPC(t *testing.T) {
+	srv := &grpcServer{
+		requests: make(chan pmetricotlp.ExportRequest, 1),
+		metadata: make(chan metadata.MD, 1),
+	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = startGRPCServer(t, srv)
+	cfg.TLSSetting.Insecure = true
+	cfg.HeadersSSM = map[string]string{"authorization": "/otlp/api-key"}
+	exp := newTestExporter(t, cfg)
+
+	require.NoError(t, exp.ConsumeMetrics(context.Background(), testMetrics()))
+	got := <-srv.requests
+	assert.Equal(t, 1, got.Metrics().MetricCount())
+	assert.Equal(t, []string{"Bearer secret"}, (<-srv.metadata).Get("authorization"))
+}
+
+func TestExporterGRPCTraces(t *testing.T) {
+	srv := &grpcTracesServer{requests: make(chan ptraceotlp.ExportRequest, 1)}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = startGRPCServerWith(t, func(server *grpc.Server) {
+		ptraceotlp.RegisterGRPCServer(server, srv)
+	})
+	cfg.TLSSetting.Insecure = true
+	exp := newTestExporter(t, cfg)
+
+	require.NoError(t, exp.ConsumeTraces(context.Background(), testTraces()))
+	got := <-srv.requests
+	assert.Equal(t, 1, got.Traces().SpanCount())
+}
+
+func TestExporterGRPCErrors(t *testing.T) {
+	testCases := map[string]struct {
+		err           error
+		wantPermanent bool
+	}{
+		"Unavailable":     {err: status.Error(codes.Unavailable, "unavailable")},
+		"InvalidArgument": {err: status.Error(codes.InvalidArgument, "invalid"), wantPermanent: true},
+		"Unauthenticated": {err: status.Error(codes.Unauthenticated, "denied"), wantPermanent: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = startGRPCServer(t, &grpcServer{err: testCase.err})
+			cfg.TLSSetting.Insecure = true
+			exp := newTestExporter(t, cfg)
+
+			err := exp.ConsumeMetrics(context.Background(), testMetrics())
+			require.Error(t, err)
+			assert.Equal(t, testCase.wantPermanent, consumererror.IsPermanent(err))
+		})
+	}
+}
+
+func TestExporterSSMError(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:4317"
+	cfg.HeadersSSM = map[string]string{"authorization": "/otlp/missing"}
+	exp := newExporter(cfg, zap.NewNop())
+	exp.getParameter = func(context.Context, string) (string, error) {
+		return "", errors.New("parameter not found")
+	}
+	assert.ErrorContains(t, exp.Start(context.Background(), componenttest.NewNopHost()), "/otlp/missing")
+	assert.NoError(t, exp.Shutdown(context.Background()))
+}
@@ -0,0 +1,48 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlpmetrics
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+)
+
+// parameterGetter returns the decrypted value of the SSM parameter.
+type parameterGetter func(ctx context.Context, name string) (string, error)
+
+// newSSMParameterGetter creates the client lazily, so the exporter does not
+// need AWS credentials unless a header is read from SSM.
+func newSSMParameterGetter(config *Config) parameterGetter {
+	var svc *ssm.SSM
+	return func(ctx context.Context, name string) (string, error) {
+		if svc == nil {
+			credentialConfig := &configaws.CredentialConfig{
+				Region:       config.Region,
+				AccessKey:    config.AccessKey,
+				SecretKey:    config.SecretKey,
+				RoleARN:      config.RoleARN,
+				Profile:      config.Profile,
+				Filename:     config.SharedCredentialFilename,
+				Token:        config.Token,
+				ProxyService: configaws.ProxyServiceSSM,
+			}
+			svc = ssm.New(credentialConfig.Credentials(), &aws.Config{
+				LogLevel: configaws.SDKLogLevel(),
+				Logger:   configaws.SDKLogger{},
+			})
+		}
+		output, err := svc.GetParameterWithContext(ctx, &ssm.GetParameterInput{
+			Name:           aws.String(name),
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return "", err
+		}
+		return aws.StringValue(output.Parameter.Value), nil
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package s3logs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/apache/arrow/go/v14/parquet"
+	"github.com/apache/arrow/go/v14/parquet/compress"
+	"github.com/apache/arrow/go/v14/parquet/file"
+	"github.com/apache/arrow/go/v14/parquet/schema"
+)
+
+const (
+	EncodingGzip    = "gzip"
+	EncodingParquet = "parquet"
+)
+
+// encoder buffers the log events of an object.
+type encoder interface {
+	// Append adds an event to the object.
+	Append(timestamp time.Time, message string) error
+	// Size is the uncompressed size of the events appended.
+	Size() int
+	// Encode returns the content of the object.
+	Encode() ([]byte, error)
+	// Extension is the suffix of the object key.
+	Extension() string
+	ContentType() string
+}
+
+func newEncoder(encoding string) (encoder, error) {
+	switch encoding {
+	case "", EncodingGzip:
+		return newGzipEncoder(), nil
+	case EncodingParquet:
+		return &parquetEncoder{}, nil
+	}
+	return nil, fmt.Errorf("unsupported encoding %q", encoding)
+}
+
+// record is a line of the gzip encoded objects.
+type record struct {
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+// gzipEncoder compresses the events as JSON lines while they are appended.
+type gzipEncoder struct {
+	buf  bytes.Buffer
+	zw   *gzip.Writer
+	size int
+}
+
+func newGzipEncoder() *gzipEncoder {
+	e := &gzipEncoder{}
+	e.zw = gzip.NewWriter(&e.buf)
+	return e
+}
+
+func (e *gzipEncoder) Append(timestamp time.Time, message string) error {
+	line, err := json.Marshal(record{Timestamp: timestamp.UnixMilli(), Message: message})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	e.size += len(line)
+	_, err = e.zw.Write(line)
+	return err
+}
+
+func (e *gzipEncoder) Size() int {
+	return e.size
+}
+
+func (e *gzipEncoder) Encode() ([]byte, error) {
+	if err := e.zw.Close(); err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+func (e *gzipEncoder) Extension() string {
+	return ".json.gz"
+}
+
+func (e *gzipEncoder) ContentType() string {
+	return "application/gzip"
+}
+
+var parquetSchema = func() *schema.GroupNode {
+	timestamp, err := schema.NewPrimitiveNodeLogical("timestamp", parquet.Repetitions.Required,
+		schema.NewTimestampLogicalType(true, schema.TimeUnitMillis), parquet.Types.Int64, -1, -1)
+	if err != nil {
+		panic(err)
+	}
+	message, err := schema.NewPrimitiveNodeLogical("message", parquet.Repetitions.Required,
+		schema.StringLogicalType{}, parquet.Types.ByteArray, -1, -1)
+	if err != nil {
+		panic(err)
+	}
+	return schema.MustGroup(schema.NewGroupNode("schema", parquet.Repetitions.Required, schema.FieldList{timestamp, message}, -1))
+}()
+
+// parquetEncoder keeps the events in columns and writes them as a single row group, compressed
+// with snappy.
+type parquetEncoder struct {
+	timestamps []int64
+	messages   []parquet.ByteArray
+	size       int
+}
+
+func (e *parquetEncoder) Append(timestamp time.Time, message string) error {
+	e.timestamps = append(e.timestamps, timestamp.UnixMilli())
+	e.messages = append(e.messages, parquet.ByteArray(message))
+	e.size += len(message) + 8
+	return nil
+}
+
+func (e *parquetEncoder) Size() int {
+	return e.size
+}
+
+func (e *parquetEncoder) Encode() ([]byte, error) {
+	var buf bytes.Buffer
+	w := file.NewParquetWriter(&buf, parquetSchema,
+		file.WithWriterProps(parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))))
+	rg := w.AppendRowGroup()
+	cw, err := rg.NextColumn()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = cw.(*file.Int64ColumnChunkWriter).WriteBatch(e.timestamps, nil, nil); err != nil {
+		return nil, err
+	}
+	if err = cw.Close(); err != nil {
+		return nil, err
+	}
+	if cw, err = rg.NextColumn(); err != nil {
+		return nil, err
+	}
+	if _, err = cw.(*file.ByteArrayColumnChunkWriter).WriteBatch(e.messages, nil, nil); err != nil {
+		return nil, err
+	}
+	if err = cw.Close(); err != nil {
+		return nil, err
+	}
+	if err = rg.Close(); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (e *parquetEncoder) Extension() string {
+	return ".parquet"
+}
+
+func (e *parquetEncoder) ContentType() string {
+	return "application/vnd.apache.parquet"
+}
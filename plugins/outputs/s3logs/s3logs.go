@@ -0,0 +1,378 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package s3logs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/outputs"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+const (
+	// Name is the name of the output, and of the log destination.
+	Name = "s3logs"
+
+	defaultPrefix         = "{log_group}/{log_stream}/%Y/%m/%d/%H"
+	defaultUploadInterval = 5 * time.Minute
+	defaultBufferSize     = 64 * 1024 * 1024
+	minPartSize           = s3manager.MinUploadPartSize
+
+	// shutdownGracePeriod is the time the last uploads get before they are canceled.
+	shutdownGracePeriod = 10 * time.Second
+	maxRetryBackoff     = time.Minute
+)
+
+// S3Logs archives the log events to an S3 bucket. The events of each log group and stream are
+// buffered, and uploaded to an object when the buffer is full or after the upload interval.
+// The objects above the part size are uploaded in parts.
+type S3Logs struct {
+	Region           string `toml:"region"`
+	EndpointOverride string `toml:"endpoint_override"`
+	AccessKey        string `toml:"access_key"`
+	SecretKey        string `toml:"secret_key"`
+	RoleARN          string `toml:"role_arn"`
+	Profile          string `toml:"profile"`
+	Filename         string `toml:"shared_credential_file"`
+	Token            string `toml:"token"`
+
+	Bucket string `toml:"bucket"`
+	// Prefix is the template of the object keys. {log_group} and {log_stream} are replaced with the
+	// names of the log group and stream, and %Y, %m, %d, %H and %M with the UTC time of the first
+	// event of the object.
+	Prefix string `toml:"prefix"`
+	// Encoding is gzip for JSON lines compressed with gzip, or parquet.
+	Encoding       string            `toml:"encoding"`
+	UploadInterval internal.Duration `toml:"upload_interval"`
+	// BufferSize is the size of the events of a log stream uploaded to an object, in bytes.
+	BufferSize int `toml:"buffer_size"`
+	// PartSize is the size of the parts of the multipart uploads, in bytes.
+	PartSize int64 `toml:"part_size"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	uploader s3manageriface.UploaderAPI
+	dests    map[target]*s3Dest
+	mu       sync.Mutex
+	seq      atomic.Uint64
+	stop     chan struct{}
+	wg       sync.WaitGroup
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+var _ logs.LogBackend = (*S3Logs)(nil)
+
+type target struct {
+	group  string
+	stream string
+}
+
+func (s *S3Logs) Connect() error {
+	if s.Bucket == "" {
+		return errors.New("s3logs: bucket is not set")
+	}
+	if _, err := newEncoder(s.Encoding); err != nil {
+		return fmt.Errorf("s3logs: %w", err)
+	}
+	if s.UploadInterval.Duration <= 0 {
+		s.UploadInterval.Duration = defaultUploadInterval
+	}
+	if s.BufferSize <= 0 {
+		s.BufferSize = defaultBufferSize
+	}
+	if s.uploader == nil {
+		credentialConfig := &configaws.CredentialConfig{
+			Region:       s.Region,
+			AccessKey:    s.AccessKey,
+			SecretKey:    s.SecretKey,
+			RoleARN:      s.RoleARN,
+			Profile:      s.Profile,
+			Filename:     s.Filename,
+			Token:        s.Token,
+			ProxyService: configaws.ProxyServiceLogs,
+		}
+		client := s3.New(credentialConfig.Credentials(), &aws.Config{
+			Endpoint: aws.String(s.EndpointOverride),
+			LogLevel: configaws.SDKLogLevel(),
+			Logger:   configaws.SDKLogger{},
+		})
+		s.uploader = s3manager.NewUploaderWithClient(client, func(u *s3manager.Uploader) {
+			u.PartSize = max(s.PartSize, minPartSize)
+		})
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.wg.Add(1)
+	go s.uploadLoop()
+	return nil
+}
+
+// Close uploads the events left in the buffers.
+func (s *S3Logs) Close() error {
+	if s.cancel == nil {
+		return nil
+	}
+	close(s.stop)
+	timer := time.AfterFunc(shutdownGracePeriod, s.cancel)
+	defer timer.Stop()
+	s.mu.Lock()
+	for _, d := range s.dests {
+		if b := d.take(); b != nil {
+			s.wg.Add(1)
+			go s.upload(d.target, b)
+		}
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	s.cancel()
+	return nil
+}
+
+func (s *S3Logs) Write([]telegraf.Metric) error {
+	return nil
+}
+
+func (s *S3Logs) CreateDest(group, stream string, _ int, _ string, _ logs.LogSrc) logs.LogDest {
+	return s.getDest(target{group: group, stream: stream})
+}
+
+func (s *S3Logs) getDest(t target) *s3Dest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d, ok := s.dests[t]; ok {
+		return d
+	}
+	d := &s3Dest{target: t, parent: s}
+	s.dests[t] = d
+	return d
+}
+
+// uploadLoop uploads the buffers older than the upload interval.
+func (s *S3Logs) uploadLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(min(time.Second, s.UploadInterval.Duration))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			for _, d := range s.dests {
+				if b := d.takeIfOlder(s.UploadInterval.Duration); b != nil {
+					s.wg.Add(1)
+					go s.upload(d.target, b)
+				}
+			}
+			s.mu.Unlock()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// upload puts the batch to its object, and retries until it is uploaded or the shutdown grace
+// period is over. The events are done once they are uploaded.
+func (s *S3Logs) upload(t target, b *batch) {
+	defer s.wg.Done()
+	body, err := b.encoder.Encode()
+	if err != nil {
+		s.Log.Errorf("Unable to encode %d log events of %s/%s: %v", len(b.events), t.group, t.stream, err)
+		return
+	}
+	input := &s3manager.UploadInput{
+		Bucket:      aws.String(s.Bucket),
+		Key:         aws.String(s.objectKey(t, b)),
+		ContentType: aws.String(b.encoder.ContentType()),
+	}
+	backoff := time.Second
+	for {
+		input.Body = bytes.NewReader(body)
+		_, err = s.uploader.UploadWithContext(s.ctx, input)
+		if err == nil {
+			break
+		}
+		s.Log.Warnf("Unable to upload %d log events of %s/%s to s3://%s/%s: %v", len(b.events), t.group, t.stream, s.Bucket, *input.Key, err)
+		select {
+		case <-time.After(backoff):
+			backoff = min(2*backoff, maxRetryBackoff)
+		case <-s.ctx.Done():
+			s.Log.Errorf("Dropped %d log events of %s/%s after the shutdown", len(b.events), t.group, t.stream)
+			return
+		}
+	}
+	s.Log.Debugf("Uploaded %d log events of %s/%s to s3://%s/%s", len(b.events), t.group, t.stream, s.Bucket, *input.Key)
+	for _, e := range b.events {
+		e.Done()
+	}
+}
+
+// objectKey expands the prefix for the batch, and appends a unique name.
+func (s *S3Logs) objectKey(t target, b *batch) string {
+	first := b.first.UTC()
+	prefix := strings.NewReplacer(
+		"{log_group}", strings.TrimPrefix(t.group, "/"),
+		"{log_stream}", t.stream,
+		"%Y", first.Format("2006"),
+		"%m", first.Format("01"),
+		"%d", first.Format("02"),
+		"%H", first.Format("15"),
+		"%M", first.Format("04"),
+	).Replace(s.Prefix)
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	// the upload time keeps the names unique across restarts
+	name := first.Format("20060102T150405Z") + "-" + strconv.FormatInt(time.Now().UnixNano(), 10) + "-" + strconv.FormatUint(s.seq.Add(1), 10)
+	return prefix + name + b.encoder.Extension()
+}
+
+// batch is the events of an object.
+type batch struct {
+	encoder encoder
+	events  []logs.LogEvent
+	first   time.Time
+	created time.Time
+}
+
+// s3Dest buffers the events of a log group and stream.
+type s3Dest struct {
+	target
+	parent *S3Logs
+
+	mu    sync.Mutex
+	batch *batch
+}
+
+var _ logs.LogDest = (*s3Dest)(nil)
+
+// Publish adds the events to the buffers of their log group and stream. The full buffers are
+// uploaded before Publish returns, which holds back the source while the bucket catches up.
+func (d *s3Dest) Publish(events []logs.LogEvent) error {
+	select {
+	case <-d.parent.stop:
+		return logs.ErrOutputStopped
+	default:
+	}
+	for _, e := range events {
+		dest := d
+		if re, ok := e.(logs.RoutedLogEvent); ok {
+			t := d.target
+			if group := re.Group(); group != "" {
+				t.group = group
+			}
+			if stream := re.Stream(); stream != "" {
+				t.stream = stream
+			}
+			if t != d.target {
+				dest = d.parent.getDest(t)
+			}
+		}
+		b, err := dest.add(e)
+		if err != nil {
+			return err
+		}
+		if b != nil {
+			d.parent.wg.Add(1)
+			d.parent.upload(dest.target, b)
+		}
+	}
+	return nil
+}
+
+// add appends the event to the buffer, and returns the buffer if it is full.
+func (d *s3Dest) add(e logs.LogEvent) (*batch, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.batch == nil {
+		enc, err := newEncoder(d.parent.Encoding)
+		if err != nil {
+			return nil, err
+		}
+		d.batch = &batch{encoder: enc, first: e.Time(), created: time.Now()}
+	}
+	if err := d.batch.encoder.Append(e.Time(), e.Message()); err != nil {
+		return nil, err
+	}
+	d.batch.events = append(d.batch.events, e)
+	if e.Time().Before(d.batch.first) {
+		d.batch.first = e.Time()
+	}
+	if d.batch.encoder.Size() < d.parent.BufferSize {
+		return nil, nil
+	}
+	b := d.batch
+	d.batch = nil
+	return b, nil
+}
+
+// take returns the buffer, if any.
+func (d *s3Dest) take() *batch {
+	return d.takeIfOlder(0)
+}
+
+// takeIfOlder returns the buffer if it was created more than age ago.
+func (d *s3Dest) takeIfOlder(age time.Duration) *batch {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.batch == nil || time.Since(d.batch.created) < age {
+		return nil
+	}
+	b := d.batch
+	d.batch = nil
+	return b
+}
+
+func (s *S3Logs) Description() string {
+	return "Archive the log events to Amazon S3"
+}
+
+var sampleConfig = `
+  ## Amazon REGION and bucket
+  region = "us-east-1"
+  bucket = "my-log-archive"
+
+  ## Object keys, see the README
+  prefix = "{log_group}/{log_stream}/%Y/%m/%d/%H"
+
+  ## gzip or parquet
+  encoding = "gzip"
+
+  ## Buffers of each log stream are uploaded when full or after the interval
+  upload_interval = "5m"
+  buffer_size = 67108864
+`
+
+// SampleConfig returns the default configuration of the Output
+func (s *S3Logs) SampleConfig() string {
+	return sampleConfig
+}
+
+func init() {
+	outputs.Add(Name, func() telegraf.Output {
+		return &S3Logs{
+			Prefix:         defaultPrefix,
+			Encoding:       EncodingGzip,
+			UploadInterval: internal.Duration{Duration: defaultUploadInterval},
+			BufferSize:     defaultBufferSize,
+			PartSize:       minPartSize,
+			dests:          make(map[target]*s3Dest),
+			stop:           make(chan struct{}),
+		}
+	})
+}
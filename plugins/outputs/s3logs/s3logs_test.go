@@ -0,0 +1,190 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package s3logs
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v14/parquet/file"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+type mockUploader struct {
+	mu       sync.Mutex
+	failures int
+	objects  map[string][]byte
+}
+
+func (m *mockUploader) Upload(input *s3manager.UploadInput, _ ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+	return m.UploadWithContext(aws.BackgroundContext(), input)
+}
+
+func (m *mockUploader) UploadWithContext(_ aws.Context, input *s3manager.UploadInput, _ ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failures > 0 {
+		m.failures--
+		return nil, errors.New("SlowDown")
+	}
+	body, err := io.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	m.objects[*input.Key] = body
+	return &s3manager.UploadOutput{}, nil
+}
+
+func (m *mockUploader) keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	for key := range m.objects {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+type testEvent struct {
+	message string
+	time    time.Time
+	done    *atomic.Int32
+	group   string
+}
+
+func (e testEvent) Message() string { return e.message }
+func (e testEvent) Time() time.Time { return e.time }
+func (e testEvent) Done()           { e.done.Add(1) }
+
+type routedEvent struct {
+	testEvent
+}
+
+func (e routedEvent) Group() string  { return e.group }
+func (e routedEvent) Stream() string { return "" }
+
+func newTestS3Logs(encoding string, bufferSize int) (*S3Logs, *mockUploader) {
+	uploader := &mockUploader{objects: map[string][]byte{}}
+	return &S3Logs{
+		Bucket:         "bucket",
+		Prefix:         defaultPrefix,
+		Encoding:       encoding,
+		UploadInterval: internal.Duration{Duration: time.Hour},
+		BufferSize:     bufferSize,
+		Log:            testutil.Logger{Name: "test"},
+		uploader:       uploader,
+		dests:          make(map[target]*s3Dest),
+		stop:           make(chan struct{}),
+	}, uploader
+}
+
+func TestS3LogsGzip(t *testing.T) {
+	s, uploader := newTestS3Logs(EncodingGzip, 100)
+	require.NoError(t, s.Connect())
+	var done atomic.Int32
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	dest := s.CreateDest("/aws/app", "i-123", -1, "", nil)
+	require.NoError(t, dest.Publish([]logs.LogEvent{
+		testEvent{message: "first", time: ts, done: &done},
+		testEvent{message: strings.Repeat("x", 100), time: ts.Add(time.Second), done: &done},
+		testEvent{message: "buffered", time: ts.Add(2 * time.Second), done: &done},
+	}))
+
+	// the full buffer is uploaded right away
+	keys := uploader.keys()
+	require.Len(t, keys, 1)
+	assert.True(t, strings.HasPrefix(keys[0], "aws/app/i-123/2024/05/06/07/20240506T070809Z-"), keys[0])
+	assert.True(t, strings.HasSuffix(keys[0], ".json.gz"), keys[0])
+	assert.EqualValues(t, 2, done.Load())
+
+	zr, err := gzip.NewReader(bytes.NewReader(uploader.objects[keys[0]]))
+	require.NoError(t, err)
+	scanner := bufio.NewScanner(zr)
+	var records []record
+	for scanner.Scan() {
+		var r record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		records = append(records, r)
+	}
+	require.Len(t, records, 2)
+	assert.Equal(t, record{Timestamp: ts.UnixMilli(), Message: "first"}, records[0])
+
+	// the events left are uploaded on close
+	require.NoError(t, s.Close())
+	assert.Len(t, uploader.keys(), 2)
+	assert.EqualValues(t, 3, done.Load())
+	assert.ErrorIs(t, dest.Publish([]logs.LogEvent{testEvent{message: "late", time: ts, done: &done}}), logs.ErrOutputStopped)
+}
+
+func TestS3LogsParquet(t *testing.T) {
+	s, uploader := newTestS3Logs(EncodingParquet, defaultBufferSize)
+	require.NoError(t, s.Connect())
+	var done atomic.Int32
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	dest := s.CreateDest("app", "stream", -1, "", nil)
+	require.NoError(t, dest.Publish([]logs.LogEvent{
+		testEvent{message: "first", time: ts, done: &done},
+		testEvent{message: "second", time: ts.Add(time.Second), done: &done},
+	}))
+	assert.Empty(t, uploader.keys())
+	require.NoError(t, s.Close())
+
+	keys := uploader.keys()
+	require.Len(t, keys, 1)
+	assert.True(t, strings.HasSuffix(keys[0], ".parquet"), keys[0])
+	assert.EqualValues(t, 2, done.Load())
+
+	r, err := file.NewParquetReader(bytes.NewReader(uploader.objects[keys[0]]))
+	require.NoError(t, err)
+	defer r.Close()
+	assert.EqualValues(t, 2, r.NumRows())
+	assert.Equal(t, "timestamp", r.MetaData().Schema.Column(0).Name())
+	assert.Equal(t, "message", r.MetaData().Schema.Column(1).Name())
+}
+
+func TestS3LogsRoutedAndRetried(t *testing.T) {
+	s, uploader := newTestS3Logs(EncodingGzip, defaultBufferSize)
+	uploader.failures = 1
+	s.Prefix = "archive/{log_group}"
+	require.NoError(t, s.Connect())
+	var done atomic.Int32
+	ts := time.Now()
+	dest := s.CreateDest("app", "stream", -1, "", nil)
+	require.NoError(t, dest.Publish([]logs.LogEvent{
+		testEvent{message: "default", time: ts, done: &done},
+		routedEvent{testEvent{message: "routed", time: ts, done: &done, group: "audit"}},
+	}))
+	require.NoError(t, s.Close())
+
+	var prefixes []string
+	for _, key := range uploader.keys() {
+		prefixes = append(prefixes, key[:strings.LastIndex(key, "/")])
+	}
+	assert.ElementsMatch(t, []string{"archive/app", "archive/audit"}, prefixes)
+	assert.EqualValues(t, 2, done.Load())
+}
+
+func TestS3LogsInvalidConfig(t *testing.T) {
+	s, _ := newTestS3Logs("csv", defaultBufferSize)
+	assert.Error(t, s.Connect())
+	s, _ = newTestS3Logs(EncodingGzip, defaultBufferSize)
+	s.Bucket = ""
+	assert.Error(t, s.Connect())
+}
@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package textfile
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
+	"go.opentelemetry.io/collector/component"
+)
+
+// fileExtension is the extension the textfile collector requires to read a file.
+const fileExtension = ".prom"
+
+// Config represent a configuration for the textfile metrics exporter.
+type Config struct {
+	// Path is the file the metrics are written to.
+	Path string `mapstructure:"path"`
+	// FlushInterval is how often the file is rewritten.
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+	// MetricExpiration is how long a series is kept in the file after its
+	// last update. Zero keeps the series until the agent is restarted.
+	MetricExpiration time.Duration `mapstructure:"metric_expiration"`
+	// Include is a list of glob patterns matched against the metric names.
+	// If empty, all metrics are written.
+	Include []string `mapstructure:"include,omitempty"`
+
+	// ResourceToTelemetrySettings is the option for converting resource
+	// attributes to telemetry attributes.
+	ResourceToTelemetrySettings resourcetotelemetry.Settings `mapstructure:"resource_to_telemetry_conversion"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the exporter configuration is valid.
+func (c *Config) Validate() error {
+	if c.Path == "" {
+		return errors.New("'path' must be set")
+	}
+	if filepath.Ext(c.Path) != fileExtension {
+		return fmt.Errorf("'path' must have the %s extension", fileExtension)
+	}
+	if c.FlushInterval < time.Second {
+		return errors.New("'flush_interval' must be at least 1 second")
+	}
+	if c.MetricExpiration < 0 {
+		return errors.New("'metric_expiration' must not be negative")
+	}
+	for _, pattern := range c.Include {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid 'include' pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package textfile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidate(t *testing.T) {
+	testCases := map[string]struct {
+		modify  func(cfg *Config)
+		wantErr bool
+	}{
+		"WithDefaults": {
+			modify: func(*Config) {},
+		},
+		"WithMissingPath": {
+			modify:  func(cfg *Config) { cfg.Path = "" },
+			wantErr: true,
+		},
+		"WithInvalidExtension": {
+			modify:  func(cfg *Config) { cfg.Path = "/var/lib/node_exporter/cwagent.txt" },
+			wantErr: true,
+		},
+		"WithShortFlushInterval": {
+			modify:  func(cfg *Config) { cfg.FlushInterval = time.Millisecond },
+			wantErr: true,
+		},
+		"WithNegativeExpiration": {
+			modify:  func(cfg *Config) { cfg.MetricExpiration = -time.Second },
+			wantErr: true,
+		},
+		"WithInvalidPattern": {
+			modify:  func(cfg *Config) { cfg.Include = []string{"cpu_[usage"} },
+			wantErr: true,
+		},
+		"WithPatterns": {
+			modify: func(cfg *Config) { cfg.Include = []string{"cpu_*", "mem_used_percent"} },
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Path = "/var/lib/node_exporter/cwagent.prom"
+			testCase.modify(cfg)
+			if testCase.wantErr {
+				assert.Error(t, cfg.Validate())
+			} else {
+				assert.NoError(t, cfg.Validate())
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package textfile provides a metric exporter for the OpenTelemetry collector
+// that writes the metrics to a file in the Prometheus text exposition format
+// for the node_exporter textfile collector.
+package textfile
+
+import (
+	"context"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+const (
+	stability = component.StabilityLevelAlpha
+
+	defaultFlushInterval    = time.Minute
+	defaultMetricExpiration = 5 * time.Minute
+)
+
+var (
+	TypeStr, _ = component.NewType("textfile")
+)
+
+func NewFactory() exporter.Factory {
+	return exporter.NewFactory(
+		TypeStr,
+		createDefaultConfig,
+		exporter.WithMetrics(createMetricsExporter, stability),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		FlushInterval:    defaultFlushInterval,
+		MetricExpiration: defaultMetricExpiration,
+		ResourceToTelemetrySettings: resourcetotelemetry.Settings{
+			Enabled: true,
+		},
+	}
+}
+
+func createMetricsExporter(
+	ctx context.Context,
+	settings exporter.CreateSettings,
+	config component.Config,
+) (exporter.Metrics, error) {
+	cfg := config.(*Config)
+	tf := newTextfile(cfg, settings.Logger)
+	exp, err := exporterhelper.NewMetricsExporter(
+		ctx,
+		settings,
+		config,
+		tf.ConsumeMetrics,
+		exporterhelper.WithStart(tf.Start),
+		exporterhelper.WithShutdown(tf.Shutdown),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return resourcetotelemetry.WrapMetricsExporter(cfg.ResourceToTelemetrySettings, exp), nil
+}
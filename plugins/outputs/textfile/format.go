@@ -0,0 +1,219 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package textfile
+
+import (
+	"bytes"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	typeCounter = "counter"
+	typeGauge   = "gauge"
+	typeSummary = "summary"
+
+	quantileLabel = "quantile"
+)
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// family is a metric family in the exposition format. All series of a family
+// share the same name and type.
+type family struct {
+	name    string
+	typ     string
+	samples map[string]*sample
+}
+
+// sample holds the rendered lines of a single series keyed by its labels.
+type sample struct {
+	lines   []string
+	updated time.Time
+}
+
+// familyType maps the metric to a type in the exposition format. Only
+// cumulative monotonic sums are counters since the textfile is a snapshot
+// of the latest values.
+func familyType(metric pmetric.Metric) (string, bool) {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		return typeGauge, true
+	case pmetric.MetricTypeSum:
+		sum := metric.Sum()
+		if sum.IsMonotonic() && sum.AggregationTemporality() == pmetric.AggregationTemporalityCumulative {
+			return typeCounter, true
+		}
+		return typeGauge, true
+	case pmetric.MetricTypeSummary:
+		return typeSummary, true
+	}
+	return "", false
+}
+
+func (f *family) add(metric pmetric.Metric, now time.Time) {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		f.addNumberDataPoints(metric.Gauge().DataPoints(), now)
+	case pmetric.MetricTypeSum:
+		f.addNumberDataPoints(metric.Sum().DataPoints(), now)
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			labels := labelPairs(dp.Attributes())
+			lines := make([]string, 0, dp.QuantileValues().Len()+2)
+			for j := 0; j < dp.QuantileValues().Len(); j++ {
+				qv := dp.QuantileValues().At(j)
+				quantile := append(slices.Clone(labels), labelPair{name: quantileLabel, value: formatFloat(qv.Quantile())})
+				lines = append(lines, f.name+formatLabels(quantile)+" "+formatFloat(qv.Value()))
+			}
+			lines = append(lines,
+				f.name+"_sum"+formatLabels(labels)+" "+formatFloat(dp.Sum()),
+				f.name+"_count"+formatLabels(labels)+" "+strconv.FormatUint(dp.Count(), 10),
+			)
+			f.samples[formatLabels(labels)] = &sample{lines: lines, updated: now}
+		}
+	}
+}
+
+func (f *family) addNumberDataPoints(dps pmetric.NumberDataPointSlice, now time.Time) {
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		var value string
+		switch dp.ValueType() {
+		case pmetric.NumberDataPointValueTypeInt:
+			value = strconv.FormatInt(dp.IntValue(), 10)
+		case pmetric.NumberDataPointValueTypeDouble:
+			value = formatFloat(dp.DoubleValue())
+		default:
+			continue
+		}
+		labels := formatLabels(labelPairs(dp.Attributes()))
+		f.samples[labels] = &sample{lines: []string{f.name + labels + " " + value}, updated: now}
+	}
+}
+
+// expire removes the series last updated before the time and returns
+// true if the family is empty.
+func (f *family) expire(before time.Time) bool {
+	for labels, s := range f.samples {
+		if s.updated.Before(before) {
+			delete(f.samples, labels)
+		}
+	}
+	return len(f.samples) == 0
+}
+
+// writeFamilies writes the families sorted by name in the Prometheus text
+// exposition format. Timestamps are left out because the textfile collector
+// rejects them.
+func writeFamilies(buf *bytes.Buffer, families map[string]*family) {
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := families[name]
+		buf.WriteString("# TYPE " + f.name + " " + f.typ + "\n")
+		keys := make([]string, 0, len(f.samples))
+		for key := range f.samples {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			for _, line := range f.samples[key].lines {
+				buf.WriteString(line + "\n")
+			}
+		}
+	}
+}
+
+type labelPair struct {
+	name  string
+	value string
+}
+
+// labelPairs converts the attributes into labels sorted by name. Attributes
+// that collide after sanitization keep the last value.
+func labelPairs(attrs pcommon.Map) []labelPair {
+	byName := make(map[string]string, attrs.Len())
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		name := sanitizeName(k)
+		// names starting with __ are reserved for internal use
+		if strings.HasPrefix(name, "__") || name == quantileLabel {
+			return true
+		}
+		byName[name] = v.AsString()
+		return true
+	})
+	labels := make([]labelPair, 0, len(byName))
+	for name, value := range byName {
+		labels = append(labels, labelPair{name: name, value: value})
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].name < labels[j].name
+	})
+	return labels
+}
+
+func formatLabels(labels []labelPair) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, label := range labels {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(label.name + `="` + labelValueReplacer.Replace(label.value) + `"`)
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sanitizeName replaces the characters that are not allowed in metric and
+// label names with underscores. Colons are only valid in metric names, but
+// are replaced for labels as well to keep a single set of rules.
+func sanitizeName(name string) string {
+	if name == "" {
+		return "_"
+	}
+	var sb strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,144 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package textfile
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/atomicfile"
+)
+
+const filePerm = 0644
+
+type textfile struct {
+	config *Config
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	families map[string]*family
+
+	shutdownChan chan struct{}
+	wg           sync.WaitGroup
+}
+
+func newTextfile(config *Config, logger *zap.Logger) *textfile {
+	return &textfile{
+		config:   config,
+		logger:   logger,
+		families: make(map[string]*family),
+	}
+}
+
+func (t *textfile) Start(context.Context, component.Host) error {
+	t.shutdownChan = make(chan struct{})
+	t.wg.Add(1)
+	go t.flushLoop()
+	return nil
+}
+
+// Shutdown stops the flush loop and writes the file one last time.
+func (t *textfile) Shutdown(context.Context) error {
+	if t.shutdownChan == nil {
+		return nil
+	}
+	close(t.shutdownChan)
+	t.wg.Wait()
+	return t.flush(time.Now())
+}
+
+// ConsumeMetrics keeps the latest value of each included series. The file
+// is only written on the flush interval.
+func (t *textfile) ConsumeMetrics(_ context.Context, md pmetric.Metrics) error {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if !t.included(metric.Name()) {
+					continue
+				}
+				t.add(metric, now)
+			}
+		}
+	}
+	return nil
+}
+
+func (t *textfile) add(metric pmetric.Metric, now time.Time) {
+	name := sanitizeName(metric.Name())
+	typ, ok := familyType(metric)
+	if !ok {
+		t.logger.Debug("Unsupported metric type for the textfile", zap.String("metric", metric.Name()), zap.Stringer("type", metric.Type()))
+		return
+	}
+	f, ok := t.families[name]
+	if !ok {
+		f = &family{name: name, typ: typ, samples: make(map[string]*sample)}
+		t.families[name] = f
+	} else if f.typ != typ {
+		t.logger.Debug("Dropping metric with conflicting type", zap.String("metric", name), zap.String("type", typ), zap.String("existing", f.typ))
+		return
+	}
+	f.add(metric, now)
+}
+
+func (t *textfile) included(name string) bool {
+	if len(t.config.Include) == 0 {
+		return true
+	}
+	for _, pattern := range t.config.Include {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *textfile) flushLoop() {
+	defer t.wg.Done()
+	ticker := time.NewTicker(t.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			if err := t.flush(now); err != nil {
+				t.logger.Error("Failed to write the textfile", zap.String("path", t.config.Path), zap.Error(err))
+			}
+		case <-t.shutdownChan:
+			return
+		}
+	}
+}
+
+// flush expires the stale series and rewrites the file.
+func (t *textfile) flush(now time.Time) error {
+	var buf bytes.Buffer
+	t.mu.Lock()
+	if t.config.MetricExpiration > 0 {
+		expireBefore := now.Add(-t.config.MetricExpiration)
+		for name, f := range t.families {
+			if f.expire(expireBefore) {
+				delete(t.families, name)
+			}
+		}
+	}
+	writeFamilies(&buf, t.families)
+	t.mu.Unlock()
+	// the temporary file does not have the .prom extension, so it is ignored by
+	// the textfile collector
+	return atomicfile.WriteFile(t.config.Path, buf.Bytes(), filePerm)
+}
@@ -0,0 +1,156 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package textfile
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+func newTestMetrics() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+
+	m := metrics.AppendEmpty()
+	m.SetName("cpu_usage_idle")
+	dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetDoubleValue(97.5)
+	dp.Attributes().PutStr("cpu", "cpu-total")
+	dp.Attributes().PutStr("host", `my"host`)
+	dp = m.Gauge().DataPoints().AppendEmpty()
+	dp.SetDoubleValue(math.Inf(1))
+	dp.Attributes().PutStr("cpu", "cpu0")
+
+	m = metrics.AppendEmpty()
+	m.SetName("net_bytes_sent")
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	sum.DataPoints().AppendEmpty().SetIntValue(1024)
+
+	m = metrics.AppendEmpty()
+	m.SetName("statsd.timer")
+	sdp := m.SetEmptySummary().DataPoints().AppendEmpty()
+	sdp.SetCount(4)
+	sdp.SetSum(10)
+	sdp.Attributes().PutStr("metric.type", "timing")
+	qv := sdp.QuantileValues().AppendEmpty()
+	qv.SetQuantile(0.5)
+	qv.SetValue(2)
+
+	m = metrics.AppendEmpty()
+	m.SetName("mem_used_percent")
+	m.SetEmptyHistogram().DataPoints().AppendEmpty()
+
+	return md
+}
+
+func TestConsumeMetrics(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cwagent.prom")
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = filename
+	tf := newTextfile(cfg, zap.NewNop())
+	require.NoError(t, tf.ConsumeMetrics(context.Background(), newTestMetrics()))
+	require.NoError(t, tf.flush(time.Now()))
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, `# TYPE cpu_usage_idle gauge
+cpu_usage_idle{cpu="cpu-total",host="my\"host"} 97.5
+cpu_usage_idle{cpu="cpu0"} +Inf
+# TYPE net_bytes_sent counter
+net_bytes_sent 1024
+# TYPE statsd_timer summary
+statsd_timer{metric_type="timing",quantile="0.5"} 2
+statsd_timer_sum{metric_type="timing"} 10
+statsd_timer_count{metric_type="timing"} 4
+`, string(content))
+
+	info, err := os.Stat(filename)
+	require.NoError(t, err)
+	assert.False(t, info.IsDir())
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary file was not cleaned up")
+}
+
+func TestConsumeMetricsWithInclude(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cwagent.prom")
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = filename
+	cfg.Include = []string{"cpu_*", "mem_used_percent"}
+	tf := newTextfile(cfg, zap.NewNop())
+	require.NoError(t, tf.ConsumeMetrics(context.Background(), newTestMetrics()))
+	assert.Len(t, tf.families, 1)
+	assert.Contains(t, tf.families, "cpu_usage_idle")
+}
+
+func TestFlushExpiration(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cwagent.prom")
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = filename
+	tf := newTextfile(cfg, zap.NewNop())
+	require.NoError(t, tf.ConsumeMetrics(context.Background(), newTestMetrics()))
+
+	require.NoError(t, tf.flush(time.Now().Add(cfg.MetricExpiration+time.Minute)))
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Empty(t, content)
+	assert.Empty(t, tf.families)
+}
+
+func TestConflictingType(t *testing.T) {
+	tf := newTextfile(createDefaultConfig().(*Config), zap.NewNop())
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	m := metrics.AppendEmpty()
+	m.SetName("requests")
+	m.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(1)
+	m = metrics.AppendEmpty()
+	m.SetName("requests")
+	m.SetEmptySummary().DataPoints().AppendEmpty().SetCount(1)
+	require.NoError(t, tf.ConsumeMetrics(context.Background(), md))
+	require.Contains(t, tf.families, "requests")
+	assert.Equal(t, typeGauge, tf.families["requests"].typ)
+	assert.Len(t, tf.families["requests"].samples, 1)
+}
+
+func TestStartShutdown(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cwagent.prom")
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = filename
+	cfg.FlushInterval = 10 * time.Millisecond
+	tf := newTextfile(cfg, zap.NewNop())
+	require.NoError(t, tf.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, tf.ConsumeMetrics(context.Background(), newTestMetrics()))
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(filename)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, tf.Shutdown(context.Background()))
+}
+
+func TestSanitizeName(t *testing.T) {
+	testCases := map[string]string{
+		"cpu_usage_idle":   "cpu_usage_idle",
+		"statsd.timer":     "statsd_timer",
+		"1st-metric":       "_1st_metric",
+		"Disk:Used %":      "Disk_Used__",
+		"":                 "_",
+		"metric_with_ütf8": "metric_with__tf8",
+	}
+	for input, want := range testCases {
+		assert.Equal(t, want, sanitizeName(input), input)
+	}
+}
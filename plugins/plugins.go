@@ -9,9 +9,23 @@ import (
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/processors/k8sdecorator"
 
 	// Enabled cloudwatch-agent input plugins
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/collectd"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/diskstats"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/docker_logs"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/efa"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/fluent_forward"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/journald"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/kafka_logs"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/kinesis_logs"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/network_connections"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/network_probe"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/nfs"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/nvidia_smi"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/otlp_logs"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/procstat"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/prometheus"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/snmp"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/statsd"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/win_perf_counters"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/windows_event_log"
@@ -19,6 +33,7 @@ import (
 	// Enabled cloudwatch-agent output plugins
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/outputs/cloudwatch"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/outputs/cloudwatchlogs"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/outputs/s3logs"
 
 	// Enabled telegraf input plugins
 	// NOTE: any plugins that are dependencies of the plugins enabled will be enabled too
@@ -30,7 +45,5 @@ import (
 	_ "github.com/influxdata/telegraf/plugins/inputs/mem"
 	_ "github.com/influxdata/telegraf/plugins/inputs/net"
 	_ "github.com/influxdata/telegraf/plugins/inputs/processes"
-	_ "github.com/influxdata/telegraf/plugins/inputs/procstat"
-	_ "github.com/influxdata/telegraf/plugins/inputs/socket_listener"
 	_ "github.com/influxdata/telegraf/plugins/inputs/swap"
 )
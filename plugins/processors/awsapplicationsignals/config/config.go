@@ -66,6 +66,14 @@ func (cfg *Config) Validate() error {
 		default:
 			return errors.New("unknown resolver")
 		}
+		if resolver.MetadataSharing != nil {
+			if resolver.Platform != PlatformEKS && resolver.Platform != PlatformK8s {
+				return errors.New("metadata_sharing is only supported for eks and k8s resolvers")
+			}
+			if resolver.MetadataSharing.RefreshInterval < 0 {
+				return errors.New("metadata_sharing refresh_interval must not be negative")
+			}
+		}
 	}
 
 	if cfg.Limiter != nil {
@@ -5,6 +5,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -80,3 +81,16 @@ func TestValidateFailedOnEmptyResolverName(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateMetadataSharing(t *testing.T) {
+	eks := NewEKSResolver("test")
+	eks.MetadataSharing = &MetadataSharingConfig{}
+	assert.Nil(t, (&Config{Resolvers: []Resolver{eks}}).Validate())
+
+	eks.MetadataSharing = &MetadataSharingConfig{RefreshInterval: -time.Second}
+	assert.NotNil(t, (&Config{Resolvers: []Resolver{eks}}).Validate())
+
+	ec2 := NewEC2Resolver("test")
+	ec2.MetadataSharing = &MetadataSharingConfig{}
+	assert.NotNil(t, (&Config{Resolvers: []Resolver{ec2}}).Validate())
+}
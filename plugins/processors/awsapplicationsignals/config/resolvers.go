@@ -3,6 +3,8 @@
 
 package config
 
+import "time"
+
 const (
 	// PlatformGeneric Platforms other than Amazon EKS
 	PlatformGeneric = "generic"
@@ -19,6 +21,19 @@ const (
 type Resolver struct {
 	Name     string `mapstructure:"name"`
 	Platform string `mapstructure:"platform"`
+	// MetadataSharing is only supported on the eks and k8s platforms.
+	MetadataSharing *MetadataSharingConfig `mapstructure:"metadata_sharing,omitempty"`
+}
+
+// MetadataSharingConfig lets the agents in a cluster share the workload metadata. Only the elected
+// leader watches the pods and services, and publishes a compressed snapshot to a ConfigMap that
+// the other agents read instead of calling the API server.
+type MetadataSharingConfig struct {
+	// Namespace of the ConfigMap and the leader election Lease. Defaults to the agent's namespace.
+	Namespace       string        `mapstructure:"namespace,omitempty"`
+	ConfigMapName   string        `mapstructure:"configmap_name,omitempty"`
+	LeaseName       string        `mapstructure:"lease_name,omitempty"`
+	RefreshInterval time.Duration `mapstructure:"refresh_interval,omitempty"`
 }
 
 func NewEKSResolver(name string) Resolver {
@@ -52,6 +52,7 @@ type kubernetesResolver struct {
 	serviceAndNamespaceToSelectors *sync.Map
 	workloadAndNamespaceToLabels   *sync.Map
 	serviceToWorkload              *sync.Map // computed from serviceAndNamespaceToSelectors and workloadAndNamespaceToLabels every 1 min
+	ipToWorkloadAndNamespace       *sync.Map // read from the shared snapshot when the metadata is shared
 	workloadPodCount               map[string]int
 	safeStopCh                     *safeChannel // trace and metric processors share the same kubernetesResolver and might close the same channel separately
 }
@@ -456,7 +457,7 @@ func minimizeService(obj interface{}) (interface{}, error) {
 	return obj, nil
 }
 
-func getKubernetesResolver(platformCode, clusterName string, logger *zap.Logger) subResolver {
+func getKubernetesResolver(platformCode, clusterName string, sharing *config.MetadataSharingConfig, logger *zap.Logger) subResolver {
 	once.Do(func() {
 		config, err := clientcmd.BuildConfigFromFlags("", "")
 		if err != nil {
@@ -471,53 +472,77 @@ func getKubernetesResolver(platformCode, clusterName string, logger *zap.Logger)
 		// jitter calls to the kubernetes api
 		jitterSleep(jitterKubernetesAPISeconds)
 
-		sharedInformerFactory := informers.NewSharedInformerFactory(clientset, 0)
-		podInformer := sharedInformerFactory.Core().V1().Pods().Informer()
-		err = podInformer.SetTransform(minimizePod)
-		if err != nil {
-			logger.Error("failed to minimize Pod objects", zap.Error(err))
-		}
-		serviceInformer := sharedInformerFactory.Core().V1().Services().Informer()
-		err = serviceInformer.SetTransform(minimizeService)
-		if err != nil {
-			logger.Error("failed to minimize Service objects", zap.Error(err))
-		}
-
 		timedDeleter := &TimedDeleter{Delay: deletionDelay}
-		poWatcher := newPodWatcher(logger, podInformer, timedDeleter)
-		svcWatcher := newServiceWatcher(logger, serviceInformer, timedDeleter)
-
 		safeStopCh := &safeChannel{ch: make(chan struct{}), closed: false}
-		// initialize the pod and service watchers for the cluster
-		poWatcher.run(safeStopCh.ch)
-		svcWatcher.Run(safeStopCh.ch)
-		// wait for caches to sync (for once) so that clients knows about the pods and services in the cluster
-		poWatcher.waitForCacheSync(safeStopCh.ch)
-		svcWatcher.waitForCacheSync(safeStopCh.ch)
-
-		serviceToWorkload := &sync.Map{}
-		svcToWorkloadMapper := newServiceToWorkloadMapper(svcWatcher.serviceAndNamespaceToSelectors, poWatcher.workloadAndNamespaceToLabels, serviceToWorkload, logger, timedDeleter)
-		svcToWorkloadMapper.Start(safeStopCh.ch)
-
 		instance = &kubernetesResolver{
-			logger:                         logger,
-			clientset:                      clientset,
-			clusterName:                    clusterName,
-			platformCode:                   platformCode,
-			ipToServiceAndNamespace:        svcWatcher.ipToServiceAndNamespace,
-			serviceAndNamespaceToSelectors: svcWatcher.serviceAndNamespaceToSelectors,
-			ipToPod:                        poWatcher.ipToPod,
-			podToWorkloadAndNamespace:      poWatcher.podToWorkloadAndNamespace,
-			workloadAndNamespaceToLabels:   poWatcher.workloadAndNamespaceToLabels,
-			serviceToWorkload:              serviceToWorkload,
-			workloadPodCount:               poWatcher.workloadPodCount,
-			safeStopCh:                     safeStopCh,
+			logger:       logger,
+			clientset:    clientset,
+			clusterName:  clusterName,
+			platformCode: platformCode,
+			safeStopCh:   safeStopCh,
+		}
+
+		if sharing != nil {
+			// the watchers are only started by the leader
+			sharer := newSnapshotSharer(logger, clientset, sharing, timedDeleter, func() *workloadWatchers {
+				return startWorkloadWatchers(clientset, logger, timedDeleter, safeStopCh.ch)
+			})
+			sharer.start(safeStopCh.ch)
+			instance.ipToServiceAndNamespace = &sync.Map{}
+			instance.serviceAndNamespaceToSelectors = &sync.Map{}
+			instance.ipToPod = &sync.Map{}
+			instance.podToWorkloadAndNamespace = &sync.Map{}
+			instance.workloadAndNamespaceToLabels = &sync.Map{}
+			instance.serviceToWorkload = &sync.Map{}
+			instance.workloadPodCount = make(map[string]int)
+			instance.ipToWorkloadAndNamespace = sharer.ipToWorkloadAndNamespace
+			return
 		}
+
+		watchers := startWorkloadWatchers(clientset, logger, timedDeleter, safeStopCh.ch)
+		instance.ipToServiceAndNamespace = watchers.svcWatcher.ipToServiceAndNamespace
+		instance.serviceAndNamespaceToSelectors = watchers.svcWatcher.serviceAndNamespaceToSelectors
+		instance.ipToPod = watchers.poWatcher.ipToPod
+		instance.podToWorkloadAndNamespace = watchers.poWatcher.podToWorkloadAndNamespace
+		instance.workloadAndNamespaceToLabels = watchers.poWatcher.workloadAndNamespaceToLabels
+		instance.serviceToWorkload = watchers.serviceToWorkload
+		instance.workloadPodCount = watchers.poWatcher.workloadPodCount
 	})
 
 	return instance
 }
 
+// startWorkloadWatchers watches the pods and services in the cluster, and waits for the caches to sync.
+func startWorkloadWatchers(clientset kubernetes.Interface, logger *zap.Logger, deleter Deleter, stopCh chan struct{}) *workloadWatchers {
+	sharedInformerFactory := informers.NewSharedInformerFactory(clientset, 0)
+	podInformer := sharedInformerFactory.Core().V1().Pods().Informer()
+	err := podInformer.SetTransform(minimizePod)
+	if err != nil {
+		logger.Error("failed to minimize Pod objects", zap.Error(err))
+	}
+	serviceInformer := sharedInformerFactory.Core().V1().Services().Informer()
+	err = serviceInformer.SetTransform(minimizeService)
+	if err != nil {
+		logger.Error("failed to minimize Service objects", zap.Error(err))
+	}
+
+	poWatcher := newPodWatcher(logger, podInformer, deleter)
+	svcWatcher := newServiceWatcher(logger, serviceInformer, deleter)
+
+	// initialize the pod and service watchers for the cluster
+	poWatcher.run(stopCh)
+	svcWatcher.Run(stopCh)
+	// wait for caches to sync (for once) so that clients knows about the pods and services in the cluster
+	poWatcher.waitForCacheSync(stopCh)
+	svcWatcher.waitForCacheSync(stopCh)
+
+	serviceToWorkload := &sync.Map{}
+	svcToWorkloadMapper := newServiceToWorkloadMapper(svcWatcher.serviceAndNamespaceToSelectors, poWatcher.workloadAndNamespaceToLabels, serviceToWorkload, logger, deleter)
+	svcToWorkloadMapper.Start(stopCh)
+
+	return &workloadWatchers{poWatcher: poWatcher, svcWatcher: svcWatcher, serviceToWorkload: serviceToWorkload}
+}
+
 func (e *kubernetesResolver) Stop(_ context.Context) error {
 	e.safeStopCh.Close()
 	return nil
@@ -526,6 +551,12 @@ func (e *kubernetesResolver) Stop(_ context.Context) error {
 // add a method to kubernetesResolver
 func (e *kubernetesResolver) getWorkloadAndNamespaceByIP(ip string) (string, string, error) {
 	var workload, namespace string
+	if e.ipToWorkloadAndNamespace != nil {
+		if workloadKey, ok := e.ipToWorkloadAndNamespace.Load(ip); ok {
+			workload, namespace = extractResourceAndNamespace(workloadKey.(string))
+			return workload, namespace, nil
+		}
+	}
 	if podKey, ok := e.ipToPod.Load(ip); ok {
 		pod := podKey.(string)
 		if workloadKey, ok := e.podToWorkloadAndNamespace.Load(pod); ok {
@@ -0,0 +1,316 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package resolver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/awsapplicationsignals/config"
+)
+
+const (
+	defaultSnapshotNamespace       = "amazon-cloudwatch"
+	defaultSnapshotConfigMapName   = "cwagent-workload-metadata"
+	defaultSnapshotLeaseName       = "cwagent-workload-metadata-leader"
+	defaultSnapshotRefreshInterval = time.Minute
+
+	snapshotDataKey = "snapshot.json.gz"
+	// ConfigMaps are limited to 1 MiB including the metadata.
+	maxSnapshotSize = 1000 * 1024
+
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+	leaseDuration = 60 * time.Second
+	renewDeadline = 15 * time.Second
+	retryPeriod   = 5 * time.Second
+)
+
+// workloadSnapshot maps the pod and service IPs to their workload and namespace. It is all the
+// followers need to resolve the IPs without watching the pods and services themselves.
+type workloadSnapshot struct {
+	Timestamp                time.Time         `json:"timestamp"`
+	IPToWorkloadAndNamespace map[string]string `json:"ip_to_workload_and_namespace"`
+}
+
+func encodeSnapshot(snapshot *workloadSnapshot) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(snapshot); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeSnapshot(data []byte) (*workloadSnapshot, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &workloadSnapshot{}
+	if err = json.Unmarshal(content, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// workloadWatchers watch the pods and services in the cluster.
+type workloadWatchers struct {
+	poWatcher         *podWatcher
+	svcWatcher        *serviceWatcher
+	serviceToWorkload *sync.Map
+}
+
+// snapshot resolves the workload of every known IP the same way as getWorkloadAndNamespaceByIP.
+func (w *workloadWatchers) snapshot() *workloadSnapshot {
+	ipToWorkloadAndNamespace := make(map[string]string)
+	w.svcWatcher.ipToServiceAndNamespace.Range(func(ip, serviceAndNamespace interface{}) bool {
+		if workloadAndNamespace, ok := w.serviceToWorkload.Load(serviceAndNamespace); ok {
+			ipToWorkloadAndNamespace[ip.(string)] = workloadAndNamespace.(string)
+		}
+		return true
+	})
+	// the pod IPs take precedence over the service IPs
+	w.poWatcher.ipToPod.Range(func(ip, pod interface{}) bool {
+		if workloadAndNamespace, ok := w.poWatcher.podToWorkloadAndNamespace.Load(pod); ok {
+			ipToWorkloadAndNamespace[ip.(string)] = workloadAndNamespace.(string)
+		}
+		return true
+	})
+	return &workloadSnapshot{Timestamp: time.Now(), IPToWorkloadAndNamespace: ipToWorkloadAndNamespace}
+}
+
+// snapshotSharer shares the workload metadata between the agents in the cluster. The elected leader
+// watches the pods and services, and publishes a compressed snapshot to a ConfigMap. The followers
+// read the ConfigMap instead of watching the API server.
+type snapshotSharer struct {
+	logger          *zap.Logger
+	clientset       kubernetes.Interface
+	namespace       string
+	configMapName   string
+	leaseName       string
+	identity        string
+	refreshInterval time.Duration
+	deleter         Deleter
+	// startWatchers is called once the agent becomes the leader for the first time.
+	startWatchers func() *workloadWatchers
+
+	ipToWorkloadAndNamespace *sync.Map
+
+	leading   atomic.Bool
+	mu        sync.Mutex
+	watchers  *workloadWatchers
+	lastApply map[string]string
+}
+
+func newSnapshotSharer(logger *zap.Logger, clientset kubernetes.Interface, cfg *config.MetadataSharingConfig, deleter Deleter, startWatchers func() *workloadWatchers) *snapshotSharer {
+	s := &snapshotSharer{
+		logger:                   logger,
+		clientset:                clientset,
+		namespace:                cfg.Namespace,
+		configMapName:            cfg.ConfigMapName,
+		leaseName:                cfg.LeaseName,
+		refreshInterval:          cfg.RefreshInterval,
+		deleter:                  deleter,
+		startWatchers:            startWatchers,
+		ipToWorkloadAndNamespace: &sync.Map{},
+	}
+	if s.namespace == "" {
+		s.namespace = defaultNamespace()
+	}
+	if s.configMapName == "" {
+		s.configMapName = defaultSnapshotConfigMapName
+	}
+	if s.leaseName == "" {
+		s.leaseName = defaultSnapshotLeaseName
+	}
+	if s.refreshInterval <= 0 {
+		s.refreshInterval = defaultSnapshotRefreshInterval
+	}
+	s.identity = os.Getenv(envconfig.PodName)
+	if s.identity == "" {
+		s.identity, _ = os.Hostname()
+	}
+	return s
+}
+
+// defaultNamespace is the namespace of the agent pod.
+func defaultNamespace() string {
+	if content, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		if namespace := strings.TrimSpace(string(content)); namespace != "" {
+			return namespace
+		}
+	}
+	return defaultSnapshotNamespace
+}
+
+func (s *snapshotSharer) start(stopCh chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+	// the followers can resolve the IPs as soon as the snapshot is read
+	s.refresh(ctx)
+	go s.runLeaderElection(ctx)
+	go func() {
+		ticker := time.NewTicker(s.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.refresh(ctx)
+			}
+		}
+	}()
+}
+
+func (s *snapshotSharer) runLeaderElection(ctx context.Context) {
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta:  metav1.ObjectMeta{Name: s.leaseName, Namespace: s.namespace},
+		Client:     s.clientset.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: s.identity},
+	}
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   renewDeadline,
+		RetryPeriod:     retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            s.leaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				s.logger.Info("Started leading the workload metadata sharing", zap.String("identity", s.identity))
+				s.startLeading(ctx)
+			},
+			OnStoppedLeading: func() {
+				s.logger.Info("Stopped leading the workload metadata sharing", zap.String("identity", s.identity))
+				s.leading.Store(false)
+			},
+		},
+	})
+	if err != nil {
+		s.logger.Error("Failed to create the leader elector for the workload metadata sharing", zap.Error(err))
+		return
+	}
+	// Run returns once the leadership is lost, so keep running for the next election.
+	for ctx.Err() == nil {
+		elector.Run(ctx)
+	}
+}
+
+func (s *snapshotSharer) startLeading(ctx context.Context) {
+	s.mu.Lock()
+	// The watchers keep running after the leadership is lost, so they are only started once.
+	if s.watchers == nil {
+		s.watchers = s.startWatchers()
+	}
+	s.mu.Unlock()
+	s.leading.Store(true)
+	s.refresh(ctx)
+}
+
+// refresh publishes the snapshot from the watchers on the leader and reads the snapshot on the
+// followers. Both use the snapshot to resolve the IPs.
+func (s *snapshotSharer) refresh(ctx context.Context) {
+	var snapshot *workloadSnapshot
+	s.mu.Lock()
+	watchers := s.watchers
+	s.mu.Unlock()
+	if s.leading.Load() && watchers != nil {
+		snapshot = watchers.snapshot()
+		if err := s.publish(ctx, snapshot); err != nil {
+			s.logger.Warn("Failed to publish the workload metadata snapshot", zap.Error(err))
+		}
+	} else {
+		var err error
+		if snapshot, err = s.load(ctx); err != nil {
+			s.logger.Debug("Failed to read the workload metadata snapshot", zap.Error(err))
+			return
+		}
+	}
+	s.apply(snapshot)
+}
+
+func (s *snapshotSharer) publish(ctx context.Context, snapshot *workloadSnapshot) error {
+	data, err := encodeSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxSnapshotSize {
+		return fmt.Errorf("snapshot of %d bytes exceeds the ConfigMap limit of %d bytes", len(data), maxSnapshotSize)
+	}
+	configMaps := s.clientset.CoreV1().ConfigMaps(s.namespace)
+	cm, err := configMaps.Get(ctx, s.configMapName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: s.configMapName, Namespace: s.namespace},
+			BinaryData: map[string][]byte{snapshotDataKey: data},
+		}
+		_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	cm.BinaryData = map[string][]byte{snapshotDataKey: data}
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+func (s *snapshotSharer) load(ctx context.Context) (*workloadSnapshot, error) {
+	cm, err := s.clientset.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.configMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	data, ok := cm.BinaryData[snapshotDataKey]
+	if !ok {
+		return nil, fmt.Errorf("ConfigMap %s/%s has no %s", s.namespace, s.configMapName, snapshotDataKey)
+	}
+	return decodeSnapshot(data)
+}
+
+// apply updates the IPs from the snapshot. The IPs missing from the snapshot are deleted with
+// the same delay as the watchers.
+func (s *snapshotSharer) apply(snapshot *workloadSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ip, workloadAndNamespace := range snapshot.IPToWorkloadAndNamespace {
+		s.ipToWorkloadAndNamespace.Store(ip, workloadAndNamespace)
+	}
+	for ip := range s.lastApply {
+		if _, ok := snapshot.IPToWorkloadAndNamespace[ip]; !ok {
+			s.deleter.DeleteWithDelay(s.ipToWorkloadAndNamespace, ip)
+		}
+	}
+	s.lastApply = snapshot.IPToWorkloadAndNamespace
+}
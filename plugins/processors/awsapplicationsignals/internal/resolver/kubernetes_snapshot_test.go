@@ -0,0 +1,128 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package resolver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/awsapplicationsignals/config"
+)
+
+func newTestWorkloadWatchers() *workloadWatchers {
+	logger := zap.NewNop()
+	w := &workloadWatchers{
+		poWatcher:         newPodWatcher(logger, nil, mockDeleter),
+		svcWatcher:        newServiceWatcher(logger, nil, mockDeleter),
+		serviceToWorkload: &sync.Map{},
+	}
+	w.poWatcher.ipToPod.Store("10.0.0.1", "web-1")
+	w.poWatcher.ipToPod.Store("10.0.0.2", "unknown-pod")
+	w.poWatcher.podToWorkloadAndNamespace.Store("web-1", "web@default")
+	w.svcWatcher.ipToServiceAndNamespace.Store("172.20.0.1", "web-svc@default")
+	w.svcWatcher.ipToServiceAndNamespace.Store("172.20.0.2", "orphan-svc@default")
+	w.serviceToWorkload.Store("web-svc@default", "web@default")
+	return w
+}
+
+func TestWorkloadSnapshot(t *testing.T) {
+	snapshot := newTestWorkloadWatchers().snapshot()
+	assert.Equal(t, map[string]string{
+		"10.0.0.1":   "web@default",
+		"172.20.0.1": "web@default",
+	}, snapshot.IPToWorkloadAndNamespace)
+
+	data, err := encodeSnapshot(snapshot)
+	require.NoError(t, err)
+	decoded, err := decodeSnapshot(data)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.IPToWorkloadAndNamespace, decoded.IPToWorkloadAndNamespace)
+	assert.True(t, snapshot.Timestamp.Equal(decoded.Timestamp))
+
+	_, err = decodeSnapshot([]byte("not gzip"))
+	assert.Error(t, err)
+}
+
+func TestSnapshotSharer(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset()
+	cfg := &config.MetadataSharingConfig{Namespace: "amazon-cloudwatch"}
+	watchers := newTestWorkloadWatchers()
+	leader := newSnapshotSharer(zap.NewNop(), clientset, cfg, mockDeleter, func() *workloadWatchers { return watchers })
+	follower := newSnapshotSharer(zap.NewNop(), clientset, cfg, mockDeleter, nil)
+	assert.Equal(t, defaultSnapshotConfigMapName, leader.configMapName)
+	assert.Equal(t, defaultSnapshotLeaseName, leader.leaseName)
+	assert.Equal(t, defaultSnapshotRefreshInterval, leader.refreshInterval)
+
+	// the follower has nothing to read before the leader publishes
+	follower.refresh(ctx)
+	_, ok := follower.ipToWorkloadAndNamespace.Load("10.0.0.1")
+	assert.False(t, ok)
+
+	leader.startLeading(ctx)
+	cm, err := clientset.CoreV1().ConfigMaps("amazon-cloudwatch").Get(ctx, defaultSnapshotConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, cm.BinaryData[snapshotDataKey])
+	workload, ok := leader.ipToWorkloadAndNamespace.Load("10.0.0.1")
+	assert.True(t, ok)
+	assert.Equal(t, "web@default", workload)
+
+	follower.refresh(ctx)
+	workload, ok = follower.ipToWorkloadAndNamespace.Load("172.20.0.1")
+	assert.True(t, ok)
+	assert.Equal(t, "web@default", workload)
+
+	// the IPs removed by the leader are removed by the follower
+	watchers.poWatcher.ipToPod.Delete("10.0.0.1")
+	leader.refresh(ctx)
+	follower.refresh(ctx)
+	_, ok = follower.ipToWorkloadAndNamespace.Load("10.0.0.1")
+	assert.False(t, ok)
+	_, ok = follower.ipToWorkloadAndNamespace.Load("172.20.0.1")
+	assert.True(t, ok)
+
+	// the former leader reads the snapshot like the other followers
+	leader.leading.Store(false)
+	leader.refresh(ctx)
+	_, ok = leader.ipToWorkloadAndNamespace.Load("172.20.0.1")
+	assert.True(t, ok)
+}
+
+func TestSnapshotSharerTooLarge(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset()
+	sharer := newSnapshotSharer(zap.NewNop(), clientset, &config.MetadataSharingConfig{Namespace: "ns", RefreshInterval: time.Second}, mockDeleter, nil)
+	snapshot := &workloadSnapshot{IPToWorkloadAndNamespace: map[string]string{}}
+	for i := 0; i < 200000; i++ {
+		snapshot.IPToWorkloadAndNamespace[time.Duration(i).String()] = time.Duration(i*7919).String() + "@default"
+	}
+	assert.ErrorContains(t, sharer.publish(ctx, snapshot), "exceeds the ConfigMap limit")
+}
+
+func TestGetWorkloadAndNamespaceByIPFromSnapshot(t *testing.T) {
+	resolver := &kubernetesResolver{
+		logger:                    zap.NewNop(),
+		ipToPod:                   &sync.Map{},
+		podToWorkloadAndNamespace: &sync.Map{},
+		ipToServiceAndNamespace:   &sync.Map{},
+		serviceToWorkload:         &sync.Map{},
+		ipToWorkloadAndNamespace:  &sync.Map{},
+	}
+	resolver.ipToWorkloadAndNamespace.Store("10.0.0.1", "web@default")
+	workload, namespace, err := resolver.getWorkloadAndNamespaceByIP("10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "web", workload)
+	assert.Equal(t, "default", namespace)
+
+	_, _, err = resolver.getWorkloadAndNamespaceByIP("10.0.0.2")
+	assert.Error(t, err)
+}
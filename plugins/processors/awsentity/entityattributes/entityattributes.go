@@ -4,6 +4,7 @@
 package entityattributes
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -28,6 +29,7 @@ const (
 	AttributeEntityAWSResource           = "AWS::Resource"
 	AttributeEntityResourceType          = AWSEntityPrefix + "resource.type"
 	AttributeEntityEC2InstanceResource   = "AWS::EC2::Instance"
+	AttributeEntityECSTaskResource       = "AWS::ECS::Task"
 	AttributeEntityIdentifier            = AWSEntityPrefix + "identifier"
 	AttributeEntityAwsAccountId          = AWSEntityPrefix + "aws.account.id"
 	AttributeEntityServiceName           = AWSEntityPrefix + "service.name"
@@ -40,11 +42,27 @@ const (
 	AttributeEntityPlatformType          = AWSEntityPrefix + "platform.type"
 	AttributeEntityInstanceID            = AWSEntityPrefix + "instance.id"
 	AttributeEntityAutoScalingGroup      = AWSEntityPrefix + "auto.scaling.group"
+	AttributeEntityECSCluster            = AWSEntityPrefix + "ecs.cluster.name"
+	AttributeEntityECSService            = AWSEntityPrefix + "ecs.service.name"
+	AttributeEntityECSLaunchType         = AWSEntityPrefix + "ecs.launch.type"
+	AttributeEntityCloudProvider         = AWSEntityPrefix + "cloud.provider"
+	AttributeEntityAzureSubscriptionID   = AWSEntityPrefix + "azure.subscription.id"
+	AttributeEntityAzureResourceGroup    = AWSEntityPrefix + "azure.resource.group"
+	AttributeEntityAzureVMID             = AWSEntityPrefix + "azure.vm.id"
+	AttributeEntityAzureLocation         = AWSEntityPrefix + "azure.location"
+	AttributeEntityDatacenter            = AWSEntityPrefix + "datacenter"
+	// AttributeEntityCustomPrefix prefixes the user defined attributes, e.g. the
+	// agent.entity_attributes and the allowed EC2 tags
+	AttributeEntityCustomPrefix = AWSEntityPrefix + "custom."
 
 	// The following are possible platform values
 	AttributeEntityEC2Platform = "AWS::EC2"
 	AttributeEntityEKSPlatform = "AWS::EKS"
+	AttributeEntityECSPlatform = "AWS::ECS"
 	AttributeEntityK8sPlatform = "K8s"
+	// AttributeEntityGenericPlatform is the platform of the hybrid hosts, the
+	// Azure virtual machines and the on-premises servers
+	AttributeEntityGenericPlatform = "Generic"
 
 	// The following Fields are the actual names attached to the Entity requests.
 	ServiceName           = "Name"
@@ -62,6 +80,20 @@ const (
 	Platform              = "PlatformType"
 	InstanceID            = "EC2.InstanceId"
 	AutoscalingGroup      = "EC2.AutoScalingGroup"
+	ECSCluster            = "ECS.Cluster"
+	ECSService            = "ECS.Service"
+	ECSLaunchType         = "ECS.LaunchType"
+	CloudProvider         = "Cloud.Provider"
+	AzureSubscriptionID   = "Azure.SubscriptionId"
+	AzureResourceGroup    = "Azure.ResourceGroup"
+	AzureVMID             = "Azure.VMId"
+	AzureLocation         = "Azure.Location"
+	Datacenter            = "Host.Datacenter"
+
+	// The following are the limits of the Attributes of an Entity
+	AttributesMax           = 10
+	AttributeKeyLengthMax   = 256
+	AttributeValueLengthMax = 2048
 
 	// The following are values used for the environment fallbacks required on EC2
 	DeploymentEnvironmentFallbackPrefix = "ec2:"
@@ -80,13 +112,22 @@ var keyAttributeEntityToShortNameMap = map[string]string{
 
 // attributeEntityToShortNameMap is used to map attributes from otel to the actual values used in the Entity object
 var attributeEntityToShortNameMap = map[string]string{
-	AttributeEntityNamespace:         NamespaceField,
-	AttributeEntityWorkload:          Workload,
-	AttributeEntityNode:              Node,
-	AttributeEntityPlatformType:      Platform,
-	AttributeEntityInstanceID:        InstanceID,
-	AttributeEntityAutoScalingGroup:  AutoscalingGroup,
-	AttributeEntityServiceNameSource: ServiceNameSource,
+	AttributeEntityNamespace:           NamespaceField,
+	AttributeEntityWorkload:            Workload,
+	AttributeEntityNode:                Node,
+	AttributeEntityPlatformType:        Platform,
+	AttributeEntityInstanceID:          InstanceID,
+	AttributeEntityAutoScalingGroup:    AutoscalingGroup,
+	AttributeEntityServiceNameSource:   ServiceNameSource,
+	AttributeEntityECSCluster:          ECSCluster,
+	AttributeEntityECSService:          ECSService,
+	AttributeEntityECSLaunchType:       ECSLaunchType,
+	AttributeEntityCloudProvider:       CloudProvider,
+	AttributeEntityAzureSubscriptionID: AzureSubscriptionID,
+	AttributeEntityAzureResourceGroup:  AzureResourceGroup,
+	AttributeEntityAzureVMID:           AzureVMID,
+	AttributeEntityAzureLocation:       AzureLocation,
+	AttributeEntityDatacenter:          Datacenter,
 }
 
 func CreateCloudWatchEntityFromAttributes(resourceAttributes pcommon.Map) cloudwatch.Entity {
@@ -108,6 +149,16 @@ func CreateCloudWatchEntityFromAttributes(resourceAttributes pcommon.Map) cloudw
 		}
 	}
 
+	// Add the user defined attributes after the ones set by the agent
+	custom := map[string]string{}
+	resourceAttributes.Range(func(k string, v pcommon.Value) bool {
+		if key, ok := strings.CutPrefix(k, AttributeEntityCustomPrefix); ok {
+			custom[key] = v.Str()
+		}
+		return true
+	})
+	AddCustomAttributes(attributeMap, custom)
+
 	// Remove entity fields from attributes and return the entity
 	removeEntityFields(resourceAttributes)
 	return cloudwatch.Entity{
@@ -127,6 +178,31 @@ func processEntityAttributes(entityMap map[string]string, targetMap map[string]*
 	}
 }
 
+// AddCustomAttributes adds the user defined attributes to the Attributes of an
+// Entity, in the order of their keys, without replacing the attributes set by
+// the agent. The attributes exceeding the limits of the Entity are dropped.
+// Returns the number of dropped attributes.
+func AddCustomAttributes(attributes map[string]*string, custom map[string]string) int {
+	keys := make([]string, 0, len(custom))
+	for k := range custom {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	dropped := 0
+	for _, k := range keys {
+		v := custom[k]
+		if _, ok := attributes[k]; ok {
+			continue
+		}
+		if k == "" || v == "" || len(k) > AttributeKeyLengthMax || len(v) > AttributeValueLengthMax || len(attributes) >= AttributesMax {
+			dropped++
+			continue
+		}
+		attributes[k] = aws.String(v)
+	}
+	return dropped
+}
+
 func clusterType(platformType string) string {
 	if platformType == AttributeEntityEKSPlatform {
 		return EksCluster
@@ -4,6 +4,8 @@
 package entityattributes
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -49,6 +51,28 @@ func TestProcessAndRemoveEntityAttributes(t *testing.T) {
 			},
 			leftoverAttributes: make(map[string]any),
 		},
+		{
+			name: "hybrid_attributes",
+			resourceAttributes: map[string]any{
+				AttributeEntityPlatformType:        AttributeEntityGenericPlatform,
+				AttributeEntityCloudProvider:       "azure",
+				AttributeEntityAzureSubscriptionID: "my-subscription",
+				AttributeEntityAzureResourceGroup:  "my-resource-group",
+				AttributeEntityAzureVMID:           "my-vm",
+				AttributeEntityAzureLocation:       "westeurope",
+				AttributeEntityDatacenter:          "my-datacenter",
+			},
+			wantedAttributes: map[string]*string{
+				Platform:            aws.String(AttributeEntityGenericPlatform),
+				CloudProvider:       aws.String("azure"),
+				AzureSubscriptionID: aws.String("my-subscription"),
+				AzureResourceGroup:  aws.String("my-resource-group"),
+				AzureVMID:           aws.String("my-vm"),
+				AzureLocation:       aws.String("westeurope"),
+				Datacenter:          aws.String("my-datacenter"),
+			},
+			leftoverAttributes: make(map[string]any),
+		},
 		{
 			name: "key_and_non_key_attributes",
 			resourceAttributes: map[string]any{
@@ -255,3 +279,87 @@ func TestCreateCloudWatchEntityFromAttributesOnEc2(t *testing.T) {
 	assert.Equal(t, 0, resourceMetrics.Resource().Attributes().Len())
 	assert.Equal(t, expectedEntity, entity)
 }
+
+func TestCreateCloudWatchEntityFromAttributesOnECS(t *testing.T) {
+	taskARN := "arn:aws:ecs:us-east-1:123456789:task/my-cluster/abc"
+	resourceMetrics := pmetric.NewResourceMetrics()
+	resourceMetrics.Resource().Attributes().PutStr(AttributeEntityType, AttributeEntityAWSResource)
+	resourceMetrics.Resource().Attributes().PutStr(AttributeEntityResourceType, AttributeEntityECSTaskResource)
+	resourceMetrics.Resource().Attributes().PutStr(AttributeEntityIdentifier, taskARN)
+	resourceMetrics.Resource().Attributes().PutStr(AttributeEntityAwsAccountId, "123456789")
+	resourceMetrics.Resource().Attributes().PutStr(AttributeEntityPlatformType, AttributeEntityECSPlatform)
+	resourceMetrics.Resource().Attributes().PutStr(AttributeEntityECSCluster, "my-cluster")
+	resourceMetrics.Resource().Attributes().PutStr(AttributeEntityECSService, "my-service")
+	resourceMetrics.Resource().Attributes().PutStr(AttributeEntityECSLaunchType, "FARGATE")
+	assert.Equal(t, 8, resourceMetrics.Resource().Attributes().Len())
+
+	expectedEntity := cloudwatch.Entity{
+		KeyAttributes: map[string]*string{
+			EntityType:   aws.String(AttributeEntityAWSResource),
+			ResourceType: aws.String(AttributeEntityECSTaskResource),
+			Identifier:   aws.String(taskARN),
+			AwsAccountId: aws.String("123456789"),
+		},
+		Attributes: map[string]*string{
+			Platform:      aws.String(AttributeEntityECSPlatform),
+			ECSCluster:    aws.String("my-cluster"),
+			ECSService:    aws.String("my-service"),
+			ECSLaunchType: aws.String("FARGATE"),
+		},
+	}
+	entity := CreateCloudWatchEntityFromAttributes(resourceMetrics.Resource().Attributes())
+	assert.Equal(t, 0, resourceMetrics.Resource().Attributes().Len())
+	assert.Equal(t, expectedEntity, entity)
+}
+
+func TestCreateCloudWatchEntityFromAttributesWithCustomAttributes(t *testing.T) {
+	resourceMetrics := pmetric.NewResourceMetrics()
+	resourceMetrics.Resource().Attributes().PutStr(AttributeEntityType, "Service")
+	resourceMetrics.Resource().Attributes().PutStr(AttributeEntityServiceName, "my-service")
+	resourceMetrics.Resource().Attributes().PutStr(AttributeEntityPlatformType, "AWS::EC2")
+	resourceMetrics.Resource().Attributes().PutStr(AttributeEntityAwsAccountId, "123456789")
+	resourceMetrics.Resource().Attributes().PutStr(AttributeEntityCustomPrefix+"team", "payments")
+	resourceMetrics.Resource().Attributes().PutStr(AttributeEntityCustomPrefix+"cost-center", "1234")
+	// the attributes set by the agent are not replaced
+	resourceMetrics.Resource().Attributes().PutStr(AttributeEntityCustomPrefix+Platform, "custom")
+	resourceMetrics.Resource().Attributes().PutStr("host", "my-host")
+
+	expectedEntity := cloudwatch.Entity{
+		KeyAttributes: map[string]*string{
+			EntityType:   aws.String(Service),
+			ServiceName:  aws.String("my-service"),
+			AwsAccountId: aws.String("123456789"),
+		},
+		Attributes: map[string]*string{
+			Platform:      aws.String("AWS::EC2"),
+			"team":        aws.String("payments"),
+			"cost-center": aws.String("1234"),
+		},
+	}
+	entity := CreateCloudWatchEntityFromAttributes(resourceMetrics.Resource().Attributes())
+	assert.Equal(t, map[string]any{"host": "my-host"}, resourceMetrics.Resource().Attributes().AsRaw())
+	assert.Equal(t, expectedEntity, entity)
+}
+
+func TestAddCustomAttributes(t *testing.T) {
+	attributes := map[string]*string{
+		Platform:   aws.String("AWS::EC2"),
+		InstanceID: aws.String("i-123"),
+	}
+	custom := map[string]string{
+		Platform:                 "custom",
+		"empty":                  "",
+		"long-value":             strings.Repeat("v", AttributeValueLengthMax+1),
+		strings.Repeat("k", 257): "long-key",
+	}
+	for i := 0; i < AttributesMax; i++ {
+		custom[fmt.Sprintf("key%02d", i)] = "value"
+	}
+	assert.Equal(t, 5, AddCustomAttributes(attributes, custom))
+	assert.Len(t, attributes, AttributesMax)
+	assert.Equal(t, "AWS::EC2", *attributes[Platform])
+	// the attributes are added in the order of their keys until the limit
+	assert.Contains(t, attributes, "key00")
+	assert.Contains(t, attributes, "key07")
+	assert.NotContains(t, attributes, "key08")
+}
@@ -0,0 +1,55 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package awsentity
+
+import (
+	"os"
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/extension/entitystore"
+)
+
+const (
+	// envServiceName and envDeploymentEnvironment are the environment variables that can be set
+	// on ephemeral compute (e.g. ECS on Fargate, batch jobs) where there is no platform metadata
+	// to derive the service entity from.
+	envServiceName           = "SERVICE_NAME"
+	envDeploymentEnvironment = "DEPLOY_ENV"
+)
+
+// envServiceAttributes are the service entity hints provided through environment variables.
+//
+// The hints are resolved with the following precedence:
+//  1. Incoming telemetry attributes and the CWA config (explicit configuration)
+//  2. Environment variable hints
+//  3. Platform heuristics (i.e. instance tags, IAM role, ASG, K8s workload)
+type envServiceAttributes struct {
+	ServiceName string
+	Environment string
+}
+
+// resolveEnvServiceAttributes reads the service entity hints from the agent's environment.
+func resolveEnvServiceAttributes() envServiceAttributes {
+	return envServiceAttributes{
+		ServiceName: strings.TrimSpace(os.Getenv(envServiceName)),
+		Environment: strings.TrimSpace(os.Getenv(envDeploymentEnvironment)),
+	}
+}
+
+// apply fills in the service name and environment that were not explicitly configured and
+// returns the updated values along with the service name source.
+func (e envServiceAttributes) apply(serviceName, environmentName, serviceNameSource string) (string, string, string) {
+	if serviceName == EMPTY && e.ServiceName != EMPTY {
+		serviceName = e.ServiceName
+		serviceNameSource = entitystore.ServiceNameSourceEnvironment
+	}
+	if environmentName == EMPTY {
+		environmentName = e.Environment
+	}
+	return serviceName, environmentName, serviceNameSource
+}
+
+func (e envServiceAttributes) isEmpty() bool {
+	return e.ServiceName == EMPTY && e.Environment == EMPTY
+}
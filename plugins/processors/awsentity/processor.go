@@ -86,6 +86,22 @@ var getEC2InfoFromEntityStore = func() entitystore.EC2Info {
 	return es.EC2Info()
 }
 
+var getECSInfoFromEntityStore = func() *entitystore.ECSInfo {
+	es := entitystore.GetEntityStore()
+	if es == nil {
+		return nil
+	}
+	return es.ECSInfo()
+}
+
+var getHostInfoFromEntityStore = func() *entitystore.HostInfo {
+	es := entitystore.GetEntityStore()
+	if es == nil {
+		return nil
+	}
+	return es.HostInfo()
+}
+
 var getAutoScalingGroupFromEntityStore = func() string {
 	// Get the following metric attributes from the EntityStore: EC2.AutoScalingGroup
 	es := entitystore.GetEntityStore()
@@ -95,6 +111,14 @@ var getAutoScalingGroupFromEntityStore = func() string {
 	return es.GetAutoScalingGroup()
 }
 
+var getEntityAttributesFromEntityStore = func() map[string]string {
+	es := entitystore.GetEntityStore()
+	if es == nil {
+		return nil
+	}
+	return es.EntityAttributes()
+}
+
 var getServiceNameSource = func() (string, string) {
 	es := entitystore.GetEntityStore()
 	if es == nil {
@@ -107,16 +131,18 @@ var getServiceNameSource = func() (string, string) {
 // deployment.environment resource attributes set, then adds the association between the log group(s) and the
 // service/environment names to the entitystore extension.
 type awsEntityProcessor struct {
-	config     *Config
-	k8sscraper scraper
-	logger     *zap.Logger
+	config        *Config
+	k8sscraper    scraper
+	envAttributes envServiceAttributes
+	logger        *zap.Logger
 }
 
 func newAwsEntityProcessor(config *Config, logger *zap.Logger) *awsEntityProcessor {
 	return &awsEntityProcessor{
-		config:     config,
-		k8sscraper: k8sattributescraper.NewK8sAttributeScraper(config.ClusterName),
-		logger:     logger,
+		config:        config,
+		k8sscraper:    k8sattributescraper.NewK8sAttributeScraper(config.ClusterName),
+		envAttributes: resolveEnvServiceAttributes(),
+		logger:        logger,
 	}
 }
 
@@ -145,7 +171,24 @@ func (p *awsEntityProcessor) processMetrics(_ context.Context, md pmetric.Metric
 					resourceAttrs.PutStr(entityattributes.AttributeEntityIdentifier, ec2Info.GetInstanceID())
 				}
 				AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityAwsAccountId, ec2Info.GetAccountID())
+			} else if p.config.Platform == config.ModeECS {
+				ecsInfo := getECSInfoFromEntityStore()
+				if ecsInfo != nil && ecsInfo.GetTaskARN() != EMPTY {
+					resourceAttrs.PutStr(entityattributes.AttributeEntityType, entityattributes.AttributeEntityAWSResource)
+					resourceAttrs.PutStr(entityattributes.AttributeEntityResourceType, entityattributes.AttributeEntityECSTaskResource)
+					resourceAttrs.PutStr(entityattributes.AttributeEntityIdentifier, ecsInfo.GetTaskARN())
+					resourceAttrs.PutStr(entityattributes.AttributeEntityPlatformType, entityattributes.AttributeEntityECSPlatform)
+					AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityAwsAccountId, ecsInfo.GetAccountID())
+					AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityECSCluster, ecsInfo.GetCluster())
+					AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityECSService, ecsInfo.GetServiceName())
+					AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityECSLaunchType, ecsInfo.GetLaunchType())
+				}
+			} else if isHybridPlatform(p.config.Platform) {
+				// There is no AWS resource for the hybrid hosts, so their metrics are
+				// associated with the service of the host
+				addHybridServiceEntity(resourceAttrs, getHostInfoFromEntityStore(), EMPTY, EMPTY, EMPTY)
 			}
+			addEntityAttributes(resourceAttrs)
 		case entityattributes.Service:
 			if logGroupNamesAttr, ok := resourceAttrs.Get(attributeAwsLogGroupNames); ok {
 				logGroupNames = logGroupNamesAttr.Str()
@@ -174,6 +217,9 @@ func (p *awsEntityProcessor) processMetrics(_ context.Context, md pmetric.Metric
 					entityServiceNameSource = entityattributes.AttributeServiceNameSourceUserConfig
 				}
 			}
+			// Environment variable hints are only used when the service attributes were not explicitly
+			// configured, but take precedence over the platform specific fallbacks below
+			entityServiceName, entityEnvironmentName, entityServiceNameSource = p.envAttributes.apply(entityServiceName, entityEnvironmentName, entityServiceNameSource)
 			if p.config.KubernetesMode != "" {
 				p.k8sscraper.Scrape(rm.At(i).Resource())
 				if p.config.Platform == config.ModeEC2 {
@@ -272,7 +318,19 @@ func (p *awsEntityProcessor) processMetrics(_ context.Context, md pmetric.Metric
 					AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityAutoScalingGroup, ec2Attributes.AutoScalingGroup)
 					AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityServiceNameSource, ec2Attributes.ServiceNameSource)
 				}
+			} else if isHybridPlatform(p.config.Platform) && getHostInfoFromEntityStore() != nil {
+				addHybridServiceEntity(resourceAttrs, getHostInfoFromEntityStore(), entityServiceName, entityEnvironmentName, entityServiceNameSource)
+			} else if !p.envAttributes.isEmpty() {
+				// There is no platform metadata (e.g. ECS on Fargate, batch jobs), so the service
+				// entity can only be built from the environment variable hints
+				ec2Info = getEC2InfoFromEntityStore()
+				AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityType, entityattributes.Service)
+				AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityServiceName, entityServiceName)
+				AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityDeploymentEnvironment, entityEnvironmentName)
+				AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityAwsAccountId, ec2Info.GetAccountID())
+				AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityServiceNameSource, entityServiceNameSource)
 			}
+			addEntityAttributes(resourceAttrs)
 			if logGroupNames == EMPTY || (serviceName == EMPTY && environmentName == EMPTY) {
 				continue
 			}
@@ -290,6 +348,50 @@ func (p *awsEntityProcessor) processMetrics(_ context.Context, md pmetric.Metric
 	return md, nil
 }
 
+func isHybridPlatform(platform string) bool {
+	return platform == config.ModeOnPrem || platform == config.ModeOnPremise
+}
+
+// addHybridServiceEntity adds the service entity of an Azure or on-premises
+// host. The service name falls back like on EC2, and the environment to the
+// resource group or the datacenter of the host.
+func addHybridServiceEntity(resourceAttrs pcommon.Map, hostInfo *entitystore.HostInfo, serviceName, environmentName, serviceNameSource string) {
+	if hostInfo == nil || hostInfo.GetAccountID() == EMPTY {
+		return
+	}
+	if serviceName == EMPTY && serviceNameSource == EMPTY {
+		serviceName, serviceNameSource = getServiceNameSource()
+	} else if serviceName != EMPTY && serviceNameSource == EMPTY {
+		serviceNameSource = entitystore.ServiceNameSourceUnknown
+	}
+	if environmentName == EMPTY {
+		environmentName = hostInfo.GetEnvironment()
+	}
+	AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityType, entityattributes.Service)
+	AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityServiceName, serviceName)
+	AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityDeploymentEnvironment, environmentName)
+	AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityAwsAccountId, hostInfo.GetAccountID())
+	resourceAttrs.PutStr(entityattributes.AttributeEntityPlatformType, entityattributes.AttributeEntityGenericPlatform)
+	AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityCloudProvider, hostInfo.GetCloudProvider())
+	AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityAzureSubscriptionID, hostInfo.GetSubscriptionID())
+	AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityAzureResourceGroup, hostInfo.GetResourceGroup())
+	AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityAzureVMID, hostInfo.GetVMID())
+	AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityAzureLocation, hostInfo.GetLocation())
+	AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityDatacenter, hostInfo.GetDatacenter())
+	AddAttributeIfNonEmpty(resourceAttrs, entityattributes.AttributeEntityServiceNameSource, serviceNameSource)
+}
+
+// addEntityAttributes adds the user defined entity attributes from the EntityStore
+// if an entity was created for the resource.
+func addEntityAttributes(resourceAttrs pcommon.Map) {
+	if _, ok := resourceAttrs.Get(entityattributes.AttributeEntityType); !ok {
+		return
+	}
+	for k, v := range getEntityAttributesFromEntityStore() {
+		resourceAttrs.PutStr(entityattributes.AttributeEntityCustomPrefix+k, v)
+	}
+}
+
 // scrapeServiceAttribute expands the datapoint attributes and search for
 // service name and environment attributes. This is only used for components
 // that only emit attributes on datapoint level. This code block contains a lot
@@ -439,6 +439,138 @@ func TestProcessMetricsResourceAttributeScraping(t *testing.T) {
 	}
 }
 
+func TestProcessMetricsEnvironmentVariableHints(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	tests := []struct {
+		name           string
+		platform       string
+		kubernetesMode string
+		clusterName    string
+		env            map[string]string
+		metrics        pmetric.Metrics
+		want           map[string]any
+	}{
+		{
+			name:     "EC2EnvironmentVariablesOverHeuristics",
+			platform: config.ModeEC2,
+			env:      map[string]string{envServiceName: "env-service", envDeploymentEnvironment: "env-environment"},
+			metrics:  generateMetrics(),
+			want: map[string]any{
+				entityattributes.AttributeEntityType:                  "Service",
+				entityattributes.AttributeEntityServiceName:           "env-service",
+				entityattributes.AttributeEntityDeploymentEnvironment: "env-environment",
+				entityattributes.AttributeEntityPlatformType:          "AWS::EC2",
+				entityattributes.AttributeEntityInstanceID:            "i-123456789",
+				entityattributes.AttributeEntityAwsAccountId:          "0123456789012",
+				entityattributes.AttributeEntityServiceNameSource:     "EnvironmentVariable",
+				entityattributes.AttributeEntityAutoScalingGroup:      "test-asg",
+			},
+		},
+		{
+			name:     "EC2ExplicitConfigOverEnvironmentVariables",
+			platform: config.ModeEC2,
+			env:      map[string]string{envServiceName: "env-service", envDeploymentEnvironment: "env-environment"},
+			metrics:  generateMetrics(attributeServiceName, "test-service"),
+			want: map[string]any{
+				entityattributes.AttributeEntityType:                  "Service",
+				entityattributes.AttributeEntityServiceName:           "test-service",
+				entityattributes.AttributeEntityDeploymentEnvironment: "env-environment",
+				entityattributes.AttributeEntityPlatformType:          "AWS::EC2",
+				entityattributes.AttributeEntityInstanceID:            "i-123456789",
+				entityattributes.AttributeEntityAwsAccountId:          "0123456789012",
+				entityattributes.AttributeEntityServiceNameSource:     "Unknown",
+				entityattributes.AttributeEntityAutoScalingGroup:      "test-asg",
+				attributeServiceName:                                  "test-service",
+			},
+		},
+		{
+			name:           "EKSEnvironmentVariablesOverWorkload",
+			kubernetesMode: config.ModeEKS,
+			clusterName:    "test-cluster",
+			env:            map[string]string{envServiceName: "env-service"},
+			metrics:        generateMetrics(semconv.AttributeK8SNamespaceName, "test-namespace", semconv.AttributeK8SDeploymentName, "test-workload", semconv.AttributeK8SNodeName, "test-node"),
+			want: map[string]any{
+				entityattributes.AttributeEntityType:                  "Service",
+				entityattributes.AttributeEntityServiceName:           "env-service",
+				entityattributes.AttributeEntityDeploymentEnvironment: "eks:test-cluster/test-namespace",
+				entityattributes.AttributeEntityCluster:               "test-cluster",
+				entityattributes.AttributeEntityNamespace:             "test-namespace",
+				entityattributes.AttributeEntityNode:                  "test-node",
+				entityattributes.AttributeEntityWorkload:              "test-workload",
+				entityattributes.AttributeEntityServiceNameSource:     "EnvironmentVariable",
+				entityattributes.AttributeEntityPlatformType:          "AWS::EKS",
+				semconv.AttributeK8SNamespaceName:                     "test-namespace",
+				semconv.AttributeK8SDeploymentName:                    "test-workload",
+				semconv.AttributeK8SNodeName:                          "test-node",
+			},
+		},
+		{
+			name:     "NoPlatformMetadata",
+			platform: config.ModeECS,
+			env:      map[string]string{envServiceName: " env-service ", envDeploymentEnvironment: "env-environment"},
+			metrics:  generateMetrics(),
+			want: map[string]any{
+				entityattributes.AttributeEntityType:                  "Service",
+				entityattributes.AttributeEntityServiceName:           "env-service",
+				entityattributes.AttributeEntityDeploymentEnvironment: "env-environment",
+				entityattributes.AttributeEntityAwsAccountId:          "0123456789012",
+				entityattributes.AttributeEntityServiceNameSource:     "EnvironmentVariable",
+			},
+		},
+		{
+			name:     "NoPlatformMetadataExplicitConfig",
+			platform: config.ModeECS,
+			env:      map[string]string{envDeploymentEnvironment: "env-environment"},
+			metrics:  generateMetrics(attributeServiceName, "test-service", attributeDeploymentEnvironment, "test-environment"),
+			want: map[string]any{
+				entityattributes.AttributeEntityType:                  "Service",
+				entityattributes.AttributeEntityServiceName:           "test-service",
+				entityattributes.AttributeEntityDeploymentEnvironment: "test-environment",
+				entityattributes.AttributeEntityAwsAccountId:          "0123456789012",
+				attributeServiceName:                                  "test-service",
+				attributeDeploymentEnvironment:                        "test-environment",
+			},
+		},
+		{
+			name:     "NoPlatformMetadataNoEnvironmentVariables",
+			platform: config.ModeECS,
+			metrics:  generateMetrics(attributeServiceName, "test-service"),
+			want: map[string]any{
+				attributeServiceName: "test-service",
+			},
+		},
+	}
+
+	resetServiceNameSource := getServiceNameSource
+	resetGetEC2InfoFromEntityStore := getEC2InfoFromEntityStore
+	resetGetAutoScalingGroup := getAutoScalingGroupFromEntityStore
+	defer func() {
+		getServiceNameSource = resetServiceNameSource
+		getEC2InfoFromEntityStore = resetGetEC2InfoFromEntityStore
+		getAutoScalingGroupFromEntityStore = resetGetAutoScalingGroup
+	}()
+	getServiceNameSource = newMockGetServiceNameAndSource("test-service-name", "ResourceTags")
+	getEC2InfoFromEntityStore = newMockGetEC2InfoFromEntityStore("i-123456789", "0123456789012")
+	getAutoScalingGroupFromEntityStore = newMockGetAutoScalingGroupFromEntityStore("test-asg")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envServiceName, "")
+			t.Setenv(envDeploymentEnvironment, "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			p := newAwsEntityProcessor(&Config{EntityType: attributeService, ClusterName: tt.clusterName}, logger)
+			p.config.Platform = tt.platform
+			p.config.KubernetesMode = tt.kubernetesMode
+			_, err := p.processMetrics(ctx, tt.metrics)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tt.metrics.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+		})
+	}
+}
+
 func TestProcessMetricsResourceEntityProcessing(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	ctx := context.Background()
@@ -491,6 +623,201 @@ func TestProcessMetricsResourceEntityProcessing(t *testing.T) {
 	}
 }
 
+func TestProcessMetricsECSTaskResourceEntityProcessing(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	taskARN := "arn:aws:ecs:us-west-2:0123456789012:task/my-cluster/abc"
+	tests := []struct {
+		name    string
+		ecsInfo *entitystore.ECSInfo
+		want    map[string]any
+	}{
+		{
+			name: "ResourceEntityECSTask",
+			ecsInfo: &entitystore.ECSInfo{
+				TaskARN:     taskARN,
+				Cluster:     "my-cluster",
+				ServiceName: "my-service",
+				LaunchType:  "FARGATE",
+				AccountID:   "0123456789012",
+			},
+			want: map[string]any{
+				entityattributes.AttributeEntityType:          entityattributes.AttributeEntityAWSResource,
+				entityattributes.AttributeEntityResourceType:  entityattributes.AttributeEntityECSTaskResource,
+				entityattributes.AttributeEntityIdentifier:    taskARN,
+				entityattributes.AttributeEntityPlatformType:  entityattributes.AttributeEntityECSPlatform,
+				entityattributes.AttributeEntityAwsAccountId:  "0123456789012",
+				entityattributes.AttributeEntityECSCluster:    "my-cluster",
+				entityattributes.AttributeEntityECSService:    "my-service",
+				entityattributes.AttributeEntityECSLaunchType: "FARGATE",
+			},
+		},
+		{
+			name:    "ResourceEntityECSTaskMetadataUnavailable",
+			ecsInfo: &entitystore.ECSInfo{},
+			want:    map[string]any{},
+		},
+		{
+			name: "ResourceEntityECSNoEntityStore",
+			want: map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getECSInfoFromEntityStore = func() *entitystore.ECSInfo {
+				return tt.ecsInfo
+			}
+			p := newAwsEntityProcessor(&Config{EntityType: entityattributes.Resource, Platform: config.ModeECS}, logger)
+			metrics := generateMetrics()
+			_, err := p.processMetrics(ctx, metrics)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, metrics.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+		})
+	}
+}
+
+func TestProcessMetricsHybridEntityProcessing(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	resetServiceNameSource := getServiceNameSource
+	getServiceNameSource = func() (string, string) {
+		return entitystore.ServiceNameUnknown, entitystore.ServiceNameSourceUnknown
+	}
+	defer func() { getServiceNameSource = resetServiceNameSource }()
+	resetGetHostInfo := getHostInfoFromEntityStore
+	defer func() { getHostInfoFromEntityStore = resetGetHostInfo }()
+
+	azureHost := &entitystore.HostInfo{
+		CloudProvider:  entitystore.CloudProviderAzure,
+		SubscriptionID: "8d10da13-8125-4ba9-a717-bf7490507b3d",
+		ResourceGroup:  "payments-rg",
+		VMID:           "02aab8a4-74ef-476e-8182-f6d2ba4166a6",
+		Location:       "westeurope",
+		AccountID:      "0123456789012",
+	}
+	tests := []struct {
+		name       string
+		entityType string
+		hostInfo   *entitystore.HostInfo
+		metrics    pmetric.Metrics
+		want       map[string]any
+	}{
+		{
+			name:       "ResourceEntityAzure",
+			entityType: entityattributes.Resource,
+			hostInfo:   azureHost,
+			metrics:    generateMetrics(),
+			want: map[string]any{
+				entityattributes.AttributeEntityType:                  entityattributes.Service,
+				entityattributes.AttributeEntityServiceName:           entitystore.ServiceNameUnknown,
+				entityattributes.AttributeEntityDeploymentEnvironment: "azure:payments-rg",
+				entityattributes.AttributeEntityAwsAccountId:          "0123456789012",
+				entityattributes.AttributeEntityPlatformType:          entityattributes.AttributeEntityGenericPlatform,
+				entityattributes.AttributeEntityCloudProvider:         entitystore.CloudProviderAzure,
+				entityattributes.AttributeEntityAzureSubscriptionID:   "8d10da13-8125-4ba9-a717-bf7490507b3d",
+				entityattributes.AttributeEntityAzureResourceGroup:    "payments-rg",
+				entityattributes.AttributeEntityAzureVMID:             "02aab8a4-74ef-476e-8182-f6d2ba4166a6",
+				entityattributes.AttributeEntityAzureLocation:         "westeurope",
+				entityattributes.AttributeEntityServiceNameSource:     entitystore.ServiceNameSourceUnknown,
+			},
+		},
+		{
+			name:       "ServiceEntityOnPremises",
+			entityType: entityattributes.Service,
+			hostInfo: &entitystore.HostInfo{
+				CloudProvider: entitystore.CloudProviderOnPremises,
+				Datacenter:    "dc-east-1",
+				AccountID:     "0123456789012",
+			},
+			metrics: generateMetrics(attributeServiceName, "test-service"),
+			want: map[string]any{
+				attributeServiceName:                                  "test-service",
+				entityattributes.AttributeEntityType:                  entityattributes.Service,
+				entityattributes.AttributeEntityServiceName:           "test-service",
+				entityattributes.AttributeEntityDeploymentEnvironment: "onprem:dc-east-1",
+				entityattributes.AttributeEntityAwsAccountId:          "0123456789012",
+				entityattributes.AttributeEntityPlatformType:          entityattributes.AttributeEntityGenericPlatform,
+				entityattributes.AttributeEntityCloudProvider:         entitystore.CloudProviderOnPremises,
+				entityattributes.AttributeEntityDatacenter:            "dc-east-1",
+				entityattributes.AttributeEntityServiceNameSource:     entitystore.ServiceNameSourceUnknown,
+			},
+		},
+		{
+			name:       "AccountIDUnavailable",
+			entityType: entityattributes.Resource,
+			hostInfo:   &entitystore.HostInfo{CloudProvider: entitystore.CloudProviderOnPremises},
+			metrics:    generateMetrics(),
+			want:       map[string]any{},
+		},
+		{
+			name:       "HybridEntityDisabled",
+			entityType: entityattributes.Resource,
+			metrics:    generateMetrics(),
+			want:       map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getHostInfoFromEntityStore = func() *entitystore.HostInfo {
+				return tt.hostInfo
+			}
+			p := newAwsEntityProcessor(&Config{EntityType: tt.entityType, Platform: config.ModeOnPremise}, logger)
+			_, err := p.processMetrics(ctx, tt.metrics)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tt.metrics.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+		})
+	}
+}
+
+func TestProcessMetricsEntityAttributes(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	ctx := context.Background()
+	resetGetEntityAttributes := getEntityAttributesFromEntityStore
+	getEntityAttributesFromEntityStore = func() map[string]string {
+		return map[string]string{"team": "payments"}
+	}
+	defer func() { getEntityAttributesFromEntityStore = resetGetEntityAttributes }()
+	resetGetEC2InfoFromEntityStore := getEC2InfoFromEntityStore
+	defer func() { getEC2InfoFromEntityStore = resetGetEC2InfoFromEntityStore }()
+
+	tests := []struct {
+		name     string
+		instance string
+		want     map[string]any
+	}{
+		{
+			name:     "WithEntity",
+			instance: "i-123456789",
+			want: map[string]any{
+				entityattributes.AttributeEntityType:                  entityattributes.AttributeEntityAWSResource,
+				entityattributes.AttributeEntityResourceType:          entityattributes.AttributeEntityEC2InstanceResource,
+				entityattributes.AttributeEntityIdentifier:            "i-123456789",
+				entityattributes.AttributeEntityAwsAccountId:          "0123456789012",
+				entityattributes.AttributeEntityCustomPrefix + "team": "payments",
+			},
+		},
+		{
+			name: "WithoutEntity",
+			want: map[string]any{
+				entityattributes.AttributeEntityAwsAccountId: "0123456789012",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getEC2InfoFromEntityStore = newMockGetEC2InfoFromEntityStore(tt.instance, "0123456789012")
+			p := newAwsEntityProcessor(&Config{EntityType: entityattributes.Resource, Platform: config.ModeEC2}, logger)
+			metrics := generateMetrics()
+			_, err := p.processMetrics(ctx, metrics)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, metrics.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+		})
+	}
+}
+
 func TestAWSEntityProcessorNoSensitiveInfoInLogs(t *testing.T) {
 	// Create a buffer to capture log output
 	var buf bytes.Buffer
@@ -0,0 +1,40 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cardinalitylimiter
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+const (
+	defaultMaxDimensionSets = 1000
+	defaultExpiration       = time.Hour
+)
+
+type Config struct {
+	// MaxDimensionSets is the number of distinct sets of dimension values
+	// published for each metric name. The data points of the other sets are
+	// published with the OTHER value for all their dimensions.
+	MaxDimensionSets int `mapstructure:"max_dimension_sets"`
+	// Expiration is the time after which a set of dimension values that is no
+	// longer seen stops counting toward the limit. The sets never expire if it
+	// is 0.
+	Expiration time.Duration `mapstructure:"expiration"`
+}
+
+// Verify Config implements Processor interface.
+var _ component.Config = (*Config)(nil)
+
+func (cfg *Config) Validate() error {
+	if cfg.MaxDimensionSets <= 0 {
+		return errors.New("max_dimension_sets must be greater than 0")
+	}
+	if cfg.Expiration < 0 {
+		return errors.New("expiration must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cardinalitylimiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func TestUnmarshalDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NoError(t, confmap.New().Unmarshal(cfg))
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg)
+	assert.NoError(t, cfg.(*Config).Validate())
+}
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]struct {
+		cfg     Config
+		wantErr string
+	}{
+		"WithValid": {
+			cfg: Config{MaxDimensionSets: 10, Expiration: time.Minute},
+		},
+		"WithoutExpiration": {
+			cfg: Config{MaxDimensionSets: 10},
+		},
+		"WithZeroLimit": {
+			cfg:     Config{Expiration: time.Minute},
+			wantErr: "max_dimension_sets must be greater than 0",
+		},
+		"WithNegativeExpiration": {
+			cfg:     Config{MaxDimensionSets: 10, Expiration: -time.Minute},
+			wantErr: "expiration must not be negative",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := testCase.cfg.Validate()
+			if testCase.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, testCase.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cardinalitylimiter
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+const (
+	// OverflowValue replaces the values of the dimensions of the data points
+	// over the limit.
+	OverflowValue = "OTHER"
+
+	// maxSweepInterval bounds how long the expired sets are kept around.
+	maxSweepInterval = time.Minute
+)
+
+// dimensionSets are the sets of dimension values of a metric name, and when
+// they were last seen.
+type dimensionSets struct {
+	lastSeen map[string]time.Time
+	// clamped is set once the limit is reached, so that it is only logged once.
+	clamped bool
+}
+
+type limiter struct {
+	maxDimensionSets int
+	expiration       time.Duration
+	component        string
+	logger           *zap.Logger
+	now              func() time.Time
+
+	mu        sync.Mutex
+	metrics   map[string]*dimensionSets
+	lastSweep time.Time
+}
+
+func newLimiter(config *Config, component string, logger *zap.Logger) *limiter {
+	return &limiter{
+		maxDimensionSets: config.MaxDimensionSets,
+		expiration:       config.Expiration,
+		component:        component,
+		logger:           logger,
+		now:              time.Now,
+		metrics:          make(map[string]*dimensionSets),
+	}
+}
+
+// processMetrics publishes the data points of the dimension sets over the
+// limit of their metric name with the OTHER value for all the dimensions, so
+// they are aggregated into a single series instead of creating new metrics.
+func (l *limiter) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.sweep(now)
+	clamped := 0
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				forEachAttributes(metric, func(attributes pcommon.Map) {
+					if !l.admit(metric.Name(), attributes, now) {
+						clampAttributes(attributes)
+						clamped++
+					}
+				})
+			}
+		}
+	}
+	if clamped > 0 {
+		selftelemetry.Add(selftelemetry.MetricClampedDatapoints, l.component, float64(clamped))
+	}
+	return md, nil
+}
+
+// admit returns true if the dimension set of the data point is within the
+// limit of the metric name.
+func (l *limiter) admit(name string, attributes pcommon.Map, now time.Time) bool {
+	key, overflow := attributesKey(attributes)
+	if overflow {
+		return true
+	}
+	sets, ok := l.metrics[name]
+	if !ok {
+		sets = &dimensionSets{lastSeen: make(map[string]time.Time)}
+		l.metrics[name] = sets
+	}
+	if _, ok = sets.lastSeen[key]; ok || len(sets.lastSeen) < l.maxDimensionSets {
+		sets.lastSeen[key] = now
+		return true
+	}
+	if !sets.clamped {
+		sets.clamped = true
+		l.logger.Warn("Metric reached the limit of dimension sets, the data points of the new sets are published with the OTHER dimension values",
+			zap.String("metric", name), zap.Int("max_dimension_sets", l.maxDimensionSets))
+	}
+	return false
+}
+
+// sweep forgets the dimension sets not seen for longer than the expiration.
+func (l *limiter) sweep(now time.Time) {
+	if l.expiration <= 0 || now.Sub(l.lastSweep) < min(l.expiration, maxSweepInterval) {
+		return
+	}
+	l.lastSweep = now
+	for name, sets := range l.metrics {
+		for key, lastSeen := range sets.lastSeen {
+			if now.Sub(lastSeen) > l.expiration {
+				delete(sets.lastSeen, key)
+			}
+		}
+		if len(sets.lastSeen) < l.maxDimensionSets {
+			sets.clamped = false
+		}
+		if len(sets.lastSeen) == 0 {
+			delete(l.metrics, name)
+		}
+	}
+}
+
+// attributesKey returns the dimension set of the attributes, and whether it is
+// already the overflow set.
+func attributesKey(attributes pcommon.Map) (string, bool) {
+	pairs := make([]string, 0, attributes.Len())
+	overflow := attributes.Len() > 0
+	attributes.Range(func(k string, v pcommon.Value) bool {
+		value := v.AsString()
+		overflow = overflow && value == OverflowValue
+		pairs = append(pairs, k+"="+value)
+		return true
+	})
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\x00"), overflow
+}
+
+func clampAttributes(attributes pcommon.Map) {
+	attributes.Range(func(_ string, v pcommon.Value) bool {
+		v.SetStr(OverflowValue)
+		return true
+	})
+}
+
+func forEachAttributes(metric pmetric.Metric, fn func(pcommon.Map)) {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes())
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cardinalitylimiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+type dataPoint struct {
+	metric     string
+	attributes map[string]string
+}
+
+func newMetrics(dataPoints ...dataPoint) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	for _, dp := range dataPoints {
+		m := metrics.AppendEmpty()
+		m.SetName(dp.metric)
+		attributes := m.SetEmptySum().DataPoints().AppendEmpty().Attributes()
+		for k, v := range dp.attributes {
+			attributes.PutStr(k, v)
+		}
+	}
+	return md
+}
+
+func attributesOf(md pmetric.Metrics) []map[string]any {
+	var got []map[string]any
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		got = append(got, metrics.At(i).Sum().DataPoints().At(0).Attributes().AsRaw())
+	}
+	return got
+}
+
+func clampedDatapoints(component string) float64 {
+	for _, sample := range selftelemetry.Default.Collect() {
+		if sample.Name == selftelemetry.MetricClampedDatapoints && sample.Component == component {
+			return sample.Value
+		}
+	}
+	return 0
+}
+
+func TestProcessMetrics(t *testing.T) {
+	l := newLimiter(&Config{MaxDimensionSets: 2}, "cardinalitylimiter/TestProcessMetrics", zap.NewNop())
+	md, err := l.processMetrics(context.Background(), newMetrics(
+		dataPoint{metric: "requests", attributes: map[string]string{"request_id": "1", "service": "a"}},
+		dataPoint{metric: "requests", attributes: map[string]string{"service": "a", "request_id": "2"}},
+		dataPoint{metric: "requests", attributes: map[string]string{"request_id": "3", "service": "a"}},
+		// the limit is per metric name
+		dataPoint{metric: "latency", attributes: map[string]string{"request_id": "3", "service": "a"}},
+		// the sets within the limit are still admitted
+		dataPoint{metric: "requests", attributes: map[string]string{"request_id": "1", "service": "a"}},
+	))
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"request_id": "1", "service": "a"},
+		{"request_id": "2", "service": "a"},
+		{"request_id": OverflowValue, "service": OverflowValue},
+		{"request_id": "3", "service": "a"},
+		{"request_id": "1", "service": "a"},
+	}, attributesOf(md))
+	assert.EqualValues(t, 1, clampedDatapoints("cardinalitylimiter/TestProcessMetrics"))
+
+	// the overflow set does not take a slot
+	md, err = l.processMetrics(context.Background(), newMetrics(
+		dataPoint{metric: "requests", attributes: map[string]string{"request_id": OverflowValue, "service": OverflowValue}},
+		dataPoint{metric: "requests", attributes: map[string]string{"request_id": "4", "service": "b"}},
+	))
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"request_id": OverflowValue, "service": OverflowValue},
+		{"request_id": OverflowValue, "service": OverflowValue},
+	}, attributesOf(md))
+	assert.EqualValues(t, 2, clampedDatapoints("cardinalitylimiter/TestProcessMetrics"))
+}
+
+func TestProcessMetricsExpiration(t *testing.T) {
+	now := time.Now()
+	l := newLimiter(&Config{MaxDimensionSets: 1, Expiration: 10 * time.Minute}, "cardinalitylimiter/TestProcessMetricsExpiration", zap.NewNop())
+	l.now = func() time.Time { return now }
+
+	_, err := l.processMetrics(context.Background(), newMetrics(dataPoint{metric: "requests", attributes: map[string]string{"request_id": "1"}}))
+	require.NoError(t, err)
+	now = now.Add(5 * time.Minute)
+	md, err := l.processMetrics(context.Background(), newMetrics(dataPoint{metric: "requests", attributes: map[string]string{"request_id": "2"}}))
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"request_id": OverflowValue}}, attributesOf(md))
+
+	// the first set is no longer seen, and makes room for the new ones
+	now = now.Add(6 * time.Minute)
+	md, err = l.processMetrics(context.Background(), newMetrics(dataPoint{metric: "requests", attributes: map[string]string{"request_id": "2"}}))
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"request_id": "2"}}, attributesOf(md))
+}
+
+func TestProcessMetricsTypes(t *testing.T) {
+	l := newLimiter(&Config{MaxDimensionSets: 1}, "cardinalitylimiter/TestProcessMetricsTypes", zap.NewNop())
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("gauge")
+	gauge.SetEmptyGauge()
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("histogram")
+	histogram.SetEmptyHistogram()
+	exponential := metrics.AppendEmpty()
+	exponential.SetName("exponential")
+	exponential.SetEmptyExponentialHistogram()
+	summary := metrics.AppendEmpty()
+	summary.SetName("summary")
+	summary.SetEmptySummary()
+	for _, id := range []string{"1", "2"} {
+		gauge.Gauge().DataPoints().AppendEmpty().Attributes().PutStr("id", id)
+		histogram.Histogram().DataPoints().AppendEmpty().Attributes().PutStr("id", id)
+		exponential.ExponentialHistogram().DataPoints().AppendEmpty().Attributes().PutStr("id", id)
+		summary.Summary().DataPoints().AppendEmpty().Attributes().PutStr("id", id)
+	}
+	_, err := l.processMetrics(context.Background(), md)
+	require.NoError(t, err)
+	for _, attributes := range []map[string]any{
+		gauge.Gauge().DataPoints().At(1).Attributes().AsRaw(),
+		histogram.Histogram().DataPoints().At(1).Attributes().AsRaw(),
+		exponential.ExponentialHistogram().DataPoints().At(1).Attributes().AsRaw(),
+		summary.Summary().DataPoints().At(1).Attributes().AsRaw(),
+	} {
+		assert.Equal(t, map[string]any{"id": OverflowValue}, attributes)
+	}
+	assert.EqualValues(t, 4, clampedDatapoints("cardinalitylimiter/TestProcessMetricsTypes"))
+}
@@ -0,0 +1,33 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package deltatocumulative
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+type Config struct {
+	// MaxStale is how long a stream is kept after its last datapoint. The
+	// next datapoint of an expired stream starts a new cumulative sum.
+	MaxStale time.Duration `mapstructure:"max_stale"`
+	// MaxStreams is the number of streams tracked at once. The datapoints of
+	// the new streams are dropped once it is reached.
+	MaxStreams int `mapstructure:"max_streams"`
+}
+
+// Verify Config implements Processor interface.
+var _ component.Config = (*Config)(nil)
+
+func (cfg *Config) Validate() error {
+	if cfg.MaxStale <= 0 {
+		return errors.New("max_stale must be positive")
+	}
+	if cfg.MaxStreams <= 0 {
+		return errors.New("max_streams must be positive")
+	}
+	return nil
+}
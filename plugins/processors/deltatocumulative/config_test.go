@@ -0,0 +1,48 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package deltatocumulative
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func TestUnmarshalDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NoError(t, confmap.New().Unmarshal(cfg))
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg)
+	assert.NoError(t, cfg.(*Config).Validate())
+}
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]struct {
+		cfg     Config
+		wantErr bool
+	}{
+		"WithValid": {
+			cfg: Config{MaxStale: time.Minute, MaxStreams: 10},
+		},
+		"WithZeroMaxStale": {
+			cfg:     Config{MaxStreams: 10},
+			wantErr: true,
+		},
+		"WithNegativeMaxStreams": {
+			cfg:     Config{MaxStale: time.Minute, MaxStreams: -1},
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if testCase.wantErr {
+				assert.Error(t, testCase.cfg.Validate())
+			} else {
+				assert.NoError(t, testCase.cfg.Validate())
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package deltatocumulative
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+// separator joins the parts of the stream keys. It is not valid UTF-8, so it
+// is not part of any attribute.
+const separator = "\xff"
+
+// accumulator converts the delta sums and histograms into cumulative ones by
+// adding up the datapoints of each stream. A stream is identified by the
+// resource, the scope, the metric and the datapoint attributes. The other
+// metrics are passed through unchanged.
+type accumulator struct {
+	*Config
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu        sync.Mutex
+	streams   map[string]*stream
+	lastSweep time.Time
+}
+
+func newAccumulator(config *Config, logger *zap.Logger) *accumulator {
+	return &accumulator{
+		Config:  config,
+		logger:  logger,
+		now:     time.Now,
+		streams: map[string]*stream{},
+	}
+}
+
+func (a *accumulator) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	a.sweep(now)
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resourceKey := attributesKey(rm.Resource().Attributes())
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			sm := sms.At(j)
+			scopeKey := resourceKey + separator + sm.Scope().Name() + separator + sm.Scope().Version()
+			sm.Metrics().RemoveIf(func(m pmetric.Metric) bool {
+				return a.processMetric(scopeKey, m, now)
+			})
+		}
+	}
+	return md, nil
+}
+
+// processMetric converts the metric if it is a delta sum or histogram.
+// Returns true if all of its datapoints were dropped.
+func (a *accumulator) processMetric(scopeKey string, m pmetric.Metric, now time.Time) bool {
+	metricKey := scopeKey + separator + m.Name() + separator + m.Unit() + separator + m.Type().String()
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		sum := m.Sum()
+		if sum.AggregationTemporality() != pmetric.AggregationTemporalityDelta {
+			return false
+		}
+		metricKey += separator + strconv.FormatBool(sum.IsMonotonic())
+		sum.DataPoints().RemoveIf(func(dp pmetric.NumberDataPoint) bool {
+			s, ok := a.lookup(metricKey+separator+attributesKey(dp.Attributes()), dp.StartTimestamp(), dp.Timestamp(), now)
+			if ok {
+				s.addNumber(dp)
+			}
+			return !ok
+		})
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		return sum.DataPoints().Len() == 0
+	case pmetric.MetricTypeHistogram:
+		histogram := m.Histogram()
+		if histogram.AggregationTemporality() != pmetric.AggregationTemporalityDelta {
+			return false
+		}
+		histogram.DataPoints().RemoveIf(func(dp pmetric.HistogramDataPoint) bool {
+			s, ok := a.lookup(metricKey+separator+attributesKey(dp.Attributes()), dp.StartTimestamp(), dp.Timestamp(), now)
+			if ok {
+				s.addHistogram(dp)
+			}
+			return !ok
+		})
+		histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		return histogram.DataPoints().Len() == 0
+	}
+	return false
+}
+
+// lookup returns the stream of the datapoint. Returns false if the datapoint
+// is dropped, either because it is not newer than the last datapoint of its
+// stream or because no more streams can be tracked.
+func (a *accumulator) lookup(key string, start, timestamp pcommon.Timestamp, now time.Time) (*stream, bool) {
+	s, ok := a.streams[key]
+	if ok && now.Sub(s.seen) > a.MaxStale {
+		delete(a.streams, key)
+		ok = false
+	}
+	if !ok {
+		if len(a.streams) >= a.MaxStreams {
+			a.logger.Debug("Dropping the datapoint of a new stream since the limit is reached", zap.Int("max_streams", a.MaxStreams))
+			return nil, false
+		}
+		if start == 0 {
+			start = timestamp
+		}
+		s = &stream{start: start}
+		a.streams[key] = s
+	} else if timestamp <= s.last {
+		a.logger.Debug("Dropping an out of order datapoint", zap.Time("timestamp", timestamp.AsTime()), zap.Time("last", s.last.AsTime()))
+		return nil, false
+	}
+	s.last = timestamp
+	s.seen = now
+	return s, true
+}
+
+// sweep removes the expired streams at most once every MaxStale.
+func (a *accumulator) sweep(now time.Time) {
+	if now.Sub(a.lastSweep) < a.MaxStale {
+		return
+	}
+	a.lastSweep = now
+	for key, s := range a.streams {
+		if now.Sub(s.seen) > a.MaxStale {
+			delete(a.streams, key)
+		}
+	}
+}
+
+// attributesKey returns the attributes sorted by key.
+func attributesKey(attrs pcommon.Map) string {
+	pairs := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		pairs = append(pairs, k+"="+v.AsString())
+		return true
+	})
+	sort.Strings(pairs)
+	return strings.Join(pairs, separator)
+}
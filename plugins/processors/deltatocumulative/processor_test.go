@@ -0,0 +1,152 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package deltatocumulative
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+var baseTime = time.Unix(1700000000, 0)
+
+func timestamp(seconds int) pcommon.Timestamp {
+	return pcommon.NewTimestampFromTime(baseTime.Add(time.Duration(seconds) * time.Second))
+}
+
+func newSum(name string, temporality pmetric.AggregationTemporality, seconds int, value int64, attrs map[string]any) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName(name)
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(temporality)
+	dp := sum.DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(timestamp(seconds - 10))
+	dp.SetTimestamp(timestamp(seconds))
+	dp.SetIntValue(value)
+	_ = dp.Attributes().FromRaw(attrs)
+	return md
+}
+
+func newHistogram(seconds int, bounds []float64, buckets []uint64, sum float64) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("latency")
+	histogram := m.SetEmptyHistogram()
+	histogram.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	dp := histogram.DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(timestamp(seconds - 10))
+	dp.SetTimestamp(timestamp(seconds))
+	dp.ExplicitBounds().FromRaw(bounds)
+	dp.BucketCounts().FromRaw(buckets)
+	var count uint64
+	for _, c := range buckets {
+		count += c
+	}
+	dp.SetCount(count)
+	dp.SetSum(sum)
+	dp.SetMin(sum / float64(count))
+	dp.SetMax(sum / float64(count))
+	return md
+}
+
+func newTestAccumulator(cfg *Config, now *time.Time) *accumulator {
+	a := newAccumulator(cfg, zap.NewNop())
+	a.now = func() time.Time { return *now }
+	return a
+}
+
+func process(t *testing.T, a *accumulator, md pmetric.Metrics) pmetric.MetricSlice {
+	t.Helper()
+	got, err := a.processMetrics(context.Background(), md)
+	require.NoError(t, err)
+	return got.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+}
+
+func TestProcessSums(t *testing.T) {
+	now := baseTime
+	a := newTestAccumulator(createDefaultConfig().(*Config), &now)
+
+	metrics := process(t, a, newSum("requests", pmetric.AggregationTemporalityDelta, 10, 3, map[string]any{"host": "a"}))
+	require.Equal(t, 1, metrics.Len())
+	sum := metrics.At(0).Sum()
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, sum.AggregationTemporality())
+	assert.True(t, sum.IsMonotonic())
+	assert.EqualValues(t, 3, sum.DataPoints().At(0).IntValue())
+	assert.Equal(t, timestamp(0), sum.DataPoints().At(0).StartTimestamp())
+
+	sum = process(t, a, newSum("requests", pmetric.AggregationTemporalityDelta, 20, 4, map[string]any{"host": "a"})).At(0).Sum()
+	assert.EqualValues(t, 7, sum.DataPoints().At(0).IntValue())
+	assert.Equal(t, timestamp(0), sum.DataPoints().At(0).StartTimestamp())
+	assert.Equal(t, timestamp(20), sum.DataPoints().At(0).Timestamp())
+
+	// the other attributes are another stream
+	sum = process(t, a, newSum("requests", pmetric.AggregationTemporalityDelta, 20, 5, map[string]any{"host": "b"})).At(0).Sum()
+	assert.EqualValues(t, 5, sum.DataPoints().At(0).IntValue())
+
+	// the datapoints that are not newer than the last one are dropped
+	metrics = process(t, a, newSum("requests", pmetric.AggregationTemporalityDelta, 15, 1, map[string]any{"host": "a"}))
+	assert.Equal(t, 0, metrics.Len())
+
+	// a double value turns the total into a double
+	md := newSum("requests", pmetric.AggregationTemporalityDelta, 30, 0, map[string]any{"host": "a"})
+	md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0).SetDoubleValue(0.5)
+	sum = process(t, a, md).At(0).Sum()
+	assert.Equal(t, 7.5, sum.DataPoints().At(0).DoubleValue())
+
+	// the cumulative sums are passed through
+	sum = process(t, a, newSum("requests", pmetric.AggregationTemporalityCumulative, 40, 100, map[string]any{"host": "a"})).At(0).Sum()
+	assert.EqualValues(t, 100, sum.DataPoints().At(0).IntValue())
+	assert.Equal(t, timestamp(30), sum.DataPoints().At(0).StartTimestamp())
+}
+
+func TestProcessHistograms(t *testing.T) {
+	now := baseTime
+	a := newTestAccumulator(createDefaultConfig().(*Config), &now)
+
+	process(t, a, newHistogram(10, []float64{1, 10}, []uint64{1, 2, 0}, 9))
+	histogram := process(t, a, newHistogram(20, []float64{1, 10}, []uint64{0, 1, 1}, 30)).At(0).Histogram()
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, histogram.AggregationTemporality())
+	dp := histogram.DataPoints().At(0)
+	assert.Equal(t, []uint64{1, 3, 1}, dp.BucketCounts().AsRaw())
+	assert.EqualValues(t, 5, dp.Count())
+	assert.Equal(t, 39.0, dp.Sum())
+	assert.Equal(t, 3.0, dp.Min())
+	assert.Equal(t, 15.0, dp.Max())
+	assert.Equal(t, timestamp(0), dp.StartTimestamp())
+
+	// the total starts over if the buckets change
+	dp = process(t, a, newHistogram(30, []float64{5}, []uint64{2, 2}, 20)).At(0).Histogram().DataPoints().At(0)
+	assert.Equal(t, []uint64{2, 2}, dp.BucketCounts().AsRaw())
+	assert.EqualValues(t, 4, dp.Count())
+	assert.Equal(t, timestamp(20), dp.StartTimestamp())
+}
+
+func TestProcessExpiredStreams(t *testing.T) {
+	now := baseTime
+	a := newTestAccumulator(&Config{MaxStale: time.Minute, MaxStreams: 1}, &now)
+
+	process(t, a, newSum("requests", pmetric.AggregationTemporalityDelta, 10, 3, nil))
+	// no more streams are tracked
+	metrics := process(t, a, newSum("errors", pmetric.AggregationTemporalityDelta, 10, 1, nil))
+	assert.Equal(t, 0, metrics.Len())
+
+	now = now.Add(2 * time.Minute)
+	metrics = process(t, a, newSum("errors", pmetric.AggregationTemporalityDelta, 130, 1, nil))
+	require.Equal(t, 1, metrics.Len())
+	assert.EqualValues(t, 1, metrics.At(0).Sum().DataPoints().At(0).IntValue())
+	// the expired stream starts over
+	now = now.Add(2 * time.Minute)
+	metrics = process(t, a, newSum("requests", pmetric.AggregationTemporalityDelta, 250, 2, nil))
+	require.Equal(t, 1, metrics.Len())
+	assert.EqualValues(t, 2, metrics.At(0).Sum().DataPoints().At(0).IntValue())
+	assert.Equal(t, timestamp(240), metrics.At(0).Sum().DataPoints().At(0).StartTimestamp())
+}
@@ -0,0 +1,106 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package deltatocumulative
+
+import (
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// stream is the running total of a delta sum or histogram.
+type stream struct {
+	// start is the start timestamp of the cumulative datapoints.
+	start pcommon.Timestamp
+	// last is the timestamp of the last datapoint added.
+	last pcommon.Timestamp
+	// seen is when the last datapoint was added, used to expire the stream.
+	seen time.Time
+
+	isDouble    bool
+	intValue    int64
+	doubleValue float64
+
+	count   uint64
+	sum     float64
+	hasSum  bool
+	min     float64
+	hasMin  bool
+	max     float64
+	hasMax  bool
+	bounds  []float64
+	buckets []uint64
+}
+
+// addNumber adds the value of the datapoint and replaces it with the total.
+// The total becomes a double once a double value is added.
+func (s *stream) addNumber(dp pmetric.NumberDataPoint) {
+	switch dp.ValueType() {
+	case pmetric.NumberDataPointValueTypeInt:
+		if s.isDouble {
+			s.doubleValue += float64(dp.IntValue())
+			dp.SetDoubleValue(s.doubleValue)
+		} else {
+			s.intValue += dp.IntValue()
+			dp.SetIntValue(s.intValue)
+		}
+	case pmetric.NumberDataPointValueTypeDouble:
+		if !s.isDouble {
+			s.isDouble = true
+			s.doubleValue = float64(s.intValue)
+		}
+		s.doubleValue += dp.DoubleValue()
+		dp.SetDoubleValue(s.doubleValue)
+	}
+	dp.SetStartTimestamp(s.start)
+}
+
+// addHistogram adds the datapoint and replaces it with the total. The total
+// starts over from the datapoint if its buckets changed.
+func (s *stream) addHistogram(dp pmetric.HistogramDataPoint) {
+	bounds := dp.ExplicitBounds().AsRaw()
+	buckets := dp.BucketCounts().AsRaw()
+	if s.buckets != nil && (!slices.Equal(s.bounds, bounds) || len(s.buckets) != len(buckets)) {
+		start := dp.StartTimestamp()
+		if start == 0 {
+			start = dp.Timestamp()
+		}
+		*s = stream{start: start, last: s.last, seen: s.seen}
+	}
+	if s.buckets == nil {
+		s.bounds = bounds
+		s.buckets = make([]uint64, len(buckets))
+	}
+	for i, count := range buckets {
+		s.buckets[i] += count
+	}
+	s.count += dp.Count()
+	if dp.HasSum() {
+		s.sum += dp.Sum()
+		s.hasSum = true
+	}
+	if dp.HasMin() && (!s.hasMin || dp.Min() < s.min) {
+		s.min = dp.Min()
+		s.hasMin = true
+	}
+	if dp.HasMax() && (!s.hasMax || dp.Max() > s.max) {
+		s.max = dp.Max()
+		s.hasMax = true
+	}
+
+	dp.SetStartTimestamp(s.start)
+	dp.SetCount(s.count)
+	dp.BucketCounts().FromRaw(s.buckets)
+	if s.hasSum {
+		dp.SetSum(s.sum)
+	}
+	if s.hasMin {
+		dp.SetMin(s.min)
+	}
+	if s.hasMax {
+		dp.SetMax(s.max)
+	}
+}
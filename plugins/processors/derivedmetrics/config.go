@@ -0,0 +1,51 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package derivedmetrics
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+type Config struct {
+	// Metrics are evaluated in order, so the expressions can reference the
+	// derived metrics before them.
+	Metrics []DerivedMetric `mapstructure:"metrics,omitempty"`
+}
+
+type DerivedMetric struct {
+	// Name of the derived metric, e.g. mem_used_percent_excluding_cache.
+	Name string `mapstructure:"name"`
+	// Expression is the arithmetic expression of the metrics it is derived
+	// from, e.g. 100 * (mem_used - mem_cached) / mem_total.
+	Expression string `mapstructure:"expression"`
+	// Unit of the derived metric, e.g. Percent.
+	Unit string `mapstructure:"unit,omitempty"`
+}
+
+// Verify Config implements Processor interface.
+var _ component.Config = (*Config)(nil)
+
+func (cfg *Config) Validate() error {
+	names := make(map[string]struct{}, len(cfg.Metrics))
+	for _, metric := range cfg.Metrics {
+		if metric.Name == "" {
+			return errors.New("derived metric name must not be empty")
+		}
+		if _, ok := names[metric.Name]; ok {
+			return fmt.Errorf("derived metric %q is defined more than once", metric.Name)
+		}
+		names[metric.Name] = struct{}{}
+		expr, err := parseExpression(metric.Expression)
+		if err != nil {
+			return fmt.Errorf("derived metric %q has an invalid expression: %w", metric.Name, err)
+		}
+		if _, ok := expr.operands()[metric.Name]; ok {
+			return fmt.Errorf("derived metric %q references itself", metric.Name)
+		}
+	}
+	return nil
+}
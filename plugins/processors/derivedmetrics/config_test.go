@@ -0,0 +1,58 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package derivedmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func TestUnmarshalDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NoError(t, confmap.New().Unmarshal(cfg))
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]struct {
+		cfg     Config
+		wantErr string
+	}{
+		"WithValid": {
+			cfg: Config{Metrics: []DerivedMetric{
+				{Name: "mem_cached_bytes", Expression: "mem_cached"},
+				{Name: "mem_used_percent_excluding_cache", Expression: "100 * (mem_used - mem_cached_bytes) / mem_total", Unit: "Percent"},
+			}},
+		},
+		"WithEmptyName": {
+			cfg:     Config{Metrics: []DerivedMetric{{Expression: "mem_used"}}},
+			wantErr: "derived metric name must not be empty",
+		},
+		"WithDuplicateName": {
+			cfg:     Config{Metrics: []DerivedMetric{{Name: "a", Expression: "b"}, {Name: "a", Expression: "c"}}},
+			wantErr: `derived metric "a" is defined more than once`,
+		},
+		"WithInvalidExpression": {
+			cfg:     Config{Metrics: []DerivedMetric{{Name: "a", Expression: "b +"}}},
+			wantErr: `derived metric "a" has an invalid expression: unexpected end of expression`,
+		},
+		"WithSelfReference": {
+			cfg:     Config{Metrics: []DerivedMetric{{Name: "a", Expression: "a * 2"}}},
+			wantErr: `derived metric "a" references itself`,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := testCase.cfg.Validate()
+			if testCase.wantErr != "" {
+				assert.EqualError(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
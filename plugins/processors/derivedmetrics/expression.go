@@ -0,0 +1,218 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package derivedmetrics
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// expression is the parsed arithmetic expression of a derived metric. The
+// grammar is:
+//
+//	expression = term { ("+" | "-") term }
+//	term       = factor { ("*" | "/") factor }
+//	factor     = "-" factor | number | metric name | "(" expression ")"
+type expression interface {
+	// evaluate returns false if an operand is missing or the result is not
+	// a number, e.g. on a division by zero.
+	evaluate(values map[string]float64) (float64, bool)
+	operands() map[string]struct{}
+}
+
+type number float64
+
+func (n number) evaluate(map[string]float64) (float64, bool) {
+	return float64(n), true
+}
+
+func (n number) operands() map[string]struct{} {
+	return map[string]struct{}{}
+}
+
+type operand string
+
+func (o operand) evaluate(values map[string]float64) (float64, bool) {
+	value, ok := values[string(o)]
+	return value, ok
+}
+
+func (o operand) operands() map[string]struct{} {
+	return map[string]struct{}{string(o): {}}
+}
+
+type negation struct {
+	expr expression
+}
+
+func (n negation) evaluate(values map[string]float64) (float64, bool) {
+	value, ok := n.expr.evaluate(values)
+	return -value, ok
+}
+
+func (n negation) operands() map[string]struct{} {
+	return n.expr.operands()
+}
+
+type binary struct {
+	op          byte
+	left, right expression
+}
+
+func (b binary) evaluate(values map[string]float64) (float64, bool) {
+	left, ok := b.left.evaluate(values)
+	if !ok {
+		return 0, false
+	}
+	right, ok := b.right.evaluate(values)
+	if !ok {
+		return 0, false
+	}
+	switch b.op {
+	case '+':
+		return left + right, true
+	case '-':
+		return left - right, true
+	case '*':
+		return left * right, true
+	default:
+		if right == 0 {
+			return 0, false
+		}
+		return left / right, true
+	}
+}
+
+func (b binary) operands() map[string]struct{} {
+	result := b.left.operands()
+	for name := range b.right.operands() {
+		result[name] = struct{}{}
+	}
+	return result
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func parseExpression(input string) (expression, error) {
+	p := &parser{input: input}
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+	}
+	return expr, nil
+}
+
+func (p *parser) parseExpression() (expression, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == '+' || p.peek() == '-' {
+		op := p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseTerm() (expression, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == '*' || p.peek() == '/' {
+		op := p.next()
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseFactor() (expression, error) {
+	c := p.peek()
+	switch {
+	case c == 0:
+		return nil, errors.New("unexpected end of expression")
+	case c == '-':
+		p.next()
+		expr, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return negation{expr: expr}, nil
+	case c == '(':
+		p.next()
+		expr, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("missing ) at position %d", p.pos)
+		}
+		p.next()
+		return expr, nil
+	case isDigit(c) || c == '.':
+		start := p.pos
+		for p.pos < len(p.input) && (isDigit(p.input[p.pos]) || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		value, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", p.input[start:p.pos])
+		}
+		return number(value), nil
+	case isNameStart(c):
+		start := p.pos
+		for p.pos < len(p.input) && (isNameStart(p.input[p.pos]) || isDigit(p.input[p.pos])) {
+			p.pos++
+		}
+		return operand(p.input[start:p.pos]), nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", c, p.pos)
+	}
+}
+
+// peek returns the next character that is not a space, or 0 at the end of
+// the input.
+func (p *parser) peek() byte {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *parser) next() byte {
+	c := p.peek()
+	p.pos++
+	return c
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
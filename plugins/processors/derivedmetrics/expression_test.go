@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package derivedmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpression(t *testing.T) {
+	values := map[string]float64{"mem_used": 6, "mem_cached": 2, "mem_total": 8, "zero": 0}
+	testCases := map[string]struct {
+		input    string
+		want     float64
+		operands []string
+		missing  bool
+	}{
+		"Number":       {input: "42.5", want: 42.5},
+		"Operand":      {input: "mem_used", want: 6, operands: []string{"mem_used"}},
+		"Precedence":   {input: "1 + 2 * 3 - 4 / 2", want: 5},
+		"LeftToRight":  {input: "8 - 2 - 1", want: 5},
+		"Parentheses":  {input: "100 * (mem_used - mem_cached) / mem_total", want: 50, operands: []string{"mem_used", "mem_cached", "mem_total"}},
+		"Negation":     {input: "-mem_used + -(-2)", want: -4, operands: []string{"mem_used"}},
+		"Missing":      {input: "mem_free / mem_total", operands: []string{"mem_free", "mem_total"}, missing: true},
+		"DivideByZero": {input: "mem_used / zero", operands: []string{"mem_used", "zero"}, missing: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			expr, err := parseExpression(testCase.input)
+			require.NoError(t, err)
+			got, ok := expr.evaluate(values)
+			assert.Equal(t, !testCase.missing, ok)
+			if ok {
+				assert.Equal(t, testCase.want, got)
+			}
+			var operands []string
+			for name := range expr.operands() {
+				operands = append(operands, name)
+			}
+			assert.ElementsMatch(t, testCase.operands, operands)
+		})
+	}
+}
+
+func TestParseExpressionFails(t *testing.T) {
+	testCases := map[string]string{
+		"":          "unexpected end of expression",
+		"a +":       "unexpected end of expression",
+		"(a + b":    "missing ) at position 6",
+		"a b":       `unexpected 'b' at position 2`,
+		"a % b":     `unexpected '%' at position 2`,
+		"1.2.3 * a": `invalid number "1.2.3"`,
+		"a * )":     `unexpected ')' at position 4`,
+	}
+	for input, wantErr := range testCases {
+		t.Run(input, func(t *testing.T) {
+			_, err := parseExpression(input)
+			assert.EqualError(t, err, wantErr)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package derivedmetrics
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// numCPUsOperand can be used in the expressions to normalize per core, e.g.
+// procstat_cpu_usage / num_cpus.
+const numCPUsOperand = "num_cpus"
+
+type derivedMetric struct {
+	DerivedMetric
+	expr expression
+}
+
+type deriver struct {
+	metrics []derivedMetric
+	numCPUs float64
+}
+
+// series is the values of the data points with the same attributes, which
+// were collected together, e.g. the fields of the mem plugin.
+type series struct {
+	attributes pcommon.Map
+	timestamp  pcommon.Timestamp
+	values     map[string]float64
+}
+
+func newDeriver(config *Config) (*deriver, error) {
+	d := &deriver{numCPUs: float64(runtime.NumCPU())}
+	for _, metric := range config.Metrics {
+		expr, err := parseExpression(metric.Expression)
+		if err != nil {
+			return nil, err
+		}
+		d.metrics = append(d.metrics, derivedMetric{DerivedMetric: metric, expr: expr})
+	}
+	return d, nil
+}
+
+// processMetrics appends the derived metrics to each scope. The operands are
+// the gauge and sum data points of the scope with the same attributes, so
+// they have to be collected by the same plugin.
+func (d *deriver) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			d.derive(sms.At(j).Metrics())
+		}
+	}
+	return md, nil
+}
+
+func (d *deriver) derive(metrics pmetric.MetricSlice) {
+	allSeries := d.groupSeries(metrics)
+	if len(allSeries) == 0 {
+		return
+	}
+	for _, metric := range d.metrics {
+		var dps pmetric.NumberDataPointSlice
+		appended := false
+		for _, s := range allSeries {
+			value, ok := metric.expr.evaluate(s.values)
+			if !ok || math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			s.values[metric.Name] = value
+			if !appended {
+				appended = true
+				m := metrics.AppendEmpty()
+				m.SetName(metric.Name)
+				m.SetUnit(metric.Unit)
+				dps = m.SetEmptyGauge().DataPoints()
+			}
+			dp := dps.AppendEmpty()
+			dp.SetDoubleValue(value)
+			dp.SetTimestamp(s.timestamp)
+			s.attributes.CopyTo(dp.Attributes())
+		}
+	}
+}
+
+// groupSeries returns the series in the order they were collected.
+func (d *deriver) groupSeries(metrics pmetric.MetricSlice) []*series {
+	var allSeries []*series
+	index := map[string]*series{}
+	for i := 0; i < metrics.Len(); i++ {
+		metric := metrics.At(i)
+		var dps pmetric.NumberDataPointSlice
+		switch metric.Type() {
+		case pmetric.MetricTypeGauge:
+			dps = metric.Gauge().DataPoints()
+		case pmetric.MetricTypeSum:
+			dps = metric.Sum().DataPoints()
+		default:
+			continue
+		}
+		for j := 0; j < dps.Len(); j++ {
+			dp := dps.At(j)
+			key := attributesKey(dp.Attributes())
+			s, ok := index[key]
+			if !ok {
+				s = &series{
+					attributes: dp.Attributes(),
+					values:     map[string]float64{numCPUsOperand: d.numCPUs},
+				}
+				index[key] = s
+				allSeries = append(allSeries, s)
+			}
+			if dp.Timestamp() > s.timestamp {
+				s.timestamp = dp.Timestamp()
+			}
+			switch dp.ValueType() {
+			case pmetric.NumberDataPointValueTypeInt:
+				s.values[metric.Name()] = float64(dp.IntValue())
+			case pmetric.NumberDataPointValueTypeDouble:
+				s.values[metric.Name()] = dp.DoubleValue()
+			}
+		}
+	}
+	return allSeries
+}
+
+func attributesKey(attrs pcommon.Map) string {
+	keys := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, _ pcommon.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		v, _ := attrs.Get(k)
+		sb.WriteString(k)
+		sb.WriteByte(0)
+		sb.WriteString(v.AsString())
+		sb.WriteByte(0)
+	}
+	return sb.String()
+}
@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package derivedmetrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func addGauge(metrics pmetric.MetricSlice, name string, value float64, ts pcommon.Timestamp, attrs map[string]any) {
+	metric := metrics.AppendEmpty()
+	metric.SetName(name)
+	point := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+	point.SetDoubleValue(value)
+	point.SetTimestamp(ts)
+	_ = point.Attributes().FromRaw(attrs)
+}
+
+func TestProcessMetrics(t *testing.T) {
+	d, err := newDeriver(&Config{Metrics: []DerivedMetric{
+		{Name: "mem_used_percent_excluding_cache", Expression: "100 * (mem_used - mem_cached) / mem_total", Unit: "Percent"},
+		{Name: "mem_free_percent_excluding_cache", Expression: "100 - mem_used_percent_excluding_cache", Unit: "Percent"},
+		{Name: "cpu_usage_active_per_core", Expression: "cpu_usage_active / num_cpus", Unit: "Percent"},
+		{Name: "swap_used_ratio", Expression: "swap_used / swap_total"},
+	}})
+	require.NoError(t, err)
+	d.numCPUs = 4
+
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	host := map[string]any{"host": "a"}
+	addGauge(metrics, "mem_used", 6, 10, host)
+	addGauge(metrics, "mem_cached", 2, 12, host)
+	// the other series of the scope are not operands of the host series
+	addGauge(metrics, "mem_cached", 4, 12, map[string]any{"host": "b"})
+	sum := metrics.AppendEmpty()
+	sum.SetName("mem_total")
+	total := sum.SetEmptySum().DataPoints().AppendEmpty()
+	total.SetIntValue(8)
+	total.SetTimestamp(11)
+	_ = total.Attributes().FromRaw(host)
+	addGauge(metrics, "cpu_usage_active", 80, 10, map[string]any{"host": "a", "cpu": "cpu-total"})
+	addGauge(metrics, "cpu_usage_active", 20, 10, map[string]any{"host": "a", "cpu": "cpu0"})
+	addGauge(metrics, "swap_total", 0, 10, host)
+	addGauge(metrics, "swap_used", 0, 10, host)
+
+	got, err := d.processMetrics(context.Background(), md)
+	require.NoError(t, err)
+	metrics = got.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 11, metrics.Len())
+
+	type point struct {
+		value      float64
+		timestamp  pcommon.Timestamp
+		attributes map[string]any
+	}
+	want := map[string]struct {
+		unit   string
+		points []point
+	}{
+		"mem_used_percent_excluding_cache": {unit: "Percent", points: []point{{50, 12, host}}},
+		"mem_free_percent_excluding_cache": {unit: "Percent", points: []point{{50, 12, host}}},
+		"cpu_usage_active_per_core": {unit: "Percent", points: []point{
+			{20, 10, map[string]any{"host": "a", "cpu": "cpu-total"}},
+			{5, 10, map[string]any{"host": "a", "cpu": "cpu0"}},
+		}},
+	}
+	for i := 8; i < metrics.Len(); i++ {
+		metric := metrics.At(i)
+		expected, ok := want[metric.Name()]
+		require.True(t, ok, metric.Name())
+		assert.Equal(t, expected.unit, metric.Unit())
+		require.Equal(t, pmetric.MetricTypeGauge, metric.Type())
+		dps := metric.Gauge().DataPoints()
+		require.Equal(t, len(expected.points), dps.Len())
+		for j, p := range expected.points {
+			assert.Equal(t, p.value, dps.At(j).DoubleValue())
+			assert.Equal(t, p.timestamp, dps.At(j).Timestamp())
+			assert.Equal(t, p.attributes, dps.At(j).Attributes().AsRaw())
+		}
+	}
+}
+
+func TestProcessMetricsWithoutOperands(t *testing.T) {
+	d, err := newDeriver(&Config{Metrics: []DerivedMetric{{Name: "ratio", Expression: "a / b"}}})
+	require.NoError(t, err)
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	addGauge(metrics, "a", 1, 10, nil)
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("b")
+	histogram.SetEmptyHistogram().DataPoints().AppendEmpty().SetSum(1)
+
+	got, err := d.processMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().Len())
+}
@@ -0,0 +1,48 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimensionnormalizer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+type Config struct {
+	// LowercaseKeys converts the data point attribute keys to lower case,
+	// e.g. Host to host.
+	LowercaseKeys bool `mapstructure:"lowercase_keys,omitempty"`
+	// Synonyms maps attribute keys to their canonical key, e.g. hostname to
+	// host. The keys are matched ignoring case if LowercaseKeys is set. The
+	// canonical keys are used as is.
+	Synonyms map[string]string `mapstructure:"synonyms,omitempty"`
+	// ReportFile is where the migration report of the renamed keys is written.
+	ReportFile string `mapstructure:"report_file,omitempty"`
+}
+
+// Verify Config implements Processor interface.
+var _ component.Config = (*Config)(nil)
+
+func (cfg *Config) Validate() error {
+	canonical := make(map[string]string, len(cfg.Synonyms))
+	for key, target := range cfg.Synonyms {
+		if key == "" {
+			return errors.New("synonym key must not be empty")
+		}
+		if target == "" {
+			return fmt.Errorf("canonical key of synonym %q must not be empty", key)
+		}
+		if !cfg.LowercaseKeys {
+			continue
+		}
+		lower := strings.ToLower(key)
+		if previous, ok := canonical[lower]; ok && previous != target {
+			return fmt.Errorf("synonym %q maps to both %q and %q when ignoring case", key, previous, target)
+		}
+		canonical[lower] = target
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimensionnormalizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func TestUnmarshalDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NoError(t, confmap.New().Unmarshal(cfg))
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]struct {
+		cfg     Config
+		wantErr bool
+	}{
+		"WithValid": {
+			cfg: Config{LowercaseKeys: true, Synonyms: map[string]string{"hostname": "host", "instance": "InstanceId"}},
+		},
+		"WithEmptyKey": {
+			cfg:     Config{Synonyms: map[string]string{"": "host"}},
+			wantErr: true,
+		},
+		"WithEmptyTarget": {
+			cfg:     Config{Synonyms: map[string]string{"hostname": ""}},
+			wantErr: true,
+		},
+		"WithCaseConflict": {
+			cfg:     Config{LowercaseKeys: true, Synonyms: map[string]string{"Node": "host", "node": "node_name"}},
+			wantErr: true,
+		},
+		"WithCaseSensitiveSynonyms": {
+			cfg: Config{Synonyms: map[string]string{"Node": "host", "node": "node_name"}},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if testCase.wantErr {
+				assert.Error(t, testCase.cfg.Validate())
+			} else {
+				assert.NoError(t, testCase.cfg.Validate())
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimensionnormalizer
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+const (
+	// reservedKeyPrefix is used by the attributes that control the CloudWatch
+	// exporter, e.g. aws:StorageResolution. They are never renamed.
+	reservedKeyPrefix = "aws:"
+
+	reportInterval = time.Minute
+)
+
+type rename struct {
+	from string
+	to   string
+}
+
+type normalizer struct {
+	*Config
+	logger   *zap.Logger
+	synonyms map[string]string
+
+	mu        sync.Mutex
+	report    *report
+	lastSaved time.Time
+	now       func() time.Time
+}
+
+func newNormalizer(config *Config, logger *zap.Logger) *normalizer {
+	synonyms := make(map[string]string, len(config.Synonyms))
+	for key, target := range config.Synonyms {
+		if config.LowercaseKeys {
+			key = strings.ToLower(key)
+		}
+		synonyms[key] = target
+	}
+	return &normalizer{
+		Config:   config,
+		logger:   logger,
+		synonyms: synonyms,
+		report:   newReport(),
+		now:      time.Now,
+	}
+}
+
+func (n *normalizer) shutdown(context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.save()
+	return nil
+}
+
+func (n *normalizer) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				for _, attrs := range dataPointAttributes(metric) {
+					n.normalize(metric.Name(), attrs)
+				}
+			}
+		}
+	}
+	if n.report.dirty && n.now().Sub(n.lastSaved) >= reportInterval {
+		n.save()
+	}
+	return md, nil
+}
+
+// canonicalKey returns the key the attribute key is renamed to.
+func (n *normalizer) canonicalKey(key string) string {
+	if strings.HasPrefix(key, reservedKeyPrefix) {
+		return key
+	}
+	lookup := key
+	if n.LowercaseKeys {
+		lookup = strings.ToLower(key)
+	}
+	if target, ok := n.synonyms[lookup]; ok {
+		return target
+	}
+	return lookup
+}
+
+// normalize renames the attribute keys. If the canonical key is already
+// set, the value of the canonical key is kept and the other one is dropped.
+func (n *normalizer) normalize(metricName string, attrs pcommon.Map) {
+	var renames []rename
+	attrs.Range(func(k string, _ pcommon.Value) bool {
+		if target := n.canonicalKey(k); target != k {
+			renames = append(renames, rename{from: k, to: target})
+		}
+		return true
+	})
+	if len(renames) == 0 {
+		return
+	}
+	series := seriesHash(metricName, attrs)
+	for _, r := range renames {
+		value, _ := attrs.Get(r.from)
+		_, conflict := attrs.Get(r.to)
+		if !conflict {
+			value.CopyTo(attrs.PutEmpty(r.to))
+		}
+		attrs.Remove(r.from)
+		if n.report.add(metricName, r, series, conflict) {
+			n.logger.Info("Normalized dimension key",
+				zap.String("metric", metricName), zap.String("from", r.from), zap.String("to", r.to))
+		}
+	}
+}
+
+func (n *normalizer) save() {
+	if n.ReportFile == "" || !n.report.dirty {
+		return
+	}
+	n.lastSaved = n.now()
+	if err := n.report.save(n.ReportFile, n.lastSaved); err != nil {
+		n.logger.Warn("Unable to save the dimension normalization report", zap.String("file", n.ReportFile), zap.Error(err))
+		return
+	}
+	n.report.dirty = false
+}
+
+// seriesHash identifies the series of the data point before the rename.
+func seriesHash(metricName string, attrs pcommon.Map) uint64 {
+	keys := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, _ pcommon.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Strings(keys)
+	h := fnv.New64a()
+	h.Write([]byte(metricName))
+	for _, k := range keys {
+		v, _ := attrs.Get(k)
+		h.Write([]byte{0})
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(v.AsString()))
+	}
+	return h.Sum64()
+}
+
+func dataPointAttributes(metric pmetric.Metric) []pcommon.Map {
+	var attrs []pcommon.Map
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attrs = append(attrs, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attrs = append(attrs, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attrs = append(attrs, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attrs = append(attrs, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attrs = append(attrs, dps.At(i).Attributes())
+		}
+	}
+	return attrs
+}
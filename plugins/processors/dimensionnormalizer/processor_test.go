@@ -0,0 +1,145 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimensionnormalizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+func newMetrics(name string, attributes ...map[string]any) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName(name)
+	dps := m.SetEmptySum().DataPoints()
+	for _, attrs := range attributes {
+		dp := dps.AppendEmpty()
+		dp.SetIntValue(1)
+		_ = dp.Attributes().FromRaw(attrs)
+	}
+	return md
+}
+
+func attributes(md pmetric.Metrics) []map[string]any {
+	var got []map[string]any
+	dps := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+	for i := 0; i < dps.Len(); i++ {
+		got = append(got, dps.At(i).Attributes().AsRaw())
+	}
+	return got
+}
+
+func TestProcessMetrics(t *testing.T) {
+	testCases := map[string]struct {
+		cfg   Config
+		input map[string]any
+		want  map[string]any
+	}{
+		"WithLowercaseKeys": {
+			cfg:   Config{LowercaseKeys: true},
+			input: map[string]any{"Host": "a", "Region": "us-east-1", "aws:StorageResolution": "true"},
+			want:  map[string]any{"host": "a", "region": "us-east-1", "aws:StorageResolution": "true"},
+		},
+		"WithSynonyms": {
+			cfg:   Config{Synonyms: map[string]string{"hostname": "host"}},
+			input: map[string]any{"hostname": "a", "Hostname": "b"},
+			want:  map[string]any{"host": "a", "Hostname": "b"},
+		},
+		"WithLowercaseSynonyms": {
+			cfg:   Config{LowercaseKeys: true, Synonyms: map[string]string{"Instance": "InstanceId"}},
+			input: map[string]any{"INSTANCE": "i-123", "Path": "/"},
+			want:  map[string]any{"InstanceId": "i-123", "path": "/"},
+		},
+		"WithConflict": {
+			cfg:   Config{LowercaseKeys: true, Synonyms: map[string]string{"hostname": "host"}},
+			input: map[string]any{"host": "a", "Host": "b", "hostname": "c"},
+			want:  map[string]any{"host": "a"},
+		},
+		"WithDisabled": {
+			input: map[string]any{"Host": "a"},
+			want:  map[string]any{"Host": "a"},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			p := newNormalizer(&testCase.cfg, zap.NewNop())
+			md, err := p.processMetrics(context.Background(), newMetrics("requests", testCase.input))
+			require.NoError(t, err)
+			assert.Equal(t, []map[string]any{testCase.want}, attributes(md))
+		})
+	}
+}
+
+func TestProcessMetricsTypes(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	metrics.AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().Attributes().PutStr("Host", "a")
+	metrics.AppendEmpty().SetEmptyHistogram().DataPoints().AppendEmpty().Attributes().PutStr("Host", "a")
+	metrics.AppendEmpty().SetEmptyExponentialHistogram().DataPoints().AppendEmpty().Attributes().PutStr("Host", "a")
+	metrics.AppendEmpty().SetEmptySummary().DataPoints().AppendEmpty().Attributes().PutStr("Host", "a")
+
+	p := newNormalizer(&Config{LowercaseKeys: true}, zap.NewNop())
+	_, err := p.processMetrics(context.Background(), md)
+	require.NoError(t, err)
+	for i := 0; i < metrics.Len(); i++ {
+		for _, attrs := range dataPointAttributes(metrics.At(i)) {
+			assert.Equal(t, map[string]any{"host": "a"}, attrs.AsRaw())
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	now := time.Unix(1700000000, 0).UTC()
+	p := newNormalizer(&Config{LowercaseKeys: true, ReportFile: path}, zap.NewNop())
+	p.now = func() time.Time { return now }
+
+	_, err := p.processMetrics(context.Background(), newMetrics("requests",
+		map[string]any{"Host": "a"},
+		map[string]any{"Host": "b"},
+		map[string]any{"Host": "b"},
+	))
+	require.NoError(t, err)
+	_, err = p.processMetrics(context.Background(), newMetrics("latency",
+		map[string]any{"Host": "a", "host": "a"},
+	))
+	require.NoError(t, err)
+
+	var got reportFile
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &got))
+	// only the first batch is written until the interval elapsed
+	require.Len(t, got.Renames, 1)
+
+	require.NoError(t, p.shutdown(context.Background()))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &got))
+	assert.Equal(t, now, got.UpdatedAt)
+	assert.Equal(t, []*reportEntry{
+		{Metric: "latency", From: "Host", To: "host", Series: 1, DataPoints: 1, Conflicts: 1},
+		{Metric: "requests", From: "Host", To: "host", Series: 2, DataPoints: 3},
+	}, got.Renames)
+}
+
+func TestReportLimit(t *testing.T) {
+	r := newReport()
+	for i := 0; i < maxReportEntries; i++ {
+		assert.True(t, r.add(fmt.Sprintf("metric%d", i), rename{from: "Host", to: "host"}, 0, false))
+	}
+	assert.False(t, r.add("omitted", rename{from: "Host", to: "host"}, 0, false))
+	assert.Len(t, r.entries, maxReportEntries)
+	assert.EqualValues(t, 1, r.omitted)
+}
@@ -0,0 +1,105 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimensionnormalizer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/atomicfile"
+)
+
+// maxReportEntries bounds the memory used by the report. The renames past
+// the limit are still applied, but only counted in the total.
+const maxReportEntries = 1000
+
+type reportKey struct {
+	metric string
+	rename
+}
+
+type reportEntry struct {
+	Metric string `json:"metric"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	// Series is the number of distinct series that were renamed.
+	Series int `json:"series"`
+	// DataPoints is the number of renamed data points.
+	DataPoints int64 `json:"data_points"`
+	// Conflicts is the number of data points that already had the canonical
+	// key, so the value of the renamed key was dropped.
+	Conflicts int64 `json:"conflicts"`
+
+	series map[uint64]struct{}
+}
+
+// report lists the series affected by the normalization, so that the
+// alarms and dashboards on the previous dimension keys can be migrated.
+type report struct {
+	entries map[reportKey]*reportEntry
+	// omitted is the number of renamed data points that are not in the
+	// entries because the limit was reached.
+	omitted int64
+	dirty   bool
+}
+
+type reportFile struct {
+	UpdatedAt time.Time      `json:"updated_at"`
+	Renames   []*reportEntry `json:"renames"`
+	Omitted   int64          `json:"omitted_data_points,omitempty"`
+}
+
+func newReport() *report {
+	return &report{entries: map[reportKey]*reportEntry{}}
+}
+
+// add counts the renamed data point and returns true if the rename is new
+// for the metric.
+func (r *report) add(metric string, rn rename, series uint64, conflict bool) bool {
+	key := reportKey{metric: metric, rename: rn}
+	entry, ok := r.entries[key]
+	if !ok {
+		if len(r.entries) >= maxReportEntries {
+			r.omitted++
+			r.dirty = true
+			return false
+		}
+		entry = &reportEntry{Metric: metric, From: rn.from, To: rn.to, series: map[uint64]struct{}{}}
+		r.entries[key] = entry
+	}
+	entry.DataPoints++
+	if conflict {
+		entry.Conflicts++
+	}
+	entry.series[series] = struct{}{}
+	entry.Series = len(entry.series)
+	r.dirty = true
+	return !ok
+}
+
+// save replaces the report file so that it is never left partially written.
+func (r *report) save(path string, now time.Time) error {
+	file := reportFile{UpdatedAt: now, Omitted: r.omitted, Renames: make([]*reportEntry, 0, len(r.entries))}
+	for _, entry := range r.entries {
+		file.Renames = append(file.Renames, entry)
+	}
+	sort.Slice(file.Renames, func(i, j int) bool {
+		a, b := file.Renames[i], file.Renames[j]
+		if a.Metric != b.Metric {
+			return a.Metric < b.Metric
+		}
+		return a.From < b.From
+	})
+	content, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, content, 0600)
+}
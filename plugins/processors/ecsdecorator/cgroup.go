@@ -22,10 +22,12 @@ const (
 
 type cgroupScanner struct {
 	mountPoint string
+	// unified is set when the host only mounts the cgroup v2 unified hierarchy, e.g. on Amazon Linux 2023.
+	unified bool
 }
 
 func newCGroupScanner(mountConfigPath string) (c *cgroupScanner) {
-	mp, err := getCGroupMountPoint(mountConfigPath)
+	mp, unified, err := getCGroupMountPoint(mountConfigPath)
 	if err != nil {
 		log.Printf("D! failed to get the cgroup mount point, error: %v, fallback to /cgroup", err)
 		mp = "/cgroup"
@@ -33,6 +35,7 @@ func newCGroupScanner(mountConfigPath string) (c *cgroupScanner) {
 
 	c = &cgroupScanner{
 		mountPoint: mp,
+		unified:    unified,
 	}
 	return c
 }
@@ -40,7 +43,10 @@ func newCGroupScannerForContainer() *cgroupScanner {
 	return newCGroupScanner(ecsInstanceMountConfigPath)
 }
 
-func (c *cgroupScanner) getCPUReserved(taskID string, clusterName string) int64 {
+func (c *cgroupScanner) getCPUReserved(taskID string, clusterName string, containers []ECSContainer) int64 {
+	if c.unified {
+		return c.getCPUReservedV2(taskID, containers)
+	}
 	cpuPath, err := getCGroupPathForTask(c.mountPoint, "cpu", taskID, clusterName)
 	if err != nil {
 		log.Printf("E! failed to get cpu cgroup path for task: %v", err)
@@ -62,6 +68,9 @@ func (c *cgroupScanner) getCPUReserved(taskID string, clusterName string) int64
 }
 
 func (c *cgroupScanner) getMEMReserved(taskID string, clusterName string, containers []ECSContainer) int64 {
+	if c.unified {
+		return c.getMEMReservedV2(taskID, containers)
+	}
 	memPath, err := getCGroupPathForTask(c.mountPoint, "memory", taskID, clusterName)
 	if err != nil {
 		log.Printf("E! failed to get memory cgroup path for task: %v", err)
@@ -123,16 +132,20 @@ func readInt64(dirpath string, file string) (int64, error) {
 
 	return val, nil
 }
-func getCGroupMountPoint(mountConfigPath string) (string, error) {
+
+// getCGroupMountPoint returns the root of the cgroup hierarchy and whether it is the cgroup v2 unified hierarchy.
+// The v1 controllers are used on hybrid hosts that mount both.
+func getCGroupMountPoint(mountConfigPath string) (string, bool, error) {
 	f, err := os.Open(mountConfigPath)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 	defer f.Close()
+	unifiedMountPoint := ""
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		if err := scanner.Err(); err != nil {
-			return "", err
+			return "", false, err
 		}
 		var (
 			text   = scanner.Text()
@@ -145,17 +158,24 @@ func getCGroupMountPoint(mountConfigPath string) (string, error) {
 		)
 		// this is an error as we can't detect if the mount is for "cgroup"
 		if numPostFields == 0 {
-			return "", fmt.Errorf("Found no fields post '-' in %q", text)
+			return "", false, fmt.Errorf("Found no fields post '-' in %q", text)
 		}
 		if postSeparatorFields[0] == "cgroup" {
 			// check that the mount is properly formated.
 			if numPostFields < 3 {
-				return "", fmt.Errorf("Error found less than 3 fields post '-' in %q", text)
+				return "", false, fmt.Errorf("Error found less than 3 fields post '-' in %q", text)
 			}
-			return filepath.Dir(fields[4]), nil
+			return filepath.Dir(fields[4]), false, nil
+		}
+		// an example: 25 22 0:22 / /sys/fs/cgroup rw,nosuid,nodev,noexec,relatime - cgroup2 cgroup2 rw,nsdelegate
+		if postSeparatorFields[0] == "cgroup2" && unifiedMountPoint == "" {
+			unifiedMountPoint = fields[4]
 		}
 	}
-	return "", fmt.Errorf("mount point not existed")
+	if unifiedMountPoint != "" {
+		return unifiedMountPoint, true, nil
+	}
+	return "", false, fmt.Errorf("mount point not existed")
 }
 
 func getCGroupPathForTask(cgroupMount, controller, taskID, clusterName string) (string, error) {
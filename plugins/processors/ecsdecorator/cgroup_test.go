@@ -11,30 +11,31 @@ import (
 )
 
 func TestGetCGroupMountPoint(t *testing.T) {
-	result, _ := getCGroupMountPoint("test/mountinfo")
+	result, unified, _ := getCGroupMountPoint("test/mountinfo")
 	assert.Equal(t, "test", result, "Expected to be equal")
+	assert.False(t, unified)
 }
 
 func TestGetCPUReservedFromShares(t *testing.T) {
 	cgroup := newCGroupScanner("test/mountinfo")
 
-	assert.Equal(t, int64(128), cgroup.getCPUReserved("test1", ""))
-	assert.Equal(t, int64(128), cgroup.getCPUReserved("test4", "myCluster"))
+	assert.Equal(t, int64(128), cgroup.getCPUReserved("test1", "", nil))
+	assert.Equal(t, int64(128), cgroup.getCPUReserved("test4", "myCluster", nil))
 }
 
 func TestGetCPUReservedFromQuota(t *testing.T) {
 	cgroup := newCGroupScanner("test/mountinfo")
-	assert.Equal(t, int64(256), cgroup.getCPUReserved("test2", ""))
+	assert.Equal(t, int64(256), cgroup.getCPUReserved("test2", "", nil))
 }
 
 func TestGetCPUReservedFromBoth(t *testing.T) {
 	cgroup := newCGroupScanner("test/mountinfo")
-	assert.Equal(t, int64(256), cgroup.getCPUReserved("test3", ""))
+	assert.Equal(t, int64(256), cgroup.getCPUReserved("test3", "", nil))
 }
 
 func TestGetCPUReservedFromFalseTaskID(t *testing.T) {
 	cgroup := newCGroupScanner("test/mountinfo")
-	assert.Equal(t, int64(0), cgroup.getCPUReserved("fake", ""))
+	assert.Equal(t, int64(0), cgroup.getCPUReserved("fake", "", nil))
 }
 
 func TestGetMEMReservedFromTask(t *testing.T) {
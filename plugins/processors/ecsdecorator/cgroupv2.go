@@ -0,0 +1,162 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecsdecorator
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// the ECS agent creates a systemd slice per task under cgroup v2, e.g. ecstasks.slice/ecstasks-<task id>.slice
+	ecsTaskSliceV2Parent = "ecstasks.slice"
+	ecsTaskSliceV2Prefix = "ecstasks-"
+	minCPUShares         = 2
+	maxCPUShares         = 262144
+	maxCPUWeight         = 10000
+)
+
+// getCPUReservedV2 returns the CPU reserved by the task in cgroup v1 cpu units. The task's cpu.max is set when the task
+// has a CPU limit, otherwise the reservation is the sum of the containers' ones.
+func (c *cgroupScanner) getCPUReservedV2(taskID string, containers []ECSContainer) int64 {
+	taskPath, err := getCGroupV2PathForTask(c.mountPoint, taskID)
+	if err != nil {
+		log.Printf("E! failed to get cgroup v2 path for task: %v", err)
+		return int64(0)
+	}
+
+	if reserved, ok := readCPUMax(taskPath); ok {
+		return reserved
+	}
+
+	sum := int64(0)
+	for _, container := range containers {
+		containerPath, err := getCGroupV2PathForContainer(taskPath, container.DockerId)
+		if err != nil {
+			continue
+		}
+		if reserved, ok := readCPUMax(containerPath); ok {
+			sum += reserved
+			continue
+		}
+		// ignore the containers with the minimum weight, it is the default of the ECS agent
+		if weight, err := readInt64(containerPath, "cpu.weight"); err == nil && weight > 1 {
+			sum += cpuWeightToShares(weight)
+		}
+	}
+	return sum
+}
+
+// getMEMReservedV2 returns the memory reserved by the task. memory.max and memory.low are the cgroup v2 hard and soft
+// limits.
+func (c *cgroupScanner) getMEMReservedV2(taskID string, containers []ECSContainer) int64 {
+	taskPath, err := getCGroupV2PathForTask(c.mountPoint, taskID)
+	if err != nil {
+		log.Printf("E! failed to get cgroup v2 path for task: %v", err)
+		return int64(0)
+	}
+
+	if memReserved, ok := readLimit(taskPath, "memory.max"); ok {
+		return memReserved
+	}
+
+	// sum the containers' memory if the task's memory limit is not configured
+	sum := int64(0)
+	for _, container := range containers {
+		containerPath, err := getCGroupV2PathForContainer(taskPath, container.DockerId)
+		if err != nil {
+			continue
+		}
+
+		//soft limit first
+		if softLimit, ok := readLimit(containerPath, "memory.low"); ok {
+			sum += softLimit
+			continue
+		}
+
+		// try hard limit when soft limit is not configured
+		if hardLimit, ok := readLimit(containerPath, "memory.max"); ok {
+			sum += hardLimit
+		}
+	}
+	return sum
+}
+
+// readCPUMax returns the CPU limit in cpu.max, e.g. "50000 100000", in cgroup v1 cpu units.
+func readCPUMax(dirpath string) (int64, bool) {
+	out, err := readString(dirpath, "cpu.max")
+	if err != nil {
+		return 0, false
+	}
+	fields := strings.Fields(out)
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0, false
+	}
+	quota, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		log.Printf("W! readCPUMax: Failed to parse quota %q from file %q: %s", fields[0], path.Join(dirpath, "cpu.max"), err)
+		return 0, false
+	}
+	period, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || period <= 0 {
+		log.Printf("W! readCPUMax: Failed to parse period %q from file %q", fields[1], path.Join(dirpath, "cpu.max"))
+		return 0, false
+	}
+	return int64(math.Ceil(float64(1024*quota) / float64(period))), true
+}
+
+// readLimit returns the limit in the file, which is unset if it is max or zero.
+func readLimit(dirpath string, file string) (int64, bool) {
+	out, err := readString(dirpath, file)
+	if err != nil || out == "" || out == "max" {
+		return 0, false
+	}
+	val, err := strconv.ParseInt(out, 10, 64)
+	if err != nil {
+		log.Printf("W! readLimit: Failed to parse int %q from file %q: %s", out, path.Join(dirpath, file), err)
+		return 0, false
+	}
+	return val, val > 0
+}
+
+// cpuWeightToShares reverses the conversion of the cpu shares set by docker to the cgroup v2 weight done by runc. The
+// weight has a lower resolution, so the shares are approximate.
+func cpuWeightToShares(weight int64) int64 {
+	if weight > maxCPUWeight {
+		weight = maxCPUWeight
+	}
+	return minCPUShares + int64(math.Round(float64((weight-1)*(maxCPUShares-minCPUShares))/float64(maxCPUWeight-1)))
+}
+
+func getCGroupV2PathForTask(cgroupMount, taskID string) (string, error) {
+	slice := ecsTaskSliceV2Prefix + taskID + ".slice"
+	for _, taskPath := range []string{
+		path.Join(cgroupMount, ecsTaskSliceV2Parent, slice),
+		path.Join(cgroupMount, slice),
+	} {
+		if _, err := os.Stat(taskPath); err == nil {
+			return taskPath, nil
+		}
+	}
+	return "", fmt.Errorf("CGroup v2 Path for task %q does not exist", taskID)
+}
+
+// getCGroupV2PathForContainer returns the scope of the container with the systemd cgroup driver, or its cgroup with
+// the cgroupfs driver.
+func getCGroupV2PathForContainer(taskPath, dockerID string) (string, error) {
+	for _, containerPath := range []string{
+		path.Join(taskPath, "docker-"+dockerID+".scope"),
+		path.Join(taskPath, dockerID),
+	} {
+		if _, err := os.Stat(containerPath); err == nil {
+			return containerPath, nil
+		}
+	}
+	return "", fmt.Errorf("CGroup v2 Path for container %q does not exist", dockerID)
+}
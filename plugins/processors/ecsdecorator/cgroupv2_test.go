@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecsdecorator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCGroupMountPointUnified(t *testing.T) {
+	result, unified, err := getCGroupMountPoint("test/cgroupv2/mountinfo")
+	require.NoError(t, err)
+	assert.Equal(t, "test/cgroupv2", result)
+	assert.True(t, unified)
+
+	// the v1 controllers are used on hybrid hosts
+	hybrid := filepath.Join(t.TempDir(), "mountinfo")
+	require.NoError(t, os.WriteFile(hybrid, []byte(
+		"25 18 0:22 / /sys/fs/cgroup/unified rw,relatime - cgroup2 cgroup2 rw,nsdelegate\n"+
+			"26 22 0:23 / /sys/fs/cgroup/cpu rw,relatime - cgroup cgroup rw,cpu\n"), 0600))
+	result, unified, err = getCGroupMountPoint(hybrid)
+	require.NoError(t, err)
+	assert.Equal(t, "/sys/fs/cgroup", result)
+	assert.False(t, unified)
+}
+
+func TestGetCPUReservedV2(t *testing.T) {
+	cgroup := newCGroupScanner("test/cgroupv2/mountinfo")
+	require.True(t, cgroup.unified)
+	containers := []ECSContainer{{DockerId: "container1"}, {DockerId: "container2"}, {DockerId: "container3"}, {DockerId: "missing"}}
+
+	// the task cpu limit
+	assert.Equal(t, int64(512), cgroup.getCPUReserved("task1", "", containers))
+	// the sum of the container1 weight and the container2 limit
+	assert.Equal(t, int64(107+256), cgroup.getCPUReserved("task2", "", containers))
+	assert.Equal(t, int64(0), cgroup.getCPUReserved("fake", "", containers))
+}
+
+func TestGetMEMReservedV2(t *testing.T) {
+	cgroup := newCGroupScanner("test/cgroupv2/mountinfo")
+	containers := []ECSContainer{{DockerId: "container1"}, {DockerId: "container2"}, {DockerId: "container3"}, {DockerId: "missing"}}
+
+	// the task memory limit
+	assert.Equal(t, int64(268435456), cgroup.getMEMReserved("task1", "", containers))
+	// the sum of the container1 soft limit and the container2 hard limit
+	assert.Equal(t, int64(134217728+67108864), cgroup.getMEMReserved("task2", "", containers))
+	assert.Equal(t, int64(0), cgroup.getMEMReserved("fake", "", containers))
+}
+
+func TestCPUWeightToShares(t *testing.T) {
+	assert.Equal(t, int64(2), cpuWeightToShares(1))
+	// docker sets 1024 shares as the weight 39
+	assert.Equal(t, int64(998), cpuWeightToShares(39))
+	assert.Equal(t, int64(262144), cpuWeightToShares(10000))
+	assert.Equal(t, int64(262144), cpuWeightToShares(20000))
+}
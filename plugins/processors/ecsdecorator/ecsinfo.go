@@ -68,7 +68,7 @@ func (e *ecsInfo) updateRunningTaskCount() {
 		}
 
 		// ignore the one only consume 2 shares which is the default value in cgroup
-		if cr := e.cgroup.getCPUReserved(taskId, e.clusterName); cr > 2 {
+		if cr := e.cgroup.getCPUReserved(taskId, e.clusterName, task.Containers); cr > 2 {
 			cpuReserved += cr
 		}
 		memReserved += e.cgroup.getMEMReserved(taskId, e.clusterName, task.Containers)
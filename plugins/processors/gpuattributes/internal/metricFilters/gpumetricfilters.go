@@ -21,21 +21,23 @@ const (
 )
 
 var ContainerGpuLabelFilter = map[string]map[string]interface{}{
-	containerinsightscommon.ClusterNameKey:   nil,
-	containerinsightscommon.InstanceIdKey:    nil,
-	containerinsightscommon.GpuDeviceKey:     nil,
-	containerinsightscommon.MetricType:       nil,
-	containerinsightscommon.NodeNameKey:      nil,
-	containerinsightscommon.K8sNamespace:     nil,
-	containerinsightscommon.FullPodNameKey:   nil,
-	containerinsightscommon.PodNameKey:       nil,
-	containerinsightscommon.TypeService:      nil,
-	containerinsightscommon.GpuUniqueId:      nil,
-	containerinsightscommon.ContainerNamekey: nil,
-	containerinsightscommon.InstanceTypeKey:  nil,
-	containerinsightscommon.VersionKey:       nil,
-	containerinsightscommon.SourcesKey:       nil,
-	containerinsightscommon.Timestamp:        nil,
+	containerinsightscommon.ClusterNameKey:          nil,
+	containerinsightscommon.InstanceIdKey:           nil,
+	containerinsightscommon.GpuDeviceKey:            nil,
+	containerinsightscommon.GpuInstanceIdKey:        nil,
+	containerinsightscommon.GpuComputeInstanceIdKey: nil,
+	containerinsightscommon.MetricType:              nil,
+	containerinsightscommon.NodeNameKey:             nil,
+	containerinsightscommon.K8sNamespace:            nil,
+	containerinsightscommon.FullPodNameKey:          nil,
+	containerinsightscommon.PodNameKey:              nil,
+	containerinsightscommon.TypeService:             nil,
+	containerinsightscommon.GpuUniqueId:             nil,
+	containerinsightscommon.ContainerNamekey:        nil,
+	containerinsightscommon.InstanceTypeKey:         nil,
+	containerinsightscommon.VersionKey:              nil,
+	containerinsightscommon.SourcesKey:              nil,
+	containerinsightscommon.Timestamp:               nil,
 	containerinsightscommon.K8sKey: {
 		containerinsightscommon.HostKey:      nil,
 		containerinsightscommon.K8sLabelsKey: nil,
@@ -48,20 +50,22 @@ var ContainerGpuLabelFilter = map[string]map[string]interface{}{
 	},
 }
 var PodGpuLabelFilter = map[string]map[string]interface{}{
-	containerinsightscommon.ClusterNameKey:  nil,
-	containerinsightscommon.InstanceIdKey:   nil,
-	containerinsightscommon.GpuDeviceKey:    nil,
-	containerinsightscommon.MetricType:      nil,
-	containerinsightscommon.NodeNameKey:     nil,
-	containerinsightscommon.K8sNamespace:    nil,
-	containerinsightscommon.FullPodNameKey:  nil,
-	containerinsightscommon.PodNameKey:      nil,
-	containerinsightscommon.TypeService:     nil,
-	containerinsightscommon.GpuUniqueId:     nil,
-	containerinsightscommon.InstanceTypeKey: nil,
-	containerinsightscommon.VersionKey:      nil,
-	containerinsightscommon.SourcesKey:      nil,
-	containerinsightscommon.Timestamp:       nil,
+	containerinsightscommon.ClusterNameKey:          nil,
+	containerinsightscommon.InstanceIdKey:           nil,
+	containerinsightscommon.GpuDeviceKey:            nil,
+	containerinsightscommon.GpuInstanceIdKey:        nil,
+	containerinsightscommon.GpuComputeInstanceIdKey: nil,
+	containerinsightscommon.MetricType:              nil,
+	containerinsightscommon.NodeNameKey:             nil,
+	containerinsightscommon.K8sNamespace:            nil,
+	containerinsightscommon.FullPodNameKey:          nil,
+	containerinsightscommon.PodNameKey:              nil,
+	containerinsightscommon.TypeService:             nil,
+	containerinsightscommon.GpuUniqueId:             nil,
+	containerinsightscommon.InstanceTypeKey:         nil,
+	containerinsightscommon.VersionKey:              nil,
+	containerinsightscommon.SourcesKey:              nil,
+	containerinsightscommon.Timestamp:               nil,
 	containerinsightscommon.K8sKey: {
 		containerinsightscommon.HostKey:      nil,
 		containerinsightscommon.K8sLabelsKey: nil,
@@ -72,15 +76,17 @@ var PodGpuLabelFilter = map[string]map[string]interface{}{
 	},
 }
 var NodeGpuLabelFilter = map[string]map[string]interface{}{
-	containerinsightscommon.ClusterNameKey:  nil,
-	containerinsightscommon.InstanceIdKey:   nil,
-	containerinsightscommon.GpuDeviceKey:    nil,
-	containerinsightscommon.MetricType:      nil,
-	containerinsightscommon.NodeNameKey:     nil,
-	containerinsightscommon.InstanceTypeKey: nil,
-	containerinsightscommon.VersionKey:      nil,
-	containerinsightscommon.SourcesKey:      nil,
-	containerinsightscommon.Timestamp:       nil,
+	containerinsightscommon.ClusterNameKey:          nil,
+	containerinsightscommon.InstanceIdKey:           nil,
+	containerinsightscommon.GpuDeviceKey:            nil,
+	containerinsightscommon.GpuInstanceIdKey:        nil,
+	containerinsightscommon.GpuComputeInstanceIdKey: nil,
+	containerinsightscommon.MetricType:              nil,
+	containerinsightscommon.NodeNameKey:             nil,
+	containerinsightscommon.InstanceTypeKey:         nil,
+	containerinsightscommon.VersionKey:              nil,
+	containerinsightscommon.SourcesKey:              nil,
+	containerinsightscommon.Timestamp:               nil,
 	containerinsightscommon.K8sKey: {
 		containerinsightscommon.HostKey: nil,
 	},
@@ -31,6 +31,7 @@ const (
 //   - ClusterName, Namespace, PodName, ContainerName
 //   - ClusterName, Namespace, PodName, FullPodName, ContainerName
 //   - ClusterName, Namespace, PodName, FullPodName, ContainerName, GpuDevice
+//   - ClusterName, Namespace, PodName, FullPodName, ContainerName, GpuDevice, GpuInstanceId, ComputeInstanceId
 //
 // - Pod
 //   - ClusterName
@@ -39,11 +40,15 @@ const (
 //   - ClusterName, Namespace, PodName
 //   - ClusterName, Namespace, PodName, FullPodName
 //   - ClusterName, Namespace, PodName, FullPodName, GpuDevice
+//   - ClusterName, Namespace, PodName, FullPodName, GpuDevice, GpuInstanceId, ComputeInstanceId
 //
 // - Node
 //   - ClusterName
 //   - ClusterName, InstanceIdKey, NodeName
 //   - ClusterName, InstanceIdKey, NodeName, GpuDevice
+//   - ClusterName, InstanceIdKey, NodeName, GpuDevice, GpuInstanceId, ComputeInstanceId
+//
+// The GpuInstanceId and ComputeInstanceId are only set on the MIG (Multi-Instance GPU) partitions.
 type gpuAttributesProcessor struct {
 	*Config
 	logger                          *zap.Logger
@@ -82,6 +82,26 @@ func TestProcessMetricsForGPUMetrics(t *testing.T) {
 				},
 			},
 		},
+		"nodeKeepMigPartition": {
+			metrics: generateGPUMetrics("node", []map[string]string{
+				{
+					"ClusterName":       "cluster",
+					"GpuDevice":         "nvidia0",
+					"GpuInstanceId":     "1",
+					"ComputeInstanceId": "0",
+					"GPU_I_PROFILE":     "1g.10gb",
+				},
+			}),
+			wantMetricCnt: 1,
+			want: []map[string]string{
+				{
+					"ClusterName":       "cluster",
+					"GpuDevice":         "nvidia0",
+					"GpuInstanceId":     "1",
+					"ComputeInstanceId": "0",
+				},
+			},
+		},
 		"dropPodWithoutPodName": {
 			metrics: generateGPUMetrics("pod", []map[string]string{
 				{
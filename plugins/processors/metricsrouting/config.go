@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metricsrouting
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gobwas/glob"
+	"go.opentelemetry.io/collector/component"
+)
+
+// DefaultRoute is the route of the data points not matching any of the
+// routes.
+const DefaultRoute = -1
+
+// Route matches the data points published to another namespace.
+type Route struct {
+	// MetricNames are the globs matching the names of the metrics, e.g.
+	// "mem_*". All the names match if it is empty.
+	MetricNames []string `mapstructure:"metric_names"`
+	// Dimensions are the values the dimensions of the data points must have.
+	// The dimensions are looked up in the attributes of the data points, then
+	// in the attributes of their resource.
+	Dimensions map[string]string `mapstructure:"dimensions"`
+}
+
+type Config struct {
+	// Routes are matched in order, the data points belong to the first route
+	// they match.
+	Routes []Route `mapstructure:"routes"`
+	// Route is the index of the route whose data points are kept, the others
+	// are dropped. The data points not matching any route are kept if it is
+	// DefaultRoute.
+	Route int `mapstructure:"route"`
+}
+
+// Verify Config implements Processor interface.
+var _ component.Config = (*Config)(nil)
+
+func (cfg *Config) Validate() error {
+	if cfg.Route < DefaultRoute || cfg.Route >= len(cfg.Routes) {
+		return fmt.Errorf("route %d is not one of the %d routes", cfg.Route, len(cfg.Routes))
+	}
+	for i, route := range cfg.Routes {
+		if len(route.MetricNames) == 0 && len(route.Dimensions) == 0 {
+			return fmt.Errorf("routes[%d]: %w", i, errors.New("metric_names or dimensions must be set"))
+		}
+		for _, name := range route.MetricNames {
+			if _, err := glob.Compile(name); err != nil {
+				return fmt.Errorf("routes[%d]: invalid metric name %q: %w", i, name, err)
+			}
+		}
+	}
+	return nil
+}
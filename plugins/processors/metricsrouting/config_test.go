@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metricsrouting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func TestUnmarshalDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NoError(t, confmap.New().Unmarshal(cfg))
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg)
+	assert.NoError(t, cfg.(*Config).Validate())
+}
+
+func TestValidate(t *testing.T) {
+	routes := []Route{
+		{MetricNames: []string{"mem_*"}},
+		{Dimensions: map[string]string{"team": "a"}},
+	}
+	testCases := map[string]struct {
+		cfg     Config
+		wantErr string
+	}{
+		"WithDefaultRoute": {
+			cfg: Config{Routes: routes, Route: DefaultRoute},
+		},
+		"WithRoute": {
+			cfg: Config{Routes: routes, Route: 1},
+		},
+		"WithUnknownRoute": {
+			cfg:     Config{Routes: routes, Route: 2},
+			wantErr: "route 2 is not one of the 2 routes",
+		},
+		"WithNegativeRoute": {
+			cfg:     Config{Routes: routes, Route: -2},
+			wantErr: "route -2 is not one of the 2 routes",
+		},
+		"WithEmptyRoute": {
+			cfg:     Config{Routes: []Route{{}}, Route: DefaultRoute},
+			wantErr: "routes[0]: metric_names or dimensions must be set",
+		},
+		"WithInvalidMetricName": {
+			cfg:     Config{Routes: []Route{{MetricNames: []string{"mem_["}}}, Route: DefaultRoute},
+			wantErr: `routes[0]: invalid metric name "mem_[": unexpected end of input`,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := testCase.cfg.Validate()
+			if testCase.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, testCase.wantErr)
+			}
+		})
+	}
+}
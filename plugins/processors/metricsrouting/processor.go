@@ -0,0 +1,123 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metricsrouting
+
+import (
+	"context"
+
+	"github.com/gobwas/glob"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+type matcher struct {
+	names      []glob.Glob
+	dimensions map[string]string
+}
+
+type router struct {
+	matchers []matcher
+	route    int
+}
+
+func newRouter(config *Config) (*router, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	r := &router{route: config.Route}
+	for _, route := range config.Routes {
+		m := matcher{dimensions: route.Dimensions}
+		for _, name := range route.MetricNames {
+			m.names = append(m.names, glob.MustCompile(name))
+		}
+		r.matchers = append(r.matchers, m)
+	}
+	return r, nil
+}
+
+// processMetrics drops the data points of the other routes, so that the
+// pipeline of each route only publishes the data points of its route.
+func (r *router) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	md.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		resource := rm.Resource().Attributes()
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			sm.Metrics().RemoveIf(func(metric pmetric.Metric) bool {
+				return removeDataPointsIf(metric, func(attributes pcommon.Map) bool {
+					return r.routeOf(metric.Name(), attributes, resource) != r.route
+				})
+			})
+			return sm.Metrics().Len() == 0
+		})
+		return rm.ScopeMetrics().Len() == 0
+	})
+	if md.ResourceMetrics().Len() == 0 {
+		return md, processorhelper.ErrSkipProcessingData
+	}
+	return md, nil
+}
+
+// routeOf returns the index of the first route matching the data point, or
+// DefaultRoute if none does.
+func (r *router) routeOf(name string, attributes, resource pcommon.Map) int {
+	for i, m := range r.matchers {
+		if m.matches(name, attributes, resource) {
+			return i
+		}
+	}
+	return DefaultRoute
+}
+
+func (m matcher) matches(name string, attributes, resource pcommon.Map) bool {
+	if len(m.names) > 0 && !matchesAny(m.names, name) {
+		return false
+	}
+	for key, want := range m.dimensions {
+		value, ok := attributes.Get(key)
+		if !ok {
+			value, ok = resource.Get(key)
+		}
+		if !ok || value.AsString() != want {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAny(globs []glob.Glob, name string) bool {
+	for _, g := range globs {
+		if g.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeDataPointsIf removes the data points of the metric the function
+// returns true for, and returns true if the metric has no data points left.
+func removeDataPointsIf(metric pmetric.Metric, fn func(pcommon.Map) bool) bool {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		dps.RemoveIf(func(dp pmetric.NumberDataPoint) bool { return fn(dp.Attributes()) })
+		return dps.Len() == 0
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		dps.RemoveIf(func(dp pmetric.NumberDataPoint) bool { return fn(dp.Attributes()) })
+		return dps.Len() == 0
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		dps.RemoveIf(func(dp pmetric.HistogramDataPoint) bool { return fn(dp.Attributes()) })
+		return dps.Len() == 0
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		dps.RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool { return fn(dp.Attributes()) })
+		return dps.Len() == 0
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		dps.RemoveIf(func(dp pmetric.SummaryDataPoint) bool { return fn(dp.Attributes()) })
+		return dps.Len() == 0
+	}
+	return true
+}
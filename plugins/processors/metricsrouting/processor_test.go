@@ -0,0 +1,120 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metricsrouting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+type dataPoint struct {
+	metric     string
+	attributes map[string]string
+}
+
+func newMetrics(resource map[string]string, dataPoints ...dataPoint) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	for k, v := range resource {
+		rm.Resource().Attributes().PutStr(k, v)
+	}
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+	for _, dp := range dataPoints {
+		m := metrics.AppendEmpty()
+		m.SetName(dp.metric)
+		attributes := m.SetEmptyGauge().DataPoints().AppendEmpty().Attributes()
+		for k, v := range dp.attributes {
+			attributes.PutStr(k, v)
+		}
+	}
+	return md
+}
+
+func dataPointsOf(md pmetric.Metrics) []dataPoint {
+	var got []dataPoint
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				dps := metrics.At(k).Gauge().DataPoints()
+				for l := 0; l < dps.Len(); l++ {
+					attributes := map[string]string{}
+					dps.At(l).Attributes().Range(func(k string, v pcommon.Value) bool {
+						attributes[k] = v.AsString()
+						return true
+					})
+					got = append(got, dataPoint{metric: metrics.At(k).Name(), attributes: attributes})
+				}
+			}
+		}
+	}
+	return got
+}
+
+func TestProcessMetrics(t *testing.T) {
+	routes := []Route{
+		{MetricNames: []string{"mem_*", "swap_used"}, Dimensions: map[string]string{"team": "a"}},
+		{Dimensions: map[string]string{"team": "b"}},
+	}
+	teamA := dataPoint{metric: "mem_used_percent", attributes: map[string]string{"team": "a"}}
+	teamB := dataPoint{metric: "mem_used_percent", attributes: map[string]string{"team": "b"}}
+	// the name of the first route does not match, so it falls through to the
+	// second one
+	teamACPU := dataPoint{metric: "cpu_usage_idle", attributes: map[string]string{"team": "a"}}
+	untagged := dataPoint{metric: "swap_used", attributes: map[string]string{}}
+	testCases := map[string]struct {
+		route int
+		want  []dataPoint
+	}{
+		"WithDefaultRoute": {
+			route: DefaultRoute,
+			want:  []dataPoint{teamACPU, untagged},
+		},
+		"WithFirstRoute": {
+			route: 0,
+			want:  []dataPoint{teamA},
+		},
+		"WithSecondRoute": {
+			route: 1,
+			want:  []dataPoint{teamB},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r, err := newRouter(&Config{Routes: routes, Route: testCase.route})
+			require.NoError(t, err)
+			md, err := r.processMetrics(context.Background(), newMetrics(nil, teamA, teamB, teamACPU, untagged))
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, dataPointsOf(md))
+		})
+	}
+}
+
+func TestProcessMetricsWithResourceDimensions(t *testing.T) {
+	r, err := newRouter(&Config{Routes: []Route{{Dimensions: map[string]string{"team": "a"}}}, Route: 0})
+	require.NoError(t, err)
+	// the attributes of the data points take precedence over the resource
+	md, err := r.processMetrics(context.Background(), newMetrics(map[string]string{"team": "a"},
+		dataPoint{metric: "cpu_usage_idle", attributes: map[string]string{}},
+		dataPoint{metric: "cpu_usage_user", attributes: map[string]string{"team": "b"}},
+	))
+	require.NoError(t, err)
+	assert.Equal(t, []dataPoint{{metric: "cpu_usage_idle", attributes: map[string]string{}}}, dataPointsOf(md))
+}
+
+func TestProcessMetricsWithoutMatches(t *testing.T) {
+	r, err := newRouter(&Config{Routes: []Route{{MetricNames: []string{"mem_*"}}}, Route: 0})
+	require.NoError(t, err)
+	md, err := r.processMetrics(context.Background(), newMetrics(nil, dataPoint{metric: "cpu_usage_idle"}))
+	assert.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+	assert.Equal(t, 0, md.ResourceMetrics().Len())
+}
@@ -0,0 +1,47 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstatheartbeat
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+type ProcessConfig struct {
+	// ID is the value of the IDAttributeKey attribute on the metrics of the process.
+	ID string `mapstructure:"id"`
+	// FlapSuppressionCount is the number of consecutive lookups that need to
+	// agree before the reported running state changes.
+	FlapSuppressionCount int `mapstructure:"flap_suppression_count,omitempty"`
+	// DropPID drops the pid metrics that were only collected to track restarts.
+	DropPID bool `mapstructure:"drop_pid,omitempty"`
+}
+
+type Config struct {
+	Processes []ProcessConfig `mapstructure:"processes,omitempty"`
+	// StateFile is where the restart counts are kept across agent restarts.
+	StateFile string `mapstructure:"state_file,omitempty"`
+}
+
+// Verify Config implements Processor interface.
+var _ component.Config = (*Config)(nil)
+
+func (cfg *Config) Validate() error {
+	ids := make(map[string]struct{}, len(cfg.Processes))
+	for _, process := range cfg.Processes {
+		if process.ID == "" {
+			return errors.New("process id must not be empty")
+		}
+		if _, ok := ids[process.ID]; ok {
+			return fmt.Errorf("duplicate process id %q", process.ID)
+		}
+		ids[process.ID] = struct{}{}
+		if process.FlapSuppressionCount < 0 {
+			return fmt.Errorf("flap_suppression_count of process %q must not be negative", process.ID)
+		}
+	}
+	return nil
+}
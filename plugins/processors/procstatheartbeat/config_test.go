@@ -0,0 +1,51 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstatheartbeat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func TestUnmarshalDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NoError(t, confmap.New().Unmarshal(cfg))
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]struct {
+		processes []ProcessConfig
+		wantErr   bool
+	}{
+		"WithValid": {
+			processes: []ProcessConfig{{ID: "a", FlapSuppressionCount: 3}, {ID: "b", DropPID: true}},
+		},
+		"WithEmptyID": {
+			processes: []ProcessConfig{{FlapSuppressionCount: 3}},
+			wantErr:   true,
+		},
+		"WithDuplicateID": {
+			processes: []ProcessConfig{{ID: "a"}, {ID: "a"}},
+			wantErr:   true,
+		},
+		"WithNegativeFlapSuppressionCount": {
+			processes: []ProcessConfig{{ID: "a", FlapSuppressionCount: -1}},
+			wantErr:   true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Config{Processes: testCase.processes}
+			if testCase.wantErr {
+				assert.Error(t, cfg.Validate())
+			} else {
+				assert.NoError(t, cfg.Validate())
+			}
+		})
+	}
+}
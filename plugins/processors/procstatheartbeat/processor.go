@@ -0,0 +1,214 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstatheartbeat
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+const (
+	// IDAttributeKey is the attribute added by the procstat input to identify
+	// the metrics of the watched processes. It is removed from all metrics.
+	IDAttributeKey = "procstat_heartbeat_id"
+
+	lookupRunningMetric = "procstat_lookup_running"
+	restartCountMetric  = "procstat_lookup_restart_count"
+	pidMetric           = "procstat_pid"
+)
+
+type heartbeatProcessor struct {
+	*Config
+	logger    *zap.Logger
+	processes map[string]ProcessConfig
+
+	mu     sync.Mutex
+	states map[string]*processState
+}
+
+func newHeartbeatProcessor(config *Config, logger *zap.Logger) *heartbeatProcessor {
+	processes := make(map[string]ProcessConfig, len(config.Processes))
+	for _, process := range config.Processes {
+		if process.FlapSuppressionCount < 1 {
+			process.FlapSuppressionCount = 1
+		}
+		processes[process.ID] = process
+	}
+	return &heartbeatProcessor{
+		Config:    config,
+		logger:    logger,
+		processes: processes,
+		states:    map[string]*processState{},
+	}
+}
+
+func (p *heartbeatProcessor) start(_ context.Context, _ component.Host) error {
+	if p.StateFile == "" {
+		return nil
+	}
+	states, err := loadState(p.StateFile)
+	if err != nil {
+		// restarts while the agent was down are missed, but the watched processes are still reported
+		p.logger.Warn("Unable to load the procstat heartbeat state", zap.String("file", p.StateFile), zap.Error(err))
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, state := range states {
+		if _, ok := p.processes[id]; ok {
+			p.states[id] = state
+		}
+	}
+	return nil
+}
+
+func (p *heartbeatProcessor) shutdown(context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.save()
+	return nil
+}
+
+func (p *heartbeatProcessor) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pids := p.collectPIDs(md)
+	changed := false
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k, n := 0, metrics.Len(); k < n; k++ {
+				if m := metrics.At(k); m.Name() == lookupRunningMetric && m.Type() == pmetric.MetricTypeGauge {
+					changed = p.processRunning(m, pids, metrics) || changed
+				}
+			}
+			metrics.RemoveIf(func(m pmetric.Metric) bool {
+				removeIDAttribute(m)
+				// the pid metrics are empty if they were all dropped
+				return m.Name() == pidMetric && m.Type() == pmetric.MetricTypeGauge && m.Gauge().DataPoints().Len() == 0
+			})
+		}
+	}
+	if changed {
+		p.save()
+	}
+	return md, nil
+}
+
+// collectPIDs returns the sorted process IDs of each watched process and
+// drops the pid datapoints that were not asked for.
+func (p *heartbeatProcessor) collectPIDs(md pmetric.Metrics) map[string][]int64 {
+	pids := map[string][]int64{}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				m := metrics.At(k)
+				if m.Name() != pidMetric || m.Type() != pmetric.MetricTypeGauge {
+					continue
+				}
+				m.Gauge().DataPoints().RemoveIf(func(dp pmetric.NumberDataPoint) bool {
+					process, ok := p.lookupProcess(dp.Attributes())
+					if !ok {
+						return false
+					}
+					pids[process.ID] = append(pids[process.ID], int64(value(dp)))
+					return process.DropPID
+				})
+			}
+		}
+	}
+	for _, ids := range pids {
+		slices.Sort(ids)
+	}
+	return pids
+}
+
+// processRunning replaces the number of running processes with the 0/1
+// reported running state and adds the restart count of the watched processes.
+func (p *heartbeatProcessor) processRunning(m pmetric.Metric, pids map[string][]int64, metrics pmetric.MetricSlice) bool {
+	changed := false
+	var restarts *pmetric.NumberDataPointSlice
+	dps := m.Gauge().DataPoints()
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		process, ok := p.lookupProcess(dp.Attributes())
+		if !ok {
+			continue
+		}
+		state, ok := p.states[process.ID]
+		if !ok {
+			// nothing to suppress or restart before the first lookup
+			state = &processState{Running: value(dp) > 0}
+			p.states[process.ID] = state
+		}
+		changed = state.observe(value(dp) > 0, pids[process.ID], process.FlapSuppressionCount) || changed
+		if state.Running {
+			dp.SetIntValue(1)
+		} else {
+			dp.SetIntValue(0)
+		}
+		if restarts == nil {
+			restartMetric := metrics.AppendEmpty()
+			restartMetric.SetName(restartCountMetric)
+			dps := restartMetric.SetEmptyGauge().DataPoints()
+			restarts = &dps
+		}
+		restart := restarts.AppendEmpty()
+		dp.Attributes().CopyTo(restart.Attributes())
+		restart.SetStartTimestamp(dp.StartTimestamp())
+		restart.SetTimestamp(dp.Timestamp())
+		restart.SetIntValue(state.RestartCount)
+	}
+	return changed
+}
+
+func (p *heartbeatProcessor) lookupProcess(attrs pcommon.Map) (ProcessConfig, bool) {
+	id, ok := attrs.Get(IDAttributeKey)
+	if !ok {
+		return ProcessConfig{}, false
+	}
+	process, ok := p.processes[id.Str()]
+	return process, ok
+}
+
+func (p *heartbeatProcessor) save() {
+	if p.StateFile == "" {
+		return
+	}
+	if err := saveState(p.StateFile, p.states); err != nil {
+		p.logger.Warn("Unable to save the procstat heartbeat state", zap.String("file", p.StateFile), zap.Error(err))
+	}
+}
+
+func removeIDAttribute(m pmetric.Metric) {
+	var dps pmetric.NumberDataPointSlice
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		dps = m.Gauge().DataPoints()
+	case pmetric.MetricTypeSum:
+		dps = m.Sum().DataPoints()
+	default:
+		return
+	}
+	for i := 0; i < dps.Len(); i++ {
+		dps.At(i).Attributes().Remove(IDAttributeKey)
+	}
+}
+
+func value(dp pmetric.NumberDataPoint) float64 {
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		return float64(dp.IntValue())
+	}
+	return dp.DoubleValue()
+}
@@ -0,0 +1,123 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstatheartbeat
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+// newLookup creates the metrics of one procstat gather for the process.
+func newLookup(id string, running int64, pids ...int64) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	m := metrics.AppendEmpty()
+	m.SetName(lookupRunningMetric)
+	dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetIntValue(running)
+	dp.Attributes().PutStr("pid_finder", "native")
+	dp.Attributes().PutStr(IDAttributeKey, id)
+	if len(pids) > 0 {
+		m = metrics.AppendEmpty()
+		m.SetName(pidMetric)
+		dps := m.SetEmptyGauge().DataPoints()
+		for _, pid := range pids {
+			dp = dps.AppendEmpty()
+			dp.SetIntValue(pid)
+			dp.Attributes().PutStr("pattern", "sshd")
+			dp.Attributes().PutStr(IDAttributeKey, id)
+		}
+	}
+	return md
+}
+
+// gather returns the values of the metrics by name.
+func gather(t *testing.T, p *heartbeatProcessor, md pmetric.Metrics) map[string][]int64 {
+	t.Helper()
+	md, err := p.processMetrics(context.Background(), md)
+	require.NoError(t, err)
+	values := map[string][]int64{}
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		m := metrics.At(i)
+		dps := m.Gauge().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			_, ok := dps.At(j).Attributes().Get(IDAttributeKey)
+			assert.False(t, ok, "id attribute not removed from %s", m.Name())
+			values[m.Name()] = append(values[m.Name()], dps.At(j).IntValue())
+		}
+	}
+	return values
+}
+
+func TestProcessMetrics(t *testing.T) {
+	p := newHeartbeatProcessor(&Config{Processes: []ProcessConfig{{ID: "sshd", FlapSuppressionCount: 2, DropPID: true}}}, zap.NewNop())
+
+	values := gather(t, p, newLookup("sshd", 2, 10, 11))
+	assert.Equal(t, map[string][]int64{lookupRunningMetric: {1}, restartCountMetric: {0}}, values)
+
+	// a single failed lookup is suppressed
+	values = gather(t, p, newLookup("sshd", 0))
+	assert.Equal(t, map[string][]int64{lookupRunningMetric: {1}, restartCountMetric: {0}}, values)
+	values = gather(t, p, newLookup("sshd", 1, 12))
+	assert.Equal(t, map[string][]int64{lookupRunningMetric: {1}, restartCountMetric: {1}}, values)
+
+	values = gather(t, p, newLookup("sshd", 0))
+	assert.Equal(t, []int64{1}, values[lookupRunningMetric])
+	values = gather(t, p, newLookup("sshd", 0))
+	assert.Equal(t, []int64{0}, values[lookupRunningMetric])
+
+	// restarted between the lookups
+	values = gather(t, p, newLookup("sshd", 1, 13))
+	assert.Equal(t, map[string][]int64{lookupRunningMetric: {0}, restartCountMetric: {2}}, values)
+	values = gather(t, p, newLookup("sshd", 1, 14))
+	assert.Equal(t, map[string][]int64{lookupRunningMetric: {1}, restartCountMetric: {3}}, values)
+	values = gather(t, p, newLookup("sshd", 1, 14))
+	assert.Equal(t, map[string][]int64{lookupRunningMetric: {1}, restartCountMetric: {3}}, values)
+}
+
+func TestProcessMetricsNotWatched(t *testing.T) {
+	p := newHeartbeatProcessor(&Config{Processes: []ProcessConfig{{ID: "sshd"}}}, zap.NewNop())
+	values := gather(t, p, newLookup("nginx", 3, 10, 11, 12))
+	assert.Equal(t, map[string][]int64{lookupRunningMetric: {3}, pidMetric: {10, 11, 12}}, values)
+	values = gather(t, p, newLookup("sshd", 1, 10))
+	assert.Equal(t, map[string][]int64{lookupRunningMetric: {1}, pidMetric: {10}, restartCountMetric: {0}}, values)
+}
+
+func TestStateFile(t *testing.T) {
+	cfg := &Config{
+		Processes: []ProcessConfig{{ID: "sshd", DropPID: true}},
+		StateFile: filepath.Join(t.TempDir(), "state", "procstat_heartbeat.json"),
+	}
+	p := newHeartbeatProcessor(cfg, zap.NewNop())
+	require.NoError(t, p.start(context.Background(), componenttest.NewNopHost()))
+	gather(t, p, newLookup("sshd", 1, 10))
+	gather(t, p, newLookup("sshd", 1, 11))
+	require.NoError(t, p.shutdown(context.Background()))
+
+	// still running after the agent restart
+	p = newHeartbeatProcessor(cfg, zap.NewNop())
+	require.NoError(t, p.start(context.Background(), componenttest.NewNopHost()))
+	values := gather(t, p, newLookup("sshd", 1, 11))
+	assert.Equal(t, []int64{1}, values[restartCountMetric])
+	require.NoError(t, p.shutdown(context.Background()))
+
+	// restarted while the agent was down
+	p = newHeartbeatProcessor(cfg, zap.NewNop())
+	require.NoError(t, p.start(context.Background(), componenttest.NewNopHost()))
+	values = gather(t, p, newLookup("sshd", 1, 20))
+	assert.Equal(t, []int64{2}, values[restartCountMetric])
+
+	// the states of the processes that are no longer watched are dropped
+	p = newHeartbeatProcessor(&Config{Processes: []ProcessConfig{{ID: "nginx"}}, StateFile: cfg.StateFile}, zap.NewNop())
+	require.NoError(t, p.start(context.Background(), componenttest.NewNopHost()))
+	assert.Empty(t, p.states)
+}
@@ -0,0 +1,97 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstatheartbeat
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/atomicfile"
+)
+
+// processState is the state of a watched process. Everything but the streak
+// is kept in the state file so that the restarts that happen while the agent
+// is down are counted as well.
+type processState struct {
+	// Running is the reported running state.
+	Running bool `json:"running"`
+	// Observed is the running state of the last lookup.
+	Observed bool `json:"observed"`
+	// Started is whether the process has ever been observed running.
+	Started bool `json:"started"`
+	// PIDs are the sorted process IDs of the last lookup that found the process running.
+	PIDs         []int64 `json:"pids,omitempty"`
+	RestartCount int64   `json:"restart_count"`
+	// streak is the number of consecutive lookups that disagree with the reported state.
+	streak int
+}
+
+// observe updates the state with the result of a lookup and returns true if
+// any of the persisted fields changed.
+func (s *processState) observe(running bool, pids []int64, flapSuppressionCount int) bool {
+	before := *s
+	if running && s.Started && (!s.Observed || disjoint(s.PIDs, pids)) {
+		s.RestartCount++
+	}
+	if running {
+		s.Started = true
+		s.PIDs = pids
+	} else {
+		s.PIDs = nil
+	}
+	s.Observed = running
+	if running == s.Running {
+		s.streak = 0
+	} else if s.streak++; s.streak >= flapSuppressionCount {
+		s.Running = running
+		s.streak = 0
+	}
+	return before.Running != s.Running || before.Observed != s.Observed || before.Started != s.Started ||
+		before.RestartCount != s.RestartCount || !slices.Equal(before.PIDs, s.PIDs)
+}
+
+// disjoint is only true if neither of the process IDs are empty, since the
+// pid metrics may not be in the same batch as the lookup metric.
+func disjoint(previous, current []int64) bool {
+	if len(previous) == 0 || len(current) == 0 {
+		return false
+	}
+	for _, pid := range current {
+		if _, found := slices.BinarySearch(previous, pid); found {
+			return false
+		}
+	}
+	return true
+}
+
+func loadState(path string) (map[string]*processState, error) {
+	states := map[string]*processState{}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return states, nil
+	}
+	if err != nil {
+		return states, err
+	}
+	if err = json.Unmarshal(content, &states); err != nil {
+		return map[string]*processState{}, err
+	}
+	return states, nil
+}
+
+// saveState replaces the state file so that it is never left partially
+// written.
+func saveState(path string, states map[string]*processState) error {
+	content, err := json.Marshal(states)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, content, 0600)
+}
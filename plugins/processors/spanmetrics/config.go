@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package spanmetrics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/spanmetrics"
+)
+
+// MaxDimensions is the number of dimensions that can be added to the service,
+// span name and span kind dimensions, within the 30 dimensions of a metric.
+const MaxDimensions = 10
+
+type Config struct {
+	// Dimensions are the span attributes, or the resource attributes if the
+	// span does not have them, added to the dimensions of the metrics.
+	Dimensions []string `mapstructure:"dimensions,omitempty"`
+	// HistogramBuckets are the upper bounds in milliseconds of the duration
+	// histogram buckets.
+	HistogramBuckets []float64 `mapstructure:"histogram_buckets,omitempty"`
+}
+
+// Verify Config implements Processor interface.
+var _ component.Config = (*Config)(nil)
+
+func (cfg *Config) Validate() error {
+	if len(cfg.Dimensions) > MaxDimensions {
+		return fmt.Errorf("at most %d dimensions can be added", MaxDimensions)
+	}
+	names := map[string]struct{}{
+		spanmetrics.DimensionServiceName: {},
+		spanmetrics.DimensionSpanName:    {},
+		spanmetrics.DimensionSpanKind:    {},
+	}
+	for _, dimension := range cfg.Dimensions {
+		if dimension == "" {
+			return errors.New("dimension must not be empty")
+		}
+		if _, ok := names[dimension]; ok {
+			return fmt.Errorf("duplicate dimension %q", dimension)
+		}
+		names[dimension] = struct{}{}
+	}
+	if len(cfg.HistogramBuckets) == 0 {
+		return errors.New("histogram_buckets must not be empty")
+	}
+	if !sort.Float64sAreSorted(cfg.HistogramBuckets) {
+		return errors.New("histogram_buckets must be sorted")
+	}
+	for i, bound := range cfg.HistogramBuckets {
+		if bound <= 0 || (i > 0 && bound == cfg.HistogramBuckets[i-1]) {
+			return errors.New("histogram_buckets must be positive and distinct")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package spanmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]struct {
+		cfg     Config
+		wantErr bool
+	}{
+		"WithValid": {
+			cfg: Config{Dimensions: []string{"http.method"}, HistogramBuckets: []float64{0.5, 10}},
+		},
+		"WithTooManyDimensions": {
+			cfg:     Config{Dimensions: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}, HistogramBuckets: []float64{1}},
+			wantErr: true,
+		},
+		"WithEmptyDimension": {
+			cfg:     Config{Dimensions: []string{""}, HistogramBuckets: []float64{1}},
+			wantErr: true,
+		},
+		"WithDefaultDimension": {
+			cfg:     Config{Dimensions: []string{"service.name"}, HistogramBuckets: []float64{1}},
+			wantErr: true,
+		},
+		"WithDuplicateDimension": {
+			cfg:     Config{Dimensions: []string{"http.method", "http.method"}, HistogramBuckets: []float64{1}},
+			wantErr: true,
+		},
+		"WithoutBuckets": {
+			cfg:     Config{},
+			wantErr: true,
+		},
+		"WithUnsortedBuckets": {
+			cfg:     Config{HistogramBuckets: []float64{10, 1}},
+			wantErr: true,
+		},
+		"WithDuplicateBuckets": {
+			cfg:     Config{HistogramBuckets: []float64{1, 1}},
+			wantErr: true,
+		},
+		"WithNegativeBucket": {
+			cfg:     Config{HistogramBuckets: []float64{-1, 1}},
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := testCase.cfg.Validate()
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package spanmetrics
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/spanmetrics"
+)
+
+const (
+	stability = component.StabilityLevelAlpha
+)
+
+var (
+	TypeStr, _            = component.NewType("spanmetrics")
+	processorCapabilities = consumer.Capabilities{MutatesData: false}
+)
+
+func NewFactory() processor.Factory {
+	return processor.NewFactory(
+		TypeStr,
+		createDefaultConfig,
+		processor.WithTraces(createTracesProcessor, stability))
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{HistogramBuckets: spanmetrics.DefaultBuckets}
+}
+
+func createTracesProcessor(
+	ctx context.Context,
+	set processor.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Traces,
+) (processor.Traces, error) {
+	processorConfig, ok := cfg.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("configuration parsing error")
+	}
+
+	tracesProcessor := newSpanMetricsProcessor(processorConfig, spanmetrics.Default)
+
+	return processorhelper.NewTracesProcessor(
+		ctx,
+		set,
+		cfg,
+		nextConsumer,
+		tracesProcessor.processTraces,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(tracesProcessor.start),
+		processorhelper.WithShutdown(tracesProcessor.shutdown))
+}
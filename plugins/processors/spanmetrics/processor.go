@@ -0,0 +1,99 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package spanmetrics
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/collector/semconv/v1.22.0"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/spanmetrics"
+)
+
+const unknownService = "unknown_service"
+
+// spanMetricsProcessor records the spans passing through the traces pipeline
+// in an aggregator published by the spanmetrics receiver. The spans are not
+// modified.
+type spanMetricsProcessor struct {
+	dimensions []string
+	registry   *spanmetrics.Registry
+	aggregator *spanmetrics.Aggregator
+	unregister func()
+}
+
+func newSpanMetricsProcessor(cfg *Config, registry *spanmetrics.Registry) *spanMetricsProcessor {
+	return &spanMetricsProcessor{
+		dimensions: cfg.Dimensions,
+		registry:   registry,
+		aggregator: spanmetrics.NewAggregator(cfg.HistogramBuckets),
+	}
+}
+
+func (p *spanMetricsProcessor) start(context.Context, component.Host) error {
+	p.unregister = p.registry.Register(p.aggregator)
+	return nil
+}
+
+func (p *spanMetricsProcessor) shutdown(context.Context) error {
+	if p.unregister != nil {
+		p.unregister()
+	}
+	return nil
+}
+
+func (p *spanMetricsProcessor) processTraces(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		resourceAttrs := rs.Resource().Attributes()
+		service := unknownService
+		if value, ok := resourceAttrs.Get(semconv.AttributeServiceName); ok && value.Str() != "" {
+			service = value.Str()
+		}
+		sss := rs.ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			spans := sss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				duration := float64(span.EndTimestamp()-span.StartTimestamp()) / 1e6
+				if span.EndTimestamp() < span.StartTimestamp() {
+					duration = 0
+				}
+				p.aggregator.Record(p.spanDimensions(service, span, resourceAttrs), duration, span.Status().Code() == ptrace.StatusCodeError)
+			}
+		}
+	}
+	return td, nil
+}
+
+// spanDimensions returns the dimensions of the span. The configured dimensions
+// the span and its resource do not have are omitted.
+func (p *spanMetricsProcessor) spanDimensions(service string, span ptrace.Span, resourceAttrs pcommon.Map) []spanmetrics.Dimension {
+	dimensions := make([]spanmetrics.Dimension, 0, 3+len(p.dimensions))
+	dimensions = append(dimensions,
+		spanmetrics.Dimension{Name: spanmetrics.DimensionServiceName, Value: service},
+		spanmetrics.Dimension{Name: spanmetrics.DimensionSpanName, Value: span.Name()},
+		spanmetrics.Dimension{Name: spanmetrics.DimensionSpanKind, Value: spanKind(span.Kind())},
+	)
+	for _, name := range p.dimensions {
+		value, ok := span.Attributes().Get(name)
+		if !ok {
+			value, ok = resourceAttrs.Get(name)
+		}
+		if ok {
+			dimensions = append(dimensions, spanmetrics.Dimension{Name: name, Value: value.AsString()})
+		}
+	}
+	return dimensions
+}
+
+// spanKind returns the upper case name of the kind, e.g. SERVER.
+func spanKind(kind ptrace.SpanKind) string {
+	return strings.ToUpper(kind.String())
+}
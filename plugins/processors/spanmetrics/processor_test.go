@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package spanmetrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/spanmetrics"
+)
+
+func addSpan(spans ptrace.SpanSlice, name string, kind ptrace.SpanKind, duration time.Duration, status ptrace.StatusCode, attrs map[string]any) {
+	span := spans.AppendEmpty()
+	span.SetName(name)
+	span.SetKind(kind)
+	start := time.Unix(1700000000, 0)
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(duration)))
+	span.Status().SetCode(status)
+	_ = span.Attributes().FromRaw(attrs)
+}
+
+func TestProcessTraces(t *testing.T) {
+	registry := spanmetrics.NewRegistry()
+	p := newSpanMetricsProcessor(&Config{Dimensions: []string{"http.method", "deployment.environment"}, HistogramBuckets: []float64{10, 100}}, registry)
+	require.NoError(t, p.start(context.Background(), componenttest.NewNopHost()))
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "shop")
+	rs.Resource().Attributes().PutStr("deployment.environment", "prod")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	addSpan(spans, "GET /cart", ptrace.SpanKindServer, 5*time.Millisecond, ptrace.StatusCodeUnset, map[string]any{"http.method": "GET"})
+	addSpan(spans, "GET /cart", ptrace.SpanKindServer, 500*time.Millisecond, ptrace.StatusCodeError, map[string]any{"http.method": "GET"})
+	// the span attribute overrides the resource attribute
+	addSpan(spans, "query", ptrace.SpanKindClient, 20*time.Millisecond, ptrace.StatusCodeOk, map[string]any{"deployment.environment": "test"})
+	// the spans without service are aggregated under the unknown service
+	addSpan(td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans(), "job", ptrace.SpanKindInternal, time.Millisecond, ptrace.StatusCodeUnset, nil)
+
+	got, err := p.processTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, td, got)
+
+	series := registry.Collect()
+	require.Len(t, series, 3)
+	assert.Equal(t, []spanmetrics.Dimension{
+		{Name: "service.name", Value: "shop"},
+		{Name: "span.name", Value: "GET /cart"},
+		{Name: "span.kind", Value: "SERVER"},
+		{Name: "http.method", Value: "GET"},
+		{Name: "deployment.environment", Value: "prod"},
+	}, series[0].Dimensions)
+	assert.EqualValues(t, 2, series[0].Calls)
+	assert.EqualValues(t, 1, series[0].Errors)
+	assert.Equal(t, 505.0, series[0].Sum)
+	assert.Equal(t, []uint64{1, 0, 1}, series[0].BucketCounts)
+	assert.Equal(t, []spanmetrics.Dimension{
+		{Name: "service.name", Value: "shop"},
+		{Name: "span.name", Value: "query"},
+		{Name: "span.kind", Value: "CLIENT"},
+		{Name: "deployment.environment", Value: "test"},
+	}, series[1].Dimensions)
+	assert.EqualValues(t, 0, series[1].Errors)
+	assert.Equal(t, []spanmetrics.Dimension{
+		{Name: "service.name", Value: "unknown_service"},
+		{Name: "span.name", Value: "job"},
+		{Name: "span.kind", Value: "INTERNAL"},
+	}, series[2].Dimensions)
+
+	require.NoError(t, p.shutdown(context.Background()))
+	addSpan(spans, "GET /cart", ptrace.SpanKindServer, time.Millisecond, ptrace.StatusCodeUnset, nil)
+	_, err = p.processTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Empty(t, registry.Collect())
+}
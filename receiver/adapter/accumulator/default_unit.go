@@ -59,6 +59,19 @@ var defaultUnits = map[string]map[string]string{
 		"read_time":        "Milliseconds",
 		"write_time":       "Milliseconds",
 	},
+	"diskstats": {
+		"read_latency":    "Milliseconds",
+		"write_latency":   "Milliseconds",
+		"discard_latency": "Milliseconds",
+		"flush_latency":   "Milliseconds",
+		"io_latency":      "Milliseconds",
+		"queue_depth":     "Count",
+		"in_flight":       "Count",
+		"read_ops":        "Count",
+		"write_ops":       "Count",
+		"read_bytes":      "Bytes",
+		"write_bytes":     "Bytes",
+	},
 
 	"swap": {
 		"used":         "Bytes",
@@ -0,0 +1,259 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package burst schedules the high-resolution burst captures. A rule is
+// triggered when a metric stays above a threshold for a duration, which
+// makes the inputs in its capture set gather at the burst resolution until
+// the capture duration elapses.
+package burst
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	// AttributeBurstCapture flags the data points gathered during a capture.
+	AttributeBurstCapture = "burst_capture"
+	// attributeStorageResolution makes the CloudWatch exporter store the
+	// data points at high resolution.
+	attributeStorageResolution = "aws:StorageResolution"
+
+	DefaultCaptureDuration = 5 * time.Minute
+	DefaultResolution      = time.Second
+)
+
+// Rule triggers a burst capture.
+type Rule struct {
+	// Name identifies the rule across the receivers it is configured on.
+	Name string `mapstructure:"name"`
+	// Metric is the name of the metric compared to the threshold.
+	Metric string `mapstructure:"metric"`
+	// Dimensions restricts the data points compared to the threshold to the
+	// ones with these attributes, e.g. cpu=cpu-total.
+	Dimensions map[string]string `mapstructure:"dimensions,omitempty"`
+	// Threshold is breached when the value is greater than it.
+	Threshold float64 `mapstructure:"threshold"`
+	// Duration is how long the threshold has to be breached. Zero triggers
+	// the capture on the first breach.
+	Duration time.Duration `mapstructure:"duration"`
+	// Capture is the set of inputs gathered at the burst resolution.
+	Capture []string `mapstructure:"capture"`
+	// CaptureDuration is how long the burst resolution is used.
+	CaptureDuration time.Duration `mapstructure:"capture_duration"`
+	// Resolution is the interval the captured inputs are gathered at.
+	Resolution time.Duration `mapstructure:"resolution"`
+}
+
+// Validate checks if the rule is valid.
+func (r *Rule) Validate() error {
+	if r.Name == "" {
+		return errors.New("burst rule 'name' must be set")
+	}
+	if r.Metric == "" {
+		return fmt.Errorf("burst rule %s: 'metric' must be set", r.Name)
+	}
+	if len(r.Capture) == 0 {
+		return fmt.Errorf("burst rule %s: 'capture' must not be empty", r.Name)
+	}
+	if r.Duration < 0 {
+		return fmt.Errorf("burst rule %s: 'duration' must not be negative", r.Name)
+	}
+	if r.Resolution < time.Second {
+		return fmt.Errorf("burst rule %s: 'resolution' must be at least 1 second", r.Name)
+	}
+	if r.CaptureDuration < r.Resolution {
+		return fmt.Errorf("burst rule %s: 'capture_duration' must be at least the resolution", r.Name)
+	}
+	return nil
+}
+
+// Captures returns true if the input is in the capture set of the rule.
+func (r *Rule) Captures(input string) bool {
+	return slices.Contains(r.Capture, input)
+}
+
+type trigger struct {
+	rule Rule
+	// breachStart is when the current breach started. Zero if the last
+	// observation was not a breach.
+	breachStart time.Time
+	// captureUntil is when the current capture ends.
+	captureUntil time.Time
+}
+
+// Scheduler tracks the rules shared by the receivers.
+type Scheduler struct {
+	mu       sync.Mutex
+	triggers map[string]*trigger
+	now      func() time.Time
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{triggers: make(map[string]*trigger), now: time.Now}
+}
+
+var defaultScheduler = NewScheduler()
+
+// Default returns the scheduler shared by the receivers in the process.
+func Default() *Scheduler {
+	return defaultScheduler
+}
+
+// Register adds the rule. Each receiver registers the rules it is configured
+// with, so a rule with the same name and configuration keeps its state.
+func (s *Scheduler) Register(rule Rule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.triggers[rule.Name]; ok && reflect.DeepEqual(t.rule, rule) {
+		return
+	}
+	s.triggers[rule.Name] = &trigger{rule: rule}
+}
+
+// Observe compares the data points of the metrics with the thresholds of the
+// rules. It returns the names of the rules that triggered a capture.
+func (s *Scheduler) Observe(md pmetric.Metrics) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.triggers) == 0 {
+		return nil
+	}
+	now := s.now()
+	var triggered []string
+	for name, t := range s.triggers {
+		breached, observed := t.observe(md)
+		if !observed {
+			continue
+		}
+		if !breached {
+			t.breachStart = time.Time{}
+			continue
+		}
+		if t.breachStart.IsZero() {
+			t.breachStart = now
+		}
+		// a rule does not trigger again until its capture ends
+		if now.Before(t.captureUntil) || now.Sub(t.breachStart) < t.rule.Duration {
+			continue
+		}
+		t.captureUntil = now.Add(t.rule.CaptureDuration)
+		t.breachStart = time.Time{}
+		triggered = append(triggered, name)
+	}
+	return triggered
+}
+
+// Capturing returns true if a capture including the input is active.
+func (s *Scheduler) Capturing(input string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for _, t := range s.triggers {
+		if now.Before(t.captureUntil) && t.rule.Captures(input) {
+			return true
+		}
+	}
+	return false
+}
+
+// observe returns whether any of the data points of the rule's metric breach
+// the threshold and whether any were found.
+func (t *trigger) observe(md pmetric.Metrics) (breached bool, observed bool) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if metric.Name() != t.rule.Metric {
+					continue
+				}
+				var dps pmetric.NumberDataPointSlice
+				switch metric.Type() {
+				case pmetric.MetricTypeGauge:
+					dps = metric.Gauge().DataPoints()
+				case pmetric.MetricTypeSum:
+					dps = metric.Sum().DataPoints()
+				default:
+					continue
+				}
+				for l := 0; l < dps.Len(); l++ {
+					dp := dps.At(l)
+					if !matches(dp.Attributes(), t.rule.Dimensions) {
+						continue
+					}
+					observed = true
+					if value(dp) > t.rule.Threshold {
+						breached = true
+					}
+				}
+			}
+		}
+	}
+	return breached, observed
+}
+
+func matches(attributes pcommon.Map, dimensions map[string]string) bool {
+	for k, v := range dimensions {
+		attr, ok := attributes.Get(k)
+		if !ok || attr.AsString() != v {
+			return false
+		}
+	}
+	return true
+}
+
+func value(dp pmetric.NumberDataPoint) float64 {
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		return float64(dp.IntValue())
+	}
+	return dp.DoubleValue()
+}
+
+// Flag marks the data points as burst-captured and stored at high resolution.
+func Flag(md pmetric.Metrics) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				flagMetric(metrics.At(k))
+			}
+		}
+	}
+}
+
+func flagMetric(metric pmetric.Metric) {
+	var attrs []pcommon.Map
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attrs = append(attrs, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attrs = append(attrs, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attrs = append(attrs, dps.At(i).Attributes())
+		}
+	}
+	for _, attr := range attrs {
+		attr.PutStr(AttributeBurstCapture, "true")
+		attr.PutStr(attributeStorageResolution, "true")
+	}
+}
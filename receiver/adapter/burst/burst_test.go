@@ -0,0 +1,134 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package burst
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func cpuMetrics(total, core float64) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("cpu_usage_active")
+	dps := metric.SetEmptyGauge().DataPoints()
+	dp := dps.AppendEmpty()
+	dp.SetDoubleValue(total)
+	dp.Attributes().PutStr("cpu", "cpu-total")
+	dp = dps.AppendEmpty()
+	dp.SetDoubleValue(core)
+	dp.Attributes().PutStr("cpu", "cpu0")
+	return md
+}
+
+func testRule() Rule {
+	return Rule{
+		Name:            "burst0",
+		Metric:          "cpu_usage_active",
+		Dimensions:      map[string]string{"cpu": "cpu-total"},
+		Threshold:       90,
+		Duration:        time.Minute,
+		Capture:         []string{"cpu", "mem", "disk"},
+		CaptureDuration: DefaultCaptureDuration,
+		Resolution:      DefaultResolution,
+	}
+}
+
+func TestRuleValidate(t *testing.T) {
+	testCases := map[string]struct {
+		modify  func(rule *Rule)
+		wantErr bool
+	}{
+		"Valid":            {modify: func(*Rule) {}},
+		"MissingName":      {modify: func(rule *Rule) { rule.Name = "" }, wantErr: true},
+		"MissingMetric":    {modify: func(rule *Rule) { rule.Metric = "" }, wantErr: true},
+		"EmptyCapture":     {modify: func(rule *Rule) { rule.Capture = nil }, wantErr: true},
+		"NegativeDuration": {modify: func(rule *Rule) { rule.Duration = -time.Second }, wantErr: true},
+		"SubSecond":        {modify: func(rule *Rule) { rule.Resolution = time.Millisecond }, wantErr: true},
+		"ShortCapture":     {modify: func(rule *Rule) { rule.CaptureDuration = 0 }, wantErr: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rule := testRule()
+			testCase.modify(&rule)
+			if testCase.wantErr {
+				assert.Error(t, rule.Validate())
+			} else {
+				assert.NoError(t, rule.Validate())
+			}
+		})
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := NewScheduler()
+	s.now = func() time.Time { return now }
+	s.Register(testRule())
+
+	// only the total is compared to the threshold
+	assert.Empty(t, s.Observe(cpuMetrics(10, 99)))
+	assert.False(t, s.Capturing("cpu"))
+
+	// the breach has to last for the duration
+	assert.Empty(t, s.Observe(cpuMetrics(95, 10)))
+	now = now.Add(30 * time.Second)
+	assert.Empty(t, s.Observe(cpuMetrics(95, 10)))
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, []string{"burst0"}, s.Observe(cpuMetrics(95, 10)))
+	assert.True(t, s.Capturing("cpu"))
+	assert.True(t, s.Capturing("mem"))
+	assert.False(t, s.Capturing("net"))
+
+	// registering the same rule again keeps the capture
+	s.Register(testRule())
+	assert.True(t, s.Capturing("disk"))
+
+	// no new trigger while capturing
+	now = now.Add(2 * time.Minute)
+	assert.Empty(t, s.Observe(cpuMetrics(95, 10)))
+
+	// reverts once the capture duration elapsed
+	now = now.Add(3 * time.Minute)
+	assert.False(t, s.Capturing("cpu"))
+
+	// a drop below the threshold resets the breach
+	assert.Empty(t, s.Observe(cpuMetrics(50, 10)))
+	now = now.Add(time.Minute)
+	assert.Empty(t, s.Observe(cpuMetrics(95, 10)))
+	assert.False(t, s.Capturing("cpu"))
+
+	// metrics without the rule's metric do not reset the breach
+	assert.Empty(t, s.Observe(pmetric.NewMetrics()))
+	now = now.Add(time.Minute)
+	assert.Equal(t, []string{"burst0"}, s.Observe(cpuMetrics(95, 10)))
+}
+
+func TestSchedulerWithoutDuration(t *testing.T) {
+	s := NewScheduler()
+	rule := testRule()
+	rule.Duration = 0
+	rule.Dimensions = nil
+	s.Register(rule)
+	// any data point breaching the threshold triggers the capture
+	assert.Equal(t, []string{"burst0"}, s.Observe(cpuMetrics(10, 99)))
+	assert.True(t, s.Capturing("cpu"))
+}
+
+func TestFlag(t *testing.T) {
+	md := cpuMetrics(95, 10)
+	Flag(md)
+	dps := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints()
+	for i := 0; i < dps.Len(); i++ {
+		v, ok := dps.At(i).Attributes().Get(AttributeBurstCapture)
+		assert.True(t, ok)
+		assert.Equal(t, "true", v.Str())
+		v, ok = dps.At(i).Attributes().Get(attributeStorageResolution)
+		assert.True(t, ok)
+		assert.Equal(t, "true", v.Str())
+	}
+}
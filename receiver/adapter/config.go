@@ -6,6 +6,8 @@ package adapter
 import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
+
+	"github.com/aws/amazon-cloudwatch-agent/receiver/adapter/burst"
 )
 
 type Config struct {
@@ -13,10 +15,18 @@ type Config struct {
 
 	// The different name of the plugin, share the similar structure with https://github.com/influxdata/telegraf/pull/6207
 	AliasName string `mapstructure:"alias_name,omitempty"`
+
+	// Burst is the burst capture rules the input triggers or is captured by.
+	Burst []burst.Rule `mapstructure:"burst,omitempty"`
 }
 
 var _ component.Config = (*Config)(nil)
 
 func (cfg *Config) Validate() error {
+	for _, rule := range cfg.Burst {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -14,6 +14,8 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
+
+	"github.com/aws/amazon-cloudwatch-agent/receiver/adapter/burst"
 )
 
 const (
@@ -61,6 +63,8 @@ func (a Adapter) createMetricsReceiver(ctx context.Context, settings receiver.Cr
 	}
 
 	rcvr := newAdaptedReceiver(input, ctx, consumer, settings.Logger)
+	rcvr.rules = cfg.Burst
+	rcvr.scheduler = burst.Default()
 
 	scraper, err := scraperhelper.NewScraper(
 		settings.ID.Type().String(),
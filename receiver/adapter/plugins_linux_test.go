@@ -14,11 +14,11 @@ import (
 	_ "github.com/influxdata/telegraf/plugins/inputs/mem"
 	_ "github.com/influxdata/telegraf/plugins/inputs/net"
 	_ "github.com/influxdata/telegraf/plugins/inputs/processes"
-	_ "github.com/influxdata/telegraf/plugins/inputs/procstat"
 	_ "github.com/influxdata/telegraf/plugins/inputs/socket_listener"
 	_ "github.com/influxdata/telegraf/plugins/inputs/swap"
 	"github.com/stretchr/testify/assert"
 
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/procstat"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/statsd"
 )
 
@@ -5,6 +5,8 @@ package adapter
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/models"
@@ -14,6 +16,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/aws/amazon-cloudwatch-agent/receiver/adapter/accumulator"
+	"github.com/aws/amazon-cloudwatch-agent/receiver/adapter/burst"
 )
 
 // AdaptedReceiver uses an OTel Scrape Controller to scrape metrics and has three phases:
@@ -26,6 +29,14 @@ type AdaptedReceiver struct {
 	ctx         context.Context
 	consumer    consumer.Metrics
 	accumulator accumulator.OtelAccumulator
+
+	// rules are the burst capture rules the input triggers or is captured by.
+	rules     []burst.Rule
+	scheduler *burst.Scheduler
+	// gatherMutex serializes the scheduled and the burst gathers.
+	gatherMutex sync.Mutex
+	done        chan struct{}
+	wg          sync.WaitGroup
 }
 
 func newAdaptedReceiver(input *models.RunningInput, ctx context.Context, consumer consumer.Metrics, logger *zap.Logger) *AdaptedReceiver {
@@ -54,6 +65,7 @@ func (r *AdaptedReceiver) start(_ context.Context, _ component.Host) error {
 		}
 	}
 
+	r.startBurst()
 	return nil
 }
 
@@ -64,16 +76,91 @@ func (r *AdaptedReceiver) scrape(_ context.Context) (pmetric.Metrics, error) {
 	// the background process is the one sending the metrics further along the pipeline but there are cases where the
 	// background process can buffer the metrics and calling Gather is what flushes the buffer. An example of this is
 	// our statsd plugin: https://github.com/aws/amazon-cloudwatch-agent/blob/2e468dfd96cf9084ab76c2420262e1bbe1eca483/plugins/inputs/statsd/statsd.go
+	md, err := r.gather()
+	if err != nil {
+		return pmetric.Metrics{}, err
+	}
+	r.observe(md)
+	return md, nil
+}
+
+func (r *AdaptedReceiver) gather() (pmetric.Metrics, error) {
+	r.gatherMutex.Lock()
+	defer r.gatherMutex.Unlock()
 	if err := r.input.Input.Gather(r.accumulator); err != nil {
 		r.accumulator.AddError(err)
 		return pmetric.Metrics{}, err
 	}
-
 	return r.accumulator.GetOtelMetrics(), nil
 }
 
+// startBurst registers the burst rules and starts gathering at the burst
+// resolution if the input is in the capture set of any rule. Service inputs
+// are not captured since they do not gather on an interval.
+func (r *AdaptedReceiver) startBurst() {
+	if len(r.rules) == 0 || r.scheduler == nil {
+		return
+	}
+	var resolution time.Duration
+	for _, rule := range r.rules {
+		r.scheduler.Register(rule)
+		if rule.Captures(r.input.Config.Name) && (resolution == 0 || rule.Resolution < resolution) {
+			resolution = rule.Resolution
+		}
+	}
+	if resolution == 0 {
+		return
+	}
+	if _, ok := r.input.Input.(telegraf.ServiceInput); ok {
+		r.logger.Warn("Burst capture is not supported for service inputs", zap.String("receiver", r.input.Config.Name))
+		return
+	}
+	r.done = make(chan struct{})
+	r.wg.Add(1)
+	go r.burstLoop(resolution)
+}
+
+func (r *AdaptedReceiver) burstLoop(resolution time.Duration) {
+	defer r.wg.Done()
+	ticker := time.NewTicker(resolution)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !r.scheduler.Capturing(r.input.Config.Name) {
+				continue
+			}
+			md, err := r.gather()
+			if err != nil || md.DataPointCount() == 0 {
+				continue
+			}
+			r.observe(md)
+			burst.Flag(md)
+			if err = r.consumer.ConsumeMetrics(r.ctx, md); err != nil {
+				r.logger.Debug("Failed to consume burst-captured metrics", zap.String("receiver", r.input.Config.Name), zap.Error(err))
+			}
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// observe checks the gathered metrics against the burst rules.
+func (r *AdaptedReceiver) observe(md pmetric.Metrics) {
+	if len(r.rules) == 0 || r.scheduler == nil {
+		return
+	}
+	for _, name := range r.scheduler.Observe(md) {
+		r.logger.Info("Burst capture triggered", zap.String("rule", name), zap.String("receiver", r.input.Config.Name))
+	}
+}
+
 func (r *AdaptedReceiver) shutdown(_ context.Context) error {
 	r.logger.Debug("Shutdown adapter", zap.String("receiver", r.input.Config.Name))
+	if r.done != nil {
+		close(r.done)
+		r.wg.Wait()
+	}
 	if serviceInput, ok := r.input.Input.(telegraf.ServiceInput); ok {
 		serviceInput.Stop()
 	}
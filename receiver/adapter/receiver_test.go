@@ -6,14 +6,18 @@ package adapter
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.uber.org/zap"
 
 	"github.com/aws/amazon-cloudwatch-agent/receiver/adapter/accumulator"
+	"github.com/aws/amazon-cloudwatch-agent/receiver/adapter/burst"
 )
 
 func Test_AdaptedReceiver_WithEmptyMetrics(t *testing.T) {
@@ -49,3 +53,52 @@ func Test_AdaptedReceiver_WithEmptyMetrics_ServiceInput(t *testing.T) {
 	err = adaptedReceiver.shutdown(ctx)
 	as.NoError(err)
 }
+
+type cpuInput struct {
+	value float64
+}
+
+func (c *cpuInput) Description() string  { return "" }
+func (c *cpuInput) SampleConfig() string { return "" }
+func (c *cpuInput) Gather(acc telegraf.Accumulator) error {
+	acc.AddGauge("cpu", map[string]interface{}{"usage_active": c.value}, map[string]string{"cpu": "cpu-total"})
+	return nil
+}
+
+func Test_AdaptedReceiver_BurstCapture(t *testing.T) {
+	ctx := context.Background()
+	ri := models.NewRunningInput(&cpuInput{value: 95}, &models.InputConfig{Name: "cpu"})
+	require.NoError(t, ri.Config.Filter.Compile())
+	sink := &consumertest.MetricsSink{}
+	adaptedReceiver := newAdaptedReceiver(ri, ctx, sink, zap.NewNop())
+	adaptedReceiver.rules = []burst.Rule{{
+		Name:            "burst0",
+		Metric:          "cpu_usage_active",
+		Threshold:       90,
+		Capture:         []string{"cpu", "mem"},
+		CaptureDuration: time.Minute,
+		Resolution:      10 * time.Millisecond,
+	}}
+	adaptedReceiver.scheduler = burst.NewScheduler()
+
+	require.NoError(t, adaptedReceiver.start(ctx, componenttest.NewNopHost()))
+	assert.Equal(t, 0, sink.DataPointCount())
+
+	// the scheduled scrape triggers the capture and is not flagged
+	md, err := adaptedReceiver.scrape(ctx)
+	require.NoError(t, err)
+	_, ok := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0).Attributes().Get(burst.AttributeBurstCapture)
+	assert.False(t, ok)
+	assert.True(t, adaptedReceiver.scheduler.Capturing("cpu"))
+
+	assert.Eventually(t, func() bool {
+		return sink.DataPointCount() >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, adaptedReceiver.shutdown(ctx))
+
+	captured := sink.AllMetrics()[0]
+	attrs := captured.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0).Attributes()
+	v, ok := attrs.Get(burst.AttributeBurstCapture)
+	assert.True(t, ok)
+	assert.Equal(t, "true", v.Str())
+}
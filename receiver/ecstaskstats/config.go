@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecstaskstats
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+)
+
+type Config struct {
+	scraperhelper.ControllerConfig `mapstructure:",squash"`
+
+	// DockerEndpoint is the address of the Docker Engine API the stats of the
+	// containers of the ECS tasks are read from.
+	DockerEndpoint string `mapstructure:"docker_endpoint"`
+	// TaskNetworkMetrics enables the network metrics of the tasks.
+	TaskNetworkMetrics bool `mapstructure:"task_network_metrics"`
+	// ServiceConnectMetrics enables the metrics of the Service Connect proxies
+	// of the tasks.
+	ServiceConnectMetrics bool `mapstructure:"service_connect_metrics"`
+	// ServiceConnectStatusDir is the host directory the ECS agent creates the
+	// admin sockets of the Service Connect proxies in, one per task.
+	ServiceConnectStatusDir string `mapstructure:"service_connect_status_dir"`
+}
+
+// Verify Config implements Receiver interface.
+var _ component.Config = (*Config)(nil)
+
+func (cfg *Config) Validate() error {
+	if !cfg.TaskNetworkMetrics && !cfg.ServiceConnectMetrics {
+		return errors.New("at least one of task_network_metrics or service_connect_metrics must be enabled")
+	}
+	if cfg.DockerEndpoint == "" {
+		return errors.New("docker_endpoint must be set")
+	}
+	if cfg.ServiceConnectMetrics && cfg.ServiceConnectStatusDir == "" {
+		return errors.New("service_connect_status_dir must be set to collect the service connect metrics")
+	}
+	return nil
+}
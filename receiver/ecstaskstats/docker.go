@@ -0,0 +1,132 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecstaskstats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// the labels set by the ECS agent on the containers of the tasks, including
+	// the pause containers of the tasks in awsvpc network mode
+	labelCluster                = "com.amazonaws.ecs.cluster"
+	labelTaskArn                = "com.amazonaws.ecs.task-arn"
+	labelTaskDefinitionFamily   = "com.amazonaws.ecs.task-definition-family"
+	labelTaskDefinitionRevision = "com.amazonaws.ecs.task-definition-version"
+
+	dockerRequestTimeout = 10 * time.Second
+)
+
+type dockerContainer struct {
+	ID     string `json:"Id"`
+	Labels map[string]string
+}
+
+type dockerNetworkStats struct {
+	RxBytes   uint64 `json:"rx_bytes"`
+	RxPackets uint64 `json:"rx_packets"`
+	RxErrors  uint64 `json:"rx_errors"`
+	RxDropped uint64 `json:"rx_dropped"`
+	TxBytes   uint64 `json:"tx_bytes"`
+	TxPackets uint64 `json:"tx_packets"`
+	TxErrors  uint64 `json:"tx_errors"`
+	TxDropped uint64 `json:"tx_dropped"`
+}
+
+func (s *dockerNetworkStats) add(other dockerNetworkStats) {
+	s.RxBytes += other.RxBytes
+	s.RxPackets += other.RxPackets
+	s.RxErrors += other.RxErrors
+	s.RxDropped += other.RxDropped
+	s.TxBytes += other.TxBytes
+	s.TxPackets += other.TxPackets
+	s.TxErrors += other.TxErrors
+	s.TxDropped += other.TxDropped
+}
+
+type dockerStats struct {
+	Read time.Time `json:"read"`
+	// Networks is only set for the containers owning their network namespace,
+	// not for the containers joining the one of another container or the host.
+	Networks map[string]dockerNetworkStats `json:"networks"`
+}
+
+// dockerClient reads the containers and their stats from the Docker Engine
+// API, listening on a unix socket or on a TCP address.
+type dockerClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func newDockerClient(endpoint string) (*dockerClient, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker endpoint %q: %w", endpoint, err)
+	}
+	switch u.Scheme {
+	case "unix":
+		return &dockerClient{
+			baseURL: "http://docker",
+			client:  newUnixSocketClient(u.Path),
+		}, nil
+	case "tcp", "http":
+		return &dockerClient{
+			baseURL: "http://" + u.Host,
+			client:  &http.Client{Timeout: dockerRequestTimeout},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported docker endpoint scheme %q", u.Scheme)
+	}
+}
+
+func newUnixSocketClient(path string) *http.Client {
+	return &http.Client{
+		Timeout: dockerRequestTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		},
+	}
+}
+
+// taskContainers returns the running containers of the ECS tasks.
+func (c *dockerClient) taskContainers(ctx context.Context) ([]dockerContainer, error) {
+	filters, _ := json.Marshal(map[string][]string{"label": {labelTaskArn}})
+	var containers []dockerContainer
+	err := c.get(ctx, "/containers/json?filters="+url.QueryEscape(string(filters)), &containers)
+	return containers, err
+}
+
+func (c *dockerClient) stats(ctx context.Context, id string) (*dockerStats, error) {
+	var stats dockerStats
+	if err := c.get(ctx, "/containers/"+id+"/stats?stream=false&one-shot=true", &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (c *dockerClient) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to get %s from docker: %w", strings.SplitN(path, "?", 2)[0], err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to get %s from docker, status code: %d", strings.SplitN(path, "?", 2)[0], resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
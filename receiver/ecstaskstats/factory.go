@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecstaskstats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+)
+
+const (
+	stability                 = component.StabilityLevelAlpha
+	defaultCollectionInterval = time.Minute
+	defaultDockerEndpoint     = "unix:///var/run/docker.sock"
+	// defaultServiceConnectStatusDir is where the ECS agent exposes the admin
+	// socket of the Service Connect proxy of each task on the host.
+	defaultServiceConnectStatusDir = "/var/run/ecs/service_connect"
+)
+
+var (
+	TypeStr, _ = component.NewType("ecstaskstats")
+)
+
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		TypeStr,
+		createDefaultConfig,
+		receiver.WithMetrics(createMetricsReceiver, stability))
+}
+
+func createDefaultConfig() component.Config {
+	cfg := scraperhelper.NewDefaultControllerConfig()
+	cfg.CollectionInterval = defaultCollectionInterval
+	return &Config{
+		ControllerConfig:        cfg,
+		DockerEndpoint:          defaultDockerEndpoint,
+		TaskNetworkMetrics:      true,
+		ServiceConnectStatusDir: defaultServiceConnectStatusDir,
+	}
+}
+
+func createMetricsReceiver(
+	_ context.Context,
+	set receiver.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Metrics,
+) (receiver.Metrics, error) {
+	receiverConfig, ok := cfg.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("configuration parsing error")
+	}
+
+	s := newScraper(receiverConfig, set.Logger)
+	scraper, err := scraperhelper.NewScraper(TypeStr.String(), s.scrape, scraperhelper.WithStart(s.start))
+	if err != nil {
+		return nil, err
+	}
+	return scraperhelper.NewScraperControllerReceiver(&receiverConfig.ControllerConfig, set, nextConsumer, scraperhelper.AddScraper(scraper))
+}
@@ -0,0 +1,51 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecstaskstats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	assert.Equal(t, "ecstaskstats", factory.Type().String())
+	cfg := factory.CreateDefaultConfig().(*Config)
+	assert.Equal(t, time.Minute, cfg.CollectionInterval)
+	assert.Equal(t, "unix:///var/run/docker.sock", cfg.DockerEndpoint)
+	assert.True(t, cfg.TaskNetworkMetrics)
+	assert.False(t, cfg.ServiceConnectMetrics)
+	assert.NoError(t, componenttest.CheckConfigStruct(cfg))
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.TaskNetworkMetrics = false
+	assert.Error(t, cfg.Validate())
+	cfg.ServiceConnectMetrics = true
+	assert.NoError(t, cfg.Validate())
+	cfg.ServiceConnectStatusDir = ""
+	assert.Error(t, cfg.Validate())
+	cfg.ServiceConnectMetrics = false
+	cfg.TaskNetworkMetrics = true
+	cfg.DockerEndpoint = ""
+	assert.Error(t, cfg.Validate())
+}
+
+func TestCreateMetricsReceiver(t *testing.T) {
+	factory := NewFactory()
+	receiver, err := factory.CreateMetricsReceiver(context.Background(), receivertest.NewNopCreateSettings(), factory.CreateDefaultConfig(), consumertest.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, receiver)
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, receiver.Shutdown(context.Background()))
+}
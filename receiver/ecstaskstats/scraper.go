@@ -0,0 +1,292 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecstaskstats
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+const (
+	attributeClusterName            = "ClusterName"
+	attributeTaskID                 = "TaskId"
+	attributeTaskDefinitionFamily   = "TaskDefinitionFamily"
+	attributeTaskDefinitionRevision = "TaskDefinitionRevision"
+	attributeType                   = "Type"
+
+	typeTask           = "Task"
+	typeServiceConnect = "ServiceConnect"
+
+	metricNetworkRxBytes    = "task_network_rx_bytes"
+	metricNetworkRxPackets  = "task_network_rx_packets"
+	metricNetworkRxErrors   = "task_network_rx_errors"
+	metricNetworkRxDropped  = "task_network_rx_dropped"
+	metricNetworkTxBytes    = "task_network_tx_bytes"
+	metricNetworkTxPackets  = "task_network_tx_packets"
+	metricNetworkTxErrors   = "task_network_tx_errors"
+	metricNetworkTxDropped  = "task_network_tx_dropped"
+	metricNetworkTotalBytes = "task_network_total_bytes"
+
+	serviceConnectMetricPrefix = "service_connect_"
+
+	unitBytesPerSecond = "Bytes/Second"
+	unitCountPerSecond = "Count/Second"
+)
+
+type task struct {
+	arn        string
+	id         string
+	cluster    string
+	family     string
+	revision   string
+	containers []string
+}
+
+type networkSample struct {
+	timestamp time.Time
+	stats     dockerNetworkStats
+}
+
+// scraper reports the network rates of the ECS tasks, the same as the pod
+// network metrics of Container Insights on EKS, and the stats of their Service
+// Connect proxies.
+type scraper struct {
+	cfg       *Config
+	logger    *zap.Logger
+	docker    *dockerClient
+	startTime pcommon.Timestamp
+	// previous holds the network counters of the last scrape by task ARN.
+	previous map[string]networkSample
+}
+
+func newScraper(cfg *Config, logger *zap.Logger) *scraper {
+	return &scraper{cfg: cfg, logger: logger, previous: map[string]networkSample{}}
+}
+
+func (s *scraper) start(context.Context, component.Host) error {
+	docker, err := newDockerClient(s.cfg.DockerEndpoint)
+	if err != nil {
+		return err
+	}
+	s.docker = docker
+	s.startTime = pcommon.NewTimestampFromTime(time.Now())
+	return nil
+}
+
+func (s *scraper) scrape(ctx context.Context) (pmetric.Metrics, error) {
+	md := pmetric.NewMetrics()
+	containers, err := s.docker.taskContainers(ctx)
+	if err != nil {
+		return md, err
+	}
+	tasks := groupTasks(containers)
+	now := time.Now()
+	for _, t := range tasks {
+		if s.cfg.TaskNetworkMetrics {
+			s.scrapeNetwork(ctx, md, t, now)
+		}
+		if s.cfg.ServiceConnectMetrics {
+			s.scrapeServiceConnect(ctx, md, t, now)
+		}
+	}
+	// forget the tasks which have stopped
+	seen := make(map[string]struct{}, len(tasks))
+	for _, t := range tasks {
+		seen[t.arn] = struct{}{}
+	}
+	for arn := range s.previous {
+		if _, ok := seen[arn]; !ok {
+			delete(s.previous, arn)
+		}
+	}
+	return md, nil
+}
+
+// scrapeNetwork adds the network rates of the task since the previous scrape.
+// The containers of a task in awsvpc network mode share the network namespace
+// of its pause container, which is the only one reporting the network stats.
+func (s *scraper) scrapeNetwork(ctx context.Context, md pmetric.Metrics, t *task, now time.Time) {
+	var current networkSample
+	found := false
+	for _, id := range t.containers {
+		stats, err := s.docker.stats(ctx, id)
+		if err != nil {
+			s.logger.Debug("Unable to get the container stats", zap.String("container", id), zap.Error(err))
+			continue
+		}
+		for _, network := range stats.Networks {
+			current.stats.add(network)
+			found = true
+		}
+		if stats.Read.After(current.timestamp) {
+			current.timestamp = stats.Read
+		}
+	}
+	if !found {
+		return
+	}
+	if current.timestamp.IsZero() {
+		current.timestamp = now
+	}
+	previous, ok := s.previous[t.arn]
+	s.previous[t.arn] = current
+	if !ok {
+		return
+	}
+	seconds := current.timestamp.Sub(previous.timestamp).Seconds()
+	if seconds <= 0 {
+		return
+	}
+	rate := func(cur, prev uint64) (float64, bool) {
+		if cur < prev {
+			// the counters were reset, e.g. a container of the task restarted
+			return 0, false
+		}
+		return float64(cur-prev) / seconds, true
+	}
+
+	metrics := newTaskMetrics(md, t, typeTask)
+	timestamp := pcommon.NewTimestampFromTime(now)
+	for _, m := range []struct {
+		name      string
+		unit      string
+		cur, prev uint64
+	}{
+		{metricNetworkRxBytes, unitBytesPerSecond, current.stats.RxBytes, previous.stats.RxBytes},
+		{metricNetworkRxPackets, unitCountPerSecond, current.stats.RxPackets, previous.stats.RxPackets},
+		{metricNetworkRxErrors, unitCountPerSecond, current.stats.RxErrors, previous.stats.RxErrors},
+		{metricNetworkRxDropped, unitCountPerSecond, current.stats.RxDropped, previous.stats.RxDropped},
+		{metricNetworkTxBytes, unitBytesPerSecond, current.stats.TxBytes, previous.stats.TxBytes},
+		{metricNetworkTxPackets, unitCountPerSecond, current.stats.TxPackets, previous.stats.TxPackets},
+		{metricNetworkTxErrors, unitCountPerSecond, current.stats.TxErrors, previous.stats.TxErrors},
+		{metricNetworkTxDropped, unitCountPerSecond, current.stats.TxDropped, previous.stats.TxDropped},
+		{metricNetworkTotalBytes, unitBytesPerSecond, current.stats.RxBytes + current.stats.TxBytes, previous.stats.RxBytes + previous.stats.TxBytes},
+	} {
+		if value, ok := rate(m.cur, m.prev); ok {
+			addGauge(metrics, m.name, m.unit, timestamp, value, nil)
+		}
+	}
+}
+
+// scrapeServiceConnect adds the stats of the Service Connect proxy of the task,
+// if it has one. The counters are reported as cumulative sums.
+func (s *scraper) scrapeServiceConnect(ctx context.Context, md pmetric.Metrics, t *task, now time.Time) {
+	socket, ok := serviceConnectSocket(s.cfg.ServiceConnectStatusDir, t.id)
+	if !ok {
+		return
+	}
+	families, err := serviceConnectStats(ctx, socket)
+	if err != nil {
+		s.logger.Debug("Unable to get the service connect stats", zap.String("task", t.arn), zap.Error(err))
+		return
+	}
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	metrics := newTaskMetrics(md, t, typeServiceConnect)
+	timestamp := pcommon.NewTimestampFromTime(now)
+	for _, name := range names {
+		family := families[name]
+		for _, m := range family.GetMetric() {
+			labels := m.GetLabel()
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				addSum(metrics, serviceConnectMetricPrefix+name, s.startTime, timestamp, m.GetCounter().GetValue(), labels)
+			case dto.MetricType_GAUGE:
+				addGauge(metrics, serviceConnectMetricPrefix+name, "", timestamp, m.GetGauge().GetValue(), labels)
+			case dto.MetricType_UNTYPED:
+				addGauge(metrics, serviceConnectMetricPrefix+name, "", timestamp, m.GetUntyped().GetValue(), labels)
+			}
+		}
+	}
+}
+
+// groupTasks groups the containers by task, sorted by task ARN.
+func groupTasks(containers []dockerContainer) []*task {
+	byArn := map[string]*task{}
+	for _, c := range containers {
+		arn := c.Labels[labelTaskArn]
+		if arn == "" {
+			continue
+		}
+		t, ok := byArn[arn]
+		if !ok {
+			t = &task{
+				arn:      arn,
+				id:       lastSegment(arn),
+				cluster:  lastSegment(c.Labels[labelCluster]),
+				family:   c.Labels[labelTaskDefinitionFamily],
+				revision: c.Labels[labelTaskDefinitionRevision],
+			}
+			byArn[arn] = t
+		}
+		t.containers = append(t.containers, c.ID)
+	}
+	tasks := make([]*task, 0, len(byArn))
+	for _, t := range byArn {
+		tasks = append(tasks, t)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].arn < tasks[j].arn
+	})
+	return tasks
+}
+
+// lastSegment returns the name or ID at the end of an ARN, e.g. the cluster
+// name of arn:aws:ecs:us-east-1:123456789012:cluster/my-cluster. The cluster
+// label is the cluster name for older ECS agents.
+func lastSegment(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+func newTaskMetrics(md pmetric.Metrics, t *task, metricType string) pmetric.MetricSlice {
+	rm := md.ResourceMetrics().AppendEmpty()
+	attrs := rm.Resource().Attributes()
+	attrs.PutStr(attributeClusterName, t.cluster)
+	attrs.PutStr(attributeTaskID, t.id)
+	attrs.PutStr(attributeTaskDefinitionFamily, t.family)
+	attrs.PutStr(attributeTaskDefinitionRevision, t.revision)
+	attrs.PutStr(attributeType, metricType)
+	return rm.ScopeMetrics().AppendEmpty().Metrics()
+}
+
+func addGauge(metrics pmetric.MetricSlice, name, unit string, timestamp pcommon.Timestamp, value float64, labels []*dto.LabelPair) {
+	m := metrics.AppendEmpty()
+	m.SetName(name)
+	m.SetUnit(unit)
+	dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(timestamp)
+	dp.SetDoubleValue(value)
+	putLabels(dp.Attributes(), labels)
+}
+
+func addSum(metrics pmetric.MetricSlice, name string, start, timestamp pcommon.Timestamp, value float64, labels []*dto.LabelPair) {
+	m := metrics.AppendEmpty()
+	m.SetName(name)
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := sum.DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(timestamp)
+	dp.SetDoubleValue(value)
+	putLabels(dp.Attributes(), labels)
+}
+
+func putLabels(attrs pcommon.Map, labels []*dto.LabelPair) {
+	for _, label := range labels {
+		attrs.PutStr(label.GetName(), label.GetValue())
+	}
+}
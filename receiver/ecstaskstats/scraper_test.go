@@ -0,0 +1,246 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecstaskstats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+const (
+	testTaskArn = "arn:aws:ecs:us-east-1:123456789012:task/my-cluster/0123456789abcdef"
+	testTaskID  = "0123456789abcdef"
+)
+
+// fakeDocker serves the containers of an awsvpc task: the pause container
+// reporting the network stats of the task and an application container.
+type fakeDocker struct {
+	mu       sync.Mutex
+	read     time.Time
+	rxBytes  uint64
+	txBytes  uint64
+	failures int
+}
+
+func (f *fakeDocker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	labels := map[string]string{
+		labelCluster:                "arn:aws:ecs:us-east-1:123456789012:cluster/my-cluster",
+		labelTaskArn:                testTaskArn,
+		labelTaskDefinitionFamily:   "my-app",
+		labelTaskDefinitionRevision: "3",
+	}
+	switch {
+	case r.URL.Path == "/containers/json":
+		if !strings.Contains(r.URL.Query().Get("filters"), labelTaskArn) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]dockerContainer{
+			{ID: "pause", Labels: labels},
+			{ID: "app", Labels: labels},
+		})
+	case r.URL.Path == "/containers/pause/stats":
+		_ = json.NewEncoder(w).Encode(dockerStats{
+			Read: f.read,
+			Networks: map[string]dockerNetworkStats{
+				"eth0": {RxBytes: f.rxBytes, TxBytes: f.txBytes, RxPackets: 10},
+			},
+		})
+	case r.URL.Path == "/containers/app/stats":
+		_ = json.NewEncoder(w).Encode(dockerStats{Read: f.read})
+	default:
+		f.failures++
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeDocker) set(read time.Time, rxBytes, txBytes uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.read = read
+	f.rxBytes = rxBytes
+	f.txBytes = txBytes
+}
+
+func newTestScraper(t *testing.T, docker *fakeDocker, cfg func(*Config)) *scraper {
+	server := httptest.NewServer(docker)
+	t.Cleanup(server.Close)
+	config := createDefaultConfig().(*Config)
+	config.DockerEndpoint = "tcp://" + strings.TrimPrefix(server.URL, "http://")
+	if cfg != nil {
+		cfg(config)
+	}
+	s := newScraper(config, zap.NewNop())
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+	return s
+}
+
+func metricsByName(md pmetric.Metrics) map[string]pmetric.Metric {
+	got := map[string]pmetric.Metric{}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		metrics := md.ResourceMetrics().At(i).ScopeMetrics().At(0).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			got[metrics.At(j).Name()] = metrics.At(j)
+		}
+	}
+	return got
+}
+
+func TestScrapeTaskNetwork(t *testing.T) {
+	docker := &fakeDocker{}
+	s := newTestScraper(t, docker, nil)
+	start := time.Now()
+	docker.set(start, 1000, 500)
+
+	// the rates are reported from the second scrape
+	md, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, md.MetricCount())
+
+	docker.set(start.Add(10*time.Second), 3000, 1500)
+	md, err = s.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	attrs := md.ResourceMetrics().At(0).Resource().Attributes().AsRaw()
+	assert.Equal(t, map[string]any{
+		attributeClusterName:            "my-cluster",
+		attributeTaskID:                 testTaskID,
+		attributeTaskDefinitionFamily:   "my-app",
+		attributeTaskDefinitionRevision: "3",
+		attributeType:                   typeTask,
+	}, attrs)
+	got := metricsByName(md)
+	assert.Len(t, got, 9)
+	for name, want := range map[string]float64{
+		metricNetworkRxBytes:    200,
+		metricNetworkTxBytes:    100,
+		metricNetworkTotalBytes: 300,
+		metricNetworkRxPackets:  0,
+	} {
+		require.Contains(t, got, name)
+		assert.Equal(t, want, got[name].Gauge().DataPoints().At(0).DoubleValue(), name)
+	}
+	assert.Equal(t, unitBytesPerSecond, got[metricNetworkRxBytes].Unit())
+	assert.Equal(t, unitCountPerSecond, got[metricNetworkRxPackets].Unit())
+
+	// the counters were reset
+	docker.set(start.Add(20*time.Second), 100, 1600)
+	md, err = s.scrape(context.Background())
+	require.NoError(t, err)
+	got = metricsByName(md)
+	assert.NotContains(t, got, metricNetworkRxBytes)
+	assert.NotContains(t, got, metricNetworkTotalBytes)
+	assert.Equal(t, float64(10), got[metricNetworkTxBytes].Gauge().DataPoints().At(0).DoubleValue())
+	assert.Zero(t, docker.failures)
+}
+
+func TestScrapeServiceConnect(t *testing.T) {
+	dir, err := os.MkdirTemp("", "sc")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, testTaskID), 0755))
+	listener, err := net.Listen("unix", filepath.Join(dir, testTaskID, serviceConnectSocketName))
+	require.NoError(t, err)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stats/prometheus" || r.URL.Query().Get("filter") != "metrics_extension" || r.URL.Query().Has("delta") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `# TYPE RequestCount counter
+RequestCount{DiscoveryName="backend",Direction="egress"} 42
+# TYPE ActiveConnectionCount gauge
+ActiveConnectionCount{DiscoveryName="backend"} 3
+# TYPE RequestLatency histogram
+RequestLatency_bucket{le="+Inf"} 1
+RequestLatency_sum 0.5
+RequestLatency_count 1
+`)
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	s := newTestScraper(t, &fakeDocker{}, func(cfg *Config) {
+		cfg.TaskNetworkMetrics = false
+		cfg.ServiceConnectMetrics = true
+		cfg.ServiceConnectStatusDir = dir
+	})
+	md, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	typ, _ := md.ResourceMetrics().At(0).Resource().Attributes().Get(attributeType)
+	assert.Equal(t, typeServiceConnect, typ.Str())
+	got := metricsByName(md)
+	assert.Len(t, got, 2)
+
+	require.Contains(t, got, "service_connect_RequestCount")
+	requests := got["service_connect_RequestCount"].Sum()
+	assert.True(t, requests.IsMonotonic())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, requests.AggregationTemporality())
+	assert.Equal(t, float64(42), requests.DataPoints().At(0).DoubleValue())
+	assert.Equal(t, map[string]any{"DiscoveryName": "backend", "Direction": "egress"}, requests.DataPoints().At(0).Attributes().AsRaw())
+
+	require.Contains(t, got, "service_connect_ActiveConnectionCount")
+	assert.Equal(t, float64(3), got["service_connect_ActiveConnectionCount"].Gauge().DataPoints().At(0).DoubleValue())
+}
+
+func TestScrapeWithoutServiceConnect(t *testing.T) {
+	s := newTestScraper(t, &fakeDocker{}, func(cfg *Config) {
+		cfg.TaskNetworkMetrics = false
+		cfg.ServiceConnectMetrics = true
+		cfg.ServiceConnectStatusDir = t.TempDir()
+	})
+	md, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, md.ResourceMetrics().Len())
+}
+
+func TestScrapeDockerError(t *testing.T) {
+	s := newScraper(createDefaultConfig().(*Config), zap.NewNop())
+	s.cfg.DockerEndpoint = "unix://" + filepath.Join(t.TempDir(), "docker.sock")
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+	_, err := s.scrape(context.Background())
+	assert.Error(t, err)
+}
+
+func TestGroupTasks(t *testing.T) {
+	tasks := groupTasks([]dockerContainer{
+		{ID: "b1", Labels: map[string]string{labelTaskArn: "arn:aws:ecs:us-east-1:123456789012:task/b", labelCluster: "legacy"}},
+		{ID: "a1", Labels: map[string]string{labelTaskArn: "arn:aws:ecs:us-east-1:123456789012:task/cluster/a"}},
+		{ID: "b2", Labels: map[string]string{labelTaskArn: "arn:aws:ecs:us-east-1:123456789012:task/b"}},
+		{ID: "other"},
+	})
+	// sorted by task ARN
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "b", tasks[0].id)
+	assert.Equal(t, "legacy", tasks[0].cluster)
+	assert.Equal(t, []string{"b1", "b2"}, tasks[0].containers)
+	assert.Equal(t, "a", tasks[1].id)
+	assert.Equal(t, []string{"a1"}, tasks[1].containers)
+}
+
+func TestNewDockerClient(t *testing.T) {
+	_, err := newDockerClient("npipe:////./pipe/docker_engine")
+	assert.Error(t, err)
+	c, err := newDockerClient("tcp://127.0.0.1:2375")
+	require.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:2375", c.baseURL)
+}
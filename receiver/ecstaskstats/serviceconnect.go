@@ -0,0 +1,54 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecstaskstats
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+const (
+	serviceConnectSocketName = "appnet_admin.sock"
+	// serviceConnectStatsPath only returns the stats of the Service Connect
+	// extension. The stats are not requested as deltas, which would reset them
+	// for the ECS agent.
+	serviceConnectStatsPath = "/stats/prometheus?usedonly&filter=metrics_extension"
+)
+
+// serviceConnectSocket returns the admin socket of the Service Connect proxy of
+// the task, or false if the task does not use Service Connect.
+func serviceConnectSocket(statusDir, taskID string) (string, bool) {
+	path := filepath.Join(statusDir, taskID, serviceConnectSocketName)
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// serviceConnectStats reads the stats of the Service Connect proxy served on
+// the admin socket in the Prometheus exposition format.
+func serviceConnectStats(ctx context.Context, socket string) (map[string]*dto.MetricFamily, error) {
+	client := newUnixSocketClient(socket)
+	defer client.CloseIdleConnections()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost"+serviceConnectStatsPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the service connect stats: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to get the service connect stats, status code: %d", resp.StatusCode)
+	}
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(resp.Body)
+}
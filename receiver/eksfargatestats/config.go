@@ -0,0 +1,38 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package eksfargatestats
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type Config struct {
+	scraperhelper.ControllerConfig `mapstructure:",squash"`
+
+	// ClusterName is the name of the EKS cluster. It cannot be detected from
+	// the EC2 tags since Fargate does not have instance metadata.
+	ClusterName string `mapstructure:"cluster_name"`
+	// KubeConfigPath is the kubeconfig used to connect to the API server. The
+	// service account of the pod is used if it is not set.
+	KubeConfigPath string `mapstructure:"kube_config_path"`
+	// NodeSelector is the label selector of the Fargate nodes.
+	NodeSelector string `mapstructure:"node_selector"`
+}
+
+// Verify Config implements Receiver interface.
+var _ component.Config = (*Config)(nil)
+
+func (cfg *Config) Validate() error {
+	if cfg.ClusterName == "" {
+		return errors.New("cluster_name must be set")
+	}
+	if _, err := labels.Parse(cfg.NodeSelector); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package eksfargatestats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+)
+
+const (
+	stability                 = component.StabilityLevelAlpha
+	defaultCollectionInterval = time.Minute
+	// defaultNodeSelector matches the nodes EKS creates for the pods scheduled
+	// on Fargate.
+	defaultNodeSelector = "eks.amazonaws.com/compute-type=fargate"
+)
+
+var (
+	TypeStr, _ = component.NewType("eksfargatestats")
+)
+
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		TypeStr,
+		createDefaultConfig,
+		receiver.WithMetrics(createMetricsReceiver, stability))
+}
+
+func createDefaultConfig() component.Config {
+	cfg := scraperhelper.NewDefaultControllerConfig()
+	cfg.CollectionInterval = defaultCollectionInterval
+	return &Config{
+		ControllerConfig: cfg,
+		NodeSelector:     defaultNodeSelector,
+	}
+}
+
+func createMetricsReceiver(
+	_ context.Context,
+	set receiver.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Metrics,
+) (receiver.Metrics, error) {
+	receiverConfig, ok := cfg.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("configuration parsing error")
+	}
+
+	s := newScraper(receiverConfig, set.Logger)
+	scraper, err := scraperhelper.NewScraper(TypeStr.String(), s.scrape, scraperhelper.WithStart(s.start))
+	if err != nil {
+		return nil, err
+	}
+	return scraperhelper.NewScraperControllerReceiver(&receiverConfig.ControllerConfig, set, nextConsumer, scraperhelper.AddScraper(scraper))
+}
@@ -0,0 +1,48 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package eksfargatestats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	assert.Equal(t, "eksfargatestats", factory.Type().String())
+	cfg := factory.CreateDefaultConfig().(*Config)
+	assert.Equal(t, time.Minute, cfg.CollectionInterval)
+	assert.Equal(t, "eks.amazonaws.com/compute-type=fargate", cfg.NodeSelector)
+	assert.Empty(t, cfg.KubeConfigPath)
+	assert.NoError(t, componenttest.CheckConfigStruct(cfg))
+	assert.EqualError(t, cfg.Validate(), "cluster_name must be set")
+}
+
+func TestValidate(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.ClusterName = "my-cluster"
+	assert.NoError(t, cfg.Validate())
+	cfg.NodeSelector = "compute-type in (fargate"
+	assert.Error(t, cfg.Validate())
+}
+
+func TestCreateMetricsReceiver(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.ClusterName = "my-cluster"
+	receiver, err := factory.CreateMetricsReceiver(context.Background(), receivertest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, receiver)
+	// not running in a pod
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	assert.Error(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, receiver.Shutdown(context.Background()))
+}
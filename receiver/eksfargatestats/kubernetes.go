@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package eksfargatestats
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	stats "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+)
+
+// clusterClient reads the Fargate nodes, the pods and the kubelet stats of the
+// nodes from the API server.
+type clusterClient interface {
+	nodes(ctx context.Context) ([]corev1.Node, error)
+	pods(ctx context.Context) ([]corev1.Pod, error)
+	summary(ctx context.Context, node string) (*stats.Summary, error)
+}
+
+type apiServerClient struct {
+	clientset    kubernetes.Interface
+	nodeSelector string
+}
+
+var _ clusterClient = (*apiServerClient)(nil)
+
+// newAPIServerClient connects to the API server with the kubeconfig, or with
+// the service account of the pod if the path is empty.
+func newAPIServerClient(kubeConfigPath string, nodeSelector string) (*apiServerClient, error) {
+	var (
+		config *rest.Config
+		err    error
+	)
+	if kubeConfigPath == "" {
+		config, err = rest.InClusterConfig()
+	} else {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeConfigPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &apiServerClient{clientset: clientset, nodeSelector: nodeSelector}, nil
+}
+
+func (c *apiServerClient) nodes(ctx context.Context) ([]corev1.Node, error) {
+	list, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: c.nodeSelector})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func (c *apiServerClient) pods(ctx context.Context) ([]corev1.Pod, error) {
+	list, err := c.clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("status.phase", string(corev1.PodRunning)).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// summary gets the stats summary of the kubelet of the node through the proxy
+// of the API server, since the agent does not run on the Fargate nodes.
+func (c *apiServerClient) summary(ctx context.Context, node string) (*stats.Summary, error) {
+	body, err := c.clientset.CoreV1().RESTClient().Get().
+		Resource("nodes").
+		Name(node).
+		SubResource("proxy").
+		Suffix("stats", "summary").
+		DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var summary stats.Summary
+	if err = json.Unmarshal(body, &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
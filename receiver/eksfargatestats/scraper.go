@@ -0,0 +1,342 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package eksfargatestats
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	stats "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+)
+
+const (
+	attributeClusterName   = "ClusterName"
+	attributeNamespace     = "Namespace"
+	attributePodName       = "PodName"
+	attributeFullPodName   = "FullPodName"
+	attributeContainerName = "ContainerName"
+	attributeNodeName      = "NodeName"
+	attributeLaunchType    = "LaunchType"
+	attributeType          = "Type"
+
+	launchTypeFargate = "fargate"
+	typePod           = "Pod"
+	typeContainer     = "Container"
+
+	metricPodCPUUsageTotal                    = "pod_cpu_usage_total"
+	metricPodCPULimit                         = "pod_cpu_limit"
+	metricPodCPUUtilization                   = "pod_cpu_utilization"
+	metricPodCPUUtilizationOverPodLimit       = "pod_cpu_utilization_over_pod_limit"
+	metricPodMemoryWorkingSet                 = "pod_memory_working_set"
+	metricPodMemoryLimit                      = "pod_memory_limit"
+	metricPodMemoryUtilization                = "pod_memory_utilization"
+	metricPodMemoryUtilizationOverPodLimit    = "pod_memory_utilization_over_pod_limit"
+	metricPodNetworkRxBytes                   = "pod_network_rx_bytes"
+	metricPodNetworkTxBytes                   = "pod_network_tx_bytes"
+	metricContainerCPUUsageTotal              = "container_cpu_usage_total"
+	metricContainerCPULimit                   = "container_cpu_limit"
+	metricContainerCPUUtilizationOverLimit    = "container_cpu_utilization_over_container_limit"
+	metricContainerMemoryWorkingSet           = "container_memory_working_set"
+	metricContainerMemoryLimit                = "container_memory_limit"
+	metricContainerMemoryUtilizationOverLimit = "container_memory_utilization_over_container_limit"
+
+	// the CPU metrics in millicores do not have a unit, like Container
+	// Insights on EC2, since CloudWatch does not support it
+	unitNone           = ""
+	unitBytes          = "Bytes"
+	unitBytesPerSecond = "Bytes/Second"
+	unitPercent        = "Percent"
+
+	nanoCoresPerMillicore = 1e6
+)
+
+type networkSample struct {
+	timestamp time.Time
+	rxBytes   uint64
+	txBytes   uint64
+}
+
+// resources are the CPU in millicores and the memory in bytes.
+type resources struct {
+	cpu    float64
+	memory float64
+}
+
+// scraper reports the pod and container metrics of Container Insights for the
+// pods running on Fargate. There is no DaemonSet on Fargate, so the agent runs
+// as a single replica and reads the kubelet stats of every Fargate node
+// through the API server.
+type scraper struct {
+	cfg    *Config
+	logger *zap.Logger
+	client clusterClient
+	// previous holds the network counters of the last scrape by pod UID.
+	previous map[types.UID]networkSample
+}
+
+func newScraper(cfg *Config, logger *zap.Logger) *scraper {
+	return &scraper{cfg: cfg, logger: logger, previous: map[types.UID]networkSample{}}
+}
+
+func (s *scraper) start(context.Context, component.Host) error {
+	client, err := newAPIServerClient(s.cfg.KubeConfigPath, s.cfg.NodeSelector)
+	if err != nil {
+		return err
+	}
+	s.client = client
+	return nil
+}
+
+func (s *scraper) scrape(ctx context.Context) (pmetric.Metrics, error) {
+	md := pmetric.NewMetrics()
+	nodes, err := s.client.nodes(ctx)
+	if err != nil {
+		return md, err
+	}
+	pods, err := s.client.pods(ctx)
+	if err != nil {
+		return md, err
+	}
+	podsByUID := make(map[types.UID]*corev1.Pod, len(pods))
+	for i := range pods {
+		podsByUID[pods[i].UID] = &pods[i]
+	}
+
+	now := time.Now()
+	seen := map[types.UID]struct{}{}
+	for i := range nodes {
+		node := &nodes[i]
+		summary, err := s.client.summary(ctx, node.Name)
+		if err != nil {
+			s.logger.Warn("Unable to get the kubelet stats of the Fargate node", zap.String("node", node.Name), zap.Error(err))
+			continue
+		}
+		for j := range summary.Pods {
+			podStats := &summary.Pods[j]
+			seen[types.UID(podStats.PodRef.UID)] = struct{}{}
+			s.addPodMetrics(md, node, podsByUID[types.UID(podStats.PodRef.UID)], podStats, now)
+		}
+	}
+	// forget the pods which have stopped
+	for uid := range s.previous {
+		if _, ok := seen[uid]; !ok {
+			delete(s.previous, uid)
+		}
+	}
+	return md, nil
+}
+
+// addPodMetrics adds the metrics of the pod and of its containers. The pod
+// can be nil if it stopped after the nodes were listed.
+func (s *scraper) addPodMetrics(md pmetric.Metrics, node *corev1.Node, pod *corev1.Pod, podStats *stats.PodStats, now time.Time) {
+	timestamp := pcommon.NewTimestampFromTime(now)
+	capacity := resources{
+		cpu:    float64(node.Status.Capacity.Cpu().MilliValue()),
+		memory: float64(node.Status.Capacity.Memory().Value()),
+	}
+	// Fargate sizes the node after the requests of the pod, so the capacity of
+	// the node is the limit of a pod without limits
+	limit := podLimits(pod)
+	if limit.cpu == 0 {
+		limit.cpu = capacity.cpu
+	}
+	if limit.memory == 0 {
+		limit.memory = capacity.memory
+	}
+
+	metrics := s.newPodMetrics(md, node, pod, podStats, typePod, "")
+	if usage, ok := cpuUsage(podStats.CPU); ok {
+		addGauge(metrics, metricPodCPUUsageTotal, unitNone, timestamp, usage)
+		addUtilization(metrics, metricPodCPUUtilization, timestamp, usage, capacity.cpu)
+		if limit.cpu > 0 {
+			addGauge(metrics, metricPodCPULimit, unitNone, timestamp, limit.cpu)
+			addUtilization(metrics, metricPodCPUUtilizationOverPodLimit, timestamp, usage, limit.cpu)
+		}
+	}
+	if workingSet, ok := memoryWorkingSet(podStats.Memory); ok {
+		addGauge(metrics, metricPodMemoryWorkingSet, unitBytes, timestamp, workingSet)
+		addUtilization(metrics, metricPodMemoryUtilization, timestamp, workingSet, capacity.memory)
+		if limit.memory > 0 {
+			addGauge(metrics, metricPodMemoryLimit, unitBytes, timestamp, limit.memory)
+			addUtilization(metrics, metricPodMemoryUtilizationOverPodLimit, timestamp, workingSet, limit.memory)
+		}
+	}
+	s.addNetworkRates(metrics, types.UID(podStats.PodRef.UID), podStats.Network, timestamp)
+
+	for i := range podStats.Containers {
+		containerStats := &podStats.Containers[i]
+		limit := containerLimits(pod, containerStats.Name)
+		metrics := s.newPodMetrics(md, node, pod, podStats, typeContainer, containerStats.Name)
+		if usage, ok := cpuUsage(containerStats.CPU); ok {
+			addGauge(metrics, metricContainerCPUUsageTotal, unitNone, timestamp, usage)
+			if limit.cpu > 0 {
+				addGauge(metrics, metricContainerCPULimit, unitNone, timestamp, limit.cpu)
+				addUtilization(metrics, metricContainerCPUUtilizationOverLimit, timestamp, usage, limit.cpu)
+			}
+		}
+		if workingSet, ok := memoryWorkingSet(containerStats.Memory); ok {
+			addGauge(metrics, metricContainerMemoryWorkingSet, unitBytes, timestamp, workingSet)
+			if limit.memory > 0 {
+				addGauge(metrics, metricContainerMemoryLimit, unitBytes, timestamp, limit.memory)
+				addUtilization(metrics, metricContainerMemoryUtilizationOverLimit, timestamp, workingSet, limit.memory)
+			}
+		}
+	}
+}
+
+// addNetworkRates adds the network rates of the pod since the previous scrape.
+func (s *scraper) addNetworkRates(metrics pmetric.MetricSlice, uid types.UID, network *stats.NetworkStats, timestamp pcommon.Timestamp) {
+	if network == nil {
+		return
+	}
+	var current networkSample
+	found := false
+	for _, iface := range network.Interfaces {
+		if iface.RxBytes != nil && iface.TxBytes != nil {
+			current.rxBytes += *iface.RxBytes
+			current.txBytes += *iface.TxBytes
+			found = true
+		}
+	}
+	if !found {
+		return
+	}
+	current.timestamp = network.Time.Time
+	previous, ok := s.previous[uid]
+	s.previous[uid] = current
+	if !ok {
+		return
+	}
+	seconds := current.timestamp.Sub(previous.timestamp).Seconds()
+	// the counters are reset if the pod sandbox is recreated
+	if seconds <= 0 || current.rxBytes < previous.rxBytes || current.txBytes < previous.txBytes {
+		return
+	}
+	addGauge(metrics, metricPodNetworkRxBytes, unitBytesPerSecond, timestamp, float64(current.rxBytes-previous.rxBytes)/seconds)
+	addGauge(metrics, metricPodNetworkTxBytes, unitBytesPerSecond, timestamp, float64(current.txBytes-previous.txBytes)/seconds)
+}
+
+func (s *scraper) newPodMetrics(md pmetric.Metrics, node *corev1.Node, pod *corev1.Pod, podStats *stats.PodStats, metricType string, containerName string) pmetric.MetricSlice {
+	rm := md.ResourceMetrics().AppendEmpty()
+	attrs := rm.Resource().Attributes()
+	attrs.PutStr(attributeClusterName, s.cfg.ClusterName)
+	attrs.PutStr(attributeNamespace, podStats.PodRef.Namespace)
+	attrs.PutStr(attributePodName, workloadName(pod, podStats.PodRef.Name))
+	attrs.PutStr(attributeFullPodName, podStats.PodRef.Name)
+	attrs.PutStr(attributeNodeName, node.Name)
+	attrs.PutStr(attributeLaunchType, launchTypeFargate)
+	attrs.PutStr(attributeType, metricType)
+	if containerName != "" {
+		attrs.PutStr(attributeContainerName, containerName)
+	}
+	return rm.ScopeMetrics().AppendEmpty().Metrics()
+}
+
+// workloadName returns the name of the controller of the pod, like the
+// PodName dimension of Container Insights on EC2. The pods of a deployment are
+// owned by a replica set named after the deployment and a hash of the pod
+// template.
+func workloadName(pod *corev1.Pod, podName string) string {
+	if pod == nil {
+		return podName
+	}
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller == nil || !*owner.Controller {
+			continue
+		}
+		if owner.Kind == "ReplicaSet" {
+			if hash, ok := pod.Labels["pod-template-hash"]; ok {
+				return strings.TrimSuffix(owner.Name, "-"+hash)
+			}
+		}
+		return owner.Name
+	}
+	return podName
+}
+
+// podLimits sums the limits of the containers of the pod. A resource is
+// unlimited, and its limit 0, if any container does not have a limit for it.
+func podLimits(pod *corev1.Pod) resources {
+	if pod == nil || len(pod.Spec.Containers) == 0 {
+		return resources{}
+	}
+	var limit resources
+	cpuLimited, memoryLimited := true, true
+	for _, container := range pod.Spec.Containers {
+		if cpu, ok := container.Resources.Limits[corev1.ResourceCPU]; ok {
+			limit.cpu += float64(cpu.MilliValue())
+		} else {
+			cpuLimited = false
+		}
+		if memory, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
+			limit.memory += float64(memory.Value())
+		} else {
+			memoryLimited = false
+		}
+	}
+	if !cpuLimited {
+		limit.cpu = 0
+	}
+	if !memoryLimited {
+		limit.memory = 0
+	}
+	return limit
+}
+
+func containerLimits(pod *corev1.Pod, name string) resources {
+	if pod == nil {
+		return resources{}
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name != name {
+			continue
+		}
+		var limit resources
+		if cpu, ok := container.Resources.Limits[corev1.ResourceCPU]; ok {
+			limit.cpu = float64(cpu.MilliValue())
+		}
+		if memory, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
+			limit.memory = float64(memory.Value())
+		}
+		return limit
+	}
+	return resources{}
+}
+
+// cpuUsage returns the CPU usage in millicores.
+func cpuUsage(cpu *stats.CPUStats) (float64, bool) {
+	if cpu == nil || cpu.UsageNanoCores == nil {
+		return 0, false
+	}
+	return float64(*cpu.UsageNanoCores) / nanoCoresPerMillicore, true
+}
+
+func memoryWorkingSet(memory *stats.MemoryStats) (float64, bool) {
+	if memory == nil || memory.WorkingSetBytes == nil {
+		return 0, false
+	}
+	return float64(*memory.WorkingSetBytes), true
+}
+
+func addUtilization(metrics pmetric.MetricSlice, name string, timestamp pcommon.Timestamp, value, total float64) {
+	if total > 0 {
+		addGauge(metrics, name, unitPercent, timestamp, value/total*100)
+	}
+}
+
+func addGauge(metrics pmetric.MetricSlice, name, unit string, timestamp pcommon.Timestamp, value float64) {
+	m := metrics.AppendEmpty()
+	m.SetName(name)
+	m.SetUnit(unit)
+	dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(timestamp)
+	dp.SetDoubleValue(value)
+}
@@ -0,0 +1,222 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package eksfargatestats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	stats "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+)
+
+const (
+	testNodeName = "fargate-ip-10-0-0-1.ec2.internal"
+	testPodName  = "web-7d9f8c-abcde"
+	testPodUID   = "0b7c5c3e-1d1f-4f0c-9e3b-7a7d1c1b2a3f"
+)
+
+type mockClient struct {
+	summaries map[string]*stats.Summary
+}
+
+func (m *mockClient) nodes(context.Context) ([]corev1.Node, error) {
+	return []corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: testNodeName},
+			Status: corev1.NodeStatus{Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			}},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "fargate-unreachable"}},
+	}, nil
+}
+
+func (m *mockClient) pods(context.Context) ([]corev1.Pod, error) {
+	controller := true
+	return []corev1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testPodName,
+			Namespace: "default",
+			UID:       testPodUID,
+			Labels:    map[string]string{"pod-template-hash": "7d9f8c"},
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "web-7d9f8c", Controller: &controller},
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: testNodeName,
+			Containers: []corev1.Container{
+				{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					}},
+				},
+				{Name: "sidecar"},
+			},
+		},
+	}}, nil
+}
+
+func (m *mockClient) summary(_ context.Context, node string) (*stats.Summary, error) {
+	summary, ok := m.summaries[node]
+	if !ok {
+		return nil, errors.New("the server is currently unable to handle the request")
+	}
+	return summary, nil
+}
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
+
+func testSummary(read time.Time, rxBytes, txBytes uint64) *stats.Summary {
+	return &stats.Summary{
+		Pods: []stats.PodStats{{
+			PodRef: stats.PodReference{Name: testPodName, Namespace: "default", UID: testPodUID},
+			CPU:    &stats.CPUStats{UsageNanoCores: uint64Ptr(500_000_000)},
+			Memory: &stats.MemoryStats{WorkingSetBytes: uint64Ptr(1 << 30)},
+			Network: &stats.NetworkStats{
+				Time: metav1.NewTime(read),
+				Interfaces: []stats.InterfaceStats{
+					{Name: "eth0", RxBytes: uint64Ptr(rxBytes), TxBytes: uint64Ptr(txBytes)},
+				},
+			},
+			Containers: []stats.ContainerStats{
+				{
+					Name:   "app",
+					CPU:    &stats.CPUStats{UsageNanoCores: uint64Ptr(250_000_000)},
+					Memory: &stats.MemoryStats{WorkingSetBytes: uint64Ptr(512 << 20)},
+				},
+				{
+					Name:   "sidecar",
+					CPU:    &stats.CPUStats{UsageNanoCores: uint64Ptr(250_000_000)},
+					Memory: &stats.MemoryStats{},
+				},
+			},
+		}},
+	}
+}
+
+// collect returns the values of the metrics by the Type and the
+// ContainerName of their resource.
+func collect(t *testing.T, md pmetric.Metrics) map[string]map[string]float64 {
+	t.Helper()
+	result := map[string]map[string]float64{}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		attrs := rm.Resource().Attributes().AsRaw()
+		assert.Equal(t, "my-cluster", attrs[attributeClusterName])
+		assert.Equal(t, "default", attrs[attributeNamespace])
+		assert.Equal(t, "web", attrs[attributePodName])
+		assert.Equal(t, testPodName, attrs[attributeFullPodName])
+		assert.Equal(t, testNodeName, attrs[attributeNodeName])
+		assert.Equal(t, "fargate", attrs[attributeLaunchType])
+		key := attrs[attributeType].(string)
+		if name, ok := attrs[attributeContainerName]; ok {
+			key += "/" + name.(string)
+		}
+		values := map[string]float64{}
+		metrics := rm.ScopeMetrics().At(0).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			values[metrics.At(j).Name()] = metrics.At(j).Gauge().DataPoints().At(0).DoubleValue()
+		}
+		result[key] = values
+	}
+	return result
+}
+
+func TestScrape(t *testing.T) {
+	now := time.Now()
+	client := &mockClient{summaries: map[string]*stats.Summary{testNodeName: testSummary(now, 1000, 500)}}
+	s := newScraper(&Config{ClusterName: "my-cluster"}, zap.NewNop())
+	s.client = client
+
+	md, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	got := collect(t, md)
+	assert.Equal(t, map[string]map[string]float64{
+		"Pod": {
+			metricPodCPUUsageTotal:                 500,
+			metricPodCPUUtilization:                25,
+			metricPodCPULimit:                      2000,
+			metricPodCPUUtilizationOverPodLimit:    25,
+			metricPodMemoryWorkingSet:              1 << 30,
+			metricPodMemoryUtilization:             25,
+			metricPodMemoryLimit:                   4 << 30,
+			metricPodMemoryUtilizationOverPodLimit: 25,
+		},
+		"Container/app": {
+			metricContainerCPUUsageTotal:              250,
+			metricContainerCPULimit:                   500,
+			metricContainerCPUUtilizationOverLimit:    50,
+			metricContainerMemoryWorkingSet:           512 << 20,
+			metricContainerMemoryLimit:                1 << 30,
+			metricContainerMemoryUtilizationOverLimit: 50,
+		},
+		"Container/sidecar": {
+			metricContainerCPUUsageTotal: 250,
+		},
+	}, got)
+
+	// the network rates are reported from the second scrape
+	client.summaries[testNodeName] = testSummary(now.Add(10*time.Second), 3000, 1500)
+	md, err = s.scrape(context.Background())
+	require.NoError(t, err)
+	got = collect(t, md)
+	assert.Equal(t, float64(200), got["Pod"][metricPodNetworkRxBytes])
+	assert.Equal(t, float64(100), got["Pod"][metricPodNetworkTxBytes])
+
+	// the pod stopped
+	client.summaries[testNodeName] = &stats.Summary{}
+	md, err = s.scrape(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, md.ResourceMetrics().Len())
+	assert.Empty(t, s.previous)
+}
+
+func TestWorkloadName(t *testing.T) {
+	controller := true
+	testCases := map[string]struct {
+		pod  *corev1.Pod
+		want string
+	}{
+		"WithoutPod": {
+			want: "my-pod",
+		},
+		"WithoutOwner": {
+			pod:  &corev1.Pod{},
+			want: "my-pod",
+		},
+		"WithDeployment": {
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Labels:          map[string]string{"pod-template-hash": "5d4f7b"},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "coredns-5d4f7b", Controller: &controller}},
+			}},
+			want: "coredns",
+		},
+		"WithJob": {
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: "backup-28391", Controller: &controller}},
+			}},
+			want: "backup-28391",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, workloadName(testCase.pod, "my-pod"))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selftelemetry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/provider"
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+const (
+	stability = component.StabilityLevelAlpha
+	// defaultCollectionInterval matches the refresh interval of the process
+	// stats of the agenthealth extension.
+	defaultCollectionInterval = time.Minute
+)
+
+var (
+	TypeStr, _ = component.NewType("selftelemetry")
+)
+
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		TypeStr,
+		createDefaultConfig,
+		receiver.WithMetrics(createMetricsReceiver, stability))
+}
+
+func createDefaultConfig() component.Config {
+	cfg := scraperhelper.NewDefaultControllerConfig()
+	cfg.CollectionInterval = defaultCollectionInterval
+	return &Config{ControllerConfig: cfg}
+}
+
+func createMetricsReceiver(
+	_ context.Context,
+	set receiver.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Metrics,
+) (receiver.Metrics, error) {
+	receiverConfig, ok := cfg.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("configuration parsing error")
+	}
+
+	s := newScraper(selftelemetry.Default, provider.GetProcessStats())
+	scraper, err := scraperhelper.NewScraper(TypeStr.String(), s.scrape, scraperhelper.WithStart(s.start))
+	if err != nil {
+		return nil, err
+	}
+	return scraperhelper.NewScraperControllerReceiver(&receiverConfig.ControllerConfig, set, nextConsumer, scraperhelper.AddScraper(scraper))
+}
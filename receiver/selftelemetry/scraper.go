@@ -0,0 +1,128 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selftelemetry
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/agent"
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+const (
+	attributeHost      = "host"
+	attributeComponent = "component"
+
+	metricCPUUsage        = "cpu_usage_percent"
+	metricMemoryRSS       = "memory_rss_bytes"
+	metricFileDescriptors = "file_descriptors"
+	metricThreads         = "threads"
+	metricGoroutines      = "goroutines"
+
+	unitPercent = "Percent"
+	unitBytes   = "Bytes"
+	unitCount   = "Count"
+	unitSeconds = "Seconds"
+	unitNone    = "None"
+)
+
+var sampleUnits = map[string]string{
+	selftelemetry.MetricDroppedEvents:          unitCount,
+	selftelemetry.MetricBufferUtilization:      unitPercent,
+	selftelemetry.MetricThrottledBatches:       unitCount,
+	selftelemetry.MetricDeferredBatches:        unitCount,
+	selftelemetry.MetricClampedDatapoints:      unitCount,
+	selftelemetry.MetricExportedBatches:        unitCount,
+	selftelemetry.MetricInvalidEMFRecords:      unitCount,
+	selftelemetry.MetricSampledLogEvents:       unitCount,
+	selftelemetry.MetricTruncatedLogEvents:     unitCount,
+	selftelemetry.MetricSplitLogEvents:         unitCount,
+	selftelemetry.MetricSanitizationFixes:      unitCount,
+	selftelemetry.MetricSanitizationRejections: unitCount,
+	selftelemetry.MetricClockSkew:              unitSeconds,
+}
+
+// scraper converts the process stats of the agenthealth extension and the
+// samples of the self telemetry registry to metrics.
+type scraper struct {
+	registry  *selftelemetry.Registry
+	process   agent.StatsProvider
+	hostname  string
+	startTime pcommon.Timestamp
+}
+
+func newScraper(registry *selftelemetry.Registry, process agent.StatsProvider) *scraper {
+	return &scraper{registry: registry, process: process}
+}
+
+func (s *scraper) start(context.Context, component.Host) error {
+	s.hostname, _ = os.Hostname()
+	s.startTime = pcommon.NewTimestampFromTime(time.Now())
+	return nil
+}
+
+func (s *scraper) scrape(context.Context) (pmetric.Metrics, error) {
+	now := pcommon.NewTimestampFromTime(time.Now())
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	if s.hostname != "" {
+		rm.Resource().Attributes().PutStr(attributeHost, s.hostname)
+	}
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	stats := s.process.Stats("")
+	if stats.CPUPercent != nil {
+		addGauge(metrics, metricCPUUsage, unitPercent, now, *stats.CPUPercent)
+	}
+	if stats.MemoryBytes != nil {
+		addGauge(metrics, metricMemoryRSS, unitBytes, now, float64(*stats.MemoryBytes))
+	}
+	if stats.FileDescriptorCount != nil {
+		addGauge(metrics, metricFileDescriptors, unitCount, now, float64(*stats.FileDescriptorCount))
+	}
+	if stats.ThreadCount != nil {
+		addGauge(metrics, metricThreads, unitCount, now, float64(*stats.ThreadCount))
+	}
+	addGauge(metrics, metricGoroutines, unitCount, now, float64(runtime.NumGoroutine()))
+
+	for _, sample := range s.registry.Collect() {
+		var dp pmetric.NumberDataPoint
+		m := metrics.AppendEmpty()
+		m.SetName(sample.Name)
+		if unit, ok := sampleUnits[sample.Name]; ok {
+			m.SetUnit(unit)
+		} else {
+			m.SetUnit(unitNone)
+		}
+		if sample.Cumulative {
+			sum := m.SetEmptySum()
+			sum.SetIsMonotonic(true)
+			sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			dp = sum.DataPoints().AppendEmpty()
+			dp.SetStartTimestamp(s.startTime)
+		} else {
+			dp = m.SetEmptyGauge().DataPoints().AppendEmpty()
+		}
+		dp.SetTimestamp(now)
+		dp.SetDoubleValue(sample.Value)
+		dp.Attributes().PutStr(attributeComponent, sample.Component)
+	}
+	return md, nil
+}
+
+func addGauge(metrics pmetric.MetricSlice, name, unit string, timestamp pcommon.Timestamp, value float64) {
+	m := metrics.AppendEmpty()
+	m.SetName(name)
+	m.SetUnit(unit)
+	dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(timestamp)
+	dp.SetDoubleValue(value)
+}
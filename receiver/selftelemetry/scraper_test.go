@@ -0,0 +1,84 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selftelemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/agent"
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
+)
+
+type mockStatsProvider struct {
+	stats agent.Stats
+}
+
+func (m *mockStatsProvider) Stats(string) agent.Stats {
+	return m.stats
+}
+
+func TestScrape(t *testing.T) {
+	registry := selftelemetry.NewRegistry()
+	registry.Add(selftelemetry.MetricDroppedEvents, "cloudwatchlogs", 3)
+	registry.RegisterGauge(selftelemetry.MetricBufferUtilization, "cloudwatch", func() float64 { return 50 })
+	process := &mockStatsProvider{stats: agent.Stats{
+		CPUPercent:          aws.Float64(1.5),
+		MemoryBytes:         aws.Uint64(1024),
+		FileDescriptorCount: aws.Int32(12),
+		ThreadCount:         aws.Int32(8),
+	}}
+	s := newScraper(registry, process)
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+
+	md, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+
+	got := map[string]pmetric.Metric{}
+	for i := 0; i < metrics.Len(); i++ {
+		got[metrics.At(i).Name()] = metrics.At(i)
+	}
+	assert.Len(t, got, 7)
+	for name, want := range map[string]float64{
+		metricCPUUsage:        1.5,
+		metricMemoryRSS:       1024,
+		metricFileDescriptors: 12,
+		metricThreads:         8,
+	} {
+		require.Contains(t, got, name)
+		assert.Equal(t, want, got[name].Gauge().DataPoints().At(0).DoubleValue(), name)
+	}
+	assert.Contains(t, got, metricGoroutines)
+
+	dropped := got[selftelemetry.MetricDroppedEvents]
+	assert.Equal(t, pmetric.MetricTypeSum, dropped.Type())
+	assert.True(t, dropped.Sum().IsMonotonic())
+	assert.Equal(t, unitCount, dropped.Unit())
+	dp := dropped.Sum().DataPoints().At(0)
+	assert.Equal(t, 3.0, dp.DoubleValue())
+	component, _ := dp.Attributes().Get(attributeComponent)
+	assert.Equal(t, "cloudwatchlogs", component.Str())
+
+	utilization := got[selftelemetry.MetricBufferUtilization]
+	assert.Equal(t, pmetric.MetricTypeGauge, utilization.Type())
+	assert.Equal(t, unitPercent, utilization.Unit())
+	assert.Equal(t, 50.0, utilization.Gauge().DataPoints().At(0).DoubleValue())
+}
+
+func TestScrapeWithoutProcessStats(t *testing.T) {
+	s := newScraper(selftelemetry.NewRegistry(), &mockStatsProvider{})
+	md, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	assert.Equal(t, metricGoroutines, metrics.At(0).Name())
+}
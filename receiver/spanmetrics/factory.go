@@ -0,0 +1,58 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package spanmetrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/spanmetrics"
+)
+
+const (
+	stability                 = component.StabilityLevelAlpha
+	defaultCollectionInterval = time.Minute
+)
+
+var (
+	TypeStr, _ = component.NewType("spanmetrics")
+)
+
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		TypeStr,
+		createDefaultConfig,
+		receiver.WithMetrics(createMetricsReceiver, stability))
+}
+
+func createDefaultConfig() component.Config {
+	cfg := scraperhelper.NewDefaultControllerConfig()
+	cfg.CollectionInterval = defaultCollectionInterval
+	return &Config{ControllerConfig: cfg}
+}
+
+func createMetricsReceiver(
+	_ context.Context,
+	set receiver.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Metrics,
+) (receiver.Metrics, error) {
+	receiverConfig, ok := cfg.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("configuration parsing error")
+	}
+
+	s := newScraper(spanmetrics.Default)
+	scraper, err := scraperhelper.NewScraper(TypeStr.String(), s.scrape, scraperhelper.WithStart(s.start))
+	if err != nil {
+		return nil, err
+	}
+	return scraperhelper.NewScraperControllerReceiver(&receiverConfig.ControllerConfig, set, nextConsumer, scraperhelper.AddScraper(scraper))
+}
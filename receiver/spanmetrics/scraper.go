@@ -0,0 +1,111 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package spanmetrics
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/spanmetrics"
+)
+
+const (
+	// attributeHost is only a resource attribute, for the log stream name of
+	// the EMF logs, so that the metrics of the hosts are not split.
+	attributeHost = "host"
+
+	metricCalls    = "calls"
+	metricErrors   = "errors"
+	metricDuration = "duration"
+
+	unitCount        = "Count"
+	unitMilliseconds = "Milliseconds"
+)
+
+// scraper converts the series of the span metrics registry to delta metrics.
+// The registry is reset by every scrape, so each scrape covers the spans
+// since the previous one.
+type scraper struct {
+	registry   *spanmetrics.Registry
+	hostname   string
+	lastScrape pcommon.Timestamp
+}
+
+func newScraper(registry *spanmetrics.Registry) *scraper {
+	return &scraper{registry: registry}
+}
+
+func (s *scraper) start(context.Context, component.Host) error {
+	s.hostname, _ = os.Hostname()
+	s.lastScrape = pcommon.NewTimestampFromTime(time.Now())
+	return nil
+}
+
+func (s *scraper) scrape(context.Context) (pmetric.Metrics, error) {
+	now := pcommon.NewTimestampFromTime(time.Now())
+	start := s.lastScrape
+	s.lastScrape = now
+	md := pmetric.NewMetrics()
+	series := s.registry.Collect()
+	if len(series) == 0 {
+		return md, nil
+	}
+	rm := md.ResourceMetrics().AppendEmpty()
+	if s.hostname != "" {
+		rm.Resource().Attributes().PutStr(attributeHost, s.hostname)
+	}
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+	calls := newDeltaSum(metrics, metricCalls)
+	errors := newDeltaSum(metrics, metricErrors)
+	duration := metrics.AppendEmpty()
+	duration.SetName(metricDuration)
+	duration.SetUnit(unitMilliseconds)
+	histogram := duration.SetEmptyHistogram()
+	histogram.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+
+	for _, ser := range series {
+		addSumDataPoint(calls, ser, start, now, ser.Calls)
+		addSumDataPoint(errors, ser, start, now, ser.Errors)
+		dp := histogram.DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(start)
+		dp.SetTimestamp(now)
+		dp.SetCount(ser.Calls)
+		dp.SetSum(ser.Sum)
+		dp.SetMin(ser.Min)
+		dp.SetMax(ser.Max)
+		dp.ExplicitBounds().FromRaw(ser.Bounds)
+		dp.BucketCounts().FromRaw(ser.BucketCounts)
+		putDimensions(dp.Attributes(), ser)
+	}
+	return md, nil
+}
+
+func newDeltaSum(metrics pmetric.MetricSlice, name string) pmetric.Sum {
+	m := metrics.AppendEmpty()
+	m.SetName(name)
+	m.SetUnit(unitCount)
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	return sum
+}
+
+func addSumDataPoint(sum pmetric.Sum, ser *spanmetrics.Series, start, now pcommon.Timestamp, value uint64) {
+	dp := sum.DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(now)
+	dp.SetIntValue(int64(value))
+	putDimensions(dp.Attributes(), ser)
+}
+
+func putDimensions(attrs pcommon.Map, ser *spanmetrics.Series) {
+	for _, d := range ser.Dimensions {
+		attrs.PutStr(d.Name, d.Value)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package spanmetrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/spanmetrics"
+)
+
+func TestScrape(t *testing.T) {
+	registry := spanmetrics.NewRegistry()
+	aggregator := spanmetrics.NewAggregator([]float64{10, 100})
+	registry.Register(aggregator)
+	s := newScraper(registry)
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+
+	md, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, md.MetricCount())
+
+	dimensions := []spanmetrics.Dimension{{Name: spanmetrics.DimensionServiceName, Value: "shop"}, {Name: spanmetrics.DimensionSpanName, Value: "GET /cart"}}
+	aggregator.Record(dimensions, 5, false)
+	aggregator.Record(dimensions, 200, true)
+	md, err = s.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 3, metrics.Len())
+
+	calls := metrics.At(0)
+	assert.Equal(t, "calls", calls.Name())
+	assert.Equal(t, "Count", calls.Unit())
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, calls.Sum().AggregationTemporality())
+	assert.EqualValues(t, 2, calls.Sum().DataPoints().At(0).IntValue())
+	assert.Equal(t, map[string]any{"service.name": "shop", "span.name": "GET /cart"}, calls.Sum().DataPoints().At(0).Attributes().AsRaw())
+
+	errors := metrics.At(1)
+	assert.Equal(t, "errors", errors.Name())
+	assert.EqualValues(t, 1, errors.Sum().DataPoints().At(0).IntValue())
+
+	duration := metrics.At(2)
+	assert.Equal(t, "duration", duration.Name())
+	assert.Equal(t, "Milliseconds", duration.Unit())
+	dp := duration.Histogram().DataPoints().At(0)
+	assert.EqualValues(t, 2, dp.Count())
+	assert.Equal(t, 205.0, dp.Sum())
+	assert.Equal(t, 5.0, dp.Min())
+	assert.Equal(t, 200.0, dp.Max())
+	assert.Equal(t, []float64{10, 100}, dp.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{1, 0, 1}, dp.BucketCounts().AsRaw())
+	assert.Equal(t, calls.Sum().DataPoints().At(0).Timestamp(), dp.Timestamp())
+
+	// each scrape only has the spans since the previous one
+	aggregator.Record(dimensions, 1, false)
+	md, err = s.scrape(context.Background())
+	require.NoError(t, err)
+	next := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0)
+	assert.EqualValues(t, 1, next.IntValue())
+	assert.Equal(t, dp.Timestamp(), next.StartTimestamp())
+}
@@ -2987,6 +2987,98 @@ func (c *CloudWatchLogs) DescribeExportTasksWithContext(ctx aws.Context, input *
 	return out, req.Send()
 }
 
+const opDescribeIndexPolicies = "DescribeIndexPolicies"
+
+// DescribeIndexPoliciesRequest generates a "aws/request.Request" representing the
+// client's request for the DescribeIndexPolicies operation. The "output" return
+// value will be populated with the request's response once the request completes
+// successfully.
+//
+// Use "Send" method on the returned Request to send the API call to the service.
+// the "output" return value is not valid until after Send returns without error.
+//
+// See DescribeIndexPolicies for more information on using the DescribeIndexPolicies
+// API call, and error handling.
+//
+// This method is useful when you want to inject custom logic or configuration
+// into the SDK's request lifecycle. Such as custom headers, or retry logic.
+//
+//	// Example sending a request using the DescribeIndexPoliciesRequest method.
+//	req, resp := client.DescribeIndexPoliciesRequest(params)
+//
+//	err := req.Send()
+//	if err == nil { // resp is now filled
+//	    fmt.Println(resp)
+//	}
+//
+// See also, https://docs.aws.amazon.com/goto/WebAPI/logs-2014-03-28/DescribeIndexPolicies
+func (c *CloudWatchLogs) DescribeIndexPoliciesRequest(input *DescribeIndexPoliciesInput) (req *request.Request, output *DescribeIndexPoliciesOutput) {
+	op := &request.Operation{
+		Name:       opDescribeIndexPolicies,
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+
+	if input == nil {
+		input = &DescribeIndexPoliciesInput{}
+	}
+
+	output = &DescribeIndexPoliciesOutput{}
+	req = c.newRequest(op, input, output)
+	return
+}
+
+// DescribeIndexPolicies API operation for Amazon CloudWatch Logs.
+//
+// Returns the field index policies of the specified log group. The policy of
+// the account is returned if the log group does not have its own policy.
+//
+// Returns awserr.Error for service API and SDK errors. Use runtime type assertions
+// with awserr.Error's Code and Message methods to get detailed information about
+// the error.
+//
+// See the AWS API reference guide for Amazon CloudWatch Logs's
+// API operation DescribeIndexPolicies for usage and error information.
+//
+// Returned Error Types:
+//
+//   - InvalidParameterException
+//     A parameter is specified incorrectly.
+//
+//   - ResourceNotFoundException
+//     The specified resource does not exist.
+//
+//   - LimitExceededException
+//     You have reached the maximum number of resources that can be created.
+//
+//   - OperationAbortedException
+//     Multiple concurrent requests to update the same resource were in conflict.
+//
+//   - ServiceUnavailableException
+//     The service cannot complete the request.
+//
+// See also, https://docs.aws.amazon.com/goto/WebAPI/logs-2014-03-28/DescribeIndexPolicies
+func (c *CloudWatchLogs) DescribeIndexPolicies(input *DescribeIndexPoliciesInput) (*DescribeIndexPoliciesOutput, error) {
+	req, out := c.DescribeIndexPoliciesRequest(input)
+	return out, req.Send()
+}
+
+// DescribeIndexPoliciesWithContext is the same as DescribeIndexPolicies with the addition of
+// the ability to pass a context and additional request options.
+//
+// See DescribeIndexPolicies for details on how to use this API operation.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (c *CloudWatchLogs) DescribeIndexPoliciesWithContext(ctx aws.Context, input *DescribeIndexPoliciesInput, opts ...request.Option) (*DescribeIndexPoliciesOutput, error) {
+	req, out := c.DescribeIndexPoliciesRequest(input)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return out, req.Send()
+}
+
 const opDescribeLogGroups = "DescribeLogGroups"
 
 // DescribeLogGroupsRequest generates a "aws/request.Request" representing the
@@ -6440,6 +6532,102 @@ func (c *CloudWatchLogs) PutDestinationPolicyWithContext(ctx aws.Context, input
 	return out, req.Send()
 }
 
+const opPutIndexPolicy = "PutIndexPolicy"
+
+// PutIndexPolicyRequest generates a "aws/request.Request" representing the
+// client's request for the PutIndexPolicy operation. The "output" return
+// value will be populated with the request's response once the request completes
+// successfully.
+//
+// Use "Send" method on the returned Request to send the API call to the service.
+// the "output" return value is not valid until after Send returns without error.
+//
+// See PutIndexPolicy for more information on using the PutIndexPolicy
+// API call, and error handling.
+//
+// This method is useful when you want to inject custom logic or configuration
+// into the SDK's request lifecycle. Such as custom headers, or retry logic.
+//
+//	// Example sending a request using the PutIndexPolicyRequest method.
+//	req, resp := client.PutIndexPolicyRequest(params)
+//
+//	err := req.Send()
+//	if err == nil { // resp is now filled
+//	    fmt.Println(resp)
+//	}
+//
+// See also, https://docs.aws.amazon.com/goto/WebAPI/logs-2014-03-28/PutIndexPolicy
+func (c *CloudWatchLogs) PutIndexPolicyRequest(input *PutIndexPolicyInput) (req *request.Request, output *PutIndexPolicyOutput) {
+	op := &request.Operation{
+		Name:       opPutIndexPolicy,
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+
+	if input == nil {
+		input = &PutIndexPolicyInput{}
+	}
+
+	output = &PutIndexPolicyOutput{}
+	req = c.newRequest(op, input, output)
+	return
+}
+
+// PutIndexPolicy API operation for Amazon CloudWatch Logs.
+//
+// Creates or updates a field index policy for the specified log group. The
+// fields of the policy are indexed in the log events ingested after the policy
+// is created, which makes the queries filtering on them scan fewer events.
+//
+// A log group can have only one field index policy, which overrides the policy
+// of the account. A policy can index up to 20 fields.
+//
+// Returns awserr.Error for service API and SDK errors. Use runtime type assertions
+// with awserr.Error's Code and Message methods to get detailed information about
+// the error.
+//
+// See the AWS API reference guide for Amazon CloudWatch Logs's
+// API operation PutIndexPolicy for usage and error information.
+//
+// Returned Error Types:
+//
+//   - InvalidParameterException
+//     A parameter is specified incorrectly.
+//
+//   - LimitExceededException
+//     You have reached the maximum number of resources that can be created.
+//
+//   - OperationAbortedException
+//     Multiple concurrent requests to update the same resource were in conflict.
+//
+//   - ResourceNotFoundException
+//     The specified resource does not exist.
+//
+//   - ServiceUnavailableException
+//     The service cannot complete the request.
+//
+// See also, https://docs.aws.amazon.com/goto/WebAPI/logs-2014-03-28/PutIndexPolicy
+func (c *CloudWatchLogs) PutIndexPolicy(input *PutIndexPolicyInput) (*PutIndexPolicyOutput, error) {
+	req, out := c.PutIndexPolicyRequest(input)
+	return out, req.Send()
+}
+
+// PutIndexPolicyWithContext is the same as PutIndexPolicy with the addition of
+// the ability to pass a context and additional request options.
+//
+// See PutIndexPolicy for details on how to use this API operation.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (c *CloudWatchLogs) PutIndexPolicyWithContext(ctx aws.Context, input *PutIndexPolicyInput, opts ...request.Option) (*PutIndexPolicyOutput, error) {
+	req, out := c.PutIndexPolicyRequest(input)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return out, req.Send()
+}
+
 const opPutLogEvents = "PutLogEvents"
 
 // PutLogEventsRequest generates a "aws/request.Request" representing the
@@ -11927,6 +12115,110 @@ func (s *DescribeExportTasksOutput) SetNextToken(v string) *DescribeExportTasksO
 	return s
 }
 
+type DescribeIndexPoliciesInput struct {
+	_ struct{} `type:"structure"`
+
+	// The name or ARN of the log group to return the index policy of. Only one
+	// log group can be specified.
+	//
+	// LogGroupIdentifiers is a required field
+	LogGroupIdentifiers []*string `locationName:"logGroupIdentifiers" min:"1" type:"list" required:"true"`
+
+	// The token for the next set of items to return. (You received this token
+	// from a previous call.)
+	NextToken *string `locationName:"nextToken" min:"1" type:"string"`
+}
+
+// String returns the string representation.
+//
+// API parameter values that are decorated as "sensitive" in the API will not
+// be included in the string output. The member name will be present, but the
+// value will be replaced with "sensitive".
+func (s DescribeIndexPoliciesInput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation.
+//
+// API parameter values that are decorated as "sensitive" in the API will not
+// be included in the string output. The member name will be present, but the
+// value will be replaced with "sensitive".
+func (s DescribeIndexPoliciesInput) GoString() string {
+	return s.String()
+}
+
+// Validate inspects the fields of the type to determine if they are valid.
+func (s *DescribeIndexPoliciesInput) Validate() error {
+	invalidParams := request.ErrInvalidParams{Context: "DescribeIndexPoliciesInput"}
+	if s.LogGroupIdentifiers == nil {
+		invalidParams.Add(request.NewErrParamRequired("LogGroupIdentifiers"))
+	}
+	if s.LogGroupIdentifiers != nil && len(s.LogGroupIdentifiers) < 1 {
+		invalidParams.Add(request.NewErrParamMinLen("LogGroupIdentifiers", 1))
+	}
+	if s.NextToken != nil && len(*s.NextToken) < 1 {
+		invalidParams.Add(request.NewErrParamMinLen("NextToken", 1))
+	}
+
+	if invalidParams.Len() > 0 {
+		return invalidParams
+	}
+	return nil
+}
+
+// SetLogGroupIdentifiers sets the LogGroupIdentifiers field's value.
+func (s *DescribeIndexPoliciesInput) SetLogGroupIdentifiers(v []*string) *DescribeIndexPoliciesInput {
+	s.LogGroupIdentifiers = v
+	return s
+}
+
+// SetNextToken sets the NextToken field's value.
+func (s *DescribeIndexPoliciesInput) SetNextToken(v string) *DescribeIndexPoliciesInput {
+	s.NextToken = &v
+	return s
+}
+
+type DescribeIndexPoliciesOutput struct {
+	_ struct{} `type:"structure"`
+
+	// The index policies of the log group.
+	IndexPolicies []*IndexPolicy `locationName:"indexPolicies" type:"list"`
+
+	// The token for the next set of items to return. The token expires after 24
+	// hours.
+	NextToken *string `locationName:"nextToken" min:"1" type:"string"`
+}
+
+// String returns the string representation.
+//
+// API parameter values that are decorated as "sensitive" in the API will not
+// be included in the string output. The member name will be present, but the
+// value will be replaced with "sensitive".
+func (s DescribeIndexPoliciesOutput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation.
+//
+// API parameter values that are decorated as "sensitive" in the API will not
+// be included in the string output. The member name will be present, but the
+// value will be replaced with "sensitive".
+func (s DescribeIndexPoliciesOutput) GoString() string {
+	return s.String()
+}
+
+// SetIndexPolicies sets the IndexPolicies field's value.
+func (s *DescribeIndexPoliciesOutput) SetIndexPolicies(v []*IndexPolicy) *DescribeIndexPoliciesOutput {
+	s.IndexPolicies = v
+	return s
+}
+
+// SetNextToken sets the NextToken field's value.
+func (s *DescribeIndexPoliciesOutput) SetNextToken(v string) *DescribeIndexPoliciesOutput {
+	s.NextToken = &v
+	return s
+}
+
 type DescribeLogGroupsInput struct {
 	_ struct{} `type:"structure"`
 
@@ -14723,6 +15015,76 @@ func (s *GetQueryResultsOutput) SetStatus(v string) *GetQueryResultsOutput {
 	return s
 }
 
+// A field index policy of a log group or of the account.
+type IndexPolicy struct {
+	_ struct{} `type:"structure"`
+
+	// The time the policy was last updated, expressed as the number of milliseconds
+	// after Jan 1, 1970 00:00:00 UTC.
+	LastUpdateTime *int64 `locationName:"lastUpdateTime" type:"long"`
+
+	// The ARN of the log group of the policy.
+	LogGroupIdentifier *string `locationName:"logGroupIdentifier" min:"1" type:"string"`
+
+	// The policy document, a JSON object with the indexed fields in the Fields
+	// array, e.g. {"Fields": ["RequestId", "TransactionId"]}.
+	PolicyDocument *string `locationName:"policyDocument" type:"string"`
+
+	// The name of the policy.
+	PolicyName *string `locationName:"policyName" type:"string"`
+
+	// Whether the policy applies to the account or only to the log group.
+	Source *string `locationName:"source" type:"string" enum:"IndexSource"`
+}
+
+// String returns the string representation.
+//
+// API parameter values that are decorated as "sensitive" in the API will not
+// be included in the string output. The member name will be present, but the
+// value will be replaced with "sensitive".
+func (s IndexPolicy) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation.
+//
+// API parameter values that are decorated as "sensitive" in the API will not
+// be included in the string output. The member name will be present, but the
+// value will be replaced with "sensitive".
+func (s IndexPolicy) GoString() string {
+	return s.String()
+}
+
+// SetLastUpdateTime sets the LastUpdateTime field's value.
+func (s *IndexPolicy) SetLastUpdateTime(v int64) *IndexPolicy {
+	s.LastUpdateTime = &v
+	return s
+}
+
+// SetLogGroupIdentifier sets the LogGroupIdentifier field's value.
+func (s *IndexPolicy) SetLogGroupIdentifier(v string) *IndexPolicy {
+	s.LogGroupIdentifier = &v
+	return s
+}
+
+// SetPolicyDocument sets the PolicyDocument field's value.
+func (s *IndexPolicy) SetPolicyDocument(v string) *IndexPolicy {
+	s.PolicyDocument = &v
+	return s
+}
+
+// SetPolicyName sets the PolicyName field's value.
+func (s *IndexPolicy) SetPolicyName(v string) *IndexPolicy {
+	s.PolicyName = &v
+	return s
+}
+
+// SetSource sets the Source field's value.
+func (s *IndexPolicy) SetSource(v string) *IndexPolicy {
+	s.Source = &v
+	return s
+}
+
 // Represents a log event, which is a record of activity that was recorded by
 // the application or resource being monitored.
 type InputLogEvent struct {
@@ -17581,6 +17943,101 @@ func (s PutDestinationPolicyOutput) GoString() string {
 	return s.String()
 }
 
+type PutIndexPolicyInput struct {
+	_ struct{} `type:"structure"`
+
+	// The name or ARN of the log group to create the index policy of.
+	//
+	// LogGroupIdentifier is a required field
+	LogGroupIdentifier *string `locationName:"logGroupIdentifier" min:"1" type:"string" required:"true"`
+
+	// The policy document, a JSON object with the fields to index in the Fields
+	// array, e.g. {"Fields": ["RequestId", "TransactionId"]}.
+	//
+	// PolicyDocument is a required field
+	PolicyDocument *string `locationName:"policyDocument" type:"string" required:"true"`
+}
+
+// String returns the string representation.
+//
+// API parameter values that are decorated as "sensitive" in the API will not
+// be included in the string output. The member name will be present, but the
+// value will be replaced with "sensitive".
+func (s PutIndexPolicyInput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation.
+//
+// API parameter values that are decorated as "sensitive" in the API will not
+// be included in the string output. The member name will be present, but the
+// value will be replaced with "sensitive".
+func (s PutIndexPolicyInput) GoString() string {
+	return s.String()
+}
+
+// Validate inspects the fields of the type to determine if they are valid.
+func (s *PutIndexPolicyInput) Validate() error {
+	invalidParams := request.ErrInvalidParams{Context: "PutIndexPolicyInput"}
+	if s.LogGroupIdentifier == nil {
+		invalidParams.Add(request.NewErrParamRequired("LogGroupIdentifier"))
+	}
+	if s.LogGroupIdentifier != nil && len(*s.LogGroupIdentifier) < 1 {
+		invalidParams.Add(request.NewErrParamMinLen("LogGroupIdentifier", 1))
+	}
+	if s.PolicyDocument == nil {
+		invalidParams.Add(request.NewErrParamRequired("PolicyDocument"))
+	}
+
+	if invalidParams.Len() > 0 {
+		return invalidParams
+	}
+	return nil
+}
+
+// SetLogGroupIdentifier sets the LogGroupIdentifier field's value.
+func (s *PutIndexPolicyInput) SetLogGroupIdentifier(v string) *PutIndexPolicyInput {
+	s.LogGroupIdentifier = &v
+	return s
+}
+
+// SetPolicyDocument sets the PolicyDocument field's value.
+func (s *PutIndexPolicyInput) SetPolicyDocument(v string) *PutIndexPolicyInput {
+	s.PolicyDocument = &v
+	return s
+}
+
+type PutIndexPolicyOutput struct {
+	_ struct{} `type:"structure"`
+
+	// The index policy that was created or updated.
+	IndexPolicy *IndexPolicy `locationName:"indexPolicy" type:"structure"`
+}
+
+// String returns the string representation.
+//
+// API parameter values that are decorated as "sensitive" in the API will not
+// be included in the string output. The member name will be present, but the
+// value will be replaced with "sensitive".
+func (s PutIndexPolicyOutput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation.
+//
+// API parameter values that are decorated as "sensitive" in the API will not
+// be included in the string output. The member name will be present, but the
+// value will be replaced with "sensitive".
+func (s PutIndexPolicyOutput) GoString() string {
+	return s.String()
+}
+
+// SetIndexPolicy sets the IndexPolicy field's value.
+func (s *PutIndexPolicyOutput) SetIndexPolicy(v *IndexPolicy) *PutIndexPolicyOutput {
+	s.IndexPolicy = v
+	return s
+}
+
 type PutLogEventsInput struct {
 	_ struct{} `type:"structure"`
 
@@ -21197,6 +21654,22 @@ func ExportTaskStatusCode_Values() []string {
 	}
 }
 
+const (
+	// IndexSourceAccount is a IndexSource enum value
+	IndexSourceAccount = "ACCOUNT"
+
+	// IndexSourceLogGroup is a IndexSource enum value
+	IndexSourceLogGroup = "LOG_GROUP"
+)
+
+// IndexSource_Values returns all elements of the IndexSource enum
+func IndexSource_Values() []string {
+	return []string{
+		IndexSourceAccount,
+		IndexSourceLogGroup,
+	}
+}
+
 const (
 	// InheritedPropertyAccountDataProtection is a InheritedProperty enum value
 	InheritedPropertyAccountDataProtection = "ACCOUNT_DATA_PROTECTION"
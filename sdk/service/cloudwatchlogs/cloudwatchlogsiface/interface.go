@@ -185,6 +185,10 @@ type CloudWatchLogsAPI interface {
 	DescribeExportTasksWithContext(aws.Context, *cloudwatchlogs.DescribeExportTasksInput, ...request.Option) (*cloudwatchlogs.DescribeExportTasksOutput, error)
 	DescribeExportTasksRequest(*cloudwatchlogs.DescribeExportTasksInput) (*request.Request, *cloudwatchlogs.DescribeExportTasksOutput)
 
+	DescribeIndexPolicies(*cloudwatchlogs.DescribeIndexPoliciesInput) (*cloudwatchlogs.DescribeIndexPoliciesOutput, error)
+	DescribeIndexPoliciesWithContext(aws.Context, *cloudwatchlogs.DescribeIndexPoliciesInput, ...request.Option) (*cloudwatchlogs.DescribeIndexPoliciesOutput, error)
+	DescribeIndexPoliciesRequest(*cloudwatchlogs.DescribeIndexPoliciesInput) (*request.Request, *cloudwatchlogs.DescribeIndexPoliciesOutput)
+
 	DescribeLogGroups(*cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
 	DescribeLogGroupsWithContext(aws.Context, *cloudwatchlogs.DescribeLogGroupsInput, ...request.Option) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
 	DescribeLogGroupsRequest(*cloudwatchlogs.DescribeLogGroupsInput) (*request.Request, *cloudwatchlogs.DescribeLogGroupsOutput)
@@ -329,6 +333,10 @@ type CloudWatchLogsAPI interface {
 	PutDestinationPolicyWithContext(aws.Context, *cloudwatchlogs.PutDestinationPolicyInput, ...request.Option) (*cloudwatchlogs.PutDestinationPolicyOutput, error)
 	PutDestinationPolicyRequest(*cloudwatchlogs.PutDestinationPolicyInput) (*request.Request, *cloudwatchlogs.PutDestinationPolicyOutput)
 
+	PutIndexPolicy(*cloudwatchlogs.PutIndexPolicyInput) (*cloudwatchlogs.PutIndexPolicyOutput, error)
+	PutIndexPolicyWithContext(aws.Context, *cloudwatchlogs.PutIndexPolicyInput, ...request.Option) (*cloudwatchlogs.PutIndexPolicyOutput, error)
+	PutIndexPolicyRequest(*cloudwatchlogs.PutIndexPolicyInput) (*request.Request, *cloudwatchlogs.PutIndexPolicyOutput)
+
 	PutLogEvents(*cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error)
 	PutLogEventsWithContext(aws.Context, *cloudwatchlogs.PutLogEventsInput, ...request.Option) (*cloudwatchlogs.PutLogEventsOutput, error)
 	PutLogEventsRequest(*cloudwatchlogs.PutLogEventsInput) (*request.Request, *cloudwatchlogs.PutLogEventsOutput)
@@ -0,0 +1,133 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package configprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"go.opentelemetry.io/collector/confmap"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/translator/util"
+)
+
+// defaultRegion is the region of the SSM parameters referenced by name. It is
+// replaced in tests.
+var defaultRegion = func() string {
+	for _, key := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := os.Getenv(key); region != "" {
+			return region
+		}
+	}
+	if region := util.DefaultEC2Region(); region != "" {
+		return region
+	}
+	return util.DefaultECSRegion()
+}
+
+// newSecretsManagerProviderFactory resolves ${secretsmanager:<arn>} to the
+// secret string. ${secretsmanager:<arn>#<key>} resolves to a field of a JSON
+// secret, e.g. the password of a database secret.
+func newSecretsManagerProviderFactory() confmap.ProviderFactory {
+	return confmap.NewProviderFactory(func(settings confmap.ProviderSettings) confmap.Provider {
+		return newSecretProvider(schemeSecretsManager, getSecretsManagerSecret, settings.Logger)
+	})
+}
+
+// newSSMProviderFactory resolves ${ssm:<name or arn>} to the decrypted value
+// of the parameter.
+func newSSMProviderFactory() confmap.ProviderFactory {
+	return confmap.NewProviderFactory(func(settings confmap.ProviderSettings) confmap.Provider {
+		return newSecretProvider(schemeSSM, getSSMParameter, settings.Logger)
+	})
+}
+
+func getSecretsManagerSecret(ctx context.Context, reference string) (string, error) {
+	secretID, key, _ := strings.Cut(reference, "#")
+	svc := secretsmanager.New(credentialConfig(secretID).Credentials(), &aws.Config{
+		LogLevel: configaws.SDKLogLevel(),
+		Logger:   configaws.SDKLogger{},
+	})
+	output, err := svc.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	})
+	if err != nil {
+		return "", err
+	}
+	value := aws.StringValue(output.SecretString)
+	if output.SecretString == nil {
+		value = string(output.SecretBinary)
+	}
+	if key == "" {
+		return value, nil
+	}
+	return secretField(value, key)
+}
+
+func getSSMParameter(ctx context.Context, reference string) (string, error) {
+	credentialConfig := credentialConfig(reference)
+	credentialConfig.ProxyService = configaws.ProxyServiceSSM
+	svc := ssm.New(credentialConfig.Credentials(), &aws.Config{
+		LogLevel: configaws.SDKLogLevel(),
+		Logger:   configaws.SDKLogger{},
+	})
+	output, err := svc.GetParameterWithContext(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(reference),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(output.Parameter.Value), nil
+}
+
+// credentialConfig uses the region of the ARN, or the default region for the
+// references by name. The profile, shared credential file and role of the
+// agent are set in the env config by the translator. The proxy of the clients
+// is read from the environment by the sessions.
+func credentialConfig(reference string) *configaws.CredentialConfig {
+	region := ""
+	if parsed, err := arn.Parse(reference); err == nil {
+		region = parsed.Region
+	}
+	if region == "" {
+		region = defaultRegion()
+	}
+	return &configaws.CredentialConfig{
+		Region:   region,
+		RoleARN:  os.Getenv(envconfig.CWAGENT_ROLE_ARN),
+		Profile:  os.Getenv(envconfig.CWAGENT_PROFILE),
+		Filename: os.Getenv(envconfig.CWAGENT_SHARED_CREDENTIAL_FILE),
+	}
+}
+
+// secretField returns the field of the JSON secret as a string.
+func secretField(secret, key string) (string, error) {
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object: %w", err)
+	}
+	switch value := fields[key].(type) {
+	case string:
+		return value, nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case nil:
+		return "", fmt.Errorf("secret does not have the %q field", key)
+	default:
+		return "", fmt.Errorf("secret field %q is not a string, number or boolean", key)
+	}
+}
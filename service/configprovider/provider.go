@@ -19,6 +19,8 @@ func GetSettings(uris []string, logger *zap.Logger) otelcol.ConfigProviderSettin
 			ProviderFactories: []confmap.ProviderFactory{
 				fileprovider.NewFactory(),
 				envprovider.NewFactory(),
+				newSecretsManagerProviderFactory(),
+				newSSMProviderFactory(),
 			},
 			ProviderSettings:   confmap.ProviderSettings{Logger: logger},
 			ConverterFactories: []confmap.ConverterFactory{expandconverter.NewFactory()},
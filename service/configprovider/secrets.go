@@ -0,0 +1,193 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package configprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/confmap"
+	"go.uber.org/zap"
+)
+
+const (
+	schemeSecretsManager = "secretsmanager"
+	schemeSSM            = "ssm"
+
+	// secretRefreshInterval is how often the referenced secrets are read
+	// again, so that the pipelines are reloaded with the rotated values.
+	secretRefreshInterval = time.Hour
+)
+
+// secretGetter returns the current value of the referenced secret.
+type secretGetter func(ctx context.Context, reference string) (string, error)
+
+// secretProvider resolves the ${<scheme>:<reference>} placeholders of the
+// config to the value of a secret, so that the secrets are not written to the
+// config files. The values are cached while the config references them, and
+// are refreshed periodically. A rotated value triggers a reload of the config.
+type secretProvider struct {
+	scheme          string
+	get             secretGetter
+	refreshInterval time.Duration
+	logger          *zap.Logger
+
+	mu      sync.Mutex
+	secrets map[string]*cachedSecret
+	watcher confmap.WatcherFunc
+	done    chan struct{}
+}
+
+type cachedSecret struct {
+	value string
+	// refs is the number of retrieved values not closed yet.
+	refs int
+}
+
+var _ confmap.Provider = (*secretProvider)(nil)
+
+func newSecretProvider(scheme string, get secretGetter, logger *zap.Logger) *secretProvider {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &secretProvider{
+		scheme:          scheme,
+		get:             get,
+		refreshInterval: secretRefreshInterval,
+		logger:          logger,
+		secrets:         map[string]*cachedSecret{},
+	}
+}
+
+func (p *secretProvider) Retrieve(ctx context.Context, uri string, watcher confmap.WatcherFunc) (*confmap.Retrieved, error) {
+	reference, ok := strings.CutPrefix(uri, p.scheme+":")
+	if !ok || reference == "" {
+		return nil, fmt.Errorf("%q uri is not supported by %q provider", uri, p.scheme)
+	}
+	value, err := p.acquire(ctx, reference)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve %s: %w", uri, err)
+	}
+	if watcher != nil {
+		p.watch(watcher)
+	}
+	return confmap.NewRetrieved(value, confmap.WithRetrievedClose(func(context.Context) error {
+		p.release(reference)
+		return nil
+	}))
+}
+
+func (p *secretProvider) Scheme() string {
+	return p.scheme
+}
+
+func (p *secretProvider) Shutdown(context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.done != nil {
+		close(p.done)
+		p.done = nil
+	}
+	return nil
+}
+
+// acquire returns the cached value of the secret, or reads it if the config
+// does not reference it yet.
+func (p *secretProvider) acquire(ctx context.Context, reference string) (string, error) {
+	p.mu.Lock()
+	if secret, ok := p.secrets[reference]; ok {
+		secret.refs++
+		p.mu.Unlock()
+		return secret.value, nil
+	}
+	p.mu.Unlock()
+
+	value, err := p.get(ctx, reference)
+	if err != nil {
+		return "", err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	secret, ok := p.secrets[reference]
+	if !ok {
+		secret = &cachedSecret{value: value}
+		p.secrets[reference] = secret
+	}
+	secret.refs++
+	return secret.value, nil
+}
+
+// release forgets the secret once the config does not reference it anymore.
+func (p *secretProvider) release(reference string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if secret, ok := p.secrets[reference]; ok {
+		secret.refs--
+		if secret.refs <= 0 {
+			delete(p.secrets, reference)
+		}
+	}
+}
+
+func (p *secretProvider) watch(watcher confmap.WatcherFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.watcher = watcher
+	if p.done == nil {
+		p.done = make(chan struct{})
+		go p.runRefresh(p.done)
+	}
+}
+
+func (p *secretProvider) runRefresh(done chan struct{}) {
+	ticker := time.NewTicker(p.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.refresh()
+		case <-done:
+			return
+		}
+	}
+}
+
+// refresh reads the referenced secrets again, and notifies the watcher if any
+// of them was rotated. The cached values are kept if they cannot be read.
+func (p *secretProvider) refresh() {
+	p.mu.Lock()
+	references := make([]string, 0, len(p.secrets))
+	for reference := range p.secrets {
+		references = append(references, reference)
+	}
+	p.mu.Unlock()
+
+	changed := false
+	for _, reference := range references {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		value, err := p.get(ctx, reference)
+		cancel()
+		if err != nil {
+			p.logger.Warn("Unable to refresh the secret", zap.String("scheme", p.scheme), zap.String("reference", reference), zap.Error(err))
+			continue
+		}
+		p.mu.Lock()
+		if secret, ok := p.secrets[reference]; ok && secret.value != value {
+			secret.value = value
+			changed = true
+		}
+		p.mu.Unlock()
+	}
+
+	p.mu.Lock()
+	watcher := p.watcher
+	p.mu.Unlock()
+	if changed && watcher != nil {
+		p.logger.Info("Secret rotated, reloading the config", zap.String("scheme", p.scheme))
+		watcher(&confmap.ChangeEvent{})
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package configprovider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/confmap/provider/envprovider"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+)
+
+type fakeSecrets struct {
+	mu     sync.Mutex
+	values map[string]string
+	reads  map[string]int
+}
+
+func newFakeSecrets(values map[string]string) *fakeSecrets {
+	return &fakeSecrets{values: values, reads: map[string]int{}}
+}
+
+func (f *fakeSecrets) get(_ context.Context, reference string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reads[reference]++
+	value, ok := f.values[reference]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return value, nil
+}
+
+func (f *fakeSecrets) set(reference, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values[reference] = value
+}
+
+func (f *fakeSecrets) readCount(reference string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.reads[reference]
+}
+
+func TestSecretProviderResolve(t *testing.T) {
+	secrets := newFakeSecrets(map[string]string{"/app/password": "hunter2", "/app/port": "8080"})
+	p := newSecretProvider(schemeSSM, secrets.get, nil)
+	t.Setenv("CONFIG", `
+receivers:
+  prometheus:
+    password: ${ssm:/app/password}
+    other_password: ${ssm:/app/password}
+    endpoint: localhost:${ssm:/app/port}
+    port: ${ssm:/app/port}
+`)
+	resolver, err := confmap.NewResolver(confmap.ResolverSettings{
+		URIs: []string{"env:CONFIG"},
+		ProviderFactories: []confmap.ProviderFactory{
+			envprovider.NewFactory(),
+			confmap.NewProviderFactory(func(confmap.ProviderSettings) confmap.Provider { return p }),
+		},
+	})
+	require.NoError(t, err)
+	conf, err := resolver.Resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"receivers": map[string]any{
+			"prometheus": map[string]any{
+				"password":       "hunter2",
+				"other_password": "hunter2",
+				"endpoint":       "localhost:8080",
+				// the secrets are strings, not parsed as YAML
+				"port": "8080",
+			},
+		},
+	}, conf.ToStringMap())
+	// the secret referenced twice is read once
+	assert.Equal(t, 1, secrets.readCount("/app/password"))
+	require.NoError(t, resolver.Shutdown(context.Background()))
+	assert.Empty(t, p.secrets)
+}
+
+func TestSecretProviderRetrieveFails(t *testing.T) {
+	p := newSecretProvider(schemeSecretsManager, newFakeSecrets(nil).get, nil)
+	_, err := p.Retrieve(context.Background(), "ssm:/app/password", nil)
+	assert.EqualError(t, err, `"ssm:/app/password" uri is not supported by "secretsmanager" provider`)
+	_, err = p.Retrieve(context.Background(), "secretsmanager:missing", nil)
+	assert.EqualError(t, err, "unable to resolve secretsmanager:missing: secret not found")
+}
+
+func TestSecretProviderRotation(t *testing.T) {
+	secrets := newFakeSecrets(map[string]string{"/app/password": "hunter2", "/app/user": "admin"})
+	p := newSecretProvider(schemeSSM, secrets.get, nil)
+	p.refreshInterval = 10 * time.Millisecond
+	changed := make(chan struct{}, 1)
+	watcher := func(*confmap.ChangeEvent) {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	ctx := context.Background()
+	password, err := p.Retrieve(ctx, "ssm:/app/password", watcher)
+	require.NoError(t, err)
+	user, err := p.Retrieve(ctx, "ssm:/app/user", watcher)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, p.Shutdown(ctx)) }()
+
+	// the secrets are refreshed without notifying the watcher while they are unchanged
+	assert.Eventually(t, func() bool { return secrets.readCount("/app/password") > 2 }, time.Second, 5*time.Millisecond)
+	assert.Empty(t, changed)
+
+	// the secrets no longer referenced are not refreshed
+	require.NoError(t, user.Close(ctx))
+	reads := secrets.readCount("/app/user")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, reads, secrets.readCount("/app/user"))
+
+	secrets.set("/app/password", "correct-horse")
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		assert.Fail(t, "watcher not notified of the rotated secret")
+	}
+	require.NoError(t, password.Close(ctx))
+}
+
+func TestSecretField(t *testing.T) {
+	secret := `{"username": "admin", "password": "hunter2", "port": 5432, "ssl": true, "hosts": ["a"]}`
+	testCases := map[string]struct {
+		key     string
+		want    string
+		wantErr string
+	}{
+		"String":  {key: "password", want: "hunter2"},
+		"Number":  {key: "port", want: "5432"},
+		"Boolean": {key: "ssl", want: "true"},
+		"Missing": {key: "host", wantErr: `secret does not have the "host" field`},
+		"Array":   {key: "hosts", wantErr: `secret field "hosts" is not a string, number or boolean`},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := secretField(secret, testCase.key)
+			if testCase.wantErr != "" {
+				assert.EqualError(t, err, testCase.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+	_, err := secretField("hunter2", "password")
+	assert.Error(t, err)
+}
+
+func TestCredentialConfigRegion(t *testing.T) {
+	original := defaultRegion
+	defer func() { defaultRegion = original }()
+	defaultRegion = func() string { return "us-west-2" }
+
+	assert.Equal(t, "eu-west-1", credentialConfig("arn:aws:secretsmanager:eu-west-1:123456789012:secret:db-AbCdEf").Region)
+	assert.Equal(t, "ap-south-1", credentialConfig("arn:aws:ssm:ap-south-1:123456789012:parameter/app/password").Region)
+	assert.Equal(t, "us-west-2", credentialConfig("/app/password").Region)
+}
+
+func TestCredentialConfigAgentCredentials(t *testing.T) {
+	original := defaultRegion
+	defer func() { defaultRegion = original }()
+	defaultRegion = func() string { return "us-west-2" }
+
+	filename := filepath.Join(t.TempDir(), "credentials")
+	require.NoError(t, os.WriteFile(filename, []byte("[agent]\naws_access_key_id = AKIDAGENT\naws_secret_access_key = secret\n"), 0600))
+	t.Setenv(envconfig.CWAGENT_PROFILE, "agent")
+	t.Setenv(envconfig.CWAGENT_SHARED_CREDENTIAL_FILE, filename)
+	t.Setenv(envconfig.CWAGENT_ROLE_ARN, "")
+
+	got := credentialConfig("/app/password")
+	assert.Equal(t, "agent", got.Profile)
+	assert.Equal(t, filename, got.Filename)
+	ses, ok := got.Credentials().(*session.Session)
+	require.True(t, ok)
+	creds, err := ses.Config.Credentials.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "AKIDAGENT", creds.AccessKeyID)
+
+	t.Setenv(envconfig.CWAGENT_ROLE_ARN, "arn:aws:iam::123456789012:role/agent")
+	got = credentialConfig("arn:aws:secretsmanager:eu-west-1:123456789012:secret:db-AbCdEf")
+	assert.Equal(t, "arn:aws:iam::123456789012:role/agent", got.RoleARN)
+	assert.Equal(t, "agent", got.Profile)
+	assert.Equal(t, "eu-west-1", got.Region)
+}
@@ -57,14 +57,29 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth"
 	"github.com/aws/amazon-cloudwatch-agent/extension/entitystore"
+	"github.com/aws/amazon-cloudwatch-agent/extension/inventory"
 	"github.com/aws/amazon-cloudwatch-agent/extension/server"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/alerting"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/cloudwatch"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/otlpmetrics"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/textfile"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/awsapplicationsignals"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/awsentity"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/cardinalitylimiter"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/deltatocumulative"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/derivedmetrics"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/dimensionnormalizer"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/ec2tagger"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/gpuattributes"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/kueueattributes"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/metricsrouting"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/procstatheartbeat"
+	spanmetricsprocessor "github.com/aws/amazon-cloudwatch-agent/plugins/processors/spanmetrics"
 	"github.com/aws/amazon-cloudwatch-agent/processor/rollupprocessor"
+	"github.com/aws/amazon-cloudwatch-agent/receiver/ecstaskstats"
+	"github.com/aws/amazon-cloudwatch-agent/receiver/eksfargatestats"
+	"github.com/aws/amazon-cloudwatch-agent/receiver/selftelemetry"
+	spanmetricsreceiver "github.com/aws/amazon-cloudwatch-agent/receiver/spanmetrics"
 )
 
 func Factories() (otelcol.Factories, error) {
@@ -76,6 +91,8 @@ func Factories() (otelcol.Factories, error) {
 		awscontainerinsightskueuereceiver.NewFactory(),
 		awsecscontainermetricsreceiver.NewFactory(),
 		awsxrayreceiver.NewFactory(),
+		ecstaskstats.NewFactory(),
+		eksfargatestats.NewFactory(),
 		filelogreceiver.NewFactory(),
 		jaegerreceiver.NewFactory(),
 		jmxreceiver.NewFactory(),
@@ -83,6 +100,8 @@ func Factories() (otelcol.Factories, error) {
 		nopreceiver.NewFactory(),
 		otlpreceiver.NewFactory(),
 		prometheusreceiver.NewFactory(),
+		selftelemetry.NewFactory(),
+		spanmetricsreceiver.NewFactory(),
 		statsdreceiver.NewFactory(),
 		tcplogreceiver.NewFactory(),
 		udplogreceiver.NewFactory(),
@@ -96,8 +115,12 @@ func Factories() (otelcol.Factories, error) {
 		awsapplicationsignals.NewFactory(),
 		awsentity.NewFactory(),
 		batchprocessor.NewFactory(),
+		cardinalitylimiter.NewFactory(),
 		cumulativetodeltaprocessor.NewFactory(),
+		deltatocumulative.NewFactory(),
 		deltatorateprocessor.NewFactory(),
+		derivedmetrics.NewFactory(),
+		dimensionnormalizer.NewFactory(),
 		ec2tagger.NewFactory(),
 		filterprocessor.NewFactory(),
 		gpuattributes.NewFactory(),
@@ -106,12 +129,15 @@ func Factories() (otelcol.Factories, error) {
 		k8sattributesprocessor.NewFactory(),
 		memorylimiterprocessor.NewFactory(),
 		metricsgenerationprocessor.NewFactory(),
+		metricsrouting.NewFactory(),
 		metricstransformprocessor.NewFactory(),
 		probabilisticsamplerprocessor.NewFactory(),
+		procstatheartbeat.NewFactory(),
 		resourceprocessor.NewFactory(),
 		resourcedetectionprocessor.NewFactory(),
 		rollupprocessor.NewFactory(),
 		spanprocessor.NewFactory(),
+		spanmetricsprocessor.NewFactory(),
 		tailsamplingprocessor.NewFactory(),
 		transformprocessor.NewFactory(),
 	); err != nil {
@@ -119,6 +145,7 @@ func Factories() (otelcol.Factories, error) {
 	}
 
 	if factories.Exporters, err = exporter.MakeFactoryMap(
+		alerting.NewFactory(),
 		awscloudwatchlogsexporter.NewFactory(),
 		awsemfexporter.NewFactory(),
 		awsxrayexporter.NewFactory(),
@@ -126,6 +153,9 @@ func Factories() (otelcol.Factories, error) {
 		debugexporter.NewFactory(),
 		nopexporter.NewFactory(),
 		prometheusremotewriteexporter.NewFactory(),
+		textfile.NewFactory(),
+		otlpmetrics.NewFactory(),
+		otlpmetrics.NewTracesFactory(),
 	); err != nil {
 		return otelcol.Factories{}, err
 	}
@@ -134,6 +164,7 @@ func Factories() (otelcol.Factories, error) {
 		agenthealth.NewFactory(),
 		awsproxy.NewFactory(),
 		entitystore.NewFactory(),
+		inventory.NewFactory(),
 		server.NewFactory(),
 		ballastextension.NewFactory(),
 		ecsobserver.NewFactory(),
@@ -21,6 +21,8 @@ func TestComponents(t *testing.T) {
 		"awscontainerinsightskueuereceiver",
 		"awsecscontainermetrics",
 		"awsxray",
+		"ecstaskstats",
+		"eksfargatestats",
 		"filelog",
 		"jaeger",
 		"jmx",
@@ -28,6 +30,8 @@ func TestComponents(t *testing.T) {
 		"nop",
 		"otlp",
 		"prometheus",
+		"selftelemetry",
+		"spanmetrics",
 		"statsd",
 		"tcplog",
 		"udplog",
@@ -44,8 +48,12 @@ func TestComponents(t *testing.T) {
 		"awsentity",
 		"attributes",
 		"batch",
+		"cardinalitylimiter",
 		"cumulativetodelta",
+		"deltatocumulative",
 		"deltatorate",
+		"derivedmetrics",
+		"dimensionnormalizer",
 		"ec2tagger",
 		"experimental_metricsgeneration",
 		"filter",
@@ -54,12 +62,15 @@ func TestComponents(t *testing.T) {
 		"groupbytrace",
 		"k8sattributes",
 		"memory_limiter",
+		"metricsrouting",
 		"metricstransform",
 		"resourcedetection",
 		"resource",
 		"rollup",
 		"probabilistic_sampler",
+		"procstatheartbeat",
 		"span",
+		"spanmetrics",
 		"tail_sampling",
 		"transform",
 	}
@@ -70,6 +81,7 @@ func TestComponents(t *testing.T) {
 	}
 
 	wantExporters := []string{
+		"alerting",
 		"awscloudwatchlogs",
 		"awsemf",
 		"awscloudwatch",
@@ -77,6 +89,9 @@ func TestComponents(t *testing.T) {
 		"debug",
 		"nop",
 		"prometheusremotewrite",
+		"textfile",
+		"otlpmetrics",
+		"otlptraces",
 	}
 	gotExporters := collections.MapSlice(maps.Keys(factories.Exporters), component.Type.String)
 	assert.Equal(t, len(wantExporters), len(gotExporters))
@@ -91,6 +106,7 @@ func TestComponents(t *testing.T) {
 		"entitystore",
 		"file_storage",
 		"health_check",
+		"inventory",
 		"memory_ballast",
 		"pprof",
 		"server",
@@ -16,6 +16,9 @@ type Config struct {
 	MetricsConfig *config.Metrics
 	LogsConfig    *config.Logs
 	TracesConfig  *config.Traces
+
+	// ApplicationSignals enables the Application Signals metrics and traces.
+	ApplicationSignals *config.ApplicationSignals
 }
 
 func (config *Config) ToMap(ctx *runtime.Context) (string, map[string]interface{}) {
@@ -32,10 +35,31 @@ func (config *Config) ToMap(ctx *runtime.Context) (string, map[string]interface{
 	if config.TracesConfig != nil {
 		util.AddToMap(ctx, resultMap, config.TracesConfig)
 	}
+	if config.ApplicationSignals != nil {
+		key, value := config.ApplicationSignals.ToMap(ctx)
+		addToSection(resultMap, "logs", "metrics_collected", key, value)
+		addToSection(resultMap, "traces", "traces_collected", key, map[string]interface{}{})
+	}
 
 	return "", resultMap
 }
 
+// addToSection adds the value under the collected key of the section,
+// creating the maps that are missing.
+func addToSection(resultMap map[string]interface{}, sectionKey, collectedKey, key string, value map[string]interface{}) {
+	section, ok := resultMap[sectionKey].(map[string]interface{})
+	if !ok {
+		section = make(map[string]interface{})
+		resultMap[sectionKey] = section
+	}
+	collected, ok := section[collectedKey].(map[string]interface{})
+	if !ok {
+		collected = make(map[string]interface{})
+		section[collectedKey] = collected
+	}
+	collected[key] = value
+}
+
 func (conf *Config) AgentConf() *config.AgentConfig {
 	if conf.AgentConfig == nil {
 		conf.AgentConfig = new(config.AgentConfig)
@@ -8,11 +8,15 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/tool/util"
 )
 
-const RUNASUSER = "run_as_user"
+const (
+	RUNASUSER = "run_as_user"
+	REGION    = "region"
+)
 
 type AgentConfig struct {
 	MetricsCollectInterval string `metrics_collection_interval`
 	Runasuser              string `run_as_user`
+	Region                 string
 }
 
 func (config *AgentConfig) ToMap(ctx *runtime.Context) (string, map[string]interface{}) {
@@ -25,5 +29,9 @@ func (config *AgentConfig) ToMap(ctx *runtime.Context) (string, map[string]inter
 		resultMap[RUNASUSER] = config.Runasuser
 	}
 
+	if config.Region != "" {
+		resultMap[REGION] = config.Region
+	}
+
 	return "agent", resultMap
 }
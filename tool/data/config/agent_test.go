@@ -28,4 +28,11 @@ func TestAgent_ToMap(t *testing.T) {
 	key, value = conf.ToMap(ctx)
 	assert.Equal(t, expectedKey, key)
 	assert.Equal(t, expectedValue, value)
+
+	region := "us-west-2"
+	expectedValue = map[string]interface{}{util.MapKeyMetricsCollectionInterval: 10, RUNASUSER: runAsUser, REGION: region}
+	conf.Region = region
+	key, value = conf.ToMap(ctx)
+	assert.Equal(t, expectedKey, key)
+	assert.Equal(t, expectedValue, value)
 }
@@ -0,0 +1,23 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/tool/runtime"
+)
+
+type ApplicationSignals struct {
+	// HostedIn is the name of the EKS cluster the services run in.
+	HostedIn string
+}
+
+func (config *ApplicationSignals) ToMap(ctx *runtime.Context) (string, map[string]interface{}) {
+	resultMap := make(map[string]interface{})
+
+	if config.HostedIn != "" {
+		resultMap["hosted_in"] = config.HostedIn
+	}
+
+	return "application_signals", resultMap
+}
@@ -12,6 +12,7 @@ type Logs struct {
 	ForceFlushInterval int `force_flush_interval`
 	LogStream          string
 	LogsCollect        *logs.Collection
+	MetricsCollect     *logs.MetricsCollected
 }
 
 func (config *Logs) ToMap(ctx *runtime.Context) (string, map[string]interface{}) {
@@ -22,6 +23,11 @@ func (config *Logs) ToMap(ctx *runtime.Context) (string, map[string]interface{})
 		resultMap[key] = value
 	}
 
+	if config.MetricsCollect != nil {
+		key, value := config.MetricsCollect.ToMap(ctx)
+		resultMap[key] = value
+	}
+
 	if config.ForceFlushInterval != 0 {
 		resultMap["force_flush_interval"] = config.ForceFlushInterval
 	}
@@ -47,3 +53,10 @@ func (config *Logs) AddWindowsEvent(eventName, logGroupName, logStream, eventFor
 	}
 	config.LogsCollect.AddWindowsEvent(eventName, logGroupName, logStream, eventFormat, eventLevels, retention, logGroupClass)
 }
+
+func (config *Logs) MetricsCollected() *logs.MetricsCollected {
+	if config.MetricsCollect == nil {
+		config.MetricsCollect = &logs.MetricsCollected{}
+	}
+	return config.MetricsCollect
+}
@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/tool/runtime"
+	"github.com/aws/amazon-cloudwatch-agent/tool/util"
+)
+
+// MetricsCollected holds the Container Insights metrics, which are sent as
+// embedded metric format logs.
+type MetricsCollected struct {
+	ECS        *ECS
+	Kubernetes *Kubernetes
+}
+
+func (config *MetricsCollected) ToMap(ctx *runtime.Context) (string, map[string]interface{}) {
+	resultMap := make(map[string]interface{})
+
+	if config.ECS != nil {
+		util.AddToMap(ctx, resultMap, config.ECS)
+	}
+
+	if config.Kubernetes != nil {
+		util.AddToMap(ctx, resultMap, config.Kubernetes)
+	}
+
+	return "metrics_collected", resultMap
+}
+
+type ECS struct {
+	TaskNetworkMetrics    bool
+	ServiceConnectMetrics bool
+}
+
+func (config *ECS) ToMap(ctx *runtime.Context) (string, map[string]interface{}) {
+	resultMap := make(map[string]interface{})
+
+	if config.TaskNetworkMetrics {
+		resultMap["task_network_metrics"] = true
+	}
+
+	if config.ServiceConnectMetrics {
+		resultMap["service_connect_metrics"] = true
+	}
+
+	return "ecs", resultMap
+}
+
+type Kubernetes struct {
+	ClusterName               string
+	EnhancedContainerInsights bool
+}
+
+func (config *Kubernetes) ToMap(ctx *runtime.Context) (string, map[string]interface{}) {
+	resultMap := make(map[string]interface{})
+
+	if config.ClusterName != "" {
+		resultMap["cluster_name"] = config.ClusterName
+	}
+
+	if config.EnhancedContainerInsights {
+		resultMap["enhanced_container_insights"] = true
+	}
+
+	return "kubernetes", resultMap
+}
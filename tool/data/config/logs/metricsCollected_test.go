@@ -0,0 +1,39 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/runtime"
+)
+
+func TestMetricsCollected_ToMap(t *testing.T) {
+	ctx := &runtime.Context{}
+	conf := &MetricsCollected{
+		ECS: &ECS{TaskNetworkMetrics: true, ServiceConnectMetrics: true},
+		Kubernetes: &Kubernetes{
+			ClusterName:               "demo",
+			EnhancedContainerInsights: true,
+		},
+	}
+	key, value := conf.ToMap(ctx)
+	assert.Equal(t, "metrics_collected", key)
+	assert.Equal(t, map[string]interface{}{
+		"ecs": map[string]interface{}{
+			"task_network_metrics":    true,
+			"service_connect_metrics": true,
+		},
+		"kubernetes": map[string]interface{}{
+			"cluster_name":                "demo",
+			"enhanced_container_insights": true,
+		},
+	}, value)
+
+	key, value = (&MetricsCollected{ECS: &ECS{}}).ToMap(ctx)
+	assert.Equal(t, "metrics_collected", key)
+	assert.Equal(t, map[string]interface{}{"ecs": map[string]interface{}{}}, value)
+}
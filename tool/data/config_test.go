@@ -8,6 +8,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/aws/amazon-cloudwatch-agent/tool/data/config"
+	"github.com/aws/amazon-cloudwatch-agent/tool/data/config/logs"
 	"github.com/aws/amazon-cloudwatch-agent/tool/runtime"
 	"github.com/aws/amazon-cloudwatch-agent/tool/util"
 )
@@ -46,3 +48,23 @@ func TestConfig_ToMap(t *testing.T) {
 	assert.Equal(t, expectedKey, key)
 	assert.Equal(t, expectedValue, value)
 }
+
+func TestConfig_ToMapWithApplicationSignals(t *testing.T) {
+	conf := new(Config)
+	conf.ApplicationSignals = &config.ApplicationSignals{HostedIn: "demo"}
+	conf.LogsConf().MetricsCollected().Kubernetes = &logs.Kubernetes{ClusterName: "demo"}
+	_, value := conf.ToMap(&runtime.Context{})
+	assert.Equal(t, map[string]interface{}{
+		"logs": map[string]interface{}{
+			"metrics_collected": map[string]interface{}{
+				"kubernetes":          map[string]interface{}{"cluster_name": "demo"},
+				"application_signals": map[string]interface{}{"hosted_in": "demo"},
+			},
+		},
+		"traces": map[string]interface{}{
+			"traces_collected": map[string]interface{}{
+				"application_signals": map[string]interface{}{},
+			},
+		},
+	}, value)
+}
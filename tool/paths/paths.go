@@ -11,6 +11,12 @@ const (
 	ENV            = "env-config.json"
 	AGENT_LOG_FILE = "amazon-cloudwatch-agent.log"
 	JMXJarName     = "opentelemetry-jmx-metrics.jar"
+	TrustStoreDir  = "trust-store"
+	AdminSocket    = "admin.sock"
+	FeatureGates   = "feature-gates.json"
+	// AgentServiceName is the name of the Windows service, also used as the
+	// source of the events the agent writes to the Event Log.
+	AgentServiceName = "AmazonCloudWatchAgent"
 )
 
 var (
@@ -23,5 +29,10 @@ var (
 	AgentLogFilePath     string
 	TranslatorBinaryPath string
 	AgentBinaryPath      string
+	StartAgentBinaryPath string
 	JMXJarPath           string
+	TrustStoreDirPath    string
+	AdminSocketPath      string
+	FeatureGatesPath     string
+	FileStateFolderPath  string
 )
@@ -14,6 +14,7 @@ const (
 	ConfigDir            = "amazon-cloudwatch-agent.d"
 	TranslatorBinaryName = "config-translator"
 	AgentBinaryName      = "amazon-cloudwatch-agent"
+	StartAgentBinaryName = "start-amazon-cloudwatch-agent"
 	WizardBinaryName     = "amazon-cloudwatch-agent-config-wizard"
 	AgentStartName       = "amazon-cloudwatch-agent-ctl"
 	//TODO this CONFIG_DIR_IN_CONTAINER should change to something indicate dir, keep it for now to avoid break testing
@@ -30,5 +31,10 @@ func init() {
 	AgentLogFilePath = filepath.Join(AgentDir, "logs", AGENT_LOG_FILE)
 	TranslatorBinaryPath = filepath.Join(AgentDir, "bin", TranslatorBinaryName)
 	AgentBinaryPath = filepath.Join(AgentDir, "bin", AgentBinaryName)
+	StartAgentBinaryPath = filepath.Join(AgentDir, "bin", StartAgentBinaryName)
 	JMXJarPath = filepath.Join(AgentDir, "bin", JMXJarName)
+	TrustStoreDirPath = filepath.Join(AgentDir, "etc", TrustStoreDir)
+	AdminSocketPath = filepath.Join(AgentDir, "var", AdminSocket)
+	FeatureGatesPath = filepath.Join(AgentDir, "etc", FeatureGates)
+	FileStateFolderPath = filepath.Join(AgentDir, "logs", "state")
 }
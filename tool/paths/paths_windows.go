@@ -19,6 +19,7 @@ const (
 	BinaryDir            = "bin"
 	TranslatorBinaryName = "config-translator.exe"
 	AgentBinaryName      = "amazon-cloudwatch-agent.exe"
+	StartAgentBinaryName = "start-amazon-cloudwatch-agent.exe"
 	WizardBinaryName     = "amazon-cloudwatch-agent-config-wizard.exe"
 	AgentStartName       = "amazon-cloudwatch-agent-ctl.ps1"
 )
@@ -52,5 +53,10 @@ func init() {
 	AgentLogFilePath = filepath.Join(AgentConfigDir, AGENT_LOG_FILE)
 	TranslatorBinaryPath = filepath.Join(AgentRootDir, TranslatorBinaryName)
 	AgentBinaryPath = filepath.Join(AgentRootDir, AgentBinaryName)
+	StartAgentBinaryPath = filepath.Join(AgentRootDir, StartAgentBinaryName)
 	JMXJarPath = filepath.Join(AgentRootDir, JMXJarName)
+	TrustStoreDirPath = filepath.Join(AgentConfigDir, TrustStoreDir)
+	AdminSocketPath = filepath.Join(AgentConfigDir, AdminSocket)
+	FeatureGatesPath = filepath.Join(AgentConfigDir, FeatureGates)
+	FileStateFolderPath = filepath.Join(AgentConfigDir, "Logs", "state")
 }
@@ -0,0 +1,80 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package profile
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/tool/data"
+	dataconfig "github.com/aws/amazon-cloudwatch-agent/tool/data/config"
+	"github.com/aws/amazon-cloudwatch-agent/tool/data/config/logs"
+	"github.com/aws/amazon-cloudwatch-agent/tool/processors"
+	"github.com/aws/amazon-cloudwatch-agent/tool/processors/defaultConfig/advancedPlan"
+	"github.com/aws/amazon-cloudwatch-agent/tool/processors/defaultConfig/basicPlan"
+	"github.com/aws/amazon-cloudwatch-agent/tool/processors/defaultConfig/standardPlan"
+	"github.com/aws/amazon-cloudwatch-agent/tool/processors/serialization"
+	"github.com/aws/amazon-cloudwatch-agent/tool/runtime"
+)
+
+// NewProcessor returns the processor generating the config from a validated
+// profile instead of asking the questions of the wizard.
+func NewProcessor(p *Profile) processors.Processor {
+	return &processor{profile: p}
+}
+
+type processor struct {
+	profile *Profile
+}
+
+func (p *processor) Process(ctx *runtime.Context, config *data.Config) {
+	profile := p.profile
+	ctx.NonInteractive = true
+	ctx.OsParameter = profile.OS
+	ctx.IsOnPrem = profile.Environment == EnvironmentOnPrem
+	if profile.IsContainer() {
+		ctx.Container = profile.Environment
+	}
+
+	agentConfig := config.AgentConf()
+	agentConfig.Runasuser = profile.RunAsUser
+	agentConfig.Region = profile.Region
+
+	if profile.MetricsPlan != MetricsPlanNone {
+		ctx.WantPerInstanceMetrics = profile.PerCoreMetrics
+		ctx.WantEC2TagDimensions = profile.EC2Dimensions
+		ctx.WantAggregateDimensions = profile.AggregateDimensions
+		ctx.MetricsCollectionInterval = profile.MetricsCollectionInterval
+	}
+	switch profile.MetricsPlan {
+	case MetricsPlanBasic:
+		basicPlan.Processor.Process(ctx, config)
+	case MetricsPlanStandard:
+		standardPlan.Processor.Process(ctx, config)
+	case MetricsPlanAdvanced:
+		advancedPlan.Processor.Process(ctx, config)
+	}
+
+	enhanced := profile.HasPreset(PresetContainerInsightsEnhanced)
+	switch profile.Environment {
+	case EnvironmentECS:
+		config.LogsConf().MetricsCollected().ECS = &logs.ECS{
+			TaskNetworkMetrics:    enhanced,
+			ServiceConnectMetrics: enhanced,
+		}
+	case EnvironmentEKS:
+		config.LogsConf().MetricsCollected().Kubernetes = &logs.Kubernetes{
+			ClusterName:               profile.ClusterName,
+			EnhancedContainerInsights: enhanced,
+		}
+	}
+	if profile.HasPreset(PresetApplicationSignals) {
+		config.ApplicationSignals = &dataconfig.ApplicationSignals{HostedIn: profile.ClusterName}
+	}
+
+	for _, logFile := range profile.LogFiles {
+		config.LogsConf().AddLogFile(logFile.FilePath, logFile.LogGroupName, logFile.LogStreamName, "", "", "", "", logFile.RetentionInDays, logFile.LogGroupClass)
+	}
+}
+
+func (p *processor) NextProcessor(ctx *runtime.Context, config *data.Config) interface{} {
+	return serialization.Processor
+}
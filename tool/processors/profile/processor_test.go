@@ -0,0 +1,157 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package profile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/data"
+	"github.com/aws/amazon-cloudwatch-agent/tool/processors/serialization"
+	"github.com/aws/amazon-cloudwatch-agent/tool/runtime"
+	"github.com/aws/amazon-cloudwatch-agent/tool/util"
+)
+
+func TestProcessor_Process(t *testing.T) {
+	testCases := map[string]struct {
+		profile Profile
+		wantCtx runtime.Context
+		want    map[string]interface{}
+	}{
+		"WithEC2": {
+			profile: Profile{
+				OS:            util.OsTypeLinux,
+				Region:        "us-east-1",
+				RunAsUser:     "cwagent",
+				EC2Dimensions: true,
+				Presets:       []string{PresetApplicationSignals},
+				LogFiles:      []LogFile{{FilePath: "/var/log/app.log"}},
+			},
+			wantCtx: runtime.Context{
+				OsParameter:               util.OsTypeLinux,
+				WantEC2TagDimensions:      true,
+				MetricsCollectionInterval: 60,
+				NonInteractive:            true,
+			},
+			want: map[string]interface{}{
+				"agent": map[string]interface{}{
+					"metrics_collection_interval": 60,
+					"region":                      "us-east-1",
+					"run_as_user":                 "cwagent",
+				},
+				"metrics": map[string]interface{}{
+					"append_dimensions": map[string]interface{}{
+						"AutoScalingGroupName": "${aws:AutoScalingGroupName}",
+						"ImageId":              "${aws:ImageId}",
+						"InstanceId":           "${aws:InstanceId}",
+						"InstanceType":         "${aws:InstanceType}",
+					},
+					"metrics_collected": map[string]interface{}{
+						"mem": map[string]interface{}{
+							"measurement":                 []string{"mem_used_percent"},
+							"metrics_collection_interval": 60,
+						},
+						"disk": map[string]interface{}{
+							"measurement":                 []string{"used_percent"},
+							"metrics_collection_interval": 60,
+							"resources":                   []string{"*"},
+						},
+					},
+				},
+				"logs": map[string]interface{}{
+					"logs_collected": map[string]interface{}{
+						"files": map[string]interface{}{
+							"collect_list": []map[string]interface{}{{
+								"file_path":         "/var/log/app.log",
+								"log_group_name":    "app.log",
+								"log_stream_name":   "{instance_id}",
+								"log_group_class":   util.StandardLogGroupClass,
+								"retention_in_days": -1,
+							}},
+						},
+					},
+					"metrics_collected": map[string]interface{}{
+						"application_signals": map[string]interface{}{},
+					},
+				},
+				"traces": map[string]interface{}{
+					"traces_collected": map[string]interface{}{
+						"application_signals": map[string]interface{}{},
+					},
+				},
+			},
+		},
+		"WithECS": {
+			profile: Profile{
+				OS:          util.OsTypeLinux,
+				Environment: EnvironmentECS,
+				Presets:     []string{PresetContainerInsightsEnhanced},
+			},
+			wantCtx: runtime.Context{
+				OsParameter:    util.OsTypeLinux,
+				NonInteractive: true,
+				Container:      util.ContainerECS,
+			},
+			want: map[string]interface{}{
+				"agent": map[string]interface{}{},
+				"logs": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"ecs": map[string]interface{}{
+							"task_network_metrics":    true,
+							"service_connect_metrics": true,
+						},
+					},
+				},
+			},
+		},
+		"WithEKS": {
+			profile: Profile{
+				OS:          util.OsTypeLinux,
+				Environment: EnvironmentEKS,
+				ClusterName: "demo",
+				Presets:     []string{PresetContainerInsightsEnhanced, PresetApplicationSignals},
+			},
+			wantCtx: runtime.Context{
+				OsParameter:    util.OsTypeLinux,
+				NonInteractive: true,
+				Container:      util.ContainerEKS,
+			},
+			want: map[string]interface{}{
+				"agent": map[string]interface{}{},
+				"logs": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"kubernetes": map[string]interface{}{
+							"cluster_name":                "demo",
+							"enhanced_container_insights": true,
+						},
+						"application_signals": map[string]interface{}{
+							"hosted_in": "demo",
+						},
+					},
+				},
+				"traces": map[string]interface{}{
+					"traces_collected": map[string]interface{}{
+						"application_signals": map[string]interface{}{},
+					},
+				},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			p := testCase.profile
+			require.NoError(t, p.Validate())
+			ctx := new(runtime.Context)
+			conf := new(data.Config)
+			processor := NewProcessor(&p)
+			processor.Process(ctx, conf)
+			assert.Equal(t, testCase.wantCtx, *ctx)
+			_, got := conf.ToMap(ctx)
+			assert.Equal(t, testCase.want, got)
+			assert.Equal(t, serialization.Processor, processor.NextProcessor(ctx, conf))
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package profile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	EnvironmentEC2    = "ec2"
+	EnvironmentOnPrem = "onPrem"
+	EnvironmentECS    = util.ContainerECS
+	EnvironmentEKS    = util.ContainerEKS
+
+	MetricsPlanBasic    = "basic"
+	MetricsPlanStandard = "standard"
+	MetricsPlanAdvanced = "advanced"
+	MetricsPlanNone     = "none"
+
+	// PresetContainerInsightsEnhanced enables the enhanced observability of
+	// Container Insights on ECS or EKS.
+	PresetContainerInsightsEnhanced = "container_insights_enhanced"
+	// PresetApplicationSignals enables the Application Signals metrics and
+	// traces.
+	PresetApplicationSignals = "application_signals"
+
+	defaultMetricsCollectionInterval = 60
+)
+
+var (
+	validOS                         = []string{util.OsTypeLinux, util.OsTypeWindows, util.OsTypeDarwin}
+	validEnvironments               = []string{EnvironmentEC2, EnvironmentOnPrem, EnvironmentECS, EnvironmentEKS}
+	validMetricsPlans               = []string{MetricsPlanBasic, MetricsPlanStandard, MetricsPlanAdvanced, MetricsPlanNone}
+	validPresets                    = []string{PresetContainerInsightsEnhanced, PresetApplicationSignals}
+	validMetricsCollectionIntervals = []int{1, 10, 30, 60}
+	validLogGroupClasses            = []string{util.StandardLogGroupClass, util.InfrequentAccessLogGroupClass}
+)
+
+// Profile holds the answers to the questions of the wizard, so that the
+// config is generated without asking them.
+type Profile struct {
+	OS          string `json:"os,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	Region      string `json:"region,omitempty"`
+	RunAsUser   string `json:"run_as_user,omitempty"`
+
+	// host metrics, which are not collected in containers
+	MetricsPlan               string `json:"metrics_plan,omitempty"`
+	MetricsCollectionInterval int    `json:"metrics_collection_interval,omitempty"`
+	PerCoreMetrics            bool   `json:"per_core_metrics,omitempty"`
+	EC2Dimensions             bool   `json:"ec2_dimensions,omitempty"`
+	AggregateDimensions       bool   `json:"aggregate_dimensions,omitempty"`
+
+	Presets     []string `json:"presets,omitempty"`
+	ClusterName string   `json:"cluster_name,omitempty"`
+
+	LogFiles []LogFile `json:"log_files,omitempty"`
+}
+
+type LogFile struct {
+	FilePath        string `json:"file_path"`
+	LogGroupName    string `json:"log_group_name,omitempty"`
+	LogStreamName   string `json:"log_stream_name,omitempty"`
+	LogGroupClass   string `json:"log_group_class,omitempty"`
+	RetentionInDays int    `json:"retention_in_days,omitempty"`
+}
+
+// Load reads the profile from a JSON file. The unknown fields are rejected
+// so that a misspelled field is not silently ignored.
+func Load(path string) (*Profile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the profile: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	p := new(Profile)
+	if err = decoder.Decode(p); err != nil {
+		return nil, fmt.Errorf("invalid profile %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// IsContainer is true for the agent running in an ECS or EKS container.
+func (p *Profile) IsContainer() bool {
+	return p.Environment == EnvironmentECS || p.Environment == EnvironmentEKS
+}
+
+// HasPreset is true if the preset is enabled.
+func (p *Profile) HasPreset(preset string) bool {
+	return slices.Contains(p.Presets, preset)
+}
+
+// Validate checks the profile and sets the defaults of the wizard for the
+// fields that are not set.
+func (p *Profile) Validate() error {
+	if p.OS == "" {
+		p.OS = util.CurOS()
+	}
+	if !slices.Contains(validOS, p.OS) {
+		return invalid("os", p.OS, validOS)
+	}
+	if p.Environment == "" {
+		p.Environment = EnvironmentEC2
+	}
+	if !slices.Contains(validEnvironments, p.Environment) {
+		return invalid("environment", p.Environment, validEnvironments)
+	}
+	if p.IsContainer() && p.OS == util.OsTypeDarwin {
+		return fmt.Errorf("the %s environment is not supported on %s", p.Environment, p.OS)
+	}
+
+	if p.MetricsPlan == "" {
+		p.MetricsPlan = MetricsPlanBasic
+		if p.IsContainer() {
+			p.MetricsPlan = MetricsPlanNone
+		}
+	}
+	if !slices.Contains(validMetricsPlans, p.MetricsPlan) {
+		return invalid("metrics_plan", p.MetricsPlan, validMetricsPlans)
+	}
+	if p.IsContainer() && p.MetricsPlan != MetricsPlanNone {
+		return fmt.Errorf("the host metrics are not collected in the %s environment, metrics_plan must be %s", p.Environment, MetricsPlanNone)
+	}
+	if p.MetricsCollectionInterval == 0 {
+		p.MetricsCollectionInterval = defaultMetricsCollectionInterval
+	}
+	if !slices.Contains(validMetricsCollectionIntervals, p.MetricsCollectionInterval) {
+		return fmt.Errorf("invalid metrics_collection_interval %d, valid values are %s", p.MetricsCollectionInterval, strings.Trim(fmt.Sprint(validMetricsCollectionIntervals), "[]"))
+	}
+	if p.Environment != EnvironmentEC2 && (p.EC2Dimensions || p.AggregateDimensions) {
+		return fmt.Errorf("the ec2 dimensions are only available in the %s environment", EnvironmentEC2)
+	}
+
+	for _, preset := range p.Presets {
+		if !slices.Contains(validPresets, preset) {
+			return invalid("preset", preset, validPresets)
+		}
+	}
+	if p.HasPreset(PresetContainerInsightsEnhanced) && !p.IsContainer() {
+		return fmt.Errorf("the %s preset is only available in the %s and %s environments", PresetContainerInsightsEnhanced, EnvironmentECS, EnvironmentEKS)
+	}
+	if p.ClusterName != "" && p.Environment != EnvironmentEKS {
+		return fmt.Errorf("cluster_name is only available in the %s environment", EnvironmentEKS)
+	}
+	// the services are only resolved with the cluster name of the agent
+	// running in the cluster
+	if p.Environment == EnvironmentEKS && p.HasPreset(PresetApplicationSignals) && p.ClusterName == "" {
+		return fmt.Errorf("cluster_name is required for the %s preset in the %s environment", PresetApplicationSignals, EnvironmentEKS)
+	}
+
+	for i := range p.LogFiles {
+		if err := p.LogFiles[i].validate(p.Environment); err != nil {
+			return fmt.Errorf("invalid log_files[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validate sets the defaults the wizard suggests for the log file.
+func (f *LogFile) validate(environment string) error {
+	if f.FilePath == "" {
+		return fmt.Errorf("empty file_path")
+	}
+	if f.LogGroupName == "" {
+		f.LogGroupName = strings.Replace(filepath.Base(f.FilePath), " ", "_", -1)
+	}
+	if f.LogStreamName == "" {
+		f.LogStreamName = "{instance_id}"
+		if environment == EnvironmentOnPrem {
+			f.LogStreamName = "{hostname}"
+		}
+	}
+	if f.LogGroupClass == "" {
+		f.LogGroupClass = util.StandardLogGroupClass
+	}
+	if !slices.Contains(validLogGroupClasses, f.LogGroupClass) {
+		return invalid("log_group_class", f.LogGroupClass, validLogGroupClasses)
+	}
+	if f.RetentionInDays == 0 {
+		f.RetentionInDays = -1
+	}
+	if !slices.Contains(translator.ValidRetentionInDays, strconv.Itoa(f.RetentionInDays)) {
+		return fmt.Errorf("invalid retention_in_days %d", f.RetentionInDays)
+	}
+	return nil
+}
+
+func invalid(field, value string, validValues []string) error {
+	return fmt.Errorf("invalid %s %s, valid values are %s", field, value, strings.Join(validValues, ", "))
+}
@@ -0,0 +1,150 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/util"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "profile.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"os": "linux",
+		"environment": "eks",
+		"cluster_name": "demo",
+		"presets": ["container_insights_enhanced", "application_signals"],
+		"log_files": [{"file_path": "/var/log/app.log", "retention_in_days": 7}]
+	}`), 0644))
+	p, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, &Profile{
+		OS:          util.OsTypeLinux,
+		Environment: EnvironmentEKS,
+		ClusterName: "demo",
+		Presets:     []string{PresetContainerInsightsEnhanced, PresetApplicationSignals},
+		LogFiles:    []LogFile{{FilePath: "/var/log/app.log", RetentionInDays: 7}},
+	}, p)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"os": "linux", "enviroment": "eks"}`), 0644))
+	_, err = Load(path)
+	assert.ErrorContains(t, err, `unknown field "enviroment"`)
+
+	_, err = Load(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]struct {
+		profile Profile
+		want    Profile
+		wantErr string
+	}{
+		"WithDefaults": {
+			profile: Profile{OS: util.OsTypeLinux},
+			want: Profile{
+				OS:                        util.OsTypeLinux,
+				Environment:               EnvironmentEC2,
+				MetricsPlan:               MetricsPlanBasic,
+				MetricsCollectionInterval: 60,
+			},
+		},
+		"WithContainerDefaults": {
+			profile: Profile{OS: util.OsTypeLinux, Environment: EnvironmentECS},
+			want: Profile{
+				OS:                        util.OsTypeLinux,
+				Environment:               EnvironmentECS,
+				MetricsPlan:               MetricsPlanNone,
+				MetricsCollectionInterval: 60,
+			},
+		},
+		"WithLogFileDefaults": {
+			profile: Profile{
+				OS:          util.OsTypeLinux,
+				Environment: EnvironmentOnPrem,
+				LogFiles:    []LogFile{{FilePath: "/var/log/my app.log"}},
+			},
+			want: Profile{
+				OS:                        util.OsTypeLinux,
+				Environment:               EnvironmentOnPrem,
+				MetricsPlan:               MetricsPlanBasic,
+				MetricsCollectionInterval: 60,
+				LogFiles: []LogFile{{
+					FilePath:        "/var/log/my app.log",
+					LogGroupName:    "my_app.log",
+					LogStreamName:   "{hostname}",
+					LogGroupClass:   util.StandardLogGroupClass,
+					RetentionInDays: -1,
+				}},
+			},
+		},
+		"WithInvalidOS": {
+			profile: Profile{OS: "solaris"},
+			wantErr: "invalid os solaris, valid values are linux, windows, darwin",
+		},
+		"WithInvalidEnvironment": {
+			profile: Profile{OS: util.OsTypeLinux, Environment: "lambda"},
+			wantErr: "invalid environment lambda, valid values are ec2, onPrem, ecs, eks",
+		},
+		"WithContainerOnDarwin": {
+			profile: Profile{OS: util.OsTypeDarwin, Environment: EnvironmentEKS},
+			wantErr: "the eks environment is not supported on darwin",
+		},
+		"WithMetricsPlanInContainer": {
+			profile: Profile{OS: util.OsTypeLinux, Environment: EnvironmentECS, MetricsPlan: MetricsPlanAdvanced},
+			wantErr: "the host metrics are not collected in the ecs environment, metrics_plan must be none",
+		},
+		"WithInvalidMetricsCollectionInterval": {
+			profile: Profile{OS: util.OsTypeLinux, MetricsCollectionInterval: 15},
+			wantErr: "invalid metrics_collection_interval 15, valid values are 1 10 30 60",
+		},
+		"WithEC2DimensionsOnPrem": {
+			profile: Profile{OS: util.OsTypeLinux, Environment: EnvironmentOnPrem, EC2Dimensions: true},
+			wantErr: "the ec2 dimensions are only available in the ec2 environment",
+		},
+		"WithInvalidPreset": {
+			profile: Profile{OS: util.OsTypeLinux, Presets: []string{"prometheus"}},
+			wantErr: "invalid preset prometheus, valid values are container_insights_enhanced, application_signals",
+		},
+		"WithContainerInsightsOnHost": {
+			profile: Profile{OS: util.OsTypeLinux, Presets: []string{PresetContainerInsightsEnhanced}},
+			wantErr: "the container_insights_enhanced preset is only available in the ecs and eks environments",
+		},
+		"WithClusterNameOnECS": {
+			profile: Profile{OS: util.OsTypeLinux, Environment: EnvironmentECS, ClusterName: "demo"},
+			wantErr: "cluster_name is only available in the eks environment",
+		},
+		"WithApplicationSignalsOnEKSWithoutClusterName": {
+			profile: Profile{OS: util.OsTypeLinux, Environment: EnvironmentEKS, Presets: []string{PresetApplicationSignals}},
+			wantErr: "cluster_name is required for the application_signals preset in the eks environment",
+		},
+		"WithoutLogFilePath": {
+			profile: Profile{OS: util.OsTypeLinux, LogFiles: []LogFile{{LogGroupName: "app"}}},
+			wantErr: "invalid log_files[0]: empty file_path",
+		},
+		"WithInvalidRetention": {
+			profile: Profile{OS: util.OsTypeLinux, LogFiles: []LogFile{{FilePath: "/var/log/app.log", RetentionInDays: 2}}},
+			wantErr: "invalid log_files[0]: invalid retention_in_days 2",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			p := testCase.profile
+			err := p.Validate()
+			if testCase.wantErr != "" {
+				assert.EqualError(t, err, testCase.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, p)
+		})
+	}
+}
@@ -4,22 +4,36 @@
 package serialization
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/aws/amazon-cloudwatch-agent/tool/data"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors/ssm"
 	"github.com/aws/amazon-cloudwatch-agent/tool/runtime"
 	"github.com/aws/amazon-cloudwatch-agent/tool/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	"github.com/aws/amazon-cloudwatch-agent/translator/render"
 )
 
 var Processor processors.Processor = &processor{}
 
+const validationClusterName = "cluster"
+
 type processor struct{}
 
 func (p *processor) Process(ctx *runtime.Context, config *data.Config) {
 	_, resultMap := config.ToMap(ctx)
 	byteArray := util.SerializeResultMapToJsonByteArray(resultMap)
+	if ctx.NonInteractive {
+		if err := output(ctx, byteArray, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 	var filepath string
 	filepath = ctx.ConfigOutputPath
 	if filepath == "" {
@@ -36,5 +50,61 @@ func (p *processor) Process(ctx *runtime.Context, config *data.Config) {
 }
 
 func (p *processor) NextProcessor(ctx *runtime.Context, config *data.Config) interface{} {
+	if ctx != nil && ctx.NonInteractive {
+		return nil
+	}
 	return ssm.Processor
 }
+
+// output prints the config to w without any other message, so that it can be
+// piped to another tool, and writes it to the config output path if set. The
+// config is neither printed nor written if its validation fails.
+func output(ctx *runtime.Context, byteArray []byte, w io.Writer) error {
+	if ctx.ValidateConfig {
+		if err := validate(ctx, byteArray); err != nil {
+			return fmt.Errorf("the generated config is invalid: %w", err)
+		}
+	}
+	if ctx.ConfigOutputPath != "" {
+		if err := os.WriteFile(ctx.ConfigOutputPath, byteArray, 0644); err != nil {
+			return fmt.Errorf("unable to write the config to %s: %w", ctx.ConfigOutputPath, err)
+		}
+	}
+	_, err := fmt.Fprintln(w, string(byteArray))
+	return err
+}
+
+// validate translates the config as the agent would. The messages the
+// translation prints to stdout are moved to stderr to keep stdout for the
+// config.
+func validate(ctx *runtime.Context, byteArray []byte) error {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = stdout }()
+	_, err := render.YAML(byteArray, environment(ctx))
+	if errors.Is(err, render.ErrNoPipelines) {
+		return nil
+	}
+	return err
+}
+
+// environment describes the agent the config is generated for to the
+// translator, so that the validation does not depend on the host running
+// the wizard.
+func environment(ctx *runtime.Context) render.Environment {
+	env := render.Environment{
+		OS:             ctx.OsParameter,
+		Mode:           config.ModeEC2,
+		RunInContainer: ctx.Container != "",
+	}
+	if ctx.IsOnPrem {
+		env.Mode = config.ModeOnPrem
+	}
+	if ctx.Container == util.ContainerEKS {
+		env.KubernetesMode = config.ModeEKS
+		// the agent detects the cluster name from the tags of its node if
+		// the config does not set it
+		env.ClusterName = validationClusterName
+	}
+	return env
+}
@@ -4,13 +4,18 @@
 package serialization
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/aws/amazon-cloudwatch-agent/tool/data"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors/ssm"
 	"github.com/aws/amazon-cloudwatch-agent/tool/runtime"
+	"github.com/aws/amazon-cloudwatch-agent/tool/util"
 )
 
 func TestProcessor_Process(t *testing.T) {
@@ -26,3 +31,51 @@ func TestProcessor_NextProcessor(t *testing.T) {
 	nextProcessor := Processor.NextProcessor(nil, nil)
 	assert.Equal(t, ssm.Processor, nextProcessor)
 }
+
+func TestProcessor_NextProcessorNonInteractive(t *testing.T) {
+	nextProcessor := Processor.NextProcessor(&runtime.Context{NonInteractive: true}, nil)
+	assert.Nil(t, nextProcessor)
+}
+
+func TestOutput(t *testing.T) {
+	valid := []byte(`{"agent": {"region": "us-west-2"}, "logs": {"metrics_collected": {"kubernetes": {"enhanced_container_insights": true}}}}`)
+	invalid := []byte(`{"agent": {"region": "us-west-2"}, "logs": {"metrics_collected": {"ecs": {"enhanced_container_insights": true}}}}`)
+	testCases := map[string]struct {
+		ctx     runtime.Context
+		config  []byte
+		wantErr string
+	}{
+		"WithoutValidation": {
+			ctx:    runtime.Context{OsParameter: util.OsTypeLinux},
+			config: invalid,
+		},
+		"WithValidConfig": {
+			ctx:    runtime.Context{OsParameter: util.OsTypeLinux, Container: util.ContainerEKS, ValidateConfig: true},
+			config: valid,
+		},
+		"WithInvalidConfig": {
+			ctx:     runtime.Context{OsParameter: util.OsTypeLinux, Container: util.ContainerECS, ValidateConfig: true},
+			config:  invalid,
+			wantErr: "the generated config is invalid: invalid json config: /logs/metrics_collected/ecs: Additional property enhanced_container_insights is not allowed",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := testCase.ctx
+			ctx.ConfigOutputPath = filepath.Join(t.TempDir(), "config.json")
+			var stdout bytes.Buffer
+			err := output(&ctx, testCase.config, &stdout)
+			if testCase.wantErr != "" {
+				assert.EqualError(t, err, testCase.wantErr)
+				assert.Empty(t, stdout.String())
+				assert.NoFileExists(t, ctx.ConfigOutputPath)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, string(testCase.config)+"\n", stdout.String())
+			content, err := os.ReadFile(ctx.ConfigOutputPath)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.config, content)
+		})
+	}
+}
@@ -22,4 +22,9 @@ type Context struct {
 	//Xray Daemon Migration
 	TracesOnly                  bool
 	NonInteractiveXrayMigration bool
+
+	//non-interactive config generation
+	NonInteractive bool
+	Container      string //ecs or eks, empty when the agent runs on the host.
+	ValidateConfig bool
 }
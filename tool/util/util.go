@@ -34,6 +34,9 @@ const (
 	OsTypeWindows      = "windows"
 	OsTypeDarwin       = "darwin"
 
+	ContainerECS = "ecs"
+	ContainerEKS = "eks"
+
 	MapKeyMetricsCollectionInterval = "metrics_collection_interval"
 	MapKeyInstances                 = "resources"
 	MapKeyMeasurement               = "measurement"
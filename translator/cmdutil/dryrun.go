@@ -0,0 +1,89 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cmdutil
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/parser"
+	"github.com/aws/amazon-cloudwatch-agent/translator/tocwconfig/totomlconfig"
+)
+
+// dryRunLines is the number of lines of each file the log parsers are run
+// against.
+const dryRunLines = 5
+
+type dryRunFileConfig struct {
+	FilePath string `toml:"file_path"`
+	parser.Config
+}
+
+type dryRunConfig struct {
+	Inputs struct {
+		Logfile []struct {
+			FileConfig []dryRunFileConfig `toml:"file_config"`
+		} `toml:"logfile"`
+	} `toml:"inputs"`
+}
+
+// PreviewLogParsers runs the log parsers of the translated TOML config
+// against the first lines of the first file matching each file path. The
+// lines are parsed one by one, so the multiline events are not previewed.
+func PreviewLogParsers(config interface{}, w io.Writer) error {
+	var cfg dryRunConfig
+	if _, err := toml.Decode(totomlconfig.ToTomlConfig(config), &cfg); err != nil {
+		return fmt.Errorf("unable to decode the log file configs: %w", err)
+	}
+	for _, logfile := range cfg.Inputs.Logfile {
+		for _, fileConfig := range logfile.FileConfig {
+			pipeline, err := parser.New(fileConfig.Config)
+			if err != nil {
+				return fmt.Errorf("invalid parsers for %s: %w", fileConfig.FilePath, err)
+			}
+			if pipeline == nil {
+				continue
+			}
+			if err = previewFile(w, fileConfig.FilePath, pipeline); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func previewFile(w io.Writer, filePath string, pipeline *parser.Pipeline) error {
+	matches, err := filepath.Glob(filePath)
+	if err != nil || len(matches) == 0 {
+		fmt.Fprintf(w, "Log parsers of %s: no file matches, skipped\n", filePath)
+		return nil
+	}
+	fmt.Fprintf(w, "Log parsers of %s (%s):\n", filePath, matches[0])
+	f, err := os.Open(matches[0])
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", matches[0], err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for i := 0; i < dryRunLines && scanner.Scan(); i++ {
+		line := scanner.Text()
+		fmt.Fprintf(w, "  < %s\n", line)
+		parsed, timestamp, ok := pipeline.Parse(line)
+		switch {
+		case !ok:
+			fmt.Fprintln(w, "  ! not parsed, published unchanged")
+		case timestamp.IsZero():
+			fmt.Fprintf(w, "  > %s\n", parsed)
+		default:
+			fmt.Fprintf(w, "  > %s @ %s\n", parsed, timestamp.UTC().Format(time.RFC3339Nano))
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cmdutil
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func logfileConfig(fileConfigs ...map[string]interface{}) map[string]interface{} {
+	list := make([]interface{}, len(fileConfigs))
+	for i, fileConfig := range fileConfigs {
+		list[i] = fileConfig
+	}
+	return map[string]interface{}{
+		"inputs": map[string]interface{}{
+			"logfile": []interface{}{
+				map[string]interface{}{"file_config": list},
+			},
+		},
+	}
+}
+
+func TestPreviewLogParsers(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.log"), []byte("1704164645678 ERROR disk full\nunstructured\n"), 0600))
+
+	config := logfileConfig(
+		map[string]interface{}{
+			"file_path": filepath.Join(dir, "*.log"),
+			"parsers": []interface{}{
+				map[string]interface{}{"type": "regex", "expression": `^(?P<time>\d+) (?P<level>\w+)`},
+			},
+			"timestamp_field": "time",
+		},
+		map[string]interface{}{
+			"file_path": filepath.Join(dir, "missing.log"),
+			"parsers":   []interface{}{map[string]interface{}{"type": "json"}},
+		},
+		map[string]interface{}{
+			"file_path": filepath.Join(dir, "app.log"),
+		},
+	)
+	var out bytes.Buffer
+	require.NoError(t, PreviewLogParsers(config, &out))
+	assert.Equal(t, "Log parsers of "+filepath.Join(dir, "*.log")+" ("+filepath.Join(dir, "app.log")+"):\n"+
+		"  < 1704164645678 ERROR disk full\n"+
+		"  > {\"level\":\"ERROR\",\"time\":\"1704164645678\"} @ 2024-01-02T03:04:05.678Z\n"+
+		"  < unstructured\n"+
+		"  ! not parsed, published unchanged\n"+
+		"Log parsers of "+filepath.Join(dir, "missing.log")+": no file matches, skipped\n", out.String())
+}
+
+func TestPreviewLogParsersInvalid(t *testing.T) {
+	config := logfileConfig(map[string]interface{}{
+		"file_path": "/var/log/app.log",
+		"parsers":   []interface{}{map[string]interface{}{"type": "grok"}},
+	})
+	assert.Error(t, PreviewLogParsers(config, &bytes.Buffer{}))
+}
@@ -0,0 +1,97 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cmdutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/constants"
+	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/migrate"
+	translatorUtil "github.com/aws/amazon-cloudwatch-agent/translator/util"
+)
+
+// MigrateJsonConfigFiles rewrites the deprecated keys of the input JSON config
+// file and of the JSON config files in the input directory to the current
+// schema version, and reports the unknown keys. The files are only reported
+// on with dryRun.
+func MigrateJsonConfigFiles(ctx *context.Context, dryRun bool, w io.Writer) error {
+	var paths []string
+	if path := ctx.InputJsonFilePath(); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	if dir := ctx.InputJsonDirPath(); dir != "" {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || filepath.Ext(strings.TrimSuffix(path, constants.FileSuffixTmp)) == constants.FileSuffixYAML {
+				return nil
+			}
+			paths = append(paths, path)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("unable to scan config dir %v: %w", dir, err)
+		}
+	}
+	for _, path := range paths {
+		if err := migrateJsonConfigFile(path, dryRun, w); err != nil {
+			return fmt.Errorf("unable to migrate %v: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func migrateJsonConfigFile(path string, dryRun bool, w io.Writer) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	jsonConfig, err := translatorUtil.GetJsonMapFromFile(path)
+	if err != nil {
+		return err
+	}
+	report, err := migrate.Migrate(jsonConfig)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s:\n", path)
+	report.Write(w)
+	if dryRun || !report.Changed() {
+		return nil
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	// keep the patterns of the config readable, e.g. the <, > and & of regexes
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(jsonConfig); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), info.Mode().Perm())
+}
+
+// checkSchemaVersion fails on the configs written for a newer agent, and warns
+// about the keys deprecated in the current schema version.
+func checkSchemaVersion(path string, jsonConfig map[string]interface{}) error {
+	report, err := migrate.Check(jsonConfig)
+	if err != nil {
+		return fmt.Errorf("invalid json config %v: %w", path, err)
+	}
+	for _, change := range report.Changes {
+		log.Printf("W! %v uses a deprecated key, run config-translator --migrate to rewrite it: %s", path, change)
+	}
+	return nil
+}
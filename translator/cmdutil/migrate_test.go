@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cmdutil
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/migrate"
+)
+
+func TestMigrateJsonConfigFiles(t *testing.T) {
+	context.ResetContext()
+	defer context.ResetContext()
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, "legacy.json.tmp")
+	current := filepath.Join(dir, "current.json")
+	yaml := filepath.Join(dir, "otel.yaml.tmp")
+	require.NoError(t, os.WriteFile(legacy, []byte(`{"logs": {"metrics_collected": {"app_signals": {}}, "log_stream_name": "<host>"}}`), 0600))
+	require.NoError(t, os.WriteFile(current, []byte(`{"schema_version": 2, "agent": {"regoin": "us-east-1"}}`), 0644))
+	require.NoError(t, os.WriteFile(yaml, []byte("receivers: {}"), 0600))
+	ctx := context.CurrentContext()
+	ctx.SetInputJsonDirPath(dir)
+
+	var out bytes.Buffer
+	require.NoError(t, MigrateJsonConfigFiles(ctx, true, &out))
+	assert.Equal(t, current+":\n"+
+		"Already at schema version 2\n"+
+		"Unknown keys:\n"+
+		"  /agent/regoin, did you mean region?\n"+
+		legacy+":\n"+
+		"Migrated from schema version 1 to 2:\n"+
+		"  renamed /logs/metrics_collected/app_signals to /logs/metrics_collected/application_signals\n", out.String())
+	// the files are not rewritten in a dry run
+	content, err := os.ReadFile(legacy)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "app_signals")
+
+	out.Reset()
+	require.NoError(t, MigrateJsonConfigFiles(ctx, false, &out))
+	content, err = os.ReadFile(legacy)
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "logs": {
+    "log_stream_name": "<host>",
+    "metrics_collected": {
+      "application_signals": {}
+    }
+  },
+  "schema_version": 2
+}
+`, string(content))
+	info, err := os.Stat(legacy)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	content, err = os.ReadFile(yaml)
+	require.NoError(t, err)
+	assert.Equal(t, "receivers: {}", string(content))
+}
+
+func TestMigrateJsonConfigFilesNewerVersion(t *testing.T) {
+	context.ResetContext()
+	defer context.ResetContext()
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"schema_version": 3}`), 0600))
+	ctx := context.CurrentContext()
+	ctx.SetInputJsonFilePath(path)
+
+	var out bytes.Buffer
+	assert.ErrorIs(t, MigrateJsonConfigFiles(ctx, false, &out), migrate.ErrNewerSchemaVersion)
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	assert.NoError(t, checkSchemaVersion("config.json", map[string]interface{}{"csm": map[string]interface{}{}}))
+	assert.ErrorIs(t, checkSchemaVersion("config.json", map[string]interface{}{"schema_version": float64(3)}), migrate.ErrNewerSchemaVersion)
+}
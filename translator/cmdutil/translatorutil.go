@@ -4,7 +4,9 @@
 package cmdutil
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -19,6 +21,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/config"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
 	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/mergeJsonUtil"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/registerrules"
 	"github.com/aws/amazon-cloudwatch-agent/translator/tocwconfig/toenvconfig"
 	"github.com/aws/amazon-cloudwatch-agent/translator/tocwconfig/totomlconfig"
@@ -55,7 +58,7 @@ func getCurBinaryPath() string {
 	return filepath.Dir(ex)
 }
 
-func getJsonConfigMap(jsonConfigFilePath, osType string) (map[string]interface{}, error) {
+func getJsonConfigMap(jsonConfigFilePath, osType string) (map[string]interface{}, *mergeJsonUtil.Source, error) {
 	if jsonConfigFilePath == "" {
 		curPath := getCurBinaryPath()
 		if osType == config.OS_TYPE_WINDOWS {
@@ -68,11 +71,22 @@ func getJsonConfigMap(jsonConfigFilePath, osType string) (map[string]interface{}
 	}
 	log.Printf("Reading json config file path: %v ...", jsonConfigFilePath)
 	if _, err := os.Stat(jsonConfigFilePath); err != nil {
-		fmt.Printf("%v does not exist or cannot read. Skipping it.\n", jsonConfigFilePath)
-		return nil, nil
+		log.Printf("%v does not exist or cannot read. Skipping it.", jsonConfigFilePath)
+		return nil, nil, nil
 	}
 
-	return translatorUtil.GetJsonMapFromFile(jsonConfigFilePath)
+	content, err := os.ReadFile(jsonConfigFilePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	jsonConfigMap, err := translatorUtil.GetJsonMapFromJsonBytes(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = checkSchemaVersion(jsonConfigFilePath, jsonConfigMap); err != nil {
+		return nil, nil, err
+	}
+	return jsonConfigMap, mergeJsonUtil.NewSource(jsonConfigFilePath, content), nil
 }
 
 func GetTomlConfigPath(tomlFilePath string) string {
@@ -110,16 +124,19 @@ func GenerateMergedJsonConfigMap(ctx *context.Context) (map[string]interface{},
 	// for the append operation when the existing file name and new .tmp file name have diff
 	// only for the ".tmp" suffix, i.e. it is override operation even it says append.
 	var jsonConfigMapMap = make(map[string]map[string]interface{})
+	// the files of the configs, for the locations of the conflicts
+	var sources = make(map[string]*mergeJsonUtil.Source)
 
 	if ctx.MultiConfig() == "append" || ctx.MultiConfig() == "remove" {
 		// backwards compatible for the old json config file
 		// this backwards compatible file can be treated as existing files
-		jsonConfigMap, err := getJsonConfigMap(ctx.InputJsonFilePath(), ctx.Os())
+		jsonConfigMap, source, err := getJsonConfigMap(ctx.InputJsonFilePath(), ctx.Os())
 		if err != nil {
 			return nil, fmt.Errorf("unable to get old json config file with error: %v", err)
 		}
 		if jsonConfigMap != nil {
 			jsonConfigMapMap[ctx.InputJsonFilePath()] = jsonConfigMap
+			sources[ctx.InputJsonFilePath()] = source
 		}
 	}
 
@@ -127,7 +144,7 @@ func GenerateMergedJsonConfigMap(ctx *context.Context) (map[string]interface{},
 		ctx.InputJsonDirPath(),
 		func(path string, info os.FileInfo, err error) error {
 			if err != nil {
-				fmt.Printf("Cannot access %v: %v \n", path, err)
+				log.Printf("Cannot access %v: %v", path, err)
 				return err
 			}
 			if envconfig.IsWindowsHostProcessContainer() {
@@ -161,12 +178,13 @@ func GenerateMergedJsonConfigMap(ctx *context.Context) (map[string]interface{},
 					return nil
 				}
 				if ctx.MultiConfig() == "default" || ctx.MultiConfig() == "append" {
-					jsonConfigMap, err := getJsonConfigMap(path, ctx.Os())
+					jsonConfigMap, source, err := getJsonConfigMap(path, ctx.Os())
 					if err != nil {
 						return err
 					}
 					if jsonConfigMap != nil {
 						jsonConfigMapMap[key] = jsonConfigMap
+						sources[key] = source
 					}
 				}
 			} else if ext == constants.FileSuffixYAML {
@@ -175,13 +193,14 @@ func GenerateMergedJsonConfigMap(ctx *context.Context) (map[string]interface{},
 			} else {
 				// non .tmp / existing files
 				if ctx.MultiConfig() == "append" || ctx.MultiConfig() == "remove" {
-					jsonConfigMap, err := getJsonConfigMap(path, ctx.Os())
+					jsonConfigMap, source, err := getJsonConfigMap(path, ctx.Os())
 					if err != nil {
 						return err
 					}
 					if jsonConfigMap != nil {
 						if _, ok := jsonConfigMapMap[path]; !ok {
 							jsonConfigMapMap[path] = jsonConfigMap
+							sources[path] = source
 						}
 					}
 				}
@@ -201,7 +220,11 @@ func GenerateMergedJsonConfigMap(ctx *context.Context) (map[string]interface{},
 			if err != nil {
 				return nil, fmt.Errorf("unable to get json map from environment variable %v with error: %v", config.CWConfigContent, err)
 			}
+			if err = checkSchemaVersion(config.CWConfigContent, jm); err != nil {
+				return nil, err
+			}
 			jsonConfigMapMap[config.CWConfigContent] = jm
+			sources[config.CWConfigContent] = mergeJsonUtil.NewSource(config.CWConfigContent, []byte(jsonConfigContent))
 		}
 	}
 
@@ -209,16 +232,57 @@ func GenerateMergedJsonConfigMap(ctx *context.Context) (map[string]interface{},
 	if err != nil {
 		return nil, err
 	}
-	mergedJsonConfigMap, err := jsonconfig.MergeJsonConfigMaps(jsonConfigMapMap, defaultConfig, ctx.MultiConfig())
+	mergedJsonConfigMap, err := jsonconfig.MergeJsonConfigMaps(jsonConfigMapMap, sources, defaultConfig, ctx.MultiConfig())
 	if err != nil {
 		return nil, err
 	}
 
+	RemoveUnsupportedSections(mergedJsonConfigMap, ctx.Os(), ctx.Arch())
+
 	// Json Schema Validation by gojsonschema
 	checkSchema(mergedJsonConfigMap)
 	return mergedJsonConfigMap, nil
 }
 
+// WriteMergedJsonConfig writes the effective json config merged from the
+// config files, with the keys sorted so that it can be diffed.
+func WriteMergedJsonConfig(jsonConfigMap map[string]interface{}, w io.Writer) error {
+	b, err := json.MarshalIndent(jsonConfigMap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal the merged json config: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+// RemoveUnsupportedSections removes the sections of the collectors that are not
+// available on the platform, so that the agent starts without them instead of
+// failing.
+func RemoveUnsupportedSections(jsonConfigMap map[string]interface{}, os, arch string) {
+	for _, path := range config.UnsupportedSections(os, arch) {
+		if removeSection(jsonConfigMap, strings.Split(path, "/")) {
+			log.Printf("W! %s is not supported on %s/%s and is ignored.", path, os, arch)
+		}
+	}
+}
+
+// removeSection removes the section at the path, and its parents left empty.
+func removeSection(section map[string]interface{}, keys []string) bool {
+	if len(keys) == 1 {
+		_, ok := section[keys[0]]
+		delete(section, keys[0])
+		return ok
+	}
+	child, ok := section[keys[0]].(map[string]interface{})
+	if !ok || !removeSection(child, keys[1:]) {
+		return false
+	}
+	if len(child) == 0 {
+		delete(section, keys[0])
+	}
+	return true
+}
+
 func TranslateJsonMapToTomlConfig(jsonConfigValue interface{}) (interface{}, error) {
 	r := new(translate.Translator)
 	_, val := r.ApplyRule(jsonConfigValue)
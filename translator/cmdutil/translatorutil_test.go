@@ -4,6 +4,7 @@
 package cmdutil
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
 )
 
 func TestTranslateJsonMapToEnvConfigFile(t *testing.T) {
@@ -38,3 +40,58 @@ func TestTranslateJsonMapToEnvConfigFile(t *testing.T) {
 	assert.Equal(t, expectedJson[envconfig.CWAGENT_LOG_LEVEL], actualJson[envconfig.CWAGENT_LOG_LEVEL])
 	assert.Equal(t, expectedJson[envconfig.AWS_SDK_LOG_LEVEL], actualJson[envconfig.AWS_SDK_LOG_LEVEL])
 }
+
+func TestRemoveUnsupportedSections(t *testing.T) {
+	newConfig := func() map[string]interface{} {
+		return map[string]interface{}{
+			"metrics": map[string]interface{}{
+				"metrics_collected": map[string]interface{}{
+					"nvidia_gpu": map[string]interface{}{},
+					"Processor":  map[string]interface{}{},
+				},
+			},
+		}
+	}
+
+	jsonConfigMap := newConfig()
+	RemoveUnsupportedSections(jsonConfigMap, config.OS_TYPE_WINDOWS, config.ARCH_TYPE_AMD64)
+	assert.Equal(t, newConfig(), jsonConfigMap)
+
+	RemoveUnsupportedSections(jsonConfigMap, config.OS_TYPE_WINDOWS, config.ARCH_TYPE_ARM64)
+	assert.Equal(t, map[string]interface{}{
+		"metrics": map[string]interface{}{
+			"metrics_collected": map[string]interface{}{
+				"Processor": map[string]interface{}{},
+			},
+		},
+	}, jsonConfigMap)
+
+	// the sections left empty are removed
+	jsonConfigMap = map[string]interface{}{
+		"agent": map[string]interface{}{},
+		"metrics": map[string]interface{}{
+			"metrics_collected": map[string]interface{}{
+				"nvidia_gpu": map[string]interface{}{},
+			},
+		},
+	}
+	RemoveUnsupportedSections(jsonConfigMap, config.OS_TYPE_WINDOWS, config.ARCH_TYPE_ARM64)
+	assert.Equal(t, map[string]interface{}{"agent": map[string]interface{}{}}, jsonConfigMap)
+}
+
+func TestWriteMergedJsonConfig(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteMergedJsonConfig(map[string]interface{}{
+		"logs":  map[string]interface{}{"force_flush_interval": 5},
+		"agent": map[string]interface{}{"region": "us-east-1"},
+	}, &buf))
+	assert.Equal(t, `{
+  "agent": {
+    "region": "us-east-1"
+  },
+  "logs": {
+    "force_flush_interval": 5
+  }
+}
+`, buf.String())
+}
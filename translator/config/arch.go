@@ -0,0 +1,48 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+var supportedArch = [...]string{ARCH_TYPE_AMD64, ARCH_TYPE_ARM64}
+
+const (
+	ARCH_TYPE_AMD64 = "amd64"
+	ARCH_TYPE_ARM64 = "arm64"
+)
+
+// unsupportedSections are the sections of the json config with collectors
+// that are not available on an os/arch platform.
+var unsupportedSections = map[string][]string{
+	// there are no NVIDIA drivers providing nvidia-smi on Windows on ARM
+	OS_TYPE_WINDOWS + "/" + ARCH_TYPE_ARM64: {
+		"metrics/metrics_collected/nvidia_gpu",
+	},
+}
+
+func ToValidArch(arch string) string {
+	if arch == "" {
+		// Give it a last try, using current arch type
+		arch = runtime.GOARCH
+	}
+
+	formattedArch := strings.ToLower(arch)
+	for _, val := range supportedArch {
+		if formattedArch == val {
+			return formattedArch
+		}
+	}
+
+	panic(fmt.Sprintf("%v is not a supported arch type", arch))
+}
+
+// UnsupportedSections returns the paths of the json config sections that are
+// not supported on the os and arch.
+func UnsupportedSections(os, arch string) []string {
+	return unsupportedSections[os+"/"+arch]
+}
@@ -32,6 +32,7 @@ func ResetContext() {
 
 type Context struct {
 	os                  string
+	arch                string
 	inputJsonFilePath   string
 	inputJsonDirPath    string
 	multiConfig         string
@@ -46,6 +47,7 @@ type Context struct {
 	runInContainer      bool
 	agentLogFile        string
 	omitHostname        bool
+	imdsV1Disabled      bool
 }
 
 func (ctx *Context) Os() string {
@@ -56,6 +58,14 @@ func (ctx *Context) SetOs(os string) {
 	ctx.os = config.ToValidOs(os)
 }
 
+func (ctx *Context) Arch() string {
+	return ctx.arch
+}
+
+func (ctx *Context) SetArch(arch string) {
+	ctx.arch = config.ToValidArch(arch)
+}
+
 func (ctx *Context) InputJsonFilePath() string {
 	return ctx.inputJsonFilePath
 }
@@ -193,3 +203,11 @@ func (ctx *Context) GetOmitHostname() bool {
 func (ctx *Context) SetOmitHostname(omitHostname bool) {
 	ctx.omitHostname = omitHostname
 }
+
+func (ctx *Context) IMDSv1Disabled() bool {
+	return ctx.imdsV1Disabled
+}
+
+func (ctx *Context) SetIMDSv1Disabled(imdsV1Disabled bool) {
+	ctx.imdsV1Disabled = imdsV1Disabled
+}
@@ -17,7 +17,10 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/util/ecsutil"
 )
 
-func MergeJsonConfigMaps(jsonConfigMapMap map[string]map[string]interface{}, defaultJsonConfigMap map[string]interface{}, multiConfig string) (map[string]interface{}, error) {
+// MergeJsonConfigMaps merges the json configs in the order of their names. The
+// sources are the files of the configs by name, and give the locations of the
+// conflicting values in the errors.
+func MergeJsonConfigMaps(jsonConfigMapMap map[string]map[string]interface{}, sources map[string]*mergeJsonUtil.Source, defaultJsonConfigMap map[string]interface{}, multiConfig string) (map[string]interface{}, error) {
 	if len(jsonConfigMapMap) == 0 {
 		if os.Getenv(config.USE_DEFAULT_CONFIG) == config.USE_DEFAULT_CONFIG_TRUE {
 			// When USE_DEFAULT_CONFIG is true, ECS and EKS will be supposed to use different default config. EKS default config logic will be added when necessary
@@ -41,15 +44,24 @@ func MergeJsonConfigMaps(jsonConfigMapMap map[string]map[string]interface{}, def
 	 *	  a. merge them into one instance if they are exactly the same,
 	 *	  b. otherwise, make them as separate instances (as list) if possible,
 	 *	  c. fail the operation if list is not allowed for that plugin.
+	 * 3. A section has to be an object in all of them, or a list for the sections merged as lists.
+	 * The conflicts are reported with the files and lines of the two values.
 	 */
 
-	keys := make([]string, len(jsonConfigMapMap))
+	keys := make([]string, 0, len(jsonConfigMapMap))
 	for key := range jsonConfigMapMap {
 		keys = append(keys, key)
 	}
 	sort.Strings(keys)
 
+	mergeJsonUtil.ResetSources()
+	defer mergeJsonUtil.ResetSources()
 	for _, k := range keys {
+		source, ok := sources[k]
+		if !ok {
+			source = &mergeJsonUtil.Source{Name: k}
+		}
+		mergeJsonUtil.SetSource(source)
 		Merge(jsonConfigMapMap[k], resultMap)
 	}
 
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/mergeJsonUtil"
 	"github.com/aws/amazon-cloudwatch-agent/translator/util"
 )
 
@@ -53,16 +54,8 @@ func TestMergeJsonConfigMaps(t *testing.T) {
 func executeTest(t *testing.T, testData TestData) {
 	log.Printf("Test %v %v started", testData.testId, testData.testName)
 	defer shouldFail(t, testData)
-	jsonConfigMapMap := make(map[string]map[string]interface{})
-	for i := 0; i < testData.inputJsonFileNumber; i++ {
-		jsonFileName := fmt.Sprintf("./sampleJsonConfig/test_%v/input_%v.json", testData.testId, i+1)
-		jsonConfigMap, err := util.GetJsonMapFromFile(jsonFileName)
-		if err != nil {
-			t.Fatalf("Failed to get json map from %v with error: %v", jsonFileName, err)
-		}
-		jsonConfigMapMap[jsonFileName] = jsonConfigMap
-	}
-	resultMap, err := MergeJsonConfigMaps(jsonConfigMapMap, nil, "default")
+	jsonConfigMapMap, sources := readInputJsonConfigs(t, testData.testId, testData.inputJsonFileNumber)
+	resultMap, err := MergeJsonConfigMaps(jsonConfigMapMap, sources, nil, "default")
 	if err != nil {
 		t.Fatalf("Failed to merge json maps with error: %v", err)
 	}
@@ -81,6 +74,62 @@ func executeTest(t *testing.T, testData TestData) {
 	assert.Truef(t, reflect.DeepEqual(expectedOutputMap, resultMap), "Test %v %v failed: expectedMap=\n%v\nresultMap=\n%v", testData.testId, testData.testName, string(expectedOutputBytes), string(resultBytes))
 }
 
+func readInputJsonConfigs(t *testing.T, testId, fileNumber int) (map[string]map[string]interface{}, map[string]*mergeJsonUtil.Source) {
+	jsonConfigMapMap := make(map[string]map[string]interface{})
+	sources := make(map[string]*mergeJsonUtil.Source)
+	for i := 0; i < fileNumber; i++ {
+		jsonFileName := fmt.Sprintf("./sampleJsonConfig/test_%v/input_%v.json", testId, i+1)
+		content, err := os.ReadFile(jsonFileName)
+		if err != nil {
+			t.Fatalf("Failed to read %v with error: %v", jsonFileName, err)
+		}
+		jsonConfigMap, err := util.GetJsonMapFromJsonBytes(content)
+		if err != nil {
+			t.Fatalf("Failed to get json map from %v with error: %v", jsonFileName, err)
+		}
+		jsonConfigMapMap[jsonFileName] = jsonConfigMap
+		sources[jsonFileName] = mergeJsonUtil.NewSource(jsonFileName, content)
+	}
+	return jsonConfigMapMap, sources
+}
+
+func TestMergeJsonConfigMapsConflictLocations(t *testing.T) {
+	testCases := map[string]struct {
+		testId   int
+		expected []string
+	}{
+		"Scalar": {
+			testId: 6,
+			expected: []string{
+				`Under path : /agent/metrics_collection_interval | Error : Different values are specified for metrics_collection_interval: 10 in ./sampleJsonConfig/test_6/input_1.json:3 and 60 in ./sampleJsonConfig/test_6/input_2.json:3`,
+			},
+		},
+		"NestedObject": {
+			testId: 14,
+			expected: []string{
+				`Under path : /metrics/metrics_collected/cpu | Error : Different values are specified for cpu/metrics_collection_interval: 10 in ./sampleJsonConfig/test_14/input_1.json:7 and 30 in ./sampleJsonConfig/test_14/input_2.json:6`,
+			},
+		},
+		"SectionType": {
+			testId: 15,
+			expected: []string{
+				`Under path : /logs/logs_collected | Error : logs_collected must be an object, got ["files"] in ./sampleJsonConfig/test_15/input_2.json:3`,
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			translator.ResetMessages()
+			defer translator.ResetMessages()
+			jsonConfigMapMap, sources := readInputJsonConfigs(t, testCase.testId, 2)
+			assert.Panics(t, func() {
+				_, _ = MergeJsonConfigMaps(jsonConfigMapMap, sources, nil, "default")
+			})
+			assert.Equal(t, testCase.expected, translator.ErrorMessages)
+		})
+	}
+}
+
 func shouldFail(t *testing.T, testData TestData) {
 	if r := recover(); r != nil {
 		if val, ok := r.(string); ok {
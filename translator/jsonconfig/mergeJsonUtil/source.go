@@ -0,0 +1,100 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package mergeJsonUtil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Source is a json config file being merged, with the lines of its keys.
+type Source struct {
+	Name string
+	// lines are the lines of the keys by their path, e.g. /agent/region.
+	lines map[string]int
+}
+
+// NewSource indexes the lines of the keys of the json content. The lines are
+// left out if the content is not valid json.
+func NewSource(name string, content []byte) *Source {
+	return &Source{Name: name, lines: indexLines(content)}
+}
+
+// Location is the file and line of the key at the path, e.g. /etc/a.json:12.
+func (s *Source) Location(path string) string {
+	if s == nil {
+		return "an unknown file"
+	}
+	if line, ok := s.lines[path]; ok {
+		return fmt.Sprintf("%s:%d", s.Name, line)
+	}
+	return s.Name
+}
+
+var (
+	// currentSource is the file merged by the MergeRules.
+	currentSource *Source
+	// origins are the files the values of the merged config come from, by path.
+	origins = map[string]*Source{}
+)
+
+// SetSource sets the file the next MergeRules are applied to.
+func SetSource(source *Source) {
+	currentSource = source
+}
+
+// ResetSources forgets the files of the previous merge.
+func ResetSources() {
+	currentSource = nil
+	origins = map[string]*Source{}
+}
+
+func indexLines(content []byte) map[string]int {
+	lines := map[string]int{}
+	dec := json.NewDecoder(bytes.NewReader(content))
+	line, offset := 1, int64(0)
+	lineOf := func() int {
+		end := dec.InputOffset()
+		line += bytes.Count(content[offset:end], []byte("\n"))
+		offset = end
+		return line
+	}
+	var walk func(path string) error
+	walk = func(path string) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				if tok, err = dec.Token(); err != nil {
+					return err
+				}
+				key := fmt.Sprint(tok)
+				lines[path+key] = lineOf()
+				if err = walk(path + key + "/"); err != nil {
+					return err
+				}
+			}
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				if err = walk(path + strconv.Itoa(i) + "/"); err != nil {
+					return err
+				}
+			}
+		default:
+			return nil
+		}
+		// the closing delimiter
+		_, err = dec.Token()
+		return err
+	}
+	if err := walk("/"); err != nil {
+		return nil
+	}
+	return lines
+}
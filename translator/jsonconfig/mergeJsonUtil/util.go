@@ -4,8 +4,12 @@
 package mergeJsonUtil
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/aws/amazon-cloudwatch-agent/translator"
 	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/mergeJsonRule"
@@ -18,6 +22,11 @@ func MergeMap(source map[string]interface{}, result map[string]interface{}, sect
 	subMapSource, exists := GetSubMap(source, sectionKey)
 	subMapResult, _ := GetSubMap(result, sectionKey)
 	if !exists {
+		if value, ok := source[sectionKey]; ok {
+			// dropping it would hide it from the schema validation of the merged config
+			key := strings.TrimSuffix(path, "/")
+			translator.AddErrorMessages(key, fmt.Sprintf("%v must be an object, got %s in %s", sectionKey, describe(value), currentSource.Location(key)))
+		}
 		return
 	}
 	if len(subMapSource) == 0 {
@@ -37,9 +46,12 @@ func mergeMap(sourceMap map[string]interface{}, resultMap map[string]interface{}
 		} else if existingValue, ok := resultMap[key]; !ok {
 			// only one defines the value
 			resultMap[key] = value
+			origins[path+key] = currentSource
 		} else if !reflect.DeepEqual(existingValue, value) {
 			// fail if different values are defined
-			translator.AddErrorMessages(fmt.Sprintf("%s%s", path, key), fmt.Sprintf("Different values are specified for %v", key))
+			diffPath, existingDiff, diff := firstDifference(path+key, existingValue, value)
+			translator.AddErrorMessages(fmt.Sprintf("%s%s", path, key), fmt.Sprintf("Different values are specified for %v: %s in %s and %s in %s",
+				strings.TrimPrefix(diffPath, path), describe(existingDiff), origins[path+key].Location(diffPath), describe(diff), currentSource.Location(diffPath)))
 		}
 		// the same value is defined by multiple sources
 	}
@@ -103,3 +115,53 @@ func GetSubList(sourceMap map[string]interface{}, subKey string) []interface{} {
 	}
 	return resultList
 }
+
+// firstDifference returns the path of the first value in the objects and lists
+// that differs, and the values.
+func firstDifference(path string, a, b interface{}) (string, interface{}, interface{}) {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(a))
+		for key := range a {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			other, ok := b[key]
+			if !ok {
+				break
+			}
+			if !reflect.DeepEqual(a[key], other) {
+				return firstDifference(path+"/"+key, a[key], other)
+			}
+		}
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			break
+		}
+		for i := range a {
+			if !reflect.DeepEqual(a[i], b[i]) {
+				return firstDifference(path+"/"+strconv.Itoa(i), a[i], b[i])
+			}
+		}
+	}
+	return path, a, b
+}
+
+// describe formats the value for the errors.
+func describe(value interface{}) string {
+	const maxLen = 80
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	if len(b) > maxLen {
+		return string(b[:maxLen]) + "..."
+	}
+	return string(b)
+}
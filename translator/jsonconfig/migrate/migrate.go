@@ -0,0 +1,210 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+const (
+	SchemaVersionKey = "schema_version"
+	// CurrentSchemaVersion is the version of the agent JSON schema supported by
+	// this agent. The configs without a schema version are version 1.
+	CurrentSchemaVersion = 2
+	initialSchemaVersion = 1
+)
+
+var ErrNewerSchemaVersion = errors.New("the config is written for a newer agent")
+
+// rename moves the value of a deprecated key to its replacement in the same object.
+type rename struct {
+	// version is the schema version deprecating the key.
+	version int
+	path    []string
+	to      string
+}
+
+// removal removes a key that is ignored by the agent.
+type removal struct {
+	version int
+	path    []string
+	reason  string
+}
+
+var (
+	renames = []rename{
+		{version: 2, path: []string{"logs", "metrics_collected", "app_signals"}, to: "application_signals"},
+		{version: 2, path: []string{"traces", "traces_collected", "app_signals"}, to: "application_signals"},
+	}
+	removals = []removal{
+		{version: 2, path: []string{"csm"}, reason: "CSM is no longer supported"},
+	}
+)
+
+// UnknownKey is a key of the config that is not in the schema.
+type UnknownKey struct {
+	Path string
+	// Suggestion is the closest key of the schema, if any.
+	Suggestion string
+}
+
+type Report struct {
+	FromVersion int
+	// Changes are the rewrites of the deprecated keys, in the order they were applied.
+	Changes []string
+	Unknown []UnknownKey
+}
+
+// Changed returns true if the config has been rewritten, which includes setting
+// the schema version.
+func (r *Report) Changed() bool {
+	return r.FromVersion < CurrentSchemaVersion || len(r.Changes) > 0
+}
+
+func (r *Report) Write(w io.Writer) {
+	if r.Changed() {
+		fmt.Fprintf(w, "Migrated from schema version %d to %d:\n", r.FromVersion, CurrentSchemaVersion)
+		for _, change := range r.Changes {
+			fmt.Fprintf(w, "  %s\n", change)
+		}
+		if len(r.Changes) == 0 {
+			fmt.Fprintln(w, "  no deprecated keys")
+		}
+	} else {
+		fmt.Fprintf(w, "Already at schema version %d\n", CurrentSchemaVersion)
+	}
+	if len(r.Unknown) > 0 {
+		fmt.Fprintln(w, "Unknown keys:")
+		for _, unknown := range r.Unknown {
+			if unknown.Suggestion != "" {
+				fmt.Fprintf(w, "  %s, did you mean %s?\n", unknown.Path, unknown.Suggestion)
+			} else {
+				fmt.Fprintf(w, "  %s\n", unknown.Path)
+			}
+		}
+	}
+}
+
+// Migrate rewrites the deprecated keys of the JSON config to the current
+// schema version and reports the keys that are not in the schema.
+func Migrate(jsonConfig map[string]interface{}) (*Report, error) {
+	version, err := SchemaVersion(jsonConfig)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{FromVersion: version}
+	for _, r := range renames {
+		if version >= r.version {
+			continue
+		}
+		parentPath, key := r.path[:len(r.path)-1], r.path[len(r.path)-1]
+		parent, ok := lookup(jsonConfig, parentPath)
+		if !ok {
+			continue
+		}
+		value, ok := parent[key]
+		if !ok {
+			continue
+		}
+		delete(parent, key)
+		from, to := formatPath(r.path), formatPath(parentPath)+"/"+r.to
+		if _, ok = parent[r.to]; ok {
+			report.Changes = append(report.Changes, fmt.Sprintf("removed %s since %s is already set", from, to))
+			continue
+		}
+		parent[r.to] = value
+		report.Changes = append(report.Changes, fmt.Sprintf("renamed %s to %s", from, to))
+	}
+	for _, r := range removals {
+		if version >= r.version {
+			continue
+		}
+		parentPath, key := r.path[:len(r.path)-1], r.path[len(r.path)-1]
+		parent, ok := lookup(jsonConfig, parentPath)
+		if !ok {
+			continue
+		}
+		if _, ok = parent[key]; !ok {
+			continue
+		}
+		delete(parent, key)
+		report.Changes = append(report.Changes, fmt.Sprintf("removed %s: %s", formatPath(r.path), r.reason))
+	}
+	if version < CurrentSchemaVersion {
+		jsonConfig[SchemaVersionKey] = CurrentSchemaVersion
+	}
+	report.Unknown, err = findUnknownKeys(jsonConfig)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Check reports what Migrate would rewrite without changing the JSON config.
+func Check(jsonConfig map[string]interface{}) (*Report, error) {
+	return Migrate(deepCopy(jsonConfig).(map[string]interface{}))
+}
+
+// SchemaVersion returns the schema version of the JSON config. Returns an
+// error if the config is written for a newer schema version.
+func SchemaVersion(jsonConfig map[string]interface{}) (int, error) {
+	value, ok := jsonConfig[SchemaVersionKey]
+	if !ok {
+		return initialSchemaVersion, nil
+	}
+	var version float64
+	switch v := value.(type) {
+	case float64:
+		version = v
+	case int:
+		version = float64(v)
+	}
+	if version != math.Trunc(version) || version < initialSchemaVersion {
+		return 0, fmt.Errorf("invalid %s %v", SchemaVersionKey, value)
+	}
+	if version > CurrentSchemaVersion {
+		return 0, fmt.Errorf("%w: %s %v, this agent supports up to %d", ErrNewerSchemaVersion, SchemaVersionKey, value, CurrentSchemaVersion)
+	}
+	return int(version), nil
+}
+
+func lookup(jsonConfig map[string]interface{}, path []string) (map[string]interface{}, bool) {
+	current := jsonConfig
+	for _, key := range path {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
+
+// formatPath formats the path the same way as the schema validation errors.
+func formatPath(path []string) string {
+	return "/" + strings.Join(path, "/")
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = deepCopy(item)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, item := range v {
+			s[i] = deepCopy(item)
+		}
+		return s
+	default:
+		return v
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package migrate
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, content string) map[string]interface{} {
+	t.Helper()
+	var jsonConfig map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(content), &jsonConfig))
+	return jsonConfig
+}
+
+func TestMigrate(t *testing.T) {
+	jsonConfig := parse(t, `{
+		"csm": {"port": 31000},
+		"logs": {"metrics_collected": {"app_signals": {"hosted_in": "cluster"}}},
+		"traces": {"traces_collected": {"app_signals": {}, "application_signals": {}}}
+	}`)
+	report, err := Migrate(jsonConfig)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.FromVersion)
+	assert.Equal(t, []string{
+		"renamed /logs/metrics_collected/app_signals to /logs/metrics_collected/application_signals",
+		"removed /traces/traces_collected/app_signals since /traces/traces_collected/application_signals is already set",
+		"removed /csm: CSM is no longer supported",
+	}, report.Changes)
+	assert.Empty(t, report.Unknown)
+	assert.Equal(t, map[string]interface{}{
+		"schema_version": CurrentSchemaVersion,
+		"logs":           map[string]interface{}{"metrics_collected": map[string]interface{}{"application_signals": map[string]interface{}{"hosted_in": "cluster"}}},
+		"traces":         map[string]interface{}{"traces_collected": map[string]interface{}{"application_signals": map[string]interface{}{}}},
+	}, jsonConfig)
+
+	// the migrated config is not rewritten again
+	report, err = Migrate(jsonConfig)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion, report.FromVersion)
+	assert.False(t, report.Changed())
+}
+
+func TestMigrateCurrentVersion(t *testing.T) {
+	// the deprecated keys are still supported by the current schema version
+	jsonConfig := parse(t, `{"schema_version": 2, "logs": {"metrics_collected": {"app_signals": {}}}}`)
+	report, err := Check(jsonConfig)
+	require.NoError(t, err)
+	assert.False(t, report.Changed())
+	assert.Empty(t, report.Unknown)
+}
+
+func TestCheck(t *testing.T) {
+	content := `{"logs": {"metrics_collected": {"app_signals": {}}}}`
+	jsonConfig := parse(t, content)
+	report, err := Check(jsonConfig)
+	require.NoError(t, err)
+	assert.True(t, report.Changed())
+	assert.Equal(t, parse(t, content), jsonConfig)
+}
+
+func TestSchemaVersion(t *testing.T) {
+	testCases := map[string]struct {
+		content string
+		want    int
+		wantErr bool
+	}{
+		"WithoutVersion":     {content: `{}`, want: 1},
+		"WithCurrentVersion": {content: `{"schema_version": 2}`, want: 2},
+		"WithNewerVersion":   {content: `{"schema_version": 3}`, wantErr: true},
+		"WithFraction":       {content: `{"schema_version": 1.5}`, wantErr: true},
+		"WithZero":           {content: `{"schema_version": 0}`, wantErr: true},
+		"WithString":         {content: `{"schema_version": "2"}`, wantErr: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := SchemaVersion(parse(t, testCase.content))
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+
+	_, err := Migrate(parse(t, `{"schema_version": 3}`))
+	assert.ErrorIs(t, err, ErrNewerSchemaVersion)
+}
+
+func TestUnknownKeys(t *testing.T) {
+	jsonConfig := parse(t, `{
+		"schema_version": 2,
+		"agent": {"metrics_collection_interva": 60, "region": "us-east-1"},
+		"metric": {},
+		"custom": {},
+		"metrics": {
+			"append_dimensions": {"InstanceId": "${aws:InstanceId}"},
+			"metrics_collected": {"cpu": {"measurment": ["usage_idle"], "totalcpu": true}}
+		},
+		"logs": {
+			"logs_collected": {
+				"files": {"collect_list": [{"file_path": "/var/log/app.log", "log_grup_name": "app"}]},
+				"syslog": {}
+			}
+		}
+	}`)
+	report, err := Migrate(jsonConfig)
+	require.NoError(t, err)
+	assert.Equal(t, []UnknownKey{
+		{Path: "/agent/metrics_collection_interva", Suggestion: "metrics_collection_interval"},
+		{Path: "/logs/logs_collected/files/collect_list/0/log_grup_name", Suggestion: "log_group_name"},
+		{Path: "/logs/logs_collected/syslog", Suggestion: ""},
+		{Path: "/metric", Suggestion: "metrics"},
+		{Path: "/metrics/metrics_collected/cpu/measurment", Suggestion: "measurement"},
+	}, report.Unknown)
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := map[string]interface{}{"log_group_name": nil, "log_stream_name": nil, "file_path": nil}
+	assert.Equal(t, "log_group_name", suggest("log_grup_name", candidates))
+	assert.Equal(t, "file_path", suggest("File_Path", candidates))
+	assert.Equal(t, "", suggest("timezone", candidates))
+	assert.Equal(t, "", suggest("fp", candidates))
+	assert.Equal(t, "", suggest("key", nil))
+}
+
+func TestReportWrite(t *testing.T) {
+	var buf bytes.Buffer
+	report := &Report{
+		FromVersion: 1,
+		Changes:     []string{"removed /csm: CSM is no longer supported"},
+		Unknown:     []UnknownKey{{Path: "/metric", Suggestion: "metrics"}, {Path: "/agent/foo"}},
+	}
+	report.Write(&buf)
+	assert.Equal(t, `Migrated from schema version 1 to 2:
+  removed /csm: CSM is no longer supported
+Unknown keys:
+  /metric, did you mean metrics?
+  /agent/foo
+`, buf.String())
+
+	buf.Reset()
+	(&Report{FromVersion: 1}).Write(&buf)
+	assert.Equal(t, "Migrated from schema version 1 to 2:\n  no deprecated keys\n", buf.String())
+
+	buf.Reset()
+	(&Report{FromVersion: 2}).Write(&buf)
+	assert.Equal(t, "Already at schema version 2\n", buf.String())
+}
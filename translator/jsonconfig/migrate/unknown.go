@@ -0,0 +1,184 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+)
+
+const refPrefix = "#/"
+
+// schema resolves the nodes of the agent JSON schema. Only the keywords
+// describing the structure of the config are supported.
+type schema struct {
+	root map[string]interface{}
+}
+
+func newSchema() (*schema, error) {
+	var root map[string]interface{}
+	if err := json.Unmarshal([]byte(config.GetJsonSchema()), &root); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return &schema{root: root}, nil
+}
+
+// resolve follows the local $ref of the node.
+func (s *schema) resolve(node interface{}) map[string]interface{} {
+	m, _ := node.(map[string]interface{})
+	for m != nil {
+		ref, ok := m["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, refPrefix) {
+			return m
+		}
+		var current interface{} = s.root
+		for _, key := range strings.Split(strings.TrimPrefix(ref, refPrefix), "/") {
+			parent, _ := current.(map[string]interface{})
+			current = parent[key]
+		}
+		m, _ = current.(map[string]interface{})
+	}
+	return nil
+}
+
+// branches returns the node and the schemas it is combined with.
+func (s *schema) branches(node map[string]interface{}) []map[string]interface{} {
+	result := []map[string]interface{}{node}
+	for _, keyword := range []string{"oneOf", "anyOf", "allOf"} {
+		combined, _ := node[keyword].([]interface{})
+		for _, branch := range combined {
+			if resolved := s.resolve(branch); resolved != nil {
+				result = append(result, s.branches(resolved)...)
+			}
+		}
+	}
+	return result
+}
+
+// properties returns the properties of the object node and whether it allows
+// additional properties.
+func (s *schema) properties(node map[string]interface{}) (map[string]interface{}, interface{}, bool) {
+	properties := map[string]interface{}{}
+	var additionalSchema interface{}
+	additional := false
+	objects := 0
+	for _, branch := range s.branches(node) {
+		props, hasProperties := branch["properties"].(map[string]interface{})
+		if !hasProperties && branch["type"] != "object" {
+			continue
+		}
+		objects++
+		for key, value := range props {
+			properties[key] = value
+		}
+		switch value := branch["additionalProperties"].(type) {
+		case bool:
+			additional = additional || value
+		case map[string]interface{}:
+			additionalSchema = value
+		default:
+			additional = true
+		}
+	}
+	// the nodes without any structure allow anything
+	return properties, additionalSchema, additional || objects == 0
+}
+
+func (s *schema) items(node map[string]interface{}) map[string]interface{} {
+	for _, branch := range s.branches(node) {
+		if items := s.resolve(branch["items"]); items != nil {
+			return items
+		}
+	}
+	return nil
+}
+
+// findUnknownKeys walks the JSON config along the schema. The keys are
+// reported where the schema does not allow additional properties, or anywhere
+// if they are close to a key of the schema.
+func findUnknownKeys(jsonConfig map[string]interface{}) ([]UnknownKey, error) {
+	s, err := newSchema()
+	if err != nil {
+		return nil, err
+	}
+	var unknown []UnknownKey
+	s.walk("", jsonConfig, s.root, &unknown)
+	return unknown, nil
+}
+
+func (s *schema) walk(path string, value interface{}, node map[string]interface{}, unknown *[]UnknownKey) {
+	if node == nil {
+		return
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, additionalSchema, additional := s.properties(node)
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			childPath := path + "/" + key
+			if property, ok := properties[key]; ok {
+				s.walk(childPath, v[key], s.resolve(property), unknown)
+				continue
+			}
+			if additionalSchema != nil {
+				s.walk(childPath, v[key], s.resolve(additionalSchema), unknown)
+				continue
+			}
+			suggestion := suggest(key, properties)
+			if !additional || suggestion != "" {
+				*unknown = append(*unknown, UnknownKey{Path: childPath, Suggestion: suggestion})
+			}
+		}
+	case []interface{}:
+		items := s.items(node)
+		for i, item := range v {
+			s.walk(path+"/"+strconv.Itoa(i), item, items, unknown)
+		}
+	}
+}
+
+// suggest returns the closest candidate, if it is close enough to be a typo.
+func suggest(key string, candidates map[string]interface{}) string {
+	best, bestDistance := "", -1
+	for candidate := range candidates {
+		d := distance(strings.ToLower(key), strings.ToLower(candidate))
+		if bestDistance < 0 || d < bestDistance || (d == bestDistance && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+	if bestDistance < 0 || bestDistance > 3 || bestDistance*3 > len(key) {
+		return ""
+	}
+	return best
+}
+
+// distance is the Levenshtein distance between the strings.
+func distance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
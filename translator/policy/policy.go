@@ -0,0 +1,205 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package policy validates the translated configuration against the
+// restrictions of a policy file. The policy file is managed by the
+// administrators of the hosts separately from the agent config, so that the
+// teams writing the agent config cannot loosen it.
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// ModeEnforce fails the translation on a violation.
+	ModeEnforce = "enforce"
+	// ModeWarn logs the violations and translates the config anyway.
+	ModeWarn = "warn"
+
+	RuleAllowedLogGroups = "allowed_log_groups"
+	RuleMaxPipelines     = "max_pipelines"
+	RuleDeniedKeys       = "denied_keys"
+
+	logGroupNameKey = "log_group_name"
+	serviceKey      = "service"
+	pipelinesKey    = "pipelines"
+	keySeparator    = "."
+)
+
+// Policy is the content of the policy file.
+type Policy struct {
+	// Mode is either enforce, the default, or warn.
+	Mode string `json:"mode"`
+	// RequireIMDSv2 disables the IMDSv1 fallback of the translator and of the
+	// agent.
+	RequireIMDSv2 bool `json:"require_imdsv2"`
+	// AllowedLogGroups are the patterns the log group names of the config must
+	// match. A * matches any characters, including /. Any log group is
+	// allowed if empty.
+	AllowedLogGroups []string `json:"allowed_log_groups"`
+	// MaxPipelines is the maximum number of OpenTelemetry pipelines of the
+	// translated config. Not limited if 0.
+	MaxPipelines int `json:"max_pipelines"`
+	// DeniedKeys are the dot separated keys the config must not set, e.g.
+	// agent.credentials.
+	DeniedKeys []string `json:"denied_keys"`
+}
+
+// Violation is a part of the config breaking a rule of the policy.
+type Violation struct {
+	Rule string
+	// Path is the place in the config, e.g. logs::logs_collected::files.
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.Path == "" {
+		return fmt.Sprintf("%s: %s", v.Rule, v.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", v.Rule, v.Path, v.Message)
+}
+
+// Load reads and validates the policy file.
+func Load(path string) (*Policy, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&p); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	if p.Mode == "" {
+		p.Mode = ModeEnforce
+	}
+	if p.Mode != ModeEnforce && p.Mode != ModeWarn {
+		return nil, fmt.Errorf("invalid policy file %s: mode (%s) must be %s or %s", path, p.Mode, ModeEnforce, ModeWarn)
+	}
+	if p.MaxPipelines < 0 {
+		return nil, fmt.Errorf("invalid policy file %s: max_pipelines (%d) must not be negative", path, p.MaxPipelines)
+	}
+	for _, pattern := range p.AllowedLogGroups {
+		if pattern == "" {
+			return nil, fmt.Errorf("invalid policy file %s: empty pattern in allowed_log_groups", path)
+		}
+	}
+	return &p, nil
+}
+
+// Enforced returns true if the violations fail the translation.
+func (p *Policy) Enforced() bool {
+	return p.Mode == ModeEnforce
+}
+
+// Check returns the violations of the json config and of the translated
+// OpenTelemetry config, sorted by their path.
+func (p *Policy) Check(jsonConfig map[string]any, yamlConfig any) []Violation {
+	var violations []Violation
+	if len(p.AllowedLogGroups) > 0 {
+		violations = append(violations, p.checkLogGroups(jsonConfig)...)
+	}
+	for _, key := range p.DeniedKeys {
+		if isSet(jsonConfig, strings.Split(key, keySeparator)) {
+			violations = append(violations, Violation{
+				Rule:    RuleDeniedKeys,
+				Path:    strings.ReplaceAll(key, keySeparator, "::"),
+				Message: "the key is not allowed",
+			})
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+	if p.MaxPipelines > 0 {
+		if count := countPipelines(yamlConfig); count > p.MaxPipelines {
+			violations = append(violations, Violation{
+				Rule:    RuleMaxPipelines,
+				Message: fmt.Sprintf("the config translates to %d pipelines, more than the %d allowed", count, p.MaxPipelines),
+			})
+		}
+	}
+	return violations
+}
+
+// checkLogGroups walks the config for the log group names.
+func (p *Policy) checkLogGroups(jsonConfig map[string]any) []Violation {
+	patterns := make([]*regexp.Regexp, 0, len(p.AllowedLogGroups))
+	for _, pattern := range p.AllowedLogGroups {
+		patterns = append(patterns, globToRegexp(pattern))
+	}
+	var violations []Violation
+	walk(jsonConfig, nil, func(path []string, key string, value any) {
+		name, ok := value.(string)
+		if key != logGroupNameKey || !ok {
+			return
+		}
+		for _, pattern := range patterns {
+			if pattern.MatchString(name) {
+				return
+			}
+		}
+		violations = append(violations, Violation{
+			Rule:    RuleAllowedLogGroups,
+			Path:    strings.Join(append(path, key), "::"),
+			Message: fmt.Sprintf("log group %s does not match any of %v", name, p.AllowedLogGroups),
+		})
+	})
+	return violations
+}
+
+// walk calls fn with every key of the maps nested in the value. The list
+// items are part of the path by their index.
+func walk(value any, path []string, fn func(path []string, key string, value any)) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			fn(path, key, child)
+			walk(child, append(path[:len(path):len(path)], key), fn)
+		}
+	case []any:
+		for i, child := range v {
+			walk(child, append(path[:len(path):len(path)], fmt.Sprint(i)), fn)
+		}
+	}
+}
+
+func isSet(config map[string]any, keys []string) bool {
+	var current any = config
+	for _, key := range keys {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return false
+		}
+		if current, ok = m[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func countPipelines(yamlConfig any) int {
+	cfg, _ := yamlConfig.(map[string]any)
+	service, _ := cfg[serviceKey].(map[string]any)
+	pipelines, _ := service[pipelinesKey].(map[string]any)
+	return len(pipelines)
+}
+
+// globToRegexp converts the pattern to an anchored expression where * matches
+// any characters.
+func globToRegexp(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
@@ -0,0 +1,141 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	testCases := map[string]struct {
+		content string
+		want    *Policy
+		wantErr string
+	}{
+		"WithDefaultMode": {
+			content: `{"max_pipelines": 4}`,
+			want:    &Policy{Mode: ModeEnforce, MaxPipelines: 4},
+		},
+		"WithWarnMode": {
+			content: `{"mode": "warn", "require_imdsv2": true, "allowed_log_groups": ["/platform/*"]}`,
+			want:    &Policy{Mode: ModeWarn, RequireIMDSv2: true, AllowedLogGroups: []string{"/platform/*"}},
+		},
+		"WithInvalidMode": {
+			content: `{"mode": "audit"}`,
+			wantErr: "mode (audit) must be enforce or warn",
+		},
+		"WithUnknownField": {
+			content: `{"max_pipeline": 4}`,
+			wantErr: `unknown field "max_pipeline"`,
+		},
+		"WithNegativeMaxPipelines": {
+			content: `{"max_pipelines": -1}`,
+			wantErr: "max_pipelines (-1) must not be negative",
+		},
+		"WithEmptyPattern": {
+			content: `{"allowed_log_groups": [""]}`,
+			wantErr: "empty pattern in allowed_log_groups",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.json")
+			require.NoError(t, os.WriteFile(path, []byte(testCase.content), 0600))
+			got, err := Load(path)
+			if testCase.wantErr != "" {
+				assert.ErrorContains(t, err, testCase.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	jsonConfig := map[string]any{
+		"agent": map[string]any{
+			"credentials": map[string]any{"role_arn": "arn:aws:iam::123456789012:role/agent"},
+		},
+		"logs": map[string]any{
+			"logs_collected": map[string]any{
+				"files": map[string]any{
+					"collect_list": []any{
+						map[string]any{"file_path": "/var/log/app.log", "log_group_name": "/platform/app"},
+						map[string]any{"file_path": "/var/log/audit.log", "log_group_name": "audit"},
+					},
+				},
+			},
+			"metrics_collected": map[string]any{
+				"emf": map[string]any{},
+			},
+		},
+	}
+	yamlConfig := map[string]any{
+		"service": map[string]any{
+			"pipelines": map[string]any{
+				"metrics/host":      map[string]any{},
+				"metrics/hostDelta": map[string]any{},
+				"logs/emf_logs":     map[string]any{},
+			},
+		},
+	}
+	testCases := map[string]struct {
+		policy *Policy
+		want   []string
+	}{
+		"WithNoRestrictions": {
+			policy: &Policy{Mode: ModeEnforce},
+		},
+		"WithAllowedLogGroups": {
+			policy: &Policy{Mode: ModeEnforce, AllowedLogGroups: []string{"/platform/*"}},
+			want: []string{
+				"allowed_log_groups: logs::logs_collected::files::collect_list::1::log_group_name: log group audit does not match any of [/platform/*]",
+			},
+		},
+		"WithAllLogGroupsAllowed": {
+			policy: &Policy{Mode: ModeEnforce, AllowedLogGroups: []string{"/platform/*", "audit"}},
+		},
+		"WithMaxPipelines": {
+			policy: &Policy{Mode: ModeEnforce, MaxPipelines: 2},
+			want: []string{
+				"max_pipelines: the config translates to 3 pipelines, more than the 2 allowed",
+			},
+		},
+		"WithDeniedKeys": {
+			policy: &Policy{Mode: ModeEnforce, DeniedKeys: []string{"agent.credentials", "agent.debug"}},
+			want: []string{
+				"denied_keys: agent::credentials: the key is not allowed",
+			},
+		},
+		"WithAll": {
+			policy: &Policy{Mode: ModeWarn, AllowedLogGroups: []string{"/platform/*"}, MaxPipelines: 1, DeniedKeys: []string{"agent.credentials.role_arn"}},
+			want: []string{
+				"denied_keys: agent::credentials::role_arn: the key is not allowed",
+				"allowed_log_groups: logs::logs_collected::files::collect_list::1::log_group_name: log group audit does not match any of [/platform/*]",
+				"max_pipelines: the config translates to 3 pipelines, more than the 1 allowed",
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, violation := range testCase.policy.Check(jsonConfig, yamlConfig) {
+				got = append(got, violation.String())
+			}
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestCheckWithoutPipelines(t *testing.T) {
+	p := &Policy{Mode: ModeEnforce, MaxPipelines: 1}
+	assert.Empty(t, p.Check(map[string]any{}, nil))
+	assert.True(t, p.Enforced())
+}
@@ -0,0 +1,170 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package render translates the agent JSON config to the OpenTelemetry YAML
+// config the agent runs, for the tools managing the agent outside of its
+// host, e.g. the CloudWatch agent operator. The environment of the agent is
+// described by the caller instead of being detected, so the rendered config
+// is the same wherever it is rendered, and can be compared with the one of a
+// running agent to detect drift.
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/cmdutil"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+	"github.com/aws/amazon-cloudwatch-agent/translator/tocwconfig/toyamlconfig"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	logsutil "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline"
+	translateutil "github.com/aws/amazon-cloudwatch-agent/translator/translate/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator/util"
+)
+
+// Environment describes where the agent runs.
+type Environment struct {
+	// OS is the operating system of the agent, linux by default.
+	OS string
+	// Arch is the architecture of the agent, amd64 by default.
+	Arch string
+	// Mode is the mode of the agent, ec2 by default.
+	Mode string
+	// KubernetesMode is EKS, K8sEC2 or K8sOnPrem for the agent running in a
+	// Kubernetes cluster, and empty otherwise.
+	KubernetesMode string
+	// RunInContainer is set for the agent running in a container.
+	RunInContainer bool
+	// Region is used if the config does not set agent.region.
+	Region string
+	// ClusterName is used if the config does not set the cluster name of the
+	// Kubernetes sections.
+	ClusterName string
+	// Metadata replaces the metadata of the instance in the placeholders,
+	// e.g. {instance_id}. The placeholders get their unknown values if it is
+	// nil.
+	Metadata *translateutil.Metadata
+	// Credentials are the credentials of the common config, e.g. the
+	// shared_credential_profile.
+	Credentials map[string]string
+}
+
+// ErrNoPipelines is returned for the configs without any OpenTelemetry
+// pipeline, for which the agent runs without a YAML config.
+var ErrNoPipelines = pipeline.ErrNoPipelines
+
+// mu serializes the renders, since the translation keeps its state in
+// package variables.
+var mu sync.Mutex
+
+// YAML renders the OpenTelemetry YAML config of the agent JSON config in the
+// environment. It does not call the instance metadata or the AWS APIs.
+func YAML(jsonConfig []byte, env Environment) ([]byte, error) {
+	var input map[string]interface{}
+	if err := json.Unmarshal(jsonConfig, &input); err != nil {
+		return nil, fmt.Errorf("invalid json config: %w", err)
+	}
+	return render(input, env)
+}
+
+func render(input map[string]interface{}, env Environment) (out []byte, err error) {
+	mu.Lock()
+	defer mu.Unlock()
+	defer func() {
+		// the translation panics on the invalid configs and modes
+		if r := recover(); r != nil {
+			out, err = nil, fmt.Errorf("unable to render the config: %v", r)
+		}
+	}()
+	defer override(env)()
+
+	ctx := context.CurrentContext()
+	if env.OS == "" {
+		env.OS = config.OS_TYPE_LINUX
+	}
+	ctx.SetOs(config.ToValidOs(env.OS))
+	if env.Arch == "" {
+		env.Arch = config.ARCH_TYPE_AMD64
+	}
+	ctx.SetArch(config.ToValidArch(env.Arch))
+	if env.Mode == "" {
+		env.Mode = config.ModeEC2
+	}
+	ctx.SetMode(env.Mode)
+	if env.KubernetesMode != "" {
+		ctx.SetKubernetesMode(env.KubernetesMode)
+		if ctx.KubernetesMode() == "" {
+			return nil, fmt.Errorf("invalid kubernetes mode %s, valid values are %s, %s and %s", env.KubernetesMode, config.ModeEKS, config.ModeK8sEC2, config.ModeK8sOnPrem)
+		}
+	}
+	ctx.SetRunInContainer(env.RunInContainer)
+	ctx.SetCredentials(env.Credentials)
+
+	cmdutil.RemoveUnsupportedSections(input, ctx.Os(), ctx.Arch())
+	result, err := cmdutil.RunSchemaValidation(input)
+	if err != nil {
+		return nil, fmt.Errorf("unable to run the schema validation: %w", err)
+	}
+	if !result.Valid() {
+		var details []string
+		for _, detail := range result.Errors() {
+			details = append(details, fmt.Sprintf("%s: %s", config.GetFormattedPath(detail.Context().String()), detail.Description()))
+		}
+		return nil, fmt.Errorf("invalid json config: %s", strings.Join(details, ", "))
+	}
+
+	// the toml translation sets the agent settings used by the otel one,
+	// e.g. the region
+	if _, err = cmdutil.TranslateJsonMapToTomlConfig(input); err != nil {
+		return nil, err
+	}
+	yamlConfig, err := cmdutil.TranslateJsonMapToYamlConfig(input)
+	if err != nil {
+		return nil, err
+	}
+	rendered := toyamlconfig.ToYamlConfig(yamlConfig)
+	if strings.TrimSpace(rendered) == "null" {
+		return nil, ErrNoPipelines
+	}
+	return []byte(rendered), nil
+}
+
+// override resets the state of the translation and replaces the lookups of
+// the host with the environment. It returns the function restoring them.
+func override(env Environment) func() {
+	context.ResetContext()
+	agent.Global_Config = agent.Agent{}
+	translator.ResetMessages()
+
+	detectRegion := util.DetectRegion
+	metadataProvider := translateutil.Ec2MetadataInfoProvider
+	clusterName := logsutil.GetClusterNameFromEc2Tagger
+	util.DetectRegion = func(string, map[string]string) (string, string) {
+		if env.Region == "" {
+			return "", config.RegionTypeNotFound
+		}
+		return env.Region, config.RegionTypeAgentConfigJson
+	}
+	translateutil.Ec2MetadataInfoProvider = func() *translateutil.Metadata {
+		if env.Metadata == nil {
+			return &translateutil.Metadata{}
+		}
+		return env.Metadata
+	}
+	logsutil.GetClusterNameFromEc2Tagger = func() string {
+		return env.ClusterName
+	}
+	return func() {
+		util.DetectRegion = detectRegion
+		translateutil.Ec2MetadataInfoProvider = metadataProvider
+		logsutil.GetClusterNameFromEc2Tagger = clusterName
+		context.ResetContext()
+		agent.Global_Config = agent.Agent{}
+		translator.ResetMessages()
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+	logsutil "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator/util"
+)
+
+const containerInsightsConfig = `{
+  "logs": {
+    "metrics_collected": {
+      "kubernetes": {
+        "metrics_collection_interval": 30
+      }
+    }
+  }
+}`
+
+func TestYAML(t *testing.T) {
+	defer func(original func(string, map[string]string) (string, string)) {
+		util.DetectRegion = original
+	}(util.DetectRegion)
+	util.DetectRegion = func(string, map[string]string) (string, string) {
+		return "eu-west-1", config.RegionTypeEC2Metadata
+	}
+	defer func(original func() string) {
+		logsutil.GetClusterNameFromEc2Tagger = original
+	}(logsutil.GetClusterNameFromEc2Tagger)
+	logsutil.GetClusterNameFromEc2Tagger = func() string {
+		return "HostCluster"
+	}
+
+	env := Environment{
+		KubernetesMode: config.ModeEKS,
+		RunInContainer: true,
+		Region:         "us-west-2",
+		ClusterName:    "TestCluster",
+	}
+	got, err := YAML([]byte(containerInsightsConfig), env)
+	require.NoError(t, err)
+
+	var rendered struct {
+		Exporters map[string]map[string]interface{} `yaml:"exporters"`
+		Receivers map[string]map[string]interface{} `yaml:"receivers"`
+	}
+	require.NoError(t, yaml.Unmarshal(got, &rendered))
+	assert.Equal(t, "us-west-2", rendered.Exporters["awsemf/containerinsights"]["region"])
+	assert.Equal(t, "TestCluster", rendered.Receivers["awscontainerinsightreceiver"]["cluster_name"])
+
+	// the same config is rendered again, and the lookups of the host are
+	// restored
+	again, err := YAML([]byte(containerInsightsConfig), env)
+	require.NoError(t, err)
+	assert.Equal(t, string(got), string(again))
+	assert.Equal(t, "", context.CurrentContext().KubernetesMode())
+	region, _ := util.DetectRegion(config.ModeEC2, nil)
+	assert.Equal(t, "eu-west-1", region)
+	assert.Equal(t, "HostCluster", logsutil.GetClusterNameFromEc2Tagger())
+}
+
+func TestYAMLErrors(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+		env   Environment
+	}{
+		"WithInvalidJSON": {
+			input: `{"logs":`,
+		},
+		"WithInvalidSchema": {
+			input: `{"logs": {"metrics_collected": {"kubernetes": {"metrics_collection_interval": "30"}}}}`,
+			env:   Environment{Region: "us-west-2"},
+		},
+		"WithInvalidMode": {
+			input: containerInsightsConfig,
+			env:   Environment{Mode: "cloud", Region: "us-west-2"},
+		},
+		"WithInvalidKubernetesMode": {
+			input: containerInsightsConfig,
+			env:   Environment{KubernetesMode: "ECS", Region: "us-west-2"},
+		},
+		"WithInvalidOS": {
+			input: containerInsightsConfig,
+			env:   Environment{OS: "plan9", Region: "us-west-2"},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := YAML([]byte(testCase.input), testCase.env)
+			assert.Error(t, err)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestYAMLNoPipelines(t *testing.T) {
+	got, err := YAML([]byte(`{"agent": {"region": "us-west-2"}}`), Environment{})
+	assert.ErrorIs(t, err, ErrNoPipelines)
+	assert.Nil(t, got)
+}
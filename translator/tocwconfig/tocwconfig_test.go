@@ -37,6 +37,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/tocwconfig/toyamlconfig"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/awscontainerinsightsnodehealth"
 	"github.com/aws/amazon-cloudwatch-agent/translator/util"
 	"github.com/aws/amazon-cloudwatch-agent/translator/util/ecsutil"
 	"github.com/aws/amazon-cloudwatch-agent/translator/util/eksdetector"
@@ -45,6 +46,8 @@ import (
 const (
 	prometheusFileNameToken = "prometheusFileName"
 	ecsSdFileNameToken      = "ecsSdFileName"
+
+	serviceAccountTokenFileNameToken = "serviceAccountTokenFileName"
 )
 
 //go:embed sampleConfig/prometheus_config.yaml
@@ -77,7 +80,11 @@ func TestGenericAppSignalsConfig(t *testing.T) {
 	context.CurrentContext().SetMode(config.ModeOnPremise)
 	t.Setenv(config.HOST_NAME, "host_name_from_env")
 	t.Setenv(config.HOST_IP, "127.0.0.1")
-	expectedEnvVars := map[string]string{"CWAGENT_LOG_LEVEL": "DEBUG"}
+	expectedEnvVars := map[string]string{
+		"CWAGENT_LOG_LEVEL":              "DEBUG",
+		"CWAGENT_PROFILE":                "AmazonCloudWatchAgent",
+		"CWAGENT_SHARED_CREDENTIAL_FILE": "fake-path",
+	}
 	checkTranslation(t, "base_appsignals_config", "linux", expectedEnvVars, "")
 	checkTranslation(t, "base_appsignals_config", "windows", expectedEnvVars, "")
 }
@@ -101,7 +108,10 @@ func TestGenericAppSignalsFallbackConfig(t *testing.T) {
 	context.CurrentContext().SetMode(config.ModeOnPremise)
 	t.Setenv(config.HOST_NAME, "host_name_from_env")
 	t.Setenv(config.HOST_IP, "127.0.0.1")
-	expectedEnvVars := map[string]string{}
+	expectedEnvVars := map[string]string{
+		"CWAGENT_PROFILE":                "AmazonCloudWatchAgent",
+		"CWAGENT_SHARED_CREDENTIAL_FILE": "fake-path",
+	}
 	checkTranslation(t, "base_appsignals_fallback_config", "linux", expectedEnvVars, "")
 	checkTranslation(t, "base_appsignals_fallback_config", "windows", expectedEnvVars, "")
 }
@@ -215,7 +225,10 @@ func TestEmfAndKubernetesConfig(t *testing.T) {
 	context.CurrentContext().SetMode(config.ModeOnPremise)
 	t.Setenv(config.HOST_NAME, "host_name_from_env")
 	t.Setenv(config.HOST_IP, "127.0.0.1")
-	expectedEnvVars := map[string]string{}
+	expectedEnvVars := map[string]string{
+		"CWAGENT_PROFILE":                "default",
+		"CWAGENT_SHARED_CREDENTIAL_FILE": "/root/.aws/credentials",
+	}
 	checkTranslation(t, "emf_and_kubernetes_config", "linux", expectedEnvVars, "")
 	checkTranslation(t, "emf_and_kubernetes_config", "darwin", nil, "")
 }
@@ -227,7 +240,10 @@ func TestEmfAndKubernetesWithGpuConfig(t *testing.T) {
 	context.CurrentContext().SetMode(config.ModeOnPremise)
 	t.Setenv(config.HOST_NAME, "host_name_from_env")
 	t.Setenv(config.HOST_IP, "127.0.0.1")
-	expectedEnvVars := map[string]string{}
+	expectedEnvVars := map[string]string{
+		"CWAGENT_PROFILE":                "default",
+		"CWAGENT_SHARED_CREDENTIAL_FILE": "/root/.aws/credentials",
+	}
 	checkTranslation(t, "emf_and_kubernetes_with_gpu_config", "linux", expectedEnvVars, "")
 	checkTranslation(t, "emf_and_kubernetes_with_gpu_config", "darwin", nil, "")
 }
@@ -239,7 +255,10 @@ func TestEmfAndKubernetesWithKueueConfig(t *testing.T) {
 	context.CurrentContext().SetMode(config.ModeOnPremise)
 	t.Setenv(config.HOST_NAME, "host_name_from_env")
 	t.Setenv(config.HOST_IP, "127.0.0.1")
-	expectedEnvVars := map[string]string{}
+	expectedEnvVars := map[string]string{
+		"CWAGENT_PROFILE":                "default",
+		"CWAGENT_SHARED_CREDENTIAL_FILE": "/root/.aws/credentials",
+	}
 	checkTranslation(t, "emf_and_kubernetes_with_kueue_config", "linux", expectedEnvVars, "")
 	checkTranslation(t, "emf_and_kubernetes_with_kueue_config", "darwin", nil, "")
 }
@@ -250,7 +269,10 @@ func TestKubernetesModeOnPremiseConfig(t *testing.T) {
 	context.CurrentContext().SetMode(config.ModeOnPremise)
 	t.Setenv(config.HOST_NAME, "host_name_from_env")
 	t.Setenv(config.HOST_IP, "127.0.0.1")
-	expectedEnvVars := map[string]string{}
+	expectedEnvVars := map[string]string{
+		"CWAGENT_PROFILE":                "AmazonCloudWatchAgent",
+		"CWAGENT_SHARED_CREDENTIAL_FILE": "fake-path",
+	}
 	checkTranslation(t, "kubernetes_on_prem_config", "linux", expectedEnvVars, "")
 }
 
@@ -268,6 +290,43 @@ func TestKueueContainerInsightsConfig(t *testing.T) {
 	checkTranslation(t, "kueue_container_insights_config", "darwin", nil, "")
 }
 
+func TestNodeHealthContainerInsightsConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetRunInContainer(true)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	t.Setenv(config.HOST_NAME, "host_name_from_env")
+	t.Setenv(config.HOST_IP, "127.0.0.1")
+	t.Setenv(envconfig.AWS_CA_BUNDLE, "/etc/test/ca_bundle.pem")
+	// the prometheus receiver requires the service account token to exist
+	tokenFileName := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFileName, nil, 0600))
+	defaultTokenPath := awscontainerinsightsnodehealth.ServiceAccountTokenPath
+	awscontainerinsightsnodehealth.ServiceAccountTokenPath = tokenFileName
+	defer func() { awscontainerinsightsnodehealth.ServiceAccountTokenPath = defaultTokenPath }()
+	tokenReplacements := map[string]string{
+		serviceAccountTokenFileNameToken: strings.ReplaceAll(tokenFileName, "\\", "\\\\"),
+	}
+	expectedEnvVars := map[string]string{
+		"AWS_CA_BUNDLE": "/etc/test/ca_bundle.pem",
+	}
+	checkTranslation(t, "node_health_container_insights_config", "linux", expectedEnvVars, "", tokenReplacements)
+	checkTranslation(t, "node_health_container_insights_config", "darwin", nil, "", tokenReplacements)
+}
+
+func TestEKSFargateContainerInsightsConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetRunInContainer(true)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	t.Setenv(config.HOST_NAME, "host_name_from_env")
+	t.Setenv(config.HOST_IP, "127.0.0.1")
+	t.Setenv(envconfig.AWS_CA_BUNDLE, "/etc/test/ca_bundle.pem")
+	expectedEnvVars := map[string]string{
+		"AWS_CA_BUNDLE": "/etc/test/ca_bundle.pem",
+	}
+	checkTranslation(t, "eks_fargate_container_insights_config", "linux", expectedEnvVars, "")
+	checkTranslation(t, "eks_fargate_container_insights_config", "darwin", nil, "")
+}
+
 func TestLogsAndKubernetesConfig(t *testing.T) {
 	resetContext(t)
 	context.CurrentContext().SetRunInContainer(true)
@@ -332,12 +391,46 @@ func TestProcstatMemorySwapConfig(t *testing.T) {
 	checkTranslation(t, "procstat_memory_swap_config", "darwin", nil, "")
 }
 
+func TestProcstatExpectRunningConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetRunInContainer(false)
+	context.CurrentContext().SetMode(config.ModeOnPremise)
+	t.Setenv(config.HOST_NAME, "host_name_from_env")
+	t.Setenv(config.HOST_IP, "127.0.0.1")
+	checkTranslation(t, "procstat_expect_running_config", "linux", nil, "")
+}
+
+func TestNetworkProbeConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetRunInContainer(false)
+	context.CurrentContext().SetMode(config.ModeOnPremise)
+	t.Setenv(config.HOST_NAME, "host_name_from_env")
+	t.Setenv(config.HOST_IP, "127.0.0.1")
+	checkTranslation(t, "network_probe_config", "linux", nil, "")
+}
+
+func TestSNMPConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetRunInContainer(false)
+	context.CurrentContext().SetMode(config.ModeOnPremise)
+	t.Setenv(config.HOST_NAME, "host_name_from_env")
+	t.Setenv(config.HOST_IP, "127.0.0.1")
+	checkTranslation(t, "snmp_config", "linux", nil, "")
+}
+
 func TestWindowsEventOnlyConfig(t *testing.T) {
 	resetContext(t)
 	expectedEnvVars := map[string]string{}
 	checkTranslation(t, "windows_eventlog_only_config", "windows", expectedEnvVars, "")
 }
 
+func TestWindowsMetricBundlesConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "windows_metric_bundles_config", "windows", expectedEnvVars, "")
+}
+
 func TestStatsDConfig(t *testing.T) {
 	testCases := map[string]testCase{
 		"linux": {
@@ -368,6 +461,13 @@ func TestStatsDConfig(t *testing.T) {
 	}
 }
 
+func TestDogStatsDConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "dogstatsd_config", "linux", expectedEnvVars, "")
+}
+
 // Linux only for CollectD
 func TestCollectDConfig(t *testing.T) {
 	resetContext(t)
@@ -653,22 +753,31 @@ func TestDoNotSkipLogDefaultTimestampConfig(t *testing.T) {
 func TestTraceConfig(t *testing.T) {
 	testCases := map[string]testCase{
 		"linux": {
-			filename:        "trace_config",
-			targetPlatform:  "linux",
-			expectedEnvVars: map[string]string{},
-			appendString:    "_linux",
+			filename:       "trace_config",
+			targetPlatform: "linux",
+			expectedEnvVars: map[string]string{
+				"CWAGENT_PROFILE":                "default",
+				"CWAGENT_SHARED_CREDENTIAL_FILE": "/root/.aws/credentials",
+			},
+			appendString: "_linux",
 		},
 		"darwin": {
-			filename:        "trace_config",
-			targetPlatform:  "darwin",
-			expectedEnvVars: map[string]string{},
-			appendString:    "_linux",
+			filename:       "trace_config",
+			targetPlatform: "darwin",
+			expectedEnvVars: map[string]string{
+				"CWAGENT_PROFILE":                "default",
+				"CWAGENT_SHARED_CREDENTIAL_FILE": "/root/.aws/credentials",
+			},
+			appendString: "_linux",
 		},
 		"windows": {
-			filename:        "trace_config",
-			targetPlatform:  "windows",
-			expectedEnvVars: map[string]string{},
-			appendString:    "_windows",
+			filename:       "trace_config",
+			targetPlatform: "windows",
+			expectedEnvVars: map[string]string{
+				"CWAGENT_PROFILE":                "default",
+				"CWAGENT_SHARED_CREDENTIAL_FILE": "/root/.aws/credentials",
+			},
+			appendString: "_windows",
 		},
 	}
 	for name, testCase := range testCases {
@@ -681,6 +790,48 @@ func TestTraceConfig(t *testing.T) {
 	}
 }
 
+func TestTraceTailSamplingConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	checkTranslation(t, "trace_tail_sampling", "linux", nil, "")
+}
+
+func TestTraceScrubbingConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	checkTranslation(t, "trace_scrubbing", "linux", nil, "")
+}
+
+func TestTraceSpanMetricsConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	checkTranslation(t, "trace_span_metrics", "linux", nil, "")
+}
+
+func TestTraceDestinationsConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	checkTranslation(t, "trace_destinations", "linux", nil, "")
+}
+
+func TestEntityAttributesConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	checkTranslation(t, "entity_attributes", "linux", nil, "")
+}
+
+func TestHybridEntityConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeOnPremise)
+	checkTranslation(t, "hybrid_entity", "linux", nil, "")
+}
+
+func TestRetryConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	checkTranslation(t, "retry_config", "linux", nil, "")
+}
+
 func TestConfigWithEnvironmentVariables(t *testing.T) {
 	resetContext(t)
 	context.CurrentContext().SetMode(config.ModeEC2)
@@ -694,10 +845,13 @@ func TestStandardConfigWithCommonConfig(t *testing.T) {
 			filename:       "standard_config_linux",
 			targetPlatform: "linux",
 			expectedEnvVars: map[string]string{
-				"AWS_CA_BUNDLE": "/etc/test/ca_bundle.pem",
-				"HTTPS_PROXY":   "https://127.0.0.1:3280",
-				"HTTP_PROXY":    "http://127.0.0.1:3280",
-				"NO_PROXY":      "254.1.1.1",
+				"AWS_CA_BUNDLE":                  "/etc/test/ca_bundle.pem",
+				"CWAGENT_PROFILE":                "AmazonCloudWatchAgent",
+				"CWAGENT_SHARED_CREDENTIAL_FILE": "fake-path",
+				"HTTPS_PROXY":                    "https://127.0.0.1:3280",
+				"HTTP_PROXY":                     "http://127.0.0.1:3280",
+				// the link-local addresses of IMDS are reached directly
+				"NO_PROXY": "254.1.1.1,169.254.0.0/16,fd00:ec2::/32,.vpce.amazonaws.com",
 			},
 			appendString: "_with_common_config",
 		},
@@ -711,10 +865,13 @@ func TestStandardConfigWithCommonConfig(t *testing.T) {
 			filename:       "standard_config_windows",
 			targetPlatform: "windows",
 			expectedEnvVars: map[string]string{
-				"AWS_CA_BUNDLE": "/etc/test/ca_bundle.pem",
-				"HTTPS_PROXY":   "https://127.0.0.1:3280",
-				"HTTP_PROXY":    "http://127.0.0.1:3280",
-				"NO_PROXY":      "254.1.1.1",
+				"AWS_CA_BUNDLE":                  "/etc/test/ca_bundle.pem",
+				"CWAGENT_PROFILE":                "AmazonCloudWatchAgent",
+				"CWAGENT_SHARED_CREDENTIAL_FILE": "fake-path",
+				"HTTPS_PROXY":                    "https://127.0.0.1:3280",
+				"HTTP_PROXY":                     "http://127.0.0.1:3280",
+				// the link-local addresses of IMDS are reached directly
+				"NO_PROXY": "254.1.1.1,169.254.0.0/16,fd00:ec2::/32,.vpce.amazonaws.com",
 			},
 			appendString: "_with_common_config",
 		},
@@ -754,6 +911,23 @@ func TestECSNodeMetricConfig(t *testing.T) {
 	ecsSingleton.Region = ""
 }
 
+func TestECSTaskMetricConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetRunInContainer(true)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	ecsutil.GetECSUtilSingleton().TaskARN = "arn:aws:ecs:us-east-1:account_id:task/task_id"
+	ecsSingleton := ecsutil.GetECSUtilSingleton()
+	ecsSingleton.Region = "us-west-2"
+	t.Setenv("RUN_IN_CONTAINER", "True")
+	t.Setenv("HOST_NAME", "fake-host-name")
+	t.Setenv("HOST_IP", "127.0.0.1")
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "ecs_task_metrics_config", "linux", expectedEnvVars, "")
+	checkTranslation(t, "ecs_task_metrics_config", "darwin", nil, "")
+	//Reset back to default value to not impact other tests
+	ecsSingleton.Region = ""
+}
+
 func TestECSNodeStatsDConfig(t *testing.T) {
 	resetContext(t)
 	context.CurrentContext().SetRunInContainer(true)
@@ -776,6 +950,108 @@ func TestLogFilterConfig(t *testing.T) {
 	checkTranslation(t, "log_filter", "darwin", nil, "")
 }
 
+func TestLogTransformTemplateConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "log_transform_template", "linux", nil, "")
+	checkTranslation(t, "log_transform_template", "darwin", nil, "")
+}
+
+func TestLogParsersConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "log_parsers", "linux", nil, "")
+	checkTranslation(t, "log_parsers", "darwin", nil, "")
+}
+
+func TestLogStructuredJSONConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "log_structured_events", "linux", nil, "")
+	checkTranslation(t, "log_structured_events", "darwin", nil, "")
+}
+
+func TestLogRateLimitConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "log_rate_limit", "linux", nil, "")
+	checkTranslation(t, "log_rate_limit", "darwin", nil, "")
+}
+
+func TestLogClockSkewConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "log_clock_skew", "linux", nil, "")
+	checkTranslation(t, "log_clock_skew", "darwin", nil, "")
+}
+
+func TestLogS3DestinationConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "log_s3_destination", "linux", nil, "")
+	checkTranslation(t, "log_s3_destination", "darwin", nil, "")
+}
+
+func TestLogMultilineConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "log_multiline", "linux", nil, "")
+	checkTranslation(t, "log_multiline", "darwin", nil, "")
+}
+
+func TestLogSamplingConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "log_sampling", "linux", nil, "")
+	checkTranslation(t, "log_sampling", "darwin", nil, "")
+}
+
+func TestLogOversizeEventsConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "log_oversize_events", "linux", nil, "")
+	checkTranslation(t, "log_oversize_events", "darwin", nil, "")
+}
+
+func TestFailoverEndpointsConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "failover_endpoints", "linux", nil, "")
+}
+
+func TestLogKafkaConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "log_kafka", "linux", nil, "")
+	checkTranslation(t, "log_kafka", "windows", nil, "")
+}
+
+func TestLogKinesisConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "log_kinesis", "linux", nil, "")
+	checkTranslation(t, "log_kinesis", "darwin", nil, "")
+}
+
+func TestLogOtlpConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "log_otlp", "linux", nil, "")
+	checkTranslation(t, "log_otlp", "windows", nil, "")
+}
+
+func TestLogFluentForwardConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "log_fluent_forward", "linux", nil, "")
+}
+
+func TestInternalMetricsConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "internal_metrics", "linux", nil, "")
+}
+
+func TestMemoryLimiterConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "memory_limiter", "linux", nil, "")
+}
+
+func TestLogJournaldConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "log_journald", "linux", nil, "")
+}
+
+func TestLogDockerConfig(t *testing.T) {
+	resetContext(t)
+	checkTranslation(t, "log_docker", "linux", nil, "")
+}
+
 func TestIgnoreInvalidAppendDimensions(t *testing.T) {
 	resetContext(t)
 	context.CurrentContext().SetMode(config.ModeEC2)
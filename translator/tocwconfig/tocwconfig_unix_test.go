@@ -23,6 +23,7 @@ func TestCompleteConfigUnix(t *testing.T) {
 		"CWAGENT_USER_AGENT": "CUSTOM USER AGENT VALUE",
 		"CWAGENT_LOG_LEVEL":  "DEBUG",
 		"AWS_SDK_LOG_LEVEL":  "LogDebug",
+		"CWAGENT_ROLE_ARN":   "global_role_arn_value",
 	}
 
 	// The translation needs to use the runtime.GOOS value in order to generate the proper configuration YAML,
@@ -40,6 +41,115 @@ func TestAMPConfig(t *testing.T) {
 	checkTranslation(t, "amp_config_linux", "darwin", nil, "")
 }
 
+func TestAggregationTemporalityConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	testutil.SetPrometheusRemoteWriteTestingEnv(t)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "aggregation_temporality_config_linux", "linux", expectedEnvVars, "")
+}
+
+func TestTextfileConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "textfile_config_linux", "linux", expectedEnvVars, "")
+	checkTranslation(t, "textfile_config_linux", "darwin", nil, "")
+}
+
+func TestAlertsConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "alerts_config_linux", "linux", expectedEnvVars, "")
+	checkTranslation(t, "alerts_config_linux", "darwin", nil, "")
+}
+
+func TestBurstCaptureConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "burst_capture_config_linux", "linux", expectedEnvVars, "")
+	checkTranslation(t, "burst_capture_config_linux", "darwin", nil, "")
+}
+
+func TestDimensionNormalizationConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "dimension_normalization_config_linux", "linux", expectedEnvVars, "")
+	checkTranslation(t, "dimension_normalization_config_linux", "darwin", nil, "")
+}
+
+func TestDerivedMetricsConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "derived_metrics_config_linux", "linux", expectedEnvVars, "")
+	checkTranslation(t, "derived_metrics_config_linux", "darwin", nil, "")
+}
+
+func TestCardinalityLimitConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "cardinality_limit_config_linux", "linux", expectedEnvVars, "")
+	checkTranslation(t, "cardinality_limit_config_linux", "darwin", nil, "")
+}
+
+func TestNamespaceRoutesConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "namespace_routes_config_linux", "linux", expectedEnvVars, "")
+	checkTranslation(t, "namespace_routes_config_linux", "darwin", nil, "")
+}
+
+func TestTransformsConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "transforms_config_linux", "linux", expectedEnvVars, "")
+	checkTranslation(t, "transforms_config_linux", "darwin", nil, "")
+}
+
+func TestEfaEnaConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "efa_ena_config_linux", "linux", expectedEnvVars, "")
+}
+
+func TestDiskStatsConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "diskstats_config_linux", "linux", expectedEnvVars, "")
+}
+
+func TestNfsConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "nfs_config_linux", "linux", expectedEnvVars, "")
+}
+
+func TestDimensionFiltersConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "dimension_filters_config_linux", "linux", expectedEnvVars, "")
+	checkTranslation(t, "dimension_filters_config_linux", "darwin", nil, "")
+}
+
+func TestOTLPDestinationConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "otlp_destination_config_linux", "linux", expectedEnvVars, "")
+	checkTranslation(t, "otlp_destination_config_linux", "darwin", nil, "")
+}
+
 func TestJMXConfigLinux(t *testing.T) {
 	resetContext(t)
 	context.CurrentContext().SetMode(config.ModeEC2)
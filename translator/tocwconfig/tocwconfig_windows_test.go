@@ -20,6 +20,7 @@ func TestCompleteConfigWindows(t *testing.T) {
 		"CWAGENT_USER_AGENT": "CUSTOM USER AGENT VALUE",
 		"CWAGENT_LOG_LEVEL":  "DEBUG",
 		"AWS_SDK_LOG_LEVEL":  "LogDebug",
+		"CWAGENT_ROLE_ARN":   "global_role_arn_value",
 	}
 
 	// The translation needs to use the runtime.GOOS value in order to generate the proper configuration YAML,
@@ -6,9 +6,13 @@ package toenvconfig
 import (
 	"encoding/json"
 	"log"
+	"strconv"
+	"strings"
 
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
 	"github.com/aws/amazon-cloudwatch-agent/cfg/commonconfig"
 	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/internal/featuregate"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
 	"github.com/aws/amazon-cloudwatch-agent/translator/util"
@@ -19,10 +23,28 @@ const (
 	debugKey          = "debug"
 	awsSdkLogLevelKey = "aws_sdk_log_level"
 	usageDataKey      = "usage_data"
+	fipsKey           = "fips"
+	dualStackKey      = "dualstack_endpoints"
+	adminAPIKey       = "admin_api"
+	vpcEndpointKey    = "vpc_endpoint_discovery"
+	profilingKey      = "profiling"
+	watchdogKey       = "watchdog"
+	featureGatesKey   = "feature_gates"
+	proxyKey          = "proxy"
+
+	pprofPortKey         = "pprof_port"
+	memoryThresholdMBKey = "memory_threshold_mb"
+	captureIntervalKey   = "capture_interval"
+	exportTimeoutKey     = "export_timeout"
 )
 
+// proxyServices are the services whose proxy can be overridden in the proxy
+// of the agent section.
+var proxyServices = []string{configaws.ProxyServiceLogs, configaws.ProxyServiceMetrics, configaws.ProxyServiceXray, configaws.ProxyServiceSSM}
+
 func ToEnvConfig(jsonConfigValue map[string]interface{}) []byte {
 	envVars := make(map[string]string)
+	proxyConfig := context.CurrentContext().Proxy()
 
 	if agentMap, ok := jsonConfigValue[agent.SectionKey].(map[string]interface{}); ok {
 		// Set CWAGENT_USER_AGENT to env config if specified by the json config in agent section
@@ -41,22 +63,113 @@ func ToEnvConfig(jsonConfigValue map[string]interface{}) []byte {
 		if usageData, ok := agentMap[usageDataKey].(bool); ok && !usageData {
 			envVars[envconfig.CWAGENT_USAGE_DATA] = "FALSE"
 		}
+
+		// Set AWS_USE_FIPS_ENDPOINT to env config if specified, otherwise the FIPS mode of the host is detected
+		if fips, ok := agentMap[fipsKey].(bool); ok {
+			envVars[envconfig.AWS_USE_FIPS_ENDPOINT] = strconv.FormatBool(fips)
+		}
+
+		// Set AWS_USE_DUALSTACK_ENDPOINT to env config if specified, otherwise it is enabled on the IPv6-only hosts
+		if dualStack, ok := agentMap[dualStackKey].(bool); ok {
+			envVars[envconfig.AWS_USE_DUALSTACK_ENDPOINT] = strconv.FormatBool(dualStack)
+		}
+
+		// Set CWAGENT_VPC_ENDPOINT_DISCOVERY to TRUE in env config if present and true in agent section
+		if vpcEndpoint, ok := agentMap[vpcEndpointKey].(bool); ok && vpcEndpoint {
+			envVars[envconfig.CWAGENT_VPC_ENDPOINT_DISCOVERY] = "TRUE"
+		}
+
+		// Set CWAGENT_ADMIN_API to TRUE in env config if present and true in agent section
+		if adminAPI, ok := agentMap[adminAPIKey].(bool); ok && adminAPI {
+			envVars[envconfig.CWAGENT_ADMIN_API] = "TRUE"
+		}
+
+		// Set the profiling env vars if the profiling is enabled in agent section
+		if profiling, ok := agentMap[profilingKey].(map[string]interface{}); ok {
+			if port, ok := profiling[pprofPortKey].(float64); ok && port > 0 {
+				envVars[envconfig.CWAGENT_PPROF_PORT] = strconv.Itoa(int(port))
+			}
+			if threshold, ok := profiling[memoryThresholdMBKey].(float64); ok && threshold > 0 {
+				envVars[envconfig.CWAGENT_PROFILE_THRESHOLD_MB] = strconv.Itoa(int(threshold))
+			}
+			if interval, ok := profiling[captureIntervalKey].(float64); ok && interval > 0 {
+				envVars[envconfig.CWAGENT_PROFILE_INTERVAL] = strconv.Itoa(int(interval)) + "s"
+			}
+		}
+
+		// Set CWAGENT_WATCHDOG_TIMEOUT to env config if the watchdog is enabled in agent section
+		if watchdog, ok := agentMap[watchdogKey].(map[string]interface{}); ok {
+			if timeout, ok := watchdog[exportTimeoutKey].(float64); ok && timeout > 0 {
+				envVars[envconfig.CWAGENT_WATCHDOG_TIMEOUT] = strconv.Itoa(int(timeout)) + "s"
+			}
+		}
+
+		// Set CWAGENT_FEATURE_GATES to env config if feature gates are set in agent section
+		if featureGates, ok := agentMap[featureGatesKey].(map[string]interface{}); ok {
+			gates := make(map[string]bool)
+			for id, value := range featureGates {
+				if enabled, ok := value.(bool); ok {
+					gates[id] = enabled
+				}
+			}
+			if len(gates) > 0 {
+				envVars[envconfig.CWAGENT_FEATURE_GATES] = featuregate.Format(gates)
+			}
+		}
+
+		// The proxy of the agent section overrides the proxy of the common config
+		if proxy, ok := agentMap[proxyKey].(map[string]interface{}); ok {
+			proxyConfig = mergeProxyConfig(proxyConfig, proxy)
+			for _, service := range proxyServices {
+				serviceProxy, ok := proxy[service].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				for key, name := range proxyEnvVars {
+					if val, ok := serviceProxy[key].(string); ok {
+						envVars[envconfig.ServiceProxy(service, name)] = val
+					}
+				}
+			}
+		}
 	}
 
-	proxy := util.GetHttpProxy(context.CurrentContext().Proxy())
+	// The credentials of the agent are used to resolve the ${secretsmanager:...}
+	// and ${ssm:...} references of the OTEL config before the components start
+	if profile, ok := agent.Global_Config.Credentials[agent.Profile_Key].(string); ok && profile != "" {
+		envVars[envconfig.CWAGENT_PROFILE] = profile
+	}
+	if filename, ok := agent.Global_Config.Credentials[agent.CredentialsFile_Key].(string); ok && filename != "" {
+		envVars[envconfig.CWAGENT_SHARED_CREDENTIAL_FILE] = filename
+	}
+	if agent.Global_Config.Role_arn != "" {
+		envVars[envconfig.CWAGENT_ROLE_ARN] = agent.Global_Config.Role_arn
+	}
+
+	proxy := util.GetHttpProxy(proxyConfig)
 	if len(proxy) > 0 {
 		envVars[envconfig.HTTP_PROXY] = proxy[commonconfig.HttpProxy]
 	}
 
-	proxy = util.GetHttpsProxy(context.CurrentContext().Proxy())
+	proxy = util.GetHttpsProxy(proxyConfig)
 	if len(proxy) > 0 {
 		envVars[envconfig.HTTPS_PROXY] = proxy[commonconfig.HttpsProxy]
 	}
 
-	proxy = util.GetNoProxy(context.CurrentContext().Proxy())
+	proxy = util.GetNoProxy(proxyConfig)
 	if len(proxy) > 0 {
 		envVars[envconfig.NO_PROXY] = proxy[commonconfig.NoProxy]
 	}
+	// The clients not using the proxy resolver of the agent, e.g. the IMDS clients
+	// of the OTel exporters, must reach IMDS directly
+	if envVars[envconfig.HTTP_PROXY] != "" || envVars[envconfig.HTTPS_PROXY] != "" {
+		envVars[envconfig.NO_PROXY] = withDefaultNoProxy(envVars[envconfig.NO_PROXY])
+	}
+
+	// The policy of the host requires the agent to use IMDSv2 only
+	if context.CurrentContext().IMDSv1Disabled() {
+		envVars[envconfig.AWS_EC2_METADATA_V1_DISABLED] = "true"
+	}
 
 	sslConfig := util.GetSSL(context.CurrentContext().SSL())
 	if len(sslConfig) > 0 {
@@ -69,3 +182,43 @@ func ToEnvConfig(jsonConfigValue map[string]interface{}) []byte {
 	}
 	return bytes
 }
+
+// proxyEnvVars are the environment variables of the proxy keys.
+var proxyEnvVars = map[string]string{
+	commonconfig.HttpProxy:  envconfig.HTTP_PROXY,
+	commonconfig.HttpsProxy: envconfig.HTTPS_PROXY,
+	commonconfig.NoProxy:    envconfig.NO_PROXY,
+}
+
+// mergeProxyConfig returns the proxy config with the proxy keys of the agent
+// section.
+func mergeProxyConfig(proxyConfig map[string]string, proxy map[string]interface{}) map[string]string {
+	merged := make(map[string]string, len(proxyConfig))
+	for key, val := range proxyConfig {
+		merged[key] = val
+	}
+	for key := range proxyEnvVars {
+		if val, ok := proxy[key].(string); ok {
+			merged[key] = val
+		}
+	}
+	return merged
+}
+
+// withDefaultNoProxy appends the addresses that are always reached directly
+// to the no_proxy value.
+func withDefaultNoProxy(val string) string {
+	entries := configaws.DefaultNoProxy
+	if val != "" {
+		entries = append(strings.Split(val, ","), configaws.DefaultNoProxy...)
+	}
+	var result []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if entry = strings.TrimSpace(entry); entry != "" && !seen[entry] {
+			seen[entry] = true
+			result = append(result, entry)
+		}
+	}
+	return strings.Join(result, ",")
+}
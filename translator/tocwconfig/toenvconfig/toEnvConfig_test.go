@@ -0,0 +1,287 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package toenvconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+)
+
+func TestToEnvConfigFIPS(t *testing.T) {
+	testCases := map[string]struct {
+		agent  map[string]interface{}
+		want   string
+		wantOk bool
+	}{
+		"WithFIPS": {
+			agent:  map[string]interface{}{"fips": true},
+			want:   "true",
+			wantOk: true,
+		},
+		"WithoutFIPS": {
+			agent:  map[string]interface{}{"fips": false},
+			want:   "false",
+			wantOk: true,
+		},
+		// the FIPS mode of the host is detected by the agent
+		"WithDefault": {
+			agent: map[string]interface{}{},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var got map[string]string
+			require.NoError(t, json.Unmarshal(ToEnvConfig(map[string]interface{}{"agent": testCase.agent}), &got))
+			value, ok := got[envconfig.AWS_USE_FIPS_ENDPOINT]
+			assert.Equal(t, testCase.wantOk, ok)
+			assert.Equal(t, testCase.want, value)
+		})
+	}
+}
+
+func TestToEnvConfigDualStack(t *testing.T) {
+	testCases := map[string]struct {
+		agent  map[string]interface{}
+		want   string
+		wantOk bool
+	}{
+		"WithDualStack": {
+			agent:  map[string]interface{}{"dualstack_endpoints": true},
+			want:   "true",
+			wantOk: true,
+		},
+		"WithoutDualStack": {
+			agent:  map[string]interface{}{"dualstack_endpoints": false},
+			want:   "false",
+			wantOk: true,
+		},
+		// enabled by the agent on the IPv6-only hosts
+		"WithDefault": {
+			agent: map[string]interface{}{},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var got map[string]string
+			require.NoError(t, json.Unmarshal(ToEnvConfig(map[string]interface{}{"agent": testCase.agent}), &got))
+			value, ok := got[envconfig.AWS_USE_DUALSTACK_ENDPOINT]
+			assert.Equal(t, testCase.wantOk, ok)
+			assert.Equal(t, testCase.want, value)
+		})
+	}
+}
+
+func TestToEnvConfigFeatureGates(t *testing.T) {
+	testCases := map[string]struct {
+		agent  map[string]interface{}
+		want   string
+		wantOk bool
+	}{
+		"WithFeatureGates": {
+			agent: map[string]interface{}{"feature_gates": map[string]interface{}{
+				"cwagent.cloudwatch.adaptiveBatching": true,
+				"cwagent.beta":                        false,
+			}},
+			want:   "-cwagent.beta,+cwagent.cloudwatch.adaptiveBatching",
+			wantOk: true,
+		},
+		"WithEmptyFeatureGates": {
+			agent: map[string]interface{}{"feature_gates": map[string]interface{}{}},
+		},
+		"WithDefault": {
+			agent: map[string]interface{}{},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var got map[string]string
+			require.NoError(t, json.Unmarshal(ToEnvConfig(map[string]interface{}{"agent": testCase.agent}), &got))
+			value, ok := got[envconfig.CWAGENT_FEATURE_GATES]
+			assert.Equal(t, testCase.wantOk, ok)
+			assert.Equal(t, testCase.want, value)
+		})
+	}
+}
+
+func TestToEnvConfigVPCEndpointDiscovery(t *testing.T) {
+	testCases := map[string]struct {
+		agent  map[string]interface{}
+		wantOk bool
+	}{
+		"WithVPCEndpointDiscovery": {
+			agent:  map[string]interface{}{"vpc_endpoint_discovery": true},
+			wantOk: true,
+		},
+		"WithoutVPCEndpointDiscovery": {
+			agent: map[string]interface{}{"vpc_endpoint_discovery": false},
+		},
+		"WithDefault": {
+			agent: map[string]interface{}{},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var got map[string]string
+			require.NoError(t, json.Unmarshal(ToEnvConfig(map[string]interface{}{"agent": testCase.agent}), &got))
+			value, ok := got[envconfig.CWAGENT_VPC_ENDPOINT_DISCOVERY]
+			assert.Equal(t, testCase.wantOk, ok)
+			if testCase.wantOk {
+				assert.Equal(t, "TRUE", value)
+			}
+		})
+	}
+}
+
+func TestToEnvConfigAdminAPI(t *testing.T) {
+	testCases := map[string]struct {
+		agent  map[string]interface{}
+		wantOk bool
+	}{
+		"WithAdminAPI": {
+			agent:  map[string]interface{}{"admin_api": true},
+			wantOk: true,
+		},
+		"WithoutAdminAPI": {
+			agent: map[string]interface{}{"admin_api": false},
+		},
+		"WithDefault": {
+			agent: map[string]interface{}{},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var got map[string]string
+			require.NoError(t, json.Unmarshal(ToEnvConfig(map[string]interface{}{"agent": testCase.agent}), &got))
+			value, ok := got[envconfig.CWAGENT_ADMIN_API]
+			assert.Equal(t, testCase.wantOk, ok)
+			if testCase.wantOk {
+				assert.Equal(t, "TRUE", value)
+			}
+		})
+	}
+}
+
+func TestToEnvConfigProfiling(t *testing.T) {
+	testCases := map[string]struct {
+		agent map[string]interface{}
+		want  map[string]string
+	}{
+		"WithProfiling": {
+			agent: map[string]interface{}{"profiling": map[string]interface{}{
+				"pprof_port":          float64(6060),
+				"memory_threshold_mb": float64(1024),
+				"capture_interval":    float64(1800),
+			}},
+			want: map[string]string{
+				envconfig.CWAGENT_PPROF_PORT:           "6060",
+				envconfig.CWAGENT_PROFILE_THRESHOLD_MB: "1024",
+				envconfig.CWAGENT_PROFILE_INTERVAL:     "1800s",
+			},
+		},
+		"WithThresholdOnly": {
+			agent: map[string]interface{}{"profiling": map[string]interface{}{"memory_threshold_mb": float64(512)}},
+			want:  map[string]string{envconfig.CWAGENT_PROFILE_THRESHOLD_MB: "512"},
+		},
+		"WithWatchdog": {
+			agent: map[string]interface{}{"watchdog": map[string]interface{}{"export_timeout": float64(900)}},
+			want:  map[string]string{envconfig.CWAGENT_WATCHDOG_TIMEOUT: "900s"},
+		},
+		"WithDefault": {
+			agent: map[string]interface{}{},
+			want:  map[string]string{},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var got map[string]string
+			require.NoError(t, json.Unmarshal(ToEnvConfig(map[string]interface{}{"agent": testCase.agent}), &got))
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestToEnvConfigProxy(t *testing.T) {
+	for _, name := range []string{"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy", "NO_PROXY", "no_proxy"} {
+		t.Setenv(name, "")
+	}
+	testCases := map[string]struct {
+		agent map[string]interface{}
+		want  map[string]string
+	}{
+		"WithProxy": {
+			agent: map[string]interface{}{
+				"proxy": map[string]interface{}{
+					"https_proxy": "http://proxy:3128",
+					"no_proxy":    "10.0.0.0/8, example.com",
+				},
+			},
+			want: map[string]string{
+				envconfig.HTTPS_PROXY: "http://proxy:3128",
+				envconfig.NO_PROXY:    "10.0.0.0/8,example.com,169.254.0.0/16,fd00:ec2::/32,.vpce.amazonaws.com",
+			},
+		},
+		"WithServiceProxy": {
+			agent: map[string]interface{}{
+				"proxy": map[string]interface{}{
+					"http_proxy": "http://proxy:3128",
+					"logs":       map[string]interface{}{"https_proxy": "http://logs-proxy:3128", "no_proxy": ""},
+					"ssm":        map[string]interface{}{"http_proxy": ""},
+				},
+			},
+			want: map[string]string{
+				envconfig.HTTP_PROXY:       "http://proxy:3128",
+				envconfig.NO_PROXY:         "169.254.0.0/16,fd00:ec2::/32,.vpce.amazonaws.com",
+				"CWAGENT_LOGS_HTTPS_PROXY": "http://logs-proxy:3128",
+				"CWAGENT_LOGS_NO_PROXY":    "",
+				"CWAGENT_SSM_HTTP_PROXY":   "",
+			},
+		},
+		"WithDefault": {
+			agent: map[string]interface{}{},
+			want:  map[string]string{},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var got map[string]string
+			require.NoError(t, json.Unmarshal(ToEnvConfig(map[string]interface{}{"agent": testCase.agent}), &got))
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestToEnvConfigIMDSv1Disabled(t *testing.T) {
+	t.Cleanup(context.ResetContext)
+	var got map[string]string
+	require.NoError(t, json.Unmarshal(ToEnvConfig(map[string]interface{}{}), &got))
+	_, ok := got[envconfig.AWS_EC2_METADATA_V1_DISABLED]
+	assert.False(t, ok)
+
+	context.CurrentContext().SetIMDSv1Disabled(true)
+	require.NoError(t, json.Unmarshal(ToEnvConfig(map[string]interface{}{}), &got))
+	assert.Equal(t, "true", got[envconfig.AWS_EC2_METADATA_V1_DISABLED])
+}
+
+func TestToEnvConfigCredentials(t *testing.T) {
+	original := agent.Global_Config
+	t.Cleanup(func() { agent.Global_Config = original })
+	agent.Global_Config.Credentials = map[string]interface{}{
+		agent.Profile_Key:         "AmazonCloudWatchAgent",
+		agent.CredentialsFile_Key: "/root/.aws/credentials",
+	}
+	agent.Global_Config.Role_arn = "arn:aws:iam::123456789012:role/agent"
+
+	var got map[string]string
+	require.NoError(t, json.Unmarshal(ToEnvConfig(map[string]interface{}{}), &got))
+	assert.Equal(t, "AmazonCloudWatchAgent", got[envconfig.CWAGENT_PROFILE])
+	assert.Equal(t, "/root/.aws/credentials", got[envconfig.CWAGENT_SHARED_CREDENTIAL_FILE])
+	assert.Equal(t, "arn:aws:iam::123456789012:role/agent", got[envconfig.CWAGENT_ROLE_ARN])
+}
@@ -32,20 +32,29 @@ type (
 
 	inputConfig struct {
 		Cadvisor        []cadvisorConfig
+		Collectd        []collectdConfig `toml:"collectd"`
 		Cpu             []cpuConfig
 		Disk            []diskConfig
 		DiskIo          []diskioConfig
+		DiskStats       []diskStatsConfig  `toml:"diskstats"`
+		DockerLogs      []dockerLogsConfig `toml:"docker_logs"`
+		Efa             []efaConfig
 		Ethtool         []ethtoolConfig
+		FluentForward   []fluentForwardConfig `toml:"fluent_forward"`
+		Journald        []journaldConfig      `toml:"journald"`
 		K8sapiserver    []k8sApiServerConfig
+		KafkaLogs       []kafkaLogsConfig   `toml:"kafka_logs"`
+		KinesisLogs     []kinesisLogsConfig `toml:"kinesis_logs"`
 		Logfile         []logFileConfig
 		Mem             []memConfig
 		Net             []netConfig
 		NetStat         []netStatConfig
-		NvidiaSmi       []nvidiaSmi `toml:"nvidia_smi"`
+		Nfs             []nfsConfig
+		NvidiaSmi       []nvidiaSmi      `toml:"nvidia_smi"`
+		OtlpLogs        []otlpLogsConfig `toml:"otlp_logs"`
 		Processes       []processesConfig
 		Prometheus      []prometheusConfig `toml:"prometheus"`
 		ProcStat        []procStatConfig
-		SocketListener  []socketListenerConfig `toml:"socket_listener"`
 		Statsd          []statsdConfig
 		Swap            []swapConfig
 		WindowsEventLog []windowsEventLogConfig `toml:"windows_event_log"`
@@ -96,6 +105,29 @@ type (
 		Interval  string
 	}
 
+	diskStatsConfig struct {
+		Cgroups        []string
+		Devices        []string
+		ExcludeDevices []string `toml:"exclude_devices"`
+		FieldPass      []string
+		Interval       string
+		SampleInterval string `toml:"sample_interval"`
+		Tags           map[string]string
+	}
+
+	nfsConfig struct {
+		FieldPass    []string
+		MountPoints  []string `toml:"mount_points"`
+		ProbeTimeout string   `toml:"probe_timeout"`
+		Tags         map[string]string
+	}
+
+	efaConfig struct {
+		DeviceInclude []string `toml:"device_include"`
+		FieldPass     []string
+		Tags          map[string]string
+	}
+
 	ethtoolConfig struct {
 		FieldPass        []string
 		InterfaceInclude []string `toml:"interface_include"`
@@ -108,13 +140,16 @@ type (
 		EventName       string   `toml:"event_name"`
 		LogGroupName    string   `toml:"log_group_name"`
 		LogStreamName   string   `toml:"log_stream_name"`
+		RemoteHost      string   `toml:"remote_host"`
 		RetentionInDays int      `toml:"retention_in_days"`
 	}
 
 	logFileConfig struct {
-		Destination     string
-		FileStateFolder string       `toml:"file_state_folder"`
-		FileConfig      []fileConfig `toml:"file_config"`
+		Destination           string
+		FileStateFolder       string       `toml:"file_state_folder"`
+		FileConfig            []fileConfig `toml:"file_config"`
+		MemoryLimitPercentage int          `toml:"memory_limit_percentage"`
+		MemoryCheckInterval   string       `toml:"memory_check_interval"`
 	}
 
 	fileConfig struct {
@@ -178,13 +213,15 @@ type (
 	}
 
 	prometheusEcsServiceDiscoveryConfig struct {
-		SdClusterRegion         string                    `toml:"sd_cluster_region"`
-		SdFrequency             string                    `toml:"sd_frequency"`
-		SdResultFile            string                    `toml:"sd_result_file"`
-		SdTargetCluster         string                    `toml:"sd_target_cluster"`
-		DockerLabel             map[string]string         `toml:"docker_label"`
-		ServiceNameListForTasks []serviceNameListForTasks `toml:"service_name_list_for_tasks"`
-		TaskDefinitionList      []taskDefinitionList      `toml:"task_definition_list"`
+		SdClusterRegion             string                        `toml:"sd_cluster_region"`
+		SdFrequency                 string                        `toml:"sd_frequency"`
+		SdResultFile                string                        `toml:"sd_result_file"`
+		SdTargetCluster             string                        `toml:"sd_target_cluster"`
+		DockerLabel                 map[string]string             `toml:"docker_label"`
+		ServiceNameListForTasks     []serviceNameListForTasks     `toml:"service_name_list_for_tasks"`
+		TaskDefinitionList          []taskDefinitionList          `toml:"task_definition_list"`
+		ServiceConnectNamespaceList []serviceConnectNamespaceList `toml:"service_connect_namespace_list"`
+		TaskTagList                 []taskTagList                 `toml:"task_tag_list"`
 	}
 
 	serviceNameListForTasks struct {
@@ -202,6 +239,22 @@ type (
 		SdTaskDefinitionArnPattern string `toml:"sd_task_definition_arn_pattern"`
 	}
 
+	serviceConnectNamespaceList struct {
+		SdContainerNamePattern string `toml:"sd_container_name_pattern"`
+		SdJobName              string `toml:"sd_job_name"`
+		SdMetricsPath          string `toml:"sd_metrics_path"`
+		SdMetricsPorts         string `toml:"sd_metrics_ports"`
+		SdNamespacePattern     string `toml:"sd_namespace_pattern"`
+	}
+
+	taskTagList struct {
+		SdContainerNamePattern string `toml:"sd_container_name_pattern"`
+		SdJobName              string `toml:"sd_job_name"`
+		SdMetricsPath          string `toml:"sd_metrics_path"`
+		SdMetricsPorts         string `toml:"sd_metrics_ports"`
+		SdTagExpression        string `toml:"sd_tag_expression"`
+	}
+
 	procStatConfig struct {
 		FieldPass  []string
 		PidFile    string `toml:"pid_file"`
@@ -210,15 +263,15 @@ type (
 		Tags       map[string]string
 	}
 
-	socketListenerConfig struct {
-		CollectdAuthFile      string   `toml:"collectd_auth_file"`
-		CollectdSecurityLevel string   `toml:"collectd_security_level"`
-		CollectdTypesDb       []string `toml:"collectd_typesdb"`
-		DataFormat            string   `toml:"data_format"`
-		NamePrefix            string   `toml:"name_prefix"`
-		NameOverride          string   `toml:"name_override"`
-		ServiceAddress        string   `toml:"service_address"`
-		Tags                  map[string]string
+	collectdConfig struct {
+		CollectdAuthFile              string   `toml:"collectd_auth_file"`
+		CollectdSecurityLevel         string   `toml:"collectd_security_level"`
+		CollectdTypesDb               []string `toml:"collectd_typesdb"`
+		CollectdTypesDbReloadInterval string   `toml:"collectd_typesdb_reload_interval"`
+		NamePrefix                    string   `toml:"name_prefix"`
+		NameOverride                  string   `toml:"name_override"`
+		ServiceAddress                string   `toml:"service_address"`
+		Tags                          map[string]string
 	}
 
 	statsdConfig struct {
@@ -242,6 +295,102 @@ type (
 		Tags            map[string]string
 	}
 
+	kafkaLogsConfig struct {
+		Brokers       []string
+		ConsumerGroup string `toml:"consumer_group"`
+		Destination   string
+		InitialOffset string `toml:"initial_offset"`
+		Region        string
+		RoleArn       string        `toml:"role_arn"`
+		SASLMechanism string        `toml:"sasl_mechanism"`
+		TLS           bool          `toml:"tls"`
+		TopicConfig   []topicConfig `toml:"topic_config"`
+	}
+
+	topicConfig struct {
+		LogGroupClass string `toml:"log_group_class"`
+		LogGroupName  string `toml:"log_group_name"`
+		LogStreamName string `toml:"log_stream_name"`
+		Retention     int    `toml:"retention_in_days"`
+		Topics        []string
+	}
+
+	kinesisLogsConfig struct {
+		ApplicationName string `toml:"application_name"`
+		CheckpointStore string `toml:"checkpoint_store"`
+		Destination     string
+		EnhancedFanOut  bool   `toml:"enhanced_fan_out"`
+		FileStateFolder string `toml:"file_state_folder"`
+		InitialPosition string `toml:"initial_position"`
+		LeaseTableName  string `toml:"lease_table_name"`
+		Region          string
+		RoleArn         string                `toml:"role_arn"`
+		StreamConfig    []kinesisStreamConfig `toml:"stream_config"`
+	}
+
+	kinesisStreamConfig struct {
+		LogGroupClass string   `toml:"log_group_class"`
+		LogGroupName  string   `toml:"log_group_name"`
+		LogStreamName string   `toml:"log_stream_name"`
+		Retention     int      `toml:"retention_in_days"`
+		StreamNames   []string `toml:"stream_names"`
+	}
+
+	journaldConfig struct {
+		Destination     string
+		FileStateFolder string          `toml:"file_state_folder"`
+		JournalConfig   []journalConfig `toml:"journal_config"`
+	}
+
+	journalConfig struct {
+		EventFormat   string `toml:"event_format"`
+		LogGroupClass string `toml:"log_group_class"`
+		LogGroupName  string `toml:"log_group_name"`
+		LogStreamName string `toml:"log_stream_name"`
+		Priority      string
+		Retention     int `toml:"retention_in_days"`
+		Units         []string
+	}
+
+	dockerLogsConfig struct {
+		ContainerConfig []containerConfig `toml:"container_config"`
+		Destination     string
+		Endpoint        string
+		FileStateFolder string `toml:"file_state_folder"`
+	}
+
+	containerConfig struct {
+		LabelFilters  []string `toml:"label_filters"`
+		LogGroupClass string   `toml:"log_group_class"`
+		LogGroupName  string   `toml:"log_group_name"`
+		LogStreamName string   `toml:"log_stream_name"`
+		Retention     int      `toml:"retention_in_days"`
+		Streams       []string
+	}
+
+	fluentForwardConfig struct {
+		Destination    string
+		LogGroupClass  string `toml:"log_group_class"`
+		LogGroupName   string `toml:"log_group_name"`
+		LogStreamName  string `toml:"log_stream_name"`
+		MessageKey     string `toml:"message_key"`
+		Retention      int    `toml:"retention_in_days"`
+		ServiceAddress string `toml:"service_address"`
+		SharedKey      string `toml:"shared_key"`
+	}
+
+	otlpLogsConfig struct {
+		Destination   string
+		HTTPEndpoint  string `toml:"http_endpoint"`
+		LogGroupClass string `toml:"log_group_class"`
+		LogGroupName  string `toml:"log_group_name"`
+		LogStreamName string `toml:"log_stream_name"`
+		RawLog        bool   `toml:"raw_log"`
+		Retention     int    `toml:"retention_in_days"`
+		TLSCert       string `toml:"tls_cert"`
+		TLSKey        string `toml:"tls_key"`
+	}
+
 	// Output plugins
 
 	cloudWatchOutputConfig struct {
@@ -267,11 +416,13 @@ type (
 	}
 
 	cloudWatchLogsConfig struct {
-		EndpointOverride   string `toml:"endpoint_override"`
-		ForceFlushInterval string `toml:"force_flush_interval"`
-		LogStreamName      string `toml:"log_stream_name"`
+		EndpointOverride   string   `toml:"endpoint_override"`
+		FailoverEndpoints  []string `toml:"failover_endpoints"`
+		ForceFlushInterval string   `toml:"force_flush_interval"`
+		LogStreamName      string   `toml:"log_stream_name"`
 		Region             string
-		RoleArn            string `toml:"role_arn"`
+		RoleArn            string   `toml:"role_arn"`
+		SigV4aRegionSet    []string `toml:"sigv4a_region_set"`
 		TagExclude         []string
 		TagPass            map[string][]string
 	}
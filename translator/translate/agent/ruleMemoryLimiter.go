@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	MemoryLimiterKey                  = "memory_limiter"
+	DefaultMemoryLimitPercentage      = 80
+	DefaultMemorySpikeLimitPercentage = 25
+	DefaultMemoryCheckInterval        = time.Second
+)
+
+// MemoryLimiter bounds the memory used by the agent, as percentages of the
+// total memory available to it.
+type MemoryLimiter struct {
+	LimitPercentage      int
+	SpikeLimitPercentage int
+	CheckInterval        time.Duration
+}
+
+// SoftLimitPercentage is the memory usage from which the pipelines refuse data
+// and the receivers pause reading.
+func (m *MemoryLimiter) SoftLimitPercentage() int {
+	return m.LimitPercentage - m.SpikeLimitPercentage
+}
+
+// ParseMemoryLimiter returns the memory limiter of the agent section, or nil if
+// it is not configured.
+func ParseMemoryLimiter(input interface{}) *MemoryLimiter {
+	m, ok := input.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	section, ok := m[MemoryLimiterKey].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	_, limit := translator.DefaultIntegralCase("limit_percentage", float64(DefaultMemoryLimitPercentage), section)
+	_, spike := translator.DefaultIntegralCase("spike_limit_percentage", float64(DefaultMemorySpikeLimitPercentage), section)
+	_, interval := translator.DefaultIntegralCase("check_interval", DefaultMemoryCheckInterval.Seconds(), section)
+	result := &MemoryLimiter{}
+	result.LimitPercentage, _ = limit.(int)
+	result.SpikeLimitPercentage, _ = spike.(int)
+	if seconds, ok := interval.(int); ok {
+		result.CheckInterval = time.Duration(seconds) * time.Second
+	}
+	return result
+}
+
+type MemoryLimiterRule struct {
+}
+
+// The memory limiter is applied to the pipelines and to the receivers, so it
+// should be applied before interpreting other component.
+func (m *MemoryLimiterRule) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	Global_Config.MemoryLimiter = ParseMemoryLimiter(input)
+	if limiter := Global_Config.MemoryLimiter; limiter != nil && limiter.SpikeLimitPercentage >= limiter.LimitPercentage {
+		translator.AddErrorMessages(
+			GetCurPath()+MemoryLimiterKey,
+			fmt.Sprintf("spike_limit_percentage (%d) must be lower than limit_percentage (%d)", limiter.SpikeLimitPercentage, limiter.LimitPercentage))
+	}
+	return
+}
+
+func init() {
+	m := new(MemoryLimiterRule)
+	RegisterRule(MemoryLimiterKey, m)
+}
@@ -48,6 +48,7 @@ func (l *Logs) ApplyRule(input interface{}) (returnKey string, returnVal interfa
 	inputs := map[string]interface{}{}
 	processors := map[string]interface{}{}
 	cloudwatchConfig := map[string]interface{}{}
+	var s3Config map[string]interface{}
 	GlobalLogConfig.MetadataInfo = util.GetMetadataInfo(util.Ec2MetadataInfoProvider)
 
 	//Apply Environment and ServiceName rules
@@ -61,8 +62,9 @@ func (l *Logs) ApplyRule(input interface{}) (returnKey string, returnVal interfa
 		returnVal = ""
 	} else {
 		//If yes, process it
+		logsSection := resolveTransformTemplates(im[SectionKey])
 		for _, rule := range ChildRule {
-			key, val := rule.ApplyRule(im[SectionKey])
+			key, val := rule.ApplyRule(logsSection)
 			//If key == "", then no instance of this class in input
 			if key != "" {
 				if key == "metrics_collected" {
@@ -84,12 +86,17 @@ func (l *Logs) ApplyRule(input interface{}) (returnKey string, returnVal interfa
 					inputs = translator.MergeTwoUniqueMaps(inputs, val.(map[string]interface{}))
 				} else if key == Output_Cloudwatch_Logs {
 					cloudwatchConfig = translator.MergeTwoUniqueMaps(cloudwatchConfig, val.(map[string]interface{}))
+				} else if key == Output_S3_Logs {
+					s3Config = val.(map[string]interface{})
 				}
 			}
 		}
 
 		cloudwatchInfo := map[string]interface{}{}
 		cloudwatchInfo["cloudwatchlogs"] = []interface{}{cloudwatchConfig}
+		if s3Config != nil {
+			cloudwatchInfo[Output_S3_Logs] = []interface{}{s3Config}
+		}
 		result["outputs"] = cloudwatchInfo
 
 		if len(inputs) > 0 {
@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectlist
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+func TestApplyRule(t *testing.T) {
+	translator.ResetMessages()
+	c := new(CollectList)
+	var rawJsonString = `
+{
+	"collect_list": [
+		{
+			"label_filters": ["com.example.team=payments", "com.example.service"],
+			"log_group_name": "/docker/{label:com.example.service}"
+		},
+		{
+			"streams": ["stderr"],
+			"log_group_name": "docker",
+			"log_stream_name": "{image}_{container_id}",
+			"log_group_class": "INFREQUENT_ACCESS",
+			"retention_in_days": 7
+		}
+	]
+}
+`
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(rawJsonString), &input))
+
+	var expected = []interface{}{
+		map[string]interface{}{
+			"label_filters":     []interface{}{"com.example.team=payments", "com.example.service"},
+			"log_group_name":    "/docker/{label:com.example.service}",
+			"log_stream_name":   "{container_name}",
+			"log_group_class":   "",
+			"retention_in_days": -1,
+		},
+		map[string]interface{}{
+			"streams":           []interface{}{"stderr"},
+			"log_group_name":    "docker",
+			"log_stream_name":   "{image}_{container_id}",
+			"log_group_class":   util.InfrequentAccessLogGroupClass,
+			"retention_in_days": 7,
+		},
+	}
+	key, actual := c.ApplyRule(input)
+	assert.Equal(t, ContainerConfigTomlKey, key)
+	assert.Equal(t, expected, actual)
+	assert.Empty(t, translator.ErrorMessages)
+}
@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/mergeJsonRule"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/mergeJsonUtil"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected"
+)
+
+var ChildRule = map[string]translator.Rule{}
+
+type Docker struct {
+}
+
+const (
+	SectionKey       = "docker"
+	SectionMappedKey = "docker_logs"
+)
+
+func GetCurPath() string {
+	return parent.GetCurPath() + SectionKey + "/"
+}
+
+func RegisterRule(ruleName string, r translator.Rule) {
+	ChildRule[ruleName] = r
+}
+
+func (d *Docker) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	dockerConfig := map[string]interface{}{
+		"destination": "cloudwatchlogs",
+	}
+
+	if _, ok := im[SectionKey]; ok {
+		for _, rule := range ChildRule {
+			key, val := rule.ApplyRule(im[SectionKey])
+			if key != "" {
+				dockerConfig[key] = val
+			}
+		}
+
+		return "inputs", map[string]interface{}{
+			SectionMappedKey: []interface{}{dockerConfig},
+		}
+	} else {
+		return "", ""
+	}
+}
+
+var MergeRuleMap = map[string]mergeJsonRule.MergeRule{}
+
+func (d *Docker) Merge(source map[string]interface{}, result map[string]interface{}) {
+	mergeJsonUtil.MergeMap(source, result, SectionKey, MergeRuleMap, GetCurPath())
+}
+
+// The containers are only collected on Linux, where the Docker Engine API
+// listens on a unix socket.
+func init() {
+	obj := new(Docker)
+	parent.RegisterLinuxRule(SectionKey, obj)
+	parent.MergeRuleMap[SectionKey] = obj
+}
@@ -0,0 +1,51 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
+)
+
+func TestApplyRule(t *testing.T) {
+	translator.ResetMessages()
+	d := new(Docker)
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"docker": {"collect_list": []}}`), &input))
+
+	var expected = map[string]interface{}{
+		"docker_logs": []interface{}{
+			map[string]interface{}{
+				"destination":       "cloudwatchlogs",
+				"endpoint":          "unix:///var/run/docker.sock",
+				"file_state_folder": util.GetFileStateFolder(),
+			},
+		},
+	}
+	key, actual := d.ApplyRule(input)
+	assert.Equal(t, "inputs", key)
+	assert.Equal(t, expected, actual)
+	assert.Empty(t, translator.ErrorMessages)
+}
+
+func TestApplyRuleWithEndpoint(t *testing.T) {
+	d := new(Docker)
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"docker": {"endpoint": "unix:///run/podman/podman.sock", "collect_list": []}}`), &input))
+	_, actual := d.ApplyRule(input)
+	config := actual.(map[string]interface{})["docker_logs"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "unix:///run/podman/podman.sock", config["endpoint"])
+}
+
+func TestApplyRuleNoDocker(t *testing.T) {
+	d := new(Docker)
+	key, _ := d.ApplyRule(map[string]interface{}{})
+	assert.Equal(t, "", key)
+}
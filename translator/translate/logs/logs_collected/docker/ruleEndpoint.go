@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	EndpointSectionKey = "endpoint"
+	defaultEndpoint    = "unix:///var/run/docker.sock"
+)
+
+type Endpoint struct {
+}
+
+func (e *Endpoint) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return translator.DefaultCase(EndpointSectionKey, defaultEndpoint, input)
+}
+
+func init() {
+	RegisterRule(EndpointSectionKey, new(Endpoint))
+}
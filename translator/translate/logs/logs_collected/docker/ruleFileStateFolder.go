@@ -0,0 +1,18 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker
+
+import "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
+
+type FileStateFolder struct {
+}
+
+// The timestamps are saved with the other log states, so this is not exposed to the customer.
+func (f *FileStateFolder) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return "file_state_folder", util.GetFileStateFolder()
+}
+
+func init() {
+	RegisterRule("file_state_folder", new(FileStateFolder))
+}
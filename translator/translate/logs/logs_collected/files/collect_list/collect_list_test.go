@@ -44,6 +44,30 @@ func TestFileConfig(t *testing.T) {
 	assert.Equal(t, expectVal, val)
 }
 
+func TestFileConfigWithFieldReferences(t *testing.T) {
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{"collect_list":[{"file_path":"path1",
+            "log_group_name":"/eks/{$.kubernetes.namespace_name}","log_stream_name":"{$.kubernetes.pod_name}"}]}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+
+	expectVal := []interface{}{map[string]interface{}{
+		"file_path":              "path1",
+		"from_beginning":         true,
+		"log_group_class":        "",
+		"log_group_name":         "/eks/{$.kubernetes.namespace_name}",
+		"log_stream_name":        "{$.kubernetes.pod_name}",
+		"pipe":                   false,
+		"retention_in_days":      -1,
+		"service_name":           "",
+		"deployment_environment": "",
+	}}
+	assert.Equal(t, expectVal, val)
+}
+
 func TestFileConfigOverride(t *testing.T) {
 	f := new(FileConfig)
 	var input interface{}
@@ -66,6 +90,31 @@ func TestFileConfigOverride(t *testing.T) {
 	assert.Equal(t, expectVal, val)
 }
 
+func TestCrossAccountDestination(t *testing.T) {
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{"collect_list":[{"file_path":"path1",
+            "log_group_name":"group1","role_arn":"arn:aws:iam::222222222222:role/central-logs","region":"eu-west-1"}]}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+
+	expectVal := []interface{}{map[string]interface{}{
+		"file_path":              "path1",
+		"from_beginning":         true,
+		"log_group_name":         "group1",
+		"log_group_class":        "",
+		"pipe":                   false,
+		"retention_in_days":      -1,
+		"service_name":           "",
+		"deployment_environment": "",
+		"role_arn":               "arn:aws:iam::222222222222:role/central-logs",
+		"region":                 "eu-west-1",
+	}}
+	assert.Equal(t, expectVal, val)
+}
+
 func TestTimestampFormat(t *testing.T) {
 	f := new(FileConfig)
 	var input interface{}
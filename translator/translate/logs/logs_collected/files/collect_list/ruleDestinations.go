@@ -0,0 +1,50 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"strings"
+)
+
+const DestinationsSectionKey = "destinations"
+
+// destinationOutputs are the outputs of the destinations of the collect_list entries.
+var destinationOutputs = map[string]string{
+	"cloudwatchlogs": "cloudwatchlogs",
+	"s3":             "s3logs",
+}
+
+// Destinations are the outputs the events of the file are published to, e.g. an S3 bucket
+// instead of or in addition to CloudWatch Logs. The log agent publishes to each of the
+// outputs in the comma separated destination of the file.
+type Destinations struct {
+}
+
+func (d *Destinations) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m, ok := input.(map[string]interface{})
+	if !ok {
+		return
+	}
+	names, ok := m[DestinationsSectionKey].([]interface{})
+	if !ok {
+		return
+	}
+	var outputs []string
+	for _, name := range names {
+		s, _ := name.(string)
+		if output, ok := destinationOutputs[s]; ok {
+			outputs = append(outputs, output)
+		}
+	}
+	if len(outputs) > 0 {
+		returnKey = "destination"
+		returnVal = strings.Join(outputs, ",")
+	}
+	return
+}
+
+func init() {
+	d := new(Destinations)
+	RegisterRule(DestinationsSectionKey, []Rule{d})
+}
@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyDestinationsRule(t *testing.T) {
+	testCases := map[string]struct {
+		input   string
+		wantKey string
+		wantVal interface{}
+	}{
+		"S3": {
+			input:   `{"destinations": ["s3"]}`,
+			wantKey: "destination",
+			wantVal: "s3logs",
+		},
+		"Both": {
+			input:   `{"destinations": ["cloudwatchlogs", "s3"]}`,
+			wantKey: "destination",
+			wantVal: "cloudwatchlogs,s3logs",
+		},
+		"Unset": {
+			input: `{"file_path": "/var/log/app.log"}`,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var input interface{}
+			require.NoError(t, json.Unmarshal([]byte(testCase.input), &input))
+			key, val := new(Destinations).ApplyRule(input)
+			assert.Equal(t, testCase.wantKey, key)
+			assert.Equal(t, testCase.wantVal, val)
+		})
+	}
+}
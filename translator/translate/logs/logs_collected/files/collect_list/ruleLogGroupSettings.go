@@ -0,0 +1,35 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	TagsSectionKey           = "tags"
+	KMSKeyIDSectionKey       = "kms_key_id"
+	ManageLogGroupSectionKey = "manage_log_group"
+)
+
+// LogGroupSettings are the tags and the KMS key of the log group, and whether the agent manages it at all. The
+// keys not in the config are left out so that the log group is managed without tags or KMS key by default.
+type LogGroupSettings struct {
+	key string
+}
+
+func (l *LogGroupSettings) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if m, ok := input.(map[string]interface{}); ok {
+		if _, ok = m[l.key]; ok {
+			returnKey, returnVal = translator.DefaultCase(l.key, "", input)
+		}
+	}
+	return
+}
+
+func init() {
+	for _, key := range []string{TagsSectionKey, KMSKeyIDSectionKey, ManageLogGroupSectionKey} {
+		RegisterRule(key, []Rule{&LogGroupSettings{key: key}})
+	}
+}
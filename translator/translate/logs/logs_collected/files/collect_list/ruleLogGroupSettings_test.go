@@ -0,0 +1,41 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyLogGroupSettingsRule(t *testing.T) {
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+			"tags": {"team": "platform"},
+			"kms_key_id": "arn:aws:kms:us-east-1:123456789012:key/abcd",
+			"manage_log_group": false
+	}`), &input))
+	testCases := map[string]interface{}{
+		TagsSectionKey:           map[string]interface{}{"team": "platform"},
+		KMSKeyIDSectionKey:       "arn:aws:kms:us-east-1:123456789012:key/abcd",
+		ManageLogGroupSectionKey: false,
+	}
+	for key, want := range testCases {
+		r := &LogGroupSettings{key: key}
+		actualReturnKey, actualReturnVal := r.ApplyRule(input)
+		assert.Equal(t, key, actualReturnKey)
+		assert.Equal(t, want, actualReturnVal)
+	}
+}
+
+func TestApplyLogGroupSettingsRuleNotSet(t *testing.T) {
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"file_path": "/var/log/app.log"}`), &input))
+	r := &LogGroupSettings{key: TagsSectionKey}
+	actualReturnKey, actualReturnVal := r.ApplyRule(input)
+	assert.Equal(t, "", actualReturnKey)
+	assert.Nil(t, actualReturnVal)
+}
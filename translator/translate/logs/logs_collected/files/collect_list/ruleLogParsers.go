@@ -0,0 +1,160 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/parser"
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	ParsersSectionKey           = "parsers"
+	ParsersTypeSectionKey       = "type"
+	ParsersExpressionSectionKey = "expression"
+	ParsersSourceSectionKey     = "source"
+	TimestampFieldSectionKey    = "timestamp_field"
+	EMFSectionKey               = "emf"
+	EMFNamespaceSectionKey      = "namespace"
+	EMFDimensionsSectionKey     = "dimensions"
+	EMFMetricsSectionKey        = "metrics"
+	EMFMetricNameSectionKey     = "name"
+	EMFMetricUnitSectionKey     = "unit"
+	EMFValidationKey            = "emf_validation"
+	EMFDeadLetterStreamKey      = "emf_dead_letter_stream"
+)
+
+type LogParsers struct {
+}
+
+func (lp *LogParsers) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	val, ok := im[ParsersSectionKey]
+	if !ok {
+		return
+	}
+	var res []interface{}
+	for _, p := range val.([]interface{}) {
+		parserMap := map[string]interface{}{}
+		for _, key := range []string{ParsersTypeSectionKey, ParsersExpressionSectionKey, ParsersSourceSectionKey} {
+			if _, parserVal := translator.DefaultCase(key, "", p); parserVal != "" {
+				parserMap[key] = parserVal
+			}
+		}
+		cfg := &parser.Parser{}
+		cfg.Type, _ = parserMap[ParsersTypeSectionKey].(string)
+		cfg.Expression, _ = parserMap[ParsersExpressionSectionKey].(string)
+		if _, err := parser.New(parser.Config{Parsers: []*parser.Parser{cfg}}); err != nil {
+			translator.AddErrorMessages(GetCurPath()+ParsersSectionKey, fmt.Sprintf("Parser %v is invalid: %v", p, err))
+			continue
+		}
+		res = append(res, parserMap)
+	}
+	return ParsersSectionKey, res
+}
+
+type TimestampField struct {
+}
+
+func (tf *TimestampField) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	val, ok := im[TimestampFieldSectionKey]
+	if !ok {
+		return
+	}
+	if _, ok = im[ParsersSectionKey]; !ok {
+		translator.AddErrorMessages(GetCurPath()+TimestampFieldSectionKey, "timestamp_field requires parsers")
+		return
+	}
+	return TimestampFieldSectionKey, val
+}
+
+type EMF struct {
+}
+
+func (e *EMF) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	val, ok := im[EMFSectionKey]
+	if !ok {
+		return
+	}
+	// true validates the log lines already written in the embedded metric format
+	if validate, isBool := val.(bool); isBool {
+		if !validate {
+			return
+		}
+		return EMFValidationKey, true
+	}
+	if _, ok = im[ParsersSectionKey]; !ok {
+		translator.AddErrorMessages(GetCurPath()+EMFSectionKey, "emf requires parsers")
+		return
+	}
+	emfMap, _ := val.(map[string]interface{})
+	cfg := &parser.EMF{}
+	res := map[string]interface{}{}
+	if namespace, ok := emfMap[EMFNamespaceSectionKey].(string); ok {
+		cfg.Namespace = namespace
+		res[EMFNamespaceSectionKey] = namespace
+	}
+	if sets, ok := emfMap[EMFDimensionsSectionKey].([]interface{}); ok {
+		var dimensions []interface{}
+		for _, set := range sets {
+			var dimensionSet []string
+			for _, dimension := range set.([]interface{}) {
+				dimensionSet = append(dimensionSet, dimension.(string))
+			}
+			cfg.Dimensions = append(cfg.Dimensions, dimensionSet)
+			dimensions = append(dimensions, dimensionSet)
+		}
+		res[EMFDimensionsSectionKey] = dimensions
+	}
+	if metrics, ok := emfMap[EMFMetricsSectionKey].([]interface{}); ok {
+		var metricMaps []interface{}
+		for _, metric := range metrics {
+			metricMap := map[string]interface{}{}
+			emfMetric := &parser.EMFMetric{}
+			if _, name := translator.DefaultCase(EMFMetricNameSectionKey, "", metric); name != "" {
+				emfMetric.Name = name.(string)
+				metricMap[EMFMetricNameSectionKey] = name
+			}
+			if _, unit := translator.DefaultCase(EMFMetricUnitSectionKey, "", metric); unit != "" {
+				emfMetric.Unit = unit.(string)
+				metricMap[EMFMetricUnitSectionKey] = unit
+			}
+			cfg.Metrics = append(cfg.Metrics, emfMetric)
+			metricMaps = append(metricMaps, metricMap)
+		}
+		res[EMFMetricsSectionKey] = metricMaps
+	}
+	// the parsers are validated by their own rule
+	if _, err := parser.New(parser.Config{Parsers: []*parser.Parser{{Type: parser.TypeJSON}}, EMF: cfg}); err != nil {
+		translator.AddErrorMessages(GetCurPath()+EMFSectionKey, fmt.Sprintf("EMF %v is invalid: %v", val, err))
+		return
+	}
+	return EMFSectionKey, res
+}
+
+type EMFDeadLetterStream struct {
+}
+
+func (d *EMFDeadLetterStream) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	val, ok := im[EMFDeadLetterStreamKey]
+	if !ok {
+		return
+	}
+	if validate, _ := im[EMFSectionKey].(bool); !validate {
+		translator.AddErrorMessages(GetCurPath()+EMFDeadLetterStreamKey, "emf_dead_letter_stream requires emf to be true")
+		return
+	}
+	return EMFDeadLetterStreamKey, val
+}
+
+func init() {
+	RegisterRule(ParsersSectionKey, []Rule{new(LogParsers)})
+	RegisterRule(TimestampFieldSectionKey, []Rule{new(TimestampField)})
+	RegisterRule(EMFSectionKey, []Rule{new(EMF)})
+	RegisterRule(EMFDeadLetterStreamKey, []Rule{new(EMFDeadLetterStream)})
+}
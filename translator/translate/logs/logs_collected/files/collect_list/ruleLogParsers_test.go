@@ -0,0 +1,104 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+func TestApplyLogParsersRules(t *testing.T) {
+	translator.ResetMessages()
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"parsers": [
+			{"type": "regex", "expression": "^(?P<time>\\S+) (?P<payload>.*)$"},
+			{"type": "json", "source": "payload"}
+		],
+		"timestamp_field": "time",
+		"emf": {
+			"namespace": "App",
+			"dimensions": [["method"]],
+			"metrics": [{"name": "latency", "unit": "Milliseconds"}, {"name": "count"}]
+		}
+	}`), &input))
+
+	key, val := new(LogParsers).ApplyRule(input)
+	assert.Equal(t, "parsers", key)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "regex", "expression": "^(?P<time>\\S+) (?P<payload>.*)$"},
+		map[string]interface{}{"type": "json", "source": "payload"},
+	}, val)
+	key, val = new(TimestampField).ApplyRule(input)
+	assert.Equal(t, "timestamp_field", key)
+	assert.Equal(t, "time", val)
+	key, val = new(EMF).ApplyRule(input)
+	assert.Equal(t, "emf", key)
+	assert.Equal(t, map[string]interface{}{
+		"namespace":  "App",
+		"dimensions": []interface{}{[]string{"method"}},
+		"metrics": []interface{}{
+			map[string]interface{}{"name": "latency", "unit": "Milliseconds"},
+			map[string]interface{}{"name": "count"},
+		},
+	}, val)
+	assert.Len(t, translator.ErrorMessages, 0)
+}
+
+func TestApplyLogParsersRulesInvalid(t *testing.T) {
+	translator.ResetMessages()
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"parsers": [
+			{"type": "regex", "expression": "^(\\S+)$"},
+			{"type": "json"}
+		],
+		"emf": {
+			"namespace": "App",
+			"metrics": []
+		}
+	}`), &input))
+
+	_, val := new(LogParsers).ApplyRule(input)
+	assert.Equal(t, []interface{}{map[string]interface{}{"type": "json"}}, val)
+	key, _ := new(EMF).ApplyRule(input)
+	assert.Equal(t, "", key)
+	assert.Len(t, translator.ErrorMessages, 2)
+}
+
+func TestApplyLogParsersRulesWithoutParsers(t *testing.T) {
+	translator.ResetMessages()
+	input := map[string]interface{}{"timestamp_field": "time", "emf": map[string]interface{}{}}
+	key, _ := new(LogParsers).ApplyRule(input)
+	assert.Equal(t, "", key)
+	key, _ = new(TimestampField).ApplyRule(input)
+	assert.Equal(t, "", key)
+	key, _ = new(EMF).ApplyRule(input)
+	assert.Equal(t, "", key)
+	assert.Len(t, translator.ErrorMessages, 2)
+}
+
+func TestApplyEMFValidationRules(t *testing.T) {
+	translator.ResetMessages()
+	input := map[string]interface{}{"emf": true, "emf_dead_letter_stream": "invalid"}
+	key, val := new(EMF).ApplyRule(input)
+	assert.Equal(t, "emf_validation", key)
+	assert.Equal(t, true, val)
+	key, val = new(EMFDeadLetterStream).ApplyRule(input)
+	assert.Equal(t, "emf_dead_letter_stream", key)
+	assert.Equal(t, "invalid", val)
+	assert.Len(t, translator.ErrorMessages, 0)
+
+	input = map[string]interface{}{"emf": false, "emf_dead_letter_stream": "invalid"}
+	key, _ = new(EMF).ApplyRule(input)
+	assert.Equal(t, "", key)
+	key, _ = new(EMFDeadLetterStream).ApplyRule(input)
+	assert.Equal(t, "", key)
+	assert.Len(t, translator.ErrorMessages, 1)
+}
@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	MultilineSectionKey         = "multiline"
+	MultilinePatternSectionKey  = "pattern"
+	MultilineNegateSectionKey   = "negate"
+	MultilineMatchSectionKey    = "match"
+	MultilineMaxLinesSectionKey = "max_lines"
+	MultilineTimeoutSectionKey  = "timeout"
+)
+
+// Multiline joins the lines of the log events with a pattern, negate and
+// match, like filebeat. The timeout is in seconds.
+type Multiline struct {
+}
+
+func (m *Multiline) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	val, ok := im[MultilineSectionKey]
+	if !ok {
+		return
+	}
+	if _, ok = im["multi_line_start_pattern"]; ok {
+		translator.AddErrorMessages(GetCurPath()+MultilineSectionKey, "multiline cannot be used together with multi_line_start_pattern")
+		return
+	}
+	multilineMap, _ := val.(map[string]interface{})
+	res := map[string]interface{}{}
+	pattern, _ := multilineMap[MultilinePatternSectionKey].(string)
+	if pattern == "{timestamp_format}" {
+		pattern = "{timestamp_regex}"
+	} else if _, err := regexp.Compile(pattern); err != nil {
+		translator.AddErrorMessages(GetCurPath()+MultilineSectionKey, fmt.Sprintf("Multiline pattern %v is invalid: %v", pattern, err))
+		return
+	}
+	res[MultilinePatternSectionKey] = pattern
+	if negate, ok := multilineMap[MultilineNegateSectionKey].(bool); ok {
+		res[MultilineNegateSectionKey] = negate
+	}
+	if match, ok := multilineMap[MultilineMatchSectionKey].(string); ok {
+		res[MultilineMatchSectionKey] = match
+	}
+	if maxLines, ok := multilineMap[MultilineMaxLinesSectionKey].(float64); ok {
+		res[MultilineMaxLinesSectionKey] = int(maxLines)
+	}
+	if timeout, ok := multilineMap[MultilineTimeoutSectionKey].(float64); ok {
+		res[MultilineTimeoutSectionKey] = fmt.Sprintf("%ds", int(timeout))
+	}
+	return MultilineSectionKey, res
+}
+
+func init() {
+	RegisterRule(MultilineSectionKey, []Rule{new(Multiline)})
+}
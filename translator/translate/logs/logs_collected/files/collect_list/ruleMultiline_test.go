@@ -0,0 +1,71 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+func TestApplyMultilineRule(t *testing.T) {
+	testCases := map[string]struct {
+		input     string
+		wantKey   string
+		wantVal   interface{}
+		wantError bool
+	}{
+		"WithAllFields": {
+			input:   `{"multiline": {"pattern": "^\\s+at ", "negate": false, "match": "after", "max_lines": 200, "timeout": 10}}`,
+			wantKey: "multiline",
+			wantVal: map[string]interface{}{
+				"pattern":   "^\\s+at ",
+				"negate":    false,
+				"match":     "after",
+				"max_lines": 200,
+				"timeout":   "10s",
+			},
+		},
+		"WithTimestampFormat": {
+			input:   `{"multiline": {"pattern": "{timestamp_format}", "negate": true}}`,
+			wantKey: "multiline",
+			wantVal: map[string]interface{}{
+				"pattern": "{timestamp_regex}",
+				"negate":  true,
+			},
+		},
+		"WithoutMultiline": {
+			input: `{"multi_line_start_pattern": "^\\S"}`,
+		},
+		"WithMultiLineStartPattern": {
+			input:     `{"multi_line_start_pattern": "^\\S", "multiline": {"pattern": "^\\s"}}`,
+			wantError: true,
+		},
+		"WithInvalidPattern": {
+			input:     `{"multiline": {"pattern": "(\\d{2}+)"}}`,
+			wantError: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			translator.ResetMessages()
+			var input interface{}
+			require.NoError(t, json.Unmarshal([]byte(testCase.input), &input))
+			key, val := new(Multiline).ApplyRule(input)
+			assert.Equal(t, testCase.wantKey, key)
+			if testCase.wantVal != nil {
+				assert.Equal(t, testCase.wantVal, val)
+			}
+			if testCase.wantError {
+				assert.Len(t, translator.ErrorMessages, 1)
+			} else {
+				assert.Len(t, translator.ErrorMessages, 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+const (
+	OversizeEventsSectionKey         = "oversize_events"
+	OversizeEventsMaxSizeSectionKey  = "max_size"
+	OversizeEventsStrategySectionKey = "strategy"
+	OversizeEventsMarkerSectionKey   = "marker"
+)
+
+// OversizeEvents truncates the log events of the entry above the max size,
+// keeping their start, their end or both, or splits them into several log
+// events.
+type OversizeEvents struct {
+}
+
+func (o *OversizeEvents) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	val, ok := im[OversizeEventsSectionKey]
+	if !ok {
+		return
+	}
+	oversizeMap, _ := val.(map[string]interface{})
+	res := map[string]interface{}{}
+	if maxSize, ok := oversizeMap[OversizeEventsMaxSizeSectionKey].(float64); ok {
+		res[OversizeEventsMaxSizeSectionKey] = int(maxSize)
+	}
+	if strategy, ok := oversizeMap[OversizeEventsStrategySectionKey].(string); ok {
+		res[OversizeEventsStrategySectionKey] = strategy
+	}
+	if marker, ok := oversizeMap[OversizeEventsMarkerSectionKey].(string); ok {
+		res[OversizeEventsMarkerSectionKey] = marker
+	}
+	return OversizeEventsSectionKey, res
+}
+
+func init() {
+	RegisterRule(OversizeEventsSectionKey, []Rule{new(OversizeEvents)})
+}
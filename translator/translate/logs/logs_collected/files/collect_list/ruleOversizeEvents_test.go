@@ -0,0 +1,47 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyOversizeEventsRule(t *testing.T) {
+	testCases := map[string]struct {
+		input   string
+		wantKey string
+		wantVal interface{}
+	}{
+		"WithAllFields": {
+			input:   `{"oversize_events": {"max_size": 65536, "strategy": "truncate_middle", "marker": "[...]"}}`,
+			wantKey: "oversize_events",
+			wantVal: map[string]interface{}{
+				"max_size": 65536,
+				"strategy": "truncate_middle",
+				"marker":   "[...]",
+			},
+		},
+		"WithStrategy": {
+			input:   `{"oversize_events": {"strategy": "split"}}`,
+			wantKey: "oversize_events",
+			wantVal: map[string]interface{}{"strategy": "split"},
+		},
+		"WithoutOversizeEvents": {
+			input: `{"file_path": "/var/log/app.log"}`,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var input interface{}
+			require.NoError(t, json.Unmarshal([]byte(testCase.input), &input))
+			key, val := new(OversizeEvents).ApplyRule(input)
+			assert.Equal(t, testCase.wantKey, key)
+			assert.Equal(t, testCase.wantVal, val)
+		})
+	}
+}
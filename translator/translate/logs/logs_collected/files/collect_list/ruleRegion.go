@@ -0,0 +1,28 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const RegionSectionKey = "region"
+
+// Region is the region of the log group. The region of the output is used if not set.
+type Region struct {
+}
+
+func (r *Region) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if m, ok := input.(map[string]interface{}); ok {
+		if _, ok = m[RegionSectionKey]; ok {
+			returnKey, returnVal = translator.DefaultCase(RegionSectionKey, "", input)
+		}
+	}
+	return
+}
+
+func init() {
+	r := new(Region)
+	RegisterRule(RegionSectionKey, []Rule{r})
+}
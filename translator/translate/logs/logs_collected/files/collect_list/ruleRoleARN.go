@@ -0,0 +1,29 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const RoleARNSectionKey = "role_arn"
+
+// RoleARN is the role assumed to publish to a log group in another account. The role of the
+// output is used if not set.
+type RoleARN struct {
+}
+
+func (r *RoleARN) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if m, ok := input.(map[string]interface{}); ok {
+		if _, ok = m[RoleARNSectionKey]; ok {
+			returnKey, returnVal = translator.DefaultCase(RoleARNSectionKey, "", input)
+		}
+	}
+	return
+}
+
+func init() {
+	r := new(RoleARN)
+	RegisterRule(RoleARNSectionKey, []Rule{r})
+}
@@ -0,0 +1,55 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+const (
+	SamplingSectionKey                 = "sampling"
+	SamplingSampleRateSectionKey       = "sample_rate"
+	SamplingRateLimitSectionKey        = "rate_limit_per_second"
+	SamplingBurstSectionKey            = "burst"
+	SamplingLevelFieldSectionKey       = "level_field"
+	SamplingLevelSampleRatesSectionKey = "level_sample_rates"
+)
+
+// Sampling samples and rate limits the log events of all the files of the
+// entry. The level sample rates replace the sample rate for the events with
+// the parsed level field.
+type Sampling struct {
+}
+
+func (s *Sampling) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	val, ok := im[SamplingSectionKey]
+	if !ok {
+		return
+	}
+	samplingMap, _ := val.(map[string]interface{})
+	res := map[string]interface{}{}
+	if sampleRate, ok := samplingMap[SamplingSampleRateSectionKey].(float64); ok {
+		res[SamplingSampleRateSectionKey] = sampleRate
+	}
+	if rateLimit, ok := samplingMap[SamplingRateLimitSectionKey].(float64); ok {
+		res[SamplingRateLimitSectionKey] = rateLimit
+	}
+	if burst, ok := samplingMap[SamplingBurstSectionKey].(float64); ok {
+		res[SamplingBurstSectionKey] = int(burst)
+	}
+	if levelField, ok := samplingMap[SamplingLevelFieldSectionKey].(string); ok {
+		res[SamplingLevelFieldSectionKey] = levelField
+	}
+	if levelSampleRates, ok := samplingMap[SamplingLevelSampleRatesSectionKey].(map[string]interface{}); ok {
+		rates := make(map[string]interface{}, len(levelSampleRates))
+		for level, sampleRate := range levelSampleRates {
+			if rate, ok := sampleRate.(float64); ok {
+				rates[level] = rate
+			}
+		}
+		res[SamplingLevelSampleRatesSectionKey] = rates
+	}
+	return SamplingSectionKey, res
+}
+
+func init() {
+	RegisterRule(SamplingSectionKey, []Rule{new(Sampling)})
+}
@@ -0,0 +1,49 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplySamplingRule(t *testing.T) {
+	testCases := map[string]struct {
+		input   string
+		wantKey string
+		wantVal interface{}
+	}{
+		"WithAllFields": {
+			input:   `{"sampling": {"sample_rate": 0.5, "rate_limit_per_second": 100, "burst": 200, "level_field": "severity", "level_sample_rates": {"debug": 0.01}}}`,
+			wantKey: "sampling",
+			wantVal: map[string]interface{}{
+				"sample_rate":           0.5,
+				"rate_limit_per_second": 100.0,
+				"burst":                 200,
+				"level_field":           "severity",
+				"level_sample_rates":    map[string]interface{}{"debug": 0.01},
+			},
+		},
+		"WithRateLimit": {
+			input:   `{"sampling": {"rate_limit_per_second": 10}}`,
+			wantKey: "sampling",
+			wantVal: map[string]interface{}{"rate_limit_per_second": 10.0},
+		},
+		"WithoutSampling": {
+			input: `{"file_path": "/var/log/app.log"}`,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var input interface{}
+			require.NoError(t, json.Unmarshal([]byte(testCase.input), &input))
+			key, val := new(Sampling).ApplyRule(input)
+			assert.Equal(t, testCase.wantKey, key)
+			assert.Equal(t, testCase.wantVal, val)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/parser"
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	StructuredJSONSectionKey           = "structured_json"
+	StructuredJSONFieldsSectionKey     = "fields"
+	StructuredJSONFieldIndexSectionKey = "field_index"
+)
+
+type StructuredJSON struct {
+}
+
+func (sj *StructuredJSON) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	val, ok := im[StructuredJSONSectionKey]
+	if !ok {
+		return
+	}
+	structuredMap, _ := val.(map[string]interface{})
+	cfg := &parser.Structured{}
+	res := map[string]interface{}{}
+	if fields, ok := structuredMap[StructuredJSONFieldsSectionKey].([]interface{}); ok {
+		for _, field := range fields {
+			name, _ := field.(string)
+			cfg.Fields = append(cfg.Fields, name)
+		}
+		res[StructuredJSONFieldsSectionKey] = cfg.Fields
+	}
+	if fieldIndex, ok := structuredMap[StructuredJSONFieldIndexSectionKey].(bool); ok {
+		cfg.FieldIndex = fieldIndex
+		res[StructuredJSONFieldIndexSectionKey] = fieldIndex
+	}
+	if _, hasEMF := im[EMFSectionKey]; hasEMF {
+		translator.AddErrorMessages(GetCurPath()+StructuredJSONSectionKey, "structured_json cannot be used with emf")
+		return
+	}
+	// the parsers are validated by their own rule
+	if _, err := parser.New(parser.Config{Structured: cfg}); err != nil {
+		translator.AddErrorMessages(GetCurPath()+StructuredJSONSectionKey, fmt.Sprintf("structured_json %v is invalid: %v", val, err))
+		return
+	}
+	return StructuredJSONSectionKey, res
+}
+
+func init() {
+	RegisterRule(StructuredJSONSectionKey, []Rule{new(StructuredJSON)})
+}
@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+func TestApplyStructuredJSONRule(t *testing.T) {
+	testCases := map[string]struct {
+		input      string
+		wantKey    string
+		wantVal    interface{}
+		wantErrors int
+	}{
+		"WithFieldIndex": {
+			input:   `{"structured_json": {"fields": ["level", "requestId"], "field_index": true}}`,
+			wantKey: "structured_json",
+			wantVal: map[string]interface{}{"fields": []string{"level", "requestId"}, "field_index": true},
+		},
+		"WithoutFieldIndex": {
+			input:   `{"structured_json": {"fields": ["level"]}}`,
+			wantKey: "structured_json",
+			wantVal: map[string]interface{}{"fields": []string{"level"}},
+		},
+		"WithEMF": {
+			input:      `{"parsers": [{"type": "json"}], "emf": {"namespace": "App"}, "structured_json": {"fields": ["level"]}}`,
+			wantErrors: 1,
+		},
+		"WithTooManyIndexedFields": {
+			input:      `{"structured_json": {"fields": ["f1","f2","f3","f4","f5","f6","f7","f8","f9","f10","f11","f12","f13","f14","f15","f16","f17","f18","f19","f20","f21"], "field_index": true}}`,
+			wantErrors: 1,
+		},
+		"Without": {
+			input: `{"parsers": [{"type": "json"}]}`,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			translator.ResetMessages()
+			var input interface{}
+			require.NoError(t, json.Unmarshal([]byte(testCase.input), &input))
+			key, val := new(StructuredJSON).ApplyRule(input)
+			assert.Equal(t, testCase.wantKey, key)
+			assert.Equal(t, testCase.wantVal, val)
+			assert.Len(t, translator.ErrorMessages, testCase.wantErrors)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+)
+
+const (
+	MemoryLimitPercentageKey = "memory_limit_percentage"
+	MemoryCheckIntervalKey   = "memory_check_interval"
+)
+
+// MemoryLimitPercentage pauses the tailers at the soft limit of the memory
+// limiter, so that the files are read again once the pipelines are below it.
+type MemoryLimitPercentage struct {
+}
+
+func (m *MemoryLimitPercentage) ApplyRule(_ interface{}) (returnKey string, returnVal interface{}) {
+	if limiter := agent.Global_Config.MemoryLimiter; limiter != nil {
+		return MemoryLimitPercentageKey, limiter.SoftLimitPercentage()
+	}
+	return
+}
+
+type MemoryCheckInterval struct {
+}
+
+func (m *MemoryCheckInterval) ApplyRule(_ interface{}) (returnKey string, returnVal interface{}) {
+	if limiter := agent.Global_Config.MemoryLimiter; limiter != nil {
+		return MemoryCheckIntervalKey, fmt.Sprintf("%ds", int(limiter.CheckInterval.Seconds()))
+	}
+	return
+}
+
+func init() {
+	RegisterRule(MemoryLimitPercentageKey, new(MemoryLimitPercentage))
+	RegisterRule(MemoryCheckIntervalKey, new(MemoryCheckInterval))
+}
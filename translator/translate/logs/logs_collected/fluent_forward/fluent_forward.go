@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent_forward
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/mergeJsonRule"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/mergeJsonUtil"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected"
+)
+
+var ChildRule = map[string]translator.Rule{}
+
+type FluentForward struct {
+}
+
+const (
+	SectionKey       = "fluent_forward"
+	SectionMappedKey = "fluent_forward"
+)
+
+func GetCurPath() string {
+	return parent.GetCurPath() + SectionKey + "/"
+}
+
+func RegisterRule(ruleName string, r translator.Rule) {
+	ChildRule[ruleName] = r
+}
+
+func (f *FluentForward) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	forwardConfig := map[string]interface{}{
+		"destination": "cloudwatchlogs",
+	}
+
+	if _, ok := im[SectionKey]; ok {
+		for _, rule := range ChildRule {
+			key, val := rule.ApplyRule(im[SectionKey])
+			if key != "" {
+				forwardConfig[key] = val
+			}
+		}
+
+		return "inputs", map[string]interface{}{
+			SectionMappedKey: []interface{}{forwardConfig},
+		}
+	} else {
+		return "", ""
+	}
+}
+
+var MergeRuleMap = map[string]mergeJsonRule.MergeRule{}
+
+func (f *FluentForward) Merge(source map[string]interface{}, result map[string]interface{}) {
+	mergeJsonUtil.MergeMap(source, result, SectionKey, MergeRuleMap, GetCurPath())
+}
+
+func init() {
+	obj := new(FluentForward)
+	parent.RegisterLinuxRule(SectionKey, obj)
+	parent.RegisterDarwinRule(SectionKey, obj)
+	parent.RegisterWindowsRule(SectionKey, obj)
+	parent.MergeRuleMap[SectionKey] = obj
+}
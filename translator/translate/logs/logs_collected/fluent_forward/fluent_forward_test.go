@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent_forward
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs"
+)
+
+func TestApplyRule(t *testing.T) {
+	translator.ResetMessages()
+	f := new(FluentForward)
+	var rawJsonString = `
+{
+	"fluent_forward": {
+		"service_address": "unix:///var/run/fluent.sock",
+		"shared_key": "secret",
+		"log_group_name": "/fluent/{tag[0]}",
+		"log_stream_name": "{tag}",
+		"retention_in_days": 7,
+		"message_key": "log"
+	}
+}
+`
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(rawJsonString), &input))
+
+	var expected = map[string]interface{}{
+		"fluent_forward": []interface{}{
+			map[string]interface{}{
+				"destination":       "cloudwatchlogs",
+				"service_address":   "unix:///var/run/fluent.sock",
+				"shared_key":        "secret",
+				"log_group_class":   "",
+				"log_group_name":    "/fluent/{tag[0]}",
+				"log_stream_name":   "{tag}",
+				"message_key":       "log",
+				"retention_in_days": 7,
+			},
+		},
+	}
+	key, actual := f.ApplyRule(input)
+	assert.Equal(t, "inputs", key)
+	assert.Equal(t, expected, actual)
+	assert.Empty(t, translator.ErrorMessages)
+}
+
+func TestApplyRuleDefault(t *testing.T) {
+	logs.GlobalLogConfig.MetadataInfo = map[string]string{"{instance_id}": "i-123"}
+	defer func() { logs.GlobalLogConfig.MetadataInfo = nil }()
+	f := new(FluentForward)
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"fluent_forward": {}}`), &input))
+
+	var expected = map[string]interface{}{
+		"fluent_forward": []interface{}{
+			map[string]interface{}{
+				"destination":       "cloudwatchlogs",
+				"service_address":   "tcp://127.0.0.1:24224",
+				"log_group_class":   "",
+				"log_group_name":    "{tag}",
+				"log_stream_name":   "i-123",
+				"retention_in_days": -1,
+			},
+		},
+	}
+	key, actual := f.ApplyRule(input)
+	assert.Equal(t, "inputs", key)
+	assert.Equal(t, expected, actual)
+}
+
+func TestApplyRuleNoFluentForward(t *testing.T) {
+	f := new(FluentForward)
+	key, _ := f.ApplyRule(map[string]interface{}{})
+	assert.Equal(t, "", key)
+}
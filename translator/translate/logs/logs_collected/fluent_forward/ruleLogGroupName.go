@@ -0,0 +1,31 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent_forward
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/util"
+)
+
+const (
+	LogGroupNameSectionKey = "log_group_name"
+	// The {tag} references are resolved by the input plugin.
+	defaultLogGroupName = "{tag}"
+)
+
+type LogGroupName struct {
+}
+
+func (l *LogGroupName) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(LogGroupNameSectionKey, defaultLogGroupName, input)
+	returnKey = LogGroupNameSectionKey
+	returnVal = util.ResolvePlaceholder(returnVal.(string), logs.GlobalLogConfig.MetadataInfo)
+	return
+}
+
+func init() {
+	l := new(LogGroupName)
+	RegisterRule(LogGroupNameSectionKey, l)
+}
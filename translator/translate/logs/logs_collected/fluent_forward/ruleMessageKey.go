@@ -0,0 +1,25 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent_forward
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const MessageKeySectionKey = "message_key"
+
+type MessageKey struct {
+}
+
+func (m *MessageKey) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	key, val := translator.DefaultCase(MessageKeySectionKey, "", input)
+	if val == "" {
+		return
+	}
+	return key, val
+}
+
+func init() {
+	RegisterRule(MessageKeySectionKey, new(MessageKey))
+}
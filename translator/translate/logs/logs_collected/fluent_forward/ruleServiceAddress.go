@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent_forward
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	ServiceAddressSectionKey = "service_address"
+	defaultServiceAddress    = "tcp://127.0.0.1:24224"
+)
+
+type ServiceAddress struct {
+}
+
+func (s *ServiceAddress) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return translator.DefaultCase(ServiceAddressSectionKey, defaultServiceAddress, input)
+}
+
+func init() {
+	RegisterRule(ServiceAddressSectionKey, new(ServiceAddress))
+}
@@ -0,0 +1,25 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent_forward
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const SharedKeySectionKey = "shared_key"
+
+type SharedKey struct {
+}
+
+func (s *SharedKey) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	key, val := translator.DefaultCase(SharedKeySectionKey, "", input)
+	if val == "" {
+		return
+	}
+	return key, val
+}
+
+func init() {
+	RegisterRule(SharedKeySectionKey, new(SharedKey))
+}
@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectlist
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+func TestApplyRule(t *testing.T) {
+	translator.ResetMessages()
+	c := new(CollectList)
+	var rawJsonString = `
+{
+	"collect_list": [
+		{
+			"units": ["nginx.service", "sshd.service"],
+			"priority": "warning",
+			"log_group_name": "/ec2/journald"
+		},
+		{
+			"event_format": "json",
+			"log_group_name": "journald",
+			"log_stream_name": "host_{unit}",
+			"log_group_class": "INFREQUENT_ACCESS",
+			"retention_in_days": 7
+		}
+	]
+}
+`
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(rawJsonString), &input))
+
+	var expected = []interface{}{
+		map[string]interface{}{
+			"units":             []interface{}{"nginx.service", "sshd.service"},
+			"priority":          "warning",
+			"event_format":      "text",
+			"log_group_name":    "/ec2/journald",
+			"log_stream_name":   "{unit}",
+			"log_group_class":   "",
+			"retention_in_days": -1,
+		},
+		map[string]interface{}{
+			"event_format":      "json",
+			"log_group_name":    "journald",
+			"log_stream_name":   "host_{unit}",
+			"log_group_class":   util.InfrequentAccessLogGroupClass,
+			"retention_in_days": 7,
+		},
+	}
+	key, actual := c.ApplyRule(input)
+	assert.Equal(t, JournalConfigTomlKey, key)
+	assert.Equal(t, expected, actual)
+	assert.Empty(t, translator.ErrorMessages)
+}
+
+func TestApplyRuleInvalidEventFormat(t *testing.T) {
+	translator.ResetMessages()
+	t.Cleanup(translator.ResetMessages)
+	c := new(CollectList)
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"collect_list": [{"log_group_name": "journald", "event_format": "xml"}]}`), &input))
+	c.ApplyRule(input)
+	assert.Len(t, translator.ErrorMessages, 1)
+}
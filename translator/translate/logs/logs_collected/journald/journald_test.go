@@ -0,0 +1,41 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package journald
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
+)
+
+func TestApplyRule(t *testing.T) {
+	translator.ResetMessages()
+	j := new(Journald)
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"journald": {"collect_list": []}}`), &input))
+
+	var expected = map[string]interface{}{
+		"journald": []interface{}{
+			map[string]interface{}{
+				"destination":       "cloudwatchlogs",
+				"file_state_folder": util.GetFileStateFolder(),
+			},
+		},
+	}
+	key, actual := j.ApplyRule(input)
+	assert.Equal(t, "inputs", key)
+	assert.Equal(t, expected, actual)
+	assert.Empty(t, translator.ErrorMessages)
+}
+
+func TestApplyRuleNoJournald(t *testing.T) {
+	j := new(Journald)
+	key, _ := j.ApplyRule(map[string]interface{}{})
+	assert.Equal(t, "", key)
+}
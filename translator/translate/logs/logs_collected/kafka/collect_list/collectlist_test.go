@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectlist
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/util"
+)
+
+func TestApplyRule(t *testing.T) {
+	c := new(CollectList)
+	var rawJsonString = `
+{
+	"collect_list": [
+		{
+			"topics": ["app", "web"]
+		},
+		{
+			"topics": ["audit"],
+			"log_group_name": "/msk/{topic}",
+			"log_stream_name": "partition-{partition}",
+			"log_group_class": "INFREQUENT_ACCESS",
+			"retention_in_days": 7
+		}
+	]
+}
+`
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(rawJsonString), &input))
+
+	var expected = []interface{}{
+		map[string]interface{}{
+			"topics":            []interface{}{"app", "web"},
+			"log_group_name":    "{topic}",
+			"log_stream_name":   "{topic}_{partition}",
+			"log_group_class":   "",
+			"retention_in_days": -1,
+		},
+		map[string]interface{}{
+			"topics":            []interface{}{"audit"},
+			"log_group_name":    "/msk/{topic}",
+			"log_stream_name":   "partition-{partition}",
+			"log_group_class":   util.InfrequentAccessLogGroupClass,
+			"retention_in_days": 7,
+		},
+	}
+	key, actual := c.ApplyRule(input)
+	assert.Equal(t, TopicConfigTomlKey, key)
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectlist
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/util"
+)
+
+const (
+	LogStreamNameSectionKey = "log_stream_name"
+	defaultLogStreamName    = "{topic}_{partition}"
+)
+
+type LogStreamName struct {
+}
+
+func (l *LogStreamName) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(LogStreamNameSectionKey, defaultLogStreamName, input)
+	returnKey = LogStreamNameSectionKey
+	returnVal = util.ResolvePlaceholder(returnVal.(string), logs.GlobalLogConfig.MetadataInfo)
+	return
+}
+
+func init() {
+	l := new(LogStreamName)
+	RegisterRule(LogStreamNameSectionKey, l)
+}
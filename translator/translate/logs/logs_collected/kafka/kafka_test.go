@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+)
+
+func TestApplyRule(t *testing.T) {
+	translator.ResetMessages()
+	agent.Global_Config = agent.Agent{Region: "us-east-1", Role_arn: "role_arn"}
+	t.Cleanup(func() {
+		agent.Global_Config = agent.Agent{}
+	})
+	k := new(Kafka)
+	var rawJsonString = `
+{
+	"kafka": {
+		"brokers": ["localhost:9092"],
+		"tls": true,
+		"collect_list": [
+			{
+				"topics": ["app"]
+			}
+		]
+	}
+}
+`
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(rawJsonString), &input))
+
+	var expected = map[string]interface{}{
+		"kafka_logs": []interface{}{
+			map[string]interface{}{
+				"brokers":        []interface{}{"localhost:9092"},
+				"consumer_group": "amazon-cloudwatch-agent",
+				"destination":    "cloudwatchlogs",
+				"initial_offset": "latest",
+				"region":         "us-east-1",
+				"role_arn":       "role_arn",
+				"tls":            true,
+			},
+		},
+	}
+	key, actual := k.ApplyRule(input)
+	assert.Equal(t, "inputs", key)
+	assert.Equal(t, expected, actual)
+	assert.Empty(t, translator.ErrorMessages)
+}
+
+func TestApplyRuleMissingBrokers(t *testing.T) {
+	translator.ResetMessages()
+	t.Cleanup(translator.ResetMessages)
+	k := new(Kafka)
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"kafka": {"brokers": []}}`), &input))
+	k.ApplyRule(input)
+	assert.Len(t, translator.ErrorMessages, 1)
+}
+
+func TestApplyRuleNoKafka(t *testing.T) {
+	k := new(Kafka)
+	key, _ := k.ApplyRule(map[string]interface{}{})
+	assert.Equal(t, "", key)
+}
@@ -0,0 +1,25 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const BrokersSectionKey = "brokers"
+
+type Brokers struct {
+}
+
+func (b *Brokers) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(BrokersSectionKey, []interface{}{}, input)
+	if brokers, ok := returnVal.([]interface{}); !ok || len(brokers) == 0 {
+		translator.AddErrorMessages(GetCurPath()+BrokersSectionKey, "At least one broker is required.")
+	}
+	return
+}
+
+func init() {
+	RegisterRule(BrokersSectionKey, new(Brokers))
+}
@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	ConsumerGroupSectionKey = "consumer_group"
+	defaultConsumerGroup    = "amazon-cloudwatch-agent"
+)
+
+type ConsumerGroup struct {
+}
+
+func (c *ConsumerGroup) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return translator.DefaultCase(ConsumerGroupSectionKey, defaultConsumerGroup, input)
+}
+
+func init() {
+	RegisterRule(ConsumerGroupSectionKey, new(ConsumerGroup))
+}
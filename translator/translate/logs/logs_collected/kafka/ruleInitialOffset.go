@@ -0,0 +1,22 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const InitialOffsetSectionKey = "initial_offset"
+
+type InitialOffset struct {
+}
+
+// ApplyRule only applies when the consumer group has no committed offset for a partition.
+func (i *InitialOffset) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return translator.DefaultCase(InitialOffsetSectionKey, "latest", input)
+}
+
+func init() {
+	RegisterRule(InitialOffsetSectionKey, new(InitialOffset))
+}
@@ -0,0 +1,21 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const TLSSectionKey = "tls"
+
+type TLS struct {
+}
+
+func (t *TLS) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return translator.DefaultCase(TLSSectionKey, false, input)
+}
+
+func init() {
+	RegisterRule(TLSSectionKey, new(TLS))
+}
@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesis
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
+)
+
+func TestApplyRule(t *testing.T) {
+	translator.ResetMessages()
+	agent.Global_Config = agent.Agent{Region: "us-east-1", Role_arn: "role_arn"}
+	t.Cleanup(func() {
+		agent.Global_Config = agent.Agent{}
+	})
+	k := new(Kinesis)
+	var rawJsonString = `
+{
+	"kinesis": {
+		"enhanced_fan_out": true,
+		"collect_list": [
+			{
+				"stream_names": ["app"]
+			}
+		]
+	}
+}
+`
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(rawJsonString), &input))
+
+	var expected = map[string]interface{}{
+		"kinesis_logs": []interface{}{
+			map[string]interface{}{
+				"application_name":  "amazon-cloudwatch-agent",
+				"checkpoint_store":  "dynamodb",
+				"destination":       "cloudwatchlogs",
+				"enhanced_fan_out":  true,
+				"file_state_folder": util.GetFileStateFolder(),
+				"initial_position":  "latest",
+				"region":            "us-east-1",
+				"role_arn":          "role_arn",
+			},
+		},
+	}
+	key, actual := k.ApplyRule(input)
+	assert.Equal(t, "inputs", key)
+	assert.Equal(t, expected, actual)
+	assert.Empty(t, translator.ErrorMessages)
+}
+
+func TestApplyRuleWithLeaseTable(t *testing.T) {
+	k := new(Kinesis)
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"kinesis": {"application_name": "app", "initial_position": "trim_horizon", "lease_table_name": "app-leases"}}`), &input))
+	_, actual := k.ApplyRule(input)
+	config := actual.(map[string]interface{})["kinesis_logs"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "app", config["application_name"])
+	assert.Equal(t, "trim_horizon", config["initial_position"])
+	assert.Equal(t, "app-leases", config["lease_table_name"])
+}
+
+func TestApplyRuleNoKinesis(t *testing.T) {
+	k := new(Kinesis)
+	key, _ := k.ApplyRule(map[string]interface{}{})
+	assert.Equal(t, "", key)
+}
@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesis
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	ApplicationNameSectionKey = "application_name"
+	defaultApplicationName    = "amazon-cloudwatch-agent"
+)
+
+type ApplicationName struct {
+}
+
+func (a *ApplicationName) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return translator.DefaultCase(ApplicationNameSectionKey, defaultApplicationName, input)
+}
+
+func init() {
+	RegisterRule(ApplicationNameSectionKey, new(ApplicationName))
+}
@@ -0,0 +1,21 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesis
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const CheckpointStoreSectionKey = "checkpoint_store"
+
+type CheckpointStore struct {
+}
+
+func (c *CheckpointStore) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return translator.DefaultCase(CheckpointStoreSectionKey, "dynamodb", input)
+}
+
+func init() {
+	RegisterRule(CheckpointStoreSectionKey, new(CheckpointStore))
+}
@@ -0,0 +1,21 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesis
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const EnhancedFanOutSectionKey = "enhanced_fan_out"
+
+type EnhancedFanOut struct {
+}
+
+func (e *EnhancedFanOut) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return translator.DefaultCase(EnhancedFanOutSectionKey, false, input)
+}
+
+func init() {
+	RegisterRule(EnhancedFanOutSectionKey, new(EnhancedFanOut))
+}
@@ -0,0 +1,22 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesis
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const InitialPositionSectionKey = "initial_position"
+
+type InitialPosition struct {
+}
+
+// ApplyRule only applies when a shard has no checkpoint.
+func (i *InitialPosition) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return translator.DefaultCase(InitialPositionSectionKey, "latest", input)
+}
+
+func init() {
+	RegisterRule(InitialPositionSectionKey, new(InitialPosition))
+}
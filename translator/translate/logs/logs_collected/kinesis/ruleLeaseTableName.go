@@ -0,0 +1,23 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesis
+
+const LeaseTableNameSectionKey = "lease_table_name"
+
+type LeaseTableName struct {
+}
+
+// ApplyRule omits the table name when it is not set so that the input plugin
+// names the table after the application.
+func (l *LeaseTableName) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if val, ok := im[LeaseTableNameSectionKey]; ok {
+		return LeaseTableNameSectionKey, val
+	}
+	return "", nil
+}
+
+func init() {
+	RegisterRule(LeaseTableNameSectionKey, new(LeaseTableName))
+}
@@ -0,0 +1,84 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+func TestApplyRule(t *testing.T) {
+	translator.ResetMessages()
+	o := new(Otlp)
+	var rawJsonString = `
+{
+	"otlp": {
+		"http_endpoint": "0.0.0.0:4320",
+		"log_group_name": "/otlp/{resource.service.name}",
+		"retention_in_days": 7,
+		"raw_log": true,
+		"tls": {
+			"cert_file": "/path/to/cert.pem",
+			"key_file": "/path/to/key.pem"
+		}
+	}
+}
+`
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(rawJsonString), &input))
+
+	var expected = map[string]interface{}{
+		"otlp_logs": []interface{}{
+			map[string]interface{}{
+				"destination":       "cloudwatchlogs",
+				"http_endpoint":     "0.0.0.0:4320",
+				"log_group_class":   "",
+				"log_group_name":    "/otlp/{resource.service.name}",
+				"log_stream_name":   "{resource.aws.log.stream.names}",
+				"raw_log":           true,
+				"retention_in_days": 7,
+				"tls_cert":          "/path/to/cert.pem",
+				"tls_key":           "/path/to/key.pem",
+			},
+		},
+	}
+	key, actual := o.ApplyRule(input)
+	assert.Equal(t, "inputs", key)
+	assert.Equal(t, expected, actual)
+	assert.Empty(t, translator.ErrorMessages)
+}
+
+func TestApplyRuleDefault(t *testing.T) {
+	o := new(Otlp)
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"otlp": {}}`), &input))
+
+	var expected = map[string]interface{}{
+		"otlp_logs": []interface{}{
+			map[string]interface{}{
+				"destination":       "cloudwatchlogs",
+				"http_endpoint":     "127.0.0.1:4318",
+				"log_group_class":   "",
+				"log_group_name":    "{resource.aws.log.group.names}",
+				"log_stream_name":   "{resource.aws.log.stream.names}",
+				"raw_log":           false,
+				"retention_in_days": -1,
+			},
+		},
+	}
+	key, actual := o.ApplyRule(input)
+	assert.Equal(t, "inputs", key)
+	assert.Equal(t, expected, actual)
+}
+
+func TestApplyRuleNoOtlp(t *testing.T) {
+	o := new(Otlp)
+	key, _ := o.ApplyRule(map[string]interface{}{})
+	assert.Equal(t, "", key)
+}
@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlp
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	HTTPEndpointSectionKey = "http_endpoint"
+	defaultHTTPEndpoint    = "127.0.0.1:4318"
+)
+
+type HTTPEndpoint struct {
+}
+
+func (h *HTTPEndpoint) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return translator.DefaultCase(HTTPEndpointSectionKey, defaultHTTPEndpoint, input)
+}
+
+func init() {
+	RegisterRule(HTTPEndpointSectionKey, new(HTTPEndpoint))
+}
@@ -0,0 +1,21 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlp
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const RawLogSectionKey = "raw_log"
+
+type RawLog struct {
+}
+
+func (r *RawLog) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return translator.DefaultCase(RawLogSectionKey, false, input)
+}
+
+func init() {
+	RegisterRule(RawLogSectionKey, new(RawLog))
+}
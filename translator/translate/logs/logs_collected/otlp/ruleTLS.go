@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlp
+
+const TLSSectionKey = "tls"
+
+// TLSFile maps a file in the tls section to the TLS server config of the input plugin.
+type TLSFile struct {
+	jsonKey string
+	tomlKey string
+}
+
+func (t *TLSFile) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if tls, ok := im[TLSSectionKey].(map[string]interface{}); ok {
+		if file, ok := tls[t.jsonKey]; ok {
+			return t.tomlKey, file
+		}
+	}
+	return
+}
+
+func init() {
+	RegisterRule("tls_cert", &TLSFile{jsonKey: "cert_file", tomlKey: "tls_cert"})
+	RegisterRule("tls_key", &TLSFile{jsonKey: "key_file", tomlKey: "tls_key"})
+}
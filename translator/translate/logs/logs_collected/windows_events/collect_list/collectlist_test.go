@@ -37,6 +37,14 @@ func TestApplyRule(t *testing.T) {
         "event_format": "xml",
         "log_group_name": "Application",
 		"retention_in_days": 1
+      },
+      {
+        "event_name": "ForwardedEvents",
+        "remote_host": "collector.example.com",
+        "event_levels": [
+          "ERROR"
+        ],
+        "log_group_name": "ForwardedEvents"
       }
     ]
 }
@@ -61,6 +69,15 @@ func TestApplyRule(t *testing.T) {
 			"retention_in_days": 1,
 			"log_group_class":   "",
 		},
+		map[string]interface{}{
+			"event_name":        "ForwardedEvents",
+			"remote_host":       "collector.example.com",
+			"event_levels":      []interface{}{"2"},
+			"log_group_name":    "ForwardedEvents",
+			"batch_read_size":   BatchReadSizeValue,
+			"retention_in_days": -1,
+			"log_group_class":   "",
+		},
 	}
 
 	var actual interface{}
@@ -201,6 +201,201 @@ func TestLogs_EndpointOverride(t *testing.T) {
 	ctx.SetMode(config.ModeEC2) //reset back to default mode
 }
 
+func TestLogs_Compression(t *testing.T) {
+	l := new(Logs)
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.RegionType = "any"
+
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"logs":{"compression":"zstd"}}`), &input)
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+
+	ctx := context.CurrentContext()
+	ctx.SetMode(config.ModeOnPrem)
+
+	hostname, _ := os.Hostname()
+	_, actual := l.ApplyRule(input)
+	expected := map[string]interface{}{
+		"outputs": map[string]interface{}{
+			"cloudwatchlogs": []interface{}{
+				map[string]interface{}{
+					"region":               "us-east-1",
+					"region_type":          "any",
+					"mode":                 "OP",
+					"compression":          "zstd",
+					"log_stream_name":      hostname,
+					"force_flush_interval": "5s",
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, actual, "Expected to be equal")
+
+	ctx.SetMode(config.ModeEC2) //reset back to default mode
+}
+
+func TestLogs_RateLimit(t *testing.T) {
+	l := new(Logs)
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.RegionType = "any"
+
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"logs":{"rate_limit":{"quota":1000,"requests_per_second":50,"log_groups":[{"log_group_name":"/aws/app/chatty","requests_per_second":5}]}}}`), &input)
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+
+	ctx := context.CurrentContext()
+	ctx.SetMode(config.ModeOnPrem)
+
+	hostname, _ := os.Hostname()
+	_, actual := l.ApplyRule(input)
+	expected := map[string]interface{}{
+		"outputs": map[string]interface{}{
+			"cloudwatchlogs": []interface{}{
+				map[string]interface{}{
+					"region":               "us-east-1",
+					"region_type":          "any",
+					"mode":                 "OP",
+					"rate_limit_quota":     float64(1000),
+					"rate_limit":           float64(50),
+					"rate_limits":          map[string]interface{}{"/aws/app/chatty": float64(5)},
+					"log_stream_name":      hostname,
+					"force_flush_interval": "5s",
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, actual, "Expected to be equal")
+
+	ctx.SetMode(config.ModeEC2) //reset back to default mode
+}
+
+func TestLogs_ClockSkew(t *testing.T) {
+	l := new(Logs)
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.RegionType = "any"
+
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"logs":{"clock_skew":{"correct_timestamps":true,"warn_threshold":120}}}`), &input)
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+
+	ctx := context.CurrentContext()
+	ctx.SetMode(config.ModeOnPrem)
+
+	hostname, _ := os.Hostname()
+	_, actual := l.ApplyRule(input)
+	expected := map[string]interface{}{
+		"outputs": map[string]interface{}{
+			"cloudwatchlogs": []interface{}{
+				map[string]interface{}{
+					"region":                "us-east-1",
+					"region_type":           "any",
+					"mode":                  "OP",
+					"clock_skew_correction": true,
+					"clock_skew_threshold":  "120s",
+					"log_stream_name":       hostname,
+					"force_flush_interval":  "5s",
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, actual, "Expected to be equal")
+
+	ctx.SetMode(config.ModeEC2) //reset back to default mode
+}
+
+func TestLogs_S3Destination(t *testing.T) {
+	l := new(Logs)
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.RegionType = "any"
+
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"logs":{"logs_destinations":{"s3":{"bucket":"archive","region":"us-west-2","prefix":"{log_group}/%Y/%m/%d","encoding":"parquet","upload_interval":60,"buffer_size_mb":16,"part_size_mb":8}}}}`), &input)
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+
+	ctx := context.CurrentContext()
+	ctx.SetMode(config.ModeOnPrem)
+
+	hostname, _ := os.Hostname()
+	_, actual := l.ApplyRule(input)
+	expected := map[string]interface{}{
+		"outputs": map[string]interface{}{
+			"cloudwatchlogs": []interface{}{
+				map[string]interface{}{
+					"region":               "us-east-1",
+					"region_type":          "any",
+					"mode":                 "OP",
+					"log_stream_name":      hostname,
+					"force_flush_interval": "5s",
+				},
+			},
+			"s3logs": []interface{}{
+				map[string]interface{}{
+					"region":          "us-west-2",
+					"bucket":          "archive",
+					"prefix":          "{log_group}/%Y/%m/%d",
+					"encoding":        "parquet",
+					"upload_interval": "60s",
+					"buffer_size":     16 * 1024 * 1024,
+					"part_size":       8 * 1024 * 1024,
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, actual, "Expected to be equal")
+
+	ctx.SetMode(config.ModeEC2) //reset back to default mode
+}
+
+func TestLogs_FailoverEndpoints(t *testing.T) {
+	l := new(Logs)
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.RegionType = "any"
+
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"logs":{"endpoint_override":["https://logs.us-east-1.amazonaws.com","https://logs.us-west-2.amazonaws.com"],"sigv4a_region_set":["us-east-1","us-west-2"]}}`), &input)
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+
+	ctx := context.CurrentContext()
+	ctx.SetMode(config.ModeOnPrem)
+
+	hostname, _ := os.Hostname()
+	_, actual := l.ApplyRule(input)
+	expected := map[string]interface{}{
+		"outputs": map[string]interface{}{
+			"cloudwatchlogs": []interface{}{
+				map[string]interface{}{
+					"region":               "us-east-1",
+					"region_type":          "any",
+					"mode":                 "OP",
+					"endpoint_override":    "https://logs.us-east-1.amazonaws.com",
+					"failover_endpoints":   []string{"https://logs.us-west-2.amazonaws.com"},
+					"sigv4a_region_set":    []interface{}{"us-east-1", "us-west-2"},
+					"log_stream_name":      hostname,
+					"force_flush_interval": "5s",
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, actual, "Expected to be equal")
+
+	ctx.SetMode(config.ModeEC2) //reset back to default mode
+}
+
 func TestLogs_ServiceAndEnvironment(t *testing.T) {
 	l := new(Logs)
 	agent.Global_Config.Region = "us-east-1"
@@ -0,0 +1,33 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package serviceconnect
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	SectionKeySDNamespacePattern = "sd_namespace_pattern"
+)
+
+type SDNamespacePattern struct {
+}
+
+// Mandatory Key
+func (d *SDNamespacePattern) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if val, ok := im[SectionKeySDNamespacePattern]; !ok {
+		returnKey = ""
+		returnVal = ""
+		translator.AddErrorMessages(GetCurPath()+SectionKeySDNamespacePattern, "sd_namespace_pattern is not defined.")
+	} else {
+		returnKey = SectionKeySDNamespacePattern
+		returnVal = val
+	}
+	return
+}
+
+func init() {
+	RegisterRule(SectionKeySDNamespacePattern, new(SDNamespacePattern))
+}
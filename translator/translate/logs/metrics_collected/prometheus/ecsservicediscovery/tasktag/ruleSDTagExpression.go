@@ -0,0 +1,48 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package tasktag
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	SectionKeySDTagExpression  = "sd_tag_expression"
+	expectedTagExpressionRegex = "^tag:[^,=]+(=[^,]*)?(,[\\s]*tag:[^,=]+(=[^,]*)?)*$"
+)
+
+type SDTagExpression struct {
+}
+
+// Mandatory Key
+func (d *SDTagExpression) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if val, ok := im[SectionKeySDTagExpression]; !ok {
+		returnKey = ""
+		returnVal = ""
+		translator.AddErrorMessages(GetCurPath()+SectionKeySDTagExpression, "sd_tag_expression is not defined.")
+	} else {
+		if !checkTagExpression(val.(string)) {
+			translator.AddErrorMessages(GetCurPath()+SectionKeySDTagExpression, fmt.Sprintf("sd_tag_expression does not follow pattern: %v.", expectedTagExpressionRegex))
+		}
+		returnKey = SectionKeySDTagExpression
+		returnVal = val
+	}
+	return
+}
+
+func checkTagExpression(expression string) bool {
+	ret, err := regexp.MatchString(expectedTagExpressionRegex, expression)
+	if err != nil || !ret {
+		return false
+	}
+	return true
+}
+
+func init() {
+	RegisterRule(SectionKeySDTagExpression, new(SDTagExpression))
+}
@@ -0,0 +1,29 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package tasktag
+
+import "testing"
+
+func Test_checkTagExpression(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       bool
+	}{
+		{name: "valid_test_key_value", expression: "tag:prometheus=true", want: true},
+		{name: "valid_test_key_only", expression: "tag:prometheus", want: true},
+		{name: "valid_test_multiple_terms", expression: "tag:prometheus=true, tag:team", want: true},
+		{name: "invalid_test_no_prefix", expression: "prometheus=true", want: false},
+		{name: "invalid_test_no_key", expression: "tag:=true", want: false},
+		{name: "invalid_test_bad_ending", expression: "tag:prometheus=true,", want: false},
+		{name: "invalid_test_empty_not_allow", expression: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkTagExpression(tt.expression); got != tt.want {
+				t.Errorf("checkTagExpression() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
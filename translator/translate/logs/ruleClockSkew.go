@@ -0,0 +1,45 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	ClockSkewSectionKey       = "clock_skew"
+	clockSkewCorrectKey       = "correct_timestamps"
+	clockSkewWarnThresholdKey = "warn_threshold"
+)
+
+// ClockSkew enables the correction of the timestamps of the log events by the skew of the host
+// clock, and sets the skew in seconds above which it is logged. The output warns about a skew of
+// one minute when it is not set.
+type ClockSkew struct {
+}
+
+func (c *ClockSkew) ApplyRule(input interface{}) (string, interface{}) {
+	_, val := translator.DefaultCase(ClockSkewSectionKey, map[string]interface{}{}, input)
+	section, ok := val.(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	result := map[string]interface{}{}
+	if correct, ok := section[clockSkewCorrectKey].(bool); ok && correct {
+		result["clock_skew_correction"] = true
+	}
+	if threshold, ok := section[clockSkewWarnThresholdKey].(float64); ok && threshold > 0 {
+		result["clock_skew_threshold"] = fmt.Sprintf("%ds", int(threshold))
+	}
+	if len(result) == 0 {
+		return "", nil
+	}
+	return Output_Cloudwatch_Logs, result
+}
+
+func init() {
+	RegisterRule(ClockSkewSectionKey, new(ClockSkew))
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import "github.com/aws/amazon-cloudwatch-agent/translator"
+
+const CompressionSectionKey = "compression"
+
+// Compression is the codec the PutLogEvents requests are compressed with. The output defaults to
+// gzip when it is not set.
+type Compression struct {
+}
+
+func (c *Compression) ApplyRule(input interface{}) (string, interface{}) {
+	_, val := translator.DefaultCase(CompressionSectionKey, "", input)
+	codec, ok := val.(string)
+	if !ok || codec == "" {
+		return "", nil
+	}
+	return Output_Cloudwatch_Logs, map[string]interface{}{CompressionSectionKey: codec}
+}
+
+func init() {
+	RegisterRule(CompressionSectionKey, new(Compression))
+}
@@ -7,14 +7,40 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator"
 )
 
+const (
+	EndpointOverrideSectionKey = "endpoint_override"
+	failoverEndpointsKey       = "failover_endpoints"
+)
+
 type EndpointOverride struct {
 }
 
+// ApplyRule translates the endpoint override. It is either a single endpoint or an ordered list
+// of endpoints, in which case the first one is used until it is unhealthy and the others are the
+// failover endpoints.
 func (r *EndpointOverride) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
 	res := map[string]interface{}{}
-	key, val := translator.DefaultCase("endpoint_override", "", input)
-	res[key] = val
-	if val != "" {
+	_, val := translator.DefaultCase(EndpointOverrideSectionKey, "", input)
+	switch v := val.(type) {
+	case string:
+		if v != "" {
+			res[EndpointOverrideSectionKey] = v
+		}
+	case []interface{}:
+		var endpoints []string
+		for _, endpoint := range v {
+			if s, ok := endpoint.(string); ok && s != "" {
+				endpoints = append(endpoints, s)
+			}
+		}
+		if len(endpoints) > 0 {
+			res[EndpointOverrideSectionKey] = endpoints[0]
+		}
+		if len(endpoints) > 1 {
+			res[failoverEndpointsKey] = endpoints[1:]
+		}
+	}
+	if len(res) > 0 {
 		returnKey = Output_Cloudwatch_Logs
 		returnVal = res
 	}
@@ -22,5 +48,5 @@ func (r *EndpointOverride) ApplyRule(input interface{}) (returnKey string, retur
 }
 func init() {
 	r := new(EndpointOverride)
-	RegisterRule("endpoint_override", r)
+	RegisterRule(EndpointOverrideSectionKey, r)
 }
@@ -0,0 +1,59 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import "github.com/aws/amazon-cloudwatch-agent/translator"
+
+const (
+	RateLimitSectionKey        = "rate_limit"
+	rateLimitQuotaKey          = "quota"
+	rateLimitRequestsPerSecond = "requests_per_second"
+	rateLimitLogGroupsKey      = "log_groups"
+	rateLimitLogGroupNameKey   = "log_group_name"
+)
+
+// RateLimit is the PutLogEvents requests per second of the log groups and of all of them. The
+// log groups are unlimited when it is not set.
+type RateLimit struct {
+}
+
+func (r *RateLimit) ApplyRule(input interface{}) (string, interface{}) {
+	_, val := translator.DefaultCase(RateLimitSectionKey, map[string]interface{}{}, input)
+	section, ok := val.(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	result := map[string]interface{}{}
+	if quota, ok := section[rateLimitQuotaKey].(float64); ok && quota > 0 {
+		result["rate_limit_quota"] = quota
+	}
+	if rps, ok := section[rateLimitRequestsPerSecond].(float64); ok && rps > 0 {
+		result["rate_limit"] = rps
+	}
+	logGroups, _ := section[rateLimitLogGroupsKey].([]interface{})
+	rateLimits := map[string]interface{}{}
+	for _, lg := range logGroups {
+		m, ok := lg.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := m[rateLimitLogGroupNameKey].(string)
+		rps, ok := m[rateLimitRequestsPerSecond].(float64)
+		if name == "" || !ok {
+			continue
+		}
+		rateLimits[name] = rps
+	}
+	if len(rateLimits) > 0 {
+		result["rate_limits"] = rateLimits
+	}
+	if len(result) == 0 {
+		return "", nil
+	}
+	return Output_Cloudwatch_Logs, result
+}
+
+func init() {
+	RegisterRule(RateLimitSectionKey, new(RateLimit))
+}
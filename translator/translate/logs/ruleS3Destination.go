@@ -0,0 +1,87 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/util"
+)
+
+const (
+	LogsDestinationsSectionKey = "logs_destinations"
+	S3DestinationKey           = "s3"
+	Output_S3_Logs             = "s3logs"
+
+	s3BucketKey         = "bucket"
+	s3PrefixKey         = "prefix"
+	s3EncodingKey       = "encoding"
+	s3UploadIntervalKey = "upload_interval"
+	s3BufferSizeMBKey   = "buffer_size_mb"
+	s3PartSizeMBKey     = "part_size_mb"
+	bytesPerMB          = 1024 * 1024
+)
+
+// S3Destination is the S3 bucket the log events of the collect_list entries with the s3
+// destination are archived to.
+type S3Destination struct {
+}
+
+func (s *S3Destination) ApplyRule(input interface{}) (string, interface{}) {
+	m, _ := input.(map[string]interface{})
+	destinations, _ := m[LogsDestinationsSectionKey].(map[string]interface{})
+	section, ok := destinations[S3DestinationKey].(map[string]interface{})
+	if !ok {
+		if usesS3Destination(m) {
+			translator.AddErrorMessages(GetCurPath()+LogsDestinationsSectionKey, "the s3 destination of the collect_list entries is not configured")
+		}
+		return "", nil
+	}
+	result := translator.MergeTwoUniqueMaps(map[string]interface{}{}, agent.Global_Config.Credentials)
+	result[agent.RegionKey] = agent.Global_Config.Region
+	if agent.Global_Config.Role_arn != "" {
+		result[Role_Arn_Key] = agent.Global_Config.Role_arn
+	}
+	for _, key := range []string{agent.RegionKey, Role_Arn_Key, EndpointOverrideSectionKey, s3BucketKey, s3EncodingKey} {
+		if v, ok := section[key].(string); ok && v != "" {
+			result[key] = v
+		}
+	}
+	if prefix, ok := section[s3PrefixKey].(string); ok && prefix != "" {
+		result[s3PrefixKey] = util.ResolvePlaceholder(prefix, GlobalLogConfig.MetadataInfo)
+	}
+	if interval, ok := section[s3UploadIntervalKey].(float64); ok {
+		result[s3UploadIntervalKey] = fmt.Sprintf("%ds", int(interval))
+	}
+	if size, ok := section[s3BufferSizeMBKey].(float64); ok {
+		result["buffer_size"] = int(size) * bytesPerMB
+	}
+	if size, ok := section[s3PartSizeMBKey].(float64); ok {
+		result["part_size"] = int(size) * bytesPerMB
+	}
+	return Output_S3_Logs, result
+}
+
+// usesS3Destination returns true if a collect_list entry of the files publishes to S3.
+func usesS3Destination(logs map[string]interface{}) bool {
+	collected, _ := logs["logs_collected"].(map[string]interface{})
+	files, _ := collected["files"].(map[string]interface{})
+	entries, _ := files["collect_list"].([]interface{})
+	for _, entry := range entries {
+		e, _ := entry.(map[string]interface{})
+		names, _ := e["destinations"].([]interface{})
+		for _, name := range names {
+			if name == S3DestinationKey {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func init() {
+	RegisterRule(LogsDestinationsSectionKey, new(S3Destination))
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import "github.com/aws/amazon-cloudwatch-agent/translator"
+
+const SigV4aRegionSetSectionKey = "sigv4a_region_set"
+
+// SigV4aRegionSet makes the requests signed with SigV4a for the regions, so they are accepted by
+// the failover endpoints in the other regions.
+type SigV4aRegionSet struct {
+}
+
+func (r *SigV4aRegionSet) ApplyRule(input interface{}) (string, interface{}) {
+	_, val := translator.DefaultCase(SigV4aRegionSetSectionKey, []interface{}{}, input)
+	regions, ok := val.([]interface{})
+	if !ok || len(regions) == 0 {
+		return "", nil
+	}
+	return Output_Cloudwatch_Logs, map[string]interface{}{SigV4aRegionSetSectionKey: regions}
+}
+
+func init() {
+	RegisterRule(SigV4aRegionSetSectionKey, new(SigV4aRegionSet))
+}
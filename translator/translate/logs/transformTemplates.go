@@ -0,0 +1,201 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/mergeJsonRule"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/mergeJsonUtil"
+)
+
+const (
+	TransformTemplatesSectionKey = "transform_templates"
+	TransformTemplateKey         = "transform_template"
+
+	logsCollectedKey = "logs_collected"
+	filesKey         = "files"
+	collectListKey   = "collect_list"
+	filtersKey       = "filters"
+)
+
+// transformTemplateFields are the collect_list fields a transform template can
+// set. The filters of a template are applied before the filters of the entry
+// or template referencing it. For the other fields, the referencing entry or
+// template wins.
+var transformTemplateFields = []string{
+	filtersKey,
+	"multi_line_start_pattern",
+	"timestamp_format",
+	"timezone",
+	"encoding",
+	"parsers",
+	"timestamp_field",
+	"emf",
+	"structured_json",
+}
+
+type TransformTemplates struct {
+}
+
+// Merge fails if the same template name is defined differently in multiple
+// sources.
+func (t *TransformTemplates) Merge(source map[string]interface{}, result map[string]interface{}) {
+	mergeJsonUtil.MergeMap(source, result, TransformTemplatesSectionKey, map[string]mergeJsonRule.MergeRule{}, GetCurPath()+TransformTemplatesSectionKey+"/")
+}
+
+// resolveTransformTemplates returns a copy of the logs section with the
+// transform templates referenced by the collect_list entries applied. The
+// input is left untouched, since it is also used by the other translators.
+func resolveTransformTemplates(input interface{}) interface{} {
+	logsMap, ok := input.(map[string]interface{})
+	if !ok {
+		return input
+	}
+	templates, _ := logsMap[TransformTemplatesSectionKey].(map[string]interface{})
+	r := &templateResolver{
+		templates: templates,
+		resolved:  map[string]map[string]interface{}{},
+		invalid:   map[string]bool{},
+		path:      GetCurPath() + TransformTemplatesSectionKey + "/",
+	}
+	// the templates that are not referenced are validated as well
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r.resolveName(name, nil)
+	}
+
+	logsCollected, _ := logsMap[logsCollectedKey].(map[string]interface{})
+	files, _ := logsCollected[filesKey].(map[string]interface{})
+	collectList, ok := files[collectListKey].([]interface{})
+	if !ok {
+		return input
+	}
+	resolvedList := make([]interface{}, len(collectList))
+	changed := false
+	for i, entry := range collectList {
+		resolvedList[i] = entry
+		entryMap, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, ok := entryMap[TransformTemplateKey]
+		if !ok {
+			continue
+		}
+		changed = true
+		entryCopy := copyMap(entryMap)
+		delete(entryCopy, TransformTemplateKey)
+		if fields, ok := r.resolveName(name, nil); ok {
+			applyTemplate(entryCopy, fields)
+		}
+		resolvedList[i] = entryCopy
+	}
+	if !changed {
+		return input
+	}
+
+	filesCopy := copyMap(files)
+	filesCopy[collectListKey] = resolvedList
+	logsCollectedCopy := copyMap(logsCollected)
+	logsCollectedCopy[filesKey] = filesCopy
+	logsCopy := copyMap(logsMap)
+	logsCopy[logsCollectedKey] = logsCollectedCopy
+	return logsCopy
+}
+
+type templateResolver struct {
+	templates map[string]interface{}
+	// resolved caches the fields of the templates with their own references applied.
+	resolved map[string]map[string]interface{}
+	// invalid are the templates that failed to resolve, so that each error is only reported once.
+	invalid map[string]bool
+	path    string
+}
+
+// resolveName returns the fields of the template including the fields of
+// the templates it references. The chain is the names of the templates
+// being resolved, used to detect cycles.
+func (r *templateResolver) resolveName(name interface{}, chain []string) (map[string]interface{}, bool) {
+	nameStr, ok := name.(string)
+	if !ok {
+		translator.AddErrorMessages(r.path, fmt.Sprintf("Transform template name %v is not a string", name))
+		return nil, false
+	}
+	if fields, ok := r.resolved[nameStr]; ok {
+		return fields, true
+	}
+	if r.invalid[nameStr] {
+		return nil, false
+	}
+	for i, visited := range chain {
+		if visited == nameStr {
+			translator.AddErrorMessages(r.path, fmt.Sprintf("Transform templates reference each other in a cycle: %s", strings.Join(append(chain[i:], nameStr), " -> ")))
+			for _, name := range chain[i:] {
+				r.invalid[name] = true
+			}
+			return nil, false
+		}
+	}
+	template, ok := r.templates[nameStr].(map[string]interface{})
+	if !ok {
+		translator.AddErrorMessages(r.path, fmt.Sprintf("Transform template %s is not defined", nameStr))
+		r.invalid[nameStr] = true
+		return nil, false
+	}
+
+	fields := map[string]interface{}{}
+	for _, field := range transformTemplateFields {
+		if val, ok := template[field]; ok {
+			fields[field] = val
+		}
+	}
+	if base, ok := template[TransformTemplateKey]; ok {
+		baseFields, ok := r.resolveName(base, append(chain, nameStr))
+		if !ok {
+			r.invalid[nameStr] = true
+			return nil, false
+		}
+		applyTemplate(fields, baseFields)
+	}
+	r.resolved[nameStr] = fields
+	return fields, true
+}
+
+// applyTemplate adds the template fields to the target. The filters are
+// prepended, the other fields are only added if not set on the target.
+func applyTemplate(target map[string]interface{}, fields map[string]interface{}) {
+	for field, val := range fields {
+		existing, ok := target[field]
+		if !ok {
+			target[field] = val
+			continue
+		}
+		if field == filtersKey {
+			templateFilters, _ := val.([]interface{})
+			targetFilters, _ := existing.([]interface{})
+			filters := make([]interface{}, 0, len(templateFilters)+len(targetFilters))
+			target[field] = append(append(filters, templateFilters...), targetFilters...)
+		}
+	}
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(m)+1)
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func init() {
+	MergeRuleMap[TransformTemplatesSectionKey] = new(TransformTemplates)
+}
@@ -0,0 +1,133 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+func TestResolveTransformTemplates(t *testing.T) {
+	translator.ResetMessages()
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"transform_templates": {
+			"drop_debug": {
+				"filters": [{"type": "exclude", "expression": "DEBUG"}]
+			},
+			"app": {
+				"transform_template": "drop_debug",
+				"timestamp_format": "%Y-%m-%d %H:%M:%S",
+				"timezone": "UTC",
+				"multi_line_start_pattern": "{timestamp_format}",
+				"filters": [{"type": "include", "expression": "app"}]
+			}
+		},
+		"logs_collected": {
+			"files": {
+				"collect_list": [
+					{
+						"file_path": "/var/log/app.log",
+						"transform_template": "app",
+						"timezone": "Local",
+						"filters": [{"type": "exclude", "expression": "health"}]
+					},
+					{
+						"file_path": "/var/log/other.log",
+						"transform_template": "drop_debug"
+					},
+					{
+						"file_path": "/var/log/plain.log"
+					}
+				]
+			}
+		}
+	}`), &input))
+	original, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	got := resolveTransformTemplates(input)
+	assert.Empty(t, translator.ErrorMessages)
+	collectList := got.(map[string]interface{})["logs_collected"].(map[string]interface{})["files"].(map[string]interface{})["collect_list"].([]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"file_path":                "/var/log/app.log",
+		"timestamp_format":         "%Y-%m-%d %H:%M:%S",
+		"timezone":                 "Local",
+		"multi_line_start_pattern": "{timestamp_format}",
+		"filters": []interface{}{
+			map[string]interface{}{"type": "exclude", "expression": "DEBUG"},
+			map[string]interface{}{"type": "include", "expression": "app"},
+			map[string]interface{}{"type": "exclude", "expression": "health"},
+		},
+	}, collectList[0])
+	assert.Equal(t, map[string]interface{}{
+		"file_path": "/var/log/other.log",
+		"filters": []interface{}{
+			map[string]interface{}{"type": "exclude", "expression": "DEBUG"},
+		},
+	}, collectList[1])
+	assert.Equal(t, map[string]interface{}{"file_path": "/var/log/plain.log"}, collectList[2])
+
+	// the input is used by the other translators as is
+	after, err := json.Marshal(input)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(original), string(after))
+}
+
+func TestResolveTransformTemplatesErrors(t *testing.T) {
+	testCases := map[string]struct {
+		input      string
+		wantErrors []string
+	}{
+		"WithCycle": {
+			input: `{
+				"transform_templates": {
+					"a": {"transform_template": "b"},
+					"b": {"transform_template": "c"},
+					"c": {"transform_template": "b"}
+				}
+			}`,
+			wantErrors: []string{
+				"Under path : /logs/transform_templates/ | Error : Transform templates reference each other in a cycle: b -> c -> b",
+			},
+		},
+		"WithSelfReference": {
+			input: `{
+				"transform_templates": {
+					"a": {"transform_template": "a"}
+				},
+				"logs_collected": {"files": {"collect_list": [{"file_path": "/tmp/a.log", "transform_template": "a"}]}}
+			}`,
+			wantErrors: []string{
+				"Under path : /logs/transform_templates/ | Error : Transform templates reference each other in a cycle: a -> a",
+			},
+		},
+		"WithUndefined": {
+			input: `{
+				"logs_collected": {"files": {"collect_list": [
+					{"file_path": "/tmp/a.log", "transform_template": "missing"},
+					{"file_path": "/tmp/b.log", "transform_template": "missing"}
+				]}}
+			}`,
+			wantErrors: []string{
+				"Under path : /logs/transform_templates/ | Error : Transform template missing is not defined",
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			translator.ResetMessages()
+			defer translator.ResetMessages()
+			var input interface{}
+			require.NoError(t, json.Unmarshal([]byte(testCase.input), &input))
+			resolveTransformTemplates(input)
+			assert.Equal(t, testCase.wantErrors, translator.ErrorMessages)
+		})
+	}
+}
@@ -9,7 +9,21 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/util"
 )
 
-const File_State_Folder_Linux = "/opt/aws/amazon-cloudwatch-agent/logs/state"
+const (
+	Logs_Folder_Linux       = "/opt/aws/amazon-cloudwatch-agent/logs"
+	File_State_Folder_Linux = "/opt/aws/amazon-cloudwatch-agent/logs/state"
+)
+
+// GetLogsFolder returns the folder of the agent log, for the files written
+// by the agent that are not the state of the log sources.
+func GetLogsFolder() (logsFolder string) {
+	if translator.GetTargetPlatform() == config.OS_TYPE_WINDOWS {
+		logsFolder = util.GetWindowsProgramDataPath() + "\\Amazon\\AmazonCloudWatchAgent\\Logs"
+	} else {
+		logsFolder = Logs_Folder_Linux
+	}
+	return
+}
 
 func GetFileStateFolder() (fileStateFolder string) {
 	if translator.GetTargetPlatform() == config.OS_TYPE_WINDOWS {
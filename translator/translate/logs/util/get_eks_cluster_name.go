@@ -24,6 +24,10 @@ const (
 
 var (
 	sleeps = []time.Duration{time.Millisecond * 200, time.Millisecond * 400, time.Millisecond * 800, time.Millisecond * 1600, time.Millisecond * 3200}
+
+	// GetClusterNameFromEc2Tagger looks up the cluster name from the tags of
+	// the instance. It is replaced when the config is rendered off the host.
+	GetClusterNameFromEc2Tagger = getClusterNameFromEc2Tagger
 )
 
 // For ASG case, the ec2 tag may be not ready as soon as the node is started up.
@@ -40,7 +44,7 @@ func GetEKSClusterName(sectionKey string, input map[string]interface{}) string {
 	return clusterName
 }
 
-func GetClusterNameFromEc2Tagger() string {
+func getClusterNameFromEc2Tagger() string {
 	instanceId := ec2util.GetEC2UtilSingleton().InstanceID
 	region := ec2util.GetEC2UtilSingleton().Region
 
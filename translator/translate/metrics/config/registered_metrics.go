@@ -20,6 +20,14 @@ var Registered_Metrics_Linux = map[string][]string{
 		"read_bytes", "read_count", "realtime_priority", "rlimit_cpu_time_hard", "rlimit_cpu_time_soft", "rlimit_file_locks_hard", "rlimit_file_locks_soft", "rlimit_memory_data_hard", "rlimit_memory_data_soft", "rlimit_memory_locked_hard", "rlimit_memory_locked_soft",
 		"rlimit_memory_rss_hard", "rlimit_memory_rss_soft", "rlimit_memory_stack_hard", "rlimit_memory_stack_soft", "rlimit_memory_vms_hard", "rlimit_memory_vms_soft", "rlimit_nice_priority_hard", "rlimit_nice_priority_soft", "rlimit_num_fds_hard", "rlimit_num_fds_soft",
 		"rlimit_realtime_priority_hard", "rlimit_realtime_priority_soft", "rlimit_signals_pending_hard", "rlimit_signals_pending_soft", "signals_pending", "voluntary_context_switches", "write_bytes", "write_count", "pid_count"},
+	"efa": {"rx_bytes", "rx_pkts", "rx_drops", "tx_bytes", "tx_pkts", "rdma_read_bytes", "rdma_write_bytes", "rdma_write_recv_bytes",
+		"retrans_bytes", "retrans_pkts", "retrans_timeout_events", "impaired_remote_conn_events", "unresponsive_remote_events"},
+	"diskstats": {"read_latency", "write_latency", "discard_latency", "flush_latency", "queue_depth", "in_flight", "io_latency",
+		"read_ops", "write_ops", "read_bytes", "write_bytes"},
+	"network_connections": {"connections", "retransmits", "rtt_avg"},
+	"network_probe":       {"rtt_min", "rtt_avg", "rtt_max", "packets_sent", "packets_received", "packet_loss"},
+	"nfs": {"ops", "retrans", "timeouts", "op_latency", "read_ops", "read_latency", "read_bytes", "write_ops", "write_latency", "write_bytes",
+		"responsive", "stale", "probe_latency"},
 	"nvidia_smi": {"utilization_gpu", "temperature_gpu", "power_draw", "utilization_memory", "fan_speed", "memory_total", "memory_used", "memory_free", "temperature_gpu", "pcie_link_gen_current", "pcie_link_width_current",
 		"encoder_stats_session_count", "encoder_stats_average_fps", "encoder_stats_average_latency", "clocks_current_graphics", "clocks_current_sm", "clocks_current_memory", "clocks_current_video"},
 }
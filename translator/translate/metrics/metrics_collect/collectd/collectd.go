@@ -17,12 +17,13 @@ import (
 //	    "name_prefix": "collectd_",
 //	    "collectd_auth_file": "/etc/collectd/auth_file",
 //	    "collectd_security_level": "encrypt",
-//	    "collectd_typesdb": ["/usr/share/collectd/types.db"],
+//	    "collectd_typesdb": ["/usr/share/collectd/types.db", "/etc/collectd/types.d/*.db"],
+//	    "collectd_typesdb_reload_interval": 60,
 //	    "metrics_aggregation_interval": 60
 //	}
 const (
 	SectionKey       = "collectd"
-	SectionMappedKey = "socket_listener"
+	SectionMappedKey = "collectd"
 )
 
 var ChildRule = map[string]translator.Rule{}
@@ -18,7 +18,8 @@ func TestCollectD_HappyCase(t *testing.T) {
 		"name_prefix": "collectd_prefix_",
 		"collectd_auth_file": "/etc/collectd/_auth_file",
 		"collectd_security_level": "none",
-		"collectd_typesdb": ["/usr/share/collectd/types.db", "/custom_location/types.db"],
+		"collectd_typesdb": ["/usr/share/collectd/types.db", "/custom_location/*.db"],
+		"collectd_typesdb_reload_interval": 300,
 		"metrics_aggregation_interval": 30
 	}}`), &input)
 	assert.NoError(t, err)
@@ -27,13 +28,13 @@ func TestCollectD_HappyCase(t *testing.T) {
 
 	expect := []interface{}{
 		map[string]interface{}{
-			"data_format":             "collectd",
-			"service_address":         "udp://127.0.0.1:123",
-			"name_prefix":             "collectd_prefix_",
-			"collectd_auth_file":      "/etc/collectd/_auth_file",
-			"collectd_security_level": "none",
-			"collectd_typesdb":        []interface{}{"/usr/share/collectd/types.db", "/custom_location/types.db"},
-			"tags":                    map[string]interface{}{"aws:AggregationInterval": "30s"},
+			"service_address":                  "udp://127.0.0.1:123",
+			"name_prefix":                      "collectd_prefix_",
+			"collectd_auth_file":               "/etc/collectd/_auth_file",
+			"collectd_security_level":          "none",
+			"collectd_typesdb":                 []interface{}{"/usr/share/collectd/types.db", "/custom_location/*.db"},
+			"collectd_typesdb_reload_interval": "300s",
+			"tags":                             map[string]interface{}{"aws:AggregationInterval": "30s"},
 		},
 	}
 
@@ -50,7 +51,6 @@ func TestCollectD_MinimumConfig(t *testing.T) {
 
 	expect := []interface{}{
 		map[string]interface{}{
-			"data_format":             "collectd",
 			"service_address":         "udp://127.0.0.1:25826",
 			"name_prefix":             "collectd_",
 			"collectd_auth_file":      "/etc/collectd/auth_file",
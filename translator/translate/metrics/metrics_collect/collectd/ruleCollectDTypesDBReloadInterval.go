@@ -0,0 +1,28 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collected
+
+import (
+	"fmt"
+)
+
+type TypesDBReloadInterval struct {
+}
+
+const SectionKey_TypesDBReloadInterval = "collectd_typesdb_reload_interval"
+
+// ApplyRule converts the reload interval in seconds, 0 disables the reload.
+func (obj *TypesDBReloadInterval) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	if val, ok := m[SectionKey_TypesDBReloadInterval].(float64); ok {
+		returnKey = SectionKey_TypesDBReloadInterval
+		returnVal = fmt.Sprintf("%ds", int(val))
+	}
+	return
+}
+
+func init() {
+	obj := new(TypesDBReloadInterval)
+	RegisterRule(SectionKey_TypesDBReloadInterval, obj)
+}
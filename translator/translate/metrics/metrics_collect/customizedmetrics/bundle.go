@@ -0,0 +1,169 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package customizedmetrics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/hash"
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+//
+//	"iis": {
+//		"version": "1",
+//		"metrics_collection_interval": 60
+//	},
+//	"mssql": {
+//		"sql_instance": "SQLEXPRESS"
+//	}
+//
+
+const (
+	IISBundleKey   = "iis"
+	MSSQLBundleKey = "mssql"
+
+	bundleVersionKey     = "version"
+	bundleSQLInstanceKey = "sql_instance"
+
+	latestBundleVersion = "1"
+
+	// mssqlDefaultObjectPrefix is the prefix of the performance objects of the
+	// default SQL Server instance. The named instances use MSSQL$<name>.
+	mssqlDefaultObjectPrefix = "SQLServer:"
+)
+
+// bundleObject is a Windows performance object of a metric bundle.
+type bundleObject struct {
+	name      string
+	counters  []string
+	instances []interface{}
+}
+
+// metricBundles are the curated Windows performance counters of each bundle
+// by version. A version must not change once released so that the metrics
+// published with it stay the same.
+var metricBundles = map[string]map[string][]bundleObject{
+	IISBundleKey: {
+		"1": {
+			{
+				name:      "Web Service",
+				counters:  []string{"Current Connections", "Connection Attempts/sec", "Bytes Received/sec", "Bytes Sent/sec", "Get Requests/sec", "Post Requests/sec", "Not Found Errors/sec"},
+				instances: []interface{}{"*"},
+			},
+			{
+				name:      "ASP.NET Applications",
+				counters:  []string{"Requests/Sec", "Errors Total/Sec", "Requests In Application Queue"},
+				instances: []interface{}{"__Total__"},
+			},
+			{
+				name:     "ASP.NET",
+				counters: []string{"Application Restarts", "Requests Queued", "Requests Rejected", "Request Execution Time"},
+			},
+			{
+				name:      "APP_POOL_WAS",
+				counters:  []string{"Current Application Pool State", "Total Worker Process Failures"},
+				instances: []interface{}{"*"},
+			},
+		},
+	},
+	MSSQLBundleKey: {
+		"1": {
+			{
+				name:     "SQLServer:General Statistics",
+				counters: []string{"User Connections", "Processes blocked"},
+			},
+			{
+				name:     "SQLServer:SQL Statistics",
+				counters: []string{"Batch Requests/sec", "SQL Compilations/sec", "SQL Re-Compilations/sec"},
+			},
+			{
+				name:     "SQLServer:Buffer Manager",
+				counters: []string{"Page life expectancy", "Page reads/sec", "Page writes/sec"},
+			},
+			{
+				name:     "SQLServer:Memory Manager",
+				counters: []string{"Memory Grants Pending", "Total Server Memory (KB)", "Target Server Memory (KB)"},
+			},
+			{
+				name:      "SQLServer:Locks",
+				counters:  []string{"Lock Waits/sec", "Lock Timeouts/sec", "Number of Deadlocks/sec"},
+				instances: []interface{}{"_Total"},
+			},
+			{
+				name:      "SQLServer:Databases",
+				counters:  []string{"Transactions/sec", "Data File(s) Size (KB)", "Log File(s) Used Size (KB)"},
+				instances: []interface{}{"*"},
+			},
+		},
+	},
+}
+
+// isBundle returns true if the object name is a metric bundle rather than a
+// Windows performance object.
+func isBundle(objectName string) bool {
+	_, ok := metricBundles[objectName]
+	return ok
+}
+
+// processBundle expands the metric bundle into the Windows performance
+// counter objects of its version. The objects are collected by a single input
+// so that they share the interval and the dimensions of the bundle. Returns
+// nil if the bundle config is invalid.
+func processBundle(bundleName string, input interface{}) map[string]interface{} {
+	path := GetObjectPath(bundleName)
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		translator.AddErrorMessages(path, fmt.Sprintf("the %s bundle config is invalid", bundleName))
+		return nil
+	}
+	version := latestBundleVersion
+	if val, ok := inputMap[bundleVersionKey]; ok {
+		version = fmt.Sprint(val)
+	}
+	objects, ok := metricBundles[bundleName][version]
+	if !ok {
+		translator.AddErrorMessages(path, fmt.Sprintf("version %s of the %s bundle is not supported", version, bundleName))
+		return nil
+	}
+	var result map[string]interface{}
+	var objectConfigs []interface{}
+	for _, object := range objects {
+		objectInput := map[string]interface{}{}
+		for _, key := range []string{util.Collect_Interval_Key, util.Append_Dimensions_Key, util.Refresh_Interval_Key} {
+			if val, ok := inputMap[key]; ok {
+				objectInput[key] = val
+			}
+		}
+		counters := make([]interface{}, 0, len(object.counters))
+		for _, counter := range object.counters {
+			counters = append(counters, counter)
+		}
+		objectInput[util.Measurement_Key] = counters
+		if object.instances != nil {
+			objectInput[util.Resource_Key] = object.instances
+		}
+		result = util.ProcessWindowsCommonConfig(objectInput, bundleObjectName(bundleName, object.name, inputMap), path)
+		objectConfigs = append(objectConfigs, result[parent.ObjectKey].([]interface{})...)
+	}
+	result[util.Alias_Key] = hash.HashName(bundleName)
+	result[parent.ObjectKey] = objectConfigs
+	return result
+}
+
+// bundleObjectName returns the name of the performance object for the SQL
+// Server instance of the mssql bundle.
+func bundleObjectName(bundleName, objectName string, inputMap map[string]interface{}) string {
+	if bundleName != MSSQLBundleKey {
+		return objectName
+	}
+	sqlInstance, _ := inputMap[bundleSQLInstanceKey].(string)
+	if sqlInstance == "" || strings.EqualFold(sqlInstance, "MSSQLSERVER") {
+		return objectName
+	}
+	return "MSSQL$" + sqlInstance + ":" + strings.TrimPrefix(objectName, mssqlDefaultObjectPrefix)
+}
@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package customizedmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/hash"
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+func TestProcessBundle(t *testing.T) {
+	translator.ResetMessages()
+	got := processBundle(MSSQLBundleKey, map[string]interface{}{"metrics_collection_interval": float64(30)})
+	require.NotNil(t, got)
+	assert.Empty(t, translator.ErrorMessages)
+	assert.Equal(t, hash.HashName(MSSQLBundleKey), got["alias"])
+	assert.Equal(t, "30s", got["interval"])
+	objects := got["object"].([]interface{})
+	require.Len(t, objects, len(metricBundles[MSSQLBundleKey][latestBundleVersion]))
+	object := objects[0].(map[string]interface{})
+	assert.Equal(t, "SQLServer:General Statistics", object["ObjectName"])
+	assert.Equal(t, []string{"User Connections", "Processes blocked"}, object["Counters"])
+	assert.Equal(t, []string{"------"}, object["Instances"])
+}
+
+func TestProcessBundleWithInvalidVersion(t *testing.T) {
+	translator.ResetMessages()
+	assert.Nil(t, processBundle(IISBundleKey, map[string]interface{}{"version": "0"}))
+	assert.Len(t, translator.ErrorMessages, 1)
+	translator.ResetMessages()
+}
+
+func TestBundleObjectName(t *testing.T) {
+	assert.Equal(t, "Web Service", bundleObjectName(IISBundleKey, "Web Service", map[string]interface{}{"sql_instance": "SQLEXPRESS"}))
+	assert.Equal(t, "SQLServer:Locks", bundleObjectName(MSSQLBundleKey, "SQLServer:Locks", map[string]interface{}{}))
+	assert.Equal(t, "SQLServer:Locks", bundleObjectName(MSSQLBundleKey, "SQLServer:Locks", map[string]interface{}{"sql_instance": "MSSQLSERVER"}))
+	assert.Equal(t, "MSSQL$SQLEXPRESS:Locks", bundleObjectName(MSSQLBundleKey, "SQLServer:Locks", map[string]interface{}{"sql_instance": "SQLEXPRESS"}))
+}
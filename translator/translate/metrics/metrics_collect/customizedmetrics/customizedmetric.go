@@ -36,7 +36,14 @@ func (c *customizedMetric) ApplyRule(input interface{}) (returnKey string, retur
 
 	sort.Strings(inputObjectNames)
 	for _, objectName := range inputObjectNames {
-		singleConfig := util.ProcessWindowsCommonConfig(inputmap[objectName], objectName, GetObjectPath(objectName))
+		var singleConfig map[string]interface{}
+		if isBundle(objectName) {
+			if singleConfig = processBundle(objectName, inputmap[objectName]); singleConfig == nil {
+				continue
+			}
+		} else {
+			singleConfig = util.ProcessWindowsCommonConfig(inputmap[objectName], objectName, GetObjectPath(objectName))
+		}
 		winPerfCountersArray = append(winPerfCountersArray, singleConfig)
 	}
 
@@ -0,0 +1,69 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package diskstats
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+var ChildRule = map[string]translator.Rule{}
+
+//	"diskstats": {
+//		"measurement": [
+//			"read_latency",
+//			"write_latency",
+//			"queue_depth"
+//		],
+//		"devices": ["nvme*"],
+//		"exclude_devices": ["loop*"],
+//		"cgroups": ["system.slice/docker-*.scope"],
+//		"sample_interval": 1,
+//		"metrics_collection_interval": 60
+//	}
+const SectionKey = "diskstats"
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey + "/"
+	return curPath
+}
+
+func RegisterRule(fieldname string, r translator.Rule) {
+	ChildRule[fieldname] = r
+}
+
+type DiskStats struct {
+}
+
+func (d *DiskStats) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	resArr := []interface{}{}
+	result := map[string]interface{}{}
+	//Check if this plugin exist in the input instance
+	//If not, not process
+	if _, ok := m[SectionKey]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		//Check if there are any config entry with rules applied
+		result = translator.ProcessRuleToApply(m[SectionKey], ChildRule, result)
+
+		//Process common config, like measurement
+		hasValidMetric := util.ProcessLinuxCommonConfig(m[SectionKey], SectionKey, GetCurPath(), result)
+		if hasValidMetric {
+			resArr = append(resArr, result)
+			returnKey = SectionKey
+			returnVal = resArr
+		} else {
+			returnKey = ""
+		}
+	}
+	return
+}
+
+func init() {
+	d := new(DiskStats)
+	parent.RegisterLinuxRule(SectionKey, d)
+}
@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package diskstats
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	d := new(DiskStats)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"diskstats":{"measurement": [
+						"read_latency",
+						"queue_depth"
+					]}}`), &input)
+	require.NoError(t, err)
+	actualKey, actualVal := d.ApplyRule(input)
+	expectedVal := []interface{}{map[string]interface{}{
+		"fieldpass": []string{"read_latency", "queue_depth"},
+	}}
+	assert.Equal(t, SectionKey, actualKey)
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestFullConfig(t *testing.T) {
+	d := new(DiskStats)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"diskstats":{"measurement": [
+						"read_latency",
+						"write_latency",
+						"diskstats_io_latency"
+					],
+					"devices": ["nvme*"],
+					"exclude_devices": ["nvme*p*"],
+					"cgroups": ["system.slice/docker-*.scope"],
+					"sample_interval": 2,
+					"metrics_collection_interval": 120,
+					"append_dimensions": {"cluster": "shared"}
+					}}`), &input)
+	require.NoError(t, err)
+	_, actualVal := d.ApplyRule(input)
+	expectedVal := []interface{}{map[string]interface{}{
+		"devices":         []interface{}{"nvme*"},
+		"exclude_devices": []interface{}{"nvme*p*"},
+		"cgroups":         []interface{}{"system.slice/docker-*.scope"},
+		"sample_interval": "2s",
+		"fieldpass":       []string{"read_latency", "write_latency", "io_latency"},
+		"interval":        "120s",
+		"tags":            map[string]interface{}{"cluster": "shared"},
+	}}
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestNoFieldConfig(t *testing.T) {
+	d := new(DiskStats)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"diskstats":{"metrics_collection_interval":60}}`), &input)
+	require.NoError(t, err)
+	actualKey, _ := d.ApplyRule(input)
+	assert.Equal(t, "", actualKey, "return key should be empty")
+}
@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package diskstats
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Cgroups struct {
+}
+
+const SectionKey_Cgroups = "cgroups"
+
+// ApplyRule passes the cgroup v2 path globs through, no cgroup is collected when it is not set.
+func (obj *Cgroups) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m, ok := input.(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	if _, ok = m[SectionKey_Cgroups]; !ok {
+		return "", nil
+	}
+	return translator.DefaultCase(SectionKey_Cgroups, []string{}, input)
+}
+
+func init() {
+	obj := new(Cgroups)
+	RegisterRule(SectionKey_Cgroups, obj)
+}
@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package diskstats
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Devices struct {
+}
+
+const SectionKey_Devices = "devices"
+
+// ApplyRule passes the device globs through, all the devices but the excluded ones are collected when it is not set.
+func (obj *Devices) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m, ok := input.(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	if _, ok = m[SectionKey_Devices]; !ok {
+		return "", nil
+	}
+	return translator.DefaultCase(SectionKey_Devices, []string{}, input)
+}
+
+func init() {
+	obj := new(Devices)
+	RegisterRule(SectionKey_Devices, obj)
+}
@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package diskstats
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type ExcludeDevices struct {
+}
+
+const SectionKey_ExcludeDevices = "exclude_devices"
+
+// ApplyRule passes the globs of the excluded devices through, the loop and ram devices are excluded when it is not set.
+func (obj *ExcludeDevices) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m, ok := input.(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	if _, ok = m[SectionKey_ExcludeDevices]; !ok {
+		return "", nil
+	}
+	return translator.DefaultCase(SectionKey_ExcludeDevices, []string{}, input)
+}
+
+func init() {
+	obj := new(ExcludeDevices)
+	RegisterRule(SectionKey_ExcludeDevices, obj)
+}
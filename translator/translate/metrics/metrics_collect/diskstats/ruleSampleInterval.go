@@ -0,0 +1,29 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package diskstats
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type SampleInterval struct {
+}
+
+const SectionKey_SampleInterval = "sample_interval"
+
+// ApplyRule translates the seconds between the samples of the devices. The
+// plugin default is used if it is not set.
+func (obj *SampleInterval) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if m, ok := input.(map[string]interface{}); ok {
+		if _, ok = m[SectionKey_SampleInterval]; ok {
+			return translator.DefaultTimeIntervalCase(SectionKey_SampleInterval, float64(0), input)
+		}
+	}
+	return
+}
+
+func init() {
+	obj := new(SampleInterval)
+	RegisterRule(SectionKey_SampleInterval, obj)
+}
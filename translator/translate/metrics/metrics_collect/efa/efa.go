@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package efa
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+var ChildRule = map[string]translator.Rule{}
+
+//	"efa": {
+//		"measurement": [
+//			"rx_bytes",
+//			"tx_bytes",
+//			"rdma_read_bytes"
+//		],
+//		"device_include": ["rdmap*"],
+//		"metrics_collection_interval": 60
+//	}
+const SectionKey = "efa"
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey + "/"
+	return curPath
+}
+
+func RegisterRule(fieldname string, r translator.Rule) {
+	ChildRule[fieldname] = r
+}
+
+type EFA struct {
+}
+
+func (e *EFA) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	resArr := []interface{}{}
+	result := map[string]interface{}{}
+	//Check if this plugin exist in the input instance
+	//If not, not process
+	if _, ok := m[SectionKey]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		//Check if there are any config entry with rules applied
+		result = translator.ProcessRuleToApply(m[SectionKey], ChildRule, result)
+		//Process common config, like measurement
+		hasValidMetric := util.ProcessLinuxCommonConfig(m[SectionKey], SectionKey, GetCurPath(), result)
+		if hasValidMetric {
+			resArr = append(resArr, result)
+			returnKey = SectionKey
+			returnVal = resArr
+		} else {
+			returnKey = ""
+		}
+	}
+	return
+}
+
+func init() {
+	e := new(EFA)
+	parent.RegisterLinuxRule(SectionKey, e)
+}
@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package efa
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	e := new(EFA)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"efa":{"measurement": [
+						"rx_bytes",
+						"tx_bytes"
+					]}}`), &input)
+	require.NoError(t, err)
+	actualKey, actualVal := e.ApplyRule(input)
+	expectedVal := []interface{}{map[string]interface{}{
+		"fieldpass": []string{"rx_bytes", "tx_bytes"},
+	}}
+	assert.Equal(t, SectionKey, actualKey)
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestFullConfig(t *testing.T) {
+	e := new(EFA)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"efa":{"measurement": [
+						"rx_bytes",
+						"rdma_read_bytes",
+						"retrans_timeout_events"
+					],
+					"device_include": ["rdmap0s6"],
+					"metrics_collection_interval": 120,
+					"append_dimensions": {"cluster": "training"}
+					}}`), &input)
+	require.NoError(t, err)
+	_, actualVal := e.ApplyRule(input)
+	expectedVal := []interface{}{map[string]interface{}{
+		"device_include": []interface{}{"rdmap0s6"},
+		"fieldpass":      []string{"rx_bytes", "rdma_read_bytes", "retrans_timeout_events"},
+		"interval":       "120s",
+		"tags":           map[string]interface{}{"cluster": "training"},
+	}}
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestNoFieldConfig(t *testing.T) {
+	e := new(EFA)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"efa":{"metrics_collection_interval":60}}`), &input)
+	require.NoError(t, err)
+	actualKey, _ := e.ApplyRule(input)
+	assert.Equal(t, "", actualKey, "return key should be empty")
+}
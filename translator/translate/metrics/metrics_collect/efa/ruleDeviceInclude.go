@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package efa
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type DeviceInclude struct {
+}
+
+const SectionKey_DeviceInclude = "device_include"
+
+// ApplyRule passes the device globs through, all the EFA devices are collected when it is not set.
+func (obj *DeviceInclude) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m, ok := input.(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	if _, ok = m[SectionKey_DeviceInclude]; !ok {
+		return "", nil
+	}
+	return translator.DefaultCase(SectionKey_DeviceInclude, []string{}, input)
+}
+
+func init() {
+	obj := new(DeviceInclude)
+	RegisterRule(SectionKey_DeviceInclude, obj)
+}
@@ -64,3 +64,31 @@ func TestFullConfig(t *testing.T) {
 	assert.Equal(t, string(marshalExpected), string(marshalActual), "Expected to be equal")
 
 }
+
+func TestEnaMetrics(t *testing.T) {
+	d := new(Ethtool)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"ethtool": {
+					"metrics_include": [
+						"ena",
+						"bw_in_allowance_exceeded",
+						"rx_queue_0_drops"
+					]
+					}}`), &input)
+	assert.NoError(t, err)
+	_, actual := d.ApplyRule(input)
+
+	expected := []interface{}{map[string]interface{}{
+		"interface_include": []string{"*"},
+		"fieldpass": []interface{}{
+			"bw_in_allowance_exceeded",
+			"bw_out_allowance_exceeded",
+			"pps_allowance_exceeded",
+			"conntrack_allowance_exceeded",
+			"linklocal_allowance_exceeded",
+			"conntrack_allowance_available",
+			"rx_queue_0_drops",
+		},
+	}}
+	assert.Equal(t, expected, actual)
+}
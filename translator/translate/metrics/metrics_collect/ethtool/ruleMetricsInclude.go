@@ -10,14 +10,55 @@ import (
 type MetricsInclude struct {
 }
 
-const SectionKey_MetricsInclude = "metrics_include"
+const (
+	SectionKey_MetricsInclude = "metrics_include"
+
+	// enaMetricsKey in metrics_include stands for the EnaMetrics.
+	enaMetricsKey = "ena"
+)
+
+// EnaMetrics are the ENA driver stats of the packets queued or dropped because the instance exceeded
+// its network allowances, and of the connections still available to the connection tracking.
+var EnaMetrics = []string{
+	"bw_in_allowance_exceeded",
+	"bw_out_allowance_exceeded",
+	"pps_allowance_exceeded",
+	"conntrack_allowance_exceeded",
+	"linklocal_allowance_exceeded",
+	"conntrack_allowance_available",
+}
 
 func (obj *MetricsInclude) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
 	_, returnVal = translator.DefaultCase(SectionKey_MetricsInclude, []string{}, input)
 	returnKey = "fieldpass"
+	if metrics, ok := returnVal.([]interface{}); ok {
+		returnVal = expandEnaMetrics(metrics)
+	}
 	return
 }
 
+// expandEnaMetrics replaces the ena entry with the EnaMetrics.
+func expandEnaMetrics(metrics []interface{}) []interface{} {
+	result := make([]interface{}, 0, len(metrics))
+	seen := map[interface{}]bool{}
+	add := func(metric interface{}) {
+		if !seen[metric] {
+			seen[metric] = true
+			result = append(result, metric)
+		}
+	}
+	for _, metric := range metrics {
+		if metric != enaMetricsKey {
+			add(metric)
+			continue
+		}
+		for _, enaMetric := range EnaMetrics {
+			add(enaMetric)
+		}
+	}
+	return result
+}
+
 func init() {
 	obj := new(MetricsInclude)
 	RegisterRule(SectionKey_MetricsInclude, obj)
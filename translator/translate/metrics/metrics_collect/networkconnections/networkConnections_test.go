@@ -0,0 +1,67 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package networkconnections
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	n := new(NetworkConnections)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"network_connections":{"measurement": [
+						"connections",
+						"retransmits"
+					]}}`), &input)
+	require.NoError(t, err)
+	actualKey, actualVal := n.ApplyRule(input)
+	expectedVal := []interface{}{map[string]interface{}{
+		"collection_method": "auto",
+		"per_destination":   true,
+		"include_udp":       true,
+		"fieldpass":         []string{"connections", "retransmits"},
+	}}
+	assert.Equal(t, SectionKey, actualKey)
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestFullConfig(t *testing.T) {
+	n := new(NetworkConnections)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"network_connections":{"measurement": [
+						"connections",
+						"retransmits",
+						"rtt_avg"
+					],
+					"collection_method": "procfs",
+					"per_destination": false,
+					"include_udp": false,
+					"metrics_collection_interval": 120,
+					"append_dimensions": {"name": "sampleName"}
+					}}`), &input)
+	require.NoError(t, err)
+	_, actualVal := n.ApplyRule(input)
+	expectedVal := []interface{}{map[string]interface{}{
+		"collection_method": "procfs",
+		"per_destination":   false,
+		"include_udp":       false,
+		"fieldpass":         []string{"connections", "retransmits", "rtt_avg"},
+		"interval":          "120s",
+		"tags":              map[string]interface{}{"name": "sampleName"},
+	}}
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestNoFieldConfig(t *testing.T) {
+	n := new(NetworkConnections)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"network_connections":{"metrics_collection_interval":"60s"}}`), &input)
+	require.NoError(t, err)
+	actualKey, _ := n.ApplyRule(input)
+	assert.Equal(t, "", actualKey, "return key should be empty")
+}
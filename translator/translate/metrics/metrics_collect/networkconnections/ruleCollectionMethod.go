@@ -0,0 +1,23 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package networkconnections
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type CollectionMethod struct {
+}
+
+const SectionKey_CollectionMethod = "collection_method"
+
+func (obj *CollectionMethod) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_CollectionMethod, "auto", input)
+	return
+}
+
+func init() {
+	obj := new(CollectionMethod)
+	RegisterRule(SectionKey_CollectionMethod, obj)
+}
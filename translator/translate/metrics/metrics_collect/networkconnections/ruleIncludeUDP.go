@@ -0,0 +1,23 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package networkconnections
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type IncludeUDP struct {
+}
+
+const SectionKey_IncludeUDP = "include_udp"
+
+func (obj *IncludeUDP) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_IncludeUDP, true, input)
+	return
+}
+
+func init() {
+	obj := new(IncludeUDP)
+	RegisterRule(SectionKey_IncludeUDP, obj)
+}
@@ -0,0 +1,23 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package networkconnections
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type PerDestination struct {
+}
+
+const SectionKey_PerDestination = "per_destination"
+
+func (obj *PerDestination) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_PerDestination, true, input)
+	return
+}
+
+func init() {
+	obj := new(PerDestination)
+	RegisterRule(SectionKey_PerDestination, obj)
+}
@@ -0,0 +1,76 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package networkprobe
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+var ChildRule = map[string]translator.Rule{}
+
+//	"network_probe": {
+//		"measurement": [
+//			"rtt_avg",
+//			"packet_loss"
+//		],
+//		"source": "dc1-agent",
+//		"max_probes_per_second": 10,
+//		"targets": [
+//			{
+//				"name": "dc2-gateway",
+//				"address": "10.1.0.1",
+//				"protocol": "icmp",
+//				"interval": 30,
+//				"count": 5,
+//				"timeout": 2
+//			}
+//		],
+//		"metrics_collection_interval": 60
+//	}
+
+const SectionKey = "network_probe"
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey + "/"
+	return curPath
+}
+
+func RegisterRule(fieldname string, r translator.Rule) {
+	ChildRule[fieldname] = r
+}
+
+type NetworkProbe struct {
+}
+
+func (n *NetworkProbe) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	resArr := []interface{}{}
+	result := map[string]interface{}{}
+	//Check if this plugin exist in the input instance
+	//If not, not process
+	if _, ok := m[SectionKey]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		//Check if there are any config entry with rules applied
+		result = translator.ProcessRuleToApply(m[SectionKey], ChildRule, result)
+		//Process common config, like measurement
+		hasValidMetric := util.ProcessLinuxCommonConfig(m[SectionKey], SectionKey, GetCurPath(), result)
+		if hasValidMetric {
+			resArr = append(resArr, result)
+			returnKey = SectionKey
+			returnVal = resArr
+		} else {
+			returnKey = ""
+		}
+	}
+	return
+}
+
+func init() {
+	n := new(NetworkProbe)
+	parent.RegisterLinuxRule(SectionKey, n)
+}
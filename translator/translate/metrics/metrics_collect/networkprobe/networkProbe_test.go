@@ -0,0 +1,74 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package networkprobe
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	n := new(NetworkProbe)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"network_probe":{"measurement": [
+						"rtt_avg",
+						"packet_loss"
+					],
+					"targets": [{"address": "10.0.0.1"}]}}`), &input)
+	require.NoError(t, err)
+	actualKey, actualVal := n.ApplyRule(input)
+	expectedVal := []interface{}{map[string]interface{}{
+		"max_probes_per_second": float64(10),
+		"target":                []interface{}{map[string]interface{}{"address": "10.0.0.1"}},
+		"fieldpass":             []string{"rtt_avg", "packet_loss"},
+	}}
+	assert.Equal(t, SectionKey, actualKey)
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestFullConfig(t *testing.T) {
+	n := new(NetworkProbe)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"network_probe":{"measurement": [
+						"rtt_min",
+						"rtt_avg",
+						"rtt_max",
+						"packet_loss"
+					],
+					"source": "dc1-agent",
+					"max_probes_per_second": 2.5,
+					"targets": [
+						{"name": "dc2-gateway", "address": "10.1.0.1", "protocol": "icmp", "interval": 30, "count": 5, "timeout": 2},
+						{"address": "example.com", "protocol": "tcp", "port": 443}
+					],
+					"metrics_collection_interval": 120,
+					"append_dimensions": {"name": "sampleName"}
+					}}`), &input)
+	require.NoError(t, err)
+	_, actualVal := n.ApplyRule(input)
+	expectedVal := []interface{}{map[string]interface{}{
+		"source":                "dc1-agent",
+		"max_probes_per_second": 2.5,
+		"target": []interface{}{
+			map[string]interface{}{"name": "dc2-gateway", "address": "10.1.0.1", "protocol": "icmp", "interval": "30s", "count": 5, "timeout": "2s"},
+			map[string]interface{}{"address": "example.com", "protocol": "tcp", "port": 443},
+		},
+		"fieldpass": []string{"rtt_min", "rtt_avg", "rtt_max", "packet_loss"},
+		"interval":  "120s",
+		"tags":      map[string]interface{}{"name": "sampleName"},
+	}}
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestNoFieldConfig(t *testing.T) {
+	n := new(NetworkProbe)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"network_probe":{"targets": [{"address": "10.0.0.1"}]}}`), &input)
+	require.NoError(t, err)
+	actualKey, _ := n.ApplyRule(input)
+	assert.Equal(t, "", actualKey, "return key should be empty")
+}
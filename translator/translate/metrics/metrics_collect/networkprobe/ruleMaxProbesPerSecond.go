@@ -0,0 +1,23 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package networkprobe
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type MaxProbesPerSecond struct {
+}
+
+const SectionKey_MaxProbesPerSecond = "max_probes_per_second"
+
+func (obj *MaxProbesPerSecond) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_MaxProbesPerSecond, float64(10), input)
+	return
+}
+
+func init() {
+	obj := new(MaxProbesPerSecond)
+	RegisterRule(SectionKey_MaxProbesPerSecond, obj)
+}
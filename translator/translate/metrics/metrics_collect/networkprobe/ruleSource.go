@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package networkprobe
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Source struct {
+}
+
+const SectionKey_Source = "source"
+
+func (obj *Source) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if m, ok := input.(map[string]interface{}); ok {
+		if _, ok = m[SectionKey_Source]; ok {
+			return translator.DefaultCase(SectionKey_Source, "", input)
+		}
+	}
+	return
+}
+
+func init() {
+	obj := new(Source)
+	RegisterRule(SectionKey_Source, obj)
+}
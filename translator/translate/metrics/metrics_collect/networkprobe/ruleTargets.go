@@ -0,0 +1,71 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package networkprobe
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Targets struct {
+}
+
+const (
+	SectionKey_Targets = "targets"
+	// SectionMappedKey_Targets is the name of the target tables in the input plugin.
+	SectionMappedKey_Targets = "target"
+
+	targetKeyName     = "name"
+	targetKeyAddress  = "address"
+	targetKeyProtocol = "protocol"
+	targetKeyPort     = "port"
+	targetKeyInterval = "interval"
+	targetKeyCount    = "count"
+	targetKeyTimeout  = "timeout"
+)
+
+// ApplyRule translates each target. The interval and timeout are in seconds and
+// the plugin defaults are used for the keys that are not set.
+func (obj *Targets) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m, ok := input.(map[string]interface{})
+	if !ok {
+		return
+	}
+	targets, ok := m[SectionKey_Targets].([]interface{})
+	if !ok {
+		return
+	}
+	result := make([]interface{}, 0, len(targets))
+	for _, t := range targets {
+		target, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		res := map[string]interface{}{}
+		for _, key := range []string{targetKeyName, targetKeyAddress, targetKeyProtocol} {
+			if _, ok = target[key]; ok {
+				k, v := translator.DefaultCase(key, "", target)
+				res[k] = v
+			}
+		}
+		for _, key := range []string{targetKeyPort, targetKeyCount} {
+			if _, ok = target[key]; ok {
+				k, v := translator.DefaultIntegralCase(key, float64(0), target)
+				res[k] = v
+			}
+		}
+		for _, key := range []string{targetKeyInterval, targetKeyTimeout} {
+			if _, ok = target[key]; ok {
+				k, v := translator.DefaultTimeIntervalCase(key, float64(0), target)
+				res[k] = v
+			}
+		}
+		result = append(result, res)
+	}
+	return SectionMappedKey_Targets, result
+}
+
+func init() {
+	obj := new(Targets)
+	RegisterRule(SectionKey_Targets, obj)
+}
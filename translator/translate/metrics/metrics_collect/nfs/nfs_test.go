@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package nfs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	n := new(NFS)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"nfs":{"measurement": [
+						"retrans",
+						"responsive"
+					]}}`), &input)
+	require.NoError(t, err)
+	actualKey, actualVal := n.ApplyRule(input)
+	expectedVal := []interface{}{map[string]interface{}{
+		"fieldpass": []string{"retrans", "responsive"},
+	}}
+	assert.Equal(t, SectionKey, actualKey)
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestFullConfig(t *testing.T) {
+	n := new(NFS)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"nfs":{"measurement": [
+						"retrans",
+						"read_latency",
+						"stale"
+					],
+					"mount_points": ["/mnt/efs*"],
+					"probe_timeout": 10,
+					"metrics_collection_interval": 120,
+					"append_dimensions": {"cluster": "shared"}
+					}}`), &input)
+	require.NoError(t, err)
+	_, actualVal := n.ApplyRule(input)
+	expectedVal := []interface{}{map[string]interface{}{
+		"mount_points":  []interface{}{"/mnt/efs*"},
+		"probe_timeout": "10s",
+		"fieldpass":     []string{"retrans", "read_latency", "stale"},
+		"interval":      "120s",
+		"tags":          map[string]interface{}{"cluster": "shared"},
+	}}
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestNoFieldConfig(t *testing.T) {
+	n := new(NFS)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"nfs":{"metrics_collection_interval":60}}`), &input)
+	require.NoError(t, err)
+	actualKey, _ := n.ApplyRule(input)
+	assert.Equal(t, "", actualKey, "return key should be empty")
+}
@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package nfs
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type MountPoints struct {
+}
+
+const SectionKey_MountPoints = "mount_points"
+
+// ApplyRule passes the mount point globs through, all the NFS mounts are collected when it is not set.
+func (obj *MountPoints) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m, ok := input.(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	if _, ok = m[SectionKey_MountPoints]; !ok {
+		return "", nil
+	}
+	return translator.DefaultCase(SectionKey_MountPoints, []string{}, input)
+}
+
+func init() {
+	obj := new(MountPoints)
+	RegisterRule(SectionKey_MountPoints, obj)
+}
@@ -0,0 +1,29 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package nfs
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type ProbeTimeout struct {
+}
+
+const SectionKey_ProbeTimeout = "probe_timeout"
+
+// ApplyRule translates the timeout in seconds of the probe of each mount
+// point. The plugin default is used if it is not set.
+func (obj *ProbeTimeout) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if m, ok := input.(map[string]interface{}); ok {
+		if _, ok = m[SectionKey_ProbeTimeout]; ok {
+			return translator.DefaultTimeIntervalCase(SectionKey_ProbeTimeout, float64(0), input)
+		}
+	}
+	return
+}
+
+func init() {
+	obj := new(ProbeTimeout)
+	RegisterRule(SectionKey_ProbeTimeout, obj)
+}
@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstat
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/hash"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/procstatheartbeat"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+const (
+	ExpectRunningKey        = "expect_running"
+	FlapSuppressionCountKey = "flap_suppression_count"
+
+	runningField = "running"
+	pidField     = "pid"
+	fieldPassKey = "fieldpass"
+)
+
+// MonitoredProcess returns the pid_file, exe, pattern, systemd_unit or
+// win_service of the process config, in that order of precedence.
+func MonitoredProcess(processConfig map[string]interface{}) string {
+	for _, key := range []string{PidFileKey, ExeKey, PatternKey, SystemdUnitKey, WinServiceKey} {
+		if val, ok := processConfig[key].(string); ok {
+			return val
+		}
+	}
+	return ""
+}
+
+// HeartbeatID returns the ID used by the procstatheartbeat processor to
+// identify the metrics of the process config. Returns an empty string if
+// the process is not expected to be running.
+func HeartbeatID(processConfig map[string]interface{}) string {
+	if expect, ok := processConfig[ExpectRunningKey].(bool); !ok || !expect {
+		return ""
+	}
+	monitored := MonitoredProcess(processConfig)
+	if monitored == "" {
+		return ""
+	}
+	return hash.HashName(monitored)
+}
+
+// applyExpectRunning makes sure the running and pid fields are collected and
+// tags the metrics of the process so that the procstatheartbeat processor
+// can find them. The lookup metrics do not have the pid_file, exe or pattern
+// tags when the lookup fails, so those cannot be used to identify them.
+func applyExpectRunning(processConfig map[string]interface{}, result map[string]interface{}) {
+	id := HeartbeatID(processConfig)
+	if id == "" {
+		return
+	}
+	fieldPass, _ := result[fieldPassKey].([]string)
+	for _, field := range []string{runningField, pidField} {
+		if !util.ListContains(fieldPass, field) {
+			fieldPass = append(fieldPass, field)
+		}
+	}
+	result[fieldPassKey] = fieldPass
+	tags, ok := result[util.Append_Dimensions_Mapped_Key].(map[string]interface{})
+	if !ok {
+		tags = map[string]interface{}{}
+		result[util.Append_Dimensions_Mapped_Key] = tags
+	}
+	tags[procstatheartbeat.IDAttributeKey] = id
+}
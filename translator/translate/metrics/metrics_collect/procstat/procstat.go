@@ -57,15 +57,10 @@ func (p *Procstat) ApplyRule(input interface{}) (returnKey string, returnVal int
 		according to the public documents if multiple configuration is specified
 		https://docs.aws.amazon.com/AmazonCloudWatch/latest/monitoring/CloudWatch-Agent-procstat-process-metrics.html#CloudWatch-Agent-procstat-configuration
 		*/
-		for _, procstatMonitored := range []string{PatternKey, ExeKey, PidFileKey} {
-			for _, rule := range ChildRule {
-				if key, val := rule.ApplyRule(processConfig); key != "" && key == procstatMonitored {
-					result[util.Alias_Key] = hash.HashName(val.(string))
-					break
-				}
-			}
-
+		if monitored := MonitoredProcess(processConfig.(map[string]interface{})); monitored != "" {
+			result[util.Alias_Key] = hash.HashName(monitored)
 		}
+		applyExpectRunning(processConfig.(map[string]interface{}), result)
 		resArray = append(resArray, result)
 	}
 
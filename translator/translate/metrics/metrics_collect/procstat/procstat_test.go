@@ -83,6 +83,46 @@ func TestPatternConfig(t *testing.T) {
 	}}
 	checkResult(t, input, expectedVal)
 }
+func TestSystemdUnitConfig(t *testing.T) {
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": [
+		{"name": "cpu_usage", "rename": "cwagent_cpu_usage", "unit": "Percent"},
+		{"name": "memory_rss", "rename": "cwagent_mem_usage", "unit": "Bytes"}
+	    ],
+	    "systemd_unit": "nginx.service"
+	}
+      ]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"systemd_unit": "nginx.service",
+		"alias":        hash.HashName("nginx.service"),
+		"pid_finder":   "native",
+		"fieldpass":    []string{"cpu_usage", "memory_rss"},
+		"tagexclude":   []string{"user", "result"},
+	}}
+	checkResult(t, input, expectedVal)
+}
+
+func TestWinServiceConfig(t *testing.T) {
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": [
+		{"name": "cpu_usage", "rename": "cwagent_cpu_usage", "unit": "Percent"},
+		{"name": "memory_rss", "rename": "cwagent_mem_usage", "unit": "Bytes"}
+	    ],
+	    "win_service": "W3SVC"
+	}
+      ]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"win_service": "W3SVC",
+		"alias":       hash.HashName("W3SVC"),
+		"pid_finder":  "native",
+		"fieldpass":   []string{"cpu_usage", "memory_rss"},
+		"tagexclude":  []string{"user", "result"},
+	}}
+	checkResult(t, input, expectedVal)
+}
+
 func TestMemorySwapConfig(t *testing.T) {
 	input := []byte(`{
 		"procstat": [
@@ -215,3 +255,38 @@ func TestIntervalErrorConfig(t *testing.T) {
 	}}
 	checkResult(t, input, expectedVal)
 }
+
+func TestExpectRunningConfig(t *testing.T) {
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": ["cpu_usage", "pid"],
+	    "append_dimensions": {"service": "ssh"},
+	    "expect_running": true,
+	    "flap_suppression_count": 3,
+	    "pattern": "sshd"
+	},
+	{
+	    "measurement": ["cpu_usage"],
+	    "expect_running": false,
+	    "exe": "cloudwatch"
+	}
+      ]}`)
+	expectedVal := []interface{}{
+		map[string]interface{}{
+			"pattern":    "sshd",
+			"alias":      hash.HashName("sshd"),
+			"pid_finder": "native",
+			"fieldpass":  []string{"cpu_usage", "pid", "running"},
+			"tags":       map[string]interface{}{"service": "ssh", "procstat_heartbeat_id": hash.HashName("sshd")},
+			"tagexclude": []string{"user", "result"},
+		},
+		map[string]interface{}{
+			"exe":        "cloudwatch",
+			"alias":      hash.HashName("cloudwatch"),
+			"pid_finder": "native",
+			"fieldpass":  []string{"cpu_usage"},
+			"tagexclude": []string{"user", "result"},
+		},
+	}
+	checkResult(t, input, expectedVal)
+}
@@ -0,0 +1,25 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstat
+
+type SystemdUnit struct{}
+
+const SystemdUnitKey = "systemd_unit"
+
+func (t *SystemdUnit) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	if _, ok := m[SystemdUnitKey]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		returnKey = SystemdUnitKey
+		returnVal = m[SystemdUnitKey]
+	}
+	return
+}
+
+func init() {
+	u := new(SystemdUnit)
+	RegisterRule(SystemdUnitKey, u)
+}
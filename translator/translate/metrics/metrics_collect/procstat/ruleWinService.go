@@ -0,0 +1,25 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstat
+
+type WinService struct{}
+
+const WinServiceKey = "win_service"
+
+func (t *WinService) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	if _, ok := m[WinServiceKey]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		returnKey = WinServiceKey
+		returnVal = m[WinServiceKey]
+	}
+	return
+}
+
+func init() {
+	u := new(WinService)
+	RegisterRule(WinServiceKey, u)
+}
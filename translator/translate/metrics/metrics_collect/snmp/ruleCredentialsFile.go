@@ -0,0 +1,29 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package snmp
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type CredentialsFile struct {
+}
+
+// SectionKey_CredentialsFile is the INI file with the community or the v3
+// credentials of each target.
+const SectionKey_CredentialsFile = "credentials_file"
+
+func (obj *CredentialsFile) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if m, ok := input.(map[string]interface{}); ok {
+		if _, ok = m[SectionKey_CredentialsFile]; ok {
+			return translator.DefaultCase(SectionKey_CredentialsFile, "", input)
+		}
+	}
+	return
+}
+
+func init() {
+	obj := new(CredentialsFile)
+	RegisterRule(SectionKey_CredentialsFile, obj)
+}
@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package snmp
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Fields struct {
+}
+
+const (
+	SectionKey_Fields = "fields"
+	// SectionMappedKey_Fields is the name of the field tables in the input plugin.
+	SectionMappedKey_Fields = "field"
+
+	fieldKeyName        = "name"
+	fieldKeyOID         = "oid"
+	fieldKeyConversion  = "conversion"
+	fieldKeyIsDimension = "is_dimension"
+	fieldKeyIsTag       = "is_tag"
+)
+
+// ApplyRule translates the scalar OIDs that are polled with a get request.
+func (obj *Fields) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m, ok := input.(map[string]interface{})
+	if !ok {
+		return
+	}
+	fields, ok := m[SectionKey_Fields].([]interface{})
+	if !ok {
+		return
+	}
+	return SectionMappedKey_Fields, translateFields(fields)
+}
+
+// translateFields maps the OIDs to the metric names. The fields that are
+// dimensions are added as tags to the metrics of the same target or row.
+func translateFields(fields []interface{}) []interface{} {
+	result := make([]interface{}, 0, len(fields))
+	for _, f := range fields {
+		field, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		res := map[string]interface{}{}
+		for _, key := range []string{fieldKeyName, fieldKeyOID, fieldKeyConversion} {
+			if _, ok = field[key]; ok {
+				k, v := translator.DefaultCase(key, "", field)
+				res[k] = v
+			}
+		}
+		if _, ok = field[fieldKeyIsDimension]; ok {
+			_, v := translator.DefaultCase(fieldKeyIsDimension, false, field)
+			res[fieldKeyIsTag] = v
+		}
+		result = append(result, res)
+	}
+	return result
+}
+
+func init() {
+	obj := new(Fields)
+	RegisterRule(SectionKey_Fields, obj)
+}
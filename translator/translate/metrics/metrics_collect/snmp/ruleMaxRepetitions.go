@@ -0,0 +1,29 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package snmp
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type MaxRepetitions struct {
+}
+
+// SectionKey_MaxRepetitions is the number of rows requested at once when the
+// tables are walked.
+const SectionKey_MaxRepetitions = "max_repetitions"
+
+func (obj *MaxRepetitions) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if m, ok := input.(map[string]interface{}); ok {
+		if _, ok = m[SectionKey_MaxRepetitions]; ok {
+			return translator.DefaultIntegralCase(SectionKey_MaxRepetitions, float64(0), input)
+		}
+	}
+	return
+}
+
+func init() {
+	obj := new(MaxRepetitions)
+	RegisterRule(SectionKey_MaxRepetitions, obj)
+}
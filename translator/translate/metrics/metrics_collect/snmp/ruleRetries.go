@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package snmp
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Retries struct {
+}
+
+const SectionKey_Retries = "retries"
+
+func (obj *Retries) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if m, ok := input.(map[string]interface{}); ok {
+		if _, ok = m[SectionKey_Retries]; ok {
+			return translator.DefaultIntegralCase(SectionKey_Retries, float64(0), input)
+		}
+	}
+	return
+}
+
+func init() {
+	obj := new(Retries)
+	RegisterRule(SectionKey_Retries, obj)
+}
@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package snmp
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Tables struct {
+}
+
+const (
+	SectionKey_Tables = "tables"
+	// SectionMappedKey_Tables is the name of the table tables in the input plugin.
+	SectionMappedKey_Tables = "table"
+
+	tableKeyName             = "name"
+	tableKeyOID              = "oid"
+	tableKeyIndexAsDimension = "index_as_dimension"
+	tableKeyIndexAsTag       = "index_as_tag"
+)
+
+// ApplyRule translates the tables that are walked. Each row is a metric named
+// after the table, with the fields of the table.
+func (obj *Tables) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m, ok := input.(map[string]interface{})
+	if !ok {
+		return
+	}
+	tables, ok := m[SectionKey_Tables].([]interface{})
+	if !ok {
+		return
+	}
+	result := make([]interface{}, 0, len(tables))
+	for _, t := range tables {
+		table, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		res := map[string]interface{}{}
+		for _, key := range []string{tableKeyName, tableKeyOID} {
+			if _, ok = table[key]; ok {
+				k, v := translator.DefaultCase(key, "", table)
+				res[k] = v
+			}
+		}
+		if _, ok = table[tableKeyIndexAsDimension]; ok {
+			_, v := translator.DefaultCase(tableKeyIndexAsDimension, false, table)
+			res[tableKeyIndexAsTag] = v
+		}
+		if fields, ok := table[SectionKey_Fields].([]interface{}); ok {
+			res[SectionMappedKey_Fields] = translateFields(fields)
+		}
+		result = append(result, res)
+	}
+	return SectionMappedKey_Tables, result
+}
+
+func init() {
+	obj := new(Tables)
+	RegisterRule(SectionKey_Tables, obj)
+}
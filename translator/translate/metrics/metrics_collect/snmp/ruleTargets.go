@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package snmp
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Targets struct {
+}
+
+const (
+	SectionKey_Targets = "targets"
+	// SectionMappedKey_Targets is the name of the target tables in the input plugin.
+	SectionMappedKey_Targets = "target"
+
+	targetKeyAddress = "address"
+	targetKeyVersion = "version"
+)
+
+// ApplyRule translates each target. The credentials of the targets are read
+// from the credentials file by the plugin.
+func (obj *Targets) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m, ok := input.(map[string]interface{})
+	if !ok {
+		return
+	}
+	targets, ok := m[SectionKey_Targets].([]interface{})
+	if !ok {
+		return
+	}
+	result := make([]interface{}, 0, len(targets))
+	for _, t := range targets {
+		target, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		res := map[string]interface{}{}
+		if _, ok = target[targetKeyAddress]; ok {
+			k, v := translator.DefaultCase(targetKeyAddress, "", target)
+			res[k] = v
+		}
+		if _, ok = target[targetKeyVersion]; ok {
+			k, v := translator.DefaultIntegralCase(targetKeyVersion, float64(0), target)
+			res[k] = v
+		}
+		result = append(result, res)
+	}
+	return SectionMappedKey_Targets, result
+}
+
+func init() {
+	obj := new(Targets)
+	RegisterRule(SectionKey_Targets, obj)
+}
@@ -0,0 +1,89 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package snmp
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+var ChildRule = map[string]translator.Rule{}
+
+//	"snmp": {
+//		"credentials_file": "/opt/aws/amazon-cloudwatch-agent/etc/snmp_credentials",
+//		"targets": [
+//			{
+//				"address": "udp://10.0.0.1:161",
+//				"version": 3
+//			}
+//		],
+//		"timeout": 5,
+//		"retries": 3,
+//		"fields": [
+//			{
+//				"name": "uptime",
+//				"oid": "1.3.6.1.2.1.1.3.0"
+//			}
+//		],
+//		"tables": [
+//			{
+//				"name": "interface",
+//				"index_as_dimension": true,
+//				"fields": [
+//					{
+//						"name": "ifDescr",
+//						"oid": "1.3.6.1.2.1.2.2.1.2",
+//						"is_dimension": true
+//					},
+//					{
+//						"name": "ifInOctets",
+//						"oid": "1.3.6.1.2.1.2.2.1.10"
+//					}
+//				]
+//			}
+//		],
+//		"metrics_collection_interval": 60
+//	}
+
+const SectionKey = "snmp"
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey + "/"
+	return curPath
+}
+
+func RegisterRule(fieldname string, r translator.Rule) {
+	ChildRule[fieldname] = r
+}
+
+type SNMP struct {
+}
+
+func (s *SNMP) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	resArr := []interface{}{}
+	result := map[string]interface{}{}
+	//Check if this plugin exist in the input instance
+	//If not, not process
+	if _, ok := m[SectionKey]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		//If exists, process it
+		//Check if there are some config entry with rules applied
+		result = translator.ProcessRuleToApply(m[SectionKey], ChildRule, result)
+		resArr = append(resArr, result)
+		returnKey = SectionKey
+		returnVal = resArr
+		//Process tags
+		util.ProcessAppendDimensions(m[SectionKey].(map[string]interface{}), SectionKey, result)
+	}
+	return
+}
+
+func init() {
+	s := new(SNMP)
+	parent.RegisterLinuxRule(SectionKey, s)
+}
@@ -0,0 +1,97 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package snmp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	s := new(SNMP)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"snmp":{
+					"targets": [{"address": "10.0.0.1"}],
+					"fields": [{"name": "uptime", "oid": "1.3.6.1.2.1.1.3.0"}]}}`), &input)
+	require.NoError(t, err)
+	actualKey, actualVal := s.ApplyRule(input)
+	expectedVal := []interface{}{map[string]interface{}{
+		"target": []interface{}{map[string]interface{}{"address": "10.0.0.1"}},
+		"field":  []interface{}{map[string]interface{}{"name": "uptime", "oid": "1.3.6.1.2.1.1.3.0"}},
+	}}
+	assert.Equal(t, SectionKey, actualKey)
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestFullConfig(t *testing.T) {
+	s := new(SNMP)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"snmp":{
+					"credentials_file": "/etc/snmp_credentials",
+					"targets": [
+						{"address": "udp://10.0.0.1:161", "version": 3},
+						{"address": "10.0.0.2", "version": 2}
+					],
+					"timeout": 2,
+					"retries": 1,
+					"max_repetitions": 20,
+					"fields": [
+						{"name": "sysName", "oid": ".1.3.6.1.2.1.1.5.0", "is_dimension": true},
+						{"name": "uptime", "oid": "1.3.6.1.2.1.1.3.0"}
+					],
+					"tables": [{
+						"name": "interface",
+						"index_as_dimension": true,
+						"fields": [
+							{"name": "ifDescr", "oid": "1.3.6.1.2.1.2.2.1.2", "is_dimension": true},
+							{"name": "ifPhysAddress", "oid": "1.3.6.1.2.1.2.2.1.6", "conversion": "hwaddr", "is_dimension": true},
+							{"name": "ifInOctets", "oid": "1.3.6.1.2.1.2.2.1.10"}
+						]
+					}],
+					"metrics_collection_interval": 120,
+					"append_dimensions": {"name": "sampleName"}
+					}}`), &input)
+	require.NoError(t, err)
+	_, actualVal := s.ApplyRule(input)
+	expectedVal := []interface{}{map[string]interface{}{
+		"credentials_file": "/etc/snmp_credentials",
+		"target": []interface{}{
+			map[string]interface{}{"address": "udp://10.0.0.1:161", "version": 3},
+			map[string]interface{}{"address": "10.0.0.2", "version": 2},
+		},
+		"timeout":         "2s",
+		"retries":         1,
+		"max_repetitions": 20,
+		"field": []interface{}{
+			map[string]interface{}{"name": "sysName", "oid": ".1.3.6.1.2.1.1.5.0", "is_tag": true},
+			map[string]interface{}{"name": "uptime", "oid": "1.3.6.1.2.1.1.3.0"},
+		},
+		"table": []interface{}{
+			map[string]interface{}{
+				"name":         "interface",
+				"index_as_tag": true,
+				"field": []interface{}{
+					map[string]interface{}{"name": "ifDescr", "oid": "1.3.6.1.2.1.2.2.1.2", "is_tag": true},
+					map[string]interface{}{"name": "ifPhysAddress", "oid": "1.3.6.1.2.1.2.2.1.6", "conversion": "hwaddr", "is_tag": true},
+					map[string]interface{}{"name": "ifInOctets", "oid": "1.3.6.1.2.1.2.2.1.10"},
+				},
+			},
+		},
+		"interval": "120s",
+		"tags":     map[string]interface{}{"name": "sampleName"},
+	}}
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestNoConfig(t *testing.T) {
+	s := new(SNMP)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"cpu":{}}`), &input)
+	require.NoError(t, err)
+	actualKey, _ := s.ApplyRule(input)
+	assert.Equal(t, "", actualKey, "return key should be empty")
+}
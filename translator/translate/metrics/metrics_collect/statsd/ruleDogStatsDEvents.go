@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+type DogStatsDEvents struct {
+}
+
+// SectionKey_DogStatsDEvents is the log destination of the dogstatsd events,
+// see StatsDEvents.
+const SectionKey_DogStatsDEvents = "dogstatsd_events"
+
+// ApplyRule enables the events in the statsd plugin.
+func (obj *DogStatsDEvents) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if m, ok := input.(map[string]interface{}); ok {
+		if _, ok = m[SectionKey_DogStatsDEvents].(map[string]interface{}); ok {
+			return SectionKey_DogStatsDEvents, true
+		}
+	}
+	return
+}
+
+func init() {
+	obj := new(DogStatsDEvents)
+	RegisterRule(SectionKey_DogStatsDEvents, obj)
+}
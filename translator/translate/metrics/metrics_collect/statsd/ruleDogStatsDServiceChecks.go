@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type DogStatsDServiceChecks struct {
+}
+
+const SectionKey_DogStatsDServiceChecks = "dogstatsd_service_checks"
+
+func (obj *DogStatsDServiceChecks) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	key, val := translator.DefaultCase(SectionKey_DogStatsDServiceChecks, false, input)
+	if val == true {
+		return key, val
+	}
+	return
+}
+
+func init() {
+	obj := new(DogStatsDServiceChecks)
+	RegisterRule(SectionKey_DogStatsDServiceChecks, obj)
+}
@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type EphemeralGraceWindow struct {
+}
+
+const SectionKey_EphemeralGraceWindow = "ephemeral_grace_window"
+
+func (obj *EphemeralGraceWindow) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if m, ok := input.(map[string]interface{}); ok {
+		if _, ok = m[SectionKey_EphemeralGraceWindow]; ok {
+			return translator.DefaultTimeIntervalCase(SectionKey_EphemeralGraceWindow, float64(0), input)
+		}
+	}
+	return
+}
+
+func init() {
+	obj := new(EphemeralGraceWindow)
+	RegisterRule(SectionKey_EphemeralGraceWindow, obj)
+}
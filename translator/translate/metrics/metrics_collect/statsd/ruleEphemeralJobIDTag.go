@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type EphemeralJobIDTag struct {
+}
+
+const SectionKey_EphemeralJobIDTag = "ephemeral_job_id_tag"
+
+func (obj *EphemeralJobIDTag) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	key, val := translator.DefaultCase(SectionKey_EphemeralJobIDTag, "", input)
+	if val != "" {
+		return key, val
+	}
+	return
+}
+
+func init() {
+	obj := new(EphemeralJobIDTag)
+	RegisterRule(SectionKey_EphemeralJobIDTag, obj)
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type EphemeralSourcePorts struct {
+}
+
+const SectionKey_EphemeralSourcePorts = "ephemeral_source_ports"
+
+func (obj *EphemeralSourcePorts) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	key, val := translator.DefaultCase(SectionKey_EphemeralSourcePorts, false, input)
+	if val == true {
+		return key, val
+	}
+	return
+}
+
+func init() {
+	obj := new(EphemeralSourcePorts)
+	RegisterRule(SectionKey_EphemeralSourcePorts, obj)
+}
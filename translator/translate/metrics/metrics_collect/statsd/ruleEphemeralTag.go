@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type EphemeralTag struct {
+}
+
+const SectionKey_EphemeralTag = "ephemeral_tag"
+
+func (obj *EphemeralTag) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	key, val := translator.DefaultCase(SectionKey_EphemeralTag, "", input)
+	if val != "" {
+		return key, val
+	}
+	return
+}
+
+func init() {
+	obj := new(EphemeralTag)
+	RegisterRule(SectionKey_EphemeralTag, obj)
+}
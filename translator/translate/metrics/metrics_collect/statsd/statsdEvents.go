@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/util"
+)
+
+// EventsSectionKey is the plugin publishing the dogstatsd events received by
+// the statsd plugin to CloudWatch Logs. It is run by the logs agent, so the
+// events are only published if the logs section is configured.
+//
+//	"statsd" : {
+//	    "dogstatsd_events": {
+//	        "log_group_name": "statsd-events",
+//	        "log_stream_name": "{instance_id}"
+//	    }
+//	}
+const EventsSectionKey = "statsd_events"
+
+const (
+	logGroupNameKey        = "log_group_name"
+	logStreamNameKey       = "log_stream_name"
+	logGroupClassKey       = "log_group_class"
+	retentionInDaysKey     = "retention_in_days"
+	defaultEventsLogStream = "{instance_id}"
+	defaultEventsLogGroup  = "statsd-events"
+	eventsDestination      = "cloudwatchlogs"
+	eventsDestinationKey   = "destination"
+)
+
+type StatsDEvents struct {
+}
+
+func (obj *StatsDEvents) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m, _ := input.(map[string]interface{})
+	statsd, _ := m[SectionKey].(map[string]interface{})
+	events, ok := statsd[SectionKey_DogStatsDEvents].(map[string]interface{})
+	if !ok {
+		return "", ""
+	}
+	metadata := util.GetMetadataInfo(util.Ec2MetadataInfoProvider)
+	result := map[string]interface{}{
+		eventsDestinationKey: eventsDestination,
+	}
+	_, logGroup := translator.DefaultCase(logGroupNameKey, defaultEventsLogGroup, events)
+	result[logGroupNameKey] = util.ResolvePlaceholder(logGroup.(string), metadata)
+	_, logStream := translator.DefaultCase(logStreamNameKey, defaultEventsLogStream, events)
+	result[logStreamNameKey] = util.ResolvePlaceholder(logStream.(string), metadata)
+	_, result[logGroupClassKey] = translator.DefaultLogGroupClassCase(logGroupClassKey, "", events)
+	_, result[retentionInDaysKey] = translator.DefaultRetentionInDaysCase(retentionInDaysKey, float64(-1), events)
+	return EventsSectionKey, []interface{}{result}
+}
+
+func init() {
+	obj := new(StatsDEvents)
+	parent.RegisterLinuxRule(EventsSectionKey, obj)
+	parent.RegisterDarwinRule(EventsSectionKey, obj)
+	parent.RegisterWindowsRule(EventsSectionKey, obj)
+}
@@ -100,3 +100,88 @@ func TestStatsD_MetricSeparator(t *testing.T) {
 
 	assert.Equal(t, expect, actual)
 }
+
+func TestStatsD_EphemeralSources(t *testing.T) {
+	obj := new(StatsD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"statsd": {
+					"ephemeral_tag": "ephemeral",
+					"ephemeral_source_ports": true,
+					"ephemeral_job_id_tag": "job_id",
+					"ephemeral_grace_window": 5
+					}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := obj.ApplyRule(input)
+
+	expect := []interface{}{
+		map[string]interface{}{
+			"service_address":        ":8125",
+			"interval":               "10s",
+			"parse_data_dog_tags":    true,
+			"tags":                   map[string]interface{}{"aws:AggregationInterval": "60s"},
+			"ephemeral_tag":          "ephemeral",
+			"ephemeral_source_ports": true,
+			"ephemeral_job_id_tag":   "job_id",
+			"ephemeral_grace_window": "5s",
+		},
+	}
+
+	assert.Equal(t, expect, actual)
+}
+
+func TestStatsD_DogStatsD(t *testing.T) {
+	obj := new(StatsD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"statsd": {
+					"dogstatsd_events": {},
+					"dogstatsd_service_checks": true
+					}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := obj.ApplyRule(input)
+
+	expect := []interface{}{
+		map[string]interface{}{
+			"service_address":          ":8125",
+			"interval":                 "10s",
+			"parse_data_dog_tags":      true,
+			"dogstatsd_events":         true,
+			"dogstatsd_service_checks": true,
+			"tags":                     map[string]interface{}{"aws:AggregationInterval": "60s"},
+		},
+	}
+
+	assert.Equal(t, expect, actual)
+}
+
+func TestStatsDEvents(t *testing.T) {
+	obj := new(StatsDEvents)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"statsd": {
+					"dogstatsd_events": {
+						"log_group_name": "app-events",
+						"log_stream_name": "events",
+						"retention_in_days": 7
+					}
+					}}`), &input)
+	assert.NoError(t, err)
+
+	key, actual := obj.ApplyRule(input)
+
+	assert.Equal(t, "statsd_events", key)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"destination":       "cloudwatchlogs",
+			"log_group_name":    "app-events",
+			"log_stream_name":   "events",
+			"log_group_class":   "",
+			"retention_in_days": 7,
+		},
+	}, actual)
+
+	err = json.Unmarshal([]byte(`{"statsd": {}}`), &input)
+	assert.NoError(t, err)
+	key, _ = obj.ApplyRule(input)
+	assert.Equal(t, "", key)
+}
@@ -25,6 +25,8 @@ const (
 	Windows_Measurement_Key      = "Measurement"
 	Windows_WarnOnMissing_Key    = "WarnOnMissing"
 	Windows_Disable_Replacer_Key = "DisableReplacer"
+	Refresh_Interval_Key         = "counters_refresh_interval"
+	Windows_Refresh_Interval_Key = "CountersRefreshInterval"
 )
 
 // ProcessLinuxCommonConfig is used by both Linux and Darwin.
@@ -80,6 +82,7 @@ func ProcessAppendDimensions(inputMap map[string]interface{}, pluginName string,
 // 1. interval: Collect_Interval_Mapped_Key
 // 2. tags: Append_Dimensions_Mapped_Key
 // 3. object config
+// and the refresh interval of the wildcard instances if configured.
 func ProcessWindowsCommonConfig(input interface{}, pluginName string, path string) (returnVal map[string]interface{}) {
 	inputMap := input.(map[string]interface{})
 	objectConfig := map[string]interface{}{}
@@ -91,6 +94,17 @@ func ProcessWindowsCommonConfig(input interface{}, pluginName string, path strin
 	// 1. Set input plugin specific interval
 	isHighRsolution = setTimeInterval(inputMap, returnVal, isHighRsolution, pluginName)
 
+	// Set the interval after which the wildcard instances are expanded again
+	if val, ok := inputMap[Refresh_Interval_Key]; ok {
+		if floatVal, ok := val.(float64); ok {
+			returnVal[Windows_Refresh_Interval_Key] = fmt.Sprintf("%ds", int(floatVal))
+		} else {
+			translator.AddErrorMessages(
+				fmt.Sprintf("metrics plugin %s", pluginName),
+				fmt.Sprintf("counters_refresh_interval value (%v) in json is not valid for time interval.", val))
+		}
+	}
+
 	// 2. Set append_dimensions as tags
 	if val, ok := inputMap[Append_Dimensions_Key]; ok {
 		returnVal[Append_Dimensions_Mapped_Key] = val
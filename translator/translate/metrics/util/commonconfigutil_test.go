@@ -61,3 +61,24 @@ func TestProcessLinuxCommonConfigHappy(t *testing.T) {
 		panic(err)
 	}
 }
+
+func TestProcessWindowsCommonConfigRefreshInterval(t *testing.T) {
+	var input interface{}
+	err := json.Unmarshal([]byte(`{
+					"resources": [
+						"*"
+					],
+					"measurement": [
+						"% Free Space"
+					],
+					"counters_refresh_interval": 300
+				}`), &input)
+	if err == nil {
+		actualResult := ProcessWindowsCommonConfig(input, "LogicalDisk", "")
+		assert.Equal(t, "300s", actualResult["CountersRefreshInterval"])
+		objects := actualResult["object"].([]interface{})
+		assert.Equal(t, []string{"*"}, objects[0].(map[string]interface{})["Instances"])
+	} else {
+		panic(err)
+	}
+}
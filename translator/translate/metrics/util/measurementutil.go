@@ -22,6 +22,9 @@ const (
 	measurement_category    = "category"
 	measurement_rename      = "rename"
 	measurement_unit        = "unit"
+	// measurement_aggregation_interval is translated to the aggregation
+	// interval processor instead of the metric decoration.
+	measurement_aggregation_interval = "aggregation_interval"
 )
 
 const (
@@ -102,6 +105,7 @@ func ApplyMeasurementRuleForMetricDecoration(inputs interface{}, pluginName stri
 					fallthrough
 				case measurement_unit:
 					decorationMap[k] = strings.TrimSpace(v.(string))
+				case measurement_aggregation_interval:
 				default:
 					fmt.Printf("Warning, detect unexpected field in measurement: %v", k)
 				}
@@ -0,0 +1,29 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+// ListenAddress returns the host:port of a service address in the format
+// expected by telegraf, e.g. udp://:25888, udp:0.0.0.0:25888 or
+// udp://[::1]:25888. The IPv6 hosts are in brackets. The address without a
+// host listens on all the IPv4 and IPv6 addresses.
+func ListenAddress(serviceAddress string) (string, error) {
+	_, address, ok := strings.Cut(serviceAddress, ":")
+	if !ok {
+		return "", errors.New("invalid service split")
+	}
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(address, "//"))
+	if err != nil {
+		return "", errors.New("invalid service split")
+	}
+	if host == "" {
+		host = "0.0.0.0"
+	}
+	return net.JoinHostPort(host, port), nil
+}
@@ -0,0 +1,38 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListenAddress(t *testing.T) {
+	testCases := map[string]struct {
+		serviceAddress string
+		want           string
+		wantErr        bool
+	}{
+		"WithHost":          {serviceAddress: "udp:0.0.0.0:25888", want: "0.0.0.0:25888"},
+		"WithDoubleSlash":   {serviceAddress: "udp://localhost:25888", want: "localhost:25888"},
+		"WithoutHost":       {serviceAddress: "tcp://:25888", want: "0.0.0.0:25888"},
+		"WithIPv6Host":      {serviceAddress: "udp://[::1]:25888", want: "[::1]:25888"},
+		"WithIPv6Wildcard":  {serviceAddress: "tcp:[::]:25888", want: "[::]:25888"},
+		"WithoutPort":       {serviceAddress: "udp://localhost", wantErr: true},
+		"WithUnbracketedV6": {serviceAddress: "udp://::1:25888", wantErr: true},
+		"WithoutNetwork":    {serviceAddress: "localhost", wantErr: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ListenAddress(testCase.serviceAddress)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
@@ -26,6 +26,8 @@ const (
 	LogsCollectedKey                   = "logs_collected"
 	TracesCollectedKey                 = "traces_collected"
 	MetricsDestinationsKey             = "metrics_destinations"
+	TracesDestinationsKey              = "traces_destinations"
+	AggregationTemporalityKey          = "aggregation_temporality"
 	ECSKey                             = "ecs"
 	KubernetesKey                      = "kubernetes"
 	CloudWatchKey                      = "cloudwatch"
@@ -33,6 +35,8 @@ const (
 	PrometheusKey                      = "prometheus"
 	PrometheusConfigPathKey            = "prometheus_config_path"
 	AMPKey                             = "amp"
+	TextfileKey                        = "textfile"
+	AlertsKey                          = "alerts"
 	WorkspaceIDKey                     = "workspace_id"
 	EMFProcessorKey                    = "emf_processor"
 	DisableMetricExtraction            = "disable_metric_extraction"
@@ -42,12 +46,16 @@ const (
 	TLSKey                             = "tls"
 	Endpoint                           = "endpoint"
 	EndpointOverrideKey                = "endpoint_override"
+	SigV4aRegionSetKey                 = "sigv4a_region_set"
 	RegionOverrideKey                  = "region_override"
 	ProxyOverrideKey                   = "proxy_override"
+	ProxyKey                           = "proxy"
+	HttpsProxyKey                      = "https_proxy"
 	InsecureKey                        = "insecure"
 	LocalModeKey                       = "local_mode"
 	CredentialsKey                     = "credentials"
 	RoleARNKey                         = "role_arn"
+	EMFDestinationKey                  = "emf_destination"
 	SigV4Auth                          = "sigv4auth"
 	MetricsCollectionIntervalKey       = "metrics_collection_interval"
 	AggregationDimensionsKey           = "aggregation_dimensions"
@@ -59,11 +67,17 @@ const (
 	PreferFullPodName                  = "prefer_full_pod_name"
 	EnableAcceleratedComputeMetric     = "accelerated_compute_metrics"
 	EnableKueueContainerInsights       = "kueue_container_insights"
+	EnableNodeHealthMetrics            = "node_health_metrics"
+	EnableFargate                      = "fargate"
+	ContainerdMetricsEndpointKey       = "containerd_metrics_endpoint"
+	EnableTaskNetworkMetrics           = "task_network_metrics"
+	EnableServiceConnectMetrics        = "service_connect_metrics"
 	AppendDimensionsKey                = "append_dimensions"
 	Console                            = "console"
 	DiskKey                            = "disk"
 	DiskIOKey                          = "diskio"
 	NetKey                             = "net"
+	EfaKey                             = "efa"
 	Emf                                = "emf"
 	StructuredLog                      = "structuredlog"
 	ServiceAddress                     = "service_address"
@@ -77,11 +91,18 @@ const (
 	NameKey                            = "name"
 	RenameKey                          = "rename"
 	UnitKey                            = "unit"
+	AggregationIntervalKey             = "aggregation_interval"
+	InternalMetricsKey                 = "internal_metrics"
+	TailSamplingKey                    = "tail_sampling"
+	ScrubbingKey                       = "scrubbing"
+	NamespaceKey                       = "namespace"
+	NamespaceRoutesKey                 = "namespace_routes"
+	SpanMetricsKey                     = "span_metrics"
 )
 
 const (
 	CollectDMetricKey = "collectd"
-	CollectDPluginKey = "socket_listener"
+	CollectDPluginKey = "collectd"
 	CPUMetricKey      = "cpu"
 	DiskMetricKey     = "disk"
 	DiskIoMetricKey   = "diskio"
@@ -115,6 +136,8 @@ const (
 	PipelineNameContainerInsightsJmx = "containerinsightsjmx"
 	PipelineNameEmfLogs              = "emf_logs"
 	PipelineNamePrometheus           = "prometheus"
+	PipelineNameInternalMetrics      = "internal_metrics"
+	PipelineNameSpanMetrics          = "span_metrics"
 	AppSignals                       = "application_signals"
 	AppSignalsFallback               = "app_signals"
 	AppSignalsRules                  = "rules"
@@ -136,6 +159,9 @@ var (
 	JmxTargets = []string{"activemq", "cassandra", "hbase", "hadoop", "jetty", "jvm", "kafka", "kafka-consumer", "kafka-producer", "solr", "tomcat", "wildfly"}
 
 	AgentDebugConfigKey             = ConfigKey(AgentKey, DebugKey)
+	InternalMetricsConfigKey        = ConfigKey(AgentKey, InternalMetricsKey)
+	HybridEntityConfigKey           = ConfigKey(AgentKey, "hybrid_entity")
+	SpanMetricsConfigKey            = ConfigKey(TracesKey, SpanMetricsKey)
 	MetricsAggregationDimensionsKey = ConfigKey(MetricsKey, AggregationDimensionsKey)
 )
 
@@ -277,6 +303,14 @@ func ParseDuration(v interface{}) (time.Duration, error) {
 	return time.Duration(0), fmt.Errorf("invalid type %v", reflect.TypeOf(v))
 }
 
+// GetServiceProxyAddress gets the HTTPS proxy overriding the proxy of the
+// agent for the clients of the service. The other proxy settings of the agent
+// are set in the environment.
+func GetServiceProxyAddress(conf *confmap.Conf, service string) (string, bool) {
+	proxyAddress, ok := GetString(conf, ConfigKey(AgentKey, ProxyKey, service, HttpsProxyKey))
+	return proxyAddress, ok && proxyAddress != ""
+}
+
 // GetString gets the string value for the key. If the key is missing,
 // ok will be false.
 func GetString(conf *confmap.Conf, key string) (string, bool) {
@@ -292,6 +326,16 @@ func GetString(conf *confmap.Conf, key string) (string, bool) {
 	return "", false
 }
 
+// GetEndpointOverrides gets the endpoints for the key, which is either a
+// single endpoint or an ordered list of endpoints to fail over to. If the key
+// is missing, the return value will be nil.
+func GetEndpointOverrides(conf *confmap.Conf, key string) []string {
+	if endpoint, ok := conf.Get(key).(string); ok {
+		return []string{endpoint}
+	}
+	return GetArray[string](conf, key)
+}
+
 // GetArray gets the array value for the key. If the key is missing,
 // the return value will be nil
 func GetArray[C any](conf *confmap.Conf, key string) []C {
@@ -454,3 +498,19 @@ func IsAnySet(conf *confmap.Conf, keys []string) bool {
 func KueueContainerInsightsEnabled(conf *confmap.Conf) bool {
 	return GetOrDefaultBool(conf, ConfigKey(LogsKey, MetricsCollectedKey, KubernetesKey, EnableKueueContainerInsights), false)
 }
+
+func NodeHealthMetricsEnabled(conf *confmap.Conf) bool {
+	return GetOrDefaultBool(conf, ConfigKey(LogsKey, MetricsCollectedKey, KubernetesKey, EnableNodeHealthMetrics), false)
+}
+
+func FargateEnabled(conf *confmap.Conf) bool {
+	return GetOrDefaultBool(conf, ConfigKey(LogsKey, MetricsCollectedKey, KubernetesKey, EnableFargate), false)
+}
+
+func TaskNetworkMetricsEnabled(conf *confmap.Conf) bool {
+	return GetOrDefaultBool(conf, ConfigKey(LogsKey, MetricsCollectedKey, ECSKey, EnableTaskNetworkMetrics), false)
+}
+
+func ServiceConnectMetricsEnabled(conf *confmap.Conf) bool {
+	return GetOrDefaultBool(conf, ConfigKey(LogsKey, MetricsCollectedKey, ECSKey, EnableServiceConnectMetrics), false)
+}
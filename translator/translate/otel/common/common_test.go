@@ -68,6 +68,36 @@ func TestGetArray(t *testing.T) {
 	require.Equal(t, []string(nil), gotStr)
 }
 
+func TestGetEndpointOverrides(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"single": "https://primary",
+		"list":   []any{"https://primary", "https://secondary"},
+	})
+	require.Equal(t, []string{"https://primary"}, GetEndpointOverrides(conf, "single"))
+	require.Equal(t, []string{"https://primary", "https://secondary"}, GetEndpointOverrides(conf, "list"))
+	require.Nil(t, GetEndpointOverrides(conf, "missing"))
+}
+
+func TestGetServiceProxyAddress(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"agent": map[string]any{
+			"proxy": map[string]any{
+				"https_proxy": "http://proxy:3128",
+				"logs":        map[string]any{"https_proxy": "http://logs-proxy:3128"},
+				"xray":        map[string]any{"https_proxy": ""},
+			},
+		},
+	})
+	got, ok := GetServiceProxyAddress(conf, LogsKey)
+	require.True(t, ok)
+	require.Equal(t, "http://logs-proxy:3128", got)
+	// the empty proxy is not a proxy address
+	_, ok = GetServiceProxyAddress(conf, XrayKey)
+	require.False(t, ok)
+	_, ok = GetServiceProxyAddress(conf, MetricsKey)
+	require.False(t, ok)
+}
+
 func TestGetBool(t *testing.T) {
 	conf := confmap.NewFromStringMap(map[string]any{"int": 10, "string": "test", "bool1": false, "bool2": true})
 	got, ok := GetBool(conf, "int")
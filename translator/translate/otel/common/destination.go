@@ -3,14 +3,37 @@
 
 package common
 
-import "go.opentelemetry.io/collector/confmap"
+import (
+	"fmt"
+	"slices"
+
+	"go.opentelemetry.io/collector/confmap"
+)
 
 const (
 	DefaultDestination = ""
+
+	DeltaTemporality      = "delta"
+	CumulativeTemporality = "cumulative"
 )
 
 var (
 	metricsDestinationsKey = ConfigKey(MetricsKey, MetricsDestinationsKey)
+
+	// TracesDestinationsConfigKey is the section of the JSON config listing
+	// the destinations the traces are exported to in parallel.
+	TracesDestinationsConfigKey = ConfigKey(TracesKey, TracesDestinationsKey)
+
+	// supportedTemporalities are the aggregation temporalities of the sums
+	// each metrics destination accepts. CloudWatch adds up the values sent in
+	// each period and Prometheus remote write drops the delta sums.
+	supportedTemporalities = map[string][]string{
+		DefaultDestination: {DeltaTemporality},
+		CloudWatchKey:      {DeltaTemporality},
+		AMPKey:             {CumulativeTemporality},
+		TextfileKey:        {DeltaTemporality, CumulativeTemporality},
+		OtlpKey:            {DeltaTemporality, CumulativeTemporality},
+	}
 )
 
 func GetMetricsDestinations(conf *confmap.Conf) []string {
@@ -21,12 +44,47 @@ func GetMetricsDestinations(conf *confmap.Conf) []string {
 	if conf.IsSet(ConfigKey(metricsDestinationsKey, AMPKey)) {
 		destinations = append(destinations, AMPKey)
 	}
+	if conf.IsSet(ConfigKey(metricsDestinationsKey, TextfileKey)) {
+		destinations = append(destinations, TextfileKey)
+	}
+	if conf.IsSet(ConfigKey(metricsDestinationsKey, OtlpKey)) {
+		destinations = append(destinations, OtlpKey)
+	}
 	if conf.IsSet(MetricsKey) && len(destinations) == 0 {
 		destinations = append(destinations, DefaultDestination)
 	}
 	return destinations
 }
 
+// GetAggregationTemporality returns the aggregation temporality the sums are
+// converted to for the metrics destination, or an empty string if the
+// conversion is left to the pipeline. Returns an error if the destination
+// does not accept the temporality.
+func GetAggregationTemporality(conf *confmap.Conf, destination string) (string, error) {
+	key := ConfigKey(metricsDestinationsKey, destination, AggregationTemporalityKey)
+	if destination == DefaultDestination || !conf.IsSet(key) {
+		return "", nil
+	}
+	temporality, _ := conf.Get(key).(string)
+	if !slices.Contains(supportedTemporalities[destination], temporality) {
+		return "", fmt.Errorf("metrics destination (%s) does not support %s (%v), supported: %v", destination, AggregationTemporalityKey, conf.Get(key), supportedTemporalities[destination])
+	}
+	return temporality, nil
+}
+
+// GetTracesDestination returns the type (xray or otlp) and the settings of the
+// traces destination at the index, or an empty type if there is none.
+func GetTracesDestination(conf *confmap.Conf, index int) (string, map[string]any) {
+	destination := GetIndexedMap(conf, TracesDestinationsConfigKey, index)
+	for _, destinationType := range []string{XrayKey, OtlpKey} {
+		if settings, ok := destination[destinationType]; ok {
+			m, _ := settings.(map[string]any)
+			return destinationType, m
+		}
+	}
+	return "", nil
+}
+
 func GetLogsDestinations() []string {
 	return []string{CloudWatchLogsKey}
 }
@@ -57,6 +57,40 @@ func TestGetMetricsDestinations(t *testing.T) {
 			},
 			want: []string{CloudWatchKey, AMPKey},
 		},
+		"WithMetrics/Textfile": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"textfile": map[string]any{},
+					},
+				},
+			},
+			want: []string{TextfileKey},
+		},
+		"WithMetrics/CloudWatch&Textfile": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"cloudwatch": map[string]any{},
+						"textfile":   map[string]any{},
+					},
+				},
+			},
+			want: []string{CloudWatchKey, TextfileKey},
+		},
+		"WithMetrics/CloudWatch&OTLP": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"cloudwatch": map[string]any{},
+						"otlp": map[string]any{
+							"endpoint": "localhost:4317",
+						},
+					},
+				},
+			},
+			want: []string{CloudWatchKey, OtlpKey},
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -66,3 +100,125 @@ func TestGetMetricsDestinations(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAggregationTemporality(t *testing.T) {
+	testCases := map[string]struct {
+		destination string
+		input       map[string]any
+		want        string
+		wantErr     bool
+	}{
+		"WithDefault": {
+			destination: DefaultDestination,
+			input:       map[string]any{"metrics": map[string]any{}},
+		},
+		"WithNotSet": {
+			destination: AMPKey,
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"amp": map[string]any{"workspace_id": "ws-12345"},
+					},
+				},
+			},
+		},
+		"WithCloudWatch/Delta": {
+			destination: CloudWatchKey,
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"cloudwatch": map[string]any{"aggregation_temporality": "delta"},
+					},
+				},
+			},
+			want: DeltaTemporality,
+		},
+		"WithCloudWatch/Cumulative": {
+			destination: CloudWatchKey,
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"cloudwatch": map[string]any{"aggregation_temporality": "cumulative"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		"WithAMP/Cumulative": {
+			destination: AMPKey,
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"amp": map[string]any{"aggregation_temporality": "cumulative"},
+					},
+				},
+			},
+			want: CumulativeTemporality,
+		},
+		"WithAMP/Delta": {
+			destination: AMPKey,
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"amp": map[string]any{"aggregation_temporality": "delta"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		"WithOTLP/Delta": {
+			destination: OtlpKey,
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"otlp": map[string]any{"aggregation_temporality": "delta"},
+					},
+				},
+			},
+			want: DeltaTemporality,
+		},
+		"WithTextfile/Invalid": {
+			destination: TextfileKey,
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"textfile": map[string]any{"aggregation_temporality": "gauge"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(testCase.input)
+			got, err := GetAggregationTemporality(conf, testCase.destination)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestGetTracesDestination(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"traces": map[string]any{
+			"traces_destinations": []any{
+				map[string]any{"xray": map[string]any{"region": "us-west-2"}},
+				map[string]any{"otlp": map[string]any{"endpoint": "localhost:4317"}},
+			},
+		},
+	})
+	destinationType, settings := GetTracesDestination(conf, 0)
+	assert.Equal(t, XrayKey, destinationType)
+	assert.Equal(t, map[string]any{"region": "us-west-2"}, settings)
+	destinationType, settings = GetTracesDestination(conf, 1)
+	assert.Equal(t, OtlpKey, destinationType)
+	assert.Equal(t, map[string]any{"endpoint": "localhost:4317"}, settings)
+	destinationType, settings = GetTracesDestination(conf, 2)
+	assert.Equal(t, "", destinationType)
+	assert.Nil(t, settings)
+}
@@ -0,0 +1,55 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
+)
+
+// FastCollectionInterval is the collection interval below which only the
+// FastCollectionInputs collect their metrics reliably.
+const FastCollectionInterval = 10 * time.Second
+
+// FastCollectionInputs are the inputs of the metrics section validated for
+// collection intervals below FastCollectionInterval.
+var FastCollectionInputs = collections.NewSet[string]("cpu", "disk", "mem", "procstat")
+
+// HasFastCollection returns whether any input of the metrics section collects
+// its metrics more often than FastCollectionInterval, with its own interval
+// or the one of the agent section.
+func HasFastCollection(conf *confmap.Conf) bool {
+	inputs, ok := conf.Get(ConfigKey(MetricsKey, MetricsCollectedKey)).(map[string]any)
+	if !ok {
+		return false
+	}
+	agentInterval, _ := GetDuration(conf, ConfigKey(AgentKey, MetricsCollectionIntervalKey))
+	isFast := func(section any) bool {
+		interval := agentInterval
+		if m, ok := section.(map[string]any); ok {
+			if value, ok := m[MetricsCollectionIntervalKey]; ok {
+				if d, err := ParseDuration(value); err == nil && d > 0 {
+					interval = d
+				}
+			}
+		}
+		return interval > 0 && interval < FastCollectionInterval
+	}
+	for _, input := range inputs {
+		// procstat has a list of processes with their own intervals
+		if processes, ok := input.([]any); ok {
+			for _, process := range processes {
+				if isFast(process) {
+					return true
+				}
+			}
+		} else if isFast(input) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func TestHasFastCollection(t *testing.T) {
+	testCases := map[string]struct {
+		input map[string]any
+		want  bool
+	}{
+		"WithoutMetrics": {
+			input: map[string]any{},
+		},
+		"WithDefaultInterval": {
+			input: map[string]any{"metrics": map[string]any{"metrics_collected": map[string]any{"cpu": map[string]any{}}}},
+		},
+		"WithInputInterval": {
+			input: map[string]any{"metrics": map[string]any{"metrics_collected": map[string]any{
+				"cpu": map[string]any{"metrics_collection_interval": float64(1)},
+				"mem": map[string]any{"metrics_collection_interval": float64(60)},
+			}}},
+			want: true,
+		},
+		"WithAgentInterval": {
+			input: map[string]any{
+				"agent":   map[string]any{"metrics_collection_interval": float64(5)},
+				"metrics": map[string]any{"metrics_collected": map[string]any{"mem": map[string]any{}}},
+			},
+			want: true,
+		},
+		"WithInputOverridingAgentInterval": {
+			input: map[string]any{
+				"agent":   map[string]any{"metrics_collection_interval": float64(5)},
+				"metrics": map[string]any{"metrics_collected": map[string]any{"mem": map[string]any{"metrics_collection_interval": float64(10)}}},
+			},
+		},
+		"WithProcstat": {
+			input: map[string]any{"metrics": map[string]any{"metrics_collected": map[string]any{"procstat": []any{
+				map[string]any{"exe": "nginx", "metrics_collection_interval": float64(60)},
+				map[string]any{"exe": "java", "metrics_collection_interval": float64(1)},
+			}}}},
+			want: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, HasFastCollection(confmap.NewFromStringMap(testCase.input)))
+		})
+	}
+}
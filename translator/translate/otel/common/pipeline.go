@@ -0,0 +1,99 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+const (
+	ServiceKey           = "service"
+	PipelineDefaultsKey  = "pipeline_defaults"
+	PipelineOverridesKey = "pipeline_overrides"
+	queueSizeKey         = "queue_size"
+	numConsumersKey      = "num_consumers"
+	batchTimeoutKey      = "batch_timeout"
+	maxBatchBytesKey     = "max_batch_bytes"
+)
+
+// PipelineConfig is the queue and batch settings of the pipelines exporting
+// to a destination. The fields that are not set keep the defaults of each
+// exporter.
+type PipelineConfig struct {
+	// QueueSize is the number of batches buffered in front of the exporter.
+	QueueSize *int
+	// NumConsumers is the number of batches sent concurrently.
+	NumConsumers *int
+	// BatchTimeout is how long the data is batched before it is sent.
+	BatchTimeout *time.Duration
+	// MaxBatchBytes is the upper bound of the size of a request.
+	MaxBatchBytes *int
+}
+
+// GetPipelineConfig gets the queue and batch settings of the destination from
+// the pipeline_overrides block of the service section, falling back field by
+// field to its pipeline_defaults block. The batch timeout is in seconds and
+// can be fractional. Returns nil if neither block is set.
+func GetPipelineConfig(conf *confmap.Conf, destination string) *PipelineConfig {
+	keychain := []string{
+		ConfigKey(ServiceKey, PipelineOverridesKey, destination),
+		ConfigKey(ServiceKey, PipelineDefaultsKey),
+	}
+	var cfg PipelineConfig
+	var ok bool
+	for _, key := range keychain {
+		if conf == nil || !conf.IsSet(key) {
+			continue
+		}
+		ok = true
+		if cfg.QueueSize == nil {
+			cfg.QueueSize = getPositiveInt(conf, ConfigKey(key, queueSizeKey))
+		}
+		if cfg.NumConsumers == nil {
+			cfg.NumConsumers = getPositiveInt(conf, ConfigKey(key, numConsumersKey))
+		}
+		if cfg.BatchTimeout == nil {
+			cfg.BatchTimeout = getSeconds(conf, ConfigKey(key, batchTimeoutKey))
+		}
+		if cfg.MaxBatchBytes == nil {
+			cfg.MaxBatchBytes = getPositiveInt(conf, ConfigKey(key, maxBatchBytesKey))
+		}
+	}
+	if !ok {
+		return nil
+	}
+	return &cfg
+}
+
+// ApplyQueue sets the queue size and the number of consumers on the
+// exporterhelper queue settings if they are set.
+func (c *PipelineConfig) ApplyQueue(cfg *exporterhelper.QueueSettings) {
+	if c == nil {
+		return
+	}
+	if c.QueueSize != nil {
+		cfg.QueueSize = *c.QueueSize
+	}
+	if c.NumConsumers != nil {
+		cfg.NumConsumers = *c.NumConsumers
+	}
+}
+
+// ApplyBatchTimeout sets the batch timeout if it is set and positive.
+func (c *PipelineConfig) ApplyBatchTimeout(timeout *time.Duration) {
+	if c != nil && c.BatchTimeout != nil && *c.BatchTimeout > 0 {
+		*timeout = *c.BatchTimeout
+	}
+}
+
+func getPositiveInt(conf *confmap.Conf, key string) *int {
+	value, ok := GetNumber(conf, key)
+	if !ok || value < 1 {
+		return nil
+	}
+	return ptr(int(value))
+}
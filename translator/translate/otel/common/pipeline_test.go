@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+func TestGetPipelineConfig(t *testing.T) {
+	testCases := map[string]struct {
+		input map[string]any
+		want  *PipelineConfig
+	}{
+		"WithoutService": {
+			input: map[string]any{"metrics": map[string]any{}},
+		},
+		"WithDefaults": {
+			input: map[string]any{
+				"service": map[string]any{"pipeline_defaults": map[string]any{
+					"queue_size":    500,
+					"batch_timeout": 0.5,
+				}},
+			},
+			want: &PipelineConfig{
+				QueueSize:    ptr(500),
+				BatchTimeout: ptr(500 * time.Millisecond),
+			},
+		},
+		"WithOverrides": {
+			input: map[string]any{
+				"service": map[string]any{
+					"pipeline_defaults": map[string]any{
+						"queue_size":      500,
+						"num_consumers":   4,
+						"max_batch_bytes": 1000000,
+					},
+					"pipeline_overrides": map[string]any{
+						"amp": map[string]any{
+							"queue_size":    2000,
+							"batch_timeout": 10,
+						},
+					},
+				},
+			},
+			want: &PipelineConfig{
+				QueueSize:     ptr(2000),
+				NumConsumers:  ptr(4),
+				BatchTimeout:  ptr(10 * time.Second),
+				MaxBatchBytes: ptr(1000000),
+			},
+		},
+		"WithOtherDestinationOverrides": {
+			input: map[string]any{
+				"service": map[string]any{"pipeline_overrides": map[string]any{
+					"otlp": map[string]any{"queue_size": 100},
+				}},
+			},
+		},
+		"WithInvalidValues": {
+			input: map[string]any{
+				"service": map[string]any{"pipeline_defaults": map[string]any{
+					"queue_size":    0,
+					"num_consumers": -1,
+				}},
+			},
+			want: &PipelineConfig{},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(testCase.input)
+			assert.Equal(t, testCase.want, GetPipelineConfig(conf, AMPKey))
+		})
+	}
+}
+
+func TestPipelineConfigApply(t *testing.T) {
+	queue := exporterhelper.NewDefaultQueueSettings()
+	timeout := time.Minute
+	var c *PipelineConfig
+	c.ApplyQueue(&queue)
+	c.ApplyBatchTimeout(&timeout)
+	assert.Equal(t, exporterhelper.NewDefaultQueueSettings(), queue)
+	assert.Equal(t, time.Minute, timeout)
+
+	c = &PipelineConfig{QueueSize: ptr(100), NumConsumers: ptr(2), BatchTimeout: ptr(time.Duration(0))}
+	c.ApplyQueue(&queue)
+	c.ApplyBatchTimeout(&timeout)
+	assert.True(t, queue.Enabled)
+	assert.Equal(t, 100, queue.QueueSize)
+	assert.Equal(t, 2, queue.NumConsumers)
+	assert.Equal(t, time.Minute, timeout)
+
+	c = &PipelineConfig{BatchTimeout: ptr(5 * time.Second)}
+	c.ApplyBatchTimeout(&timeout)
+	assert.Equal(t, 5*time.Second, timeout)
+}
@@ -0,0 +1,108 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+const (
+	RetryKey           = "retry"
+	maxRetriesKey      = "max_retries"
+	initialIntervalKey = "initial_interval"
+	maxIntervalKey     = "max_interval"
+	maxElapsedTimeKey  = "max_elapsed_time"
+)
+
+// RetryConfig is the retry policy of the AWS exporters. The fields that are
+// not set keep the defaults of each exporter.
+type RetryConfig struct {
+	// MaxRetries is the number of times a failed request is retried.
+	MaxRetries *int
+	// InitialInterval is the wait before the first retry, doubled for each
+	// of the following retries.
+	InitialInterval *time.Duration
+	// MaxInterval is the upper bound of the wait between retries.
+	MaxInterval *time.Duration
+	// MaxElapsedTime is how long a request is retried for. Zero means no limit.
+	MaxElapsedTime *time.Duration
+}
+
+// GetRetryConfig gets the retry policy of the destination of the section from
+// the retry block of the section, falling back field by field to the retry
+// block of the agent section. The intervals are in seconds and can be
+// fractional. Returns nil if neither block is set.
+func GetRetryConfig(conf *confmap.Conf, sectionKey string) *RetryConfig {
+	keychain := []string{ConfigKey(sectionKey, RetryKey), ConfigKey(AgentKey, RetryKey)}
+	var cfg RetryConfig
+	var ok bool
+	for _, key := range keychain {
+		if !conf.IsSet(key) {
+			continue
+		}
+		ok = true
+		if cfg.MaxRetries == nil {
+			if maxRetries, found := GetNumber(conf, ConfigKey(key, maxRetriesKey)); found {
+				cfg.MaxRetries = ptr(int(maxRetries))
+			}
+		}
+		if cfg.InitialInterval == nil {
+			cfg.InitialInterval = getSeconds(conf, ConfigKey(key, initialIntervalKey))
+		}
+		if cfg.MaxInterval == nil {
+			cfg.MaxInterval = getSeconds(conf, ConfigKey(key, maxIntervalKey))
+		}
+		if cfg.MaxElapsedTime == nil {
+			cfg.MaxElapsedTime = getSeconds(conf, ConfigKey(key, maxElapsedTimeKey))
+		}
+	}
+	if !ok {
+		return nil
+	}
+	return &cfg
+}
+
+// ApplyBackOff sets the fields of the retry policy that are set on the
+// exporterhelper retry settings. The retries are disabled if the maximum
+// number of retries is zero.
+func (c *RetryConfig) ApplyBackOff(cfg *configretry.BackOffConfig) {
+	if c == nil {
+		return
+	}
+	if c.MaxRetries != nil && *c.MaxRetries == 0 {
+		cfg.Enabled = false
+	}
+	if c.InitialInterval != nil {
+		cfg.InitialInterval = *c.InitialInterval
+	}
+	if c.MaxInterval != nil {
+		cfg.MaxInterval = *c.MaxInterval
+	}
+	if c.MaxElapsedTime != nil {
+		cfg.MaxElapsedTime = *c.MaxElapsedTime
+	}
+}
+
+// ApplyMaxRetries sets the maximum number of retries of the AWS SDK requests
+// if it is set.
+func (c *RetryConfig) ApplyMaxRetries(maxRetries *int) {
+	if c != nil && c.MaxRetries != nil {
+		*maxRetries = *c.MaxRetries
+	}
+}
+
+func getSeconds(conf *confmap.Conf, key string) *time.Duration {
+	seconds, ok := GetNumber(conf, key)
+	if !ok || seconds < 0 {
+		return nil
+	}
+	return ptr(time.Duration(seconds * float64(time.Second)))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func TestGetRetryConfig(t *testing.T) {
+	testCases := map[string]struct {
+		input map[string]any
+		want  *RetryConfig
+	}{
+		"WithoutRetry": {
+			input: map[string]any{"metrics": map[string]any{}},
+		},
+		"WithAgentRetry": {
+			input: map[string]any{
+				"agent": map[string]any{"retry": map[string]any{
+					"max_retries":      3,
+					"initial_interval": 0.5,
+				}},
+			},
+			want: &RetryConfig{
+				MaxRetries:      ptr(3),
+				InitialInterval: ptr(500 * time.Millisecond),
+			},
+		},
+		"WithSectionRetry": {
+			input: map[string]any{
+				"agent": map[string]any{"retry": map[string]any{
+					"max_retries":      3,
+					"initial_interval": 0.5,
+					"max_elapsed_time": 300,
+				}},
+				"metrics": map[string]any{"retry": map[string]any{
+					"max_retries":  0,
+					"max_interval": 30,
+				}},
+			},
+			want: &RetryConfig{
+				MaxRetries:      ptr(0),
+				InitialInterval: ptr(500 * time.Millisecond),
+				MaxInterval:     ptr(30 * time.Second),
+				MaxElapsedTime:  ptr(5 * time.Minute),
+			},
+		},
+		"WithOtherSectionRetry": {
+			input: map[string]any{
+				"logs": map[string]any{"retry": map[string]any{
+					"max_retries": 10,
+				}},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(testCase.input)
+			assert.Equal(t, testCase.want, GetRetryConfig(conf, MetricsKey))
+		})
+	}
+}
+
+func TestRetryConfigApply(t *testing.T) {
+	backOff := configretry.NewDefaultBackOffConfig()
+	maxRetries := 2
+	var c *RetryConfig
+	c.ApplyBackOff(&backOff)
+	c.ApplyMaxRetries(&maxRetries)
+	assert.Equal(t, configretry.NewDefaultBackOffConfig(), backOff)
+	assert.Equal(t, 2, maxRetries)
+
+	c = &RetryConfig{MaxRetries: ptr(5), MaxInterval: ptr(time.Minute), MaxElapsedTime: ptr(time.Duration(0))}
+	c.ApplyBackOff(&backOff)
+	c.ApplyMaxRetries(&maxRetries)
+	assert.True(t, backOff.Enabled)
+	assert.Equal(t, configretry.NewDefaultBackOffConfig().InitialInterval, backOff.InitialInterval)
+	assert.Equal(t, time.Minute, backOff.MaxInterval)
+	assert.Equal(t, time.Duration(0), backOff.MaxElapsedTime)
+	assert.Equal(t, 5, maxRetries)
+
+	c = &RetryConfig{MaxRetries: ptr(0)}
+	c.ApplyBackOff(&backOff)
+	assert.False(t, backOff.Enabled)
+}
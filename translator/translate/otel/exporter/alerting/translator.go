@@ -0,0 +1,146 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package alerting
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/exporter"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/alerting"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	rulesKey      = "rules"
+	nameKey       = "name"
+	metricKey     = "metric"
+	dimensionsKey = "dimensions"
+	comparisonKey = "comparison"
+	thresholdKey  = "threshold"
+	durationKey   = "duration"
+	actionsKey    = "actions"
+	typeKey       = "type"
+	pathKey       = "path"
+	commandKey    = "command"
+	urlKey        = "url"
+	addressKey    = "address"
+	communityKey  = "community"
+	trapOIDKey    = "trap_oid"
+	timeoutKey    = "timeout"
+
+	defaultActionTimeout = 10 * time.Second
+)
+
+var (
+	SectionKey = common.AlertsKey
+	RulesKey   = common.ConfigKey(SectionKey, rulesKey)
+)
+
+type translator struct {
+	name    string
+	factory exporter.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+func NewTranslator() common.Translator[component.Config] {
+	return NewTranslatorWithName("")
+}
+
+func NewTranslatorWithName(name string) common.Translator[component.Config] {
+	return &translator{name, alerting.NewFactory()}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// IsSet returns true if the alerts section has rules.
+func IsSet(conf *confmap.Conf) bool {
+	rules, ok := conf.Get(RulesKey).([]any)
+	return ok && len(rules) > 0
+}
+
+// Translate creates an exporter config based on the rules in the alerts
+// section of the JSON config.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if conf == nil || !IsSet(conf) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: RulesKey}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*alerting.Config)
+	for i, raw := range conf.Get(RulesKey).([]any) {
+		ruleConf, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid alert rule %d: %v", i, raw)
+		}
+		rule, err := translateRule(confmap.NewFromStringMap(ruleConf))
+		if err != nil {
+			return nil, fmt.Errorf("invalid alert rule %d: %w", i, err)
+		}
+		cfg.Rules = append(cfg.Rules, rule)
+	}
+	return cfg, nil
+}
+
+func translateRule(conf *confmap.Conf) (alerting.Rule, error) {
+	rule := alerting.Rule{
+		Dimensions: getStringMap(conf, dimensionsKey),
+	}
+	rule.Name, _ = common.GetString(conf, nameKey)
+	rule.Metric, _ = common.GetString(conf, metricKey)
+	rule.Comparison, _ = common.GetString(conf, comparisonKey)
+	switch threshold := conf.Get(thresholdKey).(type) {
+	case float64:
+		rule.Threshold = threshold
+	case int:
+		rule.Threshold = float64(threshold)
+	default:
+		return rule, fmt.Errorf("%s must be a number", thresholdKey)
+	}
+	if duration, ok := common.GetDuration(conf, durationKey); ok {
+		rule.Duration = duration
+	}
+	actions, _ := conf.Get(actionsKey).([]any)
+	for _, raw := range actions {
+		actionConf, ok := raw.(map[string]any)
+		if !ok {
+			return rule, fmt.Errorf("invalid action: %v", raw)
+		}
+		rule.Actions = append(rule.Actions, translateAction(confmap.NewFromStringMap(actionConf)))
+	}
+	return rule, nil
+}
+
+func translateAction(conf *confmap.Conf) alerting.Action {
+	action := alerting.Action{
+		Command: common.GetArray[string](conf, commandKey),
+		Timeout: defaultActionTimeout,
+	}
+	action.Type, _ = common.GetString(conf, typeKey)
+	action.Path, _ = common.GetString(conf, pathKey)
+	action.URL, _ = common.GetString(conf, urlKey)
+	action.Address, _ = common.GetString(conf, addressKey)
+	action.Community, _ = common.GetString(conf, communityKey)
+	action.TrapOID, _ = common.GetString(conf, trapOIDKey)
+	if timeout, ok := common.GetDuration(conf, timeoutKey); ok {
+		action.Timeout = timeout
+	}
+	return action
+}
+
+func getStringMap(conf *confmap.Conf, key string) map[string]string {
+	m, ok := conf.Get(key).(map[string]any)
+	if !ok || len(m) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = fmt.Sprint(v)
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package alerting
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/testutil"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/alerting"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	tt := NewTranslator()
+	require.EqualValues(t, "alerting", tt.ID().String())
+
+	testCases := map[string]struct {
+		input   map[string]any
+		want    *confmap.Conf
+		wantErr error
+	}{
+		"WithoutRules": {
+			input: map[string]any{
+				"alerts": map[string]any{},
+			},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: RulesKey},
+		},
+		"WithInvalidThreshold": {
+			input: map[string]any{
+				"alerts": map[string]any{
+					"rules": []any{
+						map[string]any{"name": "high_cpu", "metric": "cpu_usage_active", "comparison": ">", "threshold": "90"},
+					},
+				},
+			},
+			wantErr: errors.New("invalid alert rule 0: threshold must be a number"),
+		},
+		"WithRules": {
+			input: testutil.GetJson(t, filepath.Join("testdata", "config.json")),
+			want:  testutil.GetConf(t, filepath.Join("testdata", "config.yaml")),
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(testCase.input)
+			got, err := tt.Translate(conf)
+			if testCase.wantErr != nil {
+				assert.EqualError(t, err, testCase.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, got)
+			gotCfg, ok := got.(*alerting.Config)
+			require.True(t, ok)
+			wantCfg := &alerting.Config{}
+			require.NoError(t, testCase.want.Unmarshal(wantCfg))
+			assert.Equal(t, wantCfg, gotCfg)
+			assert.NoError(t, gotCfg.Validate())
+		})
+	}
+}
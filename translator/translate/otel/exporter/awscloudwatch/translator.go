@@ -4,26 +4,44 @@
 package awscloudwatch
 
 import (
+	"fmt"
+	"strconv"
+	"time"
+
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/exporter"
 
 	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/cloudwatch"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/metricsrouting"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/extension/agenthealth"
+	metricsroutingtranslator "github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/metricsrouting"
 )
 
 const (
 	namespaceKey          = "namespace"
 	forceFlushIntervalKey = "force_flush_interval"
+	highResFlushKey       = "high_resolution_flush_interval"
+	sanitizationKey       = "sanitization"
+	batchingKey           = "batching"
+	adaptiveKey           = "adaptive"
+	maxInFlightKey        = "max_in_flight_requests"
+	targetLatencyKey      = "target_latency"
 	dropOriginalWildcard  = "*"
 
 	internalMaxValuesPerDatum = 5000
+	// defaultHighResolutionFlushInterval flushes the high-resolution metrics
+	// if an input collects more often than common.FastCollectionInterval.
+	defaultHighResolutionFlushInterval = 5 * time.Second
 )
 
 type translator struct {
-	name    string
+	name string
+	// route is the namespace route whose namespace the metrics are published
+	// to, metrics.namespace is used if it is metricsrouting.DefaultRoute.
+	route   int
 	factory exporter.Factory
 }
 
@@ -34,7 +52,18 @@ func NewTranslator() common.Translator[component.Config] {
 }
 
 func NewTranslatorWithName(name string) common.Translator[component.Config] {
-	return &translator{name, cloudwatch.NewFactory()}
+	return &translator{name, metricsrouting.DefaultRoute, cloudwatch.NewFactory()}
+}
+
+// NewTranslatorWithRoute creates the exporter publishing the metrics of the
+// namespace route to its namespace.
+func NewTranslatorWithRoute(route int) common.Translator[component.Config] {
+	return &translator{RouteName(route), route, cloudwatch.NewFactory()}
+}
+
+// RouteName is the name of the components of the namespace route.
+func RouteName(route int) string {
+	return "route" + strconv.Itoa(route)
 }
 
 func (t *translator) ID() component.ID {
@@ -53,15 +82,29 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 	_ = credentials.Unmarshal(cfg)
 	cfg.RoleARN = getRoleARN(conf)
 	cfg.Region = agent.Global_Config.Region
-	if namespace, ok := common.GetString(conf, common.ConfigKey(common.MetricsKey, namespaceKey)); ok {
+	if t.route != metricsrouting.DefaultRoute {
+		namespace, ok := metricsroutingtranslator.Namespace(conf, t.route)
+		if !ok {
+			return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.ConfigKey(common.MetricsKey, common.NamespaceRoutesKey, namespaceKey)}
+		}
+		cfg.Namespace = namespace
+	} else if namespace, ok := common.GetString(conf, common.ConfigKey(common.MetricsKey, namespaceKey)); ok {
 		cfg.Namespace = namespace
 	}
-	if endpointOverride, ok := common.GetString(conf, common.ConfigKey(common.MetricsKey, common.EndpointOverrideKey)); ok {
-		cfg.EndpointOverride = endpointOverride
+	if endpoints := common.GetEndpointOverrides(conf, common.ConfigKey(common.MetricsKey, common.EndpointOverrideKey)); len(endpoints) > 0 {
+		cfg.EndpointOverride = endpoints[0]
+		if len(endpoints) > 1 {
+			cfg.FailoverEndpoints = endpoints[1:]
+		}
 	}
+	cfg.SigV4aRegionSet = common.GetArray[string](conf, common.ConfigKey(common.MetricsKey, common.SigV4aRegionSetKey))
+	pipeline := common.GetPipelineConfig(conf, common.CloudWatchKey)
 	if forceFlushInterval, ok := common.GetDuration(conf, common.ConfigKey(common.MetricsKey, forceFlushIntervalKey)); ok {
 		cfg.ForceFlushInterval = forceFlushInterval
+	} else {
+		pipeline.ApplyBatchTimeout(&cfg.ForceFlushInterval)
 	}
+	cfg.HighResolutionFlushInterval = getHighResolutionFlushInterval(conf)
 	if agent.Global_Config.Internal {
 		cfg.MaxValuesPerDatum = internalMaxValuesPerDatum
 	}
@@ -71,10 +114,109 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 	if dropOriginalMetrics := common.GetDropOriginalMetrics(conf); len(dropOriginalMetrics) != 0 {
 		cfg.DropOriginalConfigs = dropOriginalMetrics
 	}
+	sanitization, err := getSanitization(conf)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Sanitization = sanitization
+	cfg.Batching = getBatching(conf)
+	applyPipeline(pipeline, cfg)
+	cfg.Retry = getRetry(conf)
 	cfg.MiddlewareID = &agenthealth.MetricsID
 	return cfg, nil
 }
 
+// getHighResolutionFlushInterval gets the flush interval of the
+// high-resolution metrics from the metrics section. Defaults to
+// defaultHighResolutionFlushInterval if an input collects more often than
+// common.FastCollectionInterval, so that the flushes do not delay them.
+func getHighResolutionFlushInterval(conf *confmap.Conf) time.Duration {
+	if interval, ok := common.GetDuration(conf, common.ConfigKey(common.MetricsKey, highResFlushKey)); ok {
+		return interval
+	}
+	if common.HasFastCollection(conf) {
+		return defaultHighResolutionFlushInterval
+	}
+	return 0
+}
+
+// getSanitization unmarshals the sanitization policies from the metrics section.
+// Returns nil if the section is not set.
+func getSanitization(conf *confmap.Conf) (*cloudwatch.SanitizationConfig, error) {
+	key := common.ConfigKey(common.MetricsKey, sanitizationKey)
+	if !conf.IsSet(key) {
+		return nil, nil
+	}
+	sub, err := conf.Sub(key)
+	if err != nil {
+		return nil, err
+	}
+	var cfg cloudwatch.SanitizationConfig
+	if err = sub.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal %s: %w", key, err)
+	}
+	return &cfg, nil
+}
+
+// getBatching gets the batching tuning from the metrics section. The target
+// latency is in seconds like the other durations in the JSON config. Returns nil
+// if the section is not set.
+func getBatching(conf *confmap.Conf) *cloudwatch.BatchingConfig {
+	key := common.ConfigKey(common.MetricsKey, batchingKey)
+	if !conf.IsSet(key) {
+		return nil
+	}
+	cfg := &cloudwatch.BatchingConfig{}
+	cfg.Adaptive, _ = common.GetBool(conf, common.ConfigKey(key, adaptiveKey))
+	if maxInFlight, ok := common.GetNumber(conf, common.ConfigKey(key, maxInFlightKey)); ok {
+		cfg.MaxInFlightRequests = int(maxInFlight)
+	}
+	if targetLatency, ok := common.GetDuration(conf, common.ConfigKey(key, targetLatencyKey)); ok {
+		cfg.TargetLatency = targetLatency
+	}
+	return cfg
+}
+
+// applyPipeline sets the size of the metric buffer and, unless the batching
+// section sets it, the number of concurrent requests from the pipeline
+// settings of the CloudWatch destination.
+func applyPipeline(pipeline *common.PipelineConfig, cfg *cloudwatch.Config) {
+	if pipeline == nil {
+		return
+	}
+	if pipeline.QueueSize != nil {
+		cfg.QueueSize = *pipeline.QueueSize
+	}
+	if pipeline.NumConsumers != nil {
+		if cfg.Batching == nil {
+			cfg.Batching = &cloudwatch.BatchingConfig{}
+		}
+		if cfg.Batching.MaxInFlightRequests == 0 {
+			cfg.Batching.MaxInFlightRequests = *pipeline.NumConsumers
+		}
+	}
+}
+
+// getRetry gets the retry policy of the metrics from the metrics section,
+// falling back to the agent section. Returns nil if neither is set.
+func getRetry(conf *confmap.Conf) *cloudwatch.RetryConfig {
+	retry := common.GetRetryConfig(conf, common.MetricsKey)
+	if retry == nil {
+		return nil
+	}
+	cfg := &cloudwatch.RetryConfig{MaxRetries: retry.MaxRetries}
+	if retry.InitialInterval != nil {
+		cfg.InitialInterval = *retry.InitialInterval
+	}
+	if retry.MaxInterval != nil {
+		cfg.MaxInterval = *retry.MaxInterval
+	}
+	if retry.MaxElapsedTime != nil {
+		cfg.MaxElapsedTime = *retry.MaxElapsedTime
+	}
+	return cfg
+}
+
 func getRoleARN(conf *confmap.Conf) string {
 	key := common.ConfigKey(common.MetricsKey, common.CredentialsKey, common.RoleARNKey)
 	roleARN, ok := common.GetString(conf, key)
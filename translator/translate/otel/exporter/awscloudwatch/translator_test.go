@@ -24,6 +24,7 @@ func TestTranslator(t *testing.T) {
 	agent.Global_Config.Role_arn = "global_arn"
 	cwt := NewTranslator()
 	require.EqualValues(t, "awscloudwatch", cwt.ID().String())
+	maxRetries := 2
 	testCases := map[string]struct {
 		input       map[string]interface{}
 		internal    bool
@@ -62,6 +63,148 @@ func TestTranslator(t *testing.T) {
 				RoleARN:            "global_arn",
 			},
 		},
+		"WithFailoverEndpoints": {
+			input: map[string]interface{}{"metrics": map[string]interface{}{
+				"endpoint_override": []interface{}{
+					"https://monitoring.us-east-1.amazonaws.com",
+					"https://monitoring.us-west-2.amazonaws.com",
+				},
+				"sigv4a_region_set": []interface{}{"us-east-1", "us-west-2"},
+			}},
+			want: &cloudwatch.Config{
+				Namespace:          "CWAgent",
+				Region:             "us-east-1",
+				ForceFlushInterval: time.Minute,
+				MaxValuesPerDatum:  150,
+				EndpointOverride:   "https://monitoring.us-east-1.amazonaws.com",
+				FailoverEndpoints:  []string{"https://monitoring.us-west-2.amazonaws.com"},
+				SigV4aRegionSet:    []string{"us-east-1", "us-west-2"},
+				RoleARN:            "global_arn",
+			},
+		},
+		"WithSanitization": {
+			input: map[string]interface{}{"metrics": map[string]interface{}{
+				"sanitization": map[string]interface{}{
+					"invalid_characters": "fix",
+					"dimension_limit":    "fix",
+					"name_length":        "drop",
+					"dimension_priority": []interface{}{"InstanceId", "ImageId"},
+				},
+			}},
+			want: &cloudwatch.Config{
+				Namespace:          "CWAgent",
+				Region:             "us-east-1",
+				ForceFlushInterval: time.Minute,
+				MaxValuesPerDatum:  150,
+				RoleARN:            "global_arn",
+				Sanitization: &cloudwatch.SanitizationConfig{
+					InvalidCharacters: cloudwatch.SanitizationModeFix,
+					DimensionLimit:    cloudwatch.SanitizationModeFix,
+					NameLength:        cloudwatch.SanitizationModeDrop,
+					DimensionPriority: []string{"InstanceId", "ImageId"},
+				},
+			},
+		},
+		"WithBatching": {
+			input: map[string]interface{}{"metrics": map[string]interface{}{
+				"batching": map[string]interface{}{
+					"adaptive":               true,
+					"max_in_flight_requests": float64(4),
+					"target_latency":         float64(3),
+				},
+			}},
+			want: &cloudwatch.Config{
+				Namespace:          "CWAgent",
+				Region:             "us-east-1",
+				ForceFlushInterval: time.Minute,
+				MaxValuesPerDatum:  150,
+				RoleARN:            "global_arn",
+				Batching: &cloudwatch.BatchingConfig{
+					Adaptive:            true,
+					MaxInFlightRequests: 4,
+					TargetLatency:       3 * time.Second,
+				},
+			},
+		},
+		"WithHighResolutionFlushInterval": {
+			input: map[string]interface{}{"metrics": map[string]interface{}{
+				"high_resolution_flush_interval": float64(2),
+			}},
+			want: &cloudwatch.Config{
+				Namespace:                   "CWAgent",
+				Region:                      "us-east-1",
+				ForceFlushInterval:          time.Minute,
+				HighResolutionFlushInterval: 2 * time.Second,
+				MaxValuesPerDatum:           150,
+				RoleARN:                     "global_arn",
+			},
+		},
+		"WithFastCollection": {
+			input: map[string]interface{}{"metrics": map[string]interface{}{
+				"metrics_collected": map[string]interface{}{
+					"cpu": map[string]interface{}{"metrics_collection_interval": float64(1)},
+				},
+			}},
+			want: &cloudwatch.Config{
+				Namespace:                   "CWAgent",
+				Region:                      "us-east-1",
+				ForceFlushInterval:          time.Minute,
+				HighResolutionFlushInterval: 5 * time.Second,
+				MaxValuesPerDatum:           150,
+				RoleARN:                     "global_arn",
+			},
+		},
+		"WithRetry": {
+			input: map[string]interface{}{
+				"agent": map[string]interface{}{"retry": map[string]interface{}{
+					"max_retries":      float64(10),
+					"max_elapsed_time": float64(300),
+				}},
+				"metrics": map[string]interface{}{"retry": map[string]interface{}{
+					"max_retries":      float64(2),
+					"initial_interval": 0.5,
+				}},
+			},
+			want: &cloudwatch.Config{
+				Namespace:          "CWAgent",
+				Region:             "us-east-1",
+				ForceFlushInterval: time.Minute,
+				MaxValuesPerDatum:  150,
+				RoleARN:            "global_arn",
+				Retry: &cloudwatch.RetryConfig{
+					MaxRetries:      &maxRetries,
+					InitialInterval: 500 * time.Millisecond,
+					MaxElapsedTime:  5 * time.Minute,
+				},
+			},
+		},
+		"WithPipelineSettings": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{},
+				"service": map[string]interface{}{
+					"pipeline_defaults": map[string]interface{}{
+						"queue_size":    float64(20000),
+						"batch_timeout": float64(30),
+					},
+					"pipeline_overrides": map[string]interface{}{
+						"cloudwatch": map[string]interface{}{
+							"num_consumers": float64(4),
+						},
+					},
+				},
+			},
+			want: &cloudwatch.Config{
+				Namespace:          "CWAgent",
+				Region:             "us-east-1",
+				ForceFlushInterval: 30 * time.Second,
+				MaxValuesPerDatum:  150,
+				RoleARN:            "global_arn",
+				QueueSize:          20000,
+				Batching: &cloudwatch.BatchingConfig{
+					MaxInFlightRequests: 4,
+				},
+			},
+		},
 		"WithInvalidCredentialFields": {
 			input: map[string]interface{}{"metrics": map[string]interface{}{}},
 			credentials: map[string]interface{}{
@@ -154,6 +297,9 @@ func TestTranslator(t *testing.T) {
 				gotCfg, ok := got.(*cloudwatch.Config)
 				require.True(t, ok)
 				assert.Equal(t, testCase.want.Namespace, gotCfg.Namespace)
+				assert.Equal(t, testCase.want.EndpointOverride, gotCfg.EndpointOverride)
+				assert.Equal(t, testCase.want.FailoverEndpoints, gotCfg.FailoverEndpoints)
+				assert.Equal(t, testCase.want.SigV4aRegionSet, gotCfg.SigV4aRegionSet)
 				assert.Equal(t, testCase.want.Region, gotCfg.Region)
 				assert.Equal(t, testCase.want.ForceFlushInterval, gotCfg.ForceFlushInterval)
 				assert.Equal(t, testCase.want.RoleARN, gotCfg.RoleARN)
@@ -164,6 +310,10 @@ func TestTranslator(t *testing.T) {
 				assert.Equal(t, testCase.want.SharedCredentialFilename, gotCfg.SharedCredentialFilename)
 				assert.Equal(t, testCase.want.MaxValuesPerDatum, gotCfg.MaxValuesPerDatum)
 				assert.Equal(t, testCase.want.RollupDimensions, gotCfg.RollupDimensions)
+				assert.Equal(t, testCase.want.Sanitization, gotCfg.Sanitization)
+				assert.Equal(t, testCase.want.Retry, gotCfg.Retry)
+				assert.Equal(t, testCase.want.Batching, gotCfg.Batching)
+				assert.Equal(t, testCase.want.QueueSize, gotCfg.QueueSize)
 				assert.NotNil(t, gotCfg.MiddlewareID)
 				assert.Equal(t, "agenthealth/metrics", gotCfg.MiddlewareID.String())
 				if testCase.wantWindows != nil && runtime.GOOS == "windows" {
@@ -175,3 +325,22 @@ func TestTranslator(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslatorWithRoute(t *testing.T) {
+	agent.Global_Config.Region = "us-east-1"
+	cwt := NewTranslatorWithRoute(1)
+	require.EqualValues(t, "awscloudwatch/route1", cwt.ID().String())
+	conf := confmap.NewFromStringMap(map[string]interface{}{"metrics": map[string]interface{}{
+		"namespace": "CWAgent",
+		"namespace_routes": []interface{}{
+			map[string]interface{}{"namespace": "TeamA", "metric_names": []interface{}{"mem_*"}},
+			map[string]interface{}{"namespace": "TeamB", "metric_names": []interface{}{"cpu_*"}},
+		},
+	}})
+	got, err := cwt.Translate(conf)
+	require.NoError(t, err)
+	assert.Equal(t, "TeamB", got.(*cloudwatch.Config).Namespace)
+
+	_, err = NewTranslatorWithRoute(2).Translate(conf)
+	assert.Equal(t, &common.MissingKeyError{ID: NewTranslatorWithRoute(2).ID(), JsonKey: "metrics::namespace_routes::namespace"}, err)
+}
@@ -62,13 +62,19 @@ func (t *translator) Translate(c *confmap.Conf) (component.Config, error) {
 	}
 
 	cfg.AWSSessionSettings.CertificateFilePath = os.Getenv(envconfig.AWS_CA_BUNDLE)
-	if endpoint, ok := common.GetString(c, endpointOverrideKey); ok {
+	// the exporter does not fail over, it only uses the primary endpoint
+	if endpoints := common.GetEndpointOverrides(c, endpointOverrideKey); len(endpoints) > 0 {
+		endpoint := endpoints[0]
 		// for some reason the exporter has an endpoint field in the config that
 		// clashes with the AWSSessionsSettings
 		cfg.Endpoint = endpoint
 		cfg.AWSSessionSettings.Endpoint = endpoint
 	}
 	cfg.AWSSessionSettings.IMDSRetries = retryer.GetDefaultRetryNumber()
+	retry := common.GetRetryConfig(c, common.LogsKey)
+	retry.ApplyMaxRetries(&cfg.AWSSessionSettings.MaxRetries)
+	retry.ApplyBackOff(&cfg.BackOffConfig)
+	common.GetPipelineConfig(c, common.CloudWatchLogsKey).ApplyQueue(&cfg.QueueSettings)
 	if profileKey, ok := agent.Global_Config.Credentials[agent.Profile_Key]; ok {
 		cfg.AWSSessionSettings.Profile = fmt.Sprintf("%v", profileKey)
 	}
@@ -77,6 +83,9 @@ func (t *translator) Translate(c *confmap.Conf) (component.Config, error) {
 	if c.IsSet(roleARNPathKey) {
 		cfg.AWSSessionSettings.RoleARN, _ = common.GetString(c, roleARNPathKey)
 	}
+	if proxyAddress, ok := common.GetServiceProxyAddress(c, common.LogsKey); ok {
+		cfg.AWSSessionSettings.ProxyAddress = proxyAddress
+	}
 	if credentialsFileKey, ok := agent.Global_Config.Credentials[agent.CredentialsFile_Key]; ok {
 		cfg.AWSSessionSettings.SharedCredentialsFile = []string{fmt.Sprintf("%v", credentialsFileKey)}
 	}
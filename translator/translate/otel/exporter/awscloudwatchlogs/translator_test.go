@@ -71,6 +71,41 @@ func TestTranslator(t *testing.T) {
 				"shared_credentials_file": "/some/credentials",
 			}),
 		},
+		"WithRetry": {
+			input: map[string]any{
+				"logs": map[string]any{
+					"metrics_collected": map[string]any{
+						"emf": map[string]any{},
+					},
+					"retry": map[string]any{
+						"max_retries":      5,
+						"initial_interval": 1,
+						"max_interval":     60,
+						"max_elapsed_time": 600,
+					},
+				},
+			},
+			mode: config.ModeEC2,
+			want: confmap.NewFromStringMap(map[string]any{
+				"certificate_file_path": "/ca/bundle",
+				"emf_only":              true,
+				"imds_retries":          1,
+				"log_group_name":        "emf/logs/default",
+				"log_stream_name":       "some_instance_id",
+				"max_retries":           5,
+				"middleware":            "agenthealth/logs",
+				"profile":               "some_profile",
+				"raw_log":               true,
+				"region":                "us-east-1",
+				"retry_on_failure": map[string]any{
+					"initial_interval": "1s",
+					"max_interval":     "1m",
+					"max_elapsed_time": "10m",
+				},
+				"role_arn":                "global_arn",
+				"shared_credentials_file": "/some/credentials",
+			}),
+		},
 		"WithLogStreamName/Basic": {
 			input: map[string]any{
 				"logs": map[string]any{
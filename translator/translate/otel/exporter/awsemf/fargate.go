@@ -0,0 +1,41 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package awsemf
+
+import (
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awsemfexporter"
+)
+
+// getFargateMetricDeclarations returns the declarations for the pod and
+// container metrics reported by the eksfargatestats receiver. Fargate pods
+// run on their own nodes, so there are no node or cluster level metrics.
+func getFargateMetricDeclarations() []*awsemfexporter.MetricDeclaration {
+	return []*awsemfexporter.MetricDeclaration{
+		{
+			Dimensions: [][]string{
+				{"ClusterName"},
+				{"ClusterName", "Namespace"},
+				{"PodName", "Namespace", "ClusterName"},
+				{"FullPodName", "PodName", "Namespace", "ClusterName"},
+				{"ClusterName", "LaunchType"},
+			},
+			MetricNameSelectors: []string{
+				"pod_cpu_utilization", "pod_cpu_utilization_over_pod_limit", "pod_cpu_usage_total", "pod_cpu_limit",
+				"pod_memory_utilization", "pod_memory_utilization_over_pod_limit", "pod_memory_working_set", "pod_memory_limit",
+				"pod_network_rx_bytes", "pod_network_tx_bytes",
+			},
+		},
+		{
+			Dimensions: [][]string{
+				{"ClusterName"},
+				{"ContainerName", "PodName", "Namespace", "ClusterName"},
+				{"ContainerName", "FullPodName", "PodName", "Namespace", "ClusterName"},
+			},
+			MetricNameSelectors: []string{
+				"container_cpu_utilization_over_container_limit", "container_cpu_usage_total", "container_cpu_limit",
+				"container_memory_utilization_over_container_limit", "container_memory_working_set", "container_memory_limit",
+			},
+		},
+	}
+}
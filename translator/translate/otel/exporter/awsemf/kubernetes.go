@@ -7,10 +7,16 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awsemfexporter"
 	"go.opentelemetry.io/collector/confmap"
 
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/awscontainerinsight"
 )
 
 func setKubernetesMetricDeclaration(conf *confmap.Conf, cfg *awsemfexporter.Config) error {
+	if common.FargateEnabled(conf) {
+		cfg.MetricDeclarations = getFargateMetricDeclarations()
+		return nil
+	}
+
 	var kubernetesMetricDeclarations []*awsemfexporter.MetricDeclaration
 	// For all the supported metrics in K8s container insights, please see the following:
 	// * https://docs.aws.amazon.com/AmazonCloudWatch/latest/monitoring/Container-Insights-metrics-EKS.html
@@ -475,13 +481,16 @@ func getControlPlaneMetricDescriptors(conf *confmap.Conf) []awsemfexporter.Metri
 
 }
 
+// getGPUMetricDeclarations returns the declarations of the GPU metrics. The MIG
+// (Multi-Instance GPU) partitions of a GPU device are also reported with their
+// GpuInstanceId and ComputeInstanceId.
 func getGPUMetricDeclarations(conf *confmap.Conf) []*awsemfexporter.MetricDeclaration {
 	var metricDeclarations []*awsemfexporter.MetricDeclaration
 	enhancedContainerInsightsEnabled := awscontainerinsight.EnhancedContainerInsightsEnabled(conf)
 	if awscontainerinsight.AcceleratedComputeMetricsEnabled(conf) && enhancedContainerInsightsEnabled {
 		metricDeclarations = append(metricDeclarations, []*awsemfexporter.MetricDeclaration{
 			{
-				Dimensions: [][]string{{"ClusterName"}, {"ClusterName", "Namespace", "PodName", "ContainerName"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "ContainerName"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "ContainerName", "GpuDevice"}},
+				Dimensions: [][]string{{"ClusterName"}, {"ClusterName", "Namespace", "PodName", "ContainerName"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "ContainerName"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "ContainerName", "GpuDevice"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "ContainerName", "GpuDevice", "GpuInstanceId"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "ContainerName", "GpuDevice", "GpuInstanceId", "ComputeInstanceId"}},
 				MetricNameSelectors: []string{
 					"container_gpu_utilization",
 					"container_gpu_memory_utilization",
@@ -492,7 +501,7 @@ func getGPUMetricDeclarations(conf *confmap.Conf) []*awsemfexporter.MetricDeclar
 				},
 			},
 			{
-				Dimensions: [][]string{{"ClusterName"}, {"ClusterName", "Namespace"}, {"ClusterName", "Namespace", "Service"}, {"ClusterName", "Namespace", "PodName"}, {"ClusterName", "Namespace", "PodName", "FullPodName"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "GpuDevice"}},
+				Dimensions: [][]string{{"ClusterName"}, {"ClusterName", "Namespace"}, {"ClusterName", "Namespace", "Service"}, {"ClusterName", "Namespace", "PodName"}, {"ClusterName", "Namespace", "PodName", "FullPodName"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "GpuDevice"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "GpuDevice", "GpuInstanceId"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "GpuDevice", "GpuInstanceId", "ComputeInstanceId"}},
 				MetricNameSelectors: []string{
 					"pod_gpu_utilization",
 					"pod_gpu_memory_utilization",
@@ -503,7 +512,7 @@ func getGPUMetricDeclarations(conf *confmap.Conf) []*awsemfexporter.MetricDeclar
 				},
 			},
 			{
-				Dimensions: [][]string{{"ClusterName"}, {"ClusterName", "NodeName", "InstanceId"}, {"ClusterName", "NodeName", "InstanceId", "InstanceType", "GpuDevice"}},
+				Dimensions: [][]string{{"ClusterName"}, {"ClusterName", "NodeName", "InstanceId"}, {"ClusterName", "NodeName", "InstanceId", "InstanceType", "GpuDevice"}, {"ClusterName", "NodeName", "InstanceId", "InstanceType", "GpuDevice", "GpuInstanceId"}, {"ClusterName", "NodeName", "InstanceId", "InstanceType", "GpuDevice", "GpuInstanceId", "ComputeInstanceId"}},
 				MetricNameSelectors: []string{
 					"node_gpu_utilization",
 					"node_gpu_memory_utilization",
@@ -0,0 +1,70 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package awsemf
+
+import (
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awsemfexporter"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func setKubernetesNodeHealthMetricDeclaration(_ *confmap.Conf, cfg *awsemfexporter.Config) error {
+	cfg.MetricDeclarations = getNodeHealthMetricDeclarations()
+	cfg.MetricDescriptors = getNodeHealthMetricDescriptors()
+	return nil
+}
+
+func getNodeHealthMetricDeclarations() []*awsemfexporter.MetricDeclaration {
+	return []*awsemfexporter.MetricDeclaration{
+		{
+			Dimensions: [][]string{
+				{"ClusterName"},
+				{"ClusterName", "NodeName"},
+			},
+			MetricNameSelectors: []string{
+				"kubelet_pleg_relist_duration_seconds",
+			},
+		},
+		{
+			Dimensions: [][]string{
+				{"ClusterName"},
+				{"ClusterName", "NodeName"},
+				{"ClusterName", "NodeName", "operation_type"},
+			},
+			MetricNameSelectors: []string{
+				"kubelet_runtime_operations_errors_total",
+			},
+		},
+		{
+			Dimensions: [][]string{
+				{"ClusterName"},
+				{"ClusterName", "NodeName"},
+				{"ClusterName", "NodeName", "grpc_service"},
+			},
+			MetricNameSelectors: []string{
+				"containerd_grpc_server_handled_total",
+			},
+		},
+	}
+}
+
+func getNodeHealthMetricDescriptors() []awsemfexporter.MetricDescriptor {
+	// the kubelet and containerd metrics do not have units so we need to add them manually
+	return []awsemfexporter.MetricDescriptor{
+		{
+			MetricName: "kubelet_pleg_relist_duration_seconds",
+			Unit:       "Seconds",
+			Overwrite:  true,
+		},
+		{
+			MetricName: "kubelet_runtime_operations_errors_total",
+			Unit:       "Count",
+			Overwrite:  true,
+		},
+		{
+			MetricName: "containerd_grpc_server_handled_total",
+			Unit:       "Count",
+			Overwrite:  true,
+		},
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awsemfexporter"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
@@ -25,7 +26,9 @@ import (
 )
 
 const (
-	kueuePipelineName = "kueueContainerInsights"
+	kueuePipelineName      = "kueueContainerInsights"
+	nodeHealthPipelineName = "nodeHealthContainerInsights"
+	ecsTaskPipelineName    = "ecsTaskContainerInsights"
 )
 
 //go:embed awsemf_default_generic.yaml
@@ -34,12 +37,18 @@ var defaultGenericConfig string
 //go:embed awsemf_default_ecs.yaml
 var defaultEcsConfig string
 
+//go:embed awsemf_default_ecs_task.yaml
+var defaultEcsTaskConfig string
+
 //go:embed awsemf_default_kubernetes.yaml
 var defaultKubernetesConfig string
 
 //go:embed awsemf_default_kubernetes_kueue.yaml
 var defaultKubernetesKueueConfig string
 
+//go:embed awsemf_default_kubernetes_node_health.yaml
+var defaultKubernetesNodeHealthConfig string
+
 //go:embed awsemf_default_prometheus.yaml
 var defaultPrometheusConfig string
 
@@ -49,6 +58,12 @@ var appSignalsConfigGeneric string
 //go:embed awsemf_jmx_config.yaml
 var defaultJmxConfig string
 
+//go:embed awsemf_default_internal_metrics.yaml
+var defaultInternalMetricsConfig string
+
+//go:embed awsemf_default_span_metrics.yaml
+var defaultSpanMetricsConfig string
+
 var (
 	ecsBasePathKey             = common.ConfigKey(common.LogsKey, common.MetricsCollectedKey, common.ECSKey)
 	kubernetesBasePathKey      = common.ConfigKey(common.LogsKey, common.MetricsCollectedKey, common.KubernetesKey)
@@ -57,6 +72,9 @@ var (
 	emfProcessorBasePathKey    = common.ConfigKey(prometheusBasePathKey, common.EMFProcessorKey)
 	endpointOverrideKey        = common.ConfigKey(common.LogsKey, common.EndpointOverrideKey)
 	roleARNPathKey             = common.ConfigKey(common.LogsKey, common.CredentialsKey, common.RoleARNKey)
+	emfRoleARNPathKey          = common.ConfigKey(common.LogsKey, common.EMFDestinationKey, common.RoleARNKey)
+	emfRegionPathKey           = common.ConfigKey(common.LogsKey, common.EMFDestinationKey, common.Region)
+	emfTagsPathKey             = common.ConfigKey(common.LogsKey, common.EMFDestinationKey, common.Tags)
 )
 
 type translator struct {
@@ -88,10 +106,18 @@ func (t *translator) Translate(c *confmap.Conf) (component.Config, error) {
 		defaultConfig = appSignalsConfigGeneric
 	} else if t.isCiJMX(c) {
 		defaultConfig = defaultJmxConfig
+	} else if t.isInternalMetrics(c) {
+		defaultConfig = defaultInternalMetricsConfig
+	} else if t.isSpanMetrics(c) {
+		defaultConfig = defaultSpanMetricsConfig
+	} else if isEcsTask(c, t.name) {
+		defaultConfig = defaultEcsTaskConfig
 	} else if isEcs(c) {
 		defaultConfig = defaultEcsConfig
 	} else if isKubernetesKueue(c, t.name) {
 		defaultConfig = defaultKubernetesKueueConfig
+	} else if isKubernetesNodeHealth(c, t.name) {
+		defaultConfig = defaultKubernetesNodeHealthConfig
 	} else if isKubernetes(c) {
 		defaultConfig = defaultKubernetesConfig
 	} else if isPrometheus(c) {
@@ -109,10 +135,13 @@ func (t *translator) Translate(c *confmap.Conf) (component.Config, error) {
 		}
 	}
 	cfg.AWSSessionSettings.CertificateFilePath = os.Getenv(envconfig.AWS_CA_BUNDLE)
-	if c.IsSet(endpointOverrideKey) {
-		cfg.AWSSessionSettings.Endpoint, _ = common.GetString(c, endpointOverrideKey)
+	// the exporter does not fail over, it only uses the primary endpoint
+	if endpoints := common.GetEndpointOverrides(c, endpointOverrideKey); len(endpoints) > 0 {
+		cfg.AWSSessionSettings.Endpoint = endpoints[0]
 	}
 	cfg.AWSSessionSettings.IMDSRetries = retryer.GetDefaultRetryNumber()
+	// the EMF logs are retried like the other logs
+	common.GetRetryConfig(c, common.LogsKey).ApplyMaxRetries(&cfg.AWSSessionSettings.MaxRetries)
 	if profileKey, ok := agent.Global_Config.Credentials[agent.Profile_Key]; ok {
 		cfg.AWSSessionSettings.Profile = fmt.Sprintf("%v", profileKey)
 	}
@@ -121,6 +150,23 @@ func (t *translator) Translate(c *confmap.Conf) (component.Config, error) {
 	if c.IsSet(roleARNPathKey) {
 		cfg.AWSSessionSettings.RoleARN, _ = common.GetString(c, roleARNPathKey)
 	}
+	// the EMF logs can be published to another account or region than the other logs
+	if c.IsSet(emfRoleARNPathKey) {
+		cfg.AWSSessionSettings.RoleARN, _ = common.GetString(c, emfRoleARNPathKey)
+	}
+	if c.IsSet(emfRegionPathKey) {
+		cfg.AWSSessionSettings.Region, _ = common.GetString(c, emfRegionPathKey)
+	}
+	// the exporter adds the tags to the log groups it creates
+	if tags, ok := c.Get(emfTagsPathKey).(map[string]any); ok && len(tags) > 0 {
+		cfg.Tags = make(map[string]*string, len(tags))
+		for key, value := range tags {
+			cfg.Tags[key] = aws.String(fmt.Sprint(value))
+		}
+	}
+	if proxyAddress, ok := common.GetServiceProxyAddress(c, common.LogsKey); ok {
+		cfg.AWSSessionSettings.ProxyAddress = proxyAddress
+	}
 	if credentialsFileKey, ok := agent.Global_Config.Credentials[agent.CredentialsFile_Key]; ok {
 		cfg.AWSSessionSettings.SharedCredentialsFile = []string{fmt.Sprintf("%v", credentialsFileKey)}
 	}
@@ -136,6 +182,14 @@ func (t *translator) Translate(c *confmap.Conf) (component.Config, error) {
 		if err := setCiJmxFields(); err != nil {
 			return nil, err
 		}
+	} else if t.isInternalMetrics(c) {
+		setInternalMetricsFields(c, cfg)
+	} else if t.isSpanMetrics(c) {
+		setSpanMetricsFields(c, cfg)
+	} else if isEcsTask(c, t.name) {
+		if err := setEcsFields(c, cfg); err != nil {
+			return nil, err
+		}
 	} else if isEcs(c) {
 		if err := setEcsFields(c, cfg); err != nil {
 			return nil, err
@@ -144,6 +198,10 @@ func (t *translator) Translate(c *confmap.Conf) (component.Config, error) {
 		if err := setKubernetesKueueFields(c, cfg); err != nil {
 			return nil, err
 		}
+	} else if isKubernetesNodeHealth(c, t.name) {
+		if err := setKubernetesNodeHealthFields(c, cfg); err != nil {
+			return nil, err
+		}
 	} else if isKubernetes(c) {
 		if err := setKubernetesFields(c, cfg); err != nil {
 			return nil, err
@@ -163,10 +221,28 @@ func (t *translator) isCiJMX(conf *confmap.Conf) bool {
 	return (t.name == common.PipelineNameContainerInsightsJmx) && (conf.IsSet(common.ContainerInsightsConfigKey))
 }
 
+// isInternalMetrics is checked before the other sections, which do not apply
+// to the agent's own metrics.
+func (t *translator) isInternalMetrics(conf *confmap.Conf) bool {
+	return t.name == common.PipelineNameInternalMetrics && conf.IsSet(common.InternalMetricsConfigKey)
+}
+
+// isSpanMetrics is checked before the other sections, which do not apply to
+// the metrics of the spans.
+func (t *translator) isSpanMetrics(conf *confmap.Conf) bool {
+	return t.name == common.PipelineNameSpanMetrics && conf.IsSet(common.SpanMetricsConfigKey)
+}
+
 func isEcs(conf *confmap.Conf) bool {
 	return conf.IsSet(ecsBasePathKey)
 }
 
+// `task_network_metrics` and `service_connect_metrics` are children of `ecs` in config spec.
+func isEcsTask(conf *confmap.Conf, pipelineName string) bool {
+	return isEcs(conf) && pipelineName == ecsTaskPipelineName &&
+		(common.TaskNetworkMetricsEnabled(conf) || common.ServiceConnectMetricsEnabled(conf))
+}
+
 func isKubernetes(conf *confmap.Conf) bool {
 	return conf.IsSet(kubernetesBasePathKey)
 }
@@ -176,6 +252,11 @@ func isKubernetesKueue(conf *confmap.Conf, pipelineName string) bool {
 	return isKubernetes(conf) && pipelineName == kueuePipelineName && common.GetOrDefaultBool(conf, kubernetesKueueBasePathKey, false)
 }
 
+// `node_health_metrics` is a child of `kubernetes` in config spec.
+func isKubernetesNodeHealth(conf *confmap.Conf, pipelineName string) bool {
+	return isKubernetes(conf) && pipelineName == nodeHealthPipelineName && common.NodeHealthMetricsEnabled(conf)
+}
+
 func isPrometheus(conf *confmap.Conf) bool {
 	return conf.IsSet(prometheusBasePathKey)
 }
@@ -196,13 +277,38 @@ func setKubernetesFields(conf *confmap.Conf, cfg *awsemfexporter.Config) error {
 		return err
 	}
 
-	if awscontainerinsight.EnhancedContainerInsightsEnabled(conf) {
+	if common.FargateEnabled(conf) {
+		// add the kubernetes object to the pod and container logs, like the receiver on EC2 nodes
+		cfg.EKSFargateContainerInsightsEnabled = true
+	} else if awscontainerinsight.EnhancedContainerInsightsEnabled(conf) {
 		cfg.EnhancedContainerInsights = true
 	}
 
 	return nil
 }
 
+var internalMetricsNamespaceKey = common.ConfigKey(common.InternalMetricsConfigKey, common.NamespaceKey)
+
+func setInternalMetricsFields(conf *confmap.Conf, cfg *awsemfexporter.Config) {
+	if namespace, ok := common.GetString(conf, internalMetricsNamespaceKey); ok {
+		cfg.Namespace = namespace
+	}
+}
+
+var (
+	spanMetricsNamespaceKey    = common.ConfigKey(common.SpanMetricsConfigKey, common.NamespaceKey)
+	spanMetricsLogGroupNameKey = common.ConfigKey(common.SpanMetricsConfigKey, common.LogGroupName)
+)
+
+func setSpanMetricsFields(conf *confmap.Conf, cfg *awsemfexporter.Config) {
+	if namespace, ok := common.GetString(conf, spanMetricsNamespaceKey); ok {
+		cfg.Namespace = namespace
+	}
+	if logGroupName, ok := common.GetString(conf, spanMetricsLogGroupNameKey); ok {
+		cfg.LogGroupName = logGroupName
+	}
+}
+
 func setCiJmxFields() error {
 	return nil
 }
@@ -216,6 +322,16 @@ func setKubernetesKueueFields(conf *confmap.Conf, cfg *awsemfexporter.Config) er
 	return nil
 }
 
+func setKubernetesNodeHealthFields(conf *confmap.Conf, cfg *awsemfexporter.Config) error {
+	setDisableMetricExtraction(kubernetesBasePathKey, conf, cfg)
+
+	if err := setKubernetesNodeHealthMetricDeclaration(conf, cfg); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func setPrometheusFields(conf *confmap.Conf, cfg *awsemfexporter.Config) error {
 	setDisableMetricExtraction(prometheusBasePathKey, conf, cfg)
 
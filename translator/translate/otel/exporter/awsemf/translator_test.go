@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awsemfexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
 	"github.com/stretchr/testify/assert"
@@ -403,19 +404,19 @@ func TestTranslator(t *testing.T) {
 						MetricNameSelectors: []string{"apiserver_flowcontrol_request_concurrency_limit"},
 					},
 					{
-						Dimensions: [][]string{{"ClusterName"}, {"ClusterName", "Namespace", "PodName", "ContainerName"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "ContainerName"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "ContainerName", "GpuDevice"}},
+						Dimensions: [][]string{{"ClusterName"}, {"ClusterName", "Namespace", "PodName", "ContainerName"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "ContainerName"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "ContainerName", "GpuDevice"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "ContainerName", "GpuDevice", "GpuInstanceId"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "ContainerName", "GpuDevice", "GpuInstanceId", "ComputeInstanceId"}},
 						MetricNameSelectors: []string{
 							"container_gpu_utilization", "container_gpu_memory_utilization", "container_gpu_memory_total", "container_gpu_memory_used", "container_gpu_power_draw", "container_gpu_temperature",
 						},
 					},
 					{
-						Dimensions: [][]string{{"ClusterName"}, {"ClusterName", "Namespace"}, {"ClusterName", "Namespace", "Service"}, {"ClusterName", "Namespace", "PodName"}, {"ClusterName", "Namespace", "PodName", "FullPodName"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "GpuDevice"}},
+						Dimensions: [][]string{{"ClusterName"}, {"ClusterName", "Namespace"}, {"ClusterName", "Namespace", "Service"}, {"ClusterName", "Namespace", "PodName"}, {"ClusterName", "Namespace", "PodName", "FullPodName"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "GpuDevice"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "GpuDevice", "GpuInstanceId"}, {"ClusterName", "Namespace", "PodName", "FullPodName", "GpuDevice", "GpuInstanceId", "ComputeInstanceId"}},
 						MetricNameSelectors: []string{
 							"pod_gpu_utilization", "pod_gpu_memory_utilization", "pod_gpu_memory_total", "pod_gpu_memory_used", "pod_gpu_power_draw", "pod_gpu_temperature",
 						},
 					},
 					{
-						Dimensions: [][]string{{"ClusterName"}, {"ClusterName", "NodeName", "InstanceId"}, {"ClusterName", "NodeName", "InstanceId", "InstanceType", "GpuDevice"}},
+						Dimensions: [][]string{{"ClusterName"}, {"ClusterName", "NodeName", "InstanceId"}, {"ClusterName", "NodeName", "InstanceId", "InstanceType", "GpuDevice"}, {"ClusterName", "NodeName", "InstanceId", "InstanceType", "GpuDevice", "GpuInstanceId"}, {"ClusterName", "NodeName", "InstanceId", "InstanceType", "GpuDevice", "GpuInstanceId", "ComputeInstanceId"}},
 						MetricNameSelectors: []string{
 							"node_gpu_utilization", "node_gpu_memory_utilization", "node_gpu_memory_total", "node_gpu_memory_used", "node_gpu_power_draw", "node_gpu_temperature",
 						},
@@ -808,6 +809,75 @@ func TestTranslator(t *testing.T) {
 	}
 }
 
+func TestTranslatorEMFDestination(t *testing.T) {
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.Role_arn = "global_arn"
+	tt := NewTranslator()
+	testCases := map[string]struct {
+		input      map[string]any
+		wantRole   string
+		wantRegion string
+		wantTags   map[string]*string
+	}{
+		"WithoutDestination": {
+			input: map[string]any{
+				"logs": map[string]any{
+					"credentials": map[string]any{"role_arn": "logs_arn"},
+				},
+			},
+			wantRole:   "logs_arn",
+			wantRegion: "us-east-1",
+		},
+		"WithDestination": {
+			input: map[string]any{
+				"logs": map[string]any{
+					"credentials": map[string]any{"role_arn": "logs_arn"},
+					"emf_destination": map[string]any{
+						"role_arn": "arn:aws:iam::222222222222:role/central-metrics",
+						"region":   "eu-west-1",
+					},
+				},
+			},
+			wantRole:   "arn:aws:iam::222222222222:role/central-metrics",
+			wantRegion: "eu-west-1",
+		},
+		"WithDestinationRegion": {
+			input: map[string]any{
+				"logs": map[string]any{
+					"emf_destination": map[string]any{
+						"region": "eu-west-1",
+					},
+				},
+			},
+			wantRole:   "global_arn",
+			wantRegion: "eu-west-1",
+		},
+		"WithDestinationTags": {
+			input: map[string]any{
+				"logs": map[string]any{
+					"emf_destination": map[string]any{
+						"tags": map[string]any{"team": "platform"},
+					},
+				},
+			},
+			wantRole:   "global_arn",
+			wantRegion: "us-east-1",
+			wantTags:   map[string]*string{"team": aws.String("platform")},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.Translate(confmap.NewFromStringMap(testCase.input))
+			require.NoError(t, err)
+			gotCfg, ok := got.(*awsemfexporter.Config)
+			require.True(t, ok)
+			assert.Equal(t, testCase.wantRole, gotCfg.RoleARN)
+			assert.Equal(t, testCase.wantRegion, gotCfg.Region)
+			assert.Equal(t, testCase.wantTags, gotCfg.Tags)
+		})
+	}
+}
+
 func TestTranslatorForKueue(t *testing.T) {
 	t.Setenv(envconfig.AWS_CA_BUNDLE, "/ca/bundle")
 	agent.Global_Config.Region = "us-east-1"
@@ -1069,3 +1139,243 @@ func TestTranslateAppSignals(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslatorForNodeHealth(t *testing.T) {
+	agent.Global_Config.Region = "us-east-1"
+	tt := NewTranslatorWithName("nodeHealthContainerInsights")
+	require.EqualValues(t, "awsemf/nodeHealthContainerInsights", tt.ID().String())
+	conf := confmap.NewFromStringMap(map[string]any{
+		"logs": map[string]any{
+			"metrics_collected": map[string]any{
+				"kubernetes": map[string]any{
+					"node_health_metrics": true,
+				},
+			},
+		},
+	})
+	got, err := tt.Translate(conf)
+	require.NoError(t, err)
+	gotCfg, ok := got.(*awsemfexporter.Config)
+	require.True(t, ok)
+	assert.Equal(t, "ContainerInsights", gotCfg.Namespace)
+	assert.Equal(t, "/aws/containerinsights/{ClusterName}/performance", gotCfg.LogGroupName)
+	assert.Equal(t, "kubernetes-node-health-{NodeName}", gotCfg.LogStreamName)
+	assert.Equal(t, "NoDimensionRollup", gotCfg.DimensionRollupOption)
+	assert.False(t, gotCfg.DisableMetricExtraction)
+	assert.False(t, gotCfg.EnhancedContainerInsights)
+	assert.True(t, gotCfg.ResourceToTelemetrySettings.Enabled)
+	assert.ElementsMatch(t, []*awsemfexporter.MetricDeclaration{
+		{
+			Dimensions:          [][]string{{"ClusterName"}, {"ClusterName", "NodeName"}},
+			MetricNameSelectors: []string{"kubelet_pleg_relist_duration_seconds"},
+		},
+		{
+			Dimensions:          [][]string{{"ClusterName"}, {"ClusterName", "NodeName"}, {"ClusterName", "NodeName", "operation_type"}},
+			MetricNameSelectors: []string{"kubelet_runtime_operations_errors_total"},
+		},
+		{
+			Dimensions:          [][]string{{"ClusterName"}, {"ClusterName", "NodeName"}, {"ClusterName", "NodeName", "grpc_service"}},
+			MetricNameSelectors: []string{"containerd_grpc_server_handled_total"},
+		},
+	}, gotCfg.MetricDeclarations)
+	assert.ElementsMatch(t, []awsemfexporter.MetricDescriptor{
+		{MetricName: "kubelet_pleg_relist_duration_seconds", Unit: "Seconds", Overwrite: true},
+		{MetricName: "kubelet_runtime_operations_errors_total", Unit: "Count", Overwrite: true},
+		{MetricName: "containerd_grpc_server_handled_total", Unit: "Count", Overwrite: true},
+	}, gotCfg.MetricDescriptors)
+
+	// the main container insights pipeline is not affected
+	got, err = NewTranslatorWithName(common.PipelineNameContainerInsights).Translate(conf)
+	require.NoError(t, err)
+	assert.Equal(t, "{NodeName}", got.(*awsemfexporter.Config).LogStreamName)
+}
+
+func TestTranslatorWithRetry(t *testing.T) {
+	agent.Global_Config.Region = "us-east-1"
+	tt := NewTranslatorWithName(common.PipelineNameContainerInsights)
+	conf := confmap.NewFromStringMap(map[string]any{
+		"agent": map[string]any{"retry": map[string]any{"max_retries": 8}},
+		"logs": map[string]any{
+			"metrics_collected": map[string]any{
+				"kubernetes": map[string]any{
+					"cluster_name": "TestCluster",
+				},
+			},
+			"retry": map[string]any{"max_retries": 6},
+		},
+	})
+	got, err := tt.Translate(conf)
+	require.NoError(t, err)
+	assert.Equal(t, 6, got.(*awsemfexporter.Config).AWSSessionSettings.MaxRetries)
+}
+
+func TestTranslatorForFargate(t *testing.T) {
+	agent.Global_Config.Region = "us-east-1"
+	tt := NewTranslatorWithName(common.PipelineNameContainerInsights)
+	conf := confmap.NewFromStringMap(map[string]any{
+		"logs": map[string]any{
+			"metrics_collected": map[string]any{
+				"kubernetes": map[string]any{
+					"fargate":                     true,
+					"enhanced_container_insights": true,
+					"cluster_name":                "TestCluster",
+				},
+			},
+		},
+	})
+	got, err := tt.Translate(conf)
+	require.NoError(t, err)
+	gotCfg, ok := got.(*awsemfexporter.Config)
+	require.True(t, ok)
+	assert.Equal(t, "ContainerInsights", gotCfg.Namespace)
+	assert.Equal(t, "/aws/containerinsights/{ClusterName}/performance", gotCfg.LogGroupName)
+	assert.Equal(t, "{NodeName}", gotCfg.LogStreamName)
+	assert.False(t, gotCfg.EnhancedContainerInsights)
+	assert.True(t, gotCfg.EKSFargateContainerInsightsEnabled)
+	assert.Empty(t, gotCfg.MetricDescriptors)
+	require.Len(t, gotCfg.MetricDeclarations, 2)
+	assert.Equal(t, [][]string{
+		{"ClusterName"},
+		{"ClusterName", "Namespace"},
+		{"PodName", "Namespace", "ClusterName"},
+		{"FullPodName", "PodName", "Namespace", "ClusterName"},
+		{"ClusterName", "LaunchType"},
+	}, gotCfg.MetricDeclarations[0].Dimensions)
+	assert.Contains(t, gotCfg.MetricDeclarations[0].MetricNameSelectors, "pod_cpu_utilization")
+	assert.Contains(t, gotCfg.MetricDeclarations[0].MetricNameSelectors, "pod_network_rx_bytes")
+	assert.Equal(t, [][]string{
+		{"ClusterName"},
+		{"ContainerName", "PodName", "Namespace", "ClusterName"},
+		{"ContainerName", "FullPodName", "PodName", "Namespace", "ClusterName"},
+	}, gotCfg.MetricDeclarations[1].Dimensions)
+	assert.Contains(t, gotCfg.MetricDeclarations[1].MetricNameSelectors, "container_memory_working_set")
+}
+
+func TestTranslatorForEcsTask(t *testing.T) {
+	agent.Global_Config.Region = "us-east-1"
+	tt := NewTranslatorWithName("ecsTaskContainerInsights")
+	require.EqualValues(t, "awsemf/ecsTaskContainerInsights", tt.ID().String())
+	conf := confmap.NewFromStringMap(map[string]any{
+		"logs": map[string]any{
+			"metrics_collected": map[string]any{
+				"ecs": map[string]any{
+					"task_network_metrics":    true,
+					"service_connect_metrics": true,
+				},
+			},
+		},
+	})
+	got, err := tt.Translate(conf)
+	require.NoError(t, err)
+	gotCfg, ok := got.(*awsemfexporter.Config)
+	require.True(t, ok)
+	assert.Equal(t, "ECS/ContainerInsights", gotCfg.Namespace)
+	assert.Equal(t, "/aws/ecs/containerinsights/{ClusterName}/performance", gotCfg.LogGroupName)
+	assert.Equal(t, "TaskTelemetry-{TaskId}", gotCfg.LogStreamName)
+	assert.Equal(t, "NoDimensionRollup", gotCfg.DimensionRollupOption)
+	assert.False(t, gotCfg.DisableMetricExtraction)
+	assert.True(t, gotCfg.ResourceToTelemetrySettings.Enabled)
+	assert.ElementsMatch(t, []*awsemfexporter.MetricDeclaration{
+		{
+			Dimensions: [][]string{{"ClusterName"}, {"ClusterName", "TaskDefinitionFamily"}, {"ClusterName", "TaskDefinitionFamily", "TaskId"}},
+			MetricNameSelectors: []string{
+				"task_network_rx_bytes", "task_network_rx_packets", "task_network_rx_errors", "task_network_rx_dropped",
+				"task_network_tx_bytes", "task_network_tx_packets", "task_network_tx_errors", "task_network_tx_dropped",
+				"task_network_total_bytes",
+			},
+		},
+		{
+			Dimensions:          [][]string{{"ClusterName"}, {"ClusterName", "TaskDefinitionFamily"}},
+			MetricNameSelectors: []string{"^service_connect_"},
+		},
+	}, gotCfg.MetricDeclarations)
+
+	// the main container insights pipeline is not affected
+	got, err = NewTranslatorWithName(common.PipelineNameContainerInsights).Translate(conf)
+	require.NoError(t, err)
+	assert.Equal(t, "NodeTelemetry-{ContainerInstanceId}", got.(*awsemfexporter.Config).LogStreamName)
+}
+
+func TestTranslatorForInternalMetrics(t *testing.T) {
+	agent.Global_Config.Region = "us-east-1"
+	tt := NewTranslatorWithName(common.PipelineNameInternalMetrics)
+	require.EqualValues(t, "awsemf/internal_metrics", tt.ID().String())
+	testCases := map[string]struct {
+		input         map[string]any
+		wantNamespace string
+	}{
+		"WithDefaultNamespace": {
+			input: map[string]any{
+				"agent": map[string]any{"internal_metrics": map[string]any{}},
+				// the agent's own metrics are not published like the ECS ones
+				"logs": map[string]any{"metrics_collected": map[string]any{"ecs": map[string]any{}}},
+			},
+			wantNamespace: "CWAgent/SelfTelemetry",
+		},
+		"WithNamespace": {
+			input: map[string]any{
+				"agent": map[string]any{"internal_metrics": map[string]any{"namespace": "MyAgents"}},
+			},
+			wantNamespace: "MyAgents",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.Translate(confmap.NewFromStringMap(testCase.input))
+			require.NoError(t, err)
+			gotCfg, ok := got.(*awsemfexporter.Config)
+			require.True(t, ok)
+			assert.Equal(t, testCase.wantNamespace, gotCfg.Namespace)
+			assert.Equal(t, "/aws/cwagent/internal_metrics", gotCfg.LogGroupName)
+			assert.Equal(t, "{host}", gotCfg.LogStreamName)
+			assert.Equal(t, "NoDimensionRollup", gotCfg.DimensionRollupOption)
+			assert.Equal(t, "1", gotCfg.Version)
+			assert.True(t, gotCfg.ResourceToTelemetrySettings.Enabled)
+		})
+	}
+}
+
+func TestTranslatorForSpanMetrics(t *testing.T) {
+	agent.Global_Config.Region = "us-east-1"
+	tt := NewTranslatorWithName(common.PipelineNameSpanMetrics)
+	require.EqualValues(t, "awsemf/span_metrics", tt.ID().String())
+	testCases := map[string]struct {
+		input         map[string]any
+		wantNamespace string
+		wantLogGroup  string
+	}{
+		"WithDefaults": {
+			input: map[string]any{
+				"traces": map[string]any{"span_metrics": map[string]any{}},
+				// the span metrics are not published like the Kubernetes ones
+				"logs": map[string]any{"metrics_collected": map[string]any{"kubernetes": map[string]any{}}},
+			},
+			wantNamespace: "CWAgent/SpanMetrics",
+			wantLogGroup:  "/aws/cwagent/span_metrics",
+		},
+		"WithNamespaceAndLogGroup": {
+			input: map[string]any{
+				"traces": map[string]any{"span_metrics": map[string]any{
+					"namespace":      "Shop/RED",
+					"log_group_name": "/shop/span_metrics",
+				}},
+			},
+			wantNamespace: "Shop/RED",
+			wantLogGroup:  "/shop/span_metrics",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.Translate(confmap.NewFromStringMap(testCase.input))
+			require.NoError(t, err)
+			gotCfg, ok := got.(*awsemfexporter.Config)
+			require.True(t, ok)
+			assert.Equal(t, testCase.wantNamespace, gotCfg.Namespace)
+			assert.Equal(t, testCase.wantLogGroup, gotCfg.LogGroupName)
+			assert.Equal(t, "{host}", gotCfg.LogStreamName)
+			assert.Equal(t, "NoDimensionRollup", gotCfg.DimensionRollupOption)
+			// the host is only used for the log stream name
+			assert.False(t, gotCfg.ResourceToTelemetrySettings.Enabled)
+		})
+	}
+}
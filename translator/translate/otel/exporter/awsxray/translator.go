@@ -26,10 +26,13 @@ const (
 	concurrencyKey              = "concurrency"
 	resourceARNKey              = "resource_arn"
 	transitSpansInOtlpFormatKey = "transit_spans_in_otlp_format"
+	regionKey                   = "region"
+	endpointKey                 = "endpoint"
 )
 
 type translator struct {
 	name    string
+	index   int
 	factory exporter.Factory
 }
 
@@ -48,7 +51,14 @@ func NewTranslator() common.Translator[component.Config] {
 }
 
 func NewTranslatorWithName(name string) common.Translator[component.Config] {
-	return &translator{name, awsxrayexporter.NewFactory()}
+	return NewTranslatorWithDestination(name, -1)
+}
+
+// NewTranslatorWithDestination creates a translator for the X-Ray destination
+// at the index of the traces destinations. The region, endpoint and
+// credentials of the destination take precedence over the traces section.
+func NewTranslatorWithDestination(name string, index int) common.Translator[component.Config] {
+	return &translator{name, index, awsxrayexporter.NewFactory()}
 }
 
 func (t *translator) ID() component.ID {
@@ -78,10 +88,17 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 		return nil, fmt.Errorf("unable to unmarshal into awsxrayexporter config: %w", err)
 	}
 	cfg.AWSSessionSettings.CertificateFilePath = os.Getenv(envconfig.AWS_CA_BUNDLE)
-	if endpointOverride, ok := common.GetString(conf, common.ConfigKey(common.TracesKey, common.EndpointOverrideKey)); ok {
-		cfg.AWSSessionSettings.Endpoint = endpointOverride
+	_, destination := common.GetTracesDestination(conf, t.index)
+	if endpoint, ok := destination[endpointKey].(string); ok {
+		cfg.AWSSessionSettings.Endpoint = endpoint
+	} else if _, ok = destination[regionKey]; !ok {
+		// the endpoint override of the traces section points to its region
+		if endpointOverride, ok := common.GetString(conf, common.ConfigKey(common.TracesKey, common.EndpointOverrideKey)); ok {
+			cfg.AWSSessionSettings.Endpoint = endpointOverride
+		}
 	}
 	cfg.AWSSessionSettings.IMDSRetries = retryer.GetDefaultRetryNumber()
+	common.GetRetryConfig(conf, common.TracesKey).ApplyMaxRetries(&cfg.AWSSessionSettings.MaxRetries)
 	if context.CurrentContext().Mode() == config.ModeOnPrem || context.CurrentContext().Mode() == config.ModeOnPremise {
 		cfg.AWSSessionSettings.LocalMode = true
 	}
@@ -97,6 +114,9 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 	if profileKey, ok := agent.Global_Config.Credentials[agent.Profile_Key]; ok {
 		cfg.AWSSessionSettings.Profile = fmt.Sprintf("%v", profileKey)
 	}
+	if proxyAddress, ok := common.GetServiceProxyAddress(conf, common.XrayKey); ok {
+		cfg.AWSSessionSettings.ProxyAddress = proxyAddress
+	}
 	if proxyAddress, ok := common.GetString(conf, common.ConfigKey(common.TracesKey, common.ProxyOverrideKey)); ok {
 		cfg.AWSSessionSettings.ProxyAddress = proxyAddress
 	}
@@ -107,7 +127,15 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 		cfg.TransitSpansInOtlpFormat = transitOtlp
 	}
 	cfg.AWSSessionSettings.Region = getRegion(conf)
+	if region, ok := destination[regionKey].(string); ok {
+		cfg.AWSSessionSettings.Region = region
+	}
 	cfg.AWSSessionSettings.RoleARN = getRoleARN(conf)
+	if credentials, ok := destination[common.CredentialsKey].(map[string]any); ok {
+		if roleARN, ok := credentials[common.RoleARNKey].(string); ok {
+			cfg.AWSSessionSettings.RoleARN = roleARN
+		}
+	}
 	if credentialsFileKey, ok := agent.Global_Config.Credentials[agent.CredentialsFile_Key]; ok {
 		cfg.AWSSessionSettings.SharedCredentialsFile = []string{fmt.Sprintf("%v", credentialsFileKey)}
 	}
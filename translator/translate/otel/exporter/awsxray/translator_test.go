@@ -57,6 +57,26 @@ func TestTranslator(t *testing.T) {
 			}),
 			mode: config.ModeOnPrem,
 		},
+		"WithRetry": {
+			input: map[string]any{
+				"agent":  map[string]any{"retry": map[string]any{"max_retries": 5}},
+				"traces": map[string]any{},
+			},
+			want: confmap.NewFromStringMap(map[string]any{
+				"certificate_file_path": "/ca/bundle",
+				"region":                "us-east-1",
+				"local_mode":            "true",
+				"role_arn":              "global_arn",
+				"imds_retries":          1,
+				"max_retries":           5,
+				"telemetry": map[string]any{
+					"enabled":          true,
+					"include_metadata": true,
+				},
+				"middleware": "agenthealth/traces",
+			}),
+			mode: config.ModeOnPrem,
+		},
 		"WithCompleteConfig": {
 			input: testutil.GetJson(t, filepath.Join("testdata", "config.json")),
 			want:  testutil.GetConf(t, filepath.Join("testdata", "config.yaml")),
@@ -174,3 +194,77 @@ func TestTranslator(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslatorWithDestination(t *testing.T) {
+	t.Setenv(envconfig.AWS_CA_BUNDLE, "/ca/bundle")
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.Role_arn = "global_arn"
+	t.Cleanup(func() {
+		agent.Global_Config = agent.Agent{}
+	})
+	context.CurrentContext().SetMode(config.ModeEC2)
+	type want struct {
+		endpoint string
+		region   string
+		roleARN  string
+	}
+	testCases := map[string]struct {
+		index int
+		want  want
+	}{
+		"WithTracesSettings": {
+			index: 0,
+			want: want{
+				endpoint: "https://xray.us-east-1.amazonaws.com",
+				region:   "eu-west-1",
+				roleARN:  "traces_arn",
+			},
+		},
+		"WithDestinationSettings": {
+			index: 1,
+			want: want{
+				region:  "us-west-2",
+				roleARN: "destination_arn",
+			},
+		},
+		"WithDestinationEndpoint": {
+			index: 2,
+			want: want{
+				endpoint: "https://xray.ap-south-1.amazonaws.com",
+				region:   "ap-south-1",
+				roleARN:  "traces_arn",
+			},
+		},
+	}
+	conf := confmap.NewFromStringMap(map[string]any{
+		"traces": map[string]any{
+			"region_override":   "eu-west-1",
+			"endpoint_override": "https://xray.us-east-1.amazonaws.com",
+			"credentials":       map[string]any{"role_arn": "traces_arn"},
+			"traces_destinations": []any{
+				map[string]any{"xray": map[string]any{}},
+				map[string]any{"xray": map[string]any{
+					"region":      "us-west-2",
+					"credentials": map[string]any{"role_arn": "destination_arn"},
+				}},
+				map[string]any{"xray": map[string]any{
+					"region":   "ap-south-1",
+					"endpoint": "https://xray.ap-south-1.amazonaws.com",
+				}},
+			},
+		},
+	})
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tt := NewTranslatorWithDestination("xray/0", testCase.index)
+			assert.EqualValues(t, "awsxray/xray/0", tt.ID().String())
+			got, err := tt.Translate(conf)
+			require.NoError(t, err)
+			gotCfg, ok := got.(*awsxrayexporter.Config)
+			require.True(t, ok)
+			assert.Equal(t, testCase.want.endpoint, gotCfg.AWSSessionSettings.Endpoint)
+			assert.Equal(t, testCase.want.region, gotCfg.AWSSessionSettings.Region)
+			assert.Equal(t, testCase.want.roleARN, gotCfg.AWSSessionSettings.RoleARN)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlpmetrics
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/exporter"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/otlpmetrics"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	endpointKey    = "endpoint"
+	protocolKey    = "protocol"
+	compressionKey = "compression"
+	timeoutKey     = "timeout"
+	headersKey     = "headers"
+	headersSSMKey  = "headers_ssm"
+	tlsKey         = "tls"
+	insecureKey    = "insecure"
+	caFileKey      = "ca_file"
+	certFileKey    = "cert_file"
+	keyFileKey     = "key_file"
+)
+
+var (
+	SectionKey = common.ConfigKey(common.MetricsKey, common.MetricsDestinationsKey, common.OtlpKey)
+)
+
+type translator struct {
+	name    string
+	index   int
+	factory exporter.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+func NewTranslator() common.Translator[component.Config] {
+	return NewTranslatorWithName("")
+}
+
+func NewTranslatorWithName(name string) common.Translator[component.Config] {
+	return &translator{name, -1, otlpmetrics.NewFactory()}
+}
+
+// NewTracesTranslatorWithName creates a translator for the OTLP traces
+// exporter of the OTLP destination at the index of the traces destinations.
+func NewTracesTranslatorWithName(name string, index int) common.Translator[component.Config] {
+	return &translator{name, index, otlpmetrics.NewTracesFactory()}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates an exporter config based on the fields in the
+// otlp metrics destination section or in the otlp traces destination
+// section of the JSON config.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	section, sectionKey, err := t.getSection(conf)
+	if err != nil {
+		return nil, err
+	}
+	if section == nil || !section.IsSet(endpointKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.ConfigKey(sectionKey, endpointKey)}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*otlpmetrics.Config)
+	cfg.Endpoint, _ = common.GetString(section, endpointKey)
+	if protocol, ok := common.GetString(section, protocolKey); ok {
+		cfg.Protocol = protocol
+	}
+	if compression, ok := common.GetString(section, compressionKey); ok {
+		cfg.Compression = compression
+	}
+	if timeout, ok := common.GetDuration(section, timeoutKey); ok {
+		cfg.Timeout = timeout
+	}
+	common.GetPipelineConfig(conf, common.OtlpKey).ApplyQueue(&cfg.QueueSettings)
+	cfg.TLSSetting.Insecure, _ = common.GetBool(section, common.ConfigKey(tlsKey, insecureKey))
+	cfg.TLSSetting.CAFile, _ = common.GetString(section, common.ConfigKey(tlsKey, caFileKey))
+	cfg.TLSSetting.CertFile, _ = common.GetString(section, common.ConfigKey(tlsKey, certFileKey))
+	cfg.TLSSetting.KeyFile, _ = common.GetString(section, common.ConfigKey(tlsKey, keyFileKey))
+
+	if cfg.Headers, err = getStringMap(section, headersKey); err != nil {
+		return nil, err
+	}
+	if cfg.HeadersSSM, err = getStringMap(section, headersSSMKey); err != nil {
+		return nil, err
+	}
+	// the AWS credentials are only needed to read the SSM parameters
+	if len(cfg.HeadersSSM) > 0 {
+		credentials := confmap.NewFromStringMap(agent.Global_Config.Credentials)
+		_ = credentials.Unmarshal(cfg)
+		cfg.Region = agent.Global_Config.Region
+		if cfg.RoleARN == "" {
+			cfg.RoleARN = agent.Global_Config.Role_arn
+		}
+	}
+	return cfg, nil
+}
+
+// getSection returns the destination section the exporter is configured from
+// and its key in the JSON config.
+func (t *translator) getSection(conf *confmap.Conf) (*confmap.Conf, string, error) {
+	if t.index == -1 {
+		if conf == nil || !conf.IsSet(SectionKey) {
+			return nil, SectionKey, nil
+		}
+		section, err := conf.Sub(SectionKey)
+		return section, SectionKey, err
+	}
+	sectionKey := common.ConfigKey(fmt.Sprintf("%s[%d]", common.TracesDestinationsConfigKey, t.index), common.OtlpKey)
+	if conf == nil {
+		return nil, sectionKey, nil
+	}
+	destinationType, destination := common.GetTracesDestination(conf, t.index)
+	if destinationType != common.OtlpKey {
+		return nil, sectionKey, nil
+	}
+	return confmap.NewFromStringMap(destination), sectionKey, nil
+}
+
+func getStringMap(conf *confmap.Conf, key string) (map[string]string, error) {
+	if !conf.IsSet(key) {
+		return nil, nil
+	}
+	sub, err := conf.Sub(key)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]string
+	if err = sub.Unmarshal(&m); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal %s: %w", key, err)
+	}
+	return m, nil
+}
@@ -0,0 +1,211 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlpmetrics
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/testutil"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/otlpmetrics"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.Role_arn = "global_arn"
+	t.Cleanup(func() {
+		agent.Global_Config = agent.Agent{}
+	})
+	tt := NewTranslator()
+	require.EqualValues(t, "otlpmetrics", tt.ID().String())
+
+	testCases := map[string]struct {
+		input   map[string]any
+		want    *confmap.Conf
+		wantErr error
+	}{
+		"WithMissingEndpoint": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"otlp": map[string]any{},
+					},
+				},
+			},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: common.ConfigKey(SectionKey, endpointKey)},
+		},
+		"WithDefaults": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"otlp": map[string]any{
+							"endpoint": "localhost:4317",
+						},
+					},
+				},
+			},
+			want: confmap.NewFromStringMap(map[string]any{
+				"endpoint":    "localhost:4317",
+				"protocol":    "grpc",
+				"compression": "gzip",
+				"timeout":     "5s",
+				"sending_queue": map[string]any{
+					"enabled":       true,
+					"num_consumers": 10,
+					"queue_size":    1000,
+				},
+				"retry_on_failure": map[string]any{
+					"enabled":              true,
+					"initial_interval":     "5s",
+					"max_interval":         "30s",
+					"max_elapsed_time":     "5m",
+					"multiplier":           1.5,
+					"randomization_factor": 0.5,
+				},
+			}),
+		},
+		"WithPipelineSettings": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"otlp": map[string]any{
+							"endpoint": "localhost:4317",
+						},
+					},
+				},
+				"service": map[string]any{
+					"pipeline_defaults": map[string]any{
+						"queue_size":    5000,
+						"num_consumers": 2,
+					},
+					"pipeline_overrides": map[string]any{
+						"otlp": map[string]any{
+							"num_consumers": 20,
+						},
+					},
+				},
+			},
+			want: confmap.NewFromStringMap(map[string]any{
+				"endpoint":    "localhost:4317",
+				"protocol":    "grpc",
+				"compression": "gzip",
+				"timeout":     "5s",
+				"sending_queue": map[string]any{
+					"enabled":       true,
+					"num_consumers": 20,
+					"queue_size":    5000,
+				},
+				"retry_on_failure": map[string]any{
+					"enabled":              true,
+					"initial_interval":     "5s",
+					"max_interval":         "30s",
+					"max_elapsed_time":     "5m",
+					"multiplier":           1.5,
+					"randomization_factor": 0.5,
+				},
+			}),
+		},
+		"WithOTLPDestination": {
+			input: testutil.GetJson(t, filepath.Join("testdata", "config.json")),
+			want:  testutil.GetConf(t, filepath.Join("testdata", "config.yaml")),
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(testCase.input)
+			got, err := tt.Translate(conf)
+			assert.Equal(t, testCase.wantErr, err)
+			if err == nil {
+				require.NotNil(t, got)
+				gotCfg, ok := got.(*otlpmetrics.Config)
+				require.True(t, ok)
+				wantCfg := &otlpmetrics.Config{}
+				require.NoError(t, testCase.want.Unmarshal(wantCfg))
+				assert.Equal(t, wantCfg, gotCfg)
+				assert.NoError(t, gotCfg.Validate())
+			}
+		})
+	}
+}
+
+func TestTracesTranslator(t *testing.T) {
+	tt := NewTracesTranslatorWithName("xray/1", 1)
+	require.EqualValues(t, "otlptraces/xray/1", tt.ID().String())
+
+	testCases := map[string]struct {
+		input   map[string]any
+		want    *confmap.Conf
+		wantErr error
+	}{
+		"WithXrayDestination": {
+			input: map[string]any{
+				"traces": map[string]any{
+					"traces_destinations": []any{
+						map[string]any{"otlp": map[string]any{"endpoint": "localhost:4317"}},
+						map[string]any{"xray": map[string]any{}},
+					},
+				},
+			},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: "traces::traces_destinations[1]::otlp::endpoint"},
+		},
+		"WithOTLPDestination": {
+			input: map[string]any{
+				"traces": map[string]any{
+					"traces_destinations": []any{
+						map[string]any{"xray": map[string]any{}},
+						map[string]any{"otlp": map[string]any{
+							"endpoint": "https://collector:4318",
+							"protocol": "http",
+							"headers":  map[string]any{"x-tenant": "a"},
+							"tls":      map[string]any{"ca_file": "/ca.pem"},
+						}},
+					},
+				},
+			},
+			want: confmap.NewFromStringMap(map[string]any{
+				"endpoint":    "https://collector:4318",
+				"protocol":    "http",
+				"compression": "gzip",
+				"timeout":     "5s",
+				"headers":     map[string]any{"x-tenant": "a"},
+				"tls":         map[string]any{"ca_file": "/ca.pem"},
+				"sending_queue": map[string]any{
+					"enabled":       true,
+					"num_consumers": 10,
+					"queue_size":    1000,
+				},
+				"retry_on_failure": map[string]any{
+					"enabled":              true,
+					"initial_interval":     "5s",
+					"max_interval":         "30s",
+					"max_elapsed_time":     "5m",
+					"multiplier":           1.5,
+					"randomization_factor": 0.5,
+				},
+			}),
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(testCase.input)
+			got, err := tt.Translate(conf)
+			assert.Equal(t, testCase.wantErr, err)
+			if err == nil {
+				require.NotNil(t, got)
+				gotCfg, ok := got.(*otlpmetrics.Config)
+				require.True(t, ok)
+				wantCfg := &otlpmetrics.Config{}
+				require.NoError(t, testCase.want.Unmarshal(wantCfg))
+				assert.Equal(t, wantCfg, gotCfg)
+				assert.NoError(t, gotCfg.Validate())
+			}
+		})
+	}
+}
@@ -51,5 +51,16 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 	value, _ := common.GetString(conf, common.ConfigKey(AMPSectionKey, common.WorkspaceIDKey))
 	ampEndpoint := "https://aps-workspaces." + agent.Global_Config.Region + ".amazonaws.com/workspaces/" + value + "/api/v1/remote_write"
 	cfg.ClientConfig.Endpoint = ampEndpoint
+	if pipeline := common.GetPipelineConfig(conf, common.AMPKey); pipeline != nil {
+		if pipeline.QueueSize != nil {
+			cfg.RemoteWriteQueue.QueueSize = *pipeline.QueueSize
+		}
+		if pipeline.NumConsumers != nil {
+			cfg.RemoteWriteQueue.NumConsumers = *pipeline.NumConsumers
+		}
+		if pipeline.MaxBatchBytes != nil {
+			cfg.MaxBatchSizeBytes = *pipeline.MaxBatchBytes
+		}
+	}
 	return cfg, nil
 }
@@ -49,6 +49,10 @@ func TestTranslator(t *testing.T) {
 			input: testutil.GetJson(t, filepath.Join("testdata", "config.json")),
 			want:  testutil.GetConf(t, filepath.Join("testdata", "config.yaml")),
 		},
+		"WithPipelineSettings": {
+			input: testutil.GetJson(t, filepath.Join("testdata", "config_pipeline.json")),
+			want:  testutil.GetConf(t, filepath.Join("testdata", "config_pipeline.yaml")),
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package textfile
+
+import (
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/exporter"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/textfile"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	pathKey             = "path"
+	flushIntervalKey    = "flush_interval"
+	metricExpirationKey = "metric_expiration"
+	includeKey          = "include"
+)
+
+var (
+	SectionKey = common.ConfigKey(common.MetricsKey, common.MetricsDestinationsKey, common.TextfileKey)
+)
+
+type translator struct {
+	name    string
+	factory exporter.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+func NewTranslator() common.Translator[component.Config] {
+	return NewTranslatorWithName("")
+}
+
+func NewTranslatorWithName(name string) common.Translator[component.Config] {
+	return &translator{name, textfile.NewFactory()}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates an exporter config based on the fields in the
+// textfile section of the JSON config.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	pathSectionKey := common.ConfigKey(SectionKey, pathKey)
+	if conf == nil || !conf.IsSet(pathSectionKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: pathSectionKey}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*textfile.Config)
+	cfg.Path, _ = common.GetString(conf, pathSectionKey)
+	if flushInterval, ok := common.GetDuration(conf, common.ConfigKey(SectionKey, flushIntervalKey)); ok {
+		cfg.FlushInterval = flushInterval
+	}
+	if metricExpiration, ok := common.GetDuration(conf, common.ConfigKey(SectionKey, metricExpirationKey)); ok {
+		cfg.MetricExpiration = metricExpiration
+	}
+	cfg.Include = common.GetArray[string](conf, common.ConfigKey(SectionKey, includeKey))
+	return cfg, nil
+}
@@ -0,0 +1,78 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package textfile
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/testutil"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/textfile"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	tt := NewTranslator()
+	require.EqualValues(t, "textfile", tt.ID().String())
+
+	testCases := map[string]struct {
+		input   map[string]any
+		want    *confmap.Conf
+		wantErr error
+	}{
+		"WithMissingPath": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"textfile": map[string]any{},
+					},
+				},
+			},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: common.ConfigKey(SectionKey, pathKey)},
+		},
+		"WithDefaults": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"textfile": map[string]any{
+							"path": "/tmp/cwagent.prom",
+						},
+					},
+				},
+			},
+			want: confmap.NewFromStringMap(map[string]any{
+				"path":              "/tmp/cwagent.prom",
+				"flush_interval":    "1m",
+				"metric_expiration": "5m",
+				"resource_to_telemetry_conversion": map[string]any{
+					"enabled": true,
+				},
+			}),
+		},
+		"WithTextfileDestination": {
+			input: testutil.GetJson(t, filepath.Join("testdata", "config.json")),
+			want:  testutil.GetConf(t, filepath.Join("testdata", "config.yaml")),
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(testCase.input)
+			got, err := tt.Translate(conf)
+			assert.Equal(t, testCase.wantErr, err)
+			if err == nil {
+				require.NotNil(t, got)
+				gotCfg, ok := got.(*textfile.Config)
+				require.True(t, ok)
+				wantCfg := &textfile.Config{}
+				require.NoError(t, testCase.want.Unmarshal(wantCfg))
+				assert.Equal(t, wantCfg, gotCfg)
+				assert.NoError(t, gotCfg.Validate())
+			}
+		})
+	}
+}
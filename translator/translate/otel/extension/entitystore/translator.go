@@ -4,14 +4,23 @@
 package entitystore
 
 import (
+	"fmt"
+
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/extension"
 
 	"github.com/aws/amazon-cloudwatch-agent/extension/entitystore"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+	"github.com/aws/amazon-cloudwatch-agent/translator/util/ecsutil"
+)
+
+var (
+	entityAttributesKey = common.ConfigKey(common.AgentKey, "entity_attributes")
+	ec2TagKeysKey       = common.ConfigKey(common.AgentKey, "ec2_tag_keys")
 )
 
 type translator struct {
@@ -35,10 +44,25 @@ func (t *translator) ID() component.ID {
 func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 	cfg := t.factory.CreateDefaultConfig().(*entitystore.Config)
 	cfg.Mode = context.CurrentContext().Mode()
+	if context.CurrentContext().RunInContainer() && ecsutil.GetECSUtilSingleton().IsECS() {
+		cfg.Mode = config.ModeECS
+	}
 	cfg.KubernetesMode = context.CurrentContext().KubernetesMode()
 	cfg.Region = agent.Global_Config.Region
 	credentials := confmap.NewFromStringMap(agent.Global_Config.Credentials)
 	_ = credentials.Unmarshal(cfg)
+	if attributes, ok := conf.Get(entityAttributesKey).(map[string]any); ok && len(attributes) > 0 {
+		cfg.EntityAttributes = make(map[string]string, len(attributes))
+		for key, value := range attributes {
+			cfg.EntityAttributes[key] = fmt.Sprintf("%v", value)
+		}
+	}
+	cfg.EC2TagKeys = common.GetArray[string](conf, ec2TagKeysKey)
+	if conf.IsSet(common.HybridEntityConfigKey) {
+		cfg.Hybrid = &entitystore.HybridConfig{}
+		cfg.Hybrid.CloudProvider, _ = common.GetString(conf, common.ConfigKey(common.HybridEntityConfigKey, "cloud_provider"))
+		cfg.Hybrid.Datacenter, _ = common.GetString(conf, common.ConfigKey(common.HybridEntityConfigKey, "datacenter"))
+	}
 
 	return cfg, nil
 }
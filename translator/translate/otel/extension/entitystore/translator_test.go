@@ -13,6 +13,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/config"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
 	translateagent "github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/aws/amazon-cloudwatch-agent/translator/util/ecsutil"
 )
 
 func TestTranslate(t *testing.T) {
@@ -24,6 +25,7 @@ func TestTranslate(t *testing.T) {
 		inputK8sMode   string
 		file_exists    bool
 		profile_exists bool
+		isECS          bool
 		want           *entitystore.Config
 	}{
 		"OnlyProfile": {
@@ -62,6 +64,54 @@ func TestTranslate(t *testing.T) {
 				Filename:       "test_file",
 			},
 		},
+		"EntityAttributes": {
+			input: map[string]interface{}{
+				"agent": map[string]interface{}{
+					"entity_attributes": map[string]interface{}{
+						"team": "payments",
+						"tier": 1,
+					},
+					"ec2_tag_keys": []interface{}{"owner", "cost-center"},
+				},
+			},
+			inputMode:      config.ModeEC2,
+			profile_exists: true,
+			want: &entitystore.Config{
+				Mode:             config.ModeEC2,
+				Region:           "us-east-1",
+				Profile:          "test_profile",
+				EntityAttributes: map[string]string{"team": "payments", "tier": "1"},
+				EC2TagKeys:       []string{"owner", "cost-center"},
+			},
+		},
+		"HybridEntity": {
+			input: map[string]interface{}{
+				"agent": map[string]interface{}{
+					"hybrid_entity": map[string]interface{}{
+						"cloud_provider": "azure",
+					},
+				},
+			},
+			inputMode:      config.ModeOnPremise,
+			profile_exists: true,
+			want: &entitystore.Config{
+				Mode:    config.ModeOnPremise,
+				Region:  "us-east-1",
+				Profile: "test_profile",
+				Hybrid:  &entitystore.HybridConfig{CloudProvider: "azure"},
+			},
+		},
+		"ECS": {
+			input:          map[string]interface{}{},
+			inputMode:      config.ModeEC2,
+			isECS:          true,
+			profile_exists: true,
+			want: &entitystore.Config{
+				Mode:    config.ModeECS,
+				Region:  "us-east-1",
+				Profile: "test_profile",
+			},
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -79,6 +129,14 @@ func TestTranslate(t *testing.T) {
 			conf := confmap.NewFromStringMap(testCase.input)
 			context.CurrentContext().SetMode(testCase.inputMode)
 			context.CurrentContext().SetKubernetesMode(testCase.inputK8sMode)
+			if testCase.isECS {
+				context.CurrentContext().SetRunInContainer(true)
+				ecsutil.GetECSUtilSingleton().Region = "us-east-1"
+				t.Cleanup(func() {
+					context.CurrentContext().SetRunInContainer(false)
+					ecsutil.GetECSUtilSingleton().Region = ""
+				})
+			}
 			got, err := tt.Translate(conf)
 			assert.NoError(t, err)
 			assert.Equal(t, testCase.want, got)
@@ -0,0 +1,72 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/extension"
+
+	"github.com/aws/amazon-cloudwatch-agent/extension/inventory"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+var (
+	BaseKey     = common.ConfigKey(common.AgentKey, "inventory")
+	intervalKey = common.ConfigKey(BaseKey, "interval")
+	typeNameKey = common.ConfigKey(BaseKey, "type_name")
+)
+
+type translator struct {
+	name      string
+	pipelines []string
+	factory   extension.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+// NewTranslator creates the translator of the inventory extension reporting
+// the pipelines.
+func NewTranslator(pipelines []string) common.Translator[component.Config] {
+	return &translator{
+		pipelines: pipelines,
+		factory:   inventory.NewFactory(),
+	}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates an extension configuration.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if conf == nil || !conf.IsSet(BaseKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: BaseKey}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*inventory.Config)
+	if interval, ok := common.GetNumber(conf, intervalKey); ok {
+		cfg.Interval = time.Duration(interval) * time.Second
+	}
+	if typeName, ok := common.GetString(conf, typeNameKey); ok {
+		cfg.TypeName = typeName
+	}
+	content, err := json.Marshal(conf.ToStringMap())
+	if err != nil {
+		return nil, fmt.Errorf("unable to hash the agent config: %w", err)
+	}
+	hash := sha256.Sum256(content)
+	cfg.ConfigHash = hex.EncodeToString(hash[:])
+	cfg.Pipelines = t.pipelines
+	cfg.Region = agent.Global_Config.Region
+	credentials := confmap.NewFromStringMap(agent.Global_Config.Credentials)
+	_ = credentials.Unmarshal(cfg)
+	return cfg, nil
+}
@@ -0,0 +1,88 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/extension/inventory"
+	translateagent "github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+)
+
+func TestTranslate(t *testing.T) {
+	translateagent.Global_Config.Credentials = map[string]interface{}{"profile": "test_profile"}
+	translateagent.Global_Config.Region = "us-east-1"
+	defer func() {
+		translateagent.Global_Config.Credentials = make(map[string]interface{})
+		translateagent.Global_Config.Region = ""
+	}()
+	pipelines := []string{"metrics/host"}
+	testCases := map[string]struct {
+		input   map[string]interface{}
+		want    *inventory.Config
+		wantErr bool
+	}{
+		"WithoutInventory": {
+			input:   map[string]interface{}{"agent": map[string]interface{}{}},
+			wantErr: true,
+		},
+		"WithDefault": {
+			input: map[string]interface{}{"agent": map[string]interface{}{"inventory": map[string]interface{}{}}},
+			want: &inventory.Config{
+				Interval:  time.Hour,
+				TypeName:  "Custom:AmazonCloudWatchAgent",
+				Pipelines: pipelines,
+				Region:    "us-east-1",
+				Profile:   "test_profile",
+			},
+		},
+		"WithSettings": {
+			input: map[string]interface{}{"agent": map[string]interface{}{"inventory": map[string]interface{}{
+				"interval":  float64(900),
+				"type_name": "Custom:Monitoring",
+			}}},
+			want: &inventory.Config{
+				Interval:  15 * time.Minute,
+				TypeName:  "Custom:Monitoring",
+				Pipelines: pipelines,
+				Region:    "us-east-1",
+				Profile:   "test_profile",
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tt := NewTranslator(pipelines)
+			assert.Equal(t, "inventory", tt.ID().String())
+			got, err := tt.Translate(confmap.NewFromStringMap(testCase.input))
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			gotCfg, ok := got.(*inventory.Config)
+			require.True(t, ok)
+			assert.Len(t, gotCfg.ConfigHash, 64)
+			gotCfg.ConfigHash = ""
+			assert.Equal(t, testCase.want, gotCfg)
+		})
+	}
+}
+
+func TestTranslateConfigHash(t *testing.T) {
+	tt := NewTranslator(nil)
+	translate := func(input map[string]interface{}) string {
+		got, err := tt.Translate(confmap.NewFromStringMap(input))
+		require.NoError(t, err)
+		return got.(*inventory.Config).ConfigHash
+	}
+	first := translate(map[string]interface{}{"agent": map[string]interface{}{"inventory": map[string]interface{}{}, "debug": true}})
+	assert.Equal(t, first, translate(map[string]interface{}{"agent": map[string]interface{}{"debug": true, "inventory": map[string]interface{}{}}}))
+	assert.NotEqual(t, first, translate(map[string]interface{}{"agent": map[string]interface{}{"inventory": map[string]interface{}{}, "debug": false}}))
+}
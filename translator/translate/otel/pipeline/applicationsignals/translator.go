@@ -19,6 +19,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/awsentity"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/metricstransformprocessor"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/resourcedetection"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/tailsampling"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/otlp"
 	"github.com/aws/amazon-cloudwatch-agent/translator/util/ecsutil"
 )
@@ -68,6 +69,10 @@ func (t *translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators
 		translators.Processors.Set(awsentity.NewTranslatorWithEntityType(awsentity.Service, common.AppSignals, false))
 	}
 
+	if t.dataType == component.DataTypeTraces && conf.IsSet(tailsampling.ConfigKey) {
+		translators.Processors.Set(tailsampling.NewTranslatorWithName(common.AppSignals))
+	}
+
 	if enabled, _ := common.GetBool(conf, common.AgentDebugConfigKey); enabled {
 		translators.Exporters.Set(debug.NewTranslator(common.WithName(common.AppSignals)))
 	}
@@ -74,6 +74,26 @@ func TestTranslatorTraces(t *testing.T) {
 			detector:   eksdetector.TestK8sDetector,
 			isEKSCache: eksdetector.TestIsEKSCacheK8s,
 		},
+		"WithAppSignalsAndTailSampling": {
+			input: map[string]interface{}{
+				"traces": map[string]interface{}{
+					"traces_collected": map[string]interface{}{
+						"application_signals": map[string]interface{}{},
+					},
+					"tail_sampling": map[string]interface{}{
+						"policies": map[string]interface{}{"errors": true},
+					},
+				},
+			},
+			want: &want{
+				receivers:  []string{"otlp/application_signals"},
+				processors: []string{"resourcedetection", "awsapplicationsignals", "tail_sampling/application_signals"},
+				exporters:  []string{"awsxray/application_signals"},
+				extensions: []string{"awsproxy/application_signals", "agenthealth/traces", "agenthealth/statuscode"},
+			},
+			detector:   eksdetector.TestK8sDetector,
+			isEKSCache: eksdetector.TestIsEKSCacheK8s,
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
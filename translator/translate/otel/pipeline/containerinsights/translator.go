@@ -18,11 +18,16 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/metricstransformprocessor"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/awscontainerinsight"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/awscontainerinsightskueue"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/awscontainerinsightsnodehealth"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/ecstaskstats"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/eksfargatestats"
 )
 
 const (
-	ciPipelineName    = common.PipelineNameContainerInsights
-	kueuePipelineName = "kueueContainerInsights"
+	ciPipelineName         = common.PipelineNameContainerInsights
+	kueuePipelineName      = "kueueContainerInsights"
+	nodeHealthPipelineName = "nodeHealthContainerInsights"
+	ecsTaskPipelineName    = "ecsTaskContainerInsights"
 )
 
 var (
@@ -70,6 +75,11 @@ func (t *translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators
 
 	switch t.pipelineName {
 	case ciPipelineName:
+		if common.FargateEnabled(conf) {
+			// add the receiver scraping the kubelets of the Fargate nodes through the API server
+			receivers = common.NewTranslatorMap(eksfargatestats.NewTranslatorWithName(t.pipelineName))
+			break
+		}
 		// add aws container insights receiver
 		receivers = common.NewTranslatorMap(awscontainerinsight.NewTranslator())
 		// Append the metricstransformprocessor only if enhanced container insights is enabled
@@ -86,6 +96,12 @@ func (t *translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators
 		// add prometheus receiver for kueue
 		receivers = common.NewTranslatorMap((awscontainerinsightskueue.NewTranslator()))
 		processors.Set(kueue.NewTranslatorWithName(t.pipelineName))
+	case nodeHealthPipelineName:
+		// add prometheus receiver scraping the kubelet and containerd of the node
+		receivers = common.NewTranslatorMap(awscontainerinsightsnodehealth.NewTranslatorWithName(t.pipelineName))
+	case ecsTaskPipelineName:
+		// add the receiver reading the network and Service Connect stats of the ECS tasks
+		receivers = common.NewTranslatorMap(ecstaskstats.NewTranslatorWithName(t.pipelineName))
 	default:
 		return nil, fmt.Errorf("unknown container insights pipeline name: %s", t.pipelineName)
 	}
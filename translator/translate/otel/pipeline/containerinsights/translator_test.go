@@ -67,6 +67,26 @@ func TestTranslator(t *testing.T) {
 				extensions:   []string{"agenthealth/logs", "agenthealth/statuscode"},
 			},
 		},
+		"WithFargate": {
+			input: map[string]interface{}{
+				"logs": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"kubernetes": map[string]interface{}{
+							"fargate":                     true,
+							"enhanced_container_insights": true,
+							"cluster_name":                "TestCluster",
+						},
+					},
+				},
+			},
+			want: &want{
+				pipelineType: "metrics/containerinsights",
+				receivers:    []string{"eksfargatestats/containerinsights"},
+				processors:   []string{"batch/containerinsights"},
+				exporters:    []string{"awsemf/containerinsights"},
+				extensions:   []string{"agenthealth/logs", "agenthealth/statuscode"},
+			},
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
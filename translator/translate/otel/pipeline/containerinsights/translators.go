@@ -25,6 +25,15 @@ func NewTranslators(conf *confmap.Conf) pipeline.TranslatorMap {
 		kueueTranslator := NewTranslatorWithName(kueuePipelineName)
 		translators.Set(kueueTranslator)
 	}
+	// create node health container insights translator, Fargate nodes do not
+	// run the agent to scrape
+	if common.NodeHealthMetricsEnabled(conf) && !common.FargateEnabled(conf) {
+		translators.Set(NewTranslatorWithName(nodeHealthPipelineName))
+	}
+	// create ecs task container insights translator
+	if common.TaskNetworkMetricsEnabled(conf) || common.ServiceConnectMetricsEnabled(conf) {
+		translators.Set(NewTranslatorWithName(ecsTaskPipelineName))
+	}
 	// return the translator map
 	return translators
 }
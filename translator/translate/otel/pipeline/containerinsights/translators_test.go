@@ -82,6 +82,69 @@ func TestTranslators(t *testing.T) {
 				},
 			},
 		},
+		"WithContainerInsightsAndNodeHealthMetrics": {
+			input: map[string]interface{}{
+				"logs": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"kubernetes": map[string]interface{}{
+							"node_health_metrics": true,
+							"cluster_name":        "TestCluster",
+						},
+					},
+				},
+			},
+			want: map[string]want{
+				"metrics/containerinsights": {
+					receivers: []string{"awscontainerinsightreceiver"},
+					exporters: []string{"awsemf/containerinsights"},
+				},
+				"metrics/nodeHealthContainerInsights": {
+					receivers: []string{"prometheus/nodeHealthContainerInsights"},
+					exporters: []string{"awsemf/nodeHealthContainerInsights"},
+				},
+			},
+		},
+		"WithFargate": {
+			input: map[string]interface{}{
+				"logs": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"kubernetes": map[string]interface{}{
+							"fargate":             true,
+							"node_health_metrics": true,
+							"cluster_name":        "TestCluster",
+						},
+					},
+				},
+			},
+			want: map[string]want{
+				"metrics/containerinsights": {
+					receivers: []string{"eksfargatestats/containerinsights"},
+					exporters: []string{"awsemf/containerinsights"},
+				},
+			},
+		},
+		"WithContainerInsightsAndEcsTaskMetrics": {
+			input: map[string]interface{}{
+				"logs": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"ecs": map[string]interface{}{
+							"task_network_metrics":    true,
+							"service_connect_metrics": true,
+						},
+					},
+				},
+			},
+			want: map[string]want{
+				"metrics/containerinsights": {
+					receivers: []string{"awscontainerinsightreceiver"},
+					exporters: []string{"awsemf/containerinsights"},
+				},
+				"metrics/ecsTaskContainerInsights": {
+					receivers: []string{"ecstaskstats/ecsTaskContainerInsights"},
+					exporters: []string{"awsemf/ecsTaskContainerInsights"},
+				},
+			},
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -67,6 +67,28 @@ func TestTranslator(t *testing.T) {
 				extensions:   []string{"agenthealth/logs", "agenthealth/statuscode"},
 			},
 		},
+		"WithTransforms": {
+			input: map[string]interface{}{
+				"logs": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"emf": nil,
+					},
+					"transforms": []interface{}{
+						map[string]interface{}{
+							"context":    "log",
+							"statements": []interface{}{`set(attributes["env"], "prod")`},
+						},
+					},
+				},
+			},
+			want: &want{
+				pipelineType: "logs/emf_logs",
+				receivers:    []string{"tcplog/emf_logs", "udplog/emf_logs"},
+				processors:   []string{"transform/transforms/emf_logs", "batch/emf_logs"},
+				exporters:    []string{"awscloudwatchlogs/emf_logs"},
+				extensions:   []string{"agenthealth/logs", "agenthealth/statuscode"},
+			},
+		},
 		"WithUdpServiceAddress": {
 			input: map[string]interface{}{
 				"logs": map[string]interface{}{
@@ -12,26 +12,43 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
 
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/metricsrouting"
+	"github.com/aws/amazon-cloudwatch-agent/receiver/adapter"
 	"github.com/aws/amazon-cloudwatch-agent/translator/config"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/procstat"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/alerting"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/awscloudwatch"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/awsemf"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/otlpmetrics"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/prometheusremotewrite"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/textfile"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/extension/agenthealth"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/extension/sigv4auth"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/awsentity"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/batchprocessor"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/cardinalitylimiter"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/cumulativetodeltaprocessor"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/deltatocumulative"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/derivedmetrics"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/dimensionnormalizer"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/ec2taggerprocessor"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/filterprocessor"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/metricsdecorator"
+	metricsroutingtranslator "github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/metricsrouting"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/procstatheartbeat"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/rollupprocessor"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/transforms"
 	"github.com/aws/amazon-cloudwatch-agent/translator/util/ecsutil"
 )
 
 type translator struct {
 	name string
 	common.DestinationProvider
+	// IndexProvider is the namespace route of the pipeline, or
+	// metricsrouting.DefaultRoute for the metrics not matching any route.
+	common.IndexProvider
 	receivers common.TranslatorMap[component.Config]
 }
 
@@ -45,19 +62,24 @@ var _ common.Translator[*common.ComponentTranslators] = (*translator)(nil)
 
 // NewTranslator creates a new host pipeline translator. The receiver types
 // passed in are converted to config.ComponentIDs, sorted, and used directly
-// in the translated pipeline.
+// in the translated pipeline. The index option builds the pipeline of a
+// namespace route.
 func NewTranslator(
 	name string,
 	receivers common.TranslatorMap[component.Config],
 	opts ...common.TranslatorOption,
 ) common.Translator[*common.ComponentTranslators] {
 	t := &translator{name: name, receivers: receivers}
+	t.SetIndex(metricsrouting.DefaultRoute)
 	for _, opt := range opts {
 		opt(t)
 	}
 	if t.Destination() != "" {
 		t.name += "/" + t.Destination()
 	}
+	if t.Index() != metricsrouting.DefaultRoute {
+		t.name += "/" + awscloudwatch.RouteName(t.Index())
+	}
 	return t
 }
 
@@ -71,6 +93,11 @@ func (t translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators,
 		return nil, fmt.Errorf("no receivers configured in pipeline %s", t.name)
 	}
 
+	temporality, err := common.GetAggregationTemporality(conf, t.Destination())
+	if err != nil {
+		return nil, err
+	}
+
 	var entityProcessor common.Translator[component.Config]
 	var ec2TaggerEnabled bool
 
@@ -81,9 +108,44 @@ func (t translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators,
 		Extensions: common.NewTranslatorMap[component.Config](),
 	}
 
-	if strings.HasPrefix(t.name, common.PipelineNameHostDeltaMetrics) || strings.HasPrefix(t.name, common.PipelineNameHostOtlpMetrics) {
-		log.Printf("D! delta processor required because metrics with diskio or net are set")
-		translators.Processors.Set(cumulativetodeltaprocessor.NewTranslator(common.WithName(t.name), cumulativetodeltaprocessor.WithDefaultKeys()))
+	// the heartbeat goes first, so the other processors only see the 0/1 running state
+	if t.hasProcstatReceiver() && procstatheartbeat.IsSet(conf) {
+		log.Printf("D! procstat heartbeat processor required because expect_running is set")
+		translators.Processors.Set(procstatheartbeat.NewTranslatorWithName(t.name))
+	}
+
+	// the data points are dropped before they take any state in the processors below
+	if plugins := t.dimensionFiltersPlugins(conf); len(plugins) > 0 {
+		log.Printf("D! filter processor required because dimension_filters are set")
+		translators.Processors.Set(filterprocessor.NewDimensionFiltersTranslator(t.name, plugins...))
+	}
+
+	// the keys are normalized before the delta conversion and the ec2tagger, so neither the series state nor
+	// the appended dimensions are affected
+	if dimensionnormalizer.IsSet(conf) {
+		log.Printf("D! dimension normalizer required because dimension_normalization is set")
+		translators.Processors.Set(dimensionnormalizer.NewTranslatorWithName(t.name))
+	}
+
+	// an explicit aggregation temporality on the destination replaces the implicit delta conversion
+	switch temporality {
+	case common.CumulativeTemporality:
+		log.Printf("D! delta to cumulative processor required because aggregation_temporality is cumulative")
+		translators.Processors.Set(deltatocumulative.NewTranslatorWithName(t.name))
+	case common.DeltaTemporality:
+		log.Printf("D! delta processor required because aggregation_temporality is delta")
+		translators.Processors.Set(cumulativetodeltaprocessor.NewTranslator(common.WithName(t.name), cumulativetodeltaprocessor.WithAllMetrics()))
+	default:
+		if strings.HasPrefix(t.name, common.PipelineNameHostDeltaMetrics) || strings.HasPrefix(t.name, common.PipelineNameHostOtlpMetrics) {
+			log.Printf("D! delta processor required because metrics with diskio, net or efa are set")
+			translators.Processors.Set(cumulativetodeltaprocessor.NewTranslator(common.WithName(t.name), cumulativetodeltaprocessor.WithDefaultKeys()))
+		}
+	}
+
+	// the metrics are derived from the collected names and values, before the decorator renames and scales them
+	if derivedmetrics.IsSet(conf) {
+		log.Printf("D! derived metrics processor required because derived is set")
+		translators.Processors.Set(derivedmetrics.NewTranslatorWithName(t.name))
 	}
 
 	if t.Destination() != common.CloudWatchLogsKey {
@@ -93,6 +155,15 @@ func (t translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators,
 			ec2TaggerEnabled = true
 		}
 
+		// the aggregation interval is only used by the CloudWatch exporter and matches the names before they are renamed
+		if t.Destination() == common.DefaultDestination || t.Destination() == common.CloudWatchKey {
+			ait := metricsdecorator.NewAggregationIntervalTranslator(common.JmxKey)
+			if ait.IsSet(conf) {
+				log.Printf("D! aggregation interval processor required because aggregation_interval is set")
+				translators.Processors.Set(ait)
+			}
+		}
+
 		mdt := metricsdecorator.NewTranslator(metricsdecorator.WithIgnorePlugins(common.JmxKey))
 		if mdt.IsSet(conf) {
 			log.Printf("D! metric decorator required because measurement fields are set")
@@ -100,7 +171,26 @@ func (t translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators,
 		}
 	}
 
+	// the statements see the metrics as they are published, and may rename or drop dimensions before they are counted
+	if transforms.IsSet(conf, component.DataTypeMetrics) {
+		log.Printf("D! transform processor required because metrics transforms are set")
+		translators.Processors.Set(transforms.NewTranslatorWithName(t.name, component.DataTypeMetrics))
+	}
+
+	// the dimension sets are counted after the delta conversion, with the names and dimensions that are published
+	if cardinalitylimiter.IsSet(conf) {
+		log.Printf("D! cardinality limiter required because cardinality_limit is set")
+		translators.Processors.Set(cardinalitylimiter.NewTranslatorWithName(t.name))
+	}
+
+	// each route has its own copy of the pipeline, which only keeps the metrics published to its namespace
+	if hasNamespaceRoutes(conf, t.Destination()) {
+		log.Printf("D! metrics routing processor required because namespace_routes are set")
+		translators.Processors.Set(metricsroutingtranslator.NewTranslatorWithName(t.name, t.Index()))
+	}
+
 	currentContext := context.CurrentContext()
+	isECS := ecsutil.GetECSUtilSingleton().IsECS()
 
 	switch determinePipeline(t.name) {
 	case common.PipelineNameHostOtlpMetrics:
@@ -113,30 +203,46 @@ func (t translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators,
 			entityProcessor = awsentity.NewTranslatorWithEntityType(awsentity.Service, "telegraf", true)
 		}
 	case common.PipelineNameHost, common.PipelineNameHostDeltaMetrics:
-		if !currentContext.RunInContainer() {
+		if !currentContext.RunInContainer() || isECS {
 			entityProcessor = awsentity.NewTranslatorWithEntityType(awsentity.Resource, "", ec2TaggerEnabled)
 		}
 	}
 
 	validDestination := slices.Contains(supportedEntityProcessorDestinations[:], t.Destination())
-	// ECS is not in scope for entity association, so we only add the entity processor in non-ECS platforms
-	isECS := ecsutil.GetECSUtilSingleton().IsECS()
-	if entityProcessor != nil && currentContext.Mode() == config.ModeEC2 && !isECS && validDestination {
-		translators.Processors.Set(entityProcessor)
+	// ECS only gets the task resource entity, the service entities are limited to non-ECS platforms
+	if entityProcessor != nil && validDestination {
+		if isECS {
+			if determinePipeline(t.name) == common.PipelineNameHost || determinePipeline(t.name) == common.PipelineNameHostDeltaMetrics {
+				translators.Processors.Set(entityProcessor)
+			}
+		} else if currentContext.Mode() == config.ModeEC2 || isHybridEntityEnabled(conf) {
+			translators.Processors.Set(entityProcessor)
+		}
 	}
 
 	switch t.Destination() {
 	case common.DefaultDestination, common.CloudWatchKey:
-		translators.Exporters.Set(awscloudwatch.NewTranslator())
+		if t.Index() != metricsrouting.DefaultRoute {
+			translators.Exporters.Set(awscloudwatch.NewTranslatorWithRoute(t.Index()))
+		} else {
+			translators.Exporters.Set(awscloudwatch.NewTranslator())
+		}
 		translators.Extensions.Set(agenthealth.NewTranslator(component.DataTypeMetrics, []string{agenthealth.OperationPutMetricData}))
 		translators.Extensions.Set(agenthealth.NewTranslatorWithStatusCode(component.MustNewType("statuscode"), nil, true))
 	case common.AMPKey:
 		if conf.IsSet(common.MetricsAggregationDimensionsKey) {
 			translators.Processors.Set(rollupprocessor.NewTranslator())
 		}
-		translators.Processors.Set(batchprocessor.NewTranslatorWithNameAndSection(t.name, common.MetricsKey))
+		translators.Processors.Set(batchprocessor.NewTranslatorWithNameAndSection(t.name, common.MetricsKey, common.WithDestination(common.AMPKey)))
 		translators.Exporters.Set(prometheusremotewrite.NewTranslatorWithName(common.AMPKey))
 		translators.Extensions.Set(sigv4auth.NewTranslator())
+	case common.TextfileKey:
+		translators.Exporters.Set(textfile.NewTranslator())
+	case common.AlertsKey:
+		translators.Exporters.Set(alerting.NewTranslator())
+	case common.OtlpKey:
+		translators.Processors.Set(batchprocessor.NewTranslatorWithNameAndSection(t.name, common.MetricsKey, common.WithDestination(common.OtlpKey)))
+		translators.Exporters.Set(otlpmetrics.NewTranslator())
 	case common.CloudWatchLogsKey:
 		translators.Processors.Set(batchprocessor.NewTranslatorWithNameAndSection(t.name, common.LogsKey))
 		translators.Exporters.Set(awsemf.NewTranslator())
@@ -149,6 +255,35 @@ func (t translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators,
 	return &translators, nil
 }
 
+func (t translator) hasProcstatReceiver() bool {
+	procstatType := adapter.Type(procstat.SectionKey)
+	return slices.ContainsFunc(t.receivers.Keys(), func(id component.ID) bool {
+		return id.Type() == procstatType
+	})
+}
+
+// dimensionFiltersPlugins returns the plugins of the pipeline with dimension_filters.
+func (t translator) dimensionFiltersPlugins(conf *confmap.Conf) []string {
+	return slices.DeleteFunc(filterprocessor.DimensionFiltersPlugins(conf), func(plugin string) bool {
+		return !slices.ContainsFunc(t.receivers.Keys(), func(id component.ID) bool {
+			return id.Type() == adapter.Type(plugin)
+		})
+	})
+}
+
+// hasNamespaceRoutes returns true if the metrics of the destination are split
+// between namespaces. Only the CloudWatch destinations publish to namespaces.
+func hasNamespaceRoutes(conf *confmap.Conf, destination string) bool {
+	return (destination == common.DefaultDestination || destination == common.CloudWatchKey) && metricsroutingtranslator.IsSet(conf)
+}
+
+// isHybridEntityEnabled returns true if the entities of the Azure and the
+// on-premises hosts are enabled, which requires the on-premise mode.
+func isHybridEntityEnabled(conf *confmap.Conf) bool {
+	mode := context.CurrentContext().Mode()
+	return (mode == config.ModeOnPrem || mode == config.ModeOnPremise) && conf.IsSet(common.HybridEntityConfigKey)
+}
+
 func determinePipeline(name string) string {
 	// The conditionals have to be done in a certain order because PipelineNameHost is just "host", whereas
 	// the other constants are prefixed with "host"
@@ -64,6 +64,21 @@ func TestTranslator(t *testing.T) {
 				extensions: []string{"agenthealth/metrics", "agenthealth/statuscode"},
 			},
 		},
+		"WithMetricsSectionECS": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{},
+			},
+			pipelineName: common.PipelineNameHost,
+			mode:         config.ModeEC2,
+			isECS:        true,
+			want: &want{
+				pipelineID: "metrics/host",
+				receivers:  []string{"nop", "other"},
+				processors: []string{"awsentity/resource"},
+				exporters:  []string{"awscloudwatch"},
+				extensions: []string{"agenthealth/metrics", "agenthealth/statuscode"},
+			},
+		},
 		"WithDeltaMetrics": {
 			input: map[string]interface{}{
 				"metrics": map[string]interface{}{
@@ -82,6 +97,31 @@ func TestTranslator(t *testing.T) {
 				extensions: []string{"agenthealth/metrics", "agenthealth/statuscode"},
 			},
 		},
+		"WithDerivedMetrics": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"net": map[string]interface{}{},
+					},
+					"append_dimensions": map[string]interface{}{},
+					"derived": []interface{}{
+						map[string]interface{}{
+							"metric_name": "net_bytes_total",
+							"expression":  "net_bytes_sent + net_bytes_recv",
+						},
+					},
+				},
+			},
+			pipelineName: common.PipelineNameHostDeltaMetrics,
+			mode:         config.ModeEC2,
+			want: &want{
+				pipelineID: "metrics/hostDeltaMetrics",
+				receivers:  []string{"nop", "other"},
+				processors: []string{"cumulativetodelta/hostDeltaMetrics", "derivedmetrics/hostDeltaMetrics", "ec2tagger", "awsentity/resource"},
+				exporters:  []string{"awscloudwatch"},
+				extensions: []string{"agenthealth/metrics", "agenthealth/statuscode"},
+			},
+		},
 		"WithOtlpMetricsEC2": {
 			input: map[string]interface{}{
 				"metrics": map[string]interface{}{
@@ -327,6 +367,117 @@ func TestTranslator(t *testing.T) {
 				extensions: []string{"sigv4auth"},
 			},
 		},
+		"WithTextfileExporter": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{},
+			},
+			pipelineName: common.PipelineNameHost,
+			destination:  common.TextfileKey,
+			mode:         config.ModeEC2,
+			want: &want{
+				pipelineID: "metrics/host/textfile",
+				receivers:  []string{"nop", "other"},
+				processors: []string{},
+				exporters:  []string{"textfile"},
+				extensions: []string{},
+			},
+		},
+		"WithAlertingExporter": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{},
+			},
+			pipelineName: common.PipelineNameHost,
+			destination:  common.AlertsKey,
+			mode:         config.ModeEC2,
+			want: &want{
+				pipelineID: "metrics/host/alerts",
+				receivers:  []string{"nop", "other"},
+				processors: []string{},
+				exporters:  []string{"alerting"},
+				extensions: []string{},
+			},
+		},
+		"WithOTLPExporter": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{},
+			},
+			pipelineName: common.PipelineNameHost,
+			destination:  common.OtlpKey,
+			mode:         config.ModeEC2,
+			want: &want{
+				pipelineID: "metrics/host/otlp",
+				receivers:  []string{"nop", "other"},
+				processors: []string{"batch/host/otlp"},
+				exporters:  []string{"otlpmetrics"},
+				extensions: []string{},
+			},
+		},
+		"WithPRWExporter/Cumulative": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{
+					"metrics_destinations": map[string]interface{}{
+						"amp": map[string]interface{}{
+							"workspace_id":            "ws-12345",
+							"aggregation_temporality": "cumulative",
+						},
+					},
+				},
+			},
+			pipelineName: common.PipelineNameHost,
+			destination:  common.AMPKey,
+			mode:         config.ModeEC2,
+			want: &want{
+				pipelineID: "metrics/host/amp",
+				receivers:  []string{"nop", "other"},
+				processors: []string{"deltatocumulative/host/amp", "batch/host/amp"},
+				exporters:  []string{"prometheusremotewrite/amp"},
+				extensions: []string{"sigv4auth"},
+			},
+		},
+		"WithCloudWatchExporter/Delta": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"cpu": map[string]interface{}{},
+					},
+					"metrics_destinations": map[string]interface{}{
+						"cloudwatch": map[string]interface{}{
+							"aggregation_temporality": "delta",
+						},
+					},
+				},
+			},
+			pipelineName: common.PipelineNameHostCustomMetrics,
+			destination:  common.CloudWatchKey,
+			mode:         config.ModeEC2,
+			want: &want{
+				pipelineID: "metrics/hostCustomMetrics/cloudwatch",
+				receivers:  []string{"nop", "other"},
+				processors: []string{"cumulativetodelta/hostCustomMetrics/cloudwatch", "awsentity/service/telegraf"},
+				exporters:  []string{"awscloudwatch"},
+				extensions: []string{"agenthealth/metrics", "agenthealth/statuscode"},
+			},
+		},
+		"WithDimensionNormalization": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{
+					"dimension_normalization": map[string]interface{}{
+						"lowercase_keys": true,
+					},
+					"aggregation_dimensions": []interface{}{[]interface{}{"host"}},
+				},
+			},
+			pipelineName: common.PipelineNameHost,
+			destination:  common.AMPKey,
+			mode:         config.ModeEC2,
+			want: &want{
+				pipelineID: "metrics/host/amp",
+				receivers:  []string{"nop", "other"},
+				processors: []string{"dimensionnormalizer/host/amp", "rollup", "batch/host/amp"},
+				exporters:  []string{"prometheusremotewrite/amp"},
+				extensions: []string{"sigv4auth"},
+			},
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -367,6 +518,190 @@ func TestTranslator(t *testing.T) {
 	}
 }
 
+func TestTranslatorUnsupportedTemporality(t *testing.T) {
+	testCases := map[string]struct {
+		destination string
+		temporality string
+	}{
+		"WithCloudWatch/Cumulative": {destination: common.CloudWatchKey, temporality: common.CumulativeTemporality},
+		"WithAMP/Delta":             {destination: common.AMPKey, temporality: common.DeltaTemporality},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			resetContext()
+			ht := NewTranslator(
+				common.PipelineNameHost,
+				common.NewTranslatorMap[component.Config](&testTranslator{id: component.NewID(component.MustNewType("nop"))}),
+				common.WithDestination(testCase.destination),
+			)
+			conf := confmap.NewFromStringMap(map[string]interface{}{
+				"metrics": map[string]interface{}{
+					"metrics_destinations": map[string]interface{}{
+						testCase.destination: map[string]interface{}{
+							"aggregation_temporality": testCase.temporality,
+						},
+					},
+				},
+			})
+			_, err := ht.Translate(conf)
+			assert.ErrorContains(t, err, "does not support aggregation_temporality")
+		})
+	}
+}
+
+func TestTranslatorDimensionFilters(t *testing.T) {
+	resetContext()
+	context.CurrentContext().SetMode(config.ModeOnPrem)
+	conf := confmap.NewFromStringMap(map[string]interface{}{
+		"metrics": map[string]interface{}{
+			"metrics_collected": map[string]interface{}{
+				"disk": map[string]interface{}{
+					"dimension_filters": []interface{}{
+						map[string]interface{}{"action": "drop", "dimension": "device", "values": []interface{}{"^loop"}},
+					},
+				},
+				"net": map[string]interface{}{
+					"dimension_filters": []interface{}{
+						map[string]interface{}{"action": "drop", "dimension": "interface", "values": []interface{}{"^veth"}},
+					},
+				},
+			},
+		},
+	})
+	testCases := map[string]struct {
+		pipelineName string
+		receivers    []string
+		want         []string
+	}{
+		"WithFilteredPlugin": {
+			pipelineName: common.PipelineNameHost,
+			receivers:    []string{"telegraf_cpu", "telegraf_disk"},
+			want:         []string{"filter/dimension_filters/host"},
+		},
+		"WithDeltaMetrics": {
+			pipelineName: common.PipelineNameHostDeltaMetrics,
+			receivers:    []string{"telegraf_net"},
+			want:         []string{"filter/dimension_filters/hostDeltaMetrics", "cumulativetodelta/hostDeltaMetrics"},
+		},
+		"WithoutFilteredPlugin": {
+			pipelineName: common.PipelineNameHost,
+			receivers:    []string{"telegraf_cpu"},
+			want:         []string{},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			receivers := common.NewTranslatorMap[component.Config]()
+			for _, receiver := range testCase.receivers {
+				receivers.Set(&testTranslator{id: component.NewID(component.MustNewType(receiver))})
+			}
+			got, err := NewTranslator(testCase.pipelineName, receivers).Translate(conf)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, collections.MapSlice(got.Processors.Keys(), component.ID.String))
+		})
+	}
+}
+
+func TestTranslatorTransformsAndCardinalityLimit(t *testing.T) {
+	resetContext()
+	context.CurrentContext().SetMode(config.ModeOnPrem)
+	conf := confmap.NewFromStringMap(map[string]interface{}{
+		"metrics": map[string]interface{}{
+			"cardinality_limit": map[string]interface{}{"max_dimension_sets": 100},
+			"transforms": []interface{}{
+				map[string]interface{}{
+					"context":    "datapoint",
+					"statements": []interface{}{`delete_key(attributes, "request_id")`},
+				},
+			},
+			"metrics_collected": map[string]interface{}{
+				"statsd": map[string]interface{}{},
+				"net":    map[string]interface{}{},
+			},
+		},
+	})
+	testCases := map[string]struct {
+		pipelineName string
+		receivers    []string
+		want         []string
+	}{
+		"WithCustomMetrics": {
+			pipelineName: common.PipelineNameHostCustomMetrics,
+			receivers:    []string{"telegraf_statsd"},
+			want:         []string{"transform/transforms/hostCustomMetrics", "cardinalitylimiter/hostCustomMetrics"},
+		},
+		"WithDeltaMetrics": {
+			pipelineName: common.PipelineNameHostDeltaMetrics,
+			receivers:    []string{"telegraf_net"},
+			want:         []string{"cumulativetodelta/hostDeltaMetrics", "transform/transforms/hostDeltaMetrics", "cardinalitylimiter/hostDeltaMetrics"},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			receivers := common.NewTranslatorMap[component.Config]()
+			for _, receiver := range testCase.receivers {
+				receivers.Set(&testTranslator{id: component.NewID(component.MustNewType(receiver))})
+			}
+			got, err := NewTranslator(testCase.pipelineName, receivers).Translate(conf)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, collections.MapSlice(got.Processors.Keys(), component.ID.String))
+		})
+	}
+}
+
+func TestTranslatorNamespaceRoutes(t *testing.T) {
+	resetContext()
+	context.CurrentContext().SetMode(config.ModeOnPrem)
+	conf := confmap.NewFromStringMap(map[string]interface{}{
+		"metrics": map[string]interface{}{
+			"cardinality_limit": map[string]interface{}{"max_dimension_sets": 100},
+			"metrics_collected": map[string]interface{}{
+				"statsd": map[string]interface{}{},
+			},
+			"namespace_routes": []interface{}{
+				map[string]interface{}{"namespace": "TeamA", "dimensions": map[string]interface{}{"team": "a"}},
+			},
+		},
+	})
+	receivers := common.NewTranslatorMap[component.Config]()
+	receivers.Set(&testTranslator{id: component.NewID(component.MustNewType("telegraf_statsd"))})
+	testCases := map[string]struct {
+		opts           []common.TranslatorOption
+		wantID         string
+		wantProcessors []string
+		wantExporter   string
+	}{
+		"WithDefaultRoute": {
+			wantID:         "metrics/hostCustomMetrics",
+			wantProcessors: []string{"cardinalitylimiter/hostCustomMetrics", "metricsrouting/hostCustomMetrics"},
+			wantExporter:   "awscloudwatch",
+		},
+		"WithRoute": {
+			opts:           []common.TranslatorOption{common.WithIndex(0)},
+			wantID:         "metrics/hostCustomMetrics/route0",
+			wantProcessors: []string{"cardinalitylimiter/hostCustomMetrics/route0", "metricsrouting/hostCustomMetrics/route0"},
+			wantExporter:   "awscloudwatch/route0",
+		},
+		"WithCloudWatchDestination": {
+			opts:           []common.TranslatorOption{common.WithDestination(common.CloudWatchKey), common.WithIndex(0)},
+			wantID:         "metrics/hostCustomMetrics/cloudwatch/route0",
+			wantProcessors: []string{"cardinalitylimiter/hostCustomMetrics/cloudwatch/route0", "metricsrouting/hostCustomMetrics/cloudwatch/route0"},
+			wantExporter:   "awscloudwatch/route0",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tt := NewTranslator(common.PipelineNameHostCustomMetrics, receivers, testCase.opts...)
+			assert.Equal(t, testCase.wantID, tt.ID().String())
+			got, err := tt.Translate(conf)
+			require.NoError(t, err)
+			// the metrics are routed with the dimensions they are published with
+			assert.Equal(t, testCase.wantProcessors, collections.MapSlice(got.Processors.Keys(), component.ID.String))
+			assert.Equal(t, []string{testCase.wantExporter}, collections.MapSlice(got.Exporters.Keys(), component.ID.String))
+		})
+	}
+}
+
 func resetContext() {
 	context.ResetContext()
 	ecsutil.GetECSUtilSingleton().Region = ""
@@ -9,9 +9,12 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
 
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/metricsrouting"
 	"github.com/aws/amazon-cloudwatch-agent/receiver/adapter"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/alerting"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline"
+	metricsroutingtranslator "github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/metricsrouting"
 	adaptertranslator "github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/adapter"
 	otlpreceiver "github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/otlp"
 )
@@ -26,7 +29,6 @@ func NewTranslators(conf *confmap.Conf, configSection, os string) (pipeline.Tran
 	hostReceivers := common.NewTranslatorMap[component.Config]()
 	hostCustomReceivers := common.NewTranslatorMap[component.Config]()
 	deltaReceivers := common.NewTranslatorMap[component.Config]()
-	otlpReceivers := common.NewTranslatorMap[component.Config]()
 
 	// Gather adapter receivers
 	if configSection == MetricsKey {
@@ -35,37 +37,46 @@ func NewTranslators(conf *confmap.Conf, configSection, os string) (pipeline.Tran
 			return nil, fmt.Errorf("error finding receivers in config: %w", err)
 		}
 		adapterReceivers.Range(func(translator common.Translator[component.Config]) {
-			if translator.ID().Type() == adapter.Type(common.DiskIOKey) || translator.ID().Type() == adapter.Type(common.NetKey) {
+			switch translator.ID().Type() {
+			case adapter.Type(common.DiskIOKey), adapter.Type(common.NetKey), adapter.Type(common.EfaKey):
 				deltaReceivers.Set(translator)
-			} else if translator.ID().Type() == adapter.Type(common.StatsDMetricKey) || translator.ID().Type() == adapter.Type(common.CollectDPluginKey) {
+			case adapter.Type(common.StatsDMetricKey), adapter.Type(common.CollectDPluginKey):
 				hostCustomReceivers.Set(translator)
-			} else {
+			default:
 				hostReceivers.Set(translator)
 			}
 		})
 	}
 
 	// Gather OTLP receivers
-	switch v := conf.Get(common.ConfigKey(configSection, common.OtlpKey)).(type) {
+	otlpKey := common.ConfigKey(configSection, common.OtlpKey)
+	var otlpIndexes []int
+	switch v := conf.Get(otlpKey).(type) {
 	case []any:
 		for index := range v {
-			otlpReceivers.Set(otlpreceiver.NewTranslator(
-				otlpreceiver.WithDataType(component.DataTypeMetrics),
-				otlpreceiver.WithConfigKey(common.ConfigKey(configSection, common.OtlpKey)),
-				common.WithIndex(index),
-			))
+			otlpIndexes = append(otlpIndexes, index)
 		}
 	case map[string]any:
-		otlpReceivers.Set(otlpreceiver.NewTranslator(
-			otlpreceiver.WithDataType(component.DataTypeMetrics),
-			otlpreceiver.WithConfigKey(common.ConfigKey(configSection, common.OtlpKey)),
-		))
+		otlpIndexes = append(otlpIndexes, -1)
+	}
+	// each OTLP receiver instance can be routed to a subset of the destinations
+	otlpReceiversFor := func(destination string) common.TranslatorMap[component.Config] {
+		receivers := common.NewTranslatorMap[component.Config]()
+		for _, index := range otlpIndexes {
+			if otlpreceiver.RoutesTo(conf, otlpKey, index, destination) {
+				receivers.Set(otlpreceiver.NewTranslator(
+					otlpreceiver.WithDataType(component.DataTypeMetrics),
+					otlpreceiver.WithConfigKey(otlpKey),
+					common.WithIndex(index),
+				))
+			}
+		}
+		return receivers
 	}
 
 	hasHostPipeline := hostReceivers.Len() != 0
 	hasHostCustomPipeline := hostCustomReceivers.Len() != 0
 	hasDeltaPipeline := deltaReceivers.Len() != 0
-	hasOtlpPipeline := otlpReceivers.Len() != 0
 
 	var destinations []string
 	switch configSection {
@@ -73,48 +84,65 @@ func NewTranslators(conf *confmap.Conf, configSection, os string) (pipeline.Tran
 		destinations = common.GetLogsDestinations()
 	case MetricsKey:
 		destinations = common.GetMetricsDestinations(conf)
+		// the rules see the same metrics as the destinations, in their own pipelines
+		if alerting.IsSet(conf) {
+			destinations = append(destinations, common.AlertsKey)
+		}
 	}
 
 	for _, destination := range destinations {
 		switch destination {
-		case common.AMPKey:
-			// PRW exporter does not need the delta conversion.
+		case common.AMPKey, common.TextfileKey, common.OtlpKey:
+			// PRW, textfile and OTLP exporters do not need the delta conversion.
 			receivers := common.NewTranslatorMap[component.Config]()
 			receivers.Merge(hostReceivers)
 			receivers.Merge(deltaReceivers)
-			receivers.Merge(otlpReceivers)
+			receivers.Merge(otlpReceiversFor(destination))
 			translators.Set(NewTranslator(
 				common.PipelineNameHost,
 				receivers,
 				common.WithDestination(destination),
 			))
 		default:
-			if hasHostPipeline {
-				translators.Set(NewTranslator(
-					common.PipelineNameHost,
-					hostReceivers,
-					common.WithDestination(destination),
-				))
-			}
-			if hasHostCustomPipeline {
-				translators.Set(NewTranslator(
-					common.PipelineNameHostCustomMetrics,
-					hostCustomReceivers,
-					common.WithDestination(destination)))
+			// the metrics of each namespace route are published by a copy of the pipelines
+			routes := []int{metricsrouting.DefaultRoute}
+			if hasNamespaceRoutes(conf, destination) {
+				for route := 0; route < metricsroutingtranslator.RouteCount(conf); route++ {
+					routes = append(routes, route)
+				}
 			}
-			if hasDeltaPipeline {
-				translators.Set(NewTranslator(
-					common.PipelineNameHostDeltaMetrics,
-					deltaReceivers,
-					common.WithDestination(destination),
-				))
-			}
-			if hasOtlpPipeline {
-				translators.Set(NewTranslator(
-					common.PipelineNameHostOtlpMetrics,
-					otlpReceivers,
-					common.WithDestination(destination),
-				))
+			for _, route := range routes {
+				if hasHostPipeline {
+					translators.Set(NewTranslator(
+						common.PipelineNameHost,
+						hostReceivers,
+						common.WithDestination(destination),
+						common.WithIndex(route),
+					))
+				}
+				if hasHostCustomPipeline {
+					translators.Set(NewTranslator(
+						common.PipelineNameHostCustomMetrics,
+						hostCustomReceivers,
+						common.WithDestination(destination),
+						common.WithIndex(route)))
+				}
+				if hasDeltaPipeline {
+					translators.Set(NewTranslator(
+						common.PipelineNameHostDeltaMetrics,
+						deltaReceivers,
+						common.WithDestination(destination),
+						common.WithIndex(route),
+					))
+				}
+				if otlpReceivers := otlpReceiversFor(destination); otlpReceivers.Len() != 0 {
+					translators.Set(NewTranslator(
+						common.PipelineNameHostOtlpMetrics,
+						otlpReceivers,
+						common.WithDestination(destination),
+						common.WithIndex(route),
+					))
+				}
 			}
 		}
 	}
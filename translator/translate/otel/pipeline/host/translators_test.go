@@ -121,6 +121,102 @@ func TestTranslators(t *testing.T) {
 				},
 			},
 		},
+		"WithTextfileDestination": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"textfile": map[string]any{
+							"path": "/var/lib/node_exporter/cwagent.prom",
+						},
+						"cloudwatch": map[string]any{},
+					},
+					"metrics_collected": map[string]any{
+						"cpu": map[string]any{},
+						"net": map[string]any{},
+					},
+				},
+			},
+			configSection: MetricsKey,
+			want: map[string]want{
+				"metrics/host/cloudwatch": {
+					receivers: []string{"telegraf_cpu"},
+					exporters: []string{"awscloudwatch"},
+				},
+				"metrics/hostDeltaMetrics/cloudwatch": {
+					receivers: []string{"telegraf_net"},
+					exporters: []string{"awscloudwatch"},
+				},
+				"metrics/host/textfile": {
+					receivers: []string{"telegraf_cpu", "telegraf_net"},
+					exporters: []string{"textfile"},
+				},
+			},
+		},
+		"WithAlerts": {
+			input: map[string]any{
+				"alerts": map[string]any{
+					"rules": []any{
+						map[string]any{"name": "high_cpu", "metric": "cpu_usage_active", "comparison": ">", "threshold": 90},
+					},
+				},
+				"metrics": map[string]any{
+					"metrics_collected": map[string]any{
+						"cpu": map[string]any{},
+						"net": map[string]any{},
+					},
+				},
+			},
+			configSection: MetricsKey,
+			want: map[string]want{
+				"metrics/host": {
+					receivers: []string{"telegraf_cpu"},
+					exporters: []string{"awscloudwatch"},
+				},
+				"metrics/hostDeltaMetrics": {
+					receivers: []string{"telegraf_net"},
+					exporters: []string{"awscloudwatch"},
+				},
+				"metrics/host/alerts": {
+					receivers: []string{"telegraf_cpu"},
+					exporters: []string{"alerting"},
+				},
+				"metrics/hostDeltaMetrics/alerts": {
+					receivers: []string{"telegraf_net"},
+					exporters: []string{"alerting"},
+				},
+			},
+		},
+		"WithOTLPDestination": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"otlp": map[string]any{
+							"endpoint": "localhost:4317",
+						},
+						"cloudwatch": map[string]any{},
+					},
+					"metrics_collected": map[string]any{
+						"cpu": map[string]any{},
+						"net": map[string]any{},
+					},
+				},
+			},
+			configSection: MetricsKey,
+			want: map[string]want{
+				"metrics/host/cloudwatch": {
+					receivers: []string{"telegraf_cpu"},
+					exporters: []string{"awscloudwatch"},
+				},
+				"metrics/hostDeltaMetrics/cloudwatch": {
+					receivers: []string{"telegraf_net"},
+					exporters: []string{"awscloudwatch"},
+				},
+				"metrics/host/otlp": {
+					receivers: []string{"telegraf_cpu", "telegraf_net"},
+					exporters: []string{"otlpmetrics"},
+				},
+			},
+		},
 		"WithOtlpMetrics/CloudWatch": {
 			input: map[string]any{
 				"metrics": map[string]any{
@@ -137,6 +233,39 @@ func TestTranslators(t *testing.T) {
 				},
 			},
 		},
+		"WithOtlpMetrics/Routing": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"amp": map[string]any{
+							"workspace_id": "ws-12345",
+						},
+						"cloudwatch": map[string]any{},
+					},
+					"metrics_collected": map[string]any{
+						"otlp": []any{
+							map[string]any{
+								"destinations": []any{"amp"},
+							},
+							map[string]any{
+								"grpc_endpoint": "0.0.0.0:5317",
+							},
+						},
+					},
+				},
+			},
+			configSection: MetricsKey,
+			want: map[string]want{
+				"metrics/hostOtlpMetrics/cloudwatch": {
+					receivers: []string{"otlp/metrics/1"},
+					exporters: []string{"awscloudwatch"},
+				},
+				"metrics/host/amp": {
+					receivers: []string{"otlp/metrics/0", "otlp/metrics/1"},
+					exporters: []string{"prometheusremotewrite/amp"},
+				},
+			},
+		},
 		"WithOtlpMetrics/CloudWatchLogs": {
 			input: map[string]interface{}{
 				"logs": map[string]interface{}{
@@ -153,6 +282,39 @@ func TestTranslators(t *testing.T) {
 				},
 			},
 		},
+		"WithNamespaceRoutes": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"amp": map[string]any{
+							"workspace_id": "ws-12345",
+						},
+						"cloudwatch": map[string]any{},
+					},
+					"metrics_collected": map[string]any{
+						"cpu": map[string]any{},
+					},
+					"namespace_routes": []any{
+						map[string]any{"namespace": "TeamA", "metric_names": []any{"cpu_*"}},
+					},
+				},
+			},
+			configSection: MetricsKey,
+			want: map[string]want{
+				"metrics/host/cloudwatch": {
+					receivers: []string{"telegraf_cpu"},
+					exporters: []string{"awscloudwatch"},
+				},
+				"metrics/host/cloudwatch/route0": {
+					receivers: []string{"telegraf_cpu"},
+					exporters: []string{"awscloudwatch/route0"},
+				},
+				"metrics/host/amp": {
+					receivers: []string{"telegraf_cpu"},
+					exporters: []string{"prometheusremotewrite/amp"},
+				},
+			},
+		},
 		"WithCustomMetrics": {
 			input: map[string]interface{}{
 				"metrics": map[string]interface{}{
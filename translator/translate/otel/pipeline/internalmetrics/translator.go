@@ -0,0 +1,41 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package internalmetrics
+
+import (
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/awsemf"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/extension/agenthealth"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/selftelemetry"
+)
+
+type translator struct {
+}
+
+var _ common.Translator[*common.ComponentTranslators] = (*translator)(nil)
+
+func NewTranslator() common.Translator[*common.ComponentTranslators] {
+	return &translator{}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(component.DataTypeMetrics, common.PipelineNameInternalMetrics)
+}
+
+// Translate creates a pipeline publishing the agent's own metrics as EMF if
+// the internal metrics section is present.
+func (t *translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators, error) {
+	if conf == nil || !conf.IsSet(common.InternalMetricsConfigKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.InternalMetricsConfigKey}
+	}
+	return &common.ComponentTranslators{
+		Receivers:  common.NewTranslatorMap(selftelemetry.NewTranslatorWithName(common.PipelineNameInternalMetrics)),
+		Processors: common.NewTranslatorMap[component.Config](),
+		Exporters:  common.NewTranslatorMap(awsemf.NewTranslatorWithName(common.PipelineNameInternalMetrics)),
+		Extensions: common.NewTranslatorMap(agenthealth.NewTranslator(component.DataTypeLogs, []string{agenthealth.OperationPutLogEvents})),
+	}, nil
+}
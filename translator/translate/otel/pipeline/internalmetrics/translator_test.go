@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package internalmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	type want struct {
+		receivers  []string
+		processors []string
+		exporters  []string
+		extensions []string
+	}
+	tt := NewTranslator()
+	require.EqualValues(t, "metrics/internal_metrics", tt.ID().String())
+	testCases := map[string]struct {
+		input   map[string]any
+		want    *want
+		wantErr error
+	}{
+		"WithoutInternalMetricsKey": {
+			input:   map[string]any{"agent": map[string]any{}},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: common.InternalMetricsConfigKey},
+		},
+		"WithInternalMetricsKey": {
+			input: map[string]any{
+				"agent": map[string]any{
+					"internal_metrics": map[string]any{},
+				},
+			},
+			want: &want{
+				receivers:  []string{"selftelemetry/internal_metrics"},
+				processors: []string{},
+				exporters:  []string{"awsemf/internal_metrics"},
+				extensions: []string{"agenthealth/logs"},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(testCase.input)
+			got, err := tt.Translate(conf)
+			require.Equal(t, testCase.wantErr, err)
+			if testCase.want == nil {
+				require.Nil(t, got)
+			} else {
+				require.NotNil(t, got)
+				assert.Equal(t, testCase.want.receivers, collections.MapSlice(got.Receivers.Keys(), component.ID.String))
+				assert.Equal(t, testCase.want.processors, collections.MapSlice(got.Processors.Keys(), component.ID.String))
+				assert.Equal(t, testCase.want.exporters, collections.MapSlice(got.Exporters.Keys(), component.ID.String))
+				assert.Equal(t, testCase.want.extensions, collections.MapSlice(got.Extensions.Keys(), component.ID.String))
+			}
+		})
+	}
+}
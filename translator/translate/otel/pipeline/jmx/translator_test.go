@@ -212,6 +212,66 @@ func TestTranslator(t *testing.T) {
 				extensions: []string{"sigv4auth"},
 			},
 		},
+		"WithValidJMX/Object/Textfile": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"textfile": map[string]any{
+							"path": "/var/lib/node_exporter/cwagent.prom",
+						},
+					},
+					"metrics_collected": map[string]any{
+						"jmx": map[string]any{
+							"endpoint": "localhost:8080",
+							"jvm": map[string]any{
+								"measurement": []any{
+									"jvm.memory.heap.init",
+								},
+							},
+						},
+					},
+				},
+			},
+			index:       -1,
+			destination: "textfile",
+			want: &want{
+				pipelineID: "metrics/jmx/textfile",
+				receivers:  []string{"jmx"},
+				processors: []string{"filter/jmx", "resource/jmx"},
+				exporters:  []string{"textfile"},
+				extensions: []string{},
+			},
+		},
+		"WithValidJMX/Object/OTLP": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"otlp": map[string]any{
+							"endpoint": "localhost:4317",
+						},
+					},
+					"metrics_collected": map[string]any{
+						"jmx": map[string]any{
+							"endpoint": "localhost:8080",
+							"jvm": map[string]any{
+								"measurement": []any{
+									"jvm.memory.heap.init",
+								},
+							},
+						},
+					},
+				},
+			},
+			index:       -1,
+			destination: "otlp",
+			want: &want{
+				pipelineID: "metrics/jmx/otlp",
+				receivers:  []string{"jmx"},
+				processors: []string{"filter/jmx", "resource/jmx", "batch/jmx/otlp"},
+				exporters:  []string{"otlpmetrics"},
+				extensions: []string{},
+			},
+		},
 		"WithValidJMX/Object/AMP/EKS": {
 			input: map[string]any{
 				"metrics": map[string]any{
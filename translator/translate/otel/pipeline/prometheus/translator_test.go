@@ -111,6 +111,33 @@ func TestTranslator(t *testing.T) {
 				extensions: []string{"sigv4auth"},
 			},
 		},
+		"WithDimensionNormalization": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"dimension_normalization": map[string]any{
+						"synonyms": map[string]any{"instance": "host"},
+					},
+					"metrics_destinations": map[string]any{
+						"amp": map[string]any{
+							"workspace_id": "ws1234",
+						},
+					},
+					"metrics_collected": map[string]any{
+						"prometheus": map[string]any{
+							"prometheus_config_path": "test.yaml",
+						},
+					},
+				},
+			},
+			destination: common.AMPKey,
+			want: &want{
+				pipelineID: "metrics/prometheus/amp",
+				receivers:  []string{"prometheus"},
+				processors: []string{"dimensionnormalizer/prometheus/amp", "batch/prometheus/amp"},
+				exporters:  []string{"prometheusremotewrite/amp"},
+				extensions: []string{"sigv4auth"},
+			},
+		},
 		"WithValidCloudWatch": {
 			input: map[string]any{
 				"logs": map[string]any{
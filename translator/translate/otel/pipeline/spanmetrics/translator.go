@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package spanmetrics
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/awsemf"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/extension/agenthealth"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/spanmetrics"
+)
+
+var (
+	xrayKey = common.ConfigKey(common.TracesKey, common.TracesCollectedKey, common.XrayKey)
+	otlpKey = common.ConfigKey(common.TracesKey, common.TracesCollectedKey, common.OtlpKey)
+)
+
+type translator struct {
+}
+
+var _ common.Translator[*common.ComponentTranslators] = (*translator)(nil)
+
+func NewTranslator() common.Translator[*common.ComponentTranslators] {
+	return &translator{}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(component.DataTypeMetrics, common.PipelineNameSpanMetrics)
+}
+
+// Translate creates a pipeline publishing the metrics of the spans of the
+// traces pipeline as EMF if the span metrics section is present. The spans are
+// recorded by the span metrics processor of the traces pipeline.
+func (t *translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators, error) {
+	if conf == nil || !conf.IsSet(common.SpanMetricsConfigKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.SpanMetricsConfigKey}
+	}
+	if !conf.IsSet(xrayKey) && !conf.IsSet(otlpKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: fmt.Sprint(xrayKey, " or ", otlpKey)}
+	}
+	return &common.ComponentTranslators{
+		Receivers:  common.NewTranslatorMap(spanmetrics.NewTranslatorWithName(common.PipelineNameSpanMetrics)),
+		Processors: common.NewTranslatorMap[component.Config](),
+		Exporters:  common.NewTranslatorMap(awsemf.NewTranslatorWithName(common.PipelineNameSpanMetrics)),
+		Extensions: common.NewTranslatorMap(agenthealth.NewTranslator(component.DataTypeLogs, []string{agenthealth.OperationPutLogEvents})),
+	}, nil
+}
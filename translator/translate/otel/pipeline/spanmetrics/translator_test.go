@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package spanmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	type want struct {
+		receivers  []string
+		processors []string
+		exporters  []string
+		extensions []string
+	}
+	tt := NewTranslator()
+	require.EqualValues(t, "metrics/span_metrics", tt.ID().String())
+	testCases := map[string]struct {
+		input   map[string]any
+		want    *want
+		wantErr error
+	}{
+		"WithoutSpanMetricsKey": {
+			input: map[string]any{
+				"traces": map[string]any{"traces_collected": map[string]any{"otlp": map[string]any{}}},
+			},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: common.SpanMetricsConfigKey},
+		},
+		"WithoutTracesCollected": {
+			input: map[string]any{
+				"traces": map[string]any{"span_metrics": map[string]any{}},
+			},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: "traces::traces_collected::xray or traces::traces_collected::otlp"},
+		},
+		"WithSpanMetricsKey": {
+			input: map[string]any{
+				"traces": map[string]any{
+					"span_metrics":     map[string]any{},
+					"traces_collected": map[string]any{"xray": map[string]any{}},
+				},
+			},
+			want: &want{
+				receivers:  []string{"spanmetrics/span_metrics"},
+				processors: []string{},
+				exporters:  []string{"awsemf/span_metrics"},
+				extensions: []string{"agenthealth/logs"},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(testCase.input)
+			got, err := tt.Translate(conf)
+			require.Equal(t, testCase.wantErr, err)
+			if testCase.want == nil {
+				require.Nil(t, got)
+			} else {
+				require.NotNil(t, got)
+				assert.Equal(t, testCase.want.receivers, collections.MapSlice(got.Receivers.Keys(), component.ID.String))
+				assert.Equal(t, testCase.want.processors, collections.MapSlice(got.Processors.Keys(), component.ID.String))
+				assert.Equal(t, testCase.want.exporters, collections.MapSlice(got.Exporters.Keys(), component.ID.String))
+				assert.Equal(t, testCase.want.extensions, collections.MapSlice(got.Extensions.Keys(), component.ID.String))
+			}
+		})
+	}
+}
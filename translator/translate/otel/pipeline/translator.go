@@ -11,6 +11,7 @@ import (
 	"go.opentelemetry.io/collector/service/pipelines"
 
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/memorylimiter"
 )
 
 var (
@@ -42,7 +43,9 @@ func (t *translator) ID() component.ID {
 	return component.NewID(newType)
 }
 
-// Translate creates the pipeline configuration.
+// Translate creates the pipeline configuration. If the memory limiter is
+// configured, it is the first processor of every pipeline so that the data is
+// refused before it is buffered by the other processors.
 func (t *translator) Translate(conf *confmap.Conf) (*Translation, error) {
 	translation := Translation{
 		Pipelines: make(pipelines.Config),
@@ -53,11 +56,20 @@ func (t *translator) Translate(conf *confmap.Conf) (*Translation, error) {
 			Extensions: common.NewTranslatorMap[component.Config](),
 		},
 	}
+	var limiter common.Translator[component.Config]
+	if memorylimiter.IsSet(conf) {
+		limiter = memorylimiter.NewTranslator()
+		translation.Translators.Processors.Set(limiter)
+	}
 	t.translators.Range(func(pt common.Translator[*common.ComponentTranslators]) {
 		if pipeline, _ := pt.Translate(conf); pipeline != nil {
+			processors := pipeline.Processors.Keys()
+			if limiter != nil {
+				processors = append([]component.ID{limiter.ID()}, processors...)
+			}
 			translation.Pipelines[pt.ID()] = &pipelines.PipelineConfig{
 				Receivers:  pipeline.Receivers.Keys(),
-				Processors: pipeline.Processors.Keys(),
+				Processors: processors,
 				Exporters:  pipeline.Exporters.Keys(),
 			}
 			translation.Translators.Receivers.Merge(pipeline.Receivers)
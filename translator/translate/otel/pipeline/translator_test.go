@@ -10,7 +10,9 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/batchprocessor"
 )
 
 type testTranslator struct {
@@ -45,3 +47,25 @@ func TestTranslator(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, got)
 }
+
+func TestTranslatorWithMemoryLimiter(t *testing.T) {
+	batch := batchprocessor.NewTranslatorWithNameAndSection("", common.MetricsKey)
+	pt := NewTranslator(common.NewTranslatorMap[*common.ComponentTranslators](&testTranslator{
+		result: &common.ComponentTranslators{
+			Receivers:  common.NewTranslatorMap[component.Config](),
+			Processors: common.NewTranslatorMap(batch),
+			Exporters:  common.NewTranslatorMap[component.Config](),
+			Extensions: common.NewTranslatorMap[component.Config](),
+		},
+	}))
+	conf := confmap.NewFromStringMap(map[string]interface{}{
+		"agent": map[string]interface{}{"memory_limiter": map[string]interface{}{}},
+	})
+	got, err := pt.Translate(conf)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	for _, pipeline := range got.Pipelines {
+		require.Equal(t, []string{"memory_limiter", "batch"}, collections.MapSlice(pipeline.Processors, component.ID.String))
+	}
+	require.Equal(t, []string{"memory_limiter", "batch"}, collections.MapSlice(got.Translators.Processors.Keys(), component.ID.String))
+}
@@ -5,6 +5,7 @@ package xray
 
 import (
 	"fmt"
+	"strconv"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
@@ -12,8 +13,15 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
 	awsxrayexporter "github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/awsxray"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/otlpmetrics"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/extension/agenthealth"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/probabilisticsampler"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/scrubbing"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/spanmetrics"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/tailsampling"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/transforms"
 	awsxrayreceiver "github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/awsxray"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/otlp"
 )
@@ -28,16 +36,41 @@ var (
 )
 
 type translator struct {
+	name string
+	common.IndexProvider
 }
 
 var _ common.Translator[*common.ComponentTranslators] = (*translator)(nil)
 
-func NewTranslator() common.Translator[*common.ComponentTranslators] {
-	return &translator{}
+// NewTranslators creates a traces pipeline for each of the traces
+// destinations, or the X-Ray pipeline if no destinations are listed.
+func NewTranslators(conf *confmap.Conf) pipeline.TranslatorMap {
+	translators := common.NewTranslatorMap[*common.ComponentTranslators]()
+	destinations, ok := conf.Get(common.TracesDestinationsConfigKey).([]any)
+	if !ok {
+		translators.Set(NewTranslator())
+		return translators
+	}
+	for index := range destinations {
+		translators.Set(NewTranslator(common.WithIndex(index)))
+	}
+	return translators
+}
+
+func NewTranslator(opts ...common.TranslatorOption) common.Translator[*common.ComponentTranslators] {
+	t := &translator{name: pipelineName}
+	t.SetIndex(-1)
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.Index() != -1 {
+		t.name += "/" + strconv.Itoa(t.Index())
+	}
+	return t
 }
 
 func (t *translator) ID() component.ID {
-	return component.NewIDWithName(component.DataTypeTraces, pipelineName)
+	return component.NewIDWithName(component.DataTypeTraces, t.name)
 }
 
 func (t *translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators, error) {
@@ -46,15 +79,62 @@ func (t *translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators
 	}
 	translators := &common.ComponentTranslators{
 		Receivers:  common.NewTranslatorMap[component.Config](),
-		Processors: common.NewTranslatorMap(processor.NewDefaultTranslatorWithName(pipelineName, batchprocessor.NewFactory())),
-		Exporters:  common.NewTranslatorMap(awsxrayexporter.NewTranslator()),
-		Extensions: common.NewTranslatorMap(agenthealth.NewTranslator(component.DataTypeTraces, []string{agenthealth.OperationPutTraceSegments}),
-			agenthealth.NewTranslatorWithStatusCode(component.MustNewType("statuscode"), nil, true)),
+		Processors: common.NewTranslatorMap[component.Config](),
+		Exporters:  common.NewTranslatorMap[component.Config](),
+		Extensions: common.NewTranslatorMap[component.Config](),
+	}
+	destinationType := common.XrayKey
+	if t.Index() != -1 {
+		destinationType, _ = common.GetTracesDestination(conf, t.Index())
+	}
+	switch destinationType {
+	case common.XrayKey:
+		if t.Index() == -1 {
+			translators.Exporters.Set(awsxrayexporter.NewTranslator())
+		} else {
+			translators.Exporters.Set(awsxrayexporter.NewTranslatorWithDestination(t.name, t.Index()))
+		}
+		translators.Extensions.Set(agenthealth.NewTranslator(component.DataTypeTraces, []string{agenthealth.OperationPutTraceSegments}))
+		translators.Extensions.Set(agenthealth.NewTranslatorWithStatusCode(component.MustNewType("statuscode"), nil, true))
+	case common.OtlpKey:
+		translators.Exporters.Set(otlpmetrics.NewTracesTranslatorWithName(t.name, t.Index()))
+	default:
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: fmt.Sprintf("%s[%d]", common.TracesDestinationsConfigKey, t.Index())}
+	}
+	// the span metrics count the spans the sampling drops, once for all the destinations
+	if conf.IsSet(common.SpanMetricsConfigKey) && t.Index() <= 0 {
+		translators.Processors.Set(spanmetrics.NewTranslatorWithName(t.name))
+	}
+	// the sampling decision is made on complete traces, so it has to come before the batching
+	if conf.IsSet(tailsampling.ConfigKey) {
+		translators.Processors.Set(tailsampling.NewTranslatorWithName(t.name))
+	}
+	if t.Index() != -1 && probabilisticsampler.IsSampled(conf, t.Index()) {
+		translators.Processors.Set(probabilisticsampler.NewTranslatorWithName(t.name, t.Index()))
+	}
+	if transforms.IsSet(conf, component.DataTypeTraces) {
+		translators.Processors.Set(transforms.NewTranslatorWithName(t.name, component.DataTypeTraces))
+	}
+	// the span attributes are scrubbed before they leave the agent
+	if scrubbing.HasKeys(conf) {
+		translators.Processors.Set(scrubbing.NewAttributesTranslatorWithName(t.name))
+	}
+	if scrubbing.HasPatterns(conf) {
+		translators.Processors.Set(scrubbing.NewTransformTranslatorWithName(t.name))
 	}
+	translators.Processors.Set(processor.NewDefaultTranslatorWithName(t.name, batchprocessor.NewFactory()))
 	if conf.IsSet(xrayKey) {
 		translators.Receivers.Set(awsxrayreceiver.NewTranslator())
 	}
-	if conf.IsSet(otlpKey) {
+	if instances, ok := conf.Get(otlpKey).([]any); ok {
+		for index := range instances {
+			translators.Receivers.Set(otlp.NewTranslator(
+				otlp.WithDataType(component.DataTypeTraces),
+				otlp.WithConfigKey(otlpKey),
+				common.WithIndex(index),
+			))
+		}
+	} else if conf.IsSet(otlpKey) {
 		translators.Receivers.Set(otlp.NewTranslator(
 			otlp.WithDataType(component.DataTypeTraces),
 			otlp.WithConfigKey(otlpKey)),
@@ -80,6 +80,110 @@ func TestTranslator(t *testing.T) {
 				extensions: []string{"agenthealth/traces", "agenthealth/statuscode"},
 			},
 		},
+		"WithMultipleOtlp": {
+			input: map[string]interface{}{
+				"traces": map[string]interface{}{
+					"traces_collected": map[string]interface{}{
+						"otlp": []interface{}{
+							map[string]interface{}{},
+							map[string]interface{}{"grpc_endpoint": "0.0.0.0:5317"},
+						},
+					},
+				},
+			},
+			want: &want{
+				receivers:  []string{"otlp/traces/0", "otlp/traces/1"},
+				processors: []string{"batch/xray"},
+				exporters:  []string{"awsxray"},
+				extensions: []string{"agenthealth/traces", "agenthealth/statuscode"},
+			},
+		},
+		"WithTailSampling": {
+			input: map[string]interface{}{
+				"traces": map[string]interface{}{
+					"traces_collected": map[string]interface{}{
+						"xray": nil,
+					},
+					"tail_sampling": map[string]interface{}{
+						"policies": map[string]interface{}{"errors": true},
+					},
+				},
+			},
+			want: &want{
+				receivers:  []string{"awsxray"},
+				processors: []string{"tail_sampling/xray", "batch/xray"},
+				exporters:  []string{"awsxray"},
+				extensions: []string{"agenthealth/traces", "agenthealth/statuscode"},
+			},
+		},
+		"WithScrubbing": {
+			input: map[string]interface{}{
+				"traces": map[string]interface{}{
+					"traces_collected": map[string]interface{}{
+						"xray": nil,
+					},
+					"tail_sampling": map[string]interface{}{
+						"policies": map[string]interface{}{"errors": true},
+					},
+					"scrubbing": map[string]interface{}{
+						"http_urls": true,
+						"keys":      []interface{}{"user.email"},
+					},
+				},
+			},
+			want: &want{
+				receivers:  []string{"awsxray"},
+				processors: []string{"tail_sampling/xray", "attributes/xray", "transform/xray", "batch/xray"},
+				exporters:  []string{"awsxray"},
+				extensions: []string{"agenthealth/traces", "agenthealth/statuscode"},
+			},
+		},
+		"WithTransforms": {
+			input: map[string]interface{}{
+				"traces": map[string]interface{}{
+					"traces_collected": map[string]interface{}{
+						"xray": nil,
+					},
+					"tail_sampling": map[string]interface{}{
+						"policies": map[string]interface{}{"errors": true},
+					},
+					"transforms": []interface{}{
+						map[string]interface{}{
+							"context":    "span",
+							"statements": []interface{}{`set(attributes["env"], "prod")`},
+						},
+					},
+					"scrubbing": map[string]interface{}{
+						"keys": []interface{}{"user.email"},
+					},
+				},
+			},
+			want: &want{
+				receivers:  []string{"awsxray"},
+				processors: []string{"tail_sampling/xray", "transform/transforms/xray", "attributes/xray", "batch/xray"},
+				exporters:  []string{"awsxray"},
+				extensions: []string{"agenthealth/traces", "agenthealth/statuscode"},
+			},
+		},
+		"WithSpanMetrics": {
+			input: map[string]interface{}{
+				"traces": map[string]interface{}{
+					"traces_collected": map[string]interface{}{
+						"xray": nil,
+					},
+					"tail_sampling": map[string]interface{}{
+						"policies": map[string]interface{}{"errors": true},
+					},
+					"span_metrics": map[string]interface{}{},
+				},
+			},
+			want: &want{
+				receivers:  []string{"awsxray"},
+				processors: []string{"spanmetrics/xray", "tail_sampling/xray", "batch/xray"},
+				exporters:  []string{"awsxray"},
+				extensions: []string{"agenthealth/traces", "agenthealth/statuscode"},
+			},
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -98,3 +202,62 @@ func TestTranslator(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslators(t *testing.T) {
+	type want struct {
+		pipeline   string
+		processors []string
+		exporters  []string
+		extensions []string
+	}
+	conf := confmap.NewFromStringMap(map[string]any{
+		"traces": map[string]any{
+			"traces_collected": map[string]any{
+				"xray": map[string]any{},
+			},
+			"span_metrics": map[string]any{},
+			"traces_destinations": []any{
+				map[string]any{"xray": map[string]any{}},
+				map[string]any{"xray": map[string]any{"region": "us-west-2", "sampling_percentage": 10}},
+				map[string]any{"otlp": map[string]any{"endpoint": "localhost:4317", "sampling_percentage": 100}},
+			},
+		},
+	})
+	wants := []want{
+		{
+			pipeline:   "xray/0",
+			processors: []string{"spanmetrics/xray/0", "batch/xray/0"},
+			exporters:  []string{"awsxray/xray/0"},
+			extensions: []string{"agenthealth/traces", "agenthealth/statuscode"},
+		},
+		{
+			pipeline:   "xray/1",
+			processors: []string{"probabilistic_sampler/xray/1", "batch/xray/1"},
+			exporters:  []string{"awsxray/xray/1"},
+			extensions: []string{"agenthealth/traces", "agenthealth/statuscode"},
+		},
+		{
+			pipeline:   "xray/2",
+			processors: []string{"batch/xray/2"},
+			exporters:  []string{"otlptraces/xray/2"},
+			extensions: []string{},
+		},
+	}
+	translators := NewTranslators(conf)
+	require.Equal(t, len(wants), translators.Len())
+	for _, w := range wants {
+		t.Run(w.pipeline, func(t *testing.T) {
+			tt, ok := translators.Get(component.NewIDWithName(component.DataTypeTraces, w.pipeline))
+			require.True(t, ok)
+			got, err := tt.Translate(conf)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"awsxray"}, collections.MapSlice(got.Receivers.Keys(), component.ID.String))
+			assert.Equal(t, w.processors, collections.MapSlice(got.Processors.Keys(), component.ID.String))
+			assert.Equal(t, w.exporters, collections.MapSlice(got.Exporters.Keys(), component.ID.String))
+			assert.Equal(t, w.extensions, collections.MapSlice(got.Extensions.Keys(), component.ID.String))
+		})
+	}
+
+	translators = NewTranslators(confmap.New())
+	assert.Equal(t, []string{"traces/xray"}, collections.MapSlice(translators.Keys(), component.ID.String))
+}
@@ -88,11 +88,11 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 	switch mode {
 	case config.ModeEKS:
 		cfg.Resolvers = []appsignalsconfig.Resolver{
-			appsignalsconfig.NewEKSResolver(hostedIn),
+			t.withMetadataSharing(conf, appsignalsconfig.NewEKSResolver(hostedIn)),
 		}
 	case config.ModeK8sEC2, config.ModeK8sOnPrem:
 		cfg.Resolvers = []appsignalsconfig.Resolver{
-			appsignalsconfig.NewK8sResolver(hostedIn),
+			t.withMetadataSharing(conf, appsignalsconfig.NewK8sResolver(hostedIn)),
 		}
 	case config.ModeEC2:
 		cfg.Resolvers = []appsignalsconfig.Resolver{
@@ -114,6 +114,20 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 	return t.translateCustomRules(conf, configKey, cfg)
 }
 
+// withMetadataSharing enables the workload metadata sharing between the agents in the cluster if
+// share_kubernetes_metadata is set.
+func (t *translator) withMetadataSharing(conf *confmap.Conf, resolver appsignalsconfig.Resolver) appsignalsconfig.Resolver {
+	for _, appSignalsKey := range []string{common.AppSignals, common.AppSignalsFallback} {
+		if share, ok := common.GetBool(conf, common.ConfigKey(common.LogsKey, common.MetricsCollectedKey, appSignalsKey, "share_kubernetes_metadata")); ok {
+			if share {
+				resolver.MetadataSharing = &appsignalsconfig.MetadataSharingConfig{}
+			}
+			break
+		}
+	}
+	return resolver
+}
+
 func (t *translator) translateMetricLimiterConfig(conf *confmap.Conf, configKey []string) (*appsignalsconfig.LimiterConfig, error) {
 	limiterConfigKey := common.ConfigKey(configKey[0], "limiter")
 	if !conf.IsSet(limiterConfigKey) {
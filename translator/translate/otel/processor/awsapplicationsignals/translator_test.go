@@ -24,6 +24,8 @@ import (
 var (
 	//go:embed testdata/config_eks.yaml
 	validAppSignalsYamlEKS string
+	//go:embed testdata/config_eks_metadata_sharing.yaml
+	validAppSignalsYamlEKSMetadataSharing string
 	//go:embed testdata/config_k8s.yaml
 	validAppSignalsYamlK8s string
 	//go:embed testdata/config_ec2.yaml
@@ -70,6 +72,36 @@ func TestTranslate(t *testing.T) {
 			kubernetesMode: translatorConfig.ModeEKS,
 			mode:           translatorConfig.ModeEC2,
 		},
+		"WithAppSignalsMetadataSharingEKS": {
+			input: map[string]interface{}{
+				"logs": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"application_signals": map[string]interface{}{
+							"hosted_in":                 "test",
+							"share_kubernetes_metadata": true,
+						},
+					},
+				}},
+			want:           validAppSignalsYamlEKSMetadataSharing,
+			isKubernetes:   true,
+			kubernetesMode: translatorConfig.ModeEKS,
+			mode:           translatorConfig.ModeEC2,
+		},
+		"WithAppSignalsMetadataSharingDisabledEKS": {
+			input: map[string]interface{}{
+				"logs": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"application_signals": map[string]interface{}{
+							"hosted_in":                 "test",
+							"share_kubernetes_metadata": false,
+						},
+					},
+				}},
+			want:           validAppSignalsYamlEKS,
+			isKubernetes:   true,
+			kubernetesMode: translatorConfig.ModeEKS,
+			mode:           translatorConfig.ModeEC2,
+		},
 		"WithAppSignalsCustomRulesEnabledEKS": {
 			input:          validJsonMap,
 			want:           validAppSignalsRulesYamlEKS,
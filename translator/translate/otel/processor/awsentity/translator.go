@@ -11,6 +11,7 @@ import (
 	"go.opentelemetry.io/collector/processor"
 
 	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/awsentity"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
@@ -55,13 +56,18 @@ func (t *translator) ID() component.ID {
 }
 
 func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
-	// Do not send entity for ECS
+	cfg := t.factory.CreateDefaultConfig().(*awsentity.Config)
+
+	// Only the task resource entity is sent for ECS
 	if context.CurrentContext().RunInContainer() && ecsutil.GetECSUtilSingleton().IsECS() {
-		return nil, nil
+		if t.entityType != Resource {
+			return nil, nil
+		}
+		cfg.EntityType = Resource
+		cfg.Platform = config.ModeECS
+		return cfg, nil
 	}
 
-	cfg := t.factory.CreateDefaultConfig().(*awsentity.Config)
-
 	if t.entityType != "" {
 		cfg.EntityType = t.entityType
 	}
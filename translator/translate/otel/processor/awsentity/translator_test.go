@@ -20,6 +20,7 @@ func TestTranslate(t *testing.T) {
 		input          map[string]interface{}
 		mode           string
 		kubernetesMode string
+		entityType     string
 		want           *awsentity.Config
 	}{
 		"OnlyProfile": {
@@ -44,6 +45,21 @@ func TestTranslate(t *testing.T) {
 			mode:  config.ModeECS,
 			want:  nil,
 		},
+		"ECSResource": {
+			input:      map[string]interface{}{},
+			mode:       config.ModeECS,
+			entityType: Resource,
+			want: &awsentity.Config{
+				EntityType: Resource,
+				Platform:   config.ModeECS,
+			},
+		},
+		"ECSService": {
+			input:      map[string]interface{}{},
+			mode:       config.ModeECS,
+			entityType: Service,
+			want:       nil,
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -58,6 +74,9 @@ func TestTranslate(t *testing.T) {
 			}
 			tt := NewTranslator()
 			assert.Equal(t, "awsentity", tt.ID().String())
+			if testCase.entityType != "" {
+				tt = NewTranslatorWithEntityType(testCase.entityType, "", false)
+			}
 			conf := confmap.NewFromStringMap(testCase.input)
 			got, err := tt.Translate(conf)
 			assert.NoError(t, err)
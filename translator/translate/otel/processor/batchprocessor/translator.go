@@ -20,28 +20,47 @@ var defaultForceFlushInterval = map[string]time.Duration{
 	common.LogsKey:    5 * time.Second,
 }
 
+// defaultDestination is the destination of the pipeline settings when the
+// caller does not set one.
+var defaultDestination = map[string]string{
+	common.MetricsKey: common.CloudWatchKey,
+	common.LogsKey:    common.CloudWatchLogsKey,
+}
+
 type translator struct {
 	name                string
 	telemetrySectionKey string
-	factory             processor.Factory
+	common.DestinationProvider
+	factory processor.Factory
 }
 
 var _ common.Translator[component.Config] = (*translator)(nil)
 
-func NewTranslatorWithNameAndSection(name string, telemetrySectionKey string) common.Translator[component.Config] {
-	return &translator{name, telemetrySectionKey, batchprocessor.NewFactory()}
+func NewTranslatorWithNameAndSection(name string, telemetrySectionKey string, opts ...common.TranslatorOption) common.Translator[component.Config] {
+	t := &translator{name: name, telemetrySectionKey: telemetrySectionKey, factory: batchprocessor.NewFactory()}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.Destination() == "" {
+		t.SetDestination(defaultDestination[telemetrySectionKey])
+	}
+	return t
 }
 
 func (t *translator) ID() component.ID {
 	return component.NewIDWithName(t.factory.Type(), t.name)
 }
 
+// Translate creates a batch processor config. The force_flush_interval of the
+// section takes precedence over the batch timeout of the pipeline settings of
+// the destination.
 func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 	cfg := t.factory.CreateDefaultConfig().(*batchprocessor.Config)
 	if duration, ok := common.GetDuration(conf, common.ConfigKey(t.telemetrySectionKey, common.ForceFlushIntervalKey)); ok {
 		cfg.Timeout = duration
 	} else if defaultDuration, ok := defaultForceFlushInterval[t.telemetrySectionKey]; ok {
 		cfg.Timeout = defaultDuration
+		common.GetPipelineConfig(conf, t.Destination()).ApplyBatchTimeout(&cfg.Timeout)
 	} else {
 		return cfg, fmt.Errorf("default force_flush_interval not defined for %s", t.telemetrySectionKey)
 	}
@@ -78,6 +78,61 @@ func TestTranslator(t *testing.T) {
 				SendBatchMaxSize: 0,
 			},
 		},
+		"PipelineDefaults": {
+			translator: NewTranslatorWithNameAndSection("test", common.LogsKey),
+			input: map[string]interface{}{
+				"logs": map[string]interface{}{},
+				"service": map[string]interface{}{
+					"pipeline_defaults": map[string]interface{}{
+						"batch_timeout": 10,
+					},
+				},
+			},
+			want: &batchprocessor.Config{
+				Timeout:          10 * time.Second,
+				SendBatchSize:    8192,
+				SendBatchMaxSize: 0,
+			},
+		},
+		"PipelineOverrides": {
+			translator: NewTranslatorWithNameAndSection("test", common.MetricsKey, common.WithDestination(common.AMPKey)),
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{},
+				"service": map[string]interface{}{
+					"pipeline_defaults": map[string]interface{}{
+						"batch_timeout": 10,
+					},
+					"pipeline_overrides": map[string]interface{}{
+						"amp": map[string]interface{}{
+							"batch_timeout": 0.5,
+						},
+					},
+				},
+			},
+			want: &batchprocessor.Config{
+				Timeout:          500 * time.Millisecond,
+				SendBatchSize:    8192,
+				SendBatchMaxSize: 0,
+			},
+		},
+		"ForceFlushIntervalOverPipelineDefaults": {
+			translator: NewTranslatorWithNameAndSection("test", common.MetricsKey, common.WithDestination(common.OtlpKey)),
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{
+					"force_flush_interval": 30,
+				},
+				"service": map[string]interface{}{
+					"pipeline_defaults": map[string]interface{}{
+						"batch_timeout": 10,
+					},
+				},
+			},
+			want: &batchprocessor.Config{
+				Timeout:          30 * time.Second,
+				SendBatchSize:    8192,
+				SendBatchMaxSize: 0,
+			},
+		},
 	}
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
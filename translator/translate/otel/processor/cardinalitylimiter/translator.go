@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cardinalitylimiter
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/processor"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/cardinalitylimiter"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+var (
+	configKey           = common.ConfigKey(common.MetricsKey, "cardinality_limit")
+	maxDimensionSetsKey = common.ConfigKey(configKey, "max_dimension_sets")
+	expirationKey       = common.ConfigKey(configKey, "expiration")
+)
+
+type translator struct {
+	name    string
+	factory processor.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+func NewTranslatorWithName(name string) common.Translator[component.Config] {
+	return &translator{name, cardinalitylimiter.NewFactory()}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates the limiter from the metrics.cardinality_limit section. The
+// expiration is in seconds.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if !IsSet(conf) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: configKey}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*cardinalitylimiter.Config)
+	if maxDimensionSets, ok := common.GetNumber(conf, maxDimensionSetsKey); ok {
+		cfg.MaxDimensionSets = int(maxDimensionSets)
+	}
+	if expiration, ok := common.GetNumber(conf, expirationKey); ok {
+		cfg.Expiration = time.Duration(expiration) * time.Second
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", configKey, err)
+	}
+	return cfg, nil
+}
+
+// IsSet returns true if the cardinality limit is configured.
+func IsSet(conf *confmap.Conf) bool {
+	return conf != nil && conf.IsSet(configKey)
+}
@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cardinalitylimiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/cardinalitylimiter"
+)
+
+func TestTranslator(t *testing.T) {
+	testCases := map[string]struct {
+		input map[string]any
+		want  *cardinalitylimiter.Config
+	}{
+		"WithDefaults": {
+			input: map[string]any{},
+			want:  &cardinalitylimiter.Config{MaxDimensionSets: 1000, Expiration: time.Hour},
+		},
+		"WithLimitAndExpiration": {
+			input: map[string]any{"max_dimension_sets": 500, "expiration": 0},
+			want:  &cardinalitylimiter.Config{MaxDimensionSets: 500},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(map[string]any{
+				"metrics": map[string]any{"cardinality_limit": testCase.input},
+			})
+			assert.True(t, IsSet(conf))
+			tt := NewTranslatorWithName("hostCustomMetrics")
+			assert.Equal(t, "cardinalitylimiter/hostCustomMetrics", tt.ID().String())
+			got, err := tt.Translate(conf)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestTranslatorInvalid(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"metrics": map[string]any{"cardinality_limit": map[string]any{"max_dimension_sets": 0}},
+	})
+	_, err := NewTranslatorWithName("host").Translate(conf)
+	assert.EqualError(t, err, "invalid metrics::cardinality_limit: max_dimension_sets must be greater than 0")
+}
+
+func TestTranslatorMissingKey(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{"metrics": map[string]any{}})
+	assert.False(t, IsSet(conf))
+	assert.False(t, IsSet(nil))
+	_, err := NewTranslatorWithName("host").Translate(conf)
+	assert.Error(t, err)
+}
@@ -26,8 +26,10 @@ const (
 var (
 	netKey     = common.ConfigKey(common.MetricsKey, common.MetricsCollectedKey, common.NetKey)
 	diskioKey  = common.ConfigKey(common.MetricsKey, common.MetricsCollectedKey, common.DiskIOKey)
+	efaKey     = common.ConfigKey(common.MetricsKey, common.MetricsCollectedKey, common.EfaKey)
 	otlpKey    = common.ConfigKey(common.MetricsKey, common.MetricsCollectedKey, common.OtlpKey)
 	otlpEmfKey = common.ConfigKey(common.LogsKey, common.MetricsCollectedKey, common.OtlpKey)
+	metricsKey = common.ConfigKey(common.MetricsKey, common.MetricsCollectedKey)
 
 	exclusions = map[string][]string{
 		// DiskIO and Net Metrics are cumulative metrics
@@ -39,7 +41,13 @@ var (
 )
 
 func WithDefaultKeys() common.TranslatorOption {
-	return WithConfigKeys(diskioKey, netKey, otlpKey, otlpEmfKey)
+	return WithConfigKeys(diskioKey, netKey, efaKey, otlpKey, otlpEmfKey)
+}
+
+// WithAllMetrics converts the sums of all the metrics collected, apart from
+// the default exclusions.
+func WithAllMetrics() common.TranslatorOption {
+	return WithConfigKeys(diskioKey, netKey, efaKey, otlpKey, otlpEmfKey, metricsKey)
 }
 
 func WithConfigKeys(keys ...string) common.TranslatorOption {
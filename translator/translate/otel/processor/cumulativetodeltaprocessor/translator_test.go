@@ -31,7 +31,7 @@ func TestTranslator(t *testing.T) {
 					},
 				},
 			},
-			wantErr: &common.MissingKeyError{ID: cdpTranslator.ID(), JsonKey: fmt.Sprint(diskioKey, " or ", netKey, " or ", otlpKey, " or ", otlpEmfKey)},
+			wantErr: &common.MissingKeyError{ID: cdpTranslator.ID(), JsonKey: fmt.Sprint(diskioKey, " or ", netKey, " or ", efaKey, " or ", otlpKey, " or ", otlpEmfKey)},
 		},
 		"GenerateDeltaProcessorConfigWithNet": {
 			input: map[string]any{
@@ -45,6 +45,18 @@ func TestTranslator(t *testing.T) {
 				"initial_value": "drop",
 			},
 		},
+		"GenerateDeltaProcessorConfigWithEfa": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_collected": map[string]any{
+						"efa": map[string]any{},
+					},
+				},
+			},
+			want: map[string]any{
+				"initial_value": "drop",
+			},
+		},
 		"GenerateDeltaProcessorConfigWithDiskIO": {
 			input: map[string]any{
 				"metrics": map[string]any{
@@ -80,3 +92,21 @@ func TestTranslator(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslatorWithAllMetrics(t *testing.T) {
+	cdpTranslator := NewTranslator(common.WithName("host/cloudwatch"), WithAllMetrics())
+	conf := confmap.NewFromStringMap(map[string]any{
+		"metrics": map[string]any{
+			"metrics_collected": map[string]any{
+				"cpu":    map[string]any{},
+				"diskio": map[string]any{},
+			},
+		},
+	})
+	got, err := cdpTranslator.Translate(conf)
+	require.NoError(t, err)
+	gotCfg, ok := got.(*cumulativetodeltaprocessor.Config)
+	require.True(t, ok)
+	assert.Equal(t, []string{"iops_in_progress", "diskio_iops_in_progress"}, gotCfg.Exclude.Metrics)
+	assert.Empty(t, gotCfg.Include.Metrics)
+}
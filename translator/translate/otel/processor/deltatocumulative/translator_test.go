@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package deltatocumulative
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/deltatocumulative"
+)
+
+func TestTranslator(t *testing.T) {
+	tt := NewTranslatorWithName("host/amp")
+	assert.Equal(t, "deltatocumulative/host/amp", tt.ID().String())
+	got, err := tt.Translate(confmap.NewFromStringMap(map[string]any{"metrics": map[string]any{}}))
+	require.NoError(t, err)
+	assert.Equal(t, &deltatocumulative.Config{MaxStale: 5 * time.Minute, MaxStreams: 100000}, got)
+
+	_, err = tt.Translate(confmap.NewFromStringMap(map[string]any{"logs": map[string]any{}}))
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package derivedmetrics
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/processor"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/derivedmetrics"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	metricNameKey = "metric_name"
+	expressionKey = "expression"
+	unitKey       = "unit"
+)
+
+var configKey = common.ConfigKey(common.MetricsKey, "derived")
+
+type translator struct {
+	name    string
+	factory processor.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+func NewTranslatorWithName(name string) common.Translator[component.Config] {
+	return &translator{name, derivedmetrics.NewFactory()}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates the derived metrics from the metrics.derived section. The
+// expressions are validated here, so that the invalid ones are reported by
+// the config translator instead of when the agent starts.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if !IsSet(conf) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: configKey}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*derivedmetrics.Config)
+	entries, ok := conf.Get(configKey).([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list", configKey)
+	}
+	for _, entry := range entries {
+		fields, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a list of objects", configKey)
+		}
+		metric := derivedmetrics.DerivedMetric{}
+		metric.Name, _ = fields[metricNameKey].(string)
+		metric.Expression, _ = fields[expressionKey].(string)
+		metric.Unit, _ = fields[unitKey].(string)
+		cfg.Metrics = append(cfg.Metrics, metric)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", configKey, err)
+	}
+	return cfg, nil
+}
+
+// IsSet returns true if derived metrics are configured.
+func IsSet(conf *confmap.Conf) bool {
+	return conf != nil && conf.IsSet(configKey)
+}
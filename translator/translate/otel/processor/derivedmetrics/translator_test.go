@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package derivedmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/derivedmetrics"
+)
+
+func TestTranslator(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"metrics": map[string]any{
+			"derived": []any{
+				map[string]any{
+					"metric_name": "mem_used_percent_excluding_cache",
+					"expression":  "100 * (mem_used - mem_cached) / mem_total",
+					"unit":        "Percent",
+				},
+				map[string]any{
+					"metric_name": "procstat_cpu_usage_per_core",
+					"expression":  "procstat_cpu_usage / num_cpus",
+				},
+			},
+		},
+	})
+	assert.True(t, IsSet(conf))
+
+	tt := NewTranslatorWithName("host")
+	assert.Equal(t, "derivedmetrics/host", tt.ID().String())
+	got, err := tt.Translate(conf)
+	require.NoError(t, err)
+	assert.Equal(t, &derivedmetrics.Config{
+		Metrics: []derivedmetrics.DerivedMetric{
+			{Name: "mem_used_percent_excluding_cache", Expression: "100 * (mem_used - mem_cached) / mem_total", Unit: "Percent"},
+			{Name: "procstat_cpu_usage_per_core", Expression: "procstat_cpu_usage / num_cpus"},
+		},
+	}, got)
+}
+
+func TestTranslatorInvalidExpression(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"metrics": map[string]any{
+			"derived": []any{
+				map[string]any{"metric_name": "ratio", "expression": "(mem_used / mem_total"},
+			},
+		},
+	})
+	_, err := NewTranslatorWithName("host").Translate(conf)
+	assert.EqualError(t, err, `invalid metrics::derived: derived metric "ratio" has an invalid expression: missing ) at position 21`)
+}
+
+func TestTranslatorMissingKey(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{"metrics": map[string]any{}})
+	assert.False(t, IsSet(conf))
+	assert.False(t, IsSet(nil))
+	_, err := NewTranslatorWithName("host").Translate(conf)
+	assert.Error(t, err)
+}
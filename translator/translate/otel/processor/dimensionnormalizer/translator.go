@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimensionnormalizer
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/processor"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/dimensionnormalizer"
+	translatorcore "github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	logsutil "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	lowercaseKeysKey = "lowercase_keys"
+	synonymsKey      = "synonyms"
+	reportFilePrefix = "dimension_normalization_"
+	reportFileSuffix = ".json"
+)
+
+var configKey = common.ConfigKey(common.MetricsKey, "dimension_normalization")
+
+type translator struct {
+	name    string
+	factory processor.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+func NewTranslatorWithName(name string) common.Translator[component.Config] {
+	return &translator{name, dimensionnormalizer.NewFactory()}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates the normalization policy from the
+// metrics.dimension_normalization section. The report file is named after
+// the pipeline, so that the processors in different pipelines do not
+// overwrite each other. It is written next to the agent log, since the
+// log file state cleanup deletes the unknown files of the state folder.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if !IsSet(conf) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: configKey}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*dimensionnormalizer.Config)
+	cfg.LowercaseKeys, _ = common.GetBool(conf, common.ConfigKey(configKey, lowercaseKeysKey))
+	if synonyms, ok := conf.Get(common.ConfigKey(configKey, synonymsKey)).(map[string]any); ok {
+		cfg.Synonyms = make(map[string]string, len(synonyms))
+		for key, target := range synonyms {
+			cfg.Synonyms[key] = fmt.Sprintf("%v", target)
+		}
+	}
+	separator := "/"
+	if translatorcore.GetTargetPlatform() == config.OS_TYPE_WINDOWS {
+		separator = "\\"
+	}
+	cfg.ReportFile = logsutil.GetLogsFolder() + separator + reportFilePrefix + strings.ReplaceAll(t.name, "/", "_") + reportFileSuffix
+	return cfg, nil
+}
+
+// IsSet returns true if the normalization is enabled.
+func IsSet(conf *confmap.Conf) bool {
+	return conf != nil && conf.IsSet(configKey)
+}
@@ -0,0 +1,50 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimensionnormalizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/dimensionnormalizer"
+	translatorcore "github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+)
+
+func TestTranslator(t *testing.T) {
+	translatorcore.SetTargetPlatform(config.OS_TYPE_LINUX)
+	conf := confmap.NewFromStringMap(map[string]any{
+		"metrics": map[string]any{
+			"dimension_normalization": map[string]any{
+				"lowercase_keys": true,
+				"synonyms": map[string]any{
+					"hostname": "host",
+					"Instance": "InstanceId",
+				},
+			},
+		},
+	})
+	assert.True(t, IsSet(conf))
+
+	tt := NewTranslatorWithName("host/cloudwatch")
+	assert.Equal(t, "dimensionnormalizer/host/cloudwatch", tt.ID().String())
+	got, err := tt.Translate(conf)
+	require.NoError(t, err)
+	assert.Equal(t, &dimensionnormalizer.Config{
+		LowercaseKeys: true,
+		Synonyms:      map[string]string{"hostname": "host", "Instance": "InstanceId"},
+		ReportFile:    "/opt/aws/amazon-cloudwatch-agent/logs/dimension_normalization_host_cloudwatch.json",
+	}, got)
+}
+
+func TestTranslatorMissingKey(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{"metrics": map[string]any{}})
+	assert.False(t, IsSet(conf))
+	assert.False(t, IsSet(nil))
+	_, err := NewTranslatorWithName("prometheus/amp").Translate(conf)
+	assert.Error(t, err)
+}
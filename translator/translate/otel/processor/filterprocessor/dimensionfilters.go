@@ -0,0 +1,153 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package filterprocessor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/filterprocessor"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/processor"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	DimensionFiltersKey = "dimension_filters"
+
+	dimensionFiltersName = "dimension_filters"
+	actionKey            = "action"
+	dimensionKey         = "dimension"
+	valuesKey            = "values"
+	actionKeep           = "keep"
+	actionDrop           = "drop"
+)
+
+var metricsCollectedKey = common.ConfigKey(common.MetricsKey, common.MetricsCollectedKey)
+
+type dimensionFiltersTranslator struct {
+	name    string
+	plugins []string
+	factory processor.Factory
+}
+
+var _ common.Translator[component.Config] = (*dimensionFiltersTranslator)(nil)
+
+// NewDimensionFiltersTranslator creates the filter of the data points of the plugins by the
+// values of their dimensions. The name is the one of the pipeline.
+func NewDimensionFiltersTranslator(name string, plugins ...string) common.Translator[component.Config] {
+	return &dimensionFiltersTranslator{
+		name:    dimensionFiltersName + "/" + name,
+		plugins: plugins,
+		factory: filterprocessor.NewFactory(),
+	}
+}
+
+func (t *dimensionFiltersTranslator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates a data point condition for each of the dimension_filters
+// of the plugins. The conditions only match the metrics of their plugin, which
+// are prefixed with the plugin name. A drop filter drops the data points with
+// a value of the dimension matching one of the regular expressions, and a keep
+// filter drops the ones with a value matching none of them. The data points
+// without the dimension are not filtered.
+func (t *dimensionFiltersTranslator) Translate(conf *confmap.Conf) (component.Config, error) {
+	var conditions []string
+	for _, plugin := range t.plugins {
+		filters, ok := conf.Get(common.ConfigKey(metricsCollectedKey, plugin, DimensionFiltersKey)).([]any)
+		if !ok {
+			continue
+		}
+		for i, entry := range filters {
+			condition, err := dimensionFilterCondition(plugin, entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s[%d] of %s: %w", DimensionFiltersKey, i, plugin, err)
+			}
+			conditions = append(conditions, condition)
+		}
+	}
+	if len(conditions) == 0 {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.ConfigKey(metricsCollectedKey, "*", DimensionFiltersKey)}
+	}
+
+	cfg := t.factory.CreateDefaultConfig().(*filterprocessor.Config)
+	c := confmap.NewFromStringMap(map[string]any{
+		"error_mode": "ignore",
+		"metrics": map[string]any{
+			"datapoint": conditions,
+		},
+	})
+	if err := c.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal filter processor (%s): %w", t.ID(), err)
+	}
+	return cfg, nil
+}
+
+func dimensionFilterCondition(plugin string, entry any) (string, error) {
+	filter, ok := entry.(map[string]any)
+	if !ok {
+		return "", errors.New("not an object")
+	}
+	action, _ := filter[actionKey].(string)
+	if action != actionKeep && action != actionDrop {
+		return "", fmt.Errorf("%s must be %q or %q", actionKey, actionKeep, actionDrop)
+	}
+	dimension, _ := filter[dimensionKey].(string)
+	if dimension == "" {
+		return "", fmt.Errorf("missing %s", dimensionKey)
+	}
+	values, _ := filter[valuesKey].([]any)
+	if len(values) == 0 {
+		return "", fmt.Errorf("missing %s", valuesKey)
+	}
+	attribute := fmt.Sprintf("attributes[%s]", quote(dimension))
+	matches := make([]string, 0, len(values))
+	for _, value := range values {
+		pattern, _ := value.(string)
+		if _, err := regexp.Compile(pattern); err != nil {
+			return "", fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+		}
+		matches = append(matches, fmt.Sprintf("IsMatch(%s, %s)", attribute, quote(pattern)))
+	}
+	match := "(" + strings.Join(matches, " or ") + ")"
+	if action == actionKeep {
+		match = "not " + match
+	}
+	return fmt.Sprintf("IsMatch(metric.name, %s) and %s != nil and %s",
+		quote("^"+regexp.QuoteMeta(plugin)+"_"), attribute, match), nil
+}
+
+// quote returns the string literal of s in an OTTL statement.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// DimensionFiltersPlugins returns the sorted names of the plugins in metrics_collected
+// with dimension_filters.
+func DimensionFiltersPlugins(conf *confmap.Conf) []string {
+	if conf == nil {
+		return nil
+	}
+	collected, ok := conf.Get(metricsCollectedKey).(map[string]any)
+	if !ok {
+		return nil
+	}
+	var plugins []string
+	for plugin, value := range collected {
+		if pluginMap, ok := value.(map[string]any); ok {
+			if _, ok := pluginMap[DimensionFiltersKey]; ok {
+				plugins = append(plugins, plugin)
+			}
+		}
+	}
+	slices.Sort(plugins)
+	return plugins
+}
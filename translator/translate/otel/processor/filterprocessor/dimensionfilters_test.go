@@ -0,0 +1,120 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package filterprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/filterprocessor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor/processortest"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestDimensionFiltersTranslator(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"metrics": map[string]any{
+			"metrics_collected": map[string]any{
+				"cpu": map[string]any{},
+				"disk": map[string]any{
+					"dimension_filters": []any{
+						map[string]any{"action": "drop", "dimension": "device", "values": []any{"^loop", "^tmpfs$"}},
+					},
+				},
+				"net": map[string]any{
+					"dimension_filters": []any{
+						map[string]any{"action": "keep", "dimension": "interface", "values": []any{"^eth", "^ens"}},
+					},
+				},
+			},
+		},
+	})
+	assert.Equal(t, []string{"disk", "net"}, DimensionFiltersPlugins(conf))
+
+	tt := NewDimensionFiltersTranslator("host", "disk", "net")
+	assert.Equal(t, "filter/dimension_filters/host", tt.ID().String())
+	translated, err := tt.Translate(conf)
+	require.NoError(t, err)
+	cfg := translated.(*filterprocessor.Config)
+	assert.Equal(t, []string{
+		`IsMatch(metric.name, "^disk_") and attributes["device"] != nil and (IsMatch(attributes["device"], "^loop") or IsMatch(attributes["device"], "^tmpfs$"))`,
+		`IsMatch(metric.name, "^net_") and attributes["interface"] != nil and not (IsMatch(attributes["interface"], "^eth") or IsMatch(attributes["interface"], "^ens"))`,
+	}, cfg.Metrics.DataPointConditions)
+	require.NoError(t, cfg.Validate())
+
+	sink := new(consumertest.MetricsSink)
+	factory := filterprocessor.NewFactory()
+	p, err := factory.CreateMetricsProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	addGauge := func(name, key string, values ...string) {
+		m := metrics.AppendEmpty()
+		m.SetName(name)
+		dps := m.SetEmptyGauge().DataPoints()
+		for _, value := range values {
+			dp := dps.AppendEmpty()
+			if value != "" {
+				dp.Attributes().PutStr(key, value)
+			}
+		}
+	}
+	addGauge("disk_used_percent", "device", "nvme0n1p1", "loop0", "tmpfs", "")
+	addGauge("diskio_reads", "device", "loop0")
+	addGauge("net_bytes_sent", "interface", "eth0", "veth1a2b", "")
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+
+	got := map[string][]string{}
+	for _, md := range sink.AllMetrics() {
+		ms := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		for i := 0; i < ms.Len(); i++ {
+			dps := ms.At(i).Gauge().DataPoints()
+			for j := 0; j < dps.Len(); j++ {
+				var value string
+				for _, key := range []string{"device", "interface"} {
+					if v, ok := dps.At(j).Attributes().Get(key); ok {
+						value = v.Str()
+					}
+				}
+				got[ms.At(i).Name()] = append(got[ms.At(i).Name()], value)
+			}
+		}
+	}
+	assert.Equal(t, map[string][]string{
+		"disk_used_percent": {"nvme0n1p1", ""},
+		"diskio_reads":      {"loop0"},
+		"net_bytes_sent":    {"eth0", ""},
+	}, got)
+}
+
+func TestDimensionFiltersTranslatorInvalid(t *testing.T) {
+	testCases := map[string]map[string]any{
+		"Missing": {"cpu": map[string]any{}},
+		"Action": {"disk": map[string]any{"dimension_filters": []any{
+			map[string]any{"action": "exclude", "dimension": "device", "values": []any{"^loop"}},
+		}}},
+		"Regex": {"disk": map[string]any{"dimension_filters": []any{
+			map[string]any{"action": "drop", "dimension": "device", "values": []any{"(loop"}},
+		}}},
+	}
+	for name, collected := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(map[string]any{
+				"metrics": map[string]any{"metrics_collected": collected},
+			})
+			_, err := NewDimensionFiltersTranslator("host", "cpu", "disk").Translate(conf)
+			assert.Error(t, err)
+			if name == "Missing" {
+				assert.IsType(t, &common.MissingKeyError{}, err)
+			}
+		})
+	}
+}
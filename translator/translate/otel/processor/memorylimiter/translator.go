@@ -0,0 +1,53 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package memorylimiter
+
+import (
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/memorylimiterprocessor"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+var memoryLimiterKey = common.ConfigKey(common.AgentKey, agent.MemoryLimiterKey)
+
+type translator struct {
+	factory processor.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+// NewTranslator creates the memory limiter shared by the pipelines. The
+// processors with the same ID share the same limiter, so the memory of the
+// agent is checked once for all the pipelines.
+func NewTranslator() common.Translator[component.Config] {
+	return &translator{memorylimiterprocessor.NewFactory()}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewID(t.factory.Type())
+}
+
+// IsSet returns true if the memory limiter is configured in the agent section.
+func IsSet(conf *confmap.Conf) bool {
+	return conf != nil && conf.IsSet(memoryLimiterKey)
+}
+
+// Translate creates a memory limiter processor config with the limits as
+// percentages of the total memory, so that they follow the size of the host
+// or the memory limit of the container.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if !IsSet(conf) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: memoryLimiterKey}
+	}
+	limiter := agent.ParseMemoryLimiter(conf.Get(common.AgentKey))
+	cfg := t.factory.CreateDefaultConfig().(*memorylimiterprocessor.Config)
+	cfg.CheckInterval = limiter.CheckInterval
+	cfg.MemoryLimitPercentage = uint32(limiter.LimitPercentage)
+	cfg.MemorySpikePercentage = uint32(limiter.SpikeLimitPercentage)
+	return cfg, nil
+}
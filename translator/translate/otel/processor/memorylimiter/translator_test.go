@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package memorylimiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/processor/memorylimiterprocessor"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	tt := NewTranslator()
+	assert.EqualValues(t, "memory_limiter", tt.ID().String())
+	testCases := map[string]struct {
+		input   map[string]interface{}
+		want    *memorylimiterprocessor.Config
+		wantErr error
+	}{
+		"WithoutMemoryLimiter": {
+			input:   map[string]interface{}{"agent": map[string]interface{}{}},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: "agent::memory_limiter"},
+		},
+		"WithDefaults": {
+			input: map[string]interface{}{"agent": map[string]interface{}{"memory_limiter": map[string]interface{}{}}},
+			want: &memorylimiterprocessor.Config{
+				CheckInterval:         time.Second,
+				MemoryLimitPercentage: 80,
+				MemorySpikePercentage: 25,
+			},
+		},
+		"WithLimits": {
+			input: map[string]interface{}{"agent": map[string]interface{}{"memory_limiter": map[string]interface{}{
+				"limit_percentage":       float64(50),
+				"spike_limit_percentage": float64(10),
+				"check_interval":         float64(5),
+			}}},
+			want: &memorylimiterprocessor.Config{
+				CheckInterval:         5 * time.Second,
+				MemoryLimitPercentage: 50,
+				MemorySpikePercentage: 10,
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(testCase.input)
+			got, err := tt.Translate(conf)
+			assert.Equal(t, testCase.wantErr, err)
+			if testCase.want != nil {
+				require.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+				assert.NoError(t, got.(*memorylimiterprocessor.Config).Validate())
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metricsdecorator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/processor"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
+	translatorcontext "github.com/aws/amazon-cloudwatch-agent/translator/context"
+	metricsconfig "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/config"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/util"
+)
+
+const aggregationIntervalName = "aggregation_interval"
+
+type aggregationIntervalTranslator struct {
+	factory       processor.Factory
+	ignorePlugins collections.Set[string]
+}
+
+// NewAggregationIntervalTranslator creates the processor setting the
+// aggregation interval of the metrics with an aggregation_interval in their
+// measurement. The CloudWatch exporter aggregates the datapoints of each
+// metric over its interval before they are published.
+func NewAggregationIntervalTranslator(ignorePlugins ...string) Translator {
+	return &aggregationIntervalTranslator{
+		factory:       transformprocessor.NewFactory(),
+		ignorePlugins: collections.NewSet(ignorePlugins...),
+	}
+}
+
+func (t *aggregationIntervalTranslator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), aggregationIntervalName)
+}
+
+// Translate creates the datapoint statements setting the aggregation interval
+// attribute. The statements match the metric names before the decorator
+// renames them.
+func (t *aggregationIntervalTranslator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if conf == nil || !conf.IsSet(defaultConfigKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: defaultConfigKey}
+	}
+	var statements []string
+	for plugin, measurements := range t.getMeasurementsByPlugin(conf) {
+		standardizeNameFn := decorateMetricNameFn(translatorcontext.CurrentContext().Os(), metricsconfig.GetRealPluginName(plugin))
+		for _, m := range measurements {
+			statement, err := getAggregationIntervalStatement(m, standardizeNameFn)
+			if err != nil {
+				return nil, fmt.Errorf("unable to translate aggregation interval of %s: %w", plugin, err)
+			}
+			if statement != "" {
+				statements = append(statements, statement)
+			}
+		}
+	}
+	// sort the statements for a consistent configuration
+	sort.Strings(statements)
+	cfg := t.factory.CreateDefaultConfig().(*transformprocessor.Config)
+	c := confmap.NewFromStringMap(map[string]any{
+		"metric_statements": []ContextStatement{{Context: "datapoint", Statements: statements}},
+	})
+	if err := c.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal aggregation interval processor: %w", err)
+	}
+	return cfg, nil
+}
+
+func (t *aggregationIntervalTranslator) IsSet(conf *confmap.Conf) bool {
+	for _, measurements := range t.getMeasurementsByPlugin(conf) {
+		for _, m := range measurements {
+			if _, ok := m[common.AggregationIntervalKey]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getMeasurementsByPlugin returns the measurement objects of each plugin,
+// including each of the processes monitored by procstat.
+func (t *aggregationIntervalTranslator) getMeasurementsByPlugin(conf *confmap.Conf) map[string][]map[string]any {
+	plugins, ok := conf.Get(defaultConfigKey).(map[string]any)
+	if !ok {
+		return nil
+	}
+	result := make(map[string][]map[string]any)
+	for plugin, value := range plugins {
+		if t.ignorePlugins.Contains(plugin) {
+			continue
+		}
+		var pluginMaps []any
+		switch v := value.(type) {
+		case map[string]any:
+			pluginMaps = []any{v}
+		case []any:
+			pluginMaps = v
+		}
+		for _, pluginMap := range pluginMaps {
+			m, ok := pluginMap.(map[string]any)
+			if !ok {
+				continue
+			}
+			measurements, _ := m[common.MeasurementKey].([]any)
+			for _, measurement := range measurements {
+				if mm, ok := measurement.(map[string]any); ok {
+					result[plugin] = append(result[plugin], mm)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// getAggregationIntervalStatement returns an empty statement if the
+// measurement has no aggregation interval.
+func getAggregationIntervalStatement(m map[string]any, standardizeNameFn transformFn) (string, error) {
+	interval, ok := m[common.AggregationIntervalKey]
+	if !ok {
+		return "", nil
+	}
+	name, ok := m[common.NameKey].(string)
+	if !ok {
+		return "", errors.New("name field is missing for one of your metrics")
+	}
+	seconds, ok := interval.(float64)
+	if !ok || seconds < 1 || seconds != float64(int(seconds)) {
+		return "", fmt.Errorf("aggregation_interval (%v) of %s is not a positive number of seconds", interval, name)
+	}
+	metricName := standardizeNameFn(name)
+	if metricName == "" {
+		return "", fmt.Errorf("metric name (%q) is invalid for aggregation", name)
+	}
+	return fmt.Sprintf("set(attributes[\"%s\"], \"%ds\") where metric.name == \"%s\"", util.Aggregation_Interval_Tag_Key, int(seconds), metricName), nil
+}
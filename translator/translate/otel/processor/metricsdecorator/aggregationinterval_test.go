@@ -0,0 +1,90 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metricsdecorator
+
+import (
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	translatorconfig "github.com/aws/amazon-cloudwatch-agent/translator/config"
+	translatorcontext "github.com/aws/amazon-cloudwatch-agent/translator/context"
+)
+
+func TestAggregationIntervalTranslator(t *testing.T) {
+	translatorcontext.CurrentContext().SetOs(translatorconfig.OS_TYPE_LINUX)
+	testCases := map[string]struct {
+		input     map[string]any
+		wantIsSet bool
+		want      []string
+		wantErr   bool
+	}{
+		"WithoutAggregationInterval": {
+			input: map[string]any{
+				"metrics_collected": map[string]any{
+					"cpu": map[string]any{"measurement": []any{"usage_idle", map[string]any{"name": "usage_user"}}},
+				},
+			},
+		},
+		"WithAggregationInterval": {
+			input: map[string]any{
+				"metrics_collected": map[string]any{
+					"cpu": map[string]any{"measurement": []any{
+						"usage_idle",
+						map[string]any{"name": "usage_user", "aggregation_interval": float64(10)},
+					}},
+					"procstat": []any{
+						map[string]any{"exe": "java", "measurement": []any{
+							map[string]any{"name": "cpu_usage", "aggregation_interval": float64(300)},
+						}},
+					},
+					"jmx": map[string]any{"measurement": []any{
+						map[string]any{"name": "jvm.memory.heap.used", "aggregation_interval": float64(300)},
+					}},
+				},
+			},
+			wantIsSet: true,
+			want: []string{
+				`set(attributes["aws:AggregationInterval"], "10s") where metric.name == "cpu_usage_user"`,
+				`set(attributes["aws:AggregationInterval"], "300s") where metric.name == "procstat_cpu_usage"`,
+			},
+		},
+		"WithInvalidAggregationInterval": {
+			input: map[string]any{
+				"metrics_collected": map[string]any{
+					"mem": map[string]any{"measurement": []any{
+						map[string]any{"name": "used_percent", "aggregation_interval": float64(1.5)},
+					}},
+				},
+			},
+			wantIsSet: true,
+			wantErr:   true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tt := NewAggregationIntervalTranslator("jmx")
+			assert.Equal(t, "transform/aggregation_interval", tt.ID().String())
+			conf := confmap.NewFromStringMap(map[string]any{"metrics": testCase.input})
+			assert.Equal(t, testCase.wantIsSet, tt.IsSet(conf))
+			if !testCase.wantIsSet {
+				return
+			}
+			got, err := tt.Translate(conf)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			cfg, ok := got.(*transformprocessor.Config)
+			require.True(t, ok)
+			require.Len(t, cfg.MetricStatements, 1)
+			assert.Equal(t, "datapoint", string(cfg.MetricStatements[0].Context))
+			assert.Equal(t, testCase.want, cfg.MetricStatements[0].Statements)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metricsrouting
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/processor"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/metricsrouting"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	metricNamesKey = "metric_names"
+	dimensionsKey  = "dimensions"
+)
+
+var configKey = common.ConfigKey(common.MetricsKey, common.NamespaceRoutesKey)
+
+type translator struct {
+	name    string
+	route   int
+	factory processor.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+// NewTranslatorWithName creates the router keeping the data points of the
+// route, or the ones not matching any route if it is
+// metricsrouting.DefaultRoute.
+func NewTranslatorWithName(name string, route int) common.Translator[component.Config] {
+	return &translator{name, route, metricsrouting.NewFactory()}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates the router from the metrics.namespace_routes section.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if !IsSet(conf) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: configKey}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*metricsrouting.Config)
+	cfg.Route = t.route
+	for _, entry := range conf.Get(configKey).([]any) {
+		m, _ := entry.(map[string]any)
+		route := metricsrouting.Route{}
+		names, _ := m[metricNamesKey].([]any)
+		for _, name := range names {
+			if s, ok := name.(string); ok {
+				route.MetricNames = append(route.MetricNames, s)
+			}
+		}
+		if dimensions, ok := m[dimensionsKey].(map[string]any); ok {
+			route.Dimensions = make(map[string]string, len(dimensions))
+			for k, v := range dimensions {
+				route.Dimensions[k] = fmt.Sprint(v)
+			}
+		}
+		cfg.Routes = append(cfg.Routes, route)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", configKey, err)
+	}
+	return cfg, nil
+}
+
+// IsSet returns true if namespace routes are configured.
+func IsSet(conf *confmap.Conf) bool {
+	return RouteCount(conf) > 0
+}
+
+// RouteCount returns the number of namespace routes.
+func RouteCount(conf *confmap.Conf) int {
+	if conf == nil {
+		return 0
+	}
+	routes, _ := conf.Get(configKey).([]any)
+	return len(routes)
+}
+
+// Namespace returns the namespace the metrics of the route are published to.
+func Namespace(conf *confmap.Conf, route int) (string, bool) {
+	namespace, ok := common.GetIndexedMap(conf, configKey, route)[common.NamespaceKey].(string)
+	return namespace, ok
+}
@@ -0,0 +1,77 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metricsrouting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/metricsrouting"
+)
+
+func TestTranslator(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"metrics": map[string]any{
+			"namespace_routes": []any{
+				map[string]any{"namespace": "TeamA", "metric_names": []any{"mem_*", "swap_*"}},
+				map[string]any{"namespace": "TeamB", "dimensions": map[string]any{"team": "b"}},
+			},
+		},
+	})
+	assert.True(t, IsSet(conf))
+	assert.Equal(t, 2, RouteCount(conf))
+	namespace, ok := Namespace(conf, 1)
+	assert.True(t, ok)
+	assert.Equal(t, "TeamB", namespace)
+
+	routes := []metricsrouting.Route{
+		{MetricNames: []string{"mem_*", "swap_*"}},
+		{Dimensions: map[string]string{"team": "b"}},
+	}
+	testCases := map[string]struct {
+		route  int
+		wantID string
+	}{
+		"WithDefaultRoute": {
+			route:  metricsrouting.DefaultRoute,
+			wantID: "metricsrouting/host",
+		},
+		"WithRoute": {
+			route:  1,
+			wantID: "metricsrouting/host",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tt := NewTranslatorWithName("host", testCase.route)
+			assert.Equal(t, testCase.wantID, tt.ID().String())
+			got, err := tt.Translate(conf)
+			require.NoError(t, err)
+			assert.Equal(t, &metricsrouting.Config{Routes: routes, Route: testCase.route}, got)
+		})
+	}
+}
+
+func TestTranslatorInvalid(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"metrics": map[string]any{
+			"namespace_routes": []any{map[string]any{"namespace": "TeamA"}},
+		},
+	})
+	_, err := NewTranslatorWithName("host", 0).Translate(conf)
+	assert.EqualError(t, err, "invalid metrics::namespace_routes: routes[0]: metric_names or dimensions must be set")
+}
+
+func TestTranslatorMissingKey(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{"metrics": map[string]any{}})
+	assert.False(t, IsSet(conf))
+	assert.False(t, IsSet(nil))
+	_, ok := Namespace(conf, 0)
+	assert.False(t, ok)
+	_, err := NewTranslatorWithName("host", metricsrouting.DefaultRoute).Translate(conf)
+	assert.Error(t, err)
+}
@@ -38,6 +38,18 @@ var renameMapForDcgm = map[string]string{
 	"DCGM_FI_DEV_POWER_USAGE":     containerinsightscommon.GpuPowerDraw,
 }
 
+// labels of the MIG (Multi-Instance GPU) partitions reported by the DCGM exporter
+var renameLabelsForDcgm = []struct {
+	old, new string
+}{
+	{"GPU_I_ID", containerinsightscommon.GpuInstanceIdKey},
+	{"GPU_CI_ID", containerinsightscommon.GpuComputeInstanceIdKey},
+}
+
+// The MIG partitions do not report DCGM_FI_DEV_GPU_UTIL, so their utilization
+// is the ratio of time the graphics engine of the partition is active.
+const dcgmMigUtilization = "DCGM_FI_PROF_GR_ENGINE_ACTIVE"
+
 var renameMapForNeuronMonitor = map[string]string{
 	"execution_errors_total":                          containerinsightscommon.NeuronExecutionErrors,
 	"execution_status_total":                          containerinsightscommon.NeuronExecutionStatus,
@@ -101,6 +113,11 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 			//				"new_label": "Type",
 			//				"new_value": "ContainerGPU",
 			//			},
+			//			{
+			//				"action":    "update_label",
+			//				"label":     "GPU_I_ID",
+			//				"new_label": "GpuInstanceId",
+			//			},
 			//			<additional operations>...
 			//      ]
 			//	},
@@ -119,20 +136,21 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 					})
 				}
 				for _, t := range metricDuplicateTypes {
-					transformRules = append(transformRules, map[string]interface{}{
-						"include":  old,
-						"action":   "insert",
-						"new_name": containerinsightscommon.MetricName(t, new),
-						"operations": append([]map[string]interface{}{
-							{
-								"action":    "add_label",
-								"new_label": containerinsightscommon.MetricType,
-								"new_value": t,
-							},
-						}, operations...),
-					})
+					transformRules = append(transformRules, dcgmTransformRule(old, new, t, operations))
 				}
 			}
+			for _, t := range metricDuplicateTypes {
+				rule := dcgmTransformRule(dcgmMigUtilization, containerinsightscommon.GpuUtilization, t, []map[string]interface{}{
+					{
+						"action":             "experimental_scale_value",
+						"experimental_scale": 100,
+					},
+				})
+				rule["include"] = "^" + dcgmMigUtilization + "$"
+				rule["match_type"] = "regexp"
+				rule["experimental_match_labels"] = map[string]string{renameLabelsForDcgm[0].old: ".+"}
+				transformRules = append(transformRules, rule)
+			}
 
 			for oldName, newName := range renameMapForNeuronMonitor {
 				var operations []map[string]interface{}
@@ -198,3 +216,28 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 
 	return cfg, nil
 }
+
+// dcgmTransformRule inserts the DCGM metric under the name of the metric type,
+// with the MIG partitions of the GPU device as dimensions.
+func dcgmTransformRule(old, new, metricType string, operations []map[string]interface{}) map[string]interface{} {
+	ruleOperations := []map[string]interface{}{
+		{
+			"action":    "add_label",
+			"new_label": containerinsightscommon.MetricType,
+			"new_value": metricType,
+		},
+	}
+	for _, label := range renameLabelsForDcgm {
+		ruleOperations = append(ruleOperations, map[string]interface{}{
+			"action":    "update_label",
+			"label":     label.old,
+			"new_label": label.new,
+		})
+	}
+	return map[string]interface{}{
+		"include":    old,
+		"action":     "insert",
+		"new_name":   containerinsightscommon.MetricName(metricType, new),
+		"operations": append(ruleOperations, operations...),
+	}
+}
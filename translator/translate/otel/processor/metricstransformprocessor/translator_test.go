@@ -140,3 +140,39 @@ func TestAppSignalsRuntime(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, len(expectedCfg.Transforms), len(actualCfg.Transforms))
 }
+
+func TestContainerInsightsGpuMig(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]interface{}{
+		"logs": map[string]interface{}{
+			"metrics_collected": map[string]interface{}{
+				"kubernetes": map[string]interface{}{
+					"accelerated_compute_metrics": true,
+				},
+			},
+		},
+	})
+	translatedCfg, err := NewTranslatorWithName(common.PipelineNameContainerInsights).Translate(conf)
+	require.NoError(t, err)
+	cfg, ok := translatedCfg.(*metricstransformprocessor.Config)
+	require.True(t, ok)
+
+	var includes []string
+	for _, transform := range cfg.Transforms {
+		if transform.NewName != "container_gpu_utilization" {
+			continue
+		}
+		includes = append(includes, transform.MetricIncludeFilter.Include)
+		var labels []string
+		for _, operation := range transform.Operations {
+			if operation.Action == "update_label" {
+				labels = append(labels, operation.Label+"="+operation.NewLabel)
+			}
+		}
+		assert.Equal(t, []string{"GPU_I_ID=GpuInstanceId", "GPU_CI_ID=ComputeInstanceId"}, labels)
+		if transform.MetricIncludeFilter.Include == "^DCGM_FI_PROF_GR_ENGINE_ACTIVE$" {
+			assert.EqualValues(t, "regexp", transform.MetricIncludeFilter.MatchType)
+			assert.Equal(t, map[string]string{"GPU_I_ID": ".+"}, transform.MetricIncludeFilter.MatchLabels)
+		}
+	}
+	assert.ElementsMatch(t, []string{"DCGM_FI_DEV_GPU_UTIL", "^DCGM_FI_PROF_GR_ENGINE_ACTIVE$"}, includes)
+}
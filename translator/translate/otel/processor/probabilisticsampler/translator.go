@@ -0,0 +1,74 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package probabilisticsampler
+
+import (
+	"fmt"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/processor"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	SamplingPercentageKey = "sampling_percentage"
+)
+
+type translator struct {
+	name    string
+	index   int
+	factory processor.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+// NewTranslatorWithName creates a translator for the sampling of the traces
+// destination at the index of the traces destinations.
+func NewTranslatorWithName(name string, index int) common.Translator[component.Config] {
+	return &translator{name, index, probabilisticsamplerprocessor.NewFactory()}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates a probabilistic sampler processor config keeping the
+// sampling percentage of the traces destination. The sampling decision is
+// based on the trace ID, so the spans of a trace are kept or dropped together.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	destinationType, destination := common.GetTracesDestination(conf, t.index)
+	samplingPercentage, ok := getSamplingPercentage(destination)
+	if !ok {
+		return nil, &common.MissingKeyError{
+			ID:      t.ID(),
+			JsonKey: common.ConfigKey(fmt.Sprintf("%s[%d]", common.TracesDestinationsConfigKey, t.index), destinationType, SamplingPercentageKey),
+		}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*probabilisticsamplerprocessor.Config)
+	cfg.SamplingPercentage = float32(samplingPercentage)
+	return cfg, nil
+}
+
+// IsSampled returns true if the traces destination at the index only keeps
+// a percentage of the traces.
+func IsSampled(conf *confmap.Conf, index int) bool {
+	_, destination := common.GetTracesDestination(conf, index)
+	samplingPercentage, ok := getSamplingPercentage(destination)
+	return ok && samplingPercentage < 100
+}
+
+func getSamplingPercentage(destination map[string]any) (float64, bool) {
+	switch v := destination[SamplingPercentageKey].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
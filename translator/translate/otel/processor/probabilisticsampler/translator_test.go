@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package probabilisticsampler
+
+import (
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"traces": map[string]any{
+			"traces_destinations": []any{
+				map[string]any{"xray": map[string]any{}},
+				map[string]any{"otlp": map[string]any{"endpoint": "localhost:4317", "sampling_percentage": 12.5}},
+				map[string]any{"xray": map[string]any{"region": "us-west-2", "sampling_percentage": 100}},
+			},
+		},
+	})
+	testCases := map[string]struct {
+		index       int
+		want        float32
+		wantErr     error
+		wantSampled bool
+	}{
+		"WithoutSamplingPercentage": {
+			index:   0,
+			wantErr: &common.MissingKeyError{ID: NewTranslatorWithName("xray/0", 0).ID(), JsonKey: "traces::traces_destinations[0]::xray::sampling_percentage"},
+		},
+		"WithSamplingPercentage": {
+			index:       1,
+			want:        12.5,
+			wantSampled: true,
+		},
+		"WithAllTraces": {
+			index: 2,
+			want:  100,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tt := NewTranslatorWithName("xray/0", testCase.index)
+			require.EqualValues(t, "probabilistic_sampler/xray/0", tt.ID().String())
+			got, err := tt.Translate(conf)
+			assert.Equal(t, testCase.wantErr, err)
+			assert.Equal(t, testCase.wantSampled, IsSampled(conf, testCase.index))
+			if err == nil {
+				cfg, ok := got.(*probabilisticsamplerprocessor.Config)
+				require.True(t, ok)
+				assert.Equal(t, testCase.want, cfg.SamplingPercentage)
+				assert.NoError(t, cfg.Validate())
+			}
+		})
+	}
+}
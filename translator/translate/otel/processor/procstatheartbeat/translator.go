@@ -0,0 +1,111 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstatheartbeat
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/processor"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/procstatheartbeat"
+	translatorcore "github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	logsutil "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/procstat"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	stateFolder     = "procstat_heartbeat"
+	stateFileSuffix = ".json"
+	pidMeasurement  = "pid"
+)
+
+var configKey = common.ConfigKey(common.MetricsKey, common.MetricsCollectedKey, procstat.SectionKey)
+
+type translator struct {
+	name    string
+	factory processor.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+func NewTranslatorWithName(name string) common.Translator[component.Config] {
+	return &translator{name, procstatheartbeat.NewFactory()}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates a process config for each procstat entry that is
+// expected to be running. The state file is named after the pipeline, so
+// that the processors in different pipelines do not overwrite each other,
+// and kept in its own folder, which the log file state cleanup skips.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	cfg := t.factory.CreateDefaultConfig().(*procstatheartbeat.Config)
+	for _, processConfig := range processConfigs(conf) {
+		id := procstat.HeartbeatID(processConfig)
+		if id == "" {
+			continue
+		}
+		process := procstatheartbeat.ProcessConfig{
+			ID:      id,
+			DropPID: !hasPIDMeasurement(processConfig),
+		}
+		if count, ok := processConfig[procstat.FlapSuppressionCountKey].(float64); ok {
+			process.FlapSuppressionCount = int(count)
+		}
+		cfg.Processes = append(cfg.Processes, process)
+	}
+	separator := "/"
+	if translatorcore.GetTargetPlatform() == config.OS_TYPE_WINDOWS {
+		separator = "\\"
+	}
+	cfg.StateFile = logsutil.GetFileStateFolder() + separator + stateFolder + separator + strings.ReplaceAll(t.name, "/", "_") + stateFileSuffix
+	return cfg, nil
+}
+
+// IsSet returns true if any of the procstat entries is expected to be running.
+func IsSet(conf *confmap.Conf) bool {
+	for _, processConfig := range processConfigs(conf) {
+		if procstat.HeartbeatID(processConfig) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func processConfigs(conf *confmap.Conf) []map[string]any {
+	if conf == nil {
+		return nil
+	}
+	entries, _ := conf.Get(configKey).([]any)
+	var processConfigs []map[string]any
+	for _, entry := range entries {
+		if processConfig, ok := entry.(map[string]any); ok {
+			processConfigs = append(processConfigs, processConfig)
+		}
+	}
+	return processConfigs
+}
+
+// hasPIDMeasurement returns true if the pid is collected regardless of the
+// heartbeat.
+func hasPIDMeasurement(processConfig map[string]any) bool {
+	measurements, _ := processConfig[util.Measurement_Key].([]any)
+	for _, measurement := range measurements {
+		name, ok := measurement.(string)
+		if m, isMap := measurement.(map[string]any); isMap {
+			name, ok = m["name"].(string)
+		}
+		if ok && strings.TrimPrefix(strings.TrimSpace(name), procstat.SectionKey+"_") == pidMeasurement {
+			return true
+		}
+	}
+	return false
+}
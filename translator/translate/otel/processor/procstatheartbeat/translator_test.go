@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstatheartbeat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/hash"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/procstatheartbeat"
+	translatorcore "github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+)
+
+func TestTranslator(t *testing.T) {
+	translatorcore.SetTargetPlatform(config.OS_TYPE_LINUX)
+	conf := confmap.NewFromStringMap(map[string]any{
+		"metrics": map[string]any{
+			"metrics_collected": map[string]any{
+				"procstat": []any{
+					map[string]any{
+						"pattern":                "sshd",
+						"expect_running":         true,
+						"flap_suppression_count": float64(3),
+						"measurement":            []any{"cpu_usage"},
+					},
+					map[string]any{
+						"pid_file":       "/var/run/nginx.pid",
+						"exe":            "nginx",
+						"expect_running": true,
+						"measurement":    []any{map[string]any{"name": "procstat_pid"}},
+					},
+					map[string]any{
+						"exe":         "cloudwatch",
+						"measurement": []any{"pid"},
+					},
+				},
+			},
+		},
+	})
+	assert.True(t, IsSet(conf))
+
+	tt := NewTranslatorWithName("host/cloudwatch")
+	assert.Equal(t, "procstatheartbeat/host/cloudwatch", tt.ID().String())
+	got, err := tt.Translate(conf)
+	require.NoError(t, err)
+	assert.Equal(t, &procstatheartbeat.Config{
+		Processes: []procstatheartbeat.ProcessConfig{
+			{ID: hash.HashName("sshd"), FlapSuppressionCount: 3, DropPID: true},
+			{ID: hash.HashName("/var/run/nginx.pid")},
+		},
+		StateFile: "/opt/aws/amazon-cloudwatch-agent/logs/state/procstat_heartbeat/host_cloudwatch.json",
+	}, got)
+}
+
+func TestIsSet(t *testing.T) {
+	assert.False(t, IsSet(nil))
+	assert.False(t, IsSet(confmap.New()))
+	conf := confmap.NewFromStringMap(map[string]any{
+		"metrics": map[string]any{
+			"metrics_collected": map[string]any{
+				"procstat": []any{
+					map[string]any{"exe": "sshd", "measurement": []any{"pid"}},
+				},
+			},
+		},
+	})
+	assert.False(t, IsSet(conf))
+}
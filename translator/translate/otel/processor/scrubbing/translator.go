@@ -0,0 +1,183 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package scrubbing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/attributesprocessor"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/processor"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	httpURLsKey      = "http_urls"
+	sqlStatementsKey = "sql_statements"
+	keysKey          = "keys"
+	patternsKey      = "patterns"
+
+	// redacted replaces the scrubbed values.
+	redacted = "REDACTED"
+)
+
+var (
+	// ConfigKey is the section of the JSON config scrubbing the span attributes of the traces pipelines.
+	ConfigKey = common.ConfigKey(common.TracesKey, common.ScrubbingKey)
+
+	// urlKeys are the span attributes with a URL, from the X-Ray segments and the OTEL semantic conventions.
+	urlKeys = []string{"http.url", "url.full"}
+	// targetKeys are the span attributes with the path and query of a URL.
+	targetKeys = []string{"http.target"}
+	// queryKeys are the span attributes with only the query of a URL.
+	queryKeys = []string{"url.query"}
+	// sqlKeys are the span attributes with a SQL statement.
+	sqlKeys = []string{"db.statement", "db.query.text"}
+
+	ottlStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+type attributesTranslator struct {
+	name    string
+	factory processor.Factory
+}
+
+var _ common.Translator[component.Config] = (*attributesTranslator)(nil)
+
+// NewAttributesTranslatorWithName redacts the values of the span attributes
+// listed in traces.scrubbing.keys.
+func NewAttributesTranslatorWithName(name string) common.Translator[component.Config] {
+	return &attributesTranslator{name, attributesprocessor.NewFactory()}
+}
+
+func (t *attributesTranslator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+func (t *attributesTranslator) Translate(conf *confmap.Conf) (component.Config, error) {
+	keys := common.GetArray[string](conf, common.ConfigKey(ConfigKey, keysKey))
+	if len(keys) == 0 {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.ConfigKey(ConfigKey, keysKey)}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*attributesprocessor.Config)
+	actions := make([]any, 0, len(keys))
+	for _, key := range keys {
+		actions = append(actions, map[string]any{
+			"key":    key,
+			"action": "update",
+			"value":  redacted,
+		})
+	}
+	c := confmap.NewFromStringMap(map[string]any{"actions": actions})
+	if err := c.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal attributes processor (%s): %w", t.ID(), err)
+	}
+	return cfg, nil
+}
+
+type transformTranslator struct {
+	name    string
+	factory processor.Factory
+}
+
+var _ common.Translator[component.Config] = (*transformTranslator)(nil)
+
+// NewTransformTranslatorWithName redacts the parts of the span attributes
+// matching the patterns of traces.scrubbing, e.g. the query strings of the
+// URLs and the literals of the SQL statements.
+func NewTransformTranslatorWithName(name string) common.Translator[component.Config] {
+	return &transformTranslator{name, transformprocessor.NewFactory()}
+}
+
+func (t *transformTranslator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+func (t *transformTranslator) Translate(conf *confmap.Conf) (component.Config, error) {
+	statements, err := transformStatements(conf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to translate transform processor (%s): %w", t.ID(), err)
+	}
+	if len(statements) == 0 {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: ConfigKey}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*transformprocessor.Config)
+	c := confmap.NewFromStringMap(map[string]any{
+		"trace_statements": []any{
+			map[string]any{
+				"context":    "span",
+				"statements": statements,
+			},
+		},
+	})
+	if err = c.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal transform processor (%s): %w", t.ID(), err)
+	}
+	return cfg, nil
+}
+
+func transformStatements(conf *confmap.Conf) ([]any, error) {
+	var statements []any
+	if enabled, _ := common.GetBool(conf, common.ConfigKey(ConfigKey, httpURLsKey)); enabled {
+		for _, key := range urlKeys {
+			// the credentials and the query string
+			statements = append(statements,
+				replacePattern(key, "://[^/@]+@", "://"+redacted+"@"),
+				replacePattern(key, `[?].*`, "?"+redacted),
+			)
+		}
+		for _, key := range targetKeys {
+			statements = append(statements, replacePattern(key, `[?].*`, "?"+redacted))
+		}
+		for _, key := range queryKeys {
+			statements = append(statements, fmt.Sprintf(`set(attributes["%s"], "%s") where attributes["%s"] != nil`, key, redacted, key))
+		}
+	}
+	if enabled, _ := common.GetBool(conf, common.ConfigKey(ConfigKey, sqlStatementsKey)); enabled {
+		for _, key := range sqlKeys {
+			// the string and the number literals
+			statements = append(statements,
+				replacePattern(key, `'(?:[^']|'')*'`, "?"),
+				replacePattern(key, `\b\d+(?:\.\d+)?\b`, "?"),
+			)
+		}
+	}
+	for _, pattern := range common.GetArray[string](conf, common.ConfigKey(ConfigKey, patternsKey)) {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid scrubbing pattern %q: %w", pattern, err)
+		}
+		statements = append(statements, fmt.Sprintf(`replace_all_patterns(attributes, "value", "%s", "%s")`, ottlStringEscaper.Replace(pattern), redacted))
+	}
+	return statements, nil
+}
+
+func replacePattern(key, pattern, replacement string) string {
+	return fmt.Sprintf(`replace_pattern(attributes["%s"], "%s", "%s")`, key, ottlStringEscaper.Replace(pattern), replacement)
+}
+
+// HasKeys returns true if the values of span attributes are redacted by key.
+func HasKeys(conf *confmap.Conf) bool {
+	return len(Keys(conf)) > 0
+}
+
+// Keys returns the span attributes whose values are redacted.
+func Keys(conf *confmap.Conf) []string {
+	return common.GetArray[string](conf, common.ConfigKey(ConfigKey, keysKey))
+}
+
+// HasPatterns returns true if parts of the span attributes are redacted by
+// pattern.
+func HasPatterns(conf *confmap.Conf) bool {
+	for _, key := range []string{httpURLsKey, sqlStatementsKey} {
+		if enabled, _ := common.GetBool(conf, common.ConfigKey(ConfigKey, key)); enabled {
+			return true
+		}
+	}
+	return len(common.GetArray[string](conf, common.ConfigKey(ConfigKey, patternsKey))) > 0
+}
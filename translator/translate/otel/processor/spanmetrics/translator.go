@@ -0,0 +1,58 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package spanmetrics
+
+import (
+	"fmt"
+	"slices"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/processor"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/spanmetrics"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/scrubbing"
+)
+
+const (
+	dimensionsKey       = "dimensions"
+	histogramBucketsKey = "histogram_buckets"
+)
+
+type translator struct {
+	name    string
+	factory processor.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+func NewTranslatorWithName(name string) common.Translator[component.Config] {
+	return &translator{name, spanmetrics.NewFactory()}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates a span metrics processor config if the span metrics
+// section is present. The processor comes before the scrubbing of the span
+// attributes, so the scrubbed attributes cannot be dimensions.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if conf == nil || !conf.IsSet(common.SpanMetricsConfigKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.SpanMetricsConfigKey}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*spanmetrics.Config)
+	cfg.Dimensions = common.GetArray[string](conf, common.ConfigKey(common.SpanMetricsConfigKey, dimensionsKey))
+	scrubbed := scrubbing.Keys(conf)
+	for _, dimension := range cfg.Dimensions {
+		if slices.Contains(scrubbed, dimension) {
+			return nil, fmt.Errorf("span metrics dimension %q is scrubbed", dimension)
+		}
+	}
+	if buckets := common.GetArray[float64](conf, common.ConfigKey(common.SpanMetricsConfigKey, histogramBucketsKey)); len(buckets) > 0 {
+		cfg.HistogramBuckets = buckets
+	}
+	return cfg, nil
+}
@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package spanmetrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/spanmetrics"
+	spanmetricsprocessor "github.com/aws/amazon-cloudwatch-agent/plugins/processors/spanmetrics"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	tt := NewTranslatorWithName("xray")
+	assert.EqualValues(t, "spanmetrics/xray", tt.ID().String())
+	testCases := map[string]struct {
+		input   map[string]any
+		want    *spanmetricsprocessor.Config
+		wantErr error
+	}{
+		"WithoutSpanMetrics": {
+			input:   map[string]any{"traces": map[string]any{}},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: common.SpanMetricsConfigKey},
+		},
+		"WithDefaults": {
+			input: map[string]any{"traces": map[string]any{"span_metrics": map[string]any{}}},
+			want:  &spanmetricsprocessor.Config{HistogramBuckets: spanmetrics.DefaultBuckets},
+		},
+		"WithDimensionsAndBuckets": {
+			input: map[string]any{"traces": map[string]any{"span_metrics": map[string]any{
+				"dimensions":        []any{"http.method", "http.status_code"},
+				"histogram_buckets": []any{float64(5), float64(50), float64(500)},
+			}}},
+			want: &spanmetricsprocessor.Config{
+				Dimensions:       []string{"http.method", "http.status_code"},
+				HistogramBuckets: []float64{5, 50, 500},
+			},
+		},
+		"WithScrubbedDimension": {
+			input: map[string]any{"traces": map[string]any{
+				"span_metrics": map[string]any{"dimensions": []any{"user.id"}},
+				"scrubbing":    map[string]any{"keys": []any{"user.id"}},
+			}},
+			wantErr: errors.New(`span metrics dimension "user.id" is scrubbed`),
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.Translate(confmap.NewFromStringMap(testCase.input))
+			assert.Equal(t, testCase.wantErr, err)
+			if testCase.want != nil {
+				require.NotNil(t, got)
+				assert.Equal(t, testCase.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package tailsampling
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/processor"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	decisionWaitKey       = "decision_wait"
+	numTracesKey          = "num_traces"
+	policiesKey           = "policies"
+	errorsKey             = "errors"
+	latencyThresholdMsKey = "latency_threshold_ms"
+	spansPerSecondKey     = "spans_per_second"
+)
+
+var (
+	// ConfigKey is the section of the JSON config enabling the tail sampling of the traces pipelines.
+	ConfigKey         = common.ConfigKey(common.TracesKey, common.TailSamplingKey)
+	policiesConfigKey = common.ConfigKey(ConfigKey, policiesKey)
+
+	errNoPolicy = errors.New("at least one tail sampling policy must be enabled")
+)
+
+type translator struct {
+	name    string
+	factory processor.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+func NewTranslatorWithName(name string) common.Translator[component.Config] {
+	return &translator{name, tailsamplingprocessor.NewFactory()}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates a tail sampling processor config if the tail sampling
+// section is present. A trace is sampled if any of the policies samples it,
+// e.g. the traces with an error are kept in addition to the rate limited ones.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if conf == nil || !conf.IsSet(ConfigKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: ConfigKey}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*tailsamplingprocessor.Config)
+	if decisionWait, ok := common.GetDuration(conf, common.ConfigKey(ConfigKey, decisionWaitKey)); ok {
+		cfg.DecisionWait = decisionWait
+	}
+	if numTraces, ok := common.GetNumber(conf, common.ConfigKey(ConfigKey, numTracesKey)); ok && numTraces > 0 {
+		cfg.NumTraces = uint64(numTraces)
+	}
+
+	var policies []any
+	if enabled, _ := common.GetBool(conf, common.ConfigKey(policiesConfigKey, errorsKey)); enabled {
+		policies = append(policies, map[string]any{
+			"name":        errorsKey,
+			"type":        tailsamplingprocessor.StatusCode,
+			"status_code": map[string]any{"status_codes": []string{"ERROR"}},
+		})
+	}
+	if threshold, ok := common.GetNumber(conf, common.ConfigKey(policiesConfigKey, latencyThresholdMsKey)); ok && threshold > 0 {
+		policies = append(policies, map[string]any{
+			"name":    "latency",
+			"type":    tailsamplingprocessor.Latency,
+			"latency": map[string]any{"threshold_ms": int64(threshold)},
+		})
+	}
+	if spansPerSecond, ok := common.GetNumber(conf, common.ConfigKey(policiesConfigKey, spansPerSecondKey)); ok && spansPerSecond > 0 {
+		policies = append(policies, map[string]any{
+			"name":          "rate_limiting",
+			"type":          tailsamplingprocessor.RateLimiting,
+			"rate_limiting": map[string]any{"spans_per_second": int64(spansPerSecond)},
+		})
+	}
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("unable to translate tail sampling processor (%s): %w", t.ID(), errNoPolicy)
+	}
+
+	c := confmap.NewFromStringMap(map[string]any{"policies": policies})
+	if err := c.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal tail sampling processor (%s): %w", t.ID(), err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,116 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package tailsampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/mapstructure"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	tt := NewTranslatorWithName("xray")
+	require.EqualValues(t, "tail_sampling/xray", tt.ID().String())
+
+	type wantPolicy struct {
+		name       string
+		policyType tailsamplingprocessor.PolicyType
+	}
+	testCases := map[string]struct {
+		input            map[string]any
+		wantDecisionWait time.Duration
+		wantNumTraces    uint64
+		wantPolicies     []wantPolicy
+		wantErr          error
+	}{
+		"WithoutTailSampling": {
+			input:   map[string]any{"traces": map[string]any{}},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: ConfigKey},
+		},
+		"WithoutPolicies": {
+			input: map[string]any{"traces": map[string]any{"tail_sampling": map[string]any{
+				"policies": map[string]any{"errors": false},
+			}}},
+			wantErr: errNoPolicy,
+		},
+		"WithErrors": {
+			input: map[string]any{"traces": map[string]any{"tail_sampling": map[string]any{
+				"policies": map[string]any{"errors": true},
+			}}},
+			wantDecisionWait: 30 * time.Second,
+			wantNumTraces:    50000,
+			wantPolicies:     []wantPolicy{{"errors", tailsamplingprocessor.StatusCode}},
+		},
+		"WithAllPolicies": {
+			input: map[string]any{"traces": map[string]any{"tail_sampling": map[string]any{
+				"decision_wait": 10,
+				"num_traces":    1000,
+				"policies": map[string]any{
+					"errors":               true,
+					"latency_threshold_ms": 5000,
+					"spans_per_second":     100,
+				},
+			}}},
+			wantDecisionWait: 10 * time.Second,
+			wantNumTraces:    1000,
+			wantPolicies: []wantPolicy{
+				{"errors", tailsamplingprocessor.StatusCode},
+				{"latency", tailsamplingprocessor.Latency},
+				{"rate_limiting", tailsamplingprocessor.RateLimiting},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.Translate(confmap.NewFromStringMap(testCase.input))
+			if testCase.wantErr != nil {
+				assert.ErrorContains(t, err, testCase.wantErr.Error())
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			gotCfg, ok := got.(*tailsamplingprocessor.Config)
+			require.True(t, ok)
+			assert.Equal(t, testCase.wantDecisionWait, gotCfg.DecisionWait)
+			assert.Equal(t, testCase.wantNumTraces, gotCfg.NumTraces)
+			require.Len(t, gotCfg.PolicyCfgs, len(testCase.wantPolicies))
+			for i, want := range testCase.wantPolicies {
+				assert.Equal(t, want.name, gotCfg.PolicyCfgs[i].Name)
+				assert.Equal(t, want.policyType, gotCfg.PolicyCfgs[i].Type)
+			}
+		})
+	}
+}
+
+func TestTranslatorPolicySettings(t *testing.T) {
+	got, err := NewTranslatorWithName("xray").Translate(confmap.NewFromStringMap(map[string]any{
+		"traces": map[string]any{"tail_sampling": map[string]any{
+			"policies": map[string]any{
+				"errors":               true,
+				"latency_threshold_ms": 5000,
+				"spans_per_second":     100,
+			},
+		}},
+	}))
+	require.NoError(t, err)
+	policies := got.(*tailsamplingprocessor.Config).PolicyCfgs
+	require.Len(t, policies, 3)
+	assert.Equal(t, []string{"ERROR"}, policies[0].StatusCodeCfg.StatusCodes)
+	assert.Equal(t, int64(5000), policies[1].LatencyCfg.ThresholdMs)
+	assert.Equal(t, int64(100), policies[2].RateLimitingCfg.SpansPerSecond)
+
+	// the collector loads the marshaled config
+	marshaled, err := mapstructure.Marshal(got)
+	require.NoError(t, err)
+	loaded := tailsamplingprocessor.NewFactory().CreateDefaultConfig().(*tailsamplingprocessor.Config)
+	require.NoError(t, confmap.NewFromStringMap(marshaled).Unmarshal(loaded))
+	assert.Equal(t, got, loaded)
+}
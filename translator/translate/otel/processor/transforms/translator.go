@@ -0,0 +1,203 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package transforms
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/processor"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	// TransformsKey is the section of the OTTL statements in the metrics, logs
+	// and traces sections.
+	TransformsKey = "transforms"
+
+	contextKey    = "context"
+	statementsKey = "statements"
+	conditionsKey = "conditions"
+
+	// errorModeIgnore skips the statements failing on a record instead of
+	// dropping the whole batch.
+	errorModeIgnore = "ignore"
+)
+
+// signal is how the transforms of a data type are configured.
+type signal struct {
+	sectionKey string
+	// statementsKey is the key of the statements in the transform processor.
+	statementsKey string
+	// contexts are the OTTL contexts allowed for the data type.
+	contexts []string
+}
+
+var signals = map[component.DataType]signal{
+	component.DataTypeMetrics: {
+		sectionKey:    common.MetricsKey,
+		statementsKey: "metric_statements",
+		contexts:      []string{"resource", "scope", "metric", "datapoint"},
+	},
+	component.DataTypeLogs: {
+		sectionKey:    common.LogsKey,
+		statementsKey: "log_statements",
+		contexts:      []string{"resource", "scope", "log"},
+	},
+	component.DataTypeTraces: {
+		sectionKey:    common.TracesKey,
+		statementsKey: "trace_statements",
+		contexts:      []string{"resource", "scope", "span", "spanevent"},
+	},
+}
+
+type translator struct {
+	name     string
+	dataType component.DataType
+	factory  processor.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+// NewTranslatorWithName applies the OTTL statements of the transforms section
+// of the data type, e.g. metrics.transforms, in the pipeline.
+func NewTranslatorWithName(name string, dataType component.DataType) common.Translator[component.Config] {
+	return &translator{name: name, dataType: dataType, factory: transformprocessor.NewFactory()}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), TransformsKey+"/"+t.name)
+}
+
+// Translate compiles the transforms to the transform processor. Each statement
+// is parsed here, so that the OTTL syntax errors are reported with their place
+// in the JSON config instead of when the agent starts.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	s, ok := signals[t.dataType]
+	if !ok {
+		return nil, fmt.Errorf("transforms are not supported for %s", t.dataType)
+	}
+	configKey := ConfigKey(t.dataType)
+	if conf == nil || !conf.IsSet(configKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: configKey}
+	}
+	entries, ok := conf.Get(configKey).([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list", configKey)
+	}
+	contextStatements := make([]any, 0, len(entries))
+	for i, entry := range entries {
+		path := fmt.Sprintf("%s[%d]", configKey, i)
+		fields, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an object", path)
+		}
+		cs, err := t.translateEntry(s, path, fields)
+		if err != nil {
+			return nil, err
+		}
+		contextStatements = append(contextStatements, cs)
+	}
+	cfg, err := t.newConfig(s, contextStatements...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal transform processor (%s): %w", t.ID(), err)
+	}
+	return cfg, nil
+}
+
+func (t *translator) translateEntry(s signal, path string, fields map[string]any) (map[string]any, error) {
+	context, _ := fields[contextKey].(string)
+	if !slices.Contains(s.contexts, context) {
+		return nil, fmt.Errorf("%s%s%s must be one of %s, got %q", path, confmap.KeyDelimiter, contextKey, strings.Join(s.contexts, ", "), context)
+	}
+	statements, err := stringList(fields, path, statementsKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("%s%s%s must not be empty", path, confmap.KeyDelimiter, statementsKey)
+	}
+	conditions, err := stringList(fields, path, conditionsKey)
+	if err != nil {
+		return nil, err
+	}
+	cs := map[string]any{
+		contextKey:    context,
+		statementsKey: statements,
+	}
+	if len(conditions) > 0 {
+		cs[conditionsKey] = conditions
+	}
+	if err = t.validate(s, cs); err == nil {
+		return cs, nil
+	}
+	// parse the statements one at a time to point at the invalid one
+	for i, statement := range statements {
+		if err = t.validate(s, map[string]any{contextKey: context, statementsKey: []any{statement}}); err != nil {
+			return nil, fmt.Errorf("invalid OTTL in %s%s%s[%d]: %w", path, confmap.KeyDelimiter, statementsKey, i, err)
+		}
+	}
+	for i, condition := range conditions {
+		// a statement without side effect carries the condition
+		if err = t.validate(s, map[string]any{contextKey: context, statementsKey: []any{fmt.Sprintf("set(cache[\"condition\"], true) where %s", condition)}}); err != nil {
+			return nil, fmt.Errorf("invalid OTTL in %s%s%s[%d]: %w", path, confmap.KeyDelimiter, conditionsKey, i, err)
+		}
+	}
+	return nil, fmt.Errorf("invalid OTTL in %s: %w", path, err)
+}
+
+func (t *translator) validate(s signal, contextStatements map[string]any) error {
+	cfg, err := t.newConfig(s, contextStatements)
+	if err != nil {
+		return err
+	}
+	return cfg.Validate()
+}
+
+func (t *translator) newConfig(s signal, contextStatements ...any) (*transformprocessor.Config, error) {
+	cfg := t.factory.CreateDefaultConfig().(*transformprocessor.Config)
+	c := confmap.NewFromStringMap(map[string]any{
+		"error_mode":    errorModeIgnore,
+		s.statementsKey: contextStatements,
+	})
+	if err := c.Unmarshal(&cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func stringList(fields map[string]any, path, key string) ([]any, error) {
+	value, ok := fields[key]
+	if !ok {
+		return nil, nil
+	}
+	list, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s%s%s must be a list of strings", path, confmap.KeyDelimiter, key)
+	}
+	for _, item := range list {
+		if _, ok = item.(string); !ok {
+			return nil, fmt.Errorf("%s%s%s must be a list of strings", path, confmap.KeyDelimiter, key)
+		}
+	}
+	return list, nil
+}
+
+// ConfigKey is the transforms section of the data type, e.g. metrics::transforms.
+func ConfigKey(dataType component.DataType) string {
+	return common.ConfigKey(signals[dataType].sectionKey, TransformsKey)
+}
+
+// IsSet returns true if transforms are configured for the data type.
+func IsSet(conf *confmap.Conf, dataType component.DataType) bool {
+	if _, ok := signals[dataType]; !ok || conf == nil {
+		return false
+	}
+	return conf.IsSet(ConfigKey(dataType))
+}
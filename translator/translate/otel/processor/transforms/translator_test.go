@@ -0,0 +1,178 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package transforms
+
+import (
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func TestTranslator(t *testing.T) {
+	testCases := map[string]struct {
+		dataType component.DataType
+		input    map[string]any
+		want     map[string]any
+	}{
+		"WithMetrics": {
+			dataType: component.DataTypeMetrics,
+			input: map[string]any{
+				"metrics": map[string]any{
+					"transforms": []any{
+						map[string]any{
+							"context":    "datapoint",
+							"statements": []any{`set(attributes["env"], "prod")`, `delete_key(attributes, "request_id")`},
+							"conditions": []any{`metric.name == "requests"`},
+						},
+						map[string]any{
+							"context":    "resource",
+							"statements": []any{`set(attributes["team"], "payments")`},
+						},
+					},
+				},
+			},
+			want: map[string]any{
+				"error_mode": "ignore",
+				"metric_statements": []any{
+					map[string]any{
+						"context":    "datapoint",
+						"statements": []any{`set(attributes["env"], "prod")`, `delete_key(attributes, "request_id")`},
+						"conditions": []any{`metric.name == "requests"`},
+					},
+					map[string]any{
+						"context":    "resource",
+						"statements": []any{`set(attributes["team"], "payments")`},
+					},
+				},
+			},
+		},
+		"WithLogs": {
+			dataType: component.DataTypeLogs,
+			input: map[string]any{
+				"logs": map[string]any{
+					"transforms": []any{
+						map[string]any{
+							"context":    "log",
+							"statements": []any{`merge_maps(attributes, ParseJSON(body), "upsert") where IsMatch(body, "^\\{")`},
+						},
+					},
+				},
+			},
+			want: map[string]any{
+				"error_mode": "ignore",
+				"log_statements": []any{
+					map[string]any{
+						"context":    "log",
+						"statements": []any{`merge_maps(attributes, ParseJSON(body), "upsert") where IsMatch(body, "^\\{")`},
+					},
+				},
+			},
+		},
+		"WithTraces": {
+			dataType: component.DataTypeTraces,
+			input: map[string]any{
+				"traces": map[string]any{
+					"transforms": []any{
+						map[string]any{
+							"context":    "span",
+							"statements": []any{`set(attributes["http.route"], attributes["http.target"]) where attributes["http.route"] == nil`},
+						},
+					},
+				},
+			},
+			want: map[string]any{
+				"error_mode": "ignore",
+				"trace_statements": []any{
+					map[string]any{
+						"context":    "span",
+						"statements": []any{`set(attributes["http.route"], attributes["http.target"]) where attributes["http.route"] == nil`},
+					},
+				},
+			},
+		},
+	}
+	factory := transformprocessor.NewFactory()
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(testCase.input)
+			assert.True(t, IsSet(conf, testCase.dataType))
+			tt := NewTranslatorWithName("host", testCase.dataType)
+			assert.Equal(t, "transform/transforms/host", tt.ID().String())
+			got, err := tt.Translate(conf)
+			require.NoError(t, err)
+			want := factory.CreateDefaultConfig().(*transformprocessor.Config)
+			require.NoError(t, confmap.NewFromStringMap(testCase.want).Unmarshal(&want))
+			assert.Equal(t, want, got)
+			assert.NoError(t, component.ValidateConfig(got))
+		})
+	}
+}
+
+func TestTranslatorErrors(t *testing.T) {
+	testCases := map[string]struct {
+		dataType   component.DataType
+		transforms []any
+		wantErr    string
+	}{
+		"WithContextOfOtherSignal": {
+			dataType:   component.DataTypeLogs,
+			transforms: []any{map[string]any{"context": "datapoint", "statements": []any{`set(attributes["a"], "b")`}}},
+			wantErr:    `logs::transforms[0]::context must be one of resource, scope, log, got "datapoint"`,
+		},
+		"WithoutStatements": {
+			dataType:   component.DataTypeMetrics,
+			transforms: []any{map[string]any{"context": "datapoint"}},
+			wantErr:    "metrics::transforms[0]::statements must not be empty",
+		},
+		"WithNonStringStatement": {
+			dataType:   component.DataTypeMetrics,
+			transforms: []any{map[string]any{"context": "datapoint", "statements": []any{1}}},
+			wantErr:    "metrics::transforms[0]::statements must be a list of strings",
+		},
+		"WithSyntaxError": {
+			dataType: component.DataTypeMetrics,
+			transforms: []any{
+				map[string]any{"context": "datapoint", "statements": []any{`set(attributes["a"], "b")`}},
+				map[string]any{"context": "datapoint", "statements": []any{`set(attributes["a"], "b")`, `set(attributes["a"], "b"`}},
+			},
+			wantErr: `invalid OTTL in metrics::transforms[1]::statements[1]: unable to parse OTTL statement "set(attributes[\"a\"], \"b\"": statement has invalid syntax`,
+		},
+		"WithUnknownFunction": {
+			dataType:   component.DataTypeTraces,
+			transforms: []any{map[string]any{"context": "span", "statements": []any{`rename(attributes["a"], "b")`}}},
+			wantErr:    `invalid OTTL in traces::transforms[0]::statements[0]: unable to parse OTTL statement "rename(attributes[\"a\"], \"b\")": undefined function "rename"`,
+		},
+		"WithInvalidCondition": {
+			dataType: component.DataTypeMetrics,
+			transforms: []any{map[string]any{
+				"context":    "metric",
+				"statements": []any{`set(description, "requests")`},
+				"conditions": []any{`name = "requests"`},
+			}},
+			wantErr: `invalid OTTL in metrics::transforms[0]::conditions[0]: `,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(map[string]any{
+				signals[testCase.dataType].sectionKey: map[string]any{"transforms": testCase.transforms},
+			})
+			_, err := NewTranslatorWithName("host", testCase.dataType).Translate(conf)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), testCase.wantErr)
+		})
+	}
+}
+
+func TestTranslatorMissingKey(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{"metrics": map[string]any{}})
+	assert.False(t, IsSet(conf, component.DataTypeMetrics))
+	assert.False(t, IsSet(nil, component.DataTypeMetrics))
+	_, err := NewTranslatorWithName("host", component.DataTypeMetrics).Translate(conf)
+	assert.Error(t, err)
+}
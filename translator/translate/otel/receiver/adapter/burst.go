@@ -0,0 +1,72 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package adapter
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/receiver/adapter/burst"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	burstCaptureKey    = "burst_capture"
+	metricKeyName      = "metric"
+	thresholdKey       = "threshold"
+	durationKey        = "duration"
+	dimensionsKey      = "dimensions"
+	captureKey         = "capture"
+	captureDurationKey = "capture_duration"
+	resolutionKey      = "resolution"
+)
+
+var (
+	burstCaptureSectionKey = common.ConfigKey(common.MetricsKey, burstCaptureKey)
+)
+
+// getBurstRules returns the burst capture rules the input triggers or is
+// captured by. The input triggers a rule if the rule's metric is one of its
+// measurements, e.g. cpu_usage_active for cpu.
+func getBurstRules(conf *confmap.Conf, input string) []burst.Rule {
+	var rules []burst.Rule
+	for index, entry := range common.GetArray[map[string]any](conf, burstCaptureSectionKey) {
+		rule := toBurstRule(confmap.NewFromStringMap(entry), index)
+		if rule.Captures(input) || strings.HasPrefix(rule.Metric, input+"_") {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func toBurstRule(conf *confmap.Conf, index int) burst.Rule {
+	rule := burst.Rule{
+		Name:            fmt.Sprintf("%s_%d", burstCaptureKey, index),
+		CaptureDuration: burst.DefaultCaptureDuration,
+		Resolution:      burst.DefaultResolution,
+	}
+	rule.Metric, _ = common.GetString(conf, metricKeyName)
+	rule.Threshold, _ = common.GetNumber(conf, thresholdKey)
+	rule.Duration, _ = common.GetDuration(conf, durationKey)
+	if captureDuration, ok := common.GetDuration(conf, captureDurationKey); ok {
+		rule.CaptureDuration = captureDuration
+	}
+	if resolution, ok := common.GetDuration(conf, resolutionKey); ok {
+		rule.Resolution = resolution
+	}
+	// the capture set uses the JSON section names, the receivers use the
+	// telegraf input names
+	for _, input := range common.GetArray[string](conf, captureKey) {
+		rule.Capture = append(rule.Capture, toAlias(input))
+	}
+	if dimensions, ok := conf.Get(dimensionsKey).(map[string]any); ok {
+		rule.Dimensions = make(map[string]string, len(dimensions))
+		for k, v := range dimensions {
+			rule.Dimensions[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	return rule
+}
@@ -0,0 +1,32 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package adapter
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+// checkCollectionInterval validates the collection intervals of the metric
+// inputs below common.FastCollectionInterval. The inputs validated for them
+// must collect every 1, 2 or 5 seconds, so that each 10-second period has the
+// same number of samples, and a warning about the cost is logged. The other
+// inputs may not keep up, so only a warning is logged for them.
+func checkCollectionInterval(input string, interval time.Duration) error {
+	if interval <= 0 || interval >= common.FastCollectionInterval {
+		return nil
+	}
+	if !common.FastCollectionInputs.Contains(input) {
+		log.Printf("W! The metrics_collection_interval of %s is %v, but only cpu, disk, mem and procstat are collected reliably below %v. Some collections may be skipped", input, interval, common.FastCollectionInterval)
+		return nil
+	}
+	if common.FastCollectionInterval%interval != 0 || interval%time.Second != 0 {
+		return fmt.Errorf("the metrics_collection_interval of %s must be 1, 2 or 5 seconds below %v, got %v", input, common.FastCollectionInterval, interval)
+	}
+	log.Printf("W! Collecting %s every %v publishes high-resolution metrics. Their PutMetricData requests are flushed more often and charged per request, and the high-resolution alarms cost more than the standard ones", input, interval)
+	return nil
+}
@@ -4,6 +4,7 @@
 package adapter
 
 import (
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -80,5 +81,12 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 		cfg.CollectionInterval = common.GetOrDefaultDuration(conf, intervalKeyChain, t.defaultMetricCollectionInterval)
 	}
 
+	if strings.HasPrefix(t.cfgKey, metricKey) {
+		if err := checkCollectionInterval(strings.TrimPrefix(t.cfgType.String(), adapter.TelegrafPrefix), cfg.CollectionInterval); err != nil {
+			return nil, err
+		}
+		cfg.Burst = getBurstRules(conf, strings.TrimPrefix(t.cfgType.String(), adapter.TelegrafPrefix))
+	}
+
 	return cfg, nil
 }
@@ -13,6 +13,7 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent/internal/util/hash"
 	"github.com/aws/amazon-cloudwatch-agent/receiver/adapter"
+	"github.com/aws/amazon-cloudwatch-agent/receiver/adapter/burst"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
 )
 
@@ -118,3 +119,102 @@ func TestTranslator(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslatorBurstCapture(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"metrics": map[string]any{
+			"metrics_collected": map[string]any{
+				"cpu":    map[string]any{},
+				"mem":    map[string]any{},
+				"diskio": map[string]any{},
+			},
+			"burst_capture": []any{
+				map[string]any{
+					"metric":     "cpu_usage_active",
+					"threshold":  90,
+					"duration":   60,
+					"dimensions": map[string]any{"cpu": "cpu-total"},
+					"capture":    []any{"cpu", "mem", "disk"},
+				},
+				map[string]any{
+					"metric":           "mem_used_percent",
+					"threshold":        95,
+					"capture":          []any{"mem"},
+					"capture_duration": 120,
+					"resolution":       5,
+				},
+			},
+		},
+	})
+	cpuRule := burst.Rule{
+		Name:            "burst_capture_0",
+		Metric:          "cpu_usage_active",
+		Threshold:       90,
+		Duration:        time.Minute,
+		Dimensions:      map[string]string{"cpu": "cpu-total"},
+		Capture:         []string{"cpu", "mem", "disk"},
+		CaptureDuration: 5 * time.Minute,
+		Resolution:      time.Second,
+	}
+	memRule := burst.Rule{
+		Name:            "burst_capture_1",
+		Metric:          "mem_used_percent",
+		Threshold:       95,
+		Capture:         []string{"mem"},
+		CaptureDuration: 2 * time.Minute,
+		Resolution:      5 * time.Second,
+	}
+	testCases := map[string]struct {
+		input     string
+		wantRules []burst.Rule
+	}{
+		"Trigger":    {input: "cpu", wantRules: []burst.Rule{cpuRule}},
+		"Captured":   {input: "mem", wantRules: []burst.Rule{cpuRule, memRule}},
+		"NotCapture": {input: "diskio"},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tt := NewTranslator(testCase.input, "metrics::metrics_collected::"+testCase.input, time.Minute)
+			got, err := tt.Translate(conf)
+			require.NoError(t, err)
+			gotCfg, ok := got.(*adapter.Config)
+			require.True(t, ok)
+			require.Equal(t, testCase.wantRules, gotCfg.Burst)
+			require.NoError(t, gotCfg.Validate())
+		})
+	}
+}
+
+func TestTranslatorFastCollection(t *testing.T) {
+	testCases := map[string]struct {
+		input    string
+		interval any
+		wantErr  bool
+	}{
+		"WithOneSecond":             {input: "cpu", interval: float64(1)},
+		"WithFiveSeconds":           {input: "mem", interval: float64(5)},
+		"WithTenSeconds":            {input: "disk", interval: float64(10)},
+		"WithUnalignedInterval":     {input: "cpu", interval: float64(3), wantErr: true},
+		"WithSubSecondInterval":     {input: "cpu", interval: "500ms", wantErr: true},
+		"WithNotValidatedInput":     {input: "net", interval: float64(3)},
+		"WithNotValidatedOneSecond": {input: "diskio", interval: float64(1)},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(map[string]any{
+				"metrics": map[string]any{
+					"metrics_collected": map[string]any{
+						testCase.input: map[string]any{"metrics_collection_interval": testCase.interval},
+					},
+				},
+			})
+			tt := NewTranslator(testCase.input, common.ConfigKey(metricKey, testCase.input), time.Minute)
+			_, err := tt.Translate(conf)
+			if testCase.wantErr {
+				require.ErrorContains(t, err, "must be 1, 2 or 5 seconds")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -13,7 +13,13 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
 	translatorconfig "github.com/aws/amazon-cloudwatch-agent/translator/config"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/docker"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/files"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/fluent_forward"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/journald"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/kafka"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/kinesis"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/otlp"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/windows_events"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
 	collectd "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/collectd"
@@ -31,7 +37,7 @@ const (
 var (
 	logKey           = common.ConfigKey(common.LogsKey, common.LogsCollectedKey)
 	metricKey        = common.ConfigKey(common.MetricsKey, common.MetricsCollectedKey)
-	skipInputSet     = collections.NewSet[string](files.SectionKey, windows_events.SectionKey)
+	skipInputSet     = collections.NewSet[string](files.SectionKey, windows_events.SectionKey, kafka.SectionKey, kinesis.SectionKey, otlp.SectionKey, fluent_forward.SectionKey, journald.SectionKey, docker.SectionKey)
 	multipleInputSet = collections.NewSet[string](procstat.SectionKey)
 	// Order by PidFile, ExeKey, Pattern Key according to the public documents
 	// if multiple configuration is specified
@@ -40,6 +46,8 @@ var (
 		procstat.PidFileKey,
 		procstat.ExeKey,
 		procstat.PatternKey,
+		procstat.SystemdUnitKey,
+		procstat.WinServiceKey,
 	}
 	// windowsInputSet contains all the supported metric input plugins. All others are considered custom metrics.
 	// An exception would be procstat metrics
@@ -19,7 +19,7 @@ import (
 // TestFindReceiversInConfig confirms whether the given the agent json configuration
 // will give the appropriate receivers in the agent yaml
 func TestFindReceiversInConfig(t *testing.T) {
-	telegrafSocketListenerType, _ := component.NewType("telegraf_socket_listener")
+	telegrafCollectDType, _ := component.NewType("telegraf_collectd")
 	telegrafCPUType, _ := component.NewType("telegraf_cpu")
 	telegrafEthtoolType, _ := component.NewType("telegraf_ethtool")
 	telegrafNvidiaSmiType, _ := component.NewType("telegraf_nvidia_smi")
@@ -57,19 +57,23 @@ func TestFindReceiversInConfig(t *testing.T) {
 							map[string]interface{}{
 								"exe": "amazon-ssm-agent",
 							},
+							map[string]interface{}{
+								"systemd_unit": "nginx.service",
+							},
 						},
 					},
 				},
 			},
 			os: translatorconfig.OS_TYPE_LINUX,
 			want: map[component.ID]wantResult{
-				component.NewID(telegrafSocketListenerType):                 {"metrics::metrics_collected::collectd", time.Minute},
+				component.NewID(telegrafCollectDType):                       {"metrics::metrics_collected::collectd", time.Minute},
 				component.NewID(telegrafCPUType):                            {"metrics::metrics_collected::cpu", time.Minute},
 				component.NewID(telegrafEthtoolType):                        {"metrics::metrics_collected::ethtool", time.Minute},
 				component.NewID(telegrafNvidiaSmiType):                      {"metrics::metrics_collected::nvidia_gpu", time.Minute},
 				component.NewID(telegrafStatsdType):                         {"metrics::metrics_collected::statsd", 10 * time.Second},
 				component.NewIDWithName(telegrafProcstatType, "793254176"):  {"metrics::metrics_collected::procstat", time.Minute},
 				component.NewIDWithName(telegrafProcstatType, "3599690165"): {"metrics::metrics_collected::procstat", time.Minute},
+				component.NewIDWithName(telegrafProcstatType, "3558368438"): {"metrics::metrics_collected::procstat", time.Minute},
 			},
 		},
 		"WithWindowsMetrics": {
@@ -93,6 +97,9 @@ func TestFindReceiversInConfig(t *testing.T) {
 								"exe":                         "amazon-ssm-agent",
 								"metrics_collection_interval": 15,
 							},
+							map[string]interface{}{
+								"win_service": "W3SVC",
+							},
 						},
 					},
 				},
@@ -102,6 +109,7 @@ func TestFindReceiversInConfig(t *testing.T) {
 				component.NewID(telegrafNvidiaSmiType):                             {"metrics::metrics_collected::nvidia_gpu", time.Minute},
 				component.NewIDWithName(telegrafProcstatType, "793254176"):         {"metrics::metrics_collected::procstat", time.Minute},
 				component.NewIDWithName(telegrafProcstatType, "3599690165"):        {"metrics::metrics_collected::procstat", time.Minute},
+				component.NewIDWithName(telegrafProcstatType, "2668414479"):        {"metrics::metrics_collected::procstat", time.Minute},
 				component.NewIDWithName(telegrafWinPerfCountersType, "4283769065"): {"metrics::metrics_collected::LogicalDisk", time.Minute},
 				component.NewIDWithName(telegrafWinPerfCountersType, "1492679118"): {"metrics::metrics_collected::Memory", time.Minute},
 				component.NewIDWithName(telegrafWinPerfCountersType, "3610923661"): {"metrics::metrics_collected::Paging File", time.Minute},
@@ -124,7 +132,7 @@ func TestFindReceiversInConfig(t *testing.T) {
 			os:   translatorconfig.OS_TYPE_WINDOWS,
 			want: map[component.ID]wantResult{},
 		},
-		"WithNoSocketListener": {
+		"WithNoCollectD": {
 			input: map[string]interface{}{
 				"logs": map[string]interface{}{
 					"metrics_collected": map[string]interface{}{
@@ -136,7 +144,7 @@ func TestFindReceiversInConfig(t *testing.T) {
 			os:   translatorconfig.OS_TYPE_LINUX,
 			want: map[component.ID]wantResult{},
 		},
-		"WithOneSocketListener": {
+		"WithOneCollectD": {
 			input: map[string]interface{}{
 				"metrics": map[string]interface{}{
 					"metrics_collected": map[string]interface{}{
@@ -152,7 +160,7 @@ func TestFindReceiversInConfig(t *testing.T) {
 			},
 			os: translatorconfig.OS_TYPE_LINUX,
 			want: map[component.ID]wantResult{
-				component.NewID(telegrafSocketListenerType): {"metrics::metrics_collected::collectd", time.Minute},
+				component.NewID(telegrafCollectDType): {"metrics::metrics_collected::collectd", time.Minute},
 			},
 		},
 		"WithInvalidOS": {
@@ -33,6 +33,14 @@ const (
 
 	defaultMetricsCollectionInterval = time.Minute
 	defaultLeaderLockName            = "cwagent-clusterleader" // To maintain backwards compatability with https://github.com/aws/amazon-cloudwatch-agent/blob/2dd89abaab4590cffbbc31ef89319b62809b09d1/plugins/inputs/k8sapiserver/k8sapiserver.go#L30
+
+	leaderElectionKey = "leader_election"
+	lockNameKey       = "lock_name"
+	lockTypeKey       = "lock_type"
+	// lockTypeLease elects the agent collecting the cluster level metrics with
+	// a Lease. The receiver keeps the ConfigMap lock of the previous agents up
+	// to date as well, so a rolling update does not elect two leaders.
+	lockTypeLease = "lease"
 )
 
 type translator struct {
@@ -96,8 +104,7 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 		if err := t.setClusterName(conf, cfg); err != nil {
 			return nil, err
 		}
-		cfg.LeaderLockName = defaultLeaderLockName
-		cfg.LeaderLockUsingConfigMapOnly = true
+		t.setLeaderElection(conf, cfg)
 		tagServiceKey := common.ConfigKey(common.LogsKey, common.MetricsCollectedKey, common.KubernetesKey, "tag_service")
 		cfg.TagService = common.GetOrDefaultBool(conf, tagServiceKey, true)
 
@@ -127,6 +134,18 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 	return cfg, nil
 }
 
+// setLeaderElection sets the lock electing the agent of the DaemonSet that
+// collects the cluster level metrics, the ConfigMap one by default.
+func (t *translator) setLeaderElection(conf *confmap.Conf, cfg *awscontainerinsightreceiver.Config) {
+	baseKey := common.ConfigKey(common.LogsKey, common.MetricsCollectedKey, common.KubernetesKey, leaderElectionKey)
+	cfg.LeaderLockName = defaultLeaderLockName
+	if lockName, ok := common.GetString(conf, common.ConfigKey(baseKey, lockNameKey)); ok {
+		cfg.LeaderLockName = lockName
+	}
+	lockType, _ := common.GetString(conf, common.ConfigKey(baseKey, lockTypeKey))
+	cfg.LeaderLockUsingConfigMapOnly = lockType != lockTypeLease
+}
+
 func (t *translator) setClusterName(conf *confmap.Conf, cfg *awscontainerinsightreceiver.Config) error {
 	clusterNameKey := common.ConfigKey(common.LogsKey, common.MetricsCollectedKey, common.KubernetesKey, "cluster_name")
 	if clusterName, ok := common.GetString(conf, clusterNameKey); ok {
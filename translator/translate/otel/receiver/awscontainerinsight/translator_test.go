@@ -106,6 +106,50 @@ func TestTranslator(t *testing.T) {
 				KubeConfigPath:               "",
 			},
 		},
+		"WithKubernetes/WithLeaseLeaderElection": {
+			input: map[string]interface{}{
+				"logs": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"kubernetes": map[string]interface{}{
+							"cluster_name": "TestCluster",
+							"leader_election": map[string]interface{}{
+								"lock_name": "cwagent-leader",
+								"lock_type": "lease",
+							},
+						},
+					},
+				},
+			},
+			want: &awscontainerinsightreceiver.Config{
+				ContainerOrchestrator: eks,
+				CollectionInterval:    60 * time.Second,
+				ClusterName:           "TestCluster",
+				LeaderLockName:        "cwagent-leader",
+				TagService:            true,
+			},
+		},
+		"WithKubernetes/WithConfigMapLeaderElection": {
+			input: map[string]interface{}{
+				"logs": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"kubernetes": map[string]interface{}{
+							"cluster_name": "TestCluster",
+							"leader_election": map[string]interface{}{
+								"lock_type": "configmap",
+							},
+						},
+					},
+				},
+			},
+			want: &awscontainerinsightreceiver.Config{
+				ContainerOrchestrator:        eks,
+				CollectionInterval:           60 * time.Second,
+				ClusterName:                  "TestCluster",
+				LeaderLockName:               defaultLeaderLockName,
+				LeaderLockUsingConfigMapOnly: true,
+				TagService:                   true,
+			},
+		},
 		"WithKubernetes/WithoutClusterName": {
 			input: map[string]interface{}{
 				"logs": map[string]interface{}{
@@ -0,0 +1,176 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package awscontainerinsightsnodehealth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	defaultMetricsCollectionInterval = time.Minute
+	defaultScrapeTimeout             = 10 * time.Second
+	// containerd only serves metrics when [metrics] address is set in its config.toml. 1338 is the port used in the
+	// containerd documentation.
+	defaultContainerdMetricsEndpoint = "${env:" + envconfig.HostIP + "}:1338"
+	kubeletMetricsEndpoint           = "${env:" + envconfig.HostIP + "}:10250"
+
+	kubeletJobName    = "kubelet"
+	containerdJobName = "containerd"
+
+	// NodeHealthType is the Type attribute set on the node health metrics.
+	NodeHealthType = "NodeHealth"
+)
+
+var (
+	// ServiceAccountTokenPath is the token used to authenticate with the kubelet. The prometheus receiver fails
+	// validation if the file does not exist.
+	ServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	kubernetesKey                = common.ConfigKey(common.LogsKey, common.MetricsCollectedKey, common.KubernetesKey)
+	containerdMetricsEndpointKey = common.ConfigKey(kubernetesKey, common.ContainerdMetricsEndpointKey)
+
+	// kubeletMetricsRegex selects the PLEG relist latency and the container runtime operation errors out of the
+	// few hundred series served by the kubelet.
+	kubeletMetricsRegex = "kubelet_pleg_relist_duration_seconds_(bucket|sum|count)|kubelet_runtime_operations_errors_total"
+)
+
+type translator struct {
+	name    string
+	factory receiver.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+// NewTranslatorWithName creates a prometheus receiver translator scraping the kubelet and containerd of the node
+// the agent runs on.
+func NewTranslatorWithName(name string) common.Translator[component.Config] {
+	return &translator{
+		name:    name,
+		factory: prometheusreceiver.NewFactory(),
+	}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates a prometheus receiver config with a scrape job for the kubelet and one for containerd. Only the
+// series used for the node health metrics are kept.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if conf == nil || !common.NodeHealthMetricsEnabled(conf) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.ConfigKey(kubernetesKey, common.EnableNodeHealthMetrics)}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*prometheusreceiver.Config)
+
+	clusterName, err := getClusterName(conf)
+	if err != nil {
+		return nil, err
+	}
+	intervalKeyChain := []string{
+		common.ConfigKey(kubernetesKey, common.MetricsCollectionIntervalKey),
+		common.ConfigKey(common.AgentKey, common.MetricsCollectionIntervalKey),
+	}
+	interval := common.GetOrDefaultDuration(conf, intervalKeyChain, defaultMetricsCollectionInterval)
+	timeout := min(interval, defaultScrapeTimeout)
+	containerdEndpoint, ok := common.GetString(conf, containerdMetricsEndpointKey)
+	if !ok || containerdEndpoint == "" {
+		containerdEndpoint = defaultContainerdMetricsEndpoint
+	}
+
+	labels := map[string]any{
+		"ClusterName": clusterName,
+		"NodeName":    "${env:" + envconfig.HostName + "}",
+		"Type":        NodeHealthType,
+	}
+	scrapeConfigs := []any{
+		map[string]any{
+			"job_name":        kubeletJobName,
+			"scheme":          "https",
+			"scrape_interval": interval.String(),
+			"scrape_timeout":  timeout.String(),
+			"authorization": map[string]any{
+				"credentials_file": ServiceAccountTokenPath,
+			},
+			"tls_config": map[string]any{
+				"insecure_skip_verify": true,
+			},
+			"static_configs": []any{
+				map[string]any{
+					"targets": []any{kubeletMetricsEndpoint},
+					"labels":  labels,
+				},
+			},
+			"metric_relabel_configs": []any{
+				map[string]any{
+					"source_labels": []any{"__name__"},
+					"action":        "keep",
+					"regex":         kubeletMetricsRegex,
+				},
+			},
+		},
+		map[string]any{
+			"job_name":        containerdJobName,
+			"scheme":          "http",
+			"metrics_path":    "/v1/metrics",
+			"scrape_interval": interval.String(),
+			"scrape_timeout":  timeout.String(),
+			"static_configs": []any{
+				map[string]any{
+					"targets": []any{containerdEndpoint},
+					"labels":  labels,
+				},
+			},
+			// the gRPC requests served by containerd that did not succeed, including the CRI calls made by the kubelet
+			"metric_relabel_configs": []any{
+				map[string]any{
+					"source_labels": []any{"__name__"},
+					"action":        "keep",
+					"regex":         "grpc_server_handled_total",
+				},
+				map[string]any{
+					"source_labels": []any{"grpc_code"},
+					"action":        "drop",
+					"regex":         "OK",
+				},
+				map[string]any{
+					"source_labels": []any{"__name__"},
+					"target_label":  "__name__",
+					"action":        "replace",
+					"replacement":   "containerd_grpc_server_handled_total",
+				},
+			},
+		},
+	}
+	promCfg := confmap.NewFromStringMap(map[string]any{
+		"config": map[string]any{
+			"scrape_configs": scrapeConfigs,
+		},
+	})
+	if err = promCfg.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("unable to create node health scrape config: %w", err)
+	}
+	return cfg, nil
+}
+
+func getClusterName(conf *confmap.Conf) (string, error) {
+	clusterName, ok := common.GetString(conf, common.ConfigKey(kubernetesKey, "cluster_name"))
+	if !ok {
+		clusterName = util.GetClusterNameFromEc2Tagger()
+	}
+	if clusterName == "" {
+		return "", errors.New("cluster name is not provided and was not auto-detected from EC2 tags")
+	}
+	return clusterName, nil
+}
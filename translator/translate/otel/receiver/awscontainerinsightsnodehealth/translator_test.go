@@ -0,0 +1,130 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package awscontainerinsightsnodehealth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver"
+	"github.com/prometheus/common/model"
+	promconfig "github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	tt := NewTranslatorWithName("nodeHealthContainerInsights")
+	require.EqualValues(t, "prometheus/nodeHealthContainerInsights", tt.ID().String())
+	testCases := map[string]struct {
+		input              map[string]any
+		wantErr            error
+		wantInterval       time.Duration
+		wantTimeout        time.Duration
+		wantContainerdAddr string
+	}{
+		"WithoutNodeHealthMetrics": {
+			input: map[string]any{
+				"logs": map[string]any{
+					"metrics_collected": map[string]any{
+						"kubernetes": map[string]any{
+							"cluster_name": "TestCluster",
+						},
+					},
+				},
+			},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: "logs::metrics_collected::kubernetes::node_health_metrics"},
+		},
+		"WithDefaults": {
+			input: map[string]any{
+				"logs": map[string]any{
+					"metrics_collected": map[string]any{
+						"kubernetes": map[string]any{
+							"cluster_name":        "TestCluster",
+							"node_health_metrics": true,
+						},
+					},
+				},
+			},
+			wantInterval:       time.Minute,
+			wantTimeout:        10 * time.Second,
+			wantContainerdAddr: "${env:HOST_IP}:1338",
+		},
+		"WithContainerdEndpointAndInterval": {
+			input: map[string]any{
+				"logs": map[string]any{
+					"metrics_collected": map[string]any{
+						"kubernetes": map[string]any{
+							"cluster_name":                "TestCluster",
+							"node_health_metrics":         true,
+							"containerd_metrics_endpoint": "127.0.0.1:1338",
+							"metrics_collection_interval": 5,
+						},
+					},
+				},
+			},
+			wantInterval:       5 * time.Second,
+			wantTimeout:        5 * time.Second,
+			wantContainerdAddr: "127.0.0.1:1338",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(testCase.input)
+			got, err := tt.Translate(conf)
+			require.Equal(t, testCase.wantErr, err)
+			if testCase.wantErr != nil {
+				return
+			}
+			cfg, ok := got.(*prometheusreceiver.Config)
+			require.True(t, ok)
+			require.Len(t, cfg.PrometheusConfig.ScrapeConfigs, 2)
+
+			kubelet := cfg.PrometheusConfig.ScrapeConfigs[0]
+			assert.Equal(t, "kubelet", kubelet.JobName)
+			assert.Equal(t, "https", kubelet.Scheme)
+			assert.True(t, kubelet.HTTPClientConfig.TLSConfig.InsecureSkipVerify)
+			assert.Equal(t, ServiceAccountTokenPath, kubelet.HTTPClientConfig.Authorization.CredentialsFile)
+			assert.Equal(t, model.Duration(testCase.wantInterval), kubelet.ScrapeInterval)
+			assert.Equal(t, model.Duration(testCase.wantTimeout), kubelet.ScrapeTimeout)
+			require.Len(t, kubelet.MetricRelabelConfigs, 1)
+			assert.Equal(t, relabel.Keep, kubelet.MetricRelabelConfigs[0].Action)
+			assert.True(t, kubelet.MetricRelabelConfigs[0].Regex.MatchString("kubelet_pleg_relist_duration_seconds_bucket"))
+			assert.True(t, kubelet.MetricRelabelConfigs[0].Regex.MatchString("kubelet_runtime_operations_errors_total"))
+			assert.False(t, kubelet.MetricRelabelConfigs[0].Regex.MatchString("kubelet_runtime_operations_total"))
+
+			containerd := cfg.PrometheusConfig.ScrapeConfigs[1]
+			assert.Equal(t, "containerd", containerd.JobName)
+			assert.Equal(t, "/v1/metrics", containerd.MetricsPath)
+			assert.Equal(t, model.Duration(testCase.wantInterval), containerd.ScrapeInterval)
+			require.Len(t, containerd.MetricRelabelConfigs, 3)
+			assert.Equal(t, "containerd_grpc_server_handled_total", containerd.MetricRelabelConfigs[2].Replacement)
+			wantLabels := model.LabelSet{
+				"ClusterName": "TestCluster",
+				"NodeName":    "${env:HOST_NAME}",
+				"Type":        "NodeHealth",
+			}
+			kubeletTargets, containerdTargets := staticTargets(t, kubelet), staticTargets(t, containerd)
+			assert.Equal(t, model.LabelValue("${env:HOST_IP}:10250"), kubeletTargets.Targets[0][model.AddressLabel])
+			assert.Equal(t, wantLabels, kubeletTargets.Labels)
+			assert.Equal(t, model.LabelValue(testCase.wantContainerdAddr), containerdTargets.Targets[0][model.AddressLabel])
+			assert.Equal(t, wantLabels, containerdTargets.Labels)
+		})
+	}
+}
+
+func staticTargets(t *testing.T, scrapeConfig *promconfig.ScrapeConfig) *targetgroup.Group {
+	t.Helper()
+	require.Len(t, scrapeConfig.ServiceDiscoveryConfigs, 1)
+	staticConfig, ok := scrapeConfig.ServiceDiscoveryConfigs[0].(discovery.StaticConfig)
+	require.True(t, ok)
+	require.Len(t, staticConfig, 1)
+	return staticConfig[0]
+}
@@ -80,6 +80,9 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 	if endpoint, ok := common.GetString(conf, common.ConfigKey(common.TracesKey, common.EndpointOverrideKey)); ok {
 		cfg.ProxyServer.AWSEndpoint = endpoint
 	}
+	if proxyAddress, ok := common.GetServiceProxyAddress(conf, common.XrayKey); ok {
+		cfg.ProxyServer.ProxyAddress = proxyAddress
+	}
 	if proxyAddress, ok := common.GetString(conf, common.ConfigKey(common.TracesKey, common.ProxyOverrideKey)); ok {
 		cfg.ProxyServer.ProxyAddress = proxyAddress
 	}
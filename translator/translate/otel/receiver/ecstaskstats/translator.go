@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecstaskstats
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/aws/amazon-cloudwatch-agent/receiver/ecstaskstats"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	defaultMetricsCollectionInterval = time.Minute
+)
+
+var (
+	ecsKey = common.ConfigKey(common.LogsKey, common.MetricsCollectedKey, common.ECSKey)
+)
+
+type translator struct {
+	name    string
+	factory receiver.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+// NewTranslatorWithName creates a translator for the receiver reporting the
+// network and Service Connect metrics of the ECS tasks.
+func NewTranslatorWithName(name string) common.Translator[component.Config] {
+	return &translator{
+		name:    name,
+		factory: ecstaskstats.NewFactory(),
+	}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates an ECS task stats receiver config if the task network or
+// the Service Connect metrics are enabled in the ecs section.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if conf == nil || (!common.TaskNetworkMetricsEnabled(conf) && !common.ServiceConnectMetricsEnabled(conf)) {
+		return nil, &common.MissingKeyError{
+			ID:      t.ID(),
+			JsonKey: fmt.Sprint(common.ConfigKey(ecsKey, common.EnableTaskNetworkMetrics), " or ", common.ConfigKey(ecsKey, common.EnableServiceConnectMetrics)),
+		}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*ecstaskstats.Config)
+	intervalKeyChain := []string{
+		common.ConfigKey(ecsKey, common.MetricsCollectionIntervalKey),
+		common.ConfigKey(common.AgentKey, common.MetricsCollectionIntervalKey),
+	}
+	cfg.CollectionInterval = common.GetOrDefaultDuration(conf, intervalKeyChain, defaultMetricsCollectionInterval)
+	cfg.TaskNetworkMetrics = common.TaskNetworkMetricsEnabled(conf)
+	cfg.ServiceConnectMetrics = common.ServiceConnectMetricsEnabled(conf)
+	return cfg, nil
+}
@@ -0,0 +1,98 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecstaskstats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/receiver/ecstaskstats"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	tt := NewTranslatorWithName("ecsTaskContainerInsights")
+	assert.EqualValues(t, "ecstaskstats/ecsTaskContainerInsights", tt.ID().String())
+	testCases := map[string]struct {
+		input   map[string]any
+		want    *ecstaskstats.Config
+		wantErr error
+	}{
+		"WithoutEcs": {
+			input:   map[string]any{},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: "logs::metrics_collected::ecs::task_network_metrics or logs::metrics_collected::ecs::service_connect_metrics"},
+		},
+		"WithoutTaskMetrics": {
+			input: map[string]any{
+				"logs": map[string]any{"metrics_collected": map[string]any{"ecs": map[string]any{}}},
+			},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: "logs::metrics_collected::ecs::task_network_metrics or logs::metrics_collected::ecs::service_connect_metrics"},
+		},
+		"WithTaskNetworkMetrics": {
+			input: map[string]any{
+				"agent": map[string]any{"metrics_collection_interval": 30},
+				"logs": map[string]any{"metrics_collected": map[string]any{"ecs": map[string]any{
+					"task_network_metrics": true,
+				}}},
+			},
+			want: &ecstaskstats.Config{
+				TaskNetworkMetrics: true,
+			},
+		},
+		"WithServiceConnectMetrics": {
+			input: map[string]any{
+				"agent": map[string]any{"metrics_collection_interval": 30},
+				"logs": map[string]any{"metrics_collected": map[string]any{"ecs": map[string]any{
+					"metrics_collection_interval": 15,
+					"task_network_metrics":        false,
+					"service_connect_metrics":     true,
+				}}},
+			},
+			want: &ecstaskstats.Config{
+				ServiceConnectMetrics: true,
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.Translate(confmap.NewFromStringMap(testCase.input))
+			assert.Equal(t, testCase.wantErr, err)
+			if testCase.want == nil {
+				return
+			}
+			require.NoError(t, err)
+			cfg, ok := got.(*ecstaskstats.Config)
+			require.True(t, ok)
+			assert.Equal(t, testCase.want.TaskNetworkMetrics, cfg.TaskNetworkMetrics)
+			assert.Equal(t, testCase.want.ServiceConnectMetrics, cfg.ServiceConnectMetrics)
+			assert.NoError(t, cfg.Validate())
+		})
+	}
+}
+
+func TestTranslatorCollectionInterval(t *testing.T) {
+	tt := NewTranslatorWithName("")
+	got, err := tt.Translate(confmap.NewFromStringMap(map[string]any{
+		"agent": map[string]any{"metrics_collection_interval": 30},
+		"logs": map[string]any{"metrics_collected": map[string]any{"ecs": map[string]any{
+			"task_network_metrics": true,
+		}}},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, got.(*ecstaskstats.Config).CollectionInterval)
+
+	got, err = tt.Translate(confmap.NewFromStringMap(map[string]any{
+		"agent": map[string]any{"metrics_collection_interval": 30},
+		"logs": map[string]any{"metrics_collected": map[string]any{"ecs": map[string]any{
+			"metrics_collection_interval": 15,
+			"service_connect_metrics":     true,
+		}}},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Second, got.(*ecstaskstats.Config).CollectionInterval)
+}
@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package eksfargatestats
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/aws/amazon-cloudwatch-agent/receiver/eksfargatestats"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	defaultMetricsCollectionInterval = time.Minute
+)
+
+var (
+	kubernetesKey = common.ConfigKey(common.LogsKey, common.MetricsCollectedKey, common.KubernetesKey)
+)
+
+type translator struct {
+	name    string
+	factory receiver.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+// NewTranslatorWithName creates a translator for the receiver reporting the
+// pod and container metrics of the pods running on EKS Fargate.
+func NewTranslatorWithName(name string) common.Translator[component.Config] {
+	return &translator{
+		name:    name,
+		factory: eksfargatestats.NewFactory(),
+	}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates an EKS Fargate stats receiver config if fargate is
+// enabled in the kubernetes section.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if conf == nil || !common.FargateEnabled(conf) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.ConfigKey(kubernetesKey, common.EnableFargate)}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*eksfargatestats.Config)
+	intervalKeyChain := []string{
+		common.ConfigKey(kubernetesKey, common.MetricsCollectionIntervalKey),
+		common.ConfigKey(common.AgentKey, common.MetricsCollectionIntervalKey),
+	}
+	cfg.CollectionInterval = common.GetOrDefaultDuration(conf, intervalKeyChain, defaultMetricsCollectionInterval)
+	// there are no EC2 tags to detect the cluster name from on Fargate
+	clusterName, ok := common.GetString(conf, common.ConfigKey(kubernetesKey, "cluster_name"))
+	if !ok || clusterName == "" {
+		return nil, errors.New("cluster_name must be provided in the kubernetes section when fargate is enabled")
+	}
+	cfg.ClusterName = clusterName
+	if kubeConfigPath, ok := common.GetString(conf, common.ConfigKey(kubernetesKey, "kube_config_path")); ok {
+		cfg.KubeConfigPath = kubeConfigPath
+	}
+	return cfg, nil
+}
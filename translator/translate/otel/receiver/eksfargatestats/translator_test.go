@@ -0,0 +1,98 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package eksfargatestats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/receiver/eksfargatestats"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	tt := NewTranslatorWithName("containerinsights")
+	assert.EqualValues(t, "eksfargatestats/containerinsights", tt.ID().String())
+	testCases := map[string]struct {
+		input   map[string]any
+		want    *eksfargatestats.Config
+		wantErr error
+	}{
+		"WithoutKubernetes": {
+			input:   map[string]any{},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: "logs::metrics_collected::kubernetes::fargate"},
+		},
+		"WithoutFargate": {
+			input: map[string]any{
+				"logs": map[string]any{"metrics_collected": map[string]any{"kubernetes": map[string]any{
+					"cluster_name": "my-cluster",
+				}}},
+			},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: "logs::metrics_collected::kubernetes::fargate"},
+		},
+		"WithoutClusterName": {
+			input: map[string]any{
+				"logs": map[string]any{"metrics_collected": map[string]any{"kubernetes": map[string]any{
+					"fargate": true,
+				}}},
+			},
+			wantErr: errors.New("cluster_name must be provided in the kubernetes section when fargate is enabled"),
+		},
+		"WithFargate": {
+			input: map[string]any{
+				"agent": map[string]any{"metrics_collection_interval": 30},
+				"logs": map[string]any{"metrics_collected": map[string]any{"kubernetes": map[string]any{
+					"fargate":      true,
+					"cluster_name": "my-cluster",
+				}}},
+			},
+			want: &eksfargatestats.Config{
+				ClusterName: "my-cluster",
+			},
+		},
+		"WithKubeConfigAndInterval": {
+			input: map[string]any{
+				"agent": map[string]any{"metrics_collection_interval": 30},
+				"logs": map[string]any{"metrics_collected": map[string]any{"kubernetes": map[string]any{
+					"fargate":                     true,
+					"cluster_name":                "my-cluster",
+					"kube_config_path":            "/etc/kube/config",
+					"metrics_collection_interval": 15,
+				}}},
+			},
+			want: &eksfargatestats.Config{
+				ClusterName:    "my-cluster",
+				KubeConfigPath: "/etc/kube/config",
+			},
+		},
+	}
+	intervals := map[string]time.Duration{
+		"WithFargate":               30 * time.Second,
+		"WithKubeConfigAndInterval": 15 * time.Second,
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conf := confmap.NewFromStringMap(testCase.input)
+			got, err := tt.Translate(conf)
+			assert.Equal(t, testCase.wantErr, err)
+			if testCase.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			gotCfg, ok := got.(*eksfargatestats.Config)
+			require.True(t, ok)
+			assert.Equal(t, intervals[name], gotCfg.CollectionInterval)
+			assert.Equal(t, testCase.want.ClusterName, gotCfg.ClusterName)
+			assert.Equal(t, testCase.want.KubeConfigPath, gotCfg.KubeConfigPath)
+			assert.Equal(t, "eks.amazonaws.com/compute-type=fargate", gotCfg.NodeSelector)
+			assert.NoError(t, gotCfg.Validate())
+		})
+	}
+}
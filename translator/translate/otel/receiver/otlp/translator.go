@@ -7,6 +7,7 @@ import (
 	_ "embed"
 	"fmt"
 	"strconv"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configtls"
@@ -23,6 +24,12 @@ const (
 	defaultAppSignalsGrpcEndpoint = "0.0.0.0:4315"
 	defaultAppSignalsHttpEndpoint = "0.0.0.0:4316"
 	defaultJMXHttpEndpoint        = "0.0.0.0:4314"
+
+	compressionAlgorithmsKey = "compression_algorithms"
+	clientCAFileKey          = "client_ca_file"
+	destinationsKey          = "destinations"
+
+	tlsReloadInterval = time.Minute
 )
 
 type translator struct {
@@ -110,6 +117,13 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 		tlsSettings = &configtls.ServerConfig{}
 		tlsSettings.CertFile = tls["cert_file"].(string)
 		tlsSettings.KeyFile = tls["key_file"].(string)
+		// the rotated certificate is picked up by the next handshakes
+		tlsSettings.ReloadInterval = tlsReloadInterval
+		// the listener only accepts the clients with a certificate signed by the CA (mTLS)
+		if clientCAFile, ok := tls[clientCAFileKey].(string); ok {
+			tlsSettings.ClientCAFile = clientCAFile
+			tlsSettings.ReloadClientCAFile = true
+		}
 	}
 	cfg.GRPC.TLSSetting = tlsSettings
 	cfg.HTTP.TLSSetting = tlsSettings
@@ -122,5 +136,32 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 	if httpOk {
 		cfg.HTTP.Endpoint = httpEndpoint.(string)
 	}
+	// the gRPC server decodes every registered compression, so only the HTTP server is restricted
+	if algorithms, ok := otlpMap[compressionAlgorithmsKey].([]interface{}); ok {
+		// uncompressed requests are always accepted
+		cfg.HTTP.CompressionAlgorithms = []string{""}
+		for _, algorithm := range algorithms {
+			cfg.HTTP.CompressionAlgorithms = append(cfg.HTTP.CompressionAlgorithms, fmt.Sprint(algorithm))
+		}
+	}
 	return cfg, nil
 }
+
+// RoutesTo returns true if the OTLP receiver instance at the index sends its
+// telemetry to the destination. Instances without destinations are routed to
+// all of them. The default destination is CloudWatch.
+func RoutesTo(conf *confmap.Conf, configKey string, index int, destination string) bool {
+	destinations, ok := common.GetIndexedMap(conf, configKey, index)[destinationsKey].([]any)
+	if !ok {
+		return true
+	}
+	if destination == common.DefaultDestination {
+		destination = common.CloudWatchKey
+	}
+	for _, d := range destinations {
+		if d == destination {
+			return true
+		}
+	}
+	return false
+}
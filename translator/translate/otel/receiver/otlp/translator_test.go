@@ -6,6 +6,7 @@ package otlp
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -331,15 +332,17 @@ func TestTranslateAppSignals(t *testing.T) {
 					"grpc": map[string]interface{}{
 						"endpoint": "0.0.0.0:4315",
 						"tls": map[string]interface{}{
-							"cert_file": "path/to/cert.crt",
-							"key_file":  "path/to/key.key",
+							"cert_file":       "path/to/cert.crt",
+							"key_file":        "path/to/key.key",
+							"reload_interval": "1m",
 						},
 					},
 					"http": map[string]interface{}{
 						"endpoint": "0.0.0.0:4316",
 						"tls": map[string]interface{}{
-							"cert_file": "path/to/cert.crt",
-							"key_file":  "path/to/key.key",
+							"cert_file":       "path/to/cert.crt",
+							"key_file":        "path/to/key.key",
+							"reload_interval": "1m",
 						},
 					},
 				},
@@ -380,15 +383,17 @@ func TestTranslateAppSignals(t *testing.T) {
 					"grpc": map[string]interface{}{
 						"endpoint": "0.0.0.0:4315",
 						"tls": map[string]interface{}{
-							"cert_file": "path/to/cert.crt",
-							"key_file":  "path/to/key.key",
+							"cert_file":       "path/to/cert.crt",
+							"key_file":        "path/to/key.key",
+							"reload_interval": "1m",
 						},
 					},
 					"http": map[string]interface{}{
 						"endpoint": "0.0.0.0:4316",
 						"tls": map[string]interface{}{
-							"cert_file": "path/to/cert.crt",
-							"key_file":  "path/to/key.key",
+							"cert_file":       "path/to/cert.crt",
+							"key_file":        "path/to/key.key",
+							"reload_interval": "1m",
 						},
 					},
 				},
@@ -424,3 +429,60 @@ func TestTranslateJMX(t *testing.T) {
 	assert.NotNil(t, gotCfg.HTTP)
 	assert.Equal(t, "0.0.0.0:4314", gotCfg.HTTP.Endpoint)
 }
+
+func TestTranslateMutualTLS(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"traces": map[string]any{
+			"traces_collected": map[string]any{
+				"otlp": []any{
+					map[string]any{},
+					map[string]any{
+						"grpc_endpoint": "0.0.0.0:5317",
+						"tls": map[string]any{
+							"cert_file":      "/path/to/cert.pem",
+							"key_file":       "/path/to/key.pem",
+							"client_ca_file": "/path/to/ca.pem",
+						},
+					},
+				},
+			},
+		},
+	})
+	configKey := common.ConfigKey(common.TracesKey, common.TracesCollectedKey, common.OtlpKey)
+	tt := NewTranslator(WithDataType(component.DataTypeTraces), WithConfigKey(configKey), common.WithIndex(0))
+	assert.EqualValues(t, "otlp/traces/0", tt.ID().String())
+	got, err := tt.Translate(conf)
+	require.NoError(t, err)
+	gotCfg := got.(*otlpreceiver.Config)
+	assert.Equal(t, "127.0.0.1:4317", gotCfg.GRPC.NetAddr.Endpoint)
+	assert.Nil(t, gotCfg.GRPC.TLSSetting)
+
+	tt = NewTranslator(WithDataType(component.DataTypeTraces), WithConfigKey(configKey), common.WithIndex(1))
+	got, err = tt.Translate(conf)
+	require.NoError(t, err)
+	gotCfg = got.(*otlpreceiver.Config)
+	assert.Equal(t, "0.0.0.0:5317", gotCfg.GRPC.NetAddr.Endpoint)
+	require.NotNil(t, gotCfg.GRPC.TLSSetting)
+	assert.Equal(t, "/path/to/ca.pem", gotCfg.GRPC.TLSSetting.ClientCAFile)
+	assert.Equal(t, "/path/to/ca.pem", gotCfg.HTTP.TLSSetting.ClientCAFile)
+	assert.True(t, gotCfg.GRPC.TLSSetting.ReloadClientCAFile)
+	assert.Equal(t, time.Minute, gotCfg.GRPC.TLSSetting.ReloadInterval)
+}
+
+func TestRoutesTo(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"metrics": map[string]any{
+			"metrics_collected": map[string]any{
+				"otlp": []any{
+					map[string]any{"destinations": []any{"cloudwatch"}},
+					map[string]any{},
+				},
+			},
+		},
+	})
+	configKey := common.ConfigKey(common.MetricsKey, common.MetricsCollectedKey, common.OtlpKey)
+	assert.True(t, RoutesTo(conf, configKey, 0, common.CloudWatchKey))
+	assert.True(t, RoutesTo(conf, configKey, 0, common.DefaultDestination))
+	assert.False(t, RoutesTo(conf, configKey, 0, common.AMPKey))
+	assert.True(t, RoutesTo(conf, configKey, 1, common.AMPKey))
+}
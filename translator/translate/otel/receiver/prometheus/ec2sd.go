@@ -0,0 +1,129 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package prometheus
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	ec2SDKey = "ec2_sd"
+
+	defaultEC2SDJobName         = "ec2_sd"
+	defaultEC2SDPort            = 80
+	defaultEC2SDRefreshInterval = time.Minute
+	defaultEC2SDScrapeInterval  = time.Minute
+	defaultEC2SDScrapeTimeout   = 10 * time.Second
+
+	ec2MetaLabelPrefix = "__meta_ec2_"
+)
+
+var (
+	ec2SDConfigKey = common.ConfigKey(common.MetricsKey, common.MetricsCollectedKey, common.PrometheusKey, ec2SDKey)
+
+	// invalidLabelCharRegex matches the characters replaced by the EC2 service discovery when the instance tags
+	// are turned into __meta_ec2_tag_<key> labels.
+	invalidLabelCharRegex = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+	// ec2MetadataLabels maps the instance metadata discovered through the EC2 API to the labels added to the
+	// scraped metrics. The names match the ones used by append_dimensions.
+	ec2MetadataLabels = [][2]string{
+		{"instance_id", "InstanceId"},
+		{"instance_type", "InstanceType"},
+		{"ami", "ImageId"},
+		{"availability_zone", "AvailabilityZone"},
+	}
+)
+
+// ec2SDScrapeConfig creates a receiver config with a single scrape job discovering its targets through the EC2
+// DescribeInstances API. Only the running instances matching the filters are scraped, on their private IP and
+// either the configured port or the port set in the port_tag instance tag.
+func ec2SDScrapeConfig(conf *confmap.Conf, cfg *prometheusreceiver.Config) error {
+	jobName := defaultEC2SDJobName
+	if name, ok := common.GetString(conf, common.ConfigKey(ec2SDConfigKey, "job_name")); ok && name != "" {
+		jobName = name
+	}
+	port := defaultEC2SDPort
+	if p, ok := common.GetNumber(conf, common.ConfigKey(ec2SDConfigKey, "port")); ok {
+		port = int(p)
+	}
+	region, ok := common.GetString(conf, common.ConfigKey(ec2SDConfigKey, "region"))
+	if !ok || region == "" {
+		region = agent.Global_Config.Region
+	}
+	refreshInterval := common.GetOrDefaultDuration(conf, []string{common.ConfigKey(ec2SDConfigKey, "refresh_interval")}, defaultEC2SDRefreshInterval)
+	scrapeInterval := common.GetOrDefaultDuration(conf, []string{
+		common.ConfigKey(ec2SDConfigKey, common.MetricsCollectionIntervalKey),
+		common.ConfigKey(common.AgentKey, common.MetricsCollectionIntervalKey),
+	}, defaultEC2SDScrapeInterval)
+
+	sdConfig := map[string]any{
+		"region":           region,
+		"port":             port,
+		"refresh_interval": refreshInterval.String(),
+	}
+	// the discovery uses the same credentials as the rest of the agent
+	if profile, ok := agent.Global_Config.Credentials[agent.Profile_Key]; ok {
+		sdConfig["profile"] = fmt.Sprintf("%v", profile)
+	}
+	if agent.Global_Config.Role_arn != "" {
+		sdConfig["role_arn"] = agent.Global_Config.Role_arn
+	}
+	if filters := conf.Get(common.ConfigKey(ec2SDConfigKey, "filters")); filters != nil {
+		sdConfig["filters"] = filters
+	}
+
+	relabelConfigs := []any{
+		map[string]any{
+			"source_labels": []any{ec2MetaLabelPrefix + "instance_state"},
+			"action":        "keep",
+			"regex":         "running",
+		},
+	}
+	if portTag, ok := common.GetString(conf, common.ConfigKey(ec2SDConfigKey, "port_tag")); ok && portTag != "" {
+		relabelConfigs = append(relabelConfigs, map[string]any{
+			"source_labels": []any{ec2MetaLabelPrefix + "private_ip", ec2MetaLabelPrefix + "tag_" + invalidLabelCharRegex.ReplaceAllString(portTag, "_")},
+			"regex":         `(.+);(\d+)`,
+			"target_label":  "__address__",
+			"replacement":   "$1:$2",
+			"action":        "replace",
+		})
+	}
+	for _, label := range ec2MetadataLabels {
+		relabelConfigs = append(relabelConfigs, map[string]any{
+			"source_labels": []any{ec2MetaLabelPrefix + label[0]},
+			"target_label":  label[1],
+			"action":        "replace",
+		})
+	}
+
+	scrapeConfig := map[string]any{
+		"job_name":        jobName,
+		"scrape_interval": scrapeInterval.String(),
+		"scrape_timeout":  min(scrapeInterval, defaultEC2SDScrapeTimeout).String(),
+		"ec2_sd_configs":  []any{sdConfig},
+		"relabel_configs": relabelConfigs,
+	}
+	if metricsPath, ok := common.GetString(conf, common.ConfigKey(ec2SDConfigKey, "metrics_path")); ok && metricsPath != "" {
+		scrapeConfig["metrics_path"] = metricsPath
+	}
+
+	promCfg := confmap.NewFromStringMap(map[string]any{
+		"config": map[string]any{
+			"scrape_configs": []any{scrapeConfig},
+		},
+	})
+	if err := promCfg.Unmarshal(cfg); err != nil {
+		return fmt.Errorf("unable to create ec2 service discovery scrape config: %w", err)
+	}
+	return nil
+}
@@ -49,13 +49,37 @@ func (t *translator) ID() component.ID {
 	return component.NewIDWithName(t.factory.Type(), t.name)
 }
 
+// Translate creates a prometheus receiver config from the prometheus config file, the EC2 service discovery job or
+// both. The EC2 service discovery job is appended to the scrape configs of the file.
 func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
-	cfg := t.factory.CreateDefaultConfig().(*prometheusreceiver.Config)
-
-	if !conf.IsSet(configPathKey) {
+	if conf == nil || !(conf.IsSet(configPathKey) || conf.IsSet(ec2SDConfigKey)) {
 		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: configPathKey}
 	}
 
+	cfg := t.factory.CreateDefaultConfig().(*prometheusreceiver.Config)
+	if conf.IsSet(configPathKey) {
+		var err error
+		if cfg, err = t.translateConfigFile(conf); err != nil {
+			return nil, err
+		}
+	}
+	if !conf.IsSet(ec2SDConfigKey) {
+		return cfg, nil
+	}
+	ec2SDCfg := t.factory.CreateDefaultConfig().(*prometheusreceiver.Config)
+	if err := ec2SDScrapeConfig(conf, ec2SDCfg); err != nil {
+		return nil, err
+	}
+	if cfg.PrometheusConfig == nil {
+		cfg.PrometheusConfig = ec2SDCfg.PrometheusConfig
+	} else {
+		cfg.PrometheusConfig.ScrapeConfigs = append(cfg.PrometheusConfig.ScrapeConfigs, ec2SDCfg.PrometheusConfig.ScrapeConfigs...)
+	}
+	return cfg, nil
+}
+
+func (t *translator) translateConfigFile(conf *confmap.Conf) (*prometheusreceiver.Config, error) {
+	cfg := t.factory.CreateDefaultConfig().(*prometheusreceiver.Config)
 	configPath, _ := common.GetString(conf, configPathKey)
 	processedConfigPath, err := util.GetConfigPath("prometheus.yaml", configPathKey, configPath, nil)
 	if err != nil {
@@ -15,7 +15,9 @@ import (
 	"github.com/prometheus/prometheus/config"
 	promconfig "github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/aws"
 	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/prometheus/prometheus/model/relabel"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/config/confighttp"
@@ -23,6 +25,7 @@ import (
 	"go.opentelemetry.io/collector/confmap"
 
 	"github.com/aws/amazon-cloudwatch-agent/internal/util/testutil"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
 )
 
 func TestTranslator(t *testing.T) {
@@ -154,3 +157,96 @@ func TestTranslator(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslatorWithEC2SD(t *testing.T) {
+	tt := NewTranslator()
+	testCases := map[string]struct {
+		input            map[string]any
+		wantErr          error
+		wantJobs         []string
+		wantSDConfig     *aws.EC2SDConfig
+		wantInterval     time.Duration
+		wantRelabelCount int
+	}{
+		"WithoutConfig": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_collected": map[string]any{
+						"prometheus": map[string]any{},
+					},
+				},
+			},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: "metrics::metrics_collected::prometheus::prometheus_config_path"},
+		},
+		"WithEC2SDOnly": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_collected": map[string]any{
+						"prometheus": map[string]any{
+							"ec2_sd": map[string]any{
+								"region": "us-east-1",
+							},
+						},
+					},
+				},
+			},
+			wantJobs: []string{"ec2_sd"},
+			wantSDConfig: &aws.EC2SDConfig{
+				Region:           "us-east-1",
+				Port:             80,
+				RefreshInterval:  model.Duration(time.Minute),
+				HTTPClientConfig: promcommon.DefaultHTTPClientConfig,
+			},
+			wantInterval:     time.Minute,
+			wantRelabelCount: 5,
+		},
+		"WithConfigFileAndEC2SD": {
+			input:    testutil.GetJson(t, filepath.Join("testdata", "config_ec2_sd.json")),
+			wantJobs: []string{"prometheus_test_job", "node_exporter"},
+			wantSDConfig: &aws.EC2SDConfig{
+				Region:          "us-west-2",
+				Port:            9100,
+				RefreshInterval: model.Duration(2 * time.Minute),
+				Filters: []*aws.EC2Filter{
+					{Name: "tag:Environment", Values: []string{"prod"}},
+				},
+				HTTPClientConfig: promcommon.DefaultHTTPClientConfig,
+			},
+			wantInterval:     30 * time.Second,
+			wantRelabelCount: 6,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.Translate(confmap.NewFromStringMap(testCase.input))
+			require.Equal(t, testCase.wantErr, err)
+			if testCase.wantErr != nil {
+				return
+			}
+			gotCfg, ok := got.(*prometheusreceiver.Config)
+			require.True(t, ok)
+			scrapeConfigs := gotCfg.PrometheusConfig.ScrapeConfigs
+			require.Len(t, scrapeConfigs, len(testCase.wantJobs))
+			for i, job := range testCase.wantJobs {
+				assert.Equal(t, job, scrapeConfigs[i].JobName)
+			}
+
+			ec2Job := scrapeConfigs[len(scrapeConfigs)-1]
+			assert.Equal(t, model.Duration(testCase.wantInterval), ec2Job.ScrapeInterval)
+			assert.Equal(t, model.Duration(10*time.Second), ec2Job.ScrapeTimeout)
+			require.Len(t, ec2Job.ServiceDiscoveryConfigs, 1)
+			assert.Equal(t, testCase.wantSDConfig, ec2Job.ServiceDiscoveryConfigs[0])
+			require.Len(t, ec2Job.RelabelConfigs, testCase.wantRelabelCount)
+			assert.Equal(t, relabel.Keep, ec2Job.RelabelConfigs[0].Action)
+			if testCase.wantRelabelCount == 6 {
+				portRelabel := ec2Job.RelabelConfigs[1]
+				assert.Equal(t, model.LabelNames{"__meta_ec2_private_ip", "__meta_ec2_tag_prometheus_io_port"}, portRelabel.SourceLabels)
+				assert.Equal(t, model.AddressLabel, portRelabel.TargetLabel)
+				assert.Equal(t, "$1:$2", portRelabel.Replacement)
+			}
+			instanceIDRelabel := ec2Job.RelabelConfigs[len(ec2Job.RelabelConfigs)-4]
+			assert.Equal(t, model.LabelNames{"__meta_ec2_instance_id"}, instanceIDRelabel.SourceLabels)
+			assert.Equal(t, "InstanceId", instanceIDRelabel.TargetLabel)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selftelemetry
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/aws/amazon-cloudwatch-agent/receiver/selftelemetry"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	defaultMetricsCollectionInterval = time.Minute
+)
+
+var (
+	intervalKey = common.ConfigKey(common.InternalMetricsConfigKey, common.MetricsCollectionIntervalKey)
+)
+
+type translator struct {
+	name    string
+	factory receiver.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+func NewTranslatorWithName(name string) common.Translator[component.Config] {
+	return &translator{
+		name:    name,
+		factory: selftelemetry.NewFactory(),
+	}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates a self telemetry receiver config if the internal metrics
+// section is present.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if conf == nil || !conf.IsSet(common.InternalMetricsConfigKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.InternalMetricsConfigKey}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*selftelemetry.Config)
+	cfg.CollectionInterval = common.GetOrDefaultDuration(conf, []string{intervalKey}, defaultMetricsCollectionInterval)
+	return cfg, nil
+}
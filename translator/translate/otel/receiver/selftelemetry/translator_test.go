@@ -0,0 +1,54 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selftelemetry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/receiver/selftelemetry"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	tt := NewTranslatorWithName(common.PipelineNameInternalMetrics)
+	assert.EqualValues(t, "selftelemetry/internal_metrics", tt.ID().String())
+	testCases := map[string]struct {
+		input        map[string]any
+		wantInterval time.Duration
+		wantErr      error
+	}{
+		"WithoutInternalMetrics": {
+			input: map[string]any{"agent": map[string]any{}},
+			wantErr: &common.MissingKeyError{
+				ID:      tt.ID(),
+				JsonKey: common.InternalMetricsConfigKey,
+			},
+		},
+		"WithDefaultInterval": {
+			input:        map[string]any{"agent": map[string]any{"internal_metrics": map[string]any{}}},
+			wantInterval: time.Minute,
+		},
+		"WithInterval": {
+			input: map[string]any{"agent": map[string]any{"internal_metrics": map[string]any{
+				"metrics_collection_interval": 30,
+			}}},
+			wantInterval: 30 * time.Second,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.Translate(confmap.NewFromStringMap(testCase.input))
+			assert.Equal(t, testCase.wantErr, err)
+			if err == nil {
+				require.NotNil(t, got)
+				assert.Equal(t, testCase.wantInterval, got.(*selftelemetry.Config).CollectionInterval)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package spanmetrics
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/aws/amazon-cloudwatch-agent/receiver/spanmetrics"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	defaultMetricsCollectionInterval = time.Minute
+)
+
+var (
+	intervalKey = common.ConfigKey(common.SpanMetricsConfigKey, common.MetricsCollectionIntervalKey)
+)
+
+type translator struct {
+	name    string
+	factory receiver.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+func NewTranslatorWithName(name string) common.Translator[component.Config] {
+	return &translator{
+		name:    name,
+		factory: spanmetrics.NewFactory(),
+	}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates a span metrics receiver config if the span metrics section
+// is present. The interval is the period the metrics are aggregated over.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if conf == nil || !conf.IsSet(common.SpanMetricsConfigKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.SpanMetricsConfigKey}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*spanmetrics.Config)
+	cfg.CollectionInterval = common.GetOrDefaultDuration(conf, []string{intervalKey}, defaultMetricsCollectionInterval)
+	return cfg, nil
+}
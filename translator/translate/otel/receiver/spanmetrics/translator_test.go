@@ -0,0 +1,54 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package spanmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/receiver/spanmetrics"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	tt := NewTranslatorWithName(common.PipelineNameSpanMetrics)
+	assert.EqualValues(t, "spanmetrics/span_metrics", tt.ID().String())
+	testCases := map[string]struct {
+		input        map[string]any
+		wantInterval time.Duration
+		wantErr      error
+	}{
+		"WithoutSpanMetrics": {
+			input: map[string]any{"traces": map[string]any{}},
+			wantErr: &common.MissingKeyError{
+				ID:      tt.ID(),
+				JsonKey: common.SpanMetricsConfigKey,
+			},
+		},
+		"WithDefaultInterval": {
+			input:        map[string]any{"traces": map[string]any{"span_metrics": map[string]any{}}},
+			wantInterval: time.Minute,
+		},
+		"WithInterval": {
+			input: map[string]any{"traces": map[string]any{"span_metrics": map[string]any{
+				"metrics_collection_interval": 10,
+			}}},
+			wantInterval: 10 * time.Second,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.Translate(confmap.NewFromStringMap(testCase.input))
+			assert.Equal(t, testCase.wantErr, err)
+			if err == nil {
+				require.NotNil(t, got)
+				assert.Equal(t, testCase.wantInterval, got.(*spanmetrics.Config).CollectionInterval)
+			}
+		})
+	}
+}
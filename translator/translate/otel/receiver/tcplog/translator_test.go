@@ -6,6 +6,7 @@ package tcplog
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/tcplogreceiver"
@@ -115,6 +116,24 @@ func TestTranslator(t *testing.T) {
 				},
 			},
 		},
+		"TcpIPv6ServiceAddress": {
+			input: map[string]interface{}{
+				"logs": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"emf": map[string]interface{}{
+							"service_address": "tcp://[::1]:25888",
+						},
+					},
+				},
+			},
+			want: &tcplogreceiver.TCPLogConfig{
+				InputConfig: tcp.Config{
+					BaseConfig: tcp.BaseConfig{
+						ListenAddress: "[::1]:25888",
+					},
+				},
+			},
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -131,3 +150,24 @@ func TestTranslator(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslatorWithMemoryLimiter(t *testing.T) {
+	emf := map[string]interface{}{
+		"logs": map[string]interface{}{
+			"metrics_collected": map[string]interface{}{
+				"emf": map[string]interface{}{},
+			},
+		},
+	}
+	got, err := NewTranslator().Translate(confmap.NewFromStringMap(emf))
+	require.NoError(t, err)
+	require.False(t, got.(*tcplogreceiver.TCPLogConfig).RetryOnFailure.Enabled)
+
+	emf["agent"] = map[string]interface{}{"memory_limiter": map[string]interface{}{}}
+	got, err = NewTranslator().Translate(confmap.NewFromStringMap(emf))
+	require.NoError(t, err)
+	retry := got.(*tcplogreceiver.TCPLogConfig).RetryOnFailure
+	require.True(t, retry.Enabled)
+	require.Equal(t, time.Second, retry.InitialInterval)
+	require.Zero(t, retry.MaxElapsedTime)
+}
@@ -4,9 +4,9 @@
 package udplog
 
 import (
-	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/udplogreceiver"
 	"go.opentelemetry.io/collector/component"
@@ -14,6 +14,7 @@ import (
 	"go.opentelemetry.io/collector/receiver"
 
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/memorylimiter"
 )
 
 type translator struct {
@@ -28,11 +29,6 @@ var (
 	serviceAddressKey = common.ConfigKey(baseKey, common.ServiceAddress)
 )
 
-const (
-	addressSplit        = ":"
-	telegrafDoubleSlash = "//"
-)
-
 // NewTranslator creates a new udp logs receiver translator.
 func NewTranslator() common.Translator[component.Config] {
 	return NewTranslatorWithName("")
@@ -59,6 +55,7 @@ func (t *translator) ID() component.ID {
 // udp://127.0.0.1:25888
 // udp:0.0.0.0:25888
 // udp:localhost:25888
+// udp://[::1]:25888
 func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 	if !conf.IsSet(baseKey) ||
 		(conf.IsSet(common.ConfigKey(serviceAddressKey)) && !strings.Contains(fmt.Sprintf("%v", conf.Get(serviceAddressKey)), common.Udp)) {
@@ -68,16 +65,19 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 	if !conf.IsSet(common.ConfigKey(serviceAddressKey)) {
 		cfg.InputConfig.BaseConfig.ListenAddress = "0.0.0.0:25888"
 	} else {
-		serviceAddress := fmt.Sprintf("%v", conf.Get(serviceAddressKey))
-		serviceSplit := strings.Split(serviceAddress, addressSplit)
-		if len(serviceSplit) != 3 {
-			return nil, errors.New("invalid service split")
-		} else if serviceSplit[1] == telegrafDoubleSlash {
-			serviceSplit[1] = strings.Replace(serviceSplit[1], telegrafDoubleSlash, "0.0.0.0", 1)
-		} else if strings.Contains(serviceAddress, telegrafDoubleSlash) {
-			serviceSplit[1] = strings.Replace(serviceSplit[1], telegrafDoubleSlash, "", 1)
+		listenAddress, err := common.ListenAddress(fmt.Sprintf("%v", conf.Get(serviceAddressKey)))
+		if err != nil {
+			return nil, err
 		}
-		cfg.InputConfig.BaseConfig.ListenAddress = serviceSplit[1] + addressSplit + serviceSplit[2]
+		cfg.InputConfig.BaseConfig.ListenAddress = listenAddress
+	}
+	if memorylimiter.IsSet(conf) {
+		// Retry until the memory limiter accepts the logs, which pauses the reads
+		// instead of dropping the logs.
+		cfg.BaseConfig.RetryOnFailure.Enabled = true
+		cfg.BaseConfig.RetryOnFailure.InitialInterval = time.Second
+		cfg.BaseConfig.RetryOnFailure.MaxInterval = 30 * time.Second
+		cfg.BaseConfig.RetryOnFailure.MaxElapsedTime = 0
 	}
 	return cfg, nil
 }
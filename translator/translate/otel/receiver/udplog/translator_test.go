@@ -6,6 +6,7 @@ package udplog
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/udp"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/udplogreceiver"
@@ -115,6 +116,24 @@ func TestTranslator(t *testing.T) {
 				},
 			},
 		},
+		"UdpIPv6ServiceAddress": {
+			input: map[string]interface{}{
+				"logs": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"emf": map[string]interface{}{
+							"service_address": "udp://[::1]:25888",
+						},
+					},
+				},
+			},
+			want: &udplogreceiver.UDPLogConfig{
+				InputConfig: udp.Config{
+					BaseConfig: udp.BaseConfig{
+						ListenAddress: "[::1]:25888",
+					},
+				},
+			},
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -131,3 +150,24 @@ func TestTranslator(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslatorWithMemoryLimiter(t *testing.T) {
+	emf := map[string]interface{}{
+		"logs": map[string]interface{}{
+			"metrics_collected": map[string]interface{}{
+				"emf": map[string]interface{}{},
+			},
+		},
+	}
+	got, err := NewTranslator().Translate(confmap.NewFromStringMap(emf))
+	require.NoError(t, err)
+	require.False(t, got.(*udplogreceiver.UDPLogConfig).RetryOnFailure.Enabled)
+
+	emf["agent"] = map[string]interface{}{"memory_limiter": map[string]interface{}{}}
+	got, err = NewTranslator().Translate(confmap.NewFromStringMap(emf))
+	require.NoError(t, err)
+	retry := got.(*udplogreceiver.UDPLogConfig).RetryOnFailure
+	require.True(t, retry.Enabled)
+	require.Equal(t, time.Second, retry.InitialInterval)
+	require.Zero(t, retry.MaxElapsedTime)
+}
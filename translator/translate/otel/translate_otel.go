@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -21,6 +22,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/extension/entitystore"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/extension/inventory"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/extension/server"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline/applicationsignals"
@@ -28,11 +30,12 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline/containerinsightsjmx"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline/emf_logs"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline/host"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline/internalmetrics"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline/jmx"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline/nop"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline/prometheus"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline/spanmetrics"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline/xray"
-	"github.com/aws/amazon-cloudwatch-agent/translator/util/ecsutil"
 )
 
 var registry = common.NewTranslatorMap[*common.ComponentTranslators]()
@@ -72,8 +75,10 @@ func Translate(jsonConfig interface{}, os string) (*otelcol.Config, error) {
 	translators.Set(applicationsignals.NewTranslator(component.DataTypeMetrics))
 	translators.Merge(prometheus.NewTranslators(conf))
 	translators.Set(emf_logs.NewTranslator())
-	translators.Set(xray.NewTranslator())
+	translators.Merge(xray.NewTranslators(conf))
 	translators.Set(containerinsightsjmx.NewTranslator())
+	translators.Set(internalmetrics.NewTranslator())
+	translators.Set(spanmetrics.NewTranslator())
 	translators.Merge(jmx.NewTranslators(conf))
 	translators.Merge(registry)
 	pipelines, err := pipeline.NewTranslator(translators).Translate(conf)
@@ -84,13 +89,18 @@ func Translate(jsonConfig interface{}, os string) (*otelcol.Config, error) {
 			return nil, err
 		}
 	}
-	// ECS is not in scope for entity association, so we only add the entity store in non ECS platforms
-	if !ecsutil.GetECSUtilSingleton().IsECS() {
-		pipelines.Translators.Extensions.Set(entitystore.NewTranslator())
-	}
+	pipelines.Translators.Extensions.Set(entitystore.NewTranslator())
 	if context.CurrentContext().KubernetesMode() != "" {
 		pipelines.Translators.Extensions.Set(server.NewTranslator())
 	}
+	if conf.IsSet(inventory.BaseKey) {
+		pipelineNames := make([]string, 0, len(pipelines.Pipelines))
+		for id := range pipelines.Pipelines {
+			pipelineNames = append(pipelineNames, id.String())
+		}
+		sort.Strings(pipelineNames)
+		pipelines.Translators.Extensions.Set(inventory.NewTranslator(pipelineNames))
+	}
 	cfg := &otelcol.Config{
 		Receivers:  map[component.ID]component.Config{},
 		Exporters:  map[component.ID]component.Config{},
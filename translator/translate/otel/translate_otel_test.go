@@ -11,6 +11,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
 	"github.com/aws/amazon-cloudwatch-agent/tool/testutil"
 	"github.com/aws/amazon-cloudwatch-agent/translator"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/registerrules"
@@ -28,6 +29,7 @@ func TestTranslator(t *testing.T) {
 		wantErrContains string
 		detector        func() (eksdetector.Detector, error)
 		isEKSDataStore  func() eksdetector.IsEKSCache
+		wantExtensions  []string
 	}{
 		"WithValidConfig": {
 			input: map[string]interface{}{
@@ -44,6 +46,19 @@ func TestTranslator(t *testing.T) {
 				},
 			},
 		},
+		"WithInventory": {
+			input: map[string]interface{}{
+				"agent": map[string]interface{}{
+					"inventory": map[string]interface{}{},
+				},
+				"metrics": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"cpu": map[string]interface{}{},
+					},
+				},
+			},
+			wantExtensions: []string{"agenthealth/metrics", "agenthealth/statuscode", "entitystore", "inventory"},
+		},
 		"WithEmptyConfig": {
 			input:           map[string]interface{}{},
 			wantErrContains: "no valid pipelines",
@@ -223,6 +238,10 @@ func TestTranslator(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				assert.NotNil(t, got)
+				if testCase.wantExtensions != nil {
+					gotExtensions := collections.MapSlice(got.Service.Extensions, component.ID.String)
+					assert.ElementsMatch(t, testCase.wantExtensions, gotExtensions)
+				}
 			}
 		})
 	}
@@ -96,15 +96,30 @@ func (e *ec2Util) deriveEC2MetadataFromIMDS() error {
 		return err
 	}
 
+	e.deriveEC2MetadataFromEndpoint(ses, nil)
+	// the IPv4 endpoint cannot be reached on an IPv6-only subnet
+	if e.InstanceID == "" && !configaws.IMDSIPv6Enabled() {
+		fmt.Println("D! [EC2] could not get EC2 metadata from the IPv4 endpoint thus try the IPv6 endpoint")
+		e.deriveEC2MetadataFromEndpoint(ses, aws.String(configaws.IMDSIPv6Endpoint))
+	}
+
+	return nil
+}
+
+// deriveEC2MetadataFromEndpoint gets the metadata from the IMDS endpoint, or
+// from the endpoint of the SDK if nil.
+func (e *ec2Util) deriveEC2MetadataFromEndpoint(ses *session.Session, endpoint *string) {
 	mdDisableFallback := ec2metadata.New(ses, &aws.Config{
 		LogLevel:                  configaws.SDKLogLevel(),
 		Logger:                    configaws.SDKLogger{},
 		Retryer:                   retryer.NewIMDSRetryer(retryer.GetDefaultRetryNumber()),
 		EC2MetadataEnableFallback: aws.Bool(false),
+		Endpoint:                  endpoint,
 	})
 	mdEnableFallback := ec2metadata.New(ses, &aws.Config{
 		LogLevel: configaws.SDKLogLevel(),
 		Logger:   configaws.SDKLogger{},
+		Endpoint: endpoint,
 	})
 
 	// ec2 and ecs treats retries for getting host name differently
@@ -141,6 +156,4 @@ func (e *ec2Util) deriveEC2MetadataFromIMDS() error {
 			fmt.Println("E! [EC2] Fetch identity document from EC2 metadata fail:", errInner)
 		}
 	}
-
-	return nil
 }